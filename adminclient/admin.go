@@ -0,0 +1,561 @@
+package adminclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Health and version
+// ---------------------------------------------------------------------------
+
+// Health calls GET /admin/health and returns nil when the twin is healthy.
+func (c *Client) Health(ctx context.Context) error {
+	return c.Do(ctx, http.MethodGet, "/admin/health", nil, nil)
+}
+
+// Version calls GET /admin/version.
+func (c *Client) Version(ctx context.Context) (*VersionInfo, error) {
+	var info VersionInfo
+	if err := c.Do(ctx, http.MethodGet, "/admin/version", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// OpenAPI calls GET /admin/openapi.json and returns the admin API's OpenAPI
+// document as served by the twin.
+func (c *Client) OpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var spec json.RawMessage
+	if err := c.Do(ctx, http.MethodGet, "/admin/openapi.json", nil, &spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// ---------------------------------------------------------------------------
+// State
+// ---------------------------------------------------------------------------
+
+// Reset calls POST /admin/reset, clearing all state, the request log,
+// faults, and the simulated clock.
+func (c *Client) Reset(ctx context.Context) error {
+	return c.Do(ctx, http.MethodPost, "/admin/reset", nil, nil)
+}
+
+// ResetResources resets only the named resources (e.g. "customers"),
+// leaving the rest of the twin's state intact.
+func (c *Client) ResetResources(ctx context.Context, resources ...string) error {
+	return c.Do(ctx, http.MethodPost, "/admin/reset", map[string][]string{"resources": resources}, nil)
+}
+
+// ResetWithSeed resets the twin onto one of its named seed presets.
+func (c *Client) ResetWithSeed(ctx context.Context, preset string) error {
+	return c.Do(ctx, http.MethodPost, "/admin/reset", map[string]string{"seed": preset}, nil)
+}
+
+// State calls GET /admin/state and returns the raw snapshot.
+func (c *Client) State(ctx context.Context) (json.RawMessage, error) {
+	var state json.RawMessage
+	if err := c.Do(ctx, http.MethodGet, "/admin/state", nil, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// LoadState calls POST /admin/state, replacing the twin's state. state may be
+// raw JSON ([]byte or json.RawMessage) or any value marshaled to JSON.
+func (c *Client) LoadState(ctx context.Context, state any) error {
+	return c.Do(ctx, http.MethodPost, "/admin/state", state, nil)
+}
+
+// LintState checks state, a seed in the shape LoadState accepts, for
+// references the twin cannot resolve, without loading it.
+func (c *Client) LintState(ctx context.Context, state any) (*SeedLintResult, error) {
+	var result SeedLintResult
+	if err := c.Do(ctx, http.MethodPost, "/admin/state/lint", state, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExportState streams GET /admin/state/export (NDJSON) into w.
+func (c *Client) ExportState(ctx context.Context, w io.Writer) error {
+	return c.Do(ctx, http.MethodGet, "/admin/state/export", nil, w)
+}
+
+// StateStats reports each store's record count, estimated size, TTL, and limits.
+func (c *Client) StateStats(ctx context.Context) (*StateStats, error) {
+	var stats StateStats
+	if err := c.Do(ctx, http.MethodGet, "/admin/state/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ImportState streams NDJSON records from r to POST /admin/state/import.
+// Streamed bodies are never retried.
+func (c *Client) ImportState(ctx context.Context, r io.Reader) error {
+	return c.Do(ctx, http.MethodPost, "/admin/state/import", r, nil)
+}
+
+// ---------------------------------------------------------------------------
+// Faults and requests
+// ---------------------------------------------------------------------------
+
+// InjectFault registers a fault on an endpoint path such as "/v1/transfers",
+// or on webhook deliveries (see WebhookFaultEndpoint).
+func (c *Client) InjectFault(ctx context.Context, endpoint string, fault Fault) error {
+	return c.Do(ctx, http.MethodPost, faultPath(endpoint), fault, nil)
+}
+
+// RemoveFault removes the fault registered on an endpoint path.
+func (c *Client) RemoveFault(ctx context.Context, endpoint string) error {
+	return c.Do(ctx, http.MethodDelete, faultPath(endpoint), nil, nil)
+}
+
+// Faults returns the active faults keyed by endpoint path.
+func (c *Client) Faults(ctx context.Context) (map[string]Fault, error) {
+	var faults map[string]Fault
+	if err := c.Do(ctx, http.MethodGet, "/admin/faults", nil, &faults); err != nil {
+		return nil, err
+	}
+	return faults, nil
+}
+
+// StorageFailures returns the twin's storage failure mode.
+func (c *Client) StorageFailures(ctx context.Context) (*StorageFailures, error) {
+	var sf StorageFailures
+	if err := c.Do(ctx, http.MethodGet, "/admin/faults/storage", nil, &sf); err != nil {
+		return nil, err
+	}
+	return &sf, nil
+}
+
+// SetStorageFailures makes a share of the twin's store operations fail, so
+// API requests that hit one get the provider's 500. With AfterWrite, a
+// failing write is applied first.
+func (c *Client) SetStorageFailures(ctx context.Context, cfg StorageFailureConfig) (*StorageFailures, error) {
+	var sf StorageFailures
+	if err := c.Do(ctx, http.MethodPut, "/admin/faults/storage", cfg, &sf); err != nil {
+		return nil, err
+	}
+	return &sf, nil
+}
+
+// ClearStorageFailures turns storage failures off.
+func (c *Client) ClearStorageFailures(ctx context.Context) error {
+	return c.Do(ctx, http.MethodDelete, "/admin/faults/storage", nil, nil)
+}
+
+// Requests returns the twin's request log, oldest first.
+func (c *Client) Requests(ctx context.Context) ([]RequestLogEntry, error) {
+	var entries []RequestLogEntry
+	if err := c.Do(ctx, http.MethodGet, "/admin/requests", nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Routes lists the twin's API routes, without the admin ones.
+func (c *Client) Routes(ctx context.Context) ([]Route, error) {
+	var routes []Route
+	if err := c.Do(ctx, http.MethodGet, "/admin/routes", nil, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// Replay re-sends a logged request. The twin must capture request bodies.
+func (c *Client) Replay(ctx context.Context, requestID string) (*ReplayResult, error) {
+	var result ReplayResult
+	path := "/admin/requests/" + url.PathEscape(requestID) + "/replay"
+	if err := c.do(ctx, http.MethodPost, path, nil, &result, false); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Correlations returns the domain events and webhooks the request with the
+// given ID produced. requestID is the ID the twin echoed in X-Request-Id,
+// or a request log entry ID such as req_000001.
+func (c *Client) Correlations(ctx context.Context, requestID string) (*Correlations, error) {
+	var result Correlations
+	path := "/admin/correlations?request_id=" + url.QueryEscape(requestID)
+	if err := c.Do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ShadowDiffs returns the differences recorded between the twin's responses
+// and those of its shadow target.
+func (c *Client) ShadowDiffs(ctx context.Context) (*ShadowReport, error) {
+	var report ShadowReport
+	if err := c.Do(ctx, http.MethodGet, "/admin/shadow/diffs", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// AuditExport returns the twin's hash-chained log of admin mutations and
+// scenario runs.
+func (c *Client) AuditExport(ctx context.Context) (*AuditExport, error) {
+	var exp AuditExport
+	if err := c.Do(ctx, http.MethodGet, "/admin/audit/export", nil, &exp); err != nil {
+		return nil, err
+	}
+	return &exp, nil
+}
+
+// RecordScenario records a scenario run in the twin's audit log. The call
+// is never retried, since a retry would record the run twice.
+func (c *Client) RecordScenario(ctx context.Context, scenario string, passed bool) error {
+	req := map[string]any{"kind": "scenario", "scenario": scenario, "passed": passed}
+	return c.do(ctx, http.MethodPost, "/admin/audit/events", req, nil, false)
+}
+
+// WebhookFaultEndpoint returns the fault endpoint for the twin's webhook
+// deliveries of eventType, or of every type for "*". A fault there drops
+// the deliveries, or with only Delay set delays them.
+func WebhookFaultEndpoint(eventType string) string {
+	return "webhook:" + eventType
+}
+
+func faultPath(endpoint string) string {
+	return "/admin/fault/" + strings.TrimPrefix(endpoint, "/")
+}
+
+// ---------------------------------------------------------------------------
+// Time
+// ---------------------------------------------------------------------------
+
+// Time calls GET /admin/time.
+func (c *Client) Time(ctx context.Context) (*TimeInfo, error) {
+	var info TimeInfo
+	if err := c.Do(ctx, http.MethodGet, "/admin/time", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// AdvanceTime moves the twin's simulated clock forward by d and returns the
+// resulting clock state.
+func (c *Client) AdvanceTime(ctx context.Context, d time.Duration) (*TimeInfo, error) {
+	var info TimeInfo
+	body := map[string]string{"duration": d.String()}
+	if err := c.do(ctx, http.MethodPost, "/admin/time/advance", body, &info, false); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SetTime moves the twin's simulated clock to an absolute time, e.g. the
+// last minute of a billing period. A frozen clock stays frozen at t.
+func (c *Client) SetTime(ctx context.Context, t time.Time) (*TimeInfo, error) {
+	return c.timeOp(ctx, "/admin/time/set", map[string]string{"to": t.Format(time.RFC3339)})
+}
+
+// FreezeTime stops the twin's simulated clock until UnfreezeTime.
+func (c *Client) FreezeTime(ctx context.Context) (*TimeInfo, error) {
+	return c.timeOp(ctx, "/admin/time/freeze", nil)
+}
+
+// UnfreezeTime restarts a frozen clock from the instant it was frozen at.
+func (c *Client) UnfreezeTime(ctx context.Context) (*TimeInfo, error) {
+	return c.timeOp(ctx, "/admin/time/unfreeze", nil)
+}
+
+func (c *Client) timeOp(ctx context.Context, path string, body any) (*TimeInfo, error) {
+	var info TimeInfo
+	if err := c.Do(ctx, http.MethodPost, path, body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ---------------------------------------------------------------------------
+// Webhooks
+// ---------------------------------------------------------------------------
+
+// FlushWebhooks delivers all queued webhooks immediately.
+func (c *Client) FlushWebhooks(ctx context.Context) error {
+	return c.Do(ctx, http.MethodPost, "/admin/webhooks/flush", nil, nil)
+}
+
+// Webhooks returns queued events and delivery attempts.
+func (c *Client) Webhooks(ctx context.Context) (*Webhooks, error) {
+	var hooks Webhooks
+	if err := c.Do(ctx, http.MethodGet, "/admin/webhooks", nil, &hooks); err != nil {
+		return nil, err
+	}
+	return &hooks, nil
+}
+
+// Events returns every webhook event the twin has generated.
+func (c *Client) Events(ctx context.Context) ([]WebhookEvent, error) {
+	var events []WebhookEvent
+	if err := c.Do(ctx, http.MethodGet, "/admin/events", nil, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// DeadLetters returns the webhook events the twin gave up redelivering.
+func (c *Client) DeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	var dead []DeadLetter
+	if err := c.Do(ctx, http.MethodGet, "/admin/webhooks/dead_letters", nil, &dead); err != nil {
+		return nil, err
+	}
+	return dead, nil
+}
+
+// RetryDeadLetter attempts delivery of a dead-lettered event once more. The
+// event leaves the dead-letter list only if delivery succeeds; otherwise an
+// *APIError with status 502 is returned. The call itself is never retried.
+func (c *Client) RetryDeadLetter(ctx context.Context, eventID string) error {
+	path := "/admin/webhooks/dead_letters/" + url.PathEscape(eventID) + "/retry"
+	return c.do(ctx, http.MethodPost, path, nil, nil, false)
+}
+
+// WebhookEndpoints returns the per-tenant webhook endpoints, by tenant.
+func (c *Client) WebhookEndpoints(ctx context.Context) (map[string]WebhookEndpoint, error) {
+	var endpoints map[string]WebhookEndpoint
+	if err := c.Do(ctx, http.MethodGet, "/admin/webhooks/endpoints", nil, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// SetWebhookEndpoint delivers the tenant's webhook events to ep, signed
+// with its secret, instead of the twin's webhook URL.
+func (c *Client) SetWebhookEndpoint(ctx context.Context, tenant string, ep WebhookEndpoint) error {
+	return c.Do(ctx, http.MethodPut, "/admin/webhooks/endpoints/"+url.PathEscape(tenant), ep, nil)
+}
+
+// RemoveWebhookEndpoint sends the tenant's webhook events back to the
+// twin's webhook URL.
+func (c *Client) RemoveWebhookEndpoint(ctx context.Context, tenant string) error {
+	return c.Do(ctx, http.MethodDelete, "/admin/webhooks/endpoints/"+url.PathEscape(tenant), nil, nil)
+}
+
+// EventTypes returns the event types the twin can emit, with example
+// payloads.
+func (c *Client) EventTypes(ctx context.Context) ([]EventType, error) {
+	var types []EventType
+	if err := c.Do(ctx, http.MethodGet, "/admin/webhooks/events/catalog", nil, &types); err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// TriggerEvent emits an event of the given type without performing the API
+// action behind it. The event carries the type's example payload with
+// overrides merged in. The call is never retried, since a retry would emit
+// a second event.
+func (c *Client) TriggerEvent(ctx context.Context, eventType string, overrides map[string]any) (map[string]any, error) {
+	req := map[string]any{"type": eventType, "overrides": overrides}
+	var resp struct {
+		Event map[string]any `json:"event"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/admin/webhooks/trigger", req, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.Event, nil
+}
+
+// ---------------------------------------------------------------------------
+// Config and quirks
+// ---------------------------------------------------------------------------
+
+// Config returns the twin's runtime configuration.
+func (c *Client) Config(ctx context.Context) (map[string]any, error) {
+	var cfg map[string]any
+	if err := c.Do(ctx, http.MethodGet, "/admin/config", nil, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// UpdateConfig changes runtime settings such as "latency" and "fail_rate"
+// and returns the resulting configuration.
+func (c *Client) UpdateConfig(ctx context.Context, updates map[string]any) (map[string]any, error) {
+	var resp struct {
+		Config map[string]any `json:"config"`
+	}
+	if err := c.Do(ctx, http.MethodPut, "/admin/config", updates, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Config, nil
+}
+
+// Quirks lists the twin's quirks and whether each is enabled.
+func (c *Client) Quirks(ctx context.Context) ([]Quirk, error) {
+	var quirks []Quirk
+	if err := c.Do(ctx, http.MethodGet, "/admin/quirks", nil, &quirks); err != nil {
+		return nil, err
+	}
+	return quirks, nil
+}
+
+// EnableQuirk turns a quirk on.
+func (c *Client) EnableQuirk(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodPut, "/admin/quirks/"+url.PathEscape(id), nil, nil)
+}
+
+// DisableQuirk turns a quirk off.
+func (c *Client) DisableQuirk(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/admin/quirks/"+url.PathEscape(id), nil, nil)
+}
+
+// ---------------------------------------------------------------------------
+// Tenants
+// ---------------------------------------------------------------------------
+
+// Tenants lists every account that can authenticate against the twin's API,
+// seeded or created at runtime.
+func (c *Client) Tenants(ctx context.Context) ([]Tenant, error) {
+	var tenants []Tenant
+	if err := c.Do(ctx, http.MethodGet, "/admin/tenants", nil, &tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// CreateTenant mints an account with its own credentials, so parallel test
+// jobs don't share the seeded ones. Credentials left out of req are
+// generated. The call is never retried, since a retry would create a second
+// tenant.
+func (c *Client) CreateTenant(ctx context.Context, req TenantRequest) (*Tenant, error) {
+	var tenant Tenant
+	if err := c.do(ctx, http.MethodPost, "/admin/tenants", req, &tenant, false); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// DeleteTenant removes a tenant and the records it owns, leaving other
+// tenants untouched.
+func (c *Client) DeleteTenant(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/admin/tenants/"+url.PathEscape(id), nil, nil)
+}
+
+// ---------------------------------------------------------------------------
+// Breakpoints
+// ---------------------------------------------------------------------------
+
+// Breakpoints lists the twin's breakpoints and the requests they hold.
+func (c *Client) Breakpoints(ctx context.Context) ([]Breakpoint, error) {
+	var bps []Breakpoint
+	if err := c.Do(ctx, http.MethodGet, "/admin/breakpoints", nil, &bps); err != nil {
+		return nil, err
+	}
+	return bps, nil
+}
+
+// AddBreakpoint makes the twin hold API requests matching bp's Method and
+// Path until they are released, and returns the breakpoint with its ID.
+func (c *Client) AddBreakpoint(ctx context.Context, bp Breakpoint) (*Breakpoint, error) {
+	var out Breakpoint
+	if err := c.do(ctx, http.MethodPost, "/admin/breakpoints", bp, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReleaseBreakpoint lets requests held at breakpoint id carry on, as
+// selected by rel, and returns how many it released. A WaitFor release
+// waits on the twin, so ctx and the client's timeout must allow for it.
+func (c *Client) ReleaseBreakpoint(ctx context.Context, id string, rel BreakpointRelease) (int, error) {
+	var resp struct {
+		Released int `json:"released"`
+	}
+	path := "/admin/breakpoints/" + url.PathEscape(id) + "/release"
+	if err := c.do(ctx, http.MethodPost, path, rel, &resp, false); err != nil {
+		return 0, err
+	}
+	return resp.Released, nil
+}
+
+// RemoveBreakpoint removes breakpoint id, releasing the requests it holds.
+func (c *Client) RemoveBreakpoint(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/admin/breakpoints/"+url.PathEscape(id), nil, nil)
+}
+
+// ---------------------------------------------------------------------------
+// Intercepts
+// ---------------------------------------------------------------------------
+
+// Intercepts lists the twin's intercepts and the responses they hold.
+func (c *Client) Intercepts(ctx context.Context) ([]Intercept, error) {
+	var ics []Intercept
+	if err := c.Do(ctx, http.MethodGet, "/admin/intercepts", nil, &ics); err != nil {
+		return nil, err
+	}
+	return ics, nil
+}
+
+// AddIntercept makes the twin hold responses to API requests matching ic's
+// Method and Path until they are decided, and returns the intercept with
+// its ID.
+func (c *Client) AddIntercept(ctx context.Context, ic Intercept) (*Intercept, error) {
+	var out Intercept
+	if err := c.do(ctx, http.MethodPost, "/admin/intercepts", ic, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DecideIntercept returns a response held at intercept id to its client
+// as d says.
+func (c *Client) DecideIntercept(ctx context.Context, id string, d InterceptDecision) error {
+	return c.do(ctx, http.MethodPost, "/admin/intercepts/"+url.PathEscape(id)+"/decide", d, nil, false)
+}
+
+// RemoveIntercept removes intercept id, returning the responses it holds as
+// they are.
+func (c *Client) RemoveIntercept(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/admin/intercepts/"+url.PathEscape(id), nil, nil)
+}
+
+// ---------------------------------------------------------------------------
+// Response templates
+// ---------------------------------------------------------------------------
+
+// Templates lists the response templates set on the twin.
+func (c *Client) Templates(ctx context.Context) ([]ResponseTemplate, error) {
+	var templates []ResponseTemplate
+	if err := c.Do(ctx, http.MethodGet, "/admin/templates", nil, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// SetTemplate overrides how route's successful responses are rendered.
+// route is a method and the twin's route pattern, e.g.
+// "GET /v1/customers/{id}"; format is "json" or "go".
+func (c *Client) SetTemplate(ctx context.Context, route, format, body string) (*ResponseTemplate, error) {
+	var resp struct {
+		Template ResponseTemplate `json:"template"`
+	}
+	path := templatePath(route) + "?format=" + url.QueryEscape(format)
+	if err := c.Do(ctx, http.MethodPut, path, []byte(body), &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Template, nil
+}
+
+// RemoveTemplate restores route's own response rendering.
+func (c *Client) RemoveTemplate(ctx context.Context, route string) error {
+	return c.Do(ctx, http.MethodDelete, templatePath(route), nil, nil)
+}
+
+func templatePath(route string) string {
+	method, pattern, _ := strings.Cut(route, " ")
+	return "/admin/templates/" + strings.ToUpper(method) + (&url.URL{Path: "/" + strings.TrimPrefix(pattern, "/")}).EscapedPath()
+}
@@ -0,0 +1,236 @@
+// Package adminclient is a typed Go client for the /admin/* control plane
+// every WonderTwin twin exposes. Application test suites use it to reset,
+// seed, fault, and time-travel twins without hand-writing HTTP calls:
+//
+//	twin := adminclient.New("http://localhost:4111")
+//	if err := twin.Reset(ctx); err != nil { ... }
+//	twin.InjectFault(ctx, "/v1/transfers", adminclient.Fault{StatusCode: 503, Rate: 1})
+//	twin.AdvanceTime(ctx, 24*time.Hour)
+package adminclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client talks to one twin's admin API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	retries int
+	backoff time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithRetries sets how many times a failed request is retried and the delay
+// before the first retry, which doubles on every attempt. Only connection
+// errors and 502/503/504 responses are retried, and never for calls that
+// change state cumulatively (AdvanceTime, Replay).
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = n
+		c.backoff = backoff
+	}
+}
+
+// New creates a client for the twin at baseURL (e.g. "http://localhost:4111",
+// "http://[::1]:4111", or UnixSocketURL of a twin started with --socket).
+// By default requests time out after 10 seconds and are retried twice.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: 10 * time.Second},
+		retries: 2,
+		backoff: 100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.http = withUnixTransport(c.http, c.baseURL)
+	return c
+}
+
+// ForPort creates a client for a twin listening on localhost:port.
+func ForPort(port int, opts ...Option) *Client {
+	return New(fmt.Sprintf("http://localhost:%d", port), opts...)
+}
+
+// BaseURL returns the twin URL the client was created with.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// APIError is returned when the twin answers with a non-2xx status.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string // error.message from the twin's JSON error body, if any
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = strings.TrimSpace(string(e.Body))
+	}
+	return fmt.Sprintf("%s %s returned status %d: %s", e.Method, e.Path, e.StatusCode, msg)
+}
+
+// IsNotFound reports whether err is an APIError with status 404, which twins
+// return for admin features they do not support.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Do sends a request to any admin path and decodes the JSON response into
+// out. body may be nil, a []byte or json.RawMessage sent as-is, an io.Reader
+// streamed as-is, or any value marshaled to JSON. out may be nil, a
+// *json.RawMessage to capture the raw body, or an io.Writer the body is
+// streamed into. Do is the escape hatch for endpoints without a typed
+// method; it retries like every other call, except for streamed bodies.
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	return c.do(ctx, method, path, body, out, true)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any, retry bool) error {
+	payload, stream, err := encodeBody(body)
+	if err != nil {
+		return err
+	}
+
+	attempts := 1
+	if _, streamOut := out.(io.Writer); retry && stream == nil && !streamOut {
+		attempts += c.retries
+	}
+	delay := c.backoff
+
+	for attempt := 1; ; attempt++ {
+		err := c.once(ctx, method, path, payload, stream, out)
+		if err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (c *Client) once(ctx context.Context, method, path string, payload []byte, stream io.Reader, out any) error {
+	var reader io.Reader
+	switch {
+	case stream != nil:
+		reader = stream
+	case payload != nil:
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		if w, ok := out.(io.Writer); ok {
+			_, err := io.Copy(w, resp.Body)
+			return err
+		}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(method, path, resp.StatusCode, data)
+	}
+
+	switch o := out.(type) {
+	case nil:
+		return nil
+	case *json.RawMessage:
+		*o = append((*o)[:0], bytes.TrimSpace(data)...)
+		return nil
+	default:
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+		}
+		return nil
+	}
+}
+
+func encodeBody(body any) ([]byte, io.Reader, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil, nil
+	case []byte:
+		return b, nil, nil
+	case json.RawMessage:
+		return b, nil, nil
+	case io.Reader:
+		return nil, b, nil
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encoding request body: %w", err)
+		}
+		return data, nil, nil
+	}
+}
+
+func newAPIError(method, path string, status int, body []byte) *APIError {
+	e := &APIError{Method: method, Path: path, StatusCode: status, Body: body}
+	var envelope struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &envelope) == nil {
+		e.Message = envelope.Error.Message
+	}
+	return e
+}
+
+// retryable reports whether a failed attempt is worth repeating: the twin
+// was unreachable (e.g. still starting) or briefly unavailable.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return true
+}
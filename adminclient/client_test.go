@@ -0,0 +1,460 @@
+package adminclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{"error": map[string]any{"message": msg, "code": status}})
+}
+
+func newClient(t *testing.T, mux *http.ServeMux, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	opts = append([]Option{WithRetries(2, time.Millisecond)}, opts...)
+	return New(srv.URL+"/", opts...)
+}
+
+// ---------------------------------------------------------------------------
+// Typed calls
+// ---------------------------------------------------------------------------
+
+func TestTypedResponses(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/faults", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"/v1/transfers": map[string]any{"status_code": 503, "rate": 0.5}})
+	})
+	mux.HandleFunc("GET /admin/quirks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]any{{"id": "q1", "enabled": true}})
+	})
+	mux.HandleFunc("POST /admin/time/advance", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["duration"] != "24h0m0s" {
+			writeError(w, http.StatusBadRequest, "unexpected duration "+body["duration"])
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"offset": "24h0m0s", "simulated": "2026-01-02T00:00:00Z"})
+	})
+	c := newClient(t, mux)
+	ctx := context.Background()
+
+	faults, err := c.Faults(ctx)
+	if err != nil {
+		t.Fatalf("Faults() error: %v", err)
+	}
+	if f := faults["/v1/transfers"]; f.StatusCode != 503 || f.Rate != 0.5 {
+		t.Errorf("unexpected fault: %+v", f)
+	}
+
+	quirks, err := c.Quirks(ctx)
+	if err != nil {
+		t.Fatalf("Quirks() error: %v", err)
+	}
+	if len(quirks) != 1 || quirks[0].ID != "q1" || !quirks[0].Enabled {
+		t.Errorf("unexpected quirks: %+v", quirks)
+	}
+
+	info, err := c.AdvanceTime(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("AdvanceTime() error: %v", err)
+	}
+	if info.Offset != "24h0m0s" {
+		t.Errorf("expected offset 24h0m0s, got %q", info.Offset)
+	}
+}
+
+func TestInjectFaultPath(t *testing.T) {
+	var gotPath string
+	var gotFault Fault
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/fault/", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotFault)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "injected"})
+	})
+	c := newClient(t, mux)
+
+	if err := c.InjectFault(context.Background(), "/v1/transfers", Fault{StatusCode: 429, Rate: 1}); err != nil {
+		t.Fatalf("InjectFault() error: %v", err)
+	}
+	if gotPath != "/admin/fault/v1/transfers" {
+		t.Errorf("expected /admin/fault/v1/transfers, got %s", gotPath)
+	}
+	if gotFault.StatusCode != 429 || gotFault.Rate != 1 {
+		t.Errorf("unexpected fault body: %+v", gotFault)
+	}
+}
+
+func TestLoadStateRawJSON(t *testing.T) {
+	var got []byte
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/state", func(w http.ResponseWriter, r *http.Request) {
+		got, _ = io.ReadAll(r.Body)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "loaded"})
+	})
+	c := newClient(t, mux)
+
+	raw := []byte(`{"customers":{}}`)
+	if err := c.LoadState(context.Background(), raw); err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if !bytes.Equal(got, raw) {
+		t.Errorf("expected raw body to be sent as-is, got %s", got)
+	}
+}
+
+func TestExportStateStreams(t *testing.T) {
+	ndjson := "{\"resource\":\"a\",\"id\":\"1\",\"data\":{}}\n{\"resource\":\"a\",\"id\":\"2\",\"data\":{}}\n"
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/state/export", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		io.WriteString(w, ndjson)
+	})
+	c := newClient(t, mux)
+
+	var buf bytes.Buffer
+	if err := c.ExportState(context.Background(), &buf); err != nil {
+		t.Fatalf("ExportState() error: %v", err)
+	}
+	if buf.String() != ndjson {
+		t.Errorf("expected export to be copied verbatim, got %q", buf.String())
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Errors and retries
+// ---------------------------------------------------------------------------
+
+func TestAPIError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/quirks", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "quirk store not configured")
+	})
+	c := newClient(t, mux)
+
+	_, err := c.Quirks(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "quirk store not configured" {
+		t.Errorf("unexpected APIError: %+v", apiErr)
+	}
+	if !IsNotFound(err) {
+		t.Error("expected IsNotFound to be true")
+	}
+	if !strings.Contains(err.Error(), "quirk store not configured") {
+		t.Errorf("expected message in error string, got %q", err.Error())
+	}
+}
+
+func TestRetriesUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/reset", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			writeError(w, http.StatusServiceUnavailable, "starting")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "reset"})
+	})
+	c := newClient(t, mux)
+
+	if err := c.Reset(context.Background()); err != nil {
+		t.Fatalf("Reset() error: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/reset", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeError(w, http.StatusBadRequest, "unknown resource")
+	})
+	c := newClient(t, mux)
+
+	if err := c.ResetResources(context.Background(), "nope"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestNoRetryForAdvanceTime(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/time/advance", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeError(w, http.StatusServiceUnavailable, "busy")
+	})
+	c := newClient(t, mux)
+
+	if _, err := c.AdvanceTime(context.Background(), time.Hour); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected AdvanceTime not to be retried, got %d attempts", calls.Load())
+	}
+}
+
+func TestNoRetryForCreateTenant(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/tenants", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeError(w, http.StatusServiceUnavailable, "busy")
+	})
+	c := newClient(t, mux)
+
+	if _, err := c.CreateTenant(context.Background(), TenantRequest{Name: "job-1"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected CreateTenant not to be retried, got %d attempts", calls.Load())
+	}
+}
+
+func TestTriggerEvent(t *testing.T) {
+	var calls atomic.Int32
+	var got map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/webhooks/trigger", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			writeError(w, http.StatusServiceUnavailable, "busy")
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "triggered", "event": {"id": "evt_000001", "type": "charge.succeeded"}}`))
+	})
+	c := newClient(t, mux)
+
+	if _, err := c.TriggerEvent(context.Background(), "charge.succeeded", nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected TriggerEvent not to be retried, got %d attempts", calls.Load())
+	}
+
+	evt, err := c.TriggerEvent(context.Background(), "charge.succeeded", map[string]any{"amount": 5000})
+	if err != nil {
+		t.Fatalf("TriggerEvent: %v", err)
+	}
+	if evt["id"] != "evt_000001" {
+		t.Errorf("expected the emitted event, got %v", evt)
+	}
+	if got["type"] != "charge.succeeded" || got["overrides"].(map[string]any)["amount"] != float64(5000) {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	var retries atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/webhooks/dead_letters", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"event": {"id": "evt_000001", "type": "charge.succeeded"}, "attempts": 15, "last_error": "webhook delivery failed: status 503", "failed_at": "2025-01-01T08:00:00Z"}]`))
+	})
+	mux.HandleFunc("POST /admin/webhooks/dead_letters/{id}/retry", func(w http.ResponseWriter, r *http.Request) {
+		retries.Add(1)
+		writeError(w, http.StatusBadGateway, "redelivery failed: webhook delivery failed: status 503")
+	})
+	c := newClient(t, mux)
+
+	dead, err := c.DeadLetters(context.Background())
+	if err != nil {
+		t.Fatalf("DeadLetters: %v", err)
+	}
+	if len(dead) != 1 || dead[0].Event.ID != "evt_000001" || dead[0].Attempts != 15 {
+		t.Errorf("unexpected dead letters: %+v", dead)
+	}
+
+	err = c.RetryDeadLetter(context.Background(), "evt_000001")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a 502 APIError, got %v", err)
+	}
+	if retries.Load() != 1 {
+		t.Errorf("expected RetryDeadLetter not to be retried, got %d attempts", retries.Load())
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	c := newClient(t, mux)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Health(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	var gotPath, gotFormat, gotBody string
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /admin/templates/{method}/", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotFormat, gotBody = r.URL.Path, r.URL.Query().Get("format"), string(body)
+		writeJSON(w, http.StatusOK, map[string]any{
+			"status":   "set",
+			"template": map[string]string{"route": "GET /v1/customers/{id}", "format": gotFormat, "body": gotBody},
+		})
+	})
+	mux.HandleFunc("GET /admin/templates", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]string{{"route": "GET /v1/customers/{id}", "format": "go"}})
+	})
+	c := newClient(t, mux)
+
+	tmpl, err := c.SetTemplate(context.Background(), "get /v1/customers/{id}", "go", `{"id": {{json .record.id}}}`)
+	if err != nil {
+		t.Fatalf("SetTemplate: %v", err)
+	}
+	if gotPath != "/admin/templates/GET/v1/customers/{id}" || gotFormat != "go" || gotBody != `{"id": {{json .record.id}}}` {
+		t.Errorf("unexpected request: path %q, format %q, body %q", gotPath, gotFormat, gotBody)
+	}
+	if tmpl.Route != "GET /v1/customers/{id}" || tmpl.Format != "go" {
+		t.Errorf("unexpected template: %+v", tmpl)
+	}
+
+	templates, err := c.Templates(context.Background())
+	if err != nil {
+		t.Fatalf("Templates: %v", err)
+	}
+	if len(templates) != 1 || templates[0].Route != "GET /v1/customers/{id}" {
+		t.Errorf("unexpected templates: %+v", templates)
+	}
+}
+
+func TestStorageFailures(t *testing.T) {
+	var got map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /admin/faults/storage", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		writeJSON(w, http.StatusOK, map[string]any{"rate": got["rate"], "ops": got["ops"], "after_write": got["after_write"], "injected": 0})
+	})
+	mux.HandleFunc("GET /admin/faults/storage", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"rate": 0.2, "ops": []string{"write"}, "after_write": true, "injected": 3})
+	})
+	c := newClient(t, mux)
+
+	if _, err := c.SetStorageFailures(context.Background(), StorageFailureConfig{Rate: 0.2, Ops: []string{"write"}, AfterWrite: true}); err != nil {
+		t.Fatalf("SetStorageFailures: %v", err)
+	}
+	if got["rate"] != 0.2 || got["after_write"] != true {
+		t.Errorf("unexpected request body: %v", got)
+	}
+	sf, err := c.StorageFailures(context.Background())
+	if err != nil {
+		t.Fatalf("StorageFailures: %v", err)
+	}
+	if sf.Rate != 0.2 || !sf.AfterWrite || sf.Injected != 3 || len(sf.Ops) != 1 {
+		t.Errorf("unexpected storage failures: %+v", sf)
+	}
+}
+
+func TestCorrelations(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/correlations", func(w http.ResponseWriter, r *http.Request) {
+		if id := r.URL.Query().Get("request_id"); id != "host/abc-000001" {
+			writeError(w, http.StatusNotFound, "no logged request, events, or webhooks with request id "+id)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"request_id": "host/abc-000001",
+			"request":    map[string]any{"id": "req_000001", "method": "POST", "path": "/v1/charges", "status_code": 200},
+			"events":     []any{map[string]any{"event_id": "evt_1", "type": "charge.succeeded", "request_id": "host/abc-000001"}},
+			"webhooks": []any{map[string]any{
+				"event":      map[string]any{"id": "evt_000001", "type": "charge.succeeded"},
+				"status":     "redelivering",
+				"deliveries": []any{map[string]any{"event_id": "evt_000001", "status_code": 503, "attempt": 1}},
+			}},
+		})
+	})
+	c := newClient(t, mux)
+
+	got, err := c.Correlations(context.Background(), "host/abc-000001")
+	if err != nil {
+		t.Fatalf("Correlations: %v", err)
+	}
+	if got.Request == nil || got.Request.Path != "/v1/charges" || len(got.Events) != 1 || got.Events[0].EventID != "evt_1" {
+		t.Errorf("unexpected correlations: %+v", got)
+	}
+	if len(got.Webhooks) != 1 || got.Webhooks[0].Status != "redelivering" || got.Webhooks[0].Deliveries[0].StatusCode != 503 {
+		t.Errorf("unexpected webhooks: %+v", got.Webhooks)
+	}
+
+	_, err = c.Correlations(context.Background(), "unknown")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a 404 APIError, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Unix domain sockets
+// ---------------------------------------------------------------------------
+
+func TestUnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "twin.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "path": r.URL.Path})
+	})}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+
+	// A client of its own, or one whose HTTP client has no transport.
+	for _, c := range []*Client{ForSocket(sock), New(UnixSocketURL(sock), WithHTTPClient(&http.Client{}))} {
+		if err := c.Health(context.Background()); err != nil {
+			t.Errorf("expected the twin on the socket to answer, got %v", err)
+		}
+	}
+
+	// Any client whose transport has the scheme registered.
+	tr := &http.Transport{}
+	RegisterUnixSockets(tr)
+	resp, err := (&http.Client{Transport: tr}).Get(UnixSocketURL(sock) + "/admin/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if _, err := (&http.Client{Transport: tr}).Get(UnixScheme + "://not-hex/admin/health"); err == nil || !strings.Contains(err.Error(), "UnixSocketURL") {
+		t.Errorf("expected an error for a host that is not a socket path, got %v", err)
+	}
+}
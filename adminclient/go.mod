@@ -0,0 +1,3 @@
+module github.com/wondertwin-ai/wondertwin/adminclient
+
+go 1.25.7
@@ -0,0 +1,396 @@
+package adminclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Fault is a fault injected on one endpoint of a twin. A fault with a
+// schedule only triggers between StartAt and EndAt; After and Duration give
+// that window relative to injection, e.g. After "10m" and Duration "5m".
+// Preset selects a built-in fault every twin implements, answered with the
+// twin's own provider error, in place of StatusCode.
+type Fault struct {
+	ID         string        `json:"id,omitempty"` // set by the twin; sent in X-WT-Fault on affected responses
+	StatusCode int           `json:"status_code"`
+	Body       string        `json:"body,omitempty"`
+	Delay      time.Duration `json:"delay_ms,omitempty"`
+	Rate       float64       `json:"rate"`                  // 0.0-1.0, probability of the fault triggering
+	Preset     string        `json:"preset,omitempty"`      // "auth_invalid", "auth_expired", or "forbidden_scope"
+	RetryAfter int           `json:"retry_after,omitempty"` // seconds, for 429 faults; default 1
+
+	StartAt  *time.Time `json:"start_at,omitempty"`
+	EndAt    *time.Time `json:"end_at,omitempty"`
+	After    string     `json:"after,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+	Clock    string     `json:"clock,omitempty"` // "wall" (default) or "simulated"
+
+	Hits      int        `json:"hits,omitempty"` // requests the fault has affected; set by the twin
+	LastHitAt *time.Time `json:"last_hit_at,omitempty"`
+}
+
+// StorageFailureConfig makes a share of a twin's store operations fail.
+type StorageFailureConfig struct {
+	Rate       float64  `json:"rate"`          // 0.0-1.0
+	Ops        []string `json:"ops,omitempty"` // "read", "write"; empty means both
+	AfterWrite bool     `json:"after_write,omitempty"`
+}
+
+// StorageFailures is a twin's storage failure mode, and how many store
+// operations it has failed since the last reset.
+type StorageFailures struct {
+	StorageFailureConfig
+	Injected uint64 `json:"injected"`
+}
+
+// RequestLogEntry is one request recorded in a twin's request log.
+type RequestLogEntry struct {
+	ID           string            `json:"id"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Method       string            `json:"method"`
+	Path         string            `json:"path"`
+	Route        string            `json:"route,omitempty"` // route pattern that served it
+	Query        string            `json:"query,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Body         string            `json:"body,omitempty"`
+	BodyCaptured bool              `json:"body_captured,omitempty"`
+	StatusCode   int               `json:"status_code"`
+	Duration     time.Duration     `json:"duration_ms"`
+	RequestID    string            `json:"request_id,omitempty"`
+}
+
+// Route is an API route a twin serves.
+type Route struct {
+	Method string `json:"method"`
+	Route  string `json:"route"` // chi route pattern, e.g. /v1/accounts/{id}
+}
+
+// Correlations links a logged request to the domain events it emitted and
+// the webhooks it enqueued. Request is nil once the request log has
+// evicted the request.
+type Correlations struct {
+	RequestID string               `json:"request_id"`
+	Request   *RequestLogEntry     `json:"request,omitempty"`
+	Events    []JournalEntry       `json:"events"`
+	Webhooks  []WebhookCorrelation `json:"webhooks"`
+}
+
+// JournalEntry is a domain event a twin emitted, such as a Stripe event.
+type JournalEntry struct {
+	EventID   string    `json:"event_id"`
+	Type      string    `json:"type"`
+	RequestID string    `json:"request_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookCorrelation is a webhook event a request enqueued and what became
+// of it. Status is pending (no attempt recorded yet, or no webhook URL
+// configured), delivered, redelivering, dead_lettered, failed, or dropped
+// (by a webhook fault).
+type WebhookCorrelation struct {
+	Event      WebhookEvent      `json:"event"`
+	Status     string            `json:"status"`
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+// ReplayResult is the outcome of replaying a logged request.
+type ReplayResult struct {
+	Original RequestLogEntry `json:"original"`
+	Response struct {
+		StatusCode int               `json:"status_code"`
+		Headers    map[string]string `json:"headers,omitempty"`
+		Body       json.RawMessage   `json:"body,omitempty"`
+	} `json:"response"`
+}
+
+// ShadowReport reports how a shadow target's responses differed from the
+// twin's, for twins started with --shadow-url.
+type ShadowReport struct {
+	Target   string       `json:"target"`
+	Compared int          `json:"compared"`
+	Matched  int          `json:"matched"`
+	Dropped  int          `json:"dropped"`
+	Diffs    []ShadowDiff `json:"diffs"`
+}
+
+// ShadowDiff is one mirrored request whose responses differed.
+type ShadowDiff struct {
+	ID           string    `json:"id"`
+	Timestamp    time.Time `json:"timestamp"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Query        string    `json:"query,omitempty"`
+	RequestID    string    `json:"request_id,omitempty"`
+	StatusCode   int       `json:"status_code"`
+	ShadowStatus int       `json:"shadow_status_code,omitempty"`
+	Differences  []string  `json:"differences,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// AuditExport is a twin's hash-chained audit log. See AuditEntry.
+type AuditExport struct {
+	Twin       string       `json:"twin"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Entries    []AuditEntry `json:"entries"`
+	Dropped    int          `json:"dropped"`
+	Head       string       `json:"head"`
+	Signature  string       `json:"signature,omitempty"` // hex HMAC-SHA256 of Head under the twin's audit key
+}
+
+// AuditEntry is one admin mutation or scenario run in a twin's audit log.
+// Hash covers the entry's fields and PrevHash, the previous entry's Hash;
+// the admin API spec gives the exact input.
+type AuditEntry struct {
+	Seq        int       `json:"seq"`
+	Time       time.Time `json:"time"`
+	Kind       string    `json:"kind"` // "admin" or "scenario"
+	Method     string    `json:"method,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	BodySHA256 string    `json:"body_sha256,omitempty"`
+	Scenario   string    `json:"scenario,omitempty"`
+	Passed     *bool     `json:"passed,omitempty"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// TimeInfo reports a twin's real and simulated clocks. Simulated and Offset
+// are empty for twins without a simulated clock.
+type TimeInfo struct {
+	Real      string `json:"real"`
+	Simulated string `json:"simulated"`
+	Offset    string `json:"offset"`
+	Frozen    bool   `json:"frozen"`
+}
+
+// WebhookEvent is an event a twin generated for webhook delivery.
+type WebhookEvent struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`
+	Data      map[string]any `json:"data"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// WebhookDelivery is one webhook delivery attempt.
+type WebhookDelivery struct {
+	EventID    string    `json:"event_id"`
+	Tenant     string    `json:"tenant,omitempty"`
+	URL        string    `json:"url"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error,omitempty"`
+	Attempt    int       `json:"attempt"`
+	Timestamp  time.Time `json:"timestamp"`
+	Fault      string    `json:"fault,omitempty"`   // ID of the webhook fault that delayed or dropped it
+	Dropped    bool      `json:"dropped,omitempty"` // dropped by a webhook fault, never sent; Attempt is 0
+}
+
+// WebhookEndpoint is where a tenant's webhook events are delivered and the
+// secret they are signed with. Empty fields fall back to the twin's.
+type WebhookEndpoint struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// Webhooks is a twin's outbound webhook activity.
+type Webhooks struct {
+	Queued     []WebhookEvent    `json:"queued"`
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	// Redeliveries are failed events waiting to be retried, on twins that
+	// redeliver webhooks.
+	Redeliveries []Redelivery `json:"redeliveries,omitempty"`
+}
+
+// Redelivery is a failed webhook event waiting to be retried.
+type Redelivery struct {
+	Event       WebhookEvent `json:"event"`
+	Attempts    int          `json:"attempts"`
+	LastError   string       `json:"last_error"`
+	NextAttempt time.Time    `json:"next_attempt"` // in the twin's simulated time
+}
+
+// DeadLetter is a webhook event the twin gave up redelivering.
+type DeadLetter struct {
+	Event     WebhookEvent `json:"event"`
+	Attempts  int          `json:"attempts"`
+	LastError string       `json:"last_error"`
+	FailedAt  time.Time    `json:"failed_at"`
+}
+
+// EventType is an event type a twin can emit. Example is the payload an
+// event of the type carries.
+type EventType struct {
+	Type        string         `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Example     map[string]any `json:"example"`
+}
+
+// ResponseTemplate overrides how one route's successful responses are
+// rendered.
+type ResponseTemplate struct {
+	Route  string    `json:"route"`  // e.g. "GET /v1/customers/{id}"
+	Format string    `json:"format"` // "json" or "go"
+	Body   string    `json:"body"`
+	SetAt  time.Time `json:"set_at"`
+}
+
+// Breakpoint holds a twin's API requests matching Method (any, when empty)
+// and Path, a request path or a prefix ending in *, until they are released
+// or Timeout passes. The other fields are set by the twin.
+type Breakpoint struct {
+	ID        string        `json:"id,omitempty"`
+	Method    string        `json:"method,omitempty"`
+	Path      string        `json:"path"`
+	Timeout   string        `json:"timeout,omitempty"` // e.g. "10s"; at most 20s
+	CreatedAt time.Time     `json:"created_at"`
+	Hits      int           `json:"hits"`
+	Released  int           `json:"released"`
+	TimedOut  int           `json:"timed_out"`
+	Held      []HeldRequest `json:"held"`
+}
+
+// HeldRequest is a request paused at a breakpoint.
+type HeldRequest struct {
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Since     time.Time `json:"since"`
+}
+
+// BreakpointRelease selects the held requests ReleaseBreakpoint lets go:
+// the one with RequestID, or the Count oldest, or all of them. With
+// WaitFor, the twin first waits until that many requests are held.
+type BreakpointRelease struct {
+	Count     int    `json:"count,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	WaitFor   int    `json:"wait_for,omitempty"`
+}
+
+// Intercept holds the responses to a twin's API requests matching Method
+// (any, when empty) and Path, a request path or a prefix ending in *, until
+// each is decided or Timeout passes. The other fields are set by the twin.
+type Intercept struct {
+	ID        string         `json:"id,omitempty"`
+	Method    string         `json:"method,omitempty"`
+	Path      string         `json:"path"`
+	Timeout   string         `json:"timeout,omitempty"` // e.g. "20s"; at most 20s
+	CreatedAt time.Time      `json:"created_at"`
+	Hits      int            `json:"hits"`
+	Approved  int            `json:"approved"`
+	Edited    int            `json:"edited"`
+	Denied    int            `json:"denied"`
+	TimedOut  int            `json:"timed_out"`
+	Held      []HeldResponse `json:"held"`
+}
+
+// HeldResponse is a response held at an intercept, with the request it
+// answers.
+type HeldResponse struct {
+	RequestID  string            `json:"request_id,omitempty"`
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Query      string            `json:"query,omitempty"`
+	Since      time.Time         `json:"since"`
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// InterceptDecision decides the held response with RequestID, or the
+// oldest. Action is "approve", "edit", or "deny"; an edit replaces the
+// status code when StatusCode is set, sets Headers, and replaces the body
+// when Body is set.
+type InterceptDecision struct {
+	RequestID  string            `json:"request_id,omitempty"`
+	Action     string            `json:"action"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       *string           `json:"body,omitempty"`
+}
+
+// StoreStats is the size of one of a twin's stores, its limits, and its
+// operation metrics.
+type StoreStats struct {
+	Count       int    `json:"count"`
+	ApproxBytes int64  `json:"approx_bytes"`
+	TTL         string `json:"ttl,omitempty"`
+	MaxRecords  int    `json:"max_records,omitempty"`
+	MaxBytes    int64  `json:"max_bytes,omitempty"`
+	LimitPolicy string `json:"limit_policy,omitempty"` // "reject" or "evict"
+	Full        bool   `json:"full,omitempty"`         // writes are rejected with 507
+	Evicted     uint64 `json:"evicted,omitempty"`
+
+	Ops       map[string]StoreOpStats `json:"ops,omitempty"`        // keyed by operation, e.g. "Filter"
+	SlowScans uint64                  `json:"slow_scans,omitempty"` // filters over --store-scan-warn records
+}
+
+// StoreOpStats is how often a store operation ran since the last reset and
+// how long it took.
+type StoreOpStats struct {
+	Count   uint64  `json:"count"`
+	TotalMS float64 `json:"total_ms"`
+	MaxMS   float64 `json:"max_ms"`
+}
+
+// SeedLintResult is the response of POST /admin/state/lint.
+type SeedLintResult struct {
+	Valid     bool          `json:"valid"`
+	Relations int           `json:"relations"` // relations the twin declares
+	Problems  []SeedProblem `json:"problems"`
+}
+
+// SeedProblem is a reference in a seed that names no record.
+type SeedProblem struct {
+	Collection string `json:"collection"`
+	ID         string `json:"id"`
+	Field      string `json:"field"`
+	Value      string `json:"value,omitempty"`
+	Message    string `json:"message"`
+}
+
+// String formats the problem as `claimed_rewards["7"].reward_id: ...`.
+func (p SeedProblem) String() string {
+	return fmt.Sprintf("%s[%q].%s: %s", p.Collection, p.ID, p.Field, p.Message)
+}
+
+// StateStats is the response of GET /admin/state/stats.
+type StateStats struct {
+	Stores map[string]StoreStats `json:"stores"`
+	Total  StoreStats            `json:"total"`
+}
+
+// Quirk is the state of a single behavioral quirk.
+type Quirk struct {
+	ID       string `json:"id"`
+	Summary  string `json:"summary"`
+	Enabled  bool   `json:"enabled"`
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+}
+
+// TenantRequest describes an account to create. Credentials are keyed the
+// way the twin's API names them (e.g. "api_key"); missing ones are generated.
+type TenantRequest struct {
+	Name        string            `json:"name,omitempty"`
+	Credentials map[string]string `json:"credentials,omitempty"`
+}
+
+// Tenant is an account and the credentials that authenticate as it.
+// Headers are the request headers that authenticate as the tenant.
+type Tenant struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name,omitempty"`
+	Credentials map[string]string `json:"credentials"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// VersionInfo is a twin's build metadata.
+type VersionInfo struct {
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Commit         string `json:"commit"`
+	BuildDate      string `json:"build_date"`
+	TwinkitVersion string `json:"twinkit_version"`
+	GoVersion      string `json:"go_version"`
+}
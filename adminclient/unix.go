@@ -0,0 +1,86 @@
+package adminclient
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// UnixScheme is the URL scheme of twins listening on a unix domain socket
+// (started with --socket). Its host is the socket path, hex-encoded since a
+// path cannot appear in a URL host; UnixSocketURL builds one.
+const UnixScheme = "http+unix"
+
+// UnixSocketURL returns the base URL of a twin listening on the unix domain
+// socket at path, for New or any HTTP client whose transport has the
+// scheme registered (see RegisterUnixSockets).
+func UnixSocketURL(path string) string {
+	return UnixScheme + "://" + hex.EncodeToString([]byte(path))
+}
+
+// ForSocket creates a client for a twin listening on the unix domain socket
+// at path.
+func ForSocket(path string, opts ...Option) *Client {
+	return New(UnixSocketURL(path), opts...)
+}
+
+// RegisterUnixSockets teaches t to send requests for UnixScheme URLs over
+// the socket their host names, so every client using t can address twins
+// started with --socket. Register http.DefaultTransport to cover clients
+// with no transport of their own.
+func RegisterUnixSockets(t *http.Transport) {
+	t.RegisterProtocol(UnixScheme, unixSockets)
+}
+
+// unixSockets is the RoundTripper for UnixScheme URLs, keeping one
+// transport, and so one connection pool, per socket.
+var unixSockets = &unixTransport{bySocket: map[string]*http.Transport{}}
+
+type unixTransport struct {
+	mu       sync.Mutex
+	bySocket map[string]*http.Transport
+}
+
+func (u *unixTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, err := hex.DecodeString(req.URL.Host)
+	if err != nil || len(path) == 0 {
+		return nil, fmt.Errorf("%s URL host %q is not a hex-encoded socket path; build it with UnixSocketURL", UnixScheme, req.URL.Host)
+	}
+	r := req.Clone(req.Context())
+	r.URL.Scheme = "http"
+	r.URL.Host = "localhost"
+	r.Host = "localhost"
+	return u.transport(string(path)).RoundTrip(r)
+}
+
+func (u *unixTransport) transport(path string) *http.Transport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	t, ok := u.bySocket[path]
+	if !ok {
+		var d net.Dialer
+		t = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return d.DialContext(ctx, "unix", path)
+			},
+			MaxIdleConnsPerHost: 10,
+		}
+		u.bySocket[path] = t
+	}
+	return t
+}
+
+// withUnixTransport returns hc, or a copy of it that can reach baseURL when
+// baseURL is a UnixScheme URL and hc has no transport of its own.
+func withUnixTransport(hc *http.Client, baseURL string) *http.Client {
+	if !strings.HasPrefix(baseURL, UnixScheme+"://") || hc.Transport != nil {
+		return hc
+	}
+	c := *hc
+	c.Transport = unixSockets
+	return &c
+}
@@ -0,0 +1,1140 @@
+// Code generated by gen-adminclient from admin-api.openapi.json. DO NOT EDIT.
+//
+// WonderTwin Admin API 1.0.0
+
+export interface AdvanceTimeRequest {
+  /** Go duration string, e.g. "24h" or "30m". */
+  duration: string;
+}
+
+export interface AuditEntry {
+  /** Hex SHA-256 of the request body, when it had one. */
+  body_sha256?: string;
+  /** Hex SHA-256 of seq, time (RFC 3339, nanoseconds, UTC), kind, method, path, status_code, request_id, body_sha256, scenario, passed (true, false, or empty), and prev_hash, joined with newlines. */
+  hash: string;
+  kind: string;
+  method?: string;
+  passed?: boolean;
+  path?: string;
+  /** The previous entry's hash; 64 zeros for the first entry ever recorded. */
+  prev_hash: string;
+  request_id?: string;
+  scenario?: string;
+  seq: number;
+  status_code?: number;
+  time: string;
+}
+
+export interface AuditEvent {
+  kind: string;
+  passed?: boolean;
+  scenario: string;
+}
+
+export interface AuditExport {
+  /** Oldest entries dropped once the log exceeded 10000; the chain verifies from the first entry kept. */
+  dropped: number;
+  entries: AuditEntry[];
+  exported_at: string;
+  /** The last entry's hash. */
+  head: string;
+  /** Hex HMAC-SHA256 of head under the twin's audit key, when it has one. */
+  signature?: string;
+  twin: string;
+}
+
+export interface Breakpoint {
+  created_at?: string;
+  /** Requests held now, oldest first. */
+  held?: HeldRequest[];
+  /** Requests the breakpoint has held. */
+  hits?: number;
+  id?: string;
+  /** HTTP method to hold. Empty holds any method. */
+  method?: string;
+  /** Request path to hold, e.g. /v1/claims/clm_1/claim, or a prefix ending in *, e.g. /v1/claims/*. */
+  path: string;
+  /** Held requests released through the admin API. */
+  released?: number;
+  /** Held requests that carried on after the timeout. */
+  timed_out?: number;
+  /** How long a request is held before it carries on by itself, at most 20s. Defaults to 10s. */
+  timeout?: string;
+}
+
+export interface BreakpointRelease {
+  /** Release the count oldest held requests. Defaults to all of them. */
+  count?: number;
+  /** Release only the held request with this X-Request-Id. */
+  request_id?: string;
+  /** Wait until at least this many requests are held before releasing. */
+  wait_for?: number;
+}
+
+export interface BreakpointReleaseResult {
+  id: string;
+  /** Number of requests released. */
+  released: number;
+  status: string;
+}
+
+export interface Config {
+  /** Read-only: true when the twin was started with --admin-readonly and rejects admin requests that modify it. */
+  admin_readonly?: boolean;
+  /** Retry-After, in seconds, of shed requests. Defaults to 1. */
+  backpressure_retry_after?: number;
+  /** Per-route overrides of max_body_kb, keyed by exact path or by a path ending in /* for everything under it; 0 lifts the limit. While quirk WT-Q-009 is on, bodies over a limit are cut to it instead. */
+  body_limits?: Record<string, number>;
+  /** Cache-Control header added to API GET responses that set none of their own; empty for none. */
+  cache_control?: string;
+  /** How long a cached GET response is replayed while quirk WT-Q-008 is on, e.g. "30s". */
+  cache_stale?: string;
+  capture_bodies?: boolean;
+  /** Whether responses of 1 KB or more are gzip/br-compressed when the client's Accept-Encoding allows it. */
+  compression?: boolean;
+  /** Read-only: true when rand_seed is non-zero. */
+  deterministic?: boolean;
+  fail_rate?: number;
+  /** Read-only: the address the twin listens on with port; empty for all interfaces. */
+  host?: string;
+  latency?: string;
+  /** How long new records stay out of list responses while quirk WT-Q-007 is on, e.g. "2s". */
+  list_lag?: string;
+  /** API request bodies over this many kilobytes are rejected with the provider's 413 (or 400); 0 means no limit. */
+  max_body_kb?: number;
+  /** API requests beyond this many concurrent ones are shed with 503, Retry-After, and X-WT-Backpressure: max_in_flight, as an overloaded provider would; 0 means no limit. */
+  max_in_flight?: number;
+  /** API requests beyond this many accepted in the last second are shed with 503, Retry-After, and X-WT-Backpressure: max_rps; 0 means no limit. Shed requests do not count, so the twin recovers once the load drops. */
+  max_rps?: number;
+  name?: string;
+  port?: number;
+  /** Seed for reproducible generated codes, fault draws, and latency jitter; 0 means random. Setting it restarts the sequence, as does a full reset. */
+  rand_seed?: number;
+  /** Base URL non-admin requests are mirrored to; empty when shadowing is off. */
+  shadow_url?: string;
+  /** Read-only: the unix domain socket the twin listens on instead of port; empty when listening on TCP. */
+  socket?: string;
+  verbose?: boolean;
+  webhook_url?: string;
+  [key: string]: unknown;
+}
+
+export interface ConfigResult {
+  config: Config;
+  status: string;
+}
+
+export interface Correlations {
+  events: JournalEntry[];
+  /** Absent once the request log has evicted the request. */
+  request?: RequestLogEntry;
+  request_id: string;
+  webhooks: WebhookCorrelation[];
+}
+
+export interface DeadLetter {
+  attempts: number;
+  event: WebhookEvent;
+  failed_at: string;
+  last_error: string;
+}
+
+export interface ErrorResponse {
+  error: {
+    code: number;
+    message: string;
+    type?: string;
+  };
+}
+
+export interface EventType {
+  description?: string;
+  /** Payload an event of this type carries; for Stripe, the event's data.object. */
+  example: Record<string, unknown>;
+  type: string;
+}
+
+export interface Fault {
+  /** Start the fault this long after it is injected, e.g. "10m". Resolved into start_at. */
+  after?: string;
+  body?: string;
+  /** Clock the schedule is measured against; simulated follows /admin/time. Defaults to wall. */
+  clock?: string;
+  /** Added delay in nanoseconds (Go time.Duration). */
+  delay_ms?: number;
+  /** Keep the fault active this long once it starts, e.g. "5m". Resolved into end_at. */
+  duration?: string;
+  /** The fault stops triggering at this time. */
+  end_at?: string;
+  /** Requests the fault has affected since it was injected. */
+  hits?: number;
+  /** Assigned when the fault is injected. Responses the fault affects carry it in the X-WT-Fault header. */
+  id?: string;
+  /** When the fault last affected a request, on its clock. */
+  last_hit_at?: string;
+  /** Built-in fault every twin implements: 401 for unrecognised or expired credentials, or 403 for a missing permission, with the twin's own provider error body (unless body is set). Sets status_code. */
+  preset?: string;
+  /** Probability of the fault triggering, 0.0-1.0. */
+  rate?: number;
+  /** Retry-After, in seconds, of a 429 fault's response. Defaults to 1. */
+  retry_after?: number;
+  /** The fault does not trigger before this time. */
+  start_at?: string;
+  /** Required unless preset is set or the fault is on webhook deliveries. */
+  status_code?: number;
+}
+
+export interface FaultResult {
+  endpoint: string;
+  fault?: Fault;
+  status: string;
+}
+
+export interface HeldRequest {
+  method: string;
+  path: string;
+  request_id?: string;
+  since: string;
+}
+
+export interface HeldResponse {
+  body?: string;
+  headers?: Record<string, string>;
+  method: string;
+  path: string;
+  query?: string;
+  request_id?: string;
+  since: string;
+  status_code: number;
+}
+
+export interface ImportResult {
+  records: number;
+  status: string;
+}
+
+export interface Intercept {
+  /** Held responses returned as they were. */
+  approved?: number;
+  created_at?: string;
+  /** Held responses replaced with an error. */
+  denied?: number;
+  /** Held responses returned after an edit. */
+  edited?: number;
+  /** Responses held now, oldest first. */
+  held?: HeldResponse[];
+  /** Responses the intercept has held. */
+  hits?: number;
+  id?: string;
+  /** HTTP method whose responses to hold. Empty holds any method. */
+  method?: string;
+  /** Request path whose responses to hold, e.g. /v1/charges, or a prefix ending in *, e.g. /v1/charges/*. */
+  path: string;
+  /** Held responses returned as they were after the timeout. */
+  timed_out?: number;
+  /** How long a response is held before it is returned as it is. At most and by default 20s. */
+  timeout?: string;
+}
+
+export interface InterceptDecision {
+  action: string;
+  /** For edit, the new body. For deny, the body to answer with instead of an error. */
+  body?: string;
+  /** For edit, headers to set. */
+  headers?: Record<string, string>;
+  /** Decide the held response to the request with this X-Request-Id. Defaults to the oldest. */
+  request_id?: string;
+  /** For edit, the new status code. For deny, the error's status code, default 502. */
+  status_code?: number;
+}
+
+export interface JournalEntry {
+  event_id: string;
+  request_id?: string;
+  timestamp: string;
+  type: string;
+}
+
+export interface Quirk {
+  enabled: boolean;
+  id: string;
+  severity: string;
+  summary: string;
+  type: string;
+}
+
+export interface QuirkResult {
+  quirk_id: string;
+  status: string;
+}
+
+export interface Redelivery {
+  attempts: number;
+  event: WebhookEvent;
+  last_error: string;
+  /** In the twin's simulated time. */
+  next_attempt: string;
+}
+
+export interface ReplayResult {
+  original: RequestLogEntry;
+  response: {
+    body?: unknown;
+    headers?: Record<string, string>;
+    status_code: number;
+  };
+  status: string;
+}
+
+export interface RequestLogEntry {
+  body?: string;
+  body_captured?: boolean;
+  /** Duration in nanoseconds (Go time.Duration). */
+  duration_ms: number;
+  headers?: Record<string, string>;
+  id: string;
+  method: string;
+  path: string;
+  query?: string;
+  request_id?: string;
+  /** Route pattern that served the request, e.g. /v1/accounts/{id}; absent when no route matched */
+  route?: string;
+  status_code: number;
+  timestamp: string;
+}
+
+export interface ResetRequest {
+  /** Reset only these resources. */
+  resources?: string[];
+  /** Reset onto this named seed preset. */
+  seed?: string;
+}
+
+export interface ResetResult {
+  resources?: string[];
+  seed?: string;
+  status: string;
+}
+
+export interface ResponseTemplate {
+  body: string;
+  format: string;
+  /** Method and route pattern, e.g. "GET /v1/customers/{id}". */
+  route: string;
+  set_at: string;
+}
+
+export interface Route {
+  method: string;
+  /** chi route pattern, e.g. /v1/accounts/{id} */
+  route: string;
+}
+
+export interface SeedLintResult {
+  problems: SeedProblem[];
+  /** Relations the twin declares between its collections. */
+  relations: number;
+  valid: boolean;
+}
+
+export interface SeedProblem {
+  collection: string;
+  field: string;
+  id: string;
+  message: string;
+  value?: string;
+}
+
+export interface SetTimeRequest {
+  /** RFC 3339 timestamp, e.g. "2026-01-31T23:59:00Z". */
+  to: string;
+}
+
+export interface ShadowDiff {
+  /** One entry per difference, e.g. "$.amount: 100 != 200". */
+  differences?: string[];
+  /** Set when the shadow target could not be reached. */
+  error?: string;
+  id: string;
+  method: string;
+  path: string;
+  query?: string;
+  request_id?: string;
+  /** Status the shadow target answered with. */
+  shadow_status_code?: number;
+  /** Status the twin answered with. */
+  status_code: number;
+  timestamp: string;
+}
+
+export interface ShadowReport {
+  compared: number;
+  diffs: ShadowDiff[];
+  /** Requests not mirrored because too many were already in flight. */
+  dropped: number;
+  matched: number;
+  target: string;
+}
+
+/**
+ * Twin-specific state snapshot, keyed by resource name. Snapshots carry a
+ * schema_version; loading one from an older version migrates it, and loading
+ * one newer than the twin supports fails with 400.
+ */
+export type State = unknown;
+
+export interface StateRecord {
+  data: unknown;
+  id: string;
+  resource: string;
+}
+
+export interface StateStats {
+  stores: Record<string, StoreStats>;
+  total: StoreStats;
+}
+
+export interface Status {
+  status: string;
+}
+
+export interface StorageFailureConfig {
+  /** Apply a failing write before reporting the failure, leaving the change in place. */
+  after_write?: boolean;
+  /** Operation kinds that fail; empty means both. */
+  ops?: string[];
+  /** Share of store operations that fail, 0.0-1.0. */
+  rate: number;
+}
+
+export interface StorageFailures {
+  after_write: boolean;
+  /** Operations failed since the last reset. */
+  injected: number;
+  ops: string[];
+  rate: number;
+}
+
+export interface StoreOpStats {
+  count: number;
+  /** Slowest call, in milliseconds */
+  max_ms: number;
+  /** Time spent in the operation, in milliseconds */
+  total_ms: number;
+}
+
+export interface StoreStats {
+  /** Estimated from the JSON encoding of IDs and records */
+  approx_bytes: number;
+  count: number;
+  /** Records evicted under the evict policy since the last reset */
+  evicted?: number;
+  /** The store is at a reject limit and writes are answered with 507 */
+  full?: boolean;
+  /** What the store does at its limit; absent without limits */
+  limit_policy?: string;
+  /** Estimated size limit in bytes (--store-max-mb); absent when unlimited */
+  max_bytes?: number;
+  /** Record limit (--store-max-records); absent when unlimited */
+  max_records?: number;
+  /** Operations since the last reset, keyed by name such as Get, Set, or Filter */
+  ops?: Record<string, StoreOpStats>;
+  /** Filters since the last reset that scanned more records than --store-scan-warn */
+  slow_scans?: number;
+  /** Go duration after which records expire; absent when they never do */
+  ttl?: string;
+}
+
+export interface TemplateResult {
+  status: string;
+  template: ResponseTemplate;
+}
+
+export interface Tenant {
+  credentials: Record<string, string>;
+  /** Request headers that authenticate as the tenant, e.g. {"Authorization": "Basic ..."} */
+  headers?: Record<string, string>;
+  id: string;
+  name?: string;
+}
+
+export interface TenantRequest {
+  /** Explicit credentials, e.g. {"api_key": "..."}; generated when omitted */
+  credentials?: Record<string, string>;
+  name?: string;
+}
+
+export interface TenantResult {
+  status: string;
+  tenant_id: string;
+}
+
+export interface TimeInfo {
+  duration?: string;
+  frozen?: boolean;
+  offset?: string;
+  real?: string;
+  simulated?: string;
+  status?: string;
+}
+
+export interface TriggerEventRequest {
+  /** Fields to set on the example payload. */
+  overrides?: Record<string, unknown>;
+  /** Event type from the catalog, e.g. "charge.succeeded". */
+  type: string;
+}
+
+export interface TriggerEventResult {
+  /** The emitted event, in the twin's provider format. */
+  event: Record<string, unknown>;
+  status: string;
+}
+
+export interface VersionInfo {
+  build_date: string;
+  commit: string;
+  go_version: string;
+  name: string;
+  twinkit_version: string;
+  version: string;
+}
+
+export interface WebhookCorrelation {
+  deliveries: WebhookDelivery[];
+  event: WebhookEvent;
+  /** pending: no attempt recorded yet, or no webhook URL configured. dropped: an injected webhook fault dropped the event. */
+  status: string;
+}
+
+export interface WebhookDelivery {
+  /** 0 for a delivery an injected fault dropped. */
+  attempt: number;
+  /** An injected webhook fault dropped the delivery; it was never sent. */
+  dropped?: boolean;
+  error?: string;
+  event_id: string;
+  /** ID of the injected webhook fault that delayed or dropped the delivery. */
+  fault?: string;
+  status_code: number;
+  /** Tenant whose request produced the event. */
+  tenant?: string;
+  timestamp: string;
+  url: string;
+}
+
+export interface WebhookEndpoint {
+  /** Signing secret. Empty keeps the twin's secret. */
+  secret?: string;
+  /** Absolute http(s) URL. Empty keeps the twin's webhook URL. */
+  url?: string;
+}
+
+export interface WebhookEvent {
+  created_at: string;
+  data: Record<string, unknown>;
+  id: string;
+  type: string;
+}
+
+export interface Webhooks {
+  deliveries: WebhookDelivery[];
+  queued: WebhookEvent[];
+  /** Failed events waiting to be redelivered, on twins that redeliver webhooks. */
+  redeliveries?: Redelivery[];
+}
+
+export interface AdminClientOptions {
+  /** fetch implementation; defaults to the global fetch. */
+  fetch?: typeof fetch;
+  /**
+   * Retries for connection errors and 502/503/504 responses (default 2).
+   * Calls that change state cumulatively are never retried.
+   */
+  retries?: number;
+  /** Delay before the first retry, doubled on every attempt (default 100). */
+  retryDelayMs?: number;
+}
+
+export interface RequestOptions {
+  signal?: AbortSignal;
+}
+
+export interface RawRequestOptions extends RequestOptions {
+  /** JSON-encoded unless it is a string, which is sent as-is. */
+  body?: unknown;
+  contentType?: string;
+  responseType?: "json" | "text";
+  retry?: boolean;
+}
+
+/** Error thrown when a twin answers with a non-2xx status. */
+export declare class AdminApiError extends Error {
+  readonly method: string;
+  readonly path: string;
+  readonly status: number;
+  /** Raw response body. */
+  readonly body: string;
+  /** error.message from the twin's JSON error body, if any. */
+  readonly apiMessage: string;
+  /** True for 404, which twins return for admin features they do not support. */
+  readonly isNotFound: boolean;
+}
+
+/** Client for one twin's /admin/* control plane. */
+export declare class AdminClient {
+  /** @param baseUrl Twin URL, e.g. "http://localhost:4111". */
+  constructor(baseUrl: string, options?: AdminClientOptions);
+
+  /** Client for a twin listening on localhost:port. */
+  static forPort(port: number, options?: AdminClientOptions): AdminClient;
+
+  readonly baseUrl: string;
+
+  /** Sends a request to any admin path; the escape hatch for endpoints without a method. */
+  request<T = unknown>(method: string, path: string, options?: RawRequestOptions): Promise<T>;
+
+  /**
+   * Record a scenario run in the audit log.
+   *
+   * wt test reports each scenario it runs to every twin. Allowed on twins
+   * started with --admin-readonly.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/audit/events`
+   */
+  recordAuditEvent(body: AuditEvent, options?: RequestOptions): Promise<AuditEntry>;
+
+  /**
+   * Hash-chained log of admin mutations and scenario runs.
+   *
+   * Every admin request other than GET and HEAD, and every scenario run reported
+   * to /admin/audit/events, is chained onto the log: each entry's hash covers
+   * its fields and the previous entry's hash, so any edit breaks the chain from
+   * that entry on. With --audit-key (or WT_AUDIT_KEY) the export is signed with
+   * an HMAC of its head. Resets do not clear the log.
+   *
+   * `GET /admin/audit/export`
+   */
+  auditExport(options?: RequestOptions): Promise<AuditExport>;
+
+  /**
+   * Breakpoints and the requests they hold.
+   *
+   * `GET /admin/breakpoints`
+   */
+  listBreakpoints(options?: RequestOptions): Promise<Breakpoint[]>;
+
+  /**
+   * Hold matching requests until released.
+   *
+   * API requests matching method and path are paused before the twin handles
+   * them, until released with POST /admin/breakpoints/{id}/release, so tests can
+   * interleave concurrent operations, such as two racing claims, without sleeps.
+   * A request still held after the breakpoint's timeout carries on by itself.
+   * Responses to held requests carry X-WT-Breakpoint. Admin and health requests
+   * are never held. Cleared by a full reset.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/breakpoints`
+   */
+  addBreakpoint(body: Breakpoint, options?: RequestOptions): Promise<Breakpoint>;
+
+  /**
+   * Remove a breakpoint, releasing the requests it holds.
+   *
+   * `DELETE /admin/breakpoints/{id}`
+   */
+  removeBreakpoint(id: string, options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Let requests held at a breakpoint carry on.
+   *
+   * Releases every held request, or the one with request_id, or the count
+   * oldest. With wait_for the release first waits, up to the breakpoint's
+   * timeout, until that many requests are held. The breakpoint stays set and
+   * holds later requests.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/breakpoints/{id}/release`
+   */
+  releaseBreakpoint(id: string, body?: BreakpointRelease, options?: RequestOptions): Promise<BreakpointReleaseResult>;
+
+  /**
+   * Runtime configuration.
+   *
+   * `GET /admin/config`
+   */
+  getConfig(options?: RequestOptions): Promise<Config>;
+
+  /**
+   * Update runtime configuration.
+   *
+   * `PUT /admin/config`
+   */
+  updateConfig(body: Config, options?: RequestOptions): Promise<ConfigResult>;
+
+  /**
+   * Link a request to the events and webhooks it produced.
+   *
+   * `GET /admin/correlations`
+   * @param requestId The ID the twin echoed in X-Request-Id (or the client sent in it), or a request log entry ID such as req_000001.
+   */
+  getCorrelations(requestId: string, options?: RequestOptions): Promise<Correlations>;
+
+  /**
+   * All generated webhook events.
+   *
+   * `GET /admin/events`
+   */
+  listEvents(options?: RequestOptions): Promise<WebhookEvent[]>;
+
+  /**
+   * Inject a fault.
+   *
+   * `POST /admin/fault/{endpoint}`
+   * @param endpoint Endpoint path the fault applies to, e.g. /v1/transfers. May contain slashes. webhook:<event type>, e.g. webhook:charge.succeeded, or webhook:* addresses the twin's webhook deliveries instead: the fault drops them, or with only delay_ms set delays them, and status_code, preset, body, and retry_after do not apply.
+   */
+  injectFault(endpoint: string, body: Fault, options?: RequestOptions): Promise<FaultResult>;
+
+  /**
+   * Remove a fault.
+   *
+   * `DELETE /admin/fault/{endpoint}`
+   * @param endpoint Endpoint path the fault applies to, e.g. /v1/transfers. May contain slashes. webhook:<event type>, e.g. webhook:charge.succeeded, or webhook:* addresses the twin's webhook deliveries instead: the fault drops them, or with only delay_ms set delays them, and status_code, preset, body, and retry_after do not apply.
+   */
+  removeFault(endpoint: string, options?: RequestOptions): Promise<FaultResult>;
+
+  /**
+   * List active faults.
+   *
+   * `GET /admin/faults`
+   */
+  listFaults(options?: RequestOptions): Promise<Record<string, Fault>>;
+
+  /**
+   * Get the storage failure mode.
+   *
+   * `GET /admin/faults/storage`
+   */
+  getStorageFailures(options?: RequestOptions): Promise<StorageFailures>;
+
+  /**
+   * Make a share of store operations fail.
+   *
+   * Failing operations answer the API request that made them with the provider's
+   * 500, leaving writes made earlier in the request in place. Admin requests are
+   * not affected. Cleared by a full reset.
+   *
+   * `PUT /admin/faults/storage`
+   */
+  setStorageFailures(body: StorageFailureConfig, options?: RequestOptions): Promise<StorageFailures>;
+
+  /**
+   * Turn storage failures off.
+   *
+   * `DELETE /admin/faults/storage`
+   */
+  clearStorageFailures(options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Health check.
+   *
+   * `GET /admin/health`
+   */
+  health(options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Intercepts and the responses they hold.
+   *
+   * `GET /admin/intercepts`
+   */
+  listIntercepts(options?: RequestOptions): Promise<Intercept[]>;
+
+  /**
+   * Hold matching responses until decided.
+   *
+   * Responses to API requests matching method and path are held after the twin
+   * has handled them, until each is approved, edited, or denied with POST
+   * /admin/intercepts/{id}/decide. wt intercept uses it to show responses in the
+   * terminal before the client gets them. A response still held after the
+   * intercept's timeout is returned as it is. Decided responses carry
+   * X-WT-Intercept. Admin and health responses are never held. Cleared by a full
+   * reset.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/intercepts`
+   */
+  addIntercept(body: Intercept, options?: RequestOptions): Promise<Intercept>;
+
+  /**
+   * Remove an intercept, returning the responses it holds as they are.
+   *
+   * `DELETE /admin/intercepts/{id}`
+   */
+  removeIntercept(id: string, options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Return a held response to its client.
+   *
+   * Decides the held response with request_id, or the oldest: approve returns it
+   * as it is, edit replaces its status code, headers, and body, and deny answers
+   * with an error instead. The intercept stays set and holds later responses.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/intercepts/{id}/decide`
+   */
+  decideIntercept(id: string, body: InterceptDecision, options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Admin API OpenAPI document.
+   *
+   * This document, as served by the twin. info.version is the admin protocol
+   * version the twin implements.
+   *
+   * `GET /admin/openapi.json`
+   */
+  openAPI(options?: RequestOptions): Promise<Record<string, unknown>>;
+
+  /**
+   * List quirks.
+   *
+   * `GET /admin/quirks`
+   */
+  listQuirks(options?: RequestOptions): Promise<Quirk[]>;
+
+  /**
+   * Enable a quirk.
+   *
+   * `PUT /admin/quirks/{quirk_id}`
+   */
+  enableQuirk(quirkId: string, options?: RequestOptions): Promise<QuirkResult>;
+
+  /**
+   * Disable a quirk.
+   *
+   * `DELETE /admin/quirks/{quirk_id}`
+   */
+  disableQuirk(quirkId: string, options?: RequestOptions): Promise<QuirkResult>;
+
+  /**
+   * List logged requests.
+   *
+   * `GET /admin/requests`
+   */
+  listRequests(options?: RequestOptions): Promise<RequestLogEntry[]>;
+
+  /**
+   * Replay a logged request.
+   *
+   * The twin must capture request bodies (--capture-bodies or capture_bodies in
+   * /admin/config).
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/requests/{id}/replay`
+   */
+  replayRequest(id: string, options?: RequestOptions): Promise<ReplayResult>;
+
+  /**
+   * Reset state.
+   *
+   * With no body, clears all state, the request log, faults, and the simulated
+   * clock. resources clears only the named resources; seed lands on a named seed
+   * preset. The two cannot be combined.
+   *
+   * `POST /admin/reset`
+   */
+  reset(body?: ResetRequest, options?: RequestOptions): Promise<ResetResult>;
+
+  /**
+   * List the twin's API routes.
+   *
+   * Every route the twin's API serves, sorted by route and method; admin routes
+   * are left out. Request log entries carry the route that served them, so tools
+   * such as wt report can tell which endpoints a test run exercised.
+   *
+   * `GET /admin/routes`
+   */
+  listRoutes(options?: RequestOptions): Promise<Route[]>;
+
+  /**
+   * Responses that differed from the shadow target.
+   *
+   * With --shadow-url (or shadow_url in /admin/config) the twin mirrors every
+   * non-admin request to another base URL and records where the responses
+   * differ. Cleared by a full reset.
+   *
+   * `GET /admin/shadow/diffs`
+   */
+  shadowDiffs(options?: RequestOptions): Promise<ShadowReport>;
+
+  /**
+   * Snapshot state.
+   *
+   * `GET /admin/state`
+   */
+  getState(options?: RequestOptions): Promise<State>;
+
+  /**
+   * Replace state.
+   *
+   * With expand=true, templates in JSON strings are expanded first: ${ENV_VAR}
+   * and ${ENV_VAR:-default} from the twin's environment, ${uuid}, and ${now},
+   * ${now+24h}, ${now-7d}, ${now:unix}, ${now:unix_ms} against the simulated
+   * clock. A string holding only a unix template becomes a number; $${ is a
+   * literal ${.
+   *
+   * `POST /admin/state`
+   */
+  loadState(body: State, options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Export state as NDJSON.
+   *
+   * Streams one StateRecord per line.
+   *
+   * `GET /admin/state/export`
+   */
+  exportState(options?: RequestOptions): Promise<string>;
+
+  /**
+   * Import NDJSON state.
+   *
+   * Upserts one StateRecord per line.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/state/import`
+   */
+  importState(body: string, options?: RequestOptions): Promise<ImportResult>;
+
+  /**
+   * Check a seed file without loading it.
+   *
+   * Checks that every reference between the seed's collections resolves, using
+   * the relations the twin declares. Relations whose target collection the seed
+   * omits are skipped. State is not changed.
+   *
+   * `POST /admin/state/lint`
+   */
+  lintState(body: State, options?: RequestOptions): Promise<SeedLintResult>;
+
+  /**
+   * Per-store record counts and size estimates.
+   *
+   * Expired records in stores with a TTL are purged first.
+   *
+   * `GET /admin/state/stats`
+   */
+  stateStats(options?: RequestOptions): Promise<StateStats>;
+
+  /**
+   * Response templates overriding how routes render.
+   *
+   * `GET /admin/templates`
+   */
+  listTemplates(options?: RequestOptions): Promise<ResponseTemplate[]>;
+
+  /**
+   * Override how a route's responses render.
+   *
+   * Successful JSON responses of the route are rendered through the template,
+   * with the response the twin would have sent available as record, and status,
+   * params (URL parameters), and query. A JSON body is a JSON template: a string
+   * that is a single {{record.field}} placeholder takes that value, of any type,
+   * and placeholders in longer strings are interpolated as text. A text/plain
+   * body is a Go text/template, e.g. {"id": {{json .record.id}}}. Templates
+   * survive resets.
+   *
+   * `PUT /admin/templates/{method}/{route}`
+   * @param method HTTP method of the route, e.g. GET.
+   * @param route Route pattern, e.g. /v1/customers/{id}. May contain slashes.
+   */
+  setTemplate(method: string, route: string, body: Record<string, unknown>, options?: RequestOptions): Promise<TemplateResult>;
+
+  /**
+   * Restore a route's own response rendering.
+   *
+   * `DELETE /admin/templates/{method}/{route}`
+   * @param method HTTP method of the route, e.g. GET.
+   * @param route Route pattern, e.g. /v1/customers/{id}. May contain slashes.
+   */
+  removeTemplate(method: string, route: string, options?: RequestOptions): Promise<Status>;
+
+  /**
+   * List tenants and their credentials.
+   *
+   * `GET /admin/tenants`
+   */
+  listTenants(options?: RequestOptions): Promise<Tenant[]>;
+
+  /**
+   * Create a tenant with fresh credentials.
+   *
+   * Mints an isolated account so parallel test jobs need not share seeded
+   * credentials. Credentials are generated unless given explicitly. Tenants are
+   * state: a reset removes them.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/tenants`
+   */
+  createTenant(body?: TenantRequest, options?: RequestOptions): Promise<Tenant>;
+
+  /**
+   * Delete a tenant and the records it owns.
+   *
+   * Lets a test job clean up the tenant it created without resetting the twin
+   * under other jobs.
+   *
+   * `DELETE /admin/tenants/{tenant_id}`
+   */
+  deleteTenant(tenantId: string, options?: RequestOptions): Promise<TenantResult>;
+
+  /**
+   * Real and simulated clocks.
+   *
+   * `GET /admin/time`
+   */
+  getTime(options?: RequestOptions): Promise<TimeInfo>;
+
+  /**
+   * Advance the simulated clock.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/time/advance`
+   */
+  advanceTime(body: AdvanceTimeRequest, options?: RequestOptions): Promise<TimeInfo>;
+
+  /**
+   * Freeze the simulated clock.
+   *
+   * Stops the clock at its current time until it is unfrozen. Advancing or
+   * setting a frozen clock moves it without unfreezing it.
+   *
+   * `POST /admin/time/freeze`
+   */
+  freezeTime(options?: RequestOptions): Promise<TimeInfo>;
+
+  /**
+   * Set the simulated clock.
+   *
+   * Moves the clock to an absolute time. A frozen clock stays frozen at the new
+   * time.
+   *
+   * `POST /admin/time/set`
+   */
+  setTime(body: SetTimeRequest, options?: RequestOptions): Promise<TimeInfo>;
+
+  /**
+   * Unfreeze the simulated clock.
+   *
+   * Restarts the clock from the instant it was frozen at.
+   *
+   * `POST /admin/time/unfreeze`
+   */
+  unfreezeTime(options?: RequestOptions): Promise<TimeInfo>;
+
+  /**
+   * Build metadata.
+   *
+   * `GET /admin/version`
+   */
+  version(options?: RequestOptions): Promise<VersionInfo>;
+
+  /**
+   * Queued webhooks and delivery attempts.
+   *
+   * `GET /admin/webhooks`
+   */
+  listWebhooks(options?: RequestOptions): Promise<Webhooks>;
+
+  /**
+   * Webhook events the twin gave up redelivering.
+   *
+   * Failed deliveries are retried with exponential backoff and jitter, following
+   * the twin's simulated clock; an event whose redeliveries all fail is
+   * dead-lettered.
+   *
+   * `GET /admin/webhooks/dead_letters`
+   */
+  listDeadLetters(options?: RequestOptions): Promise<DeadLetter[]>;
+
+  /**
+   * Attempt delivery of a dead-lettered event once more.
+   *
+   * On success the event leaves the dead-letter list; on failure it stays there.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/webhooks/dead_letters/{id}/retry`
+   */
+  retryDeadLetter(id: string, options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Per-tenant webhook endpoints.
+   *
+   * Events produced by a tenant's requests are delivered to its endpoint and
+   * signed with its secret; other events go to the twin's webhook URL.
+   *
+   * `GET /admin/webhooks/endpoints`
+   */
+  listWebhookEndpoints(options?: RequestOptions): Promise<Record<string, WebhookEndpoint>>;
+
+  /**
+   * Deliver a tenant's events to its own endpoint.
+   *
+   * Endpoints are configuration: they survive resets. Seeds can set them too,
+   * under a top-level webhook_endpoints object keyed by tenant.
+   *
+   * `PUT /admin/webhooks/endpoints/{tenant}`
+   * @param tenant Tenant ID, e.g. a connected account's acct_ ID.
+   */
+  setWebhookEndpoint(tenant: string, body: WebhookEndpoint, options?: RequestOptions): Promise<{
+    endpoint?: WebhookEndpoint;
+    status?: string;
+    tenant?: string;
+  }>;
+
+  /**
+   * Send a tenant's events back to the twin's webhook URL.
+   *
+   * `DELETE /admin/webhooks/endpoints/{tenant}`
+   * @param tenant Tenant ID, e.g. a connected account's acct_ ID.
+   */
+  removeWebhookEndpoint(tenant: string, options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Event types the twin can emit, with example payloads.
+   *
+   * `GET /admin/webhooks/events/catalog`
+   */
+  listEventTypes(options?: RequestOptions): Promise<EventType[]>;
+
+  /**
+   * Deliver queued webhooks now.
+   *
+   * `POST /admin/webhooks/flush`
+   */
+  flushWebhooks(options?: RequestOptions): Promise<Status>;
+
+  /**
+   * Emit a webhook event without performing the API action behind it.
+   *
+   * Emits an event of a catalog type carrying the type's example payload, with
+   * overrides merged in key by key (nested objects are merged, other values
+   * replaced). The event is recorded and delivered like any other.
+   *
+   * Never retried: the call changes state cumulatively.
+   *
+   * `POST /admin/webhooks/trigger`
+   */
+  triggerEvent(body: TriggerEventRequest, options?: RequestOptions): Promise<TriggerEventResult>;
+}
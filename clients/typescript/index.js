@@ -0,0 +1,386 @@
+// Code generated by gen-adminclient from admin-api.openapi.json. DO NOT EDIT.
+
+/** Error thrown when a twin answers with a non-2xx status. */
+export class AdminApiError extends Error {
+  constructor(method, path, status, body) {
+    let apiMessage = "";
+    try {
+      apiMessage = JSON.parse(body)?.error?.message ?? "";
+    } catch {
+      // Not a JSON error envelope; fall back to the raw body.
+    }
+    super(`${method} ${path} returned status ${status}: ${apiMessage || body.trim()}`);
+    this.name = "AdminApiError";
+    this.method = method;
+    this.path = path;
+    this.status = status;
+    this.body = body;
+    this.apiMessage = apiMessage;
+  }
+
+  get isNotFound() {
+    return this.status === 404;
+  }
+}
+
+const RETRYABLE_STATUS = new Set([502, 503, 504]);
+
+function segment(value) {
+  return encodeURIComponent(String(value));
+}
+
+function wildcard(value) {
+  return String(value).replace(/^\/+/, "").split("/").map(encodeURIComponent).join("/");
+}
+
+function sleep(ms, signal) {
+  return new Promise((resolve, reject) => {
+    if (signal?.aborted) {
+      reject(signal.reason);
+      return;
+    }
+    const timer = setTimeout(resolve, ms);
+    signal?.addEventListener("abort", () => {
+      clearTimeout(timer);
+      reject(signal.reason);
+    }, { once: true });
+  });
+}
+
+/** Client for one twin's /admin/* control plane. */
+export class AdminClient {
+  constructor(baseUrl, options = {}) {
+    this.baseUrl = String(baseUrl).replace(/\/+$/, "");
+    this.fetch = options.fetch ?? globalThis.fetch.bind(globalThis);
+    this.retries = options.retries ?? 2;
+    this.retryDelayMs = options.retryDelayMs ?? 100;
+  }
+
+  static forPort(port, options) {
+    return new AdminClient(`http://localhost:${port}`, options);
+  }
+
+  async request(method, path, options = {}) {
+    const { body, contentType = "application/json", responseType = "json", retry = true, signal } = options;
+    const init = { method, headers: {}, signal };
+    if (body !== undefined) {
+      init.body = typeof body === "string" ? body : JSON.stringify(body);
+      init.headers["Content-Type"] = contentType;
+    }
+
+    const attempts = retry ? this.retries + 1 : 1;
+    let delay = this.retryDelayMs;
+    for (let attempt = 1; ; attempt++) {
+      let res;
+      try {
+        res = await this.fetch(this.baseUrl + path, init);
+      } catch (err) {
+        // The twin is unreachable, e.g. still starting.
+        if (signal?.aborted || attempt >= attempts) {
+          throw err;
+        }
+        await sleep(delay, signal);
+        delay *= 2;
+        continue;
+      }
+
+      const text = await res.text();
+      if (res.ok) {
+        if (responseType === "text") {
+          return text;
+        }
+        return text.trim() === "" ? undefined : JSON.parse(text);
+      }
+      if (attempt >= attempts || !RETRYABLE_STATUS.has(res.status)) {
+        throw new AdminApiError(method, path, res.status, text);
+      }
+      await sleep(delay, signal);
+      delay *= 2;
+    }
+  }
+
+  // POST /admin/audit/events
+  recordAuditEvent(body, options = {}) {
+    return this.request("POST", "/admin/audit/events", { ...options, body, retry: false });
+  }
+
+  // GET /admin/audit/export
+  auditExport(options = {}) {
+    return this.request("GET", "/admin/audit/export", { ...options });
+  }
+
+  // GET /admin/breakpoints
+  listBreakpoints(options = {}) {
+    return this.request("GET", "/admin/breakpoints", { ...options });
+  }
+
+  // POST /admin/breakpoints
+  addBreakpoint(body, options = {}) {
+    return this.request("POST", "/admin/breakpoints", { ...options, body, retry: false });
+  }
+
+  // DELETE /admin/breakpoints/{id}
+  removeBreakpoint(id, options = {}) {
+    return this.request("DELETE", `/admin/breakpoints/${segment(id)}`, { ...options });
+  }
+
+  // POST /admin/breakpoints/{id}/release
+  releaseBreakpoint(id, body, options = {}) {
+    return this.request("POST", `/admin/breakpoints/${segment(id)}/release`, { ...options, body, retry: false });
+  }
+
+  // GET /admin/config
+  getConfig(options = {}) {
+    return this.request("GET", "/admin/config", { ...options });
+  }
+
+  // PUT /admin/config
+  updateConfig(body, options = {}) {
+    return this.request("PUT", "/admin/config", { ...options, body });
+  }
+
+  // GET /admin/correlations
+  getCorrelations(requestId, options = {}) {
+    return this.request("GET", `/admin/correlations?request_id=${segment(requestId)}`, { ...options });
+  }
+
+  // GET /admin/events
+  listEvents(options = {}) {
+    return this.request("GET", "/admin/events", { ...options });
+  }
+
+  // POST /admin/fault/{endpoint}
+  injectFault(endpoint, body, options = {}) {
+    return this.request("POST", `/admin/fault/${wildcard(endpoint)}`, { ...options, body });
+  }
+
+  // DELETE /admin/fault/{endpoint}
+  removeFault(endpoint, options = {}) {
+    return this.request("DELETE", `/admin/fault/${wildcard(endpoint)}`, { ...options });
+  }
+
+  // GET /admin/faults
+  listFaults(options = {}) {
+    return this.request("GET", "/admin/faults", { ...options });
+  }
+
+  // GET /admin/faults/storage
+  getStorageFailures(options = {}) {
+    return this.request("GET", "/admin/faults/storage", { ...options });
+  }
+
+  // PUT /admin/faults/storage
+  setStorageFailures(body, options = {}) {
+    return this.request("PUT", "/admin/faults/storage", { ...options, body });
+  }
+
+  // DELETE /admin/faults/storage
+  clearStorageFailures(options = {}) {
+    return this.request("DELETE", "/admin/faults/storage", { ...options });
+  }
+
+  // GET /admin/health
+  health(options = {}) {
+    return this.request("GET", "/admin/health", { ...options });
+  }
+
+  // GET /admin/intercepts
+  listIntercepts(options = {}) {
+    return this.request("GET", "/admin/intercepts", { ...options });
+  }
+
+  // POST /admin/intercepts
+  addIntercept(body, options = {}) {
+    return this.request("POST", "/admin/intercepts", { ...options, body, retry: false });
+  }
+
+  // DELETE /admin/intercepts/{id}
+  removeIntercept(id, options = {}) {
+    return this.request("DELETE", `/admin/intercepts/${segment(id)}`, { ...options });
+  }
+
+  // POST /admin/intercepts/{id}/decide
+  decideIntercept(id, body, options = {}) {
+    return this.request("POST", `/admin/intercepts/${segment(id)}/decide`, { ...options, body, retry: false });
+  }
+
+  // GET /admin/openapi.json
+  openAPI(options = {}) {
+    return this.request("GET", "/admin/openapi.json", { ...options });
+  }
+
+  // GET /admin/quirks
+  listQuirks(options = {}) {
+    return this.request("GET", "/admin/quirks", { ...options });
+  }
+
+  // PUT /admin/quirks/{quirk_id}
+  enableQuirk(quirkId, options = {}) {
+    return this.request("PUT", `/admin/quirks/${segment(quirkId)}`, { ...options });
+  }
+
+  // DELETE /admin/quirks/{quirk_id}
+  disableQuirk(quirkId, options = {}) {
+    return this.request("DELETE", `/admin/quirks/${segment(quirkId)}`, { ...options });
+  }
+
+  // GET /admin/requests
+  listRequests(options = {}) {
+    return this.request("GET", "/admin/requests", { ...options });
+  }
+
+  // POST /admin/requests/{id}/replay
+  replayRequest(id, options = {}) {
+    return this.request("POST", `/admin/requests/${segment(id)}/replay`, { ...options, retry: false });
+  }
+
+  // POST /admin/reset
+  reset(body, options = {}) {
+    return this.request("POST", "/admin/reset", { ...options, body });
+  }
+
+  // GET /admin/routes
+  listRoutes(options = {}) {
+    return this.request("GET", "/admin/routes", { ...options });
+  }
+
+  // GET /admin/shadow/diffs
+  shadowDiffs(options = {}) {
+    return this.request("GET", "/admin/shadow/diffs", { ...options });
+  }
+
+  // GET /admin/state
+  getState(options = {}) {
+    return this.request("GET", "/admin/state", { ...options });
+  }
+
+  // POST /admin/state
+  loadState(body, options = {}) {
+    return this.request("POST", "/admin/state", { ...options, body });
+  }
+
+  // GET /admin/state/export
+  exportState(options = {}) {
+    return this.request("GET", "/admin/state/export", { ...options, responseType: "text" });
+  }
+
+  // POST /admin/state/import
+  importState(body, options = {}) {
+    return this.request("POST", "/admin/state/import", { ...options, body, contentType: "application/x-ndjson", retry: false });
+  }
+
+  // POST /admin/state/lint
+  lintState(body, options = {}) {
+    return this.request("POST", "/admin/state/lint", { ...options, body });
+  }
+
+  // GET /admin/state/stats
+  stateStats(options = {}) {
+    return this.request("GET", "/admin/state/stats", { ...options });
+  }
+
+  // GET /admin/templates
+  listTemplates(options = {}) {
+    return this.request("GET", "/admin/templates", { ...options });
+  }
+
+  // PUT /admin/templates/{method}/{route}
+  setTemplate(method, route, body, options = {}) {
+    return this.request("PUT", `/admin/templates/${segment(method)}/${wildcard(route)}`, { ...options, body });
+  }
+
+  // DELETE /admin/templates/{method}/{route}
+  removeTemplate(method, route, options = {}) {
+    return this.request("DELETE", `/admin/templates/${segment(method)}/${wildcard(route)}`, { ...options });
+  }
+
+  // GET /admin/tenants
+  listTenants(options = {}) {
+    return this.request("GET", "/admin/tenants", { ...options });
+  }
+
+  // POST /admin/tenants
+  createTenant(body, options = {}) {
+    return this.request("POST", "/admin/tenants", { ...options, body, retry: false });
+  }
+
+  // DELETE /admin/tenants/{tenant_id}
+  deleteTenant(tenantId, options = {}) {
+    return this.request("DELETE", `/admin/tenants/${segment(tenantId)}`, { ...options });
+  }
+
+  // GET /admin/time
+  getTime(options = {}) {
+    return this.request("GET", "/admin/time", { ...options });
+  }
+
+  // POST /admin/time/advance
+  advanceTime(body, options = {}) {
+    return this.request("POST", "/admin/time/advance", { ...options, body, retry: false });
+  }
+
+  // POST /admin/time/freeze
+  freezeTime(options = {}) {
+    return this.request("POST", "/admin/time/freeze", { ...options });
+  }
+
+  // POST /admin/time/set
+  setTime(body, options = {}) {
+    return this.request("POST", "/admin/time/set", { ...options, body });
+  }
+
+  // POST /admin/time/unfreeze
+  unfreezeTime(options = {}) {
+    return this.request("POST", "/admin/time/unfreeze", { ...options });
+  }
+
+  // GET /admin/version
+  version(options = {}) {
+    return this.request("GET", "/admin/version", { ...options });
+  }
+
+  // GET /admin/webhooks
+  listWebhooks(options = {}) {
+    return this.request("GET", "/admin/webhooks", { ...options });
+  }
+
+  // GET /admin/webhooks/dead_letters
+  listDeadLetters(options = {}) {
+    return this.request("GET", "/admin/webhooks/dead_letters", { ...options });
+  }
+
+  // POST /admin/webhooks/dead_letters/{id}/retry
+  retryDeadLetter(id, options = {}) {
+    return this.request("POST", `/admin/webhooks/dead_letters/${segment(id)}/retry`, { ...options, retry: false });
+  }
+
+  // GET /admin/webhooks/endpoints
+  listWebhookEndpoints(options = {}) {
+    return this.request("GET", "/admin/webhooks/endpoints", { ...options });
+  }
+
+  // PUT /admin/webhooks/endpoints/{tenant}
+  setWebhookEndpoint(tenant, body, options = {}) {
+    return this.request("PUT", `/admin/webhooks/endpoints/${segment(tenant)}`, { ...options, body });
+  }
+
+  // DELETE /admin/webhooks/endpoints/{tenant}
+  removeWebhookEndpoint(tenant, options = {}) {
+    return this.request("DELETE", `/admin/webhooks/endpoints/${segment(tenant)}`, { ...options });
+  }
+
+  // GET /admin/webhooks/events/catalog
+  listEventTypes(options = {}) {
+    return this.request("GET", "/admin/webhooks/events/catalog", { ...options });
+  }
+
+  // POST /admin/webhooks/flush
+  flushWebhooks(options = {}) {
+    return this.request("POST", "/admin/webhooks/flush", { ...options });
+  }
+
+  // POST /admin/webhooks/trigger
+  triggerEvent(body, options = {}) {
+    return this.request("POST", "/admin/webhooks/trigger", { ...options, body, retry: false });
+  }
+}
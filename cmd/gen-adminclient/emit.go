@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	mediaJSON   = "application/json"
+	mediaNDJSON = "application/x-ndjson"
+)
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// tsGlobals are names a schema must not take, since an exported interface
+// of the same name would shadow the global inside index.d.ts.
+var tsGlobals = map[string]bool{
+	"Array": true, "Date": true, "Error": true, "Map": true, "Object": true,
+	"Promise": true, "Record": true, "Request": true, "Response": true, "Set": true,
+}
+
+// generate renders the client files, keyed by file name.
+func generate(spec *Spec, source string) (map[string]string, error) {
+	for name := range spec.Components.Schemas {
+		if tsGlobals[name] {
+			return nil, fmt.Errorf("schema %q shadows a TypeScript global; rename it", name)
+		}
+	}
+
+	ops := operations(spec)
+	seen := make(map[string]bool)
+	for _, o := range ops {
+		if o.OperationID == "" {
+			return nil, fmt.Errorf("%s %s: missing operationId", o.Method, o.Path)
+		}
+		if seen[o.OperationID] {
+			return nil, fmt.Errorf("%s %s: duplicate operationId %q", o.Method, o.Path, o.OperationID)
+		}
+		seen[o.OperationID] = true
+	}
+
+	header := fmt.Sprintf("// Code generated by gen-adminclient from %s. DO NOT EDIT.\n", source)
+
+	var js bytes.Buffer
+	js.WriteString(header)
+	js.WriteString(jsRuntime)
+	for _, o := range ops {
+		if err := writeJSMethod(&js, o); err != nil {
+			return nil, err
+		}
+	}
+	js.WriteString("}\n")
+
+	var dts bytes.Buffer
+	dts.WriteString(header)
+	fmt.Fprintf(&dts, "//\n// %s %s\n\n", spec.Info.Title, spec.Info.Version)
+	for _, name := range sortedKeys(spec.Components.Schemas) {
+		writeTSType(&dts, name, spec.Components.Schemas[name])
+	}
+	dts.WriteString(dtsRuntime)
+	for _, o := range ops {
+		if err := writeTSMethod(&dts, o); err != nil {
+			return nil, err
+		}
+	}
+	dts.WriteString("}\n")
+
+	return map[string]string{
+		"index.js":   js.String(),
+		"index.d.ts": dts.String(),
+	}, nil
+}
+
+// ---------------------------------------------------------------------------
+// Methods
+// ---------------------------------------------------------------------------
+
+// signature holds what both the JS method and its declaration need.
+type signature struct {
+	args        []string // JS argument names, in order
+	argTypes    []string // matching TS types
+	argDocs     []string // matching @param text
+	urlExpr     string   // JS template literal for the request path
+	body        string   // TS body type, "" if the operation takes none
+	bodyOpt     bool
+	contentType string
+	result      string // TS result type
+	text        bool   // response is returned as text
+}
+
+func signatureOf(o op) (signature, error) {
+	var sig signature
+
+	params := make(map[string]Parameter)
+	for _, p := range o.Params {
+		if p.In == "path" {
+			params[p.Name] = p
+		}
+	}
+	var missing error
+	path := pathParam.ReplaceAllStringFunc(o.Path, func(m string) string {
+		name := m[1 : len(m)-1]
+		p, ok := params[name]
+		if !ok {
+			missing = fmt.Errorf("%s %s: path parameter %q is not declared", o.Method, o.Path, name)
+			return m
+		}
+		arg := camel(name)
+		sig.args = append(sig.args, arg)
+		sig.argTypes = append(sig.argTypes, "string")
+		sig.argDocs = append(sig.argDocs, p.Description)
+		if p.Wildcard {
+			return "${wildcard(" + arg + ")}"
+		}
+		return "${segment(" + arg + ")}"
+	})
+	if missing != nil {
+		return sig, missing
+	}
+	var query []string
+	for _, p := range o.Params {
+		if p.In == "query" && p.Required {
+			arg := camel(p.Name)
+			sig.args = append(sig.args, arg)
+			sig.argTypes = append(sig.argTypes, "string")
+			sig.argDocs = append(sig.argDocs, p.Description)
+			query = append(query, p.Name+"=${segment("+arg+")}")
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+	sig.urlExpr = "`" + path + "`"
+	if len(sig.args) == 0 {
+		sig.urlExpr = fmt.Sprintf("%q", o.Path)
+	}
+
+	if rb := o.RequestBody; rb != nil {
+		switch {
+		case rb.Content[mediaJSON].Schema != nil:
+			sig.body = tsType(rb.Content[mediaJSON].Schema, "  ")
+		case rb.Content[mediaNDJSON].Schema != nil:
+			sig.body = "string"
+			sig.contentType = mediaNDJSON
+		default:
+			return sig, fmt.Errorf("%s %s: unsupported request body content type", o.Method, o.Path)
+		}
+		sig.bodyOpt = !rb.Required
+	}
+
+	sig.result = "void"
+	for _, code := range sortedKeys(o.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		content := o.Responses[code].Content
+		switch {
+		case content[mediaJSON].Schema != nil:
+			sig.result = tsType(content[mediaJSON].Schema, "  ")
+		case content[mediaNDJSON].Schema != nil:
+			sig.result = "string"
+			sig.text = true
+		}
+		break
+	}
+	return sig, nil
+}
+
+func writeJSMethod(w *bytes.Buffer, o op) error {
+	sig, err := signatureOf(o)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{}, sig.args...)
+	opts := []string{"...options"}
+	if sig.body != "" {
+		args = append(args, "body")
+		opts = append(opts, "body")
+	}
+	args = append(args, "options = {}")
+	if sig.contentType != "" {
+		opts = append(opts, fmt.Sprintf("contentType: %q", sig.contentType))
+	}
+	if sig.text {
+		opts = append(opts, `responseType: "text"`)
+	}
+	if o.Retry != nil && !*o.Retry {
+		opts = append(opts, "retry: false")
+	}
+
+	fmt.Fprintf(w, "\n  // %s %s\n", o.Method, o.Path)
+	fmt.Fprintf(w, "  %s(%s) {\n", o.OperationID, strings.Join(args, ", "))
+	fmt.Fprintf(w, "    return this.request(%q, %s, { %s });\n", o.Method, sig.urlExpr, strings.Join(opts, ", "))
+	w.WriteString("  }\n")
+	return nil
+}
+
+func writeTSMethod(w *bytes.Buffer, o op) error {
+	sig, err := signatureOf(o)
+	if err != nil {
+		return err
+	}
+
+	w.WriteString("\n  /**\n")
+	fmt.Fprintf(w, "   * %s.\n", o.Summary)
+	if o.Description != "" {
+		w.WriteString("   *\n")
+		writeDocLines(w, "   * ", o.Description)
+	}
+	if o.Retry != nil && !*o.Retry {
+		w.WriteString("   *\n   * Never retried: the call changes state cumulatively.\n")
+	}
+	fmt.Fprintf(w, "   *\n   * `%s %s`\n", o.Method, o.Path)
+	for i, arg := range sig.args {
+		if sig.argDocs[i] != "" {
+			fmt.Fprintf(w, "   * @param %s %s\n", arg, sig.argDocs[i])
+		}
+	}
+	w.WriteString("   */\n")
+
+	var params []string
+	for i, arg := range sig.args {
+		params = append(params, arg+": "+sig.argTypes[i])
+	}
+	if sig.body != "" {
+		if sig.bodyOpt {
+			params = append(params, "body?: "+sig.body)
+		} else {
+			params = append(params, "body: "+sig.body)
+		}
+	}
+	params = append(params, "options?: RequestOptions")
+	fmt.Fprintf(w, "  %s(%s): Promise<%s>;\n", o.OperationID, strings.Join(params, ", "), sig.result)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Types
+// ---------------------------------------------------------------------------
+
+func writeTSType(w *bytes.Buffer, name string, s *Schema) {
+	if s.Description != "" {
+		w.WriteString("/**\n")
+		writeDocLines(w, " * ", s.Description)
+		w.WriteString(" */\n")
+	}
+	if len(s.Properties) > 0 {
+		fmt.Fprintf(w, "export interface %s %s\n\n", name, objectType(s, ""))
+		return
+	}
+	fmt.Fprintf(w, "export type %s = %s;\n\n", name, tsType(s, ""))
+}
+
+// tsType returns the TypeScript type for s. indent is the indentation of
+// the line the type starts on, used for nested object literals.
+func tsType(s *Schema, indent string) string {
+	if s == nil {
+		return "unknown"
+	}
+	if s.Ref != "" {
+		return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	}
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return tsType(s.Items, indent) + "[]"
+	case "object":
+		return objectType(s, indent)
+	}
+	if len(s.Properties) > 0 {
+		return objectType(s, indent)
+	}
+	return "unknown"
+}
+
+func objectType(s *Schema, indent string) string {
+	extra := additionalProperties(s)
+	if len(s.Properties) == 0 {
+		if extra == nil {
+			return "Record<string, unknown>"
+		}
+		return "Record<string, " + tsType(extra, indent) + ">"
+	}
+
+	required := make(map[string]bool)
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	inner := indent + "  "
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		if prop.Description != "" {
+			fmt.Fprintf(&b, "%s/** %s */\n", inner, prop.Description)
+		}
+		opt := "?"
+		if required[name] {
+			opt = ""
+		}
+		fmt.Fprintf(&b, "%s%s%s: %s;\n", inner, name, opt, tsType(prop, inner))
+	}
+	if extra != nil {
+		fmt.Fprintf(&b, "%s[key: string]: unknown;\n", inner)
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// additionalProperties returns the schema for extra object keys, an empty
+// schema for "true", or nil when extra keys are not declared.
+func additionalProperties(s *Schema) *Schema {
+	raw := strings.TrimSpace(string(s.AdditionalProperties))
+	switch {
+	case raw == "" || raw == "false":
+		return nil
+	case raw == "true":
+		return &Schema{}
+	}
+	var extra Schema
+	if err := json.Unmarshal([]byte(raw), &extra); err != nil {
+		return &Schema{}
+	}
+	return &extra
+}
+
+func writeDocLines(w *bytes.Buffer, prefix, text string) {
+	for _, line := range wrap(text, 76) {
+		w.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+	}
+}
+
+// wrap splits text into lines of at most width characters at word breaks.
+func wrap(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,210 @@
+// Command gen-adminclient generates the TypeScript/JavaScript admin client
+// published to npm from the admin API OpenAPI spec. It writes an ES module
+// (index.js) and its type declarations (index.d.ts) so Node test suites can
+// use the client without a build step:
+//
+//	go run ./cmd/gen-adminclient
+//
+// It also copies the spec into twinkit/admin, which embeds it and serves it
+// at GET /admin/openapi.json (twinkit is a separate module and cannot embed
+// files from schemas/). The generated files are committed; main_test.go
+// fails when they drift from the spec.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Spec is the subset of an OpenAPI 3.0 document the generator reads.
+type Spec struct {
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// PathItem holds the operations and shared parameters of one path.
+type PathItem struct {
+	Parameters []Parameter `json:"parameters"`
+	Get        *Operation  `json:"get"`
+	Post       *Operation  `json:"post"`
+	Put        *Operation  `json:"put"`
+	Delete     *Operation  `json:"delete"`
+}
+
+// Operation is one method on a path.
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Parameters  []Parameter         `json:"parameters"`
+	RequestBody *RequestBody        `json:"requestBody"`
+	Responses   map[string]Response `json:"responses"`
+
+	// Retry is x-wt-retry; false marks operations that change state
+	// cumulatively and must never be retried.
+	Retry *bool `json:"x-wt-retry"`
+}
+
+// Parameter is a path or query parameter. Path parameters and required
+// query parameters become method arguments; optional query parameters are
+// not generated. Wildcard (x-wt-wildcard) parameters may contain slashes,
+// which are kept rather than escaped.
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Wildcard    bool   `json:"x-wt-wildcard"`
+}
+
+// RequestBody describes an operation's request payload.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response status.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType holds the schema for one content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of JSON Schema used by the admin spec.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "gen-adminclient: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("gen-adminclient", flag.ContinueOnError)
+	specPath := fs.String("spec", "schemas/admin-api.openapi.json", "path to the admin API OpenAPI spec")
+	outDir := fs.String("out", "clients/typescript", "directory to write index.js and index.d.ts into")
+	embedPath := fs.String("embed", "twinkit/admin/openapi.json", "where to copy the spec for twins to serve (empty to skip)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	spec, err := loadSpec(*specPath)
+	if err != nil {
+		return fmt.Errorf("loading spec: %w", err)
+	}
+	if *embedPath != "" {
+		data, err := os.ReadFile(*specPath)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*embedPath, data, 0o644); err != nil {
+			return err
+		}
+	}
+	files, err := generate(spec, filepath.Base(*specPath))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(files) {
+		if err := os.WriteFile(filepath.Join(*outDir, name), []byte(files[name]), 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Generated admin client in %s (%d operations)\n", *outDir, len(operations(spec)))
+	return nil
+}
+
+func loadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// op is an operation flattened with its path and method.
+type op struct {
+	Path   string
+	Method string
+	*Operation
+	Params []Parameter
+}
+
+// operations returns the spec's operations sorted by path, then method, so
+// generated output is stable.
+func operations(spec *Spec) []op {
+	var ops []op
+	for _, path := range sortedKeys(spec.Paths) {
+		item := spec.Paths[path]
+		for _, m := range []struct {
+			method string
+			op     *Operation
+		}{
+			{"GET", item.Get},
+			{"POST", item.Post},
+			{"PUT", item.Put},
+			{"DELETE", item.Delete},
+		} {
+			if m.op == nil {
+				continue
+			}
+			params := append(append([]Parameter{}, item.Parameters...), m.op.Parameters...)
+			ops = append(ops, op{Path: path, Method: m.method, Operation: m.op, Params: params})
+		}
+	}
+	return ops
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// camel converts a snake_case parameter name to camelCase.
+func camel(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const specPath = "../../schemas/admin-api.openapi.json"
+
+// TestGeneratedClientUpToDate fails when clients/typescript or the spec
+// copy twins serve was not regenerated after the spec or the generator
+// changed.
+func TestGeneratedClientUpToDate(t *testing.T) {
+	spec, err := loadSpec(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := generate(spec, filepath.Base(specPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join("../../clients/typescript", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("clients/typescript/%s is out of date; run make gen-adminclient", name)
+		}
+	}
+
+	want, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../../twinkit/admin/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Error("twinkit/admin/openapi.json is out of date; run make gen-adminclient")
+	}
+}
+
+func TestGenerateMethods(t *testing.T) {
+	spec := parseSpec(t, `{
+		"paths": {
+			"/admin/fault/{endpoint}": {
+				"parameters": [{"name": "endpoint", "in": "path", "x-wt-wildcard": true}],
+				"post": {
+					"operationId": "injectFault",
+					"summary": "Inject a fault",
+					"requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Fault"}}}},
+					"responses": {"200": {"content": {"application/json": {"schema": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/Fault"}}}}}}
+				}
+			},
+			"/admin/quirks/{quirk_id}": {
+				"parameters": [{"name": "quirk_id", "in": "path"}],
+				"put": {"operationId": "enableQuirk", "summary": "Enable a quirk", "responses": {"200": {}}}
+			},
+			"/admin/correlations": {
+				"get": {
+					"operationId": "getCorrelations",
+					"parameters": [{"name": "request_id", "in": "query", "required": true}, {"name": "verbose", "in": "query"}],
+					"responses": {"200": {}}
+				}
+			},
+			"/admin/time/advance": {
+				"post": {
+					"operationId": "advanceTime",
+					"summary": "Advance time",
+					"x-wt-retry": false,
+					"requestBody": {"content": {"application/json": {"schema": {"type": "object"}}}},
+					"responses": {"200": {}}
+				}
+			}
+		},
+		"components": {"schemas": {"Fault": {"type": "object", "required": ["status_code"], "properties": {"status_code": {"type": "integer"}, "rate": {"type": "number"}}}}}
+	}`)
+
+	files, err := generate(spec, "spec.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, dts := files["index.js"], files["index.d.ts"]
+
+	for _, want := range []string{
+		"injectFault(endpoint, body, options = {}) {\n    return this.request(\"POST\", `/admin/fault/${wildcard(endpoint)}`, { ...options, body });",
+		"enableQuirk(quirkId, options = {}) {\n    return this.request(\"PUT\", `/admin/quirks/${segment(quirkId)}`, { ...options });",
+		`return this.request("POST", "/admin/time/advance", { ...options, body, retry: false });`,
+		"getCorrelations(requestId, options = {}) {\n    return this.request(\"GET\", `/admin/correlations?request_id=${segment(requestId)}`, { ...options });",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("index.js missing %q", want)
+		}
+	}
+	for _, want := range []string{
+		"export interface Fault {\n  rate?: number;\n  status_code: number;\n}",
+		"injectFault(endpoint: string, body: Fault, options?: RequestOptions): Promise<Record<string, Fault>>;",
+		"enableQuirk(quirkId: string, options?: RequestOptions): Promise<void>;",
+		"advanceTime(body?: Record<string, unknown>, options?: RequestOptions): Promise<void>;",
+	} {
+		if !strings.Contains(dts, want) {
+			t.Errorf("index.d.ts missing %q", want)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{
+			name: "missing operationId",
+			spec: `{"paths": {"/admin/health": {"get": {"responses": {}}}}}`,
+			want: "missing operationId",
+		},
+		{
+			name: "undeclared path parameter",
+			spec: `{"paths": {"/admin/quirks/{id}": {"put": {"operationId": "enableQuirk", "responses": {}}}}}`,
+			want: `path parameter "id" is not declared`,
+		},
+		{
+			name: "schema shadows global",
+			spec: `{"paths": {}, "components": {"schemas": {"Error": {"type": "object"}}}}`,
+			want: "shadows a TypeScript global",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generate(parseSpec(t, tt.spec), "spec.json")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func parseSpec(t *testing.T, doc string) *Spec {
+	t.Helper()
+	var spec Spec
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		t.Fatal(err)
+	}
+	return &spec
+}
@@ -0,0 +1,158 @@
+package main
+
+// jsRuntime is the hand-written part of index.js: errors, retries, and the
+// request helper the generated methods call. The generated methods and the
+// closing brace of AdminClient follow it.
+const jsRuntime = `
+/** Error thrown when a twin answers with a non-2xx status. */
+export class AdminApiError extends Error {
+  constructor(method, path, status, body) {
+    let apiMessage = "";
+    try {
+      apiMessage = JSON.parse(body)?.error?.message ?? "";
+    } catch {
+      // Not a JSON error envelope; fall back to the raw body.
+    }
+    super(` + "`${method} ${path} returned status ${status}: ${apiMessage || body.trim()}`" + `);
+    this.name = "AdminApiError";
+    this.method = method;
+    this.path = path;
+    this.status = status;
+    this.body = body;
+    this.apiMessage = apiMessage;
+  }
+
+  get isNotFound() {
+    return this.status === 404;
+  }
+}
+
+const RETRYABLE_STATUS = new Set([502, 503, 504]);
+
+function segment(value) {
+  return encodeURIComponent(String(value));
+}
+
+function wildcard(value) {
+  return String(value).replace(/^\/+/, "").split("/").map(encodeURIComponent).join("/");
+}
+
+function sleep(ms, signal) {
+  return new Promise((resolve, reject) => {
+    if (signal?.aborted) {
+      reject(signal.reason);
+      return;
+    }
+    const timer = setTimeout(resolve, ms);
+    signal?.addEventListener("abort", () => {
+      clearTimeout(timer);
+      reject(signal.reason);
+    }, { once: true });
+  });
+}
+
+/** Client for one twin's /admin/* control plane. */
+export class AdminClient {
+  constructor(baseUrl, options = {}) {
+    this.baseUrl = String(baseUrl).replace(/\/+$/, "");
+    this.fetch = options.fetch ?? globalThis.fetch.bind(globalThis);
+    this.retries = options.retries ?? 2;
+    this.retryDelayMs = options.retryDelayMs ?? 100;
+  }
+
+  static forPort(port, options) {
+    return new AdminClient(` + "`http://localhost:${port}`" + `, options);
+  }
+
+  async request(method, path, options = {}) {
+    const { body, contentType = "application/json", responseType = "json", retry = true, signal } = options;
+    const init = { method, headers: {}, signal };
+    if (body !== undefined) {
+      init.body = typeof body === "string" ? body : JSON.stringify(body);
+      init.headers["Content-Type"] = contentType;
+    }
+
+    const attempts = retry ? this.retries + 1 : 1;
+    let delay = this.retryDelayMs;
+    for (let attempt = 1; ; attempt++) {
+      let res;
+      try {
+        res = await this.fetch(this.baseUrl + path, init);
+      } catch (err) {
+        // The twin is unreachable, e.g. still starting.
+        if (signal?.aborted || attempt >= attempts) {
+          throw err;
+        }
+        await sleep(delay, signal);
+        delay *= 2;
+        continue;
+      }
+
+      const text = await res.text();
+      if (res.ok) {
+        if (responseType === "text") {
+          return text;
+        }
+        return text.trim() === "" ? undefined : JSON.parse(text);
+      }
+      if (attempt >= attempts || !RETRYABLE_STATUS.has(res.status)) {
+        throw new AdminApiError(method, path, res.status, text);
+      }
+      await sleep(delay, signal);
+      delay *= 2;
+    }
+  }
+`
+
+// dtsRuntime is the hand-written part of index.d.ts, declaring what
+// jsRuntime defines. The generated method declarations follow it.
+const dtsRuntime = `export interface AdminClientOptions {
+  /** fetch implementation; defaults to the global fetch. */
+  fetch?: typeof fetch;
+  /**
+   * Retries for connection errors and 502/503/504 responses (default 2).
+   * Calls that change state cumulatively are never retried.
+   */
+  retries?: number;
+  /** Delay before the first retry, doubled on every attempt (default 100). */
+  retryDelayMs?: number;
+}
+
+export interface RequestOptions {
+  signal?: AbortSignal;
+}
+
+export interface RawRequestOptions extends RequestOptions {
+  /** JSON-encoded unless it is a string, which is sent as-is. */
+  body?: unknown;
+  contentType?: string;
+  responseType?: "json" | "text";
+  retry?: boolean;
+}
+
+/** Error thrown when a twin answers with a non-2xx status. */
+export declare class AdminApiError extends Error {
+  readonly method: string;
+  readonly path: string;
+  readonly status: number;
+  /** Raw response body. */
+  readonly body: string;
+  /** error.message from the twin's JSON error body, if any. */
+  readonly apiMessage: string;
+  /** True for 404, which twins return for admin features they do not support. */
+  readonly isNotFound: boolean;
+}
+
+/** Client for one twin's /admin/* control plane. */
+export declare class AdminClient {
+  /** @param baseUrl Twin URL, e.g. "http://localhost:4111". */
+  constructor(baseUrl: string, options?: AdminClientOptions);
+
+  /** Client for a twin listening on localhost:port. */
+  static forPort(port: number, options?: AdminClientOptions): AdminClient;
+
+  readonly baseUrl: string;
+
+  /** Sends a request to any admin path; the escape hatch for endpoints without a method. */
+  request<T = unknown>(method: string, path: string, options?: RawRequestOptions): Promise<T>;
+`
@@ -1,5 +1,9 @@
-// Command gen-registry updates a registry.json file with a single twin release.
-// It is called by CI after GoReleaser produces binaries and checksums.
+// Command gen-registry updates a registry.json file with twin releases.
+// It is called by CI after GoReleaser produces binaries and checksums, either
+// for a single twin (--twin/--version/--checksums-file) or for every release
+// in a directory of checksum files (--checksums-dir). With --wt it records a
+// release of the wt CLI itself (--version/--checksums-file, and the binaries'
+// signatures from --signatures-file), for wt self update.
 package main
 
 import (
@@ -9,6 +13,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -17,6 +23,13 @@ import (
 type Registry struct {
 	SchemaVersion int                  `json:"schema_version"`
 	Twins         map[string]TwinEntry `json:"twins"`
+	WT            *CLIEntry            `json:"wt,omitempty"`
+}
+
+// CLIEntry mirrors internal/registry.CLIEntry.
+type CLIEntry struct {
+	Latest   string             `json:"latest"`
+	Versions map[string]Version `json:"versions"`
 }
 
 // TwinEntry mirrors internal/registry.TwinEntry.
@@ -27,6 +40,9 @@ type TwinEntry struct {
 	Author      string             `json:"author"`
 	Latest      string             `json:"latest"`
 	Versions    map[string]Version `json:"versions"`
+
+	Deprecated bool   `json:"deprecated,omitempty"`
+	ReplacedBy string `json:"replaced_by,omitempty"`
 }
 
 // Version mirrors internal/registry.Version.
@@ -38,6 +54,20 @@ type Version struct {
 	Tier       string            `json:"tier"`
 	Checksums  map[string]string `json:"checksums"`
 	BinaryURLs map[string]string `json:"binary_urls"`
+	Signatures map[string]string `json:"signatures,omitempty"`
+
+	ScenarioPacks map[string]ScenarioPack `json:"scenario_packs,omitempty"`
+	ReleaseNotes  string                  `json:"release_notes,omitempty"`
+
+	Yanked       bool   `json:"yanked,omitempty"`
+	YankReason   string `json:"yank_reason,omitempty"`
+	MinWTVersion string `json:"min_wt_version,omitempty"`
+}
+
+// ScenarioPack mirrors internal/registry.ScenarioPack.
+type ScenarioPack struct {
+	URL      string `json:"url"`
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // TwinManifest represents the relevant fields from twin-manifest.json.
@@ -69,49 +99,228 @@ func run(args []string) error {
 	twin := fs.String("twin", "", "twin name (e.g. stripe)")
 	version := fs.String("version", "", "version string (e.g. 0.1.0)")
 	checksumsFile := fs.String("checksums-file", "", "path to checksums file")
+	checksumsDir := fs.String("checksums-dir", "", "directory of twin-{name}-v{version}.checksums.txt files (batch mode)")
 	registryFile := fs.String("registry-file", "", "path to registry.json")
-	repo := fs.String("repo", "wondertwin-ai/registry", "GitHub repo for download URLs")
+	repo := fs.String("repo", "", "GitHub repo for download URLs (default "+twinRepo+", or "+wtRepo+" with --wt)")
 	prerelease := fs.Bool("prerelease", false, "add version without updating latest")
+	wt := fs.Bool("wt", false, "record a release of the wt CLI instead of a twin")
+	signaturesFile := fs.String("signatures-file", "", "path to a `<base64 signature>  <filename>` file signing the wt binaries (with --wt)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	if *twin == "" || *version == "" || *checksumsFile == "" || *registryFile == "" {
-		return fmt.Errorf("--twin, --version, --checksums-file, and --registry-file are all required")
+	if *wt {
+		if *twin != "" || *checksumsDir != "" {
+			return fmt.Errorf("--wt cannot be combined with --twin or --checksums-dir")
+		}
+		if *version == "" || *checksumsFile == "" || *registryFile == "" {
+			return fmt.Errorf("--version, --checksums-file, and --registry-file are all required with --wt")
+		}
+		if *repo == "" {
+			*repo = wtRepo
+		}
+		reg, err := loadRegistry(*registryFile)
+		if err != nil {
+			return fmt.Errorf("loading registry: %w", err)
+		}
+		platforms, err := applyWTRelease(reg, *version, *checksumsFile, *signaturesFile, *repo, *prerelease)
+		if err != nil {
+			return fmt.Errorf("wt v%s: %w", *version, err)
+		}
+		fmt.Printf("Updated registry: wt v%s (%d platforms)\n", *version, platforms)
+		return writeRegistry(*registryFile, reg)
+	}
+	if *signaturesFile != "" {
+		return fmt.Errorf("--signatures-file is only used with --wt")
+	}
+	if *repo == "" {
+		*repo = twinRepo
 	}
 
+	var releases []release
+	if *checksumsDir != "" {
+		if *twin != "" || *version != "" || *checksumsFile != "" {
+			return fmt.Errorf("--checksums-dir cannot be combined with --twin, --version, or --checksums-file")
+		}
+		if *registryFile == "" {
+			return fmt.Errorf("--checksums-dir and --registry-file are both required in batch mode")
+		}
+		var err error
+		releases, err = findReleases(*checksumsDir)
+		if err != nil {
+			return err
+		}
+	} else {
+		if *twin == "" || *version == "" || *checksumsFile == "" || *registryFile == "" {
+			return fmt.Errorf("--twin, --version, --checksums-file, and --registry-file are all required")
+		}
+		releases = []release{{twin: *twin, version: *version, checksumsFile: *checksumsFile}}
+	}
+
+	// Load existing registry
+	reg, err := loadRegistry(*registryFile)
+	if err != nil {
+		return fmt.Errorf("loading registry: %w", err)
+	}
+
+	for _, rel := range releases {
+		platforms, err := applyRelease(reg, rel, *repo, *prerelease)
+		if err != nil {
+			return fmt.Errorf("%s v%s: %w", rel.twin, rel.version, err)
+		}
+		fmt.Printf("Updated registry: %s v%s (%d platforms)\n", rel.twin, rel.version, platforms)
+	}
+
+	// Write back once, after every release applied cleanly
+	if err := writeRegistry(*registryFile, reg); err != nil {
+		return fmt.Errorf("writing registry: %w", err)
+	}
+	return nil
+}
+
+// twinRepo and wtRepo are the GitHub repos twin and wt binaries are
+// released from.
+const (
+	twinRepo = "wondertwin-ai/registry"
+	wtRepo   = "wondertwin-ai/wondertwin"
+)
+
+// release identifies one twin release and its checksums file.
+type release struct {
+	twin          string
+	version       string
+	checksumsFile string
+}
+
+// checksumsFileRe matches batch-mode checksum files named after the release
+// tag, e.g. twin-stripe-v0.2.0.checksums.txt.
+var checksumsFileRe = regexp.MustCompile(`^twin-([a-z0-9]+(?:-[a-z0-9]+)*?)-v(\d+\.\d+\.\d+[^/]*)\.checksums\.txt$`)
+
+// findReleases lists the releases described by the checksum files in dir,
+// sorted by twin name and version.
+func findReleases(dir string) ([]release, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading checksums dir: %w", err)
+	}
+	var releases []release
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := checksumsFileRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		releases = append(releases, release{
+			twin:          m[1],
+			version:       m[2],
+			checksumsFile: filepath.Join(dir, e.Name()),
+		})
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no twin-{name}-v{version}.checksums.txt files found in %s", dir)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].twin != releases[j].twin {
+			return releases[i].twin < releases[j].twin
+		}
+		return versionLess(releases[i].version, releases[j].version)
+	})
+	return releases, nil
+}
+
+// versionLess orders dotted numeric versions, so that applying releases in
+// order leaves the newest one as latest.
+func versionLess(a, b string) bool {
+	ap, bp := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		var an, bn int
+		fmt.Sscanf(ap[i], "%d", &an)
+		fmt.Sscanf(bp[i], "%d", &bn)
+		if an != bn {
+			return an < bn
+		}
+	}
+	return len(ap) < len(bp)
+}
+
+// applyRelease adds a single twin release to reg and returns the number of
+// platforms it ships.
+func applyRelease(reg *Registry, rel release, repo string, prerelease bool) (int, error) {
 	// 1. Read twin manifest
-	manifest, err := readManifest(*twin)
+	manifest, err := readManifest(rel.twin)
 	if err != nil {
-		return fmt.Errorf("reading manifest: %w", err)
+		return 0, fmt.Errorf("reading manifest: %w", err)
 	}
 
-	// 2. Parse checksums
-	checksums, err := parseChecksums(*checksumsFile, *twin)
+	// 2. Parse checksums (binaries and any scenario packs)
+	checksums, err := parseChecksums(rel.checksumsFile, rel.twin)
+	if err != nil {
+		return 0, fmt.Errorf("parsing checksums: %w", err)
+	}
+	packChecksums, err := parsePackChecksums(rel.checksumsFile, rel.twin)
 	if err != nil {
-		return fmt.Errorf("parsing checksums: %w", err)
+		return 0, fmt.Errorf("parsing checksums: %w", err)
 	}
 
-	// 3. Load existing registry
-	reg, err := loadRegistry(*registryFile)
+	// 3. Build version entry
+	ver := buildVersion(rel.twin, rel.version, repo, manifest, checksums)
+	ver.ScenarioPacks = buildScenarioPacks(rel.twin, rel.version, repo, packChecksums)
+	notes, err := readReleaseNotes(rel.twin, rel.version)
 	if err != nil {
-		return fmt.Errorf("loading registry: %w", err)
+		return 0, fmt.Errorf("reading changelog: %w", err)
 	}
+	ver.ReleaseNotes = notes
 
-	// 4. Build version entry
-	ver := buildVersion(*twin, *version, *repo, manifest, checksums)
+	// 4. Upsert into registry
+	upsert(reg, rel.twin, rel.version, manifest, ver, prerelease)
+	return len(checksums), nil
+}
 
-	// 5. Upsert into registry
-	upsert(reg, *twin, *version, manifest, ver, *prerelease)
+// readReleaseNotes returns the body of the twin's CHANGELOG.md section for
+// version, or "" if the twin has no changelog or no such section. Headings
+// may be written as "## 0.2.0", "## v0.2.0", or "## [0.2.0] - 2026-03-01".
+func readReleaseNotes(twin, version string) (string, error) {
+	path := filepath.Join(fmt.Sprintf("twin-%s", twin), "CHANGELOG.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return changelogSection(string(data), version), nil
+}
 
-	// 6. Write back
-	if err := writeRegistry(*registryFile, reg); err != nil {
-		return fmt.Errorf("writing registry: %w", err)
+// changelogSection extracts the section for version from a Markdown changelog.
+func changelogSection(changelog, version string) string {
+	var lines []string
+	inSection := false
+	for _, line := range strings.Split(changelog, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			if inSection {
+				break
+			}
+			inSection = headingVersion(line) == version
+			continue
+		}
+		if inSection {
+			lines = append(lines, line)
+		}
 	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
 
-	fmt.Printf("Updated registry: %s v%s (%d platforms)\n", *twin, *version, len(checksums))
-	return nil
+// headingVersion returns the version named by a "## " changelog heading.
+func headingVersion(heading string) string {
+	h := strings.TrimSpace(strings.TrimPrefix(heading, "## "))
+	if fields := strings.Fields(h); len(fields) > 0 {
+		h = fields[0]
+	}
+	h = strings.Trim(h, "[]")
+	return strings.TrimPrefix(h, "v")
 }
 
 func readManifest(twin string) (*TwinManifest, error) {
@@ -127,17 +336,72 @@ func readManifest(twin string) (*TwinManifest, error) {
 	return &m, nil
 }
 
+// packPrefix marks scenario pack artifacts (twin-{name}-pack-{pack}.json)
+// among the release files listed in the checksums file.
+const packPrefix = "pack-"
+
 // parseChecksums reads a checksums file in `<sha256hex>  <filename>` format.
 // It extracts the platform from filenames matching twin-{name}-{os}-{arch}.
+// Scenario pack artifacts are skipped; see parsePackChecksums.
 func parseChecksums(path, twin string) (map[string]string, error) {
+	all, err := readChecksums(path, twin)
+	if err != nil {
+		return nil, err
+	}
+	checksums := make(map[string]string)
+	for name, sum := range all {
+		if strings.HasPrefix(name, packPrefix) {
+			continue
+		}
+		checksums[name] = sum
+	}
+	if len(checksums) == 0 {
+		return nil, fmt.Errorf("no checksums found for twin %q", twin)
+	}
+	return checksums, nil
+}
+
+// parsePackChecksums returns the checksums of scenario pack artifacts
+// (twin-{name}-pack-{pack}.json), keyed by pack name.
+func parsePackChecksums(path, twin string) (map[string]string, error) {
+	all, err := readChecksums(path, twin)
+	if err != nil {
+		return nil, err
+	}
+	packs := make(map[string]string)
+	for name, sum := range all {
+		if !strings.HasPrefix(name, packPrefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		packs[strings.TrimSuffix(strings.TrimPrefix(name, packPrefix), ".json")] = sum
+	}
+	return packs, nil
+}
+
+// readChecksums returns every entry for the twin's release files, keyed by
+// the filename with the twin-{name}- prefix removed.
+func readChecksums(path, twin string) (map[string]string, error) {
+	entries, err := readReleaseFiles(path, fmt.Sprintf("twin-%s-", twin))
+	if err != nil {
+		return nil, err
+	}
+	for name, hex := range entries {
+		entries[name] = fmt.Sprintf("sha256:%s", hex)
+	}
+	return entries, nil
+}
+
+// readReleaseFiles reads a file of `<value>  <filename>` lines, such as a
+// checksums file, and returns the value of every file whose name starts
+// with prefix, keyed by the rest of the name.
+func readReleaseFiles(path, prefix string) (map[string]string, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	prefix := fmt.Sprintf("twin-%s-", twin)
-	checksums := make(map[string]string)
+	entries := make(map[string]string)
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -153,23 +417,76 @@ func parseChecksums(path, twin string) (map[string]string, error) {
 				continue
 			}
 		}
-		hex := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[0])
 		filename := strings.TrimSpace(parts[1])
 
 		// Extract platform from filename
 		if !strings.HasPrefix(filename, prefix) {
 			continue
 		}
-		platform := strings.TrimPrefix(filename, prefix)
-		checksums[platform] = fmt.Sprintf("sha256:%s", hex)
+		entries[strings.TrimPrefix(filename, prefix)] = value
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
-	if len(checksums) == 0 {
-		return nil, fmt.Errorf("no checksums found for twin %q", twin)
+	return entries, nil
+}
+
+// wtBinaryRe matches the platform part of a wt binary name as GoReleaser
+// names it, e.g. linux-amd64 or windows-amd64.exe.
+var wtBinaryRe = regexp.MustCompile(`^([a-z0-9]+-[a-z0-9]+)(\.exe)?$`)
+
+// applyWTRelease adds a wt release to reg from GoReleaser's checksums file,
+// whose wt-{os}-{arch} binaries it lists, and returns the number of
+// platforms it ships. With a signatures file, every binary must be signed.
+func applyWTRelease(reg *Registry, version, checksumsFile, signaturesFile, repo string, prerelease bool) (int, error) {
+	files, err := readReleaseFiles(checksumsFile, "wt-")
+	if err != nil {
+		return 0, fmt.Errorf("parsing checksums: %w", err)
 	}
-	return checksums, nil
+	var signatures map[string]string
+	if signaturesFile != "" {
+		if signatures, err = readReleaseFiles(signaturesFile, "wt-"); err != nil {
+			return 0, fmt.Errorf("parsing signatures: %w", err)
+		}
+	}
+
+	ver := Version{
+		Released:   nowFunc().UTC().Format("2006-01-02"),
+		Checksums:  make(map[string]string),
+		BinaryURLs: make(map[string]string),
+	}
+	for file, hex := range files {
+		m := wtBinaryRe.FindStringSubmatch(file)
+		if m == nil {
+			continue
+		}
+		platform := m[1]
+		ver.Checksums[platform] = "sha256:" + hex
+		ver.BinaryURLs[platform] = fmt.Sprintf("https://github.com/%s/releases/download/v%s/wt-%s", repo, version, file)
+		if signatures != nil {
+			sig, ok := signatures[file]
+			if !ok {
+				return 0, fmt.Errorf("no signature for wt-%s", file)
+			}
+			if ver.Signatures == nil {
+				ver.Signatures = make(map[string]string)
+			}
+			ver.Signatures[platform] = sig
+		}
+	}
+	if len(ver.Checksums) == 0 {
+		return 0, fmt.Errorf("no wt-{os}-{arch} binaries found in %s", checksumsFile)
+	}
+
+	if reg.WT == nil {
+		reg.WT = &CLIEntry{Versions: make(map[string]Version)}
+	}
+	if !prerelease || reg.WT.Latest == "" {
+		reg.WT.Latest = version
+	}
+	reg.WT.Versions[version] = ver
+	return len(ver.Checksums), nil
 }
 
 func loadRegistry(path string) (*Registry, error) {
@@ -208,6 +525,25 @@ func buildVersion(twin, version, repo string, manifest *TwinManifest, checksums
 	}
 }
 
+// buildScenarioPacks returns the registry entries for a release's scenario
+// packs, or nil when the release ships none.
+func buildScenarioPacks(twin, version, repo string, packChecksums map[string]string) map[string]ScenarioPack {
+	if len(packChecksums) == 0 {
+		return nil
+	}
+	packs := make(map[string]ScenarioPack, len(packChecksums))
+	for name, sum := range packChecksums {
+		packs[name] = ScenarioPack{
+			URL: fmt.Sprintf(
+				"https://github.com/%s/releases/download/twin-%s-v%s/twin-%s-pack-%s.json",
+				repo, twin, version, twin, name,
+			),
+			Checksum: sum,
+		}
+	}
+	return packs
+}
+
 func upsert(reg *Registry, twin, version string, manifest *TwinManifest, ver Version, prerelease bool) {
 	entry, exists := reg.Twins[twin]
 	if !exists {
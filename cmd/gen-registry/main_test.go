@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -177,6 +178,43 @@ func TestAddSecondVersion(t *testing.T) {
 	}
 }
 
+func TestPreservesYankedAndDeprecated(t *testing.T) {
+	dir := setupManifest(t)
+	orig, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(orig)
+
+	checksumsPath := writeChecksums(t, dir)
+	registryPath := filepath.Join(dir, "registry.json")
+	existing := `{"schema_version": 1, "twins": {"stripe": {
+  "latest": "0.1.0", "deprecated": true, "replaced_by": "stripe-v2",
+  "versions": {"0.1.0": {"released": "2026-01-01", "yanked": true, "yank_reason": "bad build", "min_wt_version": "0.3.0"}}
+}}}`
+	if err := os.WriteFile(registryPath, []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run([]string{
+		"--twin", "stripe", "--version", "0.2.0",
+		"--checksums-file", checksumsPath, "--registry-file", registryPath,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(registryPath)
+	var reg Registry
+	json.Unmarshal(data, &reg)
+
+	entry := reg.Twins["stripe"]
+	if !entry.Deprecated || entry.ReplacedBy != "stripe-v2" {
+		t.Errorf("deprecation lost: deprecated=%v replaced_by=%q", entry.Deprecated, entry.ReplacedBy)
+	}
+	old := entry.Versions["0.1.0"]
+	if !old.Yanked || old.YankReason != "bad build" || old.MinWTVersion != "0.3.0" {
+		t.Errorf("0.1.0 fields lost: %+v", old)
+	}
+}
+
 func TestAddSecondTwin(t *testing.T) {
 	dir := setupManifest(t)
 	// Also create a twilio manifest
@@ -266,6 +304,37 @@ func TestChecksumParsing(t *testing.T) {
 	}
 }
 
+func TestScenarioPackChecksums(t *testing.T) {
+	dir := t.TempDir()
+	content := `aaa111  twin-stripe-linux-amd64
+bbb222  twin-stripe-pack-payments-happy-path.json
+`
+	path := filepath.Join(dir, "checksums.txt")
+	os.WriteFile(path, []byte(content), 0o644)
+
+	checksums, err := parseChecksums(path, "stripe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checksums) != 1 || checksums["linux-amd64"] != "sha256:aaa111" {
+		t.Errorf("platform checksums = %v, want only linux-amd64", checksums)
+	}
+
+	packs, err := parsePackChecksums(path, "stripe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packs["payments-happy-path"] != "sha256:bbb222" {
+		t.Errorf("pack checksums = %v", packs)
+	}
+
+	entries := buildScenarioPacks("stripe", "0.2.0", "wondertwin-ai/registry", packs)
+	want := "https://github.com/wondertwin-ai/registry/releases/download/twin-stripe-v0.2.0/twin-stripe-pack-payments-happy-path.json"
+	if got := entries["payments-happy-path"].URL; got != want {
+		t.Errorf("pack URL = %q, want %q", got, want)
+	}
+}
+
 func TestOutputMatchesSchema(t *testing.T) {
 	dir := setupManifest(t)
 	orig, _ := os.Getwd()
@@ -577,3 +646,119 @@ func TestBackwardCompatibilityWithoutAPIVersion(t *testing.T) {
 		t.Errorf("sdk_package = %q", ver.SDKPackage)
 	}
 }
+
+func TestBatchMode(t *testing.T) {
+	dir := setupManifest(t)
+	twilioDir := filepath.Join(dir, "twin-twilio")
+	os.MkdirAll(twilioDir, 0o755)
+	os.WriteFile(filepath.Join(twilioDir, "twin-manifest.json"), []byte(`{"twin":"twilio","category":"communications","description":"Twilio twin"}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "twin-stripe", "CHANGELOG.md"), []byte(`# Changelog
+
+## [0.10.0] - 2026-03-01
+
+- Added payouts.
+
+## 0.9.0
+
+- Initial release.
+`), 0o644)
+
+	orig, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(orig)
+
+	nowFunc = fixedTime
+	defer func() { nowFunc = time.Now }()
+
+	checksumsDir := filepath.Join(dir, "dist")
+	os.MkdirAll(checksumsDir, 0o755)
+	files := map[string]string{
+		"twin-stripe-v0.9.0.checksums.txt":  "aaa  twin-stripe-linux-amd64\n",
+		"twin-stripe-v0.10.0.checksums.txt": "bbb  twin-stripe-linux-amd64\n",
+		"twin-twilio-v0.1.0.checksums.txt":  "ccc  twin-twilio-linux-amd64\n",
+		"unrelated.txt":                     "ddd  twin-stripe-linux-amd64\n",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(checksumsDir, name), []byte(content), 0o644)
+	}
+	registryPath := writeEmptyRegistry(t, dir)
+
+	if err := run([]string{"--checksums-dir", checksumsDir, "--registry-file", registryPath}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(registryPath)
+	var reg Registry
+	if err := json.Unmarshal(data, &reg); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	stripe := reg.Twins["stripe"]
+	if stripe.Latest != "0.10.0" {
+		t.Errorf("stripe latest = %q, want 0.10.0", stripe.Latest)
+	}
+	if len(stripe.Versions) != 2 {
+		t.Errorf("stripe versions = %d, want 2", len(stripe.Versions))
+	}
+	if got := stripe.Versions["0.10.0"].ReleaseNotes; got != "- Added payouts." {
+		t.Errorf("0.10.0 release notes = %q", got)
+	}
+	if got := stripe.Versions["0.9.0"].ReleaseNotes; got != "- Initial release." {
+		t.Errorf("0.9.0 release notes = %q", got)
+	}
+	if reg.Twins["twilio"].Latest != "0.1.0" {
+		t.Errorf("twilio latest = %q, want 0.1.0", reg.Twins["twilio"].Latest)
+	}
+	if reg.Twins["twilio"].Versions["0.1.0"].ReleaseNotes != "" {
+		t.Error("expected no release notes for twin without a changelog")
+	}
+}
+
+func TestBatchModeRejectsSingleFlags(t *testing.T) {
+	err := run([]string{"--checksums-dir", t.TempDir(), "--twin", "stripe", "--registry-file", "r.json"})
+	if err == nil {
+		t.Fatal("expected error combining --checksums-dir with --twin")
+	}
+}
+
+func TestWTRelease(t *testing.T) {
+	dir := t.TempDir()
+	checksums := filepath.Join(dir, "checksums.txt")
+	os.WriteFile(checksums, []byte(`aaa111  wt-linux-amd64
+bbb222  wt-windows-amd64.exe
+ccc333  wondertwin_0.5.0_linux_amd64.tar.gz
+`), 0o644)
+	signatures := filepath.Join(dir, "signatures.txt")
+	os.WriteFile(signatures, []byte(`c2lnMQ==  wt-linux-amd64
+c2lnMg==  wt-windows-amd64.exe
+`), 0o644)
+	regFile := writeEmptyRegistry(t, dir)
+	nowFunc = fixedTime
+	defer func() { nowFunc = time.Now }()
+
+	if err := run([]string{"--wt", "--version", "0.5.0", "--checksums-file", checksums, "--signatures-file", signatures, "--registry-file", regFile}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(regFile)
+	var reg Registry
+	if err := json.Unmarshal(data, &reg); err != nil {
+		t.Fatal(err)
+	}
+	if reg.WT == nil || reg.WT.Latest != "0.5.0" || len(reg.Twins) != 0 {
+		t.Fatalf("unexpected registry %s", data)
+	}
+	ver := reg.WT.Versions["0.5.0"]
+	if len(ver.Checksums) != 2 || ver.Checksums["windows-amd64"] != "sha256:bbb222" || ver.Signatures["linux-amd64"] != "c2lnMQ==" {
+		t.Errorf("unexpected version %+v", ver)
+	}
+	want := "https://github.com/wondertwin-ai/wondertwin/releases/download/v0.5.0/wt-windows-amd64.exe"
+	if ver.BinaryURLs["windows-amd64"] != want {
+		t.Errorf("binary URL = %q, want %q", ver.BinaryURLs["windows-amd64"], want)
+	}
+
+	// With a signatures file, every binary must be signed.
+	os.WriteFile(signatures, []byte("c2lnMQ==  wt-linux-amd64\n"), 0o644)
+	if err := run([]string{"--wt", "--version", "0.6.0", "--checksums-file", checksums, "--signatures-file", signatures, "--registry-file", regFile}); err == nil || !strings.Contains(err.Error(), "no signature") {
+		t.Errorf("expected an unsigned binary to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Deep validation modes.
+const (
+	deepOff   = ""
+	deepRange = "range" // fetch the executable header only
+	deepFull  = "full"  // download, verify sha256, and run --version on the host platform
+)
+
+// headerBytes is how much of each binary the range mode fetches; enough for
+// the ELF and Mach-O headers.
+const headerBytes = 64
+
+// deepCheck downloads one release artifact and verifies it according to mode.
+func deepCheck(prefix, version, platform, url, checksum, mode string) []checkResult {
+	if mode == deepRange {
+		header, err := fetchRange(url, headerBytes)
+		if err != nil {
+			return []checkResult{{fmt.Sprintf("%s header %s", prefix, platform), false, err.Error()}}
+		}
+		ok, detail := checkExecutableHeader(header, platform)
+		return []checkResult{{fmt.Sprintf("%s header %s", prefix, platform), ok, detail}}
+	}
+
+	data, err := download(url)
+	if err != nil {
+		return []checkResult{{fmt.Sprintf("%s download %s", prefix, platform), false, err.Error()}}
+	}
+
+	var results []checkResult
+	actual := fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+	if actual != checksum {
+		results = append(results, checkResult{fmt.Sprintf("%s sha256 %s", prefix, platform), false,
+			fmt.Sprintf("declared %s, downloaded %s", checksum, actual)})
+	} else {
+		results = append(results, checkResult{fmt.Sprintf("%s sha256 %s", prefix, platform), true, fmt.Sprintf("%d bytes", len(data))})
+	}
+
+	ok, detail := checkExecutableHeader(data, platform)
+	results = append(results, checkResult{fmt.Sprintf("%s header %s", prefix, platform), ok, detail})
+
+	// Only the host platform's binary can be executed.
+	if platform == runtime.GOOS+"-"+runtime.GOARCH && ok {
+		ok, detail := checkVersionFlag(data, version)
+		results = append(results, checkResult{fmt.Sprintf("%s --version %s", prefix, platform), ok, detail})
+	}
+	return results
+}
+
+func download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// fetchRange returns the first n bytes of url. Servers that ignore the
+// Range header are read only up to n bytes.
+func fetchRange(url string, n int) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", n-1))
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, int64(n)))
+}
+
+// checkExecutableHeader verifies that data starts with an executable header
+// for the given platform: ELF on linux, 64-bit Mach-O on darwin, with the
+// matching CPU architecture.
+func checkExecutableHeader(data []byte, platform string) (bool, string) {
+	goos, goarch, _ := strings.Cut(platform, "-")
+	switch goos {
+	case "linux":
+		if len(data) < 20 || !bytes.HasPrefix(data, []byte("\x7fELF")) {
+			return false, "not an ELF binary"
+		}
+		machine := map[uint16]string{0x3e: "amd64", 0xb7: "arm64"}[binary.LittleEndian.Uint16(data[18:20])]
+		if machine != goarch {
+			return false, fmt.Sprintf("ELF built for %q, expected %s", machine, goarch)
+		}
+		return true, "ELF " + machine
+	case "darwin":
+		if len(data) < 8 || binary.LittleEndian.Uint32(data[0:4]) != 0xfeedfacf {
+			return false, "not a 64-bit Mach-O binary"
+		}
+		cpu := map[uint32]string{0x01000007: "amd64", 0x0100000c: "arm64"}[binary.LittleEndian.Uint32(data[4:8])]
+		if cpu != goarch {
+			return false, fmt.Sprintf("Mach-O built for %q, expected %s", cpu, goarch)
+		}
+		return true, "Mach-O " + cpu
+	}
+	return true, "header check not supported for " + platform
+}
+
+// checkVersionFlag runs the binary with --version and verifies the output
+// names the registry version.
+func checkVersionFlag(data []byte, version string) (bool, string) {
+	dir, err := os.MkdirTemp("", "verify-registry-*")
+	if err != nil {
+		return false, err.Error()
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "twin")
+	if err := os.WriteFile(path, data, 0o755); err != nil {
+		return false, err.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		return false, fmt.Sprintf("--version failed: %v (%s)", err, output)
+	}
+	if !strings.Contains(output, version) {
+		return false, fmt.Sprintf("--version printed %q, expected it to contain %s", output, version)
+	}
+	return true, output
+}
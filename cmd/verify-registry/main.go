@@ -2,11 +2,15 @@
 //
 // It fetches registry.json, parses it, and runs a series of checks to
 // ensure all entries are well-formed and all binary downloads are reachable.
+// With --deep it also downloads every binary, recomputes its sha256, checks
+// the executable header matches the platform, and runs the host platform's
+// binary with --version. --deep-mode range fetches only the header.
 //
 // Usage:
 //
 //	go run ./cmd/verify-registry
 //	go run ./cmd/verify-registry --registry-url https://raw.githubusercontent.com/wondertwin-ai/registry/main/registry.json
+//	go run ./cmd/verify-registry --deep [--deep-mode full|range]
 package main
 
 import (
@@ -33,6 +37,9 @@ var requiredPlatforms = []string{
 // checksumRe matches the expected format: sha256:<64 hex chars>
 var checksumRe = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
 
+// semverRe matches a plain release version such as 0.3.0 or v1.2.3.
+var semverRe = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+
 // registrySchema mirrors internal/registry types for standalone parsing.
 type registrySchema struct {
 	SchemaVersion int                  `json:"schema_version"`
@@ -46,6 +53,8 @@ type twinEntry struct {
 	Author      string                `json:"author"`
 	Latest      string                `json:"latest"`
 	Versions    map[string]versionDef `json:"versions"`
+	Deprecated  bool                  `json:"deprecated"`
+	ReplacedBy  string                `json:"replaced_by"`
 }
 
 type versionDef struct {
@@ -55,6 +64,9 @@ type versionDef struct {
 	Tier       string            `json:"tier"`
 	Checksums  map[string]string `json:"checksums"`
 	BinaryURLs map[string]string `json:"binary_urls"`
+
+	Yanked       bool   `json:"yanked"`
+	MinWTVersion string `json:"min_wt_version"`
 }
 
 // checkResult stores the outcome of a single check.
@@ -66,9 +78,20 @@ type checkResult struct {
 
 func main() {
 	registryURL := flag.String("registry-url", defaultRegistryURL, "URL of the registry.json to validate")
+	deep := flag.Bool("deep", false, "download binaries and verify checksums, headers, and --version")
+	deepMode := flag.String("deep-mode", deepFull, "deep validation mode: full or range (header bytes only)")
 	flag.Parse()
 
-	results := run(*registryURL)
+	mode := deepOff
+	if *deep {
+		if *deepMode != deepFull && *deepMode != deepRange {
+			fmt.Fprintf(os.Stderr, "verify-registry: invalid --deep-mode %q (expected full or range)\n", *deepMode)
+			os.Exit(2)
+		}
+		mode = *deepMode
+	}
+
+	results := run(*registryURL, mode)
 	printResults(results)
 
 	for _, r := range results {
@@ -78,8 +101,9 @@ func main() {
 	}
 }
 
-// run performs all validation checks and returns the results.
-func run(registryURL string) []checkResult {
+// run performs all validation checks and returns the results. deepMode
+// selects the optional download verification (deepOff to skip it).
+func run(registryURL, deepMode string) []checkResult {
 	var results []checkResult
 
 	// 1. Fetch registry
@@ -110,13 +134,19 @@ func run(registryURL string) []checkResult {
 
 	// 4. Per-twin checks
 	for name, entry := range reg.Twins {
-		results = append(results, validateTwin(name, entry)...)
+		results = append(results, validateTwin(name, entry, deepMode)...)
+
+		// Deprecated twins must point at a replacement that exists
+		if entry.ReplacedBy != "" {
+			_, ok := reg.Twins[entry.ReplacedBy]
+			results = append(results, checkResult{fmt.Sprintf("[%s] replaced_by exists", name), ok, entry.ReplacedBy})
+		}
 	}
 
 	return results
 }
 
-func validateTwin(name string, entry twinEntry) []checkResult {
+func validateTwin(name string, entry twinEntry, deepMode string) []checkResult {
 	var results []checkResult
 
 	// latest points to existing version
@@ -124,18 +154,20 @@ func validateTwin(name string, entry twinEntry) []checkResult {
 		results = append(results, checkResult{fmt.Sprintf("[%s] latest defined", name), false, "latest is empty"})
 	} else if _, ok := entry.Versions[entry.Latest]; !ok {
 		results = append(results, checkResult{fmt.Sprintf("[%s] latest exists in versions", name), false, fmt.Sprintf("latest=%q not found in versions", entry.Latest)})
+	} else if entry.Versions[entry.Latest].Yanked {
+		results = append(results, checkResult{fmt.Sprintf("[%s] latest not yanked", name), false, fmt.Sprintf("latest=%q is yanked", entry.Latest)})
 	} else {
 		results = append(results, checkResult{fmt.Sprintf("[%s] latest exists in versions", name), true, entry.Latest})
 	}
 
 	for ver, vd := range entry.Versions {
-		results = append(results, validateVersion(name, ver, vd)...)
+		results = append(results, validateVersion(name, ver, vd, deepMode)...)
 	}
 
 	return results
 }
 
-func validateVersion(name, ver string, vd versionDef) []checkResult {
+func validateVersion(name, ver string, vd versionDef, deepMode string) []checkResult {
 	var results []checkResult
 	prefix := fmt.Sprintf("[%s@%s]", name, ver)
 
@@ -152,10 +184,18 @@ func validateVersion(name, ver string, vd versionDef) []checkResult {
 		}
 	}
 
+	// min_wt_version format
+	if vd.MinWTVersion != "" && !semverRe.MatchString(vd.MinWTVersion) {
+		results = append(results, checkResult{prefix + " min_wt_version format", false, vd.MinWTVersion})
+	}
+
 	// Binary URL reachability (HEAD requests)
 	for platform, url := range vd.BinaryURLs {
 		ok, detail := headCheck(url)
 		results = append(results, checkResult{fmt.Sprintf("%s reachable %s", prefix, platform), ok, detail})
+		if ok && deepMode != deepOff {
+			results = append(results, deepCheck(prefix, ver, platform, url, vd.Checksums[platform], deepMode)...)
+		}
 	}
 
 	return results
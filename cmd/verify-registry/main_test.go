@@ -1,7 +1,10 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -78,7 +81,7 @@ func TestRunFullValidRegistry(t *testing.T) {
 	}))
 	defer regServer.Close()
 
-	results := run(regServer.URL)
+	results := run(regServer.URL, deepOff)
 	for _, r := range results {
 		if !r.Passed {
 			t.Errorf("check %q failed: %s", r.Name, r.Detail)
@@ -109,7 +112,7 @@ func TestRunLatestMissing(t *testing.T) {
 	}))
 	defer regServer.Close()
 
-	results := run(regServer.URL)
+	results := run(regServer.URL, deepOff)
 	foundFail := false
 	for _, r := range results {
 		if r.Name == "[stripe] latest exists in versions" && !r.Passed {
@@ -144,7 +147,7 @@ func TestRunMissingPlatform(t *testing.T) {
 	}))
 	defer regServer.Close()
 
-	results := run(regServer.URL)
+	results := run(regServer.URL, deepOff)
 	foundFail := false
 	for _, r := range results {
 		if !r.Passed && r.Detail == "missing: linux-arm64" {
@@ -182,7 +185,7 @@ func TestRunBinaryUnreachable(t *testing.T) {
 	}))
 	defer regServer.Close()
 
-	results := run(regServer.URL)
+	results := run(regServer.URL, deepOff)
 	foundFail := false
 	for _, r := range results {
 		if r.Name == "[stripe@0.1.0] reachable linux-arm64" && !r.Passed {
@@ -215,7 +218,7 @@ func TestRunBadChecksum(t *testing.T) {
 	}))
 	defer regServer.Close()
 
-	results := run(regServer.URL)
+	results := run(regServer.URL, deepOff)
 	foundFail := false
 	for _, r := range results {
 		if !r.Passed && r.Name == "[stripe@0.1.0] checksum format darwin-amd64" {
@@ -226,3 +229,100 @@ func TestRunBadChecksum(t *testing.T) {
 		t.Error("expected checksum format check to fail")
 	}
 }
+
+func TestRunDeepChecksum(t *testing.T) {
+	body := []byte("not really a binary")
+	binaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer binaryServer.Close()
+
+	reg := validRegistry()
+	v := reg.Twins["stripe"].Versions["0.1.0"]
+	for _, p := range requiredPlatforms {
+		v.BinaryURLs[p] = binaryServer.URL + "/" + p
+		v.Checksums[p] = fmt.Sprintf("sha256:%x", sha256.Sum256(body))
+	}
+	v.Checksums["linux-arm64"] = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	reg.Twins["stripe"].Versions["0.1.0"] = v
+
+	data, _ := json.Marshal(reg)
+	regServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer regServer.Close()
+
+	got := map[string]bool{}
+	for _, r := range run(regServer.URL, deepFull) {
+		got[r.Name] = r.Passed
+	}
+	if !got["[stripe@0.1.0] sha256 darwin-amd64"] {
+		t.Error("expected matching checksum to pass")
+	}
+	if passed, ok := got["[stripe@0.1.0] sha256 linux-arm64"]; !ok || passed {
+		t.Error("expected mismatched checksum to fail")
+	}
+	if passed, ok := got["[stripe@0.1.0] header linux-amd64"]; !ok || passed {
+		t.Error("expected non-ELF body to fail the header check")
+	}
+}
+
+func TestCheckExecutableHeader(t *testing.T) {
+	elf := make([]byte, headerBytes)
+	copy(elf, "\x7fELF")
+	binary.LittleEndian.PutUint16(elf[18:], 0x3e)
+
+	macho := make([]byte, headerBytes)
+	binary.LittleEndian.PutUint32(macho[0:], 0xfeedfacf)
+	binary.LittleEndian.PutUint32(macho[4:], 0x0100000c)
+
+	tests := []struct {
+		data     []byte
+		platform string
+		want     bool
+	}{
+		{elf, "linux-amd64", true},
+		{elf, "linux-arm64", false},
+		{elf, "darwin-amd64", false},
+		{macho, "darwin-arm64", true},
+		{macho, "darwin-amd64", false},
+		{macho, "linux-arm64", false},
+		{[]byte("#!/bin/sh"), "linux-amd64", false},
+	}
+	for _, tc := range tests {
+		if got, detail := checkExecutableHeader(tc.data, tc.platform); got != tc.want {
+			t.Errorf("checkExecutableHeader(%s) = %v (%s), want %v", tc.platform, got, detail, tc.want)
+		}
+	}
+}
+
+func TestRunLatestYanked(t *testing.T) {
+	binaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer binaryServer.Close()
+
+	reg := validRegistry()
+	v := reg.Twins["stripe"].Versions["0.1.0"]
+	v.Yanked = true
+	for _, p := range requiredPlatforms {
+		v.BinaryURLs[p] = binaryServer.URL + "/" + p
+	}
+	reg.Twins["stripe"].Versions["0.1.0"] = v
+
+	data, _ := json.Marshal(reg)
+	regServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer regServer.Close()
+
+	foundFail := false
+	for _, r := range run(regServer.URL, deepOff) {
+		if !r.Passed && r.Name == "[stripe] latest not yanked" {
+			foundFail = true
+		}
+	}
+	if !foundFail {
+		t.Error("expected yanked latest to fail")
+	}
+}
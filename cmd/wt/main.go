@@ -2,16 +2,40 @@
 //
 // Usage:
 //
-//	wt up                         Start all twins from wondertwin.yaml
+//	wt up [--auto-port]           Start all twins from wondertwin.yaml
 //	wt down                       Stop all running twins
-//	wt status                     Health check all running twins
-//	wt reset                      Reset state on all running twins
-//	wt seed <twin> <file>         POST seed data to a twin's /admin/state
-//	wt logs <twin>                Tail stdout/stderr of a running twin
+//	wt apply [--dry-run]          Reconcile running twins with a changed manifest
+//	wt status [--verbose]         Health check all running twins
+//	wt status --watch             Refresh the status table, highlighting health changes
+//	wt ps [--all]                 List twin processes for this project (or all projects)
+//	wt prune [--dry-run]          Remove stale PID entries, logs, tenants, and install leftovers
+//	wt reset [twin] [--only r,..] Reset state on running twins (or selected resources)
+//	wt reset <twin> --seed <name> Reset a twin onto a named seed preset
+//	wt seed <twin> <file>         POST seed data to a twin's /admin/state (--dry-run checks references only)
+//	wt logs <twin> [filters]      Tail or query stdout/stderr of a running twin
 //	wt inspect <twin> [res]       Query a running twin's internal state
+//	wt replay <twin> <id>         Re-send a logged request against a twin
+//	wt intercept <twin> --path <p>  Hold a twin's responses to approve, edit, or deny them
+//	wt webhooks catalog <twin>    List the webhook event types a twin can emit
+//	wt webhooks trigger <twin> <type>  Emit a webhook event without the API action behind it
+//	wt fixtures scrub <in> <out>  Replace personal data in recorded fixtures with deterministic fakes
+//	wt env export [-o <file>]     Capture the twin environment for a teammate to diff against
+//	wt env diff <file>            Compare this twin environment with a teammate's export
+//	wt audit collect [-o <file>]  Gather every twin's hash-chained admin audit log into one artifact
+//	wt audit verify <file>        Check an audit artifact's hash chains and signatures
+//	wt shell [twin]               Interactive prompt for admin operations
 //	wt test [path]                Run YAML test scenarios against running twins
+//	wt test --pack <twin>/<pack>  Download and run a twin's published scenario pack
+//	wt test --parallel <n>        Run scenarios concurrently, each as its own tenant
+//	wt test --coverage            Also report which twin endpoints the scenarios exercised
+//	wt test --generate-negative <twin>  Write scenario skeletons for a twin's documented errors
+//	wt chaos replay <twin>/<pack> Replay a provider outage from an incident pack against the twins
+//	wt report [-o <file>]         Collate the last test session across twins as HTML or JSON
+//	wt graph [--format dot|mermaid]  Draw the manifest's twins, dependencies, and webhook targets
+//	wt bench <twin> --scenario <file>  Replay a scenario's requests at a target rate
 //	wt install                    Install all twins from wondertwin.yaml
 //	wt install <twin>@<version>   Install a specific twin at a version
+//	wt install --verify-conformance  Quarantine installs that fail conformance
 //	wt ci                         Install twins from lock file (frozen)
 //	wt auth login                 Activate a license key
 //	wt auth status                Show current license tier
@@ -19,39 +43,127 @@
 //	wt registry add <n> <url>     Add a named registry
 //	wt registry remove <name>     Remove a named registry
 //	wt registry list              List configured registries
+//	wt self update [version]      Replace wt with a verified release from the registry
 //	wt conformance <binary>       Run conformance tests against a twin
+//	wt diff-versions <twin> <old> <new> --scenario <file>  Compare two twin versions' responses
+//	wt k8s generate               Convert the manifest into Kubernetes resources
+//	wt completion bash|zsh|fish   Print a shell completion script
+//
+// Exit codes: 1 other failure, 2 usage or config error, 3 twin unhealthy,
+// 4 scenario failure, 5 license/tier block, 6 registry error.
 package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/audit"
 	"github.com/wondertwin-ai/wondertwin/internal/client"
 	"github.com/wondertwin-ai/wondertwin/internal/config"
 	"github.com/wondertwin-ai/wondertwin/internal/conformance"
+	"github.com/wondertwin-ai/wondertwin/internal/envdiff"
+	"github.com/wondertwin-ai/wondertwin/internal/fixtures"
+	"github.com/wondertwin-ai/wondertwin/internal/graph"
+	"github.com/wondertwin-ai/wondertwin/internal/incident"
+	"github.com/wondertwin-ai/wondertwin/internal/k8s"
 	"github.com/wondertwin-ai/wondertwin/internal/lockfile"
+	"github.com/wondertwin-ai/wondertwin/internal/logquery"
 	"github.com/wondertwin-ai/wondertwin/internal/manifest"
 	"github.com/wondertwin-ai/wondertwin/internal/mcp"
 	"github.com/wondertwin-ai/wondertwin/internal/procmgr"
 	"github.com/wondertwin-ai/wondertwin/internal/registry"
+	"github.com/wondertwin-ai/wondertwin/internal/report"
 	"github.com/wondertwin-ai/wondertwin/internal/scenario/v2"
+	"github.com/wondertwin-ai/wondertwin/internal/versiondiff"
 )
 
 // version is set at build time via -ldflags "-X main.version=..."
 var version = "dev"
 
+// releaseKey is the base64 ed25519 public key wt releases are signed with,
+// set at build time via -ldflags "-X main.releaseKey=...". Builds without
+// one, such as development builds, update themselves on checksums alone.
+var releaseKey = ""
+
 const defaultManifest = "wondertwin.json"
 
+// defaultConformancePort is the port twins are started on for conformance runs.
+const defaultConformancePort = 19876
+
+// Exit codes. CI pipelines and wrapper scripts branch on these rather than
+// on the error text, so they must not change once released.
+const (
+	exitFailure   = 1 // anything not classified below
+	exitConfig    = 2 // bad usage or flags, or an invalid manifest or config
+	exitUnhealthy = 3 // a twin is not running or failed its health check
+	exitScenario  = 4 // test scenarios, benchmark thresholds, or comparisons failed
+	exitLicense   = 5 // the license tier does not cover a twin or version
+	exitRegistry  = 6 // a registry could not be reached or has no such twin
+)
+
+// cliError is an error that carries the exit code wt ends with when a
+// command returns it. Callers may wrap it with %w; exitCode still finds it.
+type cliError struct {
+	code int
+	err  error
+}
+
+func (e *cliError) Error() string { return e.err.Error() }
+func (e *cliError) Unwrap() error { return e.err }
+
+// withExit classifies err under code. Errors that are already classified
+// keep their code, so the outermost wrapper never overrides a more
+// specific one. A nil err stays nil.
+func withExit(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	var ce *cliError
+	if errors.As(err, &ce) {
+		return err
+	}
+	return &cliError{code: code, err: err}
+}
+
+// configErrorf formats an error in the exitConfig class.
+func configErrorf(format string, args ...any) error {
+	return &cliError{code: exitConfig, err: fmt.Errorf(format, args...)}
+}
+
+// usageError returns a command's usage line as an exitConfig error.
+func usageError(usage string) error {
+	return &cliError{code: exitConfig, err: errors.New(usage)}
+}
+
+// exitCode returns the exit code for an error a command returned.
+func exitCode(err error) int {
+	var ce *cliError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return exitFailure
+}
+
 // resolveManifestPath returns the manifest path to use. If the given path
 // is the default JSON and it doesn't exist, fall back to YAML variants.
 func resolveManifestPath(path string) string {
@@ -71,13 +183,21 @@ func resolveManifestPath(path string) string {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "__complete" {
+		cmdComplete(os.Args[2:])
+		return
+	}
+
+	// Twins started with --socket are addressed by http+unix URLs
+	adminclient.RegisterUnixSockets(http.DefaultTransport.(*http.Transport))
+
 	cmd, args, manifestPath := parseArgs()
 	manifestPath = resolveManifestPath(manifestPath)
 
 	if cmd == "" || cmd == "help" || cmd == "--help" || cmd == "-h" {
 		printUsage()
 		if cmd == "" {
-			os.Exit(1)
+			os.Exit(exitConfig)
 		}
 		return
 	}
@@ -88,23 +208,49 @@ func main() {
 		fmt.Printf("wt version %s\n", version)
 		return
 	case "up":
-		err = cmdUp(manifestPath)
+		err = cmdUp(manifestPath, args)
 	case "down":
-		err = cmdDown()
+		err = cmdDown(manifestPath)
+	case "apply":
+		err = cmdApply(manifestPath, args)
 	case "status":
-		err = cmdStatus(manifestPath)
+		err = cmdStatus(manifestPath, args)
+	case "ps":
+		err = cmdPs(manifestPath, args)
+	case "prune":
+		err = cmdPrune(manifestPath, args)
 	case "reset":
-		err = cmdReset(manifestPath)
+		err = cmdReset(manifestPath, args)
 	case "seed":
 		err = cmdSeed(manifestPath, args)
 	case "logs":
 		err = cmdLogs(manifestPath, args)
 	case "inspect":
 		err = cmdInspect(manifestPath, args)
+	case "replay":
+		err = cmdReplay(manifestPath, args)
+	case "intercept":
+		err = cmdIntercept(manifestPath, args)
+	case "webhooks":
+		err = cmdWebhooks(manifestPath, args)
+	case "fixtures":
+		err = cmdFixtures(args)
+	case "env":
+		err = cmdEnv(manifestPath, args)
+	case "audit":
+		err = cmdAudit(manifestPath, args)
 	case "mcp":
 		err = cmdMcp(manifestPath)
 	case "test":
 		err = cmdTest(manifestPath, args)
+	case "chaos":
+		err = cmdChaos(manifestPath, args)
+	case "report":
+		err = cmdReport(manifestPath, args)
+	case "graph":
+		err = cmdGraph(manifestPath, args)
+	case "bench":
+		err = cmdBench(manifestPath, args)
 	case "install":
 		err = cmdInstall(manifestPath, args)
 	case "ci":
@@ -113,17 +259,27 @@ func main() {
 		err = cmdAuth(args)
 	case "registry":
 		err = cmdRegistry(args)
+	case "self":
+		err = cmdSelf(args)
 	case "conformance":
 		err = cmdConformance(args)
+	case "diff-versions":
+		err = cmdDiffVersions(manifestPath, args)
+	case "k8s":
+		err = cmdK8s(manifestPath, args)
+	case "shell":
+		err = cmdShell(manifestPath, args)
+	case "completion":
+		err = cmdCompletion(args)
 	default:
 		fmt.Fprintf(os.Stderr, "wt: unknown command %q\n\n", cmd)
 		printUsage()
-		os.Exit(1)
+		os.Exit(exitConfig)
 	}
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "wt: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -159,16 +315,87 @@ Usage:
 
 Commands:
   up                         Start all twins defined in wondertwin.json (or .yaml)
+                             (--auto-port moves twins off ports taken by other
+                             processes; --write-back saves the new ports to the manifest;
+                             --wait-timeout <dur> bounds the health wait, default 30s, and
+                             exits non-zero when any twin stays unhealthy)
   down                       Stop all running twins
-  status                     Health check all running twins
-  reset                      Reset state on all running twins
-  seed <twin> <file>         POST seed data to a twin
-  logs <twin>                Tail logs of a running twin
-  inspect <twin> [res]       Query twin state (res: state|requests|faults|time)
+  apply [--dry-run]          Reconcile running twins with a changed manifest: start
+                             added twins, stop removed ones, push latency/fail_rate/
+                             seed changes live, and restart only what must restart
+                             (--wait-timeout <dur> as for up)
+  status [--verbose]         Health check all running twins (--verbose: RSS, average CPU
+                             since start, FDs, uptime, version; --watch[=<interval>] refreshes every 2s and
+                             highlights health changes; --exit-on-unhealthy exits non-zero
+                             once any twin is not healthy, for CI readiness gates)
+  ps [--all]                 List twin processes started for this manifest (--all: every
+                             project on this machine)
+  prune [--dry-run]          Remove PID entries of exited twins, logs and install
+                             leftovers untouched for --older-than (default 168h), and
+                             tenants left on running twins by interrupted test runs
+  reset [twin]               Reset state on running twins (--only <res,...> for
+                             selected resources of one twin, --seed <preset> to
+                             land on a named seed preset)
+  seed <twin> <file>         POST seed data to a twin (--dry-run checks that the
+                             seed's references resolve without loading it)
+  logs <twin> [filters]      Tail logs of a running twin (--grep <re>, --level <lvl>,
+                             --since <dur>, --json, --follow)
+  inspect <twin> [res]       Query twin state (res: state|requests|faults|time|
+                             webhooks|events|config|quirks; --json for raw JSON)
+  replay <twin> <id>         Re-send a logged request (twin needs --capture-bodies)
+  intercept <twin> --path <p>
+                             Hold the twin's responses to matching requests
+                             (--method <m>; a prefix ending in * matches a subtree)
+                             and approve, edit in $EDITOR, or deny each before the
+                             client gets it; undecided after --timeout <d> (at
+                             most and by default 20s) they are returned as they are
+  webhooks catalog <twin>    List the webhook event types a twin emits (--json)
+  webhooks trigger <twin> <type>  Emit an event without the API action behind it
+                             (--override <path>=<value>, --overrides <json>)
+  fixtures scrub <in> <out>  Replace emails, names, phone numbers, card details, and
+                             tokens in recorded JSON fixtures or seeds (a file or a
+                             directory) with deterministic fakes (--salt <s>)
+  env export [-o <file>]     Capture this machine's twin environment as JSON: manifest
+                             entries, installed binary and seed hashes, and each
+                             running twin's version, config, quirks, and faults
+  env diff <file> [<file>]   Compare this environment (or a second export) with an
+                             export and list every field that differs
+  audit collect [-o <file>]  Gather the hash-chained log of admin mutations and
+                             scenario runs from every twin into one artifact
+  audit verify <file>        Recompute an artifact's hash chains; --key <k> also
+                             checks the twins' signatures (default $WT_AUDIT_KEY)
+  shell [twin]               Interactive prompt scoped to a twin (inspect, seed,
+                             fault, time, exec) with history
   mcp                        Start MCP server over stdio (for AI agents)
   test [path]                Run JSON test scenarios (default: ./scenarios/)
+                             --pack <twin>/<pack> runs a published scenario pack
+                             --parallel <n> runs n at a time, each as its own
+                             tenant on twins that support tenants
+                             --coverage lists the endpoints of each twin the
+                             scenarios did not exercise
+                             --generate-negative <twin> writes a skeleton per
+                             documented error to --out (default
+                             scenarios/negative/<twin>/), from the twin's
+                             error_catalog or --openapi <file|url>
+  chaos replay <twin>/<pack>  Replay a provider outage from an incident pack: apply
+                             its chaos profiles and fault schedule, run its
+                             scenarios, and restore the twins (--keep leaves them
+                             degraded for a runbook rehearsal)
+  report                     Collate the last test session's scenario results with
+                             each twin's requests, fault activations, webhook
+                             deliveries, and errors (--format html|json, -o <file>)
+  graph                      Draw the manifest's twins, the twins and services their
+                             env points at, and their webhook targets
+                             (--format mermaid|dot, default mermaid; -o <file>)
+  bench <twin> --scenario <file>
+                             Replay a scenario's requests at --rps <n> (default
+                             100) for --duration <d> (default 30s) and report
+                             latency percentiles and errors; --max-p99 <d> and
+                             --max-error-rate <percent> fail the run
   install                    Install all twins from manifest
   install <twin>@<version>   Install a specific twin at a version
+                             (--verify-conformance quarantines binaries that fail
+                             the conformance suite; also settings.verify_conformance)
   ci                         Install twins from lock file (frozen, reproducible)
   auth login                 Activate a license key
   auth status                Show current license tier and org
@@ -176,7 +403,25 @@ Commands:
   registry add <n> <url>     Add a named registry (--token <t> for auth)
   registry remove <name>     Remove a named registry
   registry list              List configured registries
+  self update [version]      Download the latest (or given) wt release from the
+                             registry, verify its checksum and signature, and
+                             replace this binary (--check only reports it)
   conformance <binary>       Run conformance tests against a twin binary
+                             (--perf adds latency/throughput baseline checks;
+                             --probe "POST /v1/x" checks reset restarts ID counters;
+                             --sdk builds and runs twin-<name>/sdkcheck, which drives
+                             the twin with the provider's real SDK, or --sdk-dir <dir>)
+  diff-versions <twin> <old> <new> --scenario <file>|--requests <file>
+                             Start two versions (registry versions or binary paths)
+                             side by side, replay a scenario or a request log saved
+                             with inspect <twin> requests --json against both, and
+                             report response differences (--ignore <f,...> skips
+                             more fields than the default timestamps)
+  k8s generate               Print Kubernetes Deployments, Services (API and admin),
+                             and seed ConfigMaps for every twin (--namespace <ns>,
+                             --image <template> with {name} and {version}, -o <file>)
+  completion bash|zsh|fish   Print a shell completion script (twin names and scenario
+                             paths complete from the manifest and working directory)
   version                    Print the wt version
 
 Options:
@@ -185,6 +430,11 @@ Options:
 Environment:
   WT_CONFIG         Override default manifest path
   WT_REGISTRY_URL   Override registry URL
+
+Exit codes:
+  1  other failure                 4  scenario, benchmark, or comparison failed
+  2  usage or config error         5  license tier does not cover the twin
+  3  twin unhealthy                6  registry error
 `, version)
 }
 
@@ -192,87 +442,386 @@ Environment:
 // wt up
 // ---------------------------------------------------------------------------
 
-func cmdUp(manifestPath string) error {
+func cmdUp(manifestPath string, args []string) error {
+	const usage = "usage: wt up [--auto-port [--write-back]] [--wait-timeout <duration>]"
+	autoPort, writeBack := false, false
+	waitTimeout := defaultWaitTimeout
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--auto-port":
+			autoPort = true
+		case a == "--write-back":
+			writeBack = true
+		case a == "--wait-timeout" || strings.HasPrefix(a, "--wait-timeout="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			if waitTimeout, err = parseWaitTimeout(v); err != nil {
+				return err
+			}
+		default:
+			return usageError(usage)
+		}
+	}
+	if writeBack && !autoPort {
+		return configErrorf("--write-back requires --auto-port")
+	}
+
 	m, err := manifest.Load(manifestPath)
 	if err != nil {
-		return err
+		return withExit(exitConfig, err)
 	}
 
 	// Ensure twins are installed before starting
-	manifestDir := filepath.Dir(manifestPath)
-	if manifestDir == "" || manifestDir == "." {
-		manifestDir, _ = os.Getwd()
+	if err := ensureInstalled(manifestPath, m); err != nil {
+		return err
 	}
 
-	if lockfile.Exists(manifestDir) {
-		fmt.Println("Using locked versions from wondertwin-lock.json")
-		if err := installFromLockFile(manifestDir, m); err != nil {
-			return fmt.Errorf("installing from lock file: %w", err)
-		}
-	} else {
-		fmt.Println("No lock file found, resolving from registry...")
-		if err := cmdInstall(manifestPath, nil); err != nil {
-			return err
-		}
+	unlock, err := procmgr.Lock(manifestPath)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	pids, _ := procmgr.LoadPids()
+	pids, _ := procmgr.LoadPids(manifestPath)
+	procmgr.ApplyRunningPorts(m, pids)
 	ac := client.New()
 
+	// Ports the manifest assigns, which --auto-port must not hand out
+	reserved := map[int]bool{}
+	for _, twin := range m.Twins {
+		if !twin.Remote() && twin.Socket == "" {
+			reserved[twin.Port] = true
+		}
+	}
+
 	fmt.Println("Starting twins...")
 	fmt.Println()
 
+	var unhealthy, waitFor []string
+
 	names := m.TwinNames()
 	for _, name := range names {
 		twin := m.Twins[name]
 
+		if twin.Remote() {
+			fmt.Printf("  %-20s remote (%s)\n", name, twin.URL)
+			continue
+		}
+
 		// Skip if already running
 		if entry, ok := pids[name]; ok && procmgr.IsRunning(entry.PID) {
 			fmt.Printf("  %-20s already running (pid %d)\n", name, entry.PID)
+			waitFor = append(waitFor, name)
 			continue
 		}
 
+		requestedPort := 0
+		if twin.Socket == "" && !procmgr.PortAvailable(twin.Port) {
+			owner := "another process"
+			if ok, _ := ac.Health(twin.BaseURL()); ok {
+				owner = "another twin"
+			}
+			if !autoPort {
+				fmt.Printf("  %-20s FAILED — port %d is in use by %s (use --auto-port to pick a free port)\n", name, twin.Port, owner)
+				unhealthy = append(unhealthy, name)
+				continue
+			}
+			port, err := procmgr.NextFreePort(twin.Port, reserved)
+			if err != nil {
+				fmt.Printf("  %-20s FAILED — port %d is in use by %s and %v\n", name, twin.Port, owner, err)
+				unhealthy = append(unhealthy, name)
+				continue
+			}
+			fmt.Printf("  %-20s port %d is in use by %s, using %d\n", name, twin.Port, owner, port)
+			requestedPort = twin.Port
+			if twin.AdminPort == twin.Port {
+				twin.AdminPort = port
+			}
+			twin.Port = port
+			reserved[port] = true
+			m.Twins[name] = twin
+
+			if writeBack {
+				if err := manifest.SetTwinPort(manifestPath, name, port); err != nil {
+					fmt.Printf("  %-20s warning: port not written to manifest — %v\n", "", err)
+				} else {
+					requestedPort = 0
+				}
+			}
+		}
+
 		pid, err := procmgr.Start(name, twin, m.Settings.LogDir, m.Settings.Verbose)
 		if err != nil {
 			fmt.Printf("  %-20s FAILED — %v\n", name, err)
+			unhealthy = append(unhealthy, name)
 			continue
 		}
 
-		pids[name] = procmgr.PidEntry{
-			PID:    pid,
-			Port:   twin.Port,
-			Binary: twin.Binary,
-		}
-		fmt.Printf("  %-20s started (pid %d, port %d)\n", name, pid, twin.Port)
+		entry := newPidEntry(pid, twin)
+		entry.RequestedPort = requestedPort
+		pids[name] = entry
+		waitFor = append(waitFor, name)
+		fmt.Printf("  %-20s started (pid %d, %s)\n", name, pid, twinListener(twin))
 	}
 
-	if err := procmgr.SavePids(pids); err != nil {
+	if err := procmgr.SavePids(manifestPath, pids); err != nil {
 		return fmt.Errorf("saving pid state: %w", err)
 	}
 
-	// Give twins a moment to bind their ports
 	fmt.Println()
-	fmt.Println("Waiting for health checks...")
-	time.Sleep(1500 * time.Millisecond)
+	fmt.Printf("Waiting for health checks (timeout %s)...\n", waitTimeout)
+	fmt.Println()
+
+	unhealthy = append(unhealthy, waitForTwins(m, pids, waitFor, waitTimeout, ac)...)
+
 	fmt.Println()
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		return withExit(exitUnhealthy, fmt.Errorf("twins not healthy: %s; use 'wt logs <twin>' to investigate", strings.Join(unhealthy, ", ")))
+	}
+	fmt.Println("All twins up and healthy.")
+	return nil
+}
 
-	allHealthy := true
+// defaultWaitTimeout is how long wt up and wt apply wait for twins to
+// become healthy unless --wait-timeout says otherwise.
+const defaultWaitTimeout = 30 * time.Second
+
+// waitForTwins polls each named twin that has a PID entry until it is
+// healthy, exits, or timeout elapses, printing each twin's result as it
+// settles and periodically naming the twins still pending. Default quirks
+// and the manifest config block are applied to healthy twins. It returns
+// the twins that never became healthy or whose config could not be
+// applied.
+func waitForTwins(m *manifest.Manifest, pids procmgr.PidMap, names []string, timeout time.Duration, ac *client.AdminClient) []string {
+	type result struct {
+		name    string
+		err     error
+		elapsed time.Duration
+	}
+	results := make(chan result)
+	pending := map[string]bool{}
+	start := time.Now()
 	for _, name := range names {
+		entry, ok := pids[name]
+		if !ok {
+			continue
+		}
+		pending[name] = true
 		twin := m.Twins[name]
-		ok, _ := ac.Health(twin.AdminPort)
-		if ok {
-			fmt.Printf("  %-20s healthy    http://localhost:%d\n", name, twin.Port)
-		} else {
-			fmt.Printf("  %-20s unhealthy  http://localhost:%d\n", name, twin.Port)
-			allHealthy = false
+		go func() {
+			err := procmgr.WaitHealthy(entry.PID, timeout, func() bool {
+				ok, _ := ac.Health(twin.AdminURL())
+				return ok
+			})
+			results <- result{name, err, time.Since(start)}
+		}()
+	}
+
+	var unhealthy []string
+	progress := time.NewTicker(5 * time.Second)
+	defer progress.Stop()
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.name)
+			twin := m.Twins[r.name]
+			switch {
+			case r.err == nil:
+				fmt.Printf("  %-20s healthy    %-23s (%s)\n", r.name, twinURL(twin), r.elapsed.Round(10*time.Millisecond))
+				applyDefaultQuirks(m, r.name, ac)
+				// A twin without its configured behavior would let tests
+				// pass against the wrong environment.
+				if err := applyTwinConfig(ac, twin, nil); err != nil {
+					fmt.Printf("  %-20s FAILED — config not applied: %v\n", "", err)
+					unhealthy = append(unhealthy, r.name)
+				}
+			case errors.Is(r.err, procmgr.ErrExited):
+				fmt.Printf("  %-20s exited     %-23s (after %s)\n", r.name, twinURL(twin), r.elapsed.Round(10*time.Millisecond))
+				unhealthy = append(unhealthy, r.name)
+			default:
+				fmt.Printf("  %-20s unhealthy  %-23s (no response in %s)\n", r.name, twinURL(twin), timeout)
+				unhealthy = append(unhealthy, r.name)
+			}
+		case <-progress.C:
+			waiting := make([]string, 0, len(pending))
+			for name := range pending {
+				waiting = append(waiting, name)
+			}
+			sort.Strings(waiting)
+			fmt.Printf("  still waiting for %s (%s)\n", strings.Join(waiting, ", "), time.Since(start).Round(time.Second))
 		}
 	}
+	return unhealthy
+}
 
-	fmt.Println()
-	if allHealthy {
-		fmt.Println("All twins up and healthy.")
-	} else {
-		fmt.Println("Some twins failed health check. Use 'wt logs <twin>' to investigate.")
+// parseWaitTimeout parses a --wait-timeout value.
+func parseWaitTimeout(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, configErrorf("--wait-timeout must be a positive duration like \"30s\", got %q", v)
+	}
+	return d, nil
+}
+
+// flagValue returns the value of the flag at args[*i], given either as
+// --flag=value or as the next argument, advancing *i past a separate value.
+func flagValue(args []string, i *int) (string, error) {
+	a := args[*i]
+	if name, v, ok := strings.Cut(a, "="); ok && strings.HasPrefix(name, "--") {
+		return v, nil
+	}
+	if *i+1 >= len(args) {
+		return "", configErrorf("%s requires a value", a)
+	}
+	*i++
+	return args[*i], nil
+}
+
+// loadManifest loads the manifest and points twins that wt up --auto-port
+// moved at the ports they are running on. Commands that talk to running
+// twins use it instead of manifest.Load.
+func loadManifest(manifestPath string) (*manifest.Manifest, error) {
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		return nil, withExit(exitConfig, err)
+	}
+	if pids, err := procmgr.LoadPids(manifestPath); err == nil {
+		procmgr.ApplyRunningPorts(m, pids)
+	}
+	return m, nil
+}
+
+// ensureInstalled installs the manifest's twins, from the lock file when one
+// exists and from the registry otherwise.
+func ensureInstalled(manifestPath string, m *manifest.Manifest) error {
+	manifestDir := filepath.Dir(manifestPath)
+	if manifestDir == "" || manifestDir == "." {
+		manifestDir, _ = os.Getwd()
+	}
+
+	if lockfile.Exists(manifestDir) {
+		fmt.Println("Using locked versions from wondertwin-lock.json")
+		if err := installFromLockFile(manifestDir, m); err != nil {
+			return fmt.Errorf("installing from lock file: %w", err)
+		}
+		return nil
+	}
+	fmt.Println("No lock file found, resolving from registry...")
+	return cmdInstall(manifestPath, nil)
+}
+
+// twinURL returns where a twin is reached, for display: its base URL, or
+// unix:<path> for a twin on a unix socket.
+func twinURL(twin manifest.Twin) string {
+	if twin.Socket != "" {
+		return twin.Addr()
+	}
+	return twin.BaseURL()
+}
+
+// twinListener describes the port or socket a local twin listens on.
+func twinListener(twin manifest.Twin) string {
+	if twin.Socket != "" {
+		return "socket " + twin.Socket
+	}
+	return fmt.Sprintf("port %d", twin.Port)
+}
+
+// newPidEntry records a freshly started twin along with the manifest entry it
+// was started from, so wt apply can later tell what changed.
+func newPidEntry(pid int, twin manifest.Twin) procmgr.PidEntry {
+	spec := twin
+	return procmgr.PidEntry{
+		PID:       pid,
+		Port:      twin.Port,
+		Binary:    twin.Binary,
+		StartedAt: time.Now().UTC(),
+		Spec:      &spec,
+	}
+}
+
+// applyDefaultQuirks enables the quirks a twin declares as on-by-default in
+// its twin-manifest.json. Failures are reported but never abort startup.
+// Twins with a read-only admin API are left as they are.
+func applyDefaultQuirks(m *manifest.Manifest, name string, ac *client.AdminClient) {
+	twin := m.Twins[name]
+	if twin.AdminReadOnly {
+		return
+	}
+	tm, err := m.TwinManifest(name)
+	if err != nil || tm == nil || !tm.Supports(manifest.CapQuirks) {
+		return
+	}
+	for _, id := range tm.Admin.DefaultQuirks {
+		if err := ac.EnableQuirk(twin.AdminURL(), id); err != nil {
+			fmt.Printf("  %-20s warning: default quirk %s not enabled — %v\n", "", id, err)
+		}
+	}
+}
+
+// applyTwinConfig pushes a twin's manifest config block through its admin
+// API: latency and fail rate (its own or its chaos profile's) and the
+// webhook URL to /admin/config, quirks to /admin/quirks, and the chaos profile's storage
+// failures to /admin/faults/storage. prev is the block the twin ran with
+// before, when wt apply changes it, so quirks and storage failures the new
+// block drops are turned off.
+func applyTwinConfig(ac *client.AdminClient, twin manifest.Twin, prev *manifest.TwinConfig) error {
+	cfg := twin.Config
+	if cfg == nil && prev == nil {
+		return nil
+	}
+	settings := cfg.Settings()
+	// Settings only the old block made fall back to the top-level ones the
+	// twin was started with.
+	for key := range prev.Settings() {
+		if _, ok := settings[key]; ok {
+			continue
+		}
+		switch {
+		case key == "fail_rate":
+			settings[key] = twin.FailRate
+		case key == "webhook_url":
+			settings[key] = "" // wt starts twins without one
+		case twin.Latency != "":
+			settings[key] = twin.Latency
+		default:
+			settings[key] = "0s"
+		}
+	}
+	if len(settings) > 0 {
+		if err := ac.UpdateConfig(twin.AdminURL(), settings); err != nil {
+			return fmt.Errorf("updating config: %w", err)
+		}
+	}
+
+	if prev != nil {
+		for _, id := range prev.Quirks {
+			if cfg == nil || !slices.Contains(cfg.Quirks, id) {
+				if err := ac.DisableQuirk(twin.AdminURL(), id); err != nil {
+					return fmt.Errorf("disabling quirk %s: %w", id, err)
+				}
+			}
+		}
+	}
+	if cfg != nil {
+		for _, id := range cfg.Quirks {
+			if err := ac.EnableQuirk(twin.AdminURL(), id); err != nil {
+				return fmt.Errorf("enabling quirk %s: %w", id, err)
+			}
+		}
+	}
+
+	if rate := cfg.StorageFailureRate(); rate > 0 {
+		if err := ac.SetStorageFailures(twin.AdminURL(), adminclient.StorageFailureConfig{Rate: rate, Ops: []string{"write"}}); err != nil {
+			return fmt.Errorf("setting storage failures: %w", err)
+		}
+	} else if prev.StorageFailureRate() > 0 {
+		if err := ac.ClearStorageFailures(twin.AdminURL()); err != nil {
+			return fmt.Errorf("clearing storage failures: %w", err)
+		}
 	}
 	return nil
 }
@@ -281,8 +830,14 @@ func cmdUp(manifestPath string) error {
 // wt down
 // ---------------------------------------------------------------------------
 
-func cmdDown() error {
-	pids, err := procmgr.LoadPids()
+func cmdDown(manifestPath string) error {
+	unlock, err := procmgr.Lock(manifestPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	pids, err := procmgr.LoadPids(manifestPath)
 	if err != nil {
 		return fmt.Errorf("loading pid state: %w", err)
 	}
@@ -307,7 +862,7 @@ func cmdDown() error {
 		}
 	}
 
-	procmgr.RemovePidFile()
+	procmgr.RemovePidFile(manifestPath)
 
 	fmt.Println()
 	fmt.Println("All twins stopped.")
@@ -315,104 +870,745 @@ func cmdDown() error {
 }
 
 // ---------------------------------------------------------------------------
-// wt status
+// wt apply
 // ---------------------------------------------------------------------------
 
-func cmdStatus(manifestPath string) error {
-	m, err := manifest.Load(manifestPath)
+// cmdApply reconciles the running fleet with a changed manifest. Twins added
+// to the manifest are started, removed twins are stopped, latency, fail rate,
+// and seed changes are pushed through the admin API, and any other change
+// restarts only the affected twin.
+func cmdApply(manifestPath string, args []string) error {
+	dryRun := false
+	waitTimeout := defaultWaitTimeout
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--dry-run":
+			dryRun = true
+		case a == "--wait-timeout" || strings.HasPrefix(a, "--wait-timeout="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			if waitTimeout, err = parseWaitTimeout(v); err != nil {
+				return err
+			}
+		default:
+			return usageError("usage: wt apply [--dry-run] [--wait-timeout <duration>]")
+		}
+	}
+
+	m, err := loadManifest(manifestPath)
 	if err != nil {
 		return err
 	}
 
-	pids, _ := procmgr.LoadPids()
-	ac := client.New()
+	unlock, err := procmgr.Lock(manifestPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	pids, err := procmgr.LoadPids(manifestPath)
+	if err != nil {
+		return fmt.Errorf("loading pid state: %w", err)
+	}
+	running := procmgr.PidMap{}
+	for name, entry := range pids {
+		if procmgr.IsRunning(entry.PID) {
+			running[name] = entry
+		}
+	}
 
+	changes := procmgr.Plan(running, m)
+	if len(changes) == 0 {
+		fmt.Println("Running twins already match the manifest.")
+		return nil
+	}
+
+	fmt.Println()
+	for _, c := range changes {
+		fmt.Printf("  %-20s %-12s %s\n", c.Twin, c.Action, strings.Join(c.Reasons, ", "))
+	}
 	fmt.Println()
-	fmt.Printf("  %-20s %-8s %-7s %-11s %s\n", "TWIN", "PID", "PORT", "HEALTH", "URL")
-	fmt.Printf("  %-20s %-8s %-7s %-11s %s\n", "----", "---", "----", "------", "---")
+	if dryRun {
+		return nil
+	}
 
-	for _, name := range m.TwinNames() {
-		twin := m.Twins[name]
-		pidStr := "-"
-		health := "stopped"
+	needsInstall := false
+	for _, c := range changes {
+		if c.Action == procmgr.ActionStart || c.Action == procmgr.ActionRestart {
+			needsInstall = true
+		}
+	}
+	if needsInstall {
+		if err := ensureInstalled(manifestPath, m); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
 
-		if entry, ok := pids[name]; ok && procmgr.IsRunning(entry.PID) {
-			pidStr = fmt.Sprintf("%d", entry.PID)
-			ok, _ := ac.Health(twin.AdminPort)
-			if ok {
-				health = "healthy"
-			} else {
-				health = "unhealthy"
+	ac := client.New()
+	var started []string
+	failed := false
+	for _, c := range changes {
+		twin := m.Twins[c.Twin]
+		switch c.Action {
+		case procmgr.ActionStop:
+			procmgr.Stop(c.Twin, running[c.Twin])
+			delete(pids, c.Twin)
+			fmt.Printf("  %-20s stopped (was pid %d)\n", c.Twin, running[c.Twin].PID)
+
+		case procmgr.ActionStart, procmgr.ActionRestart:
+			verb := "started"
+			requestedPort := 0
+			if entry, ok := running[c.Twin]; ok {
+				procmgr.Stop(c.Twin, entry)
+				delete(pids, c.Twin)
+				verb = "restarted"
+				// Keep an --auto-port assignment across the restart
+				if entry.RequestedPort != 0 && entry.Port == twin.Port {
+					requestedPort = entry.RequestedPort
+				}
+			}
+			pid, err := procmgr.Start(c.Twin, twin, m.Settings.LogDir, m.Settings.Verbose)
+			if err != nil {
+				fmt.Printf("  %-20s FAILED — %v\n", c.Twin, err)
+				failed = true
+				continue
+			}
+			entry := newPidEntry(pid, twin)
+			entry.RequestedPort = requestedPort
+			pids[c.Twin] = entry
+			started = append(started, c.Twin)
+			fmt.Printf("  %-20s %s (pid %d, %s)\n", c.Twin, verb, pid, twinListener(twin))
+
+		case procmgr.ActionReconfigure:
+			if err := reconfigureTwin(ac, twin, c); err != nil {
+				fmt.Printf("  %-20s FAILED — %v\n", c.Twin, err)
+				failed = true
+				continue
 			}
+			entry := pids[c.Twin]
+			spec := twin
+			entry.Spec = &spec
+			pids[c.Twin] = entry
+			fmt.Printf("  %-20s reconfigured\n", c.Twin)
 		}
+	}
 
-		fmt.Printf("  %-20s %-8s %-7d %-11s http://localhost:%d\n",
-			name, pidStr, twin.Port, health, twin.Port)
+	if err := procmgr.SavePids(manifestPath, pids); err != nil {
+		return fmt.Errorf("saving pid state: %w", err)
+	}
+
+	if len(started) > 0 {
+		fmt.Println()
+		fmt.Printf("Waiting for health checks (timeout %s)...\n", waitTimeout)
+		fmt.Println()
+		if unhealthy := waitForTwins(m, pids, started, waitTimeout, ac); len(unhealthy) > 0 {
+			failed = true
+		}
 	}
 
 	fmt.Println()
+	if failed {
+		return withExit(exitUnhealthy, errors.New("some twins could not be updated; use 'wt logs <twin>' to investigate"))
+	}
+	fmt.Println("Manifest applied.")
+	return nil
+}
+
+// reconfigureTwin pushes runtime config changes to a running twin and, when
+// its seed changed, resets it onto the new seed file. The manifest config
+// block is reapplied last, since it takes precedence over the top-level
+// settings and a reset clears its storage failures.
+func reconfigureTwin(ac *client.AdminClient, twin manifest.Twin, c procmgr.Change) error {
+	if len(c.Config) > 0 {
+		if err := ac.UpdateConfig(twin.AdminURL(), c.Config); err != nil {
+			return err
+		}
+	}
+	if c.Reseed {
+		if _, err := ac.Reset(twin.AdminURL()); err != nil {
+			return err
+		}
+		if twin.Seed != "" {
+			if _, err := ac.Seed(twin.AdminURL(), twin.Seed); err != nil {
+				return err
+			}
+		}
+	}
+	if c.Profile {
+		return applyTwinConfig(ac, twin, c.PrevProfile)
+	}
+	if twin.Config != nil && (len(c.Config) > 0 || c.Reseed) {
+		return applyTwinConfig(ac, twin, nil)
+	}
 	return nil
 }
 
 // ---------------------------------------------------------------------------
-// wt reset
+// wt ps [--all]
 // ---------------------------------------------------------------------------
 
-func cmdReset(manifestPath string) error {
-	m, err := manifest.Load(manifestPath)
-	if err != nil {
-		return err
+// cmdPs lists the processes wt has started for this project, or with --all
+// for every project on the machine. Unlike status it reads only PID state,
+// so it works without the manifest and never calls the twins.
+func cmdPs(manifestPath string, args []string) error {
+	all := false
+	for _, a := range args {
+		switch a {
+		case "--all", "-a":
+			all = true
+		default:
+			return usageError("usage: wt ps [--all]")
+		}
 	}
 
-	pids, _ := procmgr.LoadPids()
-	ac := client.New()
-
-	fmt.Println("Resetting twins...")
-	fmt.Println()
-
-	for _, name := range m.TwinNames() {
-		twin := m.Twins[name]
-		if entry, ok := pids[name]; !ok || !procmgr.IsRunning(entry.PID) {
-			fmt.Printf("  %-20s skipped (not running)\n", name)
-			continue
+	var projects []procmgr.Project
+	if all {
+		var err error
+		if projects, err = procmgr.Projects(); err != nil {
+			return err
 		}
-
-		resp, err := ac.Reset(twin.AdminPort)
+	} else {
+		pids, err := procmgr.LoadPids(manifestPath)
 		if err != nil {
-			fmt.Printf("  %-20s FAILED — %v\n", name, err)
-		} else {
-			fmt.Printf("  %-20s reset   %s\n", name, resp)
+			return fmt.Errorf("loading pid state: %w", err)
 		}
+		if len(pids) > 0 {
+			abs, _ := manifest.ResolvePath(manifestPath)
+			projects = []procmgr.Project{{Manifest: abs, Pids: pids}}
+		}
+	}
+	if len(projects) == 0 {
+		fmt.Println("No twins running.")
+		return nil
 	}
 
+	for _, p := range projects {
+		fmt.Println()
+		fmt.Printf("  %s\n", p.Manifest)
+		fmt.Printf("  %-20s %-8s %-7s %-8s %s\n", "TWIN", "PID", "PORT", "STATE", "UPTIME")
+		fmt.Printf("  %-20s %-8s %-7s %-8s %s\n", "----", "---", "----", "-----", "------")
+		names := make([]string, 0, len(p.Pids))
+		for name := range p.Pids {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			entry := p.Pids[name]
+			state, uptime := "exited", "-"
+			if procmgr.IsRunning(entry.PID) {
+				state = "running"
+				if !entry.StartedAt.IsZero() {
+					uptime = time.Since(entry.StartedAt).Round(time.Second).String()
+				}
+			}
+			fmt.Printf("  %-20s %-8d %-7d %-8s %s\n", name, entry.PID, entry.Port, state, uptime)
+		}
+	}
 	fmt.Println()
 	return nil
 }
 
 // ---------------------------------------------------------------------------
-// wt seed <twin> <file>
+// wt prune [--dry-run] [--older-than <duration>]
 // ---------------------------------------------------------------------------
 
-func cmdSeed(manifestPath string, args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("usage: wt seed <twin> <file>")
+// defaultPruneAge is how long a file must go untouched before wt prune
+// treats it as stale.
+const defaultPruneAge = 7 * 24 * time.Hour
+
+// cmdPrune removes state wt and its twins leave behind on long-lived
+// machines: PID entries of twins that exited without wt down, in every
+// project; log files and install leftovers nothing has touched for
+// --older-than; and the tenants wt test --parallel creates, when a run was
+// interrupted before deleting them. Tenants are only looked for on the
+// running twins of this manifest, so do not prune while a test run is in
+// progress.
+func cmdPrune(manifestPath string, args []string) error {
+	const usage = "usage: wt prune [--dry-run] [--older-than <duration>]"
+	dryRun := false
+	olderThan := defaultPruneAge
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--dry-run":
+			dryRun = true
+		case a == "--older-than" || strings.HasPrefix(a, "--older-than="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			if olderThan, err = time.ParseDuration(v); err != nil || olderThan < 0 {
+				return configErrorf("--older-than must be a duration like \"72h\", got %q", v)
+			}
+		default:
+			return usageError(usage)
+		}
 	}
+	cutoff := time.Now().Add(-olderThan)
 
-	twinName := args[0]
-	seedFile := args[1]
-
-	m, err := manifest.Load(manifestPath)
+	orphans, err := procmgr.Prune(dryRun)
 	if err != nil {
 		return err
 	}
-
-	twin, err := m.Twin(twinName)
-	if err != nil {
-		return err
+	fmt.Println()
+	removed := 0
+	for _, o := range orphans {
+		fmt.Printf("  %-12s %s (pid %d exited) in %s\n", "pid entry", o.Twin, o.Entry.PID, o.Manifest)
+		removed++
+	}
+
+	// Logs and tenants belong to this project's manifest, if there is one.
+	binaryDir := registry.ExpandPath("~/.wondertwin/bin")
+	if _, err := os.Stat(manifestPath); err == nil {
+		m, err := loadManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+		binaryDir = registry.ExpandPath(m.Settings.BinaryDir)
+		pids, _ := procmgr.LoadPids(manifestPath)
+
+		logs, _ := filepath.Glob(filepath.Join(m.Settings.LogDir, "*.log"))
+		for _, path := range logs {
+			name := strings.TrimSuffix(filepath.Base(path), ".log")
+			if entry, ok := pids[name]; ok && procmgr.IsRunning(entry.PID) {
+				continue
+			}
+			if pruneStale(path, cutoff, "log file", dryRun) {
+				removed++
+			}
+		}
+
+		ac := client.New()
+		for _, name := range m.TwinNames() {
+			twin := m.Twins[name]
+			if entry, ok := pids[name]; !ok || !procmgr.IsRunning(entry.PID) {
+				continue
+			}
+			// Twins without tenant support answer 404 here.
+			tenants, err := ac.Tenants(twin.AdminURL())
+			if err != nil {
+				continue
+			}
+			for _, t := range tenants {
+				if !strings.HasPrefix(t.Name, v2.TenantNamePrefix) {
+					continue
+				}
+				fmt.Printf("  %-12s %s on %s (%q)\n", "tenant", t.ID, name, t.Name)
+				if !dryRun {
+					if err := ac.DeleteTenant(twin.AdminURL(), t.ID); err != nil {
+						fmt.Printf("  %-12s %v\n", "", err)
+						continue
+					}
+				}
+				removed++
+			}
+		}
+	}
+
+	// Leftovers of interrupted installs and diff-versions runs, and
+	// binaries that failed conformance long ago.
+	var leftovers []string
+	leftovers = append(leftovers, registry.StagingDir(binaryDir))
+	quarantined, _ := filepath.Glob(filepath.Join(registry.ExpandPath("~/.wondertwin/quarantine"), "twin-*"))
+	leftovers = append(leftovers, quarantined...)
+	diffLogs, _ := filepath.Glob(filepath.Join(os.TempDir(), "wt-diff-versions-*"))
+	leftovers = append(leftovers, diffLogs...)
+	for _, path := range leftovers {
+		if pruneStale(path, cutoff, "leftover", dryRun) {
+			removed++
+		}
+	}
+
+	if removed == 0 {
+		fmt.Println("  Nothing to prune.")
+		fmt.Println()
+		return nil
+	}
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("Would remove %d items (dry run).\n", removed)
+	} else {
+		fmt.Printf("Removed %d items.\n", removed)
+	}
+	return nil
+}
+
+// pruneStale removes path, a file or directory, if it was last modified
+// before cutoff, printing it under kind. It reports whether path was stale.
+func pruneStale(path string, cutoff time.Time, kind string, dryRun bool) bool {
+	info, err := os.Lstat(path)
+	if err != nil || !info.ModTime().Before(cutoff) {
+		return false
+	}
+	fmt.Printf("  %-12s %s (untouched for %s)\n", kind, path, time.Since(info.ModTime()).Round(time.Hour))
+	if !dryRun {
+		if err := os.RemoveAll(path); err != nil {
+			fmt.Printf("  %-12s %v\n", "", err)
+			return false
+		}
+	}
+	return true
+}
+
+// ---------------------------------------------------------------------------
+// wt status
+// ---------------------------------------------------------------------------
+
+func cmdStatus(manifestPath string, args []string) error {
+	const usage = "usage: wt status [--verbose] [--watch[=<interval>]] [--exit-on-unhealthy]"
+	verbose, exitOnUnhealthy := false, false
+	var watch time.Duration
+	for _, a := range args {
+		switch {
+		case a == "--verbose" || a == "-v":
+			verbose = true
+		case a == "--watch" || a == "-w":
+			watch = defaultWatchInterval
+		case strings.HasPrefix(a, "--watch="):
+			d, err := time.ParseDuration(strings.TrimPrefix(a, "--watch="))
+			if err != nil || d <= 0 {
+				return configErrorf("--watch interval must be a positive duration like \"2s\"")
+			}
+			watch = d
+		case a == "--exit-on-unhealthy":
+			exitOnUnhealthy = true
+		default:
+			return usageError(usage)
+		}
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	ac := client.New()
+
+	if watch == 0 {
+		pids, _ := procmgr.LoadPids(manifestPath)
+		health := printStatus(m, pids, ac, verbose, nil)
+		if exitOnUnhealthy {
+			return unhealthyError(health)
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	tty := isTerminal(os.Stdout)
+	var prev map[string]string
+	for {
+		pids, _ := procmgr.LoadPids(manifestPath)
+		if tty {
+			fmt.Print("\033[H\033[2J")
+		}
+		fmt.Printf("Every %s: wt status    %s\n", watch, time.Now().Format(time.TimeOnly))
+		health := printStatus(m, pids, ac, verbose, prev)
+		if exitOnUnhealthy {
+			if err := unhealthyError(health); err != nil {
+				return err
+			}
+		}
+		prev = health
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watch):
+		}
+	}
+}
+
+// defaultWatchInterval is how often wt status --watch refreshes.
+const defaultWatchInterval = 2 * time.Second
+
+// printStatus prints the status table and returns each twin's health
+// ("healthy", "unhealthy", or "stopped"). Twins whose health differs from
+// prev are highlighted so transitions stand out in watch mode.
+func printStatus(m *manifest.Manifest, pids procmgr.PidMap, ac *client.AdminClient, verbose bool, prev map[string]string) map[string]string {
+	tty := isTerminal(os.Stdout)
+	fmt.Println()
+	if verbose {
+		fmt.Printf("  %-20s %-8s %-7s %-11s %-9s %-8s %-6s %-10s %-18s %-9s %-23s %s\n", "TWIN", "PID", "PORT", "HEALTH", "RSS", "AVG CPU", "FDS", "UPTIME", "VERSION", "AUTH", "URL", "CAPABILITIES")
+		fmt.Printf("  %-20s %-8s %-7s %-11s %-9s %-8s %-6s %-10s %-18s %-9s %-23s %s\n", "----", "---", "----", "------", "---", "-------", "---", "------", "-------", "----", "---", "------------")
+	} else {
+		fmt.Printf("  %-20s %-8s %-7s %-11s %s\n", "TWIN", "PID", "PORT", "HEALTH", "URL")
+		fmt.Printf("  %-20s %-8s %-7s %-11s %s\n", "----", "---", "----", "------", "---")
+	}
+
+	healths := make(map[string]string, len(m.Twins))
+	var transitions []string
+	for _, name := range m.TwinNames() {
+		twin := m.Twins[name]
+		pidStr, portStr := "-", strconv.Itoa(twin.Port)
+		if twin.Socket != "" {
+			portStr = "-"
+		}
+		health := "stopped"
+		rss, cpu, fds, uptime, version := "-", "-", "-", "-", "-"
+
+		// Remote twins have no local process; only their health is known
+		running := twin.Remote()
+		if twin.Remote() {
+			pidStr, portStr = "remote", "-"
+		} else if entry, ok := pids[name]; ok && procmgr.IsRunning(entry.PID) {
+			pidStr = fmt.Sprintf("%d", entry.PID)
+			running = true
+			if verbose {
+				if st, err := procmgr.Stats(entry.PID); err == nil {
+					rss = formatBytes(st.RSSBytes)
+					cpu = fmt.Sprintf("%.1f%%", st.CPUPercent)
+					if st.OpenFDs >= 0 {
+						fds = strconv.Itoa(st.OpenFDs)
+					}
+					uptime = st.Uptime.Round(time.Second).String()
+				}
+			}
+		}
+		if running {
+			ok, _ := ac.Health(twin.AdminURL())
+			if ok {
+				health = "healthy"
+			} else {
+				health = "unhealthy"
+			}
+			if verbose {
+				if info, err := ac.Version(twin.AdminURL()); err == nil {
+					version = formatVersion(info)
+				}
+			}
+		}
+		healths[name] = health
+
+		// Pad before coloring so escape codes don't break the columns
+		healthCol := fmt.Sprintf("%-11s", health)
+		if was, ok := prev[name]; ok && was != health {
+			transitions = append(transitions, fmt.Sprintf("%s: %s → %s", name, was, health))
+			if tty {
+				color := "\033[1;31m"
+				if health == "healthy" {
+					color = "\033[1;32m"
+				}
+				healthCol = color + healthCol + "\033[0m"
+			}
+		}
+
+		if verbose {
+			auth, caps := "-", "-"
+			if tm, err := m.TwinManifest(name); err == nil && tm != nil {
+				if tm.AuthPattern() != "" {
+					auth = tm.AuthPattern()
+				}
+				if len(tm.Admin.Capabilities) > 0 {
+					caps = strings.Join(tm.Admin.Capabilities, ",")
+				}
+			}
+			fmt.Printf("  %-20s %-8s %-7s %s %-9s %-8s %-6s %-10s %-18s %-9s %-23s %s\n",
+				name, pidStr, portStr, healthCol, rss, cpu, fds, uptime, version, auth,
+				twinURL(twin), caps)
+		} else {
+			fmt.Printf("  %-20s %-8s %-7s %s %s\n",
+				name, pidStr, portStr, healthCol, twinURL(twin))
+		}
+	}
+
+	fmt.Println()
+	for _, t := range transitions {
+		fmt.Printf("  changed  %s\n", t)
+	}
+	if len(transitions) > 0 {
+		fmt.Println()
+	}
+	return healths
+}
+
+// unhealthyError returns an error naming the twins that are not healthy,
+// or nil when all are.
+func unhealthyError(health map[string]string) error {
+	var bad []string
+	for name, h := range health {
+		if h != "healthy" {
+			bad = append(bad, name+" ("+h+")")
+		}
+	}
+	if len(bad) == 0 {
+		return nil
+	}
+	sort.Strings(bad)
+	return withExit(exitUnhealthy, fmt.Errorf("twins not healthy: %s", strings.Join(bad, ", ")))
+}
+
+// isTerminal reports whether f is a character device, i.e. an interactive
+// terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// formatVersion renders a twin's build as "<version>@<short commit>", so
+// twins built from different commits of the same version stand out.
+func formatVersion(info *client.VersionInfo) string {
+	commit := info.Commit
+	if commit == "" || commit == "unknown" {
+		return info.Version
+	}
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return info.Version + "@" + commit
+}
+
+// formatBytes renders a byte count using binary units (e.g. "12.3MiB").
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ---------------------------------------------------------------------------
+// wt reset
+// ---------------------------------------------------------------------------
+
+func cmdReset(manifestPath string, args []string) error {
+	const usage = "usage: wt reset [twin] [--only <resource,...> | --seed <preset>]"
+	var only []string
+	var target, seed string
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--only" || a == "--seed":
+			if i+1 >= len(args) {
+				return configErrorf("%s requires a value", a)
+			}
+			i++
+			if a == "--only" {
+				only = splitList(args[i])
+			} else {
+				seed = args[i]
+			}
+		case strings.HasPrefix(a, "--only="):
+			only = splitList(strings.TrimPrefix(a, "--only="))
+		case strings.HasPrefix(a, "--seed="):
+			seed = strings.TrimPrefix(a, "--seed=")
+		case strings.HasPrefix(a, "-"):
+			return usageError(usage)
+		case target == "":
+			target = a
+		default:
+			return usageError(usage)
+		}
+	}
+	if len(only) > 0 && seed != "" {
+		return configErrorf("--only and --seed cannot be combined")
+	}
+	if (len(only) > 0 || seed != "") && target == "" {
+		return configErrorf("--only and --seed require a twin name (resources and presets differ between twins)")
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	names := m.TwinNames()
+	if target != "" {
+		if _, err := m.Twin(target); err != nil {
+			return withExit(exitConfig, err)
+		}
+		names = []string{target}
+	}
+
+	pids, _ := procmgr.LoadPids(manifestPath)
+	ac := client.New()
+
+	fmt.Println("Resetting twins...")
+	fmt.Println()
+
+	for _, name := range names {
+		twin := m.Twins[name]
+		if entry, ok := pids[name]; !twin.Remote() && (!ok || !procmgr.IsRunning(entry.PID)) {
+			fmt.Printf("  %-20s skipped (not running)\n", name)
+			continue
+		}
+
+		var resp string
+		switch {
+		case len(only) > 0:
+			resp, err = ac.ResetResources(twin.AdminURL(), only)
+		case seed != "":
+			resp, err = ac.ResetWithSeed(twin.AdminURL(), seed)
+		default:
+			resp, err = ac.Reset(twin.AdminURL())
+		}
+		if err != nil {
+			fmt.Printf("  %-20s FAILED — %v\n", name, err)
+		} else {
+			fmt.Printf("  %-20s reset   %s\n", name, resp)
+		}
+	}
+
+	fmt.Println()
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// wt seed <twin> <file> [--dry-run]
+// ---------------------------------------------------------------------------
+
+func cmdSeed(manifestPath string, args []string) error {
+	const usage = "usage: wt seed <twin> <file> [--dry-run]"
+	dryRun := false
+	var positional []string
+	for _, a := range args {
+		switch {
+		case a == "--dry-run":
+			dryRun = true
+		case strings.HasPrefix(a, "-"):
+			return configErrorf("unknown flag %s\n%s", a, usage)
+		default:
+			positional = append(positional, a)
+		}
+	}
+	if len(positional) != 2 {
+		return usageError(usage)
+	}
+
+	twinName := positional[0]
+	seedFile := positional[1]
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	twin, err := m.Twin(twinName)
+	if err != nil {
+		return withExit(exitConfig, err)
 	}
 
 	ac := client.New()
-	resp, err := ac.Seed(twin.AdminPort, seedFile)
+	if dryRun {
+		return lintSeed(ac, twinName, twin.AdminURL(), seedFile)
+	}
+	resp, err := ac.Seed(twin.AdminURL(), seedFile)
 	if err != nil {
 		return fmt.Errorf("seeding %s: %w", twinName, err)
 	}
@@ -421,25 +1617,93 @@ func cmdSeed(manifestPath string, args []string) error {
 	return nil
 }
 
+// lintSeed checks a seed file's references against the relations the twin
+// declares, without loading it, and fails if any do not resolve.
+func lintSeed(ac *client.AdminClient, twinName, adminURL, seedFile string) error {
+	result, err := ac.LintSeed(adminURL, seedFile)
+	if err != nil {
+		return fmt.Errorf("checking %s: %w", twinName, err)
+	}
+	if result.Valid {
+		if result.Relations == 0 {
+			fmt.Printf("%s: valid JSON; %s declares no relations to check\n", seedFile, twinName)
+		} else {
+			fmt.Printf("%s: all references resolve (%d relations checked, nothing loaded)\n", seedFile, result.Relations)
+		}
+		return nil
+	}
+	for _, p := range result.Problems {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", seedFile, p)
+	}
+	return fmt.Errorf("%s: %d broken reference(s); nothing was loaded", seedFile, len(result.Problems))
+}
+
 // ---------------------------------------------------------------------------
 // wt logs <twin>
 // ---------------------------------------------------------------------------
 
+const logsUsage = "usage: wt logs <twin> [--grep <regex>] [--level <debug|info|warn|error>] [--since <duration>] [--json] [--follow]"
+
 func cmdLogs(manifestPath string, args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: wt logs <twin>")
+		return fmt.Errorf(logsUsage)
 	}
 
 	twinName := args[0]
 
+	// Parse filter flags. Without any, fall through to a plain tail -f.
+	var filter logquery.Filter
+	var asJSON, follow, filtered bool
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--grep", "--level", "--since":
+			if i+1 >= len(args) {
+				return configErrorf("%s requires a value\n%s", args[i], logsUsage)
+			}
+			val := args[i+1]
+			switch args[i] {
+			case "--grep":
+				re, err := regexp.Compile(val)
+				if err != nil {
+					return configErrorf("invalid --grep pattern: %w", err)
+				}
+				filter.Grep = re
+			case "--level":
+				if !logquery.ValidLevel(val) {
+					return configErrorf("invalid --level %q (expected debug, info, warn, or error)", val)
+				}
+				filter.MinLevel = val
+			case "--since":
+				d, err := time.ParseDuration(val)
+				if err != nil {
+					return configErrorf("invalid --since duration: %w", err)
+				}
+				filter.Since = time.Now().Add(-d)
+			}
+			filtered = true
+			i++
+		case "--json":
+			asJSON = true
+			filtered = true
+		case "--follow", "-f":
+			follow = true
+		default:
+			return configErrorf("unknown flag %q\n%s", args[i], logsUsage)
+		}
+	}
+
 	m, err := manifest.Load(manifestPath)
 	if err != nil {
-		return err
+		return withExit(exitConfig, err)
 	}
 
 	// Validate twin exists in manifest
-	if _, err := m.Twin(twinName); err != nil {
-		return err
+	twin, err := m.Twin(twinName)
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+	if twin.Remote() {
+		return fmt.Errorf("%s is a remote twin (%s); its logs are kept where it is hosted", twinName, twin.URL)
 	}
 
 	logPath := fmt.Sprintf("%s/%s.log", m.Settings.LogDir, twinName)
@@ -448,6 +1712,10 @@ func cmdLogs(manifestPath string, args []string) error {
 		return fmt.Errorf("no logs found for %s (expected %s)", twinName, logPath)
 	}
 
+	if filtered {
+		return queryLogs(logPath, filter, asJSON, follow)
+	}
+
 	// tail -f the log file
 	cmd := exec.Command("tail", "-f", "-n", "100", logPath)
 	cmd.Stdout = os.Stdout
@@ -466,29 +1734,97 @@ func cmdLogs(manifestPath string, args []string) error {
 	return cmd.Run()
 }
 
+// queryLogs prints the log entries matching filter, optionally following the
+// file for new entries until interrupted.
+func queryLogs(logPath string, filter logquery.Filter, asJSON, follow bool) error {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !follow {
+		n, err := logquery.Scan(f, os.Stdout, filter, asJSON)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", logPath, err)
+		}
+		if n == 0 && !asJSON {
+			fmt.Fprintln(os.Stderr, "No matching log entries.")
+		}
+		return nil
+	}
+
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sig
+		close(stop)
+	}()
+	return logquery.Follow(f, os.Stdout, filter, asJSON, stop)
+}
+
 // ---------------------------------------------------------------------------
 // wt inspect <twin> [resource]
 // ---------------------------------------------------------------------------
 
+// inspectCapabilities maps inspect resources to the admin capability a twin
+// must declare in its twin-manifest.json to serve them.
+var inspectCapabilities = map[string]string{
+	"time":     manifest.CapClock,
+	"webhooks": manifest.CapWebhooks,
+	"events":   manifest.CapWebhooks,
+	"quirks":   manifest.CapQuirks,
+}
+
+// inspectTables renders resources that have a table view. Resources not
+// listed here are always printed as JSON.
+var inspectTables = map[string]func(raw string) error{
+	"webhooks": printWebhooksTable,
+	"events":   printEventsTable,
+	"config":   printConfigTable,
+	"quirks":   printQuirksTable,
+}
+
 func cmdInspect(manifestPath string, args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("usage: wt inspect <twin> [state|requests|faults|time]")
+	asJSON := false
+	var positional []string
+	for _, a := range args {
+		if a == "--json" {
+			asJSON = true
+			continue
+		}
+		positional = append(positional, a)
+	}
+	if len(positional) < 1 || len(positional) > 2 {
+		return usageError("usage: wt inspect <twin> [state|requests|faults|time|webhooks|events|config|quirks] [--json]")
 	}
 
-	twinName := args[0]
+	twinName := positional[0]
 	resource := "state"
-	if len(args) >= 2 {
-		resource = args[1]
+	if len(positional) == 2 {
+		resource = positional[1]
 	}
 
-	m, err := manifest.Load(manifestPath)
+	m, err := loadManifest(manifestPath)
 	if err != nil {
 		return err
 	}
 
 	twin, err := m.Twin(twinName)
 	if err != nil {
-		return err
+		return withExit(exitConfig, err)
+	}
+
+	// Refuse resources the twin declares it does not support
+	if capability := inspectCapabilities[resource]; capability != "" {
+		tm, err := m.TwinManifest(twinName)
+		if err != nil {
+			return err
+		}
+		if !tm.Supports(capability) {
+			return fmt.Errorf("twin %q does not support %s (resource %q unavailable)", twinName, capability, resource)
+		}
 	}
 
 	ac := client.New()
@@ -496,20 +1832,35 @@ func cmdInspect(manifestPath string, args []string) error {
 	var raw string
 	switch resource {
 	case "state":
-		raw, err = ac.Inspect(twin.AdminPort)
+		raw, err = ac.Inspect(twin.AdminURL())
 	case "requests":
-		raw, err = ac.InspectRequests(twin.AdminPort)
+		raw, err = ac.InspectRequests(twin.AdminURL())
 	case "faults":
-		raw, err = ac.InspectFaults(twin.AdminPort)
+		raw, err = ac.InspectFaults(twin.AdminURL())
 	case "time":
-		raw, err = ac.InspectTime(twin.AdminPort)
+		raw, err = ac.InspectTime(twin.AdminURL())
+	case "webhooks":
+		raw, err = ac.InspectWebhooks(twin.AdminURL())
+	case "events":
+		raw, err = ac.InspectEvents(twin.AdminURL())
+	case "config":
+		raw, err = ac.InspectConfig(twin.AdminURL())
+	case "quirks":
+		raw, err = ac.InspectQuirks(twin.AdminURL())
 	default:
-		return fmt.Errorf("unknown resource %q (expected state, requests, faults, or time)", resource)
+		return configErrorf("unknown resource %q (expected state, requests, faults, time, webhooks, events, config, or quirks)", resource)
 	}
 	if err != nil {
 		return fmt.Errorf("inspecting %s/%s: %w", twinName, resource, err)
 	}
 
+	if printTable, ok := inspectTables[resource]; ok && !asJSON {
+		if err := printTable(raw); err == nil {
+			return nil
+		}
+		// Unexpected shape — fall through to JSON
+	}
+
 	// Pretty-print the JSON response
 	pretty, err := prettyJSON(raw)
 	if err != nil {
@@ -521,660 +1872,3591 @@ func cmdInspect(manifestPath string, args []string) error {
 	return nil
 }
 
-// prettyJSON re-formats a JSON string with indentation.
-func prettyJSON(raw string) (string, error) {
-	var parsed json.RawMessage
-	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
-		return "", err
-	}
-	indented, err := json.MarshalIndent(parsed, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(indented), nil
+// inspectEvent mirrors twinkit/webhook.Event as served by /admin/events.
+type inspectEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
-// ---------------------------------------------------------------------------
-// wt mcp
-// ---------------------------------------------------------------------------
+// inspectDelivery mirrors twinkit/webhook.Delivery.
+type inspectDelivery struct {
+	EventID    string    `json:"event_id"`
+	URL        string    `json:"url"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error"`
+	Attempt    int       `json:"attempt"`
+	Timestamp  time.Time `json:"timestamp"`
+}
 
-func cmdMcp(manifestPath string) error {
-	m, err := manifest.Load(manifestPath)
-	if err != nil {
+func printEventsTable(raw string) error {
+	var events []inspectEvent
+	if err := json.Unmarshal([]byte(raw), &events); err != nil {
 		return err
 	}
-
-	srv := mcp.NewServer(m)
-	return srv.Serve()
+	fmt.Println()
+	printEventRows(events)
+	fmt.Println()
+	return nil
+}
+
+func printEventRows(events []inspectEvent) {
+	if len(events) == 0 {
+		fmt.Println("  (none)")
+		return
+	}
+	fmt.Printf("  %-20s %-32s %s\n", "ID", "TYPE", "CREATED")
+	fmt.Printf("  %-20s %-32s %s\n", "--", "----", "-------")
+	for _, e := range events {
+		fmt.Printf("  %-20s %-32s %s\n", e.ID, e.Type, e.CreatedAt.Format(time.RFC3339))
+	}
+}
+
+func printWebhooksTable(raw string) error {
+	var hooks struct {
+		Queued     []inspectEvent    `json:"queued"`
+		Deliveries []inspectDelivery `json:"deliveries"`
+	}
+	if err := json.Unmarshal([]byte(raw), &hooks); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Queued events (%d):\n", len(hooks.Queued))
+	printEventRows(hooks.Queued)
+	fmt.Println()
+	fmt.Printf("Deliveries (%d):\n", len(hooks.Deliveries))
+	if len(hooks.Deliveries) == 0 {
+		fmt.Println("  (none)")
+	} else {
+		fmt.Printf("  %-20s %-7s %-7s %-20s %s\n", "EVENT", "ATTEMPT", "STATUS", "TIME", "URL")
+		fmt.Printf("  %-20s %-7s %-7s %-20s %s\n", "-----", "-------", "------", "----", "---")
+		for _, d := range hooks.Deliveries {
+			status := strconv.Itoa(d.StatusCode)
+			if d.Error != "" {
+				status = "error"
+			}
+			fmt.Printf("  %-20s %-7d %-7s %-20s %s\n", d.EventID, d.Attempt, status, d.Timestamp.Format(time.RFC3339), d.URL)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func printConfigTable(raw string) error {
+	var cfg map[string]any
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(cfg))
+	for k := range cfg {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Println()
+	fmt.Printf("  %-24s %s\n", "KEY", "VALUE")
+	fmt.Printf("  %-24s %s\n", "---", "-----")
+	for _, k := range keys {
+		v, _ := json.Marshal(cfg[k])
+		fmt.Printf("  %-24s %s\n", k, v)
+	}
+	fmt.Println()
+	return nil
+}
+
+func printQuirksTable(raw string) error {
+	var quirks []struct {
+		ID       string `json:"id"`
+		Summary  string `json:"summary"`
+		Enabled  bool   `json:"enabled"`
+		Type     string `json:"type"`
+		Severity string `json:"severity"`
+	}
+	if err := json.Unmarshal([]byte(raw), &quirks); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if len(quirks) == 0 {
+		fmt.Println("  (none)")
+		fmt.Println()
+		return nil
+	}
+	fmt.Printf("  %-16s %-8s %-9s %-20s %s\n", "ID", "ENABLED", "SEVERITY", "TYPE", "SUMMARY")
+	fmt.Printf("  %-16s %-8s %-9s %-20s %s\n", "--", "-------", "--------", "----", "-------")
+	for _, q := range quirks {
+		enabled := "no"
+		if q.Enabled {
+			enabled = "yes"
+		}
+		fmt.Printf("  %-16s %-8s %-9s %-20s %s\n", q.ID, enabled, q.Severity, q.Type, q.Summary)
+	}
+	fmt.Println()
+	return nil
 }
 
 // ---------------------------------------------------------------------------
-// wt test [path]
+// wt replay <twin> <request-id>
 // ---------------------------------------------------------------------------
 
-func cmdTest(manifestPath string, args []string) error {
-	m, err := manifest.Load(manifestPath)
+func cmdReplay(manifestPath string, args []string) error {
+	if len(args) < 2 {
+		return usageError("usage: wt replay <twin> <request-id>")
+	}
+
+	twinName := args[0]
+	requestID := args[1]
+
+	m, err := loadManifest(manifestPath)
 	if err != nil {
 		return err
 	}
 
-	// Determine what to load: a specific file, a directory, or the default ./scenarios/
-	path := "./scenarios/"
-	if len(args) > 0 {
-		path = args[0]
+	twin, err := m.Twin(twinName)
+	if err != nil {
+		return withExit(exitConfig, err)
 	}
 
-	info, err := os.Stat(path)
+	ac := client.New()
+	raw, err := ac.Replay(twin.AdminURL(), requestID)
 	if err != nil {
-		return fmt.Errorf("scenario path %s: %w", path, err)
+		return fmt.Errorf("replaying %s/%s: %w", twinName, requestID, err)
 	}
 
-	var totalPassed, totalFailed, totalSteps int
+	pretty, err := prettyJSON(raw)
+	if err != nil {
+		fmt.Print(raw)
+		return nil
+	}
+	fmt.Println(pretty)
+	return nil
+}
 
-	if info.IsDir() {
-		scenarios, err := v2.LoadDir(path)
-		if err != nil {
-			return fmt.Errorf("loading scenarios: %w", err)
-		}
-		runner := v2.NewRunner(m)
-		for _, s := range scenarios {
-			result, runErr := runner.Run(s)
-			p, f, st := printScenarioResult(s.Name, s.Description, result, runErr)
-			totalPassed += p
-			totalFailed += f
-			totalSteps += st
+// prettyJSON re-formats a JSON string with indentation.
+func prettyJSON(raw string) (string, error) {
+	var parsed json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return "", err
+	}
+	indented, err := json.MarshalIndent(parsed, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(indented), nil
+}
+
+// ---------------------------------------------------------------------------
+// wt webhooks catalog|trigger <twin>
+// ---------------------------------------------------------------------------
+
+func cmdWebhooks(manifestPath string, args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: wt webhooks <catalog|trigger>")
+	}
+
+	switch args[0] {
+	case "catalog":
+		return cmdWebhooksCatalog(manifestPath, args[1:])
+	case "trigger":
+		return cmdWebhooksTrigger(manifestPath, args[1:])
+	default:
+		return configErrorf("unknown webhooks subcommand %q (expected catalog or trigger)", args[0])
+	}
+}
+
+// webhookTwin resolves a twin that must support webhooks.
+func webhookTwin(manifestPath, twinName string) (manifest.Twin, error) {
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return manifest.Twin{}, err
+	}
+	twin, err := m.Twin(twinName)
+	if err != nil {
+		return manifest.Twin{}, withExit(exitConfig, err)
+	}
+	tm, err := m.TwinManifest(twinName)
+	if err != nil {
+		return manifest.Twin{}, err
+	}
+	if !tm.Supports(manifest.CapWebhooks) {
+		return manifest.Twin{}, fmt.Errorf("twin %q does not support %s", twinName, manifest.CapWebhooks)
+	}
+	return twin, nil
+}
+
+func cmdWebhooksCatalog(manifestPath string, args []string) error {
+	asJSON := false
+	var positional []string
+	for _, a := range args {
+		if a == "--json" {
+			asJSON = true
+			continue
 		}
-	} else {
-		s, err := v2.LoadScenario(path)
+		positional = append(positional, a)
+	}
+	if len(positional) != 1 {
+		return usageError("usage: wt webhooks catalog <twin> [--json]")
+	}
+	twinName := positional[0]
+
+	twin, err := webhookTwin(manifestPath, twinName)
+	if err != nil {
+		return err
+	}
+	raw, err := client.New().EventCatalog(twin.AdminURL())
+	if err != nil {
+		return fmt.Errorf("listing %s event types: %w", twinName, err)
+	}
+	if asJSON {
+		pretty, err := prettyJSON(raw)
 		if err != nil {
-			return err
+			fmt.Print(raw)
+			return nil
 		}
-		runner := v2.NewRunner(m)
-		result, runErr := runner.Run(s)
-		p, f, st := printScenarioResult(s.Name, s.Description, result, runErr)
-		totalPassed += p
-		totalFailed += f
-		totalSteps += st
+		fmt.Println(pretty)
+		return nil
 	}
 
-	// Summary
+	var types []adminclient.EventType
+	if err := json.Unmarshal([]byte(raw), &types); err != nil {
+		return fmt.Errorf("parsing event catalog: %w", err)
+	}
 	fmt.Println()
-	fmt.Printf("Results: %d passed, %d failed, %d total\n", totalPassed, totalFailed, totalPassed+totalFailed)
-
-	if totalFailed > 0 {
-		os.Exit(1)
+	fmt.Printf("  %-34s %s\n", "TYPE", "DESCRIPTION")
+	fmt.Printf("  %-34s %s\n", "----", "-----------")
+	for _, et := range types {
+		fmt.Printf("  %-34s %s\n", et.Type, et.Description)
 	}
+	fmt.Println()
 	return nil
 }
 
-// printScenarioResult prints scenario results and returns counts.
-func printScenarioResult(name, description string, result *v2.Result, err error) (passed, failed, steps int) {
-	fmt.Printf("\n--- %s ---\n", name)
-	if description != "" {
-		fmt.Printf("    %s\n", description)
+func cmdWebhooksTrigger(manifestPath string, args []string) error {
+	usage := "usage: wt webhooks trigger <twin> <type> [--override <path>=<value> ...] [--overrides <json>]"
+	var positional []string
+	overrides := map[string]any{}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch name, _, _ := strings.Cut(a, "="); name {
+		case "--override":
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			path, value, ok := strings.Cut(v, "=")
+			if !ok || path == "" {
+				return configErrorf("--override %q: expected <path>=<value>", v)
+			}
+			setOverride(overrides, strings.Split(path, "."), overrideValue(value))
+		case "--overrides":
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			var m map[string]any
+			if err := json.Unmarshal([]byte(v), &m); err != nil {
+				return configErrorf("--overrides: expected a JSON object: %w", err)
+			}
+			for k, val := range m {
+				overrides[k] = val
+			}
+		default:
+			if strings.HasPrefix(a, "--") {
+				return configErrorf("unknown flag %q\n%s", a, usage)
+			}
+			positional = append(positional, a)
+		}
 	}
-	fmt.Println()
+	if len(positional) != 2 {
+		return usageError(usage)
+	}
+	twinName, eventType := positional[0], positional[1]
 
+	twin, err := webhookTwin(manifestPath, twinName)
 	if err != nil {
-		fmt.Printf("  ERROR: %v\n", err)
-		return 0, 1, 0
+		return err
+	}
+	raw, err := client.New().TriggerEvent(twin.AdminURL(), eventType, overrides)
+	if err != nil {
+		return fmt.Errorf("triggering %s on %s: %w", eventType, twinName, err)
 	}
 
-	for _, sr := range result.Steps {
-		steps++
-		if sr.Passed {
-			fmt.Printf("  PASS  %-50s (%s)\n", sr.Name, sr.Duration.Round(time.Millisecond))
-			passed++
-		} else {
-			fmt.Printf("  FAIL  %-50s (%s)\n", sr.Name, sr.Duration.Round(time.Millisecond))
-			fmt.Printf("        %s\n", sr.Error)
-			failed++
-		}
+	var resp struct {
+		Event struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"event"`
+	}
+	if json.Unmarshal([]byte(raw), &resp) == nil && resp.Event.ID != "" {
+		fmt.Printf("Triggered %s on %s (event %s).\n", eventType, twinName, resp.Event.ID)
+		return nil
 	}
+	fmt.Printf("Triggered %s on %s.\n", eventType, twinName)
+	return nil
+}
 
-	fmt.Printf("\n  Scenario: %s (%s)\n", passFailLabel(result.Passed), result.Duration.Round(time.Millisecond))
-	return
+// overrideValue reads an --override value as JSON when it parses, so numbers,
+// booleans, and null keep their types, and as a plain string otherwise.
+func overrideValue(s string) any {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err == nil {
+		return v
+	}
+	return s
 }
 
-func passFailLabel(passed bool) string {
-	if passed {
-		return "PASSED"
+// setOverride sets value at a dotted path in overrides, creating the objects
+// along the way.
+func setOverride(overrides map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := overrides[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			overrides[key] = next
+		}
+		overrides = next
 	}
-	return "FAILED"
+	overrides[path[len(path)-1]] = value
 }
 
 // ---------------------------------------------------------------------------
-// wt install
+// wt fixtures scrub <in> <out> [--salt <s>]
 // ---------------------------------------------------------------------------
 
-func cmdInstall(manifestPath string, args []string) error {
-	// Load config for tier enforcement and registry lookup
-	cfg, _ := config.Load()
+func cmdFixtures(args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: wt fixtures scrub <in> <out> [--salt <s>]")
+	}
 
-	// wt install <twin>@<version> — install a single twin
-	if len(args) > 0 {
-		spec := args[0]
-		twinName, versionSpec := parseInstallSpec(spec)
+	switch args[0] {
+	case "scrub":
+		return cmdFixturesScrub(args[1:])
+	default:
+		return configErrorf("unknown fixtures subcommand %q (expected scrub)", args[0])
+	}
+}
 
-		if versionSpec == "" {
-			versionSpec = "latest"
+// cmdFixturesScrub rewrites personal data in recorded fixtures or seeds,
+// a JSON file or a directory of them, with deterministic fakes.
+func cmdFixturesScrub(args []string) error {
+	const usage = "usage: wt fixtures scrub <in> <out> [--salt <s>]"
+	var positional []string
+	salt := ""
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--salt" || strings.HasPrefix(a, "--salt="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			salt = v
+		case strings.HasPrefix(a, "-"):
+			return usageError(usage)
+		default:
+			positional = append(positional, a)
 		}
+	}
+	if len(positional) != 2 {
+		return usageError(usage)
+	}
+	in, out := positional[0], positional[1]
+	if filepath.Clean(in) == filepath.Clean(out) {
+		return configErrorf("refusing to scrub %s in place; write to a different path and review it first", in)
+	}
 
-		// Use public registry for ad-hoc installs (no manifest context)
-		regEntry := cfg.Registries["public"]
-		if u := os.Getenv("WT_REGISTRY_URL"); u != "" {
-			regEntry.URL = u
-		}
+	scrubber := fixtures.NewScrubber(salt)
+	files, err := scrubber.ScrubPath(in, out)
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
 
-		fmt.Println("Fetching twin registry...")
-		reg, err := registry.FetchRegistry(regEntry.URL, regEntry.Token)
-		if err != nil {
-			return err
+	counts := scrubber.Counts()
+	var replaced []string
+	for _, kind := range []string{fixtures.KindEmail, fixtures.KindName, fixtures.KindPhone, fixtures.KindCard, fixtures.KindToken} {
+		if counts[kind] > 0 {
+			replaced = append(replaced, fmt.Sprintf("%d %s", counts[kind], kind))
 		}
+	}
+	summary := "no personal data found"
+	if len(replaced) > 0 {
+		summary = "distinct values replaced: " + strings.Join(replaced, ", ")
+	}
+	fmt.Printf("Scrubbed %d file(s) into %s: %s\n", len(files), out, summary)
+	fmt.Println("Detection is heuristic: review the output before committing it.")
+	return nil
+}
 
-		resolvedVersion, ver, err := reg.ResolveVersion(twinName, versionSpec)
-		if err != nil {
-			return err
-		}
+// ---------------------------------------------------------------------------
+// wt env export [-o <file>] | wt env diff <file> [<file>]
+// ---------------------------------------------------------------------------
 
-		// Tier enforcement
-		if err := registry.CheckTierAccess(twinName, resolvedVersion, ver, cfg); err != nil {
-			return err
-		}
+func cmdEnv(manifestPath string, args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: wt env export [-o <file>] | wt env diff <file> [<file>]")
+	}
 
-		binaryDir := registry.ExpandPath("~/.wondertwin/bin")
+	switch args[0] {
+	case "export":
+		return cmdEnvExport(manifestPath, args[1:])
+	case "diff":
+		return cmdEnvDiff(manifestPath, args[1:])
+	default:
+		return configErrorf("unknown env subcommand %q (expected export or diff)", args[0])
+	}
+}
 
-		// Skip if already installed
-		if registry.IsAlreadyInstalled(twinName, resolvedVersion, binaryDir) {
-			fmt.Printf("  twin-%s v%s already installed, skipping.\n", twinName, resolvedVersion)
-			return nil
-		}
+// collectEnv captures this machine's environment for the manifest.
+func collectEnv(manifestPath string) (*envdiff.Env, error) {
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	lock, _ := lockfile.Load(filepath.Dir(manifestPath))
+	return envdiff.Collect(context.Background(), m, lock, version), nil
+}
 
-		return registry.Install(twinName, resolvedVersion, ver, binaryDir)
+// cmdEnvExport writes the environment definition a teammate can diff
+// against: declared and installed twins, and what the running ones report.
+func cmdEnvExport(manifestPath string, args []string) error {
+	const usage = "usage: wt env export [-o <file>]"
+	output := ""
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-o" || a == "--output" || strings.HasPrefix(a, "--output="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			output = v
+		default:
+			return usageError(usage)
+		}
 	}
 
-	// wt install — install all twins from manifest
-	m, err := manifest.Load(manifestPath)
+	env, err := collectEnv(manifestPath)
+	if err != nil {
+		return err
+	}
+	if output == "" || output == "-" {
+		return env.WriteJSON(os.Stdout)
+	}
+	f, err := os.Create(output)
 	if err != nil {
 		return err
 	}
+	defer f.Close()
+	if err := env.WriteJSON(f); err != nil {
+		return err
+	}
+	running := 0
+	for _, t := range env.Twins {
+		if t.Running {
+			running++
+		}
+	}
+	fmt.Printf("Wrote the environment of %d twin(s), %d running, to %s\n", len(env.Twins), running, output)
+	return nil
+}
 
-	binaryDir := registry.ExpandPath(m.Settings.BinaryDir)
-	platform := runtime.GOOS + "-" + runtime.GOARCH
+// cmdEnvDiff compares this machine's environment, or a second export, with
+// an export and lists every difference.
+func cmdEnvDiff(manifestPath string, args []string) error {
+	const usage = "usage: wt env diff <theirs.json> [<mine.json>]"
+	if len(args) < 1 || len(args) > 2 || strings.HasPrefix(args[0], "-") {
+		return usageError(usage)
+	}
+	theirs, err := envdiff.Load(args[0])
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+	var mine *envdiff.Env
+	mineLabel := "this environment"
+	if len(args) == 2 {
+		if mine, err = envdiff.Load(args[1]); err != nil {
+			return withExit(exitConfig, err)
+		}
+		mineLabel = args[1]
+	} else if mine, err = collectEnv(manifestPath); err != nil {
+		return err
+	}
 
-	// Group twins by registry so we fetch each registry at most once
-	registryCache := map[string]*registry.Registry{}
-	registryFetchedAt := time.Time{}
+	fmt.Printf("Comparing %s (mine) with %s (theirs, exported %s)\n\n",
+		mineLabel, args[0], theirs.ExportedAt.Format(time.RFC3339))
+	diffs := envdiff.Diff(mine, theirs)
+	if len(diffs) == 0 {
+		fmt.Println("Environments match.")
+		return nil
+	}
+	for _, d := range diffs {
+		fmt.Printf("  %s\n", d)
+	}
+	fmt.Printf("\n%d difference(s)\n", len(diffs))
+	return withExit(exitScenario, fmt.Errorf("environments differ in %d field(s)", len(diffs)))
+}
 
-	// Collect lock file entries as we resolve
-	lockedTwins := map[string]lockfile.LockedTwin{}
+// ---------------------------------------------------------------------------
+// wt audit collect [-o <file>] | wt audit verify <file> [--key <k>]
+// ---------------------------------------------------------------------------
 
-	fmt.Println()
-	names := m.TwinNames()
-	var failed []string
-	for _, name := range names {
-		twin := m.Twins[name]
-		versionSpec := twin.Version
-		if versionSpec == "" {
-			fmt.Printf("  %-20s skipped (no version specified, using binary path)\n", name)
-			continue
+func cmdAudit(manifestPath string, args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: wt audit collect [-o <file>] | wt audit verify <file> [--key <k>]")
+	}
+
+	switch args[0] {
+	case "collect":
+		return cmdAuditCollect(manifestPath, args[1:])
+	case "verify":
+		return cmdAuditVerify(args[1:])
+	default:
+		return configErrorf("unknown audit subcommand %q (expected collect or verify)", args[0])
+	}
+}
+
+// cmdAuditCollect gathers every twin's audit log into one artifact, for
+// compliance runs to keep alongside their test results.
+func cmdAuditCollect(manifestPath string, args []string) error {
+	const usage = "usage: wt audit collect [-o <file>]"
+	output := ""
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-o" || a == "--output" || strings.HasPrefix(a, "--output="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			output = v
+		default:
+			return usageError(usage)
 		}
+	}
 
-		// Resolve which registry this twin uses
-		regName := twin.Registry
-		reg, ok := registryCache[regName]
-		if !ok {
-			regEntry, entryOk := cfg.Registries[regName]
-			if !entryOk {
-				fmt.Printf("  %-20s FAILED — registry %q not configured (run `wt registry add %s <url>`)\n", name, regName, regName)
-				failed = append(failed, name)
-				continue
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	artifact := audit.Collect(context.Background(), m, version)
+	for _, name := range m.TwinNames() {
+		if msg, ok := artifact.Errors[name]; ok {
+			fmt.Fprintf(os.Stderr, "wt: warning: %s: %s\n", name, msg)
+		}
+	}
+	if len(artifact.Twins) == 0 {
+		return fmt.Errorf("no twin returned an audit log (are the twins running?)")
+	}
+	if output == "" || output == "-" {
+		return artifact.WriteJSON(os.Stdout)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := artifact.WriteJSON(f); err != nil {
+		return err
+	}
+	entries := 0
+	for _, exp := range artifact.Twins {
+		entries += len(exp.Entries)
+	}
+	fmt.Printf("Wrote %d audit entries from %d twin(s) to %s (digest %s)\n", entries, len(artifact.Twins), output, artifact.Digest)
+	return nil
+}
+
+// cmdAuditVerify recomputes an artifact's hash chains, and checks the
+// twins' signatures when a key is given.
+func cmdAuditVerify(args []string) error {
+	const usage = "usage: wt audit verify <file> [--key <k>]"
+	path := ""
+	key := os.Getenv("WT_AUDIT_KEY")
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--key" || strings.HasPrefix(a, "--key="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
 			}
-			if u := os.Getenv("WT_REGISTRY_URL"); u != "" && regName == "public" {
-				regEntry.URL = u
+			key = v
+		case path == "" && !strings.HasPrefix(a, "-"):
+			path = a
+		default:
+			return usageError(usage)
+		}
+	}
+	if path == "" {
+		return usageError(usage)
+	}
+
+	artifact, err := audit.Load(path)
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+	problems := artifact.Verify(key)
+	if len(problems) == 0 {
+		signed := "unsigned"
+		if key != "" {
+			signed = "signatures checked"
+		}
+		fmt.Printf("%s: %d twin(s) verified, %s\n", path, len(artifact.Twins), signed)
+		return nil
+	}
+	for _, p := range problems {
+		fmt.Printf("  %s\n", p)
+	}
+	return withExit(exitScenario, fmt.Errorf("%s failed verification in %d place(s)", path, len(problems)))
+}
+
+// ---------------------------------------------------------------------------
+// wt mcp
+// ---------------------------------------------------------------------------
+
+func cmdMcp(manifestPath string) error {
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	srv := mcp.NewServer(m)
+	return srv.Serve()
+}
+
+// ---------------------------------------------------------------------------
+// wt test [path] [--pack <twin>/<pack>] [--parallel <n>] [--coverage]
+// wt test --generate-negative <twin> [--out <dir>] [--openapi <file|url>]
+// ---------------------------------------------------------------------------
+
+// scenarioJob is a scenario queued by wt test. heading, when set, is printed
+// before it, introducing the pack it starts.
+type scenarioJob struct {
+	heading string
+	s       *v2.Scenario
+
+	result *v2.Result
+	err    error
+	done   bool
+}
+
+func cmdTest(manifestPath string, args []string) error {
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	// Determine what to load: a specific file, a directory, or the default
+	// ./scenarios/. Scenario packs from the registry may be given with --pack.
+	var path string
+	var packs []string
+	var negativeTwin, outDir, openapiSrc string
+	parallel := 1
+	coverage := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--coverage":
+			coverage = true
+		case args[i] == "--generate-negative" || args[i] == "--out" || args[i] == "--openapi":
+			if i+1 >= len(args) {
+				return configErrorf("%s requires a value", args[i])
 			}
-			fmt.Printf("  Fetching registry %q...\n", regName)
-			var fetchErr error
-			reg, fetchErr = registry.FetchRegistry(regEntry.URL, regEntry.Token)
-			if fetchErr != nil {
-				fmt.Printf("  %-20s FAILED — %v\n", name, fetchErr)
-				failed = append(failed, name)
-				continue
+			switch args[i] {
+			case "--generate-negative":
+				negativeTwin = args[i+1]
+			case "--out":
+				outDir = args[i+1]
+			default:
+				openapiSrc = args[i+1]
 			}
-			registryCache[regName] = reg
-			registryFetchedAt = time.Now().UTC()
+			i++
+		case args[i] == "--pack":
+			if i+1 >= len(args) {
+				return configErrorf("--pack requires a value (e.g. stripe/payments-happy-path)")
+			}
+			packs = append(packs, args[i+1])
+			i++
+		case strings.HasPrefix(args[i], "--pack="):
+			packs = append(packs, strings.TrimPrefix(args[i], "--pack="))
+		case args[i] == "--parallel" || strings.HasPrefix(args[i], "--parallel="):
+			v, ok := strings.CutPrefix(args[i], "--parallel=")
+			if !ok {
+				if i+1 >= len(args) {
+					return configErrorf("--parallel requires a number of scenarios to run at once")
+				}
+				v = args[i+1]
+				i++
+			}
+			if parallel, err = strconv.Atoi(v); err != nil || parallel < 1 {
+				return configErrorf("--parallel must be a positive number, got %q", v)
+			}
+		default:
+			path = args[i]
 		}
+	}
+	if negativeTwin != "" {
+		return generateNegative(m, negativeTwin, openapiSrc, outDir)
+	}
+	if path == "" && len(packs) == 0 {
+		path = "./scenarios/"
+	}
 
-		resolvedVersion, ver, err := reg.ResolveVersion(name, versionSpec)
+	var jobs []*scenarioJob
+	for _, spec := range packs {
+		pack, err := fetchPack(m, spec)
 		if err != nil {
-			fmt.Printf("  %-20s FAILED — %v\n", name, err)
-			failed = append(failed, name)
-			continue
+			return err
+		}
+		if pack.Incident != nil {
+			return configErrorf("%s is an incident pack; replay it with `wt chaos replay %s`", spec, spec)
 		}
+		heading := fmt.Sprintf("\nScenario pack %s (%d scenarios)", spec, len(pack.Scenarios))
+		if len(pack.Scenarios) == 0 {
+			fmt.Println(heading)
+		}
+		for i := range pack.Scenarios {
+			jobs = append(jobs, &scenarioJob{heading: heading, s: &pack.Scenarios[i]})
+			heading = ""
+		}
+	}
 
-		// Record lock entry
-		lockedTwins[name] = lockfile.LockedTwin{
-			Version:      resolvedVersion,
-			ResolvedFrom: versionSpec,
-			SDKPackage:   ver.SDKPackage,
-			SDKVersion:   ver.SDKVersion,
-			Checksum:     ver.Checksums[platform],
-			BinaryURL:    ver.BinaryURLs[platform],
+	if path != "" {
+		info, err := os.Stat(path)
+		if err != nil {
+			return configErrorf("scenario path %s: %w", path, err)
+		}
+		if info.IsDir() {
+			scenarios, err := v2.LoadDir(path)
+			if err != nil {
+				return configErrorf("loading scenarios: %w", err)
+			}
+			for _, s := range scenarios {
+				jobs = append(jobs, &scenarioJob{s: s})
+			}
+		} else {
+			s, err := v2.LoadScenario(path)
+			if err != nil {
+				return withExit(exitConfig, err)
+			}
+			jobs = append(jobs, &scenarioJob{s: s})
 		}
+	}
 
-		// Tier enforcement
-		if err := registry.CheckTierAccess(name, resolvedVersion, ver, cfg); err != nil {
-			fmt.Printf("  %-20s BLOCKED — %v\n", name, err)
-			failed = append(failed, name)
-			continue
+	session := &report.Session{StartedAt: time.Now()}
+
+	// With --parallel, scenarios that only make API requests run
+	// concurrently, each as its own tenant on every twin that supports
+	// tenants. The rest change state every scenario shares, so they run
+	// one at a time afterwards, exactly as without --parallel.
+	if parallel > 1 {
+		runParallel(m, jobs, parallel)
+	}
+
+	var totalPassed, totalFailed, totalSteps int
+	runner := v2.NewRunner(m)
+	for _, j := range jobs {
+		if !j.done {
+			j.result, j.err = runner.Run(j.s)
+		}
+		if j.heading != "" {
+			fmt.Println(j.heading)
+		}
+		p, f, st := printScenarioResult(j.s.Name, j.s.Description, j.result, j.err)
+		audit.RecordScenario(context.Background(), m, j.s.Name, j.err == nil && j.result.Passed)
+		totalPassed += p
+		totalFailed += f
+		totalSteps += st
+		session.Scenarios = append(session.Scenarios, report.NewScenarioResult(j.s.Name, j.result, j.err))
+	}
+
+	// Keep the session for wt report. Failing to is not a test failure.
+	session.FinishedAt = time.Now()
+	if dir, err := procmgr.StateDir(manifestPath); err == nil {
+		if err := report.SaveSession(dir, session); err != nil {
+			fmt.Fprintf(os.Stderr, "wt: warning: saving test session for wt report: %v\n", err)
 		}
+	}
+	if coverage {
+		printCoverage(report.Build(session, collectTwinData(m), time.Now()))
+	}
 
-		// Skip if already installed
-		if registry.IsAlreadyInstalled(name, resolvedVersion, binaryDir) {
-			fmt.Printf("  %-20s v%s already installed, skipping.\n", name, resolvedVersion)
+	return printTestSummary(totalPassed, totalFailed)
+}
+
+// printCoverage prints how many of each twin's endpoints a test session
+// exercised, and lists the ones it did not.
+func printCoverage(r *report.Report) {
+	fmt.Println("\nEndpoint coverage")
+	for _, tr := range r.Twins {
+		switch {
+		case tr.Unreachable != "":
+			fmt.Printf("  %s: unreachable: %s\n", tr.Name, tr.Unreachable)
+		case tr.Coverage == nil:
+			fmt.Printf("  %s: does not list its routes (upgrade the twin for coverage)\n", tr.Name)
+		default:
+			fmt.Printf("  %s: %d/%d endpoints exercised\n", tr.Name, tr.Coverage.Covered, tr.Coverage.Total)
+			for _, e := range tr.Coverage.Untested() {
+				fmt.Printf("    untested  %-7s %s\n", e.Method, e.Route)
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// wt chaos replay <twin>/<pack>|<file> [--keep]
+// ---------------------------------------------------------------------------
+
+func cmdChaos(manifestPath string, args []string) error {
+	if len(args) == 0 || args[0] != "replay" {
+		return usageError("usage: wt chaos replay <twin>/<pack>|<file> [--keep]")
+	}
+	return cmdChaosReplay(manifestPath, args[1:])
+}
+
+// cmdChaosReplay replays an incident pack: it applies the incident's chaos
+// profiles and steps to the twins, runs the pack's scenarios against them,
+// and, unless --keep is given, restores the twins' config, quirks, and
+// faults. With --keep the twins stay degraded for a runbook rehearsal until
+// they are restarted.
+func cmdChaosReplay(manifestPath string, args []string) error {
+	const usage = "usage: wt chaos replay <twin>/<pack>|<file> [--keep]"
+	spec := ""
+	keep := false
+	for _, a := range args {
+		switch {
+		case a == "--keep":
+			keep = true
+		case spec == "" && !strings.HasPrefix(a, "-"):
+			spec = a
+		default:
+			return usageError(usage)
+		}
+	}
+	if spec == "" {
+		return usageError(usage)
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	// A local pack file, while authoring one, or a published pack.
+	var pack *v2.Pack
+	if _, statErr := os.Stat(spec); statErr == nil {
+		if pack, err = v2.LoadPack(spec); err != nil {
+			return withExit(exitConfig, err)
+		}
+	} else if pack, err = fetchPack(m, spec); err != nil {
+		return err
+	}
+	inc := pack.Incident
+	if inc == nil {
+		return configErrorf("%s is a scenario pack, not an incident pack; run it with `wt test --pack %s`", spec, spec)
+	}
+
+	fmt.Printf("Incident %s: %s\n", pack.Name, inc.Summary)
+	if inc.Reference != "" {
+		fmt.Printf("  See %s\n", inc.Reference)
+	}
+
+	ctx := context.Background()
+	snap, err := incident.Take(ctx, m, inc.Twins())
+	if err != nil {
+		return fmt.Errorf("recording twin behavior before the incident (are the twins running?): %w", err)
+	}
+	if !keep {
+		defer func() {
+			errs := snap.Restore(ctx)
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "wt: warning: restoring %v\n", err)
+			}
+			if len(errs) == 0 {
+				fmt.Printf("Restored %s\n", strings.Join(inc.Twins(), ", "))
+			}
+		}()
+	}
+
+	names := make([]string, 0, len(inc.Chaos))
+	for name := range inc.Chaos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := incident.ApplyChaos(ctx, m, name, inc.Chaos[name]); err != nil {
+			return fmt.Errorf("applying chaos profile %s: %w", inc.Chaos[name], err)
+		}
+		fmt.Printf("  %-20s chaos profile %s\n", name, inc.Chaos[name])
+	}
+	runner := v2.NewRunner(m)
+	if len(inc.Steps) > 0 {
+		res, err := runner.Run(&v2.Scenario{Name: "Incident: " + pack.Name, Steps: inc.Steps})
+		if _, failed, _ := printScenarioResult("Incident: "+pack.Name, "", res, err); failed > 0 {
+			return fmt.Errorf("applying incident %s failed", pack.Name)
+		}
+	}
+
+	var totalPassed, totalFailed int
+	for i := range pack.Scenarios {
+		s := &pack.Scenarios[i]
+		res, err := runner.Run(s)
+		p, f, _ := printScenarioResult(s.Name, s.Description, res, err)
+		totalPassed += p
+		totalFailed += f
+	}
+	if keep {
+		fmt.Printf("\nLeaving %s degraded (--keep); restart them with `wt down` and `wt up` to recover\n", strings.Join(inc.Twins(), ", "))
+	}
+	return printTestSummary(totalPassed, totalFailed)
+}
+
+// runParallel runs the jobs that can be isolated, up to n at a time, and
+// marks them done.
+func runParallel(m *manifest.Manifest, jobs []*scenarioJob, n int) {
+	runner := v2.NewRunner(m)
+	runner.Isolate()
+
+	var isolated []*scenarioJob
+	for _, j := range jobs {
+		if runner.Isolatable(j.s) {
+			isolated = append(isolated, j)
+		}
+	}
+	if shared := len(jobs) - len(isolated); shared > 0 {
+		fmt.Printf("Running %d scenarios in parallel; %d change shared state (seed files, admin or exec steps, or resetting a twin without tenants) and run one at a time\n", len(isolated), shared)
+	}
+
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for _, j := range isolated {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			j.result, j.err = runner.Run(j.s)
+			j.done = true
+		}()
+	}
+	wg.Wait()
+}
+
+// printTestSummary prints the overall result line and returns an
+// exitScenario error on failure.
+func printTestSummary(passed, failed int) error {
+	fmt.Println()
+	fmt.Printf("Results: %d passed, %d failed, %d total\n", passed, failed, passed+failed)
+
+	if failed > 0 {
+		return withExit(exitScenario, fmt.Errorf("%d of %d steps failed", failed, passed+failed))
+	}
+	return nil
+}
+
+// fetchPack resolves a "<twin>[@<version>]/<pack>" reference against the
+// registry and returns the downloaded scenario pack. Without an explicit
+// version, the twin's manifest version (or latest) is used so the pack
+// matches the twin under test.
+// generateNegative writes a scenario skeleton for each documented error of
+// a twin's endpoints. The errors come from the error_catalog of the twin's
+// twin-manifest.json, or from the 4xx responses of an OpenAPI document
+// (a file or URL) when openapiSrc is set. Existing files are left alone, so
+// skeletons already filled in survive a rerun.
+func generateNegative(m *manifest.Manifest, twinName, openapiSrc, outDir string) error {
+	tm, err := m.TwinManifest(twinName)
+	if err != nil {
+		return err
+	}
+
+	catalog := tm.ErrorCatalog()
+	source := "twin-manifest.json error_catalog"
+	if openapiSrc != "" {
+		data, err := readSource(openapiSrc)
+		if err != nil {
+			return fmt.Errorf("reading OpenAPI document: %w", err)
+		}
+		if catalog, err = manifest.ErrorCatalogFromOpenAPI(data); err != nil {
+			return err
+		}
+		source = openapiSrc
+	}
+	if len(catalog) == 0 {
+		return fmt.Errorf("no documented errors for %s in %s (add an error_catalog to its twin-manifest.json or pass --openapi <file|url>)", twinName, source)
+	}
+
+	if outDir == "" {
+		outDir = filepath.Join("scenarios", "negative", twinName)
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+
+	var written, skipped, todos int
+	for _, g := range v2.GenerateNegative(twinName, tm.AuthPattern(), catalog) {
+		path := filepath.Join(outDir, g.File)
+		if _, err := os.Stat(path); err == nil {
+			skipped++
 			continue
 		}
+		data, err := json.MarshalIndent(g.Scenario, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+		written++
+		if len(g.Scenario.Variables) > 0 {
+			todos++
+		}
+	}
+
+	fmt.Printf("Generated %d negative scenarios for %s in %s (from %s)\n", written, twinName, outDir, source)
+	if skipped > 0 {
+		fmt.Printf("  %d already existed and were left unchanged\n", skipped)
+	}
+	if todos > 0 {
+		fmt.Printf("  %d have TODO variables to fill in before they run\n", todos)
+	}
+	return nil
+}
+
+// readSource reads a local file, or fetches src when it is an http(s) URL.
+func readSource(src string) ([]byte, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.ReadFile(src)
+	}
+	resp, err := http.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: status %d", src, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func fetchPack(m *manifest.Manifest, spec string) (*v2.Pack, error) {
+	twinName, versionSpec, packName, err := registry.ParsePackSpec(spec)
+	if err != nil {
+		return nil, withExit(exitConfig, err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, withExit(exitConfig, err)
+	}
+	regName := "public"
+	if twin, ok := m.Twins[twinName]; ok {
+		if versionSpec == "" {
+			versionSpec = twin.Version
+		}
+		if twin.Registry != "" {
+			regName = twin.Registry
+		}
+	}
+	if versionSpec == "" {
+		versionSpec = "latest"
+	}
+
+	regEntry, ok := cfg.Registries[regName]
+	if !ok {
+		return nil, configErrorf("registry %q not configured (run `wt registry add %s <url>`)", regName, regName)
+	}
+	if u := os.Getenv("WT_REGISTRY_URL"); u != "" && regName == "public" {
+		regEntry.URL = u
+	}
+
+	reg, err := registry.FetchRegistry(regEntry.URL, regEntry.Token)
+	if err != nil {
+		return nil, withExit(exitRegistry, err)
+	}
+	resolvedVersion, ver, err := reg.ResolveVersion(twinName, versionSpec)
+	if err != nil {
+		return nil, withExit(exitRegistry, err)
+	}
+	for _, w := range reg.ResolveWarnings(twinName, versionSpec, resolvedVersion) {
+		fmt.Fprintf(os.Stderr, "wt: warning: %s\n", w)
+	}
+	if err := registry.CheckTierAccess(twinName, resolvedVersion, ver, cfg); err != nil {
+		return nil, withExit(exitLicense, err)
+	}
+
+	packPath, err := registry.FetchScenarioPack(twinName, resolvedVersion, packName, ver, registry.ExpandPath("~/.wondertwin/packs"))
+	if err != nil {
+		return nil, withExit(exitRegistry, err)
+	}
+	return v2.LoadPack(packPath)
+}
+
+// printScenarioResult prints scenario results and returns counts.
+func printScenarioResult(name, description string, result *v2.Result, err error) (passed, failed, steps int) {
+	fmt.Printf("\n--- %s ---\n", name)
+	if description != "" {
+		fmt.Printf("    %s\n", description)
+	}
+	fmt.Println()
+
+	if err != nil {
+		fmt.Printf("  ERROR: %v\n", err)
+		return 0, 1, 0
+	}
+
+	for _, sr := range result.Steps {
+		steps++
+		if sr.Passed {
+			fmt.Printf("  PASS  %-50s (%s)\n", sr.Name, sr.Duration.Round(time.Millisecond))
+			passed++
+		} else {
+			fmt.Printf("  FAIL  %-50s (%s)\n", sr.Name, sr.Duration.Round(time.Millisecond))
+			fmt.Printf("        %s\n", sr.Error)
+			failed++
+		}
+	}
+
+	fmt.Printf("\n  Scenario: %s (%s)\n", passFailLabel(result.Passed), result.Duration.Round(time.Millisecond))
+	return
+}
+
+func passFailLabel(passed bool) string {
+	if passed {
+		return "PASSED"
+	}
+	return "FAILED"
+}
+
+// ---------------------------------------------------------------------------
+// wt intercept <twin> --path <path> [--method <m>] [--timeout <d>]
+// ---------------------------------------------------------------------------
+
+// defaultInterceptTimeout is how long a twin holds a response at an
+// intercept without a --timeout (twincore.DefaultInterceptTimeout).
+const defaultInterceptTimeout = 20 * time.Second
+
+// cmdIntercept holds a twin's responses to matching requests and shows each
+// in the terminal, where the developer approves it, edits it in $EDITOR, or
+// denies it before the client under test gets it. The twin returns a
+// response nobody decides within the timeout as it is, so a forgotten
+// terminal never wedges the client for long.
+func cmdIntercept(manifestPath string, args []string) error {
+	const usage = "usage: wt intercept <twin> --path <path> [--method <method>] [--timeout <d>]"
+	var twinName string
+	var ic client.Intercept
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "--") {
+			if twinName != "" {
+				return usageError(usage)
+			}
+			twinName = a
+			continue
+		}
+		name, _, _ := strings.Cut(a, "=")
+		v, err := flagValue(args, &i)
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "--path":
+			ic.Path = v
+		case "--method":
+			ic.Method = strings.ToUpper(v)
+		case "--timeout":
+			ic.Timeout = v
+		default:
+			return usageError(usage)
+		}
+	}
+	if twinName == "" || ic.Path == "" {
+		return usageError(usage)
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	twin, err := m.Twin(twinName)
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+	adminURL := twin.AdminURL()
+	ac := client.New()
+	if ok, detail := ac.Health(adminURL); !ok {
+		return withExit(exitUnhealthy, fmt.Errorf("%s is not healthy (%s); start it with wt up", twinName, detail))
+	}
+
+	created, err := ac.AddIntercept(adminURL, ic)
+	if err != nil {
+		var apiErr *adminclient.APIError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest:
+			return withExit(exitConfig, err)
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			return fmt.Errorf("%s does not support intercepts; upgrade it with wt install", twinName)
+		}
+		return fmt.Errorf("setting an intercept on %s: %w", twinName, err)
+	}
+	timeout := defaultInterceptTimeout
+	if d, err := time.ParseDuration(created.Timeout); err == nil {
+		timeout = d
+	}
+
+	// Removing the intercept returns anything still held, so an interrupted
+	// session leaves no client waiting on the twin.
+	var once sync.Once
+	remove := func() {
+		once.Do(func() {
+			if err := ac.RemoveIntercept(adminURL, created.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "wt: removing intercept %s: %v\n", created.ID, err)
+			}
+		})
+	}
+	defer remove()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sig
+		fmt.Println()
+		remove()
+		os.Exit(0)
+	}()
+
+	method := created.Method
+	if method == "" {
+		method = "*"
+	}
+	fmt.Printf("Intercepting %s %s on %s (%s). Each response is held up to %s; Ctrl-C to stop.\n\n", method, created.Path, twinName, created.ID, timeout)
+
+	in := bufio.NewReader(os.Stdin)
+	for {
+		held, err := nextHeldResponse(ac, adminURL, created.ID)
+		if err != nil {
+			return fmt.Errorf("watching %s: %w", twinName, err)
+		}
+		printHeldResponse(held, timeout-time.Since(held.Since))
+
+		var d adminclient.InterceptDecision
+	prompt:
+		for {
+			fmt.Print("[a]pprove, [e]dit, [d]eny, [q]uit? ")
+			line, err := in.ReadString('\n')
+			if err != nil && line == "" {
+				fmt.Println()
+				return nil
+			}
+			switch strings.ToLower(strings.TrimSpace(line)) {
+			case "a", "approve":
+				d = adminclient.InterceptDecision{Action: "approve"}
+				break prompt
+			case "d", "deny":
+				d = adminclient.InterceptDecision{Action: "deny"}
+				break prompt
+			case "e", "edit":
+				if d, err = editHeldResponse(in, held); err != nil {
+					fmt.Fprintf(os.Stderr, "wt: %v\n", err)
+					continue
+				}
+				break prompt
+			case "q", "quit":
+				return nil
+			}
+		}
+
+		d.RequestID = held.RequestID
+		var apiErr *adminclient.APIError
+		switch err := ac.DecideIntercept(adminURL, created.ID, d); {
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict:
+			fmt.Printf("Too late: %s returned the response as it was after %s.\n\n", twinName, timeout)
+		case err != nil:
+			return fmt.Errorf("deciding %s: %w", held.RequestID, err)
+		default:
+			fmt.Printf("%s.\n\n", map[string]string{"approve": "Approved", "edit": "Edited", "deny": "Denied"}[d.Action])
+		}
+	}
+}
+
+// nextHeldResponse polls the twin until intercept id holds a response and
+// returns the oldest.
+func nextHeldResponse(ac *client.AdminClient, adminURL, id string) (*adminclient.HeldResponse, error) {
+	for {
+		ics, err := ac.Intercepts(adminURL)
+		if err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(ics, func(ic client.Intercept) bool { return ic.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("intercept %s is gone; was the twin reset?", id)
+		}
+		if held := ics[i].Held; len(held) > 0 {
+			return &held[0], nil
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// printHeldResponse shows a held response and the request it answers.
+func printHeldResponse(h *adminclient.HeldResponse, left time.Duration) {
+	target := h.Path
+	if h.Query != "" {
+		target += "?" + h.Query
+	}
+	fmt.Printf("→ %s %s", h.Method, target)
+	if h.RequestID != "" {
+		fmt.Printf("  (%s)", h.RequestID)
+	}
+	fmt.Println()
+	fmt.Printf("← %d %s  (returned as it is in %s)\n", h.StatusCode, http.StatusText(h.StatusCode), max(left, 0).Round(time.Second))
+	keys := make([]string, 0, len(h.Headers))
+	for k := range h.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("  %s: %s\n", k, h.Headers[k])
+	}
+	body := h.Body
+	if pretty, err := prettyJSON(body); err == nil {
+		body = pretty
+	}
+	if body != "" {
+		fmt.Println()
+		fmt.Println("  " + strings.ReplaceAll(body, "\n", "\n  "))
+	}
+	fmt.Println()
+}
+
+// editHeldResponse opens held's body in $VISUAL or $EDITOR (default vi),
+// then asks for the status code, and returns the edit. A JSON body is
+// edited indented and sent back compact, unless the edit left it invalid.
+func editHeldResponse(in *bufio.Reader, held *adminclient.HeldResponse) (adminclient.InterceptDecision, error) {
+	body, ext := held.Body, ".txt"
+	if pretty, err := prettyJSON(body); err == nil {
+		body, ext = pretty+"\n", ".json"
+	}
+	f, err := os.CreateTemp("", "wt-intercept-*"+ext)
+	if err != nil {
+		return adminclient.InterceptDecision{}, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return adminclient.InterceptDecision{}, err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return adminclient.InterceptDecision{}, fmt.Errorf("running %s: %w", editor, err)
+	}
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return adminclient.InterceptDecision{}, err
+	}
+	if ext == ".json" {
+		var compact bytes.Buffer
+		if json.Compact(&compact, edited) == nil {
+			edited = compact.Bytes()
+		}
+	}
+
+	status := held.StatusCode
+	fmt.Printf("Status [%d]: ", status)
+	line, _ := in.ReadString('\n')
+	if v := strings.TrimSpace(line); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 100 || n > 599 {
+			return adminclient.InterceptDecision{}, fmt.Errorf("status must be an HTTP status code, got %q", v)
+		}
+		status = n
+	}
+	newBody := string(edited)
+	return adminclient.InterceptDecision{Action: "edit", StatusCode: status, Body: &newBody}, nil
+}
+
+// ---------------------------------------------------------------------------
+// wt graph [--format mermaid|dot] [-o <file>]
+// ---------------------------------------------------------------------------
+
+// cmdGraph draws the manifest's topology. It reads only the manifest, so it
+// works before wt up and in CI, where the diagram can be checked in and a
+// topology change reviewed as a diff.
+func cmdGraph(manifestPath string, args []string) error {
+	const usage = "usage: wt graph [--format mermaid|dot] [-o <file>]"
+	format, output := "", ""
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--format" || strings.HasPrefix(a, "--format="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			format = v
+		case a == "-o" || a == "--output" || strings.HasPrefix(a, "--output="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			output = v
+		default:
+			return usageError(usage)
+		}
+	}
+	if format == "" {
+		format = "mermaid"
+		if ext := strings.ToLower(filepath.Ext(output)); ext == ".dot" || ext == ".gv" {
+			format = "dot"
+		}
+	}
+	if format != "mermaid" && format != "dot" {
+		return configErrorf("unknown graph format %q (expected mermaid or dot)", format)
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	g := graph.Build(m)
+
+	w := io.Writer(os.Stdout)
+	if output != "" && output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if format == "dot" {
+		return g.WriteDOT(w)
+	}
+	return g.WriteMermaid(w)
+}
+
+// ---------------------------------------------------------------------------
+// wt report [--format html|json] [-o <file>]
+// ---------------------------------------------------------------------------
+
+// collectTwinData fetches what wt report needs from each twin's admin API.
+func collectTwinData(m *manifest.Manifest) []report.TwinData {
+	ac := client.New()
+	var twins []report.TwinData
+	for _, name := range m.TwinNames() {
+		adminURL := m.Twins[name].AdminURL()
+		td := report.TwinData{Name: name, BaseURL: m.Twins[name].BaseURL()}
+		if td.Requests, td.Err = ac.Requests(adminURL); td.Err == nil {
+			td.Webhooks, td.Err = ac.Webhooks(adminURL)
+		}
+		// Twins without webhook redelivery have no dead letters, and twins
+		// built before routes were listed have no coverage.
+		var apiErr *adminclient.APIError
+		notFound := func(err error) bool { return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound }
+		if td.Err == nil {
+			var err error
+			if td.DeadLetters, err = ac.DeadLetters(adminURL); err != nil && !notFound(err) {
+				td.Err = err
+			}
+		}
+		if td.Err == nil {
+			var err error
+			if td.Routes, err = ac.Routes(adminURL); err != nil && !notFound(err) {
+				td.Err = err
+			}
+		}
+		twins = append(twins, td)
+	}
+	return twins
+}
+
+// cmdReport collates the last wt test session with each twin's request log,
+// webhook deliveries, and dead letters into one artifact. Twins that cannot
+// be reached are listed in the report rather than failing it.
+func cmdReport(manifestPath string, args []string) error {
+	const usage = "usage: wt report [--format html|json] [-o <file>]"
+	format, output := "", ""
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--format" || strings.HasPrefix(a, "--format="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			format = v
+		case a == "-o" || a == "--output" || strings.HasPrefix(a, "--output="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			output = v
+		default:
+			return usageError(usage)
+		}
+	}
+	if format == "" {
+		format = "json"
+		if ext := strings.ToLower(filepath.Ext(output)); ext == ".html" || ext == ".htm" {
+			format = "html"
+		}
+	}
+	if format != "html" && format != "json" {
+		return configErrorf("unknown report format %q (expected html or json)", format)
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	dir, err := procmgr.StateDir(manifestPath)
+	if err != nil {
+		return err
+	}
+	session, err := report.LoadSession(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no test session recorded for %s (run wt test first)", manifestPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	r := report.Build(session, collectTwinData(m), time.Now())
+
+	toFile := output != "" && output != "-"
+	w := io.Writer(os.Stdout)
+	if toFile {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if format == "html" {
+		err = r.WriteHTML(w)
+	} else {
+		err = r.WriteJSON(w)
+	}
+	if err != nil {
+		return err
+	}
+	if toFile {
+		fmt.Printf("Wrote %s report of the test session from %s to %s (%d errors)\n",
+			format, session.StartedAt.Format(time.RFC3339), output, r.Summary.Errors)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// wt bench <twin> --scenario <file> [--rps <n>] [--duration <d>] ...
+// ---------------------------------------------------------------------------
+
+func cmdBench(manifestPath string, args []string) error {
+	const usage = "usage: wt bench <twin> --scenario <file> [--rps <n>] [--duration <d>] [--concurrency <n>] [--max-p99 <d>] [--max-error-rate <percent>]"
+	var twinName, scenarioPath string
+	opts := v2.BenchOptions{RPS: 100, Duration: 30 * time.Second}
+	var maxP99 time.Duration
+	maxErrorRate := -1.0
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "--") {
+			if twinName != "" {
+				return usageError(usage)
+			}
+			twinName = a
+			continue
+		}
+		name, _, _ := strings.Cut(a, "=")
+		v, err := flagValue(args, &i)
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "--scenario":
+			scenarioPath = v
+		case "--rps", "--concurrency":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return configErrorf("%s must be a positive number, got %q", name, v)
+			}
+			if name == "--rps" {
+				opts.RPS = n
+			} else {
+				opts.Concurrency = n
+			}
+		case "--duration", "--max-p99":
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return configErrorf("%s must be a positive duration like \"60s\", got %q", name, v)
+			}
+			if name == "--duration" {
+				opts.Duration = d
+			} else {
+				maxP99 = d
+			}
+		case "--max-error-rate":
+			pct, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+			if err != nil || pct < 0 || pct > 100 {
+				return configErrorf("--max-error-rate must be a percentage from 0 to 100, got %q", v)
+			}
+			maxErrorRate = pct / 100
+		default:
+			return usageError(usage)
+		}
+	}
+	if twinName == "" || scenarioPath == "" {
+		return usageError(usage)
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	twin, err := m.Twin(twinName)
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+	// Against a stopped twin the run would only measure connection errors.
+	if ok, detail := client.New().Health(twin.AdminURL()); !ok {
+		return withExit(exitUnhealthy, fmt.Errorf("%s is not healthy (%s); start it with wt up", twinName, detail))
+	}
+	s, err := v2.LoadScenario(scenarioPath)
+	if err != nil {
+		return err
+	}
+
+	workers := opts.Concurrency
+	if workers == 0 {
+		workers = v2.DefaultBenchConcurrency
+	}
+	fmt.Printf("Benchmarking %s with %q: %d req/s for %s (%d workers, Ctrl-C stops early)\n", twinName, s.Name, opts.RPS, opts.Duration, workers)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	res, err := v2.NewRunner(m).Bench(ctx, s, opts)
+	if err != nil {
+		return err
+	}
+	printBenchResult(res, opts.RPS)
+
+	var failures []string
+	if maxP99 > 0 && res.Latency.P99 > maxP99 {
+		failures = append(failures, fmt.Sprintf("p99 latency %s exceeds %s", roundBenchLatency(res.Latency.P99), maxP99))
+	}
+	if maxErrorRate >= 0 && res.ErrorRate() > maxErrorRate {
+		failures = append(failures, fmt.Sprintf("error rate %.2f%% exceeds %.2f%%", res.ErrorRate()*100, maxErrorRate*100))
+	}
+	if len(failures) > 0 {
+		return withExit(exitScenario, fmt.Errorf("benchmark failed: %s", strings.Join(failures, "; ")))
+	}
+	return nil
+}
+
+// printBenchResult prints the totals of a load run, then a line per step.
+func printBenchResult(res *v2.BenchResult, targetRPS int) {
+	fmt.Println()
+	fmt.Printf("  Requests  %d in %s (%.1f req/s)\n", res.Requests, res.Duration.Round(time.Millisecond), res.RPS())
+	fmt.Printf("  Errors    %d (%.2f%%)\n", res.Errors, res.ErrorRate()*100)
+	fmt.Printf("  Latency   %s\n", formatLatency(res.Latency))
+
+	codes := make([]int, 0, len(res.StatusCodes))
+	for code := range res.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	var parts []string
+	for _, code := range codes {
+		label := strconv.Itoa(code)
+		if code == 0 {
+			label = "no response"
+		}
+		parts = append(parts, fmt.Sprintf("%s: %d", label, res.StatusCodes[code]))
+	}
+	fmt.Printf("  Statuses  %s\n", strings.Join(parts, ", "))
+
+	fmt.Println()
+	for _, st := range res.Steps {
+		fmt.Printf("  %-40s %6d req  %5d err  %s\n", st.Name, st.Requests, st.Errors, formatLatency(st.Latency))
+		if st.FirstError != "" {
+			fmt.Printf("  %-40s first error: %s\n", "", st.FirstError)
+		}
+	}
+	if res.SkippedSteps > 0 {
+		fmt.Printf("\n  %d admin or exec step(s) skipped: faults, time travel, config changes, and commands are not repeated under load\n", res.SkippedSteps)
+	}
+	// The ticker drops requests no worker is free to send, so a shortfall
+	// means the twin (or the workers) could not keep up.
+	if res.RPS() < 0.9*float64(targetRPS) {
+		fmt.Printf("\n  Achieved %.1f of %d req/s: the twin could not keep up; try --concurrency for more workers\n", res.RPS(), targetRPS)
+	}
+}
+
+func formatLatency(l v2.LatencyStats) string {
+	return fmt.Sprintf("p50 %s  p90 %s  p99 %s  max %s",
+		roundBenchLatency(l.P50), roundBenchLatency(l.P90), roundBenchLatency(l.P99), roundBenchLatency(l.Max))
+}
+
+func roundBenchLatency(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
+
+// ---------------------------------------------------------------------------
+// wt install
+// ---------------------------------------------------------------------------
+
+func cmdInstall(manifestPath string, args []string) error {
+	// Load config for tier enforcement and registry lookup
+	cfg, _ := config.Load()
+
+	verify := false
+	var rest []string
+	for _, a := range args {
+		if a == "--verify-conformance" {
+			verify = true
+			continue
+		}
+		rest = append(rest, a)
+	}
+	args = rest
+
+	// wt install <twin>@<version> — install a single twin
+	if len(args) > 0 {
+		spec := args[0]
+		twinName, versionSpec := parseInstallSpec(spec)
+
+		if versionSpec == "" {
+			versionSpec = "latest"
+		}
+
+		// Use public registry for ad-hoc installs (no manifest context)
+		regEntry := cfg.Registries["public"]
+		if u := os.Getenv("WT_REGISTRY_URL"); u != "" {
+			regEntry.URL = u
+		}
+
+		fmt.Println("Fetching twin registry...")
+		reg, err := registry.FetchRegistry(regEntry.URL, regEntry.Token)
+		if err != nil {
+			return withExit(exitRegistry, err)
+		}
+
+		resolvedVersion, ver, err := reg.ResolveVersion(twinName, versionSpec)
+		if err != nil {
+			return withExit(exitRegistry, err)
+		}
+		for _, w := range reg.ResolveWarnings(twinName, versionSpec, resolvedVersion) {
+			fmt.Printf("  warning: %s\n", w)
+		}
+
+		// Tier and wt version enforcement
+		if err := registry.CheckTierAccess(twinName, resolvedVersion, ver, cfg); err != nil {
+			return withExit(exitLicense, err)
+		}
+		if err := registry.CheckWTVersion(twinName, resolvedVersion, ver, version); err != nil {
+			return err
+		}
+
+		binaryDir := registry.ExpandPath("~/.wondertwin/bin")
+
+		// Skip if already installed
+		if registry.IsAlreadyInstalled(twinName, resolvedVersion, binaryDir) {
+			fmt.Printf("  twin-%s v%s already installed, skipping.\n", twinName, resolvedVersion)
+			return nil
+		}
+
+		return withExit(exitRegistry, installTwin(twinName, resolvedVersion, ver, binaryDir, verify))
+	}
+
+	// wt install — install all twins from manifest
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+
+	binaryDir := registry.ExpandPath(m.Settings.BinaryDir)
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	verify = verify || m.Settings.VerifyConformance
+
+	// Group twins by registry so we fetch each registry at most once
+	registryCache := map[string]*registry.Registry{}
+	registryFetchedAt := time.Time{}
+
+	// Collect lock file entries as we resolve
+	lockedTwins := map[string]lockfile.LockedTwin{}
+
+	fmt.Println()
+	names := m.TwinNames()
+	var failed []string
+	// A twin the license does not cover fails the install as a license
+	// block even when others failed for registry reasons, since retrying
+	// will not help.
+	failCode := exitRegistry
+	for _, name := range names {
+		twin := m.Twins[name]
+		if twin.Remote() {
+			fmt.Printf("  %-20s skipped (remote)\n", name)
+			continue
+		}
+		versionSpec := twin.Version
+		if versionSpec == "" {
+			fmt.Printf("  %-20s skipped (no version specified, using binary path)\n", name)
+			continue
+		}
+
+		// Resolve which registry this twin uses
+		regName := twin.Registry
+		reg, ok := registryCache[regName]
+		if !ok {
+			regEntry, entryOk := cfg.Registries[regName]
+			if !entryOk {
+				fmt.Printf("  %-20s FAILED — registry %q not configured (run `wt registry add %s <url>`)\n", name, regName, regName)
+				failed = append(failed, name)
+				continue
+			}
+			if u := os.Getenv("WT_REGISTRY_URL"); u != "" && regName == "public" {
+				regEntry.URL = u
+			}
+			fmt.Printf("  Fetching registry %q...\n", regName)
+			var fetchErr error
+			reg, fetchErr = registry.FetchRegistry(regEntry.URL, regEntry.Token)
+			if fetchErr != nil {
+				fmt.Printf("  %-20s FAILED — %v\n", name, fetchErr)
+				failed = append(failed, name)
+				continue
+			}
+			registryCache[regName] = reg
+			registryFetchedAt = time.Now().UTC()
+		}
+
+		resolvedVersion, ver, err := reg.ResolveVersion(name, versionSpec)
+		if err != nil {
+			fmt.Printf("  %-20s FAILED — %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		if registry.IsVersionRange(versionSpec) {
+			fmt.Printf("  %-20s %s resolved to v%s\n", name, versionSpec, resolvedVersion)
+		}
+		for _, w := range reg.ResolveWarnings(name, versionSpec, resolvedVersion) {
+			fmt.Printf("  %-20s WARNING — %s\n", name, w)
+		}
+
+		// Record lock entry
+		lockedTwins[name] = lockfile.LockedTwin{
+			Version:      resolvedVersion,
+			ResolvedFrom: versionSpec,
+			SDKPackage:   ver.SDKPackage,
+			SDKVersion:   ver.SDKVersion,
+			Checksum:     ver.Checksums[platform],
+			BinaryURL:    ver.BinaryURLs[platform],
+		}
+
+		// Tier and wt version enforcement
+		if err := registry.CheckTierAccess(name, resolvedVersion, ver, cfg); err != nil {
+			fmt.Printf("  %-20s BLOCKED — %v\n", name, err)
+			failed = append(failed, name)
+			failCode = exitLicense
+			continue
+		}
+		if err := registry.CheckWTVersion(name, resolvedVersion, ver, version); err != nil {
+			fmt.Printf("  %-20s BLOCKED — %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+
+		// Skip if already installed
+		if registry.IsAlreadyInstalled(name, resolvedVersion, binaryDir) {
+			fmt.Printf("  %-20s v%s already installed, skipping.\n", name, resolvedVersion)
+			continue
+		}
+
+		if err := installTwin(name, resolvedVersion, ver, binaryDir, verify); err != nil {
+			fmt.Printf("  %-20s FAILED — %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+	}
+
+	fmt.Println()
+	if len(failed) > 0 {
+		return withExit(failCode, fmt.Errorf("failed to install: %s", strings.Join(failed, ", ")))
+	}
+
+	// Write lock file
+	if len(lockedTwins) > 0 {
+		now := time.Now().UTC()
+		if registryFetchedAt.IsZero() {
+			registryFetchedAt = now
+		}
+		lf := &lockfile.LockFile{
+			GeneratedAt:       now,
+			RegistryFetchedAt: registryFetchedAt,
+			Twins:             lockedTwins,
+		}
+		manifestDir := filepath.Dir(manifestPath)
+		if manifestDir == "" || manifestDir == "." {
+			manifestDir, _ = os.Getwd()
+		}
+		if err := lockfile.Save(manifestDir, lf); err != nil {
+			return fmt.Errorf("writing lock file: %w", err)
+		}
+		fmt.Printf("Wrote %s\n", lockfile.Filename)
+	}
+
+	fmt.Println("All twins installed.")
+	return nil
+}
+
+// installTwin installs a resolved twin into binaryDir and prints the
+// version's release notes. With verify, the binary is staged first and only
+// promoted once it passes the conformance suite; binaries that fail are moved
+// to ~/.wondertwin/quarantine.
+func installTwin(name, resolvedVersion string, ver registry.Version, binaryDir string, verify bool) error {
+	if !verify {
+		if err := registry.Install(name, resolvedVersion, ver, binaryDir); err != nil {
+			return err
+		}
+		printReleaseNotes(ver)
+		return nil
+	}
+
+	staging := registry.StagingDir(binaryDir)
+	if err := registry.Install(name, resolvedVersion, ver, staging); err != nil {
+		return err
+	}
+
+	fmt.Printf("  Running conformance suite against twin-%s v%s...\n", name, resolvedVersion)
+	report, err := conformance.Run(filepath.Join(staging, "twin-"+name), defaultConformancePort)
+	if err == nil && report.Failed == 0 {
+		fmt.Printf("  Conformance passed (%d checks)\n", report.Passed)
+		if err := registry.PromoteStaged(name, staging, binaryDir); err != nil {
+			return err
+		}
+		fmt.Printf("  Installed twin-%s v%s -> %s\n", name, resolvedVersion, filepath.Join(binaryDir, "twin-"+name))
+		printReleaseNotes(ver)
+		return nil
+	}
+
+	dest, qerr := registry.QuarantineStaged(name, resolvedVersion, staging, registry.ExpandPath("~/.wondertwin/quarantine"))
+	if qerr != nil {
+		return qerr
+	}
+	if err != nil {
+		return fmt.Errorf("conformance run failed: %w (binary quarantined at %s)", err, dest)
+	}
+	for _, r := range report.Results {
+		if !r.Passed {
+			fmt.Printf("  FAIL  %s: %s\n", r.Name, r.Detail)
+		}
+	}
+	return withExit(exitScenario, fmt.Errorf("failed %d of %d conformance checks (binary quarantined at %s)",
+		report.Failed, report.Passed+report.Failed, dest))
+}
+
+// printReleaseNotes prints the version's CHANGELOG section, if the registry
+// carries one, indented under the install line.
+func printReleaseNotes(ver registry.Version) {
+	if ver.ReleaseNotes == "" {
+		return
+	}
+	fmt.Println("  Release notes:")
+	for _, line := range strings.Split(ver.ReleaseNotes, "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+}
+
+// parseInstallSpec parses "twin@version" into (twin, version).
+// If no @ is present, returns (spec, "").
+func parseInstallSpec(spec string) (string, string) {
+	if i := strings.LastIndex(spec, "@"); i >= 0 {
+		return spec[:i], spec[i+1:]
+	}
+	return spec, ""
+}
+
+// ---------------------------------------------------------------------------
+// wt registry add|remove|list
+// ---------------------------------------------------------------------------
+
+func cmdRegistry(args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: wt registry <add|remove|list>")
+	}
+
+	switch args[0] {
+	case "add":
+		return cmdRegistryAdd(args[1:])
+	case "remove":
+		return cmdRegistryRemove(args[1:])
+	case "list":
+		return cmdRegistryList()
+	default:
+		return configErrorf("unknown registry subcommand %q (expected add, remove, or list)", args[0])
+	}
+}
+
+func cmdRegistryAdd(args []string) error {
+	if len(args) < 2 {
+		return usageError("usage: wt registry add <name> <url> [--token <token>]")
+	}
+
+	name := args[0]
+	url := args[1]
+
+	if name == "public" {
+		return fmt.Errorf("cannot override the built-in public registry")
+	}
+
+	var token string
+	for i := 2; i < len(args); i++ {
+		if args[i] == "--token" && i+1 < len(args) {
+			token = args[i+1]
+			i++
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+
+	cfg.Registries[name] = config.RegistryEntry{
+		URL:   url,
+		Token: token,
+	}
+
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	fmt.Printf("Registry %q added (%s)\n", name, url)
+	if token != "" {
+		fmt.Println("  Token: configured")
+	}
+	return nil
+}
+
+func cmdRegistryRemove(args []string) error {
+	if len(args) < 1 {
+		return usageError("usage: wt registry remove <name>")
+	}
+
+	name := args[0]
+	if name == "public" {
+		return fmt.Errorf("cannot remove the built-in public registry")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+
+	if _, ok := cfg.Registries[name]; !ok {
+		return fmt.Errorf("registry %q not found", name)
+	}
+
+	delete(cfg.Registries, name)
+
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	fmt.Printf("Registry %q removed.\n", name)
+	return nil
+}
+
+func cmdRegistryList() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-20s %-60s %s\n", "NAME", "URL", "AUTH")
+	fmt.Printf("  %-20s %-60s %s\n", "----", "---", "----")
+
+	for name, entry := range cfg.Registries {
+		auth := "-"
+		if entry.Token != "" {
+			auth = "token"
+		}
+		fmt.Printf("  %-20s %-60s %s\n", name, entry.URL, auth)
+	}
+
+	fmt.Println()
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// wt self update
+// ---------------------------------------------------------------------------
+
+func cmdSelf(args []string) error {
+	if len(args) == 0 || args[0] != "update" {
+		return usageError("usage: wt self update [--check] [<version>]")
+	}
+
+	check := false
+	versionSpec := ""
+	for _, a := range args[1:] {
+		switch {
+		case a == "--check":
+			check = true
+		case strings.HasPrefix(a, "-") || versionSpec != "":
+			return usageError("usage: wt self update [--check] [<version>]")
+		default:
+			versionSpec = a
+		}
+	}
+
+	cfg, _ := config.Load()
+	regEntry := cfg.Registries["public"]
+	if u := os.Getenv("WT_REGISTRY_URL"); u != "" {
+		regEntry.URL = u
+	}
+
+	fmt.Println("Fetching registry...")
+	reg, err := registry.FetchRegistry(regEntry.URL, regEntry.Token)
+	if err != nil {
+		return withExit(exitRegistry, err)
+	}
+	resolvedVersion, ver, err := reg.ResolveWT(versionSpec)
+	if err != nil {
+		return withExit(exitRegistry, err)
+	}
+	if ver.Yanked {
+		w := fmt.Sprintf("wt v%s has been yanked", resolvedVersion)
+		if ver.YankReason != "" {
+			w += ": " + ver.YankReason
+		}
+		fmt.Printf("  warning: %s\n", w)
+	}
+
+	// Without an explicit version, only move forward
+	if versionSpec == "" && !registry.IsNewer(resolvedVersion, version) {
+		fmt.Printf("  wt %s is up to date.\n", version)
+		return nil
+	}
+	if check {
+		fmt.Printf("  wt v%s is available (running %s). Run `wt self update` to install it.\n", resolvedVersion, version)
+		return nil
+	}
+	if strings.TrimPrefix(version, "v") == strings.TrimPrefix(resolvedVersion, "v") {
+		fmt.Printf("  wt v%s already installed, skipping.\n", resolvedVersion)
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating the wt binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("locating the wt binary: %w", err)
+	}
+	if releaseKey == "" {
+		fmt.Println("  warning: this wt build has no release key; verifying the checksum only")
+	}
+	if err := registry.SelfUpdate(resolvedVersion, ver, exe, releaseKey); err != nil {
+		return withExit(exitRegistry, err)
+	}
+	fmt.Printf("  Updated wt %s -> v%s (%s)\n", version, resolvedVersion, exe)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// wt auth login|status|logout
+// ---------------------------------------------------------------------------
+
+func cmdAuth(args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: wt auth <login|status|logout>")
+	}
+
+	switch args[0] {
+	case "login":
+		return cmdAuthLogin()
+	case "status":
+		return cmdAuthStatus()
+	case "logout":
+		return cmdAuthLogout()
+	default:
+		return configErrorf("unknown auth subcommand %q (expected login, status, or logout)", args[0])
+	}
+}
+
+func cmdAuthLogin() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+
+	fmt.Print("Enter license key: ")
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return fmt.Errorf("no input received")
+	}
+
+	key := strings.TrimSpace(scanner.Text())
+	if key == "" {
+		return fmt.Errorf("no license key provided")
+	}
+
+	info := config.ParseLicenseKey(key)
+	if info == nil {
+		return withExit(exitLicense, errors.New("invalid license key format"))
+	}
+
+	cfg.LicenseKey = key
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	tierName := config.TierName(info.Tier)
+	if info.Org == "ind" {
+		fmt.Printf("Activated %s license (individual).\n", tierName)
+	} else {
+		fmt.Printf("Activated %s license for org %q.\n", tierName, info.Org)
+	}
+
+	return nil
+}
+
+func cmdAuthStatus() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+
+	if cfg.LicenseKey == "" {
+		fmt.Println("Tier: free (no license key)")
+		return nil
+	}
+
+	info := config.ParseLicenseKey(cfg.LicenseKey)
+	if info == nil {
+		fmt.Println("Tier: free (invalid license key)")
+		return nil
+	}
+
+	tierName := config.TierName(info.Tier)
+	fmt.Printf("Tier: %s\n", tierName)
+	if info.Org != "ind" {
+		fmt.Printf("Org:  %s\n", info.Org)
+	}
+	fmt.Printf("Key:  %s...%s\n", info.Raw[:6], info.Raw[len(info.Raw)-4:])
+
+	return nil
+}
+
+func cmdAuthLogout() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+
+	if cfg.LicenseKey == "" {
+		fmt.Println("No license key configured.")
+		return nil
+	}
+
+	cfg.LicenseKey = ""
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	fmt.Println("License key removed.")
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// wt conformance <binary> [--port <port>] [--perf ...] [--probe ...] [--sdk ...]
+// ---------------------------------------------------------------------------
+
+func cmdConformance(args []string) error {
+	if len(args) < 1 {
+		return usageError("usage: wt conformance <binary> [--port <port>] [--perf] [--perf-endpoint <path>] [--perf-p99 <duration>] [--perf-min-rps <n>] [--probe \"POST /v1/things\"] [--probe-body <body>] [--probe-header \"Name: value\"] [--sdk] [--sdk-dir <dir>]")
+	}
+
+	binaryPath := args[0]
+	port := defaultConformancePort
+	var opts conformance.Options
+	perf := &conformance.PerfOptions{}
+	probe := &conformance.Probe{Headers: map[string]string{}}
+	sdk := &conformance.SDKOptions{}
+
+	// Parse optional flags. Any --perf-* flag implies --perf, and
+	// --sdk-dir implies --sdk.
+	for i := 1; i < len(args); i++ {
+		if args[i] == "--perf" {
+			opts.Perf = perf
+			continue
+		}
+		if args[i] == "--sdk" {
+			opts.SDK = sdk
+			continue
+		}
+		if i+1 >= len(args) {
+			continue
+		}
+		val := args[i+1]
+		switch args[i] {
+		case "--port":
+			p, err := strconv.Atoi(val)
+			if err != nil {
+				return configErrorf("invalid port: %s", val)
+			}
+			port = p
+			i++
+		case "--perf-endpoint":
+			if !strings.HasPrefix(val, "/") {
+				return configErrorf("--perf-endpoint must be a path starting with /")
+			}
+			perf.Endpoint = val
+			opts.Perf = perf
+			i++
+		case "--perf-p99":
+			d, err := time.ParseDuration(val)
+			if err != nil {
+				return configErrorf("invalid --perf-p99: %w", err)
+			}
+			perf.EndpointP99 = d
+			opts.Perf = perf
+			i++
+		case "--perf-min-rps":
+			n, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return configErrorf("invalid --perf-min-rps: %s", val)
+			}
+			perf.MinRPS = n
+			opts.Perf = perf
+			i++
+		case "--probe":
+			method, path, ok := strings.Cut(val, " ")
+			if !ok || !strings.HasPrefix(path, "/") {
+				return configErrorf("--probe must look like \"POST /v1/things\"")
+			}
+			probe.Method, probe.Path = strings.ToUpper(method), path
+			opts.Probe = probe
+			i++
+		case "--probe-body":
+			probe.Body = val
+			i++
+		case "--probe-header":
+			k, v, ok := strings.Cut(val, ":")
+			if !ok {
+				return configErrorf("--probe-header must look like \"Name: value\"")
+			}
+			probe.Headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+			i++
+		case "--sdk-dir":
+			sdk.Dir = val
+			opts.SDK = sdk
+			i++
+		}
+	}
+	if opts.Probe == nil && (probe.Body != "" || len(probe.Headers) > 0) {
+		return configErrorf("--probe-body and --probe-header require --probe")
+	}
+	if opts.SDK != nil {
+		if sdk.Dir == "" {
+			sdk.Dir = sdkCheckDir(binaryPath)
+		}
+		if info, err := os.Stat(filepath.Join(sdk.Dir, "go.mod")); err != nil || info.IsDir() {
+			return configErrorf("no SDK check program at %s (expected a Go module with a go.mod); pass --sdk-dir", sdk.Dir)
+		}
+	}
+
+	// Resolve binary path
+	absPath, err := filepath.Abs(binaryPath)
+	if err != nil {
+		return fmt.Errorf("resolving binary path: %w", err)
+	}
+
+	fmt.Printf("Running conformance suite against %s on port %d...\n\n", binaryPath, port)
+
+	report, err := conformance.RunWithOptions(absPath, port, opts)
+	if err != nil {
+		return withExit(exitUnhealthy, err)
+	}
+
+	for _, r := range report.Results {
+		if r.Passed {
+			fmt.Printf("  PASS  %s\n", r.Name)
+		} else {
+			fmt.Printf("  FAIL  %s\n", r.Name)
+			fmt.Printf("        %s\n", r.Detail)
+		}
+	}
+
+	fmt.Printf("\nResults: %d passed, %d failed, %d total\n", report.Passed, report.Failed, report.Passed+report.Failed)
+
+	if report.Failed > 0 {
+		return withExit(exitScenario, fmt.Errorf("%d of %d conformance checks failed", report.Failed, report.Passed+report.Failed))
+	}
+	return nil
+}
+
+// sdkCheckDir returns where the SDK check program of the twin binary
+// lives in a checkout of this repo: twin-stripe (or twin-stripe-1.2.0)
+// maps to twin-stripe/sdkcheck.
+func sdkCheckDir(binaryPath string) string {
+	base := strings.TrimSuffix(filepath.Base(binaryPath), ".exe")
+	if name, ok := strings.CutPrefix(base, "twin-"); ok {
+		name, _, _ = strings.Cut(name, "-")
+		base = "twin-" + name
+	}
+	return filepath.Join(base, "sdkcheck")
+}
+
+// ---------------------------------------------------------------------------
+// wt diff-versions <twin> <old> <new> (--scenario <file> | --requests <file>)
+// ---------------------------------------------------------------------------
+
+// defaultDiffPort is the first port tried for the twins wt diff-versions
+// starts.
+const defaultDiffPort = 19877
+
+// diffSide is one of the two twin versions wt diff-versions compares.
+type diffSide struct {
+	label string
+	twin  manifest.Twin
+}
+
+// diffOutcome is how the two versions answered one request.
+type diffOutcome struct {
+	name        string
+	differences []string
+	skipped     string // reason the request was not compared
+}
+
+func cmdDiffVersions(manifestPath string, args []string) error {
+	const usage = "usage: wt diff-versions <twin> <old-version> <new-version> (--scenario <file> | --requests <file>) [--ignore <field,...>] [--port <port>]"
+	var positional []string
+	var scenarioPath, requestsPath string
+	ignore := append([]string(nil), versiondiff.DefaultIgnore...)
+	port := defaultDiffPort
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "--") {
+			positional = append(positional, a)
+			continue
+		}
+		name, _, _ := strings.Cut(a, "=")
+		v, err := flagValue(args, &i)
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "--scenario":
+			scenarioPath = v
+		case "--requests":
+			requestsPath = v
+		case "--ignore":
+			for _, f := range strings.Split(v, ",") {
+				if f = strings.TrimSpace(f); f != "" {
+					ignore = append(ignore, f)
+				}
+			}
+		case "--port":
+			p, err := strconv.Atoi(v)
+			if err != nil || p < 1 || p > 65535 {
+				return configErrorf("invalid port: %s", v)
+			}
+			port = p
+		default:
+			return usageError(usage)
+		}
+	}
+	if len(positional) != 3 || (scenarioPath == "") == (requestsPath == "") {
+		return usageError(usage)
+	}
+	twinName := positional[0]
+
+	// Load what to replay before installing anything.
+	var s *v2.Scenario
+	var requests []adminclient.RequestLogEntry
+	var err error
+	if scenarioPath != "" {
+		s, err = v2.LoadScenario(scenarioPath)
+	} else {
+		requests, err = versiondiff.LoadRequests(requestsPath)
+	}
+	if err != nil {
+		return err
+	}
 
-		if err := registry.Install(name, resolvedVersion, ver, binaryDir); err != nil {
-			fmt.Printf("  %-20s FAILED — %v\n", name, err)
-			failed = append(failed, name)
-			continue
+	fetch := diffRegistry()
+	sides := make([]diffSide, 2)
+	reserved := map[int]bool{}
+	for i, spec := range positional[1:] {
+		binary, label, err := diffVersionBinary(twinName, spec, fetch)
+		if err != nil {
+			return err
+		}
+		if !procmgr.PortAvailable(port) || reserved[port] {
+			if port, err = procmgr.NextFreePort(port, reserved); err != nil {
+				return err
+			}
 		}
+		reserved[port] = true
+		sides[i] = diffSide{label: label, twin: manifest.Twin{Binary: binary, Port: port, AdminPort: port}}
 	}
 
-	fmt.Println()
-	if len(failed) > 0 {
-		return fmt.Errorf("failed to install: %s", strings.Join(failed, ", "))
+	logDir, err := os.MkdirTemp("", "wt-diff-versions-")
+	if err != nil {
+		return err
 	}
-
-	// Write lock file
-	if len(lockedTwins) > 0 {
-		now := time.Now().UTC()
-		if registryFetchedAt.IsZero() {
-			registryFetchedAt = now
+	// The twins' logs are kept only when a twin or the comparison fails,
+	// for the error to point at. Registered first, this runs after the
+	// twins are stopped.
+	compared := false
+	defer func() {
+		if compared {
+			os.RemoveAll(logDir)
 		}
-		lf := &lockfile.LockFile{
-			GeneratedAt:       now,
-			RegistryFetchedAt: registryFetchedAt,
-			Twins:             lockedTwins,
+	}()
+	ac := client.New()
+	for i, side := range sides {
+		name := twinName + "-" + [2]string{"old", "new"}[i]
+		pid, err := procmgr.Start(name, side.twin, logDir, false)
+		if err != nil {
+			return err
 		}
-		manifestDir := filepath.Dir(manifestPath)
-		if manifestDir == "" || manifestDir == "." {
-			manifestDir, _ = os.Getwd()
+		defer procmgr.Stop(name, procmgr.PidEntry{PID: pid})
+		err = procmgr.WaitHealthy(pid, 30*time.Second, func() bool {
+			ok, _ := ac.Health(side.twin.AdminURL())
+			return ok
+		})
+		if err != nil {
+			return withExit(exitUnhealthy, fmt.Errorf("%s %s: %w (see %s)", twinName, side.label, err, filepath.Join(logDir, name+".log")))
 		}
-		if err := lockfile.Save(manifestDir, lf); err != nil {
-			return fmt.Errorf("writing lock file: %w", err)
+	}
+
+	var outcomes []diffOutcome
+	if s != nil {
+		fmt.Printf("Comparing %s %s -> %s with %q\n\n", twinName, sides[0].label, sides[1].label, s.Name)
+		outcomes, err = diffScenario(manifestPath, twinName, sides, s, ignore)
+	} else {
+		fmt.Printf("Comparing %s %s -> %s with %d recorded requests\n\n", twinName, sides[0].label, sides[1].label, len(requests))
+		outcomes, err = diffRequests(ac, sides, requests, ignore)
+	}
+	if err != nil {
+		return err
+	}
+	compared = true
+
+	var same, different, skipped int
+	for _, o := range outcomes {
+		switch {
+		case o.skipped != "":
+			skipped++
+			fmt.Printf("  SKIP  %s: %s\n", o.name, o.skipped)
+		case len(o.differences) > 0:
+			different++
+			fmt.Printf("  DIFF  %s\n", o.name)
+			for _, d := range o.differences {
+				fmt.Printf("        %s\n", d)
+			}
+		default:
+			same++
+			fmt.Printf("  SAME  %s\n", o.name)
 		}
-		fmt.Printf("Wrote %s\n", lockfile.Filename)
 	}
+	fmt.Printf("\nResults: %d same, %d different, %d skipped\n", same, different, skipped)
 
-	fmt.Println("All twins installed.")
+	if different > 0 {
+		return withExit(exitScenario, fmt.Errorf("%d of %d responses differ between %s and %s", different, same+different, sides[0].label, sides[1].label))
+	}
 	return nil
 }
 
-// parseInstallSpec parses "twin@version" into (twin, version).
-// If no @ is present, returns (spec, "").
-func parseInstallSpec(spec string) (string, string) {
-	if i := strings.LastIndex(spec, "@"); i >= 0 {
-		return spec[:i], spec[i+1:]
+// diffRegistry returns a function that fetches the public registry once,
+// on first use, so comparing two local binaries needs no network.
+func diffRegistry() func() (*registry.Registry, error) {
+	var reg *registry.Registry
+	var err error
+	return func() (*registry.Registry, error) {
+		if reg == nil && err == nil {
+			cfg, _ := config.Load()
+			regEntry := cfg.Registries["public"]
+			if u := os.Getenv("WT_REGISTRY_URL"); u != "" {
+				regEntry.URL = u
+			}
+			fmt.Println("Fetching twin registry...")
+			reg, err = registry.FetchRegistry(regEntry.URL, regEntry.Token)
+		}
+		return reg, err
 	}
-	return spec, ""
 }
 
-// ---------------------------------------------------------------------------
-// wt registry add|remove|list
-// ---------------------------------------------------------------------------
+// diffVersionBinary returns the binary for one side of wt diff-versions and
+// the label to report it by. spec is a path to a local binary or a version
+// to install from the registry; registry versions are kept side by side
+// under ~/.wondertwin/versions so they do not replace the pinned binary.
+func diffVersionBinary(twinName, spec string, fetch func() (*registry.Registry, error)) (string, string, error) {
+	if strings.ContainsRune(spec, filepath.Separator) {
+		if _, err := os.Stat(spec); err != nil {
+			return "", "", fmt.Errorf("binary not found: %s", spec)
+		}
+		return spec, spec, nil
+	}
 
-func cmdRegistry(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("usage: wt registry <add|remove|list>")
+	reg, err := fetch()
+	if err != nil {
+		return "", "", withExit(exitRegistry, err)
+	}
+	resolvedVersion, ver, err := reg.ResolveVersion(twinName, spec)
+	if err != nil {
+		return "", "", withExit(exitRegistry, err)
+	}
+	cfg, _ := config.Load()
+	if err := registry.CheckTierAccess(twinName, resolvedVersion, ver, cfg); err != nil {
+		return "", "", withExit(exitLicense, err)
+	}
+	if err := registry.CheckWTVersion(twinName, resolvedVersion, ver, version); err != nil {
+		return "", "", err
 	}
 
-	switch args[0] {
-	case "add":
-		return cmdRegistryAdd(args[1:])
-	case "remove":
-		return cmdRegistryRemove(args[1:])
-	case "list":
-		return cmdRegistryList()
-	default:
-		return fmt.Errorf("unknown registry subcommand %q (expected add, remove, or list)", args[0])
+	dir := registry.ExpandPath(filepath.Join("~/.wondertwin/versions", twinName, resolvedVersion))
+	if !registry.IsAlreadyInstalled(twinName, resolvedVersion, dir) {
+		if err := registry.Install(twinName, resolvedVersion, ver, dir); err != nil {
+			return "", "", withExit(exitRegistry, err)
+		}
 	}
+	return filepath.Join(dir, "twin-"+twinName), "v" + resolvedVersion, nil
 }
 
-func cmdRegistryAdd(args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("usage: wt registry add <name> <url> [--token <token>]")
+// diffScenario runs s against each side in turn and pairs up the responses
+// step by step. Other twins the scenario uses come from the project
+// manifest, if there is one, and are shared by both runs.
+func diffScenario(manifestPath, twinName string, sides []diffSide, s *v2.Scenario, ignore []string) ([]diffOutcome, error) {
+	base := &manifest.Manifest{}
+	if _, err := os.Stat(manifestPath); err == nil {
+		if base, err = loadManifest(manifestPath); err != nil {
+			return nil, err
+		}
 	}
 
-	name := args[0]
-	url := args[1]
-
-	if name == "public" {
-		return fmt.Errorf("cannot override the built-in public registry")
+	results := make([]*v2.Result, len(sides))
+	for i, side := range sides {
+		m := *base
+		m.Twins = map[string]manifest.Twin{}
+		for name, t := range base.Twins {
+			m.Twins[name] = t
+		}
+		m.Twins[twinName] = side.twin
+		res, err := v2.NewRunner(&m).Run(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", side.label, err)
+		}
+		results[i] = res
 	}
 
-	var token string
-	for i := 2; i < len(args); i++ {
-		if args[i] == "--token" && i+1 < len(args) {
-			token = args[i+1]
-			i++
+	var outcomes []diffOutcome
+	for i := range results[0].Steps {
+		before, after := results[0].Steps[i], results[1].Steps[i]
+		o := diffOutcome{name: before.Name}
+		switch {
+		case before.StatusCode == 0 && after.StatusCode == 0 && before.Body == nil && after.Body == nil:
+			if before.Error == "" && after.Error == "" {
+				continue // admin step
+			}
+			o.skipped = "no response from either version"
+		case before.StatusCode == 0 || after.StatusCode == 0:
+			for j, sr := range []v2.StepResult{before, after} {
+				if sr.StatusCode == 0 {
+					o.differences = append(o.differences, fmt.Sprintf("no response from %s: %s", sides[j].label, sr.Error))
+				}
+			}
+		default:
+			o.differences = versiondiff.Compare(
+				versiondiff.Response{StatusCode: before.StatusCode, Body: before.Body},
+				versiondiff.Response{StatusCode: after.StatusCode, Body: after.Body},
+				ignore)
 		}
+		outcomes = append(outcomes, o)
 	}
+	return outcomes, nil
+}
 
-	cfg, err := config.Load()
-	if err != nil {
-		return err
+// diffRequests resets both sides, then sends each recorded request to both.
+func diffRequests(ac *client.AdminClient, sides []diffSide, requests []adminclient.RequestLogEntry, ignore []string) ([]diffOutcome, error) {
+	for _, side := range sides {
+		if _, err := ac.Reset(side.twin.AdminURL()); err != nil {
+			return nil, fmt.Errorf("resetting %s: %w", side.label, err)
+		}
 	}
 
-	cfg.Registries[name] = config.RegistryEntry{
-		URL:   url,
-		Token: token,
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	var outcomes []diffOutcome
+	for _, e := range requests {
+		o := diffOutcome{name: e.Method + " " + e.Path}
+		var responses [2]versiondiff.Response
+		for i, side := range sides {
+			resp, err := versiondiff.Replay(httpClient, side.twin.BaseURL(), e)
+			if err != nil {
+				o.skipped = fmt.Sprintf("%s: %v", side.label, err)
+				break
+			}
+			responses[i] = resp
+		}
+		if o.skipped == "" {
+			o.differences = versiondiff.Compare(responses[0], responses[1], ignore)
+		}
+		outcomes = append(outcomes, o)
 	}
+	return outcomes, nil
+}
 
-	if err := config.Save(cfg); err != nil {
-		return fmt.Errorf("saving config: %w", err)
-	}
+// ---------------------------------------------------------------------------
+// wt ci
+// ---------------------------------------------------------------------------
 
-	fmt.Printf("Registry %q added (%s)\n", name, url)
-	if token != "" {
-		fmt.Println("  Token: configured")
+func cmdCI(manifestPath string) error {
+	manifestDir := filepath.Dir(manifestPath)
+	if manifestDir == "" || manifestDir == "." {
+		manifestDir, _ = os.Getwd()
 	}
-	return nil
-}
 
-func cmdRegistryRemove(args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("usage: wt registry remove <name>")
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		return withExit(exitConfig, err)
 	}
 
-	name := args[0]
-	if name == "public" {
-		return fmt.Errorf("cannot remove the built-in public registry")
+	if !lockfile.Exists(manifestDir) {
+		return configErrorf("No %s found. Run 'wt install' first.", lockfile.Filename)
 	}
 
-	cfg, err := config.Load()
+	lf, err := lockfile.Load(manifestDir)
 	if err != nil {
-		return err
+		return fmt.Errorf("reading lock file: %w", err)
 	}
 
-	if _, ok := cfg.Registries[name]; !ok {
-		return fmt.Errorf("registry %q not found", name)
-	}
+	binaryDir := registry.ExpandPath(m.Settings.BinaryDir)
 
-	delete(cfg.Registries, name)
+	fmt.Println("Installing from lock file (frozen)...")
+	fmt.Println()
 
-	if err := config.Save(cfg); err != nil {
-		return fmt.Errorf("saving config: %w", err)
+	var failed []string
+	for name, locked := range lf.Twins {
+		if registry.IsAlreadyInstalled(name, locked.Version, binaryDir) {
+			fmt.Printf("  %-20s v%s already installed, skipping.\n", name, locked.Version)
+			continue
+		}
+
+		if locked.BinaryURL == "" {
+			fmt.Printf("  %-20s FAILED — no binary_url in lock file\n", name)
+			failed = append(failed, name)
+			continue
+		}
+
+		fmt.Printf("  Downloading twin-%s v%s...\n", name, locked.Version)
+		if err := registry.InstallFromURL(name, locked.Version, locked.BinaryURL, locked.Checksum, binaryDir); err != nil {
+			fmt.Printf("  %-20s FAILED — %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
 	}
 
-	fmt.Printf("Registry %q removed.\n", name)
+	fmt.Println()
+	if len(failed) > 0 {
+		return withExit(exitRegistry, fmt.Errorf("failed to install: %s", strings.Join(failed, ", ")))
+	}
+	fmt.Println("All twins installed from lock file.")
 	return nil
 }
 
-func cmdRegistryList() error {
-	cfg, err := config.Load()
+// installFromLockFile installs twins using versions from the lock file.
+func installFromLockFile(manifestDir string, m *manifest.Manifest) error {
+	lf, err := lockfile.Load(manifestDir)
 	if err != nil {
-		return err
+		return withExit(exitConfig, err)
 	}
 
-	fmt.Println()
-	fmt.Printf("  %-20s %-60s %s\n", "NAME", "URL", "AUTH")
-	fmt.Printf("  %-20s %-60s %s\n", "----", "---", "----")
+	binaryDir := registry.ExpandPath(m.Settings.BinaryDir)
 
-	for name, entry := range cfg.Registries {
-		auth := "-"
-		if entry.Token != "" {
-			auth = "token"
+	for name, locked := range lf.Twins {
+		if registry.IsAlreadyInstalled(name, locked.Version, binaryDir) {
+			fmt.Printf("  %-20s v%s (locked), already installed.\n", name, locked.Version)
+			continue
+		}
+
+		if locked.BinaryURL == "" {
+			return fmt.Errorf("twin %s: no binary_url in lock file", name)
+		}
+
+		fmt.Printf("  Downloading twin-%s v%s (locked)...\n", name, locked.Version)
+		if err := registry.InstallFromURL(name, locked.Version, locked.BinaryURL, locked.Checksum, binaryDir); err != nil {
+			return withExit(exitRegistry, fmt.Errorf("twin %s: %w", name, err))
 		}
-		fmt.Printf("  %-20s %-60s %s\n", name, entry.URL, auth)
 	}
 
-	fmt.Println()
 	return nil
 }
 
 // ---------------------------------------------------------------------------
-// wt auth login|status|logout
+// wt k8s generate
 // ---------------------------------------------------------------------------
 
-func cmdAuth(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("usage: wt auth <login|status|logout>")
+func cmdK8s(manifestPath string, args []string) error {
+	const usage = "usage: wt k8s generate [--namespace <ns>] [--image <template>] [-o <file>]"
+	if len(args) == 0 || args[0] != "generate" {
+		return usageError(usage)
+	}
+
+	var opts k8s.Options
+	output := ""
+	for i := 1; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--namespace" || a == "-n" || strings.HasPrefix(a, "--namespace="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			opts.Namespace = v
+		case a == "--image" || strings.HasPrefix(a, "--image="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			opts.Image = v
+		case a == "-o" || a == "--output" || strings.HasPrefix(a, "--output="):
+			v, err := flagValue(args, &i)
+			if err != nil {
+				return err
+			}
+			output = v
+		default:
+			return usageError(usage)
+		}
+	}
+
+	// Ports come from the manifest as written; --auto-port moves are local
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		return withExit(exitConfig, err)
+	}
+	data, err := k8s.Generate(m, opts)
+	if err != nil {
+		return err
+	}
+
+	if output == "" || output == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0o644); err != nil {
+		return err
 	}
+	fmt.Printf("Wrote Kubernetes resources for %d twin(s) to %s (apply with: kubectl apply -f %s)\n", len(m.Twins), output, output)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// wt completion bash|zsh|fish
+// ---------------------------------------------------------------------------
 
+// The completion scripts are thin shims: they pass the words typed so far to
+// the hidden "wt __complete" command, which prints candidates one per line.
+// No output means "complete file paths", which each shim falls back to.
+
+const bashCompletion = `# bash completion for wt. Load with: source <(wt completion bash)
+_wt() {
+  local IFS=$'\n'
+  COMPREPLY=($(wt __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+  if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == */ ]]; then
+    compopt -o nospace
+  fi
+}
+complete -o default -F _wt wt
+`
+
+const zshCompletion = `#compdef wt
+# zsh completion for wt. Load with: source <(wt completion zsh)
+_wt() {
+  local -a candidates
+  candidates=("${(@f)$(wt __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+  candidates=(${candidates:#})
+  if (( ${#candidates} )); then
+    compadd -S '' -- ${(M)candidates:#*/}
+    compadd -- ${candidates:#*/}
+  else
+    _files
+  fi
+}
+compdef _wt wt
+`
+
+const fishCompletion = `# fish completion for wt. Load with: wt completion fish | source
+function __wt_complete
+    set -l candidates (wt __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)
+    if test (count $candidates) -gt 0
+        printf '%s\n' $candidates
+    else
+        __fish_complete_path (commandline -ct)
+    end
+end
+complete -c wt -f -a '(__wt_complete)'
+`
+
+func cmdCompletion(args []string) error {
+	if len(args) != 1 {
+		return usageError("usage: wt completion bash|zsh|fish")
+	}
 	switch args[0] {
-	case "login":
-		return cmdAuthLogin()
-	case "status":
-		return cmdAuthStatus()
-	case "logout":
-		return cmdAuthLogout()
+	case "bash":
+		os.Stdout.WriteString(bashCompletion)
+	case "zsh":
+		os.Stdout.WriteString(zshCompletion)
+	case "fish":
+		os.Stdout.WriteString(fishCompletion)
 	default:
-		return fmt.Errorf("unknown auth subcommand %q (expected login, status, or logout)", args[0])
+		return configErrorf("unsupported shell %q (want bash, zsh, or fish)", args[0])
 	}
+	return nil
 }
 
-func cmdAuthLogin() error {
-	cfg, err := config.Load()
-	if err != nil {
-		return err
+// completionCommands are the top-level commands offered for completion.
+var completionCommands = []string{
+	"up", "down", "apply", "status", "ps", "prune", "reset", "seed", "logs", "inspect", "replay",
+	"intercept", "webhooks", "fixtures", "env", "audit", "shell", "mcp", "test", "chaos", "report", "graph", "bench", "install", "ci", "auth", "registry", "self", "conformance", "diff-versions", "k8s", "completion", "version",
+}
+
+// completionFlags lists each command's flags.
+var completionFlags = map[string][]string{
+	"up":            {"--auto-port", "--write-back", "--wait-timeout"},
+	"apply":         {"--dry-run", "--wait-timeout"},
+	"status":        {"--verbose", "--watch", "--exit-on-unhealthy"},
+	"ps":            {"--all"},
+	"prune":         {"--dry-run", "--older-than"},
+	"reset":         {"--only", "--seed"},
+	"seed":          {"--dry-run"},
+	"logs":          {"--grep", "--level", "--since", "--json", "--follow"},
+	"inspect":       {"--json"},
+	"intercept":     {"--path", "--method", "--timeout"},
+	"webhooks":      {"--json", "--override", "--overrides"},
+	"fixtures":      {"--salt"},
+	"env":           {"-o", "--output"},
+	"audit":         {"-o", "--output", "--key"},
+	"test":          {"--pack", "--parallel", "--coverage", "--generate-negative", "--out", "--openapi"},
+	"chaos":         {"--keep"},
+	"report":        {"--format", "-o"},
+	"graph":         {"--format", "-o"},
+	"bench":         {"--scenario", "--rps", "--duration", "--concurrency", "--max-p99", "--max-error-rate"},
+	"install":       {"--verify-conformance"},
+	"registry":      {"--token"},
+	"self":          {"--check"},
+	"k8s":           {"--namespace", "--image", "--output"},
+	"diff-versions": {"--scenario", "--requests", "--ignore", "--port"},
+	"conformance":   {"--port", "--perf", "--perf-endpoint", "--perf-p99", "--perf-min-rps", "--probe", "--probe-body", "--probe-header", "--sdk", "--sdk-dir"},
+}
+
+// completionValueFlags are flags that consume the following word.
+var completionValueFlags = map[string]bool{
+	"--config": true, "--wait-timeout": true, "--only": true, "--seed": true, "--grep": true, "--level": true,
+	"--since": true, "--pack": true, "--parallel": true, "--generate-negative": true, "--out": true,
+	"--openapi": true, "--scenario": true, "--rps": true, "--duration": true, "--concurrency": true,
+	"--max-p99": true, "--max-error-rate": true, "--token": true, "--port": true, "--perf-endpoint": true,
+	"--perf-p99": true, "--perf-min-rps": true, "--probe": true, "--probe-body": true,
+	"--probe-header": true, "--namespace": true, "--image": true, "--output": true, "-o": true, "-n": true,
+	"--requests": true, "--ignore": true, "--override": true, "--overrides": true, "--older-than": true,
+	"--format": true, "--salt": true, "--sdk-dir": true, "--key": true,
+	"--path": true, "--method": true, "--timeout": true,
+}
+
+// cmdComplete implements the hidden "wt __complete <words...>" command used
+// by the completion scripts. The last word is the one being completed. It
+// reads os.Args itself because parseArgs would swallow a trailing --config.
+func cmdComplete(words []string) {
+	if len(words) == 0 {
+		words = []string{""}
 	}
+	cur, prior := words[len(words)-1], words[:len(words)-1]
 
-	fmt.Print("Enter license key: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	if !scanner.Scan() {
-		return fmt.Errorf("no input received")
+	manifestPath := defaultManifest
+	if p := os.Getenv("WT_CONFIG"); p != "" {
+		manifestPath = p
+	}
+	var cmd, prev string
+	var positional []string
+	for i := 0; i < len(prior); i++ {
+		w := prior[i]
+		if w == "--config" && i+1 < len(prior) {
+			manifestPath = prior[i+1]
+			i++
+			continue
+		}
+		prev = w
+		switch {
+		case cmd == "" && !strings.HasPrefix(w, "-"):
+			cmd = w
+		case completionValueFlags[w] && i+1 < len(prior):
+			i++
+			prev = prior[i]
+		case cmd != "" && !strings.HasPrefix(w, "-"):
+			positional = append(positional, w)
+		}
 	}
+	manifestPath = resolveManifestPath(manifestPath)
 
-	key := strings.TrimSpace(scanner.Text())
-	if key == "" {
-		return fmt.Errorf("no license key provided")
+	var candidates []string
+	switch {
+	case completionValueFlags[prev] && len(prior) > 0 && prior[len(prior)-1] == prev:
+		candidates = completeFlagValue(prev, cur, manifestPath)
+	case strings.HasPrefix(cur, "-"):
+		candidates = completionFlags[cmd]
+		if cmd == "" {
+			candidates = []string{"--config", "--help", "--version"}
+		}
+	case cmd == "":
+		candidates = completionCommands
+	default:
+		candidates = completeArg(cmd, len(positional), positional, cur, manifestPath)
 	}
 
-	info := config.ParseLicenseKey(key)
-	if info == nil {
-		return fmt.Errorf("invalid license key format")
+	for _, c := range candidates {
+		if strings.HasPrefix(c, cur) {
+			fmt.Println(c)
+		}
 	}
+}
 
-	cfg.LicenseKey = key
-	if err := config.Save(cfg); err != nil {
-		return fmt.Errorf("saving config: %w", err)
+// completeArg returns candidates for the n-th (0-based) positional argument
+// of cmd.
+func completeArg(cmd string, n int, positional []string, cur, manifestPath string) []string {
+	switch {
+	case n == 0 && (cmd == "reset" || cmd == "seed" || cmd == "logs" || cmd == "inspect" || cmd == "replay" || cmd == "intercept" || cmd == "install" || cmd == "shell" || cmd == "bench" || cmd == "diff-versions"):
+		return completionTwinNames(manifestPath)
+	case n == 1 && cmd == "inspect":
+		return []string{"state", "requests", "faults", "time", "webhooks", "events", "config", "quirks"}
+	case n == 0 && cmd == "test":
+		return completeScenarioPaths(cur)
+	case n == 0 && cmd == "auth":
+		return []string{"login", "status", "logout"}
+	case n == 0 && cmd == "k8s":
+		return []string{"generate"}
+	case n == 0 && cmd == "webhooks":
+		return []string{"catalog", "trigger"}
+	case n == 0 && cmd == "fixtures":
+		return []string{"scrub"}
+	case n == 0 && cmd == "env":
+		return []string{"export", "diff"}
+	case n == 0 && cmd == "audit":
+		return []string{"collect", "verify"}
+	case n == 0 && cmd == "chaos":
+		return []string{"replay"}
+	case n == 1 && cmd == "webhooks":
+		return completionTwinNames(manifestPath)
+	case n == 0 && cmd == "registry":
+		return []string{"add", "remove", "list"}
+	case n == 0 && cmd == "self":
+		return []string{"update"}
+	case n == 1 && cmd == "registry" && positional[0] == "remove":
+		cfg, err := config.Load()
+		if err != nil {
+			return nil
+		}
+		names := make([]string, 0, len(cfg.Registries))
+		for name := range cfg.Registries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	case n == 0 && cmd == "completion":
+		return []string{"bash", "zsh", "fish"}
 	}
+	return nil
+}
 
-	tierName := config.TierName(info.Tier)
-	if info.Org == "ind" {
-		fmt.Printf("Activated %s license (individual).\n", tierName)
-	} else {
-		fmt.Printf("Activated %s license for org %q.\n", tierName, info.Org)
+// completeFlagValue returns candidates for the value of flag.
+func completeFlagValue(flag, cur, manifestPath string) []string {
+	switch flag {
+	case "--level":
+		return []string{"debug", "info", "warn", "error"}
+	case "--generate-negative":
+		return completionTwinNames(manifestPath)
+	case "--scenario":
+		return completeScenarioPaths(cur)
+	case "--pack":
+		var prefixes []string
+		for _, name := range completionTwinNames(manifestPath) {
+			prefixes = append(prefixes, name+"/")
+		}
+		return prefixes
 	}
-
 	return nil
 }
 
-func cmdAuthStatus() error {
-	cfg, err := config.Load()
+// completionTwinNames returns the twin names in the manifest, or nothing if
+// it cannot be read.
+func completionTwinNames(manifestPath string) []string {
+	m, err := manifest.Load(manifestPath)
 	if err != nil {
-		return err
-	}
-
-	if cfg.LicenseKey == "" {
-		fmt.Println("Tier: free (no license key)")
 		return nil
 	}
-
-	info := config.ParseLicenseKey(cfg.LicenseKey)
-	if info == nil {
-		fmt.Println("Tier: free (invalid license key)")
-		return nil
+	names := make([]string, 0, len(m.Twins))
+	for name := range m.Twins {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	tierName := config.TierName(info.Tier)
-	fmt.Printf("Tier: %s\n", tierName)
-	if info.Org != "ind" {
-		fmt.Printf("Org:  %s\n", info.Org)
+// completeScenarioPaths returns the .json scenario files and directories
+// matching cur, so "wt test <TAB>" walks down to scenario files.
+func completeScenarioPaths(cur string) []string {
+	matches, _ := filepath.Glob(cur + "*")
+	var paths []string
+	for _, p := range matches {
+		info, err := os.Stat(p)
+		switch {
+		case err != nil:
+		case info.IsDir():
+			paths = append(paths, p+"/")
+		case strings.EqualFold(filepath.Ext(p), ".json"):
+			paths = append(paths, p)
+		}
 	}
-	fmt.Printf("Key:  %s...%s\n", info.Raw[:6], info.Raw[len(info.Raw)-4:])
+	return paths
+}
 
-	return nil
+// ---------------------------------------------------------------------------
+// wt shell
+// ---------------------------------------------------------------------------
+
+const shellHelp = `Commands (scoped to the selected twin):
+  twins                          List twins in the manifest
+  use <twin>                     Select a twin
+  inspect [resource] [--json]    Query state|requests|faults|time|webhooks|events|config|quirks
+  seed <file>                    POST seed data to /admin/state
+  reset [--only r,..|--seed p]   Reset state
+  fault <endpoint> <status|preset> [rate] [body]
+                                 Inject a fault (rate defaults to 1); presets are
+                                 auth_invalid, auth_expired, forbidden_scope
+  fault webhook:<type> <drop|delay> [rate]
+                                 Drop or delay webhook deliveries of an event type
+  fault rm <endpoint>            Remove a fault
+  time [advance <dur>|set <RFC3339>|freeze|unfreeze]
+                                 Show or change the simulated clock
+  header [name [value]]          List, set, or (without value) clear an exec header
+  exec <METHOD> <path> [body]    Send a request to the twin's API
+  replay <id>                    Re-send a logged request
+  history                        Show command history; !! or !<n> re-runs an entry
+  help                           Show this help
+  exit                           Leave the shell
+`
+
+// shellHistoryLimit caps the history file.
+const shellHistoryLimit = 1000
+
+// shellSession is the state of one wt shell.
+type shellSession struct {
+	manifestPath string
+	twin         string
+	headers      map[string]string
+	history      []string
 }
 
-func cmdAuthLogout() error {
-	cfg, err := config.Load()
+func cmdShell(manifestPath string, args []string) error {
+	m, err := loadManifest(manifestPath)
 	if err != nil {
 		return err
 	}
-
-	if cfg.LicenseKey == "" {
-		fmt.Println("No license key configured.")
-		return nil
+	s := &shellSession{manifestPath: manifestPath, headers: map[string]string{}}
+	if names := m.TwinNames(); len(names) == 1 {
+		s.twin = names[0]
 	}
-
-	cfg.LicenseKey = ""
-	if err := config.Save(cfg); err != nil {
-		return fmt.Errorf("saving config: %w", err)
+	if len(args) > 0 {
+		if _, err := m.Twin(args[0]); err != nil {
+			return err
+		}
+		s.twin = args[0]
 	}
 
-	fmt.Println("License key removed.")
-	return nil
-}
+	historyPath := shellHistoryPath()
+	s.history = loadShellHistory(historyPath)
 
-// ---------------------------------------------------------------------------
-// wt conformance <binary> [--port <port>]
-// ---------------------------------------------------------------------------
+	fmt.Println("wt shell — type help for commands, exit to leave")
+	in := bufio.NewScanner(os.Stdin)
+	for {
+		if s.twin != "" {
+			fmt.Printf("wt(%s)> ", s.twin)
+		} else {
+			fmt.Print("wt> ")
+		}
+		if !in.Scan() {
+			fmt.Println()
+			break
+		}
+		line := strings.TrimSpace(in.Text())
+		if line == "" {
+			continue
+		}
 
-func cmdConformance(args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("usage: wt conformance <binary> [--port <port>]")
+		line, err := s.expandHistory(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wt: %v\n", err)
+			continue
+		}
+		s.history = append(s.history, line)
+		appendShellHistory(historyPath, line)
+
+		words, err := splitShellWords(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wt: %v\n", err)
+			continue
+		}
+		if words[0] == "exit" || words[0] == "quit" {
+			break
+		}
+		if err := s.run(words); err != nil {
+			fmt.Fprintf(os.Stderr, "wt: %v\n", err)
+		}
 	}
+	return nil
+}
 
-	binaryPath := args[0]
-	port := 19876 // default conformance test port
+// expandHistory replaces !! and !<n> with the matching history entry and
+// echoes the result, as shells do.
+func (s *shellSession) expandHistory(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") {
+		return line, nil
+	}
+	if len(s.history) == 0 {
+		return "", fmt.Errorf("history is empty")
+	}
+	if line == "!!" {
+		line = s.history[len(s.history)-1]
+	} else {
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 1 || n > len(s.history) {
+			return "", fmt.Errorf("no history entry %s", line)
+		}
+		line = s.history[n-1]
+	}
+	fmt.Println(line)
+	return line, nil
+}
 
-	// Parse optional --port flag
-	for i := 1; i < len(args); i++ {
-		if args[i] == "--port" && i+1 < len(args) {
-			p, err := strconv.Atoi(args[i+1])
-			if err != nil {
-				return fmt.Errorf("invalid port: %s", args[i+1])
-			}
-			port = p
-			i++
+func (s *shellSession) run(words []string) error {
+	cmd, args := words[0], words[1:]
+	switch cmd {
+	case "help":
+		fmt.Print(shellHelp)
+		return nil
+	case "history":
+		for i, line := range s.history {
+			fmt.Printf("%5d  %s\n", i+1, line)
+		}
+		return nil
+	case "twins":
+		return s.listTwins()
+	case "use":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: use <twin>")
 		}
+		m, err := loadManifest(s.manifestPath)
+		if err != nil {
+			return err
+		}
+		if _, err := m.Twin(args[0]); err != nil {
+			return err
+		}
+		s.twin = args[0]
+		return nil
+	case "header":
+		return s.header(args)
 	}
 
-	// Resolve binary path
-	absPath, err := filepath.Abs(binaryPath)
+	if s.twin == "" {
+		return fmt.Errorf("no twin selected (use <twin>)")
+	}
+	m, err := loadManifest(s.manifestPath)
 	if err != nil {
-		return fmt.Errorf("resolving binary path: %w", err)
+		return err
+	}
+	twin, err := m.Twin(s.twin)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Running conformance suite against %s on port %d...\n\n", binaryPath, port)
+	switch cmd {
+	case "inspect":
+		return cmdInspect(s.manifestPath, append([]string{s.twin}, args...))
+	case "seed":
+		return cmdSeed(s.manifestPath, append([]string{s.twin}, args...))
+	case "reset":
+		return cmdReset(s.manifestPath, append([]string{s.twin}, args...))
+	case "replay":
+		return cmdReplay(s.manifestPath, append([]string{s.twin}, args...))
+	case "fault":
+		return shellFault(twin, args)
+	case "time":
+		return shellTime(twin, args)
+	case "exec":
+		return shellExec(twin, s.headers, args)
+	}
+	return fmt.Errorf("unknown command %q (type help)", cmd)
+}
 
-	report, err := conformance.Run(absPath, port)
+func (s *shellSession) listTwins() error {
+	m, err := loadManifest(s.manifestPath)
 	if err != nil {
 		return err
 	}
-
-	for _, r := range report.Results {
-		if r.Passed {
-			fmt.Printf("  PASS  %s\n", r.Name)
-		} else {
-			fmt.Printf("  FAIL  %s\n", r.Name)
-			fmt.Printf("        %s\n", r.Detail)
+	pids, _ := procmgr.LoadPids(s.manifestPath)
+	for _, name := range m.TwinNames() {
+		twin := m.Twins[name]
+		status := "stopped"
+		if twin.Remote() {
+			status = "remote"
+		} else if entry, ok := pids[name]; ok && procmgr.IsRunning(entry.PID) {
+			status = "running"
+		}
+		marker := " "
+		if name == s.twin {
+			marker = "*"
 		}
+		fmt.Printf("%s %-20s %-28s %s\n", marker, name, twin.BaseURL(), status)
 	}
+	return nil
+}
 
-	fmt.Printf("\nResults: %d passed, %d failed, %d total\n", report.Passed, report.Failed, report.Passed+report.Failed)
-
-	if report.Failed > 0 {
-		os.Exit(1)
+func (s *shellSession) header(args []string) error {
+	switch len(args) {
+	case 0:
+		names := make([]string, 0, len(s.headers))
+		for name := range s.headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s: %s\n", name, s.headers[name])
+		}
+	case 1:
+		delete(s.headers, args[0])
+	case 2:
+		s.headers[args[0]] = args[1]
+	default:
+		return fmt.Errorf("usage: header [name [value]] (quote values with spaces)")
 	}
 	return nil
 }
 
-// ---------------------------------------------------------------------------
-// wt ci
-// ---------------------------------------------------------------------------
-
-func cmdCI(manifestPath string) error {
-	manifestDir := filepath.Dir(manifestPath)
-	if manifestDir == "" || manifestDir == "." {
-		manifestDir, _ = os.Getwd()
+func shellFault(twin manifest.Twin, args []string) error {
+	ac := client.New()
+	if len(args) == 2 && args[0] == "rm" {
+		return ac.RemoveFault(twin.AdminURL(), args[1])
+	}
+	if len(args) < 2 || len(args) > 4 {
+		return fmt.Errorf("usage: fault <endpoint> <status|preset> [rate] [body] | fault webhook:<type> <drop|delay> [rate] | fault rm <endpoint>")
+	}
+	fault := client.Fault{Rate: 1}
+	status, err := strconv.Atoi(args[1])
+	switch {
+	case strings.HasPrefix(args[0], "webhook:"):
+		// Webhook faults drop deliveries, or delay them by a duration
+		if args[1] != "drop" {
+			if fault.Delay, err = time.ParseDuration(args[1]); err != nil || fault.Delay <= 0 {
+				return fmt.Errorf("invalid webhook fault %q (want drop or a delay like 30s)", args[1])
+			}
+		}
+	case err == nil:
+		fault.StatusCode = status
+	default:
+		// The twin rejects presets it doesn't know
+		fault.Preset = args[1]
+	}
+	if len(args) > 2 {
+		if fault.Rate, err = strconv.ParseFloat(args[2], 64); err != nil || fault.Rate < 0 || fault.Rate > 1 {
+			return fmt.Errorf("invalid rate %q (want 0.0-1.0)", args[2])
+		}
 	}
+	if len(args) > 3 {
+		fault.Body = args[3]
+	}
+	return ac.InjectFault(twin.AdminURL(), args[0], fault)
+}
 
-	m, err := manifest.Load(manifestPath)
+func shellTime(twin manifest.Twin, args []string) error {
+	ac := client.New()
+	var raw string
+	var err error
+	switch {
+	case len(args) == 0:
+		raw, err = ac.InspectTime(twin.AdminURL())
+	case args[0] == "advance" && len(args) == 2:
+		d, perr := time.ParseDuration(args[1])
+		if perr != nil {
+			return fmt.Errorf("invalid duration %q", args[1])
+		}
+		raw, err = ac.AdvanceTime(twin.AdminURL(), d)
+	case args[0] == "set" && len(args) == 2:
+		t, perr := time.Parse(time.RFC3339, args[1])
+		if perr != nil {
+			return fmt.Errorf("invalid time %q (want RFC 3339)", args[1])
+		}
+		raw, err = ac.SetTime(twin.AdminURL(), t)
+	case args[0] == "freeze" && len(args) == 1:
+		raw, err = ac.FreezeTime(twin.AdminURL())
+	case args[0] == "unfreeze" && len(args) == 1:
+		raw, err = ac.UnfreezeTime(twin.AdminURL())
+	default:
+		return fmt.Errorf("usage: time [advance <duration>|set <RFC3339>|freeze|unfreeze]")
+	}
 	if err != nil {
 		return err
 	}
-
-	if !lockfile.Exists(manifestDir) {
-		return fmt.Errorf("No %s found. Run 'wt install' first.", lockfile.Filename)
+	if pretty, err := prettyJSON(raw); err == nil {
+		raw = pretty
 	}
+	fmt.Println(raw)
+	return nil
+}
 
-	lf, err := lockfile.Load(manifestDir)
+// shellExec sends a request to the twin's API port and prints the status
+// line and the (pretty-printed when JSON) body.
+func shellExec(twin manifest.Twin, headers map[string]string, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("usage: exec <METHOD> <path> [body]")
+	}
+	path := args[1]
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	var body io.Reader
+	if len(args) == 3 {
+		body = strings.NewReader(args[2])
+	}
+	req, err := http.NewRequest(strings.ToUpper(args[0]), twin.BaseURL()+path, body)
 	if err != nil {
-		return fmt.Errorf("reading lock file: %w", err)
+		return err
 	}
-
-	binaryDir := registry.ExpandPath(m.Settings.BinaryDir)
-
-	fmt.Println("Installing from lock file (frozen)...")
-	fmt.Println()
-
-	var failed []string
-	for name, locked := range lf.Twins {
-		if registry.IsAlreadyInstalled(name, locked.Version, binaryDir) {
-			fmt.Printf("  %-20s v%s already installed, skipping.\n", name, locked.Version)
-			continue
-		}
-
-		if locked.BinaryURL == "" {
-			fmt.Printf("  %-20s FAILED — no binary_url in lock file\n", name)
-			failed = append(failed, name)
-			continue
-		}
-
-		fmt.Printf("  Downloading twin-%s v%s...\n", name, locked.Version)
-		if err := registry.InstallFromURL(name, locked.Version, locked.BinaryURL, locked.Checksum, binaryDir); err != nil {
-			fmt.Printf("  %-20s FAILED — %v\n", name, err)
-			failed = append(failed, name)
-			continue
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+		if trimmed := strings.TrimSpace(args[2]); trimmed != "" && trimmed[0] != '{' && trimmed[0] != '[' {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		}
 	}
-
-	fmt.Println()
-	if len(failed) > 0 {
-		return fmt.Errorf("failed to install: %s", strings.Join(failed, ", "))
+	for name, value := range headers {
+		req.Header.Set(name, value)
 	}
-	fmt.Println("All twins installed from lock file.")
-	return nil
-}
 
-// installFromLockFile installs twins using versions from the lock file.
-func installFromLockFile(manifestDir string, m *manifest.Manifest) error {
-	lf, err := lockfile.Load(manifestDir)
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 
-	binaryDir := registry.ExpandPath(m.Settings.BinaryDir)
+	fmt.Println(resp.Status)
+	if pretty, err := prettyJSON(string(data)); err == nil {
+		fmt.Println(pretty)
+	} else if len(data) > 0 {
+		fmt.Println(string(data))
+	}
+	return nil
+}
 
-	for name, locked := range lf.Twins {
-		if registry.IsAlreadyInstalled(name, locked.Version, binaryDir) {
-			fmt.Printf("  %-20s v%s (locked), already installed.\n", name, locked.Version)
-			continue
+// splitShellWords splits a line into words, honoring single and double
+// quotes and backslash escapes so JSON bodies can be passed as one word.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
 		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
 
-		if locked.BinaryURL == "" {
-			return fmt.Errorf("twin %s: no binary_url in lock file", name)
-		}
+// shellHistoryPath returns ~/.wondertwin/shell_history, or "" when the home
+// directory is unknown (history is then kept in memory only).
+func shellHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, config.DefaultConfigDir, "shell_history")
+}
 
-		fmt.Printf("  Downloading twin-%s v%s (locked)...\n", name, locked.Version)
-		if err := registry.InstallFromURL(name, locked.Version, locked.BinaryURL, locked.Checksum, binaryDir); err != nil {
-			return fmt.Errorf("twin %s: %w", name, err)
-		}
+func loadShellHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
 	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	if len(lines) > shellHistoryLimit {
+		lines = lines[len(lines)-shellHistoryLimit:]
+	}
+	return lines
+}
 
-	return nil
+func appendShellHistory(path, line string) {
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
 }
@@ -0,0 +1,55 @@
+// Package TEMPLATEtwin wires twin-TEMPLATE's store, API, and admin plane
+// onto a twincore.Twin, for the twin-TEMPLATE binary and for hosts such as
+// twin-multi that run several twins in one process.
+package TEMPLATEtwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-TEMPLATE/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-TEMPLATE/internal/store"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Description is what twin-TEMPLATE reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-TEMPLATE",
+	SDKTarget:    twincore.SDKTarget{Package: "github.com/your-org/your-sdk", Version: "v1"},
+	DefaultPort:  4200, // Choose a unique port for your twin
+	Capabilities: []string{"clock", "quirks"},
+}
+
+// Build mounts twin-TEMPLATE on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, twin.Middleware())
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	}
+
+	twin.Logger.Info("twin-TEMPLATE ready",
+		"addr", cfg.Addr(),
+	)
+	return nil
+}
@@ -13,7 +13,9 @@ type MemoryStore struct {
 	Clock     *pkgstore.Clock
 }
 
-// New creates a new MemoryStore with empty state.
+// New creates a new MemoryStore with empty state. High-volume resources
+// (events, logs) can expire on the simulated clock so long-lived twins stay
+// bounded: s.Resources.SetTTL(24*time.Hour, s.Clock).
 func New() *MemoryStore {
 	return &MemoryStore{
 		Resources: pkgstore.New[Resource]("res"), // Prefix for generated IDs (e.g., "res_abc123")
@@ -21,23 +23,33 @@ func New() *MemoryStore {
 	}
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 // stateSnapshot is the JSON-serializable state for admin endpoints.
 // Include every resource collection so snapshots are complete.
 type stateSnapshot struct {
-	Resources map[string]Resource `json:"resources"`
+	SchemaVersion int                 `json:"schema_version"`
+	Resources     map[string]Resource `json:"resources"`
 }
 
 // Snapshot returns the full state as a JSON-serializable value.
 // Used by the admin /state endpoint.
 func (s *MemoryStore) Snapshot() any {
 	return stateSnapshot{
-		Resources: s.Resources.Snapshot(),
+		SchemaVersion: stateSchema.Current(),
+		Resources:     s.Resources.Snapshot(),
 	}
 }
 
 // LoadState replaces the full state from a JSON body.
 // Used by admin /state/load and seed data loading.
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap stateSnapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return err
@@ -2,4 +2,9 @@ module github.com/wondertwin-ai/wondertwin
 
 go 1.25.7
 
-require gopkg.in/yaml.v3 v3.0.1
+replace github.com/wondertwin-ai/wondertwin/adminclient => ./adminclient
+
+require (
+	github.com/wondertwin-ai/wondertwin/adminclient v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,192 @@
+// Package audit gathers the audit logs of a manifest's twins into one
+// artifact for `wt audit collect`, and verifies them for `wt audit verify`.
+// Each twin keeps a hash chain of its admin mutations and the scenario runs
+// wt test reports; verification recomputes every link, so an entry edited,
+// inserted, or removed after export shows up as a broken chain.
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// Format is the artifact format version, bumped when Artifact changes
+// incompatibly.
+const Format = 1
+
+// zeroHash is PrevHash of the first entry a twin ever records.
+var zeroHash = strings.Repeat("0", 64)
+
+// Artifact is the audit logs of every twin of a manifest, collected
+// together.
+type Artifact struct {
+	Format      int                                 `json:"format"`
+	WTVersion   string                              `json:"wt_version"`
+	CollectedAt time.Time                           `json:"collected_at"`
+	Twins       map[string]*adminclient.AuditExport `json:"twins"`
+	Errors      map[string]string                   `json:"errors,omitempty"` // twins whose log could not be read
+	// Digest is the hex SHA-256 of one "<twin> <head>\n" line per twin,
+	// sorted by name, tying the twins' chains together.
+	Digest string `json:"digest"`
+}
+
+// Collect reads the audit log of every twin m declares. Twins that cannot
+// be read, because they are not running or predate audit logs, are listed
+// in Errors.
+func Collect(ctx context.Context, m *manifest.Manifest, wtVersion string) *Artifact {
+	a := &Artifact{
+		Format:      Format,
+		WTVersion:   wtVersion,
+		CollectedAt: time.Now().UTC(),
+		Twins:       map[string]*adminclient.AuditExport{},
+	}
+	for _, name := range m.TwinNames() {
+		cctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		exp, err := adminclient.New(m.Twins[name].AdminURL()).AuditExport(cctx)
+		cancel()
+		if err != nil {
+			if a.Errors == nil {
+				a.Errors = map[string]string{}
+			}
+			a.Errors[name] = err.Error()
+			continue
+		}
+		a.Twins[name] = exp
+	}
+	a.Digest = a.digest()
+	return a
+}
+
+func (a *Artifact) digest() string {
+	names := make([]string, 0, len(a.Twins))
+	for name := range a.Twins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s %s\n", name, a.Twins[name].Head)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify checks every twin's chain, and its signature when key is not
+// empty, and the artifact's digest. It returns one problem per line, e.g.
+// "stripe: entry 4: hash does not match its contents", or nil when
+// everything verifies.
+func (a *Artifact) Verify(key string) []string {
+	var problems []string
+	names := make([]string, 0, len(a.Twins))
+	for name := range a.Twins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := VerifyExport(a.Twins[name], key); err != nil {
+			problems = append(problems, name+": "+err.Error())
+		}
+	}
+	if a.Digest != a.digest() {
+		problems = append(problems, "digest does not match the twins' chain heads")
+	}
+	return problems
+}
+
+// VerifyExport recomputes exp's chain and checks it ends at exp.Head. With
+// a key, it also checks exp.Signature, which an unsigned export fails.
+func VerifyExport(exp *adminclient.AuditExport, key string) error {
+	prev := ""
+	if exp.Dropped == 0 {
+		prev = zeroHash
+	}
+	for i, e := range exp.Entries {
+		if i > 0 && e.Seq != exp.Entries[i-1].Seq+1 {
+			return fmt.Errorf("entry %d follows entry %d", e.Seq, exp.Entries[i-1].Seq)
+		}
+		if prev != "" && e.PrevHash != prev {
+			return fmt.Errorf("entry %d: does not chain to the entry before it", e.Seq)
+		}
+		if e.Hash != EntryHash(e) {
+			return fmt.Errorf("entry %d: hash does not match its contents", e.Seq)
+		}
+		prev = e.Hash
+	}
+	if prev != "" && exp.Head != prev {
+		return fmt.Errorf("head %s is not the last entry's hash", exp.Head)
+	}
+	if key == "" {
+		return nil
+	}
+	if exp.Signature == "" {
+		return fmt.Errorf("export is not signed")
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(exp.Head))
+	want := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(exp.Signature), []byte(want)) {
+		return fmt.Errorf("signature does not match the key")
+	}
+	return nil
+}
+
+// EntryHash computes an entry's hash the way twins do: the hex SHA-256 of
+// its fields joined with newlines, as the admin API spec describes.
+func EntryHash(e adminclient.AuditEntry) string {
+	passed := ""
+	if e.Passed != nil {
+		passed = strconv.FormatBool(*e.Passed)
+	}
+	fields := []string{
+		strconv.Itoa(e.Seq), e.Time.UTC().Format(time.RFC3339Nano), e.Kind, e.Method, e.Path,
+		strconv.Itoa(e.StatusCode), e.RequestID, e.BodySHA256, e.Scenario, passed, e.PrevHash,
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteJSON writes the artifact as indented JSON.
+func (a *Artifact) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// Load reads an artifact written by WriteJSON.
+func Load(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var a Artifact
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("parsing %s: expected the output of wt audit collect: %w", path, err)
+	}
+	if a.Format != Format {
+		return nil, fmt.Errorf("%s is artifact format %d; this wt reads format %d", path, a.Format, Format)
+	}
+	return &a, nil
+}
+
+// RecordScenario reports a scenario run to every twin of m, so it appears
+// in their audit logs. Twins that cannot record it are skipped: the audit
+// log is evidence of a run, not a condition of it.
+func RecordScenario(ctx context.Context, m *manifest.Manifest, scenario string, passed bool) {
+	for _, name := range m.TwinNames() {
+		cctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		adminclient.New(m.Twins[name].AdminURL()).RecordScenario(cctx, scenario, passed)
+		cancel()
+	}
+}
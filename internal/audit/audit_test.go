@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// chain builds a signed export the way a twin would.
+func chain(key string) *adminclient.AuditExport {
+	passed := true
+	entries := []adminclient.AuditEntry{
+		{Kind: "admin", Method: "POST", Path: "/admin/reset", StatusCode: 200, RequestID: "req-1"},
+		{Kind: "admin", Method: "POST", Path: "/admin/state", StatusCode: 200, BodySHA256: strings.Repeat("ab", 32)},
+		{Kind: "scenario", Scenario: "checkout", Passed: &passed},
+	}
+	prev := zeroHash
+	start := time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	for i := range entries {
+		e := &entries[i]
+		e.Seq = i + 1
+		e.Time = start.Add(time.Duration(i) * time.Second)
+		e.PrevHash = prev
+		e.Hash = EntryHash(*e)
+		prev = e.Hash
+	}
+	exp := &adminclient.AuditExport{Twin: "twin-stripe", Entries: entries, Head: prev}
+	if key != "" {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(exp.Head))
+		exp.Signature = hex.EncodeToString(mac.Sum(nil))
+	}
+	return exp
+}
+
+func TestVerifyExport(t *testing.T) {
+	if err := VerifyExport(chain("k3y"), "k3y"); err != nil {
+		t.Fatalf("expected an intact chain to verify, got %v", err)
+	}
+	if err := VerifyExport(&adminclient.AuditExport{Head: zeroHash}, ""); err != nil {
+		t.Errorf("expected an empty log to verify, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(*adminclient.AuditExport)
+		key    string
+		want   string
+	}{
+		{"edited entry", func(e *adminclient.AuditExport) { e.Entries[1].StatusCode = 403 }, "", "entry 2: hash does not match"},
+		{"rehashed entry", func(e *adminclient.AuditExport) {
+			e.Entries[1].StatusCode = 403
+			e.Entries[1].Hash = EntryHash(e.Entries[1])
+		}, "", "entry 3: does not chain"},
+		{"removed entry", func(e *adminclient.AuditExport) {
+			e.Entries = append(e.Entries[:1], e.Entries[2:]...)
+		}, "", "entry 3 follows entry 1"},
+		{"truncated log", func(e *adminclient.AuditExport) { e.Entries = e.Entries[:2] }, "", "is not the last entry's hash"},
+		{"wrong key", func(e *adminclient.AuditExport) {}, "other", "signature does not match"},
+		{"unsigned", func(e *adminclient.AuditExport) { e.Signature = "" }, "k3y", "not signed"},
+	}
+	for _, tt := range tests {
+		exp := chain("k3y")
+		tt.tamper(exp)
+		if err := VerifyExport(exp, tt.key); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Once the twin drops its oldest entries, the chain verifies from the
+	// first one kept.
+	exp := chain("")
+	exp.Entries, exp.Dropped = exp.Entries[1:], 1
+	if err := VerifyExport(exp, ""); err != nil {
+		t.Errorf("expected a log with dropped entries to verify, got %v", err)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/audit/export" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(chain("k3y"))
+	}))
+	defer srv.Close()
+	port, _ := strconv.Atoi(srv.URL[strings.LastIndex(srv.URL, ":")+1:])
+	m := &manifest.Manifest{Twins: map[string]manifest.Twin{
+		"stripe": {Port: port, AdminPort: port},
+		"resend": {Port: 1, AdminPort: 1},
+	}}
+
+	a := Collect(context.Background(), m, "v0.9.0")
+	if a.Twins["stripe"] == nil || a.Errors["resend"] == "" || len(a.Twins) != 1 {
+		t.Fatalf("expected stripe collected and resend unreachable, got %+v", a)
+	}
+	if problems := a.Verify("k3y"); problems != nil {
+		t.Errorf("expected the artifact to verify, got %v", problems)
+	}
+
+	a.Twins["stripe"].Entries[0].Path = "/admin/fault/v1/charges"
+	a.Digest = strings.Repeat("0", 64)
+	problems := a.Verify("")
+	if len(problems) != 2 || !strings.HasPrefix(problems[0], "stripe: entry 1") || !strings.HasPrefix(problems[1], "digest") {
+		t.Errorf("expected the edited entry and the digest reported, got %v", problems)
+	}
+}
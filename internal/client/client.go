@@ -1,14 +1,19 @@
 // Package client provides an HTTP client for twin admin API endpoints.
+// It is a thin, URL-addressed layer over the public adminclient package
+// that returns raw response bodies for wt to display.
 package client
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
 )
 
 // AdminClient talks to twin /admin/* endpoints.
@@ -23,90 +28,271 @@ func New() *AdminClient {
 	}
 }
 
+// twin returns an adminclient for the twin whose admin API is served at
+// adminURL. wt reports failures straight to the user, so it does not retry.
+func (c *AdminClient) twin(adminURL string) *adminclient.Client {
+	return adminclient.New(adminURL,
+		adminclient.WithHTTPClient(c.http),
+		adminclient.WithRetries(0, 0),
+	)
+}
+
 // Health checks GET /admin/health. Returns (ok, response body or error message).
-func (c *AdminClient) Health(adminPort int) (bool, string) {
-	resp, err := c.http.Get(fmt.Sprintf("http://localhost:%d/admin/health", adminPort))
+func (c *AdminClient) Health(adminURL string) (bool, string) {
+	var body json.RawMessage
+	err := c.twin(adminURL).Do(context.Background(), http.MethodGet, "/admin/health", nil, &body)
 	if err != nil {
+		var apiErr *adminclient.APIError
+		if errors.As(err, &apiErr) {
+			return false, fmt.Sprintf("status %d: %s", apiErr.StatusCode, apiErr.Body)
+		}
 		return false, err.Error()
 	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode == http.StatusOK {
-		return true, strings.TrimSpace(string(body))
-	}
-	return false, fmt.Sprintf("status %d: %s", resp.StatusCode, body)
+	return true, string(body)
 }
 
 // Reset calls POST /admin/reset on a twin.
-func (c *AdminClient) Reset(adminPort int) (string, error) {
-	resp, err := c.http.Post(
-		fmt.Sprintf("http://localhost:%d/admin/reset", adminPort),
-		"application/json", nil,
-	)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("reset returned status %d: %s", resp.StatusCode, body)
-	}
-	return strings.TrimSpace(string(body)), nil
+func (c *AdminClient) Reset(adminURL string) (string, error) {
+	return c.adminPost(adminURL, "/admin/reset", nil)
+}
+
+// ResetResources calls POST /admin/reset with a body naming the resources to
+// clear, leaving the rest of the twin's state intact.
+func (c *AdminClient) ResetResources(adminURL string, resources []string) (string, error) {
+	return c.adminPost(adminURL, "/admin/reset", map[string][]string{"resources": resources})
+}
+
+// ResetWithSeed calls POST /admin/reset with a body naming the seed preset
+// the twin should load instead of its default fixtures.
+func (c *AdminClient) ResetWithSeed(adminURL string, preset string) (string, error) {
+	return c.adminPost(adminURL, "/admin/reset", map[string]string{"seed": preset})
 }
 
 // Inspect fetches GET /admin/state and returns the raw JSON body.
-func (c *AdminClient) Inspect(adminPort int) (string, error) {
-	return c.adminGet(adminPort, "/admin/state")
+func (c *AdminClient) Inspect(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/state")
 }
 
 // InspectRequests fetches GET /admin/requests and returns the raw JSON body.
-func (c *AdminClient) InspectRequests(adminPort int) (string, error) {
-	return c.adminGet(adminPort, "/admin/requests")
+func (c *AdminClient) InspectRequests(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/requests")
 }
 
 // InspectFaults fetches GET /admin/faults and returns the raw JSON body.
-func (c *AdminClient) InspectFaults(adminPort int) (string, error) {
-	return c.adminGet(adminPort, "/admin/faults")
+func (c *AdminClient) InspectFaults(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/faults")
 }
 
 // InspectTime fetches GET /admin/time and returns the raw JSON body.
-func (c *AdminClient) InspectTime(adminPort int) (string, error) {
-	return c.adminGet(adminPort, "/admin/time")
+func (c *AdminClient) InspectTime(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/time")
 }
 
-// adminGet is a helper that GETs an admin endpoint and returns the raw body.
-func (c *AdminClient) adminGet(adminPort int, path string) (string, error) {
-	resp, err := c.http.Get(fmt.Sprintf("http://localhost:%d%s", adminPort, path))
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("GET %s returned status %d: %s", path, resp.StatusCode, body)
-	}
-	return string(body), nil
+// InspectWebhooks fetches GET /admin/webhooks and returns the raw JSON body.
+func (c *AdminClient) InspectWebhooks(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/webhooks")
+}
+
+// EventCatalog fetches GET /admin/webhooks/events/catalog and returns the raw
+// JSON body.
+func (c *AdminClient) EventCatalog(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/webhooks/events/catalog")
+}
+
+// TriggerEvent calls POST /admin/webhooks/trigger to emit an event of the
+// given type, its example payload patched with overrides.
+func (c *AdminClient) TriggerEvent(adminURL, eventType string, overrides map[string]any) (string, error) {
+	return c.adminPost(adminURL, "/admin/webhooks/trigger", map[string]any{
+		"type":      eventType,
+		"overrides": overrides,
+	})
+}
+
+// InspectEvents fetches GET /admin/events and returns the raw JSON body.
+func (c *AdminClient) InspectEvents(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/events")
+}
+
+// InspectConfig fetches GET /admin/config and returns the raw JSON body.
+func (c *AdminClient) InspectConfig(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/config")
+}
+
+// InspectQuirks fetches GET /admin/quirks and returns the raw JSON body.
+func (c *AdminClient) InspectQuirks(adminURL string) (string, error) {
+	return c.adminGet(adminURL, "/admin/quirks")
+}
+
+// VersionInfo is the build metadata reported by GET /admin/version.
+type VersionInfo = adminclient.VersionInfo
+
+// Version calls GET /admin/version on a twin.
+func (c *AdminClient) Version(adminURL string) (*VersionInfo, error) {
+	return c.twin(adminURL).Version(context.Background())
+}
+
+// Replay calls POST /admin/requests/{id}/replay and returns the raw JSON body.
+func (c *AdminClient) Replay(adminURL string, requestID string) (string, error) {
+	return c.adminPost(adminURL, "/admin/requests/"+requestID+"/replay", nil)
+}
+
+// EnableQuirk calls PUT /admin/quirks/{id} on a twin.
+func (c *AdminClient) EnableQuirk(adminURL string, quirkID string) error {
+	return c.twin(adminURL).EnableQuirk(context.Background(), quirkID)
+}
+
+// DisableQuirk calls DELETE /admin/quirks/{id} on a twin.
+func (c *AdminClient) DisableQuirk(adminURL string, quirkID string) error {
+	return c.twin(adminURL).DisableQuirk(context.Background(), quirkID)
+}
+
+// UpdateConfig calls PUT /admin/config to change runtime settings such as
+// latency and fail_rate on a running twin.
+func (c *AdminClient) UpdateConfig(adminURL string, updates map[string]any) error {
+	_, err := c.twin(adminURL).UpdateConfig(context.Background(), updates)
+	return err
+}
+
+// Tenant is an account on a twin with its own credentials.
+type Tenant = adminclient.Tenant
+
+// Requests calls GET /admin/requests on a twin.
+func (c *AdminClient) Requests(adminURL string) ([]adminclient.RequestLogEntry, error) {
+	return c.twin(adminURL).Requests(context.Background())
+}
+
+// Routes calls GET /admin/routes on a twin.
+func (c *AdminClient) Routes(adminURL string) ([]adminclient.Route, error) {
+	return c.twin(adminURL).Routes(context.Background())
 }
 
-// Seed POSTs the contents of a JSON file to POST /admin/state on a twin.
-func (c *AdminClient) Seed(adminPort int, filePath string) (string, error) {
+// Webhooks calls GET /admin/webhooks on a twin.
+func (c *AdminClient) Webhooks(adminURL string) (*adminclient.Webhooks, error) {
+	return c.twin(adminURL).Webhooks(context.Background())
+}
+
+// DeadLetters calls GET /admin/webhooks/dead_letters on a twin.
+func (c *AdminClient) DeadLetters(adminURL string) ([]adminclient.DeadLetter, error) {
+	return c.twin(adminURL).DeadLetters(context.Background())
+}
+
+// Tenants calls GET /admin/tenants on a twin.
+func (c *AdminClient) Tenants(adminURL string) ([]Tenant, error) {
+	return c.twin(adminURL).Tenants(context.Background())
+}
+
+// DeleteTenant calls DELETE /admin/tenants/{id} on a twin.
+func (c *AdminClient) DeleteTenant(adminURL string, id string) error {
+	return c.twin(adminURL).DeleteTenant(context.Background(), id)
+}
+
+// Fault is a fault injected into a twin endpoint.
+type Fault = adminclient.Fault
+
+// InjectFault calls POST /admin/fault/{endpoint} on a twin.
+func (c *AdminClient) InjectFault(adminURL string, endpoint string, fault Fault) error {
+	return c.twin(adminURL).InjectFault(context.Background(), endpoint, fault)
+}
+
+// RemoveFault calls DELETE /admin/fault/{endpoint} on a twin.
+func (c *AdminClient) RemoveFault(adminURL string, endpoint string) error {
+	return c.twin(adminURL).RemoveFault(context.Background(), endpoint)
+}
+
+// SetStorageFailures calls PUT /admin/faults/storage on a twin.
+func (c *AdminClient) SetStorageFailures(adminURL string, cfg adminclient.StorageFailureConfig) error {
+	_, err := c.twin(adminURL).SetStorageFailures(context.Background(), cfg)
+	return err
+}
+
+// ClearStorageFailures calls DELETE /admin/faults/storage on a twin.
+func (c *AdminClient) ClearStorageFailures(adminURL string) error {
+	return c.twin(adminURL).ClearStorageFailures(context.Background())
+}
+
+// Intercept holds a twin's responses to matching requests until each is
+// decided.
+type Intercept = adminclient.Intercept
+
+// Intercepts calls GET /admin/intercepts on a twin.
+func (c *AdminClient) Intercepts(adminURL string) ([]Intercept, error) {
+	return c.twin(adminURL).Intercepts(context.Background())
+}
+
+// AddIntercept calls POST /admin/intercepts on a twin.
+func (c *AdminClient) AddIntercept(adminURL string, ic Intercept) (*Intercept, error) {
+	return c.twin(adminURL).AddIntercept(context.Background(), ic)
+}
+
+// DecideIntercept calls POST /admin/intercepts/{id}/decide on a twin.
+func (c *AdminClient) DecideIntercept(adminURL string, id string, d adminclient.InterceptDecision) error {
+	return c.twin(adminURL).DecideIntercept(context.Background(), id, d)
+}
+
+// RemoveIntercept calls DELETE /admin/intercepts/{id} on a twin.
+func (c *AdminClient) RemoveIntercept(adminURL string, id string) error {
+	return c.twin(adminURL).RemoveIntercept(context.Background(), id)
+}
+
+// AdvanceTime calls POST /admin/time/advance and returns the raw JSON body.
+func (c *AdminClient) AdvanceTime(adminURL string, d time.Duration) (string, error) {
+	return c.adminPost(adminURL, "/admin/time/advance", map[string]string{"duration": d.String()})
+}
+
+// SetTime calls POST /admin/time/set and returns the raw JSON body.
+func (c *AdminClient) SetTime(adminURL string, t time.Time) (string, error) {
+	return c.adminPost(adminURL, "/admin/time/set", map[string]string{"to": t.Format(time.RFC3339Nano)})
+}
+
+// FreezeTime calls POST /admin/time/freeze and returns the raw JSON body.
+func (c *AdminClient) FreezeTime(adminURL string) (string, error) {
+	return c.adminPost(adminURL, "/admin/time/freeze", nil)
+}
+
+// UnfreezeTime calls POST /admin/time/unfreeze and returns the raw JSON body.
+func (c *AdminClient) UnfreezeTime(adminURL string) (string, error) {
+	return c.adminPost(adminURL, "/admin/time/unfreeze", nil)
+}
+
+// Seed POSTs the contents of a JSON file to POST /admin/state on a twin,
+// which expands the seed templates in it.
+func (c *AdminClient) Seed(adminURL string, filePath string) (string, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return "", fmt.Errorf("reading seed file: %w", err)
 	}
+	return c.adminPost(adminURL, "/admin/state?expand=true", data)
+}
 
-	resp, err := c.http.Post(
-		fmt.Sprintf("http://localhost:%d/admin/state", adminPort),
-		"application/json",
-		bytes.NewReader(data),
-	)
+// LintSeed POSTs the contents of a JSON file to POST /admin/state/lint,
+// which expands its seed templates and checks its references without
+// loading it.
+func (c *AdminClient) LintSeed(adminURL string, filePath string) (*adminclient.SeedLintResult, error) {
+	data, err := os.ReadFile(filePath)
 	if err != nil {
+		return nil, fmt.Errorf("reading seed file: %w", err)
+	}
+	var result adminclient.SeedLintResult
+	if err := c.twin(adminURL).Do(context.Background(), http.MethodPost, "/admin/state/lint?expand=true", data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// adminPost POSTs a body to an admin endpoint and returns the raw response body.
+func (c *AdminClient) adminPost(adminURL string, path string, body any) (string, error) {
+	var resp json.RawMessage
+	if err := c.twin(adminURL).Do(context.Background(), http.MethodPost, path, body, &resp); err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("seed failed (status %d): %s", resp.StatusCode, body)
+	return strings.TrimSpace(string(resp)), nil
+}
+
+// adminGet GETs an admin endpoint and returns the raw response body.
+func (c *AdminClient) adminGet(adminURL string, path string) (string, error) {
+	var resp json.RawMessage
+	if err := c.twin(adminURL).Do(context.Background(), http.MethodGet, path, nil, &resp); err != nil {
+		return "", err
 	}
-	return strings.TrimSpace(string(body)), nil
+	return string(resp), nil
 }
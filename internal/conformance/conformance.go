@@ -30,9 +30,24 @@ type Report struct {
 	Failed  int
 }
 
+// Options selects optional parts of the conformance suite.
+type Options struct {
+	// Perf enables the performance baseline checks when non-nil.
+	Perf *PerfOptions
+	// Probe, when non-nil, is used to check that reset restarts ID counters.
+	Probe *Probe
+	// SDK, when non-nil, runs the twin's SDK checks program.
+	SDK *SDKOptions
+}
+
 // Run executes the full conformance suite against a twin binary.
 // It starts the binary, runs all checks, and returns a report.
 func Run(binaryPath string, port int) (*Report, error) {
+	return RunWithOptions(binaryPath, port, Options{})
+}
+
+// RunWithOptions is Run with optional checks enabled by opts.
+func RunWithOptions(binaryPath string, port int, opts Options) (*Report, error) {
 	report := &Report{
 		Binary: binaryPath,
 		Port:   port,
@@ -94,9 +109,34 @@ func Run(binaryPath string, port int) (*Report, error) {
 
 		// Check 9: GET /admin/quirks returns valid JSON (or 404 if not implemented)
 		report.addResult(checkQuirks(baseURL))
+
+		// Check 10-12: reset restores the baseline, state round-trips,
+		// and concurrent resets don't corrupt state
+		report.addResult(checkResetRestoresBaseline(baseURL))
+		report.addResult(checkStateRoundTrip(baseURL))
+		report.addResult(checkParallelResets(baseURL))
+
+		// Optional: reset restarts ID counters
+		if opts.Probe != nil {
+			report.addResult(checkResetRestartsCounters(baseURL, *opts.Probe))
+		}
+
+		// Optional: latency and throughput under concurrent load
+		if opts.Perf != nil {
+			perf := opts.Perf.withDefaults()
+			report.addResult(checkHealthLatency(baseURL, perf))
+			report.addResult(checkEndpointLoad(baseURL, perf))
+		}
+
+		// Optional: the provider's SDK works against the twin
+		if opts.SDK != nil {
+			for _, res := range checkSDK(baseURL, *opts.SDK) {
+				report.addResult(res)
+			}
+		}
 	}
 
-	// Check 10: Twin shuts down cleanly on SIGTERM within 5 seconds
+	// Check 13: Twin shuts down cleanly on SIGTERM within 5 seconds
 	report.addResult(checkCleanShutdown(cmd))
 
 	for _, r := range report.Results {
@@ -0,0 +1,366 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Probe is a request that creates a resource, used to check that reset
+// restarts ID counters. The response must be a JSON object with an "id".
+type Probe struct {
+	Method  string
+	Path    string
+	Body    string
+	Headers map[string]string
+}
+
+// getJSON fetches path and decodes the response body.
+func getJSON(baseURL, path string) (any, error) {
+	resp, err := http.Get(baseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %d", path, resp.StatusCode)
+	}
+	var v any
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, fmt.Errorf("GET %s: invalid JSON: %w", path, err)
+	}
+	return v, nil
+}
+
+func post(baseURL, path, body string) error {
+	resp, err := http.Post(baseURL+path, "application/json", strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("POST %s returned %d", path, resp.StatusCode)
+	}
+	return nil
+}
+
+// snapshot returns the twin's state with wall-clock timestamps masked, so
+// snapshots taken at different moments compare equal when only time moved.
+func snapshot(baseURL string) (any, error) {
+	v, err := getJSON(baseURL, "/admin/state")
+	if err != nil {
+		return nil, err
+	}
+	return maskTimestamps(v), nil
+}
+
+// maskTimestamps replaces RFC 3339 strings and Unix-second integers in the
+// current era with a placeholder.
+func maskTimestamps(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, e := range x {
+			x[k] = maskTimestamps(e)
+		}
+		return x
+	case []any:
+		for i, e := range x {
+			x[i] = maskTimestamps(e)
+		}
+		return x
+	case string:
+		if _, err := time.Parse(time.RFC3339, x); err == nil {
+			return "<time>"
+		}
+	case float64:
+		if x >= 1e9 && x < 1e10 && x == float64(int64(x)) {
+			return "<time>"
+		}
+	}
+	return v
+}
+
+// clockOffset returns the simulated clock offset, or "" if the twin has no
+// simulated clock.
+func clockOffset(baseURL string) (string, error) {
+	v, err := getJSON(baseURL, "/admin/time")
+	if err != nil {
+		return "", err
+	}
+	m, _ := v.(map[string]any)
+	offset, _ := m["offset"].(string)
+	return offset, nil
+}
+
+func faultCount(baseURL string) (int, error) {
+	v, err := getJSON(baseURL, "/admin/faults")
+	if err != nil {
+		return 0, err
+	}
+	m, _ := v.(map[string]any)
+	return len(m), nil
+}
+
+// verifyBaseline checks that the twin's state, clock, and faults match a
+// freshly reset twin.
+func verifyBaseline(baseURL string, baseline any) error {
+	got, err := snapshot(baseURL)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(got, baseline) {
+		return fmt.Errorf("state after reset differs from baseline: %s", diffHint(baseline, got))
+	}
+	offset, err := clockOffset(baseURL)
+	if err != nil {
+		return err
+	}
+	if offset != "" && offset != "0s" {
+		return fmt.Errorf("clock offset after reset is %s, expected 0s", offset)
+	}
+	n, err := faultCount(baseURL)
+	if err != nil {
+		return err
+	}
+	if n != 0 {
+		return fmt.Errorf("%d faults still registered after reset", n)
+	}
+	return nil
+}
+
+// diffHint names the top-level keys whose values differ.
+func diffHint(want, got any) string {
+	wm, wok := want.(map[string]any)
+	gm, gok := got.(map[string]any)
+	if !wok || !gok {
+		return "snapshot shape changed"
+	}
+	var keys []string
+	for k := range wm {
+		if !reflect.DeepEqual(wm[k], gm[k]) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range gm {
+		if _, ok := wm[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	return "differs in " + strings.Join(keys, ", ")
+}
+
+// resetBaseline resets the twin and returns its masked state.
+func resetBaseline(baseURL string) (any, error) {
+	if err := post(baseURL, "/admin/reset", ""); err != nil {
+		return nil, err
+	}
+	return snapshot(baseURL)
+}
+
+func checkResetRestoresBaseline(baseURL string) Result {
+	name := "POST /admin/reset restores baseline state, clock, and faults"
+
+	baseline, err := resetBaseline(baseURL)
+	if err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+
+	// Mutate everything the admin API can reach generically, and check the
+	// state change took, so the reset below has something to undo.
+	raw, err := getJSON(baseURL, "/admin/state")
+	if err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	collection, ok := addProbeRecord(raw)
+	if !ok {
+		return Result{Name: name, Passed: false, Detail: "state snapshot has no collection keyed by ID to add a record to"}
+	}
+	data, _ := json.Marshal(raw)
+	for _, step := range []struct{ path, body string }{
+		{"/admin/state", string(data)},
+		{"/admin/time/advance", `{"duration": "48h"}`},
+		{"/admin/fault/conformance-isolation", `{"status_code": 500}`},
+	} {
+		if err := post(baseURL, step.path, step.body); err != nil {
+			return Result{Name: name, Passed: false, Detail: "mutating state: " + err.Error()}
+		}
+	}
+	changed, err := snapshot(baseURL)
+	if err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	if reflect.DeepEqual(changed, baseline) {
+		return Result{Name: name, Passed: false, Detail: fmt.Sprintf("loading a snapshot with a record added to %s left state unchanged", collection)}
+	}
+
+	if err := post(baseURL, "/admin/reset", ""); err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	if err := verifyBaseline(baseURL, baseline); err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	return Result{Name: name, Passed: true, Detail: "state, clock offset, and faults match the post-reset baseline"}
+}
+
+// probeRecordID is the ID of the record checkResetRestoresBaseline adds.
+const probeRecordID = "conformance_probe"
+
+// addProbeRecord adds a record to the first collection of a state snapshot,
+// by key, that is an object keyed by ID: a copy of one of its records with
+// the probe ID, or an empty record if it has none. It returns the
+// collection's key, or false if the snapshot has no such collection.
+func addProbeRecord(state any) (string, bool) {
+	m, ok := state.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		collection, ok := m[k].(map[string]any)
+		if !ok || !isRecords(collection) {
+			continue
+		}
+		record := map[string]any{}
+		for _, v := range collection {
+			for field, value := range v.(map[string]any) {
+				record[field] = value
+			}
+			if _, ok := record["id"].(string); ok {
+				record["id"] = probeRecordID
+			}
+			break
+		}
+		collection[probeRecordID] = record
+		return k, true
+	}
+	return "", false
+}
+
+// isRecords reports whether every value of collection is an object.
+func isRecords(collection map[string]any) bool {
+	for _, v := range collection {
+		if _, ok := v.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func checkStateRoundTrip(baseURL string) Result {
+	name := "GET /admin/state round-trips through POST /admin/state"
+
+	before, err := getJSON(baseURL, "/admin/state")
+	if err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	data, _ := json.Marshal(before)
+	if err := post(baseURL, "/admin/state", string(data)); err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	after, err := getJSON(baseURL, "/admin/state")
+	if err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	if !reflect.DeepEqual(before, after) {
+		return Result{Name: name, Passed: false, Detail: "reloaded snapshot " + diffHint(before, after)}
+	}
+	return Result{Name: name, Passed: true, Detail: "snapshot unchanged after reload"}
+}
+
+func checkParallelResets(baseURL string) Result {
+	name := "Concurrent resets leave state consistent"
+	const workers = 16
+
+	baseline, err := resetBaseline(baseURL)
+	if err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*3)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := post(baseURL, "/admin/time/advance", `{"duration": "1h"}`); err != nil {
+				errs <- err
+			}
+			if err := post(baseURL, "/admin/reset", ""); err != nil {
+				errs <- err
+			}
+			if _, err := getJSON(baseURL, "/admin/state"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err, ok := <-errs; ok {
+		return Result{Name: name, Passed: false, Detail: "concurrent request failed: " + err.Error()}
+	}
+
+	if err := post(baseURL, "/admin/reset", ""); err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	if err := verifyBaseline(baseURL, baseline); err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	return Result{Name: name, Passed: true, Detail: fmt.Sprintf("%d concurrent reset/advance/read cycles, baseline intact", workers)}
+}
+
+func checkResetRestartsCounters(baseURL string, probe Probe) Result {
+	name := fmt.Sprintf("POST /admin/reset restarts ID counters (%s %s)", probe.Method, probe.Path)
+
+	create := func() (string, error) {
+		if err := post(baseURL, "/admin/reset", ""); err != nil {
+			return "", err
+		}
+		req, err := http.NewRequest(probe.Method, baseURL+probe.Path, bytes.NewReader([]byte(probe.Body)))
+		if err != nil {
+			return "", err
+		}
+		for k, v := range probe.Headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return "", fmt.Errorf("%s %s returned %d", probe.Method, probe.Path, resp.StatusCode)
+		}
+		var body struct {
+			ID any `json:"id"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.ID == nil {
+			return "", fmt.Errorf("%s %s did not return a JSON object with an id", probe.Method, probe.Path)
+		}
+		return fmt.Sprint(body.ID), nil
+	}
+
+	first, err := create()
+	if err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	second, err := create()
+	if err != nil {
+		return Result{Name: name, Passed: false, Detail: err.Error()}
+	}
+	if first != second {
+		return Result{Name: name, Passed: false, Detail: fmt.Sprintf("first resource after reset got id %s, then %s after another reset", first, second)}
+	}
+	return Result{Name: name, Passed: true, Detail: "id " + first + " reissued after reset"}
+}
@@ -0,0 +1,162 @@
+package conformance
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PerfOptions configures the optional performance baseline checks. They
+// flag twins that are slow or serialize requests behind a lock.
+type PerfOptions struct {
+	// Endpoint is the representative GET request measured alongside
+	// /admin/health. It defaults to /admin/state, which reads every store.
+	Endpoint string
+	// Concurrency is the number of parallel clients (default 10).
+	Concurrency int
+	// Requests is the total number of requests per check (default 500).
+	Requests int
+	// HealthP99 is the p99 latency limit for /admin/health (default 50ms).
+	HealthP99 time.Duration
+	// EndpointP99 is the p99 latency limit for Endpoint (default 100ms).
+	EndpointP99 time.Duration
+	// MinRPS is the minimum throughput for Endpoint (default 200 req/s).
+	MinRPS float64
+}
+
+func (o *PerfOptions) withDefaults() PerfOptions {
+	p := *o
+	if p.Endpoint == "" {
+		p.Endpoint = "/admin/state"
+	}
+	if p.Concurrency <= 0 {
+		p.Concurrency = 10
+	}
+	if p.Requests <= 0 {
+		p.Requests = 500
+	}
+	if p.HealthP99 <= 0 {
+		p.HealthP99 = 50 * time.Millisecond
+	}
+	if p.EndpointP99 <= 0 {
+		p.EndpointP99 = 100 * time.Millisecond
+	}
+	if p.MinRPS <= 0 {
+		p.MinRPS = 200
+	}
+	return p
+}
+
+// loadStats summarizes a load run.
+type loadStats struct {
+	p50, p99 time.Duration
+	rps      float64
+	errors   int
+}
+
+// measureLoad issues total GET requests to url from concurrency workers and
+// reports latency percentiles and throughput. Transport errors and 5xx
+// responses count as errors.
+func measureLoad(url string, concurrency, total int) loadStats {
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: concurrency},
+	}
+	defer client.CloseIdleConnections()
+
+	jobs := make(chan struct{}, total)
+	for i := 0; i < total; i++ {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	latencies := make([]time.Duration, 0, total)
+	errors := 0
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				t := time.Now()
+				resp, err := client.Get(url)
+				failed := err != nil
+				if err == nil {
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+					failed = resp.StatusCode >= 500
+				}
+				d := time.Since(t)
+				mu.Lock()
+				latencies = append(latencies, d)
+				if failed {
+					errors++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return loadStats{
+		p50:    percentile(latencies, 50),
+		p99:    percentile(latencies, 99),
+		rps:    float64(len(latencies)) / elapsed.Seconds(),
+		errors: errors,
+	}
+}
+
+// percentile returns the p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := (len(sorted)*p + 99) / 100
+	if idx < 1 {
+		idx = 1
+	}
+	return sorted[idx-1]
+}
+
+func checkHealthLatency(baseURL string, opts PerfOptions) Result {
+	name := fmt.Sprintf("GET /admin/health p99 under %d concurrent clients <= %s", opts.Concurrency, opts.HealthP99)
+
+	stats := measureLoad(baseURL+"/admin/health", opts.Concurrency, opts.Requests)
+	detail := fmt.Sprintf("p50 %s, p99 %s, %.0f req/s", roundLatency(stats.p50), roundLatency(stats.p99), stats.rps)
+	if stats.errors > 0 {
+		return Result{Name: name, Passed: false, Detail: fmt.Sprintf("%d of %d requests failed (%s)", stats.errors, opts.Requests, detail)}
+	}
+	if stats.p99 > opts.HealthP99 {
+		return Result{Name: name, Passed: false, Detail: "p99 over threshold: " + detail}
+	}
+	return Result{Name: name, Passed: true, Detail: detail}
+}
+
+func checkEndpointLoad(baseURL string, opts PerfOptions) Result {
+	name := fmt.Sprintf("GET %s under load: p99 <= %s, >= %.0f req/s", opts.Endpoint, opts.EndpointP99, opts.MinRPS)
+
+	stats := measureLoad(baseURL+opts.Endpoint, opts.Concurrency, opts.Requests)
+	detail := fmt.Sprintf("p50 %s, p99 %s, %.0f req/s", roundLatency(stats.p50), roundLatency(stats.p99), stats.rps)
+	if stats.errors > 0 {
+		return Result{Name: name, Passed: false, Detail: fmt.Sprintf("%d of %d requests failed (%s)", stats.errors, opts.Requests, detail)}
+	}
+	if stats.p99 > opts.EndpointP99 {
+		return Result{Name: name, Passed: false, Detail: "p99 over threshold: " + detail}
+	}
+	if stats.rps < opts.MinRPS {
+		return Result{Name: name, Passed: false, Detail: "throughput under threshold: " + detail}
+	}
+	return Result{Name: name, Passed: true, Detail: detail}
+}
+
+func roundLatency(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
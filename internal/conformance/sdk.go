@@ -0,0 +1,100 @@
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SDKOptions configures the optional SDK checks: a twin's sdkcheck
+// program, built on the twinkit/sdkcheck harness, that drives the twin
+// through the provider's real SDK.
+type SDKOptions struct {
+	// Dir is the program's module directory, e.g. twin-stripe/sdkcheck.
+	Dir string
+	// Timeout bounds building and running it (default 5m, since the first
+	// build downloads the SDK).
+	Timeout time.Duration
+}
+
+// sdkResult mirrors twinkit/sdkcheck.Result, one per line of the
+// program's output.
+type sdkResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// checkSDK builds the program in opts.Dir and runs it against the twin,
+// returning a result per SDK check it reports. A program that cannot be
+// built, or exits without reporting its checks, is a single failure.
+func checkSDK(baseURL string, opts SDKOptions) []Result {
+	name := "SDK checks in " + opts.Dir
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	tmp, err := os.MkdirTemp("", "wt-sdkcheck-")
+	if err != nil {
+		return []Result{{Name: name, Detail: err.Error()}}
+	}
+	defer os.RemoveAll(tmp)
+	bin := filepath.Join(tmp, "sdkcheck")
+
+	// The program is its own module, outside the repo's go.work. Its
+	// committed go.sum pins the SDK; -mod=readonly fails the build rather
+	// than resolving and recording dependencies the sum does not cover.
+	build := exec.CommandContext(ctx, "go", "build", "-mod=readonly", "-o", bin, ".")
+	build.Dir = opts.Dir
+	build.Env = append(os.Environ(), "GOWORK=off")
+	if out, err := build.CombinedOutput(); err != nil {
+		return []Result{{Name: name, Detail: fmt.Sprintf("building: %v\n%s", err, tail(out))}}
+	}
+
+	run := exec.CommandContext(ctx, bin)
+	run.Dir = opts.Dir
+	run.Env = append(os.Environ(), "WT_TWIN_URL="+baseURL, "WT_ADMIN_URL="+baseURL)
+	var stderr bytes.Buffer
+	run.Stderr = &stderr
+	out, runErr := run.Output()
+
+	var results []Result
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		var r sdkResult
+		if json.Unmarshal(sc.Bytes(), &r) != nil || r.Name == "" {
+			continue // the SDK's own logging
+		}
+		results = append(results, Result{Name: "SDK: " + r.Name, Passed: r.Passed, Detail: r.Detail})
+	}
+	if len(results) == 0 {
+		detail := "reported no checks"
+		if runErr != nil {
+			detail = fmt.Sprintf("%v\n%s", runErr, tail(stderr.Bytes()))
+		}
+		return []Result{{Name: name, Detail: detail}}
+	}
+	if ctx.Err() != nil {
+		results = append(results, Result{Name: name, Detail: fmt.Sprintf("timed out after %s", timeout)})
+	}
+	return results
+}
+
+// tail returns the last few lines of command output for a failure detail.
+func tail(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) > 15 {
+		lines = lines[len(lines)-15:]
+	}
+	return strings.Join(lines, "\n")
+}
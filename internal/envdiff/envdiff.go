@@ -0,0 +1,278 @@
+// Package envdiff captures a developer's twin environment for
+// `wt env export` and compares two captures for `wt env diff`: the twins
+// the manifest declares, the binaries and versions actually installed and
+// running, each running twin's config, enabled quirks, and faults, and a
+// hash of every seed file. Two exports that differ show, field by field,
+// why the same test behaves differently on two machines.
+package envdiff
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/lockfile"
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// Format is the export format version, bumped when Env changes
+// incompatibly.
+const Format = 1
+
+// Env is one machine's twin environment.
+type Env struct {
+	Format     int             `json:"format"`
+	WTVersion  string          `json:"wt_version"`
+	Platform   string          `json:"platform"` // GOOS/GOARCH
+	ExportedAt time.Time       `json:"exported_at"`
+	Twins      map[string]Twin `json:"twins"`
+}
+
+// Twin is one twin of an environment. The declared entry is the manifest's;
+// the rest is what is installed and, if the twin is running, what it
+// reports over its admin API.
+type Twin struct {
+	Declared manifest.Twin `json:"declared"`
+
+	LockedVersion string `json:"locked_version,omitempty"` // from wondertwin-lock.json
+	BinarySHA256  string `json:"binary_sha256,omitempty"`
+	SeedSHA256    string `json:"seed_sha256,omitempty"`
+
+	Running bool                         `json:"running"`
+	Build   *adminclient.VersionInfo     `json:"build,omitempty"`
+	Config  map[string]any               `json:"config,omitempty"`
+	Quirks  []string                     `json:"quirks,omitempty"` // enabled quirk IDs, sorted
+	Faults  map[string]adminclient.Fault `json:"faults,omitempty"`
+
+	Error string `json:"error,omitempty"` // why the running twin could not be read
+}
+
+// Collect captures the environment m describes. lock may be nil. Twins
+// that are not running are recorded from the manifest and disk alone.
+func Collect(ctx context.Context, m *manifest.Manifest, lock *lockfile.LockFile, wtVersion string) *Env {
+	env := &Env{
+		Format:     Format,
+		WTVersion:  wtVersion,
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		ExportedAt: time.Now().UTC(),
+		Twins:      map[string]Twin{},
+	}
+	for _, name := range m.TwinNames() {
+		declared := m.Twins[name]
+		t := Twin{Declared: portable(declared)}
+		if lock != nil {
+			t.LockedVersion = lock.Twins[name].Version
+		}
+		if declared.Binary != "" {
+			t.BinarySHA256, _ = hashFile(declared.Binary)
+		}
+		if declared.Seed != "" {
+			var err error
+			if t.SeedSHA256, err = hashFile(declared.Seed); err != nil {
+				t.SeedSHA256 = "unreadable: " + err.Error()
+			}
+		}
+		collectRunning(ctx, adminclient.New(declared.AdminURL()), &t)
+		env.Twins[name] = t
+	}
+	return env
+}
+
+// portable strips what legitimately differs between machines from a
+// manifest entry: the home directory in the binary path, and env values,
+// which may hold credentials and are reduced to hashes.
+func portable(t manifest.Twin) manifest.Twin {
+	if t.Binary != "" {
+		t.Binary = filepath.Base(t.Binary)
+	}
+	if len(t.Env) > 0 {
+		env := make(map[string]string, len(t.Env))
+		for k, v := range t.Env {
+			sum := sha256.Sum256([]byte(v))
+			env[k] = "sha256:" + hex.EncodeToString(sum[:8])
+		}
+		t.Env = env
+	}
+	return t
+}
+
+// collectRunning fills in what a running twin reports. A twin that does
+// not answer its health check is not running; one that answers but fails
+// a later call records the error.
+func collectRunning(ctx context.Context, c *adminclient.Client, t *Twin) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if c.Health(ctx) != nil {
+		return
+	}
+	t.Running = true
+
+	var err error
+	if t.Build, err = c.Version(ctx); err != nil {
+		t.Error = "version: " + err.Error()
+		return
+	}
+	if t.Config, err = c.Config(ctx); err != nil {
+		t.Error = "config: " + err.Error()
+		return
+	}
+	// Without --rand-seed every start draws a new seed; it says nothing
+	// about how the environment is set up.
+	if t.Config["deterministic"] == false {
+		delete(t.Config, "rand_seed")
+	}
+	quirks, err := c.Quirks(ctx)
+	if err != nil {
+		t.Error = "quirks: " + err.Error()
+		return
+	}
+	for _, q := range quirks {
+		if q.Enabled {
+			t.Quirks = append(t.Quirks, q.ID)
+		}
+	}
+	sort.Strings(t.Quirks)
+	faults, err := c.Faults(ctx)
+	if err != nil {
+		t.Error = "faults: " + err.Error()
+		return
+	}
+	for pattern, f := range faults {
+		// Drop what the twin assigns and counts as requests arrive.
+		f.ID, f.Hits, f.LastHitAt = "", 0, nil
+		if t.Faults == nil {
+			t.Faults = map[string]adminclient.Fault{}
+		}
+		t.Faults[pattern] = f
+	}
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteJSON writes env as indented JSON.
+func (e *Env) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// Load reads an export written by WriteJSON.
+func Load(path string) (*Env, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var env Env
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parsing %s: expected the output of wt env export: %w", path, err)
+	}
+	if env.Format != Format {
+		return nil, fmt.Errorf("%s is export format %d; this wt reads format %d", path, env.Format, Format)
+	}
+	return &env, nil
+}
+
+// Diff reports how theirs differs from mine, one line per differing field
+// with its path, e.g. `stripe.config.latency: "0s" != "250ms"`. When the
+// exports are identical apart from when they were taken, it returns nil.
+func Diff(mine, theirs *Env) []string {
+	var out []string
+	if mine.WTVersion != theirs.WTVersion {
+		out = append(out, fmt.Sprintf("wt_version: %q != %q", mine.WTVersion, theirs.WTVersion))
+	}
+	if mine.Platform != theirs.Platform {
+		out = append(out, fmt.Sprintf("platform: %q != %q", mine.Platform, theirs.Platform))
+	}
+	var a, b any
+	toJSON(mine.Twins, &a)
+	toJSON(theirs.Twins, &b)
+	diffJSON("", a, b, &out)
+	return out
+}
+
+// toJSON round-trips v through JSON so both sides compare as generic
+// values.
+func toJSON(v any, out *any) {
+	data, _ := json.Marshal(v)
+	json.Unmarshal(data, out)
+}
+
+func diffJSON(path string, a, b any, out *[]string) {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !aok || !bok {
+		if as, bs := jsonString(a), jsonString(b); as != bs {
+			*out = append(*out, fmt.Sprintf("%s: %s != %s", path, as, bs))
+		}
+		return
+	}
+	keys := make([]string, 0, len(am)+len(bm))
+	for k := range am {
+		keys = append(keys, k)
+	}
+	for k := range bm {
+		if _, ok := am[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		av, aok := am[k]
+		bv, bok := bm[k]
+		switch {
+		case !bok:
+			onlyIn(p, av, path == "", "mine", out)
+		case !aok:
+			onlyIn(p, bv, path == "", "theirs", out)
+		default:
+			diffJSON(p, av, bv, out)
+		}
+	}
+}
+
+// onlyIn reports a field only one side has. Below a twin, a map such as
+// faults is reported entry by entry, so the difference names what it holds.
+func onlyIn(path string, v any, twin bool, side string, out *[]string) {
+	m, ok := v.(map[string]any)
+	if twin || !ok || len(m) == 0 {
+		*out = append(*out, path+": only in "+side)
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		*out = append(*out, path+"."+k+": only in "+side)
+	}
+}
+
+func jsonString(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
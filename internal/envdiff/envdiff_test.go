@@ -0,0 +1,136 @@
+package envdiff
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/lockfile"
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// fakeTwin serves the admin endpoints Collect reads.
+func fakeTwin(t *testing.T) *httptest.Server {
+	t.Helper()
+	responses := map[string]string{
+		"/admin/health":  `{"status":"ok"}`,
+		"/admin/version": `{"name":"twin-stripe","version":"1.4.0"}`,
+		"/admin/config":  `{"latency":"0s","rand_seed":123456,"deterministic":false}`,
+		"/admin/quirks":  `[{"id":"WT-Q-007","enabled":true},{"id":"WT-Q-001","enabled":false}]`,
+		"/admin/faults":  `{"/v1/charges":{"id":"fault_000001","status_code":500,"rate":1,"hits":3}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollect(t *testing.T) {
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "seed.json")
+	os.WriteFile(seed, []byte(`{"accounts":[]}`), 0o644)
+	binary := filepath.Join(dir, "bin", "twin-stripe")
+	os.MkdirAll(filepath.Dir(binary), 0o755)
+	os.WriteFile(binary, []byte("binary"), 0o755)
+
+	srv := fakeTwin(t)
+	port, _ := strconv.Atoi(srv.URL[strings.LastIndex(srv.URL, ":")+1:])
+	m := &manifest.Manifest{Twins: map[string]manifest.Twin{
+		"stripe": {Binary: binary, Port: port, AdminPort: port, Seed: seed, Env: map[string]string{"API_KEY": "sk_secret"}},
+		"resend": {Binary: filepath.Join(dir, "missing"), Port: 1, AdminPort: 1},
+	}}
+	lock := &lockfile.LockFile{Twins: map[string]lockfile.LockedTwin{"stripe": {Version: "1.4.0"}}}
+
+	env := Collect(context.Background(), m, lock, "v0.9.0")
+	stripe := env.Twins["stripe"]
+	switch {
+	case !stripe.Running || stripe.Error != "":
+		t.Fatalf("expected stripe read while running, got %+v", stripe)
+	case stripe.Declared.Binary != "twin-stripe":
+		t.Errorf("expected the binary path reduced to its name, got %q", stripe.Declared.Binary)
+	case !strings.HasPrefix(stripe.Declared.Env["API_KEY"], "sha256:"):
+		t.Errorf("expected env values hashed, got %q", stripe.Declared.Env["API_KEY"])
+	case stripe.LockedVersion != "1.4.0" || stripe.Build.Version != "1.4.0":
+		t.Errorf("expected locked and running version 1.4.0, got %q %+v", stripe.LockedVersion, stripe.Build)
+	case stripe.SeedSHA256 == "" || stripe.BinarySHA256 == "":
+		t.Errorf("expected seed and binary hashes, got %+v", stripe)
+	case !reflect.DeepEqual(stripe.Quirks, []string{"WT-Q-007"}):
+		t.Errorf("expected only enabled quirks, got %v", stripe.Quirks)
+	}
+	if _, ok := stripe.Config["rand_seed"]; ok {
+		t.Error("expected a random rand_seed dropped from config")
+	}
+	if f := stripe.Faults["/v1/charges"]; f.StatusCode != 500 || f.ID != "" || f.Hits != 0 {
+		t.Errorf("expected the fault without its ID and hits, got %+v", f)
+	}
+	if resend := env.Twins["resend"]; resend.Running || resend.BinarySHA256 != "" {
+		t.Errorf("expected resend recorded as not running and not installed, got %+v", resend)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	mine := &Env{Format: Format, WTVersion: "v0.9.0", Platform: "linux/amd64", Twins: map[string]Twin{
+		"stripe": {
+			Declared: manifest.Twin{Version: "1.4.0", Port: 4111},
+			Running:  true,
+			Config:   map[string]any{"latency": "0s"},
+			Quirks:   []string{"WT-Q-007"},
+		},
+		"resend": {Declared: manifest.Twin{Version: "0.3.0", Port: 4112}},
+	}}
+
+	// Round-trip an identical export through JSON, as a teammate's would be.
+	data, _ := json.Marshal(mine)
+	var same Env
+	json.Unmarshal(data, &same)
+	if diffs := Diff(mine, &same); diffs != nil {
+		t.Errorf("expected identical exports to match, got %v", diffs)
+	}
+
+	theirs := &Env{Format: Format, WTVersion: "v0.9.0", Platform: "darwin/arm64", Twins: map[string]Twin{
+		"stripe": {
+			Declared:   manifest.Twin{Version: "1.5.0", Port: 4111},
+			SeedSHA256: "abc",
+			Running:    true,
+			Config:     map[string]any{"latency": "250ms"},
+			Faults:     map[string]adminclient.Fault{"/v1/charges": {StatusCode: 500, Rate: 1}},
+		},
+		"posthog": {Declared: manifest.Twin{Version: "0.1.0", Port: 4113}},
+	}}
+	want := []string{
+		`platform: "linux/amd64" != "darwin/arm64"`,
+		"posthog: only in theirs",
+		"resend: only in mine",
+		`stripe.config.latency: "0s" != "250ms"`,
+		`stripe.declared.version: "1.4.0" != "1.5.0"`,
+		"stripe.faults./v1/charges: only in theirs",
+		`stripe.quirks: only in mine`,
+		"stripe.seed_sha256: only in theirs",
+	}
+	if got := Diff(mine, theirs); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected differences:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestLoadRejectsOtherFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "env.json")
+	os.WriteFile(path, []byte(`{"format":2,"twins":{}}`), 0o644)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "format 2") {
+		t.Errorf("expected a format error, got %v", err)
+	}
+}
@@ -0,0 +1,325 @@
+// Package fixtures turns data recorded from real provider APIs into fixtures
+// and seed files that are safe to commit, for `wt fixtures scrub`.
+package fixtures
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of personal data a Scrubber replaces.
+const (
+	KindEmail = "email"
+	KindName  = "name"
+	KindPhone = "phone"
+	KindCard  = "card"
+	KindToken = "token"
+)
+
+var (
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phoneRe = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	cardRe  = regexp.MustCompile(`^[0-9]{13,19}$`)
+	// secretRe matches credentials recognisable by their value: provider
+	// key prefixes and bearer tokens. Group 1 is the part kept.
+	secretRe = regexp.MustCompile(`^((?:sk|rk|pk)_(?:live|test)_|whsec_|Bearer )(\S+)$`)
+)
+
+// nameKeys are fields holding a person's name whatever object they are in.
+// A plain "name" is only treated as one in an object that also has an
+// email, since products and plans have names too.
+var nameKeys = map[string]string{
+	"first_name": "first", "given_name": "first", "firstname": "first",
+	"last_name": "last", "family_name": "last", "surname": "last", "lastname": "last",
+	"middle_name": "first", "nickname": "first",
+	"full_name": "full", "display_name": "full", "cardholder_name": "full", "billing_name": "full",
+	"username": "username", "user_name": "username",
+}
+
+// tokenKeys are fields holding credentials, in addition to any field
+// ending in _token, _secret, or _password.
+var tokenKeys = map[string]bool{
+	"token": true, "secret": true, "password": true, "api_key": true, "apikey": true,
+	"fingerprint": true, "client_secret": true, "authorization": true,
+}
+
+var (
+	firstNames = []string{"Alex", "Blake", "Casey", "Dana", "Eli", "Frankie", "Gray", "Harper", "Indy", "Jordan", "Kai", "Logan", "Morgan", "Noa", "Parker", "Quinn", "Riley", "Sam", "Taylor", "Val"}
+	lastNames  = []string{"Archer", "Brooks", "Carter", "Dalton", "Ellis", "Finch", "Garner", "Hayes", "Irving", "Jensen", "Keller", "Lane", "Mercer", "Nash", "Oakley", "Porter", "Reed", "Sutton", "Tate", "Wells"}
+)
+
+// Scrubber replaces personal data in JSON documents with deterministic
+// fakes: a value is replaced by the same fake wherever it appears, in every
+// document the Scrubber sees, so references between records still line up.
+// IDs are left alone. Fakes depend on the salt, so different salts give
+// unlinkable output for the same input.
+type Scrubber struct {
+	salt  string
+	fakes map[string]string // kind + "\x00" + original -> fake
+}
+
+// NewScrubber returns a Scrubber whose fakes are derived from salt.
+func NewScrubber(salt string) *Scrubber {
+	return &Scrubber{salt: salt, fakes: map[string]string{}}
+}
+
+// Counts returns how many distinct values of each kind were replaced.
+func (s *Scrubber) Counts() map[string]int {
+	counts := map[string]int{}
+	for k := range s.fakes {
+		kind, _, _ := strings.Cut(k, "\x00")
+		counts[kind]++
+	}
+	return counts
+}
+
+// Scrub returns the JSON document data with personal data replaced,
+// indented with two spaces.
+func (s *Scrubber) Scrub(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	doc = s.value("", doc, nil)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// value scrubs v, found under key in the object parent. Elements of an
+// array keep the key and parent of the array; the top level has neither.
+func (s *Scrubber) value(key string, v any, parent map[string]any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = s.value(k, child, v)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = s.value(key, child, parent)
+		}
+		return v
+	case json.Number:
+		// Card fragments are sometimes numbers.
+		if isLast4Key(key) {
+			n, _ := strconv.Atoi(s.fake(KindCard, v.String(), s.last4))
+			return json.Number(strconv.Itoa(n))
+		}
+		return v
+	case string:
+		return s.str(key, v, parent)
+	}
+	return v
+}
+
+// str scrubs a string field, first by what its key says it holds, then by
+// what it looks like.
+func (s *Scrubber) str(key, v string, parent map[string]any) string {
+	if v == "" {
+		return v
+	}
+	k := strings.ToLower(key)
+	switch {
+	case isTokenKey(k):
+		return s.fake(KindToken, v, s.token)
+	case isLast4Key(k):
+		return s.fake(KindCard, v, s.last4)
+	case nameKeys[k] != "":
+		return s.name(nameKeys[k], v)
+	case k == "name" && hasEmail(parent):
+		return s.name("full", v)
+	case phoneRe.MatchString(v):
+		return s.fake(KindPhone, v, s.phone)
+	case cardRe.MatchString(v) && luhn(v):
+		return s.fake(KindCard, v, s.cardNumber)
+	case secretRe.MatchString(v):
+		return s.fake(KindToken, v, s.token)
+	}
+	// Emails are replaced wherever they appear, including inside text.
+	return emailRe.ReplaceAllStringFunc(v, func(email string) string {
+		return s.fake(KindEmail, strings.ToLower(email), s.email)
+	})
+}
+
+// fake returns the fake for original, generating it with gen the first
+// time original is seen.
+func (s *Scrubber) fake(kind, original string, gen func(original string) string) string {
+	k := kind + "\x00" + original
+	if f, ok := s.fakes[k]; ok {
+		return f
+	}
+	f := gen(original)
+	s.fakes[k] = f
+	return f
+}
+
+// digest returns n hex characters derived from the salt and v.
+func (s *Scrubber) digest(v string, n int) string {
+	var out strings.Builder
+	for i := 0; out.Len() < n; i++ {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", s.salt, i, v)))
+		out.WriteString(hex.EncodeToString(sum[:]))
+	}
+	return out.String()[:n]
+}
+
+// digits returns n decimal digits derived from the salt and v.
+func (s *Scrubber) digits(v string, n int) string {
+	h := s.digest(v, n)
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = '0' + byte(strings.IndexByte("0123456789abcdef", h[i]))%10
+	}
+	return string(out)
+}
+
+func (s *Scrubber) email(original string) string {
+	return "user-" + s.digest(original, 8) + "@example.com"
+}
+
+func (s *Scrubber) phone(original string) string {
+	return "+1555" + s.digits(original, 7)
+}
+
+func (s *Scrubber) last4(original string) string {
+	return s.digits(original, 4)
+}
+
+// cardNumber returns a Luhn-valid number of the original's length.
+func (s *Scrubber) cardNumber(original string) string {
+	body := "4" + s.digits(original, len(original)-2)
+	for d := byte('0'); d <= '9'; d++ {
+		if luhn(body + string(d)) {
+			return body + string(d)
+		}
+	}
+	return body + "0"
+}
+
+// token keeps a recognised prefix such as sk_live_ and replaces the rest
+// with hex of the same length.
+func (s *Scrubber) token(original string) string {
+	prefix, rest := "", original
+	if m := secretRe.FindStringSubmatch(original); m != nil {
+		prefix, rest = m[1], m[2]
+	}
+	return prefix + s.digest(original, len(rest))
+}
+
+// name returns the fake for original as the given part of a name:
+// "first", "last", "full", or "username".
+func (s *Scrubber) name(part, original string) string {
+	return s.fake(KindName, part+"\x00"+original, func(original string) string {
+		n, _ := strconv.Atoi(s.digits(original, 4))
+		first := firstNames[n%len(firstNames)]
+		last := lastNames[n/100%len(lastNames)]
+		switch part {
+		case "first":
+			return first
+		case "last":
+			return last
+		case "username":
+			return strings.ToLower(first) + "_" + s.digest(original, 6)
+		}
+		return first + " " + last
+	})
+}
+
+func isTokenKey(k string) bool {
+	return tokenKeys[k] || strings.HasSuffix(k, "_token") || strings.HasSuffix(k, "_secret") || strings.HasSuffix(k, "_password")
+}
+
+func isLast4Key(k string) bool {
+	k = strings.ToLower(k)
+	return k == "last4" || strings.HasSuffix(k, "_last4")
+}
+
+// hasEmail reports whether obj has a field holding an email address.
+func hasEmail(obj map[string]any) bool {
+	for _, v := range obj {
+		if str, ok := v.(string); ok && emailRe.MatchString(str) {
+			return true
+		}
+	}
+	return false
+}
+
+// luhn reports whether the digit string passes the Luhn checksum.
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// ScrubPath scrubs the JSON file in, or every .json file under the
+// directory in, writing the results to out at the same relative paths.
+// It returns the files written.
+func (s *Scrubber) ScrubPath(in, out string) ([]string, error) {
+	info, err := os.Stat(in)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{out}, s.scrubFile(in, out)
+	}
+
+	var files []string
+	err = filepath.WalkDir(in, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return err
+		}
+		rel, err := filepath.Rel(in, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(out, rel)
+		files = append(files, dest)
+		return s.scrubFile(path, dest)
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+func (s *Scrubber) scrubFile(in, out string) error {
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	scrubbed, err := s.Scrub(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(out, scrubbed, 0o644)
+}
@@ -0,0 +1,123 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const recorded = `{
+  "customers": [
+    {"id": "cus_1", "email": "Jane.Doe@acme.io", "name": "Jane Doe", "phone": "+14155550123",
+     "description": "VIP, contact jane.doe@acme.io <ops>"},
+    {"id": "cus_2", "email": "bob@acme.io", "name": "Bob Smith"}
+  ],
+  "charges": [
+    {"id": "ch_1", "customer": "cus_1", "receipt_email": "jane.doe@acme.io",
+     "card": {"last4": "4242", "fingerprint": "Xt5EWLLDS7FJjR1c", "number": "4111111111111111", "exp_year": 2030}},
+    {"id": "ch_2", "customer": "cus_2", "receipt_email": "bob@acme.io", "card": {"last4": 1881}}
+  ],
+  "plans": [{"id": "gold", "name": "Gold plan", "amount": 1999}],
+  "settings": {"api_key": "sk_live_51HqLyjWDarjtT1zdp7dc", "webhook_secret": "whsec_abc123", "first_name": "Jane"}
+}`
+
+func scrub(t *testing.T, s *Scrubber, data string) map[string]any {
+	t.Helper()
+	out, err := s.Scrub([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("scrubbed output is not JSON: %v\n%s", err, out)
+	}
+	return doc
+}
+
+func TestScrubReplacesPII(t *testing.T) {
+	s := NewScrubber("")
+	out, err := s.Scrub([]byte(recorded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"acme.io", "Jane", "Doe", "Bob", "Smith", "4155550123", "Xt5EWLLDS7FJjR1c", "4111111111111111", "51HqLyjWDarjtT1zdp7dc", "abc123"} {
+		if strings.Contains(string(out), leaked) {
+			t.Errorf("scrubbed output still contains %q:\n%s", leaked, out)
+		}
+	}
+	for _, kept := range []string{`"cus_1"`, `"customer": "cus_2"`, `"Gold plan"`, `"exp_year": 2030`, `"sk_live_`, `"whsec_`, "<ops>"} {
+		if !strings.Contains(string(out), kept) {
+			t.Errorf("scrubbed output lost %s:\n%s", kept, out)
+		}
+	}
+
+	counts := s.Counts()
+	if counts[KindEmail] != 2 || counts[KindPhone] != 1 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+}
+
+func TestScrubKeepsReferences(t *testing.T) {
+	doc := scrub(t, NewScrubber(""), recorded)
+	customers := doc["customers"].([]any)
+	charges := doc["charges"].([]any)
+	jane := customers[0].(map[string]any)
+	if got := charges[0].(map[string]any)["receipt_email"]; got != jane["email"] {
+		t.Errorf("expected the same email on the customer and the charge, got %v and %v", jane["email"], got)
+	}
+	if !strings.Contains(jane["description"].(string), jane["email"].(string)) {
+		t.Errorf("expected the email inside text to get the same fake, got %q", jane["description"])
+	}
+
+	card := charges[0].(map[string]any)["card"].(map[string]any)
+	if number := card["number"].(string); len(number) != 16 || !luhn(number) {
+		t.Errorf("expected a Luhn-valid 16-digit card number, got %q", number)
+	}
+	if last4 := card["last4"]; last4 == "4242" || len(last4.(string)) != 4 {
+		t.Errorf("expected last4 replaced with 4 digits, got %v", last4)
+	}
+	if last4, ok := charges[1].(map[string]any)["card"].(map[string]any)["last4"].(float64); !ok || last4 == 1881 {
+		t.Errorf("expected a numeric last4 replaced with a number, got %v", last4)
+	}
+}
+
+func TestScrubDeterministic(t *testing.T) {
+	a := scrub(t, NewScrubber(""), recorded)
+	b := scrub(t, NewScrubber(""), recorded)
+	c := scrub(t, NewScrubber("other"), recorded)
+	email := func(doc map[string]any) any { return doc["customers"].([]any)[0].(map[string]any)["email"] }
+	if email(a) != email(b) {
+		t.Errorf("expected the same fake across runs, got %v and %v", email(a), email(b))
+	}
+	if email(a) == email(c) {
+		t.Errorf("expected a different salt to give a different fake, got %v twice", email(a))
+	}
+}
+
+func TestScrubPathDirectory(t *testing.T) {
+	in, out := t.TempDir(), t.TempDir()
+	os.MkdirAll(filepath.Join(in, "stripe"), 0o755)
+	os.WriteFile(filepath.Join(in, "stripe", "seed.json"), []byte(`{"email": "jane@acme.io"}`), 0o644)
+	os.WriteFile(filepath.Join(in, "clerk.json"), []byte(`[{"email_address": "jane@acme.io"}]`), 0o644)
+	os.WriteFile(filepath.Join(in, "notes.txt"), []byte("jane@acme.io"), 0o644)
+
+	s := NewScrubber("")
+	files, err := s.ScrubPath(in, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files written, got %v", files)
+	}
+	stripe, _ := os.ReadFile(filepath.Join(out, "stripe", "seed.json"))
+	clerk, _ := os.ReadFile(filepath.Join(out, "clerk.json"))
+	fake := s.fakes[KindEmail+"\x00jane@acme.io"]
+	if fake == "" || !strings.Contains(string(stripe), fake) || !strings.Contains(string(clerk), fake) {
+		t.Errorf("expected %q in both files, got %s and %s", fake, stripe, clerk)
+	}
+	if _, err := os.Stat(filepath.Join(out, "notes.txt")); err == nil {
+		t.Error("expected non-JSON files to be skipped")
+	}
+}
@@ -0,0 +1,224 @@
+// Package graph renders the topology of a manifest for `wt graph`: its
+// twins, the twins and outside services each one is pointed at through its
+// env, and where each delivers webhooks, as a Graphviz DOT or Mermaid
+// diagram.
+package graph
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// Edge kinds.
+const (
+	// EdgeDepends is a twin whose env points at another twin of the
+	// manifest, so it needs that twin running.
+	EdgeDepends = "depends"
+	// EdgePeer is a twin whose env points at a service outside the
+	// manifest.
+	EdgePeer = "peer"
+	// EdgeWebhook is where a twin's config.webhook_url delivers webhooks.
+	EdgeWebhook = "webhook"
+)
+
+// Node is a twin of the manifest, or a service outside it that a twin is
+// pointed at.
+type Node struct {
+	ID    string // the twin's name, or ext1, ext2, ... for outside services
+	Label string
+	Twin  bool
+}
+
+// Edge connects two nodes by ID.
+type Edge struct {
+	From, To string
+	Kind     string // EdgeDepends, EdgePeer, or EdgeWebhook
+	Label    string // the env variable, or the webhook path
+}
+
+// Graph is a manifest's topology. Nodes lists the twins by name, then the
+// outside services in the order they were found.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Build derives the graph of m. Env values are read as URLs; one whose
+// host and port are a twin's API or admin address is a dependency on that
+// twin, and any other http(s) URL a peer.
+func Build(m *manifest.Manifest) *Graph {
+	g := &Graph{}
+	names := m.TwinNames()
+	for _, name := range names {
+		twin := m.Twins[name]
+		label := name
+		if twin.Version != "" {
+			label += "@" + twin.Version
+		}
+		g.Nodes = append(g.Nodes, Node{ID: name, Label: label + "\n" + twin.Addr(), Twin: true})
+	}
+
+	external := map[string]string{} // scheme://host:port, loopback as localhost → node ID
+	next := 0                       // last extN used; N skips twin names
+	target := func(raw string) (id, path string, ok bool) {
+		if name := twinAt(m, raw); name != "" {
+			return name, "", true
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", "", false
+		}
+		key := u.Scheme + "://" + hostPort(u)
+		id, seen := external[key]
+		if !seen {
+			for taken := true; taken; _, taken = m.Twins[id] {
+				next++
+				id = fmt.Sprintf("ext%d", next)
+			}
+			external[key] = id
+			g.Nodes = append(g.Nodes, Node{ID: id, Label: u.Scheme + "://" + u.Host})
+		}
+		return id, u.Path, true
+	}
+
+	for _, name := range names {
+		twin := m.Twins[name]
+		keys := make([]string, 0, len(twin.Env))
+		for k := range twin.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			to, _, ok := target(twin.Env[k])
+			if !ok || to == name {
+				continue
+			}
+			kind := EdgePeer
+			if _, isTwin := m.Twins[to]; isTwin {
+				kind = EdgeDepends
+			}
+			g.Edges = append(g.Edges, Edge{From: name, To: to, Kind: kind, Label: k})
+		}
+		if twin.Config != nil && twin.Config.WebhookURL != "" {
+			if to, path, ok := target(twin.Config.WebhookURL); ok {
+				label := "webhooks"
+				if path != "" && path != "/" {
+					label += " " + path
+				}
+				g.Edges = append(g.Edges, Edge{From: name, To: to, Kind: EdgeWebhook, Label: label})
+			}
+		}
+	}
+	return g
+}
+
+// twinAt returns the twin of m that serves raw, a URL, or "" if none does.
+// Loopback hosts are treated as one, so http://127.0.0.1:4111 is the twin
+// on localhost:4111.
+func twinAt(m *manifest.Manifest, raw string) string {
+	for _, name := range m.TwinNames() {
+		twin := m.Twins[name]
+		for _, base := range []string{twin.BaseURL(), twin.AdminURL()} {
+			if raw == base || strings.HasPrefix(raw, strings.TrimSuffix(base, "/")+"/") {
+				return name
+			}
+		}
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	addr := hostPort(u)
+	for _, name := range m.TwinNames() {
+		twin := m.Twins[name]
+		for _, base := range []string{twin.BaseURL(), twin.AdminURL()} {
+			if b, err := url.Parse(base); err == nil && b.Host != "" && hostPort(b) == addr {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// hostPort returns u's host and port, with the scheme's default port filled
+// in and loopback hosts spelled localhost.
+func hostPort(u *url.URL) string {
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// WriteDOT writes g as a Graphviz digraph.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph wondertwin {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		if n.Twin {
+			fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(n.ID), dotQuote(n.Label))
+		} else {
+			fmt.Fprintf(&b, "  %s [label=%s, shape=ellipse, style=dashed];\n", dotQuote(n.ID), dotQuote(n.Label))
+		}
+	}
+	for _, e := range g.Edges {
+		style := ""
+		if e.Kind != EdgeDepends {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=%s%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Label), style)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes g as a Mermaid flowchart, which GitHub renders in
+// Markdown files and pull requests.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	ids := make(map[string]string, len(g.Nodes)) // node ID → Mermaid ID
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		if n.Twin {
+			fmt.Fprintf(&b, "  %s[%s]\n", ids[n.ID], mermaidQuote(n.Label))
+		} else {
+			fmt.Fprintf(&b, "  %s([%s])\n", ids[n.ID], mermaidQuote(n.Label))
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-- %s -->"
+		if e.Kind != EdgeDepends {
+			arrow = "-. %s .->"
+		}
+		fmt.Fprintf(&b, "  %s "+arrow+" %s\n", ids[e.From], mermaidQuote(e.Label), ids[e.To])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes s as a DOT string; a newline becomes a line break.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// mermaidQuote quotes s as a Mermaid label; a newline becomes a line break.
+func mermaidQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	return `"` + strings.ReplaceAll(s, "\n", "<br/>") + `"`
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+func testManifest() *manifest.Manifest {
+	return &manifest.Manifest{Twins: map[string]manifest.Twin{
+		"stripe": {Port: 4111, AdminPort: 4111, Version: "1.2.0", Config: &manifest.TwinConfig{WebhookURL: "http://localhost:3000/webhooks/stripe"}},
+		"clerk": {Port: 4113, AdminPort: 4113, Config: &manifest.TwinConfig{WebhookURL: "http://127.0.0.1:3000/webhooks/clerk"}, Env: map[string]string{
+			"STRIPE_API_BASE": "http://127.0.0.1:4111/v1",
+			"SVIX_URL":        "https://api.svix.example.com",
+			"CLERK_MODE":      "test",
+		}},
+		"twilio": {URL: "https://twilio.twins.example.com"},
+	}}
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(testManifest())
+
+	wantNodes := []Node{
+		{ID: "clerk", Label: "clerk\nlocalhost:4113", Twin: true},
+		{ID: "stripe", Label: "stripe@1.2.0\nlocalhost:4111", Twin: true},
+		{ID: "twilio", Label: "twilio\nhttps://twilio.twins.example.com", Twin: true},
+		{ID: "ext1", Label: "https://api.svix.example.com"},
+		{ID: "ext2", Label: "http://127.0.0.1:3000"},
+	}
+	if !reflect.DeepEqual(g.Nodes, wantNodes) {
+		t.Errorf("nodes = %+v\nwant %+v", g.Nodes, wantNodes)
+	}
+	wantEdges := []Edge{
+		{From: "clerk", To: "stripe", Kind: EdgeDepends, Label: "STRIPE_API_BASE"},
+		{From: "clerk", To: "ext1", Kind: EdgePeer, Label: "SVIX_URL"},
+		{From: "clerk", To: "ext2", Kind: EdgeWebhook, Label: "webhooks /webhooks/clerk"},
+		{From: "stripe", To: "ext2", Kind: EdgeWebhook, Label: "webhooks /webhooks/stripe"},
+	}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Errorf("edges = %+v\nwant %+v", g.Edges, wantEdges)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	g := Build(testManifest())
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"stripe" [label="stripe@1.2.0\nlocalhost:4111"];`,
+		`"clerk" -> "stripe" [label="STRIPE_API_BASE"];`,
+		`"stripe" -> "ext2" [label="webhooks /webhooks/stripe", style=dashed];`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("expected %s in DOT output:\n%s", want, dot.String())
+		}
+	}
+
+	var mermaid bytes.Buffer
+	if err := g.WriteMermaid(&mermaid); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n1["stripe@1.2.0<br/>localhost:4111"]`,
+		`n3(["https://api.svix.example.com"])`,
+		`n0 -- "STRIPE_API_BASE" --> n1`,
+		`n1 -. "webhooks /webhooks/stripe" .-> n4`,
+	} {
+		if !strings.Contains(mermaid.String(), want) {
+			t.Errorf("expected %s in Mermaid output:\n%s", want, mermaid.String())
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package incident applies and undoes the twin-side half of an incident
+// pack for `wt chaos replay`. Before an incident is applied, Take records
+// how each twin it touches behaves — runtime config, enabled quirks,
+// faults, and storage failures — and Restore puts that back afterwards, so
+// a rehearsal leaves the environment as it found it. Twin state (records,
+// the simulated clock) is not part of the snapshot.
+package incident
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// readOnlyConfig are config keys GET /admin/config reports but PUT
+// rejects.
+var readOnlyConfig = map[string]bool{"name": true, "port": true, "host": true, "socket": true, "admin_readonly": true, "deterministic": true}
+
+// Snapshot is how a set of twins behaved before an incident.
+type Snapshot struct {
+	twins map[string]*twinSnapshot
+}
+
+type twinSnapshot struct {
+	client  *adminclient.Client
+	config  map[string]any
+	quirks  map[string]bool // quirk ID → enabled
+	faults  map[string]adminclient.Fault
+	storage *adminclient.StorageFailures
+}
+
+// Take records the behavior of the named twins of m. Every twin must be
+// running.
+func Take(ctx context.Context, m *manifest.Manifest, names []string) (*Snapshot, error) {
+	s := &Snapshot{twins: map[string]*twinSnapshot{}}
+	for _, name := range names {
+		twin, err := m.Twin(name)
+		if err != nil {
+			return nil, err
+		}
+		ts := &twinSnapshot{client: adminclient.New(twin.AdminURL()), quirks: map[string]bool{}}
+		if err := ts.read(ctx); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		s.twins[name] = ts
+	}
+	return s, nil
+}
+
+func (ts *twinSnapshot) read(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var err error
+	if ts.config, err = ts.client.Config(ctx); err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	quirks, err := ts.client.Quirks(ctx)
+	if err != nil {
+		return fmt.Errorf("reading quirks: %w", err)
+	}
+	for _, q := range quirks {
+		ts.quirks[q.ID] = q.Enabled
+	}
+	if ts.faults, err = ts.client.Faults(ctx); err != nil {
+		return fmt.Errorf("reading faults: %w", err)
+	}
+	// Twins without a store have no storage failures to restore.
+	ts.storage, _ = ts.client.StorageFailures(ctx)
+	return nil
+}
+
+// Restore puts every twin back as Take found it: config values that
+// changed, quirks, faults, and storage failures. It carries on past a twin
+// that fails and returns one error per twin.
+func (s *Snapshot) Restore(ctx context.Context) []error {
+	names := make([]string, 0, len(s.twins))
+	for name := range s.twins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		if err := s.twins[name].restore(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+func (ts *twinSnapshot) restore(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	c := ts.client
+
+	now, err := c.Config(ctx)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	changed := map[string]any{}
+	for k, v := range ts.config {
+		if !readOnlyConfig[k] && !sameJSON(v, now[k]) {
+			changed[k] = v
+		}
+	}
+	if len(changed) > 0 {
+		if _, err := c.UpdateConfig(ctx, changed); err != nil {
+			return fmt.Errorf("restoring config: %w", err)
+		}
+	}
+
+	quirks, err := c.Quirks(ctx)
+	if err != nil {
+		return fmt.Errorf("reading quirks: %w", err)
+	}
+	for _, q := range quirks {
+		switch was := ts.quirks[q.ID]; {
+		case q.Enabled && !was:
+			err = c.DisableQuirk(ctx, q.ID)
+		case !q.Enabled && was:
+			err = c.EnableQuirk(ctx, q.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("restoring quirk %s: %w", q.ID, err)
+		}
+	}
+
+	faults, err := c.Faults(ctx)
+	if err != nil {
+		return fmt.Errorf("reading faults: %w", err)
+	}
+	for endpoint, f := range faults {
+		before, ok := ts.faults[endpoint]
+		switch {
+		case !ok:
+			err = c.RemoveFault(ctx, endpoint)
+		case f.ID != before.ID:
+			// The incident replaced a fault that was already there.
+			before.ID, before.Hits, before.LastHitAt = "", 0, nil
+			err = c.InjectFault(ctx, endpoint, before)
+		}
+		if err != nil {
+			return fmt.Errorf("restoring fault on %s: %w", endpoint, err)
+		}
+	}
+
+	if ts.storage != nil {
+		now, err := c.StorageFailures(ctx)
+		if err != nil {
+			return fmt.Errorf("reading storage failures: %w", err)
+		}
+		if !reflect.DeepEqual(now.StorageFailureConfig, ts.storage.StorageFailureConfig) {
+			if ts.storage.Rate == 0 {
+				err = c.ClearStorageFailures(ctx)
+			} else {
+				_, err = c.SetStorageFailures(ctx, ts.storage.StorageFailureConfig)
+			}
+			if err != nil {
+				return fmt.Errorf("restoring storage failures: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyChaos puts a twin into one of manifest.ChaosProfiles, as the chaos
+// setting of its manifest config would.
+func ApplyChaos(ctx context.Context, m *manifest.Manifest, name, profile string) error {
+	twin, err := m.Twin(name)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	c := adminclient.New(twin.AdminURL())
+	cfg := &manifest.TwinConfig{Chaos: profile}
+	if settings := cfg.Settings(); len(settings) > 0 {
+		if _, err := c.UpdateConfig(ctx, settings); err != nil {
+			return fmt.Errorf("%s: updating config: %w", name, err)
+		}
+	}
+	if rate := cfg.StorageFailureRate(); rate > 0 {
+		if _, err := c.SetStorageFailures(ctx, adminclient.StorageFailureConfig{Rate: rate, Ops: []string{"write"}}); err != nil {
+			return fmt.Errorf("%s: setting storage failures: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func sameJSON(a, b any) bool {
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return string(aj) == string(bj)
+}
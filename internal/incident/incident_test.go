@@ -0,0 +1,141 @@
+package incident
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// fakeTwin keeps the admin state Take and Restore read and write.
+type fakeTwin struct {
+	mu      sync.Mutex
+	config  map[string]any
+	quirks  map[string]bool
+	faults  map[string]adminclient.Fault
+	storage adminclient.StorageFailureConfig
+	nextID  int
+}
+
+func (f *fakeTwin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := r.URL.Path
+	var out any = map[string]string{"status": "ok"}
+	switch {
+	case path == "/admin/config" && r.Method == http.MethodGet:
+		out = f.config
+	case path == "/admin/config":
+		var updates map[string]any
+		json.NewDecoder(r.Body).Decode(&updates)
+		for k, v := range updates {
+			if readOnlyConfig[k] {
+				http.Error(w, `{"error":"read-only"}`, http.StatusBadRequest)
+				return
+			}
+			f.config[k] = v
+		}
+		out = map[string]any{"config": f.config}
+	case path == "/admin/quirks":
+		var quirks []adminclient.Quirk
+		for id, on := range f.quirks {
+			quirks = append(quirks, adminclient.Quirk{ID: id, Enabled: on})
+		}
+		out = quirks
+	case strings.HasPrefix(path, "/admin/quirks/"):
+		f.quirks[strings.TrimPrefix(path, "/admin/quirks/")] = r.Method == http.MethodPut
+	case path == "/admin/faults":
+		out = f.faults
+	case path == "/admin/faults/storage" && r.Method == http.MethodGet:
+		out = adminclient.StorageFailures{StorageFailureConfig: f.storage}
+	case path == "/admin/faults/storage" && r.Method == http.MethodPut:
+		json.NewDecoder(r.Body).Decode(&f.storage)
+		out = adminclient.StorageFailures{StorageFailureConfig: f.storage}
+	case path == "/admin/faults/storage":
+		f.storage = adminclient.StorageFailureConfig{}
+	case strings.HasPrefix(path, "/admin/fault/"):
+		endpoint := "/" + strings.TrimPrefix(path, "/admin/fault/")
+		if r.Method == http.MethodDelete {
+			delete(f.faults, endpoint)
+			break
+		}
+		var fault adminclient.Fault
+		json.NewDecoder(r.Body).Decode(&fault)
+		f.nextID++
+		fault.ID = "fault_" + strconv.Itoa(f.nextID)
+		f.faults[endpoint] = fault
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	twin := &fakeTwin{
+		config: map[string]any{"name": "twin-stripe", "latency": "0s", "fail_rate": 0.0, "list_lag": "2s"},
+		quirks: map[string]bool{"WT-Q-001": true, "WT-Q-007": false},
+		faults: map[string]adminclient.Fault{"/v1/refunds": {ID: "fault_0", StatusCode: 500, Rate: 1}},
+	}
+	srv := httptest.NewServer(twin)
+	defer srv.Close()
+	port, _ := strconv.Atoi(srv.URL[strings.LastIndex(srv.URL, ":")+1:])
+	m := &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": {Port: port, AdminPort: port}}}
+	ctx := context.Background()
+
+	snap, err := Take(ctx, m, []string{"stripe"})
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+
+	// The incident: a chaos profile, a new fault and a replaced one, a
+	// config change, and quirks flipped.
+	if err := ApplyChaos(ctx, m, "stripe", "degraded"); err != nil {
+		t.Fatalf("ApplyChaos: %v", err)
+	}
+	if err := ApplyChaos(ctx, m, "stripe", "storage-flaky"); err != nil {
+		t.Fatalf("ApplyChaos: %v", err)
+	}
+	if twin.config["latency"] != "500ms" || twin.config["fail_rate"] != 0.1 || twin.storage.Rate != 0.05 {
+		t.Fatalf("expected the chaos profiles applied, got %v and %+v", twin.config, twin.storage)
+	}
+	c := adminclient.New(srv.URL)
+	c.InjectFault(ctx, "/v1/charges", adminclient.Fault{StatusCode: 503, Rate: 1})
+	c.InjectFault(ctx, "/v1/refunds", adminclient.Fault{StatusCode: 429, Rate: 1})
+	c.UpdateConfig(ctx, map[string]any{"list_lag": "10s"})
+	c.DisableQuirk(ctx, "WT-Q-001")
+	c.EnableQuirk(ctx, "WT-Q-007")
+
+	if errs := snap.Restore(ctx); errs != nil {
+		t.Fatalf("Restore: %v", errs)
+	}
+	if twin.config["latency"] != "0s" || twin.config["fail_rate"] != 0.0 || twin.config["list_lag"] != "2s" {
+		t.Errorf("expected the config restored, got %v", twin.config)
+	}
+	if !twin.quirks["WT-Q-001"] || twin.quirks["WT-Q-007"] {
+		t.Errorf("expected the quirks restored, got %v", twin.quirks)
+	}
+	if _, ok := twin.faults["/v1/charges"]; ok || twin.faults["/v1/refunds"].StatusCode != 500 || len(twin.faults) != 1 {
+		t.Errorf("expected only the original fault left, got %+v", twin.faults)
+	}
+	if twin.storage.Rate != 0 {
+		t.Errorf("expected storage failures cleared, got %+v", twin.storage)
+	}
+}
+
+func TestTakeRequiresRunningTwins(t *testing.T) {
+	m := &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": {Port: 1, AdminPort: 1}}}
+	if _, err := Take(context.Background(), m, []string{"stripe"}); err == nil || !strings.HasPrefix(err.Error(), "stripe: ") {
+		t.Errorf("expected an error naming the twin, got %v", err)
+	}
+	if _, err := Take(context.Background(), m, []string{"resend"}); err == nil {
+		t.Error("expected an error for a twin the manifest does not declare")
+	}
+}
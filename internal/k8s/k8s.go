@@ -0,0 +1,310 @@
+// Package k8s converts a wondertwin manifest into Kubernetes resources, so a
+// shared twin environment in a cluster runs from the same source of truth as
+// local development.
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// DefaultImage is the image template used when Options.Image is empty.
+// {name} is the twin name and {version} its manifest version, or "latest"
+// for twins pinned by binary path.
+const DefaultImage = "twin-{name}:{version}"
+
+// maxConfigMapBytes is the most data a ConfigMap can hold.
+const maxConfigMapBytes = 1 << 20
+
+// Options controls resource generation.
+type Options struct {
+	// Namespace is set on every resource when non-empty.
+	Namespace string
+	// Image is the container image template (see DefaultImage).
+	Image string
+}
+
+// Generate returns a multi-document YAML stream with, for each twin in
+// sorted order: a ConfigMap holding its seed data (if it has any), a
+// Deployment, a Service for the API port, and a Service exposing admin_port.
+// Twins serve /admin on their API port, so both Services target it.
+// Deployments run a single replica, since twin state lives in memory and
+// replicas would diverge. Remote twins, declared by url, are already hosted
+// and are skipped.
+func Generate(m *manifest.Manifest, opts Options) ([]byte, error) {
+	if opts.Image == "" {
+		opts.Image = DefaultImage
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by wt k8s generate. Edit the wondertwin manifest and regenerate.\n")
+	for _, name := range m.TwinNames() {
+		if m.Twins[name].Remote() {
+			continue
+		}
+		objs, err := twinObjects(name, m.Twins[name], opts)
+		if err != nil {
+			return nil, fmt.Errorf("twin %q: %w", name, err)
+		}
+		for _, obj := range objs {
+			buf.WriteString("---\n")
+			enc := yaml.NewEncoder(&buf)
+			enc.SetIndent(2)
+			if err := enc.Encode(obj); err != nil {
+				return nil, fmt.Errorf("twin %q: encoding %s: %w", name, obj.Kind, err)
+			}
+			enc.Close()
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func twinObjects(name string, twin manifest.Twin, opts Options) ([]object, error) {
+	if twin.Socket != "" {
+		return nil, fmt.Errorf("twin %q listens on a unix socket, which a Service cannot expose; give it a port", name)
+	}
+	base := resourceName(name)
+	labels := map[string]string{
+		"app.kubernetes.io/name":       base,
+		"app.kubernetes.io/part-of":    "wondertwin",
+		"app.kubernetes.io/managed-by": "wt",
+	}
+	selector := map[string]string{"app.kubernetes.io/name": base}
+	meta := func(n string) metadata {
+		return metadata{Name: n, Namespace: opts.Namespace, Labels: labels}
+	}
+
+	version := twin.Version
+	if version == "" {
+		version = "latest"
+	}
+	image := strings.NewReplacer("{name}", name, "{version}", version).Replace(opts.Image)
+
+	args := []string{"--port", strconv.Itoa(twin.Port)}
+	if twin.Latency != "" {
+		args = append(args, "--latency", twin.Latency)
+	}
+	if twin.FailRate > 0 {
+		args = append(args, "--fail-rate", strconv.FormatFloat(twin.FailRate, 'f', -1, 64))
+	}
+	if twin.RandSeed != 0 {
+		args = append(args, "--rand-seed", strconv.FormatUint(twin.RandSeed, 10))
+	}
+	if twin.AdminReadOnly {
+		args = append(args, "--admin-readonly")
+	}
+	args = append(args, twin.Limits.StoreArgs()...)
+
+	c := container{
+		Name:  "twin",
+		Image: image,
+		Args:  args,
+		Ports: []containerPort{{Name: "http", ContainerPort: twin.Port}},
+		// /healthz bypasses latency and fail_rate, so probes stay green
+		// however the twin is configured.
+		ReadinessProbe: &probe{HTTPGet: httpGet{Path: "/healthz", Port: "http"}, PeriodSeconds: 5},
+		LivenessProbe:  &probe{HTTPGet: httpGet{Path: "/healthz", Port: "http"}, PeriodSeconds: 10},
+	}
+	envKeys := make([]string, 0, len(twin.Env))
+	for k := range twin.Env {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		c.Env = append(c.Env, envVar{Name: k, Value: twin.Env[k]})
+	}
+	if l := twin.Limits; l != nil && l.MemoryMB > 0 {
+		c.Resources = &resources{Limits: map[string]string{"memory": fmt.Sprintf("%dMi", l.MemoryMB)}}
+	}
+
+	var objs []object
+	pod := podSpec{Containers: []container{c}}
+	if twin.Seed != "" {
+		// Seed paths resolve like wt up does: relative to the working directory
+		seedPath, err := filepath.Abs(twin.Seed)
+		if err != nil {
+			return nil, fmt.Errorf("resolving seed path: %w", err)
+		}
+		data, err := os.ReadFile(seedPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading seed: %w", err)
+		}
+		if len(data) > maxConfigMapBytes {
+			return nil, fmt.Errorf("seed %s is %d bytes; a ConfigMap holds at most 1 MiB", twin.Seed, len(data))
+		}
+		cm := base + "-seed"
+		objs = append(objs, object{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata:   meta(cm),
+			Data:       map[string]string{"seed.json": string(data)},
+		})
+		pod.Containers[0].Args = append(pod.Containers[0].Args, "--seed-file", "/etc/wondertwin/seed/seed.json")
+		pod.Containers[0].VolumeMounts = []volumeMount{{Name: "seed", MountPath: "/etc/wondertwin/seed", ReadOnly: true}}
+		pod.Volumes = []volume{{Name: "seed", ConfigMap: &configMapVolume{Name: cm}}}
+	}
+
+	objs = append(objs,
+		object{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Metadata:   meta(base),
+			Spec: deploymentSpec{
+				Replicas: 1,
+				Selector: labelSelector{MatchLabels: selector},
+				Strategy: deploymentStrategy{Type: "Recreate"},
+				Template: podTemplate{Metadata: metadata{Labels: labels}, Spec: pod},
+			},
+		},
+		object{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   meta(base),
+			Spec: serviceSpec{
+				Selector: selector,
+				Ports:    []servicePort{{Name: "http", Port: twin.Port, TargetPort: "http"}},
+			},
+		},
+		object{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   meta(base + "-admin"),
+			Spec: serviceSpec{
+				Selector: selector,
+				Ports:    []servicePort{{Name: "admin", Port: twin.AdminPort, TargetPort: "http"}},
+			},
+		},
+	)
+	return objs, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// resourceName turns a twin name into a valid Kubernetes resource name
+// (lowercase RFC 1123 label).
+func resourceName(name string) string {
+	n := invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	n = strings.Trim(n, "-")
+	if len(n) > 50 {
+		// Leave room for the -admin and -seed suffixes within 63 characters
+		n = strings.TrimRight(n[:50], "-")
+	}
+	if n == "" {
+		n = "twin"
+	}
+	return n
+}
+
+// The types below are the subset of the Kubernetes API that Generate emits,
+// declared in the order kubectl prints fields.
+
+type object struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   metadata          `yaml:"metadata"`
+	Spec       any               `yaml:"spec,omitempty"`
+	Data       map[string]string `yaml:"data,omitempty"`
+}
+
+type metadata struct {
+	Name      string            `yaml:"name,omitempty"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+type deploymentSpec struct {
+	Replicas int                `yaml:"replicas"`
+	Selector labelSelector      `yaml:"selector"`
+	Strategy deploymentStrategy `yaml:"strategy"`
+	Template podTemplate        `yaml:"template"`
+}
+
+type labelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type deploymentStrategy struct {
+	Type string `yaml:"type"`
+}
+
+type podTemplate struct {
+	Metadata metadata `yaml:"metadata"`
+	Spec     podSpec  `yaml:"spec"`
+}
+
+type podSpec struct {
+	Containers []container `yaml:"containers"`
+	Volumes    []volume    `yaml:"volumes,omitempty"`
+}
+
+type container struct {
+	Name           string          `yaml:"name"`
+	Image          string          `yaml:"image"`
+	Args           []string        `yaml:"args,omitempty"`
+	Env            []envVar        `yaml:"env,omitempty"`
+	Ports          []containerPort `yaml:"ports"`
+	ReadinessProbe *probe          `yaml:"readinessProbe,omitempty"`
+	LivenessProbe  *probe          `yaml:"livenessProbe,omitempty"`
+	Resources      *resources      `yaml:"resources,omitempty"`
+	VolumeMounts   []volumeMount   `yaml:"volumeMounts,omitempty"`
+}
+
+type envVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type containerPort struct {
+	Name          string `yaml:"name"`
+	ContainerPort int    `yaml:"containerPort"`
+}
+
+type probe struct {
+	HTTPGet       httpGet `yaml:"httpGet"`
+	PeriodSeconds int     `yaml:"periodSeconds,omitempty"`
+}
+
+type httpGet struct {
+	Path string `yaml:"path"`
+	Port string `yaml:"port"`
+}
+
+type resources struct {
+	Limits map[string]string `yaml:"limits,omitempty"`
+}
+
+type volumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+type volume struct {
+	Name      string           `yaml:"name"`
+	ConfigMap *configMapVolume `yaml:"configMap,omitempty"`
+}
+
+type configMapVolume struct {
+	Name string `yaml:"name"`
+}
+
+type serviceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []servicePort     `yaml:"ports"`
+}
+
+type servicePort struct {
+	Name       string `yaml:"name"`
+	Port       int    `yaml:"port"`
+	TargetPort string `yaml:"targetPort"`
+}
@@ -0,0 +1,181 @@
+package k8s
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+func loadManifest(t *testing.T, content string) *manifest.Manifest {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile("seed.json", []byte(`{"customers":{}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "wondertwin.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := manifest.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	return m
+}
+
+// decode splits a generated stream into documents keyed by "Kind/name".
+func decode(t *testing.T, data []byte) map[string]map[string]any {
+	t.Helper()
+	docs := make(map[string]map[string]any)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc map[string]any
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("decoding generated YAML: %v\n%s", err, data)
+		}
+		if doc == nil {
+			continue
+		}
+		name := doc["metadata"].(map[string]any)["name"].(string)
+		docs[doc["kind"].(string)+"/"+name] = doc
+	}
+	return docs
+}
+
+func TestGenerate(t *testing.T) {
+	m := loadManifest(t, `
+twins:
+  stripe:
+    version: 0.4.0
+    port: 4111
+    admin_port: 4211
+    seed: seed.json
+    latency: 50ms
+    env:
+      B: "2"
+      A: "1"
+    limits:
+      memory_mb: 256
+  Twilio_SMS:
+    binary: ./bin/twin-twilio
+    port: 4112
+`)
+
+	out, err := Generate(m, Options{Namespace: "twins", Image: "registry.example/twin-{name}:{version}"})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	docs := decode(t, out)
+
+	for _, key := range []string{
+		"ConfigMap/stripe-seed", "Deployment/stripe", "Service/stripe", "Service/stripe-admin",
+		"Deployment/twilio-sms", "Service/twilio-sms", "Service/twilio-sms-admin",
+	} {
+		doc, ok := docs[key]
+		if !ok {
+			t.Errorf("missing %s", key)
+			continue
+		}
+		if ns := doc["metadata"].(map[string]any)["namespace"]; ns != "twins" {
+			t.Errorf("%s: expected namespace twins, got %v", key, ns)
+		}
+	}
+	if len(docs) != 7 {
+		t.Errorf("expected 7 resources, got %d", len(docs))
+	}
+	if _, ok := docs["ConfigMap/twilio-sms-seed"]; ok {
+		t.Error("expected no seed ConfigMap for a twin without a seed")
+	}
+
+	if data := docs["ConfigMap/stripe-seed"]["data"].(map[string]any)["seed.json"]; data != `{"customers":{}}` {
+		t.Errorf("unexpected seed data %v", data)
+	}
+
+	spec := docs["Deployment/stripe"]["spec"].(map[string]any)
+	if spec["replicas"] != 1 {
+		t.Errorf("expected a single replica, got %v", spec["replicas"])
+	}
+	c := spec["template"].(map[string]any)["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)
+	if c["image"] != "registry.example/twin-stripe:0.4.0" {
+		t.Errorf("unexpected image %v", c["image"])
+	}
+	args := strings.Join(toStrings(c["args"]), " ")
+	if args != "--port 4111 --latency 50ms --seed-file /etc/wondertwin/seed/seed.json" {
+		t.Errorf("unexpected args %q", args)
+	}
+	env := c["env"].([]any)
+	if env[0].(map[string]any)["name"] != "A" || env[1].(map[string]any)["name"] != "B" {
+		t.Errorf("expected env sorted by name, got %v", env)
+	}
+	if mem := c["resources"].(map[string]any)["limits"].(map[string]any)["memory"]; mem != "256Mi" {
+		t.Errorf("expected a 256Mi memory limit, got %v", mem)
+	}
+
+	admin := docs["Service/stripe-admin"]["spec"].(map[string]any)["ports"].([]any)[0].(map[string]any)
+	if admin["port"] != 4211 || admin["targetPort"] != "http" {
+		t.Errorf("expected admin service on 4211 targeting the twin's port, got %v", admin)
+	}
+
+	twilio := docs["Deployment/twilio-sms"]["spec"].(map[string]any)["template"].(map[string]any)["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)
+	if twilio["image"] != "registry.example/twin-Twilio_SMS:latest" {
+		t.Errorf("expected latest for a binary-pinned twin, got %v", twilio["image"])
+	}
+}
+
+func TestGenerateDefaultsAndDeterminism(t *testing.T) {
+	m := loadManifest(t, "twins:\n  stripe:\n    version: 0.4.0\n    port: 4111\n")
+	a, err := Generate(m, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Generate(m, Options{})
+	if !bytes.Equal(a, b) {
+		t.Error("expected identical output across runs")
+	}
+	if !strings.Contains(string(a), "image: twin-stripe:0.4.0") {
+		t.Errorf("expected the default image template, got\n%s", a)
+	}
+	if strings.Contains(string(a), "namespace:") {
+		t.Error("expected no namespace unless one is given")
+	}
+}
+
+func TestGenerateMissingSeed(t *testing.T) {
+	m := loadManifest(t, "twins:\n  stripe:\n    version: 0.4.0\n    port: 4111\n    seed: missing.json\n")
+	if _, err := Generate(m, Options{}); err == nil || !strings.Contains(err.Error(), `twin "stripe"`) {
+		t.Errorf("expected a seed error naming the twin, got %v", err)
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	for in, want := range map[string]string{
+		"stripe":                "stripe",
+		"Twilio_SMS":            "twilio-sms",
+		"--odd..name--":         "odd-name",
+		"!!!":                   "twin",
+		strings.Repeat("a", 70): strings.Repeat("a", 50),
+	} {
+		if got := resourceName(in); got != want {
+			t.Errorf("resourceName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func toStrings(v any) []string {
+	var out []string
+	for _, s := range v.([]any) {
+		out = append(out, s.(string))
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+// Package logquery filters and formats the structured JSON logs written by
+// twins (log/slog JSON handler output) for `wt logs`.
+package logquery
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is a single parsed log line. Lines that are not valid JSON (panics,
+// messages written by wt itself) are kept with Structured set to false.
+type Entry struct {
+	Raw        string
+	Structured bool
+	Time       time.Time
+	Level      string
+	Message    string
+	Attrs      map[string]any
+}
+
+// Filter selects log entries. Zero-valued fields match everything.
+type Filter struct {
+	Grep     *regexp.Regexp // matched against the raw line
+	MinLevel string         // DEBUG, INFO, WARN, or ERROR (case-insensitive)
+	Since    time.Time      // entries strictly before this time are dropped
+}
+
+// levelRank orders slog levels; unknown levels rank as INFO.
+var levelRank = map[string]int{
+	"DEBUG": 0,
+	"INFO":  1,
+	"WARN":  2,
+	"ERROR": 3,
+}
+
+// ValidLevel reports whether level is a recognised slog level name.
+func ValidLevel(level string) bool {
+	_, ok := levelRank[strings.ToUpper(level)]
+	return ok
+}
+
+// Parse parses a single log line.
+func Parse(line string) Entry {
+	e := Entry{Raw: line}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return e
+	}
+	e.Structured = true
+	if s, ok := fields["time"].(string); ok {
+		e.Time, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	e.Level, _ = fields["level"].(string)
+	e.Message, _ = fields["msg"].(string)
+	delete(fields, "time")
+	delete(fields, "level")
+	delete(fields, "msg")
+	e.Attrs = fields
+	return e
+}
+
+// Match reports whether the entry passes the filter. Unstructured lines can
+// only be matched by Grep and are dropped when a level or time filter is set.
+func (f Filter) Match(e Entry) bool {
+	if f.Grep != nil && !f.Grep.MatchString(e.Raw) {
+		return false
+	}
+	if f.MinLevel == "" && f.Since.IsZero() {
+		return true
+	}
+	if !e.Structured {
+		return false
+	}
+	if f.MinLevel != "" {
+		rank, ok := levelRank[strings.ToUpper(e.Level)]
+		if !ok {
+			rank = levelRank["INFO"]
+		}
+		if rank < levelRank[strings.ToUpper(f.MinLevel)] {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && (e.Time.IsZero() || e.Time.Before(f.Since)) {
+		return false
+	}
+	return true
+}
+
+// Format renders an entry for terminal output. When asJSON is true the raw
+// line is returned unchanged; otherwise structured entries are rendered as
+// "time LEVEL message key=value ...".
+func Format(e Entry, asJSON bool) string {
+	if asJSON || !e.Structured {
+		return e.Raw
+	}
+	var b strings.Builder
+	if !e.Time.IsZero() {
+		b.WriteString(e.Time.Format("15:04:05.000"))
+		b.WriteByte(' ')
+	}
+	fmt.Fprintf(&b, "%-5s %s", e.Level, e.Message)
+
+	keys := make([]string, 0, len(e.Attrs))
+	for k := range e.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, e.Attrs[k])
+	}
+	return b.String()
+}
+
+// Scan reads lines from r and writes every entry matching f to w.
+// It returns the number of matching entries.
+func Scan(r io.Reader, w io.Writer, f Filter, asJSON bool) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	n := 0
+	for scanner.Scan() {
+		e := Parse(scanner.Text())
+		if !f.Match(e) {
+			continue
+		}
+		fmt.Fprintln(w, Format(e, asJSON))
+		n++
+	}
+	return n, scanner.Err()
+}
+
+// Follow behaves like Scan but, on reaching the end of r, keeps polling for
+// appended lines until stop is closed. It is used for `wt logs --follow`.
+func Follow(r io.Reader, w io.Writer, f Filter, asJSON bool, stop <-chan struct{}) error {
+	br := bufio.NewReader(r)
+	var partial string
+	for {
+		chunk, err := br.ReadString('\n')
+		partial += chunk
+		if err == nil {
+			e := Parse(strings.TrimRight(partial, "\r\n"))
+			partial = ""
+			if f.Match(e) {
+				fmt.Fprintln(w, Format(e, asJSON))
+			}
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package logquery
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleLog = `{"time":"2026-01-02T10:00:00Z","level":"INFO","msg":"starting twin","name":"twin-stripe","addr":":4111"}
+{"time":"2026-01-02T10:05:00Z","level":"DEBUG","msg":"request","method":"GET","path":"/v1/accounts","status":200}
+{"time":"2026-01-02T10:10:00Z","level":"ERROR","msg":"server error","err":"boom"}
+wt: resource limits are not supported on darwin; continuing without limits
+{"time":"2026-01-02T10:15:00Z","level":"WARN","msg":"slow request","path":"/v1/transfers"}
+`
+
+func scan(t *testing.T, f Filter, asJSON bool) []string {
+	t.Helper()
+	var out bytes.Buffer
+	if _, err := Scan(strings.NewReader(sampleLog), &out, f, asJSON); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	s := strings.TrimSpace(out.String())
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func TestParseStructured(t *testing.T) {
+	e := Parse(`{"time":"2026-01-02T10:00:00Z","level":"INFO","msg":"hello","port":4111}`)
+	if !e.Structured {
+		t.Fatal("expected structured entry")
+	}
+	if e.Level != "INFO" || e.Message != "hello" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Attrs["port"] != float64(4111) {
+		t.Errorf("expected port attr, got %v", e.Attrs)
+	}
+	if _, ok := e.Attrs["msg"]; ok {
+		t.Error("expected msg to be removed from attrs")
+	}
+}
+
+func TestParseUnstructured(t *testing.T) {
+	e := Parse("panic: runtime error")
+	if e.Structured {
+		t.Error("expected unstructured entry")
+	}
+}
+
+func TestScanNoFilter(t *testing.T) {
+	lines := scan(t, Filter{}, true)
+	if len(lines) != 5 {
+		t.Fatalf("expected all 5 lines, got %d", len(lines))
+	}
+}
+
+func TestScanLevel(t *testing.T) {
+	lines := scan(t, Filter{MinLevel: "warn"}, false)
+	if len(lines) != 2 {
+		t.Fatalf("expected WARN and ERROR lines, got %v", lines)
+	}
+	if !strings.Contains(lines[0], "ERROR server error err=boom") {
+		t.Errorf("unexpected formatted line: %q", lines[0])
+	}
+}
+
+func TestScanGrep(t *testing.T) {
+	lines := scan(t, Filter{Grep: regexp.MustCompile(`transfers|limits`)}, true)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %v", lines)
+	}
+}
+
+func TestScanSince(t *testing.T) {
+	since, _ := time.Parse(time.RFC3339, "2026-01-02T10:10:00Z")
+	lines := scan(t, Filter{Since: since}, true)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines at or after 10:10, got %v", lines)
+	}
+}
+
+func TestValidLevel(t *testing.T) {
+	if !ValidLevel("error") || !ValidLevel("WARN") {
+		t.Error("expected error and WARN to be valid")
+	}
+	if ValidLevel("fatal") {
+		t.Error("expected fatal to be invalid")
+	}
+}
+
+func TestFollowStops(t *testing.T) {
+	var out bytes.Buffer
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(strings.NewReader(sampleLog), &out, Filter{MinLevel: "error"}, true, stop)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("Follow() error: %v", err)
+	}
+	if got := strings.Count(out.String(), "\n"); got != 1 {
+		t.Errorf("expected 1 matching line, got %d: %s", got, out.String())
+	}
+}
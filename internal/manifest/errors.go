@@ -0,0 +1,94 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EndpointErrors lists the documented error responses of one API endpoint,
+// as declared in the "error_catalog" section of a twin-manifest.json.
+type EndpointErrors struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"` // route pattern, e.g. /v2/customers/{id}
+	Errors []DocumentedError `json:"errors"`
+}
+
+// DocumentedError is one error an endpoint is documented to return.
+type DocumentedError struct {
+	Status      int    `json:"status"`
+	Description string `json:"description,omitempty"` // when the error occurs
+	Body        any    `json:"body,omitempty"`        // a request body that triggers it
+	Message     string `json:"message,omitempty"`     // text the error response contains
+}
+
+// ErrorCatalog returns the twin's documented errors, or nil if unknown.
+func (tm *TwinManifest) ErrorCatalog() []EndpointErrors {
+	if tm == nil {
+		return nil
+	}
+	return tm.Errors
+}
+
+// ErrorCatalogFromOpenAPI builds an error catalog from the 4xx responses of
+// an OpenAPI 3 document, sorted by path and method.
+func ErrorCatalogFromOpenAPI(data []byte) ([]EndpointErrors, error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	if doc.Paths == nil {
+		return nil, fmt.Errorf("OpenAPI document has no paths")
+	}
+
+	var catalog []EndpointErrors
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			method = strings.ToUpper(method)
+			if !isHTTPMethod(method) {
+				continue // "parameters", "summary", extensions
+			}
+			var op struct {
+				Responses map[string]struct {
+					Description string `json:"description"`
+				} `json:"responses"`
+			}
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("parsing %s %s: %w", method, path, err)
+			}
+			ep := EndpointErrors{Method: method, Path: path}
+			for code, resp := range op.Responses {
+				status, err := strconv.Atoi(code)
+				if err != nil || status < 400 || status > 499 {
+					continue
+				}
+				ep.Errors = append(ep.Errors, DocumentedError{Status: status, Description: resp.Description})
+			}
+			if len(ep.Errors) == 0 {
+				continue
+			}
+			sort.Slice(ep.Errors, func(i, j int) bool { return ep.Errors[i].Status < ep.Errors[j].Status })
+			catalog = append(catalog, ep)
+		}
+	}
+	sort.Slice(catalog, func(i, j int) bool {
+		if catalog[i].Path != catalog[j].Path {
+			return catalog[i].Path < catalog[j].Path
+		}
+		return catalog[i].Method < catalog[j].Method
+	})
+	return catalog, nil
+}
+
+func isHTTPMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
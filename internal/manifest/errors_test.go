@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestErrorCatalogFromOpenAPI(t *testing.T) {
+	doc := `{
+  "openapi": "3.0.0",
+  "paths": {
+    "/v1/customers/{id}": {
+      "parameters": [{"name": "id", "in": "path"}],
+      "get": {"responses": {
+        "200": {"description": "ok"},
+        "404": {"description": "not found"},
+        "401": {"description": "unauthorized"},
+        "default": {"description": "error"}
+      }},
+      "delete": {"responses": {"200": {"description": "ok"}}}
+    },
+    "/v1/customers": {
+      "post": {"responses": {"422": {"description": "invalid"}, "500": {"description": "oops"}}}
+    }
+  }
+}`
+	catalog, err := ErrorCatalogFromOpenAPI([]byte(doc))
+	if err != nil {
+		t.Fatalf("ErrorCatalogFromOpenAPI() error: %v", err)
+	}
+	if len(catalog) != 2 {
+		t.Fatalf("expected 2 endpoints with errors, got %+v", catalog)
+	}
+	if catalog[0].Method != "POST" || catalog[0].Path != "/v1/customers" || len(catalog[0].Errors) != 1 || catalog[0].Errors[0].Status != 422 {
+		t.Errorf("unexpected first endpoint: %+v", catalog[0])
+	}
+	get := catalog[1]
+	if get.Method != "GET" || len(get.Errors) != 2 || get.Errors[0].Status != 401 || get.Errors[1].Status != 404 {
+		t.Errorf("expected GET with sorted 401 and 404, got %+v", get)
+	}
+	if get.Errors[1].Description != "not found" {
+		t.Errorf("expected description to be kept, got %q", get.Errors[1].Description)
+	}
+
+	if _, err := ErrorCatalogFromOpenAPI([]byte(`{"openapi": "3.0.0"}`)); err == nil {
+		t.Error("expected an error for a document without paths")
+	}
+}
+
+func TestTwinManifestErrorCatalog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "twin-manifest.json")
+	writeFile(t, path, `{
+  "twin": "stripe",
+  "error_catalog": [
+    {"method": "GET", "path": "/v1/customers/{id}", "errors": [{"status": 404, "message": "No such customer"}]}
+  ]
+}`)
+	tm, err := LoadTwinManifest(path)
+	if err != nil {
+		t.Fatalf("LoadTwinManifest() error: %v", err)
+	}
+	catalog := tm.ErrorCatalog()
+	if len(catalog) != 1 || catalog[0].Errors[0].Message != "No such customer" {
+		t.Errorf("unexpected catalog: %+v", catalog)
+	}
+
+	var missing *TwinManifest
+	if missing.ErrorCatalog() != nil {
+		t.Error("expected nil catalog for a missing manifest")
+	}
+}
@@ -1,27 +1,174 @@
-// Package manifest parses wondertwin.yaml and wondertwin.json project manifests.
+// Package manifest parses wondertwin.yaml and wondertwin.json project manifests,
+// along with the twin-manifest.json files twins ship to describe themselves.
 package manifest
 
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/wondertwin-ai/wondertwin/adminclient"
 	"gopkg.in/yaml.v3"
 )
 
 // Twin defines the configuration for a single twin in the manifest.
 type Twin struct {
-	Binary    string            `yaml:"binary" json:"binary"`
-	Version   string            `yaml:"version" json:"version"`
-	SDK       string            `yaml:"sdk" json:"sdk"`
-	Build     string            `yaml:"build" json:"build"`
-	Registry  string            `yaml:"registry" json:"registry"`
-	Port      int               `yaml:"port" json:"port"`
-	AdminPort int               `yaml:"admin_port" json:"admin_port"`
-	Seed      string            `yaml:"seed" json:"seed"`
-	Env       map[string]string `yaml:"env" json:"env"`
+	Binary        string            `yaml:"binary" json:"binary"`
+	URL           string            `yaml:"url" json:"url"` // base URL of a remotely hosted twin, instead of binary
+	Version       string            `yaml:"version" json:"version"`
+	SDK           string            `yaml:"sdk" json:"sdk"`
+	Build         string            `yaml:"build" json:"build"`
+	Registry      string            `yaml:"registry" json:"registry"`
+	Port          int               `yaml:"port" json:"port"`
+	AdminPort     int               `yaml:"admin_port" json:"admin_port"`
+	Host          string            `yaml:"host" json:"host"`     // listen address, e.g. ::1 for IPv6 loopback only
+	Socket        string            `yaml:"socket" json:"socket"` // unix domain socket to listen on instead of port
+	Seed          string            `yaml:"seed" json:"seed"`
+	Latency       string            `yaml:"latency" json:"latency"`               // base simulated latency, e.g. "250ms"
+	FailRate      float64           `yaml:"fail_rate" json:"fail_rate"`           // random failure rate 0.0-1.0
+	RandSeed      uint64            `yaml:"rand_seed" json:"rand_seed"`           // reproducible codes and faults when non-zero
+	AdminReadOnly bool              `yaml:"admin_readonly" json:"admin_readonly"` // reject admin requests that modify the twin
+	Env           map[string]string `yaml:"env" json:"env"`
+	Limits        *Limits           `yaml:"limits,omitempty" json:"limits,omitempty"`
+
+	Config *TwinConfig `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// TwinConfig is runtime behavior wt up applies through a twin's admin API
+// once the twin is healthy, so how an environment behaves is versioned in
+// the manifest rather than configured by hand. Unlike the top-level latency
+// and fail_rate, which are passed to the process, it changes without a
+// restart (see wt apply), and it takes precedence over them.
+type TwinConfig struct {
+	Latency  string   `yaml:"latency,omitempty" json:"latency,omitempty"`
+	FailRate *float64 `yaml:"fail_rate,omitempty" json:"fail_rate,omitempty"` // set to 0 to override a chaos profile's rate
+	Quirks   []string `yaml:"quirks,omitempty" json:"quirks,omitempty"`       // quirk IDs to enable, on top of the twin's defaults
+	Chaos    string   `yaml:"chaos,omitempty" json:"chaos,omitempty"`         // a name from ChaosProfiles
+
+	WebhookURL string `yaml:"webhook_url,omitempty" json:"webhook_url,omitempty"` // where the twin delivers webhooks
+}
+
+// ChaosProfile is a named mix of failures a twin's config can select
+// instead of tuning each setting.
+type ChaosProfile struct {
+	Latency            string  // base simulated latency
+	FailRate           float64 // share of API requests answered with a 500
+	StorageFailureRate float64 // share of store writes that fail
+}
+
+// ChaosProfiles are the profiles config.chaos can name.
+var ChaosProfiles = map[string]ChaosProfile{
+	"flaky":         {FailRate: 0.05},
+	"slow":          {Latency: "2s"},
+	"degraded":      {Latency: "500ms", FailRate: 0.1},
+	"storage-flaky": {StorageFailureRate: 0.05},
+}
+
+// Settings returns the runtime settings for PUT /admin/config: the chaos
+// profile's latency and fail rate, overridden by the config's own, and the
+// webhook URL. Settings left unset are not included.
+func (c *TwinConfig) Settings() map[string]any {
+	settings := map[string]any{}
+	if c == nil {
+		return settings
+	}
+	chaos := ChaosProfiles[c.Chaos]
+	if chaos.Latency != "" {
+		settings["latency"] = chaos.Latency
+	}
+	if chaos.FailRate > 0 {
+		settings["fail_rate"] = chaos.FailRate
+	}
+	if c.Latency != "" {
+		settings["latency"] = c.Latency
+	}
+	if c.FailRate != nil {
+		settings["fail_rate"] = *c.FailRate
+	}
+	if c.WebhookURL != "" {
+		settings["webhook_url"] = c.WebhookURL
+	}
+	return settings
+}
+
+// StorageFailureRate returns the share of store writes the config's chaos
+// profile makes fail.
+func (c *TwinConfig) StorageFailureRate() float64 {
+	if c == nil {
+		return 0
+	}
+	return ChaosProfiles[c.Chaos].StorageFailureRate
+}
+
+// validate checks a twin's config block.
+func (c *TwinConfig) validate() error {
+	if c.Latency != "" {
+		if d, err := time.ParseDuration(c.Latency); err != nil || d < 0 {
+			return fmt.Errorf("config.latency must be a non-negative duration like \"250ms\"")
+		}
+	}
+	if c.FailRate != nil && (*c.FailRate < 0 || *c.FailRate > 1) {
+		return fmt.Errorf("config.fail_rate must be between 0.0 and 1.0")
+	}
+	if _, ok := ChaosProfiles[c.Chaos]; c.Chaos != "" && !ok {
+		names := make([]string, 0, len(ChaosProfiles))
+		for name := range ChaosProfiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("config.chaos %q is not a chaos profile (known: %s)", c.Chaos, strings.Join(names, ", "))
+	}
+	for _, id := range c.Quirks {
+		if id == "" {
+			return fmt.Errorf("config.quirks must not contain empty IDs")
+		}
+	}
+	if c.WebhookURL != "" {
+		if u, err := url.Parse(c.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("config.webhook_url must be an http or https URL, got %q", c.WebhookURL)
+		}
+	}
+	return nil
+}
+
+// Limits caps the resources a twin process may consume. MemoryMB is passed
+// to the twin as GOMEMLIMIT, a soft limit its Go runtime collects garbage
+// harder to stay under, not a hard cap. The other process limits are applied
+// as POSIX rlimits where the platform supports it, and the store limits are
+// passed to the twin, which enforces them per store. Zero means unlimited.
+type Limits struct {
+	MemoryMB     int `yaml:"memory_mb" json:"memory_mb"`           // Go runtime soft memory limit (GOMEMLIMIT)
+	CPUSeconds   int `yaml:"cpu_seconds" json:"cpu_seconds"`       // total CPU time before SIGXCPU (RLIMIT_CPU)
+	MaxOpenFiles int `yaml:"max_open_files" json:"max_open_files"` // open file descriptors (RLIMIT_NOFILE)
+
+	StoreMaxRecords int    `yaml:"store_max_records,omitempty" json:"store_max_records,omitempty"`   // records per store
+	StoreMaxMB      int    `yaml:"store_max_mb,omitempty" json:"store_max_mb,omitempty"`             // estimated megabytes per store
+	StorePolicy     string `yaml:"store_limit_policy,omitempty" json:"store_limit_policy,omitempty"` // "reject" (default) or "evict"
+}
+
+// StoreArgs returns the twin flags that apply the store limits.
+func (l *Limits) StoreArgs() []string {
+	if l == nil {
+		return nil
+	}
+	var args []string
+	if l.StoreMaxRecords > 0 {
+		args = append(args, "--store-max-records", strconv.Itoa(l.StoreMaxRecords))
+	}
+	if l.StoreMaxMB > 0 {
+		args = append(args, "--store-max-mb", strconv.Itoa(l.StoreMaxMB))
+	}
+	if l.StorePolicy != "" {
+		args = append(args, "--store-limit-policy", l.StorePolicy)
+	}
+	return args
 }
 
 // Settings holds global CLI settings from the manifest.
@@ -29,6 +176,10 @@ type Settings struct {
 	BinaryDir string `yaml:"binary_dir" json:"binary_dir"`
 	LogDir    string `yaml:"log_dir" json:"log_dir"`
 	Verbose   bool   `yaml:"verbose" json:"verbose"`
+
+	// VerifyConformance runs the conformance suite against every freshly
+	// installed binary and quarantines those that fail.
+	VerifyConformance bool `yaml:"verify_conformance" json:"verify_conformance"`
 }
 
 // Manifest represents a parsed wondertwin.yaml or wondertwin.json file.
@@ -38,6 +189,8 @@ type Manifest struct {
 
 	// dir is the directory containing the manifest file, used for resolving relative paths.
 	dir string
+	// path is the absolute path of the manifest file that was loaded.
+	path string
 }
 
 // Load reads and parses a wondertwin.json or wondertwin.yaml file.
@@ -77,6 +230,7 @@ func Load(path string) (*Manifest, error) {
 		return nil, fmt.Errorf("resolving manifest path: %w", err)
 	}
 	m.dir = filepath.Dir(absPath)
+	m.path = absPath
 
 	// Apply defaults
 	if m.Settings.LogDir == "" {
@@ -87,6 +241,13 @@ func Load(path string) (*Manifest, error) {
 	}
 
 	for name, t := range m.Twins {
+		if t.URL != "" {
+			if err := validateRemote(name, &t); err != nil {
+				return nil, err
+			}
+			m.Twins[name] = t
+			continue
+		}
 		if t.Binary == "" && t.Version == "" {
 			return nil, fmt.Errorf("twin %q: binary path or version is required", name)
 		}
@@ -102,11 +263,47 @@ func Load(path string) (*Manifest, error) {
 			binDir := expandPath(m.Settings.BinaryDir)
 			t.Binary = filepath.Join(binDir, "twin-"+name)
 		}
-		if t.Port == 0 {
-			return nil, fmt.Errorf("twin %q: port is required", name)
+		if err := validateListen(name, &t); err != nil {
+			return nil, err
+		}
+		if t.Socket != "" {
+			t.Socket = m.resolvePath(t.Socket)
+		}
+		// Fall back to the default port declared by the twin itself
+		if t.Port == 0 && t.Socket == "" {
+			if tm, err := m.findTwinManifest(name, t.Binary); err == nil && tm != nil {
+				t.Port = tm.Admin.DefaultPort
+			}
+		}
+		if t.Port == 0 && t.Socket == "" {
+			return nil, fmt.Errorf("twin %q: port or socket is required", name)
+		}
+		if l := t.Limits; l != nil {
+			if l.MemoryMB < 0 || l.CPUSeconds < 0 || l.MaxOpenFiles < 0 || l.StoreMaxRecords < 0 || l.StoreMaxMB < 0 {
+				return nil, fmt.Errorf("twin %q: limits must not be negative", name)
+			}
+			if l.StorePolicy != "" && l.StorePolicy != "reject" && l.StorePolicy != "evict" {
+				return nil, fmt.Errorf("twin %q: limits.store_limit_policy must be reject or evict", name)
+			}
+		}
+		if t.Latency != "" {
+			if d, err := time.ParseDuration(t.Latency); err != nil || d < 0 {
+				return nil, fmt.Errorf("twin %q: latency must be a non-negative duration like \"250ms\"", name)
+			}
+		}
+		if t.FailRate < 0 || t.FailRate > 1 {
+			return nil, fmt.Errorf("twin %q: fail_rate must be between 0.0 and 1.0", name)
+		}
+		if t.Config != nil {
+			if t.AdminReadOnly {
+				return nil, fmt.Errorf("twin %q: config is applied through the admin API, so it cannot be combined with admin_readonly", name)
+			}
+			if err := t.Config.validate(); err != nil {
+				return nil, fmt.Errorf("twin %q: %w", name, err)
+			}
 		}
 		// Default admin_port to same as port (twins serve admin on the same router)
-		if t.AdminPort == 0 {
+		if t.AdminPort == 0 && t.Socket == "" {
 			t.AdminPort = t.Port
 		}
 		m.Twins[name] = t
@@ -115,6 +312,98 @@ func Load(path string) (*Manifest, error) {
 	return &m, nil
 }
 
+// validateRemote checks a twin declared by url. Remote twins are not
+// started by wt, so process settings have no effect and are rejected.
+func validateRemote(name string, t *Twin) error {
+	if t.Binary != "" || t.Version != "" || t.Build != "" {
+		return fmt.Errorf("twin %q: url cannot be combined with binary, version, or build", name)
+	}
+	if t.Port != 0 || t.AdminPort != 0 || t.Host != "" || t.Socket != "" {
+		return fmt.Errorf("twin %q: url cannot be combined with port, admin_port, host, or socket", name)
+	}
+	if t.Config != nil {
+		return fmt.Errorf("twin %q: config applies to twins wt starts, not remote ones", name)
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("twin %q: url must be an http:// or https:// URL, got %q", name, t.URL)
+	}
+	t.URL = strings.TrimRight(t.URL, "/")
+	return nil
+}
+
+// validateListen checks where a local twin listens. A socket replaces the
+// TCP port, API and admin alike, so it excludes port, admin_port, and host.
+func validateListen(name string, t *Twin) error {
+	if t.Socket != "" && (t.Port != 0 || t.AdminPort != 0 || t.Host != "") {
+		return fmt.Errorf("twin %q: socket cannot be combined with port, admin_port, or host", name)
+	}
+	if t.Host != "" && t.Host != "localhost" && net.ParseIP(t.Host) == nil {
+		return fmt.Errorf("twin %q: host must be an IP address or localhost, got %q", name, t.Host)
+	}
+	return nil
+}
+
+// Remote reports whether the twin is hosted elsewhere and addressed by URL
+// rather than started locally by wt.
+func (t Twin) Remote() bool {
+	return t.URL != ""
+}
+
+// BaseURL returns the URL the twin's API is served at. For a twin on a unix
+// socket it is an adminclient.UnixSocketURL, which HTTP clients reach once
+// adminclient.RegisterUnixSockets has been called on their transport.
+func (t Twin) BaseURL() string {
+	if t.Remote() {
+		return t.URL
+	}
+	return t.localURL(t.Port)
+}
+
+// AdminURL returns the URL the twin's /admin/* endpoints are served at.
+// Remote twins serve them on the same router as the API.
+func (t Twin) AdminURL() string {
+	if t.Remote() {
+		return t.URL
+	}
+	return t.localURL(t.AdminPort)
+}
+
+func (t Twin) localURL(port int) string {
+	if t.Socket != "" {
+		return adminclient.UnixSocketURL(t.Socket)
+	}
+	host := t.Host
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// Addr returns where the twin listens, for display: the URL of a remote
+// twin, unix:<path> for a socket, otherwise host:port.
+func (t Twin) Addr() string {
+	switch {
+	case t.Remote():
+		return t.URL
+	case t.Socket != "":
+		return "unix:" + t.Socket
+	}
+	return strings.TrimPrefix(t.localURL(t.Port), "http://")
+}
+
+// Path returns the absolute path of the loaded manifest file.
+func (m *Manifest) Path() string {
+	return m.path
+}
+
+// ResolvePath returns the absolute path of the manifest file Load would
+// read for path, preferring wondertwin.json next to a YAML path. Callers
+// that key state by manifest use it so every spelling of a path agrees.
+func ResolvePath(path string) (string, error) {
+	return filepath.Abs(resolveManifestFormat(path))
+}
+
 // resolveManifestFormat checks if a YAML manifest path has a JSON sibling and
 // returns the JSON path if it exists. This ensures JSON-preferred loading
 // regardless of entry point.
@@ -3,7 +3,10 @@ package manifest
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
 )
 
 func TestLoadYAML(t *testing.T) {
@@ -155,3 +158,277 @@ settings:
 		t.Error("verbose mismatch between YAML and JSON")
 	}
 }
+
+func TestLoadLimits(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.yaml")
+	content := `
+twins:
+  stripe:
+    binary: ./bin/twin-stripe
+    port: 4111
+    limits:
+      memory_mb: 256
+      cpu_seconds: 600
+      max_open_files: 1024
+      store_max_records: 50000
+      store_max_mb: 128
+      store_limit_policy: evict
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	l := m.Twins["stripe"].Limits
+	if l == nil {
+		t.Fatal("expected limits to be parsed")
+	}
+	if l.MemoryMB != 256 || l.CPUSeconds != 600 || l.MaxOpenFiles != 1024 {
+		t.Errorf("unexpected limits: %+v", *l)
+	}
+	want := []string{"--store-max-records", "50000", "--store-max-mb", "128", "--store-limit-policy", "evict"}
+	if args := l.StoreArgs(); !slices.Equal(args, want) {
+		t.Errorf("StoreArgs() = %v, want %v", args, want)
+	}
+	var none *Limits
+	if none.StoreArgs() != nil {
+		t.Error("expected no store args without limits")
+	}
+}
+
+func TestLoadInvalidStoreLimitPolicy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.json")
+	content := `{"twins": {"stripe": {"binary": "./bin/twin-stripe", "port": 4111, "limits": {"store_max_records": 10, "store_limit_policy": "drop"}}}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected error for an unknown store limit policy")
+	}
+}
+
+func TestLoadNegativeLimits(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.json")
+	content := `{"twins": {"stripe": {"binary": "./bin/twin-stripe", "port": 4111, "limits": {"memory_mb": -1}}}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected error for negative limits")
+	}
+}
+
+func TestLoadInvalidRuntimeConfig(t *testing.T) {
+	for name, twin := range map[string]string{
+		"bad latency":       `"latency": "soon"`,
+		"negative latency":  `"latency": "-1s"`,
+		"fail rate above 1": `"fail_rate": 1.5`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "wondertwin.json")
+			content := `{"twins": {"stripe": {"binary": "./bin/twin-stripe", "port": 4111, ` + twin + `}}}`
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := Load(path); err == nil {
+				t.Fatalf("expected error for %s", name)
+			}
+		})
+	}
+}
+
+func TestLoadTwinConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.yaml")
+	content := `
+twins:
+  stripe:
+    binary: ./bin/twin-stripe
+    port: 4111
+    latency: 50ms
+    config:
+      fail_rate: 0
+      quirks: [WT-Q-004]
+      chaos: degraded
+      webhook_url: http://localhost:3000/webhooks/stripe
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	cfg := m.Twins["stripe"].Config
+	if cfg == nil || len(cfg.Quirks) != 1 || cfg.Chaos != "degraded" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	// The chaos profile's latency applies; its fail rate is overridden.
+	settings := cfg.Settings()
+	if settings["latency"] != "500ms" || settings["fail_rate"] != 0.0 || settings["webhook_url"] != "http://localhost:3000/webhooks/stripe" {
+		t.Errorf("unexpected settings: %v", settings)
+	}
+	if (*TwinConfig)(nil).StorageFailureRate() != 0 || len((*TwinConfig)(nil).Settings()) != 0 {
+		t.Error("expected a nil config to change nothing")
+	}
+}
+
+func TestLoadInvalidTwinConfig(t *testing.T) {
+	for name, twin := range map[string]string{
+		"bad latency":    `"config": {"latency": "soon"}`,
+		"fail rate":      `"config": {"fail_rate": -0.1}`,
+		"unknown chaos":  `"config": {"chaos": "apocalypse"}`,
+		"empty quirk":    `"config": {"quirks": [""]}`,
+		"webhook url":    `"config": {"webhook_url": "localhost:3000/hooks"}`,
+		"admin readonly": `"admin_readonly": true, "config": {"latency": "1s"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "wondertwin.json")
+			content := `{"twins": {"stripe": {"binary": "./bin/twin-stripe", "port": 4111, ` + twin + `}}}`
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := Load(path); err == nil {
+				t.Fatalf("expected error for %s", name)
+			}
+		})
+	}
+}
+
+func TestLoadRemoteTwin(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.yaml")
+	content := `
+twins:
+  stripe:
+    url: https://stripe.twins.staging.example.com/
+    seed: ./seeds/stripe.json
+  twilio:
+    binary: ./bin/twin-twilio
+    port: 4112
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	stripe := m.Twins["stripe"]
+	if !stripe.Remote() || stripe.Binary != "" || stripe.Port != 0 {
+		t.Errorf("expected a remote twin with no binary or port, got %+v", stripe)
+	}
+	if got, want := stripe.BaseURL(), "https://stripe.twins.staging.example.com"; got != want {
+		t.Errorf("BaseURL() = %q, want %q", got, want)
+	}
+	if stripe.AdminURL() != stripe.BaseURL() {
+		t.Errorf("expected admin endpoints on the API URL, got %q", stripe.AdminURL())
+	}
+
+	twilio := m.Twins["twilio"]
+	if twilio.Remote() || twilio.BaseURL() != "http://localhost:4112" || twilio.AdminURL() != "http://localhost:4112" {
+		t.Errorf("unexpected local twin URLs: %q, %q", twilio.BaseURL(), twilio.AdminURL())
+	}
+}
+
+func TestLoadListenAddresses(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.yaml")
+	content := `
+twins:
+  stripe:
+    binary: ./bin/twin-stripe
+    socket: ./.wt/stripe.sock
+  twilio:
+    binary: ./bin/twin-twilio
+    port: 4112
+    host: "::1"
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	stripe := m.Twins["stripe"]
+	sock := filepath.Join(dir, ".wt", "stripe.sock")
+	if stripe.Socket != sock || stripe.Port != 0 || stripe.AdminPort != 0 {
+		t.Errorf("expected a socket twin at %s with no port, got %+v", sock, stripe)
+	}
+	if stripe.BaseURL() != adminclient.UnixSocketURL(sock) || stripe.AdminURL() != stripe.BaseURL() {
+		t.Errorf("unexpected socket twin URLs: %q, %q", stripe.BaseURL(), stripe.AdminURL())
+	}
+	if stripe.Addr() != "unix:"+sock {
+		t.Errorf("Addr() = %q, want unix:%s", stripe.Addr(), sock)
+	}
+
+	twilio := m.Twins["twilio"]
+	if twilio.BaseURL() != "http://[::1]:4112" || twilio.Addr() != "[::1]:4112" {
+		t.Errorf("unexpected IPv6 twin address: %q, %q", twilio.BaseURL(), twilio.Addr())
+	}
+}
+
+func TestLoadInvalidListenAddress(t *testing.T) {
+	for name, twin := range map[string]string{
+		"socket with port":  `"binary": "./bin/twin-stripe", "socket": "stripe.sock", "port": 4111`,
+		"socket with host":  `"binary": "./bin/twin-stripe", "socket": "stripe.sock", "host": "::1"`,
+		"socket with url":   `"url": "http://twins:4111", "socket": "stripe.sock"`,
+		"hostname":          `"binary": "./bin/twin-stripe", "port": 4111, "host": "twins.internal"`,
+		"no port or socket": `"binary": "./bin/twin-stripe", "host": "::1"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "wondertwin.json")
+			content := `{"twins": {"stripe": {` + twin + `}}}`
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := Load(path); err == nil {
+				t.Fatalf("expected error for %s", name)
+			}
+		})
+	}
+}
+
+func TestLoadInvalidRemoteTwin(t *testing.T) {
+	for name, twin := range map[string]string{
+		"with binary": `"url": "http://twins:4111", "binary": "./bin/twin-stripe"`,
+		"with port":   `"url": "http://twins:4111", "port": 4111`,
+		"no scheme":   `"url": "twins:4111"`,
+		"ftp":         `"url": "ftp://twins/stripe"`,
+		"with config": `"url": "http://twins:4111", "config": {"chaos": "flaky"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "wondertwin.json")
+			content := `{"twins": {"stripe": {` + twin + `}}}`
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := Load(path); err == nil {
+				t.Fatalf("expected error for %s", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Admin capabilities a twin can declare in the "admin" section of its
+// twin-manifest.json.
+const (
+	CapWebhooks   = "webhooks"
+	CapClock      = "clock"
+	CapQuirks     = "quirks"
+	CapNamespaces = "namespaces"
+	CapTenants    = "tenants"
+)
+
+// TwinManifest holds the fields of a twin's twin-manifest.json that wt
+// consumes at runtime. Coverage and generation metadata are ignored.
+type TwinManifest struct {
+	Twin           string `json:"twin"`
+	DisplayName    string `json:"display_name"`
+	ServiceSurface struct {
+		AuthPattern string `json:"auth_pattern"`
+		HasWebhooks bool   `json:"has_webhooks"`
+	} `json:"service_surface"`
+	Admin  TwinAdmin        `json:"admin"`
+	Errors []EndpointErrors `json:"error_catalog"`
+}
+
+// TwinAdmin describes the admin control plane a twin exposes.
+type TwinAdmin struct {
+	Capabilities  []string `json:"capabilities"`
+	DefaultPort   int      `json:"default_port"`
+	DefaultQuirks []string `json:"default_quirks"`
+}
+
+// LoadTwinManifest reads and parses a twin-manifest.json file.
+func LoadTwinManifest(path string) (*TwinManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading twin manifest %s: %w", path, err)
+	}
+	var tm TwinManifest
+	if err := json.Unmarshal(data, &tm); err != nil {
+		return nil, fmt.Errorf("parsing twin manifest %s: %w", path, err)
+	}
+	return &tm, nil
+}
+
+// Supports reports whether the twin declares the given admin capability.
+// Twins without a manifest, or whose manifest predates the admin section,
+// are assumed to support everything so wt never blocks on missing metadata.
+// Webhooks are also implied by service_surface.has_webhooks.
+func (tm *TwinManifest) Supports(capability string) bool {
+	if tm == nil || tm.Admin.Capabilities == nil {
+		return true
+	}
+	if capability == CapWebhooks && tm.ServiceSurface.HasWebhooks {
+		return true
+	}
+	for _, c := range tm.Admin.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthPattern returns the declared auth pattern, or "" if unknown.
+func (tm *TwinManifest) AuthPattern() string {
+	if tm == nil {
+		return ""
+	}
+	return tm.ServiceSurface.AuthPattern
+}
+
+// TwinManifest locates and loads the twin-manifest.json for a named twin.
+// It returns nil with no error when the twin ships no manifest.
+func (m *Manifest) TwinManifest(name string) (*TwinManifest, error) {
+	t, err := m.Twin(name)
+	if err != nil {
+		return nil, err
+	}
+	return m.findTwinManifest(name, t.Binary)
+}
+
+// findTwinManifest looks for a twin's manifest in, in order: a
+// twin-<name>.manifest.json sidecar next to the binary, a twin-manifest.json
+// in the binary's directory, and twin-<name>/twin-manifest.json under the
+// project manifest directory (the layout of a twin source checkout).
+func (m *Manifest) findTwinManifest(name, binary string) (*TwinManifest, error) {
+	var candidates []string
+	if binary != "" {
+		candidates = append(candidates,
+			binary+".manifest.json",
+			filepath.Join(filepath.Dir(binary), "twin-manifest.json"),
+		)
+	}
+	if m.dir != "" {
+		candidates = append(candidates, filepath.Join(m.dir, "twin-"+name, "twin-manifest.json"))
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("checking twin manifest %s: %w", path, err)
+		}
+		tm, err := LoadTwinManifest(path)
+		if err != nil {
+			return nil, err
+		}
+		// A shared directory may hold another twin's manifest; ignore it.
+		if tm.Twin != "" && tm.Twin != name {
+			continue
+		}
+		return tm, nil
+	}
+	return nil, nil
+}
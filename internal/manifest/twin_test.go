@@ -0,0 +1,103 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTwinManifestSidecar(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "wondertwin.json"), `{"twins": {"stripe": {"binary": "./bin/twin-stripe", "port": 4111}}}`)
+	writeFile(t, filepath.Join(dir, "bin", "twin-stripe.manifest.json"), `{
+  "twin": "stripe",
+  "service_surface": {"auth_pattern": "api_key", "has_webhooks": true},
+  "admin": {"capabilities": ["clock"], "default_quirks": ["STRIPE-Q-001"]}
+}`)
+
+	m, err := Load(filepath.Join(dir, "wondertwin.json"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	tm, err := m.TwinManifest("stripe")
+	if err != nil {
+		t.Fatalf("TwinManifest() error: %v", err)
+	}
+	if tm == nil {
+		t.Fatal("expected twin manifest to be found")
+	}
+	if tm.AuthPattern() != "api_key" {
+		t.Errorf("expected auth pattern api_key, got %q", tm.AuthPattern())
+	}
+	if !tm.Supports(CapClock) {
+		t.Error("expected clock to be supported")
+	}
+	if !tm.Supports(CapWebhooks) {
+		t.Error("expected webhooks to be implied by has_webhooks")
+	}
+	if tm.Supports(CapNamespaces) {
+		t.Error("expected namespaces to be unsupported")
+	}
+	if len(tm.Admin.DefaultQuirks) != 1 || tm.Admin.DefaultQuirks[0] != "STRIPE-Q-001" {
+		t.Errorf("unexpected default quirks: %v", tm.Admin.DefaultQuirks)
+	}
+}
+
+func TestTwinManifestSourceCheckout(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "wondertwin.json"), `{"twins": {"twilio": {"binary": "./bin/twin-twilio"}}}`)
+	writeFile(t, filepath.Join(dir, "twin-twilio", "twin-manifest.json"), `{
+  "twin": "twilio",
+  "admin": {"capabilities": ["clock"], "default_port": 4112}
+}`)
+
+	m, err := Load(filepath.Join(dir, "wondertwin.json"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	tw := m.Twins["twilio"]
+	if tw.Port != 4112 {
+		t.Errorf("expected default port 4112 from twin manifest, got %d", tw.Port)
+	}
+	if tw.AdminPort != 4112 {
+		t.Errorf("expected admin port to follow default port, got %d", tw.AdminPort)
+	}
+}
+
+func TestTwinManifestIgnoresOtherTwin(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "wondertwin.json"), `{"twins": {"resend": {"binary": "./bin/twin-resend", "port": 4113}}}`)
+	writeFile(t, filepath.Join(dir, "bin", "twin-manifest.json"), `{"twin": "stripe", "admin": {"capabilities": ["webhooks"]}}`)
+
+	m, err := Load(filepath.Join(dir, "wondertwin.json"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	tm, err := m.TwinManifest("resend")
+	if err != nil {
+		t.Fatalf("TwinManifest() error: %v", err)
+	}
+	if tm != nil {
+		t.Errorf("expected no manifest for resend, got one for %q", tm.Twin)
+	}
+}
+
+func TestTwinManifestSupportsWithoutMetadata(t *testing.T) {
+	var tm *TwinManifest
+	if !tm.Supports(CapQuirks) {
+		t.Error("nil manifest should support every capability")
+	}
+	if !(&TwinManifest{}).Supports(CapNamespaces) {
+		t.Error("manifest without admin section should support every capability")
+	}
+}
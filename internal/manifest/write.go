@@ -0,0 +1,157 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetTwinPort rewrites the port of one twin in the manifest file at path,
+// leaving the rest of the file as written. JSON manifests are edited in
+// place byte for byte; YAML manifests are re-encoded, which keeps key order
+// and comments but may normalize indentation.
+func SetTwinPort(path, name string, port int) error {
+	path = resolveManifestFormat(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading manifest %s: %w", path, err)
+	}
+
+	var out []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		out, err = setJSONTwinPort(data, name, port)
+	case ".yaml", ".yml":
+		out, err = setYAMLTwinPort(data, name, port)
+	default:
+		err = fmt.Errorf("unsupported manifest format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("updating %s: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, info.Mode().Perm())
+}
+
+func setJSONTwinPort(data []byte, name string, port int) ([]byte, error) {
+	start, end, err := jsonValueSpan(data, "twins", name)
+	if err != nil {
+		return nil, err
+	}
+	if data[start] != '{' {
+		return nil, fmt.Errorf("twin %q is not an object", name)
+	}
+
+	value := []byte(strconv.Itoa(port))
+	if ps, pe, err := jsonValueSpan(data[start:end], "port"); err == nil {
+		return splice(data, start+ps, start+pe, value), nil
+	}
+
+	// No port key yet: insert one before the first key, copying its indentation.
+	body := data[start+1 : end]
+	ws := len(body) - len(bytes.TrimLeft(body, " \t\r\n"))
+	entry := []byte(`"port": ` + string(value))
+	if bytes.TrimSpace(body)[0] != '}' {
+		entry = append(append(body[:ws:ws], entry...), ',')
+	}
+	return splice(data, start+1, start+1, entry), nil
+}
+
+// jsonValueSpan returns the byte range of the value at a path of object
+// keys in a JSON document.
+func jsonValueSpan(data []byte, path ...string) (start, end int, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for _, key := range path {
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return 0, 0, fmt.Errorf("expected an object containing %q", key)
+		}
+		found := false
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return 0, 0, err
+			}
+			if tok == key {
+				found = true
+				break
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return 0, 0, err
+			}
+		}
+		if !found {
+			return 0, 0, fmt.Errorf("key %q not found", key)
+		}
+	}
+	var value json.RawMessage
+	if err := dec.Decode(&value); err != nil {
+		return 0, 0, err
+	}
+	end = int(dec.InputOffset())
+	return end - len(value), end, nil
+}
+
+func splice(data []byte, start, end int, insert []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(insert))
+	out = append(out, data[:start]...)
+	out = append(out, insert...)
+	return append(out, data[end:]...)
+}
+
+func setYAMLTwinPort(data []byte, name string, port int) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("empty manifest")
+	}
+	twins := yamlMapValue(doc.Content[0], "twins")
+	twin := yamlMapValue(twins, name)
+	if twin == nil || twin.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("twin %q not found", name)
+	}
+
+	value := strconv.Itoa(port)
+	if node := yamlMapValue(twin, "port"); node != nil {
+		node.Kind, node.Tag, node.Value, node.Style = yaml.ScalarNode, "!!int", value, 0
+	} else {
+		twin.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "port"},
+			{Kind: yaml.ScalarNode, Tag: "!!int", Value: value},
+		}, twin.Content...)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	enc.Close()
+	return buf.Bytes(), nil
+}
+
+// yamlMapValue returns the value node for key in a mapping node, or nil.
+func yamlMapValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetTwinPortJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.json")
+	orig := `{
+  "twins": {
+    "stripe": {
+      "binary": "./bin/twin-stripe",
+      "env": {"port": "keep"},
+      "port": 4111
+    },
+    "twilio": {
+      "binary": "./bin/twin-twilio"
+    },
+    "resend": {}
+  }
+}
+`
+	if err := os.WriteFile(path, []byte(orig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, port := range map[string]int{"stripe": 4120, "twilio": 4121, "resend": 4122} {
+		if err := SetTwinPort(path, name, port); err != nil {
+			t.Fatalf("SetTwinPort(%s): %v", name, err)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	want := `{
+  "twins": {
+    "stripe": {
+      "binary": "./bin/twin-stripe",
+      "env": {"port": "keep"},
+      "port": 4120
+    },
+    "twilio": {
+      "port": 4121,
+      "binary": "./bin/twin-twilio"
+    },
+    "resend": {"port": 4122}
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("unexpected manifest:\n%s", data)
+	}
+
+	if err := SetTwinPort(path, "posthog", 4123); err == nil {
+		t.Error("expected an error for an unknown twin")
+	}
+}
+
+func TestSetTwinPortYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.yaml")
+	orig := `# fleet
+twins:
+  stripe:
+    binary: ./bin/twin-stripe
+    port: 4111 # main
+  twilio:
+    binary: ./bin/twin-twilio
+`
+	if err := os.WriteFile(path, []byte(orig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTwinPort(path, "stripe", 4120); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTwinPort(path, "twilio", 4121); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"# fleet", "port: 4120 # main", "port: 4121"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in:\n%s", want, data)
+		}
+	}
+}
@@ -85,8 +85,8 @@ func allTools() []toolEntry {
 		{
 			Tool: Tool{
 				Name:        "wt_seed",
-				Description: "Seed a twin with fixture data by POSTing a JSON file to its /admin/state endpoint.",
-				InputSchema: json.RawMessage(`{"type": "object", "properties": {"twin": {"type": "string", "description": "Name of the twin to seed"}, "file": {"type": "string", "description": "Path to the JSON seed file"}}, "required": ["twin", "file"]}`),
+				Description: "Seed a twin with fixture data by POSTing a JSON file to its /admin/state endpoint. With dry_run, only check that references between the seed's collections resolve.",
+				InputSchema: json.RawMessage(`{"type": "object", "properties": {"twin": {"type": "string", "description": "Name of the twin to seed"}, "file": {"type": "string", "description": "Path to the JSON seed file"}, "dry_run": {"type": "boolean", "description": "Check the seed's references without loading it"}}, "required": ["twin", "file"]}`),
 			},
 			Handler: handleSeed,
 		},
@@ -122,7 +122,12 @@ func allTools() []toolEntry {
 // ---------------------------------------------------------------------------
 
 func handleUp(m *manifest.Manifest, ac *client.AdminClient, _ json.RawMessage) ToolResult {
-	pids, _ := procmgr.LoadPids()
+	unlock, err := procmgr.Lock(m.Path())
+	if err != nil {
+		return textResult(fmt.Sprintf("Error: %v", err))
+	}
+	defer unlock()
+	pids, _ := procmgr.LoadPids(m.Path())
 
 	var out strings.Builder
 	names := m.TwinNames()
@@ -130,6 +135,10 @@ func handleUp(m *manifest.Manifest, ac *client.AdminClient, _ json.RawMessage) T
 	for _, name := range names {
 		twin := m.Twins[name]
 
+		if twin.Remote() {
+			fmt.Fprintf(&out, "%-20s remote (%s)\n", name, twin.URL)
+			continue
+		}
 		if entry, ok := pids[name]; ok && procmgr.IsRunning(entry.PID) {
 			fmt.Fprintf(&out, "%-20s already running (pid %d)\n", name, entry.PID)
 			continue
@@ -142,14 +151,15 @@ func handleUp(m *manifest.Manifest, ac *client.AdminClient, _ json.RawMessage) T
 		}
 
 		pids[name] = procmgr.PidEntry{
-			PID:    pid,
-			Port:   twin.Port,
-			Binary: twin.Binary,
+			PID:       pid,
+			Port:      twin.Port,
+			Binary:    twin.Binary,
+			StartedAt: time.Now().UTC(),
 		}
 		fmt.Fprintf(&out, "%-20s started (pid %d, port %d)\n", name, pid, twin.Port)
 	}
 
-	if err := procmgr.SavePids(pids); err != nil {
+	if err := procmgr.SavePids(m.Path(), pids); err != nil {
 		fmt.Fprintf(&out, "\nError saving pid state: %v\n", err)
 	}
 
@@ -159,19 +169,24 @@ func handleUp(m *manifest.Manifest, ac *client.AdminClient, _ json.RawMessage) T
 	out.WriteString("\nHealth:\n")
 	for _, name := range names {
 		twin := m.Twins[name]
-		ok, _ := ac.Health(twin.AdminPort)
+		ok, _ := ac.Health(twin.AdminURL())
 		status := "healthy"
 		if !ok {
 			status = "unhealthy"
 		}
-		fmt.Fprintf(&out, "%-20s %s  http://localhost:%d\n", name, status, twin.Port)
+		fmt.Fprintf(&out, "%-20s %s  %s\n", name, status, twin.BaseURL())
 	}
 
 	return textResult(out.String())
 }
 
-func handleDown(_ *manifest.Manifest, _ *client.AdminClient, _ json.RawMessage) ToolResult {
-	pids, err := procmgr.LoadPids()
+func handleDown(m *manifest.Manifest, _ *client.AdminClient, _ json.RawMessage) ToolResult {
+	unlock, err := procmgr.Lock(m.Path())
+	if err != nil {
+		return textResult(fmt.Sprintf("Error: %v", err))
+	}
+	defer unlock()
+	pids, err := procmgr.LoadPids(m.Path())
 	if err != nil {
 		return textResult(fmt.Sprintf("Error loading pid state: %v", err))
 	}
@@ -193,13 +208,13 @@ func handleDown(_ *manifest.Manifest, _ *client.AdminClient, _ json.RawMessage)
 		}
 	}
 
-	procmgr.RemovePidFile()
+	procmgr.RemovePidFile(m.Path())
 	out.WriteString("\nAll twins stopped.")
 	return textResult(out.String())
 }
 
 func handleStatus(m *manifest.Manifest, ac *client.AdminClient, _ json.RawMessage) ToolResult {
-	pids, _ := procmgr.LoadPids()
+	pids, _ := procmgr.LoadPids(m.Path())
 
 	var out strings.Builder
 	fmt.Fprintf(&out, "%-20s %-8s %-7s %-11s %s\n", "TWIN", "PID", "PORT", "HEALTH", "URL")
@@ -207,12 +222,18 @@ func handleStatus(m *manifest.Manifest, ac *client.AdminClient, _ json.RawMessag
 
 	for _, name := range m.TwinNames() {
 		twin := m.Twins[name]
-		pidStr := "-"
+		pidStr, portStr := "-", fmt.Sprintf("%d", twin.Port)
 		health := "stopped"
 
-		if entry, ok := pids[name]; ok && procmgr.IsRunning(entry.PID) {
+		running := twin.Remote()
+		if twin.Remote() {
+			pidStr, portStr = "remote", "-"
+		} else if entry, ok := pids[name]; ok && procmgr.IsRunning(entry.PID) {
 			pidStr = fmt.Sprintf("%d", entry.PID)
-			ok, _ := ac.Health(twin.AdminPort)
+			running = true
+		}
+		if running {
+			ok, _ := ac.Health(twin.AdminURL())
 			if ok {
 				health = "healthy"
 			} else {
@@ -220,8 +241,8 @@ func handleStatus(m *manifest.Manifest, ac *client.AdminClient, _ json.RawMessag
 			}
 		}
 
-		fmt.Fprintf(&out, "%-20s %-8s %-7d %-11s http://localhost:%d\n",
-			name, pidStr, twin.Port, health, twin.Port)
+		fmt.Fprintf(&out, "%-20s %-8s %-7s %-11s %s\n",
+			name, pidStr, portStr, health, twin.BaseURL())
 	}
 
 	return textResult(out.String())
@@ -239,7 +260,7 @@ func handleReset(m *manifest.Manifest, ac *client.AdminClient, params json.RawMe
 		}
 	}
 
-	pids, _ := procmgr.LoadPids()
+	pids, _ := procmgr.LoadPids(m.Path())
 
 	names := m.TwinNames()
 	if p.Twin != "" {
@@ -253,12 +274,12 @@ func handleReset(m *manifest.Manifest, ac *client.AdminClient, params json.RawMe
 	var out strings.Builder
 	for _, name := range names {
 		twin := m.Twins[name]
-		if entry, ok := pids[name]; !ok || !procmgr.IsRunning(entry.PID) {
+		if entry, ok := pids[name]; !twin.Remote() && (!ok || !procmgr.IsRunning(entry.PID)) {
 			fmt.Fprintf(&out, "%-20s skipped (not running)\n", name)
 			continue
 		}
 
-		resp, err := ac.Reset(twin.AdminPort)
+		resp, err := ac.Reset(twin.AdminURL())
 		if err != nil {
 			fmt.Fprintf(&out, "%-20s FAILED - %v\n", name, err)
 		} else {
@@ -270,8 +291,9 @@ func handleReset(m *manifest.Manifest, ac *client.AdminClient, params json.RawMe
 }
 
 type seedParams struct {
-	Twin string `json:"twin"`
-	File string `json:"file"`
+	Twin   string `json:"twin"`
+	File   string `json:"file"`
+	DryRun bool   `json:"dry_run"`
 }
 
 func handleSeed(m *manifest.Manifest, ac *client.AdminClient, params json.RawMessage) ToolResult {
@@ -291,7 +313,23 @@ func handleSeed(m *manifest.Manifest, ac *client.AdminClient, params json.RawMes
 		return textResult(fmt.Sprintf("Error: %v", err))
 	}
 
-	resp, err := ac.Seed(twin.AdminPort, p.File)
+	if p.DryRun {
+		result, err := ac.LintSeed(twin.AdminURL(), p.File)
+		if err != nil {
+			return textResult(fmt.Sprintf("Error checking %s: %v", p.Twin, err))
+		}
+		if result.Valid {
+			return textResult(fmt.Sprintf("%s: all references resolve (%d relations checked, nothing loaded)", p.File, result.Relations))
+		}
+		var out strings.Builder
+		fmt.Fprintf(&out, "%s: %d broken reference(s); nothing was loaded\n", p.File, len(result.Problems))
+		for _, prob := range result.Problems {
+			fmt.Fprintf(&out, "  %s\n", prob)
+		}
+		return textResult(out.String())
+	}
+
+	resp, err := ac.Seed(twin.AdminURL(), p.File)
 	if err != nil {
 		return textResult(fmt.Sprintf("Error seeding %s: %v", p.Twin, err))
 	}
@@ -322,7 +360,7 @@ func handleInspect(m *manifest.Manifest, _ *client.AdminClient, params json.RawM
 
 	// GET /admin/state to retrieve current twin state
 	httpClient := &http.Client{Timeout: 5 * time.Second}
-	resp, err := httpClient.Get(fmt.Sprintf("http://localhost:%d/admin/state", twin.AdminPort))
+	resp, err := httpClient.Get(fmt.Sprintf("%s/admin/state", twin.AdminURL()))
 	if err != nil {
 		return textResult(fmt.Sprintf("Error inspecting %s: %v", p.Twin, err))
 	}
@@ -363,7 +401,7 @@ func handleConfig(m *manifest.Manifest, _ *client.AdminClient, params json.RawMe
 	if len(p.Updates) > 0 {
 		// PUT /admin/config with updates
 		body, _ := json.Marshal(p.Updates)
-		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("http://localhost:%d/admin/config", twin.AdminPort), strings.NewReader(string(body)))
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/admin/config", twin.AdminURL()), strings.NewReader(string(body)))
 		req.Header.Set("Content-Type", "application/json")
 
 		resp, err := httpClient.Do(req)
@@ -380,7 +418,7 @@ func handleConfig(m *manifest.Manifest, _ *client.AdminClient, params json.RawMe
 	}
 
 	// GET /admin/config
-	resp, err := httpClient.Get(fmt.Sprintf("http://localhost:%d/admin/config", twin.AdminPort))
+	resp, err := httpClient.Get(fmt.Sprintf("%s/admin/config", twin.AdminURL()))
 	if err != nil {
 		return textResult(fmt.Sprintf("Error fetching config for %s: %v", p.Twin, err))
 	}
@@ -433,7 +471,7 @@ func handleQuirks(m *manifest.Manifest, _ *client.AdminClient, params json.RawMe
 			return textResult(fmt.Sprintf("Error: unknown action %q (use 'enable' or 'disable')", p.Action))
 		}
 
-		req, _ := http.NewRequest(method, fmt.Sprintf("http://localhost:%d/admin/quirks/%s", twin.AdminPort, p.QuirkID), nil)
+		req, _ := http.NewRequest(method, fmt.Sprintf("%s/admin/quirks/%s", twin.AdminURL(), p.QuirkID), nil)
 		resp, err := httpClient.Do(req)
 		if err != nil {
 			return textResult(fmt.Sprintf("Error toggling quirk for %s: %v", p.Twin, err))
@@ -448,7 +486,7 @@ func handleQuirks(m *manifest.Manifest, _ *client.AdminClient, params json.RawMe
 	}
 
 	// GET /admin/quirks
-	resp, err := httpClient.Get(fmt.Sprintf("http://localhost:%d/admin/quirks", twin.AdminPort))
+	resp, err := httpClient.Get(fmt.Sprintf("%s/admin/quirks", twin.AdminURL()))
 	if err != nil {
 		return textResult(fmt.Sprintf("Error fetching quirks for %s: %v", p.Twin, err))
 	}
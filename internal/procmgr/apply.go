@@ -0,0 +1,176 @@
+package procmgr
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// Action is what wt apply does to bring one twin in line with the manifest.
+type Action string
+
+const (
+	ActionStart       Action = "start"       // in the manifest but not running
+	ActionStop        Action = "stop"        // running but removed from the manifest
+	ActionRestart     Action = "restart"     // a change that needs a new process
+	ActionReconfigure Action = "reconfigure" // a change pushed over the admin API
+)
+
+// Change is one step of an apply plan.
+type Change struct {
+	Twin    string
+	Action  Action
+	Reasons []string // human-readable descriptions of what changed
+
+	// Config holds the runtime updates for PUT /admin/config
+	// (reconfigure only).
+	Config map[string]any
+	// Reseed reports whether the twin must be reset onto its new seed file
+	// (reconfigure only).
+	Reseed bool
+	// Profile reports whether the twin's manifest config block changed;
+	// PrevProfile is the block it ran with (reconfigure only).
+	Profile     bool
+	PrevProfile *manifest.TwinConfig
+}
+
+// Plan diffs the running fleet against a manifest and returns the changes
+// needed to reconcile them, sorted by twin name with stops first. running
+// must contain only live processes. Changes to latency, fail rate, seed, and
+// the config block are applied in place; anything else that affects the
+// process restarts it. Remote twins are not managed by wt and never appear in the plan.
+func Plan(running PidMap, m *manifest.Manifest) []Change {
+	var changes []Change
+
+	for _, name := range sortedNames(running) {
+		if _, ok := m.Twins[name]; !ok {
+			changes = append(changes, Change{Twin: name, Action: ActionStop, Reasons: []string{"removed from manifest"}})
+		}
+	}
+
+	for _, name := range m.TwinNames() {
+		want := m.Twins[name]
+		if want.Remote() {
+			continue
+		}
+		entry, ok := running[name]
+		if !ok {
+			changes = append(changes, Change{Twin: name, Action: ActionStart, Reasons: []string{"not running"}})
+			continue
+		}
+		if c, changed := diffTwin(name, entry, want); changed {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// diffTwin compares a running twin with its desired manifest entry.
+func diffTwin(name string, entry PidEntry, want manifest.Twin) (Change, bool) {
+	have := specOf(entry, want)
+
+	var restart []string
+	if have.Binary != want.Binary {
+		restart = append(restart, fmt.Sprintf("binary %s → %s", have.Binary, want.Binary))
+	}
+	if have.Version != want.Version {
+		restart = append(restart, fmt.Sprintf("version %s → %s", orNone(have.Version), orNone(want.Version)))
+	}
+	if have.Port != want.Port {
+		restart = append(restart, fmt.Sprintf("port %d → %d", have.Port, want.Port))
+	}
+	if have.AdminPort != want.AdminPort {
+		restart = append(restart, fmt.Sprintf("admin port %d → %d", have.AdminPort, want.AdminPort))
+	}
+	if have.Host != want.Host {
+		restart = append(restart, fmt.Sprintf("host %s → %s", orNone(have.Host), orNone(want.Host)))
+	}
+	if have.Socket != want.Socket {
+		restart = append(restart, fmt.Sprintf("socket %s → %s", orNone(have.Socket), orNone(want.Socket)))
+	}
+	if !maps.Equal(have.Env, want.Env) {
+		restart = append(restart, "env changed")
+	}
+	if !reflect.DeepEqual(have.Limits, want.Limits) {
+		restart = append(restart, "limits changed")
+	}
+	if have.AdminReadOnly != want.AdminReadOnly {
+		restart = append(restart, fmt.Sprintf("admin_readonly %t → %t", have.AdminReadOnly, want.AdminReadOnly))
+	}
+	if len(restart) > 0 {
+		return Change{Twin: name, Action: ActionRestart, Reasons: restart}, true
+	}
+
+	c := Change{Twin: name, Action: ActionReconfigure, Config: map[string]any{}}
+	if durationOf(have.Latency) != durationOf(want.Latency) {
+		c.Config["latency"] = durationOf(want.Latency).String()
+		c.Reasons = append(c.Reasons, fmt.Sprintf("latency %s → %s", durationOf(have.Latency), durationOf(want.Latency)))
+	}
+	if have.FailRate != want.FailRate {
+		c.Config["fail_rate"] = want.FailRate
+		c.Reasons = append(c.Reasons, fmt.Sprintf("fail rate %g → %g", have.FailRate, want.FailRate))
+	}
+	if have.RandSeed != want.RandSeed {
+		c.Config["rand_seed"] = want.RandSeed
+		c.Reasons = append(c.Reasons, fmt.Sprintf("rand seed %d → %d", have.RandSeed, want.RandSeed))
+	}
+	if !reflect.DeepEqual(have.Config, want.Config) {
+		c.Profile, c.PrevProfile = true, have.Config
+		c.Reasons = append(c.Reasons, "config changed")
+	}
+	if have.Seed != want.Seed {
+		c.Reseed = true
+		c.Reasons = append(c.Reasons, fmt.Sprintf("seed %s → %s", orNone(have.Seed), orNone(want.Seed)))
+	}
+	// A read-only admin API refuses runtime updates, so apply them by restarting
+	if want.AdminReadOnly && len(c.Reasons) > 0 {
+		return Change{Twin: name, Action: ActionRestart, Reasons: c.Reasons}, true
+	}
+	return c, len(c.Reasons) > 0
+}
+
+// specOf returns the manifest entry a twin was started from. Entries without
+// a recorded spec only know their binary and port; every other field is
+// assumed to match the desired entry, except latency, fail rate, rand seed,
+// and config, which are assumed to be the twin defaults.
+func specOf(entry PidEntry, want manifest.Twin) manifest.Twin {
+	if entry.Spec != nil {
+		return *entry.Spec
+	}
+	have := want
+	have.Binary = entry.Binary
+	have.Port = entry.Port
+	have.Latency = ""
+	have.FailRate = 0
+	have.RandSeed = 0
+	have.Config = nil
+	return have
+}
+
+// durationOf parses a manifest latency; the manifest loader has already
+// rejected invalid values.
+func durationOf(s string) time.Duration {
+	d, _ := time.ParseDuration(s)
+	return d
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func sortedNames(pids PidMap) []string {
+	names := make([]string, 0, len(pids))
+	for name := range pids {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
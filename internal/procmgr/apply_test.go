@@ -0,0 +1,172 @@
+package procmgr
+
+import (
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+func running(twins map[string]manifest.Twin) PidMap {
+	pids := PidMap{}
+	for name, t := range twins {
+		spec := t
+		pids[name] = PidEntry{PID: 1, Port: t.Port, Binary: t.Binary, Spec: &spec}
+	}
+	return pids
+}
+
+func TestPlanNoChanges(t *testing.T) {
+	twins := map[string]manifest.Twin{
+		"stripe": {Binary: "/bin/twin-stripe", Port: 4111, AdminPort: 4111},
+	}
+	m := &manifest.Manifest{Twins: twins}
+
+	if changes := Plan(running(twins), m); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestPlanStartAndStop(t *testing.T) {
+	pids := running(map[string]manifest.Twin{
+		"twilio": {Binary: "/bin/twin-twilio", Port: 4112, AdminPort: 4112},
+	})
+	m := &manifest.Manifest{Twins: map[string]manifest.Twin{
+		"stripe": {Binary: "/bin/twin-stripe", Port: 4111, AdminPort: 4111},
+	}}
+
+	changes := Plan(pids, m)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Twin != "twilio" || changes[0].Action != ActionStop {
+		t.Errorf("expected twilio to be stopped first, got %+v", changes[0])
+	}
+	if changes[1].Twin != "stripe" || changes[1].Action != ActionStart {
+		t.Errorf("expected stripe to be started, got %+v", changes[1])
+	}
+}
+
+func TestPlanRestartOnProcessChange(t *testing.T) {
+	base := manifest.Twin{Binary: "/bin/twin-stripe", Port: 4111, AdminPort: 4111, Latency: "100ms"}
+	pids := running(map[string]manifest.Twin{"stripe": base})
+
+	want := base
+	want.Port = 4120
+	want.AdminPort = 4120
+	want.Latency = "200ms"
+	changes := Plan(pids, &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": want}})
+
+	if len(changes) != 1 || changes[0].Action != ActionRestart {
+		t.Fatalf("expected a restart, got %+v", changes)
+	}
+}
+
+func TestPlanReconfigure(t *testing.T) {
+	base := manifest.Twin{Binary: "/bin/twin-stripe", Port: 4111, AdminPort: 4111, Seed: "a.json"}
+	pids := running(map[string]manifest.Twin{"stripe": base})
+
+	want := base
+	want.Latency = "250ms"
+	want.FailRate = 0.1
+	want.RandSeed = 42
+	want.Seed = "b.json"
+	changes := Plan(pids, &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": want}})
+
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %+v", changes)
+	}
+	c := changes[0]
+	if c.Action != ActionReconfigure {
+		t.Fatalf("expected reconfigure, got %s", c.Action)
+	}
+	if c.Config["latency"] != "250ms" {
+		t.Errorf("expected latency 250ms, got %v", c.Config["latency"])
+	}
+	if c.Config["fail_rate"] != 0.1 {
+		t.Errorf("expected fail_rate 0.1, got %v", c.Config["fail_rate"])
+	}
+	if c.Config["rand_seed"] != uint64(42) {
+		t.Errorf("expected rand_seed 42, got %v", c.Config["rand_seed"])
+	}
+	if !c.Reseed {
+		t.Error("expected seed change to trigger a reseed")
+	}
+}
+
+func TestPlanConfigBlock(t *testing.T) {
+	base := manifest.Twin{Binary: "/bin/twin-stripe", Port: 4111, AdminPort: 4111,
+		Config: &manifest.TwinConfig{Quirks: []string{"a"}}}
+	pids := running(map[string]manifest.Twin{"stripe": base})
+
+	same := base
+	same.Config = &manifest.TwinConfig{Quirks: []string{"a"}}
+	if changes := Plan(pids, &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": same}}); len(changes) != 0 {
+		t.Errorf("expected an equal config block to be a no-op, got %+v", changes)
+	}
+
+	want := base
+	want.Config = &manifest.TwinConfig{Chaos: "flaky"}
+	changes := Plan(pids, &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": want}})
+	if len(changes) != 1 || changes[0].Action != ActionReconfigure || !changes[0].Profile {
+		t.Fatalf("expected a config reconfigure, got %+v", changes)
+	}
+	if prev := changes[0].PrevProfile; prev == nil || prev.Quirks[0] != "a" {
+		t.Errorf("expected the previous block, got %+v", prev)
+	}
+}
+
+func TestPlanEquivalentLatency(t *testing.T) {
+	base := manifest.Twin{Binary: "/bin/twin-stripe", Port: 4111, AdminPort: 4111, Latency: "1s"}
+	pids := running(map[string]manifest.Twin{"stripe": base})
+
+	want := base
+	want.Latency = "1000ms"
+	if changes := Plan(pids, &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": want}}); len(changes) != 0 {
+		t.Errorf("expected equivalent latencies to be a no-op, got %+v", changes)
+	}
+}
+
+func TestPlanLegacyEntryWithoutSpec(t *testing.T) {
+	pids := PidMap{"stripe": {PID: 1, Port: 4111, Binary: "/bin/twin-stripe"}}
+	want := manifest.Twin{Binary: "/bin/twin-stripe", Port: 4111, AdminPort: 4111, Seed: "seed.json", FailRate: 0.2}
+
+	changes := Plan(pids, &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": want}})
+	if len(changes) != 1 || changes[0].Action != ActionReconfigure {
+		t.Fatalf("expected a reconfigure, got %+v", changes)
+	}
+	if changes[0].Reseed {
+		t.Error("expected an unknown seed to be assumed unchanged")
+	}
+	if changes[0].Config["fail_rate"] != 0.2 {
+		t.Errorf("expected fail_rate to be pushed, got %v", changes[0].Config["fail_rate"])
+	}
+}
+
+func TestPlanSkipsRemoteTwins(t *testing.T) {
+	m := &manifest.Manifest{Twins: map[string]manifest.Twin{
+		"stripe": {URL: "https://stripe.twins.example.com"},
+	}}
+
+	if changes := Plan(PidMap{}, m); len(changes) != 0 {
+		t.Errorf("expected remote twins to be left alone, got %+v", changes)
+	}
+}
+
+func TestPlanReadOnlyAdminRestarts(t *testing.T) {
+	base := manifest.Twin{Binary: "/bin/twin-stripe", Port: 4111, AdminPort: 4111, AdminReadOnly: true}
+	pids := running(map[string]manifest.Twin{"stripe": base})
+
+	want := base
+	want.Latency = "250ms"
+	changes := Plan(pids, &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": want}})
+	if len(changes) != 1 || changes[0].Action != ActionRestart {
+		t.Fatalf("expected a read-only twin to be restarted, got %+v", changes)
+	}
+
+	want = base
+	want.AdminReadOnly = false
+	changes = Plan(pids, &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": want}})
+	if len(changes) != 1 || changes[0].Action != ActionRestart {
+		t.Fatalf("expected toggling admin_readonly to restart, got %+v", changes)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package procmgr
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// applyLimits sets rlimits on an already-started process via prlimit(2).
+// MemoryMB is not an rlimit; Start passes it to the twin as GOMEMLIMIT.
+func applyLimits(pid int, limits *manifest.Limits) error {
+	if limits == nil {
+		return nil
+	}
+	if limits.CPUSeconds > 0 {
+		if err := prlimit(pid, syscall.RLIMIT_CPU, uint64(limits.CPUSeconds)); err != nil {
+			return fmt.Errorf("setting cpu limit: %w", err)
+		}
+	}
+	if limits.MaxOpenFiles > 0 {
+		if err := prlimit(pid, syscall.RLIMIT_NOFILE, uint64(limits.MaxOpenFiles)); err != nil {
+			return fmt.Errorf("setting open file limit: %w", err)
+		}
+	}
+	return nil
+}
+
+func prlimit(pid, resource int, value uint64) error {
+	lim := syscall.Rlimit{Cur: value, Max: value}
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64,
+		uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(&lim)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package procmgr
+
+import (
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// applyLimits is unsupported outside Linux; Start logs a warning and continues.
+func applyLimits(pid int, limits *manifest.Limits) error {
+	if limits == nil || (limits.CPUSeconds == 0 && limits.MaxOpenFiles == 0) {
+		return nil
+	}
+	return errLimitsUnsupported
+}
@@ -0,0 +1,51 @@
+package procmgr
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// maxPortSearch bounds how far NextFreePort looks past the requested port.
+const maxPortSearch = 100
+
+// PortAvailable reports whether port can be bound on all interfaces, the
+// way twins listen.
+func PortAvailable(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// NextFreePort returns the first available port after port that is not in
+// reserved (ports other twins in the manifest will use).
+func NextFreePort(port int, reserved map[int]bool) (int, error) {
+	for p := port + 1; p <= port+maxPortSearch && p <= 65535; p++ {
+		if !reserved[p] && PortAvailable(p) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port in %d-%d", port+1, port+maxPortSearch)
+}
+
+// ApplyRunningPorts points manifest twins that wt up --auto-port moved to
+// another port at the port they are actually serving on, so every command
+// reaches the running twin. A twin whose manifest port has since been
+// edited keeps the manifest port, letting wt apply restart it there.
+func ApplyRunningPorts(m *manifest.Manifest, pids PidMap) {
+	for name, entry := range pids {
+		twin, ok := m.Twins[name]
+		if !ok || entry.RequestedPort == 0 || entry.RequestedPort != twin.Port || !IsRunning(entry.PID) {
+			continue
+		}
+		if twin.AdminPort == twin.Port {
+			twin.AdminPort = entry.Port
+		}
+		twin.Port = entry.Port
+		m.Twins[name] = twin
+	}
+}
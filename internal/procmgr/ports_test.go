@@ -0,0 +1,53 @@
+package procmgr
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+func TestNextFreePort(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	busy := l.Addr().(*net.TCPAddr).Port
+
+	if PortAvailable(busy) {
+		t.Errorf("expected port %d to be in use", busy)
+	}
+	port, err := NextFreePort(busy, map[int]bool{busy + 1: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port <= busy+1 {
+		t.Errorf("expected a port past the reserved %d, got %d", busy+1, port)
+	}
+}
+
+func TestApplyRunningPorts(t *testing.T) {
+	self := os.Getpid()
+	m := &manifest.Manifest{Twins: map[string]manifest.Twin{
+		"stripe": {Port: 4111, AdminPort: 4111},
+		"twilio": {Port: 4112, AdminPort: 4112},
+		"resend": {Port: 4200, AdminPort: 4200},
+	}}
+	ApplyRunningPorts(m, PidMap{
+		"stripe": {PID: self, Port: 4120, RequestedPort: 4111},
+		"twilio": {PID: self, Port: 4112},
+		"resend": {PID: self, Port: 4121, RequestedPort: 4113}, // manifest port edited since
+	})
+
+	if got := m.Twins["stripe"]; got.Port != 4120 || got.AdminPort != 4120 {
+		t.Errorf("expected stripe on its auto-assigned port, got %+v", got)
+	}
+	if got := m.Twins["twilio"]; got.Port != 4112 {
+		t.Errorf("expected twilio unchanged, got %+v", got)
+	}
+	if got := m.Twins["resend"]; got.Port != 4200 {
+		t.Errorf("expected an edited manifest port to win, got %+v", got)
+	}
+}
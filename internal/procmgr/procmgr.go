@@ -3,11 +3,12 @@
 package procmgr
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"syscall"
 	"time"
@@ -15,45 +16,36 @@ import (
 	"github.com/wondertwin-ai/wondertwin/internal/manifest"
 )
 
-const pidFileName = ".wt/pids.json"
+// errLimitsUnsupported is returned by applyLimits on platforms without prlimit.
+var errLimitsUnsupported = errors.New("resource limits are not supported on " + runtime.GOOS)
 
 // PidEntry tracks a running twin process.
 type PidEntry struct {
-	PID    int    `json:"pid"`
-	Port   int    `json:"port"`
-	Binary string `json:"binary"`
+	PID       int       `json:"pid"`
+	Port      int       `json:"port"`
+	Binary    string    `json:"binary"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+
+	// RequestedPort is the manifest port when wt up --auto-port moved the
+	// twin to Port because the requested one was taken.
+	RequestedPort int `json:"requested_port,omitempty"`
+
+	// Spec is the manifest entry the twin was started from, which wt apply
+	// diffs against the current manifest. Entries written by older versions
+	// of wt have no spec.
+	Spec *manifest.Twin `json:"spec,omitempty"`
 }
 
-// PidMap maps twin names to their PID entries.
-type PidMap map[string]PidEntry
-
-// LoadPids reads the PID tracking file. Returns an empty map if the file doesn't exist.
-func LoadPids() (PidMap, error) {
-	data, err := os.ReadFile(pidFileName)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return PidMap{}, nil
-		}
-		return nil, err
-	}
-	var pids PidMap
-	if err := json.Unmarshal(data, &pids); err != nil {
-		return nil, err
-	}
-	return pids, nil
+// ProcStats holds runtime metrics for a twin process.
+type ProcStats struct {
+	RSSBytes   int64         // resident set size
+	CPUPercent float64       // CPU utilisation averaged since the process started
+	OpenFDs    int           // open file descriptors, or -1 if unavailable
+	Uptime     time.Duration // time since the process started
 }
 
-// SavePids writes the PID tracking file.
-func SavePids(pids PidMap) error {
-	if err := os.MkdirAll(filepath.Dir(pidFileName), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(pids, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(pidFileName, data, 0o644)
-}
+// PidMap maps twin names to their PID entries.
+type PidMap map[string]PidEntry
 
 // Start launches a twin binary as a background process with output redirected to a log file.
 // Returns the process PID.
@@ -74,8 +66,14 @@ func Start(name string, twin manifest.Twin, logDir string, verbose bool) (int, e
 	}
 
 	// Build command arguments
-	args := []string{
-		"--port", strconv.Itoa(twin.Port),
+	var args []string
+	if twin.Socket != "" {
+		args = append(args, "--socket", twin.Socket)
+	} else {
+		args = append(args, "--port", strconv.Itoa(twin.Port))
+	}
+	if twin.Host != "" {
+		args = append(args, "--host", twin.Host)
 	}
 	if verbose {
 		args = append(args, "--verbose")
@@ -87,11 +85,29 @@ func Start(name string, twin manifest.Twin, logDir string, verbose bool) (int, e
 		}
 		args = append(args, "--seed-file", seedPath)
 	}
+	if twin.Latency != "" {
+		args = append(args, "--latency", twin.Latency)
+	}
+	if twin.FailRate > 0 {
+		args = append(args, "--fail-rate", strconv.FormatFloat(twin.FailRate, 'f', -1, 64))
+	}
+	if twin.RandSeed != 0 {
+		args = append(args, "--rand-seed", strconv.FormatUint(twin.RandSeed, 10))
+	}
+	if twin.AdminReadOnly {
+		args = append(args, "--admin-readonly")
+	}
+	args = append(args, twin.Limits.StoreArgs()...)
 
 	cmd := exec.Command(binary, args...)
 
-	// Inherit env and add twin-specific vars
+	// Inherit env and add twin-specific vars. An address-space rlimit would
+	// crash a Go twin, which reserves far more address space than it uses,
+	// so the memory limit is the Go runtime's own; the twin's env overrides it.
 	cmd.Env = os.Environ()
+	if twin.Limits != nil && twin.Limits.MemoryMB > 0 {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("GOMEMLIMIT=%dMiB", twin.Limits.MemoryMB))
+	}
 	for k, v := range twin.Env {
 		cmd.Env = append(cmd.Env, k+"="+v)
 	}
@@ -117,6 +133,18 @@ func Start(name string, twin manifest.Twin, logDir string, verbose bool) (int, e
 		return 0, fmt.Errorf("starting %s: %w", name, err)
 	}
 
+	// Apply resource limits. Unsupported platforms log a warning and continue;
+	// any other failure stops the twin rather than running it uncontained.
+	if err := applyLimits(cmd.Process.Pid, twin.Limits); err != nil {
+		if !errors.Is(err, errLimitsUnsupported) {
+			cmd.Process.Kill()
+			cmd.Wait()
+			logFile.Close()
+			return 0, fmt.Errorf("applying limits to %s: %w", name, err)
+		}
+		fmt.Fprintf(logFile, "wt: %v; continuing without limits\n", err)
+	}
+
 	// Detach: let the process run independently
 	go func() {
 		cmd.Wait()
@@ -167,8 +195,3 @@ func IsRunning(pid int) bool {
 	// On Unix, FindProcess always succeeds. Signal 0 probes existence.
 	return proc.Signal(syscall.Signal(0)) == nil
 }
-
-// RemovePidFile deletes the PID tracking file.
-func RemovePidFile() {
-	os.Remove(pidFileName)
-}
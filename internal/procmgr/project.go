@@ -0,0 +1,332 @@
+package procmgr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/internal/config"
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// PID state is kept per project under ~/.wondertwin/projects/<hash>/, where
+// hash is derived from the manifest's absolute path, so fleets started from
+// different manifests on one machine never see or clobber each other.
+const (
+	projectsDirName = "projects"
+	pidFileName     = "pids.json"
+	projectFileName = "project.json"
+	lockFileName    = "lock"
+)
+
+// legacyPidFile is where wt kept PID state, relative to the manifest
+// directory, before state was scoped per project. It is still read so
+// fleets started by older versions can be stopped.
+const legacyPidFile = ".wt/pids.json"
+
+// lockTimeout bounds how long Lock waits for another wt command.
+var lockTimeout = 30 * time.Second
+
+// ErrLocked is returned by Lock when another wt command holds the project
+// lock for longer than lockTimeout.
+var ErrLocked = errors.New("another wt command is managing this project")
+
+// Project describes one manifest's PID state, as listed by Projects.
+type Project struct {
+	Manifest string // absolute manifest path
+	Pids     PidMap
+}
+
+// projectInfo is the contents of project.json.
+type projectInfo struct {
+	Manifest string `json:"manifest"`
+}
+
+func projectsRoot() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("determining home directory: %w", err)
+	}
+	return filepath.Join(home, config.DefaultConfigDir, projectsDirName), nil
+}
+
+// projectDir returns the state directory for a manifest and the manifest's
+// absolute path.
+func projectDir(manifestPath string) (dir, abs string, err error) {
+	abs, err = manifest.ResolvePath(manifestPath)
+	if err != nil {
+		return "", "", fmt.Errorf("resolving manifest path: %w", err)
+	}
+	root, err := projectsRoot()
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(root, hex.EncodeToString(sum[:8])), abs, nil
+}
+
+// StateDir returns the directory wt keeps the manifest's project state in.
+// It may not exist yet.
+func StateDir(manifestPath string) (string, error) {
+	dir, _, err := projectDir(manifestPath)
+	return dir, err
+}
+
+// LoadPids reads the PID state of the manifest's project. Returns an empty
+// map if nothing has been started.
+func LoadPids(manifestPath string) (PidMap, error) {
+	dir, abs, err := projectDir(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	pids, err := readPids(filepath.Join(dir, pidFileName))
+	if err == nil && pids == nil {
+		pids, err = readPids(filepath.Join(filepath.Dir(abs), legacyPidFile))
+	}
+	if pids == nil && err == nil {
+		pids = PidMap{}
+	}
+	return pids, err
+}
+
+// readPids returns nil, nil when path does not exist.
+func readPids(path string) (PidMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var pids PidMap
+	if err := json.Unmarshal(data, &pids); err != nil {
+		return nil, err
+	}
+	if pids == nil {
+		pids = PidMap{}
+	}
+	return pids, nil
+}
+
+// SavePids writes the PID state of the manifest's project, migrating away
+// from the legacy per-directory file.
+func SavePids(manifestPath string, pids PidMap) error {
+	dir, abs, err := projectDir(manifestPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	info, err := json.MarshalIndent(projectInfo{Manifest: abs}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, projectFileName), info, 0o644); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(pids, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, pidFileName), data, 0o644); err != nil {
+		return err
+	}
+	os.Remove(filepath.Join(filepath.Dir(abs), legacyPidFile))
+	return nil
+}
+
+// RemovePidFile deletes the PID state of the manifest's project.
+func RemovePidFile(manifestPath string) {
+	dir, abs, err := projectDir(manifestPath)
+	if err != nil {
+		return
+	}
+	os.Remove(filepath.Join(dir, pidFileName))
+	os.Remove(filepath.Join(dir, projectFileName))
+	os.Remove(filepath.Join(filepath.Dir(abs), legacyPidFile))
+}
+
+// Lock takes an exclusive lock on the manifest's project so concurrent wt
+// commands cannot start or stop the same fleet at once. It waits up to
+// lockTimeout for a lock held elsewhere, then returns ErrLocked. Call the
+// returned function to release the lock.
+func Lock(manifestPath string) (func(), error) {
+	dir, _, err := projectDir(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	return lockDir(dir, lockTimeout)
+}
+
+// lockDir takes the lock of the project state directory dir, waiting up to
+// timeout for a lock held elsewhere.
+func lockDir(dir string, timeout time.Duration) (func(), error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, lockFileName), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("locking project state: %w", err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, ErrLocked
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return func() {
+		unlock(f)
+		f.Close()
+	}, nil
+}
+
+// Projects returns every project with PID state on this machine, sorted by
+// manifest path.
+func Projects() ([]Project, error) {
+	root, err := projectsRoot()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var projects []Project
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, e.Name(), projectFileName))
+		if err != nil {
+			continue
+		}
+		var info projectInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			continue
+		}
+		pids, err := readPids(filepath.Join(root, e.Name(), pidFileName))
+		if err != nil || len(pids) == 0 {
+			continue
+		}
+		projects = append(projects, Project{Manifest: info.Manifest, Pids: pids})
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Manifest < projects[j].Manifest })
+	return projects, nil
+}
+
+// Orphan is a PID entry whose process is no longer running, as found by
+// Prune.
+type Orphan struct {
+	Manifest string // absolute manifest path of the project
+	Twin     string
+	Entry    PidEntry
+}
+
+// Prune finds the PID entries of every project on this machine whose
+// process has exited, left behind when a twin crashed or the machine
+// rebooted without wt down, and unless dryRun removes them. State
+// directories left without entries are removed. Projects another wt
+// command is managing are skipped.
+func Prune(dryRun bool) ([]Orphan, error) {
+	root, err := projectsRoot()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var orphans []Orphan
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		found, err := pruneProject(filepath.Join(root, e.Name()), dryRun)
+		if err != nil {
+			return orphans, err
+		}
+		orphans = append(orphans, found...)
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Manifest != orphans[j].Manifest {
+			return orphans[i].Manifest < orphans[j].Manifest
+		}
+		return orphans[i].Twin < orphans[j].Twin
+	})
+	return orphans, nil
+}
+
+// pruneProject prunes the project whose state is in dir while holding its
+// lock, so it never races a wt up or wt down of that project.
+func pruneProject(dir string, dryRun bool) ([]Orphan, error) {
+	unlock, err := lockDir(dir, 0)
+	if errors.Is(err, ErrLocked) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var info projectInfo
+	if data, err := os.ReadFile(filepath.Join(dir, projectFileName)); err == nil {
+		json.Unmarshal(data, &info)
+	}
+	path := filepath.Join(dir, pidFileName)
+	pids, err := readPids(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var orphans []Orphan
+	for name, entry := range pids {
+		if !IsRunning(entry.PID) {
+			orphans = append(orphans, Orphan{Manifest: info.Manifest, Twin: name, Entry: entry})
+			delete(pids, name)
+		}
+	}
+	switch {
+	case dryRun:
+	case len(pids) == 0:
+		// Only the PID state goes, as in RemovePidFile. The lock file stays
+		// (removing it while locked would let a wt waiting on it and one
+		// creating a new file both take the lock), and so does other
+		// project state, such as the last test session wt report reads.
+		os.Remove(path)
+		os.Remove(filepath.Join(dir, projectFileName))
+	case len(orphans) > 0:
+		data, err := json.MarshalIndent(pids, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return orphans, nil
+}
@@ -0,0 +1,175 @@
+package procmgr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeProject creates a manifest in its own directory and points the home
+// directory at a temp dir so tests never touch real PID state.
+func writeProject(t *testing.T, home string) string {
+	t.Helper()
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wondertwin.json")
+	if err := os.WriteFile(path, []byte(`{"twins":{}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPidsScopedPerProject(t *testing.T) {
+	home := t.TempDir()
+	a, b := writeProject(t, home), writeProject(t, home)
+
+	if err := SavePids(a, PidMap{"stripe": {PID: 101, Port: 4111}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavePids(b, PidMap{"stripe": {PID: 202, Port: 5111}}); err != nil {
+		t.Fatal(err)
+	}
+
+	pa, err := LoadPids(a)
+	if err != nil || pa["stripe"].PID != 101 {
+		t.Errorf("project a: got %v, %v", pa, err)
+	}
+	pb, err := LoadPids(b)
+	if err != nil || pb["stripe"].PID != 202 {
+		t.Errorf("project b: got %v, %v", pb, err)
+	}
+
+	// Relative and absolute spellings of a path share state
+	rel, err := filepath.Rel(mustGetwd(t), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := LoadPids(rel); p["stripe"].PID != 101 {
+		t.Errorf("expected %s to resolve to project a, got %v", rel, p)
+	}
+
+	projects, err := Projects()
+	if err != nil || len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %v, %v", projects, err)
+	}
+
+	RemovePidFile(a)
+	if p, _ := LoadPids(a); len(p) != 0 {
+		t.Errorf("expected no pids after RemovePidFile, got %v", p)
+	}
+	if p, _ := LoadPids(b); len(p) != 1 {
+		t.Errorf("expected project b untouched, got %v", p)
+	}
+}
+
+func TestLoadPidsLegacyFile(t *testing.T) {
+	path := writeProject(t, t.TempDir())
+	legacy := filepath.Join(filepath.Dir(path), legacyPidFile)
+	os.MkdirAll(filepath.Dir(legacy), 0o755)
+	if err := os.WriteFile(legacy, []byte(`{"twilio":{"pid":303,"port":4112}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pids, err := LoadPids(path)
+	if err != nil || pids["twilio"].PID != 303 {
+		t.Fatalf("expected the legacy file to be read, got %v, %v", pids, err)
+	}
+
+	if err := SavePids(path, pids); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("expected SavePids to migrate away from %s", legacy)
+	}
+}
+
+func TestLock(t *testing.T) {
+	path := writeProject(t, t.TempDir())
+	old := lockTimeout
+	lockTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { lockTimeout = old })
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lock(path); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked while held, got %v", err)
+	}
+
+	other := writeProject(t, os.Getenv("HOME"))
+	unlockOther, err := Lock(other)
+	if err != nil {
+		t.Errorf("expected another project to lock independently, got %v", err)
+	} else {
+		unlockOther()
+	}
+
+	unlock()
+	unlock, err = Lock(path)
+	if err != nil {
+		t.Fatalf("expected the lock to be free after unlock, got %v", err)
+	}
+	unlock()
+}
+
+func TestPrune(t *testing.T) {
+	home := t.TempDir()
+	a, b := writeProject(t, home), writeProject(t, home)
+	const exited = 1 << 30 // no process has this PID
+	if err := SavePids(a, PidMap{"stripe": {PID: exited, Port: 4111}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavePids(b, PidMap{"stripe": {PID: os.Getpid(), Port: 5111}, "twilio": {PID: exited, Port: 5112}}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, _, err := projectDir(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "last-test.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	orphans, err := Prune(true)
+	if err != nil || len(orphans) != 2 {
+		t.Fatalf("dry run: expected 2 orphans, got %v, %v", orphans, err)
+	}
+	if p, _ := LoadPids(a); len(p) != 1 {
+		t.Errorf("dry run changed project a: %v", p)
+	}
+
+	if orphans, err = Prune(false); err != nil || len(orphans) != 2 {
+		t.Fatalf("expected 2 orphans, got %v, %v", orphans, err)
+	}
+	if p, _ := LoadPids(a); len(p) != 0 {
+		t.Errorf("expected project a emptied, got %v", p)
+	}
+	if p, _ := LoadPids(b); len(p) != 1 || p["stripe"].PID != os.Getpid() {
+		t.Errorf("expected only the running twin of project b kept, got %v", p)
+	}
+	if projects, _ := Projects(); len(projects) != 1 {
+		t.Errorf("expected only project b left, got %v", projects)
+	}
+	var kept []string
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		kept = append(kept, e.Name())
+	}
+	if want := []string{"last-test.json", lockFileName}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("expected project a to keep %v, got %v", want, kept)
+	}
+}
+
+func mustGetwd(t *testing.T) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wd
+}
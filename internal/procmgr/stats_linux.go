@@ -0,0 +1,108 @@
+//go:build linux
+
+package procmgr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is USER_HZ, which is 100 on every mainstream Linux platform.
+const clockTicks = 100
+
+// Stats reads runtime metrics for a process from /proc. CPUPercent is the
+// average since the process started.
+func Stats(pid int) (*ProcStats, error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+	ps, ok := parseProcStat(string(stat))
+	if !ok {
+		return nil, fmt.Errorf("malformed /proc/%d/stat", pid)
+	}
+
+	st := &ProcStats{
+		RSSBytes: ps.rssPages * int64(os.Getpagesize()),
+		OpenFDs:  -1,
+	}
+
+	if boot, err := bootTime(); err == nil {
+		started := boot.Add(time.Duration(ps.startTicks / clockTicks * float64(time.Second)))
+		st.Uptime = time.Since(started)
+	}
+	st.CPUPercent = averageCPU(ps.cpuTicks, st.Uptime)
+
+	if fds, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid)); err == nil {
+		st.OpenFDs = len(fds)
+	}
+
+	return st, nil
+}
+
+// procStat holds the fields Stats uses from /proc/<pid>/stat.
+type procStat struct {
+	cpuTicks   float64 // user plus system time
+	startTicks float64 // start time after boot
+	rssPages   int64
+}
+
+// parseProcStat parses the contents of /proc/<pid>/stat, reporting false
+// if it is malformed.
+func parseProcStat(s string) (procStat, bool) {
+	// The command name (field 2) may contain spaces; fields after it start past the last ')'.
+	idx := strings.LastIndexByte(s, ')')
+	if idx < 0 {
+		return procStat{}, false
+	}
+	fields := strings.Fields(s[idx+1:])
+	// fields[0] is field 3 (state); utime=14, stime=15, starttime=22, rss=24.
+	if len(fields) < 22 {
+		return procStat{}, false
+	}
+	utime, _ := strconv.ParseFloat(fields[11], 64)
+	stime, _ := strconv.ParseFloat(fields[12], 64)
+	startTicks, _ := strconv.ParseFloat(fields[19], 64)
+	rssPages, _ := strconv.ParseInt(fields[21], 10, 64)
+	return procStat{cpuTicks: utime + stime, startTicks: startTicks, rssPages: rssPages}, true
+}
+
+// averageCPU returns the CPU utilisation of a process that used cpuTicks
+// over uptime, as a percentage of one core.
+func averageCPU(cpuTicks float64, uptime time.Duration) float64 {
+	if secs := uptime.Seconds(); secs > 0 {
+		return cpuTicks / clockTicks / secs * 100
+	}
+	return 0
+}
+
+// bootTime reads the system boot time from /proc/stat.
+func bootTime() (time.Time, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+	return parseBootTime(f)
+}
+
+// parseBootTime reads the btime line of /proc/stat from r.
+func parseBootTime(r io.Reader) (time.Time, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "btime ") {
+			secs, err := strconv.ParseInt(strings.TrimSpace(line[len("btime "):]), 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(secs, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("btime not found in /proc/stat")
+}
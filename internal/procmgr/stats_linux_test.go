@@ -0,0 +1,49 @@
+//go:build linux
+
+package procmgr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProcStat(t *testing.T) {
+	// A command name with spaces and parentheses, utime 250, stime 50,
+	// starttime 1088940, rss 272 pages.
+	stat := "11185 (twin (x) y) S 11181 11185 11181 0 -1 4194304 77 0 0 0 250 50 0 0 20 0 1 0 1088940 2703360 272 18446744073709551615 0 0 0\n"
+	got, ok := parseProcStat(stat)
+	if !ok {
+		t.Fatal("expected the stat to parse")
+	}
+	if want := (procStat{cpuTicks: 300, startTicks: 1088940, rssPages: 272}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"", "11185 cat R 1 2", "11185 (cat) R 11181 11185"} {
+		if _, ok := parseProcStat(bad); ok {
+			t.Errorf("%q: expected a malformed stat", bad)
+		}
+	}
+}
+
+func TestAverageCPU(t *testing.T) {
+	// 300 ticks (3s of CPU) over 60s is 5% of a core.
+	if got := averageCPU(300, time.Minute); got != 5 {
+		t.Errorf("expected 5%%, got %v", got)
+	}
+	if got := averageCPU(300, 0); got != 0 {
+		t.Errorf("expected 0%% without uptime, got %v", got)
+	}
+}
+
+func TestParseBootTime(t *testing.T) {
+	stat := "cpu  1 2 3 4\nintr 5\nbtime 1700000000\nprocesses 42\n"
+	got, err := parseBootTime(strings.NewReader(stat))
+	if err != nil || !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("got %v, %v", got, err)
+	}
+	if _, err := parseBootTime(strings.NewReader("cpu 1 2 3\n")); err == nil {
+		t.Error("expected an error without a btime line")
+	}
+}
@@ -0,0 +1,49 @@
+//go:build !linux
+
+package procmgr
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stats reads runtime metrics for a process using ps(1).
+// Open file descriptors are not reported on this platform.
+func Stats(pid int) (*ProcStats, error) {
+	out, err := exec.Command("ps", "-o", "rss=,pcpu=,etime=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("running ps: %w", err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("unexpected ps output %q", strings.TrimSpace(string(out)))
+	}
+
+	rssKB, _ := strconv.ParseInt(fields[0], 10, 64)
+	cpu, _ := strconv.ParseFloat(fields[1], 64)
+
+	return &ProcStats{
+		RSSBytes:   rssKB * 1024,
+		CPUPercent: cpu,
+		OpenFDs:    -1,
+		Uptime:     parseEtime(fields[2]),
+	}, nil
+}
+
+// parseEtime parses ps elapsed time in the form [[dd-]hh:]mm:ss.
+func parseEtime(s string) time.Duration {
+	var days int
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		days, _ = strconv.Atoi(s[:i])
+		s = s[i+1:]
+	}
+	var total time.Duration
+	for _, part := range strings.Split(s, ":") {
+		n, _ := strconv.Atoi(part)
+		total = total*60 + time.Duration(n)
+	}
+	return total*time.Second + time.Duration(days)*24*time.Hour
+}
@@ -0,0 +1,41 @@
+package procmgr
+
+import (
+	"errors"
+	"time"
+)
+
+// Backoff bounds for WaitHealthy polling.
+const (
+	waitInitialBackoff = 50 * time.Millisecond
+	waitMaxBackoff     = time.Second
+)
+
+var (
+	// ErrExited is returned by WaitHealthy when the process exits before
+	// becoming healthy.
+	ErrExited = errors.New("process exited before becoming healthy")
+	// ErrWaitTimeout is returned by WaitHealthy when the timeout elapses.
+	ErrWaitTimeout = errors.New("not healthy before the wait timeout")
+)
+
+// WaitHealthy polls check with exponential backoff until it reports true,
+// the process with the given pid exits, or timeout elapses.
+func WaitHealthy(pid int, timeout time.Duration, check func() bool) error {
+	deadline := time.Now().Add(timeout)
+	backoff := waitInitialBackoff
+	for {
+		if check() {
+			return nil
+		}
+		if !IsRunning(pid) {
+			return ErrExited
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return ErrWaitTimeout
+		}
+		time.Sleep(min(backoff, remaining))
+		backoff = min(backoff*2, waitMaxBackoff)
+	}
+}
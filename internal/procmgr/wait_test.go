@@ -0,0 +1,42 @@
+package procmgr
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestWaitHealthy(t *testing.T) {
+	self := os.Getpid()
+
+	calls := 0
+	err := WaitHealthy(self, 5*time.Second, func() bool {
+		calls++
+		return calls == 3
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected success on the third check, got %v after %d", err, calls)
+	}
+
+	start := time.Now()
+	err = WaitHealthy(self, 200*time.Millisecond, func() bool { return false })
+	if !errors.Is(err, ErrWaitTimeout) {
+		t.Errorf("expected ErrWaitTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to stop near its timeout, took %s", elapsed)
+	}
+}
+
+func TestWaitHealthyExited(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	err := WaitHealthy(cmd.Process.Pid, 5*time.Second, func() bool { return false })
+	if !errors.Is(err, ErrExited) {
+		t.Errorf("expected ErrExited, got %v", err)
+	}
+}
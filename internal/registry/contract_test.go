@@ -103,6 +103,18 @@ func TestContractGoldenRegistryParseable(t *testing.T) {
 	if _, ok := twilio.Versions[twilio.Latest]; !ok {
 		t.Errorf("twilio latest %q not found in versions", twilio.Latest)
 	}
+
+	// Verify the wt entry
+	if reg.WT == nil {
+		t.Fatal("missing wt entry")
+	}
+	wt, ok := reg.WT.Versions[reg.WT.Latest]
+	if !ok {
+		t.Fatalf("wt latest %q not found in versions", reg.WT.Latest)
+	}
+	if len(wt.Checksums) != 2 || len(wt.BinaryURLs) != 2 || len(wt.Signatures) != 2 {
+		t.Errorf("expected 2 checksums, binary_urls, and signatures for wt, got %+v", wt)
+	}
 }
 
 // TestContractVersionResolution verifies that ResolveVersion works with the golden registry.
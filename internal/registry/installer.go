@@ -2,6 +2,7 @@ package registry
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/wondertwin-ai/wondertwin/internal/config"
@@ -93,6 +95,23 @@ func CheckTierAccess(twinName, resolvedVersion string, ver Version, cfg *config.
 	return nil
 }
 
+// CheckWTVersion verifies that the running wt release satisfies a version's
+// min_wt_version. Development builds ("dev" or empty) are not checked.
+func CheckWTVersion(twinName, resolvedVersion string, ver Version, wtVersion string) error {
+	if ver.MinWTVersion == "" || wtVersion == "" || wtVersion == "dev" {
+		return nil
+	}
+
+	if compareSemver(wtVersion, ver.MinWTVersion) < 0 {
+		return fmt.Errorf(
+			"twin-%s v%s requires wt %s or newer (running %s).\nUpgrade wt, or pin an older twin version.",
+			twinName, resolvedVersion, ver.MinWTVersion, wtVersion,
+		)
+	}
+
+	return nil
+}
+
 // IsAlreadyInstalled checks if a twin binary with the matching version is already present.
 func IsAlreadyInstalled(twinName, resolvedVersion, binaryDir string) bool {
 	binaryPath := filepath.Join(binaryDir, "twin-"+twinName)
@@ -112,6 +131,76 @@ func IsAlreadyInstalled(twinName, resolvedVersion, binaryDir string) bool {
 	return strings.TrimSpace(string(data)) == resolvedVersion
 }
 
+// StagingDir returns the directory under binaryDir where binaries are
+// installed while they await verification.
+func StagingDir(binaryDir string) string {
+	return filepath.Join(binaryDir, ".staging")
+}
+
+// PromoteStaged moves a verified twin binary and its .version sidecar from
+// stagingDir into binaryDir, marking it installed.
+func PromoteStaged(twinName, stagingDir, binaryDir string) error {
+	name := "twin-" + twinName
+	for _, f := range []string{name, name + ".version"} {
+		if err := os.Rename(filepath.Join(stagingDir, f), filepath.Join(binaryDir, f)); err != nil {
+			return fmt.Errorf("promoting %s: %w", f, err)
+		}
+	}
+	return nil
+}
+
+// QuarantineStaged moves a staged twin binary that failed verification into
+// quarantineDir/twin-<name>-<version> so it can be inspected but is never
+// picked up by wt up. Returns the quarantined binary path.
+func QuarantineStaged(twinName, resolvedVersion, stagingDir, quarantineDir string) (string, error) {
+	if err := os.MkdirAll(quarantineDir, 0o755); err != nil {
+		return "", fmt.Errorf("creating quarantine dir %s: %w", quarantineDir, err)
+	}
+	name := "twin-" + twinName
+	dest := filepath.Join(quarantineDir, name+"-"+resolvedVersion)
+	if err := moveFile(filepath.Join(stagingDir, name), dest); err != nil {
+		return "", fmt.Errorf("quarantining %s: %w", name, err)
+	}
+	os.Remove(filepath.Join(stagingDir, name+".version"))
+	return dest, nil
+}
+
+// rename is os.Rename, replaced in tests.
+var rename = os.Rename
+
+// moveFile moves src to dst. The quarantine directory may be on another
+// filesystem than the staging directory, where rename fails with EXDEV;
+// the file is then copied, with its mode, and src removed.
+func moveFile(src, dst string) error {
+	err := rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
 // InstallFromURL downloads a twin binary from a specific URL, verifies its
 // checksum, and saves it to binaryDir. Used by lock file installs where the
 // exact URL and checksum are known.
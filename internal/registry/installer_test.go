@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
 )
 
@@ -106,6 +107,70 @@ func TestIsAlreadyInstalled(t *testing.T) {
 	}
 }
 
+func TestPromoteAndQuarantineStaged(t *testing.T) {
+	binDir := t.TempDir()
+	staging := StagingDir(binDir)
+	os.MkdirAll(staging, 0o755)
+
+	stage := func(version string) {
+		os.WriteFile(filepath.Join(staging, "twin-stripe"), []byte("binary"), 0o755)
+		os.WriteFile(filepath.Join(staging, "twin-stripe.version"), []byte(version), 0o644)
+	}
+
+	stage("0.1.0")
+	if err := PromoteStaged("stripe", staging, binDir); err != nil {
+		t.Fatalf("PromoteStaged: %v", err)
+	}
+	if !IsAlreadyInstalled("stripe", "0.1.0", binDir) {
+		t.Error("promoted binary should be installed")
+	}
+
+	stage("0.2.0")
+	quarantineDir := filepath.Join(binDir, "quarantine")
+	dest, err := QuarantineStaged("stripe", "0.2.0", staging, quarantineDir)
+	if err != nil {
+		t.Fatalf("QuarantineStaged: %v", err)
+	}
+	if dest != filepath.Join(quarantineDir, "twin-stripe-0.2.0") {
+		t.Errorf("quarantine path = %q", dest)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Errorf("quarantined binary missing: %v", err)
+	}
+	if IsAlreadyInstalled("stripe", "0.2.0", binDir) || IsAlreadyInstalled("stripe", "0.2.0", staging) {
+		t.Error("quarantined binary must not be installed")
+	}
+	if !IsAlreadyInstalled("stripe", "0.1.0", binDir) {
+		t.Error("previously installed version should be untouched")
+	}
+}
+
+func TestQuarantineStagedAcrossFilesystems(t *testing.T) {
+	rename = func(src, dst string) error {
+		return &os.LinkError{Op: "rename", Old: src, New: dst, Err: syscall.EXDEV}
+	}
+	t.Cleanup(func() { rename = os.Rename })
+
+	staging := t.TempDir()
+	os.WriteFile(filepath.Join(staging, "twin-stripe"), []byte("binary"), 0o755)
+	os.WriteFile(filepath.Join(staging, "twin-stripe.version"), []byte("0.2.0"), 0o644)
+
+	dest, err := QuarantineStaged("stripe", "0.2.0", staging, t.TempDir())
+	if err != nil {
+		t.Fatalf("QuarantineStaged: %v", err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("expected the binary copied with its mode, got %v, %v", info, err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "binary" {
+		t.Errorf("quarantined binary = %q", data)
+	}
+	if entries, _ := os.ReadDir(staging); len(entries) != 0 {
+		t.Errorf("expected the staged files removed, got %v", entries)
+	}
+}
+
 func TestInstallFromURLHTTPError(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ParsePackSpec splits a "<twin>/<pack>" reference as used by
+// `wt test --pack`. An optional "@<version>" suffix on the twin selects a
+// specific release, e.g. "stripe@0.4.0/payments-happy-path".
+func ParsePackSpec(spec string) (twinName, versionSpec, packName string, err error) {
+	twinPart, packName, ok := strings.Cut(spec, "/")
+	if !ok || twinPart == "" || packName == "" {
+		return "", "", "", fmt.Errorf("invalid pack %q (expected <twin>/<pack>)", spec)
+	}
+	twinName, versionSpec, _ = strings.Cut(twinPart, "@")
+	return twinName, versionSpec, packName, nil
+}
+
+// FetchScenarioPack downloads a scenario pack for a twin release into
+// cacheDir, verifying its checksum, and returns the local path. A cached
+// copy is reused when its checksum still matches.
+func FetchScenarioPack(twinName, resolvedVersion, packName string, ver Version, cacheDir string) (string, error) {
+	pack, ok := ver.ScenarioPacks[packName]
+	if !ok {
+		return "", fmt.Errorf("twin %q v%s has no scenario pack %q%s", twinName, resolvedVersion, packName, availablePacks(ver))
+	}
+
+	dir := filepath.Join(cacheDir, twinName, resolvedVersion)
+	packPath := filepath.Join(dir, packName+".json")
+
+	if data, err := os.ReadFile(packPath); err == nil {
+		if pack.Checksum == "" || checksumOf(data) == pack.Checksum {
+			return packPath, nil
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating pack dir %s: %w", dir, err)
+	}
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Get(pack.URL)
+	if err != nil {
+		return "", fmt.Errorf("downloading scenario pack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("scenario pack download returned HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading scenario pack: %w", err)
+	}
+
+	if pack.Checksum != "" {
+		if actual := checksumOf(data); actual != pack.Checksum {
+			return "", fmt.Errorf("checksum mismatch: expected %s, got %s", pack.Checksum, actual)
+		}
+	}
+
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing scenario pack to %s: %w", packPath, err)
+	}
+	return packPath, nil
+}
+
+func checksumOf(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+// availablePacks formats the pack names of a version for error messages.
+func availablePacks(ver Version) string {
+	if len(ver.ScenarioPacks) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(ver.ScenarioPacks))
+	for name := range ver.ScenarioPacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return " (available: " + strings.Join(names, ", ") + ")"
+}
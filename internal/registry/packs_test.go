@@ -0,0 +1,100 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePackSpec(t *testing.T) {
+	tests := []struct {
+		spec, twin, version, pack string
+		wantErr                   bool
+	}{
+		{spec: "stripe/payments-happy-path", twin: "stripe", pack: "payments-happy-path"},
+		{spec: "stripe@0.4.0/payments-happy-path", twin: "stripe", version: "0.4.0", pack: "payments-happy-path"},
+		{spec: "stripe", wantErr: true},
+		{spec: "/pack", wantErr: true},
+		{spec: "stripe/", wantErr: true},
+	}
+	for _, tt := range tests {
+		twin, version, pack, err := ParsePackSpec(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePackSpec(%q): expected error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePackSpec(%q): %v", tt.spec, err)
+			continue
+		}
+		if twin != tt.twin || version != tt.version || pack != tt.pack {
+			t.Errorf("ParsePackSpec(%q) = (%q, %q, %q), want (%q, %q, %q)",
+				tt.spec, twin, version, pack, tt.twin, tt.version, tt.pack)
+		}
+	}
+}
+
+func TestFetchScenarioPack(t *testing.T) {
+	content := []byte(`{"name":"happy","scenarios":[]}`)
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	ver := Version{ScenarioPacks: map[string]ScenarioPack{
+		"happy": {URL: srv.URL + "/pack.json", Checksum: checksumOf(content)},
+	}}
+	dir := t.TempDir()
+
+	path, err := FetchScenarioPack("stripe", "0.1.0", "happy", ver, dir)
+	if err != nil {
+		t.Fatalf("FetchScenarioPack: %v", err)
+	}
+	if want := filepath.Join(dir, "stripe", "0.1.0", "happy.json"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading pack: %v", err)
+	}
+	if string(data) != string(content) {
+		t.Errorf("pack content = %q", data)
+	}
+
+	// Second fetch is served from the cache.
+	if _, err := FetchScenarioPack("stripe", "0.1.0", "happy", ver, dir); err != nil {
+		t.Fatalf("FetchScenarioPack (cached): %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 download, got %d", hits)
+	}
+}
+
+func TestFetchScenarioPackChecksumMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tampered"))
+	}))
+	defer srv.Close()
+
+	ver := Version{ScenarioPacks: map[string]ScenarioPack{
+		"happy": {URL: srv.URL, Checksum: "sha256:0000"},
+	}}
+	if _, err := FetchScenarioPack("stripe", "0.1.0", "happy", ver, t.TempDir()); err == nil {
+		t.Fatal("expected checksum mismatch error")
+	}
+}
+
+func TestFetchScenarioPackUnknown(t *testing.T) {
+	ver := Version{ScenarioPacks: map[string]ScenarioPack{"happy": {URL: "http://unused"}}}
+	_, err := FetchScenarioPack("stripe", "0.1.0", "sad", ver, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "available: happy") {
+		t.Fatalf("expected unknown pack error listing available packs, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,6 +22,17 @@ const DefaultRegistryURL = "https://raw.githubusercontent.com/wondertwin-ai/regi
 type Registry struct {
 	SchemaVersion int                  `yaml:"schema_version" json:"schema_version"`
 	Twins         map[string]TwinEntry `yaml:"twins" json:"twins"`
+
+	// WT lists releases of the wt CLI itself, for wt self update.
+	WT *CLIEntry `yaml:"wt,omitempty" json:"wt,omitempty"`
+}
+
+// CLIEntry describes the wt releases available in the registry. Its
+// versions carry only release metadata, checksums, binary URLs, and
+// signatures; the SDK and tier fields do not apply.
+type CLIEntry struct {
+	Latest   string             `yaml:"latest" json:"latest"`
+	Versions map[string]Version `yaml:"versions" json:"versions"`
 }
 
 // TwinEntry describes a twin available in the registry.
@@ -31,6 +43,10 @@ type TwinEntry struct {
 	Author      string             `yaml:"author" json:"author"`
 	Latest      string             `yaml:"latest" json:"latest"`
 	Versions    map[string]Version `yaml:"versions" json:"versions"`
+
+	// Deprecated twins still resolve, but wt warns and points at ReplacedBy.
+	Deprecated bool   `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
+	ReplacedBy string `yaml:"replaced_by,omitempty" json:"replaced_by,omitempty"`
 }
 
 // Version describes a specific release of a twin.
@@ -42,6 +58,28 @@ type Version struct {
 	Tier       string            `yaml:"tier" json:"tier"`
 	Checksums  map[string]string `yaml:"checksums" json:"checksums"`
 	BinaryURLs map[string]string `yaml:"binary_urls" json:"binary_urls"`
+
+	// Signatures are base64 ed25519 signatures of the binaries, keyed by
+	// platform like Checksums. wt self update requires them when wt was
+	// built with a release key; see VerifySignature.
+	Signatures map[string]string `yaml:"signatures,omitempty" json:"signatures,omitempty"`
+
+	ScenarioPacks map[string]ScenarioPack `yaml:"scenario_packs,omitempty" json:"scenario_packs,omitempty"`
+	ReleaseNotes  string                  `yaml:"release_notes,omitempty" json:"release_notes,omitempty"`
+
+	// Yanked versions are skipped when resolving "latest" or "sdk:" specs.
+	// An exact pin still resolves, with a warning.
+	Yanked     bool   `yaml:"yanked,omitempty" json:"yanked,omitempty"`
+	YankReason string `yaml:"yank_reason,omitempty" json:"yank_reason,omitempty"`
+
+	// MinWTVersion is the oldest wt release able to run this version.
+	MinWTVersion string `yaml:"min_wt_version,omitempty" json:"min_wt_version,omitempty"`
+}
+
+// ScenarioPack points at a bundle of scenarios published with a release.
+type ScenarioPack struct {
+	URL      string `yaml:"url" json:"url"`
+	Checksum string `yaml:"checksum,omitempty" json:"checksum,omitempty"`
 }
 
 // FetchRegistry downloads and parses the registry from the given URL.
@@ -142,27 +180,54 @@ func fetchAndParse(url, token string) (*Registry, error) {
 
 // ResolveVersion looks up a twin in the registry and resolves the version spec.
 // The versionSpec may be:
-//   - "latest" — resolves to the entry's latest version
-//   - "0.4.0"  — exact match
-//   - "sdk:github.com/stripe/stripe-go/v76" — newest version targeting this SDK package
+//   - "latest" — resolves to the entry's latest version, or the newest
+//     non-yanked version if latest has been yanked
+//   - "0.4.0"  — exact match (yanked versions are still returned)
+//   - "sdk:github.com/stripe/stripe-go/v76" — newest non-yanked version targeting this SDK package
+//   - "^1.2", "~0.3.1", ">=1.0 <2.0" — newest non-yanked release in the semver range
+//
+// Use ResolveWarnings to report yanked or deprecated resolutions to the user.
 func (r *Registry) ResolveVersion(twinName, versionSpec string) (string, Version, error) {
 	entry, ok := r.Twins[twinName]
 	if !ok {
 		return "", Version{}, fmt.Errorf("twin %q not found in registry", twinName)
 	}
+	return resolveEntry(twinName, entry, versionSpec)
+}
 
+// ResolveWT resolves a version spec against the registry's wt releases, as
+// ResolveVersion does for twins (SDK specs do not apply).
+func (r *Registry) ResolveWT(versionSpec string) (string, Version, error) {
+	if r.WT == nil || len(r.WT.Versions) == 0 {
+		return "", Version{}, fmt.Errorf("registry lists no wt releases")
+	}
+	if strings.HasPrefix(versionSpec, "sdk:") {
+		return "", Version{}, fmt.Errorf("wt versions cannot be resolved by SDK")
+	}
+	return resolveEntry("wt", TwinEntry{Latest: r.WT.Latest, Versions: r.WT.Versions}, versionSpec)
+}
+
+// resolveEntry resolves versionSpec against entry; see ResolveVersion.
+func resolveEntry(twinName string, entry TwinEntry, versionSpec string) (string, Version, error) {
 	// SDK resolution: find newest version matching an SDK package
 	if strings.HasPrefix(versionSpec, "sdk:") {
 		sdkPackage := strings.TrimPrefix(versionSpec, "sdk:")
 		return resolveBySDK(twinName, entry, sdkPackage)
 	}
 
+	if IsVersionRange(versionSpec) {
+		return resolveByRange(twinName, entry, versionSpec)
+	}
+
 	resolvedVersion := versionSpec
 	if versionSpec == "latest" || versionSpec == "" {
 		if entry.Latest == "" {
 			return "", Version{}, fmt.Errorf("twin %q has no latest version defined", twinName)
 		}
 		resolvedVersion = entry.Latest
+		if ver, ok := entry.Versions[resolvedVersion]; ok && ver.Yanked {
+			return resolveNewest(twinName, entry, func(string, Version) bool { return true })
+		}
 	}
 
 	ver, ok := entry.Versions[resolvedVersion]
@@ -173,28 +238,103 @@ func (r *Registry) ResolveVersion(twinName, versionSpec string) (string, Version
 	return resolvedVersion, ver, nil
 }
 
-// resolveBySDK finds the newest version of a twin targeting the given SDK package.
+// resolveBySDK finds the newest non-yanked version of a twin targeting the given SDK package.
 func resolveBySDK(twinName string, entry TwinEntry, sdkPackage string) (string, Version, error) {
+	v, ver, err := resolveNewest(twinName, entry, func(_ string, ver Version) bool { return ver.SDKPackage == sdkPackage })
+	if err != nil {
+		return "", Version{}, fmt.Errorf("twin %q has no version targeting SDK %q", twinName, sdkPackage)
+	}
+	return v, ver, nil
+}
+
+// resolveByRange finds the newest non-yanked version of a twin satisfying a semver range.
+func resolveByRange(twinName string, entry TwinEntry, spec string) (string, Version, error) {
+	r, err := parseRange(spec)
+	if err != nil {
+		return "", Version{}, err
+	}
+	v, ver, err := resolveNewest(twinName, entry, func(v string, _ Version) bool { return r.matches(v) })
+	if err != nil {
+		return "", Version{}, fmt.Errorf("twin %q has no version matching %q", twinName, spec)
+	}
+	return v, ver, nil
+}
+
+// resolveNewest returns the newest non-yanked version accepted by match.
+// Ties between equivalent spellings ("1.0.0" and "v1.0.0") break on the
+// string so resolution is deterministic.
+func resolveNewest(twinName string, entry TwinEntry, match func(string, Version) bool) (string, Version, error) {
 	var bestVersion string
 	var bestVer Version
 
 	for v, ver := range entry.Versions {
-		if ver.SDKPackage != sdkPackage {
+		if ver.Yanked || !match(v, ver) {
 			continue
 		}
-		if bestVersion == "" || compareSemver(v, bestVersion) > 0 {
+		cmp := compareSemver(v, bestVersion)
+		if bestVersion == "" || cmp > 0 || (cmp == 0 && v > bestVersion) {
 			bestVersion = v
 			bestVer = ver
 		}
 	}
 
 	if bestVersion == "" {
-		return "", Version{}, fmt.Errorf("twin %q has no version targeting SDK %q", twinName, sdkPackage)
+		return "", Version{}, fmt.Errorf("twin %q has no non-yanked versions", twinName)
 	}
 
 	return bestVersion, bestVer, nil
 }
 
+// ResolveWarnings returns user-facing warnings for resolving versionSpec to
+// resolvedVersion: yanked versions that were skipped or explicitly pinned,
+// and deprecation of the twin itself.
+func (r *Registry) ResolveWarnings(twinName, versionSpec, resolvedVersion string) []string {
+	entry, ok := r.Twins[twinName]
+	if !ok {
+		return nil
+	}
+
+	var warnings []string
+	if ver := entry.Versions[resolvedVersion]; ver.Yanked {
+		w := fmt.Sprintf("twin-%s v%s has been yanked", twinName, resolvedVersion)
+		if ver.YankReason != "" {
+			w += ": " + ver.YankReason
+		}
+		warnings = append(warnings, w)
+	} else if versionSpec == "latest" || versionSpec == "" || strings.HasPrefix(versionSpec, "sdk:") || IsVersionRange(versionSpec) {
+		sdkPackage, bySDK := strings.CutPrefix(versionSpec, "sdk:")
+		var r versionRange
+		if IsVersionRange(versionSpec) {
+			r, _ = parseRange(versionSpec)
+		}
+		var skipped []string
+		for v, ver := range entry.Versions {
+			if !ver.Yanked || compareSemver(v, resolvedVersion) <= 0 {
+				continue
+			}
+			if bySDK && ver.SDKPackage != sdkPackage || r != nil && !r.matches(v) {
+				continue
+			}
+			skipped = append(skipped, "v"+v)
+		}
+		if len(skipped) > 0 {
+			sort.Slice(skipped, func(i, j int) bool { return compareSemver(skipped[i], skipped[j]) < 0 })
+			warnings = append(warnings, fmt.Sprintf("skipped yanked twin-%s %s; using v%s",
+				twinName, strings.Join(skipped, ", "), resolvedVersion))
+		}
+	}
+
+	if entry.Deprecated {
+		w := fmt.Sprintf("twin %q is deprecated", twinName)
+		if entry.ReplacedBy != "" {
+			w += fmt.Sprintf("; use %q instead", entry.ReplacedBy)
+		}
+		warnings = append(warnings, w)
+	}
+
+	return warnings
+}
+
 // compareSemver does a simple lexicographic comparison of dotted version strings.
 // Returns >0 if a > b, <0 if a < b, 0 if equal.
 func compareSemver(a, b string) int {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -124,3 +125,91 @@ func TestResolveVersion(t *testing.T) {
 		t.Errorf("expected tier free, got %q", v.Tier)
 	}
 }
+
+func yankedRegistry() *Registry {
+	return &Registry{
+		SchemaVersion: 1,
+		Twins: map[string]TwinEntry{
+			"stripe": {
+				Latest:     "0.5.0",
+				Deprecated: true,
+				ReplacedBy: "stripe-connect",
+				Versions: map[string]Version{
+					"0.3.0": {SDKPackage: "github.com/stripe/stripe-go/v76"},
+					"0.4.0": {SDKPackage: "github.com/stripe/stripe-go/v76"},
+					"0.5.0": {SDKPackage: "github.com/stripe/stripe-go/v76", Yanked: true, YankReason: "broken payouts"},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveVersionSkipsYanked(t *testing.T) {
+	reg := yankedRegistry()
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"latest", "0.4.0"},
+		{"sdk:github.com/stripe/stripe-go/v76", "0.4.0"},
+		{"0.5.0", "0.5.0"}, // exact pins still resolve
+	}
+	for _, tt := range tests {
+		got, _, err := reg.ResolveVersion("stripe", tt.spec)
+		if err != nil {
+			t.Fatalf("ResolveVersion(%q) error: %v", tt.spec, err)
+		}
+		if got != tt.want {
+			t.Errorf("ResolveVersion(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestResolveWarnings(t *testing.T) {
+	reg := yankedRegistry()
+
+	warnings := reg.ResolveWarnings("stripe", "latest", "0.4.0")
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], "skipped yanked twin-stripe v0.5.0") {
+		t.Errorf("unexpected yank warning: %q", warnings[0])
+	}
+	if !strings.Contains(warnings[1], `use "stripe-connect" instead`) {
+		t.Errorf("unexpected deprecation warning: %q", warnings[1])
+	}
+
+	warnings = reg.ResolveWarnings("stripe", "0.5.0", "0.5.0")
+	if len(warnings) == 0 || !strings.Contains(warnings[0], "yanked: broken payouts") {
+		t.Errorf("expected pinned yank warning, got %v", warnings)
+	}
+
+	if w := sampleRegistry().ResolveWarnings("stripe", "latest", "0.4.0"); len(w) != 0 {
+		t.Errorf("expected no warnings, got %v", w)
+	}
+}
+
+func TestCheckWTVersion(t *testing.T) {
+	ver := Version{MinWTVersion: "0.5.0"}
+
+	tests := []struct {
+		wtVersion string
+		wantErr   bool
+	}{
+		{"0.4.9", true},
+		{"v0.5.0", false},
+		{"0.10.0", false},
+		{"dev", false},
+	}
+	for _, tt := range tests {
+		err := CheckWTVersion("stripe", "1.0.0", ver, tt.wtVersion)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckWTVersion(%q) error = %v, wantErr %v", tt.wtVersion, err, tt.wantErr)
+		}
+	}
+
+	if err := CheckWTVersion("stripe", "1.0.0", Version{}, "0.1.0"); err != nil {
+		t.Errorf("expected no error without min_wt_version, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package registry
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// VerifySignature checks a base64 ed25519 signature of data against a
+// base64 public key.
+func VerifySignature(data []byte, signature, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("release key must be a base64 ed25519 public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), data, sig) {
+		return fmt.Errorf("signature does not match the release key")
+	}
+	return nil
+}
+
+// SelfUpdate downloads the wt binary of a release for the current platform,
+// verifies its checksum and, when publicKey is set, its signature, and
+// replaces the executable at exePath with it. The release must publish a
+// checksum; with a release key it must also publish a signature, so a
+// registry entry cannot downgrade verification by leaving one out.
+func SelfUpdate(resolvedVersion string, ver Version, exePath, publicKey string) error {
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+
+	binaryURL, ok := ver.BinaryURLs[platform]
+	if !ok {
+		return fmt.Errorf("wt v%s has no binary for platform %s", resolvedVersion, platform)
+	}
+	expectedChecksum, ok := ver.Checksums[platform]
+	if !ok {
+		return fmt.Errorf("wt v%s publishes no checksum for platform %s", resolvedVersion, platform)
+	}
+	signature, signed := ver.Signatures[platform]
+	if publicKey != "" && !signed {
+		return fmt.Errorf("wt v%s publishes no signature for platform %s", resolvedVersion, platform)
+	}
+
+	fmt.Printf("  Downloading wt v%s (%s)...\n", resolvedVersion, platform)
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(binaryURL)
+	if err != nil {
+		return fmt.Errorf("downloading binary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download returned HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading binary data: %w", err)
+	}
+
+	fmt.Printf("  Verifying checksum...\n")
+	actual := fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+	if actual != expectedChecksum {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", expectedChecksum, actual)
+	}
+	if publicKey != "" {
+		fmt.Printf("  Verifying signature...\n")
+		if err := VerifySignature(data, signature, publicKey); err != nil {
+			return err
+		}
+	}
+
+	return ReplaceExecutable(exePath, data)
+}
+
+// ReplaceExecutable atomically replaces the executable at path with data:
+// the new binary is written next to it and renamed over it, so a failed or
+// interrupted update leaves the old binary in place. Windows cannot
+// overwrite a running executable, so there the old one is first moved
+// aside to path.old, which the next update removes.
+func ReplaceExecutable(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	old := path + ".old"
+	os.Remove(old)
+
+	tmp, err := os.CreateTemp(dir, ".wt-update-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary binary in %s: %w", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing temporary binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing temporary binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return fmt.Errorf("making binary executable: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("moving the running binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		if runtime.GOOS == "windows" {
+			os.Rename(old, path)
+		}
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+	return nil
+}
+
+// IsNewer reports whether version is newer than current, the running wt
+// version. Development builds ("dev" or empty) are older than any release.
+func IsNewer(version, current string) bool {
+	if current == "" || current == "dev" {
+		return true
+	}
+	return compareSemver(version, current) > 0
+}
@@ -0,0 +1,141 @@
+package registry
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// wtRelease serves a wt binary and returns a release of it for the current
+// platform, signed with a new key, and that key.
+func wtRelease(t *testing.T, binary []byte) (Version, string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(binary)
+	}))
+	t.Cleanup(srv.Close)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	return Version{
+		Checksums:  map[string]string{platform: fmt.Sprintf("sha256:%x", sha256.Sum256(binary))},
+		BinaryURLs: map[string]string{platform: srv.URL + "/wt-" + platform},
+		Signatures: map[string]string{platform: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, binary))},
+	}, base64.StdEncoding.EncodeToString(pub)
+}
+
+func TestSelfUpdate(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho wt 0.2.0\n")
+	ver, key := wtRelease(t, binary)
+	exe := filepath.Join(t.TempDir(), "wt")
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SelfUpdate("0.2.0", ver, exe, key); err != nil {
+		t.Fatalf("SelfUpdate: %v", err)
+	}
+	data, err := os.ReadFile(exe)
+	if err != nil || string(data) != string(binary) {
+		t.Errorf("expected the binary replaced, got %q, %v", data, err)
+	}
+	if info, _ := os.Stat(exe); info.Mode()&0o111 == 0 {
+		t.Error("binary is not executable")
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(exe), ".wt-update-*")); len(leftovers) != 0 {
+		t.Errorf("expected no temporary files left, got %v", leftovers)
+	}
+}
+
+func TestSelfUpdateRejectsUnverifiedBinaries(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho wt 0.2.0\n")
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	_, otherKey := wtRelease(t, binary)
+
+	for name, tc := range map[string]struct {
+		edit func(*Version)
+		key  string
+		want string
+	}{
+		"wrong key":      {key: otherKey, want: "signature does not match"},
+		"unsigned":       {edit: func(v *Version) { v.Signatures = nil }, want: "no signature"},
+		"no checksum":    {edit: func(v *Version) { v.Checksums = nil }, want: "no checksum"},
+		"bad checksum":   {edit: func(v *Version) { v.Checksums[platform] = "sha256:00" }, want: "checksum mismatch"},
+		"other platform": {edit: func(v *Version) { v.BinaryURLs = map[string]string{"plan9-386": "x"} }, want: "no binary"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ver, key := wtRelease(t, binary)
+			if tc.edit != nil {
+				tc.edit(&ver)
+			}
+			if tc.key != "" {
+				key = tc.key
+			}
+			exe := filepath.Join(t.TempDir(), "wt")
+			os.WriteFile(exe, []byte("old"), 0o755)
+
+			err := SelfUpdate("0.2.0", ver, exe, key)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected an error containing %q, got %v", tc.want, err)
+			}
+			if data, _ := os.ReadFile(exe); string(data) != "old" {
+				t.Errorf("expected the old binary kept, got %q", data)
+			}
+		})
+	}
+}
+
+func TestSelfUpdateWithoutKeyChecksChecksum(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho wt 0.2.0\n")
+	ver, _ := wtRelease(t, binary)
+	ver.Signatures = nil
+	exe := filepath.Join(t.TempDir(), "wt")
+	os.WriteFile(exe, []byte("old"), 0o755)
+
+	if err := SelfUpdate("0.2.0", ver, exe, ""); err != nil {
+		t.Fatalf("SelfUpdate: %v", err)
+	}
+}
+
+func TestResolveWT(t *testing.T) {
+	reg := &Registry{WT: &CLIEntry{Latest: "0.3.0", Versions: map[string]Version{
+		"0.2.0": {},
+		"0.3.0": {Yanked: true},
+	}}}
+	if v, _, err := reg.ResolveWT(""); err != nil || v != "0.2.0" {
+		t.Errorf("expected latest to skip the yanked release, got %q, %v", v, err)
+	}
+	if v, _, err := reg.ResolveWT("0.3.0"); err != nil || v != "0.3.0" {
+		t.Errorf("expected an exact pin to resolve, got %q, %v", v, err)
+	}
+	if _, _, err := (&Registry{}).ResolveWT(""); err == nil {
+		t.Error("expected a registry without wt releases to fail")
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	for _, tc := range []struct {
+		version, current string
+		want             bool
+	}{
+		{"0.2.0", "0.1.9", true},
+		{"0.2.0", "0.2.0", false},
+		{"0.2.0", "v0.10.0", false},
+		{"0.2.0", "dev", true},
+	} {
+		if got := IsNewer(tc.version, tc.current); got != tc.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tc.version, tc.current, got, tc.want)
+		}
+	}
+}
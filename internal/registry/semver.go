@@ -0,0 +1,203 @@
+package registry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed MAJOR.MINOR.PATCH[-PRERELEASE] version. Build metadata
+// is ignored.
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// parseSemver parses a version such as "1.2.3", "v0.4.0-rc.1", or the
+// partial forms "1" and "1.2". parts reports how many numeric components
+// were given so range operators can tell "^1.2" from "^1.2.0".
+func parseSemver(s string) (v semver, parts int, err error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, v.pre, _ = strings.Cut(s, "-")
+
+	fields := strings.Split(s, ".")
+	if len(fields) > 3 || s == "" {
+		return semver{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	nums := [3]int{}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return semver{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		nums[i] = n
+	}
+	v.major, v.minor, v.patch = nums[0], nums[1], nums[2]
+	return v, len(fields), nil
+}
+
+// compare returns >0 if a > b, <0 if a < b, 0 if equal. A prerelease sorts
+// before the release it precedes; prerelease identifiers compare lexically.
+func (a semver) compare(b semver) int {
+	if a.major != b.major {
+		return a.major - b.major
+	}
+	if a.minor != b.minor {
+		return a.minor - b.minor
+	}
+	if a.patch != b.patch {
+		return a.patch - b.patch
+	}
+	switch {
+	case a.pre == b.pre:
+		return 0
+	case a.pre == "":
+		return 1
+	case b.pre == "":
+		return -1
+	}
+	return strings.Compare(a.pre, b.pre)
+}
+
+// comparator is a single "<op> <version>" constraint.
+type comparator struct {
+	op string
+	v  semver
+}
+
+func (c comparator) matches(v semver) bool {
+	cmp := v.compare(c.v)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	}
+	return cmp == 0
+}
+
+// versionRange is a set of alternatives ("||"), each a list of comparators
+// that must all match.
+type versionRange [][]comparator
+
+// IsVersionRange reports whether a manifest version spec is a semver range
+// (e.g. "^1.2", "~0.3.1", ">=1.0 <2.0") rather than an exact version,
+// "latest", or an "sdk:" spec.
+func IsVersionRange(spec string) bool {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || strings.HasPrefix(spec, "sdk:") {
+		return false
+	}
+	return strings.ContainsAny(spec[:1], "^~<>=") || strings.Contains(spec, " ") || strings.Contains(spec, "||")
+}
+
+// parseRange parses a range expression. Supported forms:
+//   - "^1.2.3" — compatible with 1.2.3: >=1.2.3 <2.0.0 (or <0.(N+1).0 for 0.N)
+//   - "~1.2.3" — patch updates only: >=1.2.3 <1.3.0 ("~1" allows minor updates)
+//   - ">=1.0 <2.0" — space-separated comparators, all of which must match;
+//     a partial version covers all it names, as in npm: ">1.2" is >=1.3.0,
+//     "<=1.2" is <1.3.0, and "=1.2" is >=1.2.0 <1.3.0
+//   - "^1.0 || ^2.0" — alternatives
+func parseRange(spec string) (versionRange, error) {
+	var r versionRange
+	for _, alt := range strings.Split(spec, "||") {
+		var set []comparator
+		for _, term := range strings.Fields(alt) {
+			cs, err := parseTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %w", spec, err)
+			}
+			set = append(set, cs...)
+		}
+		if len(set) == 0 {
+			return nil, fmt.Errorf("invalid version range %q: empty alternative", spec)
+		}
+		r = append(r, set)
+	}
+	return r, nil
+}
+
+func parseTerm(term string) ([]comparator, error) {
+	switch {
+	case strings.HasPrefix(term, "^"):
+		v, parts, err := parseSemver(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		upper := semver{major: v.major + 1}
+		switch {
+		case v.major == 0 && parts >= 2 && (v.minor > 0 || parts == 2):
+			upper = semver{minor: v.minor + 1}
+		case v.major == 0 && v.minor == 0 && parts == 3:
+			upper = semver{patch: v.patch + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+
+	case strings.HasPrefix(term, "~"):
+		v, parts, err := parseSemver(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		upper := semver{major: v.major, minor: v.minor + 1}
+		if parts == 1 {
+			upper = semver{major: v.major + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+	}
+
+	op := "="
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+	v, parts, err := parseSemver(strings.TrimPrefix(term, op))
+	if err != nil {
+		return nil, err
+	}
+	// As in npm, a partial version stands for every version it names:
+	// ">1.2" excludes all of 1.2.x, and "=1.2" allows any of them.
+	if parts < 3 {
+		next := semver{major: v.major + 1}
+		if parts == 2 {
+			next = semver{major: v.major, minor: v.minor + 1}
+		}
+		switch op {
+		case ">":
+			return []comparator{{">=", next}}, nil
+		case "<=":
+			return []comparator{{"<", next}}, nil
+		case "=":
+			return []comparator{{">=", v}, {"<", next}}, nil
+		}
+	}
+	return []comparator{{op, v}}, nil
+}
+
+// matches reports whether version satisfies the range. Prereleases never
+// match a range; pin them exactly instead.
+func (r versionRange) matches(version string) bool {
+	v, _, err := parseSemver(version)
+	if err != nil || v.pre != "" {
+		return false
+	}
+	for _, set := range r {
+		ok := true
+		for _, c := range set {
+			if !c.matches(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
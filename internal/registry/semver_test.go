@@ -0,0 +1,123 @@
+package registry
+
+import "testing"
+
+func TestIsVersionRange(t *testing.T) {
+	tests := []struct {
+		spec string
+		want bool
+	}{
+		{"^1.2", true},
+		{"~0.3.1", true},
+		{">=1.0 <2.0", true},
+		{"^1.0 || ^2.0", true},
+		{"1.2.3", false},
+		{"latest", false},
+		{"", false},
+		{"sdk:github.com/stripe/stripe-go/v76", false},
+	}
+	for _, tt := range tests {
+		if got := IsVersionRange(tt.spec); got != tt.want {
+			t.Errorf("IsVersionRange(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestRangeMatches(t *testing.T) {
+	tests := []struct {
+		spec    string
+		version string
+		want    bool
+	}{
+		{"^1.2", "1.2.0", true},
+		{"^1.2", "1.9.3", true},
+		{"^1.2", "2.0.0", false},
+		{"^1.2", "1.1.9", false},
+		{"^0.3.1", "0.3.5", true},
+		{"^0.3.1", "0.4.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~0.3.1", "0.3.9", true},
+		{"~0.3.1", "0.3.0", false},
+		{"~0.3.1", "0.4.0", false},
+		{"~1", "1.5.0", true},
+		{">=1.0 <2.0", "1.4.2", true},
+		{">=1.0 <2.0", "2.0.0", false},
+		{">1.0.0", "1.0.0", false},
+		{"<=1.0", "1.0.0", true},
+		{"<=1.0", "1.0.9", true},
+		{"<=1.0", "1.1.0", false},
+		{">1.2", "1.2.1", false},
+		{">1.2", "1.3.0", true},
+		{">1", "1.9.9", false},
+		{">1", "2.0.0", true},
+		{">=1.2", "1.2.0", true},
+		{"<1.2", "1.1.9", true},
+		{"<1.2", "1.2.0", false},
+		{"=1.2", "1.2.7", true},
+		{"=1.2", "1.3.0", false},
+		{"=1", "1.4.0", true},
+		{"=0.4.0", "v0.4.0", true},
+		{"^1.0 || ^3.0", "3.1.0", true},
+		{"^1.0 || ^3.0", "2.1.0", false},
+		{"^1.0", "1.1.0-rc.1", false}, // prereleases never match ranges
+	}
+	for _, tt := range tests {
+		r, err := parseRange(tt.spec)
+		if err != nil {
+			t.Fatalf("parseRange(%q) error: %v", tt.spec, err)
+		}
+		if got := r.matches(tt.version); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.spec, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, spec := range []string{"^", "~x.y", ">=1.0 <", "^1.0 ||", ">=1.2.3.4"} {
+		if _, err := parseRange(spec); err == nil {
+			t.Errorf("parseRange(%q) expected error", spec)
+		}
+	}
+}
+
+func TestResolveVersionRange(t *testing.T) {
+	reg := &Registry{
+		Twins: map[string]TwinEntry{
+			"stripe": {
+				Latest: "1.0.0",
+				Versions: map[string]Version{
+					"0.3.0":        {},
+					"0.3.4":        {},
+					"0.4.0":        {},
+					"0.4.1":        {Yanked: true},
+					"0.5.0-beta.1": {},
+					"1.0.0":        {},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"^0.3", "0.3.4"},
+		{"~0.3.1", "0.3.4"},
+		{"^0.4", "0.4.0"}, // 0.4.1 is yanked
+		{">=0.3 <1.0", "0.4.0"},
+		{">=0.1", "1.0.0"},
+	}
+	for _, tt := range tests {
+		got, _, err := reg.ResolveVersion("stripe", tt.spec)
+		if err != nil {
+			t.Fatalf("ResolveVersion(%q) error: %v", tt.spec, err)
+		}
+		if got != tt.want {
+			t.Errorf("ResolveVersion(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+
+	if _, _, err := reg.ResolveVersion("stripe", "^2.0"); err == nil {
+		t.Error("expected error for unsatisfiable range")
+	}
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+)
+
+// Coverage is how much of a twin's API the session exercised: which of the
+// routes the twin serves got at least one request.
+type Coverage struct {
+	Covered   int                `json:"covered"`
+	Total     int                `json:"total"`
+	Endpoints []EndpointCoverage `json:"endpoints"`
+}
+
+// EndpointCoverage is one route of a twin and the requests the session
+// made to it. Exercised is set when a scenario step or a logged request
+// hit it; Requests counts only the logged ones, so an endpoint a reset
+// cleared from the log can be exercised with zero requests.
+type EndpointCoverage struct {
+	Method    string `json:"method"`
+	Route     string `json:"route"`
+	Requests  int    `json:"requests"`
+	Exercised bool   `json:"exercised"`
+}
+
+// Untested returns the endpoints the session did not exercise.
+func (c *Coverage) Untested() []EndpointCoverage {
+	var out []EndpointCoverage
+	for _, e := range c.Endpoints {
+		if !e.Exercised {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// buildCoverage correlates the session's scenario requests to td.BaseURL
+// and the twin's logged requests with its routes. requests are the logged
+// ones made during the session. It returns nil for twins that do not list
+// their routes.
+func buildCoverage(session *Session, td TwinData, requests []adminclient.RequestLogEntry) *Coverage {
+	if td.Routes == nil {
+		return nil
+	}
+	c := &Coverage{Total: len(td.Routes), Endpoints: make([]EndpointCoverage, 0, len(td.Routes))}
+	index := map[string]int{} // method + " " + route -> index in c.Endpoints
+	matchers := make([]routeMatcher, 0, len(td.Routes))
+	for _, rt := range td.Routes {
+		index[rt.Method+" "+rt.Route] = len(c.Endpoints)
+		c.Endpoints = append(c.Endpoints, EndpointCoverage{Method: rt.Method, Route: rt.Route})
+		matchers = append(matchers, newRouteMatcher(rt.Route))
+	}
+
+	for _, e := range requests {
+		if i, ok := index[e.Method+" "+e.Route]; ok && e.Route != "" {
+			c.Endpoints[i].Requests++
+			c.Endpoints[i].Exercised = true
+		}
+	}
+
+	// Scenario steps also count: a reset clears the twin's request log, so
+	// it does not hold every request the scenarios made.
+	base := strings.TrimSuffix(td.BaseURL, "/")
+	for _, sc := range session.Scenarios {
+		for _, st := range sc.Steps {
+			if base == "" || st.Method == "" || !strings.HasPrefix(st.URL, base) {
+				continue
+			}
+			u, err := url.Parse(st.URL[len(base):])
+			if err != nil {
+				continue
+			}
+			path := u.Path
+			if path == "" {
+				path = "/"
+			}
+			for i, m := range matchers {
+				if c.Endpoints[i].Method == st.Method && m.match(path) {
+					c.Endpoints[i].Exercised = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, e := range c.Endpoints {
+		if e.Exercised {
+			c.Covered++
+		}
+	}
+	return c
+}
+
+// routeMatcher matches request paths against a chi route pattern, where
+// "{name}" or "{name:regexp}" matches one path segment and a trailing "*"
+// matches the rest of the path.
+type routeMatcher struct {
+	segments []string
+	patterns []*regexp.Regexp // per segment; nil for a literal or a plain parameter
+	wildcard bool
+}
+
+func newRouteMatcher(route string) routeMatcher {
+	var m routeMatcher
+	if prefix, ok := strings.CutSuffix(route, "*"); ok {
+		route, m.wildcard = prefix, true
+	}
+	m.segments = strings.Split(route, "/")
+	m.patterns = make([]*regexp.Regexp, len(m.segments))
+	for i, seg := range m.segments {
+		if name, ok := strings.CutPrefix(seg, "{"); ok && strings.HasSuffix(name, "}") {
+			if _, expr, ok := strings.Cut(strings.TrimSuffix(name, "}"), ":"); ok {
+				m.patterns[i], _ = regexp.Compile("^(?:" + expr + ")$")
+			}
+		}
+	}
+	return m
+}
+
+func (m routeMatcher) match(path string) bool {
+	parts := strings.Split(path, "/")
+	if m.wildcard {
+		// The last segment of a wildcard pattern is the empty one before
+		// the "*", which matches whatever follows.
+		if len(parts) < len(m.segments) {
+			return false
+		}
+		parts = parts[:len(m.segments)-1]
+		return m.matchSegments(m.segments[:len(m.segments)-1], parts)
+	}
+	if len(parts) != len(m.segments) {
+		return false
+	}
+	return m.matchSegments(m.segments, parts)
+}
+
+func (m routeMatcher) matchSegments(segments, parts []string) bool {
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if parts[i] == "" || (m.patterns[i] != nil && !m.patterns[i].MatchString(parts[i])) {
+				return false
+			}
+			continue
+		}
+		if seg != parts[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ms":       func(ms int64) string { return (time.Duration(ms) * time.Millisecond).String() },
+	"stamp":    stamp,
+	"statuses": sortedStatuses,
+	"label": func(passed bool) string {
+		if passed {
+			return "PASS"
+		}
+		return "FAIL"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>WonderTwin test report</title>
+<style>
+body { font: 14px/1.4 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.5em; } h2 { font-size: 1.2em; margin-top: 2em; }
+table { border-collapse: collapse; margin: .5em 0; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+.PASS { color: #1a7f37; font-weight: bold; } .FAIL { color: #cf222e; font-weight: bold; }
+.muted { color: #777; } code { font-size: 13px; }
+</style>
+</head>
+<body>
+<h1>WonderTwin test report</h1>
+<p class="muted">Session {{stamp .Session.StartedAt}} – {{stamp .Session.FinishedAt}}; generated {{stamp .GeneratedAt}}</p>
+
+<table>
+<tr><th>Scenarios</th><td>{{.Summary.Scenarios}} ({{.Summary.ScenariosFailed}} failed)</td></tr>
+<tr><th>Steps</th><td>{{.Summary.Steps}} ({{.Summary.StepsFailed}} failed)</td></tr>
+<tr><th>API requests</th><td>{{.Summary.Requests}}</td></tr>
+<tr><th>Fault activations</th><td>{{.Summary.FaultActivations}}</td></tr>
+<tr><th>Webhooks</th><td>{{.Summary.WebhooksDelivered}} delivered, {{.Summary.WebhooksFailed}} failed</td></tr>
+<tr><th>Errors</th><td>{{.Summary.Errors}}</td></tr>
+{{if .Summary.Endpoints}}<tr><th>Endpoints exercised</th><td>{{.Summary.EndpointsCovered}} of {{.Summary.Endpoints}}</td></tr>
+{{end}}</table>
+
+<h2>Scenarios</h2>
+{{range .Session.Scenarios}}
+<h3><span class="{{label .Passed}}">{{label .Passed}}</span> {{.Name}} <span class="muted">({{ms .DurationMS}})</span></h3>
+{{if .Error}}<p class="FAIL">{{.Error}}</p>{{end}}
+{{if .Steps}}<table>
+<tr><th></th><th>Step</th><th>Status</th><th>Duration</th><th>Error</th></tr>
+{{range .Steps}}<tr><td class="{{label .Passed}}">{{label .Passed}}</td><td>{{.Name}}</td><td>{{if .StatusCode}}{{.StatusCode}}{{end}}</td><td>{{ms .DurationMS}}</td><td>{{.Error}}</td></tr>
+{{end}}</table>{{end}}
+{{else}}<p class="muted">No scenarios ran.</p>
+{{end}}
+
+<h2>Twins</h2>
+<table>
+<tr><th>Twin</th><th>API requests</th><th>By status</th><th>Webhooks delivered</th><th>Failed</th><th>Dead-lettered</th></tr>
+{{range .Twins}}<tr><td>{{.Name}}</td>{{if .Unreachable}}<td colspan="5" class="FAIL">unreachable: {{.Unreachable}}</td>{{else}}<td>{{.Requests}}</td><td>{{range statuses .ByStatus}}{{.}} {{end}}</td><td>{{.Webhooks.Delivered}}</td><td>{{.Webhooks.Failed}}</td><td>{{.Webhooks.DeadLettered}}</td>{{end}}</tr>
+{{end}}</table>
+
+<h2>Endpoint coverage</h2>
+{{range .Twins}}{{if .Coverage}}
+<h3>{{.Name}} <span class="muted">({{.Coverage.Covered}}/{{.Coverage.Total}} endpoints exercised)</span></h3>
+{{with .Coverage.Untested}}<table>
+<tr><th>Untested endpoint</th></tr>
+{{range .}}<tr><td class="FAIL"><code>{{.Method}} {{.Route}}</code></td></tr>
+{{end}}</table>{{else}}<p class="muted">Every endpoint was exercised.</p>{{end}}
+{{end}}{{end}}
+
+<h2>Faults</h2>
+<table>
+<tr><th>Twin</th><th>Endpoint</th><th>Injected</th><th>Removed</th><th>Activations</th></tr>
+{{range $t := .Twins}}{{range .Faults}}<tr><td>{{$t.Name}}</td><td><code>{{.Endpoint}}</code></td><td>{{stamp .InjectedAt}}</td><td>{{if .RemovedAt}}{{stamp .RemovedAt}}{{else}}<span class="muted">still active</span>{{end}}</td><td>{{.Hits}}</td></tr>
+{{end}}{{end}}</table>
+
+<h2>Errors</h2>
+{{if .Errors}}<table>
+<tr><th>Source</th><th>Twin</th><th>At</th><th>Message</th></tr>
+{{range .Errors}}<tr><td>{{.Source}}</td><td>{{.Twin}}</td><td>{{if .At}}{{stamp .At}}{{end}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">None.</p>{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes r as a self-contained HTML page.
+func (r *Report) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, r)
+}
+
+// stamp formats a time.Time or *time.Time for the page.
+func stamp(t any) string {
+	switch t := t.(type) {
+	case time.Time:
+		return t.Format("2006-01-02 15:04:05.000")
+	case *time.Time:
+		return stamp(*t)
+	}
+	return ""
+}
+
+// sortedStatuses formats a by-status count as "2xx: 12", in status order.
+func sortedStatuses(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = fmt.Sprintf("%s: %d", k, counts[k])
+	}
+	return out
+}
@@ -0,0 +1,311 @@
+// Package report collates a test session for `wt report`: the scenario
+// results `wt test` recorded, and what each twin's admin API shows happened
+// while they ran — requests served, faults injected and how often they
+// fired, webhook deliveries, and errors — into one JSON or HTML artifact
+// for CI runs to keep.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/scenario/v2"
+)
+
+// sessionFileName is where SaveSession keeps the last test session, in the
+// project's state directory.
+const sessionFileName = "last-test.json"
+
+// Session is one `wt test` run: when it ran and how each scenario did.
+type Session struct {
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt time.Time        `json:"finished_at"`
+	Scenarios  []ScenarioResult `json:"scenarios"`
+}
+
+// ScenarioResult is how one scenario of a session did. Error is set when
+// the scenario could not run at all.
+type ScenarioResult struct {
+	Name       string       `json:"name"`
+	Passed     bool         `json:"passed"`
+	DurationMS int64        `json:"duration_ms"`
+	Error      string       `json:"error,omitempty"`
+	Steps      []StepResult `json:"steps,omitempty"`
+}
+
+// StepResult is how one scenario step did.
+type StepResult struct {
+	Name       string `json:"name"`
+	Passed     bool   `json:"passed"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Method     string `json:"method,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// NewScenarioResult records a scenario's outcome as the runner returned it.
+func NewScenarioResult(name string, result *v2.Result, err error) ScenarioResult {
+	if err != nil {
+		return ScenarioResult{Name: name, Error: err.Error()}
+	}
+	sr := ScenarioResult{Name: name, Passed: result.Passed, DurationMS: result.Duration.Milliseconds()}
+	for _, st := range result.Steps {
+		sr.Steps = append(sr.Steps, StepResult{
+			Name:       st.Name,
+			Passed:     st.Passed,
+			DurationMS: st.Duration.Milliseconds(),
+			Error:      st.Error,
+			StatusCode: st.StatusCode,
+			Method:     st.Method,
+			URL:        st.URL,
+		})
+	}
+	return sr
+}
+
+// SaveSession writes s to dir, replacing the session saved before it.
+func SaveSession(dir string, s *Session) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, sessionFileName), data, 0o644)
+}
+
+// LoadSession reads the session SaveSession last wrote to dir. The error
+// wraps os.ErrNotExist when none has been.
+func LoadSession(dir string) (*Session, error) {
+	data, err := os.ReadFile(filepath.Join(dir, sessionFileName))
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("reading test session: %w", err)
+	}
+	return &s, nil
+}
+
+// TwinData is what a twin's admin API returned for the report. Err is set
+// instead when the twin could not be reached. Routes is nil for twins that
+// do not list them, which leaves their coverage out; BaseURL is where the
+// scenarios reached the twin's API.
+type TwinData struct {
+	Name        string
+	BaseURL     string
+	Routes      []adminclient.Route
+	Requests    []adminclient.RequestLogEntry
+	Webhooks    *adminclient.Webhooks
+	DeadLetters []adminclient.DeadLetter
+	Err         error
+}
+
+// Report is a test session collated across twins.
+type Report struct {
+	GeneratedAt time.Time    `json:"generated_at"`
+	Session     *Session     `json:"session"`
+	Summary     Summary      `json:"summary"`
+	Twins       []TwinReport `json:"twins"`
+	Errors      []Problem    `json:"errors"`
+}
+
+// Summary totals a report.
+type Summary struct {
+	Scenarios         int `json:"scenarios"`
+	ScenariosFailed   int `json:"scenarios_failed"`
+	Steps             int `json:"steps"`
+	StepsFailed       int `json:"steps_failed"`
+	Requests          int `json:"requests"`
+	FaultActivations  int `json:"fault_activations"`
+	WebhooksDelivered int `json:"webhooks_delivered"`
+	WebhooksFailed    int `json:"webhooks_failed"`
+	Errors            int `json:"errors"`
+	Endpoints         int `json:"endpoints"`
+	EndpointsCovered  int `json:"endpoints_covered"`
+}
+
+// TwinReport is one twin's activity during the session. Requests and
+// ByStatus count API requests only, not admin ones.
+type TwinReport struct {
+	Name        string            `json:"name"`
+	Unreachable string            `json:"unreachable,omitempty"`
+	Requests    int               `json:"requests"`
+	ByStatus    map[string]int    `json:"by_status"` // "2xx", "4xx", ...
+	Faults      []FaultActivation `json:"faults"`
+	Webhooks    WebhookSummary    `json:"webhooks"`
+	Coverage    *Coverage         `json:"coverage,omitempty"`
+}
+
+// FaultActivation is a fault injected on an endpoint during the session.
+// It lasts until it is removed, the twin is reset, or the session ends;
+// Hits counts the requests to the endpoint answered with an error
+// meanwhile.
+type FaultActivation struct {
+	Endpoint   string     `json:"endpoint"`
+	InjectedAt time.Time  `json:"injected_at"`
+	RemovedAt  *time.Time `json:"removed_at,omitempty"`
+	Hits       int        `json:"hits"`
+}
+
+// WebhookSummary counts a twin's webhook deliveries during the session.
+type WebhookSummary struct {
+	Delivered    int `json:"delivered"`
+	Failed       int `json:"failed"`
+	DeadLettered int `json:"dead_lettered"`
+}
+
+// Problem is something that went wrong during the session: a failed
+// scenario step, a 5xx no injected fault accounts for, a failed webhook
+// delivery, or a twin the report could not reach.
+type Problem struct {
+	Source  string     `json:"source"` // "scenario", "request", "webhook", or "twin"
+	Twin    string     `json:"twin,omitempty"`
+	Message string     `json:"message"`
+	At      *time.Time `json:"at,omitempty"`
+}
+
+// Build collates session with what each twin returned. Requests are
+// counted while the session ran; webhook deliveries from its start on,
+// since they can finish after the last scenario does.
+func Build(session *Session, twins []TwinData, now time.Time) *Report {
+	r := &Report{GeneratedAt: now, Session: session, Twins: []TwinReport{}, Errors: []Problem{}}
+	for _, sc := range session.Scenarios {
+		r.Summary.Scenarios++
+		if !sc.Passed {
+			r.Summary.ScenariosFailed++
+		}
+		if sc.Error != "" {
+			r.Errors = append(r.Errors, Problem{Source: "scenario", Message: sc.Name + ": " + sc.Error})
+		}
+		for _, st := range sc.Steps {
+			r.Summary.Steps++
+			if !st.Passed {
+				r.Summary.StepsFailed++
+				r.Errors = append(r.Errors, Problem{Source: "scenario", Message: sc.Name + ": " + st.Name + ": " + st.Error})
+			}
+		}
+	}
+
+	sort.Slice(twins, func(i, j int) bool { return twins[i].Name < twins[j].Name })
+	for _, td := range twins {
+		tr, problems := buildTwin(session, td)
+		r.Twins = append(r.Twins, tr)
+		r.Errors = append(r.Errors, problems...)
+		r.Summary.Requests += tr.Requests
+		for _, f := range tr.Faults {
+			r.Summary.FaultActivations += f.Hits
+		}
+		r.Summary.WebhooksDelivered += tr.Webhooks.Delivered
+		r.Summary.WebhooksFailed += tr.Webhooks.Failed
+		if tr.Coverage != nil {
+			r.Summary.Endpoints += tr.Coverage.Total
+			r.Summary.EndpointsCovered += tr.Coverage.Covered
+		}
+	}
+	r.Summary.Errors = len(r.Errors)
+	return r
+}
+
+// buildTwin summarizes one twin's activity during session.
+func buildTwin(session *Session, td TwinData) (TwinReport, []Problem) {
+	tr := TwinReport{Name: td.Name, ByStatus: map[string]int{}, Faults: []FaultActivation{}}
+	if td.Err != nil {
+		tr.Unreachable = td.Err.Error()
+		return tr, []Problem{{Source: "twin", Twin: td.Name, Message: "could not be reached: " + td.Err.Error()}}
+	}
+	var problems []Problem
+
+	requests := make([]adminclient.RequestLogEntry, 0, len(td.Requests))
+	for _, e := range td.Requests {
+		if !e.Timestamp.Before(session.StartedAt) && !e.Timestamp.After(session.FinishedAt) {
+			requests = append(requests, e)
+		}
+	}
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].Timestamp.Before(requests[j].Timestamp) })
+	tr.Coverage = buildCoverage(session, td, requests)
+
+	active := map[string]int{} // endpoint -> index in tr.Faults
+	end := func(endpoint string, at time.Time) {
+		if i, ok := active[endpoint]; ok {
+			tr.Faults[i].RemovedAt = &at
+			delete(active, endpoint)
+		}
+	}
+	for _, e := range requests {
+		if endpoint, ok := strings.CutPrefix(e.Path, "/admin/fault/"); ok && e.StatusCode < 300 {
+			endpoint = "/" + endpoint
+			switch e.Method {
+			case "POST":
+				end(endpoint, e.Timestamp)
+				active[endpoint] = len(tr.Faults)
+				tr.Faults = append(tr.Faults, FaultActivation{Endpoint: endpoint, InjectedAt: e.Timestamp})
+			case "DELETE":
+				end(endpoint, e.Timestamp)
+			}
+			continue
+		}
+		if e.Path == "/admin/reset" && e.Method == "POST" && e.StatusCode < 300 {
+			for endpoint := range active {
+				end(endpoint, e.Timestamp)
+			}
+			continue
+		}
+		if strings.HasPrefix(e.Path, "/admin/") {
+			continue
+		}
+
+		tr.Requests++
+		tr.ByStatus[fmt.Sprintf("%dxx", e.StatusCode/100)]++
+		i, faulted := active[e.Path]
+		switch {
+		case faulted && e.StatusCode >= 400:
+			tr.Faults[i].Hits++
+		case e.StatusCode >= 500:
+			problems = append(problems, Problem{Source: "request", Twin: td.Name, Message: fmt.Sprintf("%s %s answered %d", e.Method, e.Path, e.StatusCode), At: &e.Timestamp})
+		}
+	}
+
+	if td.Webhooks != nil {
+		for _, d := range td.Webhooks.Deliveries {
+			if d.Timestamp.Before(session.StartedAt) {
+				continue
+			}
+			if d.Error == "" && d.StatusCode >= 200 && d.StatusCode < 300 {
+				tr.Webhooks.Delivered++
+				continue
+			}
+			tr.Webhooks.Failed++
+			reason := d.Error
+			if reason == "" {
+				reason = fmt.Sprintf("status %d", d.StatusCode)
+			}
+			problems = append(problems, Problem{Source: "webhook", Twin: td.Name, Message: fmt.Sprintf("delivery of %s to %s (attempt %d) failed: %s", d.EventID, d.URL, d.Attempt, reason), At: &d.Timestamp})
+		}
+	}
+	for _, dl := range td.DeadLetters {
+		if dl.FailedAt.Before(session.StartedAt) {
+			continue
+		}
+		tr.Webhooks.DeadLettered++
+		problems = append(problems, Problem{Source: "webhook", Twin: td.Name, Message: fmt.Sprintf("%s %s dead-lettered after %d attempts: %s", dl.Event.Type, dl.Event.ID, dl.Attempts, dl.LastError), At: &dl.FailedAt})
+	}
+	return tr, problems
+}
+
+// WriteJSON writes r as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
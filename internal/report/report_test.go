@@ -0,0 +1,204 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/scenario/v2"
+)
+
+var start = time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+func at(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+func testSession() *Session {
+	return &Session{
+		StartedAt:  start,
+		FinishedAt: at(60),
+		Scenarios: []ScenarioResult{
+			{Name: "checkout", Passed: true, Steps: []StepResult{{Name: "create", Passed: true}}},
+			{Name: "refund", Steps: []StepResult{{Name: "create", Passed: true}, {Name: "refund", Error: "expected status 200, got 500"}}},
+			{Name: "broken", Error: "unknown twin \"nope\""},
+		},
+	}
+}
+
+func TestBuildCountsFaultHits(t *testing.T) {
+	td := TwinData{
+		Name: "stripe",
+		Requests: []adminclient.RequestLogEntry{
+			{Timestamp: start.Add(-time.Second), Method: "POST", Path: "/v1/charges", StatusCode: 200}, // before the session
+			{Timestamp: at(1), Method: "POST", Path: "/v1/charges", StatusCode: 200},
+			{Timestamp: at(2), Method: "POST", Path: "/admin/fault/v1/refunds", StatusCode: 200},
+			{Timestamp: at(3), Method: "POST", Path: "/v1/refunds", StatusCode: 500},
+			{Timestamp: at(4), Method: "POST", Path: "/v1/refunds", StatusCode: 500},
+			{Timestamp: at(5), Method: "DELETE", Path: "/admin/fault/v1/refunds", StatusCode: 200},
+			{Timestamp: at(6), Method: "POST", Path: "/v1/refunds", StatusCode: 500}, // no fault accounts for it
+			{Timestamp: at(7), Method: "POST", Path: "/admin/fault/v1/charges", StatusCode: 200},
+			{Timestamp: at(8), Method: "POST", Path: "/admin/reset", StatusCode: 200},
+			{Timestamp: at(9), Method: "GET", Path: "/v1/charges", StatusCode: 404},
+		},
+		Webhooks: &adminclient.Webhooks{Deliveries: []adminclient.WebhookDelivery{
+			{EventID: "evt_1", URL: "http://app/hook", StatusCode: 200, Attempt: 1, Timestamp: at(1)},
+			{EventID: "evt_2", URL: "http://app/hook", Error: "connection refused", Attempt: 1, Timestamp: at(70)},
+			{EventID: "evt_0", URL: "http://app/hook", StatusCode: 500, Attempt: 1, Timestamp: start.Add(-time.Minute)},
+		}},
+		DeadLetters: []adminclient.DeadLetter{
+			{Event: adminclient.WebhookEvent{ID: "evt_2", Type: "charge.refunded"}, Attempts: 5, LastError: "connection refused", FailedAt: at(80)},
+		},
+	}
+	r := Build(testSession(), []TwinData{td, {Name: "clerk", Err: errors.New("connection refused")}}, at(90))
+
+	want := Summary{
+		Scenarios: 3, ScenariosFailed: 2, Steps: 3, StepsFailed: 1,
+		Requests: 5, FaultActivations: 2, WebhooksDelivered: 1, WebhooksFailed: 1,
+		// broken, refund/refund, the unfaulted 500, the failed delivery, the
+		// dead letter, and clerk
+		Errors: 6,
+	}
+	if r.Summary != want {
+		t.Errorf("summary = %+v, want %+v", r.Summary, want)
+	}
+	if len(r.Twins) != 2 || r.Twins[0].Name != "clerk" || r.Twins[0].Unreachable == "" {
+		t.Fatalf("expected clerk first and unreachable, got %+v", r.Twins)
+	}
+
+	stripe := r.Twins[1]
+	if stripe.ByStatus["2xx"] != 1 || stripe.ByStatus["4xx"] != 1 || stripe.ByStatus["5xx"] != 3 {
+		t.Errorf("unexpected status counts %v", stripe.ByStatus)
+	}
+	if len(stripe.Faults) != 2 {
+		t.Fatalf("expected 2 fault activations, got %+v", stripe.Faults)
+	}
+	refunds, charges := stripe.Faults[0], stripe.Faults[1]
+	if refunds.Endpoint != "/v1/refunds" || refunds.Hits != 2 || refunds.RemovedAt == nil || !refunds.RemovedAt.Equal(at(5)) {
+		t.Errorf("unexpected refunds fault %+v", refunds)
+	}
+	if charges.Endpoint != "/v1/charges" || charges.Hits != 0 || charges.RemovedAt == nil || !charges.RemovedAt.Equal(at(8)) {
+		t.Errorf("expected the reset to end the charges fault, got %+v", charges)
+	}
+	if stripe.Webhooks != (WebhookSummary{Delivered: 1, Failed: 1, DeadLettered: 1}) {
+		t.Errorf("unexpected webhook summary %+v", stripe.Webhooks)
+	}
+}
+
+func TestNewScenarioResult(t *testing.T) {
+	sr := NewScenarioResult("checkout", &v2.Result{
+		Passed:   false,
+		Duration: 1500 * time.Millisecond,
+		Steps:    []v2.StepResult{{Name: "pay", Error: "boom", StatusCode: 402, Duration: 20 * time.Millisecond}},
+	}, nil)
+	if sr.DurationMS != 1500 || len(sr.Steps) != 1 || sr.Steps[0].StatusCode != 402 || sr.Steps[0].DurationMS != 20 {
+		t.Errorf("unexpected result %+v", sr)
+	}
+	if sr := NewScenarioResult("x", nil, errors.New("no such twin")); sr.Passed || sr.Error != "no such twin" {
+		t.Errorf("unexpected result for a scenario that did not run: %+v", sr)
+	}
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadSession(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist before any session, got %v", err)
+	}
+	if err := SaveSession(dir, testSession()); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.StartedAt.Equal(start) || len(s.Scenarios) != 3 || s.Scenarios[1].Steps[1].Error == "" {
+		t.Errorf("session did not round-trip: %+v", s)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	s := testSession()
+	s.Scenarios[2].Error = "<script>"
+	var buf bytes.Buffer
+	if err := Build(s, nil, at(90)).WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, want := range []string{"checkout", "expected status 200, got 500", "&lt;script&gt;", "3 (2 failed)"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report missing %q", want)
+		}
+	}
+}
+
+func TestBuildCoverage(t *testing.T) {
+	session := &Session{
+		StartedAt:  start,
+		FinishedAt: at(60),
+		Scenarios: []ScenarioResult{{Name: "checkout", Steps: []StepResult{
+			// Made before a reset cleared it from the request log.
+			{Name: "create", Method: "POST", URL: "http://localhost:4111/v1/charges?expand=customer"},
+			{Name: "seed", Method: "POST", URL: "http://localhost:4112/admin/state"}, // another twin
+			{Name: "wait"},
+		}}},
+	}
+	td := TwinData{
+		Name:    "stripe",
+		BaseURL: "http://localhost:4111",
+		Routes: []adminclient.Route{
+			{Method: "GET", Route: "/v1/charges"},
+			{Method: "POST", Route: "/v1/charges"},
+			{Method: "GET", Route: "/v1/charges/{id}"},
+			{Method: "POST", Route: "/v1/refunds"},
+		},
+		Requests: []adminclient.RequestLogEntry{
+			{Timestamp: at(1), Method: "GET", Path: "/v1/charges/ch_1", Route: "/v1/charges/{id}", StatusCode: 200},
+			{Timestamp: at(2), Method: "GET", Path: "/v1/charges/ch_2", Route: "/v1/charges/{id}", StatusCode: 404},
+			{Timestamp: at(90), Method: "POST", Path: "/v1/refunds", Route: "/v1/refunds", StatusCode: 200}, // after the session
+		},
+	}
+	r := Build(session, []TwinData{td, {Name: "clerk"}}, at(90))
+
+	cov := r.Twins[1].Coverage
+	if cov == nil || cov.Covered != 2 || cov.Total != 4 {
+		t.Fatalf("expected 2/4 endpoints exercised, got %+v", cov)
+	}
+	if got := cov.Endpoints[2]; got.Requests != 2 || !got.Exercised {
+		t.Errorf("unexpected coverage of GET /v1/charges/{id}: %+v", got)
+	}
+	if got := cov.Endpoints[1]; got.Requests != 0 || !got.Exercised {
+		t.Errorf("expected the scenario step to exercise POST /v1/charges, got %+v", got)
+	}
+	untested := cov.Untested()
+	if len(untested) != 2 || untested[0].Route != "/v1/charges" || untested[1].Route != "/v1/refunds" {
+		t.Errorf("unexpected untested endpoints %+v", untested)
+	}
+	if r.Twins[0].Coverage != nil {
+		t.Errorf("expected no coverage for a twin without routes, got %+v", r.Twins[0].Coverage)
+	}
+	if r.Summary.Endpoints != 4 || r.Summary.EndpointsCovered != 2 {
+		t.Errorf("unexpected endpoint totals %+v", r.Summary)
+	}
+}
+
+func TestRouteMatcher(t *testing.T) {
+	for _, tc := range []struct {
+		route, path string
+		want        bool
+	}{
+		{"/v1/charges", "/v1/charges", true},
+		{"/v1/charges", "/v1/charges/ch_1", false},
+		{"/v1/charges/{id}", "/v1/charges/ch_1", true},
+		{"/v1/charges/{id}", "/v1/charges/", false},
+		{"/v1/charges/{id:ch_[0-9]+}", "/v1/charges/ch_12", true},
+		{"/v1/charges/{id:ch_[0-9]+}", "/v1/charges/pi_12", false},
+		{"/files/*", "/files/a/b.txt", true},
+		{"/files/*", "/other/a", false},
+	} {
+		if got := newRouteMatcher(tc.route).match(tc.path); got != tc.want {
+			t.Errorf("%s matching %s = %v, want %v", tc.route, tc.path, got, tc.want)
+		}
+	}
+}
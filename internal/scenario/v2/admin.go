@@ -0,0 +1,303 @@
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// adminCall is the admin API request an admin step translates to.
+type adminCall struct {
+	twin   string
+	method string
+	path   string
+	body   any
+}
+
+// isAdmin reports whether the step performs an admin action rather than a request.
+func (s *Step) isAdmin() bool {
+	return s.adminActionCount() > 0
+}
+
+func (s *Step) adminActionCount() int {
+	n := 0
+	for _, set := range []bool{
+		s.InjectFault != nil,
+		s.RemoveFault != nil,
+		s.AdvanceTime != nil,
+		s.SetConfig != nil,
+		s.EnableQuirk != nil,
+	} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// validateStep checks that a step is either a well-formed request, exactly
+// one admin action with valid parameters, or a command.
+func validateStep(s *Step) error {
+	if s.Timeout != "" {
+		if _, err := parsePositiveDuration(s.Timeout); err != nil {
+			return fmt.Errorf("step %q: timeout: %w", s.Name, err)
+		}
+	}
+	if s.Retry != nil {
+		if s.Retry.Attempts < 1 {
+			return fmt.Errorf("step %q: retry.attempts must be at least 1", s.Name)
+		}
+		if s.Retry.Backoff != "" {
+			if _, err := parsePositiveDuration(s.Retry.Backoff); err != nil {
+				return fmt.Errorf("step %q: retry.backoff: %w", s.Name, err)
+			}
+		}
+	}
+
+	if s.Exec != nil {
+		if err := s.validateExec(); err != nil {
+			return fmt.Errorf("step %q: %w", s.Name, err)
+		}
+		return nil
+	}
+
+	switch n := s.adminActionCount(); {
+	case n > 1:
+		return fmt.Errorf("step %q: only one admin action is allowed per step", s.Name)
+	case n == 1:
+		if s.Request.Method != "" || s.Request.URL != "" {
+			return fmt.Errorf("step %q: admin steps cannot also define a request", s.Name)
+		}
+		if len(s.Capture) > 0 || s.Assert != nil {
+			return fmt.Errorf("step %q: admin steps do not support capture or assert", s.Name)
+		}
+		if _, err := s.adminCall(); err != nil {
+			return fmt.Errorf("step %q: %w", s.Name, err)
+		}
+		return nil
+	}
+	if s.Request.Method == "" || s.Request.URL == "" {
+		return fmt.Errorf("step %q: request method and url are required", s.Name)
+	}
+	if s.Assert != nil && s.Assert.RateLimit != nil {
+		if err := validateRateLimit(s.Assert.RateLimit); err != nil {
+			return fmt.Errorf("step %q: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// adminCall translates the step's admin action into an admin API request,
+// validating its parameters.
+func (s *Step) adminCall() (*adminCall, error) {
+	switch {
+	case s.InjectFault != nil:
+		f := s.InjectFault
+		if err := checkEndpoint(f.Twin, f.Endpoint); err != nil {
+			return nil, fmt.Errorf("inject_fault: %w", err)
+		}
+		switch {
+		case isWebhookFault(f.Endpoint):
+			// Webhook faults drop or delay deliveries; the twin rejects
+			// anything else.
+			if f.StatusCode != 0 || f.Preset != "" || f.Body != "" {
+				return nil, fmt.Errorf("inject_fault: webhook faults take only delay_ms, rate, and a schedule")
+			}
+		case f.Preset == "":
+			if f.StatusCode < 100 || f.StatusCode > 599 {
+				return nil, fmt.Errorf("inject_fault: status_code must be a valid HTTP status, got %d", f.StatusCode)
+			}
+		case f.Preset == "auth_invalid", f.Preset == "auth_expired", f.Preset == "forbidden_scope":
+			if f.StatusCode != 0 {
+				return nil, fmt.Errorf("inject_fault: status_code and preset cannot be combined")
+			}
+		default:
+			return nil, fmt.Errorf("inject_fault: preset must be \"auth_invalid\", \"auth_expired\", or \"forbidden_scope\", got %q", f.Preset)
+		}
+		if f.Rate < 0 || f.Rate > 1 {
+			return nil, fmt.Errorf("inject_fault: rate must be between 0 and 1, got %v", f.Rate)
+		}
+		if f.DelayMS < 0 {
+			return nil, fmt.Errorf("inject_fault: delay_ms must not be negative")
+		}
+		if f.RetryAfter < 0 {
+			return nil, fmt.Errorf("inject_fault: retry_after must not be negative")
+		}
+		if f.RetryAfter > 0 && f.StatusCode != http.StatusTooManyRequests {
+			return nil, fmt.Errorf("inject_fault: retry_after only applies to status_code 429")
+		}
+		body := map[string]any{
+			"status_code": f.StatusCode,
+			"body":        f.Body,
+			// The admin API decodes delay_ms as a time.Duration.
+			"delay_ms": time.Duration(f.DelayMS) * time.Millisecond,
+			"rate":     f.Rate,
+		}
+		if f.Preset != "" {
+			body["preset"] = f.Preset
+		}
+		if f.RetryAfter > 0 {
+			body["retry_after"] = f.RetryAfter
+		}
+		for field, v := range map[string]string{"after": f.After, "duration": f.Duration} {
+			if v == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(v); err != nil || d < 0 {
+				return nil, fmt.Errorf("inject_fault: %s must be a non-negative duration like \"5m\", got %q", field, v)
+			}
+			body[field] = v
+		}
+		switch f.Clock {
+		case "":
+		case "wall", "simulated":
+			body["clock"] = f.Clock
+		default:
+			return nil, fmt.Errorf("inject_fault: clock must be \"wall\" or \"simulated\", got %q", f.Clock)
+		}
+		return &adminCall{
+			twin:   f.Twin,
+			method: http.MethodPost,
+			path:   "/admin/fault/" + strings.TrimPrefix(f.Endpoint, "/"),
+			body:   body,
+		}, nil
+	case s.RemoveFault != nil:
+		f := s.RemoveFault
+		if err := checkEndpoint(f.Twin, f.Endpoint); err != nil {
+			return nil, fmt.Errorf("remove_fault: %w", err)
+		}
+		return &adminCall{
+			twin:   f.Twin,
+			method: http.MethodDelete,
+			path:   "/admin/fault/" + strings.TrimPrefix(f.Endpoint, "/"),
+		}, nil
+	case s.AdvanceTime != nil:
+		a := s.AdvanceTime
+		if a.Twin == "" {
+			return nil, fmt.Errorf("advance_time: twin is required")
+		}
+		d, err := time.ParseDuration(a.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("advance_time: invalid duration %q", a.Duration)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("advance_time: duration must be positive")
+		}
+		return &adminCall{
+			twin:   a.Twin,
+			method: http.MethodPost,
+			path:   "/admin/time/advance",
+			body:   map[string]string{"duration": a.Duration},
+		}, nil
+	case s.SetConfig != nil:
+		c := s.SetConfig
+		if c.Twin == "" {
+			return nil, fmt.Errorf("set_config: twin is required")
+		}
+		if len(c.Values) == 0 {
+			return nil, fmt.Errorf("set_config: values must not be empty")
+		}
+		return &adminCall{
+			twin:   c.Twin,
+			method: http.MethodPut,
+			path:   "/admin/config",
+			body:   c.Values,
+		}, nil
+	case s.EnableQuirk != nil:
+		q := s.EnableQuirk
+		if q.Twin == "" {
+			return nil, fmt.Errorf("enable_quirk: twin is required")
+		}
+		if q.Quirk == "" {
+			return nil, fmt.Errorf("enable_quirk: quirk is required")
+		}
+		return &adminCall{
+			twin:   q.Twin,
+			method: http.MethodPut,
+			path:   "/admin/quirks/" + url.PathEscape(q.Quirk),
+		}, nil
+	}
+	return nil, fmt.Errorf("no admin action defined")
+}
+
+func checkEndpoint(twin, endpoint string) error {
+	if twin == "" {
+		return fmt.Errorf("twin is required")
+	}
+	if isWebhookFault(endpoint) {
+		if endpoint == "webhook:" {
+			return fmt.Errorf("webhook fault endpoints name an event type, like webhook:charge.succeeded")
+		}
+		return nil
+	}
+	if !strings.HasPrefix(endpoint, "/") {
+		return fmt.Errorf("endpoint must start with / or webhook:, got %q", endpoint)
+	}
+	return nil
+}
+
+// isWebhookFault reports whether a fault endpoint addresses a twin's webhook
+// deliveries of an event type, like webhook:charge.succeeded, rather than an
+// API path.
+func isWebhookFault(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "webhook:")
+}
+
+// runAdminStep executes an admin step against the twin's admin API.
+func (r *Runner) runAdminStep(ctx context.Context, client *http.Client, step *Step) StepResult {
+	start := time.Now()
+	sr := StepResult{Name: step.Name}
+	defer func() { sr.Duration = time.Since(start) }()
+
+	call, err := step.adminCall()
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	twin, err := r.manifest.Twin(call.twin)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+
+	var reqBody io.Reader
+	if call.body != nil {
+		data, err := json.Marshal(call.body)
+		if err != nil {
+			sr.Error = fmt.Sprintf("marshaling admin request: %v", err)
+			return sr
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, call.method, twin.AdminURL()+call.path, reqBody)
+	if err != nil {
+		sr.Error = fmt.Sprintf("building admin request: %v", err)
+		return sr
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		sr.Error = fmt.Sprintf("admin request failed: %v", err)
+		return sr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		sr.Error = fmt.Sprintf("%s %s: status %d; body: %s", call.method, call.path, resp.StatusCode, body)
+		return sr
+	}
+
+	sr.Passed = true
+	return sr
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // EvaluateBodyAssertions evaluates JSONPath-based body assertions against a response body.
@@ -46,11 +47,23 @@ func evaluateOne(doc any, path string, expected any) error {
 	return nil
 }
 
-// evaluateOperators processes operator-based assertions like {"eq": v}, {"gte": n}, etc.
+// numericOps are the numeric comparison operators, with the symbol used in
+// failure messages.
+var numericOps = map[string]struct {
+	symbol string
+	holds  func(actual, expected float64) bool
+}{
+	"gt":  {">", func(a, e float64) bool { return a > e }},
+	"gte": {">=", func(a, e float64) bool { return a >= e }},
+	"lt":  {"<", func(a, e float64) bool { return a < e }},
+	"lte": {"<=", func(a, e float64) bool { return a <= e }},
+}
+
+// evaluateOperators processes operator-based assertions like {"eq": v},
+// {"gte": n}, {"matches": "^re_"}, or {"length": {"gt": 0}}.
 func evaluateOperators(path string, results []any, ops map[string]any) error {
 	for op, expected := range ops {
-		switch op {
-		case "exists":
+		if op == "exists" {
 			wantExists, ok := expected.(bool)
 			if !ok {
 				return fmt.Errorf("JSONPath %q: 'exists' operator requires a boolean value", path)
@@ -62,74 +75,88 @@ func evaluateOperators(path string, results []any, ops map[string]any) error {
 			if !wantExists && hasResults {
 				return fmt.Errorf("JSONPath %q: expected not to exist but found %v", path, results[0])
 			}
+			continue
+		}
 
-		case "eq":
-			if len(results) == 0 {
-				return fmt.Errorf("JSONPath %q: no match found for 'eq' check", path)
-			}
-			if !valuesEqual(results[0], expected) {
-				return fmt.Errorf("JSONPath %q: expected eq %v, got %v", path, expected, results[0])
-			}
+		if len(results) == 0 {
+			return fmt.Errorf("JSONPath %q: no match found for '%s' check", path, op)
+		}
+		actual := results[0]
 
-		case "gte":
-			if len(results) == 0 {
-				return fmt.Errorf("JSONPath %q: no match found for 'gte' check", path)
-			}
-			actualNum, err := toFloat64(results[0])
+		if cmp, ok := numericOps[op]; ok {
+			actualNum, err := toFloat64(actual)
 			if err != nil {
-				return fmt.Errorf("JSONPath %q: 'gte' requires numeric actual value: %w", path, err)
+				return fmt.Errorf("JSONPath %q: '%s' requires numeric actual value: %w", path, op, err)
 			}
 			expectedNum, err := toFloat64(expected)
 			if err != nil {
-				return fmt.Errorf("JSONPath %q: 'gte' requires numeric expected value: %w", path, err)
+				return fmt.Errorf("JSONPath %q: '%s' requires numeric expected value: %w", path, op, err)
 			}
-			if actualNum < expectedNum {
-				return fmt.Errorf("JSONPath %q: expected >= %v, got %v", path, expectedNum, actualNum)
+			if !cmp.holds(actualNum, expectedNum) {
+				return fmt.Errorf("JSONPath %q: expected %s %v, got %v", path, cmp.symbol, expectedNum, actualNum)
 			}
+			continue
+		}
 
-		case "lte":
-			if len(results) == 0 {
-				return fmt.Errorf("JSONPath %q: no match found for 'lte' check", path)
-			}
-			actualNum, err := toFloat64(results[0])
-			if err != nil {
-				return fmt.Errorf("JSONPath %q: 'lte' requires numeric actual value: %w", path, err)
-			}
-			expectedNum, err := toFloat64(expected)
-			if err != nil {
-				return fmt.Errorf("JSONPath %q: 'lte' requires numeric expected value: %w", path, err)
+		switch op {
+		case "eq":
+			if !valuesEqual(actual, expected) {
+				return fmt.Errorf("JSONPath %q: expected eq %v, got %v", path, expected, actual)
 			}
-			if actualNum > expectedNum {
-				return fmt.Errorf("JSONPath %q: expected <= %v, got %v", path, expectedNum, actualNum)
+
+		case "ne":
+			if valuesEqual(actual, expected) {
+				return fmt.Errorf("JSONPath %q: expected ne %v, got %v", path, expected, actual)
 			}
 
 		case "contains":
-			if len(results) == 0 {
-				return fmt.Errorf("JSONPath %q: no match found for 'contains' check", path)
+			// Arrays contain an element; anything else a substring.
+			if arr, ok := actual.([]any); ok {
+				if !arrayContains(arr, expected) {
+					return fmt.Errorf("JSONPath %q: expected an element matching %v, got %v", path, expected, arr)
+				}
+				continue
 			}
-			actualStr := fmt.Sprintf("%v", results[0])
+			actualStr := fmt.Sprintf("%v", actual)
 			expectedStr := fmt.Sprintf("%v", expected)
 			if !strings.Contains(actualStr, expectedStr) {
 				return fmt.Errorf("JSONPath %q: expected to contain %q, got %q", path, expectedStr, actualStr)
 			}
 
-		case "regex":
-			if len(results) == 0 {
-				return fmt.Errorf("JSONPath %q: no match found for 'regex' check", path)
-			}
+		case "regex", "matches":
 			pattern, ok := expected.(string)
 			if !ok {
-				return fmt.Errorf("JSONPath %q: 'regex' operator requires a string pattern", path)
+				return fmt.Errorf("JSONPath %q: '%s' operator requires a string pattern", path, op)
 			}
 			re, err := regexp.Compile(pattern)
 			if err != nil {
 				return fmt.Errorf("JSONPath %q: invalid regex pattern %q: %w", path, pattern, err)
 			}
-			actualStr := fmt.Sprintf("%v", results[0])
+			actualStr := fmt.Sprintf("%v", actual)
 			if !re.MatchString(actualStr) {
 				return fmt.Errorf("JSONPath %q: value %q does not match regex %q", path, actualStr, pattern)
 			}
 
+		case "length":
+			n, err := valueLength(actual)
+			if err != nil {
+				return fmt.Errorf("JSONPath %q: %w", path, err)
+			}
+			// A number is the exact length; an object applies operators to it.
+			if nested, ok := expected.(map[string]any); ok {
+				if err := evaluateOperators(path+".length", []any{float64(n)}, nested); err != nil {
+					return err
+				}
+				continue
+			}
+			want, err := toFloat64(expected)
+			if err != nil {
+				return fmt.Errorf("JSONPath %q: 'length' requires a number or an operator object: %w", path, err)
+			}
+			if float64(n) != want {
+				return fmt.Errorf("JSONPath %q: expected length %v, got %d", path, want, n)
+			}
+
 		default:
 			return fmt.Errorf("JSONPath %q: unknown operator %q", path, op)
 		}
@@ -137,6 +164,58 @@ func evaluateOperators(path string, results []any, ops map[string]any) error {
 	return nil
 }
 
+// valueLength returns the number of elements in an array, keys in an
+// object, or characters in a string.
+func valueLength(v any) (int, error) {
+	switch v := v.(type) {
+	case []any:
+		return len(v), nil
+	case map[string]any:
+		return len(v), nil
+	case string:
+		return utf8.RuneCountInString(v), nil
+	default:
+		return 0, fmt.Errorf("'length' requires an array, object, or string, got %v (%T)", v, v)
+	}
+}
+
+// arrayContains reports whether arr has an element equal to expected. An
+// object matches an element that has all of its fields with equal values.
+func arrayContains(arr []any, expected any) bool {
+	for _, elem := range arr {
+		if want, ok := expected.(map[string]any); ok {
+			if obj, ok := elem.(map[string]any); ok && objectMatches(obj, want) {
+				return true
+			}
+			continue
+		}
+		if valuesEqual(elem, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func objectMatches(obj, want map[string]any) bool {
+	for k, w := range want {
+		v, ok := obj[k]
+		if !ok {
+			return false
+		}
+		if wm, ok := w.(map[string]any); ok {
+			vm, ok := v.(map[string]any)
+			if !ok || !objectMatches(vm, wm) {
+				return false
+			}
+			continue
+		}
+		if !valuesEqual(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
 // valuesEqual compares two values for equality, handling numeric type coercion.
 // Values must be the same kind (both numeric or both string) to be equal.
 func valuesEqual(actual, expected any) bool {
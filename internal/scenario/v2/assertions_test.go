@@ -260,6 +260,76 @@ func TestEvaluateBodyAssertions_ExistsOperator(t *testing.T) {
 	}
 }
 
+func TestEvaluateBodyAssertions_ComparisonOperators(t *testing.T) {
+	body := []byte(`{"points_approved": 14500, "status": "approved", "code": "re_8f2a"}`)
+
+	tests := []struct {
+		name       string
+		assertions map[string]any
+		wantErr    bool
+	}{
+		{"ne passes", map[string]any{"$.status": map[string]any{"ne": "pending"}}, false},
+		{"ne fails", map[string]any{"$.status": map[string]any{"ne": "approved"}}, true},
+		{"gt passes", map[string]any{"$.points_approved": map[string]any{"gt": float64(14499)}}, false},
+		{"gt equal fails", map[string]any{"$.points_approved": map[string]any{"gt": float64(14500)}}, true},
+		{"lt passes", map[string]any{"$.points_approved": map[string]any{"lt": float64(14501)}}, false},
+		{"lt equal fails", map[string]any{"$.points_approved": map[string]any{"lt": float64(14500)}}, true},
+		{"range", map[string]any{"$.points_approved": map[string]any{"gt": float64(0), "lte": float64(14500)}}, false},
+		{"gt on string fails", map[string]any{"$.status": map[string]any{"gt": float64(1)}}, true},
+		{"matches passes", map[string]any{"$.code": map[string]any{"matches": "^re_[0-9a-f]{4}$"}}, false},
+		{"matches fails", map[string]any{"$.code": map[string]any{"matches": "^ch_"}}, true},
+		{"missing field", map[string]any{"$.missing": map[string]any{"ne": "x"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EvaluateBodyAssertions(body, tt.assertions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateBodyAssertions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluateBodyAssertions_ArrayMatchers(t *testing.T) {
+	body := []byte(`{
+		"rewards": [
+			{"id": 1, "title": "$5 off", "cost": 500},
+			{"id": 2, "title": "Free shipping", "cost": 250, "meta": {"tier": "gold"}}
+		],
+		"tags": ["vip", "beta"],
+		"name": "Ada"
+	}`)
+
+	tests := []struct {
+		name       string
+		assertions map[string]any
+		wantErr    bool
+	}{
+		{"length", map[string]any{"$.rewards": map[string]any{"length": float64(2)}}, false},
+		{"length mismatch", map[string]any{"$.rewards": map[string]any{"length": float64(3)}}, true},
+		{"length operators", map[string]any{"$.tags": map[string]any{"length": map[string]any{"gte": float64(1), "lt": float64(3)}}}, false},
+		{"length operators fail", map[string]any{"$.tags": map[string]any{"length": map[string]any{"gt": float64(2)}}}, true},
+		{"string length", map[string]any{"$.name": map[string]any{"length": float64(3)}}, false},
+		{"length of number fails", map[string]any{"$.rewards[0].cost": map[string]any{"length": float64(3)}}, true},
+		{"contains element", map[string]any{"$.tags": map[string]any{"contains": "vip"}}, false},
+		{"contains no substring of elements", map[string]any{"$.tags": map[string]any{"contains": "vi"}}, true},
+		{"contains object subset", map[string]any{"$.rewards": map[string]any{"contains": map[string]any{"id": float64(2), "meta": map[string]any{"tier": "gold"}}}}, false},
+		{"contains object mismatch", map[string]any{"$.rewards": map[string]any{"contains": map[string]any{"id": float64(1), "cost": float64(250)}}}, true},
+		{"wildcard contains", map[string]any{"$.rewards[*].title": map[string]any{"contains": "Free shipping"}}, false},
+		{"wildcard length", map[string]any{"$.rewards[*].meta": map[string]any{"length": float64(1)}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EvaluateBodyAssertions(body, tt.assertions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateBodyAssertions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestEvaluateBodyAssertions_NestedPath(t *testing.T) {
 	body := []byte(`{"data": {"user": {"name": "Alice", "age": 30}}}`)
 
@@ -0,0 +1,247 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+)
+
+// DefaultBenchConcurrency is the number of workers Bench uses when
+// BenchOptions.Concurrency is zero.
+const DefaultBenchConcurrency = 50
+
+// BenchOptions configures a load run.
+type BenchOptions struct {
+	RPS         int           // target request rate, across all workers
+	Duration    time.Duration // how long to generate load
+	Concurrency int           // workers replaying the scenario at once
+}
+
+// LatencyStats summarizes request latencies.
+type LatencyStats struct {
+	P50, P90, P95, P99, Max time.Duration
+}
+
+// BenchStep is the load-run outcome of one request step.
+type BenchStep struct {
+	Name       string
+	Requests   int
+	Errors     int
+	Latency    LatencyStats
+	FirstError string // empty when Errors is zero
+}
+
+// BenchResult is the outcome of a load run.
+type BenchResult struct {
+	Duration     time.Duration // time spent generating load
+	Requests     int
+	Errors       int
+	Latency      LatencyStats
+	StatusCodes  map[int]int // responses by status; 0 counts requests that got none
+	Steps        []BenchStep // in scenario order
+	SkippedSteps int         // admin and exec steps, which a load run does not repeat
+}
+
+// RPS returns the achieved request rate.
+func (b *BenchResult) RPS() float64 {
+	if b.Duration <= 0 {
+		return 0
+	}
+	return float64(b.Requests) / b.Duration.Seconds()
+}
+
+// ErrorRate returns the fraction of requests that failed.
+func (b *BenchResult) ErrorRate() float64 {
+	if b.Requests == 0 {
+		return 0
+	}
+	return float64(b.Errors) / float64(b.Requests)
+}
+
+// benchSample records one request of a load run.
+type benchSample struct {
+	step    int // index into the run's request steps
+	latency time.Duration
+	status  int
+	err     string
+}
+
+// Bench replays the request steps of s against the twins at opts.RPS
+// requests per second for opts.Duration, or until ctx is done. The setup
+// runs once beforehand; admin steps are skipped, since faults or time
+// travel repeated under load would change what is being measured.
+//
+// Each worker replays the steps in order with its own variables, so values
+// captured by one step feed the next as in a normal run; when a capturing
+// step fails, the worker starts over from the first step. {{bench.worker}}
+// and {{bench.iteration}} expand to the worker number and its replay count,
+// for values that must be unique, such as IDs or emails. A request fails
+// when its step's assertions fail, or, for a step without assertions, when
+// it gets no response or a 4xx or 5xx status.
+func (r *Runner) Bench(ctx context.Context, s *Scenario, opts BenchOptions) (*BenchResult, error) {
+	if opts.RPS <= 0 {
+		return nil, fmt.Errorf("rps must be positive, got %d", opts.RPS)
+	}
+	if opts.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive, got %s", opts.Duration)
+	}
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = DefaultBenchConcurrency
+	}
+
+	result := &BenchResult{StatusCodes: make(map[int]int)}
+	var steps []*Step
+	for i := range s.Steps {
+		if s.Steps[i].isAdmin() || s.Steps[i].Exec != nil {
+			result.SkippedSteps++
+			continue
+		}
+		steps = append(steps, &s.Steps[i])
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("scenario %q has no request steps", s.Name)
+	}
+
+	if s.Setup != nil {
+		if err := r.runSetup(s.Setup, nil); err != nil {
+			return nil, fmt.Errorf("setup failed: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+	ticks := pace(ctx, opts.RPS)
+
+	// Keep a connection per worker alive; the default transport keeps only
+	// two per host, and reconnecting would dominate the latencies measured.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = workers
+	adminclient.RegisterUnixSockets(transport) // Clone drops registered protocols
+	defer transport.CloseIdleConnections()
+	lr := *r
+	lr.http = &http.Client{Timeout: r.http.Timeout, Transport: transport}
+
+	samples := make([][]benchSample, workers)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			vars := make(map[string]string, len(s.Variables))
+			for k, v := range s.Variables {
+				vars[k] = v
+			}
+			vars["bench.worker"] = strconv.Itoa(w)
+			for iteration := 1; ; iteration++ {
+				vars["bench.iteration"] = strconv.Itoa(iteration)
+				for i, step := range steps {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticks:
+					}
+					began := time.Now()
+					sr := lr.runStep(ctx, step, vars, nil, nil)
+					if ctx.Err() != nil {
+						return // cut off by the end of the run
+					}
+					sample := benchSample{step: i, latency: time.Since(began), status: sr.StatusCode}
+					switch {
+					case !sr.Passed:
+						sample.err = sr.Error
+					case step.Assert == nil && sr.StatusCode >= 400:
+						sample.err = fmt.Sprintf("status %d", sr.StatusCode)
+					}
+					samples[w] = append(samples[w], sample)
+					if sample.err != "" && len(step.Capture) > 0 {
+						break
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	result.Duration = time.Since(start)
+
+	var all []time.Duration
+	perStep := make([][]time.Duration, len(steps))
+	result.Steps = make([]BenchStep, len(steps))
+	for i, step := range steps {
+		result.Steps[i].Name = step.Name
+	}
+	for _, ws := range samples {
+		for _, sm := range ws {
+			bs := &result.Steps[sm.step]
+			bs.Requests++
+			result.Requests++
+			result.StatusCodes[sm.status]++
+			if sm.err != "" {
+				bs.Errors++
+				result.Errors++
+				if bs.FirstError == "" {
+					bs.FirstError = sm.err
+				}
+			}
+			all = append(all, sm.latency)
+			perStep[sm.step] = append(perStep[sm.step], sm.latency)
+		}
+	}
+	result.Latency = latencyStats(all)
+	for i := range result.Steps {
+		result.Steps[i].Latency = latencyStats(perStep[i])
+	}
+	return result, nil
+}
+
+// pace returns a channel that yields rps values a second until ctx is done.
+// Workers take one per request. Slots that pass while every worker is busy
+// are skipped, so a twin too slow for the target rate shows up as a lower
+// achieved rate rather than as bursts of catch-up requests.
+func pace(ctx context.Context, rps int) <-chan struct{} {
+	ticks := make(chan struct{})
+	interval := time.Second / time.Duration(rps)
+	go func() {
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		next := time.Now()
+		for {
+			timer.Reset(time.Until(next))
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case ticks <- struct{}{}:
+			}
+			next = next.Add(interval)
+			if now := time.Now(); now.Sub(next) > interval {
+				next = now
+			}
+		}
+	}()
+	return ticks
+}
+
+// latencyStats returns the nearest-rank percentiles of d, sorting it.
+func latencyStats(d []time.Duration) LatencyStats {
+	if len(d) == 0 {
+		return LatencyStats{}
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	pct := func(p float64) time.Duration {
+		return d[max(int(math.Ceil(p*float64(len(d))))-1, 0)]
+	}
+	return LatencyStats{P50: pct(0.50), P90: pct(0.90), P95: pct(0.95), P99: pct(0.99), Max: d[len(d)-1]}
+}
@@ -0,0 +1,119 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+func TestRunner_Bench(t *testing.T) {
+	var mu sync.Mutex
+	created := make(map[string]bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/things":
+			name := r.URL.Query().Get("name")
+			mu.Lock()
+			dup := created[name]
+			created[name] = true
+			mu.Unlock()
+			if dup {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "` + name + `"}`))
+		case strings.HasPrefix(r.URL.Path, "/things/"):
+			w.Write([]byte(`{"ok": true}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	parts := strings.Split(srv.URL, ":")
+	port, _ := strconv.Atoi(parts[len(parts)-1])
+	m := &manifest.Manifest{
+		Twins: map[string]manifest.Twin{"things": {Port: port, AdminPort: port}},
+	}
+
+	scenario := &Scenario{
+		Name: "Load",
+		Steps: []Step{
+			{
+				Name:    "Create",
+				Request: Request{Method: "POST", URL: "{{twins.things.url}}/things?name=w{{bench.worker}}-{{bench.iteration}}"},
+				Capture: map[string]string{"id": "$.id"},
+				Assert:  &Assert{Status: 201},
+			},
+			{Name: "Fault", InjectFault: &InjectFault{Twin: "things", Endpoint: "/things", StatusCode: 500}},
+			{Name: "Get", Request: Request{Method: "GET", URL: "{{twins.things.url}}/things/{{id}}"}},
+			{Name: "Broken", Request: Request{Method: "GET", URL: "{{twins.things.url}}/broken"}},
+		},
+	}
+
+	res, err := NewRunner(m).Bench(context.Background(), scenario, BenchOptions{RPS: 200, Duration: 300 * time.Millisecond, Concurrency: 4})
+	if err != nil {
+		t.Fatalf("Bench() error: %v", err)
+	}
+	// 200 req/s for 300ms is about 60 requests.
+	if res.Requests < 30 || res.Requests > 70 {
+		t.Errorf("expected about 60 requests, got %d", res.Requests)
+	}
+	if res.SkippedSteps != 1 || len(res.Steps) != 3 {
+		t.Fatalf("expected the admin step skipped and 3 request steps, got %d skipped, %d steps", res.SkippedSteps, len(res.Steps))
+	}
+	create, get, broken := res.Steps[0], res.Steps[1], res.Steps[2]
+	if create.Errors != 0 {
+		t.Errorf("expected unique names per replay, got %d create errors: %s", create.Errors, create.FirstError)
+	}
+	if get.Requests == 0 || get.Errors != 0 {
+		t.Errorf("expected captured IDs to feed Get, got %d requests, %d errors: %s", get.Requests, get.Errors, get.FirstError)
+	}
+	if broken.Requests == 0 || broken.Errors != broken.Requests || broken.FirstError != "status 500" {
+		t.Errorf("expected every 500 without assertions to count as an error, got %+v", broken)
+	}
+	if res.Errors != broken.Errors || res.StatusCodes[500] != broken.Requests {
+		t.Errorf("totals do not match steps: %d errors, statuses %v", res.Errors, res.StatusCodes)
+	}
+	if res.Latency.Max < res.Latency.P99 || res.Latency.P99 < res.Latency.P50 || res.Latency.P50 <= 0 {
+		t.Errorf("latency percentiles out of order: %+v", res.Latency)
+	}
+}
+
+func TestRunner_BenchOptions(t *testing.T) {
+	r := NewRunner(&manifest.Manifest{})
+	s := &Scenario{Name: "s", Steps: []Step{{Name: "get", Request: Request{Method: "GET", URL: "http://localhost:1/"}}}}
+	if _, err := r.Bench(context.Background(), s, BenchOptions{Duration: time.Second}); err == nil {
+		t.Error("expected an error without a rate")
+	}
+	if _, err := r.Bench(context.Background(), s, BenchOptions{RPS: 10}); err == nil {
+		t.Error("expected an error without a duration")
+	}
+	admin := &Scenario{Name: "admin", Steps: []Step{{Name: "fault", RemoveFault: &RemoveFault{Twin: "x", Endpoint: "/"}}}}
+	if _, err := r.Bench(context.Background(), admin, BenchOptions{RPS: 10, Duration: time.Second}); err == nil {
+		t.Error("expected an error for a scenario without request steps")
+	}
+}
+
+func TestLatencyStats(t *testing.T) {
+	var d []time.Duration
+	for i := 100; i >= 1; i-- {
+		d = append(d, time.Duration(i)*time.Millisecond)
+	}
+	got := latencyStats(d)
+	want := LatencyStats{P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P95: 95 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond}
+	if got != want {
+		t.Errorf("latencyStats() = %+v, want %+v", got, want)
+	}
+	if latencyStats(nil) != (LatencyStats{}) {
+		t.Error("expected zero stats for no samples")
+	}
+}
@@ -0,0 +1,171 @@
+package v2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// defaultExecTimeout bounds an exec step without a timeout of its own. It
+// is longer than the request timeout since commands often start a runtime
+// or compile a client first.
+const defaultExecTimeout = time.Minute
+
+// validateExec checks an exec step, which asserts through its own fields
+// and cannot be combined with a request or an admin action.
+func (s *Step) validateExec() error {
+	if s.isAdmin() || s.Request.Method != "" || s.Request.URL != "" {
+		return fmt.Errorf("exec steps cannot also define a request or an admin action")
+	}
+	if s.Assert != nil {
+		return fmt.Errorf("exec steps assert with exec.exit_code, exec.stdout_contains, and exec.stdout")
+	}
+	e := s.Exec
+	if len(e.Command) == 0 || e.Command[0] == "" {
+		return fmt.Errorf("exec: command is required")
+	}
+	if e.ExitCode < 0 || e.ExitCode > 255 {
+		return fmt.Errorf("exec: exit_code must be between 0 and 255, got %d", e.ExitCode)
+	}
+	return nil
+}
+
+// runExecStep runs an exec step's command and checks its exit code and
+// stdout, capturing variables from stdout.
+func (r *Runner) runExecStep(ctx context.Context, step *Step, vars map[string]string) StepResult {
+	start := time.Now()
+	sr := StepResult{Name: step.Name}
+	defer func() { sr.Duration = time.Since(start) }()
+
+	e := step.Exec
+	timeout := defaultExecTimeout
+	if step.Timeout != "" {
+		if d, err := parsePositiveDuration(step.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := make([]string, len(e.Command))
+	for i, arg := range e.Command {
+		expanded, err := ExpandTemplates(arg, r.manifest, vars)
+		if err != nil {
+			sr.Error = fmt.Sprintf("template expansion in command: %v", err)
+			return sr
+		}
+		args[i] = expanded
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = e.Dir
+	cmd.Env = append(os.Environ(), twinEnv(r.manifest)...)
+	for k, v := range e.Env {
+		expanded, err := ExpandTemplates(v, r.manifest, vars)
+		if err != nil {
+			sr.Error = fmt.Sprintf("template expansion in env %q: %v", k, err)
+			return sr
+		}
+		cmd.Env = append(cmd.Env, k+"="+expanded)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	// Don't wait on grandchildren still holding the pipes after a timeout.
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		sr.Error = fmt.Sprintf("%s did not finish within %s", args[0], timeout)
+		return sr
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		sr.Error = fmt.Sprintf("running %s: %v", args[0], err)
+		return sr
+	}
+	sr.Body = stdout.Bytes()
+	if len(sr.Body) > maxResponseBody {
+		sr.Body = sr.Body[:maxResponseBody]
+	}
+
+	if exitCode != e.ExitCode {
+		sr.Error = fmt.Sprintf("expected exit code %d, got %d; stderr: %s", e.ExitCode, exitCode, snippet(stderr.String()))
+		return sr
+	}
+	if err := captureVars(step.Capture, sr.Body, vars); err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	if e.StdoutContains != "" {
+		expanded, err := ExpandTemplates(e.StdoutContains, r.manifest, vars)
+		if err != nil {
+			sr.Error = fmt.Sprintf("template expansion in stdout_contains: %v", err)
+			return sr
+		}
+		if !strings.Contains(stdout.String(), expanded) {
+			sr.Error = fmt.Sprintf("stdout does not contain %q; stdout: %s", expanded, snippet(stdout.String()))
+			return sr
+		}
+	}
+	if len(e.Stdout) > 0 {
+		assertions, err := expandAssertions(e.Stdout, r.manifest, vars)
+		if err != nil {
+			sr.Error = err.Error()
+			return sr
+		}
+		if err := EvaluateBodyAssertions(sr.Body, assertions); err != nil {
+			sr.Error = "stdout: " + err.Error()
+			return sr
+		}
+	}
+
+	sr.Passed = true
+	return sr
+}
+
+// twinEnv returns the environment an exec step's command gets to find the
+// manifest's twins.
+func twinEnv(m *manifest.Manifest) []string {
+	var env []string
+	for _, name := range m.TwinNames() {
+		twin := m.Twins[name]
+		prefix := "WT_" + envName(name)
+		env = append(env, prefix+"_URL="+twin.BaseURL(), prefix+"_ADMIN_URL="+twin.AdminURL())
+		if twin.Socket != "" {
+			env = append(env, prefix+"_SOCKET="+twin.Socket)
+		}
+	}
+	return env
+}
+
+// envName turns a twin name into an environment variable name part, e.g.
+// "stripe-eu" into "STRIPE_EU".
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// snippet shortens command output for an error message.
+func snippet(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 200 {
+		s = s[:200] + "..."
+	}
+	return s
+}
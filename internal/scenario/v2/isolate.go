@@ -0,0 +1,184 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// tenant is an account created on one twin for a single isolated run.
+type tenant struct {
+	ID          string            `json:"id"`
+	Credentials map[string]string `json:"credentials"`
+	Headers     map[string]string `json:"headers"`
+}
+
+// TenantNamePrefix starts the name of every tenant an isolated run creates,
+// so tenants a crashed run left behind can be told apart (see wt prune).
+const TenantNamePrefix = "wt test: "
+
+// namespace holds the tenants an isolated run created, keyed by twin name.
+type namespace map[string]tenant
+
+// Isolate makes later runs isolated from each other, so scenarios can run
+// in parallel against the same twins. It asks each twin in the manifest
+// whether it supports tenants (GET /admin/tenants). Each run then creates a
+// tenant on every such twin, sends its requests to that twin with the
+// tenant's headers, skips resetting it (a new tenant starts empty), and
+// deletes the tenant afterwards. The tenant's credentials are available to
+// the scenario as {{tenant.<twin>.id}} and {{tenant.<twin>.<credential>}}.
+//
+// Twins without tenant support stay shared; see Isolatable.
+func (r *Runner) Isolate() {
+	r.tenantTwins = make(map[string]bool)
+	for _, name := range r.manifest.TwinNames() {
+		twin, _ := r.manifest.Twin(name)
+		resp, err := r.http.Get(twin.AdminURL() + "/admin/tenants")
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		r.tenantTwins[name] = resp.StatusCode == http.StatusOK
+	}
+}
+
+// Isolatable reports whether s can run alongside other isolated runs. A
+// scenario that seeds state, performs admin steps (faults, time travel,
+// config, quirks), or resets or sends requests to a twin without tenant
+// support changes state every run shares, and must run on its own. So must
+// one with exec steps, whose commands send no tenant headers, and one with
+// an SLO, which other runs' requests in the twin's request log would skew.
+// A request URL that names no twin of the manifest counts as shared.
+func (r *Runner) Isolatable(s *Scenario) bool {
+	if len(s.SLO) > 0 {
+		return false
+	}
+	if s.Setup != nil {
+		if len(s.Setup.SeedFiles) > 0 {
+			return false
+		}
+		for _, name := range s.Setup.Reset {
+			if !r.tenantTwins[name] {
+				return false
+			}
+		}
+	}
+	for i := range s.Steps {
+		if s.Steps[i].isAdmin() || s.Steps[i].Exec != nil {
+			return false
+		}
+		if twin := r.requestTwin(s.Steps[i].Request.URL); !r.tenantTwins[twin] {
+			return false
+		}
+	}
+	return true
+}
+
+// twinTemplate matches a {{twins.<name>.<field>}} template, capturing the name.
+var twinTemplate = regexp.MustCompile(`^\{\{\s*twins\.([^.}\s]+)\.`)
+
+// requestTwin returns the twin a step's unexpanded request URL addresses,
+// through a twins template or the twin's base URL, or "" if it names none.
+func (r *Runner) requestTwin(rawURL string) string {
+	if m := twinTemplate.FindStringSubmatch(rawURL); m != nil {
+		return m[1]
+	}
+	for _, name := range r.manifest.TwinNames() {
+		twin, _ := r.manifest.Twin(name)
+		base := strings.TrimSuffix(twin.BaseURL(), "/")
+		if rawURL == base || strings.HasPrefix(rawURL, base+"/") || strings.HasPrefix(rawURL, base+"?") {
+			return name
+		}
+	}
+	return ""
+}
+
+// createNamespace creates a tenant named after the scenario on every twin
+// that supports them. On failure it deletes the tenants already created.
+func (r *Runner) createNamespace(scenario string) (namespace, error) {
+	ns := make(namespace)
+	for _, name := range r.manifest.TwinNames() {
+		if !r.tenantTwins[name] {
+			continue
+		}
+		t, err := r.createTenant(name, TenantNamePrefix+scenario)
+		if err != nil {
+			r.deleteNamespace(ns)
+			return nil, fmt.Errorf("creating tenant on %s: %w", name, err)
+		}
+		ns[name] = t
+	}
+	return ns, nil
+}
+
+func (r *Runner) createTenant(twinName, tenantName string) (tenant, error) {
+	twin, err := r.manifest.Twin(twinName)
+	if err != nil {
+		return tenant{}, err
+	}
+	body, _ := json.Marshal(map[string]string{"name": tenantName})
+	resp, err := r.http.Post(twin.AdminURL()+"/admin/tenants", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return tenant{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return tenant{}, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var t tenant
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return tenant{}, fmt.Errorf("decoding tenant: %w", err)
+	}
+	return t, nil
+}
+
+// deleteNamespace deletes the run's tenants. Failures are ignored: a twin
+// that cannot delete tenants keeps them until its next reset, and they
+// never collide with another run's.
+func (r *Runner) deleteNamespace(ns namespace) {
+	for name, t := range ns {
+		twin, err := r.manifest.Twin(name)
+		if err != nil {
+			continue
+		}
+		req, err := http.NewRequest(http.MethodDelete, twin.AdminURL()+"/admin/tenants/"+url.PathEscape(t.ID), nil)
+		if err != nil {
+			continue
+		}
+		if resp, err := r.http.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+// vars returns the tenants' IDs and credentials as template variables.
+func (ns namespace) vars() map[string]string {
+	vars := make(map[string]string)
+	for name, t := range ns {
+		vars["tenant."+name+".id"] = t.ID
+		for k, v := range t.Credentials {
+			vars["tenant."+name+"."+k] = v
+		}
+	}
+	return vars
+}
+
+// namespaceHeaders returns the tenant headers for a request to rawURL, or
+// nil when it is not addressed to a twin with a tenant in ns.
+func (r *Runner) namespaceHeaders(ns namespace, rawURL string) map[string]string {
+	for name, t := range ns {
+		twin, err := r.manifest.Twin(name)
+		if err != nil {
+			continue
+		}
+		base := strings.TrimSuffix(twin.BaseURL(), "/")
+		if rawURL == base || strings.HasPrefix(rawURL, base+"/") || strings.HasPrefix(rawURL, base+"?") {
+			return t.Headers
+		}
+	}
+	return nil
+}
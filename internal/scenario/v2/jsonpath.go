@@ -8,8 +8,10 @@ import (
 )
 
 // jsonPathGet evaluates a simple JSONPath expression against parsed JSON data.
-// Supports dot-notation paths like $.field, $.field.nested, $.array[0], $.array[0].field.
-// Returns a slice of matching values (empty if no match).
+// Supports dot-notation paths like $.field, $.field.nested, $.array[0],
+// $.array[0].field, and the wildcard $.array[*].field, which selects the
+// field of every element. Returns a slice of matching values (empty if no
+// match); a wildcard path matches once, with the array of values it selected.
 func jsonPathGet(doc any, path string) ([]any, error) {
 	if !strings.HasPrefix(path, "$") {
 		return nil, fmt.Errorf("JSONPath must start with $: %q", path)
@@ -26,7 +28,8 @@ func jsonPathGet(doc any, path string) ([]any, error) {
 		rest = rest[1:]
 	}
 
-	current := doc
+	current := []any{doc}
+	wildcard := false
 	segments := splitPathSegments(rest)
 
 	for _, seg := range segments {
@@ -34,46 +37,55 @@ func jsonPathGet(doc any, path string) ([]any, error) {
 			continue
 		}
 
-		// Check for array index notation: field[0]
+		field, indexStr, indexed := seg, "", false
 		if idx := strings.Index(seg, "["); idx >= 0 {
-			field := seg[:idx]
-			indexStr := strings.TrimSuffix(seg[idx+1:], "]")
+			field, indexStr, indexed = seg[:idx], strings.TrimSuffix(seg[idx+1:], "]"), true
+		}
+		arrIdx := 0
+		if indexed && indexStr != "*" {
+			var err error
+			if arrIdx, err = strconv.Atoi(indexStr); err != nil {
+				return nil, fmt.Errorf("invalid array index in %q: %w", seg, err)
+			}
+		}
 
-			// Navigate to the field first (if non-empty)
+		// Values without the field, or that are not arrays, drop out
+		var next []any
+		for _, v := range current {
 			if field != "" {
-				val, err := getField(current, field)
+				val, err := getField(v, field)
 				if err != nil {
-					return nil, nil // no match
+					continue
 				}
-				current = val
+				v = val
 			}
-
-			// Parse the array index
-			arrIdx, err := strconv.Atoi(indexStr)
-			if err != nil {
-				return nil, fmt.Errorf("invalid array index in %q: %w", seg, err)
+			if !indexed {
+				next = append(next, v)
+				continue
 			}
-
-			arr, ok := current.([]any)
+			arr, ok := v.([]any)
 			if !ok {
-				return nil, nil // no match
+				continue
 			}
-			if arrIdx < 0 || arrIdx >= len(arr) {
-				return nil, nil // out of bounds
+			if indexStr == "*" {
+				next = append(next, arr...)
+			} else if arrIdx >= 0 && arrIdx < len(arr) {
+				next = append(next, arr[arrIdx])
 			}
-			current = arr[arrIdx]
-			continue
 		}
-
-		// Simple field access
-		val, err := getField(current, seg)
-		if err != nil {
-			return nil, nil // no match
+		if indexStr == "*" {
+			wildcard = true
 		}
-		current = val
+		current = next
 	}
 
-	return []any{current}, nil
+	if wildcard {
+		if current == nil {
+			current = []any{}
+		}
+		return []any{current}, nil
+	}
+	return current, nil
 }
 
 // splitPathSegments splits a path like "field.nested[0].name" into segments.
@@ -131,6 +131,42 @@ func TestJsonPathGet_ArrayIndex(t *testing.T) {
 	}
 }
 
+func TestJsonPathGet_Wildcard(t *testing.T) {
+	doc := map[string]any{
+		"items": []any{
+			map[string]any{"name": "first", "tags": []any{"a", "b"}},
+			map[string]any{"name": "second", "tags": []any{"c"}},
+			map[string]any{"id": 3},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$.items[*].name", "[first second]"},
+		{"$.items[*].tags[*]", "[a b c]"},
+		{"$.items[*].tags[0]", "[a c]"},
+		{"$.items[*].missing", "[]"},
+		{"$.missing[*]", "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			results, err := jsonPathGet(doc, tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("expected a single array result, got %v", results)
+			}
+			if got := fmt.Sprintf("%v", results[0]); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestJsonPathGet_RootDoc(t *testing.T) {
 	doc := map[string]any{"key": "value"}
 
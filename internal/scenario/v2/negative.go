@@ -0,0 +1,116 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// todo marks the values a generated skeleton leaves for the author to fill.
+const todo = "TODO"
+
+var (
+	// pathParam matches a {param} segment of a route pattern.
+	pathParam = regexp.MustCompile(`\{([^}/]+)\}`)
+	// nonSlug matches the runs of characters file names replace with "-".
+	nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// GeneratedScenario is a scenario skeleton and the file name to save it as.
+type GeneratedScenario struct {
+	File     string
+	Scenario *Scenario
+}
+
+// GenerateNegative returns a scenario skeleton for every error in catalog,
+// so each documented error of each endpoint gets a test. authPattern is the
+// twin's declared auth pattern; unless it is "none", requests send an
+// Authorization header from the {{auth}} variable, except in 401 scenarios.
+//
+// Skeletons are runnable but leave TODO variables where a valid ID, a
+// credential, or a triggering request body has to be supplied. 404
+// scenarios address a nonexistent ID, and 429 scenarios inject a fault
+// rather than exhausting a real rate limit.
+func GenerateNegative(twin, authPattern string, catalog []manifest.EndpointErrors) []GeneratedScenario {
+	var out []GeneratedScenario
+	seen := make(map[string]int)
+	for _, ep := range catalog {
+		for _, e := range ep.Errors {
+			// An endpoint may document several errors with one status
+			name := negativeFileName(twin, ep.Method, ep.Path, e.Status)
+			if seen[name]++; seen[name] > 1 {
+				name = fmt.Sprintf("%s-%d.json", strings.TrimSuffix(name, ".json"), seen[name])
+			}
+			out = append(out, GeneratedScenario{File: name, Scenario: negativeScenario(twin, authPattern, ep, e)})
+		}
+	}
+	return out
+}
+
+func negativeScenario(twin, authPattern string, ep manifest.EndpointErrors, e manifest.DocumentedError) *Scenario {
+	method := strings.ToUpper(ep.Method)
+	s := &Scenario{
+		Name:        fmt.Sprintf("%s %s %s returns %d", twin, method, ep.Path, e.Status),
+		Description: e.Description,
+		Variables:   map[string]string{},
+	}
+
+	// A 401, 404, or injected 429 is decided before the handler looks the
+	// resource up, so any ID will do; other errors need a real one.
+	path := pathParam.ReplaceAllStringFunc(ep.Path, func(m string) string {
+		name := m[1 : len(m)-1]
+		switch e.Status {
+		case http.StatusUnauthorized, http.StatusTooManyRequests:
+			return "example_" + name
+		case http.StatusNotFound:
+			return "999999" // valid as a numeric or string ID
+		}
+		s.Variables[name] = todo
+		return "{{" + name + "}}"
+	})
+
+	req := Request{
+		Method: method,
+		URL:    "{{twins." + twin + ".url}}" + path,
+	}
+	if e.Status != http.StatusUnauthorized && authPattern != "none" {
+		s.Variables["auth"] = todo
+		req.Headers = map[string]string{"Authorization": "{{auth}}"}
+	}
+	switch {
+	case e.Body != nil:
+		req.Body = e.Body
+	case method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch:
+		req.Body = map[string]any{}
+	}
+
+	assert := &Assert{Status: e.Status}
+	if e.Status != http.StatusTooManyRequests { // an injected fault has its own body
+		assert.BodyContains = e.Message
+	}
+	step := Step{Name: fmt.Sprintf("%s %s returns %d", method, ep.Path, e.Status), Request: req, Assert: assert}
+
+	if e.Status == http.StatusTooManyRequests {
+		s.Steps = []Step{
+			{Name: "simulate rate limiting", InjectFault: &InjectFault{Twin: twin, Endpoint: path, StatusCode: http.StatusTooManyRequests}},
+			step,
+			{Name: "stop rate limiting", RemoveFault: &RemoveFault{Twin: twin, Endpoint: path}},
+		}
+	} else {
+		s.Steps = []Step{step}
+	}
+	if len(s.Variables) == 0 {
+		s.Variables = nil
+	}
+	return s
+}
+
+// negativeFileName names a skeleton after its endpoint and status, e.g.
+// loyaltylion-get-v2-customers-id-404.json.
+func negativeFileName(twin, method, path string, status int) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(path), "-"), "-")
+	return fmt.Sprintf("%s-%s-%s-%d.json", twin, strings.ToLower(method), slug, status)
+}
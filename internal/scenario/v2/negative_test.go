@@ -0,0 +1,110 @@
+package v2
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+func TestGenerateNegative(t *testing.T) {
+	catalog := []manifest.EndpointErrors{
+		{Method: "GET", Path: "/v1/customers/{id}", Errors: []manifest.DocumentedError{
+			{Status: 401},
+			{Status: 404, Message: "No such customer"},
+			{Status: 429},
+		}},
+		{Method: "POST", Path: "/v1/customers/{id}/refunds", Errors: []manifest.DocumentedError{
+			{Status: 409, Message: "already refunded"},
+			{Status: 422, Body: map[string]any{"amount": -1}, Message: "amount must be positive"},
+			{Status: 422, Body: map[string]any{}, Message: "amount is required"},
+		}},
+	}
+
+	got := GenerateNegative("stripe", "api_key", catalog)
+	if len(got) != 6 {
+		t.Fatalf("expected 6 scenarios, got %d", len(got))
+	}
+	byFile := make(map[string]*Scenario)
+	dir := t.TempDir()
+	for _, g := range got {
+		if _, dup := byFile[g.File]; dup {
+			t.Fatalf("duplicate file name %s", g.File)
+		}
+		byFile[g.File] = g.Scenario
+
+		// Every skeleton must load as a valid scenario.
+		data, err := json.Marshal(g.Scenario)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, g.File)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadScenario(path); err != nil {
+			t.Errorf("LoadScenario(%s) error: %v", g.File, err)
+		}
+	}
+
+	unauthorized := byFile["stripe-get-v1-customers-id-401.json"]
+	if unauthorized == nil {
+		t.Fatal("missing 401 scenario")
+	}
+	req := unauthorized.Steps[0].Request
+	if req.URL != "{{twins.stripe.url}}/v1/customers/example_id" || req.Headers != nil {
+		t.Errorf("401 request: expected no credentials and a placeholder ID, got %+v", req)
+	}
+
+	notFound := byFile["stripe-get-v1-customers-id-404.json"]
+	step := notFound.Steps[0]
+	if !strings.HasSuffix(step.Request.URL, "/v1/customers/999999") {
+		t.Errorf("404 URL: expected a nonexistent ID, got %s", step.Request.URL)
+	}
+	if step.Request.Headers["Authorization"] != "{{auth}}" || notFound.Variables["auth"] != todo {
+		t.Errorf("404: expected a TODO auth variable, got headers %v vars %v", step.Request.Headers, notFound.Variables)
+	}
+	if step.Assert.Status != 404 || step.Assert.BodyContains != "No such customer" {
+		t.Errorf("404 assert: got %+v", step.Assert)
+	}
+
+	rateLimited := byFile["stripe-get-v1-customers-id-429.json"]
+	if len(rateLimited.Steps) != 3 {
+		t.Fatalf("429: expected inject, request, remove steps, got %d", len(rateLimited.Steps))
+	}
+	if f := rateLimited.Steps[0].InjectFault; f == nil || f.StatusCode != 429 || f.Endpoint != "/v1/customers/example_id" {
+		t.Errorf("429: expected a fault on the requested path, got %+v", f)
+	}
+	if rateLimited.Steps[2].RemoveFault == nil {
+		t.Error("429: expected the fault to be removed")
+	}
+
+	conflict := byFile["stripe-post-v1-customers-id-refunds-409.json"]
+	if conflict.Variables["id"] != todo || !strings.Contains(conflict.Steps[0].Request.URL, "{{id}}") {
+		t.Errorf("409: expected the ID left as a TODO variable, got %s %v", conflict.Steps[0].Request.URL, conflict.Variables)
+	}
+	if body, ok := conflict.Steps[0].Request.Body.(map[string]any); !ok || len(body) != 0 {
+		t.Errorf("409: expected an empty JSON body for POST, got %v", conflict.Steps[0].Request.Body)
+	}
+
+	second := byFile["stripe-post-v1-customers-id-refunds-422-2.json"]
+	if second == nil || second.Steps[0].Assert.BodyContains != "amount is required" {
+		t.Errorf("expected the second 422 in its own file, got %v", second)
+	}
+}
+
+func TestGenerateNegative_NoAuth(t *testing.T) {
+	catalog := []manifest.EndpointErrors{
+		{Method: "GET", Path: "/things/{id}", Errors: []manifest.DocumentedError{{Status: 404}}},
+	}
+	got := GenerateNegative("things", "none", catalog)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 scenario, got %d", len(got))
+	}
+	if got[0].Scenario.Variables != nil || got[0].Scenario.Steps[0].Request.Headers != nil {
+		t.Errorf("expected no credentials for a twin without auth, got %+v", got[0].Scenario)
+	}
+}
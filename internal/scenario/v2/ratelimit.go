@@ -0,0 +1,146 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate-limit headers read by rate_limit assertions unless a step names
+// others, as twins and most providers send them.
+const (
+	defaultLimitHeader     = "X-RateLimit-Limit"
+	defaultRemainingHeader = "X-RateLimit-Remaining"
+)
+
+// lastHeaders holds the headers of the latest response from each host in a
+// scenario run, for rate_limit assertions that compare with it.
+type lastHeaders map[string]http.Header
+
+// record keeps resp's headers as the latest from its host. A nil
+// lastHeaders records nothing.
+func (l lastHeaders) record(resp *http.Response) {
+	if l != nil {
+		l[resp.Request.URL.Host] = resp.Header
+	}
+}
+
+// validateRateLimit checks the durations of a rate_limit assertion.
+func validateRateLimit(a *RateLimitAssert) error {
+	if a.RetryAfter == nil {
+		return nil
+	}
+	for field, v := range map[string]string{"min": a.RetryAfter.Min, "max": a.RetryAfter.Max} {
+		if v == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(v); err != nil || d < 0 {
+			return fmt.Errorf("rate_limit.retry_after.%s must be a non-negative duration like \"30s\", got %q", field, v)
+		}
+	}
+	return nil
+}
+
+// evaluateRateLimit checks resp against a rate_limit assertion. prev holds
+// the headers of the previous response from the same host, or nil.
+func evaluateRateLimit(a *RateLimitAssert, resp *http.Response, prev http.Header) error {
+	limitHeader, remainingHeader := a.LimitHeader, a.RemainingHeader
+	if limitHeader == "" {
+		limitHeader = defaultLimitHeader
+	}
+	if remainingHeader == "" {
+		remainingHeader = defaultRemainingHeader
+	}
+
+	if a.Present {
+		for _, h := range []string{limitHeader, remainingHeader} {
+			if _, err := headerCount(resp.Header, h); err != nil {
+				return fmt.Errorf("rate_limit: %w", err)
+			}
+		}
+	}
+
+	if a.Decreasing {
+		remaining, err := headerCount(resp.Header, remainingHeader)
+		if err != nil {
+			return fmt.Errorf("rate_limit: %w", err)
+		}
+		if prev == nil {
+			return fmt.Errorf("rate_limit: decreasing needs an earlier response from %s", resp.Request.URL.Host)
+		}
+		before, err := headerCount(prev, remainingHeader)
+		if err != nil {
+			return fmt.Errorf("rate_limit: previous response: %w", err)
+		}
+		if remaining >= before {
+			return fmt.Errorf("rate_limit: expected %s to drop below %d, got %d", remainingHeader, before, remaining)
+		}
+	}
+
+	if ra := a.RetryAfter; ra != nil {
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return fmt.Errorf("rate_limit: expected a 429 with Retry-After, got status %d", resp.StatusCode)
+		}
+		wait, err := retryAfter(resp)
+		if err != nil {
+			return fmt.Errorf("rate_limit: %w", err)
+		}
+		if ra.Min != "" {
+			if lo, _ := time.ParseDuration(ra.Min); wait < lo {
+				return fmt.Errorf("rate_limit: Retry-After asks for %s, expected at least %s", wait, lo)
+			}
+		}
+		if ra.Max != "" {
+			if hi, _ := time.ParseDuration(ra.Max); wait > hi {
+				return fmt.Errorf("rate_limit: Retry-After asks for %s, expected at most %s", wait, hi)
+			}
+		}
+		if v := resp.Header.Get(remainingHeader); v != "" && v != "0" {
+			return fmt.Errorf("rate_limit: expected %s 0 on a 429, got %q", remainingHeader, v)
+		}
+	}
+	return nil
+}
+
+// headerCount parses header name of h as a non-negative integer.
+func headerCount(h http.Header, name string) (int, error) {
+	v := h.Get(name)
+	if v == "" {
+		return 0, fmt.Errorf("header %s is missing", name)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("header %s: expected a non-negative integer, got %q", name, v)
+	}
+	return n, nil
+}
+
+// retryAfter returns the wait resp's Retry-After header asks for. An HTTP
+// date is measured from the response's Date header, or from now without
+// one.
+func retryAfter(resp *http.Response) (time.Duration, error) {
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, fmt.Errorf("header Retry-After is missing")
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, fmt.Errorf("header Retry-After must not be negative, got %q", v)
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+	at, err := http.ParseTime(v)
+	if err != nil {
+		return 0, fmt.Errorf("header Retry-After: expected seconds or an HTTP date, got %q", v)
+	}
+	now := time.Now()
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		now = date
+	}
+	if wait := at.Sub(now); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
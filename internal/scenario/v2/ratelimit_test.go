@@ -0,0 +1,149 @@
+package v2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// rateLimitedServer allows limit requests, counting them down in
+// X-RateLimit-Remaining, then answers 429 with Retry-After.
+func rateLimitedServer(t *testing.T, limit int, retryAfter string) *manifest.Manifest {
+	var mu sync.Mutex
+	remaining := limit
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		if remaining == 0 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		remaining--
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	parts := strings.Split(srv.URL, ":")
+	port, _ := strconv.Atoi(parts[len(parts)-1])
+	return &manifest.Manifest{Twins: map[string]manifest.Twin{"api": {Port: port, AdminPort: port}}}
+}
+
+func rateLimitStep(name string, a *RateLimitAssert) Step {
+	return Step{
+		Name:    name,
+		Request: Request{Method: "GET", URL: "http://localhost:{{twins.api.port}}/v1/things"},
+		Assert:  &Assert{RateLimit: a},
+	}
+}
+
+func TestRunner_RateLimitAssertions(t *testing.T) {
+	m := rateLimitedServer(t, 2, "30")
+	result, err := NewRunner(m).Run(&Scenario{
+		Name: "rate limit",
+		Steps: []Step{
+			rateLimitStep("first", &RateLimitAssert{Present: true}),
+			rateLimitStep("second", &RateLimitAssert{Present: true, Decreasing: true}),
+			rateLimitStep("limited", &RateLimitAssert{RetryAfter: &RetryAfterAssert{Min: "1s", Max: "1m"}}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	for _, sr := range result.Steps {
+		if !sr.Passed {
+			t.Errorf("%s: %s", sr.Name, sr.Error)
+		}
+	}
+}
+
+func TestRunner_RateLimitFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr string
+	}{
+		{
+			name:    "decreasing without an earlier response",
+			steps:   []Step{rateLimitStep("only", &RateLimitAssert{Decreasing: true})},
+			wantErr: "needs an earlier response",
+		},
+		{
+			name: "remaining does not drop",
+			steps: []Step{
+				rateLimitStep("first", nil),
+				rateLimitStep("second", nil),
+				rateLimitStep("limited", nil),
+				rateLimitStep("still limited", &RateLimitAssert{Decreasing: true}),
+			},
+			wantErr: "expected X-RateLimit-Remaining to drop below 0, got 0",
+		},
+		{
+			name:    "retry_after before the limit",
+			steps:   []Step{rateLimitStep("first", &RateLimitAssert{RetryAfter: &RetryAfterAssert{}})},
+			wantErr: "expected a 429 with Retry-After, got status 200",
+		},
+		{
+			name: "retry_after too long",
+			steps: []Step{
+				rateLimitStep("first", nil),
+				rateLimitStep("second", nil),
+				rateLimitStep("limited", &RateLimitAssert{RetryAfter: &RetryAfterAssert{Max: "10s"}}),
+			},
+			wantErr: "Retry-After asks for 30s, expected at most 10s",
+		},
+		{
+			name:    "custom header missing",
+			steps:   []Step{rateLimitStep("first", &RateLimitAssert{Present: true, LimitHeader: "RateLimit-Limit"})},
+			wantErr: "header RateLimit-Limit is missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := rateLimitedServer(t, 2, "30")
+			result, err := NewRunner(m).Run(&Scenario{Name: tt.name, Steps: tt.steps})
+			if err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+			last := result.Steps[len(result.Steps)-1]
+			if last.Passed || !strings.Contains(last.Error, tt.wantErr) {
+				t.Errorf("expected the last step to fail with %q, got passed=%v error %q", tt.wantErr, last.Passed, last.Error)
+			}
+		})
+	}
+}
+
+func TestRetryAfterHTTPDate(t *testing.T) {
+	date := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Date", date.Format(http.TimeFormat))
+	resp.Header.Set("Retry-After", date.Add(90*time.Second).Format(http.TimeFormat))
+	if wait, err := retryAfter(resp); err != nil || wait != 90*time.Second {
+		t.Errorf("expected 90s, got %s (%v)", wait, err)
+	}
+
+	resp.Header.Set("Retry-After", "soon")
+	if _, err := retryAfter(resp); err == nil {
+		t.Error("expected an unparseable Retry-After to be rejected")
+	}
+}
+
+func TestValidateStep_RateLimit(t *testing.T) {
+	step := rateLimitStep("bad", &RateLimitAssert{RetryAfter: &RetryAfterAssert{Max: "soon"}})
+	if err := validateStep(&step); err == nil || !strings.Contains(err.Error(), "rate_limit.retry_after.max") {
+		t.Errorf("expected an invalid max to be rejected, got %v", err)
+	}
+	fault := Step{Name: "limit", InjectFault: &InjectFault{Twin: "api", Endpoint: "/v1/things", StatusCode: 503, RetryAfter: 5}}
+	if err := validateStep(&fault); err == nil || !strings.Contains(err.Error(), "only applies to status_code 429") {
+		t.Errorf("expected retry_after on a 503 fault to be rejected, got %v", err)
+	}
+}
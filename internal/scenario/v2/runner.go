@@ -2,6 +2,7 @@ package v2
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,10 +19,14 @@ const maxResponseBody = 10 * 1024 * 1024
 
 // StepResult records the outcome of a single step.
 type StepResult struct {
-	Name     string
-	Passed   bool
-	Duration time.Duration
-	Error    string // empty when passed
+	Name       string
+	Passed     bool
+	Duration   time.Duration
+	Error      string // empty when passed
+	StatusCode int    // response status; 0 for admin and exec steps or when no response arrived
+	Method     string // request method and expanded URL of an HTTP step; empty for admin and exec steps
+	URL        string
+	Body       []byte // response body, or an exec step's stdout, read up to maxResponseBody; nil for admin steps
 }
 
 // Result records the outcome of an entire scenario.
@@ -36,6 +41,10 @@ type Result struct {
 type Runner struct {
 	manifest *manifest.Manifest
 	http     *http.Client
+
+	// tenantTwins records which twins support tenants; nil until Isolate
+	// is called, after which every run creates its own.
+	tenantTwins map[string]bool
 }
 
 // NewRunner creates a Runner with the given manifest and a default HTTP client.
@@ -60,14 +69,39 @@ func (r *Runner) Run(s *Scenario) (*Result, error) {
 		vars[k] = v
 	}
 
+	var ns namespace
+	if r.tenantTwins != nil {
+		var err error
+		if ns, err = r.createNamespace(s.Name); err != nil {
+			return nil, err
+		}
+		defer r.deleteNamespace(ns)
+		for k, v := range ns.vars() {
+			vars[k] = v
+		}
+	}
+
 	// --- Setup phase ---
 	if s.Setup != nil {
-		if err := r.runSetup(s.Setup); err != nil {
+		if err := r.runSetup(s.Setup, ns); err != nil {
 			return nil, fmt.Errorf("setup failed: %w", err)
 		}
 	}
 
 	// --- Steps phase ---
+	ctx := context.Background()
+	if s.MaxDuration != "" {
+		maxDuration, err := parsePositiveDuration(s.MaxDuration)
+		if err != nil {
+			return nil, fmt.Errorf("max_duration: %w", err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, maxDuration)
+		defer cancel()
+	}
+
+	stepsStart := time.Now()
+	seen := lastHeaders{}
 	var stopEarly bool
 	for _, step := range s.Steps {
 		if stopEarly {
@@ -75,30 +109,50 @@ func (r *Runner) Run(s *Scenario) (*Result, error) {
 			result.Steps = append(result.Steps, sr)
 			continue
 		}
-		sr := r.runStep(&step, vars)
+		if ctx.Err() != nil {
+			result.Passed = false
+			sr := StepResult{Name: step.Name, Error: "skipped: scenario exceeded max_duration " + s.MaxDuration}
+			result.Steps = append(result.Steps, sr)
+			continue
+		}
+		sr := r.runStepWithRetry(ctx, &step, vars, ns, seen)
 		result.Steps = append(result.Steps, sr)
 		if !sr.Passed {
 			result.Passed = false
-			if len(step.Capture) > 0 {
+			// Later steps depend on captured values or on the admin
+			// action or command having taken effect.
+			if len(step.Capture) > 0 || step.isAdmin() || step.Exec != nil {
 				stopEarly = true
 			}
 		}
 	}
 
+	// --- SLO phase ---
+	for _, sr := range r.checkSLOs(s.SLO, stepsStart, time.Now()) {
+		result.Steps = append(result.Steps, sr)
+		if !sr.Passed {
+			result.Passed = false
+		}
+	}
+
 	result.Duration = time.Since(start)
 	return result, nil
 }
 
-// runSetup executes the reset and seed_files operations.
-func (r *Runner) runSetup(setup *Setup) error {
+// runSetup executes the reset and seed_files operations. Twins with a
+// tenant in ns are not reset, since the run's tenant starts empty.
+func (r *Runner) runSetup(setup *Setup, ns namespace) error {
 	// Reset twins
 	for _, name := range setup.Reset {
+		if _, ok := ns[name]; ok {
+			continue
+		}
 		twin, err := r.manifest.Twin(name)
 		if err != nil {
 			return fmt.Errorf("reset %s: %w", name, err)
 		}
 		resp, err := r.http.Post(
-			fmt.Sprintf("http://localhost:%d/admin/reset", twin.AdminPort),
+			twin.AdminURL()+"/admin/reset",
 			"application/json", nil,
 		)
 		if err != nil {
@@ -121,7 +175,7 @@ func (r *Runner) runSetup(setup *Setup) error {
 			return fmt.Errorf("seed %s: reading %s: %w", name, filePath, err)
 		}
 		resp, err := r.http.Post(
-			fmt.Sprintf("http://localhost:%d/admin/state", twin.AdminPort),
+			twin.AdminURL()+"/admin/state?expand=true",
 			"application/json",
 			bytes.NewReader(data),
 		)
@@ -137,8 +191,68 @@ func (r *Runner) runSetup(setup *Setup) error {
 	return nil
 }
 
-// runStep executes a single scenario step and returns its result.
-func (r *Runner) runStep(step *Step, vars map[string]string) StepResult {
+// defaultRetryBackoff is the wait before the first retry when a step's
+// retry policy does not set one.
+const defaultRetryBackoff = 500 * time.Millisecond
+
+// runStepWithRetry runs a step, re-running it according to its retry policy
+// until it passes, its attempts are exhausted, or ctx is done.
+func (r *Runner) runStepWithRetry(ctx context.Context, step *Step, vars map[string]string, ns namespace, seen lastHeaders) StepResult {
+	attempts, backoff := 1, defaultRetryBackoff
+	if step.Retry != nil {
+		attempts = step.Retry.Attempts
+		if step.Retry.Backoff != "" {
+			if d, err := parsePositiveDuration(step.Retry.Backoff); err == nil {
+				backoff = d
+			}
+		}
+	}
+
+	start := time.Now()
+	var sr StepResult
+	for attempt := 1; ; attempt++ {
+		sr = r.runStep(ctx, step, vars, ns, seen)
+		if sr.Passed || attempt >= attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			sr.Error += " (retries stopped: scenario exceeded max_duration)"
+			sr.Duration = time.Since(start)
+			return sr
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	if attempts > 1 && !sr.Passed {
+		sr.Error = fmt.Sprintf("%s (after %d attempts)", sr.Error, attempts)
+	}
+	sr.Duration = time.Since(start)
+	return sr
+}
+
+// runStep executes a single scenario step and returns its result. Requests
+// to a twin with a tenant in ns are sent with the tenant's headers. The
+// response's headers are recorded in seen, when set, for later rate_limit
+// assertions.
+func (r *Runner) runStep(ctx context.Context, step *Step, vars map[string]string, ns namespace, seen lastHeaders) StepResult {
+	// A step timeout replaces the client's default timeout for this step only.
+	client := r.http
+	if step.Timeout != "" {
+		if d, err := parsePositiveDuration(step.Timeout); err == nil {
+			c := *r.http
+			c.Timeout = d
+			client = &c
+		}
+	}
+
+	if step.isAdmin() {
+		return r.runAdminStep(ctx, client, step)
+	}
+	if step.Exec != nil {
+		return r.runExecStep(ctx, step, vars)
+	}
+
 	start := time.Now()
 	sr := StepResult{Name: step.Name}
 	defer func() { sr.Duration = time.Since(start) }()
@@ -161,8 +275,10 @@ func (r *Runner) runStep(step *Step, vars map[string]string) StepResult {
 		reqBody = strings.NewReader(bodyStr)
 	}
 
+	sr.Method, sr.URL = step.Request.Method, url
+
 	// Build HTTP request
-	req, err := http.NewRequest(step.Request.Method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, step.Request.Method, url, reqBody)
 	if err != nil {
 		sr.Error = fmt.Sprintf("building request: %v", err)
 		return sr
@@ -177,6 +293,9 @@ func (r *Runner) runStep(step *Step, vars map[string]string) StepResult {
 		}
 		req.Header.Set(k, expanded)
 	}
+	for k, v := range r.namespaceHeaders(ns, url) {
+		req.Header.Set(k, v)
+	}
 
 	// Set content-type for body requests if not already set
 	if step.Request.Body != nil && req.Header.Get("Content-Type") == "" {
@@ -184,34 +303,32 @@ func (r *Runner) runStep(step *Step, vars map[string]string) StepResult {
 	}
 
 	// Execute request
-	resp, err := r.http.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		sr.Error = fmt.Sprintf("request failed: %v", err)
 		return sr
 	}
 	defer resp.Body.Close()
+	sr.StatusCode = resp.StatusCode
+	prev := seen[resp.Request.URL.Host]
+	seen.record(resp)
 
 	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
 	if err != nil {
 		sr.Error = fmt.Sprintf("reading response body: %v", err)
 		return sr
 	}
+	sr.Body = respBody
 
 	// Capture variables from response
-	if len(step.Capture) > 0 {
-		for varName, jsonPath := range step.Capture {
-			val, err := ExtractJSONPath(respBody, jsonPath)
-			if err != nil {
-				sr.Error = fmt.Sprintf("capture %q: %v", varName, err)
-				return sr
-			}
-			vars[varName] = fmt.Sprintf("%v", val)
-		}
+	if err := captureVars(step.Capture, respBody, vars); err != nil {
+		sr.Error = err.Error()
+		return sr
 	}
 
 	// Run assertions
 	if step.Assert != nil {
-		if err := runAssertions(step.Assert, resp, respBody, r.manifest, vars); err != nil {
+		if err := runAssertions(step.Assert, resp, prev, respBody, r.manifest, vars); err != nil {
 			sr.Error = err.Error()
 			return sr
 		}
@@ -237,8 +354,9 @@ func buildBody(body any, m *manifest.Manifest, vars map[string]string) (string,
 	return ExpandTemplates(string(data), m, vars)
 }
 
-// runAssertions evaluates all assertions against the HTTP response.
-func runAssertions(assert *Assert, resp *http.Response, body []byte, m *manifest.Manifest, vars map[string]string) error {
+// runAssertions evaluates all assertions against the HTTP response. prev
+// holds the headers of the previous response from the same host, or nil.
+func runAssertions(assert *Assert, resp *http.Response, prev http.Header, body []byte, m *manifest.Manifest, vars map[string]string) error {
 	// Assert status
 	if assert.Status != 0 && resp.StatusCode != assert.Status {
 		bodySnippet := string(body)
@@ -269,23 +387,50 @@ func runAssertions(assert *Assert, resp *http.Response, body []byte, m *manifest
 
 	// Assert body (JSONPath-based)
 	if len(assert.Body) > 0 {
-		// Expand templates in expected values before comparison
-		expandedAssertions := make(map[string]any, len(assert.Body))
-		for path, expected := range assert.Body {
-			if s, ok := expected.(string); ok {
-				expanded, err := ExpandTemplates(s, m, vars)
-				if err != nil {
-					return fmt.Errorf("template expansion in assertion %q: %v", path, err)
-				}
-				expandedAssertions[path] = expanded
-			} else {
-				expandedAssertions[path] = expected
-			}
+		expandedAssertions, err := expandAssertions(assert.Body, m, vars)
+		if err != nil {
+			return err
 		}
 		if err := EvaluateBodyAssertions(body, expandedAssertions); err != nil {
 			return err
 		}
 	}
 
+	if assert.RateLimit != nil {
+		if err := evaluateRateLimit(assert.RateLimit, resp, prev); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// captureVars sets each capture variable to the value at its JSONPath in body.
+func captureVars(capture map[string]string, body []byte, vars map[string]string) error {
+	for varName, jsonPath := range capture {
+		val, err := ExtractJSONPath(body, jsonPath)
+		if err != nil {
+			return fmt.Errorf("capture %q: %v", varName, err)
+		}
+		vars[varName] = fmt.Sprintf("%v", val)
+	}
 	return nil
 }
+
+// expandAssertions expands templates in the string values of JSONPath
+// assertions before comparison.
+func expandAssertions(assertions map[string]any, m *manifest.Manifest, vars map[string]string) (map[string]any, error) {
+	expanded := make(map[string]any, len(assertions))
+	for path, expected := range assertions {
+		if s, ok := expected.(string); ok {
+			v, err := ExpandTemplates(s, m, vars)
+			if err != nil {
+				return nil, fmt.Errorf("template expansion in assertion %q: %v", path, err)
+			}
+			expanded[path] = v
+		} else {
+			expanded[path] = expected
+		}
+	}
+	return expanded, nil
+}
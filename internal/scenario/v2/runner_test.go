@@ -2,11 +2,13 @@ package v2
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/wondertwin-ai/wondertwin/internal/manifest"
 )
@@ -64,6 +66,9 @@ func TestRunner_SimpleStep(t *testing.T) {
 			}
 		}
 	}
+	if sr := result.Steps[0]; sr.StatusCode != 200 || !strings.Contains(string(sr.Body), `"status":"ok"`) {
+		t.Errorf("expected the response recorded on the step, got %d %q", sr.StatusCode, sr.Body)
+	}
 }
 
 func TestRunner_VariableCapture(t *testing.T) {
@@ -340,3 +345,382 @@ func TestRunner_RequestWithBody(t *testing.T) {
 		t.Errorf("expected body name 'test', got %v", receivedBody["name"])
 	}
 }
+
+func TestRunner_AdminSteps(t *testing.T) {
+	type call struct{ method, path, body string }
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, call{r.Method, r.URL.Path, string(body)})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	parts := strings.Split(srv.URL, ":")
+	port, _ := strconv.Atoi(parts[len(parts)-1])
+	m := &manifest.Manifest{
+		Twins: map[string]manifest.Twin{"stripe": {Port: port, AdminPort: port}},
+	}
+
+	scenario := &Scenario{
+		Name: "Admin steps",
+		Steps: []Step{
+			{Name: "fault", InjectFault: &InjectFault{Twin: "stripe", Endpoint: "/v1/transfers", StatusCode: 500, DelayMS: 5}},
+			{Name: "time", AdvanceTime: &AdvanceTime{Twin: "stripe", Duration: "24h"}},
+			{Name: "config", SetConfig: &SetConfig{Twin: "stripe", Values: map[string]any{"verbose": true}}},
+			{Name: "quirk", EnableQuirk: &EnableQuirk{Twin: "stripe", Quirk: "slow-payouts"}},
+			{Name: "unfault", RemoveFault: &RemoveFault{Twin: "stripe", Endpoint: "/v1/transfers"}},
+		},
+	}
+
+	result, err := NewRunner(m).Run(scenario)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	for _, sr := range result.Steps {
+		if !sr.Passed {
+			t.Errorf("step %q failed: %s", sr.Name, sr.Error)
+		}
+	}
+
+	want := []call{
+		{"POST", "/admin/fault/v1/transfers", ""},
+		{"POST", "/admin/time/advance", `{"duration":"24h"}`},
+		{"PUT", "/admin/config", `{"verbose":true}`},
+		{"PUT", "/admin/quirks/slow-payouts", ""},
+		{"DELETE", "/admin/fault/v1/transfers", ""},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d admin calls, got %d: %+v", len(want), len(calls), calls)
+	}
+	for i, w := range want {
+		if calls[i].method != w.method || calls[i].path != w.path {
+			t.Errorf("call %d = %s %s, want %s %s", i, calls[i].method, calls[i].path, w.method, w.path)
+		}
+		if w.body != "" && calls[i].body != w.body {
+			t.Errorf("call %d body = %s, want %s", i, calls[i].body, w.body)
+		}
+	}
+	// delay_ms is sent as a time.Duration (nanoseconds), matching the admin API.
+	if !strings.Contains(calls[0].body, `"delay_ms":5000000`) {
+		t.Errorf("expected delay in nanoseconds, got %s", calls[0].body)
+	}
+}
+
+func TestRunner_AdminStepFailureSkipsRest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	parts := strings.Split(srv.URL, ":")
+	port, _ := strconv.Atoi(parts[len(parts)-1])
+	m := &manifest.Manifest{
+		Twins: map[string]manifest.Twin{"stripe": {Port: port, AdminPort: port}},
+	}
+
+	scenario := &Scenario{
+		Name: "Quirk missing",
+		Steps: []Step{
+			{Name: "quirk", EnableQuirk: &EnableQuirk{Twin: "stripe", Quirk: "nope"}},
+			{Name: "after", Request: Request{Method: "GET", URL: srv.URL}},
+		},
+	}
+
+	result, err := NewRunner(m).Run(scenario)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if result.Passed {
+		t.Fatal("expected scenario to fail")
+	}
+	if !strings.HasPrefix(result.Steps[1].Error, "skipped") {
+		t.Errorf("expected second step to be skipped, got %q", result.Steps[1].Error)
+	}
+}
+
+func TestRunner_ExecSteps(t *testing.T) {
+	var charged string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		charged = r.URL.Query().Get("id")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	parts := strings.Split(srv.URL, ":")
+	port, _ := strconv.Atoi(parts[len(parts)-1])
+	m := &manifest.Manifest{
+		Twins: map[string]manifest.Twin{"stripe-eu": {Port: port, AdminPort: port}},
+	}
+
+	scenario := &Scenario{
+		Name:      "SDK client",
+		Variables: map[string]string{"amount": "500"},
+		Steps: []Step{
+			{
+				Name: "charge",
+				Exec: &Exec{
+					Command: []string{"sh", "-c", `echo "{\"id\": \"ch_$AMOUNT\", \"url\": \"$WT_STRIPE_EU_URL\"}"`},
+					Env:     map[string]string{"AMOUNT": "{{amount}}"},
+					Stdout:  map[string]any{"$.url": "{{twins.stripe-eu.url}}"},
+				},
+				Capture: map[string]string{"charge_id": "$.id"},
+			},
+			{Name: "check", Request: Request{Method: "GET", URL: srv.URL + "?id={{charge_id}}"}},
+			{Name: "expected failure", Exec: &Exec{Command: []string{"sh", "-c", "echo declined; exit 3"}, ExitCode: 3, StdoutContains: "declined"}},
+		},
+	}
+
+	result, err := NewRunner(m).Run(scenario)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	for _, sr := range result.Steps {
+		if !sr.Passed {
+			t.Errorf("step %q failed: %s", sr.Name, sr.Error)
+		}
+	}
+	if charged != "ch_500" {
+		t.Errorf("expected the captured charge ID ch_500 in the request, got %q", charged)
+	}
+
+	scenario = &Scenario{
+		Name: "SDK client fails",
+		Steps: []Step{
+			{Name: "charge", Exec: &Exec{Command: []string{"sh", "-c", "echo boom >&2; exit 1"}}},
+			{Name: "after", Request: Request{Method: "GET", URL: srv.URL}},
+		},
+	}
+	result, err = NewRunner(m).Run(scenario)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if result.Passed || !strings.Contains(result.Steps[0].Error, "expected exit code 0, got 1; stderr: boom") {
+		t.Errorf("expected the exit code and stderr reported, got %q", result.Steps[0].Error)
+	}
+	if !strings.HasPrefix(result.Steps[1].Error, "skipped") {
+		t.Errorf("expected the step after a failed command to be skipped, got %q", result.Steps[1].Error)
+	}
+}
+
+func TestRunner_RetryUntilPass(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits < 3 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	scenario := &Scenario{
+		Name: "Eventually consistent",
+		Steps: []Step{{
+			Name:    "Poll",
+			Request: Request{Method: "GET", URL: srv.URL},
+			Assert:  &Assert{Status: 200},
+			Retry:   &Retry{Attempts: 3, Backoff: "1ms"},
+		}},
+	}
+
+	result, err := NewRunner(&manifest.Manifest{}).Run(scenario)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !result.Passed {
+		t.Fatalf("expected scenario to pass after retries: %s", result.Steps[0].Error)
+	}
+	if hits != 3 {
+		t.Errorf("expected 3 attempts, got %d", hits)
+	}
+}
+
+func TestRunner_RetryExhausted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	scenario := &Scenario{
+		Name: "Never ready",
+		Steps: []Step{{
+			Name:    "Poll",
+			Request: Request{Method: "GET", URL: srv.URL},
+			Assert:  &Assert{Status: 200},
+			Retry:   &Retry{Attempts: 2, Backoff: "1ms"},
+		}},
+	}
+
+	result, err := NewRunner(&manifest.Manifest{}).Run(scenario)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if result.Passed {
+		t.Fatal("expected scenario to fail")
+	}
+	if !strings.Contains(result.Steps[0].Error, "after 2 attempts") {
+		t.Errorf("expected attempt count in error, got %q", result.Steps[0].Error)
+	}
+}
+
+func TestRunner_StepTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	scenario := &Scenario{
+		Name: "Slow endpoint",
+		Steps: []Step{{
+			Name:    "Slow",
+			Request: Request{Method: "GET", URL: srv.URL},
+			Timeout: "20ms",
+		}},
+	}
+
+	result, err := NewRunner(&manifest.Manifest{}).Run(scenario)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if result.Passed {
+		t.Fatal("expected step to time out")
+	}
+	if !strings.Contains(result.Steps[0].Error, "request failed") {
+		t.Errorf("expected request failure, got %q", result.Steps[0].Error)
+	}
+}
+
+func TestRunner_MaxDuration(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	scenario := &Scenario{
+		Name:        "Runaway",
+		MaxDuration: "30ms",
+		Steps: []Step{
+			{Name: "First", Request: Request{Method: "GET", URL: srv.URL}},
+			{Name: "Second", Request: Request{Method: "GET", URL: srv.URL}},
+		},
+	}
+
+	result, err := NewRunner(&manifest.Manifest{}).Run(scenario)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if result.Passed {
+		t.Fatal("expected scenario to fail")
+	}
+	if !strings.Contains(result.Steps[1].Error, "exceeded max_duration") {
+		t.Errorf("expected second step to be skipped, got %q", result.Steps[1].Error)
+	}
+}
+
+func TestRunner_Isolate(t *testing.T) {
+	var created, deleted, resets int
+	tenants := http.NewServeMux()
+	tenants.HandleFunc("GET /admin/tenants", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	tenants.HandleFunc("POST /admin/tenants", func(w http.ResponseWriter, r *http.Request) {
+		created++
+		id := "key_" + strconv.Itoa(created)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":          id,
+			"credentials": map[string]string{"api_key": id},
+			"headers":     map[string]string{"Authorization": "Bearer " + id},
+		})
+	})
+	tenants.HandleFunc("DELETE /admin/tenants/{id}", func(w http.ResponseWriter, r *http.Request) {
+		deleted++
+	})
+	tenants.HandleFunc("POST /admin/reset", func(w http.ResponseWriter, r *http.Request) {
+		resets++
+	})
+	tenants.HandleFunc("POST /v1/whoami", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.NewEncoder(w).Encode(map[string]string{"auth": r.Header.Get("Authorization"), "body": string(body)})
+	})
+	withTenants := httptest.NewServer(tenants)
+	defer withTenants.Close()
+	shared := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/tenants" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer shared.Close()
+
+	m := &manifest.Manifest{Twins: map[string]manifest.Twin{
+		"loyaltylion": {URL: withTenants.URL},
+		"resend":      {URL: shared.URL},
+	}}
+	runner := NewRunner(m)
+	runner.Isolate()
+
+	s := &Scenario{
+		Name:  "isolated",
+		Setup: &Setup{Reset: []string{"loyaltylion"}},
+		Steps: []Step{
+			{
+				Name: "scoped to the tenant",
+				Request: Request{
+					Method:  "POST",
+					URL:     "{{twins.loyaltylion.url}}/v1/whoami",
+					Headers: map[string]string{"Authorization": "Bearer seeded"},
+					Body:    map[string]any{"key": "{{tenant.loyaltylion.api_key}}"},
+				},
+				Assert: &Assert{Body: map[string]any{
+					"$.auth": "Bearer key_1",
+					"$.body": `{"key":"key_1"}`,
+				}},
+			},
+			{
+				Name: "other twins are shared",
+				Request: Request{
+					Method:  "GET",
+					URL:     "{{twins.resend.url}}/emails",
+					Headers: map[string]string{"Authorization": "Bearer seeded"},
+				},
+				Assert: &Assert{BodyContains: "Bearer seeded"},
+			},
+		},
+	}
+	if runner.Isolatable(s) {
+		t.Error("expected a scenario sending requests to a twin without tenants not to be isolatable")
+	}
+	if !runner.Isolatable(&Scenario{Name: "tenant only", Setup: s.Setup, Steps: s.Steps[:1]}) {
+		t.Error("expected a scenario using only tenant twins to be isolatable")
+	}
+	if runner.Isolatable(&Scenario{Name: "elsewhere", Steps: []Step{{Name: "x", Request: Request{Method: "GET", URL: "{{base}}/v1/whoami"}}}}) {
+		t.Error("expected a request to no known twin not to be isolatable")
+	}
+	result, err := runner.Run(s)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	for _, sr := range result.Steps {
+		if !sr.Passed {
+			t.Errorf("%s: %s", sr.Name, sr.Error)
+		}
+	}
+	if created != 1 || deleted != 1 || resets != 0 {
+		t.Errorf("expected one tenant created and deleted and no reset, got %d, %d, %d", created, deleted, resets)
+	}
+
+	for _, s := range []*Scenario{
+		{Setup: &Setup{Reset: []string{"resend"}}},
+		{Setup: &Setup{SeedFiles: map[string]string{"loyaltylion": "seed.json"}}},
+		{Steps: []Step{{AdvanceTime: &AdvanceTime{Twin: "loyaltylion", Duration: "1h"}}}},
+	} {
+		if runner.Isolatable(s) {
+			t.Errorf("expected %+v not to be isolatable", s)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+)
+
+// validateSLO checks an SLO's bounds.
+func validateSLO(slo SLO) error {
+	if slo.MaxErrorRate != nil && (*slo.MaxErrorRate < 0 || *slo.MaxErrorRate > 1) {
+		return fmt.Errorf("max_error_rate must be between 0 and 1, got %v", *slo.MaxErrorRate)
+	}
+	for field, v := range map[string]string{"p95_latency": slo.P95Latency, "p99_latency": slo.P99Latency} {
+		if v == "" {
+			continue
+		}
+		if _, err := parsePositiveDuration(v); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+	}
+	if slo.MaxRPS < 0 {
+		return fmt.Errorf("max_rps must not be negative")
+	}
+	if slo.MinRequests < 0 {
+		return fmt.Errorf("min_requests must not be negative")
+	}
+	return nil
+}
+
+// checkSLOs checks each twin's SLO against the API requests its request log
+// recorded between from and to, returning a result per twin named
+// "slo: <twin>", in twin order.
+func (r *Runner) checkSLOs(slos map[string]SLO, from, to time.Time) []StepResult {
+	twins := make([]string, 0, len(slos))
+	for twin := range slos {
+		twins = append(twins, twin)
+	}
+	sort.Strings(twins)
+
+	results := make([]StepResult, 0, len(twins))
+	for _, name := range twins {
+		start := time.Now()
+		sr := StepResult{Name: "slo: " + name}
+		if err := r.checkSLO(name, slos[name], from, to); err != nil {
+			sr.Error = err.Error()
+		} else {
+			sr.Passed = true
+		}
+		sr.Duration = time.Since(start)
+		results = append(results, sr)
+	}
+	return results
+}
+
+// checkSLO checks one twin's SLO, returning its violations as the error.
+func (r *Runner) checkSLO(name string, slo SLO, from, to time.Time) error {
+	twin, err := r.manifest.Twin(name)
+	if err != nil {
+		return err
+	}
+	entries, err := adminclient.New(twin.AdminURL(), adminclient.WithHTTPClient(r.http)).Requests(context.Background())
+	if err != nil {
+		return fmt.Errorf("reading request log: %w", err)
+	}
+
+	var latencies []time.Duration
+	failed := 0
+	for _, e := range entries {
+		if e.Timestamp.Before(from) || e.Timestamp.After(to) || strings.HasPrefix(e.Path, "/admin/") {
+			continue
+		}
+		latencies = append(latencies, e.Duration)
+		if e.StatusCode >= 500 {
+			failed++
+		}
+	}
+	n := len(latencies)
+	stats := latencyStats(latencies)
+
+	var violations []string
+	if n < slo.MinRequests {
+		violations = append(violations, fmt.Sprintf("%d requests, expected at least %d", n, slo.MinRequests))
+	}
+	if slo.MaxErrorRate != nil && n > 0 {
+		if rate := float64(failed) / float64(n); rate > *slo.MaxErrorRate {
+			violations = append(violations, fmt.Sprintf("error rate %.3f (%d of %d answered 5xx), expected at most %v", rate, failed, n, *slo.MaxErrorRate))
+		}
+	}
+	for _, bound := range []struct {
+		name  string
+		limit string
+		got   time.Duration
+	}{
+		{"p95 latency", slo.P95Latency, stats.P95},
+		{"p99 latency", slo.P99Latency, stats.P99},
+	} {
+		if bound.limit == "" {
+			continue
+		}
+		if limit, err := parsePositiveDuration(bound.limit); err == nil && bound.got > limit {
+			violations = append(violations, fmt.Sprintf("%s %s, expected at most %s", bound.name, bound.got, limit))
+		}
+	}
+	if window := to.Sub(from).Seconds(); slo.MaxRPS > 0 && window > 0 {
+		if rps := float64(n) / window; rps > slo.MaxRPS {
+			violations = append(violations, fmt.Sprintf("%.1f requests per second, expected at most %v", rps, slo.MaxRPS))
+		}
+	}
+	if len(violations) > 0 {
+		return errors.New(strings.Join(violations, "; "))
+	}
+	return nil
+}
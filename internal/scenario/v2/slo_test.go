@@ -0,0 +1,98 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/internal/manifest"
+)
+
+// sloTwin serves /v1/ok and /v1/fail, logging them the way a twin's
+// request log does, and serves the log at /admin/requests.
+func sloTwin(t *testing.T) *manifest.Manifest {
+	t.Helper()
+	var mu sync.Mutex
+	var log []adminclient.RequestLogEntry
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/admin/requests" {
+			json.NewEncoder(w).Encode(log)
+			return
+		}
+		status := http.StatusOK
+		if r.URL.Path == "/v1/fail" {
+			status = http.StatusInternalServerError
+		}
+		log = append(log, adminclient.RequestLogEntry{Timestamp: time.Now(), Method: r.Method, Path: r.URL.Path, StatusCode: status, Duration: 20 * time.Millisecond})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	parts := strings.Split(srv.URL, ":")
+	port, _ := strconv.Atoi(parts[len(parts)-1])
+	return &manifest.Manifest{Twins: map[string]manifest.Twin{"stripe": {Port: port, AdminPort: port}}}
+}
+
+func TestRunner_SLO(t *testing.T) {
+	m := sloTwin(t)
+	steps := []Step{
+		{Name: "ok", Request: Request{Method: "GET", URL: "{{twins.stripe.url}}/v1/ok"}},
+		{Name: "ok again", Request: Request{Method: "GET", URL: "{{twins.stripe.url}}/v1/ok"}},
+		{Name: "fail", Request: Request{Method: "GET", URL: "{{twins.stripe.url}}/v1/fail"}},
+	}
+	half, none := 0.5, 0.0
+
+	result, err := NewRunner(m).Run(&Scenario{Name: "within", Steps: steps, SLO: map[string]SLO{
+		"stripe": {MaxErrorRate: &half, P95Latency: "50ms", MinRequests: 3},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := result.Steps[len(result.Steps)-1]
+	if !result.Passed || last.Name != "slo: stripe" || !last.Passed {
+		t.Errorf("expected the SLO to pass, got %+v", last)
+	}
+
+	result, err = NewRunner(m).Run(&Scenario{Name: "violated", Steps: steps, SLO: map[string]SLO{
+		"stripe": {MaxErrorRate: &none, P99Latency: "10ms", MinRequests: 4},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last = result.Steps[len(result.Steps)-1]
+	if result.Passed || last.Passed {
+		t.Fatal("expected the SLO to fail")
+	}
+	for _, want := range []string{"3 requests, expected at least 4", "error rate 0.333 (1 of 3 answered 5xx)", "p99 latency 20ms, expected at most 10ms"} {
+		if !strings.Contains(last.Error, want) {
+			t.Errorf("expected %q in %q", want, last.Error)
+		}
+	}
+}
+
+func TestValidateSLO(t *testing.T) {
+	over := 1.5
+	for _, tc := range []struct {
+		slo  SLO
+		want string
+	}{
+		{SLO{MaxErrorRate: &over}, "max_error_rate must be between 0 and 1"},
+		{SLO{P95Latency: "fast"}, "p95_latency"},
+		{SLO{MaxRPS: -1}, "max_rps must not be negative"},
+	} {
+		if err := validateSLO(tc.slo); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("validateSLO(%+v) = %v, want an error containing %q", tc.slo, err, tc.want)
+		}
+	}
+	if err := validateSLO(SLO{P99Latency: "250ms", MaxRPS: 100}); err != nil {
+		t.Errorf("unexpected error for a valid SLO: %v", err)
+	}
+}
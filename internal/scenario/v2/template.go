@@ -10,7 +10,8 @@ import (
 )
 
 // ExpandTemplates replaces template placeholders in a string:
-//   - {{twins.<name>.port}} and {{twins.<name>.admin_port}} from the manifest
+//   - {{twins.<name>.url}}, {{twins.<name>.admin_url}}, {{twins.<name>.port}},
+//     and {{twins.<name>.admin_port}} from the manifest
 //   - {{env.VARIABLE}} from environment variables
 //   - {{variable_name}} from captured variables
 func ExpandTemplates(s string, m *manifest.Manifest, vars map[string]string) (string, error) {
@@ -84,11 +85,22 @@ func resolveTwinExpr(expr string, m *manifest.Manifest) (string, error) {
 	}
 
 	switch field {
-	case "port":
-		return strconv.Itoa(twin.Port), nil
-	case "admin_port":
+	case "url":
+		return twin.BaseURL(), nil
+	case "admin_url":
+		return twin.AdminURL(), nil
+	case "port", "admin_port":
+		if twin.Remote() {
+			return "", fmt.Errorf("template %q: twin %q is remote; use twins.%s.url instead", expr, twinName, twinName)
+		}
+		if twin.Socket != "" {
+			return "", fmt.Errorf("template %q: twin %q listens on a unix socket; use twins.%s.url instead", expr, twinName, twinName)
+		}
+		if field == "port" {
+			return strconv.Itoa(twin.Port), nil
+		}
 		return strconv.Itoa(twin.AdminPort), nil
 	default:
-		return "", fmt.Errorf("template %q: unknown field %q (expected port or admin_port)", expr, field)
+		return "", fmt.Errorf("template %q: unknown field %q (expected url, admin_url, port, or admin_port)", expr, field)
 	}
 }
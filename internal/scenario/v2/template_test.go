@@ -18,6 +18,9 @@ func testManifest() *manifest.Manifest {
 				Port:      4113,
 				AdminPort: 4114,
 			},
+			"twilio": {
+				URL: "https://twilio.twins.example.com",
+			},
 		},
 	}
 }
@@ -46,6 +49,26 @@ func TestExpandTemplates_TwinPort(t *testing.T) {
 			input: "{{twins.stripe.port}} and {{twins.github.port}}",
 			want:  "4111 and 4113",
 		},
+		{
+			name:  "twin url",
+			input: "{{twins.stripe.url}}/v1/customers",
+			want:  "http://localhost:4111/v1/customers",
+		},
+		{
+			name:  "twin admin_url",
+			input: "{{twins.stripe.admin_url}}/admin/health",
+			want:  "http://localhost:4112/admin/health",
+		},
+		{
+			name:  "remote twin url",
+			input: "{{twins.twilio.url}}/2010-04-01/Accounts",
+			want:  "https://twilio.twins.example.com/2010-04-01/Accounts",
+		},
+		{
+			name:    "remote twin port",
+			input:   "http://localhost:{{twins.twilio.port}}",
+			wantErr: true,
+		},
 		{
 			name:    "unknown twin",
 			input:   "{{twins.unknown.port}}",
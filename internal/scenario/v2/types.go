@@ -6,9 +6,51 @@ package v2
 type Scenario struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
-	Setup     *Setup            `json:"setup,omitempty"`
-	Variables map[string]string `json:"variables,omitempty"`
+	Setup       *Setup            `json:"setup,omitempty"`
+	Variables   map[string]string `json:"variables,omitempty"`
 	Steps       []Step            `json:"steps"`
+
+	// MaxDuration bounds the whole scenario (Go duration string). Steps
+	// that have not started when it elapses are skipped.
+	MaxDuration string `json:"max_duration,omitempty"`
+
+	// SLO sets non-functional expectations on the requests the scenario
+	// makes to each twin, keyed by twin name.
+	SLO map[string]SLO `json:"slo,omitempty"`
+}
+
+// SLO bounds a scenario's requests to a twin, as the twin's request log
+// records them from the first step until the last one completes: the share
+// answered 5xx, their latency as the twin measured it, and the rate they
+// were sent at. Unset fields are not checked.
+type SLO struct {
+	MaxErrorRate *float64 `json:"max_error_rate,omitempty"` // 0 to 1; 0 allows no 5xx
+	P95Latency   string   `json:"p95_latency,omitempty"`    // Go duration string, e.g. "200ms"
+	P99Latency   string   `json:"p99_latency,omitempty"`
+	MaxRPS       float64  `json:"max_rps,omitempty"`      // requests per second
+	MinRequests  int      `json:"min_requests,omitempty"` // so an SLO cannot pass on no traffic
+}
+
+// Pack is a bundle of scenarios shipped alongside a twin release so that
+// consumers can run canonical integration tests without copying files.
+type Pack struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Scenarios   []Scenario `json:"scenarios"`
+
+	// Incident makes the pack an incident pack, run with wt chaos replay:
+	// its scenarios only pass against twins the incident has degraded.
+	Incident *Incident `json:"incident,omitempty"`
+}
+
+// Incident is a provider outage an incident pack replays on the twins:
+// chaos profiles, and admin steps (typically scheduled inject_fault steps)
+// applied before the pack's scenarios run.
+type Incident struct {
+	Summary   string            `json:"summary"`             // what went wrong, from the client's side
+	Reference string            `json:"reference,omitempty"` // the provider's postmortem or status page
+	Chaos     map[string]string `json:"chaos,omitempty"`     // twin name → chaos profile
+	Steps     []Step            `json:"steps,omitempty"`     // admin steps only
 }
 
 // Setup defines pre-test actions: resetting twins and seeding data.
@@ -17,12 +59,90 @@ type Setup struct {
 	SeedFiles map[string]string `json:"seed_files,omitempty"`
 }
 
-// Step is a single request/assert pair within a scenario.
+// Step is a single request/assert pair within a scenario. Instead of a
+// request, a step may perform exactly one admin action against a twin, or
+// run an external command.
 type Step struct {
 	Name    string            `json:"name"`
-	Request Request           `json:"request"`
+	Request Request           `json:"request,omitzero"` // unset on admin steps
 	Capture map[string]string `json:"capture,omitempty"`
 	Assert  *Assert           `json:"assert,omitempty"`
+	Timeout string            `json:"timeout,omitempty"` // Go duration string; overrides the runner default
+	Retry   *Retry            `json:"retry,omitempty"`
+
+	InjectFault *InjectFault `json:"inject_fault,omitempty"`
+	RemoveFault *RemoveFault `json:"remove_fault,omitempty"`
+	AdvanceTime *AdvanceTime `json:"advance_time,omitempty"`
+	SetConfig   *SetConfig   `json:"set_config,omitempty"`
+	EnableQuirk *EnableQuirk `json:"enable_quirk,omitempty"`
+
+	Exec *Exec `json:"exec,omitempty"`
+}
+
+// Retry re-runs a failing step, for steps that depend on asynchronous
+// behavior such as webhook delivery.
+type Retry struct {
+	Attempts int    `json:"attempts"`          // total attempts, including the first
+	Backoff  string `json:"backoff,omitempty"` // wait before the first retry, doubled after each; default 500ms
+}
+
+// InjectFault makes a twin fail requests to an endpoint. With After or
+// Duration the fault is scheduled instead of starting immediately, measured
+// on the wall clock or, with Clock "simulated", the twin's simulated clock.
+// An Endpoint like webhook:charge.succeeded targets the twin's webhook
+// deliveries of that event type instead, dropping them or, with only
+// DelayMS, delaying them.
+type InjectFault struct {
+	Twin       string  `json:"twin"`
+	Endpoint   string  `json:"endpoint"`
+	StatusCode int     `json:"status_code,omitempty"`
+	Preset     string  `json:"preset,omitempty"` // built-in fault, in place of status_code
+	Body       string  `json:"body,omitempty"`
+	DelayMS    int     `json:"delay_ms,omitempty"`
+	Rate       float64 `json:"rate,omitempty"`        // 0 means always
+	RetryAfter int     `json:"retry_after,omitempty"` // seconds a 429 fault's Retry-After asks for
+	After      string  `json:"after,omitempty"`       // e.g. "10m"
+	Duration   string  `json:"duration,omitempty"`    // e.g. "5m"
+	Clock      string  `json:"clock,omitempty"`       // "wall" (default) or "simulated"
+}
+
+// RemoveFault clears a previously injected fault.
+type RemoveFault struct {
+	Twin     string `json:"twin"`
+	Endpoint string `json:"endpoint"`
+}
+
+// AdvanceTime moves a twin's simulated clock forward.
+type AdvanceTime struct {
+	Twin     string `json:"twin"`
+	Duration string `json:"duration"` // Go duration string, e.g. "24h"
+}
+
+// SetConfig updates a twin's runtime configuration.
+type SetConfig struct {
+	Twin   string         `json:"twin"`
+	Values map[string]any `json:"values"`
+}
+
+// EnableQuirk turns on a behavioral quirk.
+type EnableQuirk struct {
+	Twin  string `json:"twin"`
+	Quirk string `json:"quirk"`
+}
+
+// Exec runs an external command, so a scenario can drive a client written
+// with the provider's SDK in any language between admin steps. The command
+// runs with WT_<TWIN>_URL and WT_<TWIN>_ADMIN_URL set for every twin in the
+// manifest (WT_<TWIN>_SOCKET too for twins on a unix socket), the twin name
+// upper-cased with other characters as underscores. Its stdout plays the
+// part of a response body: capture reads JSONPaths from it.
+type Exec struct {
+	Command        []string          `json:"command"`                   // program and arguments, templates expanded
+	Env            map[string]string `json:"env,omitempty"`             // extra environment, templates expanded
+	Dir            string            `json:"dir,omitempty"`             // working directory; default the current one
+	ExitCode       int               `json:"exit_code,omitempty"`       // expected exit status
+	StdoutContains string            `json:"stdout_contains,omitempty"` // templates expanded
+	Stdout         map[string]any    `json:"stdout,omitempty"`          // JSONPath assertions on stdout, as in Assert.Body
 }
 
 // Request defines the HTTP request to make during a step.
@@ -33,10 +153,34 @@ type Request struct {
 	Body    any               `json:"body,omitempty"`
 }
 
-// Assert defines the expected results of a step.
+// Assert defines the expected results of a step. Body maps JSONPaths
+// ($.data[0].id, or $.data[*].id for every element) to an expected value or
+// an object of operators: exists, eq, ne, gt, gte, lt, lte, contains
+// (substring, or array element), matches (regex), and length.
 type Assert struct {
 	Status       int               `json:"status,omitempty"`
 	BodyContains string            `json:"body_contains,omitempty"`
 	Headers      map[string]string `json:"headers,omitempty"`
 	Body         map[string]any    `json:"body,omitempty"`
+	RateLimit    *RateLimitAssert  `json:"rate_limit,omitempty"`
+}
+
+// RateLimitAssert checks a response's rate-limit headers, read from
+// X-RateLimit-Limit and X-RateLimit-Remaining unless the step names others.
+// Decreasing compares the remaining count with the one on the previous
+// response from the same host in the scenario.
+type RateLimitAssert struct {
+	Present         bool              `json:"present,omitempty"`
+	Decreasing      bool              `json:"decreasing,omitempty"`
+	RetryAfter      *RetryAfterAssert `json:"retry_after,omitempty"`
+	LimitHeader     string            `json:"limit_header,omitempty"`
+	RemainingHeader string            `json:"remaining_header,omitempty"`
+}
+
+// RetryAfterAssert expects a 429 whose Retry-After header, in seconds or
+// as an HTTP date, asks for a wait between Min and Max (Go duration
+// strings, both optional). A remaining count on the response must be 0.
+type RetryAfterAssert struct {
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
 }
@@ -0,0 +1,125 @@
+// Package versiondiff compares how two versions of a twin answer the same
+// requests, for `wt diff-versions`: the requests come from a scenario or a
+// saved request log, and every difference in status or JSON body is reported
+// with its path.
+package versiondiff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// maxResponseBody caps how much of a response is read for comparison.
+const maxResponseBody = 1 << 20
+
+// maxDifferences caps the differences reported for one request.
+const maxDifferences = 50
+
+// DefaultIgnore are fields skipped at any depth because their values depend
+// on when a request ran rather than on the twin version: two twins started
+// a few seconds apart stamp the same objects with different times.
+var DefaultIgnore = []string{
+	"created", "created_at", "updated", "updated_at", "timestamp",
+	"expires_at", "expire_at", "last_active_at",
+}
+
+// ErrBodyNotCaptured is returned by Replay for a logged request whose body
+// the twin did not record, which cannot be replayed faithfully.
+var ErrBodyNotCaptured = errors.New("logged without its body; record traffic with --capture-bodies")
+
+// Response is what one twin version answered.
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Compare reports how after, the new version's response, differs from
+// before, the old version's. Status codes are compared, and JSON bodies field
+// by field, skipping the fields named in ignore at any depth; other bodies
+// are compared byte for byte. It returns nil when the responses match.
+func Compare(before, after Response, ignore []string) []string {
+	var out []string
+	if before.StatusCode != after.StatusCode {
+		out = append(out, fmt.Sprintf("status: %d != %d", before.StatusCode, after.StatusCode))
+	}
+	return append(out, twincore.DiffBodies(before.Body, after.Body, twincore.DiffOptions{
+		Ignore: ignore,
+		Max:    maxDifferences,
+		OnlyA:  "only in old",
+		OnlyB:  "only in new",
+	})...)
+}
+
+// LoadRequests reads a request log saved with
+// `wt inspect <twin> requests --json`. Admin requests are dropped, since
+// they drive the twin rather than exercise its API.
+func LoadRequests(path string) ([]adminclient.RequestLogEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []adminclient.RequestLogEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing %s: expected the output of wt inspect <twin> requests --json: %w", path, err)
+	}
+	var out []adminclient.RequestLogEntry
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Path, "/admin/") {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// hopHeaders are not copied onto replayed requests.
+var hopHeaders = []string{"Connection", "Content-Length", "Keep-Alive", "Te", "Trailer", "Transfer-Encoding", "Upgrade", "Accept-Encoding"}
+
+// Replay sends a logged request to the twin serving baseURL and returns its
+// response.
+func Replay(c *http.Client, baseURL string, e adminclient.RequestLogEntry) (Response, error) {
+	if !e.BodyCaptured && hasBody(e.Method) {
+		return Response{}, ErrBodyNotCaptured
+	}
+	url := strings.TrimRight(baseURL, "/") + e.Path
+	if e.Query != "" {
+		url += "?" + e.Query
+	}
+	req, err := http.NewRequest(e.Method, url, strings.NewReader(e.Body))
+	if err != nil {
+		return Response{}, err
+	}
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+	for _, h := range hopHeaders {
+		req.Header.Del(h)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{StatusCode: resp.StatusCode, Body: body}, nil
+}
+
+// hasBody reports whether requests with method normally carry a body.
+func hasBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package versiondiff
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+)
+
+func TestCompareMatching(t *testing.T) {
+	a := Response{StatusCode: 200, Body: []byte(`{"id":"ch_000001","amount":100,"created":1700000000}`)}
+	b := Response{StatusCode: 200, Body: []byte(`{"created":1700000042,"amount":100,"id":"ch_000001"}`)}
+	if diffs := Compare(a, b, DefaultIgnore); diffs != nil {
+		t.Errorf("expected no differences, got %v", diffs)
+	}
+}
+
+func TestCompareReportsPaths(t *testing.T) {
+	a := Response{StatusCode: 200, Body: []byte(`{"amount":100,"refunds":{"data":[]},"data":[{"id":"a"},{"id":"b"}]}`)}
+	b := Response{StatusCode: 402, Body: []byte(`{"amount":200,"status":"new","data":[{"id":"c"}]}`)}
+	want := []string{
+		"status: 200 != 402",
+		"$.amount: 100 != 200",
+		"$.data: length 2 != 1",
+		`$.data[0].id: "a" != "c"`,
+		"$.refunds: only in old",
+		"$.status: only in new",
+	}
+	if diffs := Compare(a, b, nil); !reflect.DeepEqual(diffs, want) {
+		t.Errorf("unexpected differences:\n got %q\nwant %q", diffs, want)
+	}
+}
+
+func TestCompareNonJSON(t *testing.T) {
+	if diffs := Compare(Response{StatusCode: 200, Body: []byte("ok")}, Response{StatusCode: 200, Body: []byte("ok")}, nil); diffs != nil {
+		t.Errorf("expected identical text bodies to match, got %v", diffs)
+	}
+	diffs := Compare(Response{StatusCode: 200, Body: []byte("ok")}, Response{StatusCode: 200, Body: []byte("fine")}, nil)
+	if len(diffs) != 1 || diffs[0] != "body: 2 bytes != 4 bytes" {
+		t.Errorf("unexpected differences: %v", diffs)
+	}
+}
+
+func TestLoadRequestsDropsAdmin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.json")
+	data := `[
+		{"id":"1","method":"POST","path":"/v1/customers","body":"email=a@example.com","body_captured":true,"status_code":200},
+		{"id":"2","method":"POST","path":"/admin/reset","status_code":200},
+		{"id":"3","method":"GET","path":"/v1/customers","query":"limit=1","status_code":200}
+	]`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := LoadRequests(path)
+	if err != nil {
+		t.Fatalf("LoadRequests: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "1" || entries[1].ID != "3" {
+		t.Errorf("expected the two API requests, got %+v", entries)
+	}
+
+	os.WriteFile(path, []byte(`{"id":"1"}`), 0o644)
+	if _, err := LoadRequests(path); err == nil {
+		t.Error("expected an error for a file that is not a request log")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"cus_000001"}`))
+	}))
+	defer srv.Close()
+
+	resp, err := Replay(srv.Client(), srv.URL, adminclient.RequestLogEntry{
+		Method:       "POST",
+		Path:         "/v1/customers",
+		Query:        "expand=sources",
+		Headers:      map[string]string{"Authorization": "Bearer sk_test_x", "Content-Length": "999"},
+		Body:         "email=a@example.com",
+		BodyCaptured: true,
+	})
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated || string(resp.Body) != `{"id":"cus_000001"}` {
+		t.Errorf("unexpected response: %d %s", resp.StatusCode, resp.Body)
+	}
+	if got.URL.Path != "/v1/customers" || got.URL.RawQuery != "expand=sources" || gotBody != "email=a@example.com" {
+		t.Errorf("unexpected replayed request: %s?%s %q", got.URL.Path, got.URL.RawQuery, gotBody)
+	}
+	if got.Header.Get("Authorization") != "Bearer sk_test_x" {
+		t.Errorf("expected logged headers to be sent, got %v", got.Header)
+	}
+
+	_, err = Replay(srv.Client(), srv.URL, adminclient.RequestLogEntry{Method: "POST", Path: "/v1/customers"})
+	if !errors.Is(err, ErrBodyNotCaptured) {
+		t.Errorf("expected ErrBodyNotCaptured, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Package clerktwin wires twin-clerk's store, JWT signing keys, API, and admin plane
+// onto a twincore.Twin, for the twin-clerk binary and for hosts such as
+// twin-multi that run several twins in one process.
+package clerktwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-clerk/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-clerk/internal/store"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Description is what twin-clerk reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-clerk",
+	SDKTarget:    twincore.SDKTarget{Package: "github.com/clerk/clerk-sdk-go", Version: "v2"},
+	DefaultPort:  4115,
+	Capabilities: []string{"clock", "quirks"},
+}
+
+// Build mounts twin-clerk on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+
+	// JWT manager with RSA keypair for signing tokens
+	jwtMgr, err := api.NewJWTManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize JWT manager: %w", err)
+	}
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, twin.Middleware(), jwtMgr)
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane (shared with all twins)
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	}
+
+	twin.Logger.Info("twin-clerk ready",
+		"addr", cfg.Addr(),
+		"jwks_endpoint", "/.well-known/jwks.json",
+	)
+	return nil
+}
@@ -12,55 +12,19 @@ package main
 
 import (
 	"log"
-	"os"
 
-	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twin-clerk/clerktwin"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
-	"github.com/wondertwin-ai/wondertwin/twin-clerk/internal/api"
-	"github.com/wondertwin-ai/wondertwin/twin-clerk/internal/store"
 )
 
 func main() {
-	cfg := twincore.ParseFlags("twin-clerk")
-	if cfg.Port == 0 {
-		cfg.Port = 4115
-	}
+	cfg := twincore.ParseFlagsWithDescription(clerktwin.Description)
 
 	twin := twincore.New(cfg)
-	memStore := store.New()
-
-	// JWT manager with RSA keypair for signing tokens
-	jwtMgr, err := api.NewJWTManager()
-	if err != nil {
-		log.Fatalf("failed to initialize JWT manager: %v", err)
+	if err := clerktwin.Build(twin); err != nil {
+		log.Fatal(err)
 	}
 
-	// API handlers
-	apiHandler := api.NewHandler(memStore, twin.Middleware(), jwtMgr)
-	apiHandler.Routes(twin.Router)
-
-	// Admin control plane (shared with all twins)
-	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
-	adminHandler.SetConfigProvider(twin)
-	adminHandler.Routes(twin.Router)
-
-	// Load seed data if provided
-	if cfg.SeedFile != "" {
-		data, err := os.ReadFile(cfg.SeedFile)
-		if err != nil {
-			log.Fatalf("failed to read seed file: %v", err)
-		}
-		if err := memStore.LoadState(data); err != nil {
-			log.Fatalf("failed to load seed data: %v", err)
-		}
-		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
-	}
-
-	twin.Logger.Info("twin-clerk ready",
-		"port", cfg.Port,
-		"jwks_endpoint", "/.well-known/jwks.json",
-	)
-
 	if err := twin.Serve(); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
@@ -11,6 +11,7 @@
 //	POST /v1/client/sign_ins                      → start sign-in
 //	POST /v1/client/sign_ins/{id}/attempt_first_factor → password attempt
 //	POST /v1/client/sessions/{id}/tokens          → get session JWT
+//	POST /v1/client/sessions/{id}/tokens/{template} → get JWT template token
 //	POST /v1/client/sessions/{id}/touch           → refresh session
 //	DELETE /v1/client/sessions/{id}               → end session
 //	GET  /v1/client/handshake                     → cookie refresh redirect
@@ -20,6 +21,7 @@ import (
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -620,10 +622,13 @@ func (h *Handler) completeSignIn(w http.ResponseWriter, client *store.Client, us
 
 // --- Session Token ---
 
-// GetSessionToken handles POST /v1/client/sessions/{id}/tokens.
-// Returns a fresh JWT for the session.
+// GetSessionToken handles POST /v1/client/sessions/{id}/tokens and
+// POST /v1/client/sessions/{id}/tokens/{template}.
+// Returns a fresh JWT for the session: the default session token, or one
+// shaped by the named JWT template.
 func (h *Handler) GetSessionToken(w http.ResponseWriter, r *http.Request) {
 	sessID := chi.URLParam(r, "id")
+	templateName := chi.URLParam(r, "template")
 
 	session, ok := h.store.Sessions.Get(sessID)
 	if !ok {
@@ -645,23 +650,28 @@ func (h *Handler) GetSessionToken(w http.ResponseWriter, r *http.Request) {
 	// Body may be empty, ignore decode errors
 	json.NewDecoder(r.Body).Decode(&req)
 
-	extraClaims := map[string]any{}
-	if req.OrganizationID != "" {
-		extraClaims["org_id"] = req.OrganizationID
-	}
-
-	var claimsPtr map[string]any
-	if len(extraClaims) > 0 {
-		claimsPtr = extraClaims
+	token, err := h.mintToken(session.UserID, sessID, req.OrganizationID, templateName)
+	if errors.Is(err, errTemplateNotFound) {
+		clerkError(w, http.StatusNotFound, "resource_not_found",
+			"JWT template not found.", fmt.Sprintf("No JWT template exists with name: %s", templateName))
+		return
 	}
-
-	token, err := h.jwtMgr.GenerateToken(session.UserID, sessID, claimsPtr)
 	if err != nil {
 		clerkError(w, http.StatusInternalServerError, "internal_error",
 			"Failed to generate token.", err.Error())
 		return
 	}
 
+	// Template tokens are handed to other services; only the default token
+	// becomes the session's active token and cookie.
+	if templateName != "" {
+		twincore.JSON(w, http.StatusOK, store.TokenResponse{
+			Object: "token",
+			JWT:    token,
+		})
+		return
+	}
+
 	// Update session
 	now := store.Now()
 	session.LastActiveAt = now
@@ -5,6 +5,7 @@ import (
 	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twin-clerk/internal/store"
 )
 
 // JWTManager manages RSA key pairs and JWT generation for the Clerk twin.
@@ -77,6 +79,53 @@ func (m *JWTManager) GenerateToken(userID, sessionID string, extraClaims map[str
 	return signed, nil
 }
 
+// GenerateTemplateToken creates a signed JWT for a JWT template. As on Clerk,
+// it carries only the registered claims plus the template's rendered claims,
+// and lives for the template's lifetime. A template with a custom signing key
+// is signed with that key instead of the twin's RSA key.
+func (m *JWTManager) GenerateTemplateToken(userID string, tmpl store.JWTTemplate, templateClaims map[string]any) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jti := make([]byte, 10)
+	if _, err := rand.Read(jti); err != nil {
+		return "", fmt.Errorf("failed to generate jti: %w", err)
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{}
+	for k, v := range templateClaims {
+		claims[k] = v
+	}
+	claims["iss"] = "https://clerk.twin.wondertwin.dev"
+	claims["sub"] = userID
+	claims["azp"] = "wondertwin"
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix() - int64(tmpl.AllowedClockSkew)
+	claims["exp"] = now.Add(time.Duration(tmpl.Lifetime) * time.Second).Unix()
+	claims["jti"] = hex.EncodeToString(jti)
+
+	if !tmpl.CustomSigningKey {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = m.keyID
+		signed, err := token.SignedString(m.privateKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign JWT: %w", err)
+		}
+		return signed, nil
+	}
+
+	key, err := templateSigningKey(tmpl.SigningAlgorithm, tmpl.SigningKey)
+	if err != nil {
+		return "", err
+	}
+	signed, err := jwt.NewWithClaims(jwt.GetSigningMethod(tmpl.SigningAlgorithm), claims).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return signed, nil
+}
+
 // JWKS represents a JSON Web Key Set.
 type JWKS struct {
 	Keys []JWK `json:"keys"`
@@ -120,14 +169,19 @@ func (h *Handler) GetJWKS(w http.ResponseWriter, r *http.Request) {
 
 // generateJWTRequest is the JSON body for POST /admin/jwt/generate.
 type generateJWTRequest struct {
-	UserID      string         `json:"user_id"`
-	SessionID   string         `json:"session_id,omitempty"`
-	ExpiresIn   string         `json:"expires_in,omitempty"` // Go duration string, e.g., "1h"
-	ExtraClaims map[string]any `json:"extra_claims,omitempty"`
+	UserID         string         `json:"user_id"`
+	SessionID      string         `json:"session_id,omitempty"`
+	OrganizationID string         `json:"organization_id,omitempty"`
+	Template       string         `json:"template,omitempty"`   // JWT template name
+	ExpiresIn      string         `json:"expires_in,omitempty"` // Go duration string, e.g., "1h"
+	ExtraClaims    map[string]any `json:"extra_claims,omitempty"`
 }
 
 // GenerateJWT handles POST /admin/jwt/generate.
 // This is a test-only endpoint for generating JWTs for integration tests.
+// With a template, the token is shaped by that JWT template, as
+// session.getToken({ template }) would return; otherwise it is a default
+// session token carrying the instance's custom session claims.
 func (h *Handler) GenerateJWT(w http.ResponseWriter, r *http.Request) {
 	var req generateJWTRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -148,7 +202,7 @@ func (h *Handler) GenerateJWT(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Handle custom expiration
-	extraClaims := req.ExtraClaims
+	var expiresIn time.Duration
 	if req.ExpiresIn != "" {
 		d, err := time.ParseDuration(req.ExpiresIn)
 		if err != nil {
@@ -156,13 +210,37 @@ func (h *Handler) GenerateJWT(w http.ResponseWriter, r *http.Request) {
 				"Invalid expires_in duration.", err.Error())
 			return
 		}
-		if extraClaims == nil {
-			extraClaims = make(map[string]any)
-		}
-		extraClaims["exp"] = time.Now().Add(d).Unix()
+		expiresIn = d
 	}
 
-	token, err := h.jwtMgr.GenerateToken(req.UserID, sessionID, extraClaims)
+	var token string
+	var err error
+	if req.Template != "" {
+		tmpl, ok := h.templateByName(req.Template)
+		if !ok {
+			clerkError(w, http.StatusNotFound, "resource_not_found",
+				"JWT template not found.",
+				fmt.Sprintf("No JWT template exists with name: %s", req.Template))
+			return
+		}
+		if expiresIn != 0 {
+			tmpl.Lifetime = int(expiresIn / time.Second)
+		}
+		claims := h.templateClaims(tmpl, req.UserID, req.OrganizationID)
+		for k, v := range req.ExtraClaims {
+			claims[k] = v
+		}
+		token, err = h.jwtMgr.GenerateTemplateToken(req.UserID, tmpl, claims)
+	} else {
+		claims := h.sessionTokenClaims(req.UserID, req.OrganizationID)
+		for k, v := range req.ExtraClaims {
+			claims[k] = v
+		}
+		if expiresIn != 0 {
+			claims["exp"] = time.Now().Add(expiresIn).Unix()
+		}
+		token, err = h.jwtMgr.GenerateToken(req.UserID, sessionID, claims)
+	}
 	if err != nil {
 		clerkError(w, http.StatusInternalServerError, "internal_error",
 			"Failed to generate JWT.", err.Error())
@@ -0,0 +1,485 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twin-clerk/internal/store"
+)
+
+// Defaults and bounds Clerk applies to JWT templates, in seconds.
+const (
+	defaultTemplateLifetime = 60
+	minTemplateLifetime     = 30
+	maxTemplateLifetime     = 315360000
+	defaultAllowedClockSkew = 5
+	maxTemplateAllowedSkew  = 300
+)
+
+// reservedClaims are set on every token by Clerk and cannot be overridden by
+// a JWT template or the instance's custom session claims.
+var reservedClaims = []string{"azp", "exp", "iat", "iss", "jti", "nbf", "sid", "sub"}
+
+// errTemplateNotFound is returned by mintToken for an unknown template name.
+var errTemplateNotFound = errors.New("jwt template not found")
+
+// jwtTemplateRequest is the JSON body for POST /v1/jwt_templates and
+// PATCH /v1/jwt_templates/{id}.
+type jwtTemplateRequest struct {
+	Name             *string        `json:"name,omitempty"`
+	Claims           map[string]any `json:"claims,omitempty"`
+	Lifetime         *int           `json:"lifetime,omitempty"`
+	AllowedClockSkew *int           `json:"allowed_clock_skew,omitempty"`
+	CustomSigningKey *bool          `json:"custom_signing_key,omitempty"`
+	SigningAlgorithm *string        `json:"signing_algorithm,omitempty"`
+	SigningKey       *string        `json:"signing_key,omitempty"`
+}
+
+// CreateJWTTemplate handles POST /v1/jwt_templates.
+func (h *Handler) CreateJWTTemplate(w http.ResponseWriter, r *http.Request) {
+	var req jwtTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		clerkError(w, http.StatusBadRequest, "form_param_invalid",
+			"Invalid request body.", err.Error())
+		return
+	}
+
+	if req.Name == nil {
+		clerkError(w, http.StatusUnprocessableEntity, "form_param_missing",
+			"name is required.", "You must provide a name for the JWT template.")
+		return
+	}
+	if req.Claims == nil {
+		clerkError(w, http.StatusUnprocessableEntity, "form_param_missing",
+			"claims is required.", "You must provide the claims of the JWT template.")
+		return
+	}
+
+	now := store.Now()
+	tmpl := store.JWTTemplate{
+		ID:               h.store.JWTTemplates.NextID(),
+		Object:           "jwt_template",
+		Lifetime:         defaultTemplateLifetime,
+		AllowedClockSkew: defaultAllowedClockSkew,
+		SigningAlgorithm: "RS256",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if !h.applyJWTTemplate(w, &tmpl, req) {
+		return
+	}
+
+	h.store.JWTTemplates.Set(tmpl.ID, tmpl)
+	twincore.JSON(w, http.StatusOK, jwtTemplateResponse(tmpl))
+}
+
+// GetJWTTemplate handles GET /v1/jwt_templates/{id}.
+func (h *Handler) GetJWTTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	tmpl, ok := h.store.JWTTemplates.Get(id)
+	if !ok {
+		clerkError(w, http.StatusNotFound, "resource_not_found",
+			"JWT template not found.",
+			fmt.Sprintf("No JWT template was found with id %s.", id))
+		return
+	}
+
+	twincore.JSON(w, http.StatusOK, jwtTemplateResponse(tmpl))
+}
+
+// ListJWTTemplates handles GET /v1/jwt_templates.
+func (h *Handler) ListJWTTemplates(w http.ResponseWriter, r *http.Request) {
+	templates := h.store.JWTTemplates.List()
+	data := make([]store.JWTTemplate, 0, len(templates))
+	for _, tmpl := range templates {
+		data = append(data, jwtTemplateResponse(tmpl))
+	}
+
+	twincore.JSON(w, http.StatusOK, store.ClerkList[store.JWTTemplate]{
+		Data:       data,
+		TotalCount: len(data),
+	})
+}
+
+// UpdateJWTTemplate handles PATCH /v1/jwt_templates/{id}.
+func (h *Handler) UpdateJWTTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	tmpl, ok := h.store.JWTTemplates.Get(id)
+	if !ok {
+		clerkError(w, http.StatusNotFound, "resource_not_found",
+			"JWT template not found.",
+			fmt.Sprintf("No JWT template was found with id %s.", id))
+		return
+	}
+
+	var req jwtTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		clerkError(w, http.StatusBadRequest, "form_param_invalid",
+			"Invalid request body.", err.Error())
+		return
+	}
+	if !h.applyJWTTemplate(w, &tmpl, req) {
+		return
+	}
+
+	tmpl.UpdatedAt = store.Now()
+	h.store.JWTTemplates.Set(id, tmpl)
+	twincore.JSON(w, http.StatusOK, jwtTemplateResponse(tmpl))
+}
+
+// DeleteJWTTemplate handles DELETE /v1/jwt_templates/{id}.
+func (h *Handler) DeleteJWTTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if !h.store.JWTTemplates.Delete(id) {
+		clerkError(w, http.StatusNotFound, "resource_not_found",
+			"JWT template not found.",
+			fmt.Sprintf("No JWT template was found with id %s.", id))
+		return
+	}
+
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"id":      id,
+		"object":  "jwt_template",
+		"deleted": true,
+	})
+}
+
+// applyJWTTemplate validates req and applies it to tmpl, writing a Clerk
+// error and returning false if it is invalid.
+func (h *Handler) applyJWTTemplate(w http.ResponseWriter, tmpl *store.JWTTemplate, req jwtTemplateRequest) bool {
+	if req.Name != nil {
+		if *req.Name == "" {
+			clerkError(w, http.StatusUnprocessableEntity, "form_param_missing",
+				"name is required.", "You must provide a name for the JWT template.")
+			return false
+		}
+		if _, taken := h.templateByName(*req.Name); taken && *req.Name != tmpl.Name {
+			clerkError(w, http.StatusUnprocessableEntity, "form_identifier_exists",
+				"That name is taken.",
+				fmt.Sprintf("A JWT template named %s already exists.", *req.Name))
+			return false
+		}
+		tmpl.Name = *req.Name
+	}
+	if req.Claims != nil {
+		if claim := reservedClaim(req.Claims); claim != "" {
+			clerkError(w, http.StatusUnprocessableEntity, "form_param_value_invalid",
+				"claims contains a reserved claim.",
+				fmt.Sprintf("The %s claim is set by Clerk and cannot be customized.", claim))
+			return false
+		}
+		tmpl.Claims = req.Claims
+	}
+	if req.Lifetime != nil {
+		if *req.Lifetime < minTemplateLifetime || *req.Lifetime > maxTemplateLifetime {
+			clerkError(w, http.StatusUnprocessableEntity, "form_param_value_invalid",
+				"lifetime is out of range.",
+				fmt.Sprintf("lifetime must be between %d and %d seconds.", minTemplateLifetime, maxTemplateLifetime))
+			return false
+		}
+		tmpl.Lifetime = *req.Lifetime
+	}
+	if req.AllowedClockSkew != nil {
+		if *req.AllowedClockSkew < 0 || *req.AllowedClockSkew > maxTemplateAllowedSkew {
+			clerkError(w, http.StatusUnprocessableEntity, "form_param_value_invalid",
+				"allowed_clock_skew is out of range.",
+				fmt.Sprintf("allowed_clock_skew must be between 0 and %d seconds.", maxTemplateAllowedSkew))
+			return false
+		}
+		tmpl.AllowedClockSkew = *req.AllowedClockSkew
+	}
+
+	if req.CustomSigningKey != nil {
+		tmpl.CustomSigningKey = *req.CustomSigningKey
+	}
+	if req.SigningAlgorithm != nil {
+		tmpl.SigningAlgorithm = *req.SigningAlgorithm
+	}
+	if req.SigningKey != nil {
+		tmpl.SigningKey = *req.SigningKey
+	}
+	if !tmpl.CustomSigningKey {
+		tmpl.SigningAlgorithm = "RS256"
+		tmpl.SigningKey = ""
+		return true
+	}
+	if _, err := templateSigningKey(tmpl.SigningAlgorithm, tmpl.SigningKey); err != nil {
+		clerkError(w, http.StatusUnprocessableEntity, "form_param_value_invalid",
+			"Invalid signing key.", err.Error())
+		return false
+	}
+	return true
+}
+
+// templateByName returns the JWT template with the given name.
+func (h *Handler) templateByName(name string) (store.JWTTemplate, bool) {
+	templates := h.store.JWTTemplates.Filter(func(id string, t store.JWTTemplate) bool {
+		return t.Name == name
+	})
+	if len(templates) == 0 {
+		return store.JWTTemplate{}, false
+	}
+	return templates[0], true
+}
+
+// jwtTemplateResponse hides a template's signing key from API responses.
+func jwtTemplateResponse(tmpl store.JWTTemplate) store.JWTTemplate {
+	tmpl.SigningKey = ""
+	return tmpl
+}
+
+// reservedClaim returns the first reserved claim set in claims, or "".
+func reservedClaim(claims map[string]any) string {
+	for _, c := range reservedClaims {
+		if _, ok := claims[c]; ok {
+			return c
+		}
+	}
+	return ""
+}
+
+// templateSigningKey parses a custom signing key for alg: the secret itself
+// for HMAC algorithms, a PEM-encoded private key for RSA ones.
+func templateSigningKey(alg, key string) (any, error) {
+	if key == "" {
+		return nil, errors.New("signing_key is required when custom_signing_key is true")
+	}
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		return []byte(key), nil
+	case "RS256", "RS384", "RS512":
+		k, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("signing_key is not a PEM-encoded RSA private key: %w", err)
+		}
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing_algorithm %q; use HS256, HS384, HS512, RS256, RS384, or RS512", alg)
+	}
+}
+
+// --- Token minting ---
+
+// mintToken issues a token for a user's session. Without a template name it
+// is the default session token, carrying the instance's custom session
+// claims; otherwise it is shaped by the named JWT template. orgID, if set,
+// is the session's active organization.
+func (h *Handler) mintToken(userID, sessionID, orgID, templateName string) (string, error) {
+	if templateName == "" {
+		return h.jwtMgr.GenerateToken(userID, sessionID, h.sessionTokenClaims(userID, orgID))
+	}
+	tmpl, ok := h.templateByName(templateName)
+	if !ok {
+		return "", errTemplateNotFound
+	}
+	return h.jwtMgr.GenerateTemplateToken(userID, tmpl, h.templateClaims(tmpl, userID, orgID))
+}
+
+// sessionTokenClaims are the claims a default session token adds to the
+// standard ones: the instance's custom session claims and the active
+// organization.
+func (h *Handler) sessionTokenClaims(userID, orgID string) map[string]any {
+	claims := map[string]any{}
+	if sessionClaims := h.store.SessionClaims(); sessionClaims != nil {
+		claims = renderClaims(sessionClaims, h.claimData(userID, orgID)).(map[string]any)
+	}
+	if orgID != "" {
+		claims["org_id"] = orgID
+	}
+	return claims
+}
+
+// templateClaims renders a JWT template's claims for a user.
+func (h *Handler) templateClaims(tmpl store.JWTTemplate, userID, orgID string) map[string]any {
+	return renderClaims(tmpl.Claims, h.claimData(userID, orgID)).(map[string]any)
+}
+
+// claimData is what template shortcodes can refer to: {{user.*}} and, when
+// the session has an active organization, {{org.*}}. Values Clerk would not
+// expose, such as private metadata, are left out.
+func (h *Handler) claimData(userID, orgID string) map[string]any {
+	user := map[string]any{"id": userID}
+	if u, ok := h.store.Users.Get(userID); ok {
+		user = map[string]any{
+			"id":                    u.ID,
+			"external_id":           nilIfEmpty(u.ExternalID),
+			"first_name":            nilIfEmpty(u.FirstName),
+			"last_name":             nilIfEmpty(u.LastName),
+			"full_name":             nilIfEmpty(strings.TrimSpace(u.FirstName + " " + u.LastName)),
+			"username":              nilIfEmpty(u.Username),
+			"image_url":             u.ImageURL,
+			"has_image":             u.ImageURL != "",
+			"public_metadata":       u.PublicMetadata,
+			"unsafe_metadata":       u.UnsafeMetadata,
+			"two_factor_enabled":    u.TwoFactorEnabled,
+			"created_at":            u.CreatedAt,
+			"primary_email_address": nil,
+			"email_verified":        false,
+			"primary_phone_address": nil,
+			"phone_number_verified": false,
+		}
+		for _, e := range u.EmailAddresses {
+			if e.ID == u.PrimaryEmailAddress {
+				user["primary_email_address"] = e.EmailAddress
+				user["email_verified"] = e.Verification != nil && e.Verification.Status == "verified"
+			}
+		}
+		for _, p := range u.PhoneNumbers {
+			if p.ID == u.PrimaryPhoneNumber {
+				user["primary_phone_address"] = p.PhoneNumber
+				user["phone_number_verified"] = p.Verification != nil && p.Verification.Status == "verified"
+			}
+		}
+	}
+
+	data := map[string]any{"user": user}
+	if orgID == "" {
+		return data
+	}
+	org := map[string]any{"id": orgID}
+	if o, ok := h.store.Organizations.Get(orgID); ok {
+		org["name"] = o.Name
+		org["slug"] = o.Slug
+		org["image_url"] = o.ImageURL
+		org["public_metadata"] = o.PublicMetadata
+	}
+	if u, ok := h.store.Users.Get(userID); ok {
+		for _, m := range u.OrganizationMemberships {
+			if m.Organization != nil && m.Organization.ID == orgID {
+				org["role"] = m.Role
+			}
+		}
+	}
+	data["org"] = org
+	return data
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// shortcodePattern matches a {{...}} shortcode in a claim value.
+var shortcodePattern = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// renderClaims fills in the shortcodes of a claims template, walking nested
+// objects and arrays. A string that is exactly one shortcode takes the
+// value's own type, so {{user.public_metadata}} yields an object; shortcodes
+// inside longer strings are interpolated as text, empty when unset.
+func renderClaims(v any, data map[string]any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[k] = renderClaims(val, data)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = renderClaims(val, data)
+		}
+		return out
+	case string:
+		if m := shortcodePattern.FindStringSubmatchIndex(v); m != nil && m[0] == 0 && m[1] == len(v) {
+			return evalShortcode(v[m[2]:m[3]], data)
+		}
+		return shortcodePattern.ReplaceAllStringFunc(v, func(code string) string {
+			val := evalShortcode(shortcodePattern.FindStringSubmatch(code)[1], data)
+			if val == nil {
+				return ""
+			}
+			if s, ok := val.(string); ok {
+				return s
+			}
+			b, _ := json.Marshal(val)
+			return string(b)
+		})
+	default:
+		return v
+	}
+}
+
+// evalShortcode evaluates a shortcode expression: dotted paths such as
+// user.public_metadata.role, optionally followed by || alternatives, each a
+// path or a quoted literal. The first alternative that is set wins.
+func evalShortcode(expr string, data map[string]any) any {
+	for _, alt := range strings.Split(expr, "||") {
+		alt = strings.TrimSpace(alt)
+		if len(alt) >= 2 && (alt[0] == '\'' || alt[0] == '"') && alt[len(alt)-1] == alt[0] {
+			return alt[1 : len(alt)-1]
+		}
+		if n, err := strconv.ParseFloat(alt, 64); err == nil {
+			return n
+		}
+		if alt == "true" || alt == "false" {
+			return alt == "true"
+		}
+		if val := lookupPath(data, alt); val != nil && val != "" {
+			return val
+		}
+	}
+	return nil
+}
+
+// lookupPath resolves a dotted path through nested objects.
+func lookupPath(data map[string]any, path string) any {
+	var cur any = data
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+// --- Instance session claims (admin) ---
+
+// GetSessionClaims handles GET /admin/session_claims.
+func (h *Handler) GetSessionClaims(w http.ResponseWriter, r *http.Request) {
+	claims := h.store.SessionClaims()
+	if claims == nil {
+		claims = map[string]any{}
+	}
+	twincore.JSON(w, http.StatusOK, map[string]any{"claims": claims})
+}
+
+// SetSessionClaims handles PUT /admin/session_claims.
+// It sets the instance's custom session claims, which Clerk configures in
+// the dashboard under "Customize session token". They are added to every
+// default session token and use the same shortcodes as JWT templates. An
+// empty object turns them off.
+func (h *Handler) SetSessionClaims(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Claims map[string]any `json:"claims"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		clerkError(w, http.StatusBadRequest, "form_param_invalid",
+			"Invalid request body.", err.Error())
+		return
+	}
+	if claim := reservedClaim(req.Claims); claim != "" {
+		clerkError(w, http.StatusUnprocessableEntity, "form_param_value_invalid",
+			"claims contains a reserved claim.",
+			fmt.Sprintf("The %s claim is set by Clerk and cannot be customized.", claim))
+		return
+	}
+
+	h.store.SetSessionClaims(req.Claims)
+	h.GetSessionClaims(w, r)
+}
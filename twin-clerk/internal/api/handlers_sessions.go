@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -152,7 +153,7 @@ func (h *Handler) VerifySession(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate a fresh JWT for this session
-	token, err := h.jwtMgr.GenerateToken(session.UserID, session.ID, nil)
+	token, err := h.mintToken(session.UserID, session.ID, "", "")
 	if err != nil {
 		clerkError(w, http.StatusInternalServerError, "internal_error",
 			"Failed to generate session token.", err.Error())
@@ -172,6 +173,56 @@ func (h *Handler) VerifySession(w http.ResponseWriter, r *http.Request) {
 	twincore.JSON(w, http.StatusOK, session)
 }
 
+// createSessionTokenRequest is the JSON body for POST /v1/sessions/{id}/tokens.
+type createSessionTokenRequest struct {
+	OrganizationID string `json:"organization_id,omitempty"` // twin extension: active org for {{org.*}}
+}
+
+// CreateSessionToken handles POST /v1/sessions/{id}/tokens and
+// POST /v1/sessions/{id}/tokens/{template}.
+// Returns a default session token, or one shaped by the named JWT template.
+func (h *Handler) CreateSessionToken(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	templateName := chi.URLParam(r, "template")
+
+	session, ok := h.store.Sessions.Get(id)
+	if !ok {
+		clerkError(w, http.StatusNotFound, "resource_not_found",
+			"Session not found.",
+			fmt.Sprintf("No session was found with id %s.", id))
+		return
+	}
+
+	if session.Status != "active" {
+		clerkError(w, http.StatusUnauthorized, "session_not_active",
+			"Session is not active.",
+			fmt.Sprintf("Session %s has status %s.", id, session.Status))
+		return
+	}
+
+	// Body is optional
+	var req createSessionTokenRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	token, err := h.mintToken(session.UserID, id, req.OrganizationID, templateName)
+	if errors.Is(err, errTemplateNotFound) {
+		clerkError(w, http.StatusNotFound, "resource_not_found",
+			"JWT template not found.",
+			fmt.Sprintf("No JWT template exists with name: %s", templateName))
+		return
+	}
+	if err != nil {
+		clerkError(w, http.StatusInternalServerError, "internal_error",
+			"Failed to generate session token.", err.Error())
+		return
+	}
+
+	twincore.JSON(w, http.StatusOK, store.TokenResponse{
+		Object: "token",
+		JWT:    token,
+	})
+}
+
 // RevokeSession handles POST /v1/sessions/{id}/revoke.
 func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -1,9 +1,13 @@
 package api_test
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
 	"github.com/wondertwin-ai/wondertwin/twinkit/testutil"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
@@ -525,3 +529,190 @@ func TestAdminHealth(t *testing.T) {
 	_, tc := setupClerk(t)
 	tc.Get("/admin/health").AssertStatus(200)
 }
+
+// --- JWT Template Tests ---
+
+// jwtClaims decodes a JWT's claims without verifying it.
+func jwtClaims(t *testing.T, token string) map[string]any {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a JWT, got %q", token)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decoding JWT payload: %v", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("parsing JWT claims: %v", err)
+	}
+	return claims
+}
+
+func TestJWTTemplateToken(t *testing.T) {
+	_, tc := setupClerk(t)
+
+	resp := clerkPost(tc, "/v1/users", map[string]any{
+		"email_address":   []string{"hasura@example.com"},
+		"first_name":      "Ada",
+		"public_metadata": map[string]any{"role": "editor"},
+	})
+	userID := resp.JSONMap()["id"].(string)
+	sessID := tc.Post("/admin/sessions", map[string]any{"user_id": userID}).JSONMap()["id"].(string)
+
+	resp = clerkPost(tc, "/v1/jwt_templates", map[string]any{
+		"name":     "hasura",
+		"lifetime": 120,
+		"claims": map[string]any{
+			"https://hasura.io/jwt/claims": map[string]any{
+				"x-hasura-user-id":       "{{user.id}}",
+				"x-hasura-default-role":  "{{user.public_metadata.role || 'user'}}",
+				"x-hasura-allowed-roles": []any{"user", "{{user.public_metadata.role}}"},
+			},
+			"email":    "{{user.primary_email_address}}",
+			"greeting": "Hello, {{user.first_name}}!",
+			"metadata": "{{user.public_metadata}}",
+		},
+	})
+	resp.AssertStatus(200)
+	tmpl := resp.JSONMap()
+	if tmpl["object"] != "jwt_template" || tmpl["lifetime"].(float64) != 120 || tmpl["signing_algorithm"] != "RS256" {
+		t.Errorf("unexpected template: %v", tmpl)
+	}
+
+	resp = clerkPost(tc, "/v1/sessions/"+sessID+"/tokens/hasura", nil)
+	resp.AssertStatus(200)
+	claims := jwtClaims(t, resp.JSONMap()["jwt"].(string))
+
+	hasura := claims["https://hasura.io/jwt/claims"].(map[string]any)
+	if hasura["x-hasura-user-id"] != userID || hasura["x-hasura-default-role"] != "editor" {
+		t.Errorf("unexpected hasura claims: %v", hasura)
+	}
+	if roles := hasura["x-hasura-allowed-roles"].([]any); len(roles) != 2 || roles[1] != "editor" {
+		t.Errorf("unexpected allowed roles: %v", roles)
+	}
+	if claims["email"] != "hasura@example.com" || claims["greeting"] != "Hello, Ada!" {
+		t.Errorf("unexpected rendered claims: %v", claims)
+	}
+	if claims["metadata"].(map[string]any)["role"] != "editor" {
+		t.Errorf("expected a whole-value shortcode to keep its type, got %v", claims["metadata"])
+	}
+	if claims["sub"] != userID || claims["exp"].(float64)-claims["iat"].(float64) != 120 {
+		t.Errorf("unexpected registered claims: %v", claims)
+	}
+	if _, ok := claims["sid"]; ok {
+		t.Error("expected a template token not to carry sid")
+	}
+
+	// The same template through the frontend API.
+	resp = tc.Post("/v1/client/sessions/"+sessID+"/tokens/hasura", nil)
+	resp.AssertStatus(200)
+	if got := jwtClaims(t, resp.JSONMap()["jwt"].(string))["email"]; got != "hasura@example.com" {
+		t.Errorf("expected the FAPI template token to be rendered, got %v", got)
+	}
+
+	clerkPost(tc, "/v1/sessions/"+sessID+"/tokens/missing", nil).AssertStatus(404)
+}
+
+func TestJWTTemplateCustomSigningKey(t *testing.T) {
+	_, tc := setupClerk(t)
+
+	secret := "super-secret-jwt-token-with-at-least-32-characters-long"
+	resp := clerkPost(tc, "/v1/jwt_templates", map[string]any{
+		"name":               "supabase",
+		"claims":             map[string]any{"aud": "authenticated", "role": "authenticated", "email": "{{user.primary_email_address}}"},
+		"custom_signing_key": true,
+		"signing_algorithm":  "HS256",
+		"signing_key":        secret,
+	})
+	resp.AssertStatus(200)
+	if _, ok := resp.JSONMap()["signing_key"]; ok {
+		t.Error("expected the signing key not to be returned")
+	}
+
+	resp = tc.Post("/admin/jwt/generate", map[string]any{"user_id": "user_123", "template": "supabase"})
+	resp.AssertStatus(200)
+	token := resp.JSONMap()["token"].(string)
+
+	parsed, err := jwt.Parse(token, func(*jwt.Token) (any, error) { return []byte(secret), nil },
+		jwt.WithValidMethods([]string{"HS256"}), jwt.WithAudience("authenticated"))
+	if err != nil {
+		t.Fatalf("expected the token to verify with the template's key: %v", err)
+	}
+	if role := parsed.Claims.(jwt.MapClaims)["role"]; role != "authenticated" {
+		t.Errorf("expected role=authenticated, got %v", role)
+	}
+
+	resp = clerkPost(tc, "/v1/jwt_templates", map[string]any{
+		"name":               "bad",
+		"claims":             map[string]any{},
+		"custom_signing_key": true,
+		"signing_algorithm":  "RS256",
+		"signing_key":        "not a pem",
+	})
+	resp.AssertStatus(422)
+}
+
+func TestJWTTemplateValidation(t *testing.T) {
+	_, tc := setupClerk(t)
+
+	clerkPost(tc, "/v1/jwt_templates", map[string]any{"claims": map[string]any{}}).AssertStatus(422)
+	resp := clerkPost(tc, "/v1/jwt_templates", map[string]any{"name": "t", "claims": map[string]any{"sub": "x"}})
+	resp.AssertStatus(422)
+	resp.AssertBodyContains("reserved")
+	clerkPost(tc, "/v1/jwt_templates", map[string]any{"name": "t", "claims": map[string]any{}, "lifetime": 5}).AssertStatus(422)
+
+	id := clerkPost(tc, "/v1/jwt_templates", map[string]any{"name": "t", "claims": map[string]any{}}).JSONMap()["id"].(string)
+	clerkPost(tc, "/v1/jwt_templates", map[string]any{"name": "t", "claims": map[string]any{}}).AssertStatus(422)
+
+	resp = clerkPatch(tc, "/v1/jwt_templates/"+id, map[string]any{"lifetime": 3600})
+	resp.AssertStatus(200)
+	if got := resp.JSONMap()["lifetime"].(float64); got != 3600 {
+		t.Errorf("expected lifetime=3600, got %v", got)
+	}
+	if got := clerkGet(tc, "/v1/jwt_templates").JSONMap()["total_count"].(float64); got != 1 {
+		t.Errorf("expected 1 template, got %v", got)
+	}
+	clerkDelete(tc, "/v1/jwt_templates/"+id).AssertStatus(200)
+	clerkGet(tc, "/v1/jwt_templates/"+id).AssertStatus(404)
+}
+
+func TestCustomSessionClaims(t *testing.T) {
+	_, tc := setupClerk(t)
+
+	resp := clerkPost(tc, "/v1/users", map[string]any{
+		"email_address":   []string{"claims@example.com"},
+		"public_metadata": map[string]any{"plan": "pro"},
+	})
+	userID := resp.JSONMap()["id"].(string)
+	sessID := tc.Post("/admin/sessions", map[string]any{"user_id": userID}).JSONMap()["id"].(string)
+
+	tc.DoWithHeaders("PUT", "/admin/session_claims", map[string]any{"claims": map[string]any{"iss": "x"}}, nil).AssertStatus(422)
+	resp = tc.DoWithHeaders("PUT", "/admin/session_claims", map[string]any{
+		"claims": map[string]any{"plan": "{{user.public_metadata.plan}}", "email": "{{user.primary_email_address}}"},
+	}, nil)
+	resp.AssertStatus(200)
+
+	resp = tc.Post("/v1/client/sessions/"+sessID+"/tokens", nil)
+	resp.AssertStatus(200)
+	claims := jwtClaims(t, resp.JSONMap()["jwt"].(string))
+	if claims["plan"] != "pro" || claims["email"] != "claims@example.com" || claims["sid"] != sessID {
+		t.Errorf("expected custom session claims in the session token, got %v", claims)
+	}
+
+	// Session claims are part of the admin state.
+	state := tc.Get("/admin/state").JSONMap()
+	if state["session_claims"].(map[string]any)["plan"] != "{{user.public_metadata.plan}}" {
+		t.Errorf("expected session claims in state, got %v", state["session_claims"])
+	}
+	tc.Post("/admin/reset", nil).AssertStatus(200)
+	if got := tc.Get("/admin/session_claims").JSONMap()["claims"].(map[string]any); len(got) != 0 {
+		t.Errorf("expected reset to clear session claims, got %v", got)
+	}
+	state["users"] = map[string]any{}
+	tc.Post("/admin/state", state).AssertStatus(200)
+	if got := tc.Get("/admin/session_claims").JSONMap()["claims"].(map[string]any); got["plan"] == nil {
+		t.Errorf("expected loading state to restore session claims, got %v", got)
+	}
+}
@@ -2,10 +2,12 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
 	"github.com/wondertwin-ai/wondertwin/twin-clerk/internal/store"
 )
@@ -19,9 +21,42 @@ type Handler struct {
 
 // NewHandler creates a new API handler.
 func NewHandler(s *store.MemoryStore, mw *twincore.Middleware, jwtMgr *JWTManager) *Handler {
+	mw.WriteStorageError = writeStorageError
+	mw.WriteAuthError = writeAuthError
+	mw.WriteBodyTooLarge = writeBodyTooLarge
 	return &Handler{store: s, mw: mw, jwtMgr: jwtMgr}
 }
 
+// writeStorageError answers a request whose store operation failed the way
+// Clerk reports an internal error.
+func writeStorageError(w http.ResponseWriter, r *http.Request, failure *pkgstore.StorageFailure) {
+	clerkError(w, http.StatusInternalServerError, "internal_clerk_error",
+		"Oops, an unexpected error occurred", "There was an internal error on our servers. We've been notified and are working on fixing it.")
+}
+
+// writeBodyTooLarge answers a request body over the size limit the way
+// Clerk rejects a request it cannot parse: a 400, not a 413.
+func writeBodyTooLarge(w http.ResponseWriter, r *http.Request, limitKB int) {
+	clerkError(w, http.StatusBadRequest, "request_body_invalid",
+		"Request body invalid", fmt.Sprintf("The request body exceeds the maximum allowed size of %d KB.", limitKB))
+}
+
+// writeAuthError answers a request failed by an auth fault preset the way
+// Clerk rejects a bad, expired, or unauthorized secret key.
+func writeAuthError(w http.ResponseWriter, r *http.Request, preset string) {
+	switch preset {
+	case twincore.FaultAuthExpired:
+		clerkError(w, http.StatusUnauthorized, "authentication_invalid",
+			"Invalid authentication", "The provided secret key has expired.")
+	case twincore.FaultForbiddenScope:
+		clerkError(w, http.StatusForbidden, "authorization_invalid",
+			"Unauthorized request", "You are not authorized to perform this request")
+	default:
+		clerkError(w, http.StatusUnauthorized, "authentication_invalid",
+			"Invalid authentication", "Unable to authenticate the request, you need to supply an active API key")
+	}
+}
+
 // Routes mounts the Clerk API routes.
 func (h *Handler) Routes(r chi.Router) {
 	// Public endpoints (no auth required)
@@ -39,7 +74,7 @@ func (h *Handler) Routes(r chi.Router) {
 	r.Post("/v1/client/sign_ins", h.CreateSignIn)
 	r.Post("/v1/client/sign_ins/{id}/attempt_first_factor", h.AttemptFirstFactor)
 	r.Post("/v1/client/sessions/{id}/tokens", h.GetSessionToken)
-	r.Post("/v1/client/sessions/{id}/tokens/{template}", h.GetSessionToken) // template variant
+	r.Post("/v1/client/sessions/{id}/tokens/{template}", h.GetSessionToken) // JWT template variant
 	r.Post("/v1/client/sessions/{id}/touch", h.TouchSession)
 	r.Delete("/v1/client/sessions/{id}", h.EndSession)
 	r.Get("/v1/client/handshake", h.Handshake)
@@ -70,6 +105,8 @@ func (h *Handler) Routes(r chi.Router) {
 		r.Get("/sessions/{id}", h.GetSession)
 		r.Post("/sessions/{id}/verify", h.VerifySession)
 		r.Post("/sessions/{id}/revoke", h.RevokeSession)
+		r.Post("/sessions/{id}/tokens", h.CreateSessionToken)
+		r.Post("/sessions/{id}/tokens/{template}", h.CreateSessionToken)
 
 		// Organizations
 		r.Post("/organizations", h.CreateOrganization)
@@ -77,12 +114,22 @@ func (h *Handler) Routes(r chi.Router) {
 		r.Get("/organizations/{id}", h.GetOrganization)
 		r.Patch("/organizations/{id}", h.UpdateOrganization)
 		r.Delete("/organizations/{id}", h.DeleteOrganization)
+
+		// JWT templates
+		r.Post("/jwt_templates", h.CreateJWTTemplate)
+		r.Get("/jwt_templates", h.ListJWTTemplates)
+		r.Get("/jwt_templates/{id}", h.GetJWTTemplate)
+		r.Patch("/jwt_templates/{id}", h.UpdateJWTTemplate)
+		r.Delete("/jwt_templates/{id}", h.DeleteJWTTemplate)
 	})
 
 	// Admin-only JWT generation (not part of real Clerk API, used by tests)
 	r.Post("/admin/jwt/generate", h.GenerateJWT)
 	// Admin session creation (for seeding sessions tied to users)
 	r.Post("/admin/sessions", h.AdminCreateSession)
+	// Instance-wide custom session claims (Clerk's "Customize session token")
+	r.Get("/admin/session_claims", h.GetSessionClaims)
+	r.Put("/admin/session_claims", h.SetSessionClaims)
 }
 
 // authMiddleware validates Clerk-style Bearer token authentication.
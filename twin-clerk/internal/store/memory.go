@@ -17,6 +17,11 @@ type MemoryStore struct {
 	OrgMembers    *pkgstore.Store[OrgMembership]
 	Clients       *pkgstore.Store[Client]
 	SignIns       *pkgstore.Store[SignIn]
+	JWTTemplates  *pkgstore.Store[JWTTemplate]
+
+	// sessionClaims are the instance's custom session claims, added to every
+	// default session token. Guarded by mu.
+	sessionClaims map[string]any
 
 	Clock *pkgstore.Clock
 }
@@ -30,34 +35,49 @@ func New() *MemoryStore {
 		OrgMembers:    pkgstore.New[OrgMembership]("orgmem"),
 		Clients:       pkgstore.New[Client]("client"),
 		SignIns:       pkgstore.New[SignIn]("sini"),
+		JWTTemplates:  pkgstore.New[JWTTemplate]("jtmp"),
 		Clock:         pkgstore.NewClock(),
 	}
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 // stateSnapshot is the JSON-serializable state for admin endpoints.
 type stateSnapshot struct {
+	SchemaVersion int                      `json:"schema_version"`
 	Users         map[string]User          `json:"users"`
 	Sessions      map[string]Session       `json:"sessions"`
 	Organizations map[string]Organization  `json:"organizations"`
 	OrgMembers    map[string]OrgMembership `json:"org_members"`
 	Clients       map[string]Client        `json:"clients,omitempty"`
-	SignIns       map[string]SignIn         `json:"sign_ins,omitempty"`
+	SignIns       map[string]SignIn        `json:"sign_ins,omitempty"`
+	JWTTemplates  map[string]JWTTemplate   `json:"jwt_templates,omitempty"`
+	SessionClaims map[string]any           `json:"session_claims,omitempty"`
 }
 
 // Snapshot returns the full state as a JSON-serializable value.
 func (s *MemoryStore) Snapshot() any {
 	return stateSnapshot{
+		SchemaVersion: stateSchema.Current(),
 		Users:         s.Users.Snapshot(),
 		Sessions:      s.Sessions.Snapshot(),
 		Organizations: s.Organizations.Snapshot(),
 		OrgMembers:    s.OrgMembers.Snapshot(),
 		Clients:       s.Clients.Snapshot(),
 		SignIns:       s.SignIns.Snapshot(),
+		JWTTemplates:  s.JWTTemplates.Snapshot(),
+		SessionClaims: s.SessionClaims(),
 	}
 }
 
 // LoadState replaces the full state from a JSON body.
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap stateSnapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return err
@@ -73,6 +93,10 @@ func (s *MemoryStore) LoadState(data []byte) error {
 	if snap.SignIns != nil {
 		s.SignIns.LoadSnapshot(snap.SignIns)
 	}
+	if snap.JWTTemplates != nil {
+		s.JWTTemplates.LoadSnapshot(snap.JWTTemplates)
+	}
+	s.SetSessionClaims(snap.SessionClaims)
 	return nil
 }
 
@@ -84,5 +108,53 @@ func (s *MemoryStore) Reset() {
 	s.OrgMembers.Reset()
 	s.Clients.Reset()
 	s.SignIns.Reset()
+	s.JWTTemplates.Reset()
+	s.SetSessionClaims(nil)
 	s.Clock.Reset()
 }
+
+// ResetResources clears only the named resources, keyed as in the state snapshot.
+func (s *MemoryStore) ResetResources(names []string) error {
+	return pkgstore.ResetNamed(names, map[string]func(){
+		"users":          s.Users.Reset,
+		"sessions":       s.Sessions.Reset,
+		"organizations":  s.Organizations.Reset,
+		"org_members":    s.OrgMembers.Reset,
+		"clients":        s.Clients.Reset,
+		"sign_ins":       s.SignIns.Reset,
+		"jwt_templates":  s.JWTTemplates.Reset,
+		"session_claims": func() { s.SetSessionClaims(nil) },
+	})
+}
+
+// Collections exposes the record stores for NDJSON export and import.
+func (s *MemoryStore) Collections() map[string]pkgstore.Collection {
+	return map[string]pkgstore.Collection{
+		"users":         s.Users,
+		"sessions":      s.Sessions,
+		"organizations": s.Organizations,
+		"org_members":   s.OrgMembers,
+		"clients":       s.Clients,
+		"sign_ins":      s.SignIns,
+		"jwt_templates": s.JWTTemplates,
+	}
+}
+
+// SessionClaims returns the instance's custom session claims, or nil if
+// none are set.
+func (s *MemoryStore) SessionClaims() map[string]any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sessionClaims
+}
+
+// SetSessionClaims replaces the instance's custom session claims. Nil or
+// empty claims turn them off.
+func (s *MemoryStore) SetSessionClaims(claims map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(claims) == 0 {
+		claims = nil
+	}
+	s.sessionClaims = claims
+}
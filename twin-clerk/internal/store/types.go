@@ -109,6 +109,24 @@ type OrgMembership struct {
 	UpdatedAt      int64          `json:"updated_at"`
 }
 
+// JWTTemplate shapes the claims of tokens minted for it by name. Claim
+// values may contain shortcodes such as {{user.id}}, filled in per token.
+type JWTTemplate struct {
+	ID               string         `json:"id"`
+	Object           string         `json:"object"`
+	Name             string         `json:"name"`
+	Claims           map[string]any `json:"claims"`
+	Lifetime         int            `json:"lifetime"`
+	AllowedClockSkew int            `json:"allowed_clock_skew"`
+	CustomSigningKey bool           `json:"custom_signing_key"`
+	SigningAlgorithm string         `json:"signing_algorithm"`
+	// SigningKey is kept in state so snapshots round-trip, but never
+	// returned by the API.
+	SigningKey string `json:"signing_key,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
 // ClerkError is the Clerk API error response format.
 type ClerkError struct {
 	Errors []ClerkErrorEntry `json:"errors"`
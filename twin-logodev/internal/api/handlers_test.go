@@ -1,6 +1,9 @@
 package api_test
 
 import (
+	"bytes"
+	"image/jpeg"
+	"image/png"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -17,7 +20,7 @@ func setupLogodev(t *testing.T) (*httptest.Server, *testutil.TwinClient) {
 	memStore := store.New()
 	cfg := &twincore.Config{Name: "twin-logodev-test"}
 	twin := twincore.New(cfg)
-	handler := api.NewHandler(memStore)
+	handler := api.NewHandler(memStore, twin.Middleware())
 	handler.Routes(twin.Router)
 	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
 	adminHandler.Routes(twin.Router)
@@ -105,6 +108,118 @@ func TestGetLogoDifferentDomains(t *testing.T) {
 	}
 }
 
+func TestGetLogoPNG(t *testing.T) {
+	_, tc := setupLogodev(t)
+
+	resp := tc.Get("/stripe.com?token=test&format=png&size=64")
+	resp.AssertStatus(200)
+	if ct := resp.Headers.Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected image/png, got %s", ct)
+	}
+	img, err := png.Decode(bytes.NewReader(resp.Body))
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 64 || b.Dy() != 64 {
+		t.Errorf("expected 64x64, got %v", b)
+	}
+	// The initial is drawn in the text color, so the center differs from the corner
+	if img.At(0, 0) == img.At(32, 32) && img.At(0, 0) == img.At(28, 32) {
+		t.Error("expected the initial to be drawn")
+	}
+}
+
+func TestGetLogoJPEG(t *testing.T) {
+	_, tc := setupLogodev(t)
+
+	resp := tc.Get("/stripe.com?token=test&format=jpg&size=32")
+	resp.AssertStatus(200)
+	if ct := resp.Headers.Get("Content-Type"); ct != "image/jpeg" {
+		t.Errorf("expected image/jpeg, got %s", ct)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(resp.Body)); err != nil {
+		t.Fatalf("decoding JPEG: %v", err)
+	}
+}
+
+func TestGetLogoValidation(t *testing.T) {
+	_, tc := setupLogodev(t)
+
+	tc.Get("/not_a_domain?token=test").AssertStatus(422).AssertBodyContains("invalid domain")
+	tc.Get("/stripe.com?token=test&size=0").AssertStatus(422)
+	tc.Get("/stripe.com?token=test&size=4096").AssertStatus(422)
+	tc.Get("/stripe.com?token=test&format=webp").AssertStatus(422).AssertBodyContains("unsupported format")
+}
+
+func TestGetLogoStrictModeFromSeededDomains(t *testing.T) {
+	_, tc := setupLogodev(t)
+	ac := testutil.NewAdminClient(tc)
+	ac.LoadState(map[string]any{
+		"domains": map[string]any{
+			"acme.com": map[string]any{"name": "Zephyr", "color": "#112233"},
+		},
+	}).AssertStatus(200)
+
+	resp := tc.Get("/acme.com?token=test")
+	resp.AssertStatus(200)
+	body := string(resp.Body)
+	if !strings.Contains(body, ">ZE<") || !strings.Contains(body, `fill="#112233"`) {
+		t.Errorf("expected seeded name and color, got %s", body)
+	}
+
+	tc.Get("/unknown.com?token=test").AssertStatus(404).AssertBodyContains("logo not found")
+	tc.Get("/unknown.com?token=test&fallback=monogram").AssertStatus(200)
+}
+
+func TestGetLogoNonASCIIInitials(t *testing.T) {
+	_, tc := setupLogodev(t)
+	ac := testutil.NewAdminClient(tc)
+	ac.LoadState(map[string]any{
+		"domains": map[string]any{
+			"emile.fr": map[string]any{"name": "émile"},
+		},
+	}).AssertStatus(200)
+
+	resp := tc.Get("/emile.fr?token=test")
+	resp.AssertStatus(200)
+	if body := string(resp.Body); !strings.Contains(body, ">ÉM<") {
+		t.Errorf("expected initials 'ÉM', got %s", body)
+	}
+}
+
+func TestGetLogoCacheHeaders(t *testing.T) {
+	_, tc := setupLogodev(t)
+
+	first := tc.Get("/stripe.com?token=test")
+	first.AssertStatus(200)
+	if cc := first.Headers.Get("Cache-Control"); cc != "public, max-age=86400" {
+		t.Errorf("unexpected Cache-Control %q", cc)
+	}
+	if first.Headers.Get("X-Cache") != "MISS" {
+		t.Errorf("expected first request to MISS, got %q", first.Headers.Get("X-Cache"))
+	}
+	etag := first.Headers.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	second := tc.DoWithHeaders("GET", "/stripe.com?token=test", nil, map[string]string{"If-None-Match": etag})
+	second.AssertStatus(304)
+	if second.Headers.Get("X-Cache") != "HIT" {
+		t.Errorf("expected repeat request to HIT, got %q", second.Headers.Get("X-Cache"))
+	}
+}
+
+func TestGetLogoRateLimitFault(t *testing.T) {
+	_, tc := setupLogodev(t)
+
+	tc.Post("/admin/fault/*", map[string]any{"status_code": 429, "body": `{"error":"rate limit exceeded"}`}).AssertStatus(200)
+
+	tc.Get("/stripe.com?token=test").AssertStatus(429).AssertBodyContains("rate limit exceeded")
+	tc.Get("/github.com?token=test").AssertStatus(429)
+	tc.Get("/admin/health").AssertStatus(200)
+}
+
 // --- Admin Tests ---
 
 func TestAdminListLogos(t *testing.T) {
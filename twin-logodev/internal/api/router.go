@@ -3,9 +3,13 @@ package api
 import (
 	"crypto/md5"
 	"fmt"
+	"html"
+	"image/color"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
@@ -15,60 +19,122 @@ import (
 // Handler holds logo API state.
 type Handler struct {
 	store *store.MemoryStore
+	mw    *twincore.Middleware
 }
 
 // NewHandler creates a new logo API handler.
-func NewHandler(s *store.MemoryStore) *Handler {
-	return &Handler{store: s}
+func NewHandler(s *store.MemoryStore, mw *twincore.Middleware) *Handler {
+	return &Handler{store: s, mw: mw}
 }
 
+// AllDomains is the fault endpoint covering every logo request, e.g.
+// POST /admin/fault/* to rate limit the whole API.
+const AllDomains = "/*"
+
+// maxSize is the largest size= accepted.
+const maxSize = 1024
+
+// validDomain matches hostnames such as stripe.com or api.example.co.uk.
+var validDomain = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
 // Routes mounts the Logo.dev-compatible routes.
 func (h *Handler) Routes(r chi.Router) {
 	// Logo.dev uses GET /{domain} with ?token= param
-	r.Get("/{domain}", h.GetLogo)
+	r.With(h.mw.FaultInjection, h.mw.FaultInjectionFor(AllDomains)).Get("/{domain}", h.GetLogo)
 
 	// Admin extras
 	r.Get("/admin/logos", h.ListLogos)
 }
 
-// GetLogo handles GET /{domain} - returns a deterministic SVG placeholder.
+// GetLogo handles GET /{domain}. It returns a deterministic monogram in the
+// requested format (svg, png, or jpg) with CDN-style caching headers. In
+// strict mode (domains seeded) unseeded domains return 404 unless
+// fallback=monogram is set.
 func (h *Handler) GetLogo(w http.ResponseWriter, r *http.Request) {
-	domain := chi.URLParam(r, "domain")
+	domain := strings.ToLower(chi.URLParam(r, "domain"))
+	q := r.URL.Query()
 
 	// Validate token (accept any non-empty token)
-	token := r.URL.Query().Get("token")
+	token := q.Get("token")
 	if token == "" {
 		twincore.Error(w, http.StatusUnauthorized, "API token required")
 		return
 	}
 
+	if !validDomain.MatchString(domain) {
+		twincore.Error(w, http.StatusUnprocessableEntity, "invalid domain: "+domain)
+		return
+	}
+
 	size := 128
-	if s := r.URL.Query().Get("size"); s != "" {
-		if parsed, err := strconv.Atoi(s); err == nil && parsed > 0 {
-			size = parsed
+	if s := q.Get("size"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed <= 0 || parsed > maxSize {
+			twincore.Error(w, http.StatusUnprocessableEntity, fmt.Sprintf("size must be an integer between 1 and %d", maxSize))
+			return
 		}
+		size = parsed
 	}
 
-	format := r.URL.Query().Get("format")
-	greyscale := r.URL.Query().Get("greyscale") == "true"
+	format := q.Get("format")
+	contentType, ok := contentTypes[format]
+	if !ok {
+		twincore.Error(w, http.StatusUnprocessableEntity, "unsupported format: "+format+" (use svg, png, or jpg)")
+		return
+	}
+	greyscale := q.Get("greyscale") == "true"
 
 	// Record the request
+	cached := h.store.Requested(domain, size, format, greyscale)
 	h.store.RecordRequest(domain, size, format, greyscale)
 
-	// Check for custom logo
-	if custom, ok := h.store.CustomLogos[domain]; ok {
-		w.Header().Set("Content-Type", "image/svg+xml")
-		w.WriteHeader(http.StatusOK)
-		w.Write(custom)
+	d, known := h.store.LookupDomain(domain)
+	if !known && q.Get("fallback") != "monogram" {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		twincore.Error(w, http.StatusNotFound, "logo not found for "+domain)
 		return
 	}
 
-	// Generate deterministic placeholder SVG
-	svg := generatePlaceholderSVG(domain, size, greyscale)
-	w.Header().Set("Content-Type", "image/svg+xml")
+	var body []byte
+	if custom, ok := h.store.CustomLogos[domain]; ok && contentType == "image/svg+xml" {
+		body = custom
+	} else {
+		bg, fg := palette(d, greyscale)
+		switch contentType {
+		case "image/svg+xml":
+			body = []byte(generatePlaceholderSVG(size, bg, fg, initials(d)))
+		default:
+			var err error
+			body, err = renderRaster(format, size, bg, fg, initials(d)[0])
+			if err != nil {
+				twincore.Error(w, http.StatusInternalServerError, "rendering logo: "+err.Error())
+				return
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("Vary", "Accept")
+	if cached {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
+	}
+	if twincore.NotModified(w, r, twincore.ETag(body)) {
+		return
+	}
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(svg))
+	w.Write(body)
+}
+
+// contentTypes maps the format= parameter to the response content type.
+var contentTypes = map[string]string{
+	"":     "image/svg+xml",
+	"svg":  "image/svg+xml",
+	"png":  "image/png",
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
 }
 
 // ListLogos handles GET /admin/logos - returns all requested domains.
@@ -84,34 +150,49 @@ func (h *Handler) ListLogos(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// generatePlaceholderSVG creates a colored square with domain initials.
-func generatePlaceholderSVG(domain string, size int, greyscale bool) string {
+// palette returns the background and text colors for a domain's monogram.
+// The background is the seeded color or one derived from the domain; the
+// text is white or black depending on the background's luminance.
+func palette(d store.Domain, greyscale bool) (bg, fg color.RGBA) {
 	// Generate deterministic color from domain
-	hash := md5.Sum([]byte(domain))
+	hash := md5.Sum([]byte(d.Domain))
 	r, g, b := int(hash[0]), int(hash[1]), int(hash[2])
+	var cr, cg, cb uint8
+	if _, err := fmt.Sscanf(d.Color, "#%02x%02x%02x", &cr, &cg, &cb); err == nil {
+		r, g, b = int(cr), int(cg), int(cb)
+	}
 
 	if greyscale {
 		avg := (r + g + b) / 3
 		r, g, b = avg, avg, avg
 	}
 
-	// Get initials from domain
-	parts := strings.Split(domain, ".")
-	name := parts[0]
-	initials := strings.ToUpper(name[:1])
-	if len(name) > 1 {
-		initials += strings.ToUpper(name[1:2])
-	}
-
-	color := fmt.Sprintf("#%02x%02x%02x", r, g, b)
-
 	// Calculate text color (white or black based on luminance)
 	luminance := 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
-	textColor := "#ffffff"
+	fg = color.RGBA{255, 255, 255, 255}
 	if luminance > 128 {
-		textColor = "#000000"
+		fg = color.RGBA{0, 0, 0, 255}
+	}
+	return color.RGBA{uint8(r), uint8(g), uint8(b), 255}, fg
+}
+
+// initials returns up to two uppercase letters from the brand name, or from
+// the first label of the domain.
+func initials(d store.Domain) string {
+	name := strings.ReplaceAll(d.Name, " ", "")
+	if name == "" {
+		name = strings.Split(d.Domain, ".")[0]
 	}
+	first, size := utf8.DecodeRuneInString(name)
+	initials := strings.ToUpper(string(first))
+	if second, _ := utf8.DecodeRuneInString(name[size:]); second != utf8.RuneError {
+		initials += strings.ToUpper(string(second))
+	}
+	return initials
+}
 
+// generatePlaceholderSVG creates a colored square with domain initials.
+func generatePlaceholderSVG(size int, bg, fg color.RGBA, initials string) string {
 	fontSize := size / 3
 
 	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">
@@ -119,6 +200,10 @@ func generatePlaceholderSVG(domain string, size int, greyscale bool) string {
   <text x="50%%" y="50%%" dominant-baseline="central" text-anchor="middle" fill="%s" font-family="system-ui, sans-serif" font-size="%d" font-weight="600">%s</text>
 </svg>`,
 		size, size, size, size,
-		size, size, size/8, color,
-		textColor, fontSize, initials)
+		size, size, size/8, hexColor(bg),
+		hexColor(fg), fontSize, html.EscapeString(initials))
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
 }
@@ -10,6 +10,7 @@ import (
 // MemoryStore holds all logo twin state.
 type MemoryStore struct {
 	Requests *pkgstore.Store[LogoRequest]
+	Domains  *pkgstore.Store[Domain] // keyed by domain
 	// CustomLogos maps domain -> SVG content for specific test domains
 	CustomLogos map[string][]byte
 	Clock       *pkgstore.Clock
@@ -19,11 +20,30 @@ type MemoryStore struct {
 func New() *MemoryStore {
 	return &MemoryStore{
 		Requests:    pkgstore.New[LogoRequest]("logo"),
+		Domains:     pkgstore.New[Domain]("domain"),
 		CustomLogos: make(map[string][]byte),
 		Clock:       pkgstore.NewClock(),
 	}
 }
 
+// LookupDomain returns the seeded record for domain. known is false only in
+// strict mode (some domains seeded) for a domain that was not seeded.
+func (s *MemoryStore) LookupDomain(domain string) (d Domain, known bool) {
+	if d, ok := s.Domains.Get(domain); ok {
+		d.Domain = domain
+		return d, true
+	}
+	return Domain{Domain: domain}, s.Domains.Count() == 0
+}
+
+// Requested reports whether an identical logo request was served before,
+// which the API reports as a CDN cache hit.
+func (s *MemoryStore) Requested(domain string, size int, format string, greyscale bool) bool {
+	return len(s.Requests.Filter(func(_ string, r LogoRequest) bool {
+		return r.Domain == domain && r.Size == size && r.Format == format && r.Greyscale == greyscale
+	})) > 0
+}
+
 // RecordRequest logs a logo request.
 func (s *MemoryStore) RecordRequest(domain string, size int, format string, greyscale bool) {
 	id := s.Requests.NextID()
@@ -36,28 +56,64 @@ func (s *MemoryStore) RecordRequest(domain string, size int, format string, grey
 	})
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 type stateSnapshot struct {
-	Requests    map[string]LogoRequest `json:"requests"`
-	CustomLogos map[string]string      `json:"custom_logos,omitempty"` // domain -> base64 SVG
+	SchemaVersion int                    `json:"schema_version"`
+	Requests      map[string]LogoRequest `json:"requests"`
+	Domains       map[string]Domain      `json:"domains,omitempty"`
+	CustomLogos   map[string]string      `json:"custom_logos,omitempty"` // domain -> base64 SVG
 }
 
 func (s *MemoryStore) Snapshot() any {
 	return stateSnapshot{
-		Requests: s.Requests.Snapshot(),
+		SchemaVersion: stateSchema.Current(),
+		Requests:      s.Requests.Snapshot(),
+		Domains:       s.Domains.Snapshot(),
 	}
 }
 
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap stateSnapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return err
 	}
 	s.Requests.LoadSnapshot(snap.Requests)
+	if snap.Domains != nil {
+		s.Domains.LoadSnapshot(snap.Domains)
+	}
 	return nil
 }
 
 func (s *MemoryStore) Reset() {
 	s.Requests.Reset()
+	s.Domains.Reset()
 	s.CustomLogos = make(map[string][]byte)
 	s.Clock.Reset()
 }
+
+// ResetResources clears only the named resources, keyed as in the state snapshot.
+func (s *MemoryStore) ResetResources(names []string) error {
+	return pkgstore.ResetNamed(names, map[string]func(){
+		"requests": s.Requests.Reset,
+		"domains":  s.Domains.Reset,
+		"custom_logos": func() {
+			s.CustomLogos = make(map[string][]byte)
+		},
+	})
+}
+
+// Collections exposes the record stores for NDJSON export and import.
+// Custom logos are not record-backed and only round-trip via LoadState.
+func (s *MemoryStore) Collections() map[string]pkgstore.Collection {
+	return map[string]pkgstore.Collection{
+		"requests": s.Requests,
+		"domains":  s.Domains,
+	}
+}
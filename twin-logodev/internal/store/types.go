@@ -10,3 +10,11 @@ type LogoRequest struct {
 	Greyscale bool      `json:"greyscale,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// Domain is a domain the twin has a logo for. Seeding any domains switches
+// the twin to strict mode, where unseeded domains return 404.
+type Domain struct {
+	Domain string `json:"domain"`
+	Name   string `json:"name,omitempty"`  // brand name; its first letter is the monogram initial
+	Color  string `json:"color,omitempty"` // background as #rrggbb; derived from the domain when empty
+}
@@ -0,0 +1,55 @@
+// Package logodevtwin wires twin-logodev's store, API, and admin plane
+// onto a twincore.Twin, for the twin-logodev binary and for hosts such as
+// twin-multi that run several twins in one process.
+package logodevtwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-logodev/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-logodev/internal/store"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Description is what twin-logodev reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-logodev",
+	SDKTarget:    twincore.SDKTarget{Package: "logo.dev", Version: "v1"},
+	DefaultPort:  4116,
+	Capabilities: []string{"clock", "quirks"},
+	Faults: []twincore.NamedFault{
+		{Name: "rate_limited", Endpoint: api.AllDomains, Description: "Every logo request returns 429", Fault: twincore.FaultConfig{StatusCode: 429, Body: `{"error":"rate limit exceeded"}`, Rate: 1.0}},
+	},
+}
+
+// Build mounts twin-logodev on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, twin.Middleware())
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+	}
+
+	twin.Logger.Info("twin-logodev ready", "addr", cfg.Addr())
+	return nil
+}
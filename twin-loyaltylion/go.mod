@@ -4,7 +4,11 @@ go 1.25.7
 
 replace github.com/wondertwin-ai/wondertwin/twinkit => ../twinkit
 
+replace github.com/wondertwin-ai/wondertwin/adminclient => ../adminclient
+
 require (
 	github.com/go-chi/chi/v5 v5.2.5
 	github.com/wondertwin-ai/wondertwin/twinkit v0.0.0-00010101000000-000000000000
 )
+
+require github.com/wondertwin-ai/wondertwin/adminclient v0.1.0 // indirect
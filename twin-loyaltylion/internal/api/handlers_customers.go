@@ -16,9 +16,6 @@ func (h *Handler) ListCustomers(w http.ResponseWriter, r *http.Request) {
 	apiKey := getAPIKey(r)
 	email := r.URL.Query().Get("email")
 
-	// Process any expired points before returning data
-	h.store.ProcessExpiredPoints(h.store.Clock.Now())
-
 	var customers []store.Customer
 	if email != "" {
 		c := h.store.GetCustomerByEmail(apiKey, email)
@@ -46,8 +43,6 @@ func (h *Handler) GetCustomer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.store.ProcessExpiredPoints(h.store.Clock.Now())
-
 	c, ok := h.store.Customers.Get(store.CustomerKey(id))
 	if !ok || c.APIKey != apiKey {
 		twincore.Error(w, http.StatusNotFound, "customer not found")
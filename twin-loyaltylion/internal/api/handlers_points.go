@@ -2,22 +2,24 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
 	"github.com/wondertwin-ai/wondertwin/twin-loyaltylion/internal/store"
 )
 
+// errInsufficientPoints aborts a customer update whose balance cannot cover a debit.
+var errInsufficientPoints = errors.New("insufficient_points")
+
 // GetPoints handles GET /v2/customers/{merchant_id}/points.
 func (h *Handler) GetPoints(w http.ResponseWriter, r *http.Request) {
 	apiKey := getAPIKey(r)
 	merchantID := chi.URLParam(r, "merchant_id")
 
-	h.store.ProcessExpiredPoints(h.store.Clock.Now())
-
 	c := h.store.GetCustomerByMerchantID(apiKey, merchantID)
 	if c == nil {
 		twincore.Error(w, http.StatusNotFound, "customer not found")
@@ -57,22 +59,30 @@ func (h *Handler) AddPoints(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Credit the balance and record the transaction in one commit.
 	now := h.store.Clock.Now()
-	c.PointsApproved += req.Points
-	c.UpdatedAt = now.Format(time.RFC3339)
-	h.store.Customers.Set(store.CustomerKey(c.ID), *c)
-
-	// Record transaction
-	txnID := h.store.NextTransactionID()
-	h.store.Transactions.Set(fmt.Sprintf("%d", txnID), store.PointsTransaction{
-		ID:         txnID,
-		CustomerID: c.ID,
-		Type:       "earn",
-		Amount:     req.Points,
-		Reason:     req.Reason,
-		Timestamp:  now.Format(time.RFC3339),
-		APIKey:     apiKey,
+	tx := pkgstore.NewTx()
+	h.store.Transactions.JoinTx(tx)
+	var updated store.Customer
+	h.store.Customers.UpdateTx(tx, store.CustomerKey(c.ID), func(c store.Customer) (store.Customer, error) {
+		c.PointsApproved += req.Points
+		c.UpdatedAt = now.Format(time.RFC3339)
+		h.store.RecordTransactionTx(tx, store.PointsTransaction{
+			CustomerID: c.ID,
+			Type:       "earn",
+			Amount:     req.Points,
+			Reason:     req.Reason,
+			Timestamp:  now.Format(time.RFC3339),
+			APIKey:     apiKey,
+		})
+		updated = c
+		return c, nil
 	})
+	if err := tx.Commit(); err != nil {
+		twincore.Error(w, http.StatusNotFound, "customer not found")
+		return
+	}
+	c = &updated
 
 	twincore.JSON(w, http.StatusOK, map[string]int{
 		"points_approved": c.PointsApproved,
@@ -107,28 +117,40 @@ func (h *Handler) RemovePoints(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if c.PointsApproved < req.Points {
+	// Check and debit atomically so concurrent removals cannot overspend,
+	// recording the transaction in the same commit.
+	now := h.store.Clock.Now()
+	tx := pkgstore.NewTx()
+	h.store.Transactions.JoinTx(tx)
+	var updated store.Customer
+	h.store.Customers.UpdateTx(tx, store.CustomerKey(c.ID), func(c store.Customer) (store.Customer, error) {
+		if c.PointsApproved < req.Points {
+			return c, errInsufficientPoints
+		}
+		c.PointsApproved -= req.Points
+		c.PointsSpent += req.Points
+		c.UpdatedAt = now.Format(time.RFC3339)
+		h.store.RecordTransactionTx(tx, store.PointsTransaction{
+			CustomerID: c.ID,
+			Type:       "spend",
+			Amount:     req.Points,
+			Reason:     req.Reason,
+			Timestamp:  now.Format(time.RFC3339),
+			APIKey:     apiKey,
+		})
+		updated = c
+		return c, nil
+	})
+	err := tx.Commit()
+	if errors.Is(err, errInsufficientPoints) {
 		twincore.Error(w, http.StatusUnprocessableEntity, "insufficient_points")
 		return
 	}
-
-	now := h.store.Clock.Now()
-	c.PointsApproved -= req.Points
-	c.PointsSpent += req.Points
-	c.UpdatedAt = now.Format(time.RFC3339)
-	h.store.Customers.Set(store.CustomerKey(c.ID), *c)
-
-	// Record transaction
-	txnID := h.store.NextTransactionID()
-	h.store.Transactions.Set(fmt.Sprintf("%d", txnID), store.PointsTransaction{
-		ID:         txnID,
-		CustomerID: c.ID,
-		Type:       "spend",
-		Amount:     req.Points,
-		Reason:     req.Reason,
-		Timestamp:  now.Format(time.RFC3339),
-		APIKey:     apiKey,
-	})
+	if err != nil {
+		twincore.Error(w, http.StatusNotFound, "customer not found")
+		return
+	}
+	c = &updated
 
 	twincore.JSON(w, http.StatusOK, map[string]int{
 		"points_approved": c.PointsApproved,
@@ -1,9 +1,8 @@
 package api
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -11,10 +10,17 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
 	"github.com/wondertwin-ai/wondertwin/twin-loyaltylion/internal/store"
 )
 
+var (
+	errClaimNotFound   = errors.New("claimed reward not found")
+	errAlreadyRefunded = errors.New("already refunded")
+	errIdempotentClaim = errors.New("idempotent claim")
+)
+
 // ListAvailableRewards handles GET /v2/customers/{merchant_id}/available_rewards.
 func (h *Handler) ListAvailableRewards(w http.ResponseWriter, r *http.Request) {
 	apiKey := getAPIKey(r)
@@ -66,55 +72,64 @@ func (h *Handler) ClaimReward(w http.ResponseWriter, r *http.Request) {
 	totalCost := reward.PointCost * req.Multiplier
 	now := h.store.Clock.Now()
 
-	// Idempotency check
-	if existing := h.store.FindIdempotentClaim(c.ID, req.RewardID, req.Multiplier, apiKey, now); existing != nil {
+	// The idempotency check, balance check, debit, claim, and transaction all
+	// commit together while the transaction holds the Customers,
+	// ClaimedRewards, and Transactions stores locked, so concurrent claims
+	// cannot double-spend and readers never see a debit without its claim.
+	tx := pkgstore.NewTx()
+	h.store.ClaimedRewards.JoinTx(tx)
+	h.store.Transactions.JoinTx(tx)
+	var claimed, existing *store.ClaimedReward
+	h.store.Customers.UpdateTx(tx, store.CustomerKey(c.ID), func(c store.Customer) (store.Customer, error) {
+		if existing = h.store.FindIdempotentClaim(tx, c.ID, req.RewardID, req.Multiplier, apiKey, now); existing != nil {
+			return c, errIdempotentClaim
+		}
+		if c.PointsApproved < totalCost {
+			return c, errInsufficientPoints
+		}
+		c.PointsApproved -= totalCost
+		c.PointsSpent += totalCost
+		c.UpdatedAt = now.Format(time.RFC3339)
+
+		claimID := h.store.NextClaimedRewardID()
+		claimed = &store.ClaimedReward{
+			ID:         claimID,
+			RewardID:   req.RewardID,
+			PointCost:  totalCost,
+			Redeemable: store.Redeemable{Code: h.generateDiscountCode(), Fulfilled: false},
+			Refunded:   false,
+			CreatedAt:  now.Format(time.RFC3339),
+			CustomerID: c.ID,
+			APIKey:     apiKey,
+			Multiplier: req.Multiplier,
+		}
+		h.store.ClaimedRewards.SetTx(tx, store.ClaimedRewardKey(claimID), *claimed)
+		h.store.RecordTransactionTx(tx, store.PointsTransaction{
+			CustomerID: c.ID,
+			Type:       "spend",
+			Amount:     totalCost,
+			Reason:     fmt.Sprintf("Redeemed: %s", reward.Title),
+			Timestamp:  now.Format(time.RFC3339),
+			APIKey:     apiKey,
+		})
+		return c, nil
+	})
+	switch err := tx.Commit(); {
+	case errors.Is(err, errIdempotentClaim):
 		twincore.JSON(w, http.StatusOK, map[string]any{
 			"claimed_reward": existing,
 		})
 		return
-	}
-
-	// Check balance
-	if c.PointsApproved < totalCost {
+	case errors.Is(err, errInsufficientPoints):
 		twincore.JSON(w, http.StatusUnprocessableEntity, map[string]string{
 			"error": "insufficient_points",
 		})
 		return
+	case err != nil:
+		twincore.Error(w, http.StatusNotFound, "customer not found")
+		return
 	}
 
-	// Debit points
-	c.PointsApproved -= totalCost
-	c.PointsSpent += totalCost
-	c.UpdatedAt = now.Format(time.RFC3339)
-	h.store.Customers.Set(store.CustomerKey(c.ID), *c)
-
-	// Record transaction
-	txnID := h.store.NextTransactionID()
-	h.store.Transactions.Set(fmt.Sprintf("%d", txnID), store.PointsTransaction{
-		ID:         txnID,
-		CustomerID: c.ID,
-		Type:       "spend",
-		Amount:     totalCost,
-		Reason:     fmt.Sprintf("Redeemed: %s", reward.Title),
-		Timestamp:  now.Format(time.RFC3339),
-		APIKey:     apiKey,
-	})
-
-	// Create claimed reward
-	claimID := h.store.NextClaimedRewardID()
-	claimed := store.ClaimedReward{
-		ID:         claimID,
-		RewardID:   req.RewardID,
-		PointCost:  totalCost,
-		Redeemable: store.Redeemable{Code: generateDiscountCode(), Fulfilled: false},
-		Refunded:   false,
-		CreatedAt:  now.Format(time.RFC3339),
-		CustomerID: c.ID,
-		APIKey:     apiKey,
-		Multiplier: req.Multiplier,
-	}
-	h.store.ClaimedRewards.Set(store.ClaimedRewardKey(claimID), claimed)
-
 	twincore.JSON(w, http.StatusCreated, map[string]any{
 		"claimed_reward": claimed,
 	})
@@ -138,53 +153,64 @@ func (h *Handler) RefundClaimedReward(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	claimed, ok := h.store.ClaimedRewards.Get(store.ClaimedRewardKey(id))
-	if !ok || claimed.APIKey != apiKey || claimed.CustomerID != c.ID {
+	now := h.store.Clock.Now()
+
+	// Re-check the claim and restore points while the transaction holds the
+	// stores locked, so a claim can only be refunded once; the refund's
+	// transaction commits with it.
+	tx := pkgstore.NewTx()
+	h.store.ClaimedRewards.JoinTx(tx)
+	h.store.Transactions.JoinTx(tx)
+	var claimed store.ClaimedReward
+	h.store.Customers.UpdateTx(tx, store.CustomerKey(c.ID), func(c store.Customer) (store.Customer, error) {
+		var ok bool
+		claimed, ok = h.store.ClaimedRewards.GetTx(tx, store.ClaimedRewardKey(id))
+		if !ok || claimed.APIKey != apiKey || claimed.CustomerID != c.ID {
+			return c, errClaimNotFound
+		}
+		if claimed.Refunded {
+			return c, errAlreadyRefunded
+		}
+
+		c.PointsApproved += claimed.PointCost
+		c.PointsSpent -= claimed.PointCost
+		if c.PointsSpent < 0 {
+			c.PointsSpent = 0
+		}
+		c.UpdatedAt = now.Format(time.RFC3339)
+
+		claimed.Refunded = true
+		h.store.ClaimedRewards.SetTx(tx, store.ClaimedRewardKey(id), claimed)
+		h.store.RecordTransactionTx(tx, store.PointsTransaction{
+			CustomerID: c.ID,
+			Type:       "adjust",
+			Amount:     claimed.PointCost,
+			Reason:     "Redemption refund",
+			Timestamp:  now.Format(time.RFC3339),
+			APIKey:     apiKey,
+		})
+		return c, nil
+	})
+	switch err := tx.Commit(); {
+	case errors.Is(err, errClaimNotFound):
 		twincore.Error(w, http.StatusNotFound, "claimed reward not found")
 		return
-	}
-
-	if claimed.Refunded {
+	case errors.Is(err, errAlreadyRefunded):
 		twincore.Error(w, http.StatusUnprocessableEntity, "already refunded")
 		return
+	case err != nil:
+		twincore.Error(w, http.StatusNotFound, "customer not found")
+		return
 	}
 
-	now := h.store.Clock.Now()
-
-	// Restore points
-	c.PointsApproved += claimed.PointCost
-	c.PointsSpent -= claimed.PointCost
-	if c.PointsSpent < 0 {
-		c.PointsSpent = 0
-	}
-	c.UpdatedAt = now.Format(time.RFC3339)
-	h.store.Customers.Set(store.CustomerKey(c.ID), *c)
-
-	// Record transaction
-	txnID := h.store.NextTransactionID()
-	h.store.Transactions.Set(fmt.Sprintf("%d", txnID), store.PointsTransaction{
-		ID:         txnID,
-		CustomerID: c.ID,
-		Type:       "adjust",
-		Amount:     claimed.PointCost,
-		Reason:     "Redemption refund",
-		Timestamp:  now.Format(time.RFC3339),
-		APIKey:     apiKey,
-	})
-
-	// Mark as refunded
-	claimed.Refunded = true
-	h.store.ClaimedRewards.Set(store.ClaimedRewardKey(id), claimed)
-
 	twincore.JSON(w, http.StatusOK, map[string]any{
 		"claimed_reward": claimed,
 	})
 }
 
 // generateDiscountCode creates a unique code in the format LOYAL-XXXX-XXXX.
-func generateDiscountCode() string {
-	b := make([]byte, 4)
-	rand.Read(b)
-	hex := strings.ToUpper(hex.EncodeToString(b))
+// Codes are reproducible when the twin runs with --rand-seed.
+func (h *Handler) generateDiscountCode() string {
+	hex := strings.ToUpper(h.mw.Rand.Hex(4))
 	return fmt.Sprintf("LOYAL-%s-%s", hex[:4], hex[4:])
 }
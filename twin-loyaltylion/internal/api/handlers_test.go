@@ -3,7 +3,10 @@ package api_test
 import (
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
@@ -20,6 +23,7 @@ func setupLoyaltyLion(t *testing.T) (*testutil.TwinClient, *testutil.AdminClient
 	cfg := &twincore.Config{Name: "twin-loyaltylion-test"}
 	twin := twincore.New(cfg)
 	mw := twin.Middleware()
+	twin.Router.Use(memStore.Clock.Middleware)
 	handler := api.NewHandler(memStore, mw)
 	handler.Routes(twin.Router)
 	adminHandler := admin.NewHandler(memStore, mw, memStore.Clock)
@@ -351,6 +355,74 @@ func TestClaimRewardIdempotency(t *testing.T) {
 	}
 }
 
+// --- Concurrency Tests ---
+
+// concurrentPosts fires n identical POSTs at once and returns the count of
+// each status code. It uses the raw HTTP client because testutil assertions
+// must not run outside the test goroutine.
+func concurrentPosts(t *testing.T, tc *testutil.TwinClient, n int, path, body string) map[int]int {
+	t.Helper()
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		counts = make(map[int]int)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("POST", tc.BaseURL+path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", authAlpha["Authorization"])
+			resp, err := tc.HTTPClient.Do(req)
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+			mu.Lock()
+			counts[resp.StatusCode]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return counts
+}
+
+func TestConcurrentRemovePointsNoOverspend(t *testing.T) {
+	tc, _, _ := setupLoyaltyLion(t)
+
+	// Sarah has 4200 approved points; 100 concurrent 100-point debits must
+	// succeed exactly 42 times.
+	counts := concurrentPosts(t, tc, 100, "/v2/customers/cust-001/points/remove", `{"points": 100, "reason": "stress"}`)
+	if counts[200] != 42 || counts[422] != 58 {
+		t.Errorf("expected 42 successes and 58 rejections, got %v", counts)
+	}
+
+	points := llGet(tc, "/v2/customers/cust-001/points", authAlpha).JSONMap()
+	if points["points_approved"] != float64(0) {
+		t.Errorf("expected 0 approved, got %v", points["points_approved"])
+	}
+	if points["points_spent"] != float64(7700) {
+		t.Errorf("expected 7700 spent, got %v", points["points_spent"])
+	}
+}
+
+func TestConcurrentClaimRewardSingleDebit(t *testing.T) {
+	tc, _, _ := setupLoyaltyLion(t)
+
+	// Identical concurrent claims must collapse into one debit via idempotency.
+	counts := concurrentPosts(t, tc, 20, "/v2/customers/cust-003/claimed_rewards", `{"reward_id": 1, "multiplier": 1}`)
+	if counts[201] != 1 || counts[200] != 19 {
+		t.Errorf("expected 1 created and 19 idempotent replays, got %v", counts)
+	}
+
+	points := llGet(tc, "/v2/customers/cust-003/points", authAlpha).JSONMap()
+	if points["points_approved"] != float64(14500) {
+		t.Errorf("expected 14500 (single debit), got %v", points["points_approved"])
+	}
+}
+
 func TestDiscountCodeUniqueness(t *testing.T) {
 	tc, _, _ := setupLoyaltyLion(t)
 
@@ -458,6 +530,24 @@ func TestPointsExpiration(t *testing.T) {
 	}
 }
 
+func TestPointsExpirationOnTimeAdvance(t *testing.T) {
+	_, ac, _ := setupLoyaltyLion(t)
+
+	ac.AdvanceTime("744h").AssertStatus(200) // 31 days
+
+	// No API read in between: advancing the clock materializes the expiry
+	state := ac.GetState().JSONMap()
+	expiring := state["expiring_points"].(map[string]any)
+	if len(expiring) == 0 {
+		t.Fatal("expected seeded expiring points")
+	}
+	for id, ep := range expiring {
+		if ep.(map[string]any)["expired"] != true {
+			t.Errorf("expected expiring points %s to be expired after advance, got %v", id, ep)
+		}
+	}
+}
+
 // --- Fault Injection Tests ---
 
 func TestFaultInjectionRedemptionUnavailable(t *testing.T) {
@@ -560,6 +650,61 @@ func TestAdminReset(t *testing.T) {
 	}
 }
 
+func TestAdminTenantsIsolated(t *testing.T) {
+	tc, ac, _ := setupLoyaltyLion(t)
+
+	var tenant admin.Tenant
+	tc.Post("/admin/tenants", map[string]any{"name": "CI Job 1"}).AssertStatus(201).JSON(&tenant)
+	key, secret := tenant.Credentials["api_key"], tenant.Credentials["api_secret"]
+	if key == "" || secret == "" || key == "ll_test_key_alpha" {
+		t.Fatalf("expected fresh credentials, got %+v", tenant)
+	}
+
+	// The new merchant authenticates and starts with no customers
+	auth := map[string]string{"Authorization": basicAuth(key, secret)}
+	resp := llGet(tc, "/v2/customers", auth).AssertStatus(200)
+	if customers, _ := resp.JSONMap()["customers"].([]any); len(customers) != 0 {
+		t.Errorf("expected a new tenant to see no customers, got %d", len(customers))
+	}
+
+	var tenants []admin.Tenant
+	tc.Get("/admin/tenants").AssertStatus(200).JSON(&tenants)
+	if len(tenants) != 3 {
+		t.Errorf("expected 2 seeded tenants plus the new one, got %d", len(tenants))
+	}
+
+	// Explicit credentials must be unique
+	tc.Post("/admin/tenants", map[string]any{
+		"credentials": map[string]string{"api_key": key},
+	}).AssertStatus(400)
+
+	ac.Reset().AssertStatus(200)
+	llGet(tc, "/v2/customers", auth).AssertStatus(401)
+}
+
+func TestAdminDeleteTenant(t *testing.T) {
+	tc, _, _ := setupLoyaltyLion(t)
+
+	var tenant admin.Tenant
+	tc.Post("/admin/tenants", nil).AssertStatus(201).JSON(&tenant)
+
+	// The tenant's headers authenticate as it
+	llPost(tc, "/v2/customers", map[string]any{
+		"merchant_id": "c-1",
+		"email":       "tenant@example.com",
+	}, tenant.Headers).AssertStatus(201)
+
+	tc.Delete("/admin/tenants/" + tenant.ID).AssertStatus(200)
+	tc.Delete("/admin/tenants/" + tenant.ID).AssertStatus(404)
+	llGet(tc, "/v2/customers", tenant.Headers).AssertStatus(401)
+	state := tc.Get("/admin/state").AssertStatus(200).JSONMap()
+	for id, c := range state["customers"].(map[string]any) {
+		if c.(map[string]any)["email"] == "tenant@example.com" {
+			t.Errorf("expected the tenant's customers to be deleted, found %s", id)
+		}
+	}
+}
+
 func TestAdminGetState(t *testing.T) {
 	_, ac, _ := setupLoyaltyLion(t)
 	resp := ac.GetState()
@@ -613,3 +758,16 @@ func TestAdminLoadState(t *testing.T) {
 	resp = llGet(tc, "/v2/customers?email=custom@example.com", customAuth)
 	resp.AssertStatus(200)
 }
+
+func TestAdminStateSchemaVersion(t *testing.T) {
+	_, ac, _ := setupLoyaltyLion(t)
+
+	state := ac.GetState().AssertStatus(200).JSONMap()
+	if state["schema_version"] != float64(1) {
+		t.Errorf("expected schema_version 1, got %v", state["schema_version"])
+	}
+
+	ac.LoadState(map[string]any{"schema_version": 99}).
+		AssertStatus(400).
+		AssertBodyContains("newer than this twin supports")
+}
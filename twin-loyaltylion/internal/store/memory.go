@@ -3,9 +3,11 @@ package store
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/wondertwin-ai/wondertwin/twinkit/seedlint"
 	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 )
 
@@ -26,11 +28,14 @@ type MemoryStore struct {
 	claimedRewardCounter atomic.Int64
 	activityCounter      atomic.Int64
 	expiringCounter      atomic.Int64
+
+	tenantMu sync.Mutex // serializes CreateTenant's check-then-set on Merchants
 }
 
-// New creates a new MemoryStore.
+// New creates a new MemoryStore. Expiring points are materialized by the
+// clock (see ProcessExpiredPoints).
 func New() *MemoryStore {
-	return &MemoryStore{
+	s := &MemoryStore{
 		Merchants:      pkgstore.New[Merchant]("merchant"),
 		Customers:      pkgstore.New[Customer]("cust"),
 		Transactions:   pkgstore.New[PointsTransaction]("txn"),
@@ -40,6 +45,8 @@ func New() *MemoryStore {
 		ExpiringPoints: pkgstore.New[ExpiringPoints]("exp"),
 		Clock:          pkgstore.NewClock(),
 	}
+	s.Clock.Derive(s.ProcessExpiredPoints)
+	return s
 }
 
 // NextCustomerID returns the next auto-increment customer ID.
@@ -136,8 +143,9 @@ func (s *MemoryStore) GetClaimedRewardsByCustomer(customerID int, apiKey string)
 }
 
 // FindIdempotentClaim checks for a recent identical claim (same customer, reward_id, multiplier within 60s).
-func (s *MemoryStore) FindIdempotentClaim(customerID, rewardID, multiplier int, apiKey string, now time.Time) *ClaimedReward {
-	items := s.ClaimedRewards.Filter(func(_ string, cr ClaimedReward) bool {
+// Called from an UpdateTx fn, it sees the claims as tx has left them.
+func (s *MemoryStore) FindIdempotentClaim(tx *pkgstore.Tx, customerID, rewardID, multiplier int, apiKey string, now time.Time) *ClaimedReward {
+	items := s.ClaimedRewards.FilterTx(tx, func(_ string, cr ClaimedReward) bool {
 		if cr.CustomerID != customerID || cr.RewardID != rewardID || cr.Multiplier != multiplier || cr.APIKey != apiKey || cr.Refunded {
 			return false
 		}
@@ -153,7 +161,15 @@ func (s *MemoryStore) FindIdempotentClaim(customerID, rewardID, multiplier int,
 	return &items[0]
 }
 
+// RecordTransactionTx queues txn in tx under the next transaction ID, so
+// the ledger entry lands together with the balance change it records.
+func (s *MemoryStore) RecordTransactionTx(tx *pkgstore.Tx, txn PointsTransaction) {
+	txn.ID = s.NextTransactionID()
+	s.Transactions.SetTx(tx, fmt.Sprintf("%d", txn.ID), txn)
+}
+
 // ProcessExpiredPoints checks all expiring points and transitions expired ones.
+// It is registered with Clock.Derive, so handlers never call it directly.
 func (s *MemoryStore) ProcessExpiredPoints(now time.Time) {
 	ids, items := s.ExpiringPoints.FilterWithIDs(func(_ string, ep ExpiringPoints) bool {
 		if ep.Expired || ep.Amount <= 0 {
@@ -167,51 +183,60 @@ func (s *MemoryStore) ProcessExpiredPoints(now time.Time) {
 	})
 
 	for i, ep := range items {
-		// Mark as expired
-		ep.Expired = true
-		s.ExpiringPoints.Set(ids[i], ep)
-
-		// Update customer balance
-		c, ok := s.Customers.Get(CustomerKey(ep.CustomerID))
-		if !ok {
+		// Mark as expired, skipping entries a concurrent caller got to first
+		marked := false
+		s.ExpiringPoints.Update(ids[i], func(cur ExpiringPoints) (ExpiringPoints, error) {
+			if !cur.Expired {
+				cur.Expired = true
+				marked = true
+			}
+			return cur, nil
+		})
+		if !marked {
 			continue
 		}
-		expired := ep.Amount
-		if expired > c.PointsApproved {
-			expired = c.PointsApproved
-		}
-		c.PointsApproved -= expired
-		c.PointsExpired += expired
-		c.UpdatedAt = now.Format(time.RFC3339)
-		s.Customers.Set(CustomerKey(c.ID), c)
-
-		// Record transaction
-		txnID := s.NextTransactionID()
-		s.Transactions.Set(fmt.Sprintf("%d", txnID), PointsTransaction{
-			ID:         txnID,
-			CustomerID: ep.CustomerID,
-			Type:       "expire",
-			Amount:     expired,
-			Reason:     "Points expired",
-			Timestamp:  now.Format(time.RFC3339),
-			APIKey:     ep.APIKey,
+
+		// Update the customer's balance and record the transaction together.
+		tx := pkgstore.NewTx()
+		s.Transactions.JoinTx(tx)
+		s.Customers.UpdateTx(tx, CustomerKey(ep.CustomerID), func(c Customer) (Customer, error) {
+			expired := min(ep.Amount, c.PointsApproved)
+			c.PointsApproved -= expired
+			c.PointsExpired += expired
+			c.UpdatedAt = now.Format(time.RFC3339)
+			s.RecordTransactionTx(tx, PointsTransaction{
+				CustomerID: ep.CustomerID,
+				Type:       "expire",
+				Amount:     expired,
+				Reason:     "Points expired",
+				Timestamp:  now.Format(time.RFC3339),
+				APIKey:     ep.APIKey,
+			})
+			return c, nil
 		})
+		tx.Commit()
 	}
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 type stateSnapshot struct {
-	Merchants      map[string]Merchant         `json:"merchants"`
-	Customers      map[string]Customer         `json:"customers"`
+	SchemaVersion  int                          `json:"schema_version"`
+	Merchants      map[string]Merchant          `json:"merchants"`
+	Customers      map[string]Customer          `json:"customers"`
 	Transactions   map[string]PointsTransaction `json:"transactions"`
-	Rewards        map[string]Reward           `json:"rewards"`
-	ClaimedRewards map[string]ClaimedReward    `json:"claimed_rewards"`
-	Activities     map[string]Activity         `json:"activities"`
-	ExpiringPoints map[string]ExpiringPoints   `json:"expiring_points"`
+	Rewards        map[string]Reward            `json:"rewards"`
+	ClaimedRewards map[string]ClaimedReward     `json:"claimed_rewards"`
+	Activities     map[string]Activity          `json:"activities"`
+	ExpiringPoints map[string]ExpiringPoints    `json:"expiring_points"`
 }
 
 // Snapshot returns full state as JSON-serializable value.
 func (s *MemoryStore) Snapshot() any {
 	return stateSnapshot{
+		SchemaVersion:  stateSchema.Current(),
 		Merchants:      s.Merchants.Snapshot(),
 		Customers:      s.Customers.Snapshot(),
 		Transactions:   s.Transactions.Snapshot(),
@@ -224,6 +249,10 @@ func (s *MemoryStore) Snapshot() any {
 
 // LoadState loads state from JSON.
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap stateSnapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return err
@@ -254,6 +283,30 @@ func (s *MemoryStore) LoadState(data []byte) error {
 
 // Reset clears all state and reloads seed fixtures.
 func (s *MemoryStore) Reset() {
+	s.clear()
+	s.SeedDefaults()
+}
+
+// seedPresets are the named seed packs accepted by ResetWithSeed.
+var seedPresets = map[string]func(*MemoryStore){
+	"default": (*MemoryStore).SeedDefaults,
+	"empty":   func(*MemoryStore) {},
+}
+
+// ResetWithSeed clears all state and loads the named seed preset instead of
+// the default fixtures. Known presets are "default" and "empty".
+func (s *MemoryStore) ResetWithSeed(preset string) error {
+	seed, ok := seedPresets[preset]
+	if !ok {
+		return fmt.Errorf("unknown seed preset %q (known: default, empty)", preset)
+	}
+	s.clear()
+	seed(s)
+	return nil
+}
+
+// clear empties every resource and resets the ID counters and clock.
+func (s *MemoryStore) clear() {
 	s.Merchants.Reset()
 	s.Customers.Reset()
 	s.Transactions.Reset()
@@ -268,7 +321,61 @@ func (s *MemoryStore) Reset() {
 	s.claimedRewardCounter.Store(0)
 	s.activityCounter.Store(0)
 	s.expiringCounter.Store(0)
-	s.SeedDefaults()
+}
+
+// ResetResources clears only the named resources, keyed as in the state
+// snapshot. Unlike Reset it does not reload seed fixtures.
+func (s *MemoryStore) ResetResources(names []string) error {
+	return pkgstore.ResetNamed(names, map[string]func(){
+		"merchants": s.Merchants.Reset,
+		"customers": func() {
+			s.Customers.Reset()
+			s.customerCounter.Store(0)
+		},
+		"transactions": func() {
+			s.Transactions.Reset()
+			s.transactionCounter.Store(0)
+		},
+		"rewards": func() {
+			s.Rewards.Reset()
+			s.rewardCounter.Store(0)
+		},
+		"claimed_rewards": func() {
+			s.ClaimedRewards.Reset()
+			s.claimedRewardCounter.Store(0)
+		},
+		"activities": func() {
+			s.Activities.Reset()
+			s.activityCounter.Store(0)
+		},
+		"expiring_points": func() {
+			s.ExpiringPoints.Reset()
+			s.expiringCounter.Store(0)
+		},
+	})
+}
+
+// Collections exposes the record stores for NDJSON export and import.
+func (s *MemoryStore) Collections() map[string]pkgstore.Collection {
+	return map[string]pkgstore.Collection{
+		"merchants":       s.Merchants,
+		"customers":       s.Customers,
+		"transactions":    s.Transactions,
+		"rewards":         s.Rewards,
+		"claimed_rewards": s.ClaimedRewards,
+		"activities":      s.Activities,
+		"expiring_points": s.ExpiringPoints,
+	}
+}
+
+// SeedRelations declares the references between collections that
+// POST /admin/state/lint checks in a seed file.
+func (s *MemoryStore) SeedRelations() []seedlint.Relation {
+	return []seedlint.Relation{
+		{From: "transactions", Field: "customer_id", To: "customers"},
+		{From: "claimed_rewards", Field: "reward_id", To: "rewards"},
+		{From: "expiring_points", Field: "customer_id", To: "customers"},
+	}
 }
 
 // SeedDefaults populates the store with default fixture data.
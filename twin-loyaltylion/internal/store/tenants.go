@@ -0,0 +1,91 @@
+package store
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
+)
+
+// CreateTenant adds a merchant for POST /admin/tenants, so parallel test
+// jobs can each authenticate as their own merchant. Credentials are
+// "api_key" and "api_secret"; either is generated when omitted.
+func (s *MemoryStore) CreateTenant(req admin.TenantRequest) (admin.Tenant, error) {
+	for k := range req.Credentials {
+		if k != "api_key" && k != "api_secret" {
+			return admin.Tenant{}, fmt.Errorf("unknown credential %q (known: api_key, api_secret)", k)
+		}
+	}
+
+	s.tenantMu.Lock()
+	defer s.tenantMu.Unlock()
+
+	key := req.Credentials["api_key"]
+	if key == "" {
+		// Seeded or imported merchants may already hold a generated key
+		for {
+			key = "ll_test_key_" + s.Merchants.NextID()
+			if _, taken := s.Merchants.Get(key); !taken {
+				break
+			}
+		}
+	} else if _, taken := s.Merchants.Get(key); taken {
+		return admin.Tenant{}, fmt.Errorf("api_key %q already in use", key)
+	}
+	secret := req.Credentials["api_secret"]
+	if secret == "" {
+		secret = strings.Replace(key, "_key_", "_secret_", 1)
+		if secret == key {
+			secret = key + "_secret"
+		}
+	}
+
+	m := Merchant{APIKey: key, APISecret: secret, Name: req.Name}
+	s.Merchants.Set(key, m)
+	return merchantTenant(m), nil
+}
+
+// DeleteTenant removes a merchant along with the customers, rewards, and
+// activity recorded under its API key.
+func (s *MemoryStore) DeleteTenant(id string) error {
+	if !s.Merchants.Delete(id) {
+		return fmt.Errorf("no merchant with api_key %q", id)
+	}
+	deleteOwned(s.Customers, id, func(c Customer) string { return c.APIKey })
+	deleteOwned(s.Transactions, id, func(t PointsTransaction) string { return t.APIKey })
+	deleteOwned(s.Rewards, id, func(r Reward) string { return r.APIKey })
+	deleteOwned(s.ClaimedRewards, id, func(cr ClaimedReward) string { return cr.APIKey })
+	deleteOwned(s.Activities, id, func(a Activity) string { return a.APIKey })
+	deleteOwned(s.ExpiringPoints, id, func(e ExpiringPoints) string { return e.APIKey })
+	return nil
+}
+
+func deleteOwned[T any](st *pkgstore.Store[T], apiKey string, owner func(T) string) {
+	ids, _ := st.FilterWithIDs(func(_ string, item T) bool { return owner(item) == apiKey })
+	for _, id := range ids {
+		st.Delete(id)
+	}
+}
+
+// ListTenants returns every merchant and its credentials.
+func (s *MemoryStore) ListTenants() []admin.Tenant {
+	merchants := s.Merchants.List()
+	tenants := make([]admin.Tenant, 0, len(merchants))
+	for _, m := range merchants {
+		tenants = append(tenants, merchantTenant(m))
+	}
+	return tenants
+}
+
+func merchantTenant(m Merchant) admin.Tenant {
+	return admin.Tenant{
+		ID:          m.APIKey,
+		Name:        m.Name,
+		Credentials: map[string]string{"api_key": m.APIKey, "api_secret": m.APISecret},
+		Headers: map[string]string{
+			"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(m.APIKey+":"+m.APISecret)),
+		},
+	}
+}
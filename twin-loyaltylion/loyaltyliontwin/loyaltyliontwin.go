@@ -0,0 +1,59 @@
+// Package loyaltyliontwin wires twin-loyaltylion's store, API, and admin plane
+// onto a twincore.Twin, for the twin-loyaltylion binary and for hosts such as
+// twin-multi that run several twins in one process.
+package loyaltyliontwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-loyaltylion/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-loyaltylion/internal/store"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Description is what twin-loyaltylion reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-loyaltylion",
+	SDKTarget:    twincore.SDKTarget{Package: "loyaltylion", Version: "v2", APIVersion: "v2"},
+	DefaultPort:  8090,
+	Capabilities: []string{"clock", "quirks", "tenants"},
+}
+
+// Build mounts twin-loyaltylion on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+	memStore.SeedDefaults()
+
+	// Expire points as simulated time passes, before any handler reads them
+	twin.Router.Use(memStore.Clock.Middleware)
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, twin.Middleware())
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided (overrides defaults)
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	}
+
+	twin.Logger.Info("twin-loyaltylion ready",
+		"addr", cfg.Addr(),
+	)
+	return nil
+}
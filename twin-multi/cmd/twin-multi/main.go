@@ -0,0 +1,180 @@
+// twin-multi hosts several WonderTwin twins in one process, to save the
+// per-process overhead of running each twin on its own in small CI
+// containers. Each twin keeps its own state, admin plane, simulated clock,
+// and config; only the process is shared.
+//
+// A twin is mounted on its own port, where wt and SDKs address it exactly
+// as the standalone binary:
+//
+//	twin-multi --twin stripe --twin resend=4213
+//
+// or under a path prefix on the shared --port, for SDKs pointed at a base
+// URL such as http://localhost:4100/stripe:
+//
+//	twin-multi --port 4100 --twin stripe=/stripe --twin twilio=/twilio
+//
+// Per-twin seed files and webhook URLs are given as name=value.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wondertwin-ai/wondertwin/twin-clerk/clerktwin"
+	"github.com/wondertwin-ai/wondertwin/twin-logodev/logodevtwin"
+	"github.com/wondertwin-ai/wondertwin/twin-loyaltylion/loyaltyliontwin"
+	"github.com/wondertwin-ai/wondertwin/twin-posthog/posthogtwin"
+	"github.com/wondertwin-ai/wondertwin/twin-resend/resendtwin"
+	"github.com/wondertwin-ai/wondertwin/twin-smile/smiletwin"
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/stripetwin"
+	"github.com/wondertwin-ai/wondertwin/twin-twilio/twiliotwin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// builder is a twin twin-multi can host.
+type builder struct {
+	desc  twincore.Description
+	build func(*twincore.Twin) error
+}
+
+var twins = map[string]builder{
+	"clerk":       {clerktwin.Description, clerktwin.Build},
+	"logodev":     {logodevtwin.Description, logodevtwin.Build},
+	"loyaltylion": {loyaltyliontwin.Description, loyaltyliontwin.Build},
+	"posthog":     {posthogtwin.Description, posthogtwin.Build},
+	"resend":      {resendtwin.Description, resendtwin.Build},
+	"smile":       {smiletwin.Description, smiletwin.Build},
+	"stripe":      {stripetwin.Description, stripetwin.Build},
+	"twilio":      {twiliotwin.Description, twiliotwin.Build},
+}
+
+// mount is one --twin flag: a twin on port, or under prefix when set.
+type mount struct {
+	name   string
+	port   int
+	prefix string
+}
+
+func main() {
+	var mounts []mount
+	seedFiles := map[string]string{}
+	webhookURLs := map[string]string{}
+
+	flag.Func("twin", "Twin to host, as name (its default port), name=port, or name=/prefix on --port; repeatable", func(s string) error {
+		name, at, _ := strings.Cut(s, "=")
+		name = strings.TrimPrefix(name, "twin-")
+		b, ok := twins[name]
+		if !ok {
+			return fmt.Errorf("unknown twin %q; available: %s", name, strings.Join(twinNames(), ", "))
+		}
+		m := mount{name: name, port: b.desc.DefaultPort}
+		switch {
+		case strings.HasPrefix(at, "/"):
+			m.prefix = at
+		case at != "":
+			port, err := strconv.Atoi(at)
+			if err != nil || port <= 0 {
+				return fmt.Errorf("%s: expected a port or a /prefix, got %q", name, at)
+			}
+			m.port = port
+		}
+		for _, other := range mounts {
+			if other.name == name {
+				return fmt.Errorf("%s is already hosted; each twin can be hosted once", name)
+			}
+		}
+		mounts = append(mounts, m)
+		return nil
+	})
+	flag.Func("seed-file", "Seed file for a twin, as name=path; repeatable", namedValue(seedFiles))
+	flag.Func("webhook-url", "Webhook URL for a twin, as name=url; repeatable", namedValue(webhookURLs))
+	port := flag.Int("port", 4100, "Shared listen port of twins mounted under a path prefix")
+	verbose := flag.Bool("verbose", false, "Enable request/response logging")
+	captureBodies := flag.Bool("capture-bodies", false, "Record request bodies in the request logs for replay")
+	randSeed := flag.Uint64("rand-seed", 0, "Seed for reproducible generated codes and random faults (0 = random)")
+	adminReadOnly := flag.Bool("admin-readonly", false, "Reject admin requests that modify the twins; inspection endpoints keep working")
+	showVersion := flag.Bool("version", false, "Print the version and exit")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Printf("twin-multi %s\n", twincore.Version)
+		os.Exit(0)
+	}
+	if len(mounts) == 0 {
+		fmt.Fprintf(os.Stderr, "no twins to host; pass --twin for each, e.g. --twin stripe --twin resend=/resend\navailable: %s\n", strings.Join(twinNames(), ", "))
+		os.Exit(2)
+	}
+	for _, named := range []map[string]string{seedFiles, webhookURLs} {
+		for name := range named {
+			if !hosted(mounts, name) {
+				fmt.Fprintf(os.Stderr, "%s is not hosted; add --twin %s\n", name, name)
+				os.Exit(2)
+			}
+		}
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	group := twincore.NewGroup(*port, logger)
+	for _, m := range mounts {
+		b := twins[m.name]
+		cfg := twincore.DefaultConfig(b.desc)
+		cfg.Port = m.port
+		if m.prefix != "" {
+			cfg.Port = *port
+		}
+		cfg.SeedFile = seedFiles[m.name]
+		cfg.WebhookURL = webhookURLs[m.name]
+		cfg.Verbose = *verbose
+		cfg.CaptureBodies = *captureBodies
+		cfg.RandSeed = *randSeed
+		cfg.AdminReadOnly = *adminReadOnly
+
+		twin := twincore.New(cfg)
+		if err := b.build(twin); err != nil {
+			log.Fatalf("%s: %v", b.desc.Name, err)
+		}
+		if err := group.Add(twin, m.prefix); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := group.Serve(); err != nil {
+		log.Fatalf("server error: %v", err)
+	}
+}
+
+// namedValue parses a name=value flag into values.
+func namedValue(values map[string]string) func(string) error {
+	return func(s string) error {
+		name, value, ok := strings.Cut(s, "=")
+		if !ok || value == "" {
+			return fmt.Errorf("expected name=value, got %q", s)
+		}
+		values[strings.TrimPrefix(name, "twin-")] = value
+		return nil
+	}
+}
+
+func hosted(mounts []mount, name string) bool {
+	for _, m := range mounts {
+		if m.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func twinNames() []string {
+	names := make([]string, 0, len(twins))
+	for name := range twins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
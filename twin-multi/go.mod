@@ -0,0 +1,40 @@
+module github.com/wondertwin-ai/wondertwin/twin-multi
+
+go 1.25.7
+
+replace github.com/wondertwin-ai/wondertwin/twinkit => ../twinkit
+
+replace github.com/wondertwin-ai/wondertwin/adminclient => ../adminclient
+
+replace github.com/wondertwin-ai/wondertwin/twin-clerk => ../twin-clerk
+
+replace github.com/wondertwin-ai/wondertwin/twin-logodev => ../twin-logodev
+
+replace github.com/wondertwin-ai/wondertwin/twin-loyaltylion => ../twin-loyaltylion
+
+replace github.com/wondertwin-ai/wondertwin/twin-posthog => ../twin-posthog
+
+replace github.com/wondertwin-ai/wondertwin/twin-resend => ../twin-resend
+
+replace github.com/wondertwin-ai/wondertwin/twin-smile => ../twin-smile
+
+replace github.com/wondertwin-ai/wondertwin/twin-stripe => ../twin-stripe
+
+replace github.com/wondertwin-ai/wondertwin/twin-twilio => ../twin-twilio
+
+require (
+	github.com/wondertwin-ai/wondertwin/twin-clerk v0.0.0-00010101000000-000000000000
+	github.com/wondertwin-ai/wondertwin/twin-logodev v0.0.0-00010101000000-000000000000
+	github.com/wondertwin-ai/wondertwin/twin-loyaltylion v0.0.0-00010101000000-000000000000
+	github.com/wondertwin-ai/wondertwin/twin-posthog v0.0.0-00010101000000-000000000000
+	github.com/wondertwin-ai/wondertwin/twin-resend v0.0.0-00010101000000-000000000000
+	github.com/wondertwin-ai/wondertwin/twin-smile v0.0.0-00010101000000-000000000000
+	github.com/wondertwin-ai/wondertwin/twin-stripe v0.0.0-00010101000000-000000000000
+	github.com/wondertwin-ai/wondertwin/twin-twilio v0.0.0-00010101000000-000000000000
+	github.com/wondertwin-ai/wondertwin/twinkit v0.0.0
+)
+
+require (
+	github.com/go-chi/chi/v5 v5.2.5 // indirect
+	github.com/golang-jwt/jwt/v5 v5.3.0 // indirect
+)
@@ -53,22 +53,32 @@ func (s *MemoryStore) SetFeatureFlags(flags []FeatureFlag) {
 	}
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 // stateSnapshot is the JSON-serializable state for admin endpoints.
 type stateSnapshot struct {
-	Events       map[string]CapturedEvent `json:"events"`
-	FeatureFlags map[string]FeatureFlag   `json:"feature_flags"`
+	SchemaVersion int                      `json:"schema_version"`
+	Events        map[string]CapturedEvent `json:"events"`
+	FeatureFlags  map[string]FeatureFlag   `json:"feature_flags"`
 }
 
 // Snapshot returns the full state as a JSON-serializable value.
 func (s *MemoryStore) Snapshot() any {
 	return stateSnapshot{
-		Events:       s.Events.Snapshot(),
-		FeatureFlags: s.GetFeatureFlags(),
+		SchemaVersion: stateSchema.Current(),
+		Events:        s.Events.Snapshot(),
+		FeatureFlags:  s.GetFeatureFlags(),
 	}
 }
 
 // LoadState replaces the full state from a JSON body.
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap stateSnapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return err
@@ -90,3 +100,23 @@ func (s *MemoryStore) Reset() {
 	defer s.mu.Unlock()
 	s.FeatureFlags = make(map[string]FeatureFlag)
 }
+
+// ResetResources clears only the named resources, keyed as in the state snapshot.
+func (s *MemoryStore) ResetResources(names []string) error {
+	return pkgstore.ResetNamed(names, map[string]func(){
+		"events": s.Events.Reset,
+		"feature_flags": func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.FeatureFlags = make(map[string]FeatureFlag)
+		},
+	})
+}
+
+// Collections exposes the record stores for NDJSON export and import.
+// Feature flags are not record-backed and only round-trip via LoadState.
+func (s *MemoryStore) Collections() map[string]pkgstore.Collection {
+	return map[string]pkgstore.Collection{
+		"events": s.Events,
+	}
+}
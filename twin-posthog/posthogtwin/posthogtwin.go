@@ -0,0 +1,55 @@
+// Package posthogtwin wires twin-posthog's store, API, and admin plane
+// onto a twincore.Twin, for the twin-posthog binary and for hosts such as
+// twin-multi that run several twins in one process.
+package posthogtwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-posthog/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-posthog/internal/store"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Description is what twin-posthog reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-posthog",
+	SDKTarget:    twincore.SDKTarget{Package: "github.com/posthog/posthog-go", Version: "v0"},
+	DefaultPort:  4114,
+	Capabilities: []string{"clock", "quirks"},
+}
+
+// Build mounts twin-posthog on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, twin.Middleware())
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	}
+
+	twin.Logger.Info("twin-posthog ready",
+		"addr", cfg.Addr(),
+	)
+	return nil
+}
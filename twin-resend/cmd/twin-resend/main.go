@@ -8,48 +8,19 @@ package main
 
 import (
 	"log"
-	"os"
 
-	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twin-resend/resendtwin"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
-	"github.com/wondertwin-ai/wondertwin/twin-resend/internal/api"
-	"github.com/wondertwin-ai/wondertwin/twin-resend/internal/store"
 )
 
 func main() {
-	cfg := twincore.ParseFlags("twin-resend")
-	if cfg.Port == 0 {
-		cfg.Port = 4113
-	}
+	cfg := twincore.ParseFlagsWithDescription(resendtwin.Description)
 
 	twin := twincore.New(cfg)
-	memStore := store.New()
-
-	// API handlers
-	apiHandler := api.NewHandler(memStore, twin.Middleware())
-	apiHandler.Routes(twin.Router)
-
-	// Admin control plane
-	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
-	adminHandler.SetConfigProvider(twin)
-	adminHandler.Routes(twin.Router)
-
-	// Load seed data if provided
-	if cfg.SeedFile != "" {
-		data, err := os.ReadFile(cfg.SeedFile)
-		if err != nil {
-			log.Fatalf("failed to read seed file: %v", err)
-		}
-		if err := memStore.LoadState(data); err != nil {
-			log.Fatalf("failed to load seed data: %v", err)
-		}
-		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	if err := resendtwin.Build(twin); err != nil {
+		log.Fatal(err)
 	}
 
-	twin.Logger.Info("twin-resend ready",
-		"port", cfg.Port,
-	)
-
 	if err := twin.Serve(); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
@@ -8,3 +8,7 @@ require (
 )
 
 replace github.com/wondertwin-ai/wondertwin/twinkit => ../twinkit
+
+replace github.com/wondertwin-ai/wondertwin/adminclient => ../adminclient
+
+require github.com/wondertwin-ai/wondertwin/adminclient v0.1.0 // indirect
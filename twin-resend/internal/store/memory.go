@@ -20,20 +20,30 @@ func New() *MemoryStore {
 	}
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 // stateSnapshot is the JSON-serializable state for admin endpoints.
 type stateSnapshot struct {
-	Emails map[string]Email `json:"emails"`
+	SchemaVersion int              `json:"schema_version"`
+	Emails        map[string]Email `json:"emails"`
 }
 
 // Snapshot returns the full state as a JSON-serializable value.
 func (s *MemoryStore) Snapshot() any {
 	return stateSnapshot{
-		Emails: s.Emails.Snapshot(),
+		SchemaVersion: stateSchema.Current(),
+		Emails:        s.Emails.Snapshot(),
 	}
 }
 
 // LoadState replaces the full state from a JSON body.
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap stateSnapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return err
@@ -47,3 +57,17 @@ func (s *MemoryStore) Reset() {
 	s.Emails.Reset()
 	s.Clock.Reset()
 }
+
+// ResetResources clears only the named resources, keyed as in the state snapshot.
+func (s *MemoryStore) ResetResources(names []string) error {
+	return pkgstore.ResetNamed(names, map[string]func(){
+		"emails": s.Emails.Reset,
+	})
+}
+
+// Collections exposes the record stores for NDJSON export and import.
+func (s *MemoryStore) Collections() map[string]pkgstore.Collection {
+	return map[string]pkgstore.Collection{
+		"emails": s.Emails,
+	}
+}
@@ -0,0 +1,58 @@
+// Package resendtwin wires twin-resend's store, API, and admin plane
+// onto a twincore.Twin, for the twin-resend binary and for hosts such as
+// twin-multi that run several twins in one process.
+package resendtwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-resend/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-resend/internal/store"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Description is what twin-resend reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-resend",
+	SDKTarget:    twincore.SDKTarget{Package: "github.com/resend/resend-go", Version: "v2"},
+	DefaultPort:  4113,
+	Capabilities: []string{"clock", "quirks"},
+	Faults: []twincore.NamedFault{
+		{Name: "emails_rate_limited", Endpoint: "/emails", Description: "Sending is rate limited", Fault: twincore.FaultConfig{StatusCode: 429, Rate: 1.0}},
+	},
+}
+
+// Build mounts twin-resend on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, twin.Middleware())
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	}
+
+	twin.Logger.Info("twin-resend ready",
+		"addr", cfg.Addr(),
+	)
+	return nil
+}
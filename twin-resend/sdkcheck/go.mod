@@ -0,0 +1,13 @@
+module github.com/wondertwin-ai/wondertwin/twin-resend/sdkcheck
+
+go 1.25.7
+
+replace github.com/wondertwin-ai/wondertwin/twinkit => ../../twinkit
+
+replace github.com/wondertwin-ai/wondertwin/adminclient => ../../adminclient
+
+require (
+	github.com/resend/resend-go/v2 v2.13.0
+	github.com/wondertwin-ai/wondertwin/adminclient v0.1.0
+	github.com/wondertwin-ai/wondertwin/twinkit v0.0.0-00010101000000-000000000000
+)
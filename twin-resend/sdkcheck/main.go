@@ -0,0 +1,98 @@
+// Command sdkcheck drives twin-resend with resend-go, the SDK the twin
+// targets, for wt conformance --sdk.
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/resend/resend-go/v2"
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/twinkit/sdkcheck"
+)
+
+func main() {
+	sdkcheck.Main(
+		sdkcheck.Check{Name: "send and retrieve an email", Run: sendAndGet},
+		sdkcheck.Check{Name: "send a batch", Run: sendBatch},
+		sdkcheck.Check{Name: "rejected key is an error", Run: authError},
+	)
+}
+
+// client returns a resend-go client whose requests go to the twin.
+func client(env *sdkcheck.Env) (*resend.Client, error) {
+	c := resend.NewClient("re_sdkcheck")
+	base, err := url.Parse(env.URL + "/")
+	if err != nil {
+		return nil, err
+	}
+	c.BaseURL = base
+	return c, nil
+}
+
+func sendAndGet(env *sdkcheck.Env) error {
+	c, err := client(env)
+	if err != nil {
+		return err
+	}
+	sent, err := c.Emails.Send(&resend.SendEmailRequest{
+		From:    "SDK Check <sdkcheck@example.com>",
+		To:      []string{"user@example.com"},
+		Subject: "Hello from sdkcheck",
+		Html:    "<p>It works</p>",
+	})
+	if err != nil {
+		return fmt.Errorf("Emails.Send: %w", err)
+	}
+	if sent.Id == "" {
+		return fmt.Errorf("expected an email ID")
+	}
+
+	got, err := c.Emails.Get(sent.Id)
+	if err != nil {
+		return fmt.Errorf("Emails.Get: %w", err)
+	}
+	switch {
+	case got.Id != sent.Id:
+		return fmt.Errorf("expected ID %s, got %s", sent.Id, got.Id)
+	case got.Subject != "Hello from sdkcheck":
+		return fmt.Errorf("expected the subject back, got %q", got.Subject)
+	case !slices.Contains(got.To, "user@example.com"):
+		return fmt.Errorf("expected the recipient back, got %v", got.To)
+	}
+	return nil
+}
+
+func sendBatch(env *sdkcheck.Env) error {
+	c, err := client(env)
+	if err != nil {
+		return err
+	}
+	batch := []*resend.SendEmailRequest{
+		{From: "sdkcheck@example.com", To: []string{"a@example.com"}, Subject: "One", Text: "1"},
+		{From: "sdkcheck@example.com", To: []string{"b@example.com"}, Subject: "Two", Text: "2"},
+	}
+	sent, err := c.Batch.Send(batch)
+	if err != nil {
+		return fmt.Errorf("Batch.Send: %w", err)
+	}
+	if len(sent.Data) != len(batch) {
+		return fmt.Errorf("expected %d IDs, got %d", len(batch), len(sent.Data))
+	}
+	return nil
+}
+
+func authError(env *sdkcheck.Env) error {
+	c, err := client(env)
+	if err != nil {
+		return err
+	}
+	if err := env.Admin.InjectFault(env.Ctx, "/emails", adminclient.Fault{Preset: "auth_invalid"}); err != nil {
+		return fmt.Errorf("injecting the auth fault: %w", err)
+	}
+	if _, err := c.Emails.Send(&resend.SendEmailRequest{From: "sdkcheck@example.com", To: []string{"user@example.com"}, Subject: "Denied", Text: "x"}); err == nil {
+		return fmt.Errorf("expected Emails.Send to fail on a rejected key")
+	}
+	return nil
+}
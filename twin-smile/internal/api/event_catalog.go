@@ -0,0 +1,49 @@
+package api
+
+import (
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twin-smile/internal/store"
+)
+
+// EventCatalog lists the webhook topics the twin sends, each with an example
+// payload. Implements admin.EventTrigger.
+func (h *Handler) EventCatalog() []admin.EventType {
+	now := h.store.Clock.Now().Unix()
+	customer := store.Customer{
+		ID:              "cust_example",
+		Email:           "customer@example.com",
+		FirstName:       "Ada",
+		LastName:        "Lovelace",
+		PointsBalance:   1200,
+		Tier:            "silver",
+		PointsPerDollar: 100,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	txn := store.PointsTransaction{
+		ID:           "ptx_example",
+		CustomerID:   customer.ID,
+		PointsChange: 1200,
+		Description:  "Placed an order",
+		CreatedAt:    now,
+	}
+
+	return []admin.EventType{
+		{
+			Type:        "points_transaction/created",
+			Description: "Points were earned, redeemed, or refunded.",
+			Example:     map[string]any{"points_transaction": txn},
+		},
+		{
+			Type:        "customer/tier_changed",
+			Description: "A customer was promoted or demoted between VIP tiers.",
+			Example:     map[string]any{"customer": customer, "previous_tier": "member"},
+		},
+	}
+}
+
+// TriggerEvent sends a webhook carrying payload, as the action behind the
+// topic would. Implements admin.EventTrigger.
+func (h *Handler) TriggerEvent(eventType string, payload map[string]any) (any, error) {
+	return h.dispatcher.Enqueue(eventType, payload), nil
+}
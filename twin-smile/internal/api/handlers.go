@@ -88,6 +88,7 @@ func (h *Handler) RedeemPoints(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:      now,
 	}
 	h.store.Redemptions.Set(redID, redemption)
+	h.recordTransaction(r.Context(), req.CustomerID, -req.Points, "Points redeemed", redID)
 
 	twincore.JSON(w, http.StatusCreated, redemption)
 }
@@ -142,6 +143,7 @@ func (h *Handler) RefundPoints(w http.ResponseWriter, r *http.Request) {
 	// Update redemption status
 	redemption.Status = "refunded"
 	h.store.Redemptions.Set(req.RedemptionID, redemption)
+	h.recordTransaction(r.Context(), redemption.CustomerID, refundPoints, "Redemption refunded", req.RedemptionID)
 
 	twincore.JSON(w, http.StatusOK, redemption)
 }
@@ -0,0 +1,157 @@
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/testutil"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twinkit/webhook"
+	"github.com/wondertwin-ai/wondertwin/twin-smile/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-smile/internal/store"
+)
+
+func setupSmile(t *testing.T) (*testutil.TwinClient, *testutil.AdminClient, *webhook.Dispatcher) {
+	t.Helper()
+	memStore := store.New()
+	cfg := &twincore.Config{Name: "twin-smile-test"}
+	twin := twincore.New(cfg)
+	twin.Router.Use(memStore.Clock.Middleware)
+	dispatcher := webhook.NewDispatcher(webhook.Config{})
+	handler := api.NewHandler(memStore, dispatcher, twin.Middleware())
+	handler.Routes(twin.Router)
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.OnReset(dispatcher.Reset)
+	adminHandler.SetEventTrigger(handler)
+	adminHandler.Routes(twin.Router)
+	srv := httptest.NewServer(twin.Router)
+	t.Cleanup(srv.Close)
+	tc := testutil.NewTwinClient(t, srv)
+	ac := testutil.NewAdminClient(tc)
+
+	ac.LoadState(map[string]any{
+		"customers": map[string]any{
+			"cust_1": map[string]any{"id": "cust_1", "email": "ada@example.com", "points_balance": 500, "tier": "member", "points_per_dollar": 100},
+		},
+	}).AssertStatus(200)
+	return tc, ac, dispatcher
+}
+
+func eventTypes(d *webhook.Dispatcher) []string {
+	var types []string
+	for _, evt := range d.AllEvents() {
+		types = append(types, evt.Type)
+	}
+	return types
+}
+
+func TestPointsTransactionPromotesTier(t *testing.T) {
+	tc, _, d := setupSmile(t)
+
+	resp := tc.Post("/v1/points_transactions", map[string]any{
+		"customer_id":   "cust_1",
+		"points_change": 1200,
+		"description":   "Placed an order",
+	})
+	resp.AssertStatus(201)
+	if txn := resp.JSONMap(); txn["points_change"] != float64(1200) {
+		t.Errorf("expected points_change 1200, got %v", txn["points_change"])
+	}
+
+	c := tc.Get("/v1/customers/cust_1").AssertStatus(200).JSONMap()
+	if c["tier"] != "silver" || c["points_balance"] != float64(1700) {
+		t.Errorf("expected silver with 1700 points, got tier %v balance %v", c["tier"], c["points_balance"])
+	}
+
+	types := eventTypes(d)
+	if len(types) != 2 || types[0] != "points_transaction/created" || types[1] != "customer/tier_changed" {
+		t.Fatalf("expected points_transaction/created then customer/tier_changed, got %v", types)
+	}
+	if prev := d.AllEvents()[1].Payload["previous_tier"]; prev != "member" {
+		t.Errorf("expected previous_tier member, got %v", prev)
+	}
+}
+
+func TestTierDemotesWhenEarnedPointsAgeOut(t *testing.T) {
+	tc, ac, d := setupSmile(t)
+
+	tc.Post("/v1/points_transactions", map[string]any{"customer_id": "cust_1", "points_change": 5000}).AssertStatus(201)
+	if c := tc.Get("/v1/customers/cust_1").JSONMap(); c["tier"] != "gold" {
+		t.Fatalf("expected gold, got %v", c["tier"])
+	}
+
+	ac.AdvanceTime("8784h").AssertStatus(200) // 366 days
+
+	if c := tc.Get("/v1/customers/cust_1").JSONMap(); c["tier"] != "member" {
+		t.Errorf("expected demotion to member after a year, got %v", c["tier"])
+	}
+	types := eventTypes(d)
+	if last := types[len(types)-1]; last != "customer/tier_changed" || len(types) != 3 {
+		t.Errorf("expected a second tier_changed event, got %v", types)
+	}
+}
+
+func TestRedemptionRecordsTransactionWithoutPromotion(t *testing.T) {
+	tc, _, d := setupSmile(t)
+
+	tc.Post("/v1/points/redeem", map[string]any{"customer_id": "cust_1", "points": 200}).AssertStatus(201)
+
+	txns := tc.Get("/v1/points_transactions?customer_id=cust_1").AssertStatus(200).JSONMap()["points_transactions"].([]any)
+	if len(txns) != 1 || txns[0].(map[string]any)["points_change"] != float64(-200) {
+		t.Fatalf("expected one -200 transaction, got %v", txns)
+	}
+	if types := eventTypes(d); len(types) != 1 || types[0] != "points_transaction/created" {
+		t.Errorf("expected only points_transaction/created, got %v", types)
+	}
+}
+
+func TestPointsTransactionValidation(t *testing.T) {
+	tc, _, _ := setupSmile(t)
+
+	tc.Post("/v1/points_transactions", map[string]any{"customer_id": "cust_1"}).AssertStatus(422)
+	tc.Post("/v1/points_transactions", map[string]any{"customer_id": "cust_1", "points_change": -501}).AssertStatus(422)
+	tc.Post("/v1/points_transactions", map[string]any{"customer_id": "cust_404", "points_change": 10}).AssertStatus(404)
+}
+
+func TestSeededTiersReplaceDefaults(t *testing.T) {
+	tc, ac, _ := setupSmile(t)
+
+	ac.LoadState(map[string]any{
+		"tiers": map[string]any{
+			"bronze": map[string]any{"id": "bronze", "name": "Bronze", "min_points": 0},
+			"elite":  map[string]any{"id": "elite", "name": "Elite", "min_points": 100},
+		},
+	}).AssertStatus(200)
+
+	tiers := tc.Get("/v1/tiers").AssertStatus(200).JSONMap()["tiers"].([]any)
+	if len(tiers) != 2 || tiers[1].(map[string]any)["id"] != "elite" {
+		t.Fatalf("expected seeded tiers, got %v", tiers)
+	}
+
+	tc.Post("/v1/points_transactions", map[string]any{"customer_id": "cust_1", "points_change": 150}).AssertStatus(201)
+	if c := tc.Get("/v1/customers/cust_1").JSONMap(); c["tier"] != "elite" {
+		t.Errorf("expected elite, got %v", c["tier"])
+	}
+}
+
+func TestTriggerEvent(t *testing.T) {
+	_, ac, d := setupSmile(t)
+
+	resp := ac.TriggerEvent("customer/tier_changed", map[string]any{
+		"customer":      map[string]any{"id": "cust_1", "tier": "gold"},
+		"previous_tier": "silver",
+	})
+	resp.AssertStatus(200)
+
+	events := d.AllEvents()
+	if len(events) != 1 || events[0].Type != "customer/tier_changed" {
+		t.Fatalf("expected one customer/tier_changed webhook, got %v", eventTypes(d))
+	}
+	customer := events[0].Payload["customer"].(map[string]any)
+	if customer["id"] != "cust_1" || customer["tier"] != "gold" || customer["email"] != "customer@example.com" || events[0].Payload["previous_tier"] != "silver" {
+		t.Errorf("expected the example payload with overrides, got %v", events[0].Payload)
+	}
+
+	ac.TriggerEvent("customer/created", nil).AssertStatus(404)
+}
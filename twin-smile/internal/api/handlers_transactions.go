@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twin-smile/internal/store"
+)
+
+// errInsufficientPoints aborts a customer update whose balance cannot cover a debit.
+var errInsufficientPoints = errors.New("insufficient points balance")
+
+// CreatePointsTransaction handles POST /v1/points_transactions. A positive
+// points_change awards points, which count toward the customer's VIP tier;
+// a negative one deducts them.
+func (h *Handler) CreatePointsTransaction(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CustomerID   string `json:"customer_id"`
+		PointsChange int64  `json:"points_change"`
+		Description  string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if req.CustomerID == "" {
+		twincore.Error(w, http.StatusUnprocessableEntity, "customer_id is required")
+		return
+	}
+	if req.PointsChange == 0 {
+		twincore.Error(w, http.StatusUnprocessableEntity, "points_change must be a non-zero integer")
+		return
+	}
+
+	now := h.store.Clock.Now().Unix()
+	_, err := h.store.Customers.Update(req.CustomerID, func(c store.Customer) (store.Customer, error) {
+		if c.PointsBalance+req.PointsChange < 0 {
+			return c, errInsufficientPoints
+		}
+		c.PointsBalance += req.PointsChange
+		c.UpdatedAt = now
+		return c, nil
+	})
+	switch {
+	case errors.Is(err, errInsufficientPoints):
+		twincore.Error(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	case err != nil:
+		twincore.Error(w, http.StatusNotFound, "customer not found")
+		return
+	}
+
+	txn := h.recordTransaction(r.Context(), req.CustomerID, req.PointsChange, req.Description, "")
+
+	// Promote the customer now rather than on the next request, so the
+	// tier_changed webhook follows its points_transaction.
+	h.store.Clock.Materialize()
+
+	twincore.JSON(w, http.StatusCreated, txn)
+}
+
+// ListPointsTransactions handles GET /v1/points_transactions with an
+// optional ?customer_id= filter.
+func (h *Handler) ListPointsTransactions(w http.ResponseWriter, r *http.Request) {
+	customerID := r.URL.Query().Get("customer_id")
+	txns := h.store.PointsTransactions.Filter(func(_ string, t store.PointsTransaction) bool {
+		return customerID == "" || t.CustomerID == customerID
+	})
+	if txns == nil {
+		txns = []store.PointsTransaction{}
+	}
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"points_transactions": txns,
+	})
+}
+
+// ListTiers handles GET /v1/tiers.
+func (h *Handler) ListTiers(w http.ResponseWriter, r *http.Request) {
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"tiers": h.store.TierDefinitions(),
+	})
+}
+
+// recordTransaction stores a points transaction and sends the
+// points_transaction/created webhook, correlated with the request ctx
+// belongs to. The balance must already be updated.
+func (h *Handler) recordTransaction(ctx context.Context, customerID string, change int64, description, redemptionID string) store.PointsTransaction {
+	txn := store.PointsTransaction{
+		ID:           h.store.PointsTransactions.NextID(),
+		CustomerID:   customerID,
+		PointsChange: change,
+		Description:  description,
+		RedemptionID: redemptionID,
+		CreatedAt:    h.store.Clock.Now().Unix(),
+	}
+	h.store.PointsTransactions.Set(txn.ID, txn)
+	h.dispatcher.EnqueueContext(ctx, "points_transaction/created", map[string]any{
+		"points_transaction": txn,
+	})
+	return txn
+}
+
+// progressTiers is the clock's derived-state function for VIP tiers. It
+// sends customer/tier_changed for every promotion and demotion.
+func (h *Handler) progressTiers(now time.Time) {
+	for _, change := range h.store.ProgressTiers(now) {
+		h.dispatcher.Enqueue("customer/tier_changed", map[string]any{
+			"customer":      change.Customer,
+			"previous_tier": change.PreviousTier,
+		})
+	}
+}
@@ -4,18 +4,23 @@ package api
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twinkit/webhook"
 	"github.com/wondertwin-ai/wondertwin/twin-smile/internal/store"
 )
 
 // Handler holds all API handler state.
 type Handler struct {
-	store *store.MemoryStore
-	mw    *twincore.Middleware
+	store      *store.MemoryStore
+	dispatcher *webhook.Dispatcher
+	mw         *twincore.Middleware
 }
 
-// NewHandler creates a new API handler.
-func NewHandler(s *store.MemoryStore, mw *twincore.Middleware) *Handler {
-	return &Handler{store: s, mw: mw}
+// NewHandler creates a new API handler. It registers VIP tier progression
+// with the store's clock, so tiers follow simulated time.
+func NewHandler(s *store.MemoryStore, d *webhook.Dispatcher, mw *twincore.Middleware) *Handler {
+	h := &Handler{store: s, dispatcher: d, mw: mw}
+	s.Clock.Derive(h.progressTiers)
+	return h
 }
 
 // Routes mounts the Smile.io v1 API routes.
@@ -29,5 +34,10 @@ func (h *Handler) Routes(r chi.Router) {
 		// Points
 		r.Post("/points/redeem", h.RedeemPoints)
 		r.Post("/points/refund", h.RefundPoints)
+		r.Post("/points_transactions", h.CreatePointsTransaction)
+		r.Get("/points_transactions", h.ListPointsTransactions)
+
+		// VIP tiers
+		r.Get("/tiers", h.ListTiers)
 	})
 }
@@ -2,42 +2,76 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
+	"sort"
+	"time"
 
+	"github.com/wondertwin-ai/wondertwin/twinkit/seedlint"
 	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 )
 
+// TierWindow is the milestone period for VIP tiers: only points earned in
+// the trailing year count toward a customer's tier.
+const TierWindow = 365 * 24 * time.Hour
+
+// DefaultTiers are used when no tiers are seeded.
+var DefaultTiers = []Tier{
+	{ID: "member", Name: "Member", MinPoints: 0},
+	{ID: "silver", Name: "Silver", MinPoints: 1000},
+	{ID: "gold", Name: "Gold", MinPoints: 5000},
+	{ID: "vip", Name: "VIP", MinPoints: 10000},
+}
+
 // MemoryStore holds all Smile.io twin state in memory.
 type MemoryStore struct {
-	Customers   *pkgstore.Store[Customer]
-	Redemptions *pkgstore.Store[Redemption]
-	Clock       *pkgstore.Clock
+	Customers          *pkgstore.Store[Customer]
+	Redemptions        *pkgstore.Store[Redemption]
+	PointsTransactions *pkgstore.Store[PointsTransaction]
+	Tiers              *pkgstore.Store[Tier] // keyed by tier ID
+	Clock              *pkgstore.Clock
 }
 
 // New creates a new MemoryStore with empty state.
 func New() *MemoryStore {
 	return &MemoryStore{
-		Customers:   pkgstore.New[Customer]("cust"),
-		Redemptions: pkgstore.New[Redemption]("red"),
-		Clock:       pkgstore.NewClock(),
+		Customers:          pkgstore.New[Customer]("cust"),
+		Redemptions:        pkgstore.New[Redemption]("red"),
+		PointsTransactions: pkgstore.New[PointsTransaction]("ptx"),
+		Tiers:              pkgstore.New[Tier]("tier"),
+		Clock:              pkgstore.NewClock(),
 	}
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 // stateSnapshot is the JSON-serializable state for admin endpoints.
 type stateSnapshot struct {
-	Customers   map[string]Customer   `json:"customers"`
-	Redemptions map[string]Redemption `json:"redemptions"`
+	SchemaVersion      int                          `json:"schema_version"`
+	Customers          map[string]Customer          `json:"customers"`
+	Redemptions        map[string]Redemption        `json:"redemptions"`
+	PointsTransactions map[string]PointsTransaction `json:"points_transactions"`
+	Tiers              map[string]Tier              `json:"tiers"`
 }
 
 // Snapshot returns the full state as a JSON-serializable value.
 func (s *MemoryStore) Snapshot() any {
 	return stateSnapshot{
-		Customers:   s.Customers.Snapshot(),
-		Redemptions: s.Redemptions.Snapshot(),
+		SchemaVersion:      stateSchema.Current(),
+		Customers:          s.Customers.Snapshot(),
+		Redemptions:        s.Redemptions.Snapshot(),
+		PointsTransactions: s.PointsTransactions.Snapshot(),
+		Tiers:              s.Tiers.Snapshot(),
 	}
 }
 
 // LoadState replaces the full state from a JSON body.
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap stateSnapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return err
@@ -48,6 +82,12 @@ func (s *MemoryStore) LoadState(data []byte) error {
 	if snap.Redemptions != nil {
 		s.Redemptions.LoadSnapshot(snap.Redemptions)
 	}
+	if snap.PointsTransactions != nil {
+		s.PointsTransactions.LoadSnapshot(snap.PointsTransactions)
+	}
+	if snap.Tiers != nil {
+		s.Tiers.LoadSnapshot(snap.Tiers)
+	}
 	return nil
 }
 
@@ -55,9 +95,42 @@ func (s *MemoryStore) LoadState(data []byte) error {
 func (s *MemoryStore) Reset() {
 	s.Customers.Reset()
 	s.Redemptions.Reset()
+	s.PointsTransactions.Reset()
+	s.Tiers.Reset()
 	s.Clock.Reset()
 }
 
+// ResetResources clears only the named resources, keyed as in the state snapshot.
+func (s *MemoryStore) ResetResources(names []string) error {
+	return pkgstore.ResetNamed(names, map[string]func(){
+		"customers":           s.Customers.Reset,
+		"redemptions":         s.Redemptions.Reset,
+		"points_transactions": s.PointsTransactions.Reset,
+		"tiers":               s.Tiers.Reset,
+	})
+}
+
+// Collections exposes the record stores for NDJSON export and import.
+func (s *MemoryStore) Collections() map[string]pkgstore.Collection {
+	return map[string]pkgstore.Collection{
+		"customers":           s.Customers,
+		"redemptions":         s.Redemptions,
+		"points_transactions": s.PointsTransactions,
+		"tiers":               s.Tiers,
+	}
+}
+
+// SeedRelations declares the references between collections that
+// POST /admin/state/lint checks in a seed file.
+func (s *MemoryStore) SeedRelations() []seedlint.Relation {
+	return []seedlint.Relation{
+		{From: "customers", Field: "tier", To: "tiers", Optional: true},
+		{From: "redemptions", Field: "customer_id", To: "customers"},
+		{From: "points_transactions", Field: "customer_id", To: "customers"},
+		{From: "points_transactions", Field: "redemption_id", To: "redemptions", Optional: true},
+	}
+}
+
 // FindRedemptionByIdempotencyKey returns the first redemption matching the given key, if any.
 func (s *MemoryStore) FindRedemptionByIdempotencyKey(key string) *Redemption {
 	if key == "" {
@@ -71,3 +144,80 @@ func (s *MemoryStore) FindRedemptionByIdempotencyKey(key string) *Redemption {
 	}
 	return &items[0]
 }
+
+// TierDefinitions returns the VIP tiers ordered by MinPoints, falling back
+// to DefaultTiers when none are seeded.
+func (s *MemoryStore) TierDefinitions() []Tier {
+	tiers := s.Tiers.List()
+	if len(tiers) == 0 {
+		tiers = append([]Tier(nil), DefaultTiers...)
+	}
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].MinPoints < tiers[j].MinPoints })
+	return tiers
+}
+
+// TierChange records a customer moving between VIP tiers.
+type TierChange struct {
+	Customer     Customer
+	PreviousTier string
+}
+
+// errTierUnchanged leaves a customer already in its tier unwritten.
+var errTierUnchanged = errors.New("tier unchanged")
+
+// ProgressTiers moves customers into the tier matching the points they
+// earned within TierWindow of now, promoting as thresholds are crossed and
+// demoting as earned points age out of the window. Only customers with at
+// least one earning transaction are evaluated, so a seeded tier holds until
+// the customer earns points. It returns the changes in customer order.
+func (s *MemoryStore) ProgressTiers(now time.Time) []TierChange {
+	since, until := now.Add(-TierWindow).Unix(), now.Unix()
+	earned := make(map[string]int64)
+	var order []string
+	s.PointsTransactions.Filter(func(_ string, t PointsTransaction) bool {
+		if t.PointsChange <= 0 || t.RedemptionID != "" {
+			return false
+		}
+		if _, seen := earned[t.CustomerID]; !seen {
+			earned[t.CustomerID] = 0
+			order = append(order, t.CustomerID)
+		}
+		if t.CreatedAt > since && t.CreatedAt <= until {
+			earned[t.CustomerID] += t.PointsChange
+		}
+		return false
+	})
+
+	tiers := s.TierDefinitions()
+	var changes []TierChange
+	for _, id := range order {
+		tier := tiers[0].ID
+		for _, t := range tiers {
+			if earned[id] >= t.MinPoints {
+				tier = t.ID
+			}
+		}
+
+		// Only customers whose tier changes are written, so a run that
+		// changes nothing leaves the store (and its write hooks) alone.
+		current, ok := s.Customers.Get(id)
+		if !ok || current.Tier == tier {
+			continue
+		}
+		var previous string
+		c, err := s.Customers.Update(id, func(c Customer) (Customer, error) {
+			if c.Tier == tier {
+				return c, errTierUnchanged // changed since the Get
+			}
+			previous = c.Tier
+			c.Tier = tier
+			c.UpdatedAt = until
+			return c, nil
+		})
+		if err != nil {
+			continue
+		}
+		changes = append(changes, TierChange{Customer: c, PreviousTier: previous})
+	}
+	return changes
+}
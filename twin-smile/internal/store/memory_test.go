@@ -0,0 +1,33 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgressTiersWritesOnlyChanges(t *testing.T) {
+	s := New()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.Customers.Set("cust_1", Customer{ID: "cust_1", Tier: "member", UpdatedAt: 1})
+	s.Customers.Set("cust_2", Customer{ID: "cust_2", Tier: "member", UpdatedAt: 1})
+	s.PointsTransactions.Set("ptx_1", PointsTransaction{ID: "ptx_1", CustomerID: "cust_1", PointsChange: 100, CreatedAt: now.Unix()})
+	s.PointsTransactions.Set("ptx_2", PointsTransaction{ID: "ptx_2", CustomerID: "cust_2", PointsChange: 1500, CreatedAt: now.Unix()})
+
+	changes := s.ProgressTiers(now)
+	if len(changes) != 1 || changes[0].Customer.ID != "cust_2" || changes[0].Customer.Tier != "silver" || changes[0].PreviousTier != "member" {
+		t.Fatalf("expected only cust_2 promoted to silver, got %+v", changes)
+	}
+	if got := s.Customers.Stats().Ops["Update"].Count; got != 1 {
+		t.Errorf("expected 1 customer update, got %d", got)
+	}
+	if c, _ := s.Customers.Get("cust_1"); c.UpdatedAt != 1 {
+		t.Errorf("expected cust_1 left alone, got %+v", c)
+	}
+
+	if changes := s.ProgressTiers(now); len(changes) != 0 {
+		t.Errorf("expected no changes on a second run, got %+v", changes)
+	}
+	if got := s.Customers.Stats().Ops["Update"].Count; got != 1 {
+		t.Errorf("expected no customer updates on a second run, got %d", got)
+	}
+}
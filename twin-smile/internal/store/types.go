@@ -7,7 +7,7 @@ type Customer struct {
 	FirstName       string            `json:"first_name"`
 	LastName        string            `json:"last_name"`
 	PointsBalance   int64             `json:"points_balance"`
-	Tier            string            `json:"tier"`             // "member", "silver", "gold", "vip"
+	Tier            string            `json:"tier"`             // tier ID, e.g. "member", "silver", "gold", "vip"
 	PointsPerDollar float64           `json:"points_per_dollar"` // conversion rate
 	Metadata        map[string]string `json:"metadata,omitempty"`
 	CreatedAt       int64             `json:"created_at"`
@@ -24,3 +24,23 @@ type Redemption struct {
 	IdempotencyKey string `json:"idempotency_key,omitempty"`
 	CreatedAt      int64  `json:"created_at"`
 }
+
+// PointsTransaction records a change to a customer's points balance.
+// Earned points (positive changes not tied to a redemption) count toward
+// VIP tier progression.
+type PointsTransaction struct {
+	ID           string `json:"id"`
+	CustomerID   string `json:"customer_id"`
+	PointsChange int64  `json:"points_change"`
+	Description  string `json:"description,omitempty"`
+	RedemptionID string `json:"redemption_id,omitempty"` // set for redemptions and their refunds
+	CreatedAt    int64  `json:"created_at"`
+}
+
+// Tier is a VIP tier. A customer belongs to the highest tier whose
+// MinPoints they earned within the trailing TierWindow.
+type Tier struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MinPoints int64  `json:"min_points"`
+}
@@ -0,0 +1,33 @@
+// Package webhook implements webhook signing for the Smile.io twin.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// SignatureHeader carries the base64-encoded HMAC-SHA256 of the raw
+// request body, keyed with the webhook secret.
+const SignatureHeader = "X-Smile-Signature"
+
+// SmileSigner signs webhook deliveries with HMAC-SHA256.
+type SmileSigner struct{}
+
+// NewSmileSigner creates a new Smile.io webhook signer.
+func NewSmileSigner() *SmileSigner {
+	return &SmileSigner{}
+}
+
+// Sign produces the signature header.
+// Implements pkg/webhook.Signer interface.
+func (s *SmileSigner) Sign(payload []byte, secret string) map[string]string {
+	return map[string]string{SignatureHeader: ComputeSignature(payload, secret)}
+}
+
+// ComputeSignature returns the base64-encoded HMAC-SHA256 of payload.
+func ComputeSignature(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,83 @@
+// Package smiletwin wires twin-smile's store, webhooks, API, and admin
+// plane onto a twincore.Twin, for the twin-smile binary and for hosts such
+// as twin-multi that run several twins in one process.
+package smiletwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-smile/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-smile/internal/store"
+	smilewh "github.com/wondertwin-ai/wondertwin/twin-smile/internal/webhook"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	pkgwebhook "github.com/wondertwin-ai/wondertwin/twinkit/webhook"
+)
+
+// Description is what twin-smile reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-smile",
+	SDKTarget:    twincore.SDKTarget{Package: "smile.io", Version: "v1", APIVersion: "v1"},
+	DefaultPort:  8087,
+	Capabilities: []string{"webhooks", "clock", "quirks"},
+}
+
+// Build mounts twin-smile on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+
+	webhookSecret := os.Getenv("SMILE_WEBHOOK_SECRET")
+	if webhookSecret == "" {
+		webhookSecret = "smile_sim_test_secret"
+	}
+
+	dispatcher := pkgwebhook.NewDispatcher(pkgwebhook.Config{
+		URL:         cfg.WebhookURL,
+		Secret:      webhookSecret,
+		Signer:      smilewh.NewSmileSigner(),
+		Logger:      twin.Logger,
+		EventPrefix: "evt",
+		AutoDeliver: cfg.WebhookURL != "",
+		Guarantee:   pkgwebhook.Guarantee(cfg.WebhookDelivery),
+		Clock:       memStore.Clock.Now,
+		Faults:      twin.Middleware().Faults,
+	})
+
+	// Re-evaluate VIP tiers as simulated time passes, before any handler reads them
+	twin.Router.Use(memStore.Clock.Middleware)
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, dispatcher, twin.Middleware())
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetFlusher(dispatcher)
+	adminHandler.SetWebhookInspector(dispatcher)
+	adminHandler.SetDeadLetterQueue(dispatcher)
+	adminHandler.SetEventTrigger(apiHandler)
+	adminHandler.OnReset(dispatcher.Reset)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	}
+
+	twin.Logger.Info("twin-smile ready",
+		"addr", cfg.Addr(),
+		"webhook_url", cfg.WebhookURL,
+	)
+	return nil
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
+)
+
+// EventCatalog lists the event types the twin emits, each with an example
+// object in the shape its data.object takes. Implements admin.EventTrigger.
+func (h *Handler) EventCatalog() []admin.EventType {
+	now := h.store.Clock.Now().Unix()
+
+	charge := store.Charge{
+		ID:                 "ch_example",
+		Object:             "charge",
+		Amount:             2000,
+		AmountCaptured:     2000,
+		BalanceTransaction: "txn_example",
+		Captured:           true,
+		Currency:           "usd",
+		Paid:               true,
+		Status:             "succeeded",
+		Created:            now,
+	}
+	refunded := charge
+	refunded.AmountRefunded, refunded.Refunded = charge.Amount, true
+
+	refund := store.Refund{
+		ID:                 "re_example",
+		Object:             "refund",
+		Amount:             2000,
+		BalanceTransaction: "txn_example",
+		Charge:             charge.ID,
+		Currency:           "usd",
+		Status:             "succeeded",
+		Created:            now,
+	}
+
+	dispute := store.Dispute{
+		ID:                  "dp_example",
+		Object:              "dispute",
+		Amount:              2000,
+		BalanceTransactions: []string{"txn_example"},
+		Charge:              charge.ID,
+		Currency:            "usd",
+		Evidence:            map[string]string{},
+		EvidenceDetails:     store.EvidenceDetails{DueBy: now + disputeResponseWindow},
+		Reason:              "fraudulent",
+		Status:              store.DisputeStatusNeedsResponse,
+		Created:             now,
+	}
+	underReview := dispute
+	underReview.Evidence = map[string]string{"uncategorized_text": "Customer signed for the delivery."}
+	underReview.EvidenceDetails.HasEvidence, underReview.EvidenceDetails.SubmissionCount = true, 1
+	underReview.Status = store.DisputeStatusUnderReview
+	won := underReview
+	won.Status = store.DisputeStatusWon
+
+	payout := store.Payout{
+		ID:          "po_example",
+		Object:      "payout",
+		Amount:      5000,
+		Currency:    "usd",
+		ArrivalDate: now + 86400*2,
+		Destination: "ba_example",
+		Method:      "standard",
+		Status:      store.PayoutStatusPending,
+		Type:        "bank_account",
+		Created:     now,
+	}
+	inTransit, paid, failed, canceled := payout, payout, payout, payout
+	inTransit.Status = store.PayoutStatusInTransit
+	paid.Status = store.PayoutStatusPaid
+	failed.Status, failed.FailureCode, failed.FailureMessage = store.PayoutStatusFailed, "account_closed", "The bank account has been closed."
+	canceled.Status = store.PayoutStatusCanceled
+
+	transfer := store.Transfer{
+		ID:                 "tr_example",
+		Object:             "transfer",
+		Amount:             1000,
+		Currency:           "usd",
+		Destination:        "acct_example",
+		DestinationPayment: "py_example",
+		Created:            now,
+	}
+
+	account := store.Account{
+		ID:               "acct_example",
+		Object:           "account",
+		Type:             "express",
+		Country:          "US",
+		DefaultCurrency:  "usd",
+		ChargesEnabled:   true,
+		PayoutsEnabled:   true,
+		DetailsSubmitted: true,
+		Capabilities:     map[string]string{"card_payments": store.CapabilityActive, "transfers": store.CapabilityActive},
+		Requirements:     &store.Requirements{CurrentlyDue: []string{}, EventuallyDue: []string{}, PastDue: []string{}},
+		Created:          now,
+	}
+
+	return []admin.EventType{
+		{Type: "account.updated", Description: "A connected account's details, capabilities, or requirements changed.", Example: accountToMap(account)},
+		{Type: "charge.succeeded", Description: "A card was charged.", Example: chargeToMap(charge)},
+		{Type: "charge.refunded", Description: "A charge was refunded, in full or in part.", Example: chargeToMap(refunded)},
+		{Type: "charge.dispute.created", Description: "A cardholder disputed a charge.", Example: disputeToMap(dispute)},
+		{Type: "charge.dispute.funds_withdrawn", Description: "The disputed amount and fee were withdrawn from the balance.", Example: disputeToMap(dispute)},
+		{Type: "charge.dispute.updated", Description: "Evidence was added to a dispute.", Example: disputeToMap(underReview)},
+		{Type: "charge.dispute.closed", Description: "A dispute was won or lost.", Example: disputeToMap(won)},
+		{Type: "charge.dispute.funds_reinstated", Description: "A won dispute's amount was returned to the balance.", Example: disputeToMap(won)},
+		{Type: "payout.created", Description: "A payout was created.", Example: payoutToMap(payout)},
+		{Type: "payout.updated", Description: "A payout moved to in_transit.", Example: payoutToMap(inTransit)},
+		{Type: "payout.paid", Description: "A payout arrived in the bank account.", Example: payoutToMap(paid)},
+		{Type: "payout.failed", Description: "A payout could not be paid.", Example: payoutToMap(failed)},
+		{Type: "payout.canceled", Description: "A pending payout was canceled.", Example: payoutToMap(canceled)},
+		{Type: "refund.created", Description: "A refund was created.", Example: refundToMap(refund)},
+		{Type: "transfer.created", Description: "A transfer to a connected account was created.", Example: transferToMap(transfer)},
+		{Type: "transfer.paid", Description: "A transfer reached the connected account.", Example: transferToMap(transfer)},
+	}
+}
+
+// TriggerEvent records an event carrying object and sends its webhook, as
+// the API action behind the event would. Implements admin.EventTrigger.
+func (h *Handler) TriggerEvent(eventType string, object map[string]any) (any, error) {
+	return h.emitEvent(context.Background(), eventType, object), nil
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twinkit/webhook"
+)
+
+// Schemas of the objects Stripe documents for each event's data.object.
+// Like the request schemas, they allow fields the twin does not model.
+const (
+	chargeObjectSchema = `{
+		"type": "object",
+		"required": ["id", "object", "amount", "currency", "captured", "paid", "refunded", "status", "created"],
+		"properties": {
+			"id": {"type": "string", "minLength": 1},
+			"object": {"type": "string", "enum": ["charge"]},
+			"amount": {"type": "integer", "minimum": 0},
+			"amount_captured": {"type": "integer", "minimum": 0},
+			"amount_refunded": {"type": "integer", "minimum": 0},
+			"currency": {"type": "string", "pattern": "^[a-z]{3}$"},
+			"captured": {"type": "boolean"},
+			"paid": {"type": "boolean"},
+			"refunded": {"type": "boolean"},
+			"status": {"type": "string", "enum": ["succeeded", "pending", "failed"]},
+			"metadata": {"type": "object"},
+			"created": {"type": "integer"}
+		}
+	}`
+
+	refundObjectSchema = `{
+		"type": "object",
+		"required": ["id", "object", "amount", "charge", "currency", "status", "created"],
+		"properties": {
+			"id": {"type": "string", "minLength": 1},
+			"object": {"type": "string", "enum": ["refund"]},
+			"amount": {"type": "integer", "minimum": 0},
+			"charge": {"type": "string"},
+			"currency": {"type": "string", "pattern": "^[a-z]{3}$"},
+			"status": {"type": "string", "enum": ["pending", "requires_action", "succeeded", "failed", "canceled"]},
+			"metadata": {"type": "object"},
+			"created": {"type": "integer"}
+		}
+	}`
+
+	disputeObjectSchema = `{
+		"type": "object",
+		"required": ["id", "object", "amount", "charge", "currency", "evidence_details", "reason", "status", "created"],
+		"properties": {
+			"id": {"type": "string", "minLength": 1},
+			"object": {"type": "string", "enum": ["dispute"]},
+			"amount": {"type": "integer", "minimum": 0},
+			"balance_transactions": {"type": "array", "items": {"type": "string"}},
+			"charge": {"type": "string"},
+			"currency": {"type": "string", "pattern": "^[a-z]{3}$"},
+			"evidence": {"type": "object"},
+			"evidence_details": {
+				"type": "object",
+				"required": ["due_by", "has_evidence", "past_due", "submission_count"],
+				"properties": {
+					"due_by": {"type": "integer"},
+					"has_evidence": {"type": "boolean"},
+					"past_due": {"type": "boolean"},
+					"submission_count": {"type": "integer", "minimum": 0}
+				}
+			},
+			"reason": {"type": "string"},
+			"status": {"type": "string", "enum": ["warning_needs_response", "warning_under_review", "warning_closed", "needs_response", "under_review", "won", "lost"]},
+			"metadata": {"type": "object"},
+			"created": {"type": "integer"}
+		}
+	}`
+
+	payoutObjectSchema = `{
+		"type": "object",
+		"required": ["id", "object", "amount", "arrival_date", "currency", "method", "status", "type", "created"],
+		"properties": {
+			"id": {"type": "string", "minLength": 1},
+			"object": {"type": "string", "enum": ["payout"]},
+			"amount": {"type": "integer", "minimum": 0},
+			"arrival_date": {"type": "integer"},
+			"currency": {"type": "string", "pattern": "^[a-z]{3}$"},
+			"method": {"type": "string", "enum": ["standard", "instant"]},
+			"status": {"type": "string", "enum": ["pending", "in_transit", "paid", "failed", "canceled"]},
+			"type": {"type": "string", "enum": ["bank_account", "card"]},
+			"failure_code": {"type": "string"},
+			"failure_message": {"type": "string"},
+			"metadata": {"type": "object"},
+			"created": {"type": "integer"}
+		}
+	}`
+
+	transferObjectSchema = `{
+		"type": "object",
+		"required": ["id", "object", "amount", "currency", "destination", "reversed", "created"],
+		"properties": {
+			"id": {"type": "string", "minLength": 1},
+			"object": {"type": "string", "enum": ["transfer"]},
+			"amount": {"type": "integer", "minimum": 0},
+			"amount_reversed": {"type": "integer", "minimum": 0},
+			"currency": {"type": "string", "pattern": "^[a-z]{3}$"},
+			"destination": {"type": "string", "minLength": 1},
+			"reversed": {"type": "boolean"},
+			"metadata": {"type": "object"},
+			"created": {"type": "integer"}
+		}
+	}`
+
+	accountObjectSchema = `{
+		"type": "object",
+		"required": ["id", "object", "type", "charges_enabled", "payouts_enabled", "details_submitted", "created"],
+		"properties": {
+			"id": {"type": "string", "minLength": 1},
+			"object": {"type": "string", "enum": ["account"]},
+			"type": {"type": "string", "enum": ["standard", "express", "custom"]},
+			"country": {"type": "string"},
+			"default_currency": {"type": "string"},
+			"charges_enabled": {"type": "boolean"},
+			"payouts_enabled": {"type": "boolean"},
+			"details_submitted": {"type": "boolean"},
+			"capabilities": {"type": "object"},
+			"requirements": {"type": "object"},
+			"metadata": {"type": "object"},
+			"created": {"type": "integer"}
+		}
+	}`
+)
+
+// eventObjectSchemas maps each event type the twin emits to the schema of
+// its data.object.
+var eventObjectSchemas = map[string]string{
+	"account.updated":                 accountObjectSchema,
+	"charge.succeeded":                chargeObjectSchema,
+	"charge.refunded":                 chargeObjectSchema,
+	"charge.dispute.created":          disputeObjectSchema,
+	"charge.dispute.funds_withdrawn":  disputeObjectSchema,
+	"charge.dispute.updated":          disputeObjectSchema,
+	"charge.dispute.closed":           disputeObjectSchema,
+	"charge.dispute.funds_reinstated": disputeObjectSchema,
+	"payout.created":                  payoutObjectSchema,
+	"payout.updated":                  payoutObjectSchema,
+	"payout.paid":                     payoutObjectSchema,
+	"payout.failed":                   payoutObjectSchema,
+	"payout.canceled":                 payoutObjectSchema,
+	"refund.created":                  refundObjectSchema,
+	"transfer.created":                transferObjectSchema,
+	"transfer.paid":                   transferObjectSchema,
+}
+
+// registerEventSchemas has the dispatcher check every webhook payload
+// against the event envelope Stripe documents, wrapped around its type's
+// object schema.
+func registerEventSchemas(d *webhook.Dispatcher) {
+	for eventType, object := range eventObjectSchemas {
+		d.RegisterSchema(eventType, twincore.MustParseSchema(fmt.Sprintf(`{
+			"type": "object",
+			"required": ["id", "object", "type", "data", "api_version", "created", "livemode", "pending_webhooks"],
+			"properties": {
+				"id": {"type": "string", "pattern": "^evt_"},
+				"object": {"type": "string", "enum": ["event"]},
+				"type": {"type": "string", "enum": [%q]},
+				"data": {
+					"type": "object",
+					"required": ["object"],
+					"properties": {"object": %s}
+				},
+				"api_version": {"type": "string"},
+				"created": {"type": "integer"},
+				"livemode": {"type": "boolean"},
+				"pending_webhooks": {"type": "integer", "minimum": 0}
+			}
+		}`, eventType, object)))
+	}
+}
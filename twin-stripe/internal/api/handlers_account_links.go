@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
+)
+
+// accountLinkTTL is how long an account link stays usable, in simulated
+// seconds, matching Stripe's few minutes.
+const accountLinkTTL = 300
+
+// CreateAccountLink handles POST /v1/account_links.
+// Stripe SDK: accountlink.New(params)
+// The link points at the twin's own onboarding flow (see VisitAccountLink).
+func (h *Handler) CreateAccountLink(w http.ResponseWriter, r *http.Request) {
+	if err := parseFormOrJSON(r); err != nil {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_missing", err.Error())
+		return
+	}
+
+	accountID := r.FormValue("account")
+	if _, ok := h.store.Accounts.Get(accountID); !ok {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "resource_missing",
+			"No such account: '"+accountID+"'")
+		return
+	}
+
+	id := h.store.AccountLinks.NextID()
+	now := h.store.Clock.Now().Unix()
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	link := store.AccountLink{
+		ID:         id,
+		Object:     "account_link",
+		Account:    accountID,
+		Type:       r.FormValue("type"),
+		RefreshURL: r.FormValue("refresh_url"),
+		ReturnURL:  r.FormValue("return_url"),
+		URL:        scheme + "://" + r.Host + "/connect/onboarding/" + id,
+		Created:    now,
+		ExpiresAt:  now + accountLinkTTL,
+	}
+	h.store.AccountLinks.Set(id, link)
+
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"object":     link.Object,
+		"created":    link.Created,
+		"expires_at": link.ExpiresAt,
+		"url":        link.URL,
+	})
+}
+
+// VisitAccountLink handles GET /connect/onboarding/{id}, the URL of an
+// account link. It stands in for the onboarding form a user would fill in:
+// whatever the account has due is filled with Stripe's test values, the
+// account is verified at once, and the browser is redirected to the link's
+// return_url. A link works once and until it expires; after that, as on
+// Stripe, it redirects to refresh_url so the platform can mint a new one.
+func (h *Handler) VisitAccountLink(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	link, ok := h.store.AccountLinks.Get(id)
+	if !ok {
+		twincore.Error(w, http.StatusNotFound, "No such account link: "+id)
+		return
+	}
+	acct, ok := h.store.Accounts.Get(link.Account)
+	if !ok || link.Used || h.store.Clock.Now().Unix() > link.ExpiresAt {
+		http.Redirect(w, r, link.RefreshURL, http.StatusFound)
+		return
+	}
+
+	link.Used = true
+	h.store.AccountLinks.Set(id, link)
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	h.completeOnboarding(&acct, ip)
+	h.refreshAccountStatus(&acct)
+	acct.Updated = store.Now()
+	h.store.Accounts.Set(acct.ID, acct)
+	h.emitEvent(r.Context(), "account.updated", accountToMap(acct))
+
+	http.Redirect(w, r, link.ReturnURL, http.StatusFound)
+}
+
+// completeOnboarding fills in what an account has due with the values
+// Stripe documents for test mode, adding its test bank account if it has
+// none.
+func (h *Handler) completeOnboarding(acct *store.Account, ip string) {
+	if acct.BusinessType == "" {
+		acct.BusinessType = "individual"
+	}
+	fill := func(fields map[string]any, values map[string]any) map[string]any {
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		for f, v := range values {
+			if blank(fields, f) {
+				fields[f] = v
+			}
+		}
+		return fields
+	}
+	switch acct.BusinessType {
+	case "individual":
+		acct.Individual = fill(acct.Individual, map[string]any{"first_name": "Jenny", "last_name": "Rosen"})
+	case "company", "non_profit", "government_entity":
+		acct.Company = fill(acct.Company, map[string]any{"name": "Rocket Rides", "tax_id": "000000000"})
+	}
+	acct.TOSAcceptance = fill(acct.TOSAcceptance, map[string]any{
+		"date": strconv.FormatInt(h.store.Clock.Now().Unix(), 10),
+		"ip":   ip,
+	})
+
+	if len(h.getExternalAccountsForAccount(acct.ID).Data) == 0 {
+		id := h.store.ExternalAccts.NextID()
+		h.store.ExternalAccts.Set(id, newBankAccount(id, acct.ID, "110000000", "000123456789", acct.Country, acct.DefaultCurrency))
+	}
+}
@@ -1,12 +1,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
 	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
 )
@@ -49,13 +52,11 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		PayoutsEnabled:   false,
 		DetailsSubmitted: false,
 		Capabilities:     make(map[string]string),
-		Requirements: &store.Requirements{
-			CurrentlyDue:  []string{"business_type", "external_account", "tos_acceptance.date", "tos_acceptance.ip"},
-			EventuallyDue: []string{"business_type", "external_account", "tos_acceptance.date", "tos_acceptance.ip"},
-			PastDue:       []string{},
-		},
-		Metadata: extractMetadata(r),
-		Created:  now,
+		Requirements:     &store.Requirements{},
+		Individual:       nestedFormValues(r, "individual"),
+		Company:          nestedFormValues(r, "company"),
+		Metadata:         extractMetadata(r),
+		Created:          now,
 	}
 
 	// Parse capabilities from form
@@ -64,7 +65,7 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 			capName := strings.TrimPrefix(key, "capabilities[")
 			capName = strings.TrimSuffix(capName, "][requested]")
 			if len(values) > 0 && values[0] == "true" {
-				acct.Capabilities[capName] = "inactive"
+				acct.Capabilities[capName] = store.CapabilityInactive
 			}
 		}
 	}
@@ -92,11 +93,12 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		URL:     "/v1/accounts/" + id + "/external_accounts",
 	}
 
-	h.store.Accounts.Set(id, acct)
+	h.refreshAccountStatus(&acct)
+	h.accounts(r).Set(id, acct)
 	h.store.GetOrCreateBalance(id)
 
 	// Emit account.updated event
-	h.emitEvent("account.updated", accountToMap(acct))
+	h.emitEvent(r.Context(), "account.updated", accountToMap(acct))
 
 	twincore.JSON(w, http.StatusOK, acct)
 }
@@ -106,7 +108,7 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	acct, ok := h.store.Accounts.Get(id)
+	acct, ok := h.accounts(r).Get(id)
 	if !ok {
 		twincore.StripeError(w, http.StatusNotFound,
 			"invalid_request_error", "resource_missing",
@@ -125,7 +127,7 @@ func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	acct, ok := h.store.Accounts.Get(id)
+	acct, ok := h.accounts(r).Get(id)
 	if !ok {
 		twincore.StripeError(w, http.StatusNotFound,
 			"invalid_request_error", "resource_missing",
@@ -158,28 +160,18 @@ func (h *Handler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse individual updates (KYB fields)
-	for key, values := range r.Form {
-		if strings.HasPrefix(key, "individual[") && len(values) > 0 {
-			if acct.Individual == nil {
-				acct.Individual = make(map[string]any)
-			}
-			field := strings.TrimPrefix(key, "individual[")
-			field = strings.TrimSuffix(field, "]")
-			acct.Individual[field] = values[0]
+	// Parse individual and company updates (KYC/KYB fields)
+	for field, value := range nestedFormValues(r, "individual") {
+		if acct.Individual == nil {
+			acct.Individual = make(map[string]any)
 		}
+		acct.Individual[field] = value
 	}
-
-	// Parse company updates
-	for key, values := range r.Form {
-		if strings.HasPrefix(key, "company[") && len(values) > 0 {
-			if acct.Company == nil {
-				acct.Company = make(map[string]any)
-			}
-			field := strings.TrimPrefix(key, "company[")
-			field = strings.TrimSuffix(field, "]")
-			acct.Company[field] = values[0]
+	for field, value := range nestedFormValues(r, "company") {
+		if acct.Company == nil {
+			acct.Company = make(map[string]any)
 		}
+		acct.Company[field] = value
 	}
 
 	// Parse tos_acceptance
@@ -199,9 +191,10 @@ func (h *Handler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 				if acct.Capabilities == nil {
 					acct.Capabilities = make(map[string]string)
 				}
-				// If not already active, set to inactive (pending verification)
+				// Newly requested capabilities start inactive; refreshAccountStatus
+				// activates them once nothing is due.
 				if _, exists := acct.Capabilities[capName]; !exists {
-					acct.Capabilities[capName] = "inactive"
+					acct.Capabilities[capName] = store.CapabilityInactive
 				}
 			}
 		}
@@ -223,30 +216,49 @@ func (h *Handler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Simulate KYB verification progress:
-	// If business_type, tos_acceptance, and some individual/company info is provided,
-	// mark requirements as satisfied and enable capabilities.
-	if acct.BusinessType != "" && acct.TOSAcceptance != nil {
-		hasDetails := (acct.Individual != nil && len(acct.Individual) > 0) ||
-			(acct.Company != nil && len(acct.Company) > 0)
-		if hasDetails {
-			acct.DetailsSubmitted = true
-			acct.Requirements.CurrentlyDue = []string{}
-			acct.Requirements.EventuallyDue = []string{}
-			// Enable capabilities
-			for cap := range acct.Capabilities {
-				acct.Capabilities[cap] = "active"
-			}
-			acct.ChargesEnabled = true
-			acct.PayoutsEnabled = true
-		}
-	}
+	// Simulate verification: whatever the update supplied is accepted at once.
+	h.refreshAccountStatus(&acct)
 
 	acct.Updated = store.Now()
-	h.store.Accounts.Set(id, acct)
+	h.accounts(r).Set(id, acct)
 
 	// Emit account.updated event
-	h.emitEvent("account.updated", accountToMap(acct))
+	h.emitEvent(r.Context(), "account.updated", accountToMap(acct))
+
+	acct.ExternalAccounts = h.getExternalAccountsForAccount(id)
+	twincore.JSON(w, http.StatusOK, acct)
+}
+
+// RejectAccount handles POST /v1/accounts/{id}/reject.
+// Stripe SDK: account.Reject(id, params)
+// A rejected account loses its capabilities and can no longer receive
+// transfers or pay out.
+func (h *Handler) RejectAccount(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	acct, ok := h.accounts(r).Get(id)
+	if !ok {
+		twincore.StripeError(w, http.StatusNotFound,
+			"invalid_request_error", "resource_missing",
+			"No such account: '"+id+"'")
+		return
+	}
+
+	if err := parseFormOrJSON(r); err != nil {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_missing", err.Error())
+		return
+	}
+
+	// rejectAccountSchema has checked the reason.
+	if acct.Requirements == nil {
+		acct.Requirements = &store.Requirements{}
+	}
+	acct.Requirements.DisabledReason = "rejected." + r.FormValue("reason")
+	h.refreshAccountStatus(&acct)
+	acct.Updated = store.Now()
+	h.accounts(r).Set(id, acct)
+
+	h.emitEvent(r.Context(), "account.updated", accountToMap(acct))
 
 	acct.ExternalAccounts = h.getExternalAccountsForAccount(id)
 	twincore.JSON(w, http.StatusOK, acct)
@@ -256,7 +268,7 @@ func (h *Handler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if !h.store.Accounts.Delete(id) {
+	if !h.accounts(r).Delete(id) {
 		twincore.StripeError(w, http.StatusNotFound,
 			"invalid_request_error", "resource_missing",
 			"No such account: '"+id+"'")
@@ -278,7 +290,7 @@ func (h *Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 		fmt.Sscanf(l, "%d", &limit)
 	}
 
-	page := h.store.Accounts.Paginate(cursor, limit)
+	page := h.accounts(r).Paginate(cursor, limit)
 
 	twincore.JSON(w, http.StatusOK, map[string]any{
 		"object":   "list",
@@ -301,6 +313,128 @@ func (h *Handler) getExternalAccountsForAccount(accountID string) *store.Externa
 	}
 }
 
+// accountRequirements lists what an account still has due, named as in
+// Stripe's requirements hash and sorted as Stripe sorts them.
+func (h *Handler) accountRequirements(acct store.Account) []string {
+	due := []string{}
+	if acct.BusinessType == "" {
+		due = append(due, "business_type")
+	}
+	if len(h.getExternalAccountsForAccount(acct.ID).Data) == 0 {
+		due = append(due, "external_account")
+	}
+	switch acct.BusinessType {
+	case "individual":
+		for _, f := range []string{"first_name", "last_name"} {
+			if blank(acct.Individual, f) {
+				due = append(due, "individual."+f)
+			}
+		}
+	case "company", "non_profit", "government_entity":
+		for _, f := range []string{"name", "tax_id"} {
+			if blank(acct.Company, f) {
+				due = append(due, "company."+f)
+			}
+		}
+	}
+	for _, f := range []string{"date", "ip"} {
+		if blank(acct.TOSAcceptance, f) {
+			due = append(due, "tos_acceptance."+f)
+		}
+	}
+	sort.Strings(due)
+	return due
+}
+
+// blank reports whether a submitted field is missing or empty.
+func blank(fields map[string]any, f string) bool {
+	v, ok := fields[f]
+	return !ok || v == nil || v == ""
+}
+
+// refreshAccountStatus recomputes an account's requirements and, from them,
+// its capabilities and whether it can charge and pay out. Verification is
+// instant: a requested capability is active as soon as nothing is due.
+// Everything due is past due, since the account cannot transact until it
+// is provided. It reports whether anything changed, for account.updated.
+func (h *Handler) refreshAccountStatus(acct *store.Account) bool {
+	before := accountStatus(*acct)
+	if acct.Requirements == nil {
+		acct.Requirements = &store.Requirements{}
+	}
+	req := acct.Requirements
+	rejected := strings.HasPrefix(req.DisabledReason, "rejected.")
+
+	due := h.accountRequirements(*acct)
+	req.CurrentlyDue, req.EventuallyDue, req.PastDue = due, due, due
+	switch {
+	case rejected:
+	case len(due) > 0:
+		req.DisabledReason = "requirements.past_due"
+	default:
+		req.DisabledReason = ""
+	}
+
+	// Details are submitted once only the bank account is missing.
+	acct.DetailsSubmitted = len(due) == 0 || (len(due) == 1 && due[0] == "external_account")
+	acct.ChargesEnabled = !rejected && acct.DetailsSubmitted
+	acct.PayoutsEnabled = !rejected && len(due) == 0
+	for c := range acct.Capabilities {
+		if acct.PayoutsEnabled {
+			acct.Capabilities[c] = store.CapabilityActive
+		} else {
+			acct.Capabilities[c] = store.CapabilityInactive
+		}
+	}
+	return accountStatus(*acct) != before
+}
+
+// accountStatus serializes the fields refreshAccountStatus derives, so
+// changes to them can be detected.
+func accountStatus(acct store.Account) string {
+	data, _ := json.Marshal([]any{acct.Requirements, acct.Capabilities, acct.DetailsSubmitted, acct.ChargesEnabled, acct.PayoutsEnabled})
+	return string(data)
+}
+
+// syncAccountStatus refreshes a stored account after something it depends
+// on changed, such as its external accounts, and sends account.updated if
+// its status moved.
+func (h *Handler) syncAccountStatus(ctx context.Context, accountID string) {
+	acct, ok := h.store.Accounts.Get(accountID)
+	if !ok || !h.refreshAccountStatus(&acct) {
+		return
+	}
+	acct.Updated = store.Now()
+	h.store.Accounts.Set(accountID, acct)
+	h.emitEvent(ctx, "account.updated", accountToMap(acct))
+}
+
+// transferCapable reports whether an account can receive transfers. Accounts
+// that requested capabilities need transfers active; accounts created
+// without any, as older integrations do, only need not to be rejected.
+func transferCapable(acct store.Account) bool {
+	if acct.Requirements != nil && strings.HasPrefix(acct.Requirements.DisabledReason, "rejected.") {
+		return false
+	}
+	return len(acct.Capabilities) == 0 || acct.Capabilities["transfers"] == store.CapabilityActive
+}
+
+// nestedFormValues extracts param[field]=value pairs from form data, e.g.
+// individual[first_name]. It returns nil when there are none.
+func nestedFormValues(r *http.Request, param string) map[string]any {
+	var out map[string]any
+	for key, values := range r.Form {
+		if strings.HasPrefix(key, param+"[") && strings.HasSuffix(key, "]") && len(values) > 0 {
+			if out == nil {
+				out = make(map[string]any)
+			}
+			field := strings.TrimSuffix(strings.TrimPrefix(key, param+"["), "]")
+			out[field] = values[0]
+		}
+	}
+	return out
+}
+
 // extractMetadata extracts metadata[key]=value from form data.
 func extractMetadata(r *http.Request) map[string]string {
 	meta := make(map[string]string)
@@ -326,3 +460,10 @@ func accountToMap(acct store.Account) map[string]any {
 	return m
 }
 
+
+// accounts returns the accounts store as the region r was routed to sees
+// it, so clients reading back from another region can miss recent writes
+// (see twincore.Middleware.Regions).
+func (h *Handler) accounts(r *http.Request) *pkgstore.RegionView[store.Account] {
+	return h.store.Accounts.Region(twincore.RequestRegion(r))
+}
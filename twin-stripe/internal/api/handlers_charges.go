@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
+)
+
+// Stripe's test card tokens that change how a charge behaves.
+const (
+	tokenDeclined      = "tok_chargeDeclined"
+	tokenCreateDispute = "tok_createDispute"
+)
+
+// CreateCharge handles POST /v1/charges.
+// Stripe SDK: charge.New(params)
+// Charges settle to the platform balance at once, net of Stripe's fee.
+// source=tok_chargeDeclined declines; source=tok_createDispute succeeds and
+// is disputed straight away, as the matching Stripe test cards are.
+func (h *Handler) CreateCharge(w http.ResponseWriter, r *http.Request) {
+	if err := parseFormOrJSON(r); err != nil {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_missing", err.Error())
+		return
+	}
+
+	// createChargeSchema has checked the amount.
+	amount, _ := strconv.ParseInt(r.FormValue("amount"), 10, 64)
+	currency := r.FormValue("currency")
+	if currency == "" {
+		currency = "usd"
+	}
+	source := r.FormValue("source")
+
+	if source == tokenDeclined {
+		twincore.StripeError(w, http.StatusPaymentRequired, "card_error", "card_declined",
+			"Your card was declined.")
+		return
+	}
+
+	id := h.store.Charges.NextID()
+	fee := stripeFee(amount)
+	charge := store.Charge{
+		ID:                 id,
+		Object:             "charge",
+		Amount:             amount,
+		AmountCaptured:     amount,
+		BalanceTransaction: h.store.RecordBalanceTransaction("charge", id, currency, amount, fee),
+		Captured:           true,
+		Currency:           currency,
+		Description:        r.FormValue("description"),
+		Paid:               true,
+		Status:             "succeeded",
+		TransferGroup:      r.FormValue("transfer_group"),
+		Metadata:           extractMetadata(r),
+		Created:            h.store.Clock.Now().Unix(),
+	}
+	h.store.Charges.Set(id, charge)
+	h.store.CreditBalance("", currency, amount-fee)
+
+	h.emitEvent(r.Context(), "charge.succeeded", chargeToMap(charge))
+
+	if source == tokenCreateDispute {
+		h.openDispute(r.Context(), charge, charge.Amount, "fraudulent")
+		charge, _ = h.store.Charges.Get(id)
+	}
+
+	twincore.JSON(w, http.StatusOK, charge)
+}
+
+// GetCharge handles GET /v1/charges/{id}.
+// Stripe SDK: charge.Get(id, nil)
+func (h *Handler) GetCharge(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	charge, ok := h.store.Charges.Get(id)
+	if !ok {
+		twincore.StripeError(w, http.StatusNotFound,
+			"invalid_request_error", "resource_missing",
+			"No such charge: '"+id+"'")
+		return
+	}
+
+	twincore.JSON(w, http.StatusOK, charge)
+}
+
+// ListCharges handles GET /v1/charges.
+func (h *Handler) ListCharges(w http.ResponseWriter, r *http.Request) {
+	cursor := r.URL.Query().Get("starting_after")
+	limit := 10
+	if l := r.URL.Query().Get("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &limit)
+	}
+
+	page := h.store.Charges.Paginate(cursor, limit)
+
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"object":   "list",
+		"url":      "/v1/charges",
+		"data":     page.Data,
+		"has_more": page.HasMore,
+	})
+}
+
+// stripeFee is Stripe's standard card fee: 2.9% + 30¢.
+func stripeFee(amount int64) int64 {
+	return (amount*29+500)/1000 + 30
+}
+
+func chargeToMap(c store.Charge) map[string]any {
+	data, _ := json.Marshal(c)
+	var m map[string]any
+	json.Unmarshal(data, &m)
+	return m
+}
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
+)
+
+const (
+	// disputeFee is what Stripe withdraws, on top of the disputed amount,
+	// when a dispute opens. It is not returned if the dispute is won.
+	disputeFee = 1500
+	// disputeResponseWindow is how long the platform has to submit evidence,
+	// in simulated seconds. A dispute left without a response by then is lost.
+	disputeResponseWindow = 7 * 24 * 3600
+)
+
+// Evidence values that decide a submitted dispute at once, as in Stripe's
+// test mode.
+const (
+	winningEvidence = "winning_evidence"
+	losingEvidence  = "losing_evidence"
+)
+
+// GetDispute handles GET /v1/disputes/{id}.
+// Stripe SDK: dispute.Get(id, nil)
+func (h *Handler) GetDispute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	d, ok := h.store.Disputes.Get(id)
+	if !ok {
+		twincore.StripeError(w, http.StatusNotFound,
+			"invalid_request_error", "resource_missing",
+			"No such dispute: '"+id+"'")
+		return
+	}
+
+	twincore.JSON(w, http.StatusOK, d)
+}
+
+// ListDisputes handles GET /v1/disputes.
+func (h *Handler) ListDisputes(w http.ResponseWriter, r *http.Request) {
+	cursor := r.URL.Query().Get("starting_after")
+	limit := 10
+	if l := r.URL.Query().Get("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &limit)
+	}
+
+	page := h.store.Disputes.Paginate(cursor, limit)
+
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"object":   "list",
+		"url":      "/v1/disputes",
+		"data":     page.Data,
+		"has_more": page.HasMore,
+	})
+}
+
+// UpdateDispute handles POST /v1/disputes/{id}.
+// Stripe SDK: dispute.Update(id, params)
+// Evidence is staged with submit=false and submitted otherwise, which puts
+// the dispute under review. In test mode, submitting uncategorized_text of
+// winning_evidence or losing_evidence decides the dispute straight away.
+func (h *Handler) UpdateDispute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	d, ok := h.store.Disputes.Get(id)
+	if !ok {
+		twincore.StripeError(w, http.StatusNotFound,
+			"invalid_request_error", "resource_missing",
+			"No such dispute: '"+id+"'")
+		return
+	}
+
+	if err := parseFormOrJSON(r); err != nil {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_missing", err.Error())
+		return
+	}
+
+	if d.Status != store.DisputeStatusNeedsResponse {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "dispute_already_submitted",
+			"This dispute has a status of "+d.Status+"; evidence can only be updated while it needs a response.")
+		return
+	}
+
+	for field, value := range nestedFormValues(r, "evidence") {
+		if d.Evidence == nil {
+			d.Evidence = make(map[string]string)
+		}
+		d.Evidence[field] = fmt.Sprint(value)
+	}
+	for field, value := range nestedFormValues(r, "metadata") {
+		if d.Metadata == nil {
+			d.Metadata = make(map[string]string)
+		}
+		d.Metadata[field] = fmt.Sprint(value)
+	}
+	d.EvidenceDetails.HasEvidence = len(d.Evidence) > 0
+
+	if r.FormValue("submit") == "false" {
+		h.store.Disputes.Set(id, d)
+		h.emitEvent(r.Context(), "charge.dispute.updated", disputeToMap(d))
+		twincore.JSON(w, http.StatusOK, d)
+		return
+	}
+
+	d.Status = store.DisputeStatusUnderReview
+	d.EvidenceDetails.SubmissionCount++
+	h.store.Disputes.Set(id, d)
+	h.emitEvent(r.Context(), "charge.dispute.updated", disputeToMap(d))
+
+	switch d.Evidence["uncategorized_text"] {
+	case winningEvidence:
+		d = h.closeDispute(r.Context(), d, store.DisputeStatusWon)
+	case losingEvidence:
+		d = h.closeDispute(r.Context(), d, store.DisputeStatusLost)
+	}
+
+	twincore.JSON(w, http.StatusOK, d)
+}
+
+// CloseDispute handles POST /v1/disputes/{id}/close.
+// Stripe SDK: dispute.Close(id, nil)
+// Closing accepts the dispute: it is lost, and the funds stay withdrawn.
+func (h *Handler) CloseDispute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	d, ok := h.store.Disputes.Get(id)
+	if !ok {
+		twincore.StripeError(w, http.StatusNotFound,
+			"invalid_request_error", "resource_missing",
+			"No such dispute: '"+id+"'")
+		return
+	}
+
+	if d.Status != store.DisputeStatusNeedsResponse {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "dispute_already_submitted",
+			"This dispute has a status of "+d.Status+" and cannot be closed.")
+		return
+	}
+
+	twincore.JSON(w, http.StatusOK, h.closeDispute(r.Context(), d, store.DisputeStatusLost))
+}
+
+// adminDisputeRequest is the JSON body for POST /admin/disputes/create.
+type adminDisputeRequest struct {
+	Charge string `json:"charge"`
+	Amount int64  `json:"amount"` // defaults to the charge's unrefunded amount
+	Reason string `json:"reason"` // defaults to "fraudulent"
+}
+
+// AdminCreateDispute handles POST /admin/disputes/create.
+// Opens a dispute on a charge, as a cardholder's bank would.
+func (h *Handler) AdminCreateDispute(w http.ResponseWriter, r *http.Request) {
+	var req adminDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	charge, ok := h.store.Charges.Get(req.Charge)
+	if !ok {
+		twincore.Error(w, http.StatusNotFound, "No such charge: "+req.Charge)
+		return
+	}
+	if charge.Disputed {
+		twincore.Error(w, http.StatusConflict, "Charge "+charge.ID+" is already disputed")
+		return
+	}
+
+	remaining := charge.Amount - charge.AmountRefunded
+	if req.Amount == 0 {
+		req.Amount = remaining
+	}
+	if req.Amount <= 0 || req.Amount > remaining {
+		twincore.Error(w, http.StatusBadRequest, fmt.Sprintf("amount must be between 1 and the charge's unrefunded %d", remaining))
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = "fraudulent"
+	}
+
+	twincore.JSON(w, http.StatusOK, h.openDispute(r.Context(), charge, req.Amount, req.Reason))
+}
+
+// adminResolveRequest is the JSON body for POST /admin/disputes/{id}/resolve.
+type adminResolveRequest struct {
+	Status string `json:"status"` // "won" or "lost"
+}
+
+// AdminResolveDispute handles POST /admin/disputes/{id}/resolve.
+// Decides an open dispute, as the card network would after review.
+func (h *Handler) AdminResolveDispute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	d, ok := h.store.Disputes.Get(id)
+	if !ok {
+		twincore.Error(w, http.StatusNotFound, "No such dispute: "+id)
+		return
+	}
+
+	var req adminResolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if req.Status != store.DisputeStatusWon && req.Status != store.DisputeStatusLost {
+		twincore.Error(w, http.StatusBadRequest, `status must be "won" or "lost"`)
+		return
+	}
+	if d.Status == store.DisputeStatusWon || d.Status == store.DisputeStatusLost {
+		twincore.Error(w, http.StatusBadRequest, "Dispute already "+d.Status)
+		return
+	}
+
+	twincore.JSON(w, http.StatusOK, h.closeDispute(r.Context(), d, req.Status))
+}
+
+// openDispute disputes amount of a charge and withdraws it, plus the
+// dispute fee, from the platform balance. The balance may go negative.
+func (h *Handler) openDispute(ctx context.Context, charge store.Charge, amount int64, reason string) store.Dispute {
+	now := h.store.Clock.Now().Unix()
+	id := h.store.Disputes.NextID()
+
+	h.store.CreditBalance("", charge.Currency, -(amount + disputeFee))
+	txn := h.store.RecordBalanceTransaction("adjustment", id, charge.Currency, -amount, disputeFee)
+
+	d := store.Dispute{
+		ID:                  id,
+		Object:              "dispute",
+		Amount:              amount,
+		BalanceTransactions: []string{txn},
+		Charge:              charge.ID,
+		Currency:            charge.Currency,
+		Evidence:            map[string]string{},
+		EvidenceDetails:     store.EvidenceDetails{DueBy: now + disputeResponseWindow},
+		Reason:              reason,
+		Status:              store.DisputeStatusNeedsResponse,
+		Created:             now,
+	}
+	h.store.Disputes.Set(id, d)
+
+	charge.Disputed = true
+	h.store.Charges.Set(charge.ID, charge)
+
+	h.emitEvent(ctx, "charge.dispute.created", disputeToMap(d))
+	h.emitEvent(ctx, "charge.dispute.funds_withdrawn", disputeToMap(d))
+	return d
+}
+
+// closeDispute decides a dispute. A won dispute returns the disputed amount
+// to the platform balance; the fee is kept either way.
+func (h *Handler) closeDispute(ctx context.Context, d store.Dispute, status string) store.Dispute {
+	d.Status = status
+	if status == store.DisputeStatusWon {
+		h.store.CreditBalance("", d.Currency, d.Amount)
+		d.BalanceTransactions = append(d.BalanceTransactions,
+			h.store.RecordBalanceTransaction("adjustment", d.ID, d.Currency, d.Amount, 0))
+		d.IsChargeRefundable = true
+	}
+	h.store.Disputes.Set(d.ID, d)
+
+	h.emitEvent(ctx, "charge.dispute.closed", disputeToMap(d))
+	if status == store.DisputeStatusWon {
+		h.emitEvent(ctx, "charge.dispute.funds_reinstated", disputeToMap(d))
+	}
+	return d
+}
+
+// disputeForCharge returns the dispute opened on a charge, if any.
+func (h *Handler) disputeForCharge(chargeID string) (store.Dispute, bool) {
+	disputes := h.store.Disputes.Filter(func(id string, d store.Dispute) bool {
+		return d.Charge == chargeID
+	})
+	if len(disputes) == 0 {
+		return store.Dispute{}, false
+	}
+	return disputes[0], true
+}
+
+// expireDisputes is the clock's derived-state function for disputes. A
+// dispute still needing a response when its evidence is due is lost.
+func (h *Handler) expireDisputes(now time.Time) {
+	for _, id := range h.store.Disputes.ListIDs() {
+		d, ok := h.store.Disputes.Get(id)
+		if !ok || d.Status != store.DisputeStatusNeedsResponse || now.Unix() < d.EvidenceDetails.DueBy {
+			continue
+		}
+		d.EvidenceDetails.PastDue = true
+		h.closeDispute(context.Background(), d, store.DisputeStatusLost)
+	}
+}
+
+func disputeToMap(d store.Dispute) map[string]any {
+	data, _ := json.Marshal(d)
+	var m map[string]any
+	json.Unmarshal(data, &m)
+	return m
+}
@@ -1,16 +1,21 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twinkit/webhook"
 	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
 )
 
-// emitEvent creates a Stripe event and optionally enqueues a webhook.
-func (h *Handler) emitEvent(eventType string, objectData map[string]any) {
+// emitEvent creates a Stripe event and optionally enqueues a webhook. ctx
+// is the context of the request that caused the event, which both are
+// correlated with for /admin/correlations; events caused by time passing
+// or admin calls pass context.Background().
+func (h *Handler) emitEvent(ctx context.Context, eventType string, objectData map[string]any) store.Event {
 	id := h.store.Events.NextID()
 	evt := store.Event{
 		ID:              id,
@@ -23,10 +28,11 @@ func (h *Handler) emitEvent(eventType string, objectData map[string]any) {
 		PendingWebhooks: 1,
 	}
 	h.store.Events.Set(id, evt)
+	h.mw.Events.Record(ctx, id, eventType)
 
 	// Enqueue webhook delivery
 	if h.dispatcher != nil {
-		h.dispatcher.Enqueue(eventType, map[string]any{
+		payload := map[string]any{
 			"id":               evt.ID,
 			"object":           "event",
 			"type":             evt.Type,
@@ -35,8 +41,14 @@ func (h *Handler) emitEvent(eventType string, objectData map[string]any) {
 			"created":          evt.Created,
 			"livemode":         evt.Livemode,
 			"pending_webhooks": evt.PendingWebhooks,
-		})
+		}
+		// Connect events name the connected account they belong to.
+		if acct := webhook.TenantFromContext(ctx); acct != "" {
+			payload["account"] = acct
+		}
+		h.dispatcher.EnqueueContext(ctx, eventType, payload)
 	}
+	return evt
 }
 
 // GetEvent handles GET /v1/events/{id}.
@@ -51,16 +51,25 @@ func (h *Handler) CreateExternalAccount(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	// Generate last4 from account number
+	ea := newBankAccount(id, accountID, routingNumber, accountNumber, country, currency)
+	ea.Metadata = extractMetadata(r)
+
+	h.store.ExternalAccts.Set(id, ea)
+
+	// A bank account may be the last requirement the account had due.
+	h.syncAccountStatus(r.Context(), accountID)
+
+	twincore.JSON(w, http.StatusOK, ea)
+}
+
+// newBankAccount builds a bank account for a connected account, with the
+// last4 and fingerprint Stripe derives from the account number.
+func newBankAccount(id, accountID, routingNumber, accountNumber, country, currency string) store.ExternalAccount {
 	last4 := "0000"
 	if len(accountNumber) >= 4 {
 		last4 = accountNumber[len(accountNumber)-4:]
 	}
-
-	// Generate fingerprint
-	fingerprint := fmt.Sprintf("%x", sha256.Sum256([]byte(routingNumber+accountNumber)))[:16]
-
-	ea := store.ExternalAccount{
+	return store.ExternalAccount{
 		ID:                 id,
 		Object:             "bank_account",
 		AccountID:          accountID,
@@ -71,13 +80,8 @@ func (h *Handler) CreateExternalAccount(w http.ResponseWriter, r *http.Request)
 		RoutingNumber:      routingNumber,
 		Status:             "new",
 		DefaultForCurrency: true,
-		Fingerprint:        fingerprint,
-		Metadata:           extractMetadata(r),
+		Fingerprint:        fmt.Sprintf("%x", sha256.Sum256([]byte(routingNumber+accountNumber)))[:16],
 	}
-
-	h.store.ExternalAccts.Set(id, ea)
-
-	twincore.JSON(w, http.StatusOK, ea)
 }
 
 // GetExternalAccount handles GET /v1/accounts/{account_id}/external_accounts/{id}.
@@ -136,13 +140,17 @@ func (h *Handler) UpdateExternalAccount(w http.ResponseWriter, r *http.Request)
 func (h *Handler) DeleteExternalAccount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if !h.store.ExternalAccts.Delete(id) {
+	ea, ok := h.store.ExternalAccts.Get(id)
+	if !ok || !h.store.ExternalAccts.Delete(id) {
 		twincore.StripeError(w, http.StatusNotFound,
 			"invalid_request_error", "resource_missing",
 			"No such external account: '"+id+"'")
 		return
 	}
 
+	// Without a bank account, the account can no longer pay out.
+	h.syncAccountStatus(r.Context(), ea.AccountID)
+
 	twincore.JSON(w, http.StatusOK, map[string]any{
 		"id":      id,
 		"object":  "bank_account",
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
@@ -66,8 +68,21 @@ func (h *Handler) CreatePayout(w http.ResponseWriter, r *http.Request) {
 		payout.Method = method
 	}
 
-	// Debit balance if account specified
+	// Connected accounts pay out to their bank account, once onboarded,
+	// from their own balance.
 	if accountID != "" {
+		acct, ok := h.store.Accounts.Get(accountID)
+		if !ok {
+			twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "resource_missing",
+				"No such account: '"+accountID+"'")
+			return
+		}
+		if !acct.PayoutsEnabled {
+			twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "payouts_not_allowed",
+				"Payouts are not enabled for this account. Provide the information in its requirements to enable them.")
+			return
+		}
+		payout.Destination = h.payoutDestination(accountID, currency)
 		if err := h.store.DebitBalance(accountID, currency, amount); err != nil {
 			twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "balance_insufficient",
 				"You have insufficient funds in your Stripe account.")
@@ -81,7 +96,7 @@ func (h *Handler) CreatePayout(w http.ResponseWriter, r *http.Request) {
 	h.store.RecordBalanceTransaction("payout", payout.ID, payout.Currency, -payout.Amount, 0)
 
 	// Emit payout.created webhook
-	h.emitEvent("payout.created", payoutToMap(payout))
+	h.emitEvent(r.Context(), "payout.created", payoutToMap(payout))
 
 	twincore.JSON(w, http.StatusOK, payout)
 }
@@ -99,10 +114,6 @@ func (h *Handler) GetPayout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Advance state based on simulated clock
-	h.advancePayoutState(&payout)
-	h.store.Payouts.Set(id, payout)
-
 	twincore.JSON(w, http.StatusOK, payout)
 }
 
@@ -114,9 +125,6 @@ func (h *Handler) ListPayouts(w http.ResponseWriter, r *http.Request) {
 		fmt.Sscanf(l, "%d", &limit)
 	}
 
-	// Advance all payout states before listing
-	h.advanceAllPayoutStates()
-
 	page := h.store.Payouts.Paginate(cursor, limit)
 
 	twincore.JSON(w, http.StatusOK, map[string]any{
@@ -138,8 +146,8 @@ func (h *Handler) AdminFailPayout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if payout.Status == store.PayoutStatusPaid {
-		twincore.Error(w, http.StatusBadRequest, "Payout already paid, cannot fail")
+	if payout.Status != store.PayoutStatusPending && payout.Status != store.PayoutStatusInTransit {
+		twincore.Error(w, http.StatusBadRequest, "Payout already "+payout.Status+", cannot fail")
 		return
 	}
 
@@ -148,49 +156,107 @@ func (h *Handler) AdminFailPayout(w http.ResponseWriter, r *http.Request) {
 	payout.FailureMessage = "The bank could not process this payout."
 	h.store.Payouts.Set(id, payout)
 
-	h.emitEvent("payout.failed", payoutToMap(payout))
+	// The bank returns the funds to the account's balance.
+	h.returnPayoutFunds(payout, "payout_failure")
+	h.emitEvent(r.Context(), "payout.failed", payoutToMap(payout))
 
 	twincore.JSON(w, http.StatusOK, payout)
 }
 
-// advancePayoutState checks the simulated clock and transitions a single payout.
-func (h *Handler) advancePayoutState(p *store.Payout) {
-	now := h.store.Clock.Now().Unix()
-	elapsed := now - p.Created
+// CancelPayout handles POST /v1/payouts/{id}/cancel.
+// Stripe SDK: payout.Cancel(id, params)
+// Only pending payouts can be canceled; the funds return to the balance.
+func (h *Handler) CancelPayout(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
 
-	switch p.Status {
-	case store.PayoutStatusPending:
-		if elapsed >= payoutPaidDelay {
-			p.Status = store.PayoutStatusPaid
-			h.emitEvent("payout.paid", payoutToMap(*p))
-		} else if elapsed >= payoutInTransitDelay {
-			p.Status = store.PayoutStatusInTransit
-			h.emitEvent("payout.updated", payoutToMap(*p))
-		}
-	case store.PayoutStatusInTransit:
-		if elapsed >= payoutPaidDelay {
-			p.Status = store.PayoutStatusPaid
-			h.emitEvent("payout.paid", payoutToMap(*p))
+	payout, ok := h.store.Payouts.Get(id)
+	if !ok {
+		twincore.StripeError(w, http.StatusNotFound,
+			"invalid_request_error", "resource_missing",
+			"No such payout: '"+id+"'")
+		return
+	}
+
+	if payout.Status != store.PayoutStatusPending {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "payout_not_cancelable",
+			"This payout has a status of "+payout.Status+" and cannot be canceled. Only pending payouts can be canceled.")
+		return
+	}
+
+	payout.Status = store.PayoutStatusCanceled
+	h.store.Payouts.Set(id, payout)
+
+	h.returnPayoutFunds(payout, "payout_cancel")
+	h.emitEvent(r.Context(), "payout.canceled", payoutToMap(payout))
+
+	twincore.JSON(w, http.StatusOK, payout)
+}
+
+// payoutDestination returns the bank account a connected account's payouts
+// in currency go to: its default one for the currency, else its first.
+func (h *Handler) payoutDestination(accountID, currency string) string {
+	accts := h.getExternalAccountsForAccount(accountID).Data
+	for _, ea := range accts {
+		if ea.Currency == currency && ea.DefaultForCurrency {
+			return ea.ID
 		}
 	}
+	if len(accts) > 0 {
+		return accts[0].ID
+	}
+	return ""
 }
 
-// advanceAllPayoutStates processes all payouts in the store.
-func (h *Handler) advanceAllPayoutStates() {
-	ids := h.store.Payouts.ListIDs()
-	for _, id := range ids {
+// returnPayoutFunds credits a failed or canceled payout back to the balance
+// of the connected account it was paid from. Platform payouts never debit
+// a balance, so they have nothing to return.
+func (h *Handler) returnPayoutFunds(p store.Payout, txType string) {
+	ea, ok := h.store.ExternalAccts.Get(p.Destination)
+	if !ok {
+		return
+	}
+	h.store.CreditBalance(ea.AccountID, p.Currency, p.Amount)
+	h.store.RecordBalanceTransaction(txType, p.ID, p.Currency, p.Amount, 0)
+}
+
+// progressPayouts is the clock's derived-state function for payouts. It
+// moves each one along pending → in_transit → paid as simulated time
+// passes, sending payout.updated and payout.paid.
+func (h *Handler) progressPayouts(now time.Time) {
+	for _, id := range h.store.Payouts.ListIDs() {
 		p, ok := h.store.Payouts.Get(id)
 		if !ok {
 			continue
 		}
 		oldStatus := p.Status
-		h.advancePayoutState(&p)
+		h.advancePayoutState(&p, now.Unix())
 		if p.Status != oldStatus {
 			h.store.Payouts.Set(id, p)
 		}
 	}
 }
 
+// advancePayoutState transitions a single payout as of now.
+func (h *Handler) advancePayoutState(p *store.Payout, now int64) {
+	elapsed := now - p.Created
+
+	switch p.Status {
+	case store.PayoutStatusPending:
+		if elapsed >= payoutPaidDelay {
+			p.Status = store.PayoutStatusPaid
+			h.emitEvent(context.Background(), "payout.paid", payoutToMap(*p))
+		} else if elapsed >= payoutInTransitDelay {
+			p.Status = store.PayoutStatusInTransit
+			h.emitEvent(context.Background(), "payout.updated", payoutToMap(*p))
+		}
+	case store.PayoutStatusInTransit:
+		if elapsed >= payoutPaidDelay {
+			p.Status = store.PayoutStatusPaid
+			h.emitEvent(context.Background(), "payout.paid", payoutToMap(*p))
+		}
+	}
+}
+
 func payoutToMap(p store.Payout) map[string]any {
 	data, _ := json.Marshal(p)
 	var m map[string]any
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
+)
+
+// CreateRefund handles POST /v1/refunds.
+// Stripe SDK: refund.New(params)
+// Without an amount, the rest of the charge is refunded. The refund comes
+// out of the platform balance.
+func (h *Handler) CreateRefund(w http.ResponseWriter, r *http.Request) {
+	if err := parseFormOrJSON(r); err != nil {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_missing", err.Error())
+		return
+	}
+
+	chargeID := r.FormValue("charge")
+	charge, ok := h.store.Charges.Get(chargeID)
+	if !ok {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "resource_missing",
+			"No such charge: '"+chargeID+"'")
+		return
+	}
+
+	if charge.Refunded {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "charge_already_refunded",
+			"Charge "+chargeID+" has already been refunded.")
+		return
+	}
+	if d, ok := h.disputeForCharge(chargeID); ok && !d.IsChargeRefundable {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "charge_disputed",
+			"Charge "+chargeID+" has been charged back; cannot issue a refund.")
+		return
+	}
+
+	remaining := charge.Amount - charge.AmountRefunded
+	amount := remaining
+	if v := r.FormValue("amount"); v != "" {
+		// createRefundSchema has checked the amount.
+		amount, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if amount > remaining {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "amount_too_large",
+			fmt.Sprintf("Refund amount (%s) is greater than unrefunded amount on charge (%s)",
+				formatAmount(amount, charge.Currency), formatAmount(remaining, charge.Currency)))
+		return
+	}
+
+	// Stripe keeps its fee, so a full refund can take a balance holding only
+	// this charge below zero, as it does on Stripe.
+	h.store.CreditBalance("", charge.Currency, -amount)
+
+	id := h.store.Refunds.NextID()
+	refund := store.Refund{
+		ID:                 id,
+		Object:             "refund",
+		Amount:             amount,
+		BalanceTransaction: h.store.RecordBalanceTransaction("refund", id, charge.Currency, -amount, 0),
+		Charge:             chargeID,
+		Currency:           charge.Currency,
+		Reason:             r.FormValue("reason"),
+		Status:             "succeeded",
+		Metadata:           extractMetadata(r),
+		Created:            h.store.Clock.Now().Unix(),
+	}
+	h.store.Refunds.Set(id, refund)
+
+	charge.AmountRefunded += amount
+	charge.Refunded = charge.AmountRefunded == charge.Amount
+	h.store.Charges.Set(chargeID, charge)
+
+	h.emitEvent(r.Context(), "refund.created", refundToMap(refund))
+	h.emitEvent(r.Context(), "charge.refunded", chargeToMap(charge))
+
+	twincore.JSON(w, http.StatusOK, refund)
+}
+
+// GetRefund handles GET /v1/refunds/{id}.
+// Stripe SDK: refund.Get(id, nil)
+func (h *Handler) GetRefund(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	refund, ok := h.store.Refunds.Get(id)
+	if !ok {
+		twincore.StripeError(w, http.StatusNotFound,
+			"invalid_request_error", "resource_missing",
+			"No such refund: '"+id+"'")
+		return
+	}
+
+	twincore.JSON(w, http.StatusOK, refund)
+}
+
+// ListRefunds handles GET /v1/refunds, optionally filtered by ?charge=.
+func (h *Handler) ListRefunds(w http.ResponseWriter, r *http.Request) {
+	cursor := r.URL.Query().Get("starting_after")
+	chargeID := r.URL.Query().Get("charge")
+	limit := 10
+	if l := r.URL.Query().Get("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &limit)
+	}
+
+	if chargeID != "" {
+		filtered := h.store.Refunds.Filter(func(id string, re store.Refund) bool {
+			return re.Charge == chargeID
+		})
+		data := filtered
+		if len(data) > limit {
+			data = data[:limit]
+		}
+		twincore.JSON(w, http.StatusOK, map[string]any{
+			"object":   "list",
+			"url":      "/v1/refunds",
+			"data":     data,
+			"has_more": len(filtered) > limit,
+		})
+		return
+	}
+
+	page := h.store.Refunds.Paginate(cursor, limit)
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"object":   "list",
+		"url":      "/v1/refunds",
+		"data":     page.Data,
+		"has_more": page.HasMore,
+	})
+}
+
+// formatAmount renders an amount in minor units the way Stripe's error
+// messages do: 1000 usd is "$10.00".
+func formatAmount(amount int64, currency string) string {
+	s := fmt.Sprintf("%d.%02d", amount/100, amount%100)
+	if currency == "usd" {
+		return "$" + s
+	}
+	return s + " " + currency
+}
+
+func refundToMap(re store.Refund) map[string]any {
+	data, _ := json.Marshal(re)
+	var m map[string]any
+	json.Unmarshal(data, &m)
+	return m
+}
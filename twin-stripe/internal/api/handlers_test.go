@@ -1,7 +1,11 @@
 package api_test
 
 import (
+	"encoding/json"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
@@ -17,10 +21,12 @@ func setupStripe(t *testing.T) (*httptest.Server, *testutil.TwinClient) {
 	memStore := store.New()
 	cfg := &twincore.Config{Name: "twin-stripe-test"}
 	twin := twincore.New(cfg)
-	dispatcher := webhook.NewDispatcher(webhook.Config{})
+	twin.Router.Use(memStore.Clock.Middleware)
+	dispatcher := webhook.NewDispatcher(webhook.Config{StrictPayloads: true})
 	handler := api.NewHandler(memStore, dispatcher, twin.Middleware())
 	handler.Routes(twin.Router)
 	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetEventTrigger(handler)
 	adminHandler.Routes(twin.Router)
 	srv := httptest.NewServer(twin.Router)
 	t.Cleanup(srv.Close)
@@ -100,6 +106,21 @@ func TestListAccounts(t *testing.T) {
 	}
 }
 
+func TestListAccountsPagination(t *testing.T) {
+	_, tc := setupStripe(t)
+	auth := map[string]string{"Authorization": "Bearer sk_test_sim_123"}
+	create := func(int) string {
+		return stripePost(tc, "/v1/accounts", nil).AssertStatus(200).JSONMap()["id"].(string)
+	}
+
+	testutil.PaginationStress{
+		Records: 95,
+		Seed:    create,
+		List:    testutil.StartingAfter(tc, "/v1/accounts", 10, auth),
+		Write:   create,
+	}.Run(t)
+}
+
 func TestDeleteAccount(t *testing.T) {
 	_, tc := setupStripe(t)
 
@@ -178,6 +199,28 @@ func TestCreatePayout(t *testing.T) {
 	resp.AssertBodyContains("amount")
 }
 
+func TestCreateTransferValidation(t *testing.T) {
+	_, tc := setupStripe(t)
+
+	tests := []struct {
+		body  map[string]any
+		code  string
+		param string
+	}{
+		{map[string]any{"destination": "acct_1"}, "parameter_missing", "amount"},
+		{map[string]any{"amount": "ten", "destination": "acct_1"}, "parameter_invalid", "amount"},
+		{map[string]any{"amount": 100, "currency": "dollars", "destination": "acct_1"}, "parameter_invalid", "currency"},
+	}
+	for _, tt := range tests {
+		resp := stripePost(tc, "/v1/transfers", tt.body)
+		resp.AssertStatus(400)
+		e, _ := resp.JSONMap()["error"].(map[string]any)
+		if e["type"] != "invalid_request_error" || e["code"] != tt.code || e["param"] != tt.param {
+			t.Errorf("%v: expected %s for %s, got %v", tt.body, tt.code, tt.param, e)
+		}
+	}
+}
+
 func TestListEvents(t *testing.T) {
 	_, tc := setupStripe(t)
 
@@ -238,3 +281,509 @@ func TestAdminFailPayout(t *testing.T) {
 	resp := tc.Post("/admin/payouts/po_nonexistent/fail", nil)
 	resp.AssertStatus(404)
 }
+
+// stripeForm sends a form-encoded POST with Stripe auth, as the SDKs do,
+// acting on a connected account when account is set.
+func stripeForm(t *testing.T, srv *httptest.Server, path string, form url.Values, account string) (int, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest("POST", srv.URL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer sk_test_sim_123")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if account != "" {
+		req.Header.Set("Stripe-Account", account)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var m map[string]any
+	json.NewDecoder(resp.Body).Decode(&m)
+	return resp.StatusCode, m
+}
+
+// visitLink opens an account link without following its redirect and
+// returns where it redirects to.
+func visitLink(t *testing.T, link string) string {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(link)
+	if err != nil {
+		t.Fatalf("visiting account link: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected a redirect from the account link, got %d", resp.StatusCode)
+	}
+	return resp.Header.Get("Location")
+}
+
+// onboardAccount creates a connected account requesting transfers and
+// completes its onboarding through an account link.
+func onboardAccount(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	_, acct := stripeForm(t, srv, "/v1/accounts", url.Values{"type": {"custom"}, "capabilities[transfers][requested]": {"true"}}, "")
+	id := acct["id"].(string)
+	_, link := stripeForm(t, srv, "/v1/account_links", url.Values{
+		"account":     {id},
+		"type":        {"account_onboarding"},
+		"refresh_url": {"https://example.com/reauth"},
+		"return_url":  {"https://example.com/return"},
+	}, "")
+	if got := visitLink(t, link["url"].(string)); got != "https://example.com/return" {
+		t.Fatalf("expected a redirect to return_url, got %q", got)
+	}
+	return id
+}
+
+func TestConnectOnboarding(t *testing.T) {
+	srv, tc := setupStripe(t)
+
+	status, acct := stripeForm(t, srv, "/v1/accounts", url.Values{"type": {"custom"}, "capabilities[transfers][requested]": {"true"}}, "")
+	if status != 200 {
+		t.Fatalf("expected 200, got %d: %v", status, acct)
+	}
+	id := acct["id"].(string)
+	req := acct["requirements"].(map[string]any)
+	if req["disabled_reason"] != "requirements.past_due" || len(req["past_due"].([]any)) == 0 {
+		t.Errorf("expected a new account to have requirements past due, got %v", req)
+	}
+	if acct["charges_enabled"] != false || acct["capabilities"].(map[string]any)["transfers"] != "inactive" {
+		t.Errorf("expected a new account to be disabled, got %v", acct)
+	}
+
+	// Not onboarded: no transfers in, no payouts out.
+	status, body := stripeForm(t, srv, "/v1/transfers", url.Values{"amount": {"100"}, "destination": {id}}, "")
+	if status != 400 || body["error"].(map[string]any)["code"] != "insufficient_capabilities_for_transfer" {
+		t.Errorf("expected a transfer to an unverified account to fail, got %d %v", status, body)
+	}
+	status, body = stripeForm(t, srv, "/v1/payouts", url.Values{"amount": {"100"}}, id)
+	if status != 400 || body["error"].(map[string]any)["code"] != "payouts_not_allowed" {
+		t.Errorf("expected a payout from an unverified account to fail, got %d %v", status, body)
+	}
+
+	// Onboard through an account link.
+	status, link := stripeForm(t, srv, "/v1/account_links", url.Values{
+		"account":     {id},
+		"type":        {"account_onboarding"},
+		"refresh_url": {"https://example.com/reauth"},
+		"return_url":  {"https://example.com/return"},
+	}, "")
+	if status != 200 || link["object"] != "account_link" || link["expires_at"].(float64) <= link["created"].(float64) {
+		t.Fatalf("unexpected account link: %d %v", status, link)
+	}
+	if got := visitLink(t, link["url"].(string)); got != "https://example.com/return" {
+		t.Errorf("expected a redirect to return_url, got %q", got)
+	}
+	if got := visitLink(t, link["url"].(string)); got != "https://example.com/reauth" {
+		t.Errorf("expected a used link to redirect to refresh_url, got %q", got)
+	}
+
+	acct = stripeGet(tc, "/v1/accounts/"+id).JSONMap()
+	req = acct["requirements"].(map[string]any)
+	if len(req["currently_due"].([]any)) != 0 || req["disabled_reason"] != nil {
+		t.Errorf("expected nothing due after onboarding, got %v", req)
+	}
+	if acct["payouts_enabled"] != true || acct["details_submitted"] != true || acct["capabilities"].(map[string]any)["transfers"] != "active" {
+		t.Errorf("expected an onboarded account to be enabled, got %v", acct)
+	}
+	if banks := acct["external_accounts"].(map[string]any)["data"].([]any); len(banks) != 1 {
+		t.Errorf("expected onboarding to add a bank account, got %v", banks)
+	}
+
+	events := stripeGet(tc, "/v1/events?type=account.updated&limit=100").JSONMap()["data"].([]any)
+	if len(events) < 2 {
+		t.Errorf("expected account.updated on creation and onboarding, got %d", len(events))
+	}
+}
+
+func TestConnectAccountLinkExpires(t *testing.T) {
+	srv, tc := setupStripe(t)
+
+	_, acct := stripeForm(t, srv, "/v1/accounts", nil, "")
+	_, link := stripeForm(t, srv, "/v1/account_links", url.Values{
+		"account":     {acct["id"].(string)},
+		"type":        {"account_onboarding"},
+		"refresh_url": {"https://example.com/reauth"},
+		"return_url":  {"https://example.com/return"},
+	}, "")
+	tc.Post("/admin/time/advance", map[string]string{"duration": "10m"}).AssertStatus(200)
+	if got := visitLink(t, link["url"].(string)); got != "https://example.com/reauth" {
+		t.Errorf("expected an expired link to redirect to refresh_url, got %q", got)
+	}
+
+	status, body := stripeForm(t, srv, "/v1/account_links", url.Values{"account": {acct["id"].(string)}, "type": {"onboarding"}}, "")
+	if status != 400 || body["error"].(map[string]any)["param"] != "type" {
+		t.Errorf("expected an invalid type to be rejected, got %d %v", status, body)
+	}
+}
+
+func TestConnectSettlement(t *testing.T) {
+	srv, tc := setupStripe(t)
+	id := onboardAccount(t, srv)
+
+	status, transfer := stripeForm(t, srv, "/v1/transfers", url.Values{"amount": {"5000"}, "currency": {"usd"}, "destination": {id}}, "")
+	if status != 200 {
+		t.Fatalf("transfer failed: %d %v", status, transfer)
+	}
+
+	status, payout := stripeForm(t, srv, "/v1/payouts", url.Values{"amount": {"2000"}, "currency": {"usd"}}, id)
+	if status != 200 || payout["status"] != "pending" || payout["destination"] == "" {
+		t.Fatalf("unexpected payout: %d %v", status, payout)
+	}
+	if got := connectedBalance(t, srv, id); got != 3000 {
+		t.Errorf("expected 3000 available after the payout, got %d", got)
+	}
+
+	// payout.paid is sent as simulated time passes, without polling the payout.
+	tc.Post("/admin/time/advance", map[string]string{"duration": "2h"}).AssertStatus(200)
+	paid := stripeGet(tc, "/v1/events?type=payout.paid").JSONMap()["data"].([]any)
+	if len(paid) != 1 {
+		t.Fatalf("expected payout.paid after 2h, got %d events", len(paid))
+	}
+	if got := stripeGet(tc, "/v1/payouts/"+payout["id"].(string)).JSONMap()["status"]; got != "paid" {
+		t.Errorf("expected the payout paid, got %v", got)
+	}
+	if status, _ := stripeForm(t, srv, "/v1/payouts/"+payout["id"].(string)+"/cancel", nil, id); status != 400 {
+		t.Errorf("expected a paid payout not to be cancelable, got %d", status)
+	}
+
+	// Canceled and failed payouts return their funds.
+	_, canceled := stripeForm(t, srv, "/v1/payouts", url.Values{"amount": {"1000"}}, id)
+	status, canceled = stripeForm(t, srv, "/v1/payouts/"+canceled["id"].(string)+"/cancel", nil, id)
+	if status != 200 || canceled["status"] != "canceled" {
+		t.Errorf("expected the payout canceled, got %d %v", status, canceled)
+	}
+	status, _ = stripeForm(t, srv, "/v1/payouts/"+canceled["id"].(string)+"/cancel", nil, id)
+	if status != 400 {
+		t.Errorf("expected a canceled payout not to be cancelable again, got %d", status)
+	}
+	_, failed := stripeForm(t, srv, "/v1/payouts", url.Values{"amount": {"500"}}, id)
+	tc.Post("/admin/payouts/"+failed["id"].(string)+"/fail", nil).AssertStatus(200)
+	if got := connectedBalance(t, srv, id); got != 3000 {
+		t.Errorf("expected canceled and failed payouts returned to the balance, got %d", got)
+	}
+}
+
+func TestRejectAccount(t *testing.T) {
+	srv, _ := setupStripe(t)
+	id := onboardAccount(t, srv)
+
+	status, acct := stripeForm(t, srv, "/v1/accounts/"+id+"/reject", url.Values{"reason": {"fraud"}}, "")
+	if status != 200 || acct["requirements"].(map[string]any)["disabled_reason"] != "rejected.fraud" {
+		t.Fatalf("unexpected rejected account: %d %v", status, acct)
+	}
+	if acct["charges_enabled"] != false || acct["payouts_enabled"] != false {
+		t.Errorf("expected a rejected account disabled, got %v", acct)
+	}
+	status, _ = stripeForm(t, srv, "/v1/transfers", url.Values{"amount": {"100"}, "destination": {id}}, "")
+	if status != 400 {
+		t.Errorf("expected a transfer to a rejected account to fail, got %d", status)
+	}
+}
+
+// connectedBalance returns a connected account's available USD balance.
+func connectedBalance(t *testing.T, srv *httptest.Server, account string) int64 {
+	t.Helper()
+	req, _ := http.NewRequest("GET", srv.URL+"/v1/balance", nil)
+	req.Header.Set("Authorization", "Bearer sk_test_sim_123")
+	req.Header.Set("Stripe-Account", account)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var b struct {
+		Available []struct {
+			Amount   int64  `json:"amount"`
+			Currency string `json:"currency"`
+		} `json:"available"`
+	}
+	json.NewDecoder(resp.Body).Decode(&b)
+	for _, a := range b.Available {
+		if a.Currency == "usd" {
+			return a.Amount
+		}
+	}
+	return 0
+}
+
+func TestChargeAndRefund(t *testing.T) {
+	srv, tc := setupStripe(t)
+
+	status, charge := stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"10000"}, "currency": {"usd"}, "source": {"tok_visa"}}, "")
+	if status != 200 || charge["status"] != "succeeded" {
+		t.Fatalf("unexpected charge: %d %v", status, charge)
+	}
+	chargeID := charge["id"].(string)
+	// 2.9% + 30¢ of $100 is $3.20.
+	if got := connectedBalance(t, srv, ""); got != 9680 {
+		t.Errorf("expected the charge net of fees in the platform balance, got %d", got)
+	}
+
+	status, refund := stripeForm(t, srv, "/v1/refunds", url.Values{"charge": {chargeID}, "amount": {"4000"}, "reason": {"requested_by_customer"}}, "")
+	if status != 200 || refund["amount"].(float64) != 4000 || refund["status"] != "succeeded" {
+		t.Fatalf("unexpected refund: %d %v", status, refund)
+	}
+	charge = stripeGet(tc, "/v1/charges/"+chargeID).JSONMap()
+	if charge["amount_refunded"].(float64) != 4000 || charge["refunded"] != false {
+		t.Errorf("expected a partially refunded charge, got %v", charge)
+	}
+
+	status, body := stripeForm(t, srv, "/v1/refunds", url.Values{"charge": {chargeID}, "amount": {"7000"}}, "")
+	if status != 400 || body["error"].(map[string]any)["message"] != "Refund amount ($70.00) is greater than unrefunded amount on charge ($60.00)" {
+		t.Errorf("expected an over-refund to fail, got %d %v", status, body)
+	}
+
+	// Without an amount, the rest is refunded.
+	status, refund = stripeForm(t, srv, "/v1/refunds", url.Values{"charge": {chargeID}}, "")
+	if status != 200 || refund["amount"].(float64) != 6000 {
+		t.Fatalf("unexpected refund: %d %v", status, refund)
+	}
+	if got := stripeGet(tc, "/v1/charges/"+chargeID).JSONMap()["refunded"]; got != true {
+		t.Errorf("expected the charge fully refunded, got %v", got)
+	}
+	if got := connectedBalance(t, srv, ""); got != -320 {
+		t.Errorf("expected the fee lost after a full refund, got %d", got)
+	}
+	status, body = stripeForm(t, srv, "/v1/refunds", url.Values{"charge": {chargeID}}, "")
+	if status != 400 || body["error"].(map[string]any)["code"] != "charge_already_refunded" {
+		t.Errorf("expected a second full refund to fail, got %d %v", status, body)
+	}
+
+	refunds := stripeGet(tc, "/v1/refunds?charge="+chargeID).JSONMap()["data"].([]any)
+	if len(refunds) != 2 {
+		t.Errorf("expected 2 refunds for the charge, got %d", len(refunds))
+	}
+	if n := len(stripeGet(tc, "/v1/events?type=charge.refunded").JSONMap()["data"].([]any)); n != 2 {
+		t.Errorf("expected charge.refunded per refund, got %d", n)
+	}
+
+	status, body = stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"500"}, "source": {"tok_chargeDeclined"}}, "")
+	if status != 402 || body["error"].(map[string]any)["code"] != "card_declined" {
+		t.Errorf("expected a declined card, got %d %v", status, body)
+	}
+}
+
+func TestDisputeEvidenceFlow(t *testing.T) {
+	srv, tc := setupStripe(t)
+
+	_, charge := stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"10000"}}, "")
+	chargeID := charge["id"].(string)
+
+	resp := tc.Post("/admin/disputes/create", map[string]any{"charge": chargeID, "reason": "product_not_received"})
+	resp.AssertStatus(200)
+	dispute := resp.JSONMap()
+	id := dispute["id"].(string)
+	if dispute["status"] != "needs_response" || dispute["amount"].(float64) != 10000 {
+		t.Fatalf("unexpected dispute: %v", dispute)
+	}
+	// The disputed amount and the $15 fee are withdrawn.
+	if got := connectedBalance(t, srv, ""); got != 9680-10000-1500 {
+		t.Errorf("expected the dispute withdrawn from the balance, got %d", got)
+	}
+	tc.Post("/admin/disputes/create", map[string]any{"charge": chargeID}).AssertStatus(409)
+
+	status, body := stripeForm(t, srv, "/v1/refunds", url.Values{"charge": {chargeID}}, "")
+	if status != 400 || body["error"].(map[string]any)["code"] != "charge_disputed" {
+		t.Errorf("expected a disputed charge not to be refundable, got %d %v", status, body)
+	}
+
+	// Stage evidence, then submit it.
+	status, dispute = stripeForm(t, srv, "/v1/disputes/"+id, url.Values{"evidence[customer_name]": {"Jenny Rosen"}, "submit": {"false"}}, "")
+	if status != 200 || dispute["status"] != "needs_response" || dispute["evidence"].(map[string]any)["customer_name"] != "Jenny Rosen" {
+		t.Fatalf("unexpected staged dispute: %d %v", status, dispute)
+	}
+	status, dispute = stripeForm(t, srv, "/v1/disputes/"+id, url.Values{"evidence[uncategorized_text]": {"winning_evidence"}}, "")
+	if status != 200 || dispute["status"] != "won" {
+		t.Fatalf("expected winning evidence to win the dispute, got %d %v", status, dispute)
+	}
+	details := dispute["evidence_details"].(map[string]any)
+	if details["submission_count"].(float64) != 1 || details["has_evidence"] != true {
+		t.Errorf("unexpected evidence details: %v", details)
+	}
+	if got := connectedBalance(t, srv, ""); got != 9680-1500 {
+		t.Errorf("expected the disputed amount reinstated, less the fee, got %d", got)
+	}
+	for _, typ := range []string{"charge.dispute.created", "charge.dispute.funds_withdrawn", "charge.dispute.updated", "charge.dispute.closed", "charge.dispute.funds_reinstated"} {
+		if n := len(stripeGet(tc, "/v1/events?type="+typ).JSONMap()["data"].([]any)); n == 0 {
+			t.Errorf("expected a %s event", typ)
+		}
+	}
+	status, _ = stripeForm(t, srv, "/v1/disputes/"+id, url.Values{"evidence[customer_name]": {"x"}}, "")
+	if status != 400 {
+		t.Errorf("expected evidence on a closed dispute to be rejected, got %d", status)
+	}
+}
+
+func TestDisputeLost(t *testing.T) {
+	srv, tc := setupStripe(t)
+
+	// tok_createDispute disputes the charge straight away.
+	_, charge := stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"2000"}, "source": {"tok_createDispute"}}, "")
+	if charge["disputed"] != true {
+		t.Fatalf("expected the charge disputed, got %v", charge)
+	}
+	disputes := stripeGet(tc, "/v1/disputes").JSONMap()["data"].([]any)
+	if len(disputes) != 1 {
+		t.Fatalf("expected 1 dispute, got %d", len(disputes))
+	}
+	id := disputes[0].(map[string]any)["id"].(string)
+
+	// Left unanswered past its deadline, the dispute is lost.
+	tc.Post("/admin/time/advance", map[string]string{"duration": "168h"}).AssertStatus(200)
+	dispute := stripeGet(tc, "/v1/disputes/"+id).JSONMap()
+	if dispute["status"] != "lost" || dispute["evidence_details"].(map[string]any)["past_due"] != true {
+		t.Errorf("expected an unanswered dispute lost, got %v", dispute)
+	}
+
+	// Accepting and resolving through the admin API.
+	_, charge = stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"2000"}}, "")
+	resp := tc.Post("/admin/disputes/create", map[string]any{"charge": charge["id"]})
+	id = resp.JSONMap()["id"].(string)
+	status, dispute := stripeForm(t, srv, "/v1/disputes/"+id+"/close", nil, "")
+	if status != 200 || dispute["status"] != "lost" {
+		t.Errorf("expected closing to accept the dispute, got %d %v", status, dispute)
+	}
+	tc.Post("/admin/disputes/"+id+"/resolve", map[string]string{"status": "won"}).AssertStatus(400)
+
+	_, charge = stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"2000"}}, "")
+	id = tc.Post("/admin/disputes/create", map[string]any{"charge": charge["id"]}).JSONMap()["id"].(string)
+	stripeForm(t, srv, "/v1/disputes/"+id, url.Values{"evidence[customer_name]": {"Jenny Rosen"}}, "")
+	resp = tc.Post("/admin/disputes/"+id+"/resolve", map[string]string{"status": "lost"})
+	resp.AssertStatus(200)
+	if got := resp.JSONMap()["status"]; got != "lost" {
+		t.Errorf("expected the dispute under review resolved as lost, got %v", got)
+	}
+}
+
+func TestStripeVersion(t *testing.T) {
+	srv, tc := setupStripe(t)
+
+	_, charge := stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"1000"}}, "")
+	chargeID := charge["id"].(string)
+	stripeForm(t, srv, "/v1/refunds", url.Values{"charge": {chargeID}, "amount": {"400"}}, "")
+
+	get := func(version string) *testutil.Response {
+		return tc.DoWithHeaders("GET", "/v1/charges/"+chargeID, nil, map[string]string{
+			"Authorization":  "Bearer sk_test_sim_123",
+			"Stripe-Version": version,
+		})
+	}
+
+	resp := get("2024-12-18.acacia")
+	resp.AssertStatus(200)
+	if got := resp.Headers.Get("Stripe-Version"); got != "2024-12-18.acacia" {
+		t.Errorf("expected the version echoed, got %q", got)
+	}
+	if _, ok := resp.JSONMap()["refunds"]; ok {
+		t.Error("expected current charges not to include refunds")
+	}
+
+	// Before 2022-11-15, charges include their refunds.
+	resp = get("2022-08-01")
+	resp.AssertStatus(200)
+	refunds, ok := resp.JSONMap()["refunds"].(map[string]any)
+	if !ok || refunds["total_count"].(float64) != 1 {
+		t.Errorf("expected the charge's refunds on an older version, got %v", resp.JSONMap()["refunds"])
+	}
+
+	resp = get("2019-01-01")
+	resp.AssertStatus(400)
+	resp.AssertBodyContains("Invalid Stripe API version: 2019-01-01")
+}
+
+func TestEventPayloadsMatchSchemas(t *testing.T) {
+	memStore := store.New()
+	twin := twincore.New(&twincore.Config{Name: "twin-stripe-test"})
+	dispatcher := webhook.NewDispatcher(webhook.Config{StrictPayloads: true})
+	handler := api.NewHandler(memStore, dispatcher, twin.Middleware())
+
+	// The dispatcher panics on a payload that does not match its schema.
+	for _, et := range handler.EventCatalog() {
+		handler.TriggerEvent(et.Type, et.Example)
+	}
+	if got := len(dispatcher.QueuedEvents()); got != len(handler.EventCatalog()) {
+		t.Errorf("expected every catalog event to be queued, got %d", got)
+	}
+
+	err := dispatcher.CheckPayload("payout.paid", map[string]any{
+		"id": "evt_1", "object": "event", "type": "payout.paid",
+		"data":        map[string]any{"object": map[string]any{"id": "po_1", "object": "payout", "amount": "5000"}},
+		"api_version": "2024-04-10", "created": 1, "livemode": false, "pending_webhooks": 1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "data.object.amount must be an integer") {
+		t.Errorf("expected a malformed payout to be rejected, got %v", err)
+	}
+}
+
+func TestConnectEventsCarryAccount(t *testing.T) {
+	memStore := store.New()
+	twin := twincore.New(&twincore.Config{Name: "twin-stripe-test"})
+	dispatcher := webhook.NewDispatcher(webhook.Config{StrictPayloads: true})
+	api.NewHandler(memStore, dispatcher, twin.Middleware()).Routes(twin.Router)
+	srv := httptest.NewServer(twin.Router)
+	t.Cleanup(srv.Close)
+
+	stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"1000"}}, "acct_merchant")
+	stripeForm(t, srv, "/v1/charges", url.Values{"amount": {"1000"}}, "")
+
+	events := dispatcher.QueuedEvents()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 queued events, got %d", len(events))
+	}
+	// The connected account's event routes to its endpoint and names it,
+	// as Stripe's Connect webhooks do; the platform's does neither.
+	if events[0].Tenant != "acct_merchant" || events[0].Payload["account"] != "acct_merchant" {
+		t.Errorf("expected the event tagged with acct_merchant, got tenant %q account %v", events[0].Tenant, events[0].Payload["account"])
+	}
+	if _, ok := events[1].Payload["account"]; ok || events[1].Tenant != "" {
+		t.Errorf("expected the platform event untagged, got %+v", events[1])
+	}
+}
+
+func TestTriggerEvent(t *testing.T) {
+	_, tc := setupStripe(t)
+
+	resp := tc.Get("/admin/webhooks/events/catalog")
+	resp.AssertStatus(200)
+	var catalog []admin.EventType
+	resp.JSON(&catalog)
+	types := map[string]admin.EventType{}
+	for _, et := range catalog {
+		types[et.Type] = et
+	}
+	for _, typ := range []string{"charge.succeeded", "charge.dispute.created", "payout.paid", "account.updated"} {
+		if _, ok := types[typ]; !ok {
+			t.Errorf("expected %s in the catalog", typ)
+		}
+	}
+	if example := types["payout.failed"].Example; example["object"] != "payout" || example["status"] != "failed" {
+		t.Errorf("expected a failed payout as the payout.failed example, got %v", example)
+	}
+
+	resp = tc.Post("/admin/webhooks/trigger", map[string]any{
+		"type":      "charge.succeeded",
+		"overrides": map[string]any{"amount": 5000, "metadata": map[string]string{"order_id": "42"}},
+	})
+	resp.AssertStatus(200)
+	evt := resp.JSONMap()["event"].(map[string]any)
+	if evt["object"] != "event" || evt["type"] != "charge.succeeded" {
+		t.Errorf("expected a Stripe event, got %v", evt)
+	}
+
+	// The event is listed like any other, carrying the overridden charge.
+	resp = stripeGet(tc, "/v1/events/"+evt["id"].(string))
+	resp.AssertStatus(200)
+	object := resp.JSONMap()["data"].(map[string]any)["object"].(map[string]any)
+	if object["id"] != "ch_example" || object["amount"] != float64(5000) || object["metadata"].(map[string]any)["order_id"] != "42" {
+		t.Errorf("expected the example charge with overrides, got %v", object)
+	}
+
+	resp = tc.Post("/admin/webhooks/trigger", map[string]any{"type": "customer.created"})
+	resp.AssertStatus(404)
+	resp.AssertBodyContains("charge.succeeded")
+}
@@ -40,12 +40,18 @@ func (h *Handler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify destination account exists
-	if _, ok := h.store.Accounts.Get(destination); !ok {
+	// Verify destination account exists and can receive funds
+	acct, ok := h.store.Accounts.Get(destination)
+	if !ok {
 		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "resource_missing",
 			"No such account: '"+destination+"'")
 		return
 	}
+	if !transferCapable(acct) {
+		twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "insufficient_capabilities_for_transfer",
+			"Your destination account needs to have at least one of the following capabilities enabled: transfers, crypto_transfers, legacy_payments")
+		return
+	}
 
 	currency := r.FormValue("currency")
 	if currency == "" {
@@ -82,10 +88,10 @@ func (h *Handler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
 	h.store.RecordBalanceTransaction("transfer", transfer.ID, currency, amount, 0)  // destination credit
 
 	// Emit transfer.created event
-	h.emitEvent("transfer.created", transferToMap(transfer))
+	h.emitEvent(r.Context(), "transfer.created", transferToMap(transfer))
 
 	// Emit transfer.paid event (in sim, transfers complete instantly)
-	h.emitEvent("transfer.paid", transferToMap(transfer))
+	h.emitEvent(r.Context(), "transfer.paid", transferToMap(transfer))
 
 	twincore.JSON(w, http.StatusOK, transfer)
 }
@@ -2,10 +2,12 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
 	"github.com/wondertwin-ai/wondertwin/twinkit/webhook"
 	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
@@ -16,11 +18,63 @@ type Handler struct {
 	store      *store.MemoryStore
 	dispatcher *webhook.Dispatcher
 	mw         *twincore.Middleware
+	versions   *twincore.Versioning
 }
 
-// NewHandler creates a new API handler.
+// NewHandler creates a new API handler. It registers payout progression
+// and dispute deadlines with the store's clock, so payouts settle and
+// unanswered disputes are lost as simulated time passes.
 func NewHandler(s *store.MemoryStore, d *webhook.Dispatcher, mw *twincore.Middleware) *Handler {
-	return &Handler{store: s, dispatcher: d, mw: mw}
+	mw.WriteValidationError = writeValidationError
+	mw.WriteStorageError = writeStorageError
+	mw.WriteAuthError = writeAuthError
+	mw.WriteBodyTooLarge = writeBodyTooLarge
+	if d != nil {
+		registerEventSchemas(d)
+	}
+	h := &Handler{store: s, dispatcher: d, mw: mw}
+	h.versions = h.newVersioning()
+	s.Clock.Derive(h.progressPayouts)
+	s.Clock.Derive(h.expireDisputes)
+	return h
+}
+
+// writeStorageError answers a request whose store operation failed the way
+// Stripe reports an internal error.
+func writeStorageError(w http.ResponseWriter, r *http.Request, failure *pkgstore.StorageFailure) {
+	twincore.JSON(w, http.StatusInternalServerError, map[string]any{
+		"error": map[string]any{
+			"type":    "api_error",
+			"message": "An unknown error occurred",
+		},
+	})
+}
+
+// writeBodyTooLarge answers a request body over the size limit the way
+// Stripe rejects an oversized request.
+func writeBodyTooLarge(w http.ResponseWriter, r *http.Request, limitKB int) {
+	twincore.StripeError(w, http.StatusRequestEntityTooLarge,
+		"invalid_request_error", "",
+		fmt.Sprintf("Request size exceeded the limit of %d KB. Reduce the size of the request and try again.", limitKB))
+}
+
+// writeAuthError answers a request failed by an auth fault preset the way
+// Stripe rejects a bad, expired, or restricted API key.
+func writeAuthError(w http.ResponseWriter, r *http.Request, preset string) {
+	switch preset {
+	case twincore.FaultAuthExpired:
+		twincore.StripeError(w, http.StatusUnauthorized,
+			"invalid_request_error", "api_key_expired",
+			"Expired API Key provided: sk_test_********.")
+	case twincore.FaultForbiddenScope:
+		twincore.StripeError(w, http.StatusForbidden,
+			"invalid_request_error", "secret_key_required",
+			"The provided key does not have the required permissions for this endpoint.")
+	default:
+		twincore.StripeError(w, http.StatusUnauthorized,
+			"invalid_request_error", "",
+			"Invalid API Key provided: sk_test_********.")
+	}
 }
 
 // Routes mounts the Stripe v1 API routes.
@@ -28,6 +82,8 @@ func (h *Handler) Routes(r chi.Router) {
 	r.Route("/v1", func(r chi.Router) {
 		// Auth middleware for all v1 routes
 		r.Use(h.authMiddleware)
+		// Stripe-Version negotiation; responses are downgraded for older versions
+		r.Use(h.versions.Middleware)
 		// Idempotency key caching for POST requests
 		r.Use(h.idempotencyMiddleware)
 		// Fault injection for API routes (not admin)
@@ -39,6 +95,10 @@ func (h *Handler) Routes(r chi.Router) {
 		r.Post("/accounts/{id}", h.UpdateAccount)
 		r.Delete("/accounts/{id}", h.DeleteAccount)
 		r.Get("/accounts", h.ListAccounts)
+		r.With(h.mw.Validate(rejectAccountSchema)).Post("/accounts/{id}/reject", h.RejectAccount)
+
+		// Account Links
+		r.With(h.mw.Validate(createAccountLinkSchema)).Post("/account_links", h.CreateAccountLink)
 
 		// External Accounts
 		r.Post("/accounts/{account_id}/external_accounts", h.CreateExternalAccount)
@@ -47,17 +107,34 @@ func (h *Handler) Routes(r chi.Router) {
 		r.Delete("/accounts/{account_id}/external_accounts/{id}", h.DeleteExternalAccount)
 
 		// Transfers
-		r.Post("/transfers", h.CreateTransfer)
+		r.With(h.mw.Validate(createTransferSchema)).Post("/transfers", h.CreateTransfer)
 		r.Get("/transfers/{id}", h.GetTransfer)
 		r.Get("/transfers", h.ListTransfers)
 
+		// Charges
+		r.With(h.mw.Validate(createChargeSchema)).Post("/charges", h.CreateCharge)
+		r.Get("/charges/{id}", h.GetCharge)
+		r.Get("/charges", h.ListCharges)
+
+		// Refunds
+		r.With(h.mw.Validate(createRefundSchema)).Post("/refunds", h.CreateRefund)
+		r.Get("/refunds/{id}", h.GetRefund)
+		r.Get("/refunds", h.ListRefunds)
+
+		// Disputes
+		r.Get("/disputes/{id}", h.GetDispute)
+		r.With(h.mw.Validate(updateDisputeSchema)).Post("/disputes/{id}", h.UpdateDispute)
+		r.Post("/disputes/{id}/close", h.CloseDispute)
+		r.Get("/disputes", h.ListDisputes)
+
 		// Balance
 		r.Get("/balance", h.GetBalance)
 
 		// Payouts
-		r.Post("/payouts", h.CreatePayout)
+		r.With(h.mw.Validate(createPayoutSchema)).Post("/payouts", h.CreatePayout)
 		r.Get("/payouts/{id}", h.GetPayout)
 		r.Get("/payouts", h.ListPayouts)
+		r.Post("/payouts/{id}/cancel", h.CancelPayout)
 
 		// Balance Transactions
 		r.Get("/balance_transactions", h.ListBalanceTransactions)
@@ -68,9 +145,15 @@ func (h *Handler) Routes(r chi.Router) {
 		r.Get("/events/{id}", h.GetEvent)
 	})
 
+	// Hosted onboarding that account links point to (outside /v1, no auth,
+	// as a browser visits it)
+	r.Get("/connect/onboarding/{id}", h.VisitAccountLink)
+
 	// Stripe-specific admin endpoints (outside /v1, no auth)
 	r.Post("/admin/payouts/{id}/fail", h.AdminFailPayout)
 	r.Post("/admin/accounts/{id}/fund", h.AdminFundAccount)
+	r.Post("/admin/disputes/create", h.AdminCreateDispute)
+	r.Post("/admin/disputes/{id}/resolve", h.AdminResolveDispute)
 }
 
 // authMiddleware validates Stripe-style Bearer token authentication.
@@ -97,6 +180,12 @@ func (h *Handler) authMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Events raised on behalf of a connected account go to that
+		// account's webhook endpoint, when one is configured.
+		if acct := stripeAccountFromRequest(r); acct != "" {
+			r = r.WithContext(webhook.WithTenant(r.Context(), acct))
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
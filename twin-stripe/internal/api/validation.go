@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Request schemas enforced before the handlers run. Unknown parameters are
+// allowed: the twin does not model every parameter Stripe accepts.
+var (
+	createTransferSchema = twincore.MustParseSchema(`{
+		"type": "object",
+		"required": ["amount", "destination"],
+		"properties": {
+			"amount": {"type": "integer", "minimum": 1},
+			"currency": {"type": "string", "pattern": "^[a-zA-Z]{3}$"},
+			"destination": {"type": "string", "minLength": 1},
+			"description": {"type": "string"},
+			"transfer_group": {"type": "string"},
+			"source_transaction": {"type": "string"},
+			"metadata": {"type": "object"}
+		}
+	}`)
+
+	createAccountLinkSchema = twincore.MustParseSchema(`{
+		"type": "object",
+		"required": ["account", "type"],
+		"properties": {
+			"account": {"type": "string", "minLength": 1},
+			"type": {"type": "string", "enum": ["account_onboarding", "account_update"]},
+			"refresh_url": {"type": "string"},
+			"return_url": {"type": "string"},
+			"collect": {"type": "string", "enum": ["currently_due", "eventually_due"]}
+		}
+	}`)
+
+	rejectAccountSchema = twincore.MustParseSchema(`{
+		"type": "object",
+		"required": ["reason"],
+		"properties": {
+			"reason": {"type": "string", "enum": ["fraud", "terms_of_service", "other"]}
+		}
+	}`)
+
+	createChargeSchema = twincore.MustParseSchema(`{
+		"type": "object",
+		"required": ["amount"],
+		"properties": {
+			"amount": {"type": "integer", "minimum": 1},
+			"currency": {"type": "string", "pattern": "^[a-zA-Z]{3}$"},
+			"source": {"type": "string"},
+			"description": {"type": "string"},
+			"transfer_group": {"type": "string"},
+			"metadata": {"type": "object"}
+		}
+	}`)
+
+	createRefundSchema = twincore.MustParseSchema(`{
+		"type": "object",
+		"required": ["charge"],
+		"properties": {
+			"charge": {"type": "string", "minLength": 1},
+			"amount": {"type": "integer", "minimum": 1},
+			"reason": {"type": "string", "enum": ["duplicate", "fraudulent", "requested_by_customer"]},
+			"metadata": {"type": "object"}
+		}
+	}`)
+
+	updateDisputeSchema = twincore.MustParseSchema(`{
+		"type": "object",
+		"properties": {
+			"evidence": {"type": "object"},
+			"submit": {"type": "boolean"},
+			"metadata": {"type": "object"}
+		}
+	}`)
+
+	createPayoutSchema = twincore.MustParseSchema(`{
+		"type": "object",
+		"required": ["amount"],
+		"properties": {
+			"amount": {"type": "integer", "minimum": 1},
+			"currency": {"type": "string", "pattern": "^[a-zA-Z]{3}$"},
+			"description": {"type": "string"},
+			"method": {"type": "string", "enum": ["standard", "instant"]},
+			"metadata": {"type": "object"}
+		}
+	}`)
+)
+
+// writeValidationError answers a request that failed its route's schema in
+// Stripe's error format, reporting the first offending parameter.
+func writeValidationError(w http.ResponseWriter, r *http.Request, errs []twincore.FieldError) {
+	fe := errs[0]
+	code, message := "parameter_invalid", "Invalid "+fe.Field+": "+fe.Message
+	switch fe.Code {
+	case twincore.FieldMissing:
+		code, message = "parameter_missing", "Missing required param: "+fe.Field+"."
+	case twincore.FieldUnknown:
+		code, message = "parameter_unknown", "Received unknown parameter: "+fe.Field
+	}
+	if fe.Field == "" {
+		message = fe.Message
+	}
+	twincore.JSON(w, http.StatusBadRequest, map[string]any{
+		"error": map[string]any{
+			"type":    "invalid_request_error",
+			"code":    code,
+			"param":   fe.Field,
+			"message": message,
+		},
+	})
+}
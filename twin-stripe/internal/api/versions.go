@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
+)
+
+// stripeVersions are the Stripe API versions the twin accepts in the
+// Stripe-Version header, oldest first. Handlers write the latest shape, the
+// version stripe-go v81 pins.
+var stripeVersions = []string{
+	"2020-08-27",
+	"2022-08-01",
+	"2022-11-15",
+	"2023-08-16",
+	"2023-10-16",
+	"2024-04-10",
+	"2024-06-20",
+	"2024-09-30.acacia",
+	"2024-10-28.acacia",
+	"2024-11-20.acacia",
+	"2024-12-18.acacia",
+}
+
+// newVersioning negotiates Stripe-Version and registers the response changes
+// the twin models, so SDKs pinned to older versions see the shapes they
+// expect.
+func (h *Handler) newVersioning() *twincore.Versioning {
+	v := twincore.NewVersioning("Stripe-Version", stripeVersions...)
+	v.WriteInvalid = writeInvalidVersion
+
+	// 2022-11-15: charges no longer include their refunds.
+	v.Change("2022-11-15", h.includeChargeRefunds)
+	return v
+}
+
+// includeChargeRefunds adds the refunds list that charges carried before
+// 2022-11-15, to a charge or to each charge of a list.
+func (h *Handler) includeChargeRefunds(r *http.Request, body any) any {
+	m, ok := body.(map[string]any)
+	if !ok {
+		return body
+	}
+	charges := []any{m}
+	if m["object"] == "list" {
+		charges, _ = m["data"].([]any)
+	}
+	for _, c := range charges {
+		charge, ok := c.(map[string]any)
+		if !ok || charge["object"] != "charge" {
+			continue
+		}
+		id, _ := charge["id"].(string)
+		refunds := h.store.Refunds.Filter(func(_ string, re store.Refund) bool {
+			return re.Charge == id
+		})
+		charge["refunds"] = map[string]any{
+			"object":      "list",
+			"url":         "/v1/charges/" + id + "/refunds",
+			"data":        refunds,
+			"has_more":    false,
+			"total_count": len(refunds),
+		}
+	}
+	return m
+}
+
+// writeInvalidVersion answers a request for an unknown Stripe-Version the
+// way Stripe does.
+func writeInvalidVersion(w http.ResponseWriter, r *http.Request, version string) {
+	twincore.StripeError(w, http.StatusBadRequest, "invalid_request_error", "",
+		"Invalid Stripe API version: "+version)
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/wondertwin-ai/wondertwin/twinkit/seedlint"
 	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 )
 
@@ -14,8 +15,12 @@ type MemoryStore struct {
 	mu sync.RWMutex
 
 	Accounts         *pkgstore.Store[Account]
+	AccountLinks     *pkgstore.Store[AccountLink]
 	ExternalAccts    *pkgstore.Store[ExternalAccount]
 	Transfers        *pkgstore.Store[Transfer]
+	Charges          *pkgstore.Store[Charge]
+	Refunds          *pkgstore.Store[Refund]
+	Disputes         *pkgstore.Store[Dispute]
 	Payouts          *pkgstore.Store[Payout]
 	Events               *pkgstore.Store[Event]
 	BalanceTransactions  *pkgstore.Store[BalanceTransaction]
@@ -33,8 +38,12 @@ type MemoryStore struct {
 func New() *MemoryStore {
 	return &MemoryStore{
 		Accounts:        pkgstore.New[Account]("acct"),
+		AccountLinks:    pkgstore.New[AccountLink]("acctlink"),
 		ExternalAccts:   pkgstore.New[ExternalAccount]("ba"),
 		Transfers:       pkgstore.New[Transfer]("tr"),
+		Charges:         pkgstore.New[Charge]("ch"),
+		Refunds:         pkgstore.New[Refund]("re"),
+		Disputes:        pkgstore.New[Dispute]("dp"),
 		Payouts:         pkgstore.New[Payout]("po"),
 		Events:              pkgstore.New[Event]("evt"),
 		BalanceTransactions: pkgstore.New[BalanceTransaction]("txn"),
@@ -145,24 +154,38 @@ func (s *MemoryStore) RecordBalanceTransaction(txType, source, currency string,
 	return id
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 // stateSnapshot is the JSON-serializable state for admin endpoints.
 type stateSnapshot struct {
-	Accounts            map[string]Account             `json:"accounts"`
-	ExternalAccts       map[string]ExternalAccount     `json:"external_accounts"`
-	Transfers           map[string]Transfer            `json:"transfers"`
-	Payouts             map[string]Payout              `json:"payouts"`
-	Events              map[string]Event               `json:"events"`
-	BalanceTransactions map[string]BalanceTransaction   `json:"balance_transactions"`
-	Balances            map[string]*AccountBalance     `json:"balances"`
-	PlatformBalance     *AccountBalance                `json:"platform_balance"`
+	SchemaVersion       int                           `json:"schema_version"`
+	Accounts            map[string]Account            `json:"accounts"`
+	AccountLinks        map[string]AccountLink        `json:"account_links"`
+	ExternalAccts       map[string]ExternalAccount    `json:"external_accounts"`
+	Transfers           map[string]Transfer           `json:"transfers"`
+	Charges             map[string]Charge             `json:"charges"`
+	Refunds             map[string]Refund             `json:"refunds"`
+	Disputes            map[string]Dispute            `json:"disputes"`
+	Payouts             map[string]Payout             `json:"payouts"`
+	Events              map[string]Event              `json:"events"`
+	BalanceTransactions map[string]BalanceTransaction `json:"balance_transactions"`
+	Balances            map[string]*AccountBalance    `json:"balances"`
+	PlatformBalance     *AccountBalance               `json:"platform_balance"`
 }
 
 // Snapshot returns the full state as a JSON-serializable value.
 func (s *MemoryStore) Snapshot() any {
 	return stateSnapshot{
+		SchemaVersion:       stateSchema.Current(),
 		Accounts:            s.Accounts.Snapshot(),
+		AccountLinks:        s.AccountLinks.Snapshot(),
 		ExternalAccts:       s.ExternalAccts.Snapshot(),
 		Transfers:           s.Transfers.Snapshot(),
+		Charges:             s.Charges.Snapshot(),
+		Refunds:             s.Refunds.Snapshot(),
+		Disputes:            s.Disputes.Snapshot(),
 		Payouts:             s.Payouts.Snapshot(),
 		Events:              s.Events.Snapshot(),
 		BalanceTransactions: s.BalanceTransactions.Snapshot(),
@@ -183,14 +206,22 @@ func (s *MemoryStore) snapshotBalances() map[string]*AccountBalance {
 
 // LoadState replaces the full state from a JSON body.
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap stateSnapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return err
 	}
 
 	s.Accounts.LoadSnapshot(snap.Accounts)
+	s.AccountLinks.LoadSnapshot(snap.AccountLinks)
 	s.ExternalAccts.LoadSnapshot(snap.ExternalAccts)
 	s.Transfers.LoadSnapshot(snap.Transfers)
+	s.Charges.LoadSnapshot(snap.Charges)
+	s.Refunds.LoadSnapshot(snap.Refunds)
+	s.Disputes.LoadSnapshot(snap.Disputes)
 	s.Payouts.LoadSnapshot(snap.Payouts)
 	s.Events.LoadSnapshot(snap.Events)
 	s.BalanceTransactions.LoadSnapshot(snap.BalanceTransactions)
@@ -209,8 +240,12 @@ func (s *MemoryStore) LoadState(data []byte) error {
 // Reset clears all state.
 func (s *MemoryStore) Reset() {
 	s.Accounts.Reset()
+	s.AccountLinks.Reset()
 	s.ExternalAccts.Reset()
 	s.Transfers.Reset()
+	s.Charges.Reset()
+	s.Refunds.Reset()
+	s.Disputes.Reset()
 	s.Payouts.Reset()
 	s.Events.Reset()
 	s.BalanceTransactions.Reset()
@@ -221,3 +256,55 @@ func (s *MemoryStore) Reset() {
 	s.Balances = make(map[string]*AccountBalance)
 	s.PlatformBalance = NewAccountBalance()
 }
+
+// ResetResources clears only the named resources, keyed as in the state snapshot.
+func (s *MemoryStore) ResetResources(names []string) error {
+	return pkgstore.ResetNamed(names, map[string]func(){
+		"accounts":             s.Accounts.Reset,
+		"account_links":        s.AccountLinks.Reset,
+		"external_accounts":    s.ExternalAccts.Reset,
+		"transfers":            s.Transfers.Reset,
+		"charges":              s.Charges.Reset,
+		"refunds":              s.Refunds.Reset,
+		"disputes":             s.Disputes.Reset,
+		"payouts":              s.Payouts.Reset,
+		"events":               s.Events.Reset,
+		"balance_transactions": s.BalanceTransactions.Reset,
+		"balances": func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.Balances = make(map[string]*AccountBalance)
+			s.PlatformBalance = NewAccountBalance()
+		},
+	})
+}
+
+// Collections exposes the record stores for NDJSON export and import.
+// Balances are not record-backed and only round-trip via LoadState.
+func (s *MemoryStore) Collections() map[string]pkgstore.Collection {
+	return map[string]pkgstore.Collection{
+		"accounts":             s.Accounts,
+		"account_links":        s.AccountLinks,
+		"external_accounts":    s.ExternalAccts,
+		"transfers":            s.Transfers,
+		"charges":              s.Charges,
+		"refunds":              s.Refunds,
+		"disputes":             s.Disputes,
+		"payouts":              s.Payouts,
+		"events":               s.Events,
+		"balance_transactions": s.BalanceTransactions,
+	}
+}
+
+// SeedRelations declares the references between collections that
+// POST /admin/state/lint checks in a seed file.
+func (s *MemoryStore) SeedRelations() []seedlint.Relation {
+	return []seedlint.Relation{
+		{From: "external_accounts", Field: "account", To: "accounts"},
+		{From: "account_links", Field: "account", To: "accounts"},
+		{From: "transfers", Field: "destination", To: "accounts"},
+		{From: "refunds", Field: "charge", To: "charges"},
+		{From: "disputes", Field: "charge", To: "charges"},
+		{From: "payouts", Field: "destination", To: "external_accounts", Optional: true},
+	}
+}
@@ -61,6 +61,23 @@ type ExternalAccount struct {
 	Metadata          map[string]string `json:"metadata,omitempty"`
 }
 
+// AccountLink is a single-use link to the onboarding flow for a Connect
+// account. The twin serves the flow itself, at URL: visiting it fills in
+// whatever the account still has due and redirects to ReturnURL, or to
+// RefreshURL once the link is used or expired.
+type AccountLink struct {
+	ID         string `json:"id"`
+	Object     string `json:"object"`
+	Account    string `json:"account"`
+	Type       string `json:"type"` // "account_onboarding" or "account_update"
+	RefreshURL string `json:"refresh_url"`
+	ReturnURL  string `json:"return_url"`
+	URL        string `json:"url"`
+	Used       bool   `json:"used"`
+	Created    int64  `json:"created"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
 // Transfer represents a Stripe transfer to a connected account.
 type Transfer struct {
 	ID                 string            `json:"id"`
@@ -79,6 +96,67 @@ type Transfer struct {
 	Created            int64             `json:"created"`
 }
 
+// Charge represents a card payment to the platform.
+type Charge struct {
+	ID                 string            `json:"id"`
+	Object             string            `json:"object"`
+	Amount             int64             `json:"amount"`
+	AmountCaptured     int64             `json:"amount_captured"`
+	AmountRefunded     int64             `json:"amount_refunded"`
+	BalanceTransaction string            `json:"balance_transaction"`
+	Captured           bool              `json:"captured"`
+	Currency           string            `json:"currency"`
+	Description        string            `json:"description,omitempty"`
+	Disputed           bool              `json:"disputed"`
+	Livemode           bool              `json:"livemode"`
+	Paid               bool              `json:"paid"`
+	Refunded           bool              `json:"refunded"`
+	Status             string            `json:"status"`
+	TransferGroup      string            `json:"transfer_group,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	Created            int64             `json:"created"`
+}
+
+// Refund represents a full or partial refund of a charge.
+type Refund struct {
+	ID                 string            `json:"id"`
+	Object             string            `json:"object"`
+	Amount             int64             `json:"amount"`
+	BalanceTransaction string            `json:"balance_transaction"`
+	Charge             string            `json:"charge"`
+	Currency           string            `json:"currency"`
+	Reason             string            `json:"reason,omitempty"`
+	Status             string            `json:"status"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	Created            int64             `json:"created"`
+}
+
+// Dispute represents a cardholder's chargeback of a charge.
+type Dispute struct {
+	ID                  string            `json:"id"`
+	Object              string            `json:"object"`
+	Amount              int64             `json:"amount"`
+	BalanceTransactions []string          `json:"balance_transactions"`
+	Charge              string            `json:"charge"`
+	Currency            string            `json:"currency"`
+	Evidence            map[string]string `json:"evidence"`
+	EvidenceDetails     EvidenceDetails   `json:"evidence_details"`
+	IsChargeRefundable  bool              `json:"is_charge_refundable"`
+	Livemode            bool              `json:"livemode"`
+	Reason              string            `json:"reason"`
+	Status              string            `json:"status"`
+	Metadata            map[string]string `json:"metadata,omitempty"`
+	Created             int64             `json:"created"`
+}
+
+// EvidenceDetails tracks the response deadline and submissions of a dispute.
+type EvidenceDetails struct {
+	DueBy           int64 `json:"due_by"`
+	HasEvidence     bool  `json:"has_evidence"`
+	PastDue         bool  `json:"past_due"`
+	SubmissionCount int   `json:"submission_count"`
+}
+
 // Balance represents a Stripe account balance.
 type Balance struct {
 	Object    string          `json:"object"`
@@ -164,6 +242,12 @@ func NewAccountBalance() *AccountBalance {
 	}
 }
 
+// Capability status constants.
+const (
+	CapabilityActive   = "active"
+	CapabilityInactive = "inactive"
+)
+
 // PayoutStatus constants.
 const (
 	PayoutStatusPending   = "pending"
@@ -173,6 +257,14 @@ const (
 	PayoutStatusCanceled  = "canceled"
 )
 
+// DisputeStatus constants.
+const (
+	DisputeStatusNeedsResponse = "needs_response"
+	DisputeStatusUnderReview   = "under_review"
+	DisputeStatusWon           = "won"
+	DisputeStatusLost          = "lost"
+)
+
 // Default timestamps for testing.
 func Now() int64 {
 	return time.Now().Unix()
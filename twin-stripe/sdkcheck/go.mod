@@ -0,0 +1,13 @@
+module github.com/wondertwin-ai/wondertwin/twin-stripe/sdkcheck
+
+go 1.25.7
+
+replace github.com/wondertwin-ai/wondertwin/twinkit => ../../twinkit
+
+replace github.com/wondertwin-ai/wondertwin/adminclient => ../../adminclient
+
+require (
+	github.com/stripe/stripe-go/v81 v81.4.0
+	github.com/wondertwin-ai/wondertwin/adminclient v0.1.0
+	github.com/wondertwin-ai/wondertwin/twinkit v0.0.0-00010101000000-000000000000
+)
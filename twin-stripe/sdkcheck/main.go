@@ -0,0 +1,137 @@
+// Command sdkcheck drives twin-stripe with stripe-go, the SDK the twin
+// targets, for wt conformance --sdk.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/account"
+	"github.com/stripe/stripe-go/v81/balance"
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/twinkit/sdkcheck"
+)
+
+func main() {
+	sdkcheck.Main(
+		sdkcheck.Check{Name: "create and retrieve an account", Run: accountRoundTrip},
+		sdkcheck.Check{Name: "auto-paginate accounts", Run: listAccounts},
+		sdkcheck.Check{Name: "retrieve the balance", Run: retrieveBalance},
+		sdkcheck.Check{Name: "missing resource is a *stripe.Error", Run: missingAccount},
+		sdkcheck.Check{Name: "rejected key is a 401 *stripe.Error", Run: authError},
+	)
+}
+
+// useTwin points stripe-go's API backend at the twin, without the retries
+// that would hide a failed request.
+func useTwin(env *sdkcheck.Env) {
+	stripe.Key = "sk_test_sdkcheck"
+	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		URL:               stripe.String(env.URL),
+		MaxNetworkRetries: stripe.Int64(0),
+	}))
+}
+
+func accountRoundTrip(env *sdkcheck.Env) error {
+	useTwin(env)
+	params := &stripe.AccountParams{
+		Type:  stripe.String(string(stripe.AccountTypeExpress)),
+		Email: stripe.String("sdkcheck@example.com"),
+	}
+	params.AddMetadata("order_id", "42")
+	created, err := account.New(params)
+	if err != nil {
+		return fmt.Errorf("account.New: %w", err)
+	}
+	if !strings.HasPrefix(created.ID, "acct_") {
+		return fmt.Errorf("expected an acct_ ID, got %q", created.ID)
+	}
+
+	got, err := account.GetByID(created.ID, nil)
+	if err != nil {
+		return fmt.Errorf("account.GetByID: %w", err)
+	}
+	switch {
+	case got.ID != created.ID:
+		return fmt.Errorf("expected ID %s, got %s", created.ID, got.ID)
+	case got.Type != stripe.AccountTypeExpress:
+		return fmt.Errorf("expected type express, got %q", got.Type)
+	case got.Email != "sdkcheck@example.com":
+		return fmt.Errorf("expected the email back, got %q", got.Email)
+	case got.Metadata["order_id"] != "42":
+		return fmt.Errorf("expected metadata order_id 42, got %v", got.Metadata)
+	}
+	return nil
+}
+
+func listAccounts(env *sdkcheck.Env) error {
+	useTwin(env)
+	const n = 25
+	for i := 0; i < n; i++ {
+		if _, err := account.New(&stripe.AccountParams{Type: stripe.String(string(stripe.AccountTypeCustom))}); err != nil {
+			return fmt.Errorf("account.New: %w", err)
+		}
+	}
+
+	params := &stripe.AccountListParams{}
+	params.Limit = stripe.Int64(10)
+	seen := map[string]bool{}
+	iter := account.List(params)
+	for iter.Next() {
+		id := iter.Account().ID
+		if seen[id] {
+			return fmt.Errorf("account %s listed twice", id)
+		}
+		seen[id] = true
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("account.List: %w", err)
+	}
+	if len(seen) != n {
+		return fmt.Errorf("expected %d accounts across pages of 10, got %d", n, len(seen))
+	}
+	return nil
+}
+
+func retrieveBalance(env *sdkcheck.Env) error {
+	useTwin(env)
+	b, err := balance.Get(nil)
+	if err != nil {
+		return fmt.Errorf("balance.Get: %w", err)
+	}
+	if b.Object != "balance" {
+		return fmt.Errorf("expected object balance, got %q", b.Object)
+	}
+	return nil
+}
+
+func missingAccount(env *sdkcheck.Env) error {
+	useTwin(env)
+	_, err := account.GetByID("acct_missing", nil)
+	var se *stripe.Error
+	if !errors.As(err, &se) {
+		return fmt.Errorf("expected a *stripe.Error, got %v", err)
+	}
+	if se.HTTPStatusCode != 404 || se.Type != stripe.ErrorTypeInvalidRequest || se.Code != stripe.ErrorCodeResourceMissing {
+		return fmt.Errorf("expected 404 invalid_request_error resource_missing, got %d %s %s", se.HTTPStatusCode, se.Type, se.Code)
+	}
+	return nil
+}
+
+func authError(env *sdkcheck.Env) error {
+	useTwin(env)
+	if err := env.Admin.InjectFault(env.Ctx, "/v1/balance", adminclient.Fault{Preset: "auth_invalid"}); err != nil {
+		return fmt.Errorf("injecting the auth fault: %w", err)
+	}
+	_, err := balance.Get(nil)
+	var se *stripe.Error
+	if !errors.As(err, &se) {
+		return fmt.Errorf("expected a *stripe.Error, got %v", err)
+	}
+	if se.HTTPStatusCode != 401 {
+		return fmt.Errorf("expected status 401, got %d", se.HTTPStatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package stripetwin wires twin-stripe's store, webhooks, API, and admin
+// plane onto a twincore.Twin, for the twin-stripe binary and for hosts such
+// as twin-multi that run several twins in one process.
+package stripetwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-stripe/internal/store"
+	stripewh "github.com/wondertwin-ai/wondertwin/twin-stripe/internal/webhook"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	pkgwebhook "github.com/wondertwin-ai/wondertwin/twinkit/webhook"
+)
+
+// Description is what twin-stripe reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-stripe",
+	SDKTarget:    twincore.SDKTarget{Package: "github.com/stripe/stripe-go", Version: "v81", APIVersion: "2024-12-18"},
+	DefaultPort:  4111,
+	Capabilities: []string{"webhooks", "clock", "quirks"},
+	Faults: []twincore.NamedFault{
+		{Name: "transfers_unavailable", Endpoint: "/v1/transfers", Description: "Transfer creation returns 503", Fault: twincore.FaultConfig{StatusCode: 503, Rate: 1.0}},
+		{Name: "payouts_rate_limited", Endpoint: "/v1/payouts", Description: "Payout creation is rate limited", Fault: twincore.FaultConfig{StatusCode: 429, Rate: 1.0}},
+	},
+}
+
+// Build mounts twin-stripe on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+
+	// Webhook secret from env or default
+	webhookSecret := os.Getenv("STRIPE_WEBHOOK_SECRET")
+	if webhookSecret == "" {
+		webhookSecret = "whsec_sim_test_secret"
+	}
+
+	// Webhook dispatcher with Stripe v1 signing
+	dispatcher := pkgwebhook.NewDispatcher(pkgwebhook.Config{
+		URL:         cfg.WebhookURL,
+		Secret:      webhookSecret,
+		Signer:      stripewh.NewStripeSigner(),
+		Logger:      twin.Logger,
+		EventPrefix: "evt",
+		AutoDeliver: cfg.WebhookURL != "",
+		Guarantee:   pkgwebhook.Guarantee(cfg.WebhookDelivery),
+		Clock:       memStore.Clock.Now,
+		Faults:      twin.Middleware().Faults,
+	})
+
+	// Settle payouts as simulated time passes, before each request
+	twin.Router.Use(memStore.Clock.Middleware)
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, dispatcher, twin.Middleware())
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetFlusher(dispatcher)
+	adminHandler.SetWebhookInspector(dispatcher)
+	adminHandler.SetDeadLetterQueue(dispatcher)
+	adminHandler.SetEventTrigger(apiHandler)
+	adminHandler.OnReset(dispatcher.Reset)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	}
+
+	twin.Logger.Info("twin-stripe ready",
+		"addr", cfg.Addr(),
+		"webhook_url", cfg.WebhookURL,
+		"webhook_secret", webhookSecret[:10]+"...",
+	)
+	return nil
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -103,16 +105,20 @@ func (h *Handler) GetMessage(w http.ResponseWriter, r *http.Request) {
 }
 
 // ListMessages handles GET /2010-04-01/Accounts/{AccountSid}/Messages.json
+// Without To or From filters it pages through messages PageSize at a time
+// (default 50), each page linking the next with an opaque PageToken, as
+// Twilio does; a token the twin no longer honors is a 400. Filtered lists
+// are returned as a single page.
 func (h *Handler) ListMessages(w http.ResponseWriter, r *http.Request) {
-	to := r.URL.Query().Get("To")
-	from := r.URL.Query().Get("From")
-
-	messages := h.store.Messages.List()
+	q := r.URL.Query()
+	to := q.Get("To")
+	from := q.Get("From")
+	uri := "/2010-04-01/Accounts/" + chi.URLParam(r, "AccountSid") + "/Messages.json"
 
 	// Filter if query params provided
 	if to != "" || from != "" {
 		var filtered []store.Message
-		for _, msg := range messages {
+		for _, msg := range h.store.Messages.List() {
 			if to != "" && msg.To != to {
 				continue
 			}
@@ -121,19 +127,53 @@ func (h *Handler) ListMessages(w http.ResponseWriter, r *http.Request) {
 			}
 			filtered = append(filtered, msg)
 		}
-		messages = filtered
+		writeMessagePage(w, uri, filtered, 0, 50, "")
+		return
 	}
 
+	pageSize := 50
+	if v := q.Get("PageSize"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			twilioError(w, http.StatusBadRequest, 20001, "Invalid PageSize: must be between 1 and 1000")
+			return
+		}
+		pageSize = n
+	}
+	pageNum, _ := strconv.Atoi(q.Get("Page"))
+	page, err := h.store.Messages.PaginateCursor(q.Get("PageToken"), pageSize)
+	if err != nil {
+		twilioError(w, http.StatusBadRequest, 20001, "Invalid PageToken ("+err.Error()+"); restart from the first page")
+		return
+	}
+	next := ""
+	if page.HasMore {
+		next = fmt.Sprintf("%s?PageSize=%d&Page=%d&PageToken=%s", uri, pageSize, pageNum+1, url.QueryEscape(page.Cursor))
+	}
+	writeMessagePage(w, uri, page.Data, pageNum, pageSize, next)
+}
+
+// writeMessagePage writes a page of messages in Twilio's list format.
+// next is the URI of the following page, or "" for the last.
+func writeMessagePage(w http.ResponseWriter, uri string, messages []store.Message, pageNum, pageSize int, next string) {
+	if messages == nil {
+		messages = []store.Message{}
+	}
+	var nextURI any
+	if next != "" {
+		nextURI = next
+	}
+	start := pageNum * pageSize
 	twincore.JSON(w, http.StatusOK, map[string]any{
-		"messages":         messages,
-		"end":              len(messages) - 1,
-		"first_page_uri":  "/2010-04-01/Accounts/Messages.json?PageSize=50&Page=0",
-		"next_page_uri":   nil,
-		"page":            0,
-		"page_size":       50,
+		"messages":          messages,
+		"end":               start + len(messages) - 1,
+		"first_page_uri":    fmt.Sprintf("%s?PageSize=%d&Page=0", uri, pageSize),
+		"next_page_uri":     nextURI,
+		"page":              pageNum,
+		"page_size":         pageSize,
 		"previous_page_uri": nil,
-		"start":           0,
-		"uri":             "/2010-04-01/Accounts/Messages.json?PageSize=50&Page=0",
+		"start":             start,
+		"uri":               fmt.Sprintf("%s?PageSize=%d&Page=%d", uri, pageSize, pageNum),
 	})
 }
 
@@ -29,6 +29,7 @@ func setupTwilio(t *testing.T) (*httptest.Server, *testutil.TwinClient) {
 	handler := api.NewHandler(memStore, twin.Middleware())
 	handler.Routes(twin.Router)
 	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
 	adminHandler.Routes(twin.Router)
 	srv := httptest.NewServer(twin.Router)
 	t.Cleanup(srv.Close)
@@ -139,6 +140,51 @@ func TestListMessages(t *testing.T) {
 	}
 }
 
+func TestListMessagesPageTokens(t *testing.T) {
+	_, tc := setupTwilio(t)
+	for _, body := range []string{"msg1", "msg2", "msg3"} {
+		twilioPostForm(t, tc, msgPath("/Messages.json"), map[string]string{
+			"To": "+15551111111", "From": "+15550000000", "Body": body,
+		})
+	}
+
+	first := twilioGet(tc, msgPath("/Messages.json?PageSize=2"))
+	first.AssertStatus(200)
+	m := first.JSONMap()
+	next, _ := m["next_page_uri"].(string)
+	if msgs, _ := m["messages"].([]any); len(msgs) != 2 || next == "" {
+		t.Fatalf("expected 2 messages and a next page, got %v", m)
+	}
+	u, _ := url.Parse(next)
+	token := u.Query().Get("PageToken")
+	if token == "" || strings.HasPrefix(token, "SM") {
+		t.Fatalf("expected an opaque PageToken, got %q", token)
+	}
+
+	second := twilioGet(tc, next)
+	second.AssertStatus(200)
+	m = second.JSONMap()
+	if msgs, _ := m["messages"].([]any); len(msgs) != 1 || m["next_page_uri"] != nil || m["page"] != float64(1) || m["start"] != float64(2) {
+		t.Fatalf("expected the last message on page 1, got %v", m)
+	}
+
+	// A token the twin did not issue, such as a message SID, is rejected.
+	sid := first.JSONMap()["messages"].([]any)[1].(map[string]any)["sid"].(string)
+	twilioGet(tc, msgPath("/Messages.json?PageSize=2&PageToken="+sid)).AssertStatus(400)
+
+	// With WT-Q-006 on, issued tokens are rejected at random too.
+	tc.DoWithHeaders("PUT", "/admin/quirks/"+twincore.QuirkInvalidCursors, nil, nil).AssertStatus(200)
+	rejected := 0
+	for range 100 {
+		if twilioGet(tc, next).StatusCode == 400 {
+			rejected++
+		}
+	}
+	if rejected == 0 || rejected == 100 {
+		t.Errorf("expected some page tokens rejected with the quirk on, got %d of 100", rejected)
+	}
+}
+
 func TestCreateMessageMissingTo(t *testing.T) {
 	_, tc := setupTwilio(t)
 
@@ -1,10 +1,8 @@
 package api
 
 import (
-	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"math/big"
 	"net/http"
 	"time"
 
@@ -35,7 +33,7 @@ func (h *Handler) CreateVerification(w http.ResponseWriter, r *http.Request) {
 
 	now := h.store.Clock.Now()
 	sid := h.store.Verifications.NextID()
-	code := generateCode(6)
+	code := h.mw.Rand.Digits(6)
 	ttl := time.Duration(h.store.OTPTTLSeconds) * time.Second
 
 	v := store.Verification{
@@ -226,13 +224,3 @@ func twilioError(w http.ResponseWriter, status int, code int, message string) {
 	w.WriteHeader(status)
 	w.Write(data)
 }
-
-// generateCode generates a random numeric code of the given length.
-func generateCode(length int) string {
-	code := ""
-	for i := 0; i < length; i++ {
-		n, _ := rand.Int(rand.Reader, big.NewInt(10))
-		code += fmt.Sprintf("%d", n.Int64())
-	}
-	return code
-}
@@ -2,9 +2,11 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	pkgstore "github.com/wondertwin-ai/wondertwin/twinkit/store"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
 	"github.com/wondertwin-ai/wondertwin/twin-twilio/internal/store"
 )
@@ -17,9 +19,48 @@ type Handler struct {
 
 // NewHandler creates a new API handler.
 func NewHandler(s *store.MemoryStore, mw *twincore.Middleware) *Handler {
+	mw.WriteStorageError = writeStorageError
+	mw.WriteAuthError = writeAuthError
+	mw.WriteBodyTooLarge = writeBodyTooLarge
+	// Twilio's PageTokens are opaque, so sign them, and let quirk WT-Q-006
+	// reject them.
+	s.Messages.SetCursors(pkgstore.CursorOptions{Clock: s.Clock, Invalidate: mw.CursorInvalidator()})
 	return &Handler{store: s, mw: mw}
 }
 
+// writeStorageError answers a request whose store operation failed the way
+// Twilio reports an internal error.
+func writeStorageError(w http.ResponseWriter, r *http.Request, failure *pkgstore.StorageFailure) {
+	twilioError(w, http.StatusInternalServerError, 20500, "Internal Server Error")
+}
+
+// writeBodyTooLarge answers a request body over the size limit with
+// Twilio's error for an oversized request.
+func writeBodyTooLarge(w http.ResponseWriter, r *http.Request, limitKB int) {
+	twilioError(w, http.StatusRequestEntityTooLarge, 20413, "Request Entity Too Large")
+}
+
+// writeAuthError answers a request failed by an auth fault preset with the
+// Twilio error for bad credentials, an expired access token, or a request
+// the account may not make.
+func writeAuthError(w http.ResponseWriter, r *http.Request, preset string) {
+	status, code, message := http.StatusUnauthorized, 20003, "Authenticate"
+	switch preset {
+	case twincore.FaultAuthExpired:
+		code, message = 20104, "Access Token expired or expiration date invalid"
+	case twincore.FaultForbiddenScope:
+		status, code, message = http.StatusForbidden, 20403, "Forbidden"
+	default:
+		w.Header().Set("WWW-Authenticate", `Basic realm="Twilio API"`)
+	}
+	twincore.JSON(w, status, map[string]any{
+		"code":      code,
+		"message":   message,
+		"more_info": fmt.Sprintf("https://www.twilio.com/docs/errors/%d", code),
+		"status":    status,
+	})
+}
+
 // Routes mounts the Twilio API routes and admin extras.
 func (h *Handler) Routes(r chi.Router) {
 	// Twilio REST API routes (Basic Auth required)
@@ -24,8 +24,13 @@ func New() *MemoryStore {
 	}
 }
 
+// stateSchema versions the snapshot format. When the format changes, bump
+// Version and add a migration so older snapshots and seed files still load.
+var stateSchema = pkgstore.SnapshotSchema{Version: 1}
+
 // stateSnapshot is the JSON-serializable state for admin endpoints.
 type stateSnapshot struct {
+	SchemaVersion int                     `json:"schema_version"`
 	Messages      map[string]Verification `json:"messages"`
 	Verifications map[string]Verification `json:"verifications"`
 }
@@ -33,9 +38,11 @@ type stateSnapshot struct {
 // Snapshot returns the full state as a JSON-serializable value.
 func (s *MemoryStore) Snapshot() any {
 	return struct {
+		SchemaVersion int                     `json:"schema_version"`
 		Messages      map[string]Message      `json:"messages"`
 		Verifications map[string]Verification `json:"verifications"`
 	}{
+		SchemaVersion: stateSchema.Current(),
 		Messages:      s.Messages.Snapshot(),
 		Verifications: s.Verifications.Snapshot(),
 	}
@@ -43,6 +50,10 @@ func (s *MemoryStore) Snapshot() any {
 
 // LoadState replaces the full state from a JSON body.
 func (s *MemoryStore) LoadState(data []byte) error {
+	data, err := stateSchema.Migrate(data)
+	if err != nil {
+		return err
+	}
 	var snap struct {
 		Messages      map[string]Message      `json:"messages"`
 		Verifications map[string]Verification `json:"verifications"`
@@ -65,3 +76,19 @@ func (s *MemoryStore) Reset() {
 	s.Verifications.Reset()
 	s.Clock.Reset()
 }
+
+// ResetResources clears only the named resources, keyed as in the state snapshot.
+func (s *MemoryStore) ResetResources(names []string) error {
+	return pkgstore.ResetNamed(names, map[string]func(){
+		"messages":      s.Messages.Reset,
+		"verifications": s.Verifications.Reset,
+	})
+}
+
+// Collections exposes the record stores for NDJSON export and import.
+func (s *MemoryStore) Collections() map[string]pkgstore.Collection {
+	return map[string]pkgstore.Collection{
+		"messages":      s.Messages,
+		"verifications": s.Verifications,
+	}
+}
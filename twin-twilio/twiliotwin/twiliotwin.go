@@ -0,0 +1,55 @@
+// Package twiliotwin wires twin-twilio's store, API, and admin plane
+// onto a twincore.Twin, for the twin-twilio binary and for hosts such as
+// twin-multi that run several twins in one process.
+package twiliotwin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/twin-twilio/internal/api"
+	"github.com/wondertwin-ai/wondertwin/twin-twilio/internal/store"
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Description is what twin-twilio reports with --describe.
+var Description = twincore.Description{
+	Name:         "twin-twilio",
+	SDKTarget:    twincore.SDKTarget{Package: "github.com/twilio/twilio-go", Version: "v1", APIVersion: "2010-04-01"},
+	DefaultPort:  4112,
+	Capabilities: []string{"clock", "quirks"},
+}
+
+// Build mounts twin-twilio on twin and loads twin.Config.SeedFile, if set.
+func Build(twin *twincore.Twin) error {
+	cfg := twin.Config
+	memStore := store.New()
+
+	// API handlers
+	apiHandler := api.NewHandler(memStore, twin.Middleware())
+	apiHandler.Routes(twin.Router)
+
+	// Admin control plane
+	adminHandler := admin.NewHandler(memStore, twin.Middleware(), memStore.Clock)
+	adminHandler.SetConfigProvider(twin)
+	adminHandler.SetQuirkStore(twin.Middleware().Quirks)
+	adminHandler.Routes(twin.Router)
+
+	// Load seed data if provided
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := memStore.LoadState(data); err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		twin.Logger.Info("loaded seed data", "file", cfg.SeedFile)
+	}
+
+	twin.Logger.Info("twin-twilio ready",
+		"addr", cfg.Addr(),
+	)
+	return nil
+}
@@ -3,16 +3,34 @@
 package admin
 
 import (
+	"context"
+	_ "embed"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/wondertwin-ai/wondertwin/twinkit/seedlint"
+	"github.com/wondertwin-ai/wondertwin/twinkit/seedtmpl"
 	"github.com/wondertwin-ai/wondertwin/twinkit/store"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twinkit/webhook"
 )
 
+// openAPISpec is the admin API contract, copied from
+// schemas/admin-api.openapi.json by make gen-adminclient.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
 // StateStore is the interface a twin must implement to support admin state management.
 type StateStore interface {
 	// Snapshot returns the full state as a JSON-serializable value.
@@ -23,18 +41,161 @@ type StateStore interface {
 	Reset()
 }
 
+// ResourceResetter is optionally implemented by state stores that can clear
+// individual resources, enabling selective resets via POST /admin/reset.
+type ResourceResetter interface {
+	// ResetResources clears only the named resources (e.g. "customers").
+	ResetResources(names []string) error
+}
+
+// SeedResetter is optionally implemented by state stores that ship named seed
+// presets, letting POST /admin/reset land on a preset instead of the defaults.
+type SeedResetter interface {
+	// ResetWithSeed clears all state and loads the named seed preset.
+	ResetWithSeed(preset string) error
+}
+
+// CollectionStore is optionally implemented by state stores that expose their
+// resources as named collections, enabling NDJSON export and import one
+// record at a time via /admin/state/export and /admin/state/import.
+type CollectionStore interface {
+	Collections() map[string]store.Collection
+}
+
+// SeedRelationStore is optionally implemented by state stores whose
+// collections reference each other, letting POST /admin/state/lint check a
+// seed file's referential integrity before it is loaded.
+type SeedRelationStore interface {
+	SeedRelations() []seedlint.Relation
+}
+
+// SeedLintResult is the response of POST /admin/state/lint.
+type SeedLintResult struct {
+	Valid     bool               `json:"valid"`
+	Relations int                `json:"relations"` // relations the twin declares
+	Problems  []seedlint.Problem `json:"problems"`
+}
+
+// TenantStore is optionally implemented by state stores whose API
+// authenticates per account, letting tests mint isolated credentials via
+// POST /admin/tenants instead of sharing the seeded ones.
+type TenantStore interface {
+	// CreateTenant creates an account, generating any credentials the
+	// request leaves out.
+	CreateTenant(req TenantRequest) (Tenant, error)
+	// ListTenants returns every account that can authenticate, seeded or
+	// created at runtime.
+	ListTenants() []Tenant
+}
+
+// TenantDeleter is optionally implemented by tenant stores that can remove
+// a tenant and everything it owns, letting test runners clean up after
+// themselves via DELETE /admin/tenants/{tenant_id}.
+type TenantDeleter interface {
+	// DeleteTenant removes the tenant with the given ID, returning an
+	// error if there is none.
+	DeleteTenant(id string) error
+}
+
+// TenantRequest is the body of POST /admin/tenants.
+type TenantRequest struct {
+	Name        string            `json:"name,omitempty"`
+	Credentials map[string]string `json:"credentials,omitempty"`
+}
+
+// Tenant is an account and the credentials that authenticate as it, keyed
+// the way the twin's API names them (e.g. "api_key", "api_secret"). Headers
+// are the request headers that authenticate as the tenant, so clients such
+// as wt test can scope requests to it without knowing the twin's auth scheme.
+type Tenant struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name,omitempty"`
+	Credentials map[string]string `json:"credentials"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// Record is a single line of an NDJSON state export or import.
+type Record struct {
+	Resource string          `json:"resource"`
+	ID       string          `json:"id"`
+	Data     json.RawMessage `json:"data"`
+}
+
 // WebhookFlusher is optionally implemented by twins that have pending webhooks.
 type WebhookFlusher interface {
 	FlushWebhooks() error
 }
 
+// WebhookInspector is optionally implemented by twins that can report their
+// outbound webhook activity. *webhook.Dispatcher satisfies it.
+type WebhookInspector interface {
+	QueuedEvents() []webhook.Event
+	AllEvents() []webhook.Event
+	Deliveries() []webhook.Delivery
+}
+
+// DeadLetterQueue is optionally implemented by twins that redeliver failed
+// webhooks and dead-letter those that never succeed. *webhook.Dispatcher
+// satisfies it.
+type DeadLetterQueue interface {
+	Redeliveries() []webhook.Redelivery
+	DeadLetters() []webhook.DeadLetter
+	// RetryDeadLetter attempts delivery once more, returning
+	// webhook.ErrNotDeadLettered for an unknown event.
+	RetryDeadLetter(eventID string) error
+}
+
+// WebhookCorrelator is optionally implemented by webhook inspectors that
+// record the request each event was enqueued from, for
+// /admin/correlations. *webhook.Dispatcher satisfies it.
+type WebhookCorrelator interface {
+	Correlate(requestID string) []webhook.Correlation
+}
+
+// WebhookEndpointRouter is optionally implemented by webhook inspectors
+// that deliver each tenant's events to its own endpoint, configured via
+// /admin/webhooks/endpoints or a seed's webhook_endpoints.
+// *webhook.Dispatcher satisfies it.
+type WebhookEndpointRouter interface {
+	SetTenantEndpoint(tenant string, ep webhook.Endpoint)
+	RemoveTenantEndpoint(tenant string) bool
+	TenantEndpoints() map[string]webhook.Endpoint
+}
+
+// EventTrigger is optionally implemented by twins that can emit their
+// provider's webhook events on demand, letting tests fire any event via
+// POST /admin/webhooks/trigger without performing the API action behind it.
+type EventTrigger interface {
+	// EventCatalog lists the event types the twin emits.
+	EventCatalog() []EventType
+	// TriggerEvent emits an event of a catalog type carrying payload,
+	// exactly as the API action behind it would, and returns the event.
+	TriggerEvent(eventType string, payload map[string]any) (any, error)
+}
+
+// EventType describes an event type a twin emits. Example is the payload an
+// event of the type carries (for Stripe, the event's data.object); triggered
+// events carry it with the request's overrides merged in.
+type EventType struct {
+	Type        string         `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Example     map[string]any `json:"example"`
+}
+
 // ConfigProvider exposes runtime configuration for reading and updating.
 type ConfigProvider interface {
 	GetConfig() map[string]any
 	UpdateConfig(updates map[string]any) error
 }
 
+// VersionProvider is optionally implemented by the config provider to report
+// build metadata. *twincore.Twin satisfies it.
+type VersionProvider interface {
+	BuildInfo() twincore.BuildInfo
+}
+
 // QuirkStore manages behavioral quirks that can be toggled at runtime.
+// *twincore.QuirkRegistry satisfies it.
 type QuirkStore interface {
 	ListQuirks() []QuirkStatus
 	EnableQuirk(id string) error
@@ -43,31 +204,104 @@ type QuirkStore interface {
 }
 
 // QuirkStatus describes the state of a single quirk.
-type QuirkStatus struct {
-	ID       string `json:"id"`
-	Summary  string `json:"summary"`
-	Enabled  bool   `json:"enabled"`
-	Type     string `json:"type"`
-	Severity string `json:"severity"`
-}
+type QuirkStatus = twincore.QuirkStatus
 
 // Handler provides the shared admin endpoints.
 type Handler struct {
 	state   StateStore
 	flusher WebhookFlusher
+	hooks   WebhookInspector
+	dead    DeadLetterQueue
+	events  EventTrigger
 	mw      *twincore.Middleware
 	clock   *store.Clock
 	config  ConfigProvider
 	quirks  QuirkStore
+	router  http.Handler // router the admin routes were mounted on, used for replay
+
+	resetHooks []func()
 }
 
-// NewHandler creates a new admin handler.
+// NewHandler creates a new admin handler. When clock is non-nil, faults
+// scheduled against the simulated clock follow it. When state is a
+// CollectionStore, the store limits configured on mw (--store-max-records,
+// --store-max-mb) are applied to each of its collections, and mw rejects
+// writes while one of them is full; the collections also fail as
+// /admin/faults/storage sets, lag behind in the regions that mw's
+// --regions give a replication lag, and keep new records out of listings
+// while twincore.QuirkListLag is on, measured on clock when non-nil. Their
+// filters scanning more records than mw's --store-scan-warn are logged.
 func NewHandler(state StateStore, mw *twincore.Middleware, clock *store.Clock) *Handler {
-	return &Handler{
+	if mw != nil && clock != nil {
+		mw.Faults.SetClock(clock.Now)
+	}
+	h := &Handler{
 		state: state,
 		mw:    mw,
 		clock: clock,
 	}
+	if cs, ok := state.(CollectionStore); ok && mw != nil {
+		maxRecords, maxMB, policy := mw.StoreLimits()
+		if maxRecords > 0 || maxMB > 0 {
+			limits := store.Limits{MaxRecords: maxRecords, MaxBytes: int64(maxMB) << 20, Policy: store.LimitPolicy(policy)}
+			for _, c := range cs.Collections() {
+				if l, ok := c.(store.Limiter); ok {
+					l.SetLimits(limits)
+				}
+			}
+		}
+		for _, c := range cs.Collections() {
+			if f, ok := c.(store.Failable); ok {
+				f.SetFailures(mw.StoreFailures)
+			}
+		}
+		if lags := mw.ReplicationLags(); len(lags) > 0 {
+			replication := store.Replication{Lags: lags}
+			if clock != nil {
+				replication.Now = clock.Now
+			}
+			for _, c := range cs.Collections() {
+				if rc, ok := c.(store.Replicated); ok {
+					rc.SetReplication(replication)
+				}
+			}
+		}
+		listLag := store.ListLag{Lag: mw.ListLag()}
+		if clock != nil {
+			listLag.Now = clock.Now
+		}
+		for _, c := range cs.Collections() {
+			if ll, ok := c.(store.ListLagged); ok {
+				ll.SetListLag(listLag)
+			}
+		}
+		for name, c := range cs.Collections() {
+			if sw, ok := c.(store.ScanWarner); ok {
+				sw.SetScanWarning(mw.ScanWarning(name))
+			}
+		}
+		mw.Capacity = h.storeCapacity
+	}
+	return h
+}
+
+// storeCapacity reports the first full collection, in name order, for
+// twincore.Middleware.Capacity.
+func (h *Handler) storeCapacity() error {
+	collections := h.state.(CollectionStore).Collections()
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if l, ok := collections[name].(store.Limiter); ok {
+			if err := l.CheckCapacity(); err != nil {
+				return fmt.Errorf("%s %w", name, err)
+			}
+		}
+	}
+	return nil
 }
 
 // SetFlusher sets the webhook flusher (optional).
@@ -75,6 +309,27 @@ func (h *Handler) SetFlusher(f WebhookFlusher) {
 	h.flusher = f
 }
 
+// SetWebhookInspector sets the webhook inspector (optional).
+func (h *Handler) SetWebhookInspector(wi WebhookInspector) {
+	h.hooks = wi
+}
+
+// SetDeadLetterQueue sets the webhook dead-letter queue (optional).
+func (h *Handler) SetDeadLetterQueue(q DeadLetterQueue) {
+	h.dead = q
+}
+
+// SetEventTrigger sets the event trigger (optional).
+func (h *Handler) SetEventTrigger(et EventTrigger) {
+	h.events = et
+}
+
+// OnReset registers a hook that runs after every full reset, for state that
+// lives outside the StateStore (e.g. a webhook dispatcher's queue).
+func (h *Handler) OnReset(hook func()) {
+	h.resetHooks = append(h.resetHooks, hook)
+}
+
 // SetConfigProvider sets the config provider (optional).
 func (h *Handler) SetConfigProvider(cp ConfigProvider) {
 	h.config = cp
@@ -87,34 +342,140 @@ func (h *Handler) SetQuirkStore(qs QuirkStore) {
 
 // Routes mounts the admin endpoints on the given router.
 func (h *Handler) Routes(r chi.Router) {
+	h.router = r
 	r.Route("/admin", func(r chi.Router) {
 		r.Post("/reset", h.handleReset)
 		r.Get("/state", h.handleGetState)
 		r.Post("/state", h.handleLoadState)
-		r.Post("/fault/{endpoint}", h.handleInjectFault)
-		r.Delete("/fault/{endpoint}", h.handleRemoveFault)
+		r.Post("/state/lint", h.handleLintState)
+		r.Get("/state/export", h.handleExportState)
+		r.Post("/state/import", h.handleImportState)
+		r.Get("/state/stats", h.handleStateStats)
+		r.Post("/fault/*", h.handleInjectFault)
+		r.Delete("/fault/*", h.handleRemoveFault)
 		r.Get("/faults", h.handleListFaults)
+		r.Get("/faults/storage", h.handleGetStorageFailures)
+		r.Put("/faults/storage", h.handleSetStorageFailures)
+		r.Delete("/faults/storage", h.handleClearStorageFailures)
 		r.Get("/requests", h.handleGetRequests)
+		r.Get("/routes", h.handleListRoutes)
+		r.Post("/requests/{id}/replay", h.handleReplayRequest)
+		r.Get("/correlations", h.handleCorrelations)
+		r.Get("/webhooks", h.handleListWebhooks)
 		r.Post("/webhooks/flush", h.handleFlushWebhooks)
+		r.Get("/webhooks/dead_letters", h.handleListDeadLetters)
+		r.Post("/webhooks/dead_letters/{id}/retry", h.handleRetryDeadLetter)
+		r.Get("/webhooks/endpoints", h.handleListWebhookEndpoints)
+		r.Put("/webhooks/endpoints/{tenant}", h.handleSetWebhookEndpoint)
+		r.Delete("/webhooks/endpoints/{tenant}", h.handleRemoveWebhookEndpoint)
+		r.Get("/webhooks/events/catalog", h.handleEventCatalog)
+		r.Post("/webhooks/trigger", h.handleTriggerEvent)
+		r.Get("/events", h.handleListEvents)
+		r.Get("/shadow/diffs", h.handleShadowDiffs)
+		r.Get("/audit/export", h.handleAuditExport)
+		r.Post("/audit/events", h.handleAuditEvent)
+		r.Get("/breakpoints", h.handleListBreakpoints)
+		r.Post("/breakpoints", h.handleAddBreakpoint)
+		r.Post("/breakpoints/{id}/release", h.handleReleaseBreakpoint)
+		r.Delete("/breakpoints/{id}", h.handleRemoveBreakpoint)
+		r.Get("/intercepts", h.handleListIntercepts)
+		r.Post("/intercepts", h.handleAddIntercept)
+		r.Post("/intercepts/{id}/decide", h.handleDecideIntercept)
+		r.Delete("/intercepts/{id}", h.handleRemoveIntercept)
+		r.Get("/templates", h.handleListTemplates)
+		r.Put("/templates/{method}/*", h.handleSetTemplate)
+		r.Delete("/templates/{method}/*", h.handleRemoveTemplate)
 		r.Post("/time/advance", h.handleTimeAdvance)
+		r.Post("/time/set", h.handleTimeSet)
+		r.Post("/time/freeze", h.handleTimeFreeze)
+		r.Post("/time/unfreeze", h.handleTimeUnfreeze)
 		r.Get("/time", h.handleGetTime)
 		r.Get("/health", h.handleHealth)
+		r.Get("/version", h.handleVersion)
+		r.Get("/openapi.json", h.handleOpenAPI)
 		r.Get("/config", h.handleGetConfig)
 		r.Put("/config", h.handleUpdateConfig)
 		r.Get("/quirks", h.handleListQuirks)
 		r.Put("/quirks/{quirk_id}", h.handleEnableQuirk)
 		r.Delete("/quirks/{quirk_id}", h.handleDisableQuirk)
+		r.Get("/tenants", h.handleListTenants)
+		r.Post("/tenants", h.handleCreateTenant)
+		r.Delete("/tenants/{tenant_id}", h.handleDeleteTenant)
 	})
 }
 
 func (h *Handler) handleReset(w http.ResponseWriter, r *http.Request) {
-	h.state.Reset()
+	var req struct {
+		Resources []string `json:"resources"`
+		Seed      string   `json:"seed"`
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+		return
+	}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			twincore.Error(w, http.StatusBadRequest, "invalid reset body: "+err.Error())
+			return
+		}
+	}
+
+	if len(req.Resources) > 0 && req.Seed != "" {
+		twincore.Error(w, http.StatusBadRequest, "resources and seed cannot be combined")
+		return
+	}
+
+	// Selective reset clears only the named resources and leaves the request
+	// log, faults, and clock alone.
+	if len(req.Resources) > 0 {
+		rr, ok := h.state.(ResourceResetter)
+		if !ok {
+			twincore.Error(w, http.StatusBadRequest, "selective reset not supported by this twin")
+			return
+		}
+		if err := rr.ResetResources(req.Resources); err != nil {
+			twincore.Error(w, http.StatusBadRequest, "failed to reset resources: "+err.Error())
+			return
+		}
+		twincore.JSON(w, http.StatusOK, map[string]any{"status": "reset", "resources": req.Resources})
+		return
+	}
+
+	if req.Seed != "" {
+		seeder, ok := h.state.(SeedResetter)
+		if !ok {
+			twincore.Error(w, http.StatusBadRequest, "seed presets not supported by this twin")
+			return
+		}
+		if err := seeder.ResetWithSeed(req.Seed); err != nil {
+			twincore.Error(w, http.StatusBadRequest, "failed to reset with seed: "+err.Error())
+			return
+		}
+	} else {
+		h.state.Reset()
+	}
 	h.mw.ReqLog.Clear()
+	h.mw.Events.Clear()
+	h.mw.ShadowLog.Clear()
+	h.mw.Cache.Clear()
 	h.mw.Faults.Reset()
+	h.mw.Breakpoints.Reset()
+	h.mw.Intercepts.Reset()
+	h.mw.StoreFailures.Reset()
 	h.mw.Idempotent.Reset()
+	h.mw.Rand.Reset()
 	if h.clock != nil {
 		h.clock.Reset()
 	}
+	for _, hook := range h.resetHooks {
+		hook()
+	}
+
+	if req.Seed != "" {
+		twincore.JSON(w, http.StatusOK, map[string]string{"status": "reset", "seed": req.Seed})
+		return
+	}
 	twincore.JSON(w, http.StatusOK, map[string]string{"status": "reset"})
 }
 
@@ -128,31 +489,251 @@ func (h *Handler) handleLoadState(w http.ResponseWriter, r *http.Request) {
 		twincore.Error(w, http.StatusBadRequest, "failed to read body: "+err.Error())
 		return
 	}
+	body, err = h.expandSeed(r, body)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "failed to expand seed templates: "+err.Error())
+		return
+	}
+	body, endpoints, err := seedWebhookEndpoints(body)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid webhook_endpoints: "+err.Error())
+		return
+	}
 	if err := h.state.LoadState(body); err != nil {
 		twincore.Error(w, http.StatusBadRequest, "failed to load state: "+err.Error())
 		return
 	}
+	if router, ok := h.hooks.(WebhookEndpointRouter); ok {
+		for tenant, ep := range endpoints {
+			router.SetTenantEndpoint(tenant, ep)
+		}
+	}
 	twincore.JSON(w, http.StatusOK, map[string]string{"status": "loaded"})
 }
 
+// seedWebhookEndpoints takes a seed's top-level webhook_endpoints, a map
+// from tenant to the endpoint its webhooks are delivered to, out of the
+// seed, returning the rest for the state store.
+func seedWebhookEndpoints(seed []byte) ([]byte, map[string]webhook.Endpoint, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(seed, &doc); err != nil || doc["webhook_endpoints"] == nil {
+		return seed, nil, nil // a seed that is not an object is for the state store to judge
+	}
+	var endpoints map[string]webhook.Endpoint
+	if err := json.Unmarshal(doc["webhook_endpoints"], &endpoints); err != nil {
+		return nil, nil, err
+	}
+	for tenant, ep := range endpoints {
+		if err := validateWebhookEndpoint(tenant, ep); err != nil {
+			return nil, nil, err
+		}
+	}
+	delete(doc, "webhook_endpoints")
+	rest, err := json.Marshal(doc)
+	return rest, endpoints, err
+}
+
+// expandSeed expands the seed templates in body if the request asks for it
+// with ?expand=true, as wt seed does. Other bodies are used as sent, so a
+// snapshot holding a literal ${...} loads unchanged.
+func (h *Handler) expandSeed(r *http.Request, body []byte) ([]byte, error) {
+	if r.URL.Query().Get("expand") != "true" {
+		return body, nil
+	}
+	return seedtmpl.Expand(body, h.now())
+}
+
+// now returns the twin's simulated time, or the real time for twins
+// without a clock.
+func (h *Handler) now() time.Time {
+	if h.clock != nil {
+		return h.clock.Now()
+	}
+	return time.Now()
+}
+
+// handleLintState checks a seed file without loading it.
+func (h *Handler) handleLintState(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+		return
+	}
+	body, err = h.expandSeed(r, body)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "failed to expand seed templates: "+err.Error())
+		return
+	}
+	var relations []seedlint.Relation
+	if rs, ok := h.state.(SeedRelationStore); ok {
+		relations = rs.SeedRelations()
+	}
+	problems, err := seedlint.Check(body, relations)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusOK, SeedLintResult{
+		Valid:     len(problems) == 0,
+		Relations: len(relations),
+		Problems:  problems,
+	})
+}
+
+// exportFlushEvery is how many NDJSON records are written between flushes.
+const exportFlushEvery = 1000
+
+func (h *Handler) handleExportState(w http.ResponseWriter, r *http.Request) {
+	cs, ok := h.state.(CollectionStore)
+	if !ok {
+		twincore.Error(w, http.StatusNotImplemented, "streaming export not supported by this twin")
+		return
+	}
+	collections := cs.Collections()
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	n := 0
+	for _, name := range names {
+		err := collections[name].RangeJSON(func(id string, data json.RawMessage) error {
+			if err := enc.Encode(Record{Resource: name, ID: id, Data: data}); err != nil {
+				return err
+			}
+			if n++; flusher != nil && n%exportFlushEvery == 0 {
+				flusher.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			// Headers are already sent; the truncated stream is the only signal.
+			return
+		}
+	}
+}
+
+func (h *Handler) handleImportState(w http.ResponseWriter, r *http.Request) {
+	cs, ok := h.state.(CollectionStore)
+	if !ok {
+		twincore.Error(w, http.StatusNotImplemented, "streaming import not supported by this twin")
+		return
+	}
+	collections := cs.Collections()
+
+	// Records are decoded one at a time, so the body is never held in memory.
+	// Import upserts; reset first to replace existing state.
+	dec := json.NewDecoder(r.Body)
+	n := 0
+	for {
+		var rec Record
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			twincore.Error(w, http.StatusBadRequest, fmt.Sprintf("record %d: invalid JSON: %v (%d records imported)", n+1, err, n))
+			return
+		}
+		c, ok := collections[rec.Resource]
+		if !ok {
+			twincore.Error(w, http.StatusBadRequest, fmt.Sprintf("record %d: unknown resource %q (%d records imported)", n+1, rec.Resource, n))
+			return
+		}
+		if rec.ID == "" {
+			twincore.Error(w, http.StatusBadRequest, fmt.Sprintf("record %d: id is required (%d records imported)", n+1, n))
+			return
+		}
+		if err := c.SetJSON(rec.ID, rec.Data); err != nil {
+			twincore.Error(w, http.StatusBadRequest, fmt.Sprintf("record %d: %v (%d records imported)", n+1, err, n))
+			return
+		}
+		n++
+	}
+	twincore.JSON(w, http.StatusOK, map[string]any{"status": "imported", "records": n})
+}
+
+// handleStateStats reports each collection's record count, estimated size,
+// TTL, and operation metrics, so operators can see what a long-lived twin
+// is holding on to and which stores are hot or scanned slowly.
+func (h *Handler) handleStateStats(w http.ResponseWriter, r *http.Request) {
+	cs, ok := h.state.(CollectionStore)
+	if !ok {
+		twincore.Error(w, http.StatusNotImplemented, "state stats not supported by this twin")
+		return
+	}
+	stats := make(map[string]store.Stats)
+	var total store.Stats
+	for name, c := range cs.Collections() {
+		var st store.Stats
+		if sr, ok := c.(store.StatsReporter); ok {
+			st = sr.Stats()
+		} else {
+			c.RangeJSON(func(id string, data json.RawMessage) error {
+				st.Count++
+				st.ApproxBytes += int64(len(id) + len(data))
+				return nil
+			})
+		}
+		stats[name] = st
+		total.Count += st.Count
+		total.ApproxBytes += st.ApproxBytes
+		total.SlowScans += st.SlowScans
+		for op, o := range st.Ops {
+			if total.Ops == nil {
+				total.Ops = map[string]store.OpStats{}
+			}
+			t := total.Ops[op]
+			t.Count += o.Count
+			t.TotalMS += o.TotalMS
+			t.MaxMS = max(t.MaxMS, o.MaxMS)
+			total.Ops[op] = t
+		}
+	}
+	twincore.JSON(w, http.StatusOK, map[string]any{"stores": stats, "total": total})
+}
+
+// faultEndpoint returns the endpoint a fault route refers to. The wildcard
+// lets multi-segment paths such as /admin/fault/v1/transfers address
+// /v1/transfers; /admin/fault/webhook:charge.succeeded addresses webhook
+// deliveries (see twincore.WebhookFaultPrefix).
+func faultEndpoint(r *http.Request) string {
+	endpoint := chi.URLParam(r, "*")
+	if twincore.IsWebhookFault(endpoint) {
+		return endpoint
+	}
+	return "/" + endpoint
+}
+
 func (h *Handler) handleInjectFault(w http.ResponseWriter, r *http.Request) {
-	endpoint := "/" + chi.URLParam(r, "endpoint")
+	endpoint := faultEndpoint(r)
 
 	var fault twincore.FaultConfig
 	if err := json.NewDecoder(r.Body).Decode(&fault); err != nil {
 		twincore.Error(w, http.StatusBadRequest, "invalid fault config: "+err.Error())
 		return
 	}
-	h.mw.Faults.Set(endpoint, fault)
+	if twincore.IsWebhookFault(endpoint) && h.flusher == nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid fault: this twin does not send webhooks")
+		return
+	}
+	if err := h.mw.Faults.Set(endpoint, fault); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid fault: "+err.Error())
+		return
+	}
+	// Echo the registered fault, with any relative schedule resolved
 	twincore.JSON(w, http.StatusOK, map[string]any{
 		"status":   "injected",
 		"endpoint": endpoint,
-		"fault":    fault,
+		"fault":    h.mw.Faults.All()[endpoint],
 	})
 }
 
 func (h *Handler) handleRemoveFault(w http.ResponseWriter, r *http.Request) {
-	endpoint := "/" + chi.URLParam(r, "endpoint")
+	endpoint := faultEndpoint(r)
 	if h.mw.Faults.Remove(endpoint) {
 		twincore.JSON(w, http.StatusOK, map[string]any{"status": "removed", "endpoint": endpoint})
 	} else {
@@ -164,10 +745,342 @@ func (h *Handler) handleListFaults(w http.ResponseWriter, r *http.Request) {
 	twincore.JSON(w, http.StatusOK, h.mw.Faults.All())
 }
 
+// storageFailures is the storage failure mode and how many operations it
+// has failed since the last reset.
+func (h *Handler) storageFailures() map[string]any {
+	cfg := h.mw.StoreFailures.Config()
+	if cfg.Ops == nil {
+		cfg.Ops = []string{}
+	}
+	return map[string]any{
+		"rate":        cfg.Rate,
+		"ops":         cfg.Ops,
+		"after_write": cfg.AfterWrite,
+		"injected":    h.mw.StoreFailures.Injected(),
+	}
+}
+
+func (h *Handler) handleGetStorageFailures(w http.ResponseWriter, r *http.Request) {
+	twincore.JSON(w, http.StatusOK, h.storageFailures())
+}
+
+// handleSetStorageFailures makes a share of the twin's store operations
+// fail, answering the API requests that hit one with the provider's 500.
+func (h *Handler) handleSetStorageFailures(w http.ResponseWriter, r *http.Request) {
+	var cfg store.FailureConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid storage failure config: "+err.Error())
+		return
+	}
+	if err := h.mw.StoreFailures.Configure(cfg); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid storage failure config: "+err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusOK, h.storageFailures())
+}
+
+func (h *Handler) handleClearStorageFailures(w http.ResponseWriter, r *http.Request) {
+	h.mw.StoreFailures.Reset()
+	twincore.JSON(w, http.StatusOK, map[string]string{"status": "cleared"})
+}
+
+// Route is an API route the twin serves, as chi registered it.
+type Route struct {
+	Method string `json:"method"`
+	Route  string `json:"route"` // e.g. /v1/accounts/{id}
+}
+
+// handleListRoutes lists the twin's API routes, so tools can tell which of
+// its endpoints a test run exercised (request log entries carry the route
+// that served them). Admin routes are left out.
+func (h *Handler) handleListRoutes(w http.ResponseWriter, r *http.Request) {
+	routes := []Route{}
+	if rs, ok := h.router.(chi.Routes); ok {
+		chi.Walk(rs, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			if !strings.HasPrefix(route, "/admin/") {
+				routes = append(routes, Route{Method: method, Route: route})
+			}
+			return nil
+		})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Route != routes[j].Route {
+			return routes[i].Route < routes[j].Route
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	twincore.JSON(w, http.StatusOK, routes)
+}
+
 func (h *Handler) handleGetRequests(w http.ResponseWriter, r *http.Request) {
 	twincore.JSON(w, http.StatusOK, h.mw.ReqLog.Entries())
 }
 
+// handleShadowDiffs reports how the shadow target's responses differed from
+// the twin's since the last reset. The report is empty, with no target,
+// unless the twin runs with --shadow-url.
+func (h *Handler) handleShadowDiffs(w http.ResponseWriter, r *http.Request) {
+	twincore.JSON(w, http.StatusOK, h.mw.ShadowReport())
+}
+
+// handleAuditExport returns the twin's hash-chained audit log, signed when
+// the twin runs with --audit-key. Resets do not clear it.
+func (h *Handler) handleAuditExport(w http.ResponseWriter, r *http.Request) {
+	twincore.JSON(w, http.StatusOK, h.mw.ExportAudit())
+}
+
+// handleAuditEvent records a scenario run in the audit log. wt test reports
+// each scenario it runs to every twin of the manifest.
+func (h *Handler) handleAuditEvent(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Kind     string `json:"kind"`
+		Scenario string `json:"scenario"`
+		Passed   *bool  `json:"passed"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid audit event: "+err.Error())
+		return
+	}
+	if req.Kind != twincore.AuditScenario {
+		twincore.Error(w, http.StatusBadRequest, fmt.Sprintf("kind must be %q", twincore.AuditScenario))
+		return
+	}
+	if req.Scenario == "" {
+		twincore.Error(w, http.StatusBadRequest, "scenario is required")
+		return
+	}
+	e := h.mw.Audit.Append(twincore.AuditEntry{
+		Kind:      twincore.AuditScenario,
+		Scenario:  req.Scenario,
+		Passed:    req.Passed,
+		RequestID: chimw.GetReqID(r.Context()),
+	})
+	twincore.JSON(w, http.StatusCreated, e)
+}
+
+func (h *Handler) handleListBreakpoints(w http.ResponseWriter, r *http.Request) {
+	twincore.JSON(w, http.StatusOK, h.mw.Breakpoints.All())
+}
+
+func (h *Handler) handleAddBreakpoint(w http.ResponseWriter, r *http.Request) {
+	var bp twincore.Breakpoint
+	if err := json.NewDecoder(r.Body).Decode(&bp); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid breakpoint: "+err.Error())
+		return
+	}
+	bp, err := h.mw.Breakpoints.Add(bp)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid breakpoint: "+err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusCreated, bp)
+}
+
+// handleReleaseBreakpoint lets requests held at a breakpoint carry on. The
+// body is optional; without one, every held request is released.
+func (h *Handler) handleReleaseBreakpoint(w http.ResponseWriter, r *http.Request) {
+	var rel twincore.BreakpointRelease
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+		return
+	}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &rel); err != nil {
+			twincore.Error(w, http.StatusBadRequest, "invalid release: "+err.Error())
+			return
+		}
+	}
+	if rel.Count < 0 || rel.WaitFor < 0 {
+		twincore.Error(w, http.StatusBadRequest, "invalid release: count and wait_for must not be negative")
+		return
+	}
+	id := chi.URLParam(r, "id")
+	n, err := h.mw.Breakpoints.Release(r.Context(), id, rel)
+	switch {
+	case errors.Is(err, twincore.ErrBreakpointNotFound):
+		twincore.Error(w, http.StatusNotFound, "no breakpoint "+id)
+	case err != nil:
+		twincore.Error(w, http.StatusConflict, err.Error())
+	default:
+		twincore.JSON(w, http.StatusOK, map[string]any{"status": "released", "id": id, "released": n})
+	}
+}
+
+func (h *Handler) handleRemoveBreakpoint(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.mw.Breakpoints.Remove(id) {
+		twincore.Error(w, http.StatusNotFound, "no breakpoint "+id)
+		return
+	}
+	twincore.JSON(w, http.StatusOK, map[string]string{"status": "removed", "id": id})
+}
+
+func (h *Handler) handleListIntercepts(w http.ResponseWriter, r *http.Request) {
+	twincore.JSON(w, http.StatusOK, h.mw.Intercepts.All())
+}
+
+func (h *Handler) handleAddIntercept(w http.ResponseWriter, r *http.Request) {
+	var ic twincore.Intercept
+	if err := json.NewDecoder(r.Body).Decode(&ic); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid intercept: "+err.Error())
+		return
+	}
+	ic, err := h.mw.Intercepts.Add(ic)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid intercept: "+err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusCreated, ic)
+}
+
+// handleDecideIntercept returns a response held at an intercept to its
+// client: approved as it is, edited, or denied.
+func (h *Handler) handleDecideIntercept(w http.ResponseWriter, r *http.Request) {
+	var d twincore.InterceptDecision
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid decision: "+err.Error())
+		return
+	}
+	if err := d.Validate(); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid decision: "+err.Error())
+		return
+	}
+	id := chi.URLParam(r, "id")
+	err := h.mw.Intercepts.Decide(id, d)
+	switch {
+	case errors.Is(err, twincore.ErrInterceptNotFound):
+		twincore.Error(w, http.StatusNotFound, "no intercept "+id)
+	case err != nil:
+		twincore.Error(w, http.StatusConflict, err.Error())
+	default:
+		twincore.JSON(w, http.StatusOK, map[string]string{"status": "decided", "id": id, "action": d.Action})
+	}
+}
+
+func (h *Handler) handleRemoveIntercept(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.mw.Intercepts.Remove(id) {
+		twincore.Error(w, http.StatusNotFound, "no intercept "+id)
+		return
+	}
+	twincore.JSON(w, http.StatusOK, map[string]string{"status": "removed", "id": id})
+}
+
+// templateRoute returns the route a /admin/templates/{method}/{route}
+// request names, e.g. "GET /v1/customers/{id}".
+func templateRoute(r *http.Request) string {
+	return strings.ToUpper(chi.URLParam(r, "method")) + " /" + chi.URLParam(r, "*")
+}
+
+func (h *Handler) handleListTemplates(w http.ResponseWriter, r *http.Request) {
+	twincore.JSON(w, http.StatusOK, h.mw.Templates.All())
+}
+
+// handleSetTemplate overrides a route's response rendering. The format
+// query parameter names the template format; without it, a JSON body
+// (Content-Type application/json) is a JSON template with placeholders and
+// any other body is a Go template.
+func (h *Handler) handleSetTemplate(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = twincore.TemplateFormatGo
+		if strings.Contains(r.Header.Get("Content-Type"), "json") {
+			format = twincore.TemplateFormatJSON
+		}
+	}
+	t, err := h.mw.Templates.Set(templateRoute(r), format, string(body))
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid template: "+err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusOK, map[string]any{"status": "set", "template": t})
+}
+
+func (h *Handler) handleRemoveTemplate(w http.ResponseWriter, r *http.Request) {
+	route := templateRoute(r)
+	if !h.mw.Templates.Remove(route) {
+		twincore.Error(w, http.StatusNotFound, "no template set for "+route)
+		return
+	}
+	twincore.JSON(w, http.StatusOK, map[string]string{"status": "removed", "route": route})
+}
+
+// replayHeader marks replayed requests so they can be told apart in the request log.
+const replayHeader = "X-WonderTwin-Replay-Of"
+
+// replayResponse is the captured response of a replayed request.
+type replayResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       any               `json:"body,omitempty"`
+}
+
+func (h *Handler) handleReplayRequest(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	entry, ok := h.mw.ReqLog.Get(id)
+	if !ok {
+		twincore.Error(w, http.StatusNotFound, "no logged request with id "+id)
+		return
+	}
+	if !entry.BodyCaptured {
+		twincore.Error(w, http.StatusConflict,
+			"request "+id+" was logged without body capture; start the twin with --capture-bodies or set capture_bodies in /admin/config")
+		return
+	}
+	if strings.HasPrefix(entry.Path, "/admin/requests/") {
+		twincore.Error(w, http.StatusBadRequest, "cannot replay a replay request")
+		return
+	}
+
+	target := entry.Path
+	if entry.Query != "" {
+		target += "?" + entry.Query
+	}
+	// A fresh routing context is required: chi reuses any RouteContext it finds,
+	// which would otherwise route the replay as if it were this admin request.
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, chi.NewRouteContext())
+	req, err := http.NewRequestWithContext(ctx, entry.Method, target, strings.NewReader(entry.Body))
+	if err != nil {
+		twincore.Error(w, http.StatusInternalServerError, "failed to build replay request: "+err.Error())
+		return
+	}
+	for k, v := range entry.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(replayHeader, entry.ID)
+	req.RemoteAddr = r.RemoteAddr
+
+	rec := httptest.NewRecorder()
+	h.router.ServeHTTP(rec, req)
+
+	resp := replayResponse{
+		StatusCode: rec.Code,
+		Headers:    make(map[string]string, len(rec.Header())),
+	}
+	for k := range rec.Header() {
+		resp.Headers[k] = rec.Header().Get(k)
+	}
+	if raw := rec.Body.Bytes(); len(raw) > 0 {
+		if json.Valid(raw) {
+			resp.Body = json.RawMessage(raw)
+		} else {
+			resp.Body = string(raw)
+		}
+	}
+
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"status":   "replayed",
+		"original": entry,
+		"response": resp,
+	})
+}
+
 func (h *Handler) handleFlushWebhooks(w http.ResponseWriter, r *http.Request) {
 	if h.flusher == nil {
 		twincore.JSON(w, http.StatusOK, map[string]string{"status": "no webhooks configured"})
@@ -180,6 +1093,245 @@ func (h *Handler) handleFlushWebhooks(w http.ResponseWriter, r *http.Request) {
 	twincore.JSON(w, http.StatusOK, map[string]string{"status": "flushed"})
 }
 
+func (h *Handler) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if h.hooks == nil {
+		twincore.Error(w, http.StatusNotFound, "webhook inspector not configured")
+		return
+	}
+	resp := map[string]any{
+		"queued":     h.hooks.QueuedEvents(),
+		"deliveries": h.hooks.Deliveries(),
+	}
+	if h.dead != nil {
+		resp["redeliveries"] = h.dead.Redeliveries()
+	}
+	twincore.JSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if h.dead == nil {
+		twincore.Error(w, http.StatusNotFound, "webhook redelivery not configured")
+		return
+	}
+	twincore.JSON(w, http.StatusOK, h.dead.DeadLetters())
+}
+
+// handleRetryDeadLetter makes one more delivery attempt for a dead-lettered
+// event. It answers 502 if the attempt fails; the event stays dead-lettered.
+func (h *Handler) handleRetryDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if h.dead == nil {
+		twincore.Error(w, http.StatusNotFound, "webhook redelivery not configured")
+		return
+	}
+	id := chi.URLParam(r, "id")
+	err := h.dead.RetryDeadLetter(id)
+	if errors.Is(err, webhook.ErrNotDeadLettered) {
+		twincore.Error(w, http.StatusNotFound, fmt.Sprintf("event %q is not dead-lettered", id))
+		return
+	}
+	if err != nil {
+		twincore.Error(w, http.StatusBadGateway, "redelivery failed: "+err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusOK, map[string]string{"status": "delivered", "event_id": id})
+}
+
+// webhookEndpointRouter returns the twin's per-tenant webhook routing,
+// writing a 404 if it has none.
+func (h *Handler) webhookEndpointRouter(w http.ResponseWriter) (WebhookEndpointRouter, bool) {
+	router, ok := h.hooks.(WebhookEndpointRouter)
+	if !ok {
+		twincore.Error(w, http.StatusNotFound, "per-tenant webhook endpoints not supported by this twin")
+	}
+	return router, ok
+}
+
+func (h *Handler) handleListWebhookEndpoints(w http.ResponseWriter, r *http.Request) {
+	router, ok := h.webhookEndpointRouter(w)
+	if !ok {
+		return
+	}
+	twincore.JSON(w, http.StatusOK, router.TenantEndpoints())
+}
+
+func (h *Handler) handleSetWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	router, ok := h.webhookEndpointRouter(w)
+	if !ok {
+		return
+	}
+	tenant := chi.URLParam(r, "tenant")
+	var ep webhook.Endpoint
+	if err := json.NewDecoder(r.Body).Decode(&ep); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid endpoint: "+err.Error())
+		return
+	}
+	if err := validateWebhookEndpoint(tenant, ep); err != nil {
+		twincore.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	router.SetTenantEndpoint(tenant, ep)
+	twincore.JSON(w, http.StatusOK, map[string]any{"status": "set", "tenant": tenant, "endpoint": ep})
+}
+
+func (h *Handler) handleRemoveWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	router, ok := h.webhookEndpointRouter(w)
+	if !ok {
+		return
+	}
+	tenant := chi.URLParam(r, "tenant")
+	if !router.RemoveTenantEndpoint(tenant) {
+		twincore.Error(w, http.StatusNotFound, "no webhook endpoint for tenant "+tenant)
+		return
+	}
+	twincore.JSON(w, http.StatusOK, map[string]string{"status": "removed", "tenant": tenant})
+}
+
+// validateWebhookEndpoint checks a tenant's endpoint: an absolute http(s)
+// URL, or none to keep the twin's URL and only change the secret.
+func validateWebhookEndpoint(tenant string, ep webhook.Endpoint) error {
+	if tenant == "" {
+		return fmt.Errorf("tenant is required")
+	}
+	if ep.URL == "" && ep.Secret == "" {
+		return fmt.Errorf("endpoint for tenant %s needs a url or a secret", tenant)
+	}
+	if ep.URL != "" {
+		u, err := url.Parse(ep.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint for tenant %s: url must be an absolute http(s) URL, got %q", tenant, ep.URL)
+		}
+	}
+	return nil
+}
+
+func (h *Handler) handleEventCatalog(w http.ResponseWriter, r *http.Request) {
+	if h.events == nil {
+		twincore.Error(w, http.StatusNotFound, "event trigger not configured")
+		return
+	}
+	twincore.JSON(w, http.StatusOK, h.events.EventCatalog())
+}
+
+// handleTriggerEvent emits an event of a catalog type, carrying the type's
+// example payload with the request's overrides merged in.
+func (h *Handler) handleTriggerEvent(w http.ResponseWriter, r *http.Request) {
+	if h.events == nil {
+		twincore.Error(w, http.StatusNotFound, "event trigger not configured")
+		return
+	}
+	var req struct {
+		Type      string         `json:"type"`
+		Overrides map[string]any `json:"overrides"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	if req.Type == "" {
+		twincore.Error(w, http.StatusBadRequest, "type is required")
+		return
+	}
+
+	var example map[string]any
+	var known []string
+	for _, et := range h.events.EventCatalog() {
+		if et.Type == req.Type {
+			example = et.Example
+		}
+		known = append(known, et.Type)
+	}
+	if example == nil {
+		twincore.Error(w, http.StatusNotFound, fmt.Sprintf("unknown event type %q; the twin emits: %s", req.Type, strings.Join(known, ", ")))
+		return
+	}
+
+	// Copy the example so overrides never leak into the catalog.
+	data, err := json.Marshal(example)
+	if err != nil {
+		twincore.Error(w, http.StatusInternalServerError, "copying example payload: "+err.Error())
+		return
+	}
+	var payload map[string]any
+	json.Unmarshal(data, &payload)
+	mergeOverrides(payload, req.Overrides)
+
+	evt, err := h.events.TriggerEvent(req.Type, payload)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusOK, map[string]any{
+		"status": "triggered",
+		"event":  evt,
+	})
+}
+
+// mergeOverrides sets each override on dst. Objects are merged key by key,
+// so an override can change one nested field; any other value replaces the
+// one in dst.
+func mergeOverrides(dst, overrides map[string]any) {
+	for k, v := range overrides {
+		if om, ok := v.(map[string]any); ok {
+			if dm, ok := dst[k].(map[string]any); ok {
+				mergeOverrides(dm, om)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+func (h *Handler) handleListEvents(w http.ResponseWriter, r *http.Request) {
+	if h.hooks == nil {
+		twincore.Error(w, http.StatusNotFound, "webhook inspector not configured")
+		return
+	}
+	twincore.JSON(w, http.StatusOK, h.hooks.AllEvents())
+}
+
+// correlationsResponse is the response of GET /admin/correlations.
+type correlationsResponse struct {
+	RequestID string                    `json:"request_id"`
+	Request   *twincore.RequestLogEntry `json:"request,omitempty"`
+	Events    []twincore.JournalEntry   `json:"events"`
+	Webhooks  []webhook.Correlation     `json:"webhooks"`
+}
+
+// handleCorrelations links a request to the domain events it emitted and
+// the webhooks it enqueued, with their deliveries. request_id is the ID the
+// twin echoed in X-Request-Id, or a request log entry ID such as
+// req_000001. The request itself is left out once the log has evicted it.
+func (h *Handler) handleCorrelations(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("request_id")
+	if id == "" {
+		twincore.Error(w, http.StatusBadRequest, "request_id is required")
+		return
+	}
+
+	resp := correlationsResponse{RequestID: id, Webhooks: []webhook.Correlation{}}
+	if entry, ok := h.mw.ReqLog.Get(id); ok && entry.RequestID != "" {
+		resp.RequestID = entry.RequestID
+		resp.Request = &entry
+	} else {
+		for _, e := range h.mw.ReqLog.Entries() {
+			if e.RequestID == id {
+				resp.Request = &e
+				break
+			}
+		}
+	}
+	resp.Events = h.mw.Events.ForRequest(resp.RequestID)
+	if c, ok := h.hooks.(WebhookCorrelator); ok {
+		resp.Webhooks = c.Correlate(resp.RequestID)
+	}
+
+	if resp.Request == nil && len(resp.Events) == 0 && len(resp.Webhooks) == 0 {
+		twincore.Error(w, http.StatusNotFound, "no logged request, events, or webhooks with request id "+id)
+		return
+	}
+	twincore.JSON(w, http.StatusOK, resp)
+}
+
 func (h *Handler) handleTimeAdvance(w http.ResponseWriter, r *http.Request) {
 	if h.clock == nil {
 		twincore.Error(w, http.StatusBadRequest, "simulated clock not configured")
@@ -201,12 +1353,61 @@ func (h *Handler) handleTimeAdvance(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.clock.Advance(d)
-	twincore.JSON(w, http.StatusOK, map[string]any{
-		"status":    "advanced",
-		"duration":  d.String(),
+	resp := h.timeState("advanced")
+	resp["duration"] = d.String()
+	twincore.JSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleTimeSet(w http.ResponseWriter, r *http.Request) {
+	if h.clock == nil {
+		twincore.Error(w, http.StatusBadRequest, "simulated clock not configured")
+		return
+	}
+
+	var req struct {
+		To string `json:"to"` // RFC 3339 timestamp, e.g., "2026-01-31T23:59:00Z"
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "invalid time, expected RFC 3339: "+err.Error())
+		return
+	}
+
+	h.clock.Set(to)
+	twincore.JSON(w, http.StatusOK, h.timeState("set"))
+}
+
+func (h *Handler) handleTimeFreeze(w http.ResponseWriter, r *http.Request) {
+	if h.clock == nil {
+		twincore.Error(w, http.StatusBadRequest, "simulated clock not configured")
+		return
+	}
+	h.clock.Freeze()
+	twincore.JSON(w, http.StatusOK, h.timeState("frozen"))
+}
+
+func (h *Handler) handleTimeUnfreeze(w http.ResponseWriter, r *http.Request) {
+	if h.clock == nil {
+		twincore.Error(w, http.StatusBadRequest, "simulated clock not configured")
+		return
+	}
+	h.clock.Unfreeze()
+	twincore.JSON(w, http.StatusOK, h.timeState("unfrozen"))
+}
+
+// timeState describes the simulated clock after a time operation.
+func (h *Handler) timeState(status string) map[string]any {
+	return map[string]any{
+		"status":    status,
 		"offset":    h.clock.Offset().String(),
 		"simulated": h.clock.Now().Format(time.RFC3339),
-	})
+		"frozen":    h.clock.Frozen(),
+	}
 }
 
 func (h *Handler) handleGetTime(w http.ResponseWriter, r *http.Request) {
@@ -220,6 +1421,7 @@ func (h *Handler) handleGetTime(w http.ResponseWriter, r *http.Request) {
 		"real":      time.Now().Format(time.RFC3339),
 		"simulated": h.clock.Now().Format(time.RFC3339),
 		"offset":    h.clock.Offset().String(),
+		"frozen":    h.clock.Frozen(),
 	})
 }
 
@@ -227,6 +1429,24 @@ func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	twincore.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// handleVersion reports build metadata. Twins whose config provider does not
+// implement VersionProvider still report the twinkit-level build info.
+func (h *Handler) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if vp, ok := h.config.(VersionProvider); ok {
+		twincore.JSON(w, http.StatusOK, vp.BuildInfo())
+		return
+	}
+	twincore.JSON(w, http.StatusOK, twincore.ReadBuildInfo(""))
+}
+
+// handleOpenAPI serves the admin API spec so tooling in any language can
+// integrate against the protocol version this twin was built with.
+func (h *Handler) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPISpec)
+}
+
 func (h *Handler) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 	if h.config == nil {
 		twincore.Error(w, http.StatusNotFound, "config provider not configured")
@@ -291,3 +1511,58 @@ func (h *Handler) handleDisableQuirk(w http.ResponseWriter, r *http.Request) {
 	}
 	twincore.JSON(w, http.StatusOK, map[string]any{"status": "disabled", "quirk_id": id})
 }
+
+func (h *Handler) handleListTenants(w http.ResponseWriter, r *http.Request) {
+	ts, ok := h.state.(TenantStore)
+	if !ok {
+		twincore.Error(w, http.StatusNotFound, "tenants not supported by this twin")
+		return
+	}
+	tenants := ts.ListTenants()
+	if tenants == nil {
+		tenants = []Tenant{}
+	}
+	twincore.JSON(w, http.StatusOK, tenants)
+}
+
+func (h *Handler) handleCreateTenant(w http.ResponseWriter, r *http.Request) {
+	ts, ok := h.state.(TenantStore)
+	if !ok {
+		twincore.Error(w, http.StatusNotFound, "tenants not supported by this twin")
+		return
+	}
+
+	var req TenantRequest
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+		return
+	}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			twincore.Error(w, http.StatusBadRequest, "invalid tenant body: "+err.Error())
+			return
+		}
+	}
+
+	tenant, err := ts.CreateTenant(req)
+	if err != nil {
+		twincore.Error(w, http.StatusBadRequest, "failed to create tenant: "+err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusCreated, tenant)
+}
+
+func (h *Handler) handleDeleteTenant(w http.ResponseWriter, r *http.Request) {
+	td, ok := h.state.(TenantDeleter)
+	if !ok {
+		twincore.Error(w, http.StatusNotFound, "deleting tenants not supported by this twin")
+		return
+	}
+	id := chi.URLParam(r, "tenant_id")
+	if err := td.DeleteTenant(id); err != nil {
+		twincore.Error(w, http.StatusNotFound, "tenant not found: "+err.Error())
+		return
+	}
+	twincore.JSON(w, http.StatusOK, map[string]string{"status": "deleted", "tenant_id": id})
+}
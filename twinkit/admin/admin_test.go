@@ -3,14 +3,23 @@ package admin
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/wondertwin-ai/wondertwin/twinkit/seedlint"
 	"github.com/wondertwin-ai/wondertwin/twinkit/store"
 	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+	"github.com/wondertwin-ai/wondertwin/twinkit/webhook"
 )
 
 // ---------------------------------------------------------------------------
@@ -143,6 +152,9 @@ type testServerOpts struct {
 	flusher WebhookFlusher
 	config  ConfigProvider
 	quirks  QuirkStore
+	hooks   WebhookInspector
+	dead    DeadLetterQueue
+	events  EventTrigger
 }
 
 func setupTestServer(state StateStore, clock *store.Clock, flusher WebhookFlusher) *httptest.Server {
@@ -166,6 +178,15 @@ func setupTestServerFull(opts testServerOpts) *httptest.Server {
 	if opts.quirks != nil {
 		h.SetQuirkStore(opts.quirks)
 	}
+	if opts.hooks != nil {
+		h.SetWebhookInspector(opts.hooks)
+	}
+	if opts.dead != nil {
+		h.SetDeadLetterQueue(opts.dead)
+	}
+	if opts.events != nil {
+		h.SetEventTrigger(opts.events)
+	}
 
 	r := chi.NewRouter()
 	h.Routes(r)
@@ -198,15 +219,12 @@ func TestHandleHealth(t *testing.T) {
 	}
 }
 
-func TestHandleReset(t *testing.T) {
-	state := newMockState()
-	clk := store.NewClock()
-	clk.Advance(1000)
-
-	srv := setupTestServer(state, clk, nil)
+func TestHandleVersion(t *testing.T) {
+	cfg := &twincore.Config{Name: "twin-test"}
+	srv := setupTestServerFull(testServerOpts{config: twincore.New(cfg)})
 	defer srv.Close()
 
-	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", nil)
+	resp, err := http.Get(srv.URL + "/admin/version")
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -215,19 +233,25 @@ func TestHandleReset(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("expected 200, got %d", resp.StatusCode)
 	}
-	if !state.resetCalled {
-		t.Error("expected state Reset to be called")
+
+	var body twincore.BuildInfo
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body.Name != "twin-test" {
+		t.Errorf("expected name=twin-test, got %q", body.Name)
 	}
-	if clk.Offset() != 0 {
-		t.Errorf("expected clock offset to be reset, got %v", clk.Offset())
+	if body.Version != twincore.Version {
+		t.Errorf("expected version=%q, got %q", twincore.Version, body.Version)
+	}
+	if body.Commit == "" || body.BuildDate == "" || body.TwinkitVersion == "" || body.GoVersion == "" {
+		t.Errorf("expected every field to be populated, got %+v", body)
 	}
 }
 
-func TestHandleGetState(t *testing.T) {
+func TestHandleVersionWithoutProvider(t *testing.T) {
 	srv := setupTestServer(newMockState(), nil, nil)
 	defer srv.Close()
 
-	resp, err := http.Get(srv.URL + "/admin/state")
+	resp, err := http.Get(srv.URL + "/admin/version")
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -237,20 +261,21 @@ func TestHandleGetState(t *testing.T) {
 		t.Errorf("expected 200, got %d", resp.StatusCode)
 	}
 
-	var body map[string]string
+	var body twincore.BuildInfo
 	json.NewDecoder(resp.Body).Decode(&body)
-	if body["key"] != "value" {
-		t.Errorf("expected key=value, got %+v", body)
+	if body.Name != "" {
+		t.Errorf("expected empty name without a provider, got %q", body.Name)
+	}
+	if body.Version != twincore.Version {
+		t.Errorf("expected version=%q, got %q", twincore.Version, body.Version)
 	}
 }
 
-func TestHandleLoadState(t *testing.T) {
-	state := newMockState()
-	srv := setupTestServer(state, nil, nil)
+func TestHandleOpenAPI(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
 	defer srv.Close()
 
-	newState := `{"foo":"bar"}`
-	resp, err := http.Post(srv.URL+"/admin/state", "application/json", strings.NewReader(newState))
+	resp, err := http.Get(srv.URL + "/admin/openapi.json")
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -259,39 +284,60 @@ func TestHandleLoadState(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("expected 200, got %d", resp.StatusCode)
 	}
-	if state.data["foo"] != "bar" {
-		t.Errorf("expected state to be updated, got %+v", state.data)
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+	var spec struct {
+		OpenAPI string                   `json:"openapi"`
+		Info    struct{ Version string } `json:"info"`
+		Paths   map[string]any           `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("decoding spec: %v", err)
+	}
+	if spec.OpenAPI == "" || spec.Info.Version == "" || spec.Paths["/admin/openapi.json"] == nil {
+		t.Errorf("unexpected spec: %+v", spec)
 	}
 }
 
-func TestHandleLoadStateInvalid(t *testing.T) {
-	srv := setupTestServer(newMockState(), nil, nil)
-	defer srv.Close()
-
-	resp, err := http.Post(srv.URL+"/admin/state", "application/json", strings.NewReader("{bad json"))
-	if err != nil {
-		t.Fatalf("request failed: %v", err)
+// TestOpenAPICoversRoutes fails when a route is added to Handler.Routes
+// without documenting it in schemas/admin-api.openapi.json.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatal(err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusBadRequest {
-		t.Errorf("expected 400, got %d", resp.StatusCode)
+	r := chi.NewRouter()
+	NewHandler(newMockState(), twincore.NewMiddleware(&twincore.Config{}, nil), nil).Routes(r)
+	param := regexp.MustCompile(`\{[^}]+\}`)
+	documented := map[string]bool{}
+	for path, item := range spec.Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+param.ReplaceAllString(path, "{}")] = true
+		}
 	}
+
+	chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = param.ReplaceAllString(strings.Replace(route, "/*", "/{}", 1), "{}")
+		if !documented[method+" "+route] {
+			t.Errorf("%s %s is not in the OpenAPI spec", method, route)
+		}
+		return nil
+	})
 }
 
-func TestHandleInjectFault(t *testing.T) {
-	cfg := &twincore.Config{Name: "test"}
-	mw := twincore.NewMiddleware(cfg, nil)
+func TestHandleReset(t *testing.T) {
 	state := newMockState()
+	clk := store.NewClock()
+	clk.Advance(1000)
 
-	h := NewHandler(state, mw, nil)
-	r := chi.NewRouter()
-	h.Routes(r)
-	srv := httptest.NewServer(r)
+	srv := setupTestServer(state, clk, nil)
 	defer srv.Close()
 
-	body := `{"status_code":503,"rate":1.0}`
-	resp, err := http.Post(srv.URL+"/admin/fault/charges", "application/json", strings.NewReader(body))
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", nil)
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -300,46 +346,60 @@ func TestHandleInjectFault(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("expected 200, got %d", resp.StatusCode)
 	}
-
-	// Verify the fault was registered (handler prepends "/" to the endpoint param)
-	fault := mw.Faults.Check("/charges")
-	if fault == nil {
-		t.Fatal("expected fault to be registered")
+	if !state.resetCalled {
+		t.Error("expected state Reset to be called")
 	}
-	if fault.StatusCode != 503 {
-		t.Errorf("expected status 503, got %d", fault.StatusCode)
+	if clk.Offset() != 0 {
+		t.Errorf("expected clock offset to be reset, got %v", clk.Offset())
 	}
 }
 
-func TestHandleInjectFaultInvalidBody(t *testing.T) {
-	srv := setupTestServer(newMockState(), nil, nil)
+func TestHandleResetRestartsSeededRandom(t *testing.T) {
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test-admin", RandSeed: 42}, nil)
+	r := chi.NewRouter()
+	NewHandler(newMockState(), mw, nil).Routes(r)
+	srv := httptest.NewServer(r)
 	defer srv.Close()
 
-	resp, err := http.Post(srv.URL+"/admin/fault/test", "application/json", strings.NewReader("{bad"))
+	first := mw.Rand.Digits(6)
+	mw.Rand.Digits(6)
+
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", nil)
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
-	defer resp.Body.Close()
+	resp.Body.Close()
 
-	if resp.StatusCode != http.StatusBadRequest {
-		t.Errorf("expected 400, got %d", resp.StatusCode)
+	if got := mw.Rand.Digits(6); got != first {
+		t.Errorf("expected reset to restart the seeded sequence at %s, got %s", first, got)
 	}
 }
 
-func TestHandleRemoveFault(t *testing.T) {
-	cfg := &twincore.Config{Name: "test"}
-	mw := twincore.NewMiddleware(cfg, nil)
-	mw.Faults.Set("/test", twincore.FaultConfig{StatusCode: 500, Rate: 1.0})
+// mockResettableState adds selective reset support to mockState.
+type mockResettableState struct {
+	*mockState
+	resetResources []string
+}
 
-	state := newMockState()
-	h := NewHandler(state, mw, nil)
-	r := chi.NewRouter()
-	h.Routes(r)
-	srv := httptest.NewServer(r)
+func (m *mockResettableState) ResetResources(names []string) error {
+	for _, n := range names {
+		if _, ok := m.data[n]; !ok {
+			return fmt.Errorf("unknown resource %q", n)
+		}
+	}
+	m.resetResources = names
+	return nil
+}
+
+func TestHandleResetSelective(t *testing.T) {
+	state := &mockResettableState{mockState: newMockState()}
+	clk := store.NewClock()
+	clk.Advance(1000)
+
+	srv := setupTestServer(state, clk, nil)
 	defer srv.Close()
 
-	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/admin/fault/test", nil)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", strings.NewReader(`{"resources": ["key"]}`))
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -348,70 +408,73 @@ func TestHandleRemoveFault(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("expected 200, got %d", resp.StatusCode)
 	}
-
-	if mw.Faults.Check("/test") != nil {
-		t.Error("expected fault to be removed")
+	if len(state.resetResources) != 1 || state.resetResources[0] != "key" {
+		t.Errorf("expected ResetResources([key]), got %v", state.resetResources)
+	}
+	if state.resetCalled {
+		t.Error("expected full Reset not to be called")
+	}
+	if clk.Offset() == 0 {
+		t.Error("expected clock to be left alone by a selective reset")
 	}
 }
 
-func TestHandleRemoveFaultNotFound(t *testing.T) {
-	srv := setupTestServer(newMockState(), nil, nil)
+func TestHandleResetSelectiveUnknownResource(t *testing.T) {
+	state := &mockResettableState{mockState: newMockState()}
+	srv := setupTestServer(state, nil, nil)
 	defer srv.Close()
 
-	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/admin/fault/nonexistent", nil)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", strings.NewReader(`{"resources": ["nope"]}`))
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNotFound {
-		t.Errorf("expected 404, got %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
 	}
 }
 
-func TestHandleListFaults(t *testing.T) {
-	cfg := &twincore.Config{Name: "test"}
-	mw := twincore.NewMiddleware(cfg, nil)
-	mw.Faults.Set("/a", twincore.FaultConfig{StatusCode: 500, Rate: 1.0})
-
+func TestHandleResetSelectiveUnsupported(t *testing.T) {
 	state := newMockState()
-	h := NewHandler(state, mw, nil)
-	r := chi.NewRouter()
-	h.Routes(r)
-	srv := httptest.NewServer(r)
+	srv := setupTestServer(state, nil, nil)
 	defer srv.Close()
 
-	resp, err := http.Get(srv.URL + "/admin/faults")
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", strings.NewReader(`{"resources": ["key"]}`))
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("expected 200, got %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
 	}
-
-	var body map[string]twincore.FaultConfig
-	json.NewDecoder(resp.Body).Decode(&body)
-	if _, ok := body["/a"]; !ok {
-		t.Errorf("expected fault /a in listing, got %+v", body)
+	if state.resetCalled {
+		t.Error("expected no reset when selective reset is unsupported")
 	}
 }
 
-func TestHandleGetRequests(t *testing.T) {
-	cfg := &twincore.Config{Name: "test"}
-	mw := twincore.NewMiddleware(cfg, nil)
-	mw.ReqLog.Add(twincore.RequestLogEntry{Method: "GET", Path: "/test"})
+// mockSeedState adds named seed presets to mockState.
+type mockSeedState struct {
+	*mockState
+	seeded string
+}
 
-	state := newMockState()
-	h := NewHandler(state, mw, nil)
-	r := chi.NewRouter()
-	h.Routes(r)
-	srv := httptest.NewServer(r)
+func (m *mockSeedState) ResetWithSeed(preset string) error {
+	if preset != "big" {
+		return fmt.Errorf("unknown seed preset %q", preset)
+	}
+	m.seeded = preset
+	m.data = map[string]string{"key": "value", "extra": "seeded"}
+	return nil
+}
+
+func TestHandleResetWithSeed(t *testing.T) {
+	state := &mockSeedState{mockState: newMockState()}
+	srv := setupTestServer(state, store.NewClock(), nil)
 	defer srv.Close()
 
-	resp, err := http.Get(srv.URL + "/admin/requests")
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", strings.NewReader(`{"seed": "big"}`))
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -420,95 +483,127 @@ func TestHandleGetRequests(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("expected 200, got %d", resp.StatusCode)
 	}
-
-	var body []twincore.RequestLogEntry
+	var body map[string]string
 	json.NewDecoder(resp.Body).Decode(&body)
-	if len(body) != 1 {
-		t.Fatalf("expected 1 entry, got %d", len(body))
+	if body["seed"] != "big" {
+		t.Errorf("expected seed=big in response, got %+v", body)
 	}
-	if body[0].Method != "GET" {
-		t.Errorf("expected GET, got %s", body[0].Method)
+	if state.seeded != "big" {
+		t.Errorf("expected ResetWithSeed(big), got %q", state.seeded)
+	}
+	if state.resetCalled {
+		t.Error("expected default Reset not to be called")
 	}
 }
 
-func TestHandleTimeAdvance(t *testing.T) {
-	clk := store.NewClock()
-	srv := setupTestServer(newMockState(), clk, nil)
+func TestHandleResetWithUnknownSeed(t *testing.T) {
+	state := &mockSeedState{mockState: newMockState()}
+	srv := setupTestServer(state, nil, nil)
 	defer srv.Close()
 
-	body := `{"duration":"1h"}`
-	resp, err := http.Post(srv.URL+"/admin/time/advance", "application/json", strings.NewReader(body))
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", strings.NewReader(`{"seed": "missing"}`))
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("expected 200, got %d", resp.StatusCode)
-	}
-
-	var result map[string]any
-	json.NewDecoder(resp.Body).Decode(&result)
-	if result["status"] != "advanced" {
-		t.Errorf("expected status=advanced, got %v", result["status"])
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
 	}
 }
 
-func TestHandleTimeAdvanceNoClock(t *testing.T) {
-	srv := setupTestServer(newMockState(), nil, nil)
+func TestHandleResetWithSeedUnsupported(t *testing.T) {
+	state := newMockState()
+	srv := setupTestServer(state, nil, nil)
 	defer srv.Close()
 
-	body := `{"duration":"1h"}`
-	resp, err := http.Post(srv.URL+"/admin/time/advance", "application/json", strings.NewReader(body))
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", strings.NewReader(`{"seed": "big"}`))
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusBadRequest {
-		t.Errorf("expected 400 when no clock configured, got %d", resp.StatusCode)
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+	if state.resetCalled {
+		t.Error("expected no reset when seed presets are unsupported")
 	}
 }
 
-func TestHandleTimeAdvanceInvalidDuration(t *testing.T) {
-	clk := store.NewClock()
-	srv := setupTestServer(newMockState(), clk, nil)
+func TestHandleResetRunsHooks(t *testing.T) {
+	cfg := &twincore.Config{Name: "test-admin"}
+	h := NewHandler(newMockState(), twincore.NewMiddleware(cfg, nil), nil)
+	calls := 0
+	h.OnReset(func() { calls++ })
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
 	defer srv.Close()
 
-	body := `{"duration":"not-a-duration"}`
-	resp, err := http.Post(srv.URL+"/admin/time/advance", "application/json", strings.NewReader(body))
+	resp, err := http.Post(srv.URL+"/admin/reset", "application/json", nil)
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
-	defer resp.Body.Close()
+	resp.Body.Close()
 
-	if resp.StatusCode != http.StatusBadRequest {
-		t.Errorf("expected 400 for invalid duration, got %d", resp.StatusCode)
+	if calls != 1 {
+		t.Errorf("expected reset hook to run once, ran %d times", calls)
 	}
 }
 
-func TestHandleTimeAdvanceInvalidJSON(t *testing.T) {
-	clk := store.NewClock()
-	srv := setupTestServer(newMockState(), clk, nil)
+// mockCollectionState exposes record-level collections to the admin handler.
+type mockCollectionState struct {
+	*mockState
+	items *store.Store[map[string]any]
+}
+
+func (m *mockCollectionState) Collections() map[string]store.Collection {
+	return map[string]store.Collection{"items": m.items}
+}
+
+func TestHandleExportState(t *testing.T) {
+	state := &mockCollectionState{mockState: newMockState(), items: store.New[map[string]any]("item")}
+	state.items.Set("item_1", map[string]any{"name": "a"})
+	state.items.Set("item_2", map[string]any{"name": "b"})
+	srv := setupTestServer(state, nil, nil)
 	defer srv.Close()
 
-	resp, err := http.Post(srv.URL+"/admin/time/advance", "application/json", strings.NewReader("{bad"))
+	resp, err := http.Get(srv.URL + "/admin/state/export")
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusBadRequest {
-		t.Errorf("expected 400 for invalid JSON, got %d", resp.StatusCode)
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected NDJSON content type, got %q", ct)
+	}
+	dec := json.NewDecoder(resp.Body)
+	var ids []string
+	for dec.More() {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("decoding record: %v", err)
+		}
+		if rec.Resource != "items" {
+			t.Errorf("expected resource items, got %q", rec.Resource)
+		}
+		ids = append(ids, rec.ID)
+	}
+	if len(ids) != 2 || ids[0] != "item_1" || ids[1] != "item_2" {
+		t.Errorf("expected records item_1, item_2 in order, got %v", ids)
 	}
 }
 
-func TestHandleGetTime(t *testing.T) {
-	clk := store.NewClock()
-	srv := setupTestServer(newMockState(), clk, nil)
+func TestHandleImportState(t *testing.T) {
+	state := &mockCollectionState{mockState: newMockState(), items: store.New[map[string]any]("item")}
+	srv := setupTestServer(state, nil, nil)
 	defer srv.Close()
 
-	resp, err := http.Get(srv.URL + "/admin/time")
+	body := `{"resource": "items", "id": "item_1", "data": {"name": "a"}}
+{"resource": "items", "id": "item_2", "data": {"name": "b"}}
+`
+	resp, err := http.Post(srv.URL+"/admin/state/import", "application/x-ndjson", strings.NewReader(body))
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -517,62 +612,1051 @@ func TestHandleGetTime(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("expected 200, got %d", resp.StatusCode)
 	}
-
-	var body map[string]any
-	json.NewDecoder(resp.Body).Decode(&body)
-	if _, ok := body["real"]; !ok {
-		t.Error("expected 'real' field")
-	}
-	if _, ok := body["simulated"]; !ok {
-		t.Error("expected 'simulated' field")
+	var result map[string]any
+	json.NewDecoder(resp.Body).Decode(&result)
+	if result["records"] != float64(2) {
+		t.Errorf("expected 2 records imported, got %v", result["records"])
 	}
-	if _, ok := body["offset"]; !ok {
-		t.Error("expected 'offset' field")
+	if item, ok := state.items.Get("item_2"); !ok || item["name"] != "b" {
+		t.Errorf("expected item_2 to be imported, got %v", item)
 	}
 }
 
-func TestHandleGetTimeNoClock(t *testing.T) {
-	srv := setupTestServer(newMockState(), nil, nil)
+func TestHandleImportStateUnknownResource(t *testing.T) {
+	state := &mockCollectionState{mockState: newMockState(), items: store.New[map[string]any]("item")}
+	srv := setupTestServer(state, nil, nil)
 	defer srv.Close()
 
-	resp, err := http.Get(srv.URL + "/admin/time")
+	body := `{"resource": "items", "id": "item_1", "data": {}}
+{"resource": "widgets", "id": "w_1", "data": {}}
+`
+	resp, err := http.Post(srv.URL+"/admin/state/import", "application/x-ndjson", strings.NewReader(body))
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("expected 200, got %d", resp.StatusCode)
-	}
-
-	var body map[string]any
-	json.NewDecoder(resp.Body).Decode(&body)
-	if _, ok := body["real"]; !ok {
-		t.Error("expected 'real' field")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
 	}
-	if _, ok := body["simulated"]; ok {
-		t.Error("did not expect 'simulated' field when no clock")
+	if state.items.Count() != 1 {
+		t.Errorf("expected records before the bad line to be kept, got %d", state.items.Count())
 	}
 }
 
-func TestHandleFlushWebhooksNoFlusher(t *testing.T) {
+func TestHandleExportStateUnsupported(t *testing.T) {
 	srv := setupTestServer(newMockState(), nil, nil)
 	defer srv.Close()
 
-	resp, err := http.Post(srv.URL+"/admin/webhooks/flush", "application/json", nil)
+	resp, err := http.Get(srv.URL + "/admin/state/export")
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
-	defer resp.Body.Close()
+	resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("expected 200, got %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("expected 501, got %d", resp.StatusCode)
 	}
+}
 
-	var body map[string]string
-	json.NewDecoder(resp.Body).Decode(&body)
-	if body["status"] != "no webhooks configured" {
-		t.Errorf("unexpected status: %s", body["status"])
+func TestHandleStateStats(t *testing.T) {
+	state := &mockCollectionState{mockState: newMockState(), items: store.New[map[string]any]("item")}
+	state.items.Set("item_1", map[string]any{"name": "a"})
+	state.items.Set("item_2", map[string]any{"name": "b"})
+	state.items.SetTTL(time.Hour, store.NewClock())
+	srv := setupTestServer(state, nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/state/stats")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Stores map[string]store.Stats `json:"stores"`
+		Total  store.Stats            `json:"total"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding stats: %v", err)
+	}
+	items := body.Stores["items"]
+	if items.Count != 2 || items.ApproxBytes == 0 || items.TTL != "1h0m0s" {
+		t.Errorf("unexpected item stats %+v", items)
+	}
+	if items.Ops["Set"].Count != 2 || body.Total.Ops["Set"].Count != 2 {
+		t.Errorf("expected both sets counted, got %+v and %+v", items.Ops, body.Total.Ops)
+	}
+	if body.Total.Count != 2 || body.Total.ApproxBytes != items.ApproxBytes {
+		t.Errorf("unexpected totals %+v", body.Total)
+	}
+}
+
+func TestNewHandlerAppliesStoreLimits(t *testing.T) {
+	state := &mockCollectionState{mockState: newMockState(), items: store.New[map[string]any]("item")}
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test-admin", StoreMaxRecords: 2, StoreLimitPolicy: "reject"}, nil)
+	h := NewHandler(state, mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	if mw.Capacity == nil {
+		t.Fatal("expected NewHandler to install a capacity check")
+	}
+	state.items.Set("item_1", map[string]any{"name": "a"})
+	if err := mw.Capacity(); err != nil {
+		t.Errorf("expected room below the limit, got %v", err)
+	}
+	state.items.Set("item_2", map[string]any{"name": "b"})
+	if err := mw.Capacity(); err == nil || !strings.HasPrefix(err.Error(), "items ") {
+		t.Errorf("expected the items store reported full, got %v", err)
+	}
+
+	resp, err := http.Get(srv.URL + "/admin/state/stats")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Stores map[string]store.Stats `json:"stores"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding stats: %v", err)
+	}
+	if items := body.Stores["items"]; !items.Full || items.MaxRecords != 2 || items.LimitPolicy != "reject" {
+		t.Errorf("expected limits in stats, got %+v", items)
+	}
+}
+
+func TestNewHandlerAppliesListLag(t *testing.T) {
+	state := &mockCollectionState{mockState: newMockState(), items: store.New[map[string]any]("item")}
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test-admin", ListLag: time.Minute}, nil)
+	clock := store.NewClock()
+	clock.Freeze()
+	NewHandler(state, mw, clock)
+
+	if err := mw.Quirks.EnableQuirk(twincore.QuirkListLag); err != nil {
+		t.Fatalf("expected NewHandler to register the list lag quirk: %v", err)
+	}
+	state.items.Set("item_1", map[string]any{"name": "a"})
+	if n := len(state.items.List()); n != 0 {
+		t.Errorf("expected the new item kept out of listings, got %d", n)
+	}
+	if _, ok := state.items.Get("item_1"); !ok {
+		t.Error("expected Get to find the new item")
+	}
+	clock.Advance(time.Minute)
+	if n := len(state.items.List()); n != 1 {
+		t.Errorf("expected the item listed after the lag, got %d", n)
+	}
+}
+
+func TestHandleStorageFailures(t *testing.T) {
+	state := &mockCollectionState{mockState: newMockState(), items: store.New[map[string]any]("item")}
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test-admin"}, slog.Default())
+	h := NewHandler(state, mw, nil)
+	r := chi.NewRouter()
+	r.Use(mw.StorageFailures)
+	h.Routes(r)
+	// An API route that writes two records, so a failing write can leave
+	// the first behind.
+	r.Post("/v1/items", func(w http.ResponseWriter, r *http.Request) {
+		state.items.Set(state.items.NextID(), map[string]any{"name": "a"})
+		state.items.Set(state.items.NextID(), map[string]any{"name": "b"})
+		twincore.JSON(w, http.StatusOK, map[string]int{"count": state.items.Count()})
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	put := func(body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPut, srv.URL+"/admin/faults/storage", strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := put(`{"rate": 1.5}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a rate over 1, got %d", resp.StatusCode)
+	}
+	if resp := put(`{"rate": 1, "ops": ["write"], "after_write": true}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	resp, err := http.Post(srv.URL+"/v1/items", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected 500 from a failing write, got %d", resp.StatusCode)
+	}
+	// The first write was applied before it failed, and the second never ran.
+	if n := state.items.Count(); n != 1 {
+		t.Errorf("expected the failed request to leave 1 record, got %d", n)
+	}
+
+	// Admin requests are not affected.
+	resp, err = http.Get(srv.URL + "/admin/faults/storage")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var mode map[string]any
+	json.NewDecoder(resp.Body).Decode(&mode)
+	resp.Body.Close()
+	if mode["rate"] != float64(1) || mode["after_write"] != true || mode["injected"] != float64(1) {
+		t.Errorf("unexpected storage failure mode: %v", mode)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/admin/faults/storage", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	resp, err = http.Post(srv.URL+"/v1/items", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || state.items.Count() != 3 {
+		t.Errorf("expected writes to succeed once cleared, got %d with %d records", resp.StatusCode, state.items.Count())
+	}
+}
+
+func TestHandleStateStatsUnsupported(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/state/stats")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("expected 501, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleGetState(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/state")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body map[string]string
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body["key"] != "value" {
+		t.Errorf("expected key=value, got %+v", body)
+	}
+}
+
+func TestHandleLoadState(t *testing.T) {
+	state := newMockState()
+	srv := setupTestServer(state, nil, nil)
+	defer srv.Close()
+
+	newState := `{"foo":"bar"}`
+	resp, err := http.Post(srv.URL+"/admin/state", "application/json", strings.NewReader(newState))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if state.data["foo"] != "bar" {
+		t.Errorf("expected state to be updated, got %+v", state.data)
+	}
+}
+
+func TestHandleLoadStateTemplates(t *testing.T) {
+	t.Setenv("SEED_TEST_SECRET", "sk_test_123")
+	clock := store.NewClock()
+	clock.Set(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	clock.Freeze()
+	state := newMockState()
+	srv := setupTestServer(state, clock, nil)
+	defer srv.Close()
+
+	seed := `{"secret": "${SEED_TEST_SECRET}", "expires": "${now+24h}"}`
+	resp, err := http.Post(srv.URL+"/admin/state", "application/json", strings.NewReader(seed))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if state.data["secret"] != "${SEED_TEST_SECRET}" {
+		t.Errorf("expected templates left as sent without expand=true, got %+v", state.data)
+	}
+
+	resp, err = http.Post(srv.URL+"/admin/state?expand=true", "application/json", strings.NewReader(seed))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if state.data["secret"] != "sk_test_123" || state.data["expires"] != "2025-03-02T12:00:00Z" {
+		t.Errorf("expected templates expanded against the simulated clock, got %+v", state.data)
+	}
+
+	resp, err = http.Post(srv.URL+"/admin/state?expand=true", "application/json", strings.NewReader(`{"secret": "${SEED_TEST_UNSET}"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unset variable, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleLoadStateInvalid(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/state", "application/json", strings.NewReader("{bad json"))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// mockRelationState declares seed relations on mockState.
+type mockRelationState struct {
+	*mockState
+}
+
+func (m *mockRelationState) SeedRelations() []seedlint.Relation {
+	return []seedlint.Relation{{From: "claimed_rewards", Field: "reward_id", To: "rewards"}}
+}
+
+func TestHandleLintState(t *testing.T) {
+	state := &mockRelationState{mockState: newMockState()}
+	srv := setupTestServer(state, nil, nil)
+	defer srv.Close()
+
+	lint := func(body string) (int, SeedLintResult) {
+		resp, err := http.Post(srv.URL+"/admin/state/lint", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var result SeedLintResult
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+
+	code, result := lint(`{"rewards": {"1": {}}, "claimed_rewards": {"7": {"reward_id": 1}}}`)
+	if code != http.StatusOK || !result.Valid || result.Relations != 1 {
+		t.Errorf("expected a valid seed, got %d %+v", code, result)
+	}
+
+	code, result = lint(`{"rewards": {"1": {}}, "claimed_rewards": {"7": {"reward_id": 2}}}`)
+	if code != http.StatusOK || result.Valid || len(result.Problems) != 1 {
+		t.Fatalf("expected one problem, got %d %+v", code, result)
+	}
+	if got := result.Problems[0].String(); got != `claimed_rewards["7"].reward_id: references rewards "2", which is not in the seed` {
+		t.Errorf("unexpected problem: %s", got)
+	}
+	if state.data["key"] != "value" {
+		t.Error("expected linting to leave state untouched")
+	}
+
+	if code, _ = lint("{bad json"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for malformed JSON, got %d", code)
+	}
+}
+
+func TestHandleLintStateWithoutRelations(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/state/lint", "application/json", strings.NewReader(`{"key": "value"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var result SeedLintResult
+	json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK || !result.Valid || result.Relations != 0 {
+		t.Errorf("expected a valid result with no relations, got %d %+v", resp.StatusCode, result)
+	}
+}
+
+func TestHandleInjectFault(t *testing.T) {
+	cfg := &twincore.Config{Name: "test"}
+	mw := twincore.NewMiddleware(cfg, nil)
+	state := newMockState()
+
+	h := NewHandler(state, mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	body := `{"status_code":503,"rate":1.0}`
+	resp, err := http.Post(srv.URL+"/admin/fault/charges", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	// Verify the fault was registered (handler prepends "/" to the endpoint param)
+	fault := mw.Faults.Check("/charges")
+	if fault == nil {
+		t.Fatal("expected fault to be registered")
+	}
+	if fault.StatusCode != 503 {
+		t.Errorf("expected status 503, got %d", fault.StatusCode)
+	}
+}
+
+func TestHandleInjectFaultMultiSegment(t *testing.T) {
+	cfg := &twincore.Config{Name: "test"}
+	mw := twincore.NewMiddleware(cfg, nil)
+
+	h := NewHandler(newMockState(), mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/fault/v1/transfers", "application/json", strings.NewReader(`{"status_code":500}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if mw.Faults.Check("/v1/transfers") == nil {
+		t.Fatal("expected fault to be registered for /v1/transfers")
+	}
+}
+
+func TestHandleInjectWebhookFault(t *testing.T) {
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test"}, nil)
+	h := NewHandler(newMockState(), mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/fault/webhook:charge.succeeded", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 from a twin without webhooks, got %d", resp.StatusCode)
+	}
+
+	h.SetFlusher(&mockFlusher{})
+	resp, err = http.Post(srv.URL+"/admin/fault/webhook:charge.succeeded", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if f := mw.Faults.CheckWebhook("charge.succeeded"); f == nil || !f.Drops() {
+		t.Errorf("expected a drop fault registered for charge.succeeded, got %+v", f)
+	}
+
+	resp, err = http.Post(srv.URL+"/admin/fault/webhook:charge.failed", "application/json", strings.NewReader(`{"status_code":500}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a status code on a webhook fault, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleBreakpoints(t *testing.T) {
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test"}, nil)
+	h := NewHandler(newMockState(), mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	post := func(path, body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	if resp := post("/admin/breakpoints", `{"path":"/admin/reset"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an admin path, got %d", resp.StatusCode)
+	}
+	resp := post("/admin/breakpoints", `{"method":"POST","path":"/v1/claims/*","timeout":"50ms"}`)
+	var bp twincore.Breakpoint
+	json.NewDecoder(resp.Body).Decode(&bp)
+	if resp.StatusCode != http.StatusCreated || bp.ID == "" {
+		t.Fatalf("expected 201 with an ID, got %d %+v", resp.StatusCode, bp)
+	}
+	if all := mw.Breakpoints.All(); len(all) != 1 || all[0].Path != "/v1/claims/*" {
+		t.Errorf("expected the breakpoint registered, got %+v", all)
+	}
+
+	resp = post("/admin/breakpoints/"+bp.ID+"/release", "")
+	var released struct {
+		Released int `json:"released"`
+	}
+	json.NewDecoder(resp.Body).Decode(&released)
+	if resp.StatusCode != http.StatusOK || released.Released != 0 {
+		t.Errorf("expected 200 releasing nothing, got %d %+v", resp.StatusCode, released)
+	}
+	if resp := post("/admin/breakpoints/"+bp.ID+"/release", `{"wait_for":1}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 when no request is held in time, got %d", resp.StatusCode)
+	}
+	if resp := post("/admin/breakpoints/bp_404/release", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown breakpoint, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/admin/breakpoints/"+bp.ID, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(mw.Breakpoints.All()) != 0 {
+		t.Errorf("expected the breakpoint removed, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleIntercepts(t *testing.T) {
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test"}, nil)
+	h := NewHandler(newMockState(), mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	post := func(path, body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	if resp := post("/admin/intercepts", `{"path":"/healthz"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a health path, got %d", resp.StatusCode)
+	}
+	resp := post("/admin/intercepts", `{"method":"POST","path":"/v1/charges"}`)
+	var ic twincore.Intercept
+	json.NewDecoder(resp.Body).Decode(&ic)
+	if resp.StatusCode != http.StatusCreated || ic.ID == "" {
+		t.Fatalf("expected 201 with an ID, got %d %+v", resp.StatusCode, ic)
+	}
+	if all := mw.Intercepts.All(); len(all) != 1 || all[0].Path != "/v1/charges" {
+		t.Errorf("expected the intercept registered, got %+v", all)
+	}
+
+	if resp := post("/admin/intercepts/"+ic.ID+"/decide", `{"action":"shrug"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown action, got %d", resp.StatusCode)
+	}
+	if resp := post("/admin/intercepts/"+ic.ID+"/decide", `{"action":"approve"}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 when no response is held, got %d", resp.StatusCode)
+	}
+	if resp := post("/admin/intercepts/ic_404/decide", `{"action":"approve"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown intercept, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/admin/intercepts/"+ic.ID, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(mw.Intercepts.All()) != 0 {
+		t.Errorf("expected the intercept removed, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleInjectScheduledFault(t *testing.T) {
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test"}, nil)
+	clk := store.NewClock()
+	clk.Freeze()
+	h := NewHandler(newMockState(), mw, clk)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	body := `{"status_code":503,"after":"10m","duration":"5m","clock":"simulated"}`
+	resp, err := http.Post(srv.URL+"/admin/fault/v1/charges", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var result struct {
+		Fault twincore.FaultConfig `json:"fault"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if result.Fault.StartAt == nil || !result.Fault.StartAt.Equal(clk.Now().Add(10*time.Minute)) {
+		t.Errorf("expected start_at 10m after the simulated now, got %v", result.Fault.StartAt)
+	}
+
+	if mw.Faults.Check("/v1/charges") != nil {
+		t.Error("expected the fault to be inactive before its window")
+	}
+	clk.Advance(12 * time.Minute)
+	if mw.Faults.Check("/v1/charges") == nil {
+		t.Error("expected the fault to be active after advancing simulated time into its window")
+	}
+	clk.Advance(5 * time.Minute)
+	if mw.Faults.Check("/v1/charges") != nil {
+		t.Error("expected the fault to end with its window")
+	}
+
+	resp, err = http.Post(srv.URL+"/admin/fault/v1/charges", "application/json", strings.NewReader(`{"status_code":503,"after":"later"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid schedule, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleInjectFaultInvalidBody(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/fault/test", "application/json", strings.NewReader("{bad"))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleRemoveFault(t *testing.T) {
+	cfg := &twincore.Config{Name: "test"}
+	mw := twincore.NewMiddleware(cfg, nil)
+	mw.Faults.Set("/test", twincore.FaultConfig{StatusCode: 500, Rate: 1.0})
+
+	state := newMockState()
+	h := NewHandler(state, mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/admin/fault/test", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if mw.Faults.Check("/test") != nil {
+		t.Error("expected fault to be removed")
+	}
+}
+
+func TestHandleRemoveFaultNotFound(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/admin/fault/nonexistent", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleListFaults(t *testing.T) {
+	cfg := &twincore.Config{Name: "test"}
+	mw := twincore.NewMiddleware(cfg, nil)
+	mw.Faults.Set("/a", twincore.FaultConfig{StatusCode: 500, Rate: 1.0})
+	mw.Faults.Check("/a")
+	mw.Faults.Check("/a")
+
+	state := newMockState()
+	h := NewHandler(state, mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/faults")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body map[string]twincore.FaultConfig
+	json.NewDecoder(resp.Body).Decode(&body)
+	f, ok := body["/a"]
+	if !ok {
+		t.Fatalf("expected fault /a in listing, got %+v", body)
+	}
+	if f.ID == "" || f.Hits != 2 || f.LastHitAt == nil {
+		t.Errorf("expected an ID and 2 hits with a last hit time, got %+v", f)
+	}
+}
+
+func TestHandleGetRequests(t *testing.T) {
+	cfg := &twincore.Config{Name: "test"}
+	mw := twincore.NewMiddleware(cfg, nil)
+	mw.ReqLog.Add(twincore.RequestLogEntry{Method: "GET", Path: "/test"})
+
+	state := newMockState()
+	h := NewHandler(state, mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/requests")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body []twincore.RequestLogEntry
+	json.NewDecoder(resp.Body).Decode(&body)
+	if len(body) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(body))
+	}
+	if body[0].Method != "GET" {
+		t.Errorf("expected GET, got %s", body[0].Method)
+	}
+}
+
+func TestHandleShadowDiffs(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"amount":200}`))
+	}))
+	defer target.Close()
+
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test", ShadowURL: target.URL}, nil)
+	api := mw.Shadow(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"amount":100}`))
+	}))
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/charges/ch_1", nil))
+	mw.ShadowLog.Wait()
+
+	h := NewHandler(newMockState(), mw, nil)
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/shadow/diffs")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var report twincore.ShadowReport
+	json.NewDecoder(resp.Body).Decode(&report)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if report.Target != target.URL || report.Compared != 1 || len(report.Diffs) != 1 {
+		t.Fatalf("expected one diff against %s, got %+v", target.URL, report)
+	}
+	if got := report.Diffs[0].Differences; len(got) != 1 || got[0] != "$.amount: 100 != 200" {
+		t.Errorf("unexpected differences: %q", got)
+	}
+
+	resp, err = http.Post(srv.URL+"/admin/reset", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if r := mw.ShadowReport(); r.Compared != 0 || len(r.Diffs) != 0 {
+		t.Errorf("expected reset to clear shadow diffs, got %+v", r)
+	}
+}
+
+func TestHandleTimeAdvance(t *testing.T) {
+	clk := store.NewClock()
+	srv := setupTestServer(newMockState(), clk, nil)
+	defer srv.Close()
+
+	body := `{"duration":"1h"}`
+	resp, err := http.Post(srv.URL+"/admin/time/advance", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var result map[string]any
+	json.NewDecoder(resp.Body).Decode(&result)
+	if result["status"] != "advanced" {
+		t.Errorf("expected status=advanced, got %v", result["status"])
+	}
+}
+
+func TestHandleTimeAdvanceNoClock(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	body := `{"duration":"1h"}`
+	resp, err := http.Post(srv.URL+"/admin/time/advance", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 when no clock configured, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleTimeAdvanceInvalidDuration(t *testing.T) {
+	clk := store.NewClock()
+	srv := setupTestServer(newMockState(), clk, nil)
+	defer srv.Close()
+
+	body := `{"duration":"not-a-duration"}`
+	resp, err := http.Post(srv.URL+"/admin/time/advance", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid duration, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleTimeAdvanceInvalidJSON(t *testing.T) {
+	clk := store.NewClock()
+	srv := setupTestServer(newMockState(), clk, nil)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/time/advance", "application/json", strings.NewReader("{bad"))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid JSON, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleTimeSet(t *testing.T) {
+	clk := store.NewClock()
+	srv := setupTestServer(newMockState(), clk, nil)
+	defer srv.Close()
+
+	body := `{"to":"2028-02-29T12:00:00Z"}`
+	resp, err := http.Post(srv.URL+"/admin/time/set", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	want := time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)
+	if diff := clk.Now().Sub(want); diff < 0 || diff > time.Second {
+		t.Errorf("expected clock at %v, got %v", want, clk.Now())
+	}
+}
+
+func TestHandleTimeSetInvalid(t *testing.T) {
+	srv := setupTestServer(newMockState(), store.NewClock(), nil)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/time/set", "application/json", strings.NewReader(`{"to":"tomorrow"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a non-RFC 3339 time, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleTimeFreezeAndUnfreeze(t *testing.T) {
+	clk := store.NewClock()
+	srv := setupTestServer(newMockState(), clk, nil)
+	defer srv.Close()
+
+	post := func(path string) map[string]any {
+		t.Helper()
+		resp, err := http.Post(srv.URL+path, "application/json", nil)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, resp.StatusCode)
+		}
+		var result map[string]any
+		json.NewDecoder(resp.Body).Decode(&result)
+		return result
+	}
+
+	if result := post("/admin/time/freeze"); result["frozen"] != true {
+		t.Errorf("expected frozen=true, got %v", result["frozen"])
+	}
+	if !clk.Frozen() {
+		t.Error("expected clock to be frozen")
+	}
+	if result := post("/admin/time/unfreeze"); result["frozen"] != false {
+		t.Errorf("expected frozen=false, got %v", result["frozen"])
+	}
+	if clk.Frozen() {
+		t.Error("expected clock to be running")
+	}
+}
+
+func TestHandleTimeFreezeNoClock(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/time/freeze", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 when no clock configured, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleGetTime(t *testing.T) {
+	clk := store.NewClock()
+	srv := setupTestServer(newMockState(), clk, nil)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/time")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body map[string]any
+	json.NewDecoder(resp.Body).Decode(&body)
+	if _, ok := body["real"]; !ok {
+		t.Error("expected 'real' field")
+	}
+	if _, ok := body["simulated"]; !ok {
+		t.Error("expected 'simulated' field")
+	}
+	if _, ok := body["offset"]; !ok {
+		t.Error("expected 'offset' field")
+	}
+}
+
+func TestHandleGetTimeNoClock(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/time")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body map[string]any
+	json.NewDecoder(resp.Body).Decode(&body)
+	if _, ok := body["real"]; !ok {
+		t.Error("expected 'real' field")
+	}
+	if _, ok := body["simulated"]; ok {
+		t.Error("did not expect 'simulated' field when no clock")
+	}
+}
+
+func TestHandleFlushWebhooksNoFlusher(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/webhooks/flush", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body map[string]string
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body["status"] != "no webhooks configured" {
+		t.Errorf("unexpected status: %s", body["status"])
 	}
 }
 
@@ -665,68 +1749,408 @@ func TestHandleGetConfigNilProvider(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNotFound {
-		t.Errorf("expected 404 when no config provider, got %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 when no config provider, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleUpdateConfig(t *testing.T) {
+	cp := newMockConfigProvider()
+	srv := setupTestServerFull(testServerOpts{config: cp})
+	defer srv.Close()
+
+	body := `{"verbose": true, "latency": "200ms"}`
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/admin/config", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if cp.cfg["verbose"] != true {
+		t.Errorf("expected verbose=true, got %v", cp.cfg["verbose"])
+	}
+	if cp.cfg["latency"] != "200ms" {
+		t.Errorf("expected latency=200ms, got %v", cp.cfg["latency"])
+	}
+}
+
+func TestHandleUpdateConfigNilProvider(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/admin/config", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 when no config provider, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleUpdateConfigInvalidJSON(t *testing.T) {
+	srv := setupTestServerFull(testServerOpts{config: newMockConfigProvider()})
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/admin/config", strings.NewReader("{bad"))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Webhook and event inspection tests
+// ---------------------------------------------------------------------------
+
+func TestHandleListWebhooks(t *testing.T) {
+	d := webhook.NewDispatcher(webhook.Config{})
+	d.Enqueue("transfer.created", map[string]any{"id": "tr_1"})
+	srv := setupTestServerFull(testServerOpts{hooks: d})
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/webhooks")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Queued     []webhook.Event    `json:"queued"`
+		Deliveries []webhook.Delivery `json:"deliveries"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if len(body.Queued) != 1 || body.Queued[0].Type != "transfer.created" {
+		t.Errorf("expected one queued transfer.created event, got %+v", body.Queued)
+	}
+	if len(body.Deliveries) != 0 {
+		t.Errorf("expected no deliveries, got %d", len(body.Deliveries))
+	}
+}
+
+func TestHandleListEvents(t *testing.T) {
+	d := webhook.NewDispatcher(webhook.Config{})
+	d.Enqueue("payout.paid", map[string]any{"id": "po_1"})
+	d.Enqueue("payout.failed", map[string]any{"id": "po_2"})
+	srv := setupTestServerFull(testServerOpts{hooks: d})
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/events")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body []webhook.Event
+	json.NewDecoder(resp.Body).Decode(&body)
+	if len(body) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(body))
+	}
+}
+
+func TestHandleListWebhooksNilInspector(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	for _, path := range []string{"/admin/webhooks", "/admin/events"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+// mockEventTrigger emits events into a slice.
+type mockEventTrigger struct {
+	triggered []map[string]any
+}
+
+func (m *mockEventTrigger) EventCatalog() []EventType {
+	return []EventType{
+		{Type: "charge.succeeded", Description: "A charge succeeded.", Example: map[string]any{
+			"id": "ch_example", "amount": float64(1000), "metadata": map[string]any{"order": "1", "source": "web"},
+		}},
+		{Type: "payout.paid", Example: map[string]any{"id": "po_example"}},
+	}
+}
+
+func (m *mockEventTrigger) TriggerEvent(eventType string, payload map[string]any) (any, error) {
+	if payload["amount"] == float64(0) {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	m.triggered = append(m.triggered, payload)
+	return map[string]any{"id": "evt_000001", "type": eventType, "data": payload}, nil
+}
+
+func TestHandleEventCatalog(t *testing.T) {
+	srv := setupTestServerFull(testServerOpts{events: &mockEventTrigger{}})
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/webhooks/events/catalog")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var catalog []EventType
+	json.NewDecoder(resp.Body).Decode(&catalog)
+	if resp.StatusCode != http.StatusOK || len(catalog) != 2 || catalog[0].Type != "charge.succeeded" || catalog[0].Example["id"] != "ch_example" {
+		t.Errorf("unexpected catalog: %d %+v", resp.StatusCode, catalog)
+	}
+}
+
+func TestHandleTriggerEvent(t *testing.T) {
+	events := &mockEventTrigger{}
+	srv := setupTestServerFull(testServerOpts{events: events})
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/webhooks/trigger", "application/json",
+		strings.NewReader(`{"type": "charge.succeeded", "overrides": {"amount": 5000, "metadata": {"order": "42"}}}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var body struct {
+		Status string         `json:"status"`
+		Event  map[string]any `json:"event"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body.Status != "triggered" || body.Event["id"] != "evt_000001" {
+		t.Errorf("unexpected response: %+v", body)
+	}
+
+	if len(events.triggered) != 1 {
+		t.Fatalf("expected one triggered event, got %d", len(events.triggered))
+	}
+	payload := events.triggered[0]
+	metadata := payload["metadata"].(map[string]any)
+	if payload["id"] != "ch_example" || payload["amount"] != float64(5000) || metadata["order"] != "42" || metadata["source"] != "web" {
+		t.Errorf("expected the example with overrides merged in, got %v", payload)
+	}
+
+	// Overrides never change the catalog's example.
+	if example := events.EventCatalog()[0].Example; example["amount"] != float64(1000) {
+		t.Errorf("expected the example untouched, got %v", example)
+	}
+}
+
+func TestHandleTriggerEventErrors(t *testing.T) {
+	srv := setupTestServerFull(testServerOpts{events: &mockEventTrigger{}})
+	defer srv.Close()
+
+	tests := []struct {
+		body     string
+		status   int
+		contains string
+	}{
+		{`{"type": "customer.created"}`, http.StatusNotFound, "charge.succeeded, payout.paid"},
+		{`{}`, http.StatusBadRequest, "type is required"},
+		{`not json`, http.StatusBadRequest, "invalid request"},
+		{`{"type": "charge.succeeded", "overrides": {"amount": 0}}`, http.StatusBadRequest, "amount must be positive"},
+	}
+	for _, tt := range tests {
+		resp, err := http.Post(srv.URL+"/admin/webhooks/trigger", "application/json", strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || !strings.Contains(string(data), tt.contains) {
+			t.Errorf("%s: expected %d containing %q, got %d %s", tt.body, tt.status, tt.contains, resp.StatusCode, data)
+		}
+	}
+}
+
+func TestHandleTriggerEventUnsupported(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/webhooks/events/catalog")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+	resp, err = http.Post(srv.URL+"/admin/webhooks/trigger", "application/json", strings.NewReader(`{"type": "charge.succeeded"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+// mockDeadLetterQueue dead-letters one event; retrying it fails until down
+// is cleared.
+type mockDeadLetterQueue struct {
+	dead []webhook.DeadLetter
+	down bool
+}
+
+func (q *mockDeadLetterQueue) Redeliveries() []webhook.Redelivery {
+	return []webhook.Redelivery{{Event: webhook.Event{ID: "evt_000002", Type: "charge.refunded"}, Attempts: 4}}
+}
+
+func (q *mockDeadLetterQueue) DeadLetters() []webhook.DeadLetter { return q.dead }
+
+func (q *mockDeadLetterQueue) RetryDeadLetter(eventID string) error {
+	for i, dl := range q.dead {
+		if dl.Event.ID != eventID {
+			continue
+		}
+		if q.down {
+			return fmt.Errorf("webhook delivery failed: status 503")
+		}
+		q.dead = append(q.dead[:i], q.dead[i+1:]...)
+		return nil
 	}
+	return webhook.ErrNotDeadLettered
 }
 
-func TestHandleUpdateConfig(t *testing.T) {
-	cp := newMockConfigProvider()
-	srv := setupTestServerFull(testServerOpts{config: cp})
+func TestHandleDeadLetters(t *testing.T) {
+	q := &mockDeadLetterQueue{
+		dead: []webhook.DeadLetter{{Event: webhook.Event{ID: "evt_000001", Type: "charge.succeeded"}, Attempts: 15, LastError: "webhook delivery failed: status 503"}},
+		down: true,
+	}
+	srv := setupTestServerFull(testServerOpts{hooks: webhook.NewDispatcher(webhook.Config{}), dead: q})
 	defer srv.Close()
 
-	body := `{"verbose": true, "latency": "200ms"}`
-	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/admin/config", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := http.Get(srv.URL + "/admin/webhooks/dead_letters")
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
-	defer resp.Body.Close()
+	var dead []webhook.DeadLetter
+	json.NewDecoder(resp.Body).Decode(&dead)
+	resp.Body.Close()
+	if len(dead) != 1 || dead[0].Event.ID != "evt_000001" || dead[0].Attempts != 15 {
+		t.Errorf("unexpected dead letters: %+v", dead)
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("expected 200, got %d", resp.StatusCode)
+	resp, err = http.Get(srv.URL + "/admin/webhooks")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var hooks struct {
+		Redeliveries []webhook.Redelivery `json:"redeliveries"`
+	}
+	json.NewDecoder(resp.Body).Decode(&hooks)
+	resp.Body.Close()
+	if len(hooks.Redeliveries) != 1 || hooks.Redeliveries[0].Event.ID != "evt_000002" {
+		t.Errorf("expected /admin/webhooks to list redeliveries, got %+v", hooks.Redeliveries)
 	}
 
-	if cp.cfg["verbose"] != true {
-		t.Errorf("expected verbose=true, got %v", cp.cfg["verbose"])
+	retry := func(id string) int {
+		resp, err := http.Post(srv.URL+"/admin/webhooks/dead_letters/"+id+"/retry", "application/json", nil)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
 	}
-	if cp.cfg["latency"] != "200ms" {
-		t.Errorf("expected latency=200ms, got %v", cp.cfg["latency"])
+	if code := retry("evt_000001"); code != http.StatusBadGateway {
+		t.Errorf("expected 502 while the endpoint is down, got %d", code)
+	}
+	q.down = false
+	if code := retry("evt_000001"); code != http.StatusOK {
+		t.Errorf("expected 200, got %d", code)
+	}
+	if code := retry("evt_000001"); code != http.StatusNotFound {
+		t.Errorf("expected 404 once redelivered, got %d", code)
 	}
 }
 
-func TestHandleUpdateConfigNilProvider(t *testing.T) {
+func TestHandleDeadLettersUnsupported(t *testing.T) {
 	srv := setupTestServer(newMockState(), nil, nil)
 	defer srv.Close()
 
-	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/admin/config", strings.NewReader(`{}`))
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := http.Get(srv.URL + "/admin/webhooks/dead_letters")
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
-	defer resp.Body.Close()
-
+	resp.Body.Close()
 	if resp.StatusCode != http.StatusNotFound {
-		t.Errorf("expected 404 when no config provider, got %d", resp.StatusCode)
+		t.Errorf("expected 404, got %d", resp.StatusCode)
 	}
 }
 
-func TestHandleUpdateConfigInvalidJSON(t *testing.T) {
-	srv := setupTestServerFull(testServerOpts{config: newMockConfigProvider()})
+func TestHandleTemplates(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
 	defer srv.Close()
 
-	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/admin/config", strings.NewReader("{bad"))
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	put := func(path, contentType, body string) int {
+		req, _ := http.NewRequest(http.MethodPut, srv.URL+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := put("/admin/templates/get/v1/customers/%7Bid%7D", "application/json", `{"id": "{{record.id}}"}`); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := put("/admin/templates/POST/v1/customers", "text/plain", `{"id": {{json .record.id}}}`); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := put("/admin/templates/GET/v1/charges", "application/json", `{bad`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid template, got %d", code)
+	}
+
+	resp, err := http.Get(srv.URL + "/admin/templates")
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
-	defer resp.Body.Close()
+	var templates []twincore.ResponseTemplate
+	json.NewDecoder(resp.Body).Decode(&templates)
+	resp.Body.Close()
+	if len(templates) != 2 || templates[0].Route != "GET /v1/customers/{id}" || templates[0].Format != twincore.TemplateFormatJSON ||
+		templates[1].Route != "POST /v1/customers" || templates[1].Format != twincore.TemplateFormatGo {
+		t.Errorf("unexpected templates: %+v", templates)
+	}
 
-	if resp.StatusCode != http.StatusBadRequest {
-		t.Errorf("expected 400, got %d", resp.StatusCode)
+	del := func(path string) int {
+		req, _ := http.NewRequest(http.MethodDelete, srv.URL+path, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := del("/admin/templates/POST/v1/customers"); code != http.StatusOK {
+		t.Errorf("expected 200, got %d", code)
+	}
+	if code := del("/admin/templates/POST/v1/customers"); code != http.StatusNotFound {
+		t.Errorf("expected 404 once removed, got %d", code)
 	}
 }
 
@@ -880,3 +2304,446 @@ func TestHandleDisableQuirkNilStore(t *testing.T) {
 		t.Errorf("expected 404 when no quirk store, got %d", resp.StatusCode)
 	}
 }
+
+func setupReplayServer(captureBodies bool) (*httptest.Server, *twincore.Middleware) {
+	cfg := &twincore.Config{Name: "test", CaptureBodies: captureBodies}
+	mw := twincore.NewMiddleware(cfg, nil)
+
+	r := chi.NewRouter()
+	r.Use(mw.RequestLog)
+	r.Post("/v1/echo", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		body["auth"] = r.Header.Get("Authorization")
+		body["replay_of"] = r.Header.Get("X-WonderTwin-Replay-Of")
+		twincore.JSON(w, http.StatusCreated, body)
+	})
+
+	h := NewHandler(newMockState(), mw, nil)
+	h.Routes(r)
+	return httptest.NewServer(r), mw
+}
+
+func TestHandleReplayRequest(t *testing.T) {
+	srv, mw := setupReplayServer(true)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/echo", strings.NewReader(`{"amount": 42}`))
+	req.Header.Set("Authorization", "Bearer sk_test")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	entries := mw.ReqLog.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 logged request, got %d", len(entries))
+	}
+	id := entries[0].ID
+
+	resp, err = http.Post(srv.URL+"/admin/requests/"+id+"/replay", "application/json", nil)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status   string `json:"status"`
+		Response struct {
+			StatusCode int            `json:"status_code"`
+			Body       map[string]any `json:"body"`
+		} `json:"response"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body.Status != "replayed" {
+		t.Errorf("expected status=replayed, got %q", body.Status)
+	}
+	if body.Response.StatusCode != http.StatusCreated {
+		t.Errorf("expected replayed status 201, got %d", body.Response.StatusCode)
+	}
+	if body.Response.Body["amount"] != float64(42) {
+		t.Errorf("expected replayed body to carry amount=42, got %v", body.Response.Body["amount"])
+	}
+	if body.Response.Body["auth"] != "Bearer sk_test" {
+		t.Errorf("expected original Authorization header, got %v", body.Response.Body["auth"])
+	}
+	if body.Response.Body["replay_of"] != id {
+		t.Errorf("expected replay header %q, got %v", id, body.Response.Body["replay_of"])
+	}
+}
+
+func TestHandleReplayRequestNotFound(t *testing.T) {
+	srv, _ := setupReplayServer(true)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/requests/req_999999/replay", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleReplayRequestWithoutCapture(t *testing.T) {
+	srv, mw := setupReplayServer(false)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/echo", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	id := mw.ReqLog.Entries()[0].ID
+	resp, err = http.Post(srv.URL+"/admin/requests/"+id+"/replay", "application/json", nil)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleCorrelations(t *testing.T) {
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test"}, nil)
+	d := webhook.NewDispatcher(webhook.Config{})
+
+	r := chi.NewRouter()
+	r.Use(chimw.RequestID)
+	r.Use(mw.RequestLog)
+	r.Post("/v1/charges", func(w http.ResponseWriter, r *http.Request) {
+		mw.Events.Record(r.Context(), "evt_1", "charge.succeeded")
+		d.EnqueueContext(r.Context(), "charge.succeeded", map[string]any{"id": "evt_1"})
+		twincore.JSON(w, http.StatusOK, map[string]any{})
+	})
+	h := NewHandler(newMockState(), mw, nil)
+	h.SetWebhookInspector(d)
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/charges", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	requestID := resp.Header.Get(twincore.RequestIDHeader)
+	if requestID == "" {
+		t.Fatal("expected the request ID to be echoed")
+	}
+
+	// By the echoed ID, and by the request log entry ID.
+	for _, id := range []string{requestID, mw.ReqLog.Entries()[0].ID} {
+		resp, err = http.Get(srv.URL + "/admin/correlations?request_id=" + url.QueryEscape(id))
+		if err != nil {
+			t.Fatalf("correlations failed: %v", err)
+		}
+		var body struct {
+			RequestID string                    `json:"request_id"`
+			Request   *twincore.RequestLogEntry `json:"request"`
+			Events    []twincore.JournalEntry   `json:"events"`
+			Webhooks  []webhook.Correlation     `json:"webhooks"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", id, resp.StatusCode)
+		}
+		if body.RequestID != requestID || body.Request == nil || body.Request.Path != "/v1/charges" {
+			t.Errorf("%s: expected the logged request, got %q %+v", id, body.RequestID, body.Request)
+		}
+		if len(body.Events) != 1 || body.Events[0].EventID != "evt_1" {
+			t.Errorf("%s: expected evt_1 in events, got %+v", id, body.Events)
+		}
+		if len(body.Webhooks) != 1 || body.Webhooks[0].Status != webhook.StatusPending {
+			t.Errorf("%s: expected one pending webhook, got %+v", id, body.Webhooks)
+		}
+	}
+
+	for query, want := range map[string]int{"": http.StatusBadRequest, "?request_id=nope": http.StatusNotFound} {
+		resp, err = http.Get(srv.URL + "/admin/correlations" + query)
+		if err != nil {
+			t.Fatalf("correlations failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%q: expected %d, got %d", query, want, resp.StatusCode)
+		}
+	}
+}
+
+// mockTenantState mints tenants with sequential credentials.
+type mockTenantState struct {
+	*mockState
+	tenants []Tenant
+}
+
+func (m *mockTenantState) CreateTenant(req TenantRequest) (Tenant, error) {
+	creds := req.Credentials
+	if creds == nil {
+		creds = map[string]string{"api_key": fmt.Sprintf("key_%d", len(m.tenants)+1)}
+	}
+	for _, t := range m.tenants {
+		if t.Credentials["api_key"] == creds["api_key"] {
+			return Tenant{}, fmt.Errorf("api_key %q already in use", creds["api_key"])
+		}
+	}
+	t := Tenant{ID: creds["api_key"], Name: req.Name, Credentials: creds}
+	m.tenants = append(m.tenants, t)
+	return t, nil
+}
+
+func (m *mockTenantState) ListTenants() []Tenant {
+	return m.tenants
+}
+
+func (m *mockTenantState) DeleteTenant(id string) error {
+	for i, t := range m.tenants {
+		if t.ID == id {
+			m.tenants = append(m.tenants[:i], m.tenants[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no tenant %q", id)
+}
+
+func TestHandleTenants(t *testing.T) {
+	state := &mockTenantState{mockState: newMockState()}
+	srv := setupTestServer(state, nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/tenants")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var tenants []Tenant
+	json.NewDecoder(resp.Body).Decode(&tenants)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || tenants == nil || len(tenants) != 0 {
+		t.Errorf("expected 200 with an empty list, got %d %v", resp.StatusCode, tenants)
+	}
+
+	resp, err = http.Post(srv.URL+"/admin/tenants", "application/json", strings.NewReader(`{"name": "job-1"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var created Tenant
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201, got %d", resp.StatusCode)
+	}
+	if created.Name != "job-1" || created.Credentials["api_key"] != "key_1" {
+		t.Errorf("unexpected tenant %+v", created)
+	}
+
+	// An empty body is allowed
+	resp, err = http.Post(srv.URL+"/admin/tenants", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || len(state.tenants) != 2 {
+		t.Errorf("expected a second tenant, got %d with %d tenants", resp.StatusCode, len(state.tenants))
+	}
+}
+
+func TestHandleCreateTenantConflict(t *testing.T) {
+	state := &mockTenantState{mockState: newMockState()}
+	srv := setupTestServer(state, nil, nil)
+	defer srv.Close()
+
+	for i, want := range []int{http.StatusCreated, http.StatusBadRequest} {
+		resp, err := http.Post(srv.URL+"/admin/tenants", "application/json", strings.NewReader(`{"credentials": {"api_key": "fixed"}}`))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("request %d: expected %d, got %d", i+1, want, resp.StatusCode)
+		}
+	}
+}
+
+func TestHandleTenantsUnsupported(t *testing.T) {
+	srv := setupTestServer(newMockState(), nil, nil)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/admin/tenants", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleDeleteTenant(t *testing.T) {
+	state := &mockTenantState{mockState: newMockState()}
+	state.CreateTenant(TenantRequest{})
+	srv := setupTestServer(state, nil, nil)
+	defer srv.Close()
+
+	for i, want := range []int{http.StatusOK, http.StatusNotFound} {
+		req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/admin/tenants/key_1", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("request %d: expected %d, got %d", i+1, want, resp.StatusCode)
+		}
+	}
+	if len(state.tenants) != 0 {
+		t.Errorf("expected the tenant to be deleted, got %v", state.tenants)
+	}
+}
+
+func TestHandleWebhookEndpoints(t *testing.T) {
+	d := webhook.NewDispatcher(webhook.Config{})
+	state := newMockState()
+	srv := setupTestServerFull(testServerOpts{state: state, hooks: d})
+	defer srv.Close()
+
+	send := func(method, path, body string) int {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := send(http.MethodPut, "/admin/webhooks/endpoints/acct_a", `{"url": "http://localhost:9000/a", "secret": "whsec_a"}`); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := send(http.MethodPut, "/admin/webhooks/endpoints/acct_b", `{"url": "not a url"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a relative URL, got %d", code)
+	}
+	seed := `{"key": "seeded", "webhook_endpoints": {"acct_c": {"secret": "whsec_c"}}}`
+	if code := send(http.MethodPost, "/admin/state", seed); code != http.StatusOK {
+		t.Fatalf("expected the seed to load, got %d", code)
+	}
+	if state.data["key"] != "seeded" {
+		t.Errorf("expected the rest of the seed loaded, got %v", state.data)
+	}
+
+	resp, err := http.Get(srv.URL + "/admin/webhooks/endpoints")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var body map[string]webhook.Endpoint
+	json.NewDecoder(resp.Body).Decode(&body)
+	resp.Body.Close()
+	if body["acct_a"].Secret != "whsec_a" || body["acct_c"].Secret != "whsec_c" || len(body) != 2 {
+		t.Errorf("expected endpoints for acct_a and acct_c, got %+v", body)
+	}
+
+	if code := send(http.MethodDelete, "/admin/webhooks/endpoints/acct_a", ""); code != http.StatusOK {
+		t.Errorf("expected 200, got %d", code)
+	}
+	if code := send(http.MethodDelete, "/admin/webhooks/endpoints/acct_a", ""); code != http.StatusNotFound {
+		t.Errorf("expected 404 for a removed endpoint, got %d", code)
+	}
+}
+
+func TestHandleAudit(t *testing.T) {
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "twin-test", AuditKey: "k3y"}, nil)
+	h := NewHandler(newMockState(), mw, nil)
+	r := chi.NewRouter()
+	r.Use(mw.AuditMutations)
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	post := func(path, body string) int {
+		t.Helper()
+		resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := post("/admin/reset", ""); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := post("/admin/audit/events", `{"kind": "scenario", "scenario": "checkout", "passed": true}`); code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", code)
+	}
+	if code := post("/admin/audit/events", `{"kind": "admin", "scenario": "checkout"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a kind other than scenario, got %d", code)
+	}
+	if code := post("/admin/audit/events", `{"kind": "scenario"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 without a scenario, got %d", code)
+	}
+
+	resp, err := http.Get(srv.URL + "/admin/audit/export")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var exp twincore.AuditExport
+	json.NewDecoder(resp.Body).Decode(&exp)
+	resp.Body.Close()
+	if exp.Twin != "twin-test" || exp.Signature == "" || len(exp.Entries) != 2 {
+		t.Fatalf("expected a signed export of the reset and the scenario, got %+v", exp)
+	}
+	reset, run := exp.Entries[0], exp.Entries[1]
+	if reset.Kind != twincore.AuditAdmin || reset.Path != "/admin/reset" {
+		t.Errorf("expected the reset recorded, got %+v", reset)
+	}
+	if run.Scenario != "checkout" || run.Passed == nil || !*run.Passed || run.PrevHash != reset.Hash {
+		t.Errorf("expected the scenario chained after the reset, got %+v", run)
+	}
+}
+
+func TestHandleListRoutes(t *testing.T) {
+	mw := twincore.NewMiddleware(&twincore.Config{Name: "test"}, nil)
+	r := chi.NewRouter()
+	r.Use(mw.RequestLog)
+	r.Route("/v1/items", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+		r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+	NewHandler(newMockState(), mw, nil).Routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/items/item_1")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if entries := mw.ReqLog.Entries(); len(entries) != 1 || entries[0].Route != "/v1/items/{id}" {
+		t.Errorf("expected the request logged with its route, got %+v", entries)
+	}
+
+	resp, err = http.Get(srv.URL + "/admin/routes")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var routes []Route
+	if err := json.NewDecoder(resp.Body).Decode(&routes); err != nil {
+		t.Fatalf("decoding routes: %v", err)
+	}
+	want := []Route{{"GET", "/v1/items/"}, {"DELETE", "/v1/items/{id}"}, {"GET", "/v1/items/{id}"}}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("routes = %v, want %v", routes, want)
+	}
+}
@@ -2,4 +2,9 @@ module github.com/wondertwin-ai/wondertwin/twinkit
 
 go 1.25.7
 
-require github.com/go-chi/chi/v5 v5.2.5
+replace github.com/wondertwin-ai/wondertwin/adminclient => ../adminclient
+
+require (
+	github.com/go-chi/chi/v5 v5.2.5
+	github.com/wondertwin-ai/wondertwin/adminclient v0.1.0
+)
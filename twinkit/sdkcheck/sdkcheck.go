@@ -0,0 +1,105 @@
+// Package sdkcheck is the harness for a twin's SDK conformance program: a
+// small Go program, kept in the twin's sdkcheck/ directory as its own
+// module, that drives the twin with the provider's real SDK. Where handler
+// tests check the responses the twin's authors expected, an SDK check shows
+// the SDK itself accepts them: its base URL override reaches the twin, its
+// auth is accepted, and its requests and responses (de)serialize.
+//
+// The program lists its checks and hands them to Main:
+//
+//	func main() {
+//		sdkcheck.Main(
+//			sdkcheck.Check{Name: "create account", Run: createAccount},
+//			sdkcheck.Check{Name: "auth error", Run: authError},
+//		)
+//	}
+//
+// `wt conformance <binary> --sdk` builds it, starts the twin, and runs it
+// with the twin's URL in WT_TWIN_URL. Main resets the twin before each
+// check and writes one JSON Result per line to stdout for wt to report.
+package sdkcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+)
+
+// Environment variables wt conformance --sdk sets for the program.
+const (
+	EnvTwinURL  = "WT_TWIN_URL"  // base URL of the twin, e.g. http://localhost:19876
+	EnvAdminURL = "WT_ADMIN_URL" // base URL of its admin API; WT_TWIN_URL when unset
+)
+
+// Env is what a check gets to work with.
+type Env struct {
+	// URL is the twin's base URL, to point the SDK at.
+	URL string
+	// Admin controls the twin, e.g. to inject an auth fault and check the
+	// SDK surfaces it as its own error type.
+	Admin *adminclient.Client
+	// Ctx is the context for admin calls.
+	Ctx context.Context
+}
+
+// Check is one SDK conformance check. Run returns nil when it passes.
+type Check struct {
+	Name string
+	Run  func(env *Env) error
+}
+
+// Result is the outcome of a check, as written by Main.
+type Result struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Main runs checks in order against the twin named by the environment and
+// exits with status 1 if any fails, or 2 if the environment is incomplete.
+func Main(checks ...Check) {
+	twinURL := os.Getenv(EnvTwinURL)
+	if twinURL == "" {
+		fmt.Fprintf(os.Stderr, "sdkcheck: %s is not set; run this through wt conformance --sdk\n", EnvTwinURL)
+		os.Exit(2)
+	}
+	adminURL := os.Getenv(EnvAdminURL)
+	if adminURL == "" {
+		adminURL = twinURL
+	}
+	env := &Env{URL: twinURL, Admin: adminclient.New(adminURL), Ctx: context.Background()}
+
+	failed := false
+	enc := json.NewEncoder(os.Stdout)
+	for _, c := range checks {
+		res := Run(env, c)
+		failed = failed || !res.Passed
+		enc.Encode(res)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Run resets the twin and runs one check, turning a panic into a failure.
+func Run(env *Env, c Check) (res Result) {
+	res.Name = c.Name
+	defer func() {
+		if p := recover(); p != nil {
+			res.Passed, res.Detail = false, fmt.Sprintf("panic: %v", p)
+		}
+	}()
+	if err := env.Admin.Reset(env.Ctx); err != nil {
+		res.Detail = "resetting the twin: " + err.Error()
+		return res
+	}
+	if err := c.Run(env); err != nil {
+		res.Detail = err.Error()
+		return res
+	}
+	res.Passed = true
+	return res
+}
@@ -0,0 +1,56 @@
+package sdkcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+)
+
+func TestRun(t *testing.T) {
+	resets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/reset" {
+			resets++
+		}
+		w.Write([]byte(`{"status":"reset"}`))
+	}))
+	defer srv.Close()
+	env := &Env{URL: srv.URL, Admin: adminclient.New(srv.URL), Ctx: context.Background()}
+
+	tests := []struct {
+		name       string
+		run        func(*Env) error
+		wantPassed bool
+		wantDetail string
+	}{
+		{"passes", func(*Env) error { return nil }, true, ""},
+		{"fails", func(*Env) error { return errors.New("expected acct_ ID") }, false, "expected acct_ ID"},
+		{"panics", func(*Env) error { panic("nil customer") }, false, "panic: nil customer"},
+	}
+	for _, tt := range tests {
+		res := Run(env, Check{Name: tt.name, Run: tt.run})
+		if res.Name != tt.name || res.Passed != tt.wantPassed || res.Detail != tt.wantDetail {
+			t.Errorf("%s: got %+v", tt.name, res)
+		}
+	}
+	if resets != len(tests) {
+		t.Errorf("expected the twin reset before each of %d checks, got %d resets", len(tests), resets)
+	}
+}
+
+func TestRunResetFails(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	env := &Env{URL: srv.URL, Admin: adminclient.New(srv.URL), Ctx: context.Background()}
+
+	ran := false
+	res := Run(env, Check{Name: "never runs", Run: func(*Env) error { ran = true; return nil }})
+	if ran || res.Passed || !strings.HasPrefix(res.Detail, "resetting the twin") {
+		t.Errorf("expected the check skipped after a failed reset, got ran=%v %+v", ran, res)
+	}
+}
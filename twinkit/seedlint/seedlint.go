@@ -0,0 +1,167 @@
+// Package seedlint checks the referential integrity of a twin seed file
+// before it is loaded, so a claimed reward pointing at a reward that does
+// not exist fails with a precise message instead of surfacing later as a
+// confusing 404 from the twin.
+//
+// A seed file is the JSON body of POST /admin/state: an object of
+// collections, each an object of records keyed by ID. Twins declare how
+// their collections reference each other as Relations:
+//
+//	var relations = []seedlint.Relation{
+//		{From: "claimed_rewards", Field: "reward_id", To: "rewards"},
+//		{From: "points_transactions", Field: "redemption_id", To: "redemptions", Optional: true},
+//	}
+//
+// and Check reports every record whose reference names no record in the
+// target collection.
+package seedlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Relation declares that a field of the records in one collection holds
+// the ID (the record key) of a record in another.
+type Relation struct {
+	From  string `json:"from"`  // collection holding the reference, e.g. "claimed_rewards"
+	Field string `json:"field"` // field holding the ID, dotted for nested objects, e.g. "redeemable.reward_id"
+	To    string `json:"to"`    // referenced collection, e.g. "rewards"
+
+	// Optional allows the field to be absent, null, "", or 0.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// Problem is one broken reference found by Check.
+type Problem struct {
+	Collection string `json:"collection"`
+	ID         string `json:"id"`
+	Field      string `json:"field"`
+	Value      string `json:"value,omitempty"`
+	Message    string `json:"message"`
+}
+
+// String formats the problem as `claimed_rewards["7"].reward_id: ...`.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s[%q].%s: %s", p.Collection, p.ID, p.Field, p.Message)
+}
+
+// Check reports the broken references in seed, sorted by collection, ID,
+// and field. A relation whose target collection is not in the seed is
+// skipped: loading the seed leaves that collection as it is in the twin,
+// so the reference may well resolve. Check returns an error only when seed
+// is not an object of collections of records.
+func Check(seed []byte, relations []Relation) ([]Problem, error) {
+	collections, err := parse(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []Problem{}
+	for _, rel := range relations {
+		from, ok := collections[rel.From]
+		if !ok {
+			continue
+		}
+		to, ok := collections[rel.To]
+		if !ok {
+			continue
+		}
+		for id, record := range from {
+			value, present := lookup(record, rel.Field)
+			ref, ok := idString(value)
+			switch {
+			case !present || value == nil || ref == "" || ref == "0":
+				if !rel.Optional {
+					problems = append(problems, Problem{
+						Collection: rel.From, ID: id, Field: rel.Field,
+						Message: fmt.Sprintf("is required (references %s)", rel.To),
+					})
+				}
+			case !ok:
+				problems = append(problems, Problem{
+					Collection: rel.From, ID: id, Field: rel.Field, Value: ref,
+					Message: fmt.Sprintf("must be an ID in %s, got %s", rel.To, ref),
+				})
+			default:
+				if _, exists := to[ref]; !exists {
+					problems = append(problems, Problem{
+						Collection: rel.From, ID: id, Field: rel.Field, Value: ref,
+						Message: fmt.Sprintf("references %s %q, which is not in the seed", rel.To, ref),
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.Collection != b.Collection {
+			return a.Collection < b.Collection
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Field < b.Field
+	})
+	return problems, nil
+}
+
+// parse decodes seed into collections of records. Collections that are
+// not objects of records (e.g. a twin's scalar settings) are ignored.
+func parse(seed []byte) (map[string]map[string]map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(seed))
+	dec.UseNumber()
+	var top map[string]json.RawMessage
+	if err := dec.Decode(&top); err != nil {
+		return nil, fmt.Errorf("seed is not a JSON object: %w", err)
+	}
+
+	collections := make(map[string]map[string]map[string]any, len(top))
+	for name, raw := range top {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var records map[string]map[string]any
+		if dec.Decode(&records) != nil {
+			continue
+		}
+		collections[name] = records
+	}
+	return collections, nil
+}
+
+// lookup returns the value at a dotted field path.
+func lookup(record map[string]any, field string) (any, bool) {
+	var v any = record
+	for _, part := range strings.Split(field, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// idString renders a reference the way the store keys records: strings
+// as-is and integers in decimal. It reports false for other values.
+func idString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		if _, err := v.Int64(); err != nil {
+			return v.String(), false
+		}
+		return v.String(), true
+	case nil:
+		return "", true
+	}
+	b, _ := json.Marshal(v)
+	return string(b), false
+}
@@ -0,0 +1,68 @@
+package seedlint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var relations = []Relation{
+	{From: "claimed_rewards", Field: "reward_id", To: "rewards"},
+	{From: "claimed_rewards", Field: "customer.id", To: "customers"},
+	{From: "transactions", Field: "redemption_id", To: "redemptions", Optional: true},
+	{From: "transactions", Field: "customer_id", To: "customers"},
+}
+
+func TestCheck(t *testing.T) {
+	seed := `{
+		"customers": {"1": {"id": 1}, "cus_b": {"id": "cus_b"}},
+		"rewards": {"10": {"id": 10}},
+		"redemptions": {},
+		"claimed_rewards": {
+			"100": {"reward_id": 10, "customer": {"id": 1}},
+			"101": {"reward_id": 11, "customer": {"id": "cus_b"}},
+			"102": {"customer": {"id": 2}}
+		},
+		"transactions": {
+			"t1": {"customer_id": "cus_b", "redemption_id": ""},
+			"t2": {"customer_id": 1.5, "redemption_id": "red_9"}
+		},
+		"settings": {"mode": "test"}
+	}`
+	got, err := Check([]byte(seed), relations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		{Collection: "claimed_rewards", ID: "101", Field: "reward_id", Value: "11", Message: `references rewards "11", which is not in the seed`},
+		{Collection: "claimed_rewards", ID: "102", Field: "customer.id", Value: "2", Message: `references customers "2", which is not in the seed`},
+		{Collection: "claimed_rewards", ID: "102", Field: "reward_id", Message: "is required (references rewards)"},
+		{Collection: "transactions", ID: "t2", Field: "customer_id", Value: "1.5", Message: "must be an ID in customers, got 1.5"},
+		{Collection: "transactions", ID: "t2", Field: "redemption_id", Value: "red_9", Message: `references redemptions "red_9", which is not in the seed`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() =\n%+v\nwant\n%+v", got, want)
+	}
+	if s := got[0].String(); s != `claimed_rewards["101"].reward_id: references rewards "11", which is not in the seed` {
+		t.Errorf("unexpected String(): %s", s)
+	}
+}
+
+func TestCheckSkipsCollectionsNotInSeed(t *testing.T) {
+	// Loading this seed keeps the twin's current rewards, so the reference
+	// cannot be judged from the file alone.
+	got, err := Check([]byte(`{"claimed_rewards": {"1": {"reward_id": 99, "customer": {"id": 1}}}}`), relations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no problems, got %+v", got)
+	}
+}
+
+func TestCheckInvalidSeed(t *testing.T) {
+	_, err := Check([]byte(`[1, 2]`), relations)
+	if err == nil || !strings.Contains(err.Error(), "not a JSON object") {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+// Package seedtmpl expands templates in twin seed files, so fixtures can
+// reference secrets and dates relative to the twin's simulated clock
+// without per-developer editing. Templates may appear in any JSON string,
+// including record keys:
+//
+//	${STRIPE_ACCOUNT}          environment variable; an error if unset
+//	${STRIPE_ACCOUNT:-acct_1}  environment variable with a default
+//	${uuid}                    a random UUID (v4), fresh for each occurrence
+//	${now}                     the simulated time, RFC 3339
+//	${now+24h} ${now-7d}       the simulated time offset by a Go duration or days
+//	${now:unix}                as Unix seconds; ${now+1h:unix_ms} as milliseconds
+//	$${...}                    a literal ${...}
+//
+// A string that is nothing but a ${now:unix} or ${now:unix_ms} template
+// becomes a JSON number, so `"created": "${now-1d:unix}"` loads as an
+// integer timestamp.
+package seedtmpl
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envName matches an environment variable name.
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// nowExpr matches a now template: an optional offset, then an optional format.
+var nowExpr = regexp.MustCompile(`^now(?:([+-])([0-9][0-9a-zµ.]*))?(?::(rfc3339|unix|unix_ms))?$`)
+
+// Expand returns seed with its templates expanded, now being the twin's
+// simulated time. A seed without templates is returned unchanged; one with
+// templates is re-encoded, with numbers kept exactly as written. Expand
+// fails on invalid JSON, an unknown template, or an unset environment
+// variable without a default.
+func Expand(seed []byte, now time.Time) ([]byte, error) {
+	if !bytes.Contains(seed, []byte("${")) {
+		return seed, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(seed))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	v, err := expandValue(v, now)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func expandValue(v any, now time.Time) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			key, err := expandString(k, now)
+			if err != nil {
+				return nil, err
+			}
+			if out[key], err = expandValue(val, now); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case []any:
+		for i, val := range v {
+			var err error
+			if v[i], err = expandValue(val, now); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case string:
+		// A lone numeric timestamp template becomes a number.
+		if strings.HasPrefix(v, "${") && strings.Index(v, "}") == len(v)-1 {
+			if m := nowExpr.FindStringSubmatch(v[2 : len(v)-1]); m != nil && strings.HasPrefix(m[3], "unix") {
+				s, err := expandNow(m, now)
+				if err != nil {
+					return nil, err
+				}
+				return json.Number(s), nil
+			}
+		}
+		return expandString(v, now)
+	}
+	return v, nil
+}
+
+// expandString expands every template in s.
+func expandString(s string, now time.Time) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			// $${ is an escaped, literal ${.
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated template in %q", s)
+		}
+		val, err := expandTemplate(s[i+2:i+end], now)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s[:i])
+		b.WriteString(val)
+		s = s[i+end+1:]
+	}
+}
+
+// expandTemplate evaluates the body of one ${...} template.
+func expandTemplate(expr string, now time.Time) (string, error) {
+	if expr == "uuid" {
+		return newUUID(), nil
+	}
+	if m := nowExpr.FindStringSubmatch(expr); m != nil {
+		return expandNow(m, now)
+	}
+	name, def, hasDefault := strings.Cut(expr, ":-")
+	if !envName.MatchString(name) {
+		return "", fmt.Errorf("unknown template ${%s} (expected an environment variable, uuid, or now)", expr)
+	}
+	if v, ok := os.LookupEnv(name); ok {
+		return v, nil
+	}
+	if hasDefault {
+		return def, nil
+	}
+	return "", fmt.Errorf("environment variable %s is not set (use ${%s:-default} for a default)", name, name)
+}
+
+// expandNow formats now, offset and formatted as matched by nowExpr.
+func expandNow(m []string, now time.Time) (string, error) {
+	t := now
+	if m[1] != "" {
+		d, err := parseOffset(m[2])
+		if err != nil {
+			return "", fmt.Errorf("invalid offset in ${%s}: %w", m[0], err)
+		}
+		if m[1] == "-" {
+			d = -d
+		}
+		t = t.Add(d)
+	}
+	switch m[3] {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case "unix_ms":
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// parseOffset parses a Go duration, or a whole number of days such as "7d".
+func parseOffset(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a whole number of days", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,116 @@
+package seedtmpl
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func expand(t *testing.T, seed string) map[string]any {
+	t.Helper()
+	out, err := Expand([]byte(seed), testNow)
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	var v map[string]any
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatalf("Expand produced invalid JSON %s: %v", out, err)
+	}
+	return v
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("SEED_ACCOUNT", "acct_live")
+	v := expand(t, `{"accounts": {"${SEED_ACCOUNT}": {"name": "Shop ${SEED_ACCOUNT}", "region": "${SEED_REGION:-us}"}}}`)
+
+	acct, ok := v["accounts"].(map[string]any)["acct_live"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected the record key to be expanded, got %v", v)
+	}
+	if acct["name"] != "Shop acct_live" || acct["region"] != "us" {
+		t.Errorf("unexpected record: %v", acct)
+	}
+}
+
+func TestExpandUnsetEnv(t *testing.T) {
+	_, err := Expand([]byte(`{"accounts": {"a": {"key": "${SEED_UNSET_SECRET}"}}}`), testNow)
+	if err == nil || !strings.Contains(err.Error(), "SEED_UNSET_SECRET is not set") {
+		t.Errorf("expected an unset variable error, got %v", err)
+	}
+}
+
+func TestExpandNow(t *testing.T) {
+	v := expand(t, `{"r": {"1": {
+		"now": "${now}",
+		"tomorrow": "${now+24h}",
+		"last_week": "${now-7d}",
+		"created": "${now-1h:unix}",
+		"created_ms": "${now:unix_ms}",
+		"note": "expires ${now+1d:unix}"
+	}}}`)
+	rec := v["r"].(map[string]any)["1"].(map[string]any)
+
+	want := map[string]any{
+		"now":        "2025-03-01T12:00:00Z",
+		"tomorrow":   "2025-03-02T12:00:00Z",
+		"last_week":  "2025-02-22T12:00:00Z",
+		"created":    float64(testNow.Add(-time.Hour).Unix()),
+		"created_ms": float64(testNow.UnixMilli()),
+		"note":       "expires 1740916800",
+	}
+	for k, w := range want {
+		if rec[k] != w {
+			t.Errorf("%s: expected %v (%T), got %v (%T)", k, w, w, rec[k], rec[k])
+		}
+	}
+}
+
+func TestExpandUUID(t *testing.T) {
+	v := expand(t, `{"r": {"1": {"a": "${uuid}", "b": "${uuid}"}}}`)
+	rec := v["r"].(map[string]any)["1"].(map[string]any)
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if !uuid.MatchString(rec["a"].(string)) {
+		t.Errorf("expected a v4 UUID, got %v", rec["a"])
+	}
+	if rec["a"] == rec["b"] {
+		t.Error("expected each ${uuid} to be fresh")
+	}
+}
+
+func TestExpandPreservesNumbersAndEscapes(t *testing.T) {
+	out, err := Expand([]byte(`{"r": {"1": {"amount": 12345678901234567890, "t": "${now:unix}", "lit": "$${HOME}"}}}`), testNow)
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if !strings.Contains(string(out), `"amount":12345678901234567890`) {
+		t.Errorf("expected large numbers to be kept exactly, got %s", out)
+	}
+	if !strings.Contains(string(out), `"lit":"${HOME}"`) {
+		t.Errorf("expected $${ to produce a literal ${, got %s", out)
+	}
+}
+
+func TestExpandNoTemplates(t *testing.T) {
+	seed := []byte(`{"r": {"1": {"amount": 1.50}}}`)
+	out, err := Expand(seed, testNow)
+	if err != nil || string(out) != string(seed) {
+		t.Errorf("expected a seed without templates to pass through unchanged, got %s, %v", out, err)
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	for _, seed := range []string{
+		`{"r": {"1": {"a": "${now+soon}"}}}`,
+		`{"r": {"1": {"a": "${random}x${"}}}`,
+		`{"r": {"1": {"a": "${lower-case}"}}}`,
+		`{"r": ${`,
+	} {
+		if _, err := Expand([]byte(seed), testNow); err == nil {
+			t.Errorf("%s: expected an error", seed)
+		}
+	}
+}
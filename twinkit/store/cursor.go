@@ -0,0 +1,154 @@
+package store
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Cursor errors returned by PaginateCursor. Their messages are written for
+// API clients, so twins can pass them on (see twincore.InvalidCursor).
+var (
+	// ErrInvalidCursor reports a cursor the store did not issue, or one it
+	// no longer honors. Every cursor error wraps it.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrCursorExpired reports a cursor older than CursorOptions.TTL.
+	ErrCursorExpired = fmt.Errorf("%w: expired", ErrInvalidCursor)
+)
+
+// cursorMACSize is how many bytes of HMAC-SHA256 a cursor carries.
+const cursorMACSize = 16
+
+// CursorOptions set the lifecycle of the cursors PaginateCursor issues, so
+// twins can invalidate cursors the way their provider does and clients'
+// restart-from-the-first-page logic gets exercised.
+type CursorOptions struct {
+	// TTL expires cursors that long after they were issued, measured on
+	// Clock (or the wall clock when Clock is nil). Zero means cursors never
+	// expire.
+	TTL   time.Duration
+	Clock *Clock
+
+	// InvalidateOnWrite rejects cursors issued before any record in the
+	// store was created, updated, deleted, or expired.
+	InvalidateOnWrite bool
+
+	// Invalidate, when set, is consulted for each cursor presented; true
+	// rejects it. See twincore.Middleware.CursorInvalidator.
+	Invalidate func() bool
+}
+
+// SetCursors makes PaginateCursor issue signed, opaque cursors and reject
+// those it did not issue, or that opts invalidate. The signing key is
+// random and changes on Reset, so cursors never survive a restart or a
+// reset. Call it when the store is created; the options survive Reset.
+func (s *Store[T]) SetCursors(opts CursorOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursors = opts
+	s.cursorKey = newCursorKey()
+}
+
+// PaginateCursor is Paginate for APIs whose list cursors are opaque and can
+// go stale. Without SetCursors a cursor is the last ID seen, as with
+// Paginate; with it, Page.Cursor is a signed token. Either way, a cursor
+// whose last record has since been deleted is rejected rather than
+// silently restarting from the beginning. Errors wrap ErrInvalidCursor.
+func (s *Store[T]) PaginateCursor(cursor string, limit int) (Page[T], error) {
+	defer s.observe("PaginateCursor", time.Now())
+	s.checkRead("PaginateCursor")
+	s.expire()
+	s.mu.RLock()
+	invalidate := s.cursors.Invalidate
+	s.mu.RUnlock()
+	if cursor != "" && invalidate != nil && invalidate() {
+		return Page[T]{}, ErrInvalidCursor
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	listed := s.listedLocked()
+	start := 0
+	if cursor != "" {
+		id, err := s.openCursorLocked(cursor)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		i := indexOf(listed, id)
+		if i < 0 {
+			return Page[T]{}, fmt.Errorf("%w: the record it points after no longer exists", ErrInvalidCursor)
+		}
+		start = i + 1
+	}
+	page := s.pageLocked(listed, start, limit)
+	if page.Cursor != "" && s.cursorKey != nil {
+		page.Cursor = s.sealCursorLocked(page.Cursor)
+	}
+	return page, nil
+}
+
+// sealCursorLocked returns a signed cursor for the last ID seen, stamped
+// with the time and the store's write count. Callers must hold s.mu.
+func (s *Store[T]) sealCursorLocked(id string) string {
+	payload := fmt.Appendf(nil, "%s\x00%d\x00%d", id, s.cursorNowLocked().UnixNano(), s.writes)
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.cursorMAC(payload)...))
+}
+
+// openCursorLocked verifies a cursor and returns the last ID it saw.
+// Callers must hold s.mu.
+func (s *Store[T]) openCursorLocked(cursor string) (string, error) {
+	if s.cursorKey == nil {
+		return cursor, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(data) <= cursorMACSize {
+		return "", ErrInvalidCursor
+	}
+	payload, mac := data[:len(data)-cursorMACSize], data[len(data)-cursorMACSize:]
+	if !hmac.Equal(mac, s.cursorMAC(payload)) {
+		return "", ErrInvalidCursor
+	}
+	fields := bytes.Split(payload, []byte{0})
+	if len(fields) != 3 {
+		return "", ErrInvalidCursor
+	}
+	issued, err1 := strconv.ParseInt(string(fields[1]), 10, 64)
+	writes, err2 := strconv.ParseUint(string(fields[2]), 10, 64)
+	if err1 != nil || err2 != nil {
+		return "", ErrInvalidCursor
+	}
+	if ttl := s.cursors.TTL; ttl > 0 && !s.cursorNowLocked().Before(time.Unix(0, issued).Add(ttl)) {
+		return "", ErrCursorExpired
+	}
+	if s.cursors.InvalidateOnWrite && writes != s.writes {
+		return "", fmt.Errorf("%w: the list changed since it was issued", ErrInvalidCursor)
+	}
+	return string(fields[0]), nil
+}
+
+func (s *Store[T]) cursorMAC(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.cursorKey)
+	mac.Write(payload)
+	return mac.Sum(nil)[:cursorMACSize]
+}
+
+// cursorNowLocked returns the time cursors are issued and expired by.
+// Callers must hold s.mu.
+func (s *Store[T]) cursorNowLocked() time.Time {
+	if s.cursors.Clock != nil {
+		return s.cursors.Clock.Now()
+	}
+	return time.Now()
+}
+
+func newCursorKey() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
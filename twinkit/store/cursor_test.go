@@ -0,0 +1,129 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func cursorStore(n int, opts CursorOptions) *Store[testItem] {
+	s := New[testItem]("item")
+	for i := 0; i < n; i++ {
+		s.Set(s.NextID(), testItem{Name: fmt.Sprint(i), Value: i})
+	}
+	s.SetCursors(opts)
+	return s
+}
+
+func TestPaginateCursorWalksPages(t *testing.T) {
+	s := cursorStore(5, CursorOptions{})
+
+	var seen []int
+	cursor := ""
+	for {
+		page, err := s.PaginateCursor(cursor, 2)
+		if err != nil {
+			t.Fatalf("PaginateCursor(%q): %v", cursor, err)
+		}
+		for _, it := range page.Data {
+			seen = append(seen, it.Value)
+		}
+		if !page.HasMore {
+			break
+		}
+		if page.Cursor == "item_000002" {
+			t.Fatal("expected an opaque cursor, got the last ID")
+		}
+		cursor = page.Cursor
+	}
+	if fmt.Sprint(seen) != "[0 1 2 3 4]" {
+		t.Errorf("expected every item once, got %v", seen)
+	}
+}
+
+func TestPaginateCursorRejectsForgedCursors(t *testing.T) {
+	s := cursorStore(3, CursorOptions{})
+	page, _ := s.PaginateCursor("", 1)
+
+	other := cursorStore(3, CursorOptions{})
+	for _, cursor := range []string{"item_000001", "!!!", page.Cursor[:len(page.Cursor)-2] + "AA"} {
+		if _, err := s.PaginateCursor(cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("PaginateCursor(%q): expected ErrInvalidCursor, got %v", cursor, err)
+		}
+	}
+	if _, err := other.PaginateCursor(page.Cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected another store to reject the cursor, got %v", err)
+	}
+
+	s.Reset()
+	if _, err := s.PaginateCursor(page.Cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected Reset to invalidate cursors, got %v", err)
+	}
+}
+
+func TestPaginateCursorExpiry(t *testing.T) {
+	clock := NewClock()
+	clock.Freeze()
+	s := cursorStore(3, CursorOptions{TTL: 5 * time.Minute, Clock: clock})
+	page, _ := s.PaginateCursor("", 1)
+
+	clock.Advance(4 * time.Minute)
+	if _, err := s.PaginateCursor(page.Cursor, 1); err != nil {
+		t.Fatalf("expected the cursor to be valid within its TTL, got %v", err)
+	}
+	clock.Advance(time.Minute)
+	if _, err := s.PaginateCursor(page.Cursor, 1); !errors.Is(err, ErrCursorExpired) || !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrCursorExpired, got %v", err)
+	}
+}
+
+func TestPaginateCursorInvalidation(t *testing.T) {
+	s := cursorStore(3, CursorOptions{InvalidateOnWrite: true})
+	page, _ := s.PaginateCursor("", 1)
+	if _, err := s.PaginateCursor(page.Cursor, 1); err != nil {
+		t.Fatalf("expected an unchanged list to keep the cursor valid, got %v", err)
+	}
+	s.Set(s.NextID(), testItem{Name: "new"})
+	if _, err := s.PaginateCursor(page.Cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected a write to invalidate the cursor, got %v", err)
+	}
+
+	// Without InvalidateOnWrite, only losing the record a cursor points
+	// after invalidates it.
+	s = cursorStore(3, CursorOptions{})
+	page, _ = s.PaginateCursor("", 1)
+	s.Set(s.NextID(), testItem{Name: "new"})
+	if _, err := s.PaginateCursor(page.Cursor, 1); err != nil {
+		t.Errorf("expected the cursor to survive an unrelated write, got %v", err)
+	}
+	s.Delete("item_000001")
+	if _, err := s.PaginateCursor(page.Cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected a deleted anchor record to invalidate the cursor, got %v", err)
+	}
+
+	rejected := false
+	s = cursorStore(3, CursorOptions{Invalidate: func() bool { return rejected }})
+	page, _ = s.PaginateCursor("", 1)
+	rejected = true
+	if _, err := s.PaginateCursor(page.Cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected Invalidate to reject the cursor, got %v", err)
+	}
+	if _, err := s.PaginateCursor("", 1); err != nil {
+		t.Errorf("expected a first page to need no cursor, got %v", err)
+	}
+}
+
+func TestPaginateCursorPlainIDs(t *testing.T) {
+	s := New[testItem]("item")
+	for i := 0; i < 3; i++ {
+		s.Set(s.NextID(), testItem{Value: i})
+	}
+	page, err := s.PaginateCursor("item_000001", 1)
+	if err != nil || page.Cursor != "item_000002" || page.Data[0].Value != 1 {
+		t.Errorf("expected plain ID cursors without SetCursors, got %+v, %v", page, err)
+	}
+	if _, err := s.PaginateCursor("item_000009", 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected an unknown ID to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Operation kinds a FailureConfig can target.
+const (
+	// OpRead covers Get, List, ListIDs, Paginate, PaginateCursor, Count,
+	// Filter, and FilterWithIDs.
+	OpRead = "read"
+	// OpWrite covers Set, Update, and Delete.
+	OpWrite = "write"
+)
+
+// StorageFailure is the panic value of a store operation that Failures
+// made fail. Store operations cannot return errors, so a failure unwinds
+// the handler that called it; twincore.Middleware.StorageFailures recovers
+// it and answers with the provider's 500, leaving any writes the handler
+// made before the failure in place.
+type StorageFailure struct {
+	Store string // the store's ID prefix
+	Op    string // the operation, e.g. "Set"
+	// Committed reports that the failing write was applied before the
+	// error, as with FailureConfig.AfterWrite.
+	Committed bool
+}
+
+func (f *StorageFailure) Error() string {
+	if f.Committed {
+		return fmt.Sprintf("simulated storage failure in %s %s after the write was applied", f.Store, f.Op)
+	}
+	return fmt.Sprintf("simulated storage failure in %s %s", f.Store, f.Op)
+}
+
+// FailureConfig is a storage failure mode: the share of store operations
+// that fail, and which.
+type FailureConfig struct {
+	Rate float64  `json:"rate"`          // 0 to 1; 0 turns failures off
+	Ops  []string `json:"ops,omitempty"` // OpRead, OpWrite; empty means both
+	// AfterWrite applies a failing write before reporting the failure, so
+	// the backend holds the change the caller was told had failed.
+	AfterWrite bool `json:"after_write,omitempty"`
+}
+
+// Validate reports a rate outside 0 to 1 or an unknown operation kind.
+func (c FailureConfig) Validate() error {
+	if c.Rate < 0 || c.Rate > 1 {
+		return fmt.Errorf("rate must be between 0 and 1, got %v", c.Rate)
+	}
+	for _, op := range c.Ops {
+		if op != OpRead && op != OpWrite {
+			return fmt.Errorf("unknown operation %q (expected %s or %s)", op, OpRead, OpWrite)
+		}
+	}
+	return nil
+}
+
+// Failures makes a share of store operations fail, to exercise how an
+// application handles a failing persistent backend in ways an endpoint
+// fault cannot, such as a request that writes one record and then fails.
+// One Failures is shared by all of a twin's stores (see SetFailures).
+//
+// Operations only fail while armed, which twincore.Middleware does for the
+// duration of each API request, so admin calls and state loads are not
+// affected unless they overlap one.
+type Failures struct {
+	mu       sync.RWMutex
+	cfg      FailureConfig
+	roll     func() float64
+	armed    atomic.Int64
+	injected atomic.Uint64
+}
+
+// NewFailures creates a Failures, off, that draws from roll, a source of
+// values in [0, 1).
+func NewFailures(roll func() float64) *Failures {
+	return &Failures{roll: roll}
+}
+
+// Configure replaces the failure mode.
+func (f *Failures) Configure(cfg FailureConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cfg = cfg
+	return nil
+}
+
+// Config returns the failure mode.
+func (f *Failures) Config() FailureConfig {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.cfg
+}
+
+// Injected returns how many operations have failed since the last Reset.
+func (f *Failures) Injected() uint64 {
+	return f.injected.Load()
+}
+
+// Reset turns failures off and zeroes the count.
+func (f *Failures) Reset() {
+	f.mu.Lock()
+	f.cfg = FailureConfig{}
+	f.mu.Unlock()
+	f.injected.Store(0)
+}
+
+// Arm lets operations fail until the returned function is called. Arms
+// nest, so concurrent requests can each arm.
+func (f *Failures) Arm() (disarm func()) {
+	f.armed.Add(1)
+	return func() { f.armed.Add(-1) }
+}
+
+// fail reports whether an operation of kind should fail now.
+func (f *Failures) fail(kind string) (fail, afterWrite bool) {
+	if f.armed.Load() <= 0 {
+		return false, false
+	}
+	f.mu.RLock()
+	cfg := f.cfg
+	f.mu.RUnlock()
+	if cfg.Rate <= 0 || (len(cfg.Ops) > 0 && !slices.Contains(cfg.Ops, kind)) {
+		return false, false
+	}
+	if f.roll() >= cfg.Rate {
+		return false, false
+	}
+	f.injected.Add(1)
+	return true, cfg.AfterWrite && kind == OpWrite
+}
+
+// Failable is implemented by collections whose operations can be made to
+// fail. *Store[T] satisfies it.
+type Failable interface {
+	SetFailures(*Failures)
+}
+
+// SetFailures makes the store's operations fail as f decides. Call it when
+// the store is created; admin.NewHandler does for every collection.
+func (s *Store[T]) SetFailures(f *Failures) {
+	s.failures.Store(f)
+}
+
+// checkRead panics with a StorageFailure when a read should fail.
+func (s *Store[T]) checkRead(op string) {
+	if f := s.failures.Load(); f != nil {
+		if fail, _ := f.fail(OpRead); fail {
+			panic(&StorageFailure{Store: s.prefix, Op: op})
+		}
+	}
+}
+
+// checkWrite panics with a StorageFailure when a write should fail before
+// it is applied. When it should fail after, it returns the failure for the
+// caller to panic with once the write is done.
+func (s *Store[T]) checkWrite(op string) *StorageFailure {
+	f := s.failures.Load()
+	if f == nil {
+		return nil
+	}
+	fail, after := f.fail(OpWrite)
+	if !fail {
+		return nil
+	}
+	if !after {
+		panic(&StorageFailure{Store: s.prefix, Op: op})
+	}
+	return &StorageFailure{Store: s.prefix, Op: op, Committed: true}
+}
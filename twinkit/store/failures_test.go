@@ -0,0 +1,84 @@
+package store
+
+import "testing"
+
+// failing runs fn and returns the StorageFailure it panicked with, if any.
+func failing(fn func()) (failure *StorageFailure) {
+	defer func() {
+		if v := recover(); v != nil {
+			failure = v.(*StorageFailure)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestFailuresOnlyWhileArmed(t *testing.T) {
+	f := NewFailures(func() float64 { return 0 })
+	f.Configure(FailureConfig{Rate: 1})
+	s := New[testItem]("item")
+	s.SetFailures(f)
+
+	if failure := failing(func() { s.Set("a", testItem{}) }); failure != nil {
+		t.Fatalf("expected no failure while disarmed, got %v", failure)
+	}
+	disarm := f.Arm()
+	failure := failing(func() { s.Get("a") })
+	if failure == nil || failure.Op != "Get" || failure.Store != "item" {
+		t.Errorf("expected Get to fail while armed, got %v", failure)
+	}
+	disarm()
+	if _, ok := s.Get("a"); !ok {
+		t.Error("expected Get to succeed once disarmed")
+	}
+	if f.Injected() != 1 {
+		t.Errorf("expected 1 injected failure, got %d", f.Injected())
+	}
+}
+
+func TestFailuresByKind(t *testing.T) {
+	f := NewFailures(func() float64 { return 0.5 })
+	s := New[testItem]("item")
+	s.SetFailures(f)
+	defer f.Arm()()
+
+	f.Configure(FailureConfig{Rate: 0.4})
+	if failure := failing(func() { s.Set("a", testItem{}) }); failure != nil {
+		t.Errorf("expected a roll above the rate to pass, got %v", failure)
+	}
+
+	f.Configure(FailureConfig{Rate: 0.6, Ops: []string{OpWrite}})
+	if failure := failing(func() { s.List() }); failure != nil {
+		t.Errorf("expected reads to pass when only writes fail, got %v", failure)
+	}
+	if failure := failing(func() { s.Delete("a") }); failure == nil || failure.Committed {
+		t.Errorf("expected Delete to fail before it is applied, got %v", failure)
+	}
+	if _, ok := s.items["a"]; !ok {
+		t.Error("expected the failed Delete to leave the item")
+	}
+
+	f.Configure(FailureConfig{Rate: 0.6, Ops: []string{OpWrite}, AfterWrite: true})
+	failure := failing(func() {
+		s.Update("a", func(it testItem) (testItem, error) { it.Value = 7; return it, nil })
+	})
+	if failure == nil || !failure.Committed {
+		t.Errorf("expected Update to fail after it is applied, got %v", failure)
+	}
+	if s.items["a"].Value != 7 {
+		t.Error("expected the failed Update to be applied")
+	}
+
+	f.Reset()
+	if failure := failing(func() { s.Set("b", testItem{}) }); failure != nil || f.Injected() != 0 {
+		t.Errorf("expected Reset to turn failures off, got %v", failure)
+	}
+}
+
+func TestFailureConfigValidate(t *testing.T) {
+	for _, cfg := range []FailureConfig{{Rate: -0.1}, {Rate: 1.1}, {Rate: 0.5, Ops: []string{"scan"}}} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%+v: expected an error", cfg)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LimitPolicy decides what a store does once it reaches a limit.
+type LimitPolicy string
+
+const (
+	// LimitReject keeps every record the store is given and reports the
+	// store as full, so the twin can refuse requests that would add more
+	// with a provider-style error (see twincore.Middleware.Capacity).
+	LimitReject LimitPolicy = "reject"
+	// LimitEvict makes room for a new record by evicting the oldest ones.
+	LimitEvict LimitPolicy = "evict"
+)
+
+// Limits bound a store's size, so generated load cannot grow a long-lived
+// twin until it runs out of memory. Zero fields mean no limit.
+type Limits struct {
+	MaxRecords int
+	// MaxBytes caps the store's estimated size: the JSON encodings of its
+	// IDs and items, the measure Stats reports as ApproxBytes.
+	MaxBytes int64
+	Policy   LimitPolicy // LimitReject when empty
+}
+
+// Limiter is implemented by collections that can be bounded. *Store[T]
+// satisfies it.
+type Limiter interface {
+	SetLimits(Limits)
+	// CheckCapacity returns an error describing the limit reached when the
+	// collection has no room for another record, and nil otherwise.
+	CheckCapacity() error
+}
+
+// SetLimits bounds the store. Under LimitEvict, a record that takes the
+// store over a limit evicts the oldest records, in insertion order, until
+// the store fits again; under LimitReject, see CheckCapacity. Records
+// already over a new LimitEvict limit are evicted at once. Call it when the
+// store is created; limits survive Reset. Zero Limits turn them off.
+func (s *Store[T]) SetLimits(l Limits) {
+	if l.Policy == "" {
+		l.Policy = LimitReject
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = l
+	s.sizes = nil
+	s.bytes = 0
+	if l.MaxBytes > 0 {
+		s.sizes = make(map[string]int64, len(s.items))
+		for id, item := range s.items {
+			s.trackLocked(id, item)
+		}
+	}
+	s.evictLocked("")
+}
+
+// Limits returns the store's limits.
+func (s *Store[T]) Limits() Limits {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limits
+}
+
+// CheckCapacity returns an error when the store has reached a LimitReject
+// limit. Handlers do not consult it; the twin's middleware does before each
+// write request, so a request that creates several records can take the
+// store slightly past its limit.
+func (s *Store[T]) CheckCapacity() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.limits.Policy != LimitReject {
+		return nil
+	}
+	if max := s.limits.MaxRecords; max > 0 && len(s.items) >= max {
+		return fmt.Errorf("holds %d records (limit %d)", len(s.items), max)
+	}
+	if max := s.limits.MaxBytes; max > 0 && s.bytes >= max {
+		return fmt.Errorf("holds about %d bytes (limit %d)", s.bytes, max)
+	}
+	return nil
+}
+
+// trackLocked records the estimated size of the item stored under id when
+// MaxBytes is set. Callers must hold s.mu for writing.
+func (s *Store[T]) trackLocked(id string, item T) {
+	if s.sizes == nil {
+		return
+	}
+	size := int64(len(id))
+	if data, err := json.Marshal(item); err == nil {
+		size += int64(len(data))
+	}
+	s.bytes += size - s.sizes[id]
+	s.sizes[id] = size
+}
+
+// untrackLocked forgets the size of the item stored under id. Callers must
+// hold s.mu for writing.
+func (s *Store[T]) untrackLocked(id string) {
+	if s.sizes == nil {
+		return
+	}
+	s.bytes -= s.sizes[id]
+	delete(s.sizes, id)
+}
+
+// overLocked reports whether the store exceeds a limit. Callers must hold s.mu.
+func (s *Store[T]) overLocked() bool {
+	return (s.limits.MaxRecords > 0 && len(s.items) > s.limits.MaxRecords) ||
+		(s.limits.MaxBytes > 0 && s.bytes > s.limits.MaxBytes)
+}
+
+// evictLocked evicts the oldest records, other than keep, while the store
+// exceeds a LimitEvict limit. Callers must hold s.mu for writing.
+func (s *Store[T]) evictLocked(keep string) {
+	if s.limits.Policy != LimitEvict {
+		return
+	}
+	for s.overLocked() && len(s.order) > 0 {
+		id := s.order[0]
+		if id == keep {
+			if len(s.order) == 1 {
+				return
+			}
+			id = s.order[1]
+		}
+		s.deleteLocked(id)
+		s.evicted++
+	}
+}
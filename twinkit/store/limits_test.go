@@ -0,0 +1,112 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLimitsRejectReportsFull(t *testing.T) {
+	s := New[testItem]("item")
+	s.SetLimits(Limits{MaxRecords: 2})
+	if got := s.Limits().Policy; got != LimitReject {
+		t.Errorf("expected the policy to default to reject, got %q", got)
+	}
+
+	s.Set("a", testItem{Name: "a"})
+	if err := s.CheckCapacity(); err != nil {
+		t.Errorf("expected room below the limit, got %v", err)
+	}
+	s.Set("b", testItem{Name: "b"})
+	err := s.CheckCapacity()
+	if err == nil || !strings.Contains(err.Error(), "limit 2") {
+		t.Fatalf("expected the store to be full, got %v", err)
+	}
+	// Reject never drops records itself; the twin refuses the request.
+	s.Set("c", testItem{Name: "c"})
+	if got := s.Count(); got != 3 {
+		t.Errorf("expected all 3 records kept, got %d", got)
+	}
+	st := s.Stats()
+	if !st.Full || st.MaxRecords != 2 || st.LimitPolicy != "reject" || st.Evicted != 0 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+
+	s.Delete("a")
+	s.Delete("b")
+	if err := s.CheckCapacity(); err != nil {
+		t.Errorf("expected room after deleting, got %v", err)
+	}
+}
+
+func TestLimitsEvictOldest(t *testing.T) {
+	s := New[testItem]("item")
+	s.SetLimits(Limits{MaxRecords: 2, Policy: LimitEvict})
+	s.Set("a", testItem{Name: "a"})
+	s.Set("b", testItem{Name: "b"})
+	s.Set("c", testItem{Name: "c"})
+
+	if ids := s.ListIDs(); len(ids) != 2 || ids[0] != "b" || ids[1] != "c" {
+		t.Errorf("expected the oldest record evicted, got %v", ids)
+	}
+	if err := s.CheckCapacity(); err != nil {
+		t.Errorf("expected an evicting store never to report full, got %v", err)
+	}
+	st := s.Stats()
+	if st.Evicted != 1 || st.Full || st.LimitPolicy != "evict" {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+
+	s.Reset()
+	if st := s.Stats(); st.Evicted != 0 || st.MaxRecords != 2 {
+		t.Errorf("expected Reset to clear the eviction count but keep limits, got %+v", st)
+	}
+}
+
+func TestLimitsMaxBytes(t *testing.T) {
+	s := New[testItem]("item")
+	s.Set("a", testItem{Name: "a"})
+	s.Set("b", testItem{Name: "b"})
+	size := s.Stats().ApproxBytes / 2
+
+	// Limits set on a populated store account for what it already holds.
+	s.SetLimits(Limits{MaxBytes: 2 * size})
+	if err := s.CheckCapacity(); err == nil || !strings.Contains(err.Error(), "bytes") {
+		t.Errorf("expected the store to be full by size, got %v", err)
+	}
+	s.Delete("a")
+	if err := s.CheckCapacity(); err != nil {
+		t.Errorf("expected room after deleting, got %v", err)
+	}
+
+	s.SetLimits(Limits{MaxBytes: 2 * size, Policy: LimitEvict})
+	s.Set("c", testItem{Name: "c"})
+	s.Set("d", testItem{Name: "d"})
+	if ids := s.ListIDs(); len(ids) != 2 || ids[0] != "c" || ids[1] != "d" {
+		t.Errorf("expected b evicted to fit the size limit, got %v", ids)
+	}
+	if st := s.Stats(); st.ApproxBytes > 2*size || st.Evicted != 1 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+
+	// Updating a record in place does not evict it.
+	if _, err := s.Update("c", func(it testItem) (testItem, error) { it.Value = 1; return it, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("c"); !ok {
+		t.Error("expected the updated record to be kept")
+	}
+}
+
+func TestLimitsOff(t *testing.T) {
+	s := New[testItem]("item")
+	s.SetLimits(Limits{MaxRecords: 1, Policy: LimitEvict})
+	s.SetLimits(Limits{})
+	s.Set("a", testItem{Name: "a"})
+	s.Set("b", testItem{Name: "b"})
+	if got := s.Count(); got != 2 {
+		t.Errorf("expected no limit, got %d records", got)
+	}
+	if st := s.Stats(); st.Full || st.MaxRecords != 0 || st.LimitPolicy != "" {
+		t.Errorf("expected no limits in stats, got %+v", st)
+	}
+}
@@ -0,0 +1,94 @@
+package store
+
+import "time"
+
+// ListLag simulates a provider whose list endpoints read from a replica
+// that trails the primary: a record created less than the lag ago is
+// missing from List, ListIDs, Paginate, and PaginateCursor, while Get,
+// Filter, and writes see it at once. Updates and deletes are not delayed.
+type ListLag struct {
+	// Lag returns how long new records stay out of listings. It is
+	// consulted on every create and listing, so the lag can follow a
+	// runtime toggle; zero, or a nil Lag, lists records at once.
+	Lag func() time.Duration
+	// Now returns the time lag is measured on, typically the twin's
+	// simulated clock, so tests can advance past it; nil means the wall
+	// clock.
+	Now func() time.Time
+}
+
+// ListLagged is implemented by collections that can simulate list lag.
+// *Store[T] satisfies it.
+type ListLagged interface {
+	SetListLag(ListLag)
+}
+
+// SetListLag delays new records' appearance in listings as l says. Call it
+// when the store is created; admin.NewHandler does for every collection.
+// Zero ListLag turns it off.
+func (s *Store[T]) SetListLag(l ListLag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listLag = l
+	s.created = nil
+}
+
+// listLagLocked returns the current list lag and the time it is measured
+// from, or zero when listings are not delayed. Callers must hold s.mu.
+func (s *Store[T]) listLagLocked() (time.Duration, time.Time) {
+	if s.listLag.Lag == nil {
+		return 0, time.Time{}
+	}
+	lag := s.listLag.Lag()
+	if lag <= 0 {
+		return 0, time.Time{}
+	}
+	if s.listLag.Now != nil {
+		return lag, s.listLag.Now()
+	}
+	return lag, time.Now()
+}
+
+// noteCreatedLocked records when id was created while listings are
+// delayed, dropping records whose lag has long passed once the record map
+// has doubled since it was last pruned. Callers must hold s.mu for
+// writing.
+func (s *Store[T]) noteCreatedLocked(id string) {
+	lag, now := s.listLagLocked()
+	if lag <= 0 {
+		return
+	}
+	if s.created == nil {
+		s.created = make(map[string]time.Time)
+	}
+	if len(s.created) >= s.createdPruneAt {
+		for cid, at := range s.created {
+			if !now.Before(at.Add(lag)) {
+				delete(s.created, cid)
+			}
+		}
+		s.createdPruneAt = max(64, 2*len(s.created))
+	}
+	s.created[id] = now
+}
+
+// listedLocked returns the IDs listings show, in insertion order: all of
+// them unless a list lag hides recently created ones. Callers must hold
+// s.mu and must not modify the result.
+func (s *Store[T]) listedLocked() []string {
+	if len(s.created) == 0 {
+		return s.order
+	}
+	lag, now := s.listLagLocked()
+	if lag <= 0 {
+		return s.order
+	}
+	out := make([]string, 0, len(s.order))
+	for _, id := range s.order {
+		if at, ok := s.created[id]; ok && now.Before(at.Add(lag)) {
+			continue
+		}
+		out = append(out, id)
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListLag(t *testing.T) {
+	clock := NewClock()
+	clock.Freeze()
+	lag := 2 * time.Second
+	s := New[testItem]("item")
+	s.Set("old", testItem{Name: "old"})
+	s.SetListLag(ListLag{Lag: func() time.Duration { return lag }, Now: clock.Now})
+
+	s.Set("new", testItem{Name: "new"})
+	if _, ok := s.Get("new"); !ok {
+		t.Fatal("expected Get to see a new record at once")
+	}
+	if got := s.Filter(func(string, testItem) bool { return true }); len(got) != 2 {
+		t.Errorf("expected Filter to see both records, got %v", got)
+	}
+	if ids := s.ListIDs(); len(ids) != 1 || ids[0] != "old" {
+		t.Errorf("expected only the old record listed, got %v", ids)
+	}
+	if page := s.Paginate("", 10); len(page.Data) != 1 || page.Total != 1 {
+		t.Errorf("expected a page of the old record, got %+v", page)
+	}
+	if page, err := s.PaginateCursor("", 10); err != nil || len(page.Data) != 1 {
+		t.Errorf("expected PaginateCursor to hide the new record, got %+v, %v", page, err)
+	}
+
+	// Updates do not restart the lag.
+	clock.Advance(time.Second)
+	s.Set("new", testItem{Name: "new", Value: 1})
+	clock.Advance(time.Second)
+	if items := s.List(); len(items) != 2 || items[1].Value != 1 {
+		t.Errorf("expected both records listed once the lag passed, got %v", items)
+	}
+
+	s.Set("newer", testItem{})
+	lag = 0
+	if ids := s.ListIDs(); len(ids) != 3 {
+		t.Errorf("expected every record listed with the lag off, got %v", ids)
+	}
+}
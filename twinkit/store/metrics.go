@@ -0,0 +1,131 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// ScanWarning flags Filter and FilterWithIDs calls that visit more than
+// Records records, a sign that a twin looks records up by scanning where
+// it should keep an index (such as a second store keyed by the field it
+// filters on). Every such scan is counted in Stats.SlowScans; Warn, when
+// set, is also called for one at most once a minute, so a hot handler
+// does not flood the log.
+type ScanWarning struct {
+	// Records is how many records a scan may visit before it is slow;
+	// zero turns the warning off.
+	Records int
+	// Warn reports a slow scan by operation, with the records it visited
+	// and how long it took. It must not call back into the store.
+	Warn func(op string, scanned int, took time.Duration)
+}
+
+// ScanWarner is implemented by collections that can warn about slow
+// scans. *Store[T] satisfies it.
+type ScanWarner interface {
+	SetScanWarning(ScanWarning)
+}
+
+// OpStats reports how often a store operation ran since the last reset and
+// how long it took, in milliseconds.
+type OpStats struct {
+	Count   uint64  `json:"count"`
+	TotalMS float64 `json:"total_ms"`
+	MaxMS   float64 `json:"max_ms"`
+}
+
+// scanWarnInterval is the least time between two ScanWarning.Warn calls
+// for a store.
+const scanWarnInterval = time.Minute
+
+// metrics records a store's operations. It has its own lock, so recording
+// an operation never waits on the store's.
+type metrics struct {
+	mu        sync.Mutex
+	ops       map[string]*opTiming
+	warning   ScanWarning
+	slowScans uint64
+	warnedAt  time.Time
+}
+
+type opTiming struct {
+	count      uint64
+	total, max time.Duration
+}
+
+// SetScanWarning makes Filter and FilterWithIDs flag scans as w says. Call
+// it when the store is created; admin.NewHandler does for every collection.
+// The warning survives Reset; zero ScanWarning turns it off.
+func (s *Store[T]) SetScanWarning(w ScanWarning) {
+	s.metrics.mu.Lock()
+	defer s.metrics.mu.Unlock()
+	s.metrics.warning = w
+}
+
+// observe records an operation that started at start.
+func (s *Store[T]) observe(op string, start time.Time) {
+	s.metrics.record(op, time.Since(start), -1)
+}
+
+// observeScan records a scan over scanned records that started at start,
+// flagging it if it is slow.
+func (s *Store[T]) observeScan(op string, start time.Time, scanned int) {
+	s.metrics.record(op, time.Since(start), scanned)
+}
+
+// record adds an operation that took d, and scanned records unless
+// scanned is negative.
+func (m *metrics) record(op string, d time.Duration, scanned int) {
+	m.mu.Lock()
+	if m.ops == nil {
+		m.ops = map[string]*opTiming{}
+	}
+	t := m.ops[op]
+	if t == nil {
+		t = &opTiming{}
+		m.ops[op] = t
+	}
+	t.count++
+	t.total += d
+	t.max = max(t.max, d)
+
+	var warn func(string, int, time.Duration)
+	if limit := m.warning.Records; limit > 0 && scanned > limit {
+		m.slowScans++
+		if now := time.Now(); m.warning.Warn != nil && now.Sub(m.warnedAt) >= scanWarnInterval {
+			m.warnedAt = now
+			warn = m.warning.Warn
+		}
+	}
+	m.mu.Unlock()
+	if warn != nil {
+		warn(op, scanned, d)
+	}
+}
+
+// snapshot returns the recorded operations and the slow scan count.
+func (m *metrics) snapshot() (map[string]OpStats, uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.ops) == 0 {
+		return nil, m.slowScans
+	}
+	ops := make(map[string]OpStats, len(m.ops))
+	for op, t := range m.ops {
+		ops[op] = OpStats{Count: t.count, TotalMS: millis(t.total), MaxMS: millis(t.max)}
+	}
+	return ops, m.slowScans
+}
+
+// reset forgets the recorded operations, keeping the warning.
+func (m *metrics) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ops = nil
+	m.slowScans = 0
+	m.warnedAt = time.Time{}
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsOps(t *testing.T) {
+	s := New[testItem]("item")
+	s.Set("a", testItem{Name: "a"})
+	s.Set("b", testItem{Name: "b"})
+	s.Get("a")
+	s.Filter(func(string, testItem) bool { return true })
+
+	st := s.Stats()
+	if st.Ops["Set"].Count != 2 || st.Ops["Get"].Count != 1 || st.Ops["Filter"].Count != 1 {
+		t.Errorf("unexpected ops %+v", st.Ops)
+	}
+	if st.Ops["Set"].MaxMS > st.Ops["Set"].TotalMS {
+		t.Errorf("expected the slowest Set within the total, got %+v", st.Ops["Set"])
+	}
+	// Stats walks the store itself without counting the walk.
+	if again := s.Stats(); again.Ops["Get"].Count != 1 || again.Ops["ListIDs"].Count != 0 {
+		t.Errorf("expected Stats not to count its own reads, got %+v", again.Ops)
+	}
+
+	s.Reset()
+	if st := s.Stats(); st.Ops != nil {
+		t.Errorf("expected Reset to clear the ops, got %+v", st.Ops)
+	}
+}
+
+func TestScanWarning(t *testing.T) {
+	s := New[testItem]("item")
+	for _, id := range []string{"a", "b", "c"} {
+		s.Set(id, testItem{Name: id})
+	}
+	var warned []int
+	s.SetScanWarning(ScanWarning{Records: 2, Warn: func(op string, scanned int, took time.Duration) {
+		if op != "Filter" && op != "FilterWithIDs" {
+			t.Errorf("unexpected op %s", op)
+		}
+		warned = append(warned, scanned)
+	}})
+
+	all := func(string, testItem) bool { return true }
+	s.Filter(all)
+	s.FilterWithIDs(all)
+	if st := s.Stats(); st.SlowScans != 2 {
+		t.Errorf("expected both scans counted as slow, got %d", st.SlowScans)
+	}
+	if len(warned) != 1 || warned[0] != 3 {
+		t.Errorf("expected one warning a minute for a scan of 3 records, got %v", warned)
+	}
+
+	s.Delete("c")
+	s.Filter(all)
+	if st := s.Stats(); st.SlowScans != 2 {
+		t.Errorf("expected a scan within the limit not to count, got %d", st.SlowScans)
+	}
+
+	// The warning survives a reset, which clears the count.
+	s.Reset()
+	for _, id := range []string{"a", "b", "c"} {
+		s.Set(id, testItem{Name: id})
+	}
+	s.Filter(all)
+	if st := s.Stats(); st.SlowScans != 1 || len(warned) != 2 {
+		t.Errorf("expected the warning to survive Reset, got %d slow scans, %v", st.SlowScans, warned)
+	}
+}
@@ -0,0 +1,223 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// Replication simulates a globally distributed backend: a write made from
+// one region reaches each other region only after that region's lag, so
+// reading back from another region can miss it. Writes made through the
+// store itself, rather than a RegionView, are visible everywhere at once.
+type Replication struct {
+	// Lags is how long writes take to reach each region. Regions not
+	// listed see every write at once.
+	Lags map[string]time.Duration
+	// Now returns the time lag is measured on, typically the twin's
+	// simulated clock, so tests can advance past it; nil means the wall
+	// clock.
+	Now func() time.Time
+}
+
+// Replicated is implemented by collections that can simulate replication
+// lag. *Store[T] satisfies it.
+type Replicated interface {
+	SetReplication(Replication)
+}
+
+// version is one state of an item on its way to every region.
+type version[T any] struct {
+	item   T
+	exists bool
+	region string // the region the write was made from; "" for the state before any pending write
+	at     time.Time
+}
+
+// SetReplication turns on replication lag for writes made through
+// RegionView. Call it when the store is created; admin.NewHandler does for
+// every collection when the twin runs with regions. Zero Replication turns
+// it off.
+func (s *Store[T]) SetReplication(r Replication) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replication = r
+	s.versions = nil
+}
+
+// RegionView is a store as seen from one region. Its writes are visible in
+// its own region at once and in others after their lag (see
+// SetReplication); its reads see other regions' writes only once they
+// have arrived. A view of region "" is the store itself.
+type RegionView[T any] struct {
+	s      *Store[T]
+	region string
+}
+
+// Region returns the store as seen from region, e.g. the region a request
+// was routed to (see twincore.RequestRegion).
+func (s *Store[T]) Region(region string) *RegionView[T] {
+	return &RegionView[T]{s: s, region: region}
+}
+
+// Get retrieves the item as region sees it.
+func (v *RegionView[T]) Get(id string) (T, bool) {
+	item, ok := v.s.Get(id)
+	v.s.mu.RLock()
+	defer v.s.mu.RUnlock()
+	if vs := v.s.versions[id]; len(vs) > 0 {
+		return v.s.visibleLocked(vs, v.region, v.s.replicationNow())
+	}
+	return item, ok
+}
+
+// Set stores an item from region.
+func (v *RegionView[T]) Set(id string, item T) {
+	v.s.setFrom(id, item, v.region)
+}
+
+// Update replaces an item from region, with Update's guarantees. fn sees
+// the primary's copy, which may be newer than the one region reads.
+func (v *RegionView[T]) Update(id string, fn func(T) (T, error)) (T, error) {
+	return v.s.updateFrom(id, fn, v.region)
+}
+
+// Delete removes an item from region. Returns true if the item existed.
+func (v *RegionView[T]) Delete(id string) bool {
+	return v.s.deleteFrom(id, v.region)
+}
+
+// List returns the items region sees: those in the store in insertion
+// order, then any deleted elsewhere whose deletion has not arrived.
+func (v *RegionView[T]) List() []T {
+	_, items := v.visible()
+	return items
+}
+
+// Paginate is Store.Paginate over the items region sees.
+func (v *RegionView[T]) Paginate(cursor string, limit int) Page[T] {
+	ids, items := v.visible()
+	start := 0
+	for i, id := range ids {
+		if id == cursor {
+			start = i + 1
+			break
+		}
+	}
+	if limit <= 0 {
+		limit = len(ids)
+	}
+	end := min(start+limit, len(ids))
+	page := Page[T]{Data: items[start:end], HasMore: end < len(ids), Total: len(ids)}
+	if end > start {
+		page.Cursor = ids[end-1]
+	}
+	return page
+}
+
+// visible returns the IDs and items region sees.
+func (v *RegionView[T]) visible() ([]string, []T) {
+	ids := v.s.ListIDs()
+	s := v.s
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := s.replicationNow()
+	outIDs := make([]string, 0, len(ids))
+	out := make([]T, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+		item, ok := s.items[id]
+		if vs := s.versions[id]; len(vs) > 0 {
+			item, ok = s.visibleLocked(vs, v.region, now)
+		}
+		if ok {
+			outIDs = append(outIDs, id)
+			out = append(out, item)
+		}
+	}
+	// Deleted from the primary, but perhaps not yet from region.
+	var gone []string
+	for id := range s.versions {
+		if !seen[id] {
+			gone = append(gone, id)
+		}
+	}
+	sort.Strings(gone)
+	for _, id := range gone {
+		if item, ok := s.visibleLocked(s.versions[id], v.region, now); ok {
+			outIDs = append(outIDs, id)
+			out = append(out, item)
+		}
+	}
+	return outIDs, out
+}
+
+// visibleLocked returns the newest of vs that has reached region by now.
+// Callers must hold s.mu.
+func (s *Store[T]) visibleLocked(vs []version[T], region string, now time.Time) (T, bool) {
+	for i := len(vs) - 1; i > 0; i-- {
+		if s.arrivedLocked(vs[i], region, now) {
+			return vs[i].item, vs[i].exists
+		}
+	}
+	return vs[0].item, vs[0].exists
+}
+
+// arrivedLocked reports whether v is visible in region by now. Callers
+// must hold s.mu.
+func (s *Store[T]) arrivedLocked(v version[T], region string, now time.Time) bool {
+	return v.region == region || !now.Before(v.at.Add(s.replication.Lags[region]))
+}
+
+// beforeWriteLocked prepares id's versions for a write from region: a
+// regional write keeps the current state for the regions it has yet to
+// reach, and a primary write reaches every region at once. Callers must
+// hold s.mu for writing.
+func (s *Store[T]) beforeWriteLocked(id, region string) {
+	if len(s.replication.Lags) == 0 {
+		return
+	}
+	if region == "" {
+		delete(s.versions, id)
+		return
+	}
+	if len(s.versions[id]) == 0 {
+		item, ok := s.items[id]
+		if s.versions == nil {
+			s.versions = make(map[string][]version[T])
+		}
+		s.versions[id] = []version[T]{{item: item, exists: ok}}
+	}
+}
+
+// afterWriteLocked records a regional write to id, and forgets the
+// versions every region has moved past. Callers must hold s.mu for
+// writing.
+func (s *Store[T]) afterWriteLocked(id, region string) {
+	if len(s.replication.Lags) == 0 || region == "" {
+		return
+	}
+	now := s.replicationNow()
+	item, ok := s.items[id]
+	vs := append(s.versions[id], version[T]{item: item, exists: ok, region: region, at: now})
+	var maxLag time.Duration
+	for _, lag := range s.replication.Lags {
+		maxLag = max(maxLag, lag)
+	}
+	for len(vs) > 1 && !now.Before(vs[1].at.Add(maxLag)) {
+		vs = vs[1:]
+	}
+	if len(vs) == 1 {
+		delete(s.versions, id)
+		return
+	}
+	s.versions[id] = vs
+}
+
+// replicationNow returns the time replication lag is measured on.
+func (s *Store[T]) replicationNow() time.Time {
+	if s.replication.Now != nil {
+		return s.replication.Now()
+	}
+	return time.Now()
+}
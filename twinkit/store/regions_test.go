@@ -0,0 +1,96 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func replicatedStore(clock *Clock) *Store[testItem] {
+	s := New[testItem]("item")
+	s.SetReplication(Replication{
+		Lags: map[string]time.Duration{"us": 0, "eu": 2 * time.Second},
+		Now:  clock.Now,
+	})
+	return s
+}
+
+func TestRegionViewReadAfterWrite(t *testing.T) {
+	clock := NewClock()
+	clock.Freeze()
+	s := replicatedStore(clock)
+	us, eu := s.Region("us"), s.Region("eu")
+
+	us.Set("a", testItem{Name: "a", Value: 1})
+	if _, ok := us.Get("a"); !ok {
+		t.Fatal("expected a write to be visible in its own region")
+	}
+	if _, ok := eu.Get("a"); ok {
+		t.Error("expected the write not to have reached eu yet")
+	}
+	if _, ok := s.Get("a"); !ok {
+		t.Error("expected the primary to see every write")
+	}
+	if items := eu.List(); len(items) != 0 {
+		t.Errorf("expected eu to list nothing yet, got %v", items)
+	}
+
+	clock.Advance(2 * time.Second)
+	if it, ok := eu.Get("a"); !ok || it.Value != 1 {
+		t.Errorf("expected the write to reach eu after its lag, got %v, %v", it, ok)
+	}
+
+	// eu writes reach us, which has no lag, at once, and an update
+	// shows eu its own value while us sees it too.
+	eu.Update("a", func(it testItem) (testItem, error) { it.Value = 2; return it, nil })
+	if it, _ := us.Get("a"); it.Value != 2 {
+		t.Errorf("expected us to see eu's update at once, got %v", it)
+	}
+
+	us.Delete("a")
+	if _, ok := eu.Get("a"); !ok {
+		t.Error("expected eu to keep the record until the delete arrives")
+	}
+	if items := eu.List(); len(items) != 1 {
+		t.Errorf("expected eu to still list the deleted record, got %v", items)
+	}
+	clock.Advance(2 * time.Second)
+	if _, ok := eu.Get("a"); ok {
+		t.Error("expected the delete to reach eu after its lag")
+	}
+}
+
+func TestRegionViewPrimaryWrites(t *testing.T) {
+	clock := NewClock()
+	clock.Freeze()
+	s := replicatedStore(clock)
+	us, eu := s.Region("us"), s.Region("eu")
+
+	us.Set("a", testItem{Value: 1})
+	s.Set("a", testItem{Value: 5})
+	if it, ok := eu.Get("a"); !ok || it.Value != 5 {
+		t.Errorf("expected a primary write to be visible everywhere, got %v, %v", it, ok)
+	}
+
+	for i := 0; i < 3; i++ {
+		us.Set(s.NextID(), testItem{Value: i})
+	}
+	page := eu.Paginate("", 10)
+	if page.Total != 1 || len(page.Data) != 1 {
+		t.Errorf("expected eu to page over only what reached it, got %+v", page)
+	}
+	page = us.Paginate("", 2)
+	if len(page.Data) != 2 || !page.HasMore || page.Total != 4 {
+		t.Errorf("unexpected us page: %+v", page)
+	}
+}
+
+func TestRegionViewWithoutReplication(t *testing.T) {
+	s := New[testItem]("item")
+	s.Region("us").Set("a", testItem{Value: 1})
+	if _, ok := s.Region("eu").Get("a"); !ok {
+		t.Error("expected every region to see writes at once without replication")
+	}
+	if n := len(s.versions); n != 0 {
+		t.Errorf("expected no versions kept without replication, got %d", n)
+	}
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersionKey is the snapshot field holding the schema version.
+const SchemaVersionKey = "schema_version"
+
+// Migration upgrades a snapshot by one schema version, editing its
+// top-level fields (usually one per resource) in place. A migration that
+// renames a field of every customer, say, decodes snap["customers"],
+// rewrites each record, and stores the result back.
+type Migration func(snap map[string]json.RawMessage) error
+
+// SnapshotSchema versions a twin's state snapshot format, so a twin whose
+// internal model has changed can still load snapshots and seed files
+// written by older versions:
+//
+//	var stateSchema = store.SnapshotSchema{
+//		Version: 2,
+//		Migrations: map[int]store.Migration{
+//			1: renameCustomerTier, // version 1 -> 2
+//		},
+//	}
+//
+// Snapshot stamps its output with Version, and LoadState passes incoming
+// data through Migrate before decoding it. Snapshots without a
+// schema_version, written before versioning, are version 1.
+type SnapshotSchema struct {
+	Version    int               // current version; 0 is treated as 1
+	Migrations map[int]Migration // Migrations[v] upgrades version v to v+1
+}
+
+// Current returns the version Snapshot should stamp.
+func (s SnapshotSchema) Current() int {
+	return max(s.Version, 1)
+}
+
+// Migrate returns data upgraded to the current version, running each
+// migration from the snapshot's version onwards in order. Data already at
+// the current version is returned unchanged. It fails, naming both
+// versions, when the snapshot is newer than the twin or a step has no
+// migration.
+func (s SnapshotSchema) Migrate(data []byte) ([]byte, error) {
+	var snap map[string]json.RawMessage
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("state snapshot must be a JSON object: %w", err)
+	}
+
+	version := 1
+	if raw, ok := snap[SchemaVersionKey]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil || version < 1 {
+			return nil, fmt.Errorf("%s must be a positive integer, got %s", SchemaVersionKey, raw)
+		}
+	}
+	current := s.Current()
+	switch {
+	case version == current:
+		return data, nil
+	case version > current:
+		return nil, fmt.Errorf("snapshot %s %d is newer than this twin supports (%d); upgrade the twin", SchemaVersionKey, version, current)
+	}
+
+	for v := version; v < current; v++ {
+		migrate, ok := s.Migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("cannot load snapshot %s %d: no migration from version %d to %d", SchemaVersionKey, version, v, v+1)
+		}
+		if err := migrate(snap); err != nil {
+			return nil, fmt.Errorf("migrating snapshot from %s %d to %d: %w", SchemaVersionKey, v, v+1, err)
+		}
+	}
+	snap[SchemaVersionKey] = json.RawMessage(fmt.Sprint(current))
+	return json.Marshal(snap)
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testSchema renames customers' "level" to "tier" (v1 -> v2) and wraps
+// each tier in an object (v2 -> v3).
+var testSchema = SnapshotSchema{
+	Version: 3,
+	Migrations: map[int]Migration{
+		1: func(snap map[string]json.RawMessage) error {
+			return editRecords(snap, "customers", func(c map[string]any) {
+				c["tier"] = c["level"]
+				delete(c, "level")
+			})
+		},
+		2: func(snap map[string]json.RawMessage) error {
+			return editRecords(snap, "customers", func(c map[string]any) {
+				c["tier"] = map[string]any{"id": c["tier"]}
+			})
+		},
+	},
+}
+
+func editRecords(snap map[string]json.RawMessage, resource string, fn func(map[string]any)) error {
+	var records map[string]map[string]any
+	if err := json.Unmarshal(snap[resource], &records); err != nil {
+		return fmt.Errorf("%s: %w", resource, err)
+	}
+	for _, r := range records {
+		fn(r)
+	}
+	data, err := json.Marshal(records)
+	snap[resource] = data
+	return err
+}
+
+func TestSnapshotSchemaMigrate(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"unversioned is v1", `{"customers": {"c1": {"level": "gold"}}}`, `{"customers":{"c1":{"tier":{"id":"gold"}}},"schema_version":3}`},
+		{"from v2", `{"schema_version": 2, "customers": {"c1": {"tier": "gold"}}}`, `{"customers":{"c1":{"tier":{"id":"gold"}}},"schema_version":3}`},
+		{"current is untouched", `{"schema_version": 3, "customers": {}}`, `{"schema_version": 3, "customers": {}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testSchema.Migrate([]byte(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Migrate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotSchemaMigrateErrors(t *testing.T) {
+	gap := SnapshotSchema{Version: 3, Migrations: map[int]Migration{2: func(map[string]json.RawMessage) error { return nil }}}
+	tests := []struct {
+		name   string
+		schema SnapshotSchema
+		in     string
+		want   string
+	}{
+		{"newer", testSchema, `{"schema_version": 4}`, "schema_version 4 is newer than this twin supports (3)"},
+		{"missing step", gap, `{}`, "no migration from version 1 to 2"},
+		{"bad version", testSchema, `{"schema_version": "two"}`, "schema_version must be a positive integer"},
+		{"not an object", testSchema, `[]`, "must be a JSON object"},
+		{"failing step", testSchema, `{"customers": []}`, "migrating snapshot from schema_version 1 to 2: customers:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.schema.Migrate([]byte(tt.in))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSnapshotSchemaUnversionedDefault(t *testing.T) {
+	var s SnapshotSchema
+	if s.Current() != 1 {
+		t.Errorf("expected a zero schema to be version 1, got %d", s.Current())
+	}
+	data := `{"customers": {}}`
+	got, err := s.Migrate([]byte(data))
+	if err != nil || string(got) != data {
+		t.Errorf("expected an unversioned snapshot to load unchanged, got %s, %v", got, err)
+	}
+}
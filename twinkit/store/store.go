@@ -1,12 +1,18 @@
 // Package store provides a generic, thread-safe, in-memory key-value store
 // for use by WonderTwin twins. It supports CRUD operations, listing with cursor-based
-// pagination, and deterministic ID generation.
+// pagination, deterministic ID generation, optional expiry (see SetTTL),
+// optional size limits (see SetLimits), optional cursor expiry (see
+// SetCursors), optional list lag (see SetListLag), and atomic writes across
+// stores (see Tx).
 package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -20,6 +26,46 @@ type Store[T any] struct {
 	order   []string // insertion order for deterministic listing
 	prefix  string
 	counter atomic.Uint64
+	seq     uint64 // creation order; see Tx.Commit
+
+	// Expiry, when SetTTL is used: stored records when each item was first
+	// stored, on the clock's simulated time.
+	ttl    time.Duration
+	clock  *Clock
+	stored map[string]time.Time
+
+	// Limits, when SetLimits is used: sizes records each item's estimated
+	// size while MaxBytes is set, and evicted counts LimitEvict evictions
+	// since the last Reset.
+	limits  Limits
+	sizes   map[string]int64
+	bytes   int64
+	evicted uint64
+
+	// Cursors, when SetCursors is used: cursorKey signs the cursors
+	// PaginateCursor issues. writes counts changes to the store, so a
+	// cursor can tell whether the data moved under it.
+	cursors   CursorOptions
+	cursorKey []byte
+	writes    uint64
+
+	// failures, when SetFailures is used, makes operations fail.
+	failures atomic.Pointer[Failures]
+
+	// Replication, when SetReplication is used: versions holds, per ID,
+	// the writes made from a region that have yet to reach every other.
+	replication Replication
+	versions    map[string][]version[T]
+
+	// List lag, when SetListLag is used: created records when recent
+	// items were created, so listings can leave them out.
+	listLag        ListLag
+	created        map[string]time.Time
+	createdPruneAt int
+
+	// metrics counts and times operations for Stats, flagging slow scans
+	// when SetScanWarning is used.
+	metrics metrics
 }
 
 // New creates a new Store with the given ID prefix (e.g., "acct", "msg", "evt").
@@ -28,6 +74,7 @@ func New[T any](prefix string) *Store[T] {
 		items:  make(map[string]T),
 		order:  make([]string, 0),
 		prefix: prefix,
+		seq:    storeSeq.Add(1),
 	}
 }
 
@@ -41,56 +88,178 @@ func (s *Store[T]) NextID() string {
 // Set stores an item with the given ID. If the ID already exists, it is overwritten
 // but its position in the insertion order is preserved.
 func (s *Store[T]) Set(id string, item T) {
+	s.setFrom(id, item, "")
+}
+
+// setFrom is Set, made from region ("" for the primary).
+func (s *Store[T]) setFrom(id string, item T, region string) {
+	defer s.observe("Set", time.Now())
+	failure := s.checkWrite("Set")
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.setLocked(id, item, region)
+	if failure != nil {
+		panic(failure)
+	}
+}
+
+// setLocked stores item under id, written from region. Callers must hold
+// s.mu for writing.
+func (s *Store[T]) setLocked(id string, item T, region string) {
+	s.beforeWriteLocked(id, region)
+	now := s.nowLocked()
+	if s.expiredLocked(id, now) {
+		// An expired ID is stored afresh, as if it had been purged
+		s.deleteLocked(id)
+	}
 	if _, exists := s.items[id]; !exists {
 		s.order = append(s.order, id)
+		if s.ttl > 0 {
+			s.stored[id] = now
+		}
+		s.noteCreatedLocked(id)
 	}
 	s.items[id] = item
+	s.writes++
+	s.trackLocked(id, item)
+	s.evictLocked(id)
+	s.afterWriteLocked(id, region)
+}
+
+// getLocked is Get for callers that hold s.mu.
+func (s *Store[T]) getLocked(id string) (T, bool) {
+	item, ok := s.items[id]
+	if ok && s.expiredLocked(id, s.nowLocked()) {
+		var zero T
+		return zero, false
+	}
+	return item, ok
 }
 
 // Get retrieves an item by ID. Returns the item and true if found, zero value and false otherwise.
 func (s *Store[T]) Get(id string) (T, bool) {
+	defer s.observe("Get", time.Now())
+	return s.get(id)
+}
+
+// get is Get without recording the operation, for walks over the whole
+// store that should not skew its metrics.
+func (s *Store[T]) get(id string) (T, bool) {
+	s.checkRead("Get")
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	item, ok := s.items[id]
-	return item, ok
+	return s.getLocked(id)
+}
+
+// ErrNotFound is returned by Update when no item has the given ID.
+var ErrNotFound = errors.New("store: item not found")
+
+// Update atomically replaces an item with the result of fn, which runs under
+// the store's write lock so concurrent read-modify-write cycles cannot
+// interleave. If fn returns an error the item is left unchanged and the error
+// is returned. fn must not call back into the same store.
+func (s *Store[T]) Update(id string, fn func(T) (T, error)) (T, error) {
+	return s.updateFrom(id, fn, "")
+}
+
+// updateFrom is Update, made from region ("" for the primary).
+func (s *Store[T]) updateFrom(id string, fn func(T) (T, error), region string) (T, error) {
+	defer s.observe("Update", time.Now())
+	failure := s.checkWrite("Update")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.getLocked(id)
+	if !ok {
+		var zero T
+		return zero, ErrNotFound
+	}
+	updated, err := fn(item)
+	if err != nil {
+		return item, err
+	}
+	s.setLocked(id, updated, region)
+	if failure != nil {
+		panic(failure)
+	}
+	return updated, nil
 }
 
 // Delete removes an item by ID. Returns true if the item existed.
 func (s *Store[T]) Delete(id string) bool {
+	return s.deleteFrom(id, "")
+}
+
+// deleteFrom is Delete, made from region ("" for the primary).
+func (s *Store[T]) deleteFrom(id, region string) bool {
+	defer s.observe("Delete", time.Now())
+	failure := s.checkWrite("Delete")
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	existed := s.removeLocked(id, region)
+	if failure != nil {
+		panic(failure)
+	}
+	return existed
+}
+
+// removeLocked deletes id, written from region, and reports whether an
+// unexpired item was stored under it. Callers must hold s.mu for writing.
+func (s *Store[T]) removeLocked(id, region string) bool {
 	if _, exists := s.items[id]; !exists {
 		return false
 	}
+	expired := s.expiredLocked(id, s.nowLocked())
+	s.beforeWriteLocked(id, region)
+	s.deleteLocked(id)
+	s.afterWriteLocked(id, region)
+	return !expired
+}
+
+func (s *Store[T]) deleteLocked(id string) {
+	s.writes++
+	s.untrackLocked(id)
 	delete(s.items, id)
+	delete(s.stored, id)
+	delete(s.created, id)
 	for i, oid := range s.order {
 		if oid == id {
 			s.order = append(s.order[:i], s.order[i+1:]...)
 			break
 		}
 	}
-	return true
 }
 
-// List returns all items in insertion order.
+// List returns all items in insertion order, less any a list lag hides
+// (see SetListLag).
 func (s *Store[T]) List() []T {
+	defer s.observe("List", time.Now())
+	s.checkRead("List")
+	s.expire()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	result := make([]T, 0, len(s.order))
-	for _, id := range s.order {
+	listed := s.listedLocked()
+	result := make([]T, 0, len(listed))
+	for _, id := range listed {
 		result = append(result, s.items[id])
 	}
 	return result
 }
 
-// ListIDs returns all IDs in insertion order.
+// ListIDs returns all IDs in insertion order, less any a list lag hides.
 func (s *Store[T]) ListIDs() []string {
+	defer s.observe("ListIDs", time.Now())
+	return s.listIDs()
+}
+
+// listIDs is ListIDs without recording the operation.
+func (s *Store[T]) listIDs() []string {
+	s.checkRead("ListIDs")
+	s.expire()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	out := make([]string, len(s.order))
-	copy(out, s.order)
+	listed := s.listedLocked()
+	out := make([]string, len(listed))
+	copy(out, listed)
 	return out
 }
 
@@ -106,48 +275,63 @@ type Page[T any] struct {
 // The cursor is the last ID seen. An empty cursor starts from the beginning.
 // Limit controls the page size (0 means return all).
 func (s *Store[T]) Paginate(cursor string, limit int) Page[T] {
+	defer s.observe("Paginate", time.Now())
+	s.checkRead("Paginate")
+	s.expire()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	listed := s.listedLocked()
+	return s.pageLocked(listed, indexOf(listed, cursor)+1, limit)
+}
 
-	startIdx := 0
-	if cursor != "" {
-		for i, id := range s.order {
-			if id == cursor {
-				startIdx = i + 1
-				break
-			}
+// indexOf returns id's position in order, or -1.
+func indexOf(order []string, id string) int {
+	if id == "" {
+		return -1
+	}
+	for i, oid := range order {
+		if oid == id {
+			return i
 		}
 	}
+	return -1
+}
 
+// pageLocked returns up to limit items starting at startIdx in order, a
+// list of IDs in insertion order. Callers must hold s.mu.
+func (s *Store[T]) pageLocked(order []string, startIdx, limit int) Page[T] {
 	if limit <= 0 {
-		limit = len(s.order)
+		limit = len(order)
 	}
 
 	endIdx := startIdx + limit
 	hasMore := false
-	if endIdx > len(s.order) {
-		endIdx = len(s.order)
-	} else if endIdx < len(s.order) {
+	if endIdx > len(order) {
+		endIdx = len(order)
+	} else if endIdx < len(order) {
 		hasMore = true
 	}
 
 	data := make([]T, 0, endIdx-startIdx)
 	var lastCursor string
 	for i := startIdx; i < endIdx; i++ {
-		data = append(data, s.items[s.order[i]])
-		lastCursor = s.order[i]
+		data = append(data, s.items[order[i]])
+		lastCursor = order[i]
 	}
 
 	return Page[T]{
 		Data:    data,
 		HasMore: hasMore,
 		Cursor:  lastCursor,
-		Total:   len(s.order),
+		Total:   len(order),
 	}
 }
 
 // Count returns the number of items in the store.
 func (s *Store[T]) Count() int {
+	defer s.observe("Count", time.Now())
+	s.checkRead("Count")
+	s.expire()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return len(s.items)
@@ -155,8 +339,12 @@ func (s *Store[T]) Count() int {
 
 // Filter returns items that match the given predicate, in insertion order.
 func (s *Store[T]) Filter(predicate func(id string, item T) bool) []T {
+	start := time.Now()
+	s.checkRead("Filter")
+	s.expire()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	defer s.observeScan("Filter", start, len(s.order))
 	var result []T
 	for _, id := range s.order {
 		if predicate(id, s.items[id]) {
@@ -168,8 +356,12 @@ func (s *Store[T]) Filter(predicate func(id string, item T) bool) []T {
 
 // FilterWithIDs returns items and their IDs that match the given predicate.
 func (s *Store[T]) FilterWithIDs(predicate func(id string, item T) bool) ([]string, []T) {
+	start := time.Now()
+	s.checkRead("FilterWithIDs")
+	s.expire()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	defer s.observeScan("FilterWithIDs", start, len(s.order))
 	var ids []string
 	var items []T
 	for _, id := range s.order {
@@ -188,10 +380,94 @@ func (s *Store[T]) Reset() {
 	s.items = make(map[string]T)
 	s.order = make([]string, 0)
 	s.counter.Store(0)
+	s.writes++
+	s.versions = nil
+	s.created = nil
+	if s.cursorKey != nil {
+		// IDs restart, so a cursor from before the reset would point
+		// somewhere else.
+		s.cursorKey = newCursorKey()
+	}
+	if s.stored != nil {
+		s.stored = make(map[string]time.Time)
+	}
+	if s.sizes != nil {
+		s.sizes = make(map[string]int64)
+	}
+	s.bytes = 0
+	s.evicted = 0
+	s.metrics.reset()
+}
+
+// ResetNamed runs the reset function registered for each named resource.
+// Every name is validated first, so an unknown name leaves all state untouched.
+func ResetNamed(names []string, resets map[string]func()) error {
+	for _, name := range names {
+		if _, ok := resets[name]; !ok {
+			known := make([]string, 0, len(resets))
+			for k := range resets {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("unknown resource %q (known: %s)", name, strings.Join(known, ", "))
+		}
+	}
+	for _, name := range names {
+		resets[name]()
+	}
+	return nil
+}
+
+// Range calls fn for each item in insertion order, stopping at the first
+// error. Only the ID list is copied up front and each item is read under its
+// own short lock, so ranging a large store neither duplicates it in memory
+// nor blocks writers for the whole walk. Items deleted mid-walk are skipped.
+func (s *Store[T]) Range(fn func(id string, item T) error) error {
+	for _, id := range s.listIDs() {
+		item, ok := s.get(id)
+		if !ok {
+			continue
+		}
+		if err := fn(id, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collection is a type-erased, JSON-level view of a Store, used to stream
+// state one record at a time (e.g. NDJSON export/import).
+type Collection interface {
+	// RangeJSON calls fn with each item's ID and JSON encoding.
+	RangeJSON(fn func(id string, data json.RawMessage) error) error
+	// SetJSON decodes data into an item and stores it under id.
+	SetJSON(id string, data json.RawMessage) error
+}
+
+// RangeJSON implements Collection.
+func (s *Store[T]) RangeJSON(fn func(id string, data json.RawMessage) error) error {
+	return s.Range(func(id string, item T) error {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", id, err)
+		}
+		return fn(id, data)
+	})
+}
+
+// SetJSON implements Collection.
+func (s *Store[T]) SetJSON(id string, data json.RawMessage) error {
+	var item T
+	if err := json.Unmarshal(data, &item); err != nil {
+		return fmt.Errorf("decoding %s: %w", id, err)
+	}
+	s.Set(id, item)
+	return nil
 }
 
 // Snapshot returns all items as a JSON-serializable map.
 func (s *Store[T]) Snapshot() map[string]T {
+	s.expire()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	snapshot := make(map[string]T, len(s.items))
@@ -208,11 +484,27 @@ func (s *Store[T]) LoadSnapshot(snapshot map[string]T) {
 	defer s.mu.Unlock()
 	s.items = make(map[string]T, len(snapshot))
 	s.order = make([]string, 0, len(snapshot))
+	if s.stored != nil {
+		s.stored = make(map[string]time.Time, len(snapshot))
+	}
+	if s.sizes != nil {
+		s.sizes = make(map[string]int64, len(snapshot))
+	}
+	s.bytes = 0
+	s.writes++
+	s.versions = nil
+	s.created = nil
+	now := s.nowLocked()
 	for k, v := range snapshot {
 		s.items[k] = v
 		s.order = append(s.order, k)
+		if s.ttl > 0 {
+			s.stored[k] = now
+		}
+		s.trackLocked(k, v)
 	}
 	sort.Strings(s.order)
+	s.evictLocked("")
 }
 
 // MarshalJSON serializes the store to JSON (the items map).
@@ -230,10 +522,21 @@ func (s *Store[T]) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// Clock provides a simulated clock for time-dependent twin behavior.
+// Clock provides a simulated clock for time-dependent twin behavior. It runs
+// at real speed from an offset that Advance and Set move, and can be frozen
+// so Now returns the same instant until it is advanced, set, or unfrozen.
+//
+// Records that change on their own as time passes (points expiring,
+// trials ending) are materialized by derived-state functions registered
+// with Derive rather than by each handler checking the clock.
 type Clock struct {
 	mu     sync.RWMutex
 	offset time.Duration
+	frozen bool
+	at     time.Time // the frozen instant, while frozen
+
+	derivedMu sync.Mutex // serializes Materialize
+	derived   []func(now time.Time)
 }
 
 // NewClock creates a new simulated clock with no offset.
@@ -245,26 +548,115 @@ func NewClock() *Clock {
 func (c *Clock) Now() time.Time {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	return c.now()
+}
+
+func (c *Clock) now() time.Time {
+	if c.frozen {
+		return c.at
+	}
 	return time.Now().Add(c.offset)
 }
 
-// Advance moves the simulated clock forward by the given duration.
+// Advance moves the simulated clock forward by the given duration and
+// materializes derived state at the new time.
 func (c *Clock) Advance(d time.Duration) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.offset += d
+	c.at = c.at.Add(d)
+	c.mu.Unlock()
+	c.Materialize()
 }
 
-// Reset resets the clock offset to zero.
+// Set moves the simulated clock to an absolute time and materializes
+// derived state there. A frozen clock stays frozen at t.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	c.offset = time.Until(t)
+	c.at = t
+	c.mu.Unlock()
+	c.Materialize()
+}
+
+// Freeze stops the simulated clock at its current time.
+func (c *Clock) Freeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.frozen {
+		c.at = c.now()
+		c.frozen = true
+	}
+}
+
+// Unfreeze restarts a frozen clock from the instant it was frozen at.
+func (c *Clock) Unfreeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen {
+		c.offset = time.Until(c.at)
+		c.frozen = false
+	}
+}
+
+// Frozen reports whether the clock is frozen.
+func (c *Clock) Frozen() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.frozen
+}
+
+// Reset returns the clock to real time, unfrozen.
 func (c *Clock) Reset() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.offset = 0
+	c.frozen = false
 }
 
-// Offset returns the current clock offset.
+// Offset returns how far the simulated time is from real time. While the
+// clock is frozen the offset shrinks as real time passes.
 func (c *Clock) Offset() time.Duration {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	if c.frozen {
+		return time.Until(c.at)
+	}
 	return c.offset
 }
+
+// Derive registers a derived-state function that brings the twin's records
+// up to date with the simulated time now. It runs before every request
+// served through Middleware and after every Advance or Set, so it must be
+// idempotent. Register functions once, when the store is created; they
+// survive Reset.
+func (c *Clock) Derive(fn func(now time.Time)) {
+	c.derivedMu.Lock()
+	defer c.derivedMu.Unlock()
+	c.derived = append(c.derived, fn)
+}
+
+// Materialize runs every derived-state function at the current simulated
+// time. Calls are serialized, so two concurrent requests never materialize
+// the same record twice.
+func (c *Clock) Materialize() {
+	c.derivedMu.Lock()
+	defer c.derivedMu.Unlock()
+	if len(c.derived) == 0 {
+		return
+	}
+	now := c.Now()
+	for _, fn := range c.derived {
+		fn(now)
+	}
+}
+
+// Middleware materializes derived state before each request, so handlers
+// and /admin/state always read records as of the simulated time:
+//
+//	twin.Router.Use(memStore.Clock.Middleware)
+func (c *Clock) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Materialize()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -2,6 +2,9 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -99,6 +102,53 @@ func TestDeleteNonExistent(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	s := New[testItem]("item")
+	s.Set("a", testItem{Name: "a", Value: 1})
+
+	got, err := s.Update("a", func(it testItem) (testItem, error) {
+		it.Value += 10
+		return it, nil
+	})
+	if err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	if got.Value != 11 {
+		t.Errorf("expected returned value 11, got %d", got.Value)
+	}
+	if stored, _ := s.Get("a"); stored.Value != 11 {
+		t.Errorf("expected stored value 11, got %d", stored.Value)
+	}
+}
+
+func TestUpdateError(t *testing.T) {
+	s := New[testItem]("item")
+	s.Set("a", testItem{Name: "a", Value: 1})
+	boom := errors.New("boom")
+
+	_, err := s.Update("a", func(it testItem) (testItem, error) {
+		it.Value = 99
+		return it, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if stored, _ := s.Get("a"); stored.Value != 1 {
+		t.Errorf("expected item unchanged on error, got %d", stored.Value)
+	}
+}
+
+func TestUpdateNotFound(t *testing.T) {
+	s := New[testItem]("item")
+	_, err := s.Update("missing", func(it testItem) (testItem, error) {
+		t.Error("fn should not run for a missing item")
+		return it, nil
+	})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Listing
 // ---------------------------------------------------------------------------
@@ -308,6 +358,72 @@ func TestLoadSnapshotReplacesExisting(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Range / Collection
+// ---------------------------------------------------------------------------
+
+func TestRange(t *testing.T) {
+	s := New[testItem]("item")
+	s.Set("b", testItem{Name: "b"})
+	s.Set("a", testItem{Name: "a"})
+
+	var seen []string
+	err := s.Range(func(id string, item testItem) error {
+		seen = append(seen, id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Range error: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != "b" || seen[1] != "a" {
+		t.Errorf("expected insertion order [b a], got %v", seen)
+	}
+}
+
+func TestRangeStopsOnError(t *testing.T) {
+	s := New[testItem]("item")
+	s.Set("a", testItem{Name: "a"})
+	s.Set("b", testItem{Name: "b"})
+	stop := errors.New("stop")
+
+	calls := 0
+	err := s.Range(func(id string, item testItem) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected Range to stop after first error, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestCollectionRoundTrip(t *testing.T) {
+	src := New[testItem]("item")
+	src.Set("a", testItem{Name: "a", Value: 1})
+	src.Set("b", testItem{Name: "b", Value: 2})
+
+	var dst Collection = New[testItem]("item")
+	err := src.RangeJSON(func(id string, data json.RawMessage) error {
+		return dst.SetJSON(id, data)
+	})
+	if err != nil {
+		t.Fatalf("round trip error: %v", err)
+	}
+	got, ok := dst.(*Store[testItem]).Get("b")
+	if !ok || got.Value != 2 {
+		t.Errorf("expected b to round-trip, got %+v", got)
+	}
+}
+
+func TestSetJSONInvalid(t *testing.T) {
+	s := New[testItem]("item")
+	if err := s.SetJSON("a", json.RawMessage(`{"value": "not a number"}`)); err == nil {
+		t.Error("expected error for mistyped field")
+	}
+	if s.Count() != 0 {
+		t.Errorf("expected nothing stored on error, got %d", s.Count())
+	}
+}
+
 // ---------------------------------------------------------------------------
 // JSON marshaling
 // ---------------------------------------------------------------------------
@@ -375,6 +491,37 @@ func TestReset(t *testing.T) {
 	}
 }
 
+func TestResetNamed(t *testing.T) {
+	a := New[testItem]("a")
+	b := New[testItem]("b")
+	a.Set("a1", testItem{Name: "a1"})
+	b.Set("b1", testItem{Name: "b1"})
+	resets := map[string]func(){"alpha": a.Reset, "beta": b.Reset}
+
+	if err := ResetNamed([]string{"alpha"}, resets); err != nil {
+		t.Fatalf("ResetNamed error: %v", err)
+	}
+	if a.Count() != 0 {
+		t.Errorf("expected alpha to be cleared, got %d items", a.Count())
+	}
+	if b.Count() != 1 {
+		t.Errorf("expected beta to be untouched, got %d items", b.Count())
+	}
+}
+
+func TestResetNamedUnknown(t *testing.T) {
+	a := New[testItem]("a")
+	a.Set("a1", testItem{Name: "a1"})
+	resets := map[string]func(){"alpha": a.Reset}
+
+	if err := ResetNamed([]string{"alpha", "gamma"}, resets); err == nil {
+		t.Fatal("expected error for unknown resource")
+	}
+	if a.Count() != 1 {
+		t.Errorf("expected no resets when a name is unknown, got %d items", a.Count())
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Count
 // ---------------------------------------------------------------------------
@@ -421,6 +568,42 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 }
 
+func TestConcurrentUpdate(t *testing.T) {
+	s := New[testItem]("item")
+	s.Set("a", testItem{Name: "balance", Value: 50})
+	errInsufficient := errors.New("insufficient")
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Update("a", func(it testItem) (testItem, error) {
+				if it.Value < 1 {
+					return it, errInsufficient
+				}
+				it.Value--
+				return it, nil
+			})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 50 {
+		t.Errorf("expected exactly 50 successful debits, got %d", succeeded)
+	}
+	if got, _ := s.Get("a"); got.Value != 0 {
+		t.Errorf("expected balance 0, got %d", got.Value)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Clock
 // ---------------------------------------------------------------------------
@@ -483,3 +666,92 @@ func TestClockReset(t *testing.T) {
 		t.Errorf("expected zero offset after reset, got %v", c.Offset())
 	}
 }
+
+func TestClockSet(t *testing.T) {
+	c := NewClock()
+	want := time.Date(2026, 1, 31, 23, 59, 0, 0, time.UTC)
+	c.Set(want)
+
+	if diff := c.Now().Sub(want); diff < 0 || diff > time.Second {
+		t.Errorf("expected clock at %v, got %v", want, c.Now())
+	}
+}
+
+func TestClockFreeze(t *testing.T) {
+	c := NewClock()
+	c.Freeze()
+	first := c.Now()
+	time.Sleep(5 * time.Millisecond)
+	if !c.Now().Equal(first) {
+		t.Fatalf("expected frozen clock to stay at %v, got %v", first, c.Now())
+	}
+
+	c.Advance(time.Hour)
+	if got := c.Now().Sub(first); got != time.Hour {
+		t.Errorf("expected Advance to move a frozen clock by 1h, moved %v", got)
+	}
+
+	leap := time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)
+	c.Set(leap)
+	if !c.Frozen() || !c.Now().Equal(leap) {
+		t.Errorf("expected Set to keep the clock frozen at %v, got %v (frozen=%v)", leap, c.Now(), c.Frozen())
+	}
+
+	c.Unfreeze()
+	if c.Frozen() {
+		t.Fatal("expected clock to be running after Unfreeze")
+	}
+	if diff := c.Now().Sub(leap); diff < 0 || diff > time.Second {
+		t.Errorf("expected clock to resume from %v, got %v", leap, c.Now())
+	}
+}
+
+func TestClockResetUnfreezes(t *testing.T) {
+	c := NewClock()
+	c.Set(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.Freeze()
+	c.Reset()
+
+	if c.Frozen() || c.Offset() != 0 {
+		t.Errorf("expected reset to return to real time, got frozen=%v offset=%v", c.Frozen(), c.Offset())
+	}
+}
+
+func TestClockDeriveOnAdvanceAndSet(t *testing.T) {
+	c := NewClock()
+	var calls []time.Time
+	c.Derive(func(now time.Time) { calls = append(calls, now) })
+
+	c.Advance(time.Hour)
+	target := time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC)
+	c.Set(target)
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 materializations, got %d", len(calls))
+	}
+	if diff := calls[1].Sub(target); diff < 0 || diff > time.Second {
+		t.Errorf("expected materialization at %v, got %v", target, calls[1])
+	}
+
+	c.Reset()
+	c.Freeze()
+	if len(calls) != 2 {
+		t.Errorf("expected Reset and Freeze not to materialize, got %d calls", len(calls))
+	}
+}
+
+func TestClockMiddlewareMaterializesBeforeHandler(t *testing.T) {
+	c := NewClock()
+	expired := false
+	c.Derive(func(now time.Time) { expired = true })
+
+	var sawExpired bool
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawExpired = expired
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/things", nil))
+
+	if !sawExpired {
+		t.Error("expected derived state to be materialized before the handler ran")
+	}
+}
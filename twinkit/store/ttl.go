@@ -0,0 +1,150 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SetTTL makes items expire ttl after they were first stored, measured on
+// the simulated clock, so long-lived twins in shared environments don't grow
+// without bound. Expired items are never returned by reads and are purged
+// whenever a scanning read runs or the clock materializes derived state
+// (every Advance or Set, and each request served through Clock.Middleware).
+// Items already in the store without a timestamp start their TTL now. A zero ttl turns expiry
+// off. Call it when the store is created; the TTL survives Reset.
+func (s *Store[T]) SetTTL(ttl time.Duration, clock *Clock) {
+	now := clock.Now()
+	s.mu.Lock()
+	register := s.clock != clock
+	s.ttl = ttl
+	s.clock = clock
+	if s.stored == nil {
+		s.stored = make(map[string]time.Time, len(s.items))
+	}
+	for id := range s.items {
+		if _, ok := s.stored[id]; !ok {
+			s.stored[id] = now
+		}
+	}
+	s.mu.Unlock()
+	if register {
+		clock.Derive(func(now time.Time) { s.Purge(now) })
+	}
+}
+
+// TTL returns the store's TTL, or 0 when items never expire.
+func (s *Store[T]) TTL() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ttl
+}
+
+// Purge removes every item that has expired as of now and returns how many
+// were removed. It is a no-op without a TTL.
+func (s *Store[T]) Purge(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ttl <= 0 {
+		return 0
+	}
+	kept := s.order[:0]
+	removed := 0
+	for _, id := range s.order {
+		if s.expiredLocked(id, now) {
+			s.untrackLocked(id)
+			delete(s.items, id)
+			delete(s.stored, id)
+			delete(s.created, id)
+			s.writes++
+			removed++
+			continue
+		}
+		kept = append(kept, id)
+	}
+	s.order = kept
+	return removed
+}
+
+// expire purges expired items before a scanning read.
+func (s *Store[T]) expire() {
+	s.mu.RLock()
+	ttl, clock := s.ttl, s.clock
+	s.mu.RUnlock()
+	if ttl > 0 {
+		s.Purge(clock.Now())
+	}
+}
+
+// nowLocked returns the simulated time when a TTL is set, and the zero time
+// otherwise. Callers must hold s.mu.
+func (s *Store[T]) nowLocked() time.Time {
+	if s.ttl <= 0 {
+		return time.Time{}
+	}
+	return s.clock.Now()
+}
+
+// expiredLocked reports whether the item stored under id has outlived the
+// TTL. Callers must hold s.mu.
+func (s *Store[T]) expiredLocked(id string, now time.Time) bool {
+	if s.ttl <= 0 {
+		return false
+	}
+	at, ok := s.stored[id]
+	return ok && !now.Before(at.Add(s.ttl))
+}
+
+// Stats summarizes a store's size for GET /admin/state/stats.
+type Stats struct {
+	Count int `json:"count"`
+	// ApproxBytes estimates the store's memory use from the JSON encoding
+	// of its IDs and items.
+	ApproxBytes int64  `json:"approx_bytes"`
+	TTL         string `json:"ttl,omitempty"`
+
+	// Limits, when set (see SetLimits). Full reports that a LimitReject
+	// store refuses new records; Evicted counts LimitEvict evictions since
+	// the last reset.
+	MaxRecords  int    `json:"max_records,omitempty"`
+	MaxBytes    int64  `json:"max_bytes,omitempty"`
+	LimitPolicy string `json:"limit_policy,omitempty"`
+	Full        bool   `json:"full,omitempty"`
+	Evicted     uint64 `json:"evicted,omitempty"`
+
+	// Ops reports, by name (Get, Set, Filter, ...), how often each
+	// operation ran since the last reset and how long it took. SlowScans
+	// counts the Filter and FilterWithIDs calls that visited more records
+	// than the store's ScanWarning allows (see SetScanWarning).
+	Ops       map[string]OpStats `json:"ops,omitempty"`
+	SlowScans uint64             `json:"slow_scans,omitempty"`
+}
+
+// StatsReporter is implemented by collections that can report Stats.
+// *Store[T] satisfies it.
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// Stats purges expired items and reports the store's count, estimated
+// size, TTL, limits, and operation metrics.
+func (s *Store[T]) Stats() Stats {
+	s.expire()
+	var st Stats
+	s.RangeJSON(func(id string, data json.RawMessage) error {
+		st.Count++
+		st.ApproxBytes += int64(len(id) + len(data))
+		return nil
+	})
+	if ttl := s.TTL(); ttl > 0 {
+		st.TTL = ttl.String()
+	}
+	s.mu.RLock()
+	if l := s.limits; l.MaxRecords > 0 || l.MaxBytes > 0 {
+		st.MaxRecords, st.MaxBytes, st.LimitPolicy = l.MaxRecords, l.MaxBytes, string(l.Policy)
+		st.Evicted = s.evicted
+	}
+	s.mu.RUnlock()
+	st.Full = s.CheckCapacity() != nil
+	st.Ops, st.SlowScans = s.metrics.snapshot()
+	return st
+}
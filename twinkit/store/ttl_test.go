@@ -0,0 +1,110 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLHidesExpiredItems(t *testing.T) {
+	clock := NewClock()
+	s := New[testItem]("item")
+	s.SetTTL(time.Hour, clock)
+	s.Set("a", testItem{Name: "a"})
+	s.Set("b", testItem{Name: "b"})
+
+	if _, ok := s.Get("a"); !ok {
+		t.Fatal("expected a to be readable before its TTL")
+	}
+
+	// Age a past its TTL without running derived state, so only expiry on
+	// read can hide it.
+	s.mu.Lock()
+	s.stored["a"] = clock.Now().Add(-time.Hour)
+	s.mu.Unlock()
+
+	if _, ok := s.Get("a"); ok {
+		t.Error("expected Get to hide an expired item")
+	}
+	if _, err := s.Update("a", func(it testItem) (testItem, error) { return it, nil }); err != ErrNotFound {
+		t.Errorf("expected Update on an expired item to return ErrNotFound, got %v", err)
+	}
+	if got := s.Count(); got != 1 {
+		t.Errorf("expected Count to purge the expired item, got %d", got)
+	}
+	if ids := s.ListIDs(); len(ids) != 1 || ids[0] != "b" {
+		t.Errorf("expected only b to remain, got %v", ids)
+	}
+}
+
+func TestTTLPurgesOnAdvance(t *testing.T) {
+	clock := NewClock()
+	s := New[testItem]("item")
+	s.Set("seeded", testItem{Name: "seeded"})
+	s.SetTTL(time.Hour, clock)
+
+	clock.Advance(30 * time.Minute)
+	s.Set("fresh", testItem{Name: "fresh"})
+
+	clock.Advance(45 * time.Minute)
+	s.mu.RLock()
+	n := len(s.items)
+	s.mu.RUnlock()
+	if n != 1 {
+		t.Fatalf("expected Advance to purge the seeded item, %d items left", n)
+	}
+	if _, ok := s.Get("fresh"); !ok {
+		t.Error("expected fresh to survive")
+	}
+
+	clock.Advance(time.Hour)
+	if got := s.Count(); got != 0 {
+		t.Errorf("expected every item purged, got %d", got)
+	}
+}
+
+func TestTTLSetRevivesExpiredID(t *testing.T) {
+	clock := NewClock()
+	s := New[testItem]("item")
+	s.SetTTL(time.Minute, clock)
+	s.Set("a", testItem{Value: 1})
+	s.Set("b", testItem{Value: 2})
+
+	s.mu.Lock()
+	s.stored["a"] = clock.Now().Add(-2 * time.Minute)
+	s.mu.Unlock()
+
+	s.Set("a", testItem{Value: 3})
+	if got, ok := s.Get("a"); !ok || got.Value != 3 {
+		t.Errorf("expected a to be stored afresh, got %+v %v", got, ok)
+	}
+	if ids := s.ListIDs(); len(ids) != 2 || ids[0] != "b" || ids[1] != "a" {
+		t.Errorf("expected a revived ID to move to the end, got %v", ids)
+	}
+}
+
+func TestTTLZeroDisablesExpiry(t *testing.T) {
+	clock := NewClock()
+	s := New[testItem]("item")
+	s.SetTTL(0, clock)
+	s.Set("a", testItem{})
+	clock.Advance(24 * time.Hour)
+	if _, ok := s.Get("a"); !ok {
+		t.Error("expected no expiry with a zero TTL")
+	}
+}
+
+func TestStats(t *testing.T) {
+	s := New[testItem]("item")
+	s.Set("a", testItem{Name: "x", Value: 1})
+
+	st := s.Stats()
+	want := int64(len("a") + len(`{"name":"x","value":1}`))
+	if st.Count != 1 || st.ApproxBytes != want || st.TTL != "" {
+		t.Errorf("unexpected stats %+v (want %d bytes)", st, want)
+	}
+
+	s.SetTTL(time.Hour, NewClock())
+	if st := s.Stats(); st.TTL != "1h0m0s" {
+		t.Errorf("expected TTL in stats, got %q", st.TTL)
+	}
+}
@@ -0,0 +1,228 @@
+package store
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+)
+
+// storeSeq numbers stores as they are created, giving Tx.Commit an order
+// to lock them in.
+var storeSeq atomic.Uint64
+
+// Tx groups writes to several stores so they are applied together or not
+// at all. Writes are queued with SetTx, UpdateTx, and DeleteTx and take
+// effect at Commit, which holds every store involved locked while it runs
+// them, so concurrent readers see either none of the writes or all of
+// them:
+//
+//	tx := store.NewTx()
+//	s.Customers.UpdateTx(tx, customerID, debitPoints(cost))
+//	s.Claims.SetTx(tx, claimID, claim)
+//	s.Transactions.SetTx(tx, txnID, txn)
+//	if err := tx.Commit(); err != nil { ... } // nothing was written
+//
+// A Tx is not safe for concurrent use, and is spent once committed.
+type Tx struct {
+	stages []txStage
+	byKey  map[any]txStage // by *Store[T]
+	ops    []func() error
+
+	committing bool
+}
+
+// txStage is the type-erased view of a store's part in a transaction.
+type txStage interface {
+	seq() uint64
+	lock()
+	unlock()
+	checkWrite() *StorageFailure
+	applyLocked()
+}
+
+// stagedWrites holds a store's writes until Commit applies them. A nil
+// item marks a deletion; order records the IDs in the order they were
+// first written.
+type stagedWrites[T any] struct {
+	s     *Store[T]
+	items map[string]*T
+	order []string
+}
+
+// NewTx returns an empty transaction.
+func NewTx() *Tx {
+	return &Tx{byKey: make(map[any]txStage)}
+}
+
+// stageFor returns the writes tx has staged for s, adding s to tx.
+func stageFor[T any](tx *Tx, s *Store[T]) *stagedWrites[T] {
+	if st, ok := tx.byKey[s]; ok {
+		return st.(*stagedWrites[T])
+	}
+	if tx.committing {
+		panic("store: write to a store not in the committing transaction; add it with JoinTx first")
+	}
+	st := &stagedWrites[T]{s: s, items: make(map[string]*T)}
+	tx.byKey[s] = st
+	tx.stages = append(tx.stages, st)
+	return st
+}
+
+// JoinTx adds s to tx without queuing a write, so that Commit locks it and
+// an UpdateTx fn can read it with GetTx or FilterTx or queue writes to it.
+func (s *Store[T]) JoinTx(tx *Tx) {
+	stageFor(tx, s)
+}
+
+// SetTx queues a Set of item under id for when tx commits.
+func (s *Store[T]) SetTx(tx *Tx, id string, item T) {
+	st := stageFor(tx, s)
+	tx.ops = append(tx.ops, func() error {
+		st.put(id, &item)
+		return nil
+	})
+}
+
+// UpdateTx queues an Update of id for when tx commits. fn sees the item as
+// the transaction's earlier writes left it; if the item does not exist or
+// fn returns an error, Commit returns that error and applies nothing. Like
+// Update's fn, it must not call into any store in the transaction except
+// through GetTx, FilterTx, and the *Tx methods; writes it queues run after
+// the ones already queued.
+func (s *Store[T]) UpdateTx(tx *Tx, id string, fn func(T) (T, error)) {
+	st := stageFor(tx, s)
+	tx.ops = append(tx.ops, func() error {
+		item, ok := st.get(id)
+		if !ok {
+			return ErrNotFound
+		}
+		updated, err := fn(item)
+		if err != nil {
+			return err
+		}
+		st.put(id, &updated)
+		return nil
+	})
+}
+
+// DeleteTx queues a Delete of id for when tx commits.
+func (s *Store[T]) DeleteTx(tx *Tx, id string) {
+	st := stageFor(tx, s)
+	tx.ops = append(tx.ops, func() error {
+		st.put(id, nil)
+		return nil
+	})
+}
+
+// GetTx is Get as seen from inside tx. Called from an UpdateTx fn while tx
+// commits, it reads a store in the transaction under the lock Commit holds,
+// including the writes queued before that fn; otherwise it is Get.
+func (s *Store[T]) GetTx(tx *Tx, id string) (T, bool) {
+	st, ok := tx.byKey[s]
+	if !ok || !tx.committing {
+		return s.Get(id)
+	}
+	return st.(*stagedWrites[T]).get(id)
+}
+
+// FilterTx is Filter as seen from inside tx, on the same terms as GetTx.
+func (s *Store[T]) FilterTx(tx *Tx, predicate func(id string, item T) bool) []T {
+	st, ok := tx.byKey[s]
+	if !ok || !tx.committing {
+		return s.Filter(predicate)
+	}
+	staged := st.(*stagedWrites[T])
+	var result []T
+	visit := func(id string) {
+		if item, ok := staged.get(id); ok && predicate(id, item) {
+			result = append(result, item)
+		}
+	}
+	for _, id := range s.order {
+		visit(id)
+	}
+	for _, id := range staged.order {
+		if _, exists := s.items[id]; !exists {
+			visit(id)
+		}
+	}
+	return result
+}
+
+// Commit applies the transaction's writes. It locks every store involved,
+// runs the queued writes in order, and applies them only if none failed,
+// returning the first error otherwise. An injected storage failure (see
+// SetFailures) on any of the stores fails the whole transaction: before
+// anything is applied, or once all of it is for failures after the write.
+func (tx *Tx) Commit() error {
+	var failure *StorageFailure
+	for _, st := range tx.stages {
+		if f := st.checkWrite(); f != nil && failure == nil {
+			failure = f
+		}
+	}
+
+	// Lock in creation order, so concurrent transactions over the same
+	// stores cannot deadlock.
+	locked := slices.Clone(tx.stages)
+	slices.SortFunc(locked, func(a, b txStage) int { return cmp.Compare(a.seq(), b.seq()) })
+	for _, st := range locked {
+		st.lock()
+		defer st.unlock()
+	}
+
+	tx.committing = true
+	defer func() { tx.committing = false }()
+	for i := 0; i < len(tx.ops); i++ { // ops may queue more ops
+		if err := tx.ops[i](); err != nil {
+			return err
+		}
+	}
+	for _, st := range tx.stages {
+		st.applyLocked()
+	}
+	if failure != nil {
+		panic(failure)
+	}
+	return nil
+}
+
+// get returns id as the transaction has left it so far. Callers hold the
+// store's lock.
+func (st *stagedWrites[T]) get(id string) (T, bool) {
+	if item, ok := st.items[id]; ok {
+		if item == nil {
+			var zero T
+			return zero, false
+		}
+		return *item, true
+	}
+	return st.s.getLocked(id)
+}
+
+// put stages a write of item to id, or a deletion if item is nil.
+func (st *stagedWrites[T]) put(id string, item *T) {
+	if _, ok := st.items[id]; !ok {
+		st.order = append(st.order, id)
+	}
+	st.items[id] = item
+}
+
+func (st *stagedWrites[T]) seq() uint64 { return st.s.seq }
+func (st *stagedWrites[T]) lock()       { st.s.mu.Lock() }
+func (st *stagedWrites[T]) unlock()     { st.s.mu.Unlock() }
+
+func (st *stagedWrites[T]) checkWrite() *StorageFailure {
+	return st.s.checkWrite("Commit")
+}
+
+// applyLocked writes the staged items to the store. Callers hold its lock.
+func (st *stagedWrites[T]) applyLocked() {
+	for _, id := range st.order {
+		if item := st.items[id]; item != nil {
+			st.s.setLocked(id, *item, "")
+		} else {
+			st.s.removeLocked(id, "")
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package store
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestTxCommit(t *testing.T) {
+	balances := New[testItem]("bal")
+	claims := New[testItem]("clm")
+	balances.Set("bal_1", testItem{Name: "alice", Value: 100})
+	claims.Set("clm_old", testItem{Name: "stale"})
+
+	tx := NewTx()
+	claims.SetTx(tx, "clm_1", testItem{Name: "reward", Value: 30})
+	balances.UpdateTx(tx, "bal_1", func(b testItem) (testItem, error) {
+		b.Value -= 30
+		return b, nil
+	})
+	claims.UpdateTx(tx, "clm_1", func(c testItem) (testItem, error) {
+		c.Name += " (claimed)" // sees the write queued before it
+		return c, nil
+	})
+	claims.DeleteTx(tx, "clm_old")
+	if _, ok := claims.Get("clm_1"); ok {
+		t.Fatal("expected nothing written before Commit")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+
+	if b, _ := balances.Get("bal_1"); b.Value != 70 {
+		t.Errorf("expected balance 70, got %d", b.Value)
+	}
+	if c, _ := claims.Get("clm_1"); c.Name != "reward (claimed)" {
+		t.Errorf("expected the updated claim, got %+v", c)
+	}
+	if _, ok := claims.Get("clm_old"); ok {
+		t.Error("expected clm_old to be deleted")
+	}
+}
+
+func TestTxAllOrNothing(t *testing.T) {
+	balances := New[testItem]("bal")
+	claims := New[testItem]("clm")
+	balances.Set("bal_1", testItem{Value: 10})
+	errInsufficient := errors.New("insufficient points")
+
+	tx := NewTx()
+	claims.SetTx(tx, "clm_1", testItem{Value: 30})
+	balances.UpdateTx(tx, "bal_1", func(b testItem) (testItem, error) {
+		if b.Value < 30 {
+			return b, errInsufficient
+		}
+		b.Value -= 30
+		return b, nil
+	})
+	if err := tx.Commit(); !errors.Is(err, errInsufficient) {
+		t.Fatalf("expected errInsufficient, got %v", err)
+	}
+	if claims.Count() != 0 {
+		t.Error("expected the claim not to be written")
+	}
+
+	tx = NewTx()
+	claims.SetTx(tx, "clm_1", testItem{Value: 30})
+	balances.DeleteTx(tx, "bal_1")
+	balances.UpdateTx(tx, "bal_1", func(b testItem) (testItem, error) { return b, nil })
+	if err := tx.Commit(); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an item deleted earlier in the transaction, got %v", err)
+	}
+	if _, ok := balances.Get("bal_1"); !ok || claims.Count() != 0 {
+		t.Error("expected nothing applied")
+	}
+}
+
+func TestTxNotObservedHalfApplied(t *testing.T) {
+	a := New[testItem]("a")
+	b := New[testItem]("b")
+	a.Set("x", testItem{Value: 100})
+	b.Set("x", testItem{Value: 0})
+
+	move := func(from, to *Store[testItem]) {
+		tx := NewTx()
+		from.UpdateTx(tx, "x", func(i testItem) (testItem, error) { i.Value--; return i, nil })
+		to.UpdateTx(tx, "x", func(i testItem) (testItem, error) { i.Value++; return i, nil })
+		if err := tx.Commit(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				move(a, b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				move(b, a)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		// Hold one store's read lock while reading the other, as a reader
+		// that wants a consistent view of both would.
+		a.mu.RLock()
+		av := a.items["x"].Value
+		bv, _ := b.Get("x")
+		a.mu.RUnlock()
+		if av+bv.Value != 100 {
+			t.Fatalf("observed a half-applied transaction: %d + %d", av, bv.Value)
+		}
+	}
+}
+
+func TestTxStorageFailure(t *testing.T) {
+	f := NewFailures(func() float64 { return 0 })
+	if err := f.Configure(FailureConfig{Rate: 1, Ops: []string{OpWrite}}); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Arm()()
+	a := New[testItem]("a")
+	b := New[testItem]("b")
+	b.SetFailures(f)
+
+	tx := NewTx()
+	a.SetTx(tx, "x", testItem{Value: 1})
+	b.SetTx(tx, "x", testItem{Value: 1})
+	func() {
+		defer func() {
+			if _, ok := recover().(*StorageFailure); !ok {
+				t.Error("expected a StorageFailure panic")
+			}
+		}()
+		tx.Commit()
+	}()
+	if a.Count() != 0 {
+		t.Error("expected a failed transaction to write nothing")
+	}
+}
+
+func TestTxReadAndQueueFromUpdate(t *testing.T) {
+	balances := New[testItem]("bal")
+	claims := New[testItem]("clm")
+	balances.Set("bal_1", testItem{Name: "alice", Value: 100})
+	claims.Set("clm_1", testItem{Name: "alice", Value: 10})
+
+	claim := func() error {
+		tx := NewTx()
+		claims.JoinTx(tx)
+		balances.UpdateTx(tx, "bal_1", func(b testItem) (testItem, error) {
+			// Reads see the store as the transaction has left it, without
+			// taking the lock Commit holds.
+			if len(claims.FilterTx(tx, func(_ string, c testItem) bool { return c.Name == b.Name })) >= 2 {
+				return b, errors.New("already claimed twice")
+			}
+			if _, ok := claims.GetTx(tx, "clm_1"); !ok {
+				t.Error("expected GetTx to find clm_1")
+			}
+			b.Value -= 30
+			claims.SetTx(tx, claims.NextID(), testItem{Name: b.Name, Value: 30})
+			return b, nil
+		})
+		return tx.Commit()
+	}
+
+	if err := claim(); err != nil {
+		t.Fatalf("first claim: %v", err)
+	}
+	if err := claim(); err == nil {
+		t.Fatal("expected the second claim to see the first")
+	}
+	if b, _ := balances.Get("bal_1"); b.Value != 70 || claims.Count() != 2 {
+		t.Errorf("expected one debit and one new claim, got balance %d and %d claims", b.Value, claims.Count())
+	}
+
+	tx := NewTx()
+	balances.UpdateTx(tx, "bal_1", func(b testItem) (testItem, error) {
+		claims.SetTx(tx, "clm_2", testItem{})
+		return b, nil
+	})
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a write to a store outside the transaction to panic")
+		}
+	}()
+	tx.Commit()
+}
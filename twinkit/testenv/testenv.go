@@ -0,0 +1,381 @@
+// Package testenv runs the twins from a wondertwin.json manifest for the
+// duration of a Go test, so integration tests can embed a full twin fleet
+// without invoking the wt CLI:
+//
+//	func TestCheckout(t *testing.T) {
+//		env := testenv.Start(t, "../wondertwin.json")
+//		stripe := env.Twin("stripe")
+//		app := newApp(stripe.URL)
+//
+//		t.Run("declined card", func(t *testing.T) {
+//			env.Reset(t)
+//			stripe.Admin.InjectFault(ctx, "/v1/charges", adminclient.Fault{StatusCode: 402, Rate: 1})
+//			...
+//		})
+//	}
+//
+// Each twin listens on a free port chosen at start, so parallel test
+// packages never collide with each other or with twins started by wt up.
+// Binaries are resolved the way wt resolves them (the binary path, or
+// binary_dir/twin-<name> for versioned twins), so run wt install first.
+// Only JSON manifests are supported: twinkit cannot use wt's manifest
+// loader, so a wondertwin.yaml (or any manifest that is not JSON) fails the
+// test with an error saying so.
+package testenv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
+	"github.com/wondertwin-ai/wondertwin/twinkit/testutil"
+)
+
+// manifest is the subset of wondertwin.json that testenv uses. Ports are
+// ignored: every twin gets a free port.
+type manifest struct {
+	Twins map[string]struct {
+		Binary   string            `json:"binary"`
+		Version  string            `json:"version"`
+		Seed     string            `json:"seed"`
+		Latency  string            `json:"latency"`
+		FailRate float64           `json:"fail_rate"`
+		RandSeed uint64            `json:"rand_seed"`
+		Env      map[string]string `json:"env"`
+	} `json:"twins"`
+	Settings struct {
+		BinaryDir string `json:"binary_dir"`
+	} `json:"settings"`
+}
+
+// Twin is a running twin.
+type Twin struct {
+	Name  string
+	Port  int
+	URL   string // base URL, e.g. "http://127.0.0.1:53121"
+	Admin *adminclient.Client
+
+	seed    string
+	logPath string
+	cmd     *exec.Cmd
+	exited  chan struct{}
+}
+
+// Env is a set of running twins.
+type Env struct {
+	twins map[string]*Twin
+}
+
+type config struct {
+	only    []string
+	timeout time.Duration
+}
+
+// Option configures Start.
+type Option func(*config)
+
+// WithTwins starts only the named twins instead of every twin in the manifest.
+func WithTwins(names ...string) Option {
+	return func(c *config) { c.only = names }
+}
+
+// WithStartTimeout sets how long Start waits for each twin to become
+// healthy (default 10 seconds).
+func WithStartTimeout(d time.Duration) Option {
+	return func(c *config) { c.timeout = d }
+}
+
+// Start launches the twins in the manifest at manifestPath, waits until
+// every one is healthy, and stops them when the test and its subtests
+// finish. Twins start with their manifest seed, latency, fail_rate,
+// rand_seed, and env. Start fails the test if any twin cannot be started.
+func Start(t testing.TB, manifestPath string, opts ...Option) *Env {
+	t.Helper()
+	cfg := config{timeout: 10 * time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	m, dir, err := loadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("testenv: %v", err)
+	}
+
+	names := cfg.only
+	if len(names) == 0 {
+		for name := range m.Twins {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	env := &Env{twins: make(map[string]*Twin)}
+	t.Cleanup(func() { env.stop(t) })
+
+	logDir := t.TempDir()
+	for _, name := range names {
+		spec, ok := m.Twins[name]
+		if !ok {
+			t.Fatalf("testenv: twin %q is not in %s", name, manifestPath)
+		}
+
+		binary := spec.Binary
+		if binary == "" {
+			binDir := m.Settings.BinaryDir
+			if binDir == "" {
+				binDir = "~/.wondertwin/bin"
+			}
+			binary = filepath.Join(expandPath(binDir), "twin-"+name)
+		}
+		binary = resolvePath(dir, binary)
+		if _, err := os.Stat(binary); err != nil {
+			t.Fatalf("testenv: twin %q: binary not found at %s (run wt install)", name, binary)
+		}
+
+		port, err := freePort()
+		if err != nil {
+			t.Fatalf("testenv: twin %q: %v", name, err)
+		}
+
+		args := []string{"--port", strconv.Itoa(port)}
+		tw := &Twin{
+			Name:    name,
+			Port:    port,
+			URL:     fmt.Sprintf("http://127.0.0.1:%d", port),
+			logPath: filepath.Join(logDir, name+".log"),
+			exited:  make(chan struct{}),
+		}
+		tw.Admin = adminclient.New(tw.URL)
+		if spec.Seed != "" {
+			tw.seed = resolvePath(dir, spec.Seed)
+			args = append(args, "--seed-file", tw.seed)
+		}
+		if spec.Latency != "" {
+			args = append(args, "--latency", spec.Latency)
+		}
+		if spec.FailRate > 0 {
+			args = append(args, "--fail-rate", strconv.FormatFloat(spec.FailRate, 'f', -1, 64))
+		}
+		if spec.RandSeed != 0 {
+			args = append(args, "--rand-seed", strconv.FormatUint(spec.RandSeed, 10))
+		}
+
+		if err := tw.start(binary, args, spec.Env); err != nil {
+			t.Fatalf("testenv: twin %q: %v", name, err)
+		}
+		env.twins[name] = tw
+	}
+
+	for _, name := range names {
+		tw := env.twins[name]
+		if err := tw.waitHealthy(cfg.timeout); err != nil {
+			t.Fatalf("testenv: twin %q: %v\n%s", name, err, tw.logTail())
+		}
+	}
+	return env
+}
+
+// Twin returns the running twin with the given name. It panics if the twin
+// was not started, which is always a mistake in the test itself.
+func (e *Env) Twin(name string) *Twin {
+	tw, ok := e.twins[name]
+	if !ok {
+		panic(fmt.Sprintf("testenv: twin %q is not running in this environment", name))
+	}
+	return tw
+}
+
+// Twins returns every running twin, sorted by name.
+func (e *Env) Twins() []*Twin {
+	twins := make([]*Twin, 0, len(e.twins))
+	for _, tw := range e.twins {
+		twins = append(twins, tw)
+	}
+	sort.Slice(twins, func(i, j int) bool { return twins[i].Name < twins[j].Name })
+	return twins
+}
+
+// Reset returns every twin to its starting state. Call it at the top of
+// each test or subtest that shares an Env.
+func (e *Env) Reset(t testing.TB) {
+	t.Helper()
+	for _, tw := range e.Twins() {
+		tw.Reset(t)
+	}
+}
+
+// Reset clears the twin's state, request log, faults, and simulated clock,
+// then reloads its manifest seed file, if any.
+func (tw *Twin) Reset(t testing.TB) {
+	t.Helper()
+	ctx := context.Background()
+	if err := tw.Admin.Reset(ctx); err != nil {
+		t.Fatalf("testenv: resetting %s: %v", tw.Name, err)
+	}
+	if tw.seed == "" {
+		return
+	}
+	data, err := os.ReadFile(tw.seed)
+	if err != nil {
+		t.Fatalf("testenv: reading %s seed: %v", tw.Name, err)
+	}
+	if err := tw.Admin.LoadState(ctx, data); err != nil {
+		t.Fatalf("testenv: seeding %s: %v", tw.Name, err)
+	}
+}
+
+// Client returns a testutil client for asserting on the twin's API.
+func (tw *Twin) Client(t *testing.T) *testutil.TwinClient {
+	return testutil.NewTwinClientURL(t, tw.URL)
+}
+
+func (tw *Twin) start(binary string, args []string, env map[string]string) error {
+	logFile, err := os.Create(tw.logPath)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(binary, args...)
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return err
+	}
+	tw.cmd = cmd
+
+	go func() {
+		cmd.Wait()
+		logFile.Close()
+		close(tw.exited)
+	}()
+	return nil
+}
+
+func (tw *Twin) waitHealthy(timeout time.Duration) error {
+	client := adminclient.New(tw.URL, adminclient.WithRetries(0, 0))
+	deadline := time.Now().Add(timeout)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := client.Health(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-tw.exited:
+			return fmt.Errorf("exited before becoming healthy")
+		default:
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("not healthy after %s: %w", timeout, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// stop terminates every twin, waiting up to 5 seconds for a graceful exit,
+// and logs each twin's output when the test failed.
+func (e *Env) stop(t testing.TB) {
+	for _, tw := range e.Twins() {
+		if tw.cmd == nil {
+			continue
+		}
+		if err := tw.cmd.Process.Signal(os.Interrupt); err != nil {
+			tw.cmd.Process.Kill()
+		}
+		select {
+		case <-tw.exited:
+		case <-time.After(5 * time.Second):
+			tw.cmd.Process.Kill()
+			<-tw.exited
+		}
+		if t.Failed() {
+			t.Logf("testenv: %s log:\n%s", tw.Name, tw.logTail())
+		}
+	}
+}
+
+// logTail returns the last lines of the twin's log for failure messages.
+func (tw *Twin) logTail() string {
+	data, err := os.ReadFile(tw.logPath)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > 20 {
+		lines = lines[len(lines)-20:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// loadManifest reads a JSON manifest, rejecting a YAML path with an error
+// that asks for wondertwin.json. It returns the manifest and its directory.
+func loadManifest(path string) (*manifest, string, error) {
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		return nil, "", fmt.Errorf("%s: testenv reads JSON manifests only; use a wondertwin.json", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading manifest: %w", err)
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] != '{' {
+			return nil, "", fmt.Errorf("%s is not JSON: testenv reads JSON manifests only; use a wondertwin.json", path)
+		}
+		return nil, "", fmt.Errorf("parsing manifest %s: %w", path, err)
+	}
+	if len(m.Twins) == 0 {
+		return nil, "", errors.New("manifest has no twins defined")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return &m, filepath.Dir(abs), nil
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("finding a free port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// resolvePath resolves p relative to the manifest directory.
+func resolvePath(dir, p string) string {
+	p = expandPath(p)
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+func expandPath(p string) string {
+	if strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, p[2:])
+		}
+	}
+	return p
+}
@@ -0,0 +1,183 @@
+package testenv
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/admin"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// TestMain lets the test binary double as a twin: manifests written by the
+// tests point at os.Executable with TESTENV_FAKE_TWIN set.
+func TestMain(m *testing.M) {
+	if os.Getenv("TESTENV_FAKE_TWIN") == "1" {
+		runFakeTwin()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+type fakeStore struct {
+	mu    sync.Mutex
+	state map[string]any
+}
+
+func (s *fakeStore) Snapshot() any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+func (s *fakeStore) LoadState(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.Unmarshal(data, &s.state)
+}
+
+func (s *fakeStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = map[string]any{}
+}
+
+func runFakeTwin() {
+	cfg := twincore.ParseFlags("twin-fake")
+	twin := twincore.New(cfg)
+	st := &fakeStore{state: map[string]any{"greeting": os.Getenv("FAKE_GREETING")}}
+	admin.NewHandler(st, twin.Middleware(), nil).Routes(twin.Router)
+	if cfg.SeedFile != "" {
+		data, err := os.ReadFile(cfg.SeedFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := st.LoadState(data); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := twin.Serve(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeManifest(t *testing.T) string {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "seed.json"), []byte(`{"customers":["cus_1"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := map[string]any{
+		"twins": map[string]any{
+			"alpha": map[string]any{
+				"binary": exe,
+				"seed":   "seed.json",
+				"env":    map[string]string{"TESTENV_FAKE_TWIN": "1"},
+			},
+			"beta": map[string]any{
+				"binary": exe,
+				"port":   1,
+				"env":    map[string]string{"TESTENV_FAKE_TWIN": "1", "FAKE_GREETING": "hi"},
+			},
+		},
+	}
+	data, _ := json.Marshal(m)
+	path := filepath.Join(dir, "wondertwin.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func state(t *testing.T, tw *Twin) map[string]any {
+	t.Helper()
+	raw, err := tw.Admin.State(context.Background())
+	if err != nil {
+		t.Fatalf("State(%s): %v", tw.Name, err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestStartResetAndStop(t *testing.T) {
+	path := writeManifest(t)
+	var twins []*Twin
+
+	t.Run("fleet", func(t *testing.T) {
+		env := Start(t, path)
+		twins = env.Twins()
+		if len(twins) != 2 || twins[0].Name != "alpha" || twins[1].Name != "beta" {
+			t.Fatalf("unexpected twins: %+v", twins)
+		}
+		alpha, beta := env.Twin("alpha"), env.Twin("beta")
+		if alpha.Port == beta.Port || beta.Port == 1 {
+			t.Errorf("expected distinct free ports, got %d and %d", alpha.Port, beta.Port)
+		}
+
+		if _, ok := state(t, alpha)["customers"]; !ok {
+			t.Errorf("expected alpha to start with its seed, got %v", state(t, alpha))
+		}
+		if got := state(t, beta)["greeting"]; got != "hi" {
+			t.Errorf("expected manifest env to reach beta, got greeting %v", got)
+		}
+
+		ctx := context.Background()
+		if err := alpha.Admin.LoadState(ctx, []byte(`{"customers":[]}`)); err != nil {
+			t.Fatal(err)
+		}
+		if err := beta.Admin.LoadState(ctx, []byte(`{"leftover":true}`)); err != nil {
+			t.Fatal(err)
+		}
+
+		env.Reset(t)
+		if got := state(t, alpha)["customers"]; len(got.([]any)) != 1 {
+			t.Errorf("expected reset to reload alpha's seed, got %v", got)
+		}
+		if got := state(t, beta); len(got) != 0 {
+			t.Errorf("expected reset to clear beta, got %v", got)
+		}
+
+		alpha.Client(t).Get("/admin/health").AssertStatus(200)
+	})
+
+	for _, tw := range twins {
+		select {
+		case <-tw.exited:
+		default:
+			t.Errorf("expected %s to be stopped after the test", tw.Name)
+		}
+	}
+}
+
+func TestStartWithTwins(t *testing.T) {
+	env := Start(t, writeManifest(t), WithTwins("beta"))
+	if twins := env.Twins(); len(twins) != 1 || twins[0].Name != "beta" {
+		t.Errorf("expected only beta, got %+v", twins)
+	}
+}
+
+func TestLoadManifestRejectsYAML(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "twins:\n  stripe:\n    binary: ./bin/twin-stripe\n"
+	for _, name := range []string{"wondertwin.yaml", "manifest"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := loadManifest(path); err == nil || !strings.Contains(err.Error(), "testenv reads JSON manifests only") {
+			t.Errorf("%s: expected a JSON-only error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/store"
+)
+
+// DefaultStressRecords is how many records PaginationStress seeds unless
+// told otherwise: enough for dozens of pages at typical page sizes.
+const DefaultStressRecords = 250
+
+// Paginator fetches one page of a list endpoint: the IDs on the page after
+// cursor ("" for the first page) and the cursor of the next page, "" after
+// the last one.
+type Paginator func(cursor string) (ids []string, next string)
+
+// PaginationStress pages through a list endpoint with many records and
+// checks what twins commonly get wrong at page boundaries: records listed
+// twice or not at all, cursors that loop, and orderings that change from
+// one walk to the next or when records are created mid-walk.
+//
+// Seed creates record i and returns its ID, through the API or straight
+// into a store (see SeedStore). When Write is set, it is called between
+// page requests of one walk, as a concurrent client's creates would land,
+// and must return the ID it created; records it creates may or may not be
+// listed by that walk, but must not disturb the seeded ones.
+type PaginationStress struct {
+	Records int // default DefaultStressRecords
+	Seed    func(i int) string
+	List    Paginator
+	Write   func(i int) string
+}
+
+// Run seeds the records and walks the list endpoint, reporting every
+// problem it finds on t. The list is expected to hold exactly the seeded
+// records, so run it against a freshly reset twin.
+func (ps PaginationStress) Run(t testing.TB) {
+	t.Helper()
+	n := ps.Records
+	if n <= 0 {
+		n = DefaultStressRecords
+	}
+	seeded := make([]string, n)
+	for i := range seeded {
+		seeded[i] = ps.Seed(i)
+	}
+
+	first := walkPages(t, ps.List, n, nil)
+	checkListed(t, "first walk", first, seeded, nil)
+
+	second := walkPages(t, ps.List, n, nil)
+	if !equalIDs(first, second) {
+		t.Errorf("second walk: listed %d records in a different order than the first walk (first difference at position %d)", len(second), firstDifference(first, second))
+	}
+
+	if ps.Write == nil {
+		return
+	}
+	written := map[string]bool{}
+	writes := 0
+	third := walkPages(t, ps.List, n, func() {
+		written[ps.Write(writes)] = true
+		writes++
+	})
+	checkListed(t, "walk with concurrent writes", third, seeded, written)
+	var seededOrder []string
+	for _, id := range third {
+		if !written[id] {
+			seededOrder = append(seededOrder, id)
+		}
+	}
+	if !equalIDs(first, seededOrder) {
+		t.Errorf("walk with concurrent writes: seeded records listed in a different order than without writes (first difference at position %d)", firstDifference(first, seededOrder))
+	}
+}
+
+// walkPages follows list from the first page to the last, calling between
+// (when set) before every page after the first. It stops with an error
+// when a cursor repeats or the walk runs far past the expected records.
+func walkPages(t testing.TB, list Paginator, expected int, between func()) []string {
+	t.Helper()
+	var all []string
+	seen := map[string]bool{}
+	cursor := ""
+	for page := 0; ; page++ {
+		if page > 0 && between != nil {
+			between()
+		}
+		ids, next := list(cursor)
+		all = append(all, ids...)
+		if next == "" {
+			return all
+		}
+		if seen[next] {
+			t.Errorf("page %d: cursor %q was already returned by an earlier page; the walk would loop", page, next)
+			return all
+		}
+		seen[next] = true
+		if len(all) > 2*expected+100 {
+			t.Errorf("page %d: listed %d records for %d seeded without reaching the last page", page, len(all), expected)
+			return all
+		}
+		cursor = next
+	}
+}
+
+// checkListed reports records listed twice, seeded records not listed, and
+// listed records that are neither seeded nor in extra.
+func checkListed(t testing.TB, walk string, listed, seeded []string, extra map[string]bool) {
+	t.Helper()
+	count := map[string]int{}
+	for _, id := range listed {
+		count[id]++
+	}
+	var dups, missing, unknown []string
+	for _, id := range listed {
+		if count[id] > 1 {
+			dups = append(dups, fmt.Sprintf("%s (%dx)", id, count[id]))
+			count[id] = 1
+		}
+	}
+	isSeeded := map[string]bool{}
+	for _, id := range seeded {
+		isSeeded[id] = true
+		if count[id] == 0 {
+			missing = append(missing, id)
+		}
+	}
+	for id := range count {
+		if !isSeeded[id] && !extra[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(dups) > 0 {
+		t.Errorf("%s: %d records listed more than once: %s", walk, len(dups), sample(dups))
+	}
+	if len(missing) > 0 {
+		t.Errorf("%s: %d of %d seeded records never listed: %s", walk, len(missing), len(seeded), sample(missing))
+	}
+	if len(unknown) > 0 {
+		t.Errorf("%s: %d records listed that were not created by the test: %s", walk, len(unknown), sample(unknown))
+	}
+}
+
+// sample formats the first few IDs of a problem for a failure message.
+func sample(ids []string) string {
+	if len(ids) > 5 {
+		return strings.Join(ids[:5], ", ") + ", ..."
+	}
+	return strings.Join(ids, ", ")
+}
+
+func equalIDs(a, b []string) bool {
+	return firstDifference(a, b) == -1
+}
+
+// firstDifference returns the first position at which a and b differ, or
+// -1 when they are equal.
+func firstDifference(a, b []string) int {
+	for i := range a {
+		if i >= len(b) || a[i] != b[i] {
+			return i
+		}
+	}
+	if len(b) > len(a) {
+		return len(a)
+	}
+	return -1
+}
+
+// SeedStore returns a PaginationStress Seed that puts build(id, i) into s under
+// a new ID, for tests that hold the twin's store.
+func SeedStore[T any](s *store.Store[T], build func(id string, i int) T) func(i int) string {
+	return func(i int) string {
+		id := s.NextID()
+		s.Set(id, build(id, i))
+		return id
+	}
+}
+
+// StartingAfter pages a Stripe-style list: GET path?limit=<limit>, then
+// &starting_after=<last ID> while has_more is true, reading data[].id.
+func StartingAfter(c *TwinClient, path string, limit int, headers map[string]string) Paginator {
+	return func(cursor string) ([]string, string) {
+		c.t.Helper()
+		q := url.Values{"limit": {strconv.Itoa(limit)}}
+		if cursor != "" {
+			q.Set("starting_after", cursor)
+		}
+		var page struct {
+			Data    []struct{ ID string } `json:"data"`
+			HasMore bool                  `json:"has_more"`
+		}
+		c.listPage(withQuery(path, q), headers, &page)
+		ids := make([]string, len(page.Data))
+		for i, d := range page.Data {
+			ids[i] = d.ID
+		}
+		if !page.HasMore || len(ids) == 0 {
+			return ids, ""
+		}
+		return ids, ids[len(ids)-1]
+	}
+}
+
+// Offset pages a list addressed by offset: GET path?limit=<limit>&offset=<n>,
+// reading data[].id, until a page comes back short.
+func Offset(c *TwinClient, path string, limit int, headers map[string]string) Paginator {
+	return func(cursor string) ([]string, string) {
+		c.t.Helper()
+		offset := 0
+		if cursor != "" {
+			offset, _ = strconv.Atoi(cursor)
+		}
+		q := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(offset)}}
+		var page struct {
+			Data []struct{ ID string } `json:"data"`
+		}
+		c.listPage(withQuery(path, q), headers, &page)
+		ids := make([]string, len(page.Data))
+		for i, d := range page.Data {
+			ids[i] = d.ID
+		}
+		if len(ids) < limit {
+			return ids, ""
+		}
+		return ids, strconv.Itoa(offset + len(ids))
+	}
+}
+
+// listPage GETs one page of a list and decodes it into v.
+func (c *TwinClient) listPage(path string, headers map[string]string, v any) {
+	c.t.Helper()
+	resp := c.do("GET", path, nil, headers)
+	if resp.StatusCode != 200 {
+		c.t.Fatalf("GET %s: expected status 200, got %d\nbody: %s", path, resp.StatusCode, resp.Body)
+	}
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		c.t.Fatalf("GET %s: decoding list page: %v\nbody: %s", path, err, resp.Body)
+	}
+}
+
+// withQuery appends q to path, which may already have a query.
+func withQuery(path string, q url.Values) string {
+	if strings.Contains(path, "?") {
+		return path + "&" + q.Encode()
+	}
+	return path + "?" + q.Encode()
+}
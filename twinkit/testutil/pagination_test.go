@@ -0,0 +1,107 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/store"
+)
+
+type item struct {
+	ID string `json:"id"`
+}
+
+// newListServer serves a store through GET /items with Stripe-style
+// starting_after pagination, or offset pagination when newestFirst is set,
+// listing newest first as some providers do.
+func newListServer(s *store.Store[item], newestFirst bool) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items", func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		if !newestFirst {
+			json.NewEncoder(w).Encode(s.Paginate(r.URL.Query().Get("starting_after"), limit))
+			return
+		}
+		all := s.List()
+		for i, j := 0, len(all)-1; i < j; i, j = i+1, j-1 {
+			all[i], all[j] = all[j], all[i]
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := min(offset+limit, len(all))
+		json.NewEncoder(w).Encode(map[string]any{"data": all[min(offset, end):end]})
+	})
+	return httptest.NewServer(mux)
+}
+
+// recordingTB records failures instead of failing the test, for checking
+// what PaginationStress reports.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestPaginationStress(t *testing.T) {
+	s := store.New[item]("item")
+	srv := newListServer(s, false)
+	defer srv.Close()
+	client := NewTwinClient(t, srv)
+
+	seed := SeedStore(s, func(id string, i int) item { return item{ID: id} })
+	PaginationStress{
+		Seed:  seed,
+		List:  StartingAfter(client, "/items", 7, nil),
+		Write: seed,
+	}.Run(t)
+}
+
+func TestPaginationStressReportsShiftingOffsets(t *testing.T) {
+	s := store.New[item]("item")
+	srv := newListServer(s, true)
+	defer srv.Close()
+	client := NewTwinClient(t, srv)
+
+	seed := SeedStore(s, func(id string, i int) item { return item{ID: id} })
+	rec := &recordingTB{TB: t}
+	PaginationStress{
+		Records: 40,
+		Seed:    seed,
+		List:    Offset(client, "/items", 10, nil),
+		Write:   seed,
+	}.Run(rec)
+
+	// New records at the front push every later page back by one, so the
+	// last record of each page comes round again on the next.
+	if len(rec.errors) == 0 {
+		t.Fatal("expected newest-first offset pagination under writes to be reported")
+	}
+	if !strings.Contains(rec.errors[0], "walk with concurrent writes") || !strings.Contains(rec.errors[0], "listed more than once") {
+		t.Errorf("expected duplicates to be reported, got %q", rec.errors)
+	}
+}
+
+func TestPaginationStressReportsLoopingCursor(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	n := 0
+	PaginationStress{
+		Records: 3,
+		Seed:    func(i int) string { n++; return fmt.Sprintf("rec_%d", n) },
+		List: func(cursor string) ([]string, string) {
+			return []string{"rec_1", "rec_2"}, "rec_2"
+		},
+	}.Run(rec)
+	if len(rec.errors) == 0 || !strings.Contains(rec.errors[0], "already returned by an earlier page") {
+		t.Errorf("expected a looping cursor to be reported, got %q", rec.errors)
+	}
+}
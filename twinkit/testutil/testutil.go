@@ -11,6 +11,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/adminclient"
 )
 
 // TwinClient is an HTTP client for interacting with a WonderTwin twin in tests.
@@ -175,13 +177,22 @@ func (c *TwinClient) doReq(req *http.Request) *Response {
 }
 
 // AdminClient provides convenience methods for the /admin/* control plane.
+// Its methods return raw responses for asserting on HTTP behavior; API offers
+// the same endpoints as typed calls that return decoded values and errors.
 type AdminClient struct {
 	*TwinClient
+	API *adminclient.Client
 }
 
 // NewAdminClient creates an admin client from a twin client.
 func NewAdminClient(tc *TwinClient) *AdminClient {
-	return &AdminClient{tc}
+	return &AdminClient{
+		TwinClient: tc,
+		API: adminclient.New(tc.BaseURL,
+			adminclient.WithHTTPClient(tc.HTTPClient),
+			adminclient.WithRetries(0, 0),
+		),
+	}
 }
 
 // Reset calls POST /admin/reset.
@@ -220,12 +231,36 @@ func (ac *AdminClient) GetRequests() *Response {
 	return ac.Get("/admin/requests")
 }
 
+// ReplayRequest calls POST /admin/requests/{id}/replay.
+func (ac *AdminClient) ReplayRequest(id string) *Response {
+	ac.t.Helper()
+	return ac.Post("/admin/requests/"+id+"/replay", nil)
+}
+
 // FlushWebhooks calls POST /admin/webhooks/flush.
 func (ac *AdminClient) FlushWebhooks() *Response {
 	ac.t.Helper()
 	return ac.Post("/admin/webhooks/flush", nil)
 }
 
+// DeadLetters calls GET /admin/webhooks/dead_letters.
+func (ac *AdminClient) DeadLetters() *Response {
+	ac.t.Helper()
+	return ac.Get("/admin/webhooks/dead_letters")
+}
+
+// RetryDeadLetter calls POST /admin/webhooks/dead_letters/{id}/retry.
+func (ac *AdminClient) RetryDeadLetter(eventID string) *Response {
+	ac.t.Helper()
+	return ac.Post("/admin/webhooks/dead_letters/"+eventID+"/retry", nil)
+}
+
+// TriggerEvent calls POST /admin/webhooks/trigger.
+func (ac *AdminClient) TriggerEvent(eventType string, overrides map[string]any) *Response {
+	ac.t.Helper()
+	return ac.Post("/admin/webhooks/trigger", map[string]any{"type": eventType, "overrides": overrides})
+}
+
 // AdvanceTime calls POST /admin/time/advance.
 func (ac *AdminClient) AdvanceTime(duration string) *Response {
 	ac.t.Helper()
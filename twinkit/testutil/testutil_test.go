@@ -1,6 +1,7 @@
 package testutil
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -423,6 +424,28 @@ func TestAdminClientAdvanceTime(t *testing.T) {
 	resp.AssertBodyContains("advanced")
 }
 
+func TestAdminClientTypedAPI(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	ac := NewAdminClient(NewTwinClient(t, srv))
+
+	ctx := context.Background()
+	if err := ac.API.Health(ctx); err != nil {
+		t.Fatalf("Health() error: %v", err)
+	}
+	if err := ac.API.Reset(ctx); err != nil {
+		t.Fatalf("Reset() error: %v", err)
+	}
+	requests, err := ac.API.Requests(ctx)
+	if err != nil {
+		t.Fatalf("Requests() error: %v", err)
+	}
+	if len(requests) != 1 || requests[0].Path != "/items" {
+		t.Errorf("unexpected requests: %+v", requests)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // AdminClient with leading slash handling
 // ---------------------------------------------------------------------------
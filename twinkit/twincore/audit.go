@@ -0,0 +1,193 @@
+package twincore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// maxAuditEntries caps the audit log. Older entries are dropped, and
+// counted in AuditExport.Dropped, but the chain stays verifiable from the
+// first entry kept.
+const maxAuditEntries = 10000
+
+// Audit entry kinds.
+const (
+	AuditAdmin    = "admin"    // a request that modified the twin through its admin API
+	AuditScenario = "scenario" // a scenario run, reported by wt test
+)
+
+// AuditEntry is one link of the audit chain. Hash covers the entry's fields
+// and PrevHash, the previous entry's Hash (64 zeros for the first), so
+// editing, inserting, or removing an entry breaks every hash after it. See
+// AuditEntry.ComputeHash for the exact input.
+type AuditEntry struct {
+	Seq        int       `json:"seq"`
+	Time       time.Time `json:"time"`
+	Kind       string    `json:"kind"`
+	Method     string    `json:"method,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	BodySHA256 string    `json:"body_sha256,omitempty"` // of the request body, when it had one
+	Scenario   string    `json:"scenario,omitempty"`
+	Passed     *bool     `json:"passed,omitempty"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// ComputeHash returns the entry's hash: the hex SHA-256 of its fields, one
+// per line in declaration order after Seq, with Time in RFC 3339 (nanosecond)
+// UTC, Passed as true, false, or empty, and PrevHash last.
+func (e AuditEntry) ComputeHash() string {
+	passed := ""
+	if e.Passed != nil {
+		passed = strconv.FormatBool(*e.Passed)
+	}
+	fields := []string{
+		strconv.Itoa(e.Seq), e.Time.UTC().Format(time.RFC3339Nano), e.Kind, e.Method, e.Path,
+		strconv.Itoa(e.StatusCode), e.RequestID, e.BodySHA256, e.Scenario, passed, e.PrevHash,
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditExport is the response of GET /admin/audit/export.
+type AuditExport struct {
+	Twin       string       `json:"twin"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Entries    []AuditEntry `json:"entries"`
+	Dropped    int          `json:"dropped"` // entries dropped from the front of the log
+	Head       string       `json:"head"`    // the last entry's hash
+	// Signature is the hex HMAC-SHA256 of Head under Config.AuditKey, when
+	// the twin has one, so the export can be traced to a keyed twin.
+	Signature string `json:"signature,omitempty"`
+}
+
+// zeroHash is PrevHash of the first entry.
+var zeroHash = strings.Repeat("0", 64)
+
+// AuditLog is the twin's hash-chained record of admin mutations and
+// scenario runs. It survives /admin/reset, since resets are among what it
+// records.
+type AuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+	dropped int
+	seq     int
+	head    string
+}
+
+// NewAuditLog creates an empty audit log.
+func NewAuditLog() *AuditLog {
+	return &AuditLog{head: zeroHash}
+}
+
+// Append chains e onto the log, filling in Seq, Time if unset, PrevHash,
+// and Hash, and returns the stored entry.
+func (al *AuditLog) Append(e AuditEntry) AuditEntry {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.seq++
+	e.Seq = al.seq
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Time = e.Time.UTC()
+	e.PrevHash = al.head
+	e.Hash = e.ComputeHash()
+	al.head = e.Hash
+	al.entries = append(al.entries, e)
+	if len(al.entries) > maxAuditEntries {
+		al.entries = al.entries[1:]
+		al.dropped++
+	}
+	return e
+}
+
+// Export returns the log for the named twin, signed with key when it is
+// not empty.
+func (al *AuditLog) Export(twin, key string) AuditExport {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	out := AuditExport{
+		Twin:       twin,
+		ExportedAt: time.Now().UTC(),
+		Entries:    append([]AuditEntry{}, al.entries...),
+		Dropped:    al.dropped,
+		Head:       al.head,
+	}
+	if key != "" {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(out.Head))
+		out.Signature = hex.EncodeToString(mac.Sum(nil))
+	}
+	return out
+}
+
+// ExportAudit returns the twin's audit log, signed with Config.AuditKey.
+func (m *Middleware) ExportAudit() AuditExport {
+	return m.Audit.Export(m.cfg.Name, m.cfg.AuditKey)
+}
+
+// AuditMutations appends an AuditAdmin entry to Middleware.Audit for every
+// admin request that can modify the twin — anything but GET, HEAD, and the
+// read-only POST /admin/state/lint — with its status and a hash of its
+// body, including requests rejected by AdminReadOnly. The audit endpoints
+// themselves are left out; scenario runs are recorded through
+// POST /admin/audit/events instead.
+func (m *Middleware) AuditMutations(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/admin/") || strings.HasPrefix(r.URL.Path, "/admin/audit/") ||
+			r.Method == http.MethodGet || r.Method == http.MethodHead || r.URL.Path == "/admin/state/lint" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body *hashingReader
+		if r.Body != nil && r.Body != http.NoBody {
+			body = &hashingReader{ReadCloser: r.Body, h: sha256.New()}
+			r.Body = body
+		}
+		rec := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		e := AuditEntry{
+			Kind:       AuditAdmin,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			StatusCode: rec.statusCode,
+			RequestID:  chimw.GetReqID(r.Context()),
+		}
+		if body != nil {
+			io.Copy(io.Discard, body) // hash what the handler left unread
+			if body.n > 0 {
+				e.BodySHA256 = hex.EncodeToString(body.h.Sum(nil))
+			}
+		}
+		m.Audit.Append(e)
+	})
+}
+
+// hashingReader hashes a request body as it is read.
+type hashingReader struct {
+	io.ReadCloser
+	h hash.Hash
+	n int64
+}
+
+func (hr *hashingReader) Read(p []byte) (int, error) {
+	n, err := hr.ReadCloser.Read(p)
+	hr.h.Write(p[:n])
+	hr.n += int64(n)
+	return n, err
+}
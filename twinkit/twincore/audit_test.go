@@ -0,0 +1,89 @@
+package twincore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuditLogChain(t *testing.T) {
+	al := NewAuditLog()
+	passed := true
+	al.Append(AuditEntry{Kind: AuditAdmin, Method: "POST", Path: "/admin/reset", StatusCode: 200})
+	al.Append(AuditEntry{Kind: AuditScenario, Scenario: "checkout", Passed: &passed})
+
+	exp := al.Export("twin-stripe", "")
+	if len(exp.Entries) != 2 || exp.Signature != "" {
+		t.Fatalf("expected 2 unsigned entries, got %+v", exp)
+	}
+	prev := zeroHash
+	for i, e := range exp.Entries {
+		if e.Seq != i+1 || e.PrevHash != prev || e.Hash != e.ComputeHash() {
+			t.Errorf("entry %d does not chain: %+v", i, e)
+		}
+		prev = e.Hash
+	}
+	if exp.Head != prev {
+		t.Errorf("expected head %s, got %s", prev, exp.Head)
+	}
+
+	// Any edit changes the entry's hash, so the next entry no longer chains.
+	edited := exp.Entries[0]
+	edited.StatusCode = 500
+	if edited.ComputeHash() == exp.Entries[1].PrevHash {
+		t.Error("expected an edited entry to break the chain")
+	}
+
+	signed := al.Export("twin-stripe", "k3y")
+	mac := hmac.New(sha256.New, []byte("k3y"))
+	mac.Write([]byte(signed.Head))
+	if signed.Signature != hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("expected the head signed with the key, got %q", signed.Signature)
+	}
+}
+
+func TestAuditLogDropsOldest(t *testing.T) {
+	al := NewAuditLog()
+	for range maxAuditEntries + 2 {
+		al.Append(AuditEntry{Kind: AuditAdmin, Method: "POST", Path: "/admin/reset"})
+	}
+	exp := al.Export("twin", "")
+	if len(exp.Entries) != maxAuditEntries || exp.Dropped != 2 || exp.Entries[0].Seq != 3 {
+		t.Errorf("expected the two oldest entries dropped, got %d entries, %d dropped, first seq %d",
+			len(exp.Entries), exp.Dropped, exp.Entries[0].Seq)
+	}
+}
+
+func TestAuditMutations(t *testing.T) {
+	mw := NewMiddleware(&Config{AdminReadOnly: true}, slog.Default())
+	handler := mw.AuditMutations(mw.AdminReadOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	for _, req := range []struct{ method, path, body string }{
+		{"POST", "/admin/state", `{"accounts":[]}`},
+		{"GET", "/admin/state", ""},
+		{"POST", "/admin/state/lint", `{}`},
+		{"POST", "/admin/audit/events", `{}`},
+		{"POST", "/v1/charges", `amount=100`},
+		{"DELETE", "/admin/fault/v1/charges", ""},
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, strings.NewReader(req.body)))
+	}
+
+	entries := mw.ExportAudit().Entries
+	if len(entries) != 2 {
+		t.Fatalf("expected the two admin mutations recorded, got %+v", entries)
+	}
+	sum := sha256.Sum256([]byte(`{"accounts":[]}`))
+	if e := entries[0]; e.Path != "/admin/state" || e.StatusCode != http.StatusForbidden || e.BodySHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected the rejected state load with its body hash, got %+v", e)
+	}
+	if e := entries[1]; e.Method != "DELETE" || e.BodySHA256 != "" {
+		t.Errorf("expected the fault removal without a body hash, got %+v", e)
+	}
+}
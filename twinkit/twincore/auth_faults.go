@@ -0,0 +1,70 @@
+package twincore
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Built-in fault presets, available on every twin. Inject one on any
+// endpoint by sending {"preset": "auth_expired"} to POST
+// /admin/fault/{endpoint}; the twin answers with its provider's own auth
+// error (see Middleware.WriteAuthError), so a test of token-expiry handling
+// does not need to know each provider's error shape.
+const (
+	// FaultAuthInvalid answers 401 as if the credentials were not recognised.
+	FaultAuthInvalid = "auth_invalid"
+	// FaultAuthExpired answers 401 as if the credentials had expired.
+	FaultAuthExpired = "auth_expired"
+	// FaultForbiddenScope answers 403 as if the credentials lacked the
+	// permission or scope the request needs.
+	FaultForbiddenScope = "forbidden_scope"
+)
+
+// faultPreset is the status code and generic description of a preset.
+type faultPreset struct {
+	status  int
+	message string
+}
+
+var faultPresets = map[string]faultPreset{
+	FaultAuthInvalid:    {http.StatusUnauthorized, "Invalid API key provided."},
+	FaultAuthExpired:    {http.StatusUnauthorized, "The API key provided has expired."},
+	FaultForbiddenScope: {http.StatusForbidden, "The API key provided does not have permission to perform this request."},
+}
+
+// BuiltinFaults returns the fault presets twincore implements for every
+// twin. They have no Endpoint: each applies to whichever endpoint it is
+// injected on.
+func BuiltinFaults() []NamedFault {
+	return []NamedFault{
+		{Name: FaultAuthInvalid, Description: "Requests are rejected with the provider's 401 for unrecognised credentials", Fault: FaultConfig{StatusCode: http.StatusUnauthorized, Rate: 1.0, Preset: FaultAuthInvalid}},
+		{Name: FaultAuthExpired, Description: "Requests are rejected with the provider's 401 for expired credentials", Fault: FaultConfig{StatusCode: http.StatusUnauthorized, Rate: 1.0, Preset: FaultAuthExpired}},
+		{Name: FaultForbiddenScope, Description: "Requests are rejected with the provider's 403 for credentials missing a permission", Fault: FaultConfig{StatusCode: http.StatusForbidden, Rate: 1.0, Preset: FaultForbiddenScope}},
+	}
+}
+
+// resolvePreset checks a fault's preset and sets its status code to the
+// preset's.
+func resolvePreset(f *FaultConfig) error {
+	if f.Preset == "" {
+		return nil
+	}
+	p, ok := faultPresets[f.Preset]
+	if !ok {
+		return fmt.Errorf("unknown preset %q (known: %s, %s, %s)", f.Preset, FaultAuthInvalid, FaultAuthExpired, FaultForbiddenScope)
+	}
+	f.StatusCode = p.status
+	return nil
+}
+
+// AuthErrorWriter writes the response for a request failed by one of the
+// auth fault presets (FaultAuthInvalid, FaultAuthExpired,
+// FaultForbiddenScope).
+type AuthErrorWriter func(w http.ResponseWriter, r *http.Request, preset string)
+
+// WriteAuthError is the default AuthErrorWriter: the preset's status code
+// in the shape of Error.
+func WriteAuthError(w http.ResponseWriter, r *http.Request, preset string) {
+	p := faultPresets[preset]
+	Error(w, p.status, p.message)
+}
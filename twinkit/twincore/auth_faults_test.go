@@ -0,0 +1,57 @@
+package twincore
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFaultPresetSetsStatus(t *testing.T) {
+	fr := NewFaultRegistry()
+	if err := fr.Set("/v1/test", FaultConfig{Preset: FaultForbiddenScope}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fr.All()["/v1/test"].StatusCode; got != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", got)
+	}
+
+	if err := fr.Set("/v1/other", FaultConfig{Preset: "auth_bogus"}); err == nil {
+		t.Error("expected an unknown preset to be rejected")
+	}
+	if _, ok := fr.All()["/v1/other"]; ok {
+		t.Error("expected nothing registered for an unknown preset")
+	}
+}
+
+func TestFaultPresetUsesTwinAuthError(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	handler := mw.FaultInjection(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected the fault to answer the request")
+	}))
+	mw.Faults.Set("/v1/things", FaultConfig{Preset: FaultAuthExpired})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/things", nil))
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "expired") {
+		t.Errorf("default writer: got %d %s", rec.Code, rec.Body.String())
+	}
+
+	mw.WriteAuthError = func(w http.ResponseWriter, r *http.Request, preset string) {
+		JSON(w, http.StatusUnauthorized, map[string]string{"provider_error": preset})
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/things", nil))
+	if !strings.Contains(rec.Body.String(), `"provider_error":"auth_expired"`) {
+		t.Errorf("twin writer: got %s", rec.Body.String())
+	}
+
+	// An explicit body wins over the twin's error.
+	mw.Faults.Set("/v1/things", FaultConfig{Preset: FaultAuthInvalid, Body: `{"custom":true}`})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/things", nil))
+	if rec.Body.String() != `{"custom":true}` {
+		t.Errorf("explicit body: got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,89 @@
+package twincore
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BackpressureHeader names the threshold, "max_in_flight" or "max_rps",
+// that made the twin shed a request, so tests can tell simulated overload
+// from a real failure.
+const BackpressureHeader = "X-WT-Backpressure"
+
+// backpressureRetryAfter returns BackpressureRetryAfter, or
+// DefaultRetryAfter when it is unset.
+func (c *Config) backpressureRetryAfter() int {
+	if c.BackpressureRetryAfter <= 0 {
+		return DefaultRetryAfter
+	}
+	return c.BackpressureRetryAfter
+}
+
+// backpressure tracks the load Middleware.Backpressure measures.
+type backpressure struct {
+	mu       sync.Mutex
+	inFlight int
+	accepted []time.Time // arrival of recently accepted requests, oldest first
+}
+
+// admit reports which threshold, if any, a request arriving now exceeds,
+// counting it as in flight when it does not. Callers that are admitted call
+// done when the request finishes.
+func (b *backpressure) admit(now time.Time, maxInFlight, maxRPS int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if maxInFlight > 0 && b.inFlight >= maxInFlight {
+		return "max_in_flight"
+	}
+	if maxRPS > 0 {
+		cutoff := now.Add(-time.Second)
+		i := 0
+		for i < len(b.accepted) && !b.accepted[i].After(cutoff) {
+			i++
+		}
+		b.accepted = b.accepted[i:]
+		if len(b.accepted) >= maxRPS {
+			return "max_rps"
+		}
+		b.accepted = append(b.accepted, now)
+	}
+	b.inFlight++
+	return ""
+}
+
+func (b *backpressure) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.inFlight--
+}
+
+// Backpressure simulates a provider shedding load. While Config.MaxInFlight
+// API requests are already being handled, or Config.MaxRPS were accepted in
+// the last second, further requests are answered with 503 Service
+// Unavailable and a Retry-After of Config.BackpressureRetryAfter seconds,
+// and carry BackpressureHeader. Shed requests do not count towards either
+// threshold, so the twin recovers as soon as the load drops below them.
+// Admin and health requests are never shed.
+func (m *Middleware) Backpressure(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxInFlight, maxRPS := m.cfg.MaxInFlight, m.cfg.MaxRPS
+		if (maxInFlight <= 0 && maxRPS <= 0) || strings.HasPrefix(r.URL.Path, "/admin/") || r.URL.Path == healthzPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if over := m.load.admit(time.Now(), maxInFlight, maxRPS); over != "" {
+			retryAfter := m.cfg.backpressureRetryAfter()
+			m.logger.Debug("shed request under simulated load", "method", r.Method, "path", r.URL.Path, "threshold", over)
+			w.Header().Set(BackpressureHeader, over)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			Error(w, http.StatusServiceUnavailable, fmt.Sprintf("the service is overloaded (%s exceeded); retry after %ds", over, retryAfter))
+			return
+		}
+		defer m.load.done()
+		next.ServeHTTP(w, r)
+	})
+}
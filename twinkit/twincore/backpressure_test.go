@@ -0,0 +1,82 @@
+package twincore
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackpressureMaxInFlight(t *testing.T) {
+	cfg := &Config{MaxInFlight: 1, BackpressureRetryAfter: 3}
+	mw := NewMiddleware(cfg, slog.Default())
+	entered, release := make(chan struct{}), make(chan struct{})
+	handler := mw.Backpressure(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/slow" {
+			close(entered)
+			<-release
+		}
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/slow", nil))
+		close(done)
+	}()
+	<-entered
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/fast", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "3" || rec.Header().Get(BackpressureHeader) != "max_in_flight" {
+		t.Errorf("expected a shed 503 with Retry-After 3, got %d %v", rec.Code, rec.Header())
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/admin/reset", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected admin requests never shed, got %d", rec.Code)
+	}
+
+	close(release)
+	<-done
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/fast", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected the twin to recover below the threshold, got %d", rec.Code)
+	}
+}
+
+func TestBackpressureMaxRPS(t *testing.T) {
+	var b backpressure
+	now := time.Now()
+	for i := range 2 {
+		if over := b.admit(now, 0, 2); over != "" {
+			t.Fatalf("request %d: expected it admitted, got %s", i, over)
+		}
+		b.done()
+	}
+	if over := b.admit(now.Add(500*time.Millisecond), 0, 2); over != "max_rps" {
+		t.Errorf("expected the third request in a second shed, got %q", over)
+	}
+	// Shed requests do not count, so load recovers once the window moves on.
+	if over := b.admit(now.Add(1001*time.Millisecond), 0, 2); over != "" {
+		t.Errorf("expected a request a second later admitted, got %q", over)
+	}
+}
+
+func TestTwinUpdateConfigBackpressure(t *testing.T) {
+	twin := New(&Config{Name: "test"})
+	if err := twin.UpdateConfig(map[string]any{"max_in_flight": 4.0, "max_rps": 100.0, "backpressure_retry_after": 5.0}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := twin.GetConfig()
+	if cfg["max_in_flight"] != 4 || cfg["max_rps"] != 100 || cfg["backpressure_retry_after"] != 5 {
+		t.Errorf("unexpected config %v", cfg)
+	}
+	if err := twin.UpdateConfig(map[string]any{"max_rps": -1.0}); err == nil {
+		t.Error("expected a negative max_rps to be rejected")
+	}
+	if got := New(&Config{}).GetConfig()["backpressure_retry_after"]; got != DefaultRetryAfter {
+		t.Errorf("expected backpressure_retry_after to default to %d, got %v", DefaultRetryAfter, got)
+	}
+}
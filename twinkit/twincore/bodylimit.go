@@ -0,0 +1,144 @@
+package twincore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// QuirkTruncatedBodies cuts request bodies over the size limit down to the
+// limit and lets the request through, instead of rejecting it, as a proxy
+// with a fixed buffer in front of the provider might. It has no effect on
+// routes without a limit; see Middleware.BodyLimit.
+const QuirkTruncatedBodies = "WT-Q-009"
+
+// BodyTooLargeWriter writes the response for a request whose body is over
+// limitKB kilobytes.
+type BodyTooLargeWriter func(w http.ResponseWriter, r *http.Request, limitKB int)
+
+// WriteBodyTooLarge is the default BodyTooLargeWriter: 413 Content Too
+// Large in the shape of Error.
+func WriteBodyTooLarge(w http.ResponseWriter, r *http.Request, limitKB int) {
+	Error(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds the %d KB limit", limitKB))
+}
+
+// BodyLimit enforces Config.MaxBodyKB and the per-route Config.BodyLimits
+// on requests outside /admin. A body over its route's limit is answered
+// with m.WriteBodyTooLarge, so twins reject it the way their provider
+// does, or with QuirkTruncatedBodies on is cut to the limit and passed on.
+// Bodies are checked as they are read, so chunked uploads without a
+// Content-Length are limited too.
+func (m *Middleware) BodyLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limitKB := m.cfg.bodyLimit(r.URL.Path)
+		if limitKB <= 0 || r.Body == nil || r.Body == http.NoBody || strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		limit := int64(limitKB) << 10
+		truncate := m.Quirks.IsEnabled(QuirkTruncatedBodies)
+		if r.ContentLength > limit && !truncate {
+			m.rejectBody(w, r, limitKB)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+		if err != nil {
+			Error(w, http.StatusBadRequest, "reading request body: "+err.Error())
+			return
+		}
+		if int64(len(body)) > limit {
+			if !truncate {
+				m.rejectBody(w, r, limitKB)
+				return
+			}
+			m.logger.Info("truncated request body", "method", r.Method, "path", r.URL.Path, "limit_kb", limitKB)
+			body = body[:limit]
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *Middleware) rejectBody(w http.ResponseWriter, r *http.Request, limitKB int) {
+	// The rest of the body is never read; close the connection rather
+	// than drain an upload the client may still be sending.
+	w.Header().Set("Connection", "close")
+	write := m.WriteBodyTooLarge
+	if write == nil {
+		write = WriteBodyTooLarge
+	}
+	write(w, r, limitKB)
+}
+
+// bodyLimit returns the body size limit in kilobytes for path: that of the
+// longest BodyLimits route matching it, else MaxBodyKB.
+func (c *Config) bodyLimit(path string) int {
+	best, limit := -1, c.MaxBodyKB
+	for route, kb := range c.BodyLimits {
+		if len(route) > best && routeMatches(route, path) {
+			best, limit = len(route), kb
+		}
+	}
+	return limit
+}
+
+// routeMatches reports whether path is route, or is under it when route
+// ends in "/*".
+func routeMatches(route, path string) bool {
+	if prefix, ok := strings.CutSuffix(route, "/*"); ok {
+		return strings.HasPrefix(path, prefix+"/")
+	}
+	return route == path
+}
+
+// ParseBodyLimits parses per-route body size limits written as
+// comma-separated route=kilobytes pairs, e.g. "/v1/files=32768,/emails=40960".
+// A route ending in "/*" covers every path under it, and 0 lifts the limit
+// for a route.
+func ParseBodyLimits(s string) (map[string]int, error) {
+	limits := map[string]int{}
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		route, kb, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected route=kilobytes, got %q", pair)
+		}
+		n, err := strconv.Atoi(kb)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s: limit must be a non-negative number of kilobytes, got %q", route, kb)
+		}
+		if err := checkBodyLimitRoute(route); err != nil {
+			return nil, err
+		}
+		limits[route] = n
+	}
+	return limits, nil
+}
+
+func checkBodyLimitRoute(route string) error {
+	if !strings.HasPrefix(route, "/") {
+		return fmt.Errorf("route %q must start with /", route)
+	}
+	if strings.HasPrefix(route, "/admin/") {
+		return fmt.Errorf("route %q: admin requests are never limited", route)
+	}
+	return nil
+}
+
+// bodyLimitConfig copies body limits for GET /admin/config, reporting none
+// as an empty object.
+func bodyLimitConfig(limits map[string]int) map[string]int {
+	out := make(map[string]int, len(limits))
+	for route, kb := range limits {
+		out[route] = kb
+	}
+	return out
+}
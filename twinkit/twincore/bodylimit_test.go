@@ -0,0 +1,99 @@
+package twincore
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLimit(t *testing.T) {
+	cfg := &Config{MaxBodyKB: 1, BodyLimits: map[string]int{"/v1/files": 4, "/v1/accounts/*": 2, "/v1/bulk": 0}}
+	mw := NewMiddleware(cfg, slog.Default())
+	var got int
+	handler := mw.BodyLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = len(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	send := func(path string, size int, chunked bool) int {
+		got = -1
+		var body io.Reader = strings.NewReader(strings.Repeat("a", size))
+		if chunked {
+			body = io.MultiReader(body) // hides the length, as a chunked upload does
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("POST", path, body))
+		return rec.Code
+	}
+
+	tests := []struct {
+		path    string
+		size    int
+		chunked bool
+		want    int
+	}{
+		{"/v1/charges", 1024, false, http.StatusOK},
+		{"/v1/charges", 1025, false, http.StatusRequestEntityTooLarge},
+		{"/v1/charges", 1025, true, http.StatusRequestEntityTooLarge},
+		{"/v1/files", 4096, false, http.StatusOK},
+		{"/v1/files", 4097, true, http.StatusRequestEntityTooLarge},
+		{"/v1/accounts/acct_1/persons", 2048, false, http.StatusOK},
+		{"/v1/accounts", 2048, false, http.StatusRequestEntityTooLarge}, // not under /v1/accounts/
+		{"/v1/bulk", 1 << 20, false, http.StatusOK},                     // 0 lifts the limit
+		{"/admin/state", 1 << 20, false, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := send(tt.path, tt.size, tt.chunked); code != tt.want {
+			t.Errorf("POST %s with %d bytes (chunked %v): expected %d, got %d", tt.path, tt.size, tt.chunked, tt.want, code)
+		}
+	}
+
+	mw.Quirks.EnableQuirk(QuirkTruncatedBodies)
+	if code := send("/v1/charges", 5000, false); code != http.StatusOK || got != 1024 {
+		t.Errorf("expected the body truncated to 1024 bytes, got %d with %d bytes", code, got)
+	}
+
+	mw.Quirks.DisableQuirk(QuirkTruncatedBodies)
+	mw.WriteBodyTooLarge = func(w http.ResponseWriter, r *http.Request, limitKB int) {
+		StripeError(w, http.StatusBadRequest, "invalid_request_error", "", "too large")
+	}
+	if code := send("/v1/charges", 5000, false); code != http.StatusBadRequest {
+		t.Errorf("expected the twin's writer to answer 400, got %d", code)
+	}
+}
+
+func TestParseBodyLimits(t *testing.T) {
+	limits, err := ParseBodyLimits(" /v1/files=32768, /v1/accounts/*=64 ")
+	if err != nil || len(limits) != 2 || limits["/v1/files"] != 32768 || limits["/v1/accounts/*"] != 64 {
+		t.Errorf("expected two limits, got %v (%v)", limits, err)
+	}
+	for _, bad := range []string{"/v1/files", "/v1/files=-1", "/v1/files=1MB", "v1/files=1", "/admin/state=1"} {
+		if _, err := ParseBodyLimits(bad); err == nil {
+			t.Errorf("ParseBodyLimits(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestUpdateConfigBodyLimits(t *testing.T) {
+	twin := New(DefaultConfig(Description{Name: "twin"}))
+	if err := twin.UpdateConfig(map[string]any{"max_body_kb": 64.0, "body_limits": map[string]any{"/v1/files": 1024.0}}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := twin.GetConfig()
+	if cfg["max_body_kb"] != 64 || cfg["body_limits"].(map[string]int)["/v1/files"] != 1024 {
+		t.Errorf("expected the limits applied, got %v and %v", cfg["max_body_kb"], cfg["body_limits"])
+	}
+	for _, bad := range []map[string]any{
+		{"max_body_kb": -1.0},
+		{"max_body_kb": 1.5},
+		{"body_limits": map[string]any{"/admin/state": 1.0}},
+		{"body_limits": map[string]any{"/v1/files": "1MB"}},
+	} {
+		if err := twin.UpdateConfig(bad); err == nil {
+			t.Errorf("UpdateConfig(%v): expected an error", bad)
+		}
+	}
+}
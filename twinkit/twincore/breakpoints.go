@@ -0,0 +1,337 @@
+package twincore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// BreakpointHeader carries the ID of the breakpoint that held a request, on
+// its response.
+const BreakpointHeader = "X-WT-Breakpoint"
+
+// DefaultBreakpointTimeout is how long a breakpoint without a Timeout holds
+// a request.
+const DefaultBreakpointTimeout = 10 * time.Second
+
+// MaxBreakpointTimeout caps a breakpoint's Timeout, leaving a held request
+// time to be handled within the server's 30s write timeout.
+const MaxBreakpointTimeout = 20 * time.Second
+
+// ErrBreakpointNotFound is returned for an unknown breakpoint ID.
+var ErrBreakpointNotFound = errors.New("breakpoint not found")
+
+// Breakpoint pauses the API requests matching Method and Path before the
+// twin handles them, until they are released through
+// POST /admin/breakpoints/{id}/release. Tests use breakpoints to interleave
+// concurrent operations deterministically, for instance holding two claim
+// requests and releasing them together to force a race, instead of relying
+// on sleeps.
+//
+// Path is a request path such as /v1/claims/clm_1/claim, or a prefix ending
+// in * such as /v1/claims/*; an empty Method matches any method. A request
+// still held after Timeout carries on by itself, so a breakpoint a test
+// forgets to release slows the suite down rather than hanging it.
+//
+// ID, CreatedAt, the counters, and Held are kept by the registry and
+// ignored by Add.
+type Breakpoint struct {
+	ID        string        `json:"id"`
+	Method    string        `json:"method,omitempty"`
+	Path      string        `json:"path"`
+	Timeout   string        `json:"timeout,omitempty"` // e.g. "10s"; default DefaultBreakpointTimeout
+	CreatedAt time.Time     `json:"created_at"`
+	Hits      int           `json:"hits"`      // requests the breakpoint has held
+	Released  int           `json:"released"`  // held requests released through the admin API
+	TimedOut  int           `json:"timed_out"` // held requests that carried on after Timeout
+	Held      []HeldRequest `json:"held"`      // requests held now, oldest first
+
+	timeout time.Duration
+	waiting []*heldRequest
+}
+
+// HeldRequest is a request paused at a breakpoint.
+type HeldRequest struct {
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Since     time.Time `json:"since"`
+}
+
+type heldRequest struct {
+	HeldRequest
+	release chan struct{}
+}
+
+// BreakpointRelease selects which held requests a release lets go: the one
+// with RequestID, or the Count oldest (all of them when Count is 0). With
+// WaitFor, the release first waits, up to the breakpoint's Timeout, until at
+// least that many requests are held, so a test can start its concurrent
+// requests and release them together without polling.
+type BreakpointRelease struct {
+	Count     int    `json:"count,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	WaitFor   int    `json:"wait_for,omitempty"`
+}
+
+// BreakpointRegistry holds the breakpoints set through /admin/breakpoints.
+type BreakpointRegistry struct {
+	mu      sync.Mutex
+	points  map[string]*Breakpoint
+	nextID  int
+	changed chan struct{} // closed and replaced whenever a request is held
+}
+
+// NewBreakpointRegistry creates an empty breakpoint registry.
+func NewBreakpointRegistry() *BreakpointRegistry {
+	return &BreakpointRegistry{
+		points:  map[string]*Breakpoint{},
+		changed: make(chan struct{}),
+	}
+}
+
+// Add sets a breakpoint and returns it with its ID. It returns an error,
+// and sets nothing, if the path or timeout is invalid.
+func (br *BreakpointRegistry) Add(bp Breakpoint) (Breakpoint, error) {
+	if !strings.HasPrefix(bp.Path, "/") {
+		return Breakpoint{}, fmt.Errorf("path must start with /, got %q", bp.Path)
+	}
+	if strings.HasPrefix(bp.Path, "/admin/") || bp.Path == healthzPath {
+		return Breakpoint{}, fmt.Errorf("admin and health requests cannot be held")
+	}
+	bp.Method = strings.ToUpper(bp.Method)
+	bp.timeout = DefaultBreakpointTimeout
+	if bp.Timeout != "" {
+		d, err := time.ParseDuration(bp.Timeout)
+		if err != nil || d <= 0 || d > MaxBreakpointTimeout {
+			return Breakpoint{}, fmt.Errorf("timeout must be a positive duration of at most %s, like \"10s\", got %q", MaxBreakpointTimeout, bp.Timeout)
+		}
+		bp.timeout = d
+	}
+
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.nextID++
+	bp.ID = fmt.Sprintf("bp_%06d", br.nextID)
+	bp.CreatedAt = time.Now()
+	bp.Hits, bp.Released, bp.TimedOut, bp.Held, bp.waiting = 0, 0, 0, nil, nil
+	br.points[bp.ID] = &bp
+	return bp.snapshot(), nil
+}
+
+// All returns the breakpoints, oldest first.
+func (br *BreakpointRegistry) All() []Breakpoint {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	out := make([]Breakpoint, 0, len(br.points))
+	for _, bp := range br.points {
+		out = append(out, bp.snapshot())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Release lets held requests at the breakpoint carry on and returns how
+// many it released. It returns ErrBreakpointNotFound for an unknown ID, and
+// an error without releasing anything if WaitFor requests are not held in
+// time or no held request has RequestID.
+func (br *BreakpointRegistry) Release(ctx context.Context, id string, rel BreakpointRelease) (int, error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	bp, ok := br.points[id]
+	if !ok {
+		return 0, ErrBreakpointNotFound
+	}
+
+	if rel.WaitFor > 0 {
+		deadline := time.NewTimer(bp.timeout)
+		defer deadline.Stop()
+		for expired := false; len(bp.waiting) < rel.WaitFor; {
+			if expired {
+				return 0, fmt.Errorf("only %d of %d requests were held within %s", len(bp.waiting), rel.WaitFor, bp.timeout)
+			}
+			changed := br.changed
+			br.mu.Unlock()
+			select {
+			case <-changed:
+			case <-deadline.C:
+				expired = true
+			case <-ctx.Done():
+			}
+			br.mu.Lock()
+			if br.points[id] != bp {
+				return 0, ErrBreakpointNotFound
+			}
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+		}
+	}
+
+	var release []*heldRequest
+	switch {
+	case rel.RequestID != "":
+		for _, h := range bp.waiting {
+			if h.RequestID == rel.RequestID {
+				release = append(release, h)
+			}
+		}
+		if len(release) == 0 {
+			return 0, fmt.Errorf("request %s is not held at breakpoint %s", rel.RequestID, id)
+		}
+	case rel.Count > 0 && rel.Count < len(bp.waiting):
+		release = bp.waiting[:rel.Count]
+	default:
+		release = bp.waiting
+	}
+	for _, h := range release {
+		bp.unhold(h)
+		close(h.release)
+	}
+	bp.Released += len(release)
+	return len(release), nil
+}
+
+// Remove deletes a breakpoint, releasing the requests it holds.
+func (br *BreakpointRegistry) Remove(id string) bool {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	bp, ok := br.points[id]
+	if !ok {
+		return false
+	}
+	bp.releaseAll()
+	delete(br.points, id)
+	return true
+}
+
+// Reset deletes every breakpoint, releasing the requests they hold, and
+// restarts breakpoint IDs.
+func (br *BreakpointRegistry) Reset() {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	for _, bp := range br.points {
+		bp.releaseAll()
+	}
+	br.points = map[string]*Breakpoint{}
+	br.nextID = 0
+}
+
+// Hold pauses r if a breakpoint matches it, until the request is released,
+// the breakpoint's timeout passes, or the client goes away. It returns the
+// ID of the breakpoint that held the request, or "" if none matched.
+func (br *BreakpointRegistry) Hold(r *http.Request) string {
+	br.mu.Lock()
+	bp := br.matchLocked(r)
+	if bp == nil {
+		br.mu.Unlock()
+		return ""
+	}
+	h := &heldRequest{
+		HeldRequest: HeldRequest{
+			RequestID: chimw.GetReqID(r.Context()),
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Since:     time.Now(),
+		},
+		release: make(chan struct{}),
+	}
+	bp.waiting = append(bp.waiting, h)
+	bp.Hits++
+	close(br.changed)
+	br.changed = make(chan struct{})
+	timeout := bp.timeout
+	br.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-h.release:
+		return bp.ID
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	if bp.unhold(h) && r.Context().Err() == nil {
+		bp.TimedOut++
+	}
+	return bp.ID
+}
+
+// matchLocked returns the oldest breakpoint matching r. Callers hold br.mu.
+func (br *BreakpointRegistry) matchLocked(r *http.Request) *Breakpoint {
+	var match *Breakpoint
+	for _, bp := range br.points {
+		if matchesRequest(bp.Method, bp.Path, r) && (match == nil || bp.ID < match.ID) {
+			match = bp
+		}
+	}
+	return match
+}
+
+// matchesRequest reports whether r has method (any, when empty) and path,
+// a request path or a prefix ending in *.
+func matchesRequest(method, path string, r *http.Request) bool {
+	if method != "" && method != r.Method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(path, "*"); ok {
+		return strings.HasPrefix(r.URL.Path, prefix)
+	}
+	return path == r.URL.Path
+}
+
+// unhold removes h from the held requests, reporting whether it was held.
+func (bp *Breakpoint) unhold(h *heldRequest) bool {
+	for i, w := range bp.waiting {
+		if w == h {
+			bp.waiting = append(bp.waiting[:i:i], bp.waiting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (bp *Breakpoint) releaseAll() {
+	for _, h := range bp.waiting {
+		close(h.release)
+	}
+	bp.Released += len(bp.waiting)
+	bp.waiting = nil
+}
+
+func (bp *Breakpoint) snapshot() Breakpoint {
+	out := *bp
+	out.waiting = nil
+	out.Held = make([]HeldRequest, len(bp.waiting))
+	for i, h := range bp.waiting {
+		out.Held[i] = h.HeldRequest
+	}
+	return out
+}
+
+// PauseAtBreakpoints holds API requests matching a breakpoint set through
+// /admin/breakpoints until they are released. Responses to held requests
+// carry BreakpointHeader. Admin and health requests are never held.
+func (m *Middleware) PauseAtBreakpoints(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/admin/") && r.URL.Path != healthzPath {
+			if id := m.Breakpoints.Hold(r); id != "" {
+				if r.Context().Err() != nil {
+					return
+				}
+				w.Header().Set(BreakpointHeader, id)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
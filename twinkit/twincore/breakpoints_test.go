@@ -0,0 +1,172 @@
+package twincore
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// breakpointHandler returns a handler behind PauseAtBreakpoints and a func
+// listing the X-Name of the requests it has handled, in order.
+func breakpointHandler() (*Middleware, http.Handler, func() []string) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	var mu sync.Mutex
+	var handled []string
+	h := mw.PauseAtBreakpoints(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		handled = append(handled, r.Header.Get("X-Name"))
+		mu.Unlock()
+	}))
+	return mw, h, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), handled...)
+	}
+}
+
+// waitHeld waits until n requests are held at the registry's first breakpoint.
+func waitHeld(br *BreakpointRegistry, n int) {
+	for len(br.All()[0].Held) < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func serveNamed(h http.Handler, method, path, name string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("X-Name", name)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBreakpointValidation(t *testing.T) {
+	br := NewBreakpointRegistry()
+	for _, bp := range []Breakpoint{
+		{Path: "v1/claims"},
+		{Path: "/admin/reset"},
+		{Path: "/v1/claims", Timeout: "soon"},
+		{Path: "/v1/claims", Timeout: "1m"},
+	} {
+		if _, err := br.Add(bp); err == nil {
+			t.Errorf("%+v: expected an error", bp)
+		}
+	}
+	bp, err := br.Add(Breakpoint{Method: "post", Path: "/v1/claims/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bp.ID != "bp_000001" || bp.Method != "POST" || len(br.All()) != 1 {
+		t.Errorf("unexpected breakpoint %+v", bp)
+	}
+}
+
+func TestBreakpointReleasesHeldRequestsTogether(t *testing.T) {
+	mw, h, handled := breakpointHandler()
+	bp, _ := mw.Breakpoints.Add(Breakpoint{Method: "POST", Path: "/v1/claims/*"})
+
+	var wg sync.WaitGroup
+	recs := make([]*httptest.ResponseRecorder, 2)
+	for i, name := range []string{"a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recs[i] = serveNamed(h, "POST", "/v1/claims/clm_1/claim", name)
+		}()
+	}
+	if rec := serveNamed(h, "GET", "/v1/claims/clm_1", "get"); rec.Header().Get(BreakpointHeader) != "" {
+		t.Error("expected a request of another method not to be held")
+	}
+
+	n, err := mw.Breakpoints.Release(context.Background(), bp.ID, BreakpointRelease{WaitFor: 2})
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 requests released, got %d, %v", n, err)
+	}
+	wg.Wait()
+	if len(handled()) != 3 {
+		t.Errorf("expected every request handled, got %v", handled())
+	}
+	for _, rec := range recs {
+		if rec.Header().Get(BreakpointHeader) != bp.ID {
+			t.Errorf("expected %s=%s, got %q", BreakpointHeader, bp.ID, rec.Header().Get(BreakpointHeader))
+		}
+	}
+	if got := mw.Breakpoints.All()[0]; got.Hits != 2 || got.Released != 2 || len(got.Held) != 0 {
+		t.Errorf("unexpected counters %+v", got)
+	}
+}
+
+func TestBreakpointReleasesInOrder(t *testing.T) {
+	mw, h, handled := breakpointHandler()
+	bp, _ := mw.Breakpoints.Add(Breakpoint{Path: "/v1/claims"})
+
+	var wg sync.WaitGroup
+	for i, name := range []string{"first", "second"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serveNamed(h, "POST", "/v1/claims", name)
+		}()
+		// Hold the first before sending the second, so the order is known.
+		waitHeld(mw.Breakpoints, i+1)
+	}
+	if _, err := mw.Breakpoints.Release(context.Background(), bp.ID, BreakpointRelease{RequestID: "none"}); err == nil {
+		t.Fatal("expected releasing a request that is not held to fail")
+	}
+
+	for i := 1; i <= 2; i++ {
+		if n, err := mw.Breakpoints.Release(context.Background(), bp.ID, BreakpointRelease{Count: 1}); err != nil || n != 1 {
+			t.Fatalf("expected one request released, got %d, %v", n, err)
+		}
+		for len(handled()) < i {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	wg.Wait()
+	if strings.Join(handled(), ",") != "first,second" {
+		t.Errorf("expected requests handled in the order they were held, got %v", handled())
+	}
+}
+
+func TestBreakpointTimeout(t *testing.T) {
+	mw, h, handled := breakpointHandler()
+	bp, _ := mw.Breakpoints.Add(Breakpoint{Path: "/v1/claims", Timeout: "20ms"})
+
+	serveNamed(h, "POST", "/v1/claims", "late")
+	if len(handled()) != 1 {
+		t.Fatal("expected the request to carry on after the timeout")
+	}
+	if got := mw.Breakpoints.All()[0]; got.TimedOut != 1 || got.Released != 0 {
+		t.Errorf("unexpected counters %+v", got)
+	}
+
+	_, err := mw.Breakpoints.Release(context.Background(), bp.ID, BreakpointRelease{WaitFor: 1})
+	if err == nil || !strings.Contains(err.Error(), "only 0 of 1") {
+		t.Errorf("expected wait_for to time out, got %v", err)
+	}
+	if _, err := mw.Breakpoints.Release(context.Background(), "bp_404", BreakpointRelease{}); !errors.Is(err, ErrBreakpointNotFound) {
+		t.Errorf("expected ErrBreakpointNotFound, got %v", err)
+	}
+}
+
+func TestBreakpointResetReleasesHeldRequests(t *testing.T) {
+	mw, h, handled := breakpointHandler()
+	mw.Breakpoints.Add(Breakpoint{Path: "/v1/claims"})
+
+	done := make(chan struct{})
+	go func() {
+		serveNamed(h, "POST", "/v1/claims", "held")
+		close(done)
+	}()
+	waitHeld(mw.Breakpoints, 1)
+	mw.Breakpoints.Reset()
+	<-done
+	if len(handled()) != 1 || len(mw.Breakpoints.All()) != 0 {
+		t.Errorf("expected the held request released and the breakpoint gone, got %v, %v", handled(), mw.Breakpoints.All())
+	}
+}
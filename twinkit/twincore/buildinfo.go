@@ -0,0 +1,75 @@
+package twincore
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// twinkitModule is the module path used to look up the twinkit version in
+// the binary's embedded build info.
+const twinkitModule = "github.com/wondertwin-ai/wondertwin/twinkit"
+
+// BuildInfo describes the build a running twin came from. It is served by
+// GET /admin/version so mixed-version fleets can be diagnosed.
+type BuildInfo struct {
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Commit         string `json:"commit"`
+	BuildDate      string `json:"build_date"`
+	TwinkitVersion string `json:"twinkit_version"`
+	GoVersion      string `json:"go_version"`
+}
+
+// ReadBuildInfo returns the build metadata for the named twin. Values set via
+// ldflags win; otherwise the commit, build date, and twinkit version are
+// read from the build info the Go toolchain embeds, and default to
+// "unknown" (or "dev" for twinkit) when that is unavailable too.
+func ReadBuildInfo(name string) BuildInfo {
+	info := BuildInfo{
+		Name:           name,
+		Version:        Version,
+		Commit:         Commit,
+		BuildDate:      BuildDate,
+		TwinkitVersion: "dev",
+		GoVersion:      runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if bi.Main.Path == twinkitModule {
+			info.TwinkitVersion = moduleVersion(bi.Main)
+		}
+		for _, dep := range bi.Deps {
+			if dep.Path == twinkitModule {
+				info.TwinkitVersion = moduleVersion(*dep)
+			}
+		}
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = s.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+// moduleVersion returns a module's version, following replace directives and
+// mapping the workspace placeholder "(devel)" to "dev".
+func moduleVersion(m debug.Module) string {
+	if m.Replace != nil {
+		m = *m.Replace
+	}
+	if m.Version == "" || m.Version == "(devel)" {
+		return "dev"
+	}
+	return m.Version
+}
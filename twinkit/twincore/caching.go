@@ -0,0 +1,237 @@
+package twincore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QuirkStaleCache replays the first GET response for a URL for
+// Config.CacheStale, however the record changes in the meantime, as when a
+// CDN in front of the provider serves stale representations. Conditional
+// requests are answered against the replayed representation's ETag.
+const QuirkStaleCache = "WT-Q-008"
+
+// defaultCacheStale is how long QuirkStaleCache replays a response when
+// Config.CacheStale is unset.
+const defaultCacheStale = 30 * time.Second
+
+// maxCachedResponses bounds the representations QuirkStaleCache holds.
+const maxCachedResponses = 1000
+
+// ETag returns a strong entity tag for v: a hash of its JSON encoding, or
+// of v itself when it is a []byte. Records that encode identically get the
+// same tag, so a tag changes exactly when a client would see a different
+// body.
+func ETag(v any) string {
+	b, ok := v.([]byte)
+	if !ok {
+		var err error
+		if b, err = json.Marshal(v); err != nil {
+			b = []byte(err.Error())
+		}
+	}
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// WeakETag returns ETag(v) marked weak (W/"..."), for representations that
+// are equivalent but not byte-for-byte identical across requests, such as
+// lists whose timestamps are rendered at request time.
+func WeakETag(v any) string {
+	return "W/" + ETag(v)
+}
+
+// NotModified sets the ETag header and, when a GET or HEAD request's
+// If-None-Match matches etag, answers 304 Not Modified and returns true.
+// Handlers call it before writing the body:
+//
+//	if twincore.NotModified(w, r, twincore.ETag(acct)) {
+//		return
+//	}
+//	twincore.JSON(w, http.StatusOK, acct)
+//
+// Matching uses the weak comparison RFC 9110 specifies for If-None-Match,
+// so W/"x" matches "x".
+func NotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag.
+func etagMatches(header, etag string) bool {
+	if header == "" || etag == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// cachedResponse is a representation QuirkStaleCache replays.
+type cachedResponse struct {
+	header http.Header
+	body   []byte
+	stored time.Time
+}
+
+// ResponseCache holds the representations QuirkStaleCache replays, keyed
+// by URL and credentials.
+type ResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+// NewResponseCache creates an empty cache.
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{entries: make(map[string]cachedResponse)}
+}
+
+// Clear drops every cached representation.
+func (c *ResponseCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// get returns the representation cached under key, unless it is older than
+// ttl.
+func (c *ResponseCache) get(key string, now time.Time, ttl time.Duration) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if ok && now.Sub(e.stored) >= ttl {
+		delete(c.entries, key)
+		return cachedResponse{}, false
+	}
+	return e, ok
+}
+
+// put caches a representation, first dropping expired ones if the cache
+// is full.
+func (c *ResponseCache) put(key string, e cachedResponse, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedResponses {
+		for k, old := range c.entries {
+			if e.stored.Sub(old.stored) >= ttl {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCachedResponses {
+			return
+		}
+	}
+	c.entries[key] = e
+}
+
+// HTTPCache emulates HTTP caching in front of the twin's API. GET and HEAD
+// responses that set no Cache-Control of their own get Config.CacheControl
+// (--cache-control, or cache_control in /admin/config), if any. While
+// QuirkStaleCache is on, the first successful GET for a URL and
+// credentials is replayed, with an Age header, until Config.CacheStale has
+// passed. Admin endpoints are never affected.
+func (m *Middleware) HTTPCache(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/admin/") || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cacheControl := m.cfg.CacheControl
+		if !m.Quirks.IsEnabled(QuirkStaleCache) {
+			if cacheControl == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(&cacheControlWriter{ResponseWriter: w, value: cacheControl}, r)
+			return
+		}
+
+		key := r.URL.RequestURI() + "\x00" + r.Header.Get("Authorization")
+		ttl := m.cfg.cacheStale()
+		now := time.Now()
+		if e, ok := m.Cache.get(key, now, ttl); ok {
+			for k, v := range e.header {
+				w.Header()[k] = v
+			}
+			w.Header().Set("Age", strconv.Itoa(int(now.Sub(e.stored).Seconds())))
+			if NotModified(w, r, e.header.Get("ETag")) {
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			if r.Method != http.MethodHead {
+				w.Write(e.body)
+			}
+			return
+		}
+
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if cacheControl != "" && rec.header.Get("Cache-Control") == "" {
+			rec.header.Set("Cache-Control", cacheControl)
+		}
+		if rec.status == http.StatusOK && r.Method == http.MethodGet {
+			m.Cache.put(key, cachedResponse{header: rec.header.Clone(), body: rec.body.Bytes(), stored: now}, ttl)
+		}
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes())
+	})
+}
+
+// cacheControlWriter sets a default Cache-Control on a response that did
+// not set one.
+type cacheControlWriter struct {
+	http.ResponseWriter
+	value       string
+	wroteHeader bool
+}
+
+func (cw *cacheControlWriter) WriteHeader(code int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if cw.Header().Get("Cache-Control") == "" {
+			cw.Header().Set("Cache-Control", cw.value)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *cacheControlWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// cacheStale returns how long QuirkStaleCache replays a response.
+func (c *Config) cacheStale() time.Duration {
+	if c.CacheStale > 0 {
+		return c.CacheStale
+	}
+	return defaultCacheStale
+}
@@ -0,0 +1,146 @@
+package twincore
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestETag(t *testing.T) {
+	type account struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	}
+	a := ETag(account{ID: "acct_1", Email: "a@example.com"})
+	if !strings.HasPrefix(a, `"`) || !strings.HasSuffix(a, `"`) {
+		t.Errorf("expected a quoted strong tag, got %s", a)
+	}
+	if ETag(account{ID: "acct_1", Email: "a@example.com"}) != a {
+		t.Error("expected equal records to get equal tags")
+	}
+	if ETag(account{ID: "acct_1", Email: "b@example.com"}) == a {
+		t.Error("expected a changed record to get a new tag")
+	}
+	if ETag([]byte(`{"id":"acct_1","email":"a@example.com"}`)) != a {
+		t.Error("expected a []byte to be hashed as its JSON encoding would be")
+	}
+	if w := WeakETag(account{ID: "acct_1"}); !strings.HasPrefix(w, `W/"`) {
+		t.Errorf("expected a weak tag, got %s", w)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	etag := ETag(map[string]string{"id": "acct_1"})
+	tests := []struct {
+		method, ifNoneMatch string
+		want                bool
+	}{
+		{"GET", "", false},
+		{"GET", etag, true},
+		{"HEAD", etag, true},
+		{"GET", "W/" + etag, true},
+		{"GET", `"other", ` + etag, true},
+		{"GET", "*", true},
+		{"GET", `"other"`, false},
+		{"POST", etag, false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/v1/accounts/acct_1", nil)
+		if tt.ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		got := NotModified(rec, r, etag)
+		if got != tt.want {
+			t.Errorf("%s If-None-Match %q: expected %v, got %v", tt.method, tt.ifNoneMatch, tt.want, got)
+		}
+		if got && rec.Code != http.StatusNotModified {
+			t.Errorf("%s If-None-Match %q: expected 304, got %d", tt.method, tt.ifNoneMatch, rec.Code)
+		}
+		if rec.Header().Get("ETag") != etag {
+			t.Errorf("expected the ETag header set, got %q", rec.Header().Get("ETag"))
+		}
+	}
+}
+
+// cachedTwin serves a record that PUT replaces through HTTPCache.
+func cachedTwin(cfg *Config) (*Middleware, http.Handler) {
+	mw := NewMiddleware(cfg, slog.Default())
+	email := "a@example.com"
+	return mw, mw.HTTPCache(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			email = r.URL.Query().Get("email")
+			return
+		}
+		acct := map[string]string{"id": "acct_1", "email": email}
+		if NotModified(w, r, ETag(acct)) {
+			return
+		}
+		JSON(w, http.StatusOK, acct)
+	}))
+}
+
+func get(h http.Handler, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", path, nil)
+	if ifNoneMatch != "" {
+		r.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestHTTPCacheControl(t *testing.T) {
+	_, h := cachedTwin(&Config{})
+	if cc := get(h, "/v1/accounts/acct_1", "").Header().Get("Cache-Control"); cc != "" {
+		t.Errorf("expected no Cache-Control by default, got %q", cc)
+	}
+
+	_, h = cachedTwin(&Config{CacheControl: "private, max-age=60"})
+	rec := get(h, "/v1/accounts/acct_1", "")
+	if cc := rec.Header().Get("Cache-Control"); cc != "private, max-age=60" {
+		t.Errorf("expected the configured Cache-Control, got %q", cc)
+	}
+	rec = get(h, "/v1/accounts/acct_1", rec.Header().Get("ETag"))
+	if rec.Code != http.StatusNotModified || rec.Header().Get("Cache-Control") == "" {
+		t.Errorf("expected a 304 with Cache-Control, got %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestQuirkStaleCache(t *testing.T) {
+	mw, h := cachedTwin(&Config{CacheStale: time.Hour})
+	if err := mw.Quirks.EnableQuirk(QuirkStaleCache); err != nil {
+		t.Fatal(err)
+	}
+	first := get(h, "/v1/accounts/acct_1", "")
+	etag := first.Header().Get("ETag")
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/v1/accounts/acct_1?email=b@example.com", nil))
+
+	stale := get(h, "/v1/accounts/acct_1", "")
+	if !strings.Contains(stale.Body.String(), "a@example.com") || stale.Header().Get("Age") == "" {
+		t.Errorf("expected the stale representation with an Age, got %q %v", stale.Body.String(), stale.Header())
+	}
+	if rec := get(h, "/v1/accounts/acct_1", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("expected the stale ETag to revalidate, got %d", rec.Code)
+	}
+	if rec := get(h, "/v1/accounts/acct_1?expand=x", ""); !strings.Contains(rec.Body.String(), "b@example.com") {
+		t.Errorf("expected another URL to see the change, got %q", rec.Body.String())
+	}
+
+	mw.Cache.Clear()
+	if rec := get(h, "/v1/accounts/acct_1", ""); !strings.Contains(rec.Body.String(), "b@example.com") {
+		t.Errorf("expected a fresh representation after clearing, got %q", rec.Body.String())
+	}
+	mw.Quirks.DisableQuirk(QuirkStaleCache)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/v1/accounts/acct_1?email=c@example.com", nil))
+	if rec := get(h, "/v1/accounts/acct_1", ""); !strings.Contains(rec.Body.String(), "c@example.com") {
+		t.Errorf("expected no caching with the quirk off, got %q", rec.Body.String())
+	}
+	if rec := get(h, "/admin/state", ""); rec.Header().Get("Age") != "" {
+		t.Error("expected admin endpoints never cached")
+	}
+}
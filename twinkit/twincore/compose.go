@@ -0,0 +1,192 @@
+package twincore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Group hosts several twins in one process, to save the per-process
+// overhead of running each twin on its own in small CI containers. Every
+// twin keeps its own router, stores, admin plane, clock, and config; the
+// group only shares the process and, for twins mounted under a path
+// prefix, a listener.
+//
+// A twin added without a prefix listens on its own Config.Port, Host, or
+// Socket, exactly as Serve would, so wt and SDKs address it as usual. A
+// twin added with a prefix such as "/stripe" is served on the group's Port
+// with the prefix stripped, so its API and admin plane are at /stripe/v1/... and
+// /stripe/admin/...; GET /healthz on the shared port answers for the
+// group as a whole.
+type Group struct {
+	// Port is the shared listen port of prefix-mounted twins.
+	Port   int
+	Logger *slog.Logger
+
+	twins    []*Twin
+	prefixes map[string]*Twin // prefix → twin; empty for port-mounted twins
+	draining atomic.Bool
+}
+
+// NewGroup returns an empty Group whose prefix-mounted twins share port.
+func NewGroup(port int, logger *slog.Logger) *Group {
+	return &Group{Port: port, Logger: logger, prefixes: map[string]*Twin{}}
+}
+
+// Add mounts t on its own Config.Port or Socket when prefix is empty, or under
+// prefix on the group's Port. It rejects a prefix or port already in use.
+func (g *Group) Add(t *Twin, prefix string) error {
+	if prefix != "" {
+		if !strings.HasPrefix(prefix, "/") || strings.HasSuffix(prefix, "/") || prefix == healthzPath {
+			return fmt.Errorf("%s: prefix must start with / and not end with one, like /stripe; got %q", t.Config.Name, prefix)
+		}
+		for p := range g.prefixes {
+			if strings.HasPrefix(p+"/", prefix+"/") || strings.HasPrefix(prefix+"/", p+"/") {
+				return fmt.Errorf("%s: prefix %s overlaps %s, already mounted for %s", t.Config.Name, prefix, p, g.prefixes[p].Config.Name)
+			}
+		}
+		g.prefixes[prefix] = t
+		g.twins = append(g.twins, t)
+		return nil
+	}
+	if t.Config.Socket != "" {
+		g.twins = append(g.twins, t)
+		return nil
+	}
+	if t.Config.Port == 0 {
+		return fmt.Errorf("%s: a twin mounted on its own port needs a port", t.Config.Name)
+	}
+	if t.Config.Port == g.Port {
+		return fmt.Errorf("%s: port %d is the group's shared port", t.Config.Name, t.Config.Port)
+	}
+	for _, other := range g.twins {
+		if g.prefixOf(other) == "" && other.Config.Socket == "" && other.Config.Port == t.Config.Port {
+			return fmt.Errorf("%s: port %d is already used by %s", t.Config.Name, t.Config.Port, other.Config.Name)
+		}
+	}
+	g.twins = append(g.twins, t)
+	return nil
+}
+
+func (g *Group) prefixOf(t *Twin) string {
+	for p, pt := range g.prefixes {
+		if pt == t {
+			return p
+		}
+	}
+	return ""
+}
+
+// ServeHTTP dispatches requests on the shared port to the prefix-mounted
+// twin whose prefix matches, longest first.
+func (g *Group) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == healthzPath && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		if g.draining.Load() {
+			JSON(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting_down"})
+			return
+		}
+		JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		return
+	}
+	prefixes := g.sortedPrefixes()
+	for _, p := range prefixes {
+		if r.URL.Path == p || strings.HasPrefix(r.URL.Path, p+"/") {
+			http.StripPrefix(p, g.prefixes[p]).ServeHTTP(w, r)
+			return
+		}
+	}
+	Error(w, http.StatusNotFound, fmt.Sprintf("no twin is mounted at %s; mounted prefixes: %s", r.URL.Path, strings.Join(prefixes, ", ")))
+}
+
+func (g *Group) sortedPrefixes() []string {
+	prefixes := make([]string, 0, len(g.prefixes))
+	for p := range g.prefixes {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if len(prefixes[i]) != len(prefixes[j]) {
+			return len(prefixes[i]) > len(prefixes[j])
+		}
+		return prefixes[i] < prefixes[j]
+	})
+	return prefixes
+}
+
+// Serve listens on every port the group needs and blocks until a shutdown
+// signal, then drains all twins together. It fails before serving anything
+// if a port cannot be bound.
+func (g *Group) Serve() error {
+	var servers []*http.Server
+	var listeners []net.Listener
+	closeAll := func() {
+		for _, ln := range listeners {
+			ln.Close()
+		}
+	}
+	serve := func(ln net.Listener, h http.Handler, what string) {
+		servers = append(servers, newServer(ln.Addr().String(), h))
+		listeners = append(listeners, ln)
+		g.Logger.Info("starting twin group listener", "for", what, "addr", ln.Addr().String())
+	}
+
+	for _, t := range g.twins {
+		if g.prefixOf(t) == "" {
+			ln, err := t.Config.Listen()
+			if err != nil {
+				closeAll()
+				return fmt.Errorf("%s: %w", t.Config.Name, err)
+			}
+			serve(ln, t, t.Config.Name)
+		}
+	}
+	if len(g.prefixes) > 0 {
+		ln, err := net.Listen("tcp", fmt.Sprintf(":%d", g.Port))
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("prefixes %s: %w", strings.Join(g.sortedPrefixes(), ", "), err)
+		}
+		serve(ln, g, "prefixes "+strings.Join(g.sortedPrefixes(), ", "))
+	}
+
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
+
+	failed := make(chan error, len(servers))
+	for i, srv := range servers {
+		go func() {
+			if err := srv.Serve(listeners[i]); err != nil && err != http.ErrServerClosed {
+				failed <- err
+			}
+		}()
+	}
+
+	var serveErr error
+	select {
+	case <-done:
+	case serveErr = <-failed:
+		g.Logger.Error("server error", "err", serveErr)
+	}
+	g.Logger.Info("shutting down twin group", "twins", len(g.twins))
+	g.draining.Store(true)
+	for _, t := range g.twins {
+		t.draining.Store(true)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	errs := []error{serveErr}
+	for _, srv := range servers {
+		errs = append(errs, srv.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,91 @@
+package twincore
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := DefaultConfig(Description{Name: "twin-stripe", DefaultPort: 4111})
+	if cfg.Name != "twin-stripe" || cfg.Port != 4111 || cfg.WebhookDelivery != "at-most-once" ||
+		cfg.StoreLimitPolicy != "reject" || cfg.ListLag != defaultListLag || cfg.CacheStale != defaultCacheStale {
+		t.Errorf("expected the flag defaults, got %+v", cfg)
+	}
+}
+
+func TestGroupPrefixes(t *testing.T) {
+	newTwin := func(name string) *Twin {
+		tw := New(DefaultConfig(Description{Name: name}))
+		tw.Router.Get("/v1/whoami", func(w http.ResponseWriter, r *http.Request) {
+			JSON(w, http.StatusOK, map[string]string{"twin": name, "path": r.URL.Path})
+		})
+		return tw
+	}
+	g := NewGroup(9000, slog.Default())
+	if err := g.Add(newTwin("twin-stripe"), "/stripe"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add(newTwin("twin-stripe-eu"), "/stripe-eu"); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"/stripe/v1/whoami":    `"twin":"twin-stripe"`,
+		"/stripe-eu/v1/whoami": `"twin":"twin-stripe-eu"`,
+		"/stripe/healthz":      `"status":"ok"`,
+		"/healthz":             `"status":"ok"`,
+	} {
+		rec := httptest.NewRecorder()
+		g.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("GET %s: expected 200 with %s, got %d %s", path, want, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, httptest.NewRequest("GET", "/resend/emails", nil))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "/stripe-eu, /stripe") {
+		t.Errorf("expected a 404 listing the mounted prefixes, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	g.draining.Store(true)
+	rec = httptest.NewRecorder()
+	g.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while draining, got %d", rec.Code)
+	}
+}
+
+func TestGroupAddRejectsConflicts(t *testing.T) {
+	g := NewGroup(9000, slog.Default())
+	twin := func(port int) *Twin {
+		cfg := DefaultConfig(Description{Name: "twin"})
+		cfg.Port = port
+		return New(cfg)
+	}
+	if err := g.Add(twin(4111), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add(twin(0), "/stripe"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		port   int
+		prefix string
+	}{
+		{4111, ""},        // port taken
+		{9000, ""},        // the shared port
+		{0, ""},           // no port
+		{0, "/stripe"},    // prefix taken
+		{0, "/stripe/eu"}, // nested under a prefix
+		{0, "stripe"},     // no leading slash
+		{0, "/healthz"},   // the group's probe
+	} {
+		if err := g.Add(twin(tt.port), tt.prefix); err == nil {
+			t.Errorf("Add(port %d, prefix %q): expected an error", tt.port, tt.prefix)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package twincore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compressMinBytes is the smallest response body worth compressing. Real
+// providers compress large list responses but not small objects.
+const compressMinBytes = 1024
+
+// Compression compresses responses of compressMinBytes or more with gzip or
+// br when the client's Accept-Encoding allows it. Set
+// Config.DisableCompression (--disable-compression, or compression=false in
+// /admin/config) to turn it off. With QuirkMislabeledEncoding enabled the
+// Content-Encoding label is swapped to exercise clients that trust it.
+func (m *Middleware) Compression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.cfg.DisableCompression || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		label := encoding
+		if m.Quirks.IsEnabled(QuirkMislabeledEncoding) {
+			label = map[string]string{"gzip": "br", "br": "gzip"}[encoding]
+		}
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, label: label, status: http.StatusOK}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks gzip or br from an Accept-Encoding header, or ""
+// for identity. On equal preference gzip wins, because br bodies are stored
+// rather than compressed (see brotliWriter).
+func negotiateEncoding(header string) string {
+	q := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = f
+			}
+		}
+		switch name = strings.ToLower(name); name {
+		case "gzip", "br":
+			q[name] = weight
+		case "*":
+			for _, enc := range []string{"gzip", "br"} {
+				if _, set := q[enc]; !set {
+					q[enc] = weight
+				}
+			}
+		}
+	}
+	switch {
+	case q["gzip"] > 0 && q["gzip"] >= q["br"]:
+		return "gzip"
+	case q["br"] > 0:
+		return "br"
+	}
+	return ""
+}
+
+// compressibleType reports whether a content type benefits from compression.
+func compressibleType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		mediaType == "application/javascript"
+}
+
+// compressWriter buffers the start of a response until it knows whether the
+// body reaches compressMinBytes, then either compresses or passes through.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string // what the body is encoded with
+	label    string // what Content-Encoding claims
+	status   int
+	buf      []byte
+	decided  bool
+	enc      io.WriteCloser // nil when passing through
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if !cw.decided {
+		cw.status = code
+		return
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.decided {
+		if cw.enc != nil {
+			return cw.enc.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= compressMinBytes {
+		if err := cw.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// decide sends the headers and any buffered body, compressing if the body
+// is large enough and compressible.
+func (cw *compressWriter) decide() error {
+	cw.decided = true
+	h := cw.Header()
+	if h.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	if len(cw.buf) >= compressMinBytes && h.Get("Content-Encoding") == "" &&
+		cw.status != http.StatusNoContent && cw.status != http.StatusNotModified &&
+		compressibleType(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", cw.label)
+		h.Del("Content-Length")
+		if cw.encoding == "br" {
+			cw.enc = newBrotliWriter(cw.ResponseWriter)
+		} else {
+			cw.enc = gzip.NewWriter(cw.ResponseWriter)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := cw.Write(buf)
+	return err
+}
+
+// Close flushes a short buffered body and finishes the compressed stream.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if err := cw.decide(); err != nil {
+			return err
+		}
+	}
+	if cw.enc != nil {
+		return cw.enc.Close()
+	}
+	return nil
+}
+
+// Flush sends what has been written so far. A response flushed before it
+// reaches compressMinBytes is streamed uncompressed.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.decide()
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Hijack lets websocket-style handlers take over the connection.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(cw.ResponseWriter).Hijack()
+}
+
+// brotliBlockSize is the largest meta-block brotliWriter emits.
+const brotliBlockSize = 1 << 16
+
+// brotliWriter writes a valid brotli stream made only of uncompressed
+// meta-blocks (RFC 7932, section 9.2). The standard library has no brotli
+// encoder; stored blocks are not smaller, but every brotli decoder accepts
+// them, which is what exercising a client's br path needs.
+type brotliWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func newBrotliWriter(w io.Writer) *brotliWriter {
+	return &brotliWriter{w: w}
+}
+
+func (bw *brotliWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), brotliBlockSize)
+		var bits bitWriter
+		if !bw.started {
+			bits.write(0, 1) // WBITS = 16
+			bw.started = true
+		}
+		bits.write(0, 1)            // ISLAST
+		bits.write(0, 2)            // MNIBBLES = 4
+		bits.write(uint64(n-1), 16) // MLEN - 1
+		bits.write(1, 1)            // ISUNCOMPRESSED
+		if _, err := bw.w.Write(append(bits.bytes(), p[:n]...)); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Close writes the final empty meta-block.
+func (bw *brotliWriter) Close() error {
+	var bits bitWriter
+	if !bw.started {
+		bits.write(0, 1) // WBITS = 16
+	}
+	bits.write(1, 1) // ISLAST
+	bits.write(1, 1) // ISLASTEMPTY
+	_, err := bw.w.Write(bits.bytes())
+	return err
+}
+
+// bitWriter packs values least-significant bit first, as brotli requires.
+type bitWriter struct {
+	buf   []byte
+	nbits uint
+}
+
+func (b *bitWriter) write(v uint64, n uint) {
+	for i := uint(0); i < n; i++ {
+		if b.nbits%8 == 0 {
+			b.buf = append(b.buf, 0)
+		}
+		if v&(1<<i) != 0 {
+			b.buf[len(b.buf)-1] |= 1 << (b.nbits % 8)
+		}
+		b.nbits++
+	}
+}
+
+// bytes returns the packed bits, zero-padded to a byte boundary.
+func (b *bitWriter) bytes() []byte {
+	return b.buf
+}
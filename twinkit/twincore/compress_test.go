@@ -0,0 +1,199 @@
+package twincore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"br", "br"},
+		{"gzip, deflate, br", "gzip"},
+		{"gzip;q=0.5, br", "br"},
+		{"br;q=0, gzip;q=0", ""},
+		{"*", "gzip"},
+		{"br, *;q=0", "br"},
+		{"GZIP", "gzip"},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+// serveCompressed runs body through the Compression middleware.
+func serveCompressed(mw *Middleware, acceptEncoding, body string) *httptest.ResponseRecorder {
+	handler := mw.Compression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		// Write in pieces so the buffering threshold is crossed mid-response
+		for len(body) > 0 {
+			n := min(len(body), 300)
+			io.WriteString(w, body[:n])
+			body = body[n:]
+		}
+	}))
+	req := httptest.NewRequest("GET", "/v1/things", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func largeJSON() string {
+	return `{"data":[` + strings.Repeat(`{"id":"obj_123","object":"thing"},`, 100) + `{}]}`
+}
+
+func TestCompressionGzip(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	body := largeJSON()
+	rec := serveCompressed(mw, "gzip, br", body)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected status to pass through, got %d", rec.Code)
+	}
+	if ce := rec.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("expected gzip, got %q", ce)
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("expected Vary: Accept-Encoding, got %q", rec.Header().Get("Vary"))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(zr)
+	if string(got) != body {
+		t.Error("decompressed body does not match")
+	}
+}
+
+func TestCompressionBrotli(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	body := largeJSON()
+	rec := serveCompressed(mw, "br", body)
+
+	if ce := rec.Header().Get("Content-Encoding"); ce != "br" {
+		t.Fatalf("expected br, got %q", ce)
+	}
+	got, err := decodeStoredBrotli(rec.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Error("decoded body does not match")
+	}
+}
+
+func TestBrotliWriterLargeAndEmpty(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 15000) // spans three meta-blocks
+	var buf bytes.Buffer
+	bw := newBrotliWriter(&buf)
+	bw.Write(data)
+	bw.Close()
+	got, err := decodeStoredBrotli(buf.Bytes())
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("round trip failed: %v", err)
+	}
+
+	buf.Reset()
+	newBrotliWriter(&buf).Close()
+	if !bytes.Equal(buf.Bytes(), []byte{0x06}) {
+		t.Errorf("expected the canonical empty brotli stream 0x06, got %x", buf.Bytes())
+	}
+}
+
+func TestCompressionSkipsSmallAndUnaccepted(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+
+	rec := serveCompressed(mw, "gzip", `{"id":"obj_123"}`)
+	if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("expected small body uncompressed, got %q", ce)
+	}
+	if rec.Body.String() != `{"id":"obj_123"}` || rec.Code != http.StatusCreated {
+		t.Errorf("unexpected passthrough response %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = serveCompressed(mw, "", largeJSON())
+	if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("expected no compression without Accept-Encoding, got %q", ce)
+	}
+
+	mw.cfg.DisableCompression = true
+	rec = serveCompressed(mw, "gzip", largeJSON())
+	if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("expected no compression when disabled, got %q", ce)
+	}
+}
+
+func TestCompressionMislabeledEncodingQuirk(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	if err := mw.Quirks.EnableQuirk(QuirkMislabeledEncoding); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serveCompressed(mw, "gzip", largeJSON())
+	if ce := rec.Header().Get("Content-Encoding"); ce != "br" {
+		t.Fatalf("expected gzip body labeled br, got %q", ce)
+	}
+	if _, err := gzip.NewReader(rec.Body); err != nil {
+		t.Errorf("expected the body to still be gzip: %v", err)
+	}
+}
+
+// decodeStoredBrotli decodes the stored-block brotli streams brotliWriter
+// produces. It is not a general brotli decoder.
+func decodeStoredBrotli(data []byte) ([]byte, error) {
+	var out []byte
+	pos, bit := 0, uint(0)
+	read := func(n uint) uint64 {
+		var v uint64
+		for i := uint(0); i < n; i++ {
+			if data[pos]&(1<<bit) != 0 {
+				v |= 1 << i
+			}
+			if bit++; bit == 8 {
+				pos, bit = pos+1, 0
+			}
+		}
+		return v
+	}
+	if read(1) != 0 {
+		return nil, io.ErrUnexpectedEOF // only WBITS=16 is produced
+	}
+	for {
+		if read(1) == 1 { // ISLAST
+			if read(1) != 1 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return out, nil
+		}
+		if read(2) != 0 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		n := int(read(16)) + 1
+		if read(1) != 1 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if bit != 0 {
+			pos, bit = pos+1, 0
+		}
+		out = append(out, data[pos:pos+n]...)
+		pos += n
+	}
+}
@@ -0,0 +1,54 @@
+package twincore
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Description is the static metadata a twin binary reports with --describe.
+// It lets wt, the registry publisher, and the conformance runner introspect
+// a binary without starting its server.
+type Description struct {
+	Name         string       `json:"name"`
+	Version      string       `json:"version"`
+	SDKTarget    SDKTarget    `json:"sdk_target"`
+	DefaultPort  int          `json:"default_port,omitempty"`
+	Capabilities []string     `json:"capabilities"`
+	Quirks       []string     `json:"quirks"`
+	Faults       []NamedFault `json:"faults"`
+}
+
+// SDKTarget identifies the SDK (or REST API) a twin is compatible with.
+type SDKTarget struct {
+	Package    string `json:"package"`
+	Version    string `json:"version"`
+	APIVersion string `json:"api_version,omitempty"`
+}
+
+// NamedFault is a ready-made fault a twin advertises for common failure
+// scenarios. Endpoint and Fault map directly onto POST /admin/fault/{endpoint}.
+// The built-in faults (see BuiltinFaults) have no Endpoint and apply to
+// whichever endpoint they are injected on.
+type NamedFault struct {
+	Name        string      `json:"name"`
+	Endpoint    string      `json:"endpoint"`
+	Description string      `json:"description,omitempty"`
+	Fault       FaultConfig `json:"fault"`
+}
+
+// WriteJSON writes the description as indented JSON. Nil lists are written
+// as empty arrays so consumers can rely on every field being present.
+func (d Description) WriteJSON(w io.Writer) error {
+	if d.Capabilities == nil {
+		d.Capabilities = []string{}
+	}
+	if d.Quirks == nil {
+		d.Quirks = []string{}
+	}
+	if d.Faults == nil {
+		d.Faults = []NamedFault{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
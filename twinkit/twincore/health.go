@@ -0,0 +1,165 @@
+package twincore
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Health probe endpoints served by every twin alongside /admin/health, so
+// Kubernetes liveness and readiness probes and service meshes can supervise
+// twins deployed as shared services:
+//
+//   - GET /healthz answers 200 while the twin is serving and 503 once it
+//     has begun shutting down.
+//   - grpc.health.v1.Health/Check implements the gRPC health checking
+//     protocol over cleartext HTTP/2 (h2c), for gRPC probes. The empty
+//     service name and the twin's name are known; any other is NOT_FOUND.
+//
+// Probes are answered before the router, so latency, fail_rate, faults,
+// quirks, and the request log never touch them.
+const (
+	healthzPath     = "/healthz"
+	grpcHealthCheck = "/grpc.health.v1.Health/Check"
+	grpcHealthWatch = "/grpc.health.v1.Health/Watch"
+)
+
+// grpc.health.v1.HealthCheckResponse.ServingStatus values.
+const (
+	grpcServing    = 1
+	grpcNotServing = 2
+)
+
+// gRPC status codes used by the health service.
+const (
+	grpcOK            = 0
+	grpcInvalidArg    = 3
+	grpcNotFound      = 5
+	grpcUnimplemented = 12
+)
+
+// serveHealth answers health probes and reports whether r was one.
+func (t *Twin) serveHealth(w http.ResponseWriter, r *http.Request) bool {
+	switch r.URL.Path {
+	case healthzPath:
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			return false
+		}
+		if t.draining.Load() {
+			JSON(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting_down"})
+			return true
+		}
+		JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		return true
+	case grpcHealthCheck:
+		t.grpcHealthCheck(w, r)
+		return true
+	case grpcHealthWatch:
+		// Streaming Watch is optional in the protocol; probes use Check.
+		grpcTrailersOnly(w, grpcUnimplemented, "Watch is not supported; use Check")
+		return true
+	}
+	return false
+}
+
+func (t *Twin) grpcHealthCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests must POST application/grpc", http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+	if err != nil {
+		grpcTrailersOnly(w, grpcInvalidArg, "reading request: "+err.Error())
+		return
+	}
+	service, ok := decodeHealthCheckRequest(body)
+	if !ok {
+		grpcTrailersOnly(w, grpcInvalidArg, "malformed HealthCheckRequest")
+		return
+	}
+	if service != "" && service != t.Config.Name {
+		grpcTrailersOnly(w, grpcNotFound, "unknown service "+strconv.Quote(service))
+		return
+	}
+
+	status := byte(grpcServing)
+	if t.draining.Load() {
+		status = grpcNotServing
+	}
+	// HealthCheckResponse{status: status}: field 1, varint, in a
+	// length-prefixed, uncompressed gRPC message frame.
+	msg := []byte{0x08, status}
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	frame = append(frame, msg...)
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.WriteHeader(http.StatusOK)
+	w.Write(frame)
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(grpcOK))
+}
+
+// grpcTrailersOnly ends a gRPC call with a status and no message.
+func grpcTrailersOnly(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	w.Header().Set("Grpc-Message", message)
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeHealthCheckRequest extracts the service name from a gRPC-framed
+// grpc.health.v1.HealthCheckRequest. Unknown fields are skipped.
+func decodeHealthCheckRequest(frame []byte) (string, bool) {
+	if len(frame) == 0 {
+		return "", true // some clients send no message for the default service
+	}
+	if len(frame) < 5 || frame[0] != 0 {
+		return "", false // short frame, or compressed, which we never negotiate
+	}
+	n := binary.BigEndian.Uint32(frame[1:5])
+	if uint64(n) != uint64(len(frame)-5) {
+		return "", false
+	}
+	msg := frame[5:]
+
+	var service string
+	for len(msg) > 0 {
+		key, k := binary.Uvarint(msg)
+		if k <= 0 {
+			return "", false
+		}
+		msg = msg[k:]
+		switch key & 7 {
+		case 0: // varint
+			_, k := binary.Uvarint(msg)
+			if k <= 0 {
+				return "", false
+			}
+			msg = msg[k:]
+		case 1: // fixed64
+			if len(msg) < 8 {
+				return "", false
+			}
+			msg = msg[8:]
+		case 2: // length-delimited
+			l, k := binary.Uvarint(msg)
+			if k <= 0 || uint64(len(msg)-k) < l {
+				return "", false
+			}
+			if key>>3 == 1 {
+				service = string(msg[k : k+int(l)])
+			}
+			msg = msg[k+int(l):]
+		case 5: // fixed32
+			if len(msg) < 4 {
+				return "", false
+			}
+			msg = msg[4:]
+		default:
+			return "", false
+		}
+	}
+	return service, true
+}
@@ -0,0 +1,132 @@
+package twincore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newHealthServer(t *testing.T) (*Twin, *httptest.Server, *http.Client) {
+	t.Helper()
+	twin := New(&Config{Name: "twin-test", FailRate: 1})
+	srv := httptest.NewUnstartedServer(twin)
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	// gRPC clients speak HTTP/2 with prior knowledge
+	tr := &http.Transport{Protocols: new(http.Protocols)}
+	tr.Protocols.SetUnencryptedHTTP2(true)
+	t.Cleanup(tr.CloseIdleConnections)
+	return twin, srv, &http.Client{Transport: tr}
+}
+
+// grpcCheck calls grpc.health.v1.Health/Check for service and returns the
+// grpc-status and, on success, the serving status.
+func grpcCheck(t *testing.T, srv *httptest.Server, c *http.Client, service string) (string, byte) {
+	t.Helper()
+	msg := append([]byte{0x0a, byte(len(service))}, service...)
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	frame = append(frame, msg...)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/grpc.health.v1.Health/Check", bytes.NewReader(frame))
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("expected HTTP/2, got %s", resp.Proto)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	status := resp.Header.Get("Grpc-Status")
+	if status == "" {
+		status = resp.Trailer.Get("Grpc-Status")
+	}
+	if status != "0" {
+		return status, 0
+	}
+	if len(body) != 7 || body[5] != 0x08 {
+		t.Fatalf("unexpected HealthCheckResponse frame %x", body)
+	}
+	return status, body[6]
+}
+
+func TestHealthz(t *testing.T) {
+	twin, srv, _ := newHealthServer(t)
+
+	// FailRate 1 fails every routed request; probes must bypass it
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if n := len(twin.Middleware().ReqLog.Entries()); n != 0 {
+		t.Errorf("expected probes to stay out of the request log, got %d entries", n)
+	}
+
+	twin.draining.Store(true)
+	resp, err = http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while shutting down, got %d", resp.StatusCode)
+	}
+}
+
+func TestGRPCHealthCheck(t *testing.T) {
+	twin, srv, c := newHealthServer(t)
+
+	for _, service := range []string{"", "twin-test"} {
+		if code, status := grpcCheck(t, srv, c, service); code != "0" || status != grpcServing {
+			t.Errorf("service %q: expected OK/SERVING, got grpc-status %s, serving status %d", service, code, status)
+		}
+	}
+	if code, _ := grpcCheck(t, srv, c, "other"); code != "5" {
+		t.Errorf("expected NOT_FOUND for an unknown service, got grpc-status %s", code)
+	}
+
+	twin.draining.Store(true)
+	if code, status := grpcCheck(t, srv, c, ""); code != "0" || status != grpcNotServing {
+		t.Errorf("expected NOT_SERVING while shutting down, got grpc-status %s, serving status %d", code, status)
+	}
+}
+
+func TestDecodeHealthCheckRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   []byte
+		service string
+		ok      bool
+	}{
+		{"empty body", nil, "", true},
+		{"empty message", []byte{0, 0, 0, 0, 0}, "", true},
+		{"service", []byte{0, 0, 0, 0, 3, 0x0a, 1, 'x'}, "x", true},
+		{"unknown field skipped", []byte{0, 0, 0, 0, 5, 0x10, 7, 0x0a, 1, 'y'}, "y", true},
+		{"compressed", []byte{1, 0, 0, 0, 0}, "", false},
+		{"length mismatch", []byte{0, 0, 0, 0, 9, 0x0a}, "", false},
+		{"truncated string", []byte{0, 0, 0, 0, 3, 0x0a, 5, 'x'}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, ok := decodeHealthCheckRequest(tt.frame)
+			if service != tt.service || ok != tt.ok {
+				t.Errorf("got (%q, %v), want (%q, %v)", service, ok, tt.service, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package twincore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// InterceptHeader carries the ID of the intercept that held a response,
+// and how the response was decided, such as "ic_000001 edited".
+const InterceptHeader = "X-WT-Intercept"
+
+// DefaultInterceptTimeout is how long an intercept without a Timeout holds
+// a response. Someone reads and decides each one, so it is the longest a
+// response may be held.
+const DefaultInterceptTimeout = MaxBreakpointTimeout
+
+// Intercept decisions.
+const (
+	InterceptApprove = "approve"
+	InterceptEdit    = "edit"
+	InterceptDeny    = "deny"
+)
+
+// ErrInterceptNotFound is returned for an unknown intercept ID.
+var ErrInterceptNotFound = errors.New("intercept not found")
+
+// Intercept holds the responses to API requests matching Method and Path
+// after the twin has handled them, until each is decided through
+// POST /admin/intercepts/{id}/decide: approved as it is, edited, or denied.
+// `wt intercept` uses it to show a developer responses in the terminal
+// before the client under test gets them, like a debugging proxy built
+// into the twin.
+//
+// Path and Method match as for a Breakpoint. A response still held after
+// Timeout is returned as it is.
+//
+// ID, CreatedAt, the counters, and Held are kept by the registry and
+// ignored by Add.
+type Intercept struct {
+	ID        string         `json:"id"`
+	Method    string         `json:"method,omitempty"`
+	Path      string         `json:"path"`
+	Timeout   string         `json:"timeout,omitempty"` // e.g. "20s"; default DefaultInterceptTimeout
+	CreatedAt time.Time      `json:"created_at"`
+	Hits      int            `json:"hits"`      // responses the intercept has held
+	Approved  int            `json:"approved"`  // returned as they were
+	Edited    int            `json:"edited"`    // returned after an edit
+	Denied    int            `json:"denied"`    // replaced with an error
+	TimedOut  int            `json:"timed_out"` // returned as they were after Timeout
+	Held      []HeldResponse `json:"held"`      // responses held now, oldest first
+
+	timeout time.Duration
+	waiting []*heldResponse
+}
+
+// HeldResponse is a response held at an intercept, with the request it
+// answers.
+type HeldResponse struct {
+	RequestID  string            `json:"request_id"`
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Query      string            `json:"query,omitempty"`
+	Since      time.Time         `json:"since"`
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+type heldResponse struct {
+	HeldResponse
+	decided chan InterceptDecision
+}
+
+// InterceptDecision decides a held response: the one with RequestID, or
+// the oldest when it is empty. An edit replaces the status code when
+// StatusCode is set, sets Headers, and replaces the body when Body is set.
+// A deny answers with StatusCode (default 502) and Body, or the twin's
+// error body when Body is unset.
+type InterceptDecision struct {
+	RequestID  string            `json:"request_id,omitempty"`
+	Action     string            `json:"action"` // InterceptApprove, InterceptEdit, or InterceptDeny
+	StatusCode int               `json:"status_code,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       *string           `json:"body,omitempty"`
+}
+
+// Validate reports whether d is a decision Decide accepts.
+func (d InterceptDecision) Validate() error {
+	switch d.Action {
+	case InterceptApprove:
+		if d.StatusCode != 0 || d.Headers != nil || d.Body != nil {
+			return fmt.Errorf("an approval takes no status_code, headers, or body")
+		}
+	case InterceptEdit:
+	case InterceptDeny:
+		if d.Headers != nil {
+			return fmt.Errorf("a denial takes no headers")
+		}
+	default:
+		return fmt.Errorf("action must be %q, %q, or %q, got %q", InterceptApprove, InterceptEdit, InterceptDeny, d.Action)
+	}
+	if d.StatusCode != 0 && (d.StatusCode < 100 || d.StatusCode > 599) {
+		return fmt.Errorf("status_code must be a valid HTTP status, got %d", d.StatusCode)
+	}
+	return nil
+}
+
+// InterceptRegistry holds the intercepts set through /admin/intercepts.
+type InterceptRegistry struct {
+	mu     sync.Mutex
+	points map[string]*Intercept
+	nextID int
+}
+
+// NewInterceptRegistry creates an empty intercept registry.
+func NewInterceptRegistry() *InterceptRegistry {
+	return &InterceptRegistry{points: map[string]*Intercept{}}
+}
+
+// Add sets an intercept and returns it with its ID. It returns an error,
+// and sets nothing, if the path or timeout is invalid.
+func (ir *InterceptRegistry) Add(ic Intercept) (Intercept, error) {
+	if !strings.HasPrefix(ic.Path, "/") {
+		return Intercept{}, fmt.Errorf("path must start with /, got %q", ic.Path)
+	}
+	if strings.HasPrefix(ic.Path, "/admin/") || ic.Path == healthzPath {
+		return Intercept{}, fmt.Errorf("admin and health responses cannot be intercepted")
+	}
+	ic.Method = strings.ToUpper(ic.Method)
+	ic.timeout = DefaultInterceptTimeout
+	if ic.Timeout != "" {
+		d, err := time.ParseDuration(ic.Timeout)
+		if err != nil || d <= 0 || d > DefaultInterceptTimeout {
+			return Intercept{}, fmt.Errorf("timeout must be a positive duration of at most %s, like \"20s\", got %q", DefaultInterceptTimeout, ic.Timeout)
+		}
+		ic.timeout = d
+	}
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	ir.nextID++
+	ic.ID = fmt.Sprintf("ic_%06d", ir.nextID)
+	ic.CreatedAt = time.Now()
+	ic.Hits, ic.Approved, ic.Edited, ic.Denied, ic.TimedOut, ic.Held, ic.waiting = 0, 0, 0, 0, 0, nil, nil
+	ir.points[ic.ID] = &ic
+	return ic.snapshot(), nil
+}
+
+// All returns the intercepts, oldest first.
+func (ir *InterceptRegistry) All() []Intercept {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	out := make([]Intercept, 0, len(ir.points))
+	for _, ic := range ir.points {
+		out = append(out, ic.snapshot())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Decide returns a held response to its client as d says. It returns
+// ErrInterceptNotFound for an unknown ID, and an error if d is invalid or
+// no matching response is held.
+func (ir *InterceptRegistry) Decide(id string, d InterceptDecision) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	ic, ok := ir.points[id]
+	if !ok {
+		return ErrInterceptNotFound
+	}
+	var h *heldResponse
+	for _, w := range ic.waiting {
+		if d.RequestID == "" || w.RequestID == d.RequestID {
+			h = w
+			break
+		}
+	}
+	if h == nil {
+		if d.RequestID == "" {
+			return fmt.Errorf("no response is held at intercept %s", id)
+		}
+		return fmt.Errorf("no response to request %s is held at intercept %s", d.RequestID, id)
+	}
+	ic.unhold(h)
+	switch d.Action {
+	case InterceptApprove:
+		ic.Approved++
+	case InterceptEdit:
+		ic.Edited++
+	case InterceptDeny:
+		ic.Denied++
+	}
+	h.decided <- d
+	return nil
+}
+
+// Remove deletes an intercept, returning the responses it holds as they
+// are.
+func (ir *InterceptRegistry) Remove(id string) bool {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	ic, ok := ir.points[id]
+	if !ok {
+		return false
+	}
+	ic.approveAll()
+	delete(ir.points, id)
+	return true
+}
+
+// Reset deletes every intercept, returning the responses they hold as they
+// are, and restarts intercept IDs.
+func (ir *InterceptRegistry) Reset() {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	for _, ic := range ir.points {
+		ic.approveAll()
+	}
+	ir.points = map[string]*Intercept{}
+	ir.nextID = 0
+}
+
+// Matches reports whether an intercept matches r, so its response should
+// be buffered for Hold.
+func (ir *InterceptRegistry) Matches(r *http.Request) bool {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	return ir.matchLocked(r) != nil
+}
+
+// Hold holds the response to r if an intercept matches r, until it is
+// decided, the intercept's timeout passes, or the client goes away. It
+// returns the ID of the intercept that held the response, or "" if none
+// matched, and the decision; a response nobody decided is approved.
+func (ir *InterceptRegistry) Hold(r *http.Request, status int, header http.Header, body []byte) (string, InterceptDecision) {
+	approve := InterceptDecision{Action: InterceptApprove}
+	ir.mu.Lock()
+	ic := ir.matchLocked(r)
+	if ic == nil {
+		ir.mu.Unlock()
+		return "", approve
+	}
+	headers := make(map[string]string, len(header))
+	for k := range header {
+		headers[k] = header.Get(k)
+	}
+	h := &heldResponse{
+		HeldResponse: HeldResponse{
+			RequestID:  chimw.GetReqID(r.Context()),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Query:      r.URL.RawQuery,
+			Since:      time.Now(),
+			StatusCode: status,
+			Headers:    headers,
+			Body:       string(body),
+		},
+		decided: make(chan InterceptDecision, 1),
+	}
+	ic.waiting = append(ic.waiting, h)
+	ic.Hits++
+	timeout := ic.timeout
+	ir.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case d := <-h.decided:
+		return ic.ID, d
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	if !ic.unhold(h) {
+		// Decided while the timer fired.
+		return ic.ID, <-h.decided
+	}
+	if r.Context().Err() == nil {
+		ic.TimedOut++
+	}
+	return ic.ID, approve
+}
+
+// matchLocked returns the oldest intercept matching r. Callers hold ir.mu.
+func (ir *InterceptRegistry) matchLocked(r *http.Request) *Intercept {
+	var match *Intercept
+	for _, ic := range ir.points {
+		if matchesRequest(ic.Method, ic.Path, r) && (match == nil || ic.ID < match.ID) {
+			match = ic
+		}
+	}
+	return match
+}
+
+// unhold removes h from the held responses, reporting whether it was held.
+func (ic *Intercept) unhold(h *heldResponse) bool {
+	for i, w := range ic.waiting {
+		if w == h {
+			ic.waiting = append(ic.waiting[:i:i], ic.waiting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (ic *Intercept) approveAll() {
+	for _, h := range ic.waiting {
+		h.decided <- InterceptDecision{Action: InterceptApprove}
+	}
+	ic.Approved += len(ic.waiting)
+	ic.waiting = nil
+}
+
+func (ic *Intercept) snapshot() Intercept {
+	out := *ic
+	out.waiting = nil
+	out.Held = make([]HeldResponse, len(ic.waiting))
+	for i, h := range ic.waiting {
+		out.Held[i] = h.HeldResponse
+	}
+	return out
+}
+
+// InterceptResponses holds the responses to API requests matching an
+// intercept set through /admin/intercepts until they are decided, and
+// returns them approved, edited, or denied. Intercepted responses carry
+// InterceptHeader. Admin and health responses are never held.
+func (m *Middleware) InterceptResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/admin/") || r.URL.Path == healthzPath || !m.Intercepts.Matches(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		id, d := m.Intercepts.Hold(r, rec.status, rec.header, rec.body.Bytes())
+		if r.Context().Err() != nil {
+			return
+		}
+
+		if d.Action == InterceptDeny {
+			status := d.StatusCode
+			if status == 0 {
+				status = http.StatusBadGateway
+			}
+			w.Header().Set(InterceptHeader, id+" denied")
+			if d.Body == nil {
+				Error(w, status, "response denied at intercept "+id)
+				return
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(*d.Body)))
+			w.WriteHeader(status)
+			w.Write([]byte(*d.Body))
+			return
+		}
+
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		status, body := rec.status, rec.body.Bytes()
+		outcome := "approved"
+		if d.Action == InterceptEdit {
+			outcome = "edited"
+			if d.StatusCode != 0 {
+				status = d.StatusCode
+			}
+			for k, v := range d.Headers {
+				w.Header().Set(k, v)
+			}
+			if d.Body != nil {
+				body = []byte(*d.Body)
+			}
+		}
+		if id != "" { // "" when the intercept was removed while the twin handled the request
+			w.Header().Set(InterceptHeader, id+" "+outcome)
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(status)
+		w.Write(body)
+	})
+}
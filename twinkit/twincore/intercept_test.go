@@ -0,0 +1,150 @@
+package twincore
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// interceptHandler returns a handler behind InterceptResponses that answers
+// 201 with a JSON body naming the request.
+func interceptHandler() (*Middleware, http.Handler) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	h := chimw.RequestID(mw.InterceptResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"ch_1","status":"succeeded"}`))
+	})))
+	return mw, h
+}
+
+// serveIntercepted serves a POST to path in the background, returning a
+// channel that yields the response.
+func serveIntercepted(h http.Handler, path string) <-chan *httptest.ResponseRecorder {
+	out := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", path, nil))
+		out <- rec
+	}()
+	return out
+}
+
+// waitHeldResponse waits until a response is held at the registry's first
+// intercept and returns it.
+func waitHeldResponse(ir *InterceptRegistry) HeldResponse {
+	for {
+		if held := ir.All()[0].Held; len(held) > 0 {
+			return held[0]
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestInterceptValidation(t *testing.T) {
+	ir := NewInterceptRegistry()
+	for _, ic := range []Intercept{
+		{Path: "v1/charges"},
+		{Path: "/admin/requests"},
+		{Path: "/v1/charges", Timeout: "1m"},
+	} {
+		if _, err := ir.Add(ic); err == nil {
+			t.Errorf("%+v: expected an error", ic)
+		}
+	}
+	ic, err := ir.Add(Intercept{Method: "post", Path: "/v1/charges"})
+	if err != nil || ic.ID != "ic_000001" || ic.Method != "POST" {
+		t.Fatalf("unexpected intercept %+v, %v", ic, err)
+	}
+
+	body := "{}"
+	for _, d := range []InterceptDecision{
+		{Action: "maybe"},
+		{Action: InterceptApprove, Body: &body},
+		{Action: InterceptDeny, Headers: map[string]string{"X-A": "1"}},
+		{Action: InterceptEdit, StatusCode: 1000},
+	} {
+		if err := ir.Decide(ic.ID, d); err == nil {
+			t.Errorf("%+v: expected an error", d)
+		}
+	}
+	if err := ir.Decide(ic.ID, InterceptDecision{Action: InterceptApprove}); err == nil || !strings.Contains(err.Error(), "no response is held") {
+		t.Errorf("expected an error with nothing held, got %v", err)
+	}
+	if err := ir.Decide("ic_404", InterceptDecision{Action: InterceptApprove}); !errors.Is(err, ErrInterceptNotFound) {
+		t.Errorf("expected ErrInterceptNotFound, got %v", err)
+	}
+}
+
+func TestInterceptDecisions(t *testing.T) {
+	mw, h := interceptHandler()
+	ic, _ := mw.Intercepts.Add(Intercept{Path: "/v1/charges"})
+
+	// Approve: the response is returned as the twin wrote it.
+	resp := serveIntercepted(h, "/v1/charges")
+	held := waitHeldResponse(mw.Intercepts)
+	if held.StatusCode != http.StatusCreated || held.Body != `{"id":"ch_1","status":"succeeded"}` || held.Headers["Content-Type"] != "application/json" || held.RequestID == "" {
+		t.Fatalf("unexpected held response %+v", held)
+	}
+	if err := mw.Intercepts.Decide(ic.ID, InterceptDecision{RequestID: held.RequestID, Action: InterceptApprove}); err != nil {
+		t.Fatal(err)
+	}
+	rec := <-resp
+	if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), "succeeded") || rec.Header().Get(InterceptHeader) != ic.ID+" approved" {
+		t.Errorf("unexpected approved response %d %v %s", rec.Code, rec.Header(), rec.Body)
+	}
+
+	// Edit: status, headers, and body are replaced.
+	resp = serveIntercepted(h, "/v1/charges")
+	waitHeldResponse(mw.Intercepts)
+	body := `{"id":"ch_1","status":"failed"}`
+	mw.Intercepts.Decide(ic.ID, InterceptDecision{Action: InterceptEdit, StatusCode: 402, Headers: map[string]string{"X-Edited": "yes"}, Body: &body})
+	rec = <-resp
+	if rec.Code != 402 || rec.Body.String() != body || rec.Header().Get("X-Edited") != "yes" || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected edited response %d %v %s", rec.Code, rec.Header(), rec.Body)
+	}
+
+	// Deny: the client gets an error instead.
+	resp = serveIntercepted(h, "/v1/charges")
+	waitHeldResponse(mw.Intercepts)
+	mw.Intercepts.Decide(ic.ID, InterceptDecision{Action: InterceptDeny})
+	rec = <-resp
+	if rec.Code != http.StatusBadGateway || !strings.Contains(rec.Body.String(), "denied") || rec.Header().Get(InterceptHeader) != ic.ID+" denied" {
+		t.Errorf("unexpected denied response %d %v %s", rec.Code, rec.Header(), rec.Body)
+	}
+
+	if got := mw.Intercepts.All()[0]; got.Hits != 3 || got.Approved != 1 || got.Edited != 1 || got.Denied != 1 || len(got.Held) != 0 {
+		t.Errorf("unexpected counters %+v", got)
+	}
+}
+
+func TestInterceptTimeoutAndReset(t *testing.T) {
+	mw, h := interceptHandler()
+	mw.Intercepts.Add(Intercept{Path: "/v1/*", Timeout: "20ms"})
+
+	if rec := <-serveIntercepted(h, "/v1/charges"); rec.Code != http.StatusCreated {
+		t.Errorf("expected the response returned as it was after the timeout, got %d", rec.Code)
+	}
+	if got := mw.Intercepts.All()[0]; got.TimedOut != 1 || got.Approved != 0 {
+		t.Errorf("unexpected counters %+v", got)
+	}
+
+	mw.Intercepts.Reset()
+	mw.Intercepts.Add(Intercept{Path: "/v1/*"})
+	resp := serveIntercepted(h, "/v1/refunds")
+	waitHeldResponse(mw.Intercepts)
+	mw.Intercepts.Reset()
+	if rec := <-resp; rec.Code != http.StatusCreated || len(mw.Intercepts.All()) != 0 {
+		t.Errorf("expected the held response returned and the intercept gone, got %d, %v", rec.Code, mw.Intercepts.All())
+	}
+
+	if rec := <-serveIntercepted(h, "/v1/charges"); rec.Header().Get(InterceptHeader) != "" {
+		t.Error("expected responses to pass through without intercepts")
+	}
+}
@@ -0,0 +1,78 @@
+package twincore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestIDHeader carries a request's ID on every response. A client can
+// also choose the ID by sending it on the request, as chi's RequestID
+// middleware reads it. The ID keys the request log, the EventJournal, and
+// the webhooks the request produced, so GET
+// /admin/correlations?request_id=... links all three.
+const RequestIDHeader = "X-Request-Id"
+
+// JournalEntry is a domain event a twin emitted, such as a Stripe event.
+type JournalEntry struct {
+	EventID   string    `json:"event_id"`
+	Type      string    `json:"type"`
+	RequestID string    `json:"request_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// EventJournal is a thread-safe ring buffer of the domain events a twin
+// emitted, recorded with the API request that produced them.
+type EventJournal struct {
+	mu      sync.RWMutex
+	entries []JournalEntry
+	maxSize int
+}
+
+// NewEventJournal creates an event journal with the given max size.
+func NewEventJournal(maxSize int) *EventJournal {
+	return &EventJournal{
+		entries: make([]JournalEntry, 0, maxSize),
+		maxSize: maxSize,
+	}
+}
+
+// Record adds an event, evicting the oldest if at capacity. ctx is the
+// context of the request that produced the event, or context.Background()
+// for one produced by time passing or an admin call.
+func (j *EventJournal) Record(ctx context.Context, eventID, eventType string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.entries) >= j.maxSize {
+		j.entries = j.entries[1:]
+	}
+	j.entries = append(j.entries, JournalEntry{
+		EventID:   eventID,
+		Type:      eventType,
+		RequestID: chimw.GetReqID(ctx),
+		Timestamp: time.Now(),
+	})
+}
+
+// ForRequest returns the events the request with the given ID produced,
+// oldest first.
+func (j *EventJournal) ForRequest(requestID string) []JournalEntry {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	out := []JournalEntry{}
+	for _, e := range j.entries {
+		if e.RequestID == requestID {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Clear removes all entries.
+func (j *EventJournal) Clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = j.entries[:0]
+}
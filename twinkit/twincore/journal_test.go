@@ -0,0 +1,58 @@
+package twincore
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+func TestEventJournal(t *testing.T) {
+	j := NewEventJournal(2)
+	ctx := context.WithValue(context.Background(), chimw.RequestIDKey, "req-1")
+	j.Record(ctx, "evt_1", "charge.succeeded")
+	j.Record(context.Background(), "evt_2", "payout.paid")
+	j.Record(ctx, "evt_3", "charge.refunded")
+
+	got := j.ForRequest("req-1")
+	if len(got) != 1 || got[0].EventID != "evt_3" {
+		t.Fatalf("expected only evt_3 to survive eviction, got %+v", got)
+	}
+	if got := j.ForRequest(""); len(got) != 1 || got[0].EventID != "evt_2" {
+		t.Errorf("expected the uncorrelated event under \"\", got %+v", got)
+	}
+
+	j.Clear()
+	if got := j.ForRequest("req-1"); len(got) != 0 {
+		t.Errorf("expected no entries after Clear, got %d", len(got))
+	}
+}
+
+func TestRequestLogEchoesRequestID(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	var recorded string
+	handler := chimw.RequestID(mw.RequestLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mw.Events.Record(r.Context(), "evt_1", "test.event")
+	})))
+
+	req := httptest.NewRequest("POST", "/v1/things", nil)
+	req.Header.Set(RequestIDHeader, "my-request")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "my-request" {
+		t.Errorf("%s = %q, want my-request", RequestIDHeader, got)
+	}
+	if entries := mw.ReqLog.Entries(); len(entries) == 1 {
+		recorded = entries[0].RequestID
+	}
+	if recorded != "my-request" {
+		t.Errorf("request log recorded %q, want my-request", recorded)
+	}
+	if got := mw.Events.ForRequest("my-request"); len(got) != 1 {
+		t.Errorf("expected the event to be journaled with the request, got %+v", got)
+	}
+}
@@ -0,0 +1,96 @@
+package twincore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DiffOptions configures DiffBodies.
+type DiffOptions struct {
+	// Ignore names fields skipped at any depth, such as timestamps.
+	Ignore []string
+	// Max caps the differences reported; zero reports them all.
+	Max int
+	// OnlyA and OnlyB describe a field present only in the first or only
+	// in the second body, e.g. "only in old" and "only in new".
+	OnlyA, OnlyB string
+}
+
+// DiffBodies compares two response bodies. JSON bodies are compared value
+// by value and each difference is reported with its path, e.g.
+// `$.data[0].amount: 100 != 200`; other bodies are compared byte for byte.
+// It returns nil when the bodies match.
+func DiffBodies(a, b []byte, opts DiffOptions) []string {
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		if bytes.Equal(a, b) {
+			return nil
+		}
+		return []string{fmt.Sprintf("body: %d bytes != %d bytes", len(a), len(b))}
+	}
+	skip := make(map[string]bool, len(opts.Ignore))
+	for _, f := range opts.Ignore {
+		skip[f] = true
+	}
+	var out []string
+	diffJSON("$", av, bv, skip, opts, &out)
+	return out
+}
+
+func diffJSON(path string, a, b any, skip map[string]bool, opts DiffOptions, out *[]string) {
+	if opts.Max > 0 && len(*out) >= opts.Max {
+		return
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		bm, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bm))
+		for k := range av {
+			if !skip[k] {
+				keys = append(keys, k)
+			}
+		}
+		for k := range bm {
+			if _, ok := av[k]; !ok && !skip[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			av, aok := av[k]
+			bv, bok := bm[k]
+			switch {
+			case !bok:
+				*out = append(*out, fmt.Sprintf("%s.%s: %s", path, k, opts.OnlyA))
+			case !aok:
+				*out = append(*out, fmt.Sprintf("%s.%s: %s", path, k, opts.OnlyB))
+			default:
+				diffJSON(path+"."+k, av, bv, skip, opts, out)
+			}
+		}
+		return
+	case []any:
+		bs, ok := b.([]any)
+		if !ok {
+			break
+		}
+		if len(av) != len(bs) {
+			*out = append(*out, fmt.Sprintf("%s: length %d != %d", path, len(av), len(bs)))
+		}
+		for i := range min(len(av), len(bs)) {
+			diffJSON(fmt.Sprintf("%s[%d]", path, i), av[i], bs[i], skip, opts, out)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		aj, _ := json.Marshal(a)
+		bj, _ := json.Marshal(b)
+		*out = append(*out, fmt.Sprintf("%s: %s != %s", path, aj, bj))
+	}
+}
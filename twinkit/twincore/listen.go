@@ -0,0 +1,64 @@
+package twincore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Listen opens the listener the twin serves on: the unix domain socket at
+// Socket if set, otherwise Port on Host. A socket file left behind by a
+// twin that did not shut down cleanly is replaced; one a running process
+// still answers on is not.
+func (c *Config) Listen() (net.Listener, error) {
+	if c.Socket != "" {
+		if err := removeStaleSocket(c.Socket); err != nil {
+			return nil, err
+		}
+		ln, err := net.Listen("unix", c.Socket)
+		if err != nil {
+			return nil, fmt.Errorf("listening on socket %s: %w", c.Socket, err)
+		}
+		return ln, nil
+	}
+	network := "tcp"
+	if ip := net.ParseIP(c.Host); ip != nil && ip.To4() == nil {
+		network = "tcp6"
+	}
+	ln, err := net.Listen(network, net.JoinHostPort(c.Host, strconv.Itoa(c.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", c.Addr(), err)
+	}
+	return ln, nil
+}
+
+// Addr describes where the twin listens, for logs: unix:<path> for a
+// socket, otherwise host:port.
+func (c *Config) Addr() string {
+	if c.Socket != "" {
+		return "unix:" + c.Socket
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("socket %s is in use by another process", path)
+	}
+	return os.Remove(path)
+}
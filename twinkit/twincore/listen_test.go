@@ -0,0 +1,55 @@
+package twincore
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListenSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "twin.sock")
+	cfg := &Config{Socket: path}
+	ln, err := cfg.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr() != "unix:"+path {
+		t.Errorf("expected addr unix:%s, got %s", path, cfg.Addr())
+	}
+
+	if _, err := cfg.Listen(); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("expected a second listener on a live socket to fail, got %v", err)
+	}
+
+	// A socket file left by a twin that died is replaced.
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+	ln, err = cfg.Listen()
+	if err != nil {
+		t.Fatalf("expected the stale socket replaced, got %v", err)
+	}
+	ln.Close()
+
+	file := filepath.Join(t.TempDir(), "not-a-socket")
+	os.WriteFile(file, nil, 0o644)
+	if _, err := (&Config{Socket: file}).Listen(); err == nil {
+		t.Error("expected a regular file at the socket path to be left alone")
+	}
+}
+
+func TestListenIPv6(t *testing.T) {
+	cfg := &Config{Host: "::1"}
+	ln, err := cfg.Listen()
+	if err != nil {
+		t.Skipf("no IPv6 loopback: %v", err)
+	}
+	defer ln.Close()
+	if ln.Addr().Network() != "tcp" || !strings.HasPrefix(ln.Addr().String(), "[::1]:") {
+		t.Errorf("expected a listener on [::1], got %s %s", ln.Addr().Network(), ln.Addr())
+	}
+	if cfg.Addr() != "[::1]:0" {
+		t.Errorf("expected addr [::1]:0, got %s", cfg.Addr())
+	}
+}
@@ -1,23 +1,39 @@
 package twincore
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"log/slog"
-	"math/rand/v2"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/wondertwin-ai/wondertwin/twinkit/store"
 )
 
+// maxCapturedBody is the largest request body the request log will retain for replay (1 MB).
+const maxCapturedBody = 1 << 20
+
 // RequestLogEntry captures details of an incoming request for admin inspection.
 type RequestLogEntry struct {
-	Timestamp  time.Time         `json:"timestamp"`
-	Method     string            `json:"method"`
-	Path       string            `json:"path"`
-	Headers    map[string]string `json:"headers,omitempty"`
-	StatusCode int               `json:"status_code"`
-	Duration   time.Duration     `json:"duration_ms"`
-	RequestID  string            `json:"request_id,omitempty"`
+	ID           string            `json:"id"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Method       string            `json:"method"`
+	Path         string            `json:"path"`
+	Route        string            `json:"route,omitempty"` // route pattern that served it, e.g. /v1/accounts/{id}
+	Query        string            `json:"query,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Body         string            `json:"body,omitempty"`
+	BodyCaptured bool              `json:"body_captured,omitempty"`
+	StatusCode   int               `json:"status_code"`
+	Duration     time.Duration     `json:"duration_ms"`
+	RequestID    string            `json:"request_id,omitempty"`
 }
 
 // RequestLog is a thread-safe ring buffer of recent requests.
@@ -25,6 +41,7 @@ type RequestLog struct {
 	mu      sync.RWMutex
 	entries []RequestLogEntry
 	maxSize int
+	counter int
 }
 
 // NewRequestLog creates a request log with the given max size.
@@ -36,9 +53,14 @@ func NewRequestLog(maxSize int) *RequestLog {
 }
 
 // Add appends an entry, evicting the oldest if at capacity.
+// Entries without an ID are assigned a sequential one of the form "req_000001".
 func (rl *RequestLog) Add(entry RequestLogEntry) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
+	rl.counter++
+	if entry.ID == "" {
+		entry.ID = fmt.Sprintf("req_%06d", rl.counter)
+	}
 	if len(rl.entries) >= rl.maxSize {
 		rl.entries = rl.entries[1:]
 	}
@@ -54,42 +76,203 @@ func (rl *RequestLog) Entries() []RequestLogEntry {
 	return out
 }
 
-// Clear removes all entries.
+// Get returns the entry with the given ID, if it is still in the buffer.
+func (rl *RequestLog) Get(id string) (RequestLogEntry, bool) {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	for _, e := range rl.entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return RequestLogEntry{}, false
+}
+
+// Clear removes all entries and resets the ID counter.
 func (rl *RequestLog) Clear() {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 	rl.entries = rl.entries[:0]
+	rl.counter = 0
 }
 
 // FaultConfig defines a fault injection for a specific endpoint pattern.
+//
+// A fault is active as soon as it is set unless it is scheduled. StartAt and
+// EndAt bound the window it is active in; After and Duration give the same
+// window relative to when the fault is set ("503 from T+10m to T+15m" is
+// After "10m", Duration "5m") and are resolved into StartAt and EndAt by
+// FaultRegistry.Set. Clock selects the time the window is measured against:
+// the wall clock, or the twin's simulated clock so /admin/time/advance can
+// move a test into and out of an outage.
+//
+// Preset names one of the built-in presets (FaultAuthInvalid,
+// FaultAuthExpired, FaultForbiddenScope); it sets StatusCode, and without
+// a Body the response is the twin's own auth error.
+//
+// A 429 fault answers with a Retry-After header of RetryAfter seconds, or
+// DefaultRetryAfter when it is unset, as real rate limiters do.
+//
+// ID, Hits, and LastHitAt are kept by the registry and ignored by Set: the
+// fault's ID, sent in FaultHeader on every response it affects, how many
+// requests it has affected, and when it last did.
 type FaultConfig struct {
+	ID         string        `json:"id,omitempty"`
 	StatusCode int           `json:"status_code"`
 	Body       string        `json:"body,omitempty"`
 	Delay      time.Duration `json:"delay_ms,omitempty"`
 	Rate       float64       `json:"rate"` // 0.0-1.0, probability of fault triggering
+	Preset     string        `json:"preset,omitempty"`
+	RetryAfter int           `json:"retry_after,omitempty"` // seconds; 429 faults only
+
+	StartAt  *time.Time `json:"start_at,omitempty"`
+	EndAt    *time.Time `json:"end_at,omitempty"`
+	After    string     `json:"after,omitempty"`    // delay before the fault starts, e.g. "10m"
+	Duration string     `json:"duration,omitempty"` // how long the fault stays active, e.g. "5m"
+	Clock    string     `json:"clock,omitempty"`    // FaultClockWall (default) or FaultClockSimulated
+
+	Hits      int        `json:"hits"`
+	LastHitAt *time.Time `json:"last_hit_at,omitempty"`
+}
+
+// FaultHeader carries the ID of the injected fault that affected a
+// response, so tests can tell an injected failure from a real one.
+const FaultHeader = "X-WT-Fault"
+
+// DefaultRetryAfter is the Retry-After, in seconds, of a 429 fault that
+// does not set one.
+const DefaultRetryAfter = 1
+
+// Clocks a fault schedule can be measured against.
+const (
+	FaultClockWall      = "wall"
+	FaultClockSimulated = "simulated"
+)
+
+// activeAt reports whether the fault's schedule covers t.
+func (f *FaultConfig) activeAt(t time.Time) bool {
+	if f.StartAt != nil && t.Before(*f.StartAt) {
+		return false
+	}
+	return f.EndAt == nil || t.Before(*f.EndAt)
 }
 
 // FaultRegistry manages injected faults for specific endpoint patterns.
 type FaultRegistry struct {
 	mu     sync.RWMutex
 	faults map[string]FaultConfig // path pattern -> fault config
+	rand   *Random                // decides partial-rate faults
+	simNow func() time.Time       // simulated clock for scheduled faults, if any
+	nextID int                    // numbers fault IDs
 }
 
 // NewFaultRegistry creates a new fault registry.
 func NewFaultRegistry() *FaultRegistry {
 	return &FaultRegistry{
 		faults: make(map[string]FaultConfig),
+		rand:   NewRandom(0),
 	}
 }
 
-// Set injects a fault for the given endpoint pattern.
-func (fr *FaultRegistry) Set(pattern string, fault FaultConfig) {
+// SetClock gives the registry the twin's simulated clock, so faults
+// scheduled with Clock "simulated" follow /admin/time. admin.NewHandler
+// wires it up from the store.Clock it is given.
+func (fr *FaultRegistry) SetClock(now func() time.Time) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	fr.simNow = now
+}
+
+// Set injects a fault for the given endpoint pattern. A relative schedule
+// (After, Duration) is resolved against the fault's clock at the time of the
+// call. The fault gets a new ID and its hit count starts from zero, even
+// when it replaces one on the same pattern. Set returns an error, and
+// registers nothing, if the schedule or preset is invalid.
+func (fr *FaultRegistry) Set(pattern string, fault FaultConfig) error {
 	fr.mu.Lock()
 	defer fr.mu.Unlock()
 	if fault.Rate == 0 {
 		fault.Rate = 1.0
 	}
+	if IsWebhookFault(pattern) {
+		if err := checkWebhookFault(pattern, &fault); err != nil {
+			return err
+		}
+	}
+	if err := resolvePreset(&fault); err != nil {
+		return err
+	}
+	if fault.RetryAfter < 0 {
+		return fmt.Errorf("retry_after must not be negative, got %d", fault.RetryAfter)
+	}
+	if err := fr.resolveSchedule(&fault); err != nil {
+		return err
+	}
+	fr.nextID++
+	fault.ID = fmt.Sprintf("fault_%06d", fr.nextID)
+	fault.Hits, fault.LastHitAt = 0, nil
 	fr.faults[pattern] = fault
+	return nil
+}
+
+// resolveSchedule validates a fault's schedule and turns After and Duration
+// into StartAt and EndAt. Callers hold fr.mu.
+func (fr *FaultRegistry) resolveSchedule(f *FaultConfig) error {
+	switch f.Clock {
+	case "", FaultClockWall:
+	case FaultClockSimulated:
+		if fr.simNow == nil {
+			return fmt.Errorf("this twin has no simulated clock")
+		}
+	default:
+		return fmt.Errorf("clock must be %q or %q, got %q", FaultClockWall, FaultClockSimulated, f.Clock)
+	}
+
+	parse := func(field, s string) (time.Duration, error) {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("%s must be a non-negative duration like \"5m\", got %q", field, s)
+		}
+		return d, nil
+	}
+	now := fr.nowLocked(f.Clock)
+	if f.After != "" {
+		if f.StartAt != nil {
+			return fmt.Errorf("after and start_at cannot be combined")
+		}
+		d, err := parse("after", f.After)
+		if err != nil {
+			return err
+		}
+		start := now.Add(d)
+		f.StartAt = &start
+	}
+	if f.Duration != "" {
+		if f.EndAt != nil {
+			return fmt.Errorf("duration and end_at cannot be combined")
+		}
+		d, err := parse("duration", f.Duration)
+		if err != nil {
+			return err
+		}
+		end := now.Add(d)
+		if f.StartAt != nil {
+			end = f.StartAt.Add(d)
+		}
+		f.EndAt = &end
+	}
+	if f.StartAt != nil && f.EndAt != nil && !f.EndAt.After(*f.StartAt) {
+		return fmt.Errorf("end_at must be after start_at")
+	}
+	return nil
+}
+
+// nowLocked returns the current time on the given fault clock. Callers hold fr.mu.
+func (fr *FaultRegistry) nowLocked(clock string) time.Time {
+	if clock == FaultClockSimulated && fr.simNow != nil {
+		return fr.simNow()
+	}
+	return time.Now()
 }
 
 // Remove removes a fault for the given endpoint pattern.
@@ -101,19 +284,28 @@ func (fr *FaultRegistry) Remove(pattern string) bool {
 	return existed
 }
 
-// Check returns a fault config if one matches the given path, or nil if no fault applies.
+// Check returns a fault config if one matches the given path, or nil if no
+// fault applies. A fault it returns counts as a hit.
 func (fr *FaultRegistry) Check(path string) *FaultConfig {
-	fr.mu.RLock()
-	defer fr.mu.RUnlock()
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
 	if f, ok := fr.faults[path]; ok {
-		if f.Rate >= 1.0 || rand.Float64() < f.Rate {
+		now := fr.nowLocked(f.Clock)
+		if (f.StartAt != nil || f.EndAt != nil) && !f.activeAt(now) {
+			return nil
+		}
+		if f.Rate >= 1.0 || fr.rand.Float64() < f.Rate {
+			f.Hits++
+			f.LastHitAt = &now
+			fr.faults[path] = f
 			return &f
 		}
 	}
 	return nil
 }
 
-// All returns all registered faults.
+// All returns all registered faults, including scheduled faults that are
+// not active yet or have ended.
 func (fr *FaultRegistry) All() map[string]FaultConfig {
 	fr.mu.RLock()
 	defer fr.mu.RUnlock()
@@ -124,11 +316,12 @@ func (fr *FaultRegistry) All() map[string]FaultConfig {
 	return out
 }
 
-// Reset clears all faults.
+// Reset clears all faults and restarts fault IDs.
 func (fr *FaultRegistry) Reset() {
 	fr.mu.Lock()
 	defer fr.mu.Unlock()
 	fr.faults = make(map[string]FaultConfig)
+	fr.nextID = 0
 }
 
 // IdempotencyTracker tracks idempotency keys and their cached responses.
@@ -185,16 +378,103 @@ type Middleware struct {
 	ReqLog     *RequestLog
 	Faults     *FaultRegistry
 	Idempotent *IdempotencyTracker
+
+	// Rand is the twin's source of random values. Handlers use it for
+	// generated codes so --rand-seed makes them reproducible.
+	Rand *Random
+
+	// Quirks holds the built-in quirks (see BuiltinQuirks) plus any the
+	// twin registers. Pass it to admin.Handler.SetQuirkStore.
+	Quirks *QuirkRegistry
+
+	// ShadowLog records how a shadow target's responses differ from the
+	// twin's. See Middleware.Shadow.
+	ShadowLog *ShadowLog
+
+	// Templates holds the response templates overriding how routes render.
+	// See Middleware.ResponseTemplates.
+	Templates *TemplateRegistry
+
+	// Cache holds the responses QuirkStaleCache replays. See
+	// Middleware.HTTPCache.
+	Cache *ResponseCache
+
+	// Audit is the hash-chained log of admin mutations and scenario runs
+	// behind /admin/audit/export. See Middleware.AuditMutations.
+	Audit *AuditLog
+
+	// Breakpoints holds the breakpoints set through /admin/breakpoints.
+	// See Middleware.PauseAtBreakpoints.
+	Breakpoints *BreakpointRegistry
+
+	// Intercepts holds the response intercepts set through
+	// /admin/intercepts. See Middleware.InterceptResponses.
+	Intercepts *InterceptRegistry
+
+	// Events records the domain events the twin emits with the request
+	// that produced them, for /admin/correlations. Twins call
+	// Events.Record wherever they emit one.
+	Events *EventJournal
+
+	// WriteValidationError writes the response when Middleware.Validate
+	// rejects a request. Twins set it to answer in their provider's error
+	// format; nil means the package-level WriteValidationError.
+	WriteValidationError ValidationErrorWriter
+
+	// StoreFailures makes a share of store operations fail, set through
+	// /admin/faults/storage. admin.NewHandler attaches it to the twin's
+	// stores. See Middleware.StorageFailures.
+	StoreFailures *store.Failures
+
+	// WriteStorageError writes the response when a store operation fails.
+	// Twins set it to answer with their provider's 500; nil means the
+	// package-level WriteStorageError.
+	WriteStorageError StorageErrorWriter
+
+	// WriteAuthError writes the response for an injected auth fault preset.
+	// Twins set it to answer with their provider's 401 and 403 bodies; nil
+	// means the package-level WriteAuthError.
+	WriteAuthError AuthErrorWriter
+
+	// WriteBodyTooLarge writes the response for a request body over its
+	// size limit. Twins set it to answer with their provider's 413 or 400;
+	// nil means the package-level WriteBodyTooLarge.
+	WriteBodyTooLarge BodyTooLargeWriter
+
+	// Capacity, when set, reports whether the twin's stores have room for
+	// more records. While it returns an error, POST, PUT, and PATCH
+	// requests outside /admin are answered with 507 Insufficient Storage.
+	// admin.NewHandler sets it from the stores' limits.
+	Capacity func() error
+
+	load           backpressure // in-flight and recent requests, for Backpressure
+	capacityWarned atomic.Int64 // unix time of the last capacity warning
+	cursorQuirk    sync.Once    // registers QuirkInvalidCursors
+	listLagQuirk   sync.Once    // registers QuirkListLag
 }
 
 // NewMiddleware creates a new Middleware instance.
 func NewMiddleware(cfg *Config, logger *slog.Logger) *Middleware {
+	rng := NewRandom(cfg.RandSeed)
+	faults := NewFaultRegistry()
+	faults.rand = rng
 	return &Middleware{
 		cfg:        cfg,
 		logger:     logger,
 		ReqLog:     NewRequestLog(1000),
-		Faults:     NewFaultRegistry(),
+		Faults:     faults,
 		Idempotent: NewIdempotencyTracker(),
+		Rand:       rng,
+		Quirks:     NewQuirkRegistry(BuiltinQuirks()...),
+		ShadowLog:  NewShadowLog(200),
+		Templates:  NewTemplateRegistry(),
+		Cache:      NewResponseCache(),
+		Audit:      NewAuditLog(),
+		Events:     NewEventJournal(1000),
+
+		Breakpoints:   NewBreakpointRegistry(),
+		Intercepts:    NewInterceptRegistry(),
+		StoreFailures: store.NewFailures(rng.Float64),
 	}
 }
 
@@ -203,7 +483,7 @@ func (m *Middleware) CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, Idempotency-Key, Stripe-Account, X-Api-Key")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, Idempotency-Key, Stripe-Account, X-Api-Key, X-Request-Id")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		if r.Method == http.MethodOptions {
@@ -214,6 +494,72 @@ func (m *Middleware) CORS(next http.Handler) http.Handler {
 	})
 }
 
+// AdminReadOnly rejects requests that would modify the twin through its
+// admin API with 403 when Config.AdminReadOnly is set, so a shared twin
+// cannot be reset or reconfigured by one user under everyone else. Only
+// GET and HEAD requests to /admin/ get through, plus POST /admin/state/lint,
+// which only reads the seed it is sent, and POST /admin/audit/events, which
+// only records a scenario run; the twin's own API is not affected.
+func (m *Middleware) AdminReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.cfg.AdminReadOnly && strings.HasPrefix(r.URL.Path, "/admin/") &&
+			r.Method != http.MethodGet && r.Method != http.MethodHead &&
+			r.URL.Path != "/admin/state/lint" && r.URL.Path != "/admin/audit/events" {
+			Error(w, http.StatusForbidden, "admin API is read-only on this twin (--admin-readonly)")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// StoreLimits returns the configured store limits: maximum records and
+// megabytes per store (zero means none) and the limit policy.
+func (m *Middleware) StoreLimits() (maxRecords, maxMB int, policy string) {
+	return m.cfg.StoreMaxRecords, m.cfg.StoreMaxMB, m.cfg.StoreLimitPolicy
+}
+
+// ScanWarning returns the store.ScanWarning for the named store: scans
+// over Config.StoreScanWarn records are logged, at most once a minute.
+// It is zero, turning warnings off, when StoreScanWarn is.
+func (m *Middleware) ScanWarning(name string) store.ScanWarning {
+	limit := m.cfg.StoreScanWarn
+	if limit <= 0 {
+		return store.ScanWarning{}
+	}
+	return store.ScanWarning{
+		Records: limit,
+		Warn: func(op string, scanned int, took time.Duration) {
+			m.logger.Warn("store filter scanned more records than --store-scan-warn; look records up by an index instead",
+				"store", name, "op", op, "scanned", scanned, "limit", limit, "took", took)
+		},
+	}
+}
+
+// StoreCapacity rejects requests that would add records to a full store,
+// the way a provider reports an exhausted quota, instead of letting the
+// twin grow until it runs out of memory. Reads, deletes, and admin requests
+// pass, so a full twin can still be inspected, pruned, and reset. It warns
+// in the log at most once a minute while writes are being rejected.
+func (m *Middleware) StoreCapacity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			if m.Capacity == nil || strings.HasPrefix(r.URL.Path, "/admin/") {
+				break
+			}
+			if err := m.Capacity(); err != nil {
+				if now := time.Now().Unix(); now-m.capacityWarned.Load() >= 60 {
+					m.capacityWarned.Store(now)
+					m.logger.Warn("store limit reached; rejecting writes", "error", err.Error())
+				}
+				Error(w, http.StatusInsufficientStorage, "twin store limit reached: "+err.Error()+"; delete records or reset the twin (POST /admin/reset)")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // statusRecorder captures the status code written by downstream handlers.
 type statusRecorder struct {
 	http.ResponseWriter
@@ -225,22 +571,49 @@ func (sr *statusRecorder) WriteHeader(code int) {
 	sr.ResponseWriter.WriteHeader(code)
 }
 
-// RequestLog middleware captures request details into the ring buffer.
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// handlers can flush through the request log.
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// RequestLog middleware captures request details into the ring buffer, and
+// echoes the request's ID in RequestIDHeader.
+// When CaptureBodies is enabled, request headers and bodies (up to 1 MB) are
+// recorded as well so the request can later be replayed.
 func (m *Middleware) RequestLog(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		if id := chimw.GetReqID(r.Context()); id != "" {
+			w.Header().Set(RequestIDHeader, id)
+		}
 		rec := &statusRecorder{ResponseWriter: w, statusCode: 200}
 
+		var body []byte
+		bodyCaptured := false
+		if m.cfg.CaptureBodies {
+			body, bodyCaptured = captureBody(r)
+		}
+
 		next.ServeHTTP(rec, r)
 
 		entry := RequestLogEntry{
 			Timestamp:  start,
 			Method:     r.Method,
 			Path:       r.URL.Path,
+			Query:      r.URL.RawQuery,
 			StatusCode: rec.statusCode,
 			Duration:   time.Since(start),
+			RequestID:  chimw.GetReqID(r.Context()),
 		}
-		if m.cfg.Verbose {
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			entry.Route = rctx.RoutePattern()
+		}
+		if bodyCaptured {
+			entry.Body = string(body)
+			entry.BodyCaptured = true
+		}
+		if m.cfg.Verbose || m.cfg.CaptureBodies {
 			entry.Headers = make(map[string]string)
 			for k := range r.Header {
 				entry.Headers[k] = r.Header.Get(k)
@@ -259,13 +632,37 @@ func (m *Middleware) RequestLog(next http.Handler) http.Handler {
 	})
 }
 
-// LatencyInjection adds configurable latency to every request.
+// captureBody reads the request body and restores it for downstream handlers.
+// It reports false if the body exceeds maxCapturedBody and so cannot be replayed.
+func captureBody(r *http.Request) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxCapturedBody+1))
+	if err != nil {
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		return nil, false
+	}
+	if len(data) > maxCapturedBody {
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), r.Body))
+		return nil, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	return data, true
+}
+
+// LatencyInjection adds configurable latency to every request, or the
+// latency of the region the request was routed to (see Regions).
 func (m *Middleware) LatencyInjection(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if m.cfg.Latency > 0 {
+		latency := m.cfg.Latency
+		if region, _ := r.Context().Value(regionKey{}).(*Region); region != nil && region.Latency > 0 {
+			latency = region.Latency
+		}
+		if latency > 0 {
 			// Add some jitter: 80-120% of configured latency
-			jitter := 0.8 + rand.Float64()*0.4
-			delay := time.Duration(float64(m.cfg.Latency) * jitter)
+			jitter := 0.8 + m.Rand.Float64()*0.4
+			delay := time.Duration(float64(latency) * jitter)
 			time.Sleep(delay)
 		}
 		next.ServeHTTP(w, r)
@@ -275,7 +672,7 @@ func (m *Middleware) LatencyInjection(next http.Handler) http.Handler {
 // RandomFailure randomly returns 500 errors based on the configured fail rate.
 func (m *Middleware) RandomFailure(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if m.cfg.FailRate > 0 && rand.Float64() < m.cfg.FailRate {
+		if m.cfg.FailRate > 0 && m.Rand.Float64() < m.cfg.FailRate {
 			Error(w, http.StatusInternalServerError, "simulated random failure")
 			return
 		}
@@ -288,21 +685,63 @@ func (m *Middleware) RandomFailure(next http.Handler) http.Handler {
 // are not affected.
 func (m *Middleware) FaultInjection(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if fault := m.Faults.Check(r.URL.Path); fault != nil {
-			if fault.Delay > 0 {
-				time.Sleep(fault.Delay)
-			}
-			if fault.StatusCode > 0 {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(fault.StatusCode)
-				if fault.Body != "" {
-					fmt.Fprint(w, fault.Body)
-				} else {
-					fmt.Fprintf(w, `{"error":{"message":"injected fault","type":"api_error","code":%d}}`, fault.StatusCode)
-				}
-				return
-			}
+		if m.serveFault(w, r, r.URL.Path) {
+			return
 		}
 		next.ServeHTTP(w, r)
 	})
 }
+
+// FaultInjectionFor applies faults injected for a fixed endpoint instead of
+// the request path. Use it for open-ended routes such as /{domain}, where
+// no single path addresses every request.
+func (m *Middleware) FaultInjectionFor(endpoint string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if m.serveFault(w, r, endpoint) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// serveFault applies the fault registered for endpoint, if any, and reports
+// whether it wrote the response. Responses the fault affects, including
+// ones it only delays, carry its ID in FaultHeader.
+func (m *Middleware) serveFault(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+	fault := m.Faults.Check(endpoint)
+	if fault == nil {
+		return false
+	}
+	w.Header().Set(FaultHeader, fault.ID)
+	if fault.Delay > 0 {
+		time.Sleep(fault.Delay)
+	}
+	if fault.StatusCode <= 0 {
+		return false
+	}
+	if fault.Preset != "" && fault.Body == "" {
+		write := m.WriteAuthError
+		if write == nil {
+			write = WriteAuthError
+		}
+		write(w, r, fault.Preset)
+		return true
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if fault.StatusCode == http.StatusTooManyRequests {
+		retryAfter := fault.RetryAfter
+		if retryAfter == 0 {
+			retryAfter = DefaultRetryAfter
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	w.WriteHeader(fault.StatusCode)
+	if fault.Body != "" {
+		fmt.Fprint(w, fault.Body)
+	} else {
+		fmt.Fprintf(w, `{"error":{"message":"injected fault","type":"api_error","code":%d}}`, fault.StatusCode)
+	}
+	return true
+}
@@ -1,9 +1,12 @@
 package twincore
 
 import (
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -180,6 +183,77 @@ func TestFaultRegistryReset(t *testing.T) {
 	}
 }
 
+func TestFaultRegistryScheduleWallClock(t *testing.T) {
+	fr := NewFaultRegistry()
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	fr.Set("/ended", FaultConfig{StatusCode: 503, EndAt: &past})
+	fr.Set("/pending", FaultConfig{StatusCode: 503, StartAt: &future})
+	fr.Set("/window", FaultConfig{StatusCode: 503, StartAt: &past, EndAt: &future})
+	if err := fr.Set("/later", FaultConfig{StatusCode: 503, After: "10m", Duration: "5m"}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	if fr.Check("/ended") != nil || fr.Check("/pending") != nil || fr.Check("/later") != nil {
+		t.Error("expected faults outside their window not to trigger")
+	}
+	if fr.Check("/window") == nil {
+		t.Error("expected a fault inside its window to trigger")
+	}
+
+	later := fr.All()["/later"]
+	if later.StartAt == nil || later.EndAt == nil || later.EndAt.Sub(*later.StartAt) != 5*time.Minute {
+		t.Errorf("expected after/duration to resolve to a 5m window, got %v-%v", later.StartAt, later.EndAt)
+	}
+}
+
+func TestFaultRegistryScheduleSimulatedClock(t *testing.T) {
+	fr := NewFaultRegistry()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fr.SetClock(func() time.Time { return now })
+
+	if err := fr.Set("/v1/charges", FaultConfig{StatusCode: 503, After: "10m", Duration: "5m", Clock: FaultClockSimulated}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	for _, step := range []struct {
+		advance time.Duration
+		active  bool
+	}{
+		{0, false},
+		{10 * time.Minute, true},
+		{4 * time.Minute, true},
+		{time.Minute, false},
+	} {
+		now = now.Add(step.advance)
+		if got := fr.Check("/v1/charges") != nil; got != step.active {
+			t.Errorf("at %s: expected active=%v, got %v", now.Format(time.TimeOnly), step.active, got)
+		}
+	}
+}
+
+func TestFaultRegistryInvalidSchedule(t *testing.T) {
+	start := time.Now()
+	end := start.Add(-time.Minute)
+	for name, fault := range map[string]FaultConfig{
+		"bad after":            {After: "soon"},
+		"negative duration":    {Duration: "-5m"},
+		"end before start":     {StartAt: &start, EndAt: &end},
+		"after with start_at":  {After: "1m", StartAt: &start},
+		"unknown clock":        {Clock: "lunar"},
+		"no simulated clock":   {Clock: FaultClockSimulated},
+		"duration with end_at": {Duration: "1m", EndAt: &end},
+	} {
+		fr := NewFaultRegistry()
+		fault.StatusCode = 503
+		if err := fr.Set("/x", fault); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if len(fr.All()) != 0 {
+			t.Errorf("%s: expected the fault not to be registered", name)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // IdempotencyTracker
 // ---------------------------------------------------------------------------
@@ -332,6 +406,65 @@ func TestRequestLogMiddlewareVerbose(t *testing.T) {
 	}
 }
 
+func TestRequestLogMiddlewareCaptureBodies(t *testing.T) {
+	cfg := &Config{CaptureBodies: true}
+	mw := NewMiddleware(cfg, slog.Default())
+
+	var seen string
+	handler := mw.RequestLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		seen = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/v1/charges?expand=customer", strings.NewReader("amount=100"))
+	req.Header.Set("Authorization", "Bearer sk_test")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if seen != "amount=100" {
+		t.Errorf("expected downstream handler to read body, got %q", seen)
+	}
+
+	entries := mw.ReqLog.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.ID != "req_000001" {
+		t.Errorf("expected ID req_000001, got %q", e.ID)
+	}
+	if !e.BodyCaptured || e.Body != "amount=100" {
+		t.Errorf("expected captured body, got captured=%v body=%q", e.BodyCaptured, e.Body)
+	}
+	if e.Query != "expand=customer" {
+		t.Errorf("expected query expand=customer, got %q", e.Query)
+	}
+	if e.Headers["Authorization"] != "Bearer sk_test" {
+		t.Errorf("expected Authorization header to be captured, got %v", e.Headers)
+	}
+}
+
+func TestRequestLogGet(t *testing.T) {
+	rl := NewRequestLog(10)
+	rl.Add(RequestLogEntry{Path: "/a"})
+	rl.Add(RequestLogEntry{Path: "/b"})
+
+	e, ok := rl.Get("req_000002")
+	if !ok || e.Path != "/b" {
+		t.Errorf("expected /b for req_000002, got %+v (found=%v)", e, ok)
+	}
+	if _, ok := rl.Get("req_000003"); ok {
+		t.Error("expected req_000003 to be missing")
+	}
+
+	rl.Clear()
+	rl.Add(RequestLogEntry{Path: "/c"})
+	if e := rl.Entries()[0]; e.ID != "req_000001" {
+		t.Errorf("expected ID counter to reset on Clear, got %q", e.ID)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Middleware – FaultInjection
 // ---------------------------------------------------------------------------
@@ -359,6 +492,89 @@ func TestFaultInjectionMiddleware(t *testing.T) {
 	}
 }
 
+func TestFaultInjectionForFixedEndpoint(t *testing.T) {
+	cfg := &Config{}
+	mw := NewMiddleware(cfg, slog.Default())
+	handler := mw.FaultInjectionFor("/*")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/stripe.com", nil))
+	if rec.Code != 200 {
+		t.Fatalf("expected 200 with no fault, got %d", rec.Code)
+	}
+
+	mw.Faults.Set("/*", FaultConfig{StatusCode: 429, Rate: 1.0})
+	for _, path := range []string{"/stripe.com", "/github.com"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != 429 {
+			t.Errorf("%s: expected 429 from the /* fault, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestFaultInjectionRetryAfter(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	handler := mw.FaultInjection(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	mw.Faults.Set("/v1/limited", FaultConfig{StatusCode: 429})
+	mw.Faults.Set("/v1/slow", FaultConfig{StatusCode: 429, RetryAfter: 30})
+	mw.Faults.Set("/v1/down", FaultConfig{StatusCode: 503})
+
+	for path, want := range map[string]string{"/v1/limited": "1", "/v1/slow": "30", "/v1/down": ""} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if got := rec.Header().Get("Retry-After"); got != want {
+			t.Errorf("%s: expected Retry-After %q, got %q", path, want, got)
+		}
+	}
+	if err := mw.Faults.Set("/v1/bad", FaultConfig{StatusCode: 429, RetryAfter: -1}); err == nil {
+		t.Error("expected a negative retry_after to be rejected")
+	}
+}
+
+func TestFaultInjectionCountsHits(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	handler := mw.FaultInjection(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	mw.Faults.Set("/v1/down", FaultConfig{StatusCode: 503})
+	mw.Faults.Set("/v1/slow", FaultConfig{Delay: time.Millisecond})
+	mw.Faults.Set("/v1/later", FaultConfig{StatusCode: 503, After: "1h"})
+
+	for _, path := range []string{"/v1/down", "/v1/down", "/v1/slow", "/v1/later", "/v1/ok"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		want := mw.Faults.All()[path].ID
+		if path == "/v1/later" {
+			want = ""
+		}
+		if got := rec.Header().Get(FaultHeader); got != want {
+			t.Errorf("%s: expected %s %q, got %q", path, FaultHeader, want, got)
+		}
+	}
+
+	faults := mw.Faults.All()
+	for path, want := range map[string]int{"/v1/down": 2, "/v1/slow": 1, "/v1/later": 0} {
+		f := faults[path]
+		if f.Hits != want {
+			t.Errorf("%s: expected %d hits, got %d", path, want, f.Hits)
+		}
+		if (f.LastHitAt != nil) != (want > 0) {
+			t.Errorf("%s: expected last_hit_at set only after a hit, got %v", path, f.LastHitAt)
+		}
+	}
+
+	down := faults["/v1/down"].ID
+	mw.Faults.Set("/v1/down", FaultConfig{StatusCode: 500})
+	if f := mw.Faults.All()["/v1/down"]; f.ID == down || f.Hits != 0 {
+		t.Errorf("expected a replaced fault to get a new ID and no hits, got %s with %d hits", f.ID, f.Hits)
+	}
+}
+
 func TestFaultInjectionWithCustomBody(t *testing.T) {
 	cfg := &Config{}
 	mw := NewMiddleware(cfg, slog.Default())
@@ -482,6 +698,91 @@ func TestRandomFailureNever(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// AdminReadOnly
+// ---------------------------------------------------------------------------
+
+func TestAdminReadOnly(t *testing.T) {
+	tests := []struct {
+		readOnly bool
+		method   string
+		path     string
+		want     int
+	}{
+		{true, "POST", "/admin/reset", http.StatusForbidden},
+		{true, "POST", "/admin/state", http.StatusForbidden},
+		{true, "PUT", "/admin/config", http.StatusForbidden},
+		{true, "DELETE", "/admin/fault/v1/charges", http.StatusForbidden},
+		{true, "POST", "/admin/time/advance", http.StatusForbidden},
+		{true, "GET", "/admin/state", http.StatusOK},
+		{true, "HEAD", "/admin/health", http.StatusOK},
+		{true, "POST", "/admin/state/lint", http.StatusOK},
+		{true, "POST", "/v1/charges", http.StatusOK},
+		{false, "POST", "/admin/reset", http.StatusOK},
+	}
+	for _, tt := range tests {
+		mw := NewMiddleware(&Config{AdminReadOnly: tt.readOnly}, slog.Default())
+		handler := mw.AdminReadOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if rec.Code != tt.want {
+			t.Errorf("read-only=%v %s %s: expected %d, got %d", tt.readOnly, tt.method, tt.path, tt.want, rec.Code)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// StoreCapacity
+// ---------------------------------------------------------------------------
+
+func TestStoreCapacity(t *testing.T) {
+	full := errors.New("customers holds 2 records (limit 2)")
+	tests := []struct {
+		capacity error
+		method   string
+		path     string
+		want     int
+	}{
+		{full, "POST", "/v1/customers", http.StatusInsufficientStorage},
+		{full, "PATCH", "/v1/customers/1", http.StatusInsufficientStorage},
+		{full, "GET", "/v1/customers", http.StatusOK},
+		{full, "DELETE", "/v1/customers/1", http.StatusOK},
+		{full, "POST", "/admin/reset", http.StatusOK},
+		{nil, "POST", "/v1/customers", http.StatusOK},
+	}
+	for _, tt := range tests {
+		mw := NewMiddleware(&Config{}, slog.Default())
+		mw.Capacity = func() error { return tt.capacity }
+		handler := mw.StoreCapacity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if rec.Code != tt.want {
+			t.Errorf("full=%v %s %s: expected %d, got %d", tt.capacity != nil, tt.method, tt.path, tt.want, rec.Code)
+		}
+		if rec.Code == http.StatusInsufficientStorage && !strings.Contains(rec.Body.String(), "limit 2") {
+			t.Errorf("expected the limit in the error, got %s", rec.Body.String())
+		}
+	}
+
+	// Without a Capacity check the middleware passes everything through.
+	mw := NewMiddleware(&Config{}, slog.Default())
+	rec := httptest.NewRecorder()
+	mw.StoreCapacity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})).ServeHTTP(rec, httptest.NewRequest("POST", "/v1/customers", nil))
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected 201 without a capacity check, got %d", rec.Code)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // NewMiddleware
 // ---------------------------------------------------------------------------
@@ -499,4 +800,59 @@ func TestNewMiddleware(t *testing.T) {
 	if mw.Idempotent == nil {
 		t.Error("expected non-nil Idempotent")
 	}
+	if mw.Rand == nil || mw.Rand.Deterministic() {
+		t.Error("expected a non-deterministic Rand without a seed")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Random
+// ---------------------------------------------------------------------------
+
+func TestRandomSeededIsReproducible(t *testing.T) {
+	draw := func(r *Random) string {
+		return r.Digits(6) + "/" + r.Hex(4)
+	}
+	a, b := NewRandom(42), NewRandom(42)
+	for i := 0; i < 3; i++ {
+		if x, y := draw(a), draw(b); x != y {
+			t.Fatalf("draw %d: expected equal sequences, got %s and %s", i, x, y)
+		}
+	}
+	if got := NewRandom(7); draw(got) == draw(NewRandom(42)) {
+		t.Error("expected different seeds to produce different values")
+	}
+}
+
+func TestRandomReset(t *testing.T) {
+	r := NewRandom(42)
+	first := r.Digits(6)
+	r.Digits(6)
+	r.Reset()
+	if got := r.Digits(6); got != first {
+		t.Errorf("expected Reset to restart the sequence at %s, got %s", first, got)
+	}
+
+	r.Reseed(0)
+	if r.Deterministic() || r.Seed() != 0 {
+		t.Error("expected Reseed(0) to turn deterministic mode off")
+	}
+}
+
+func TestFaultRegistryPartialRateSeeded(t *testing.T) {
+	hits := func() []bool {
+		mw := NewMiddleware(&Config{RandSeed: 9}, slog.Default())
+		mw.Faults.Set("/v1/charges", FaultConfig{StatusCode: 500, Rate: 0.5})
+		var out []bool
+		for i := 0; i < 20; i++ {
+			out = append(out, mw.Faults.Check("/v1/charges") != nil)
+		}
+		return out
+	}
+	a, b := hits(), hits()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("expected seeded fault draws to match, differ at %d: %v vs %v", i, a, b)
+		}
+	}
 }
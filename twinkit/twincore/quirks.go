@@ -0,0 +1,186 @@
+package twincore
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// QuirkStatus describes the state of a single quirk.
+type QuirkStatus struct {
+	ID       string `json:"id"`
+	Summary  string `json:"summary"`
+	Enabled  bool   `json:"enabled"`
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+}
+
+// Built-in quirks, available on every twin through Middleware.Quirks. They
+// exercise client transport and parsing code without per-twin work; see
+// ResponseQuirks for all but the first, HTTPCache for QuirkStaleCache, and
+// BodyLimit for QuirkTruncatedBodies.
+const (
+	// QuirkMislabeledEncoding swaps the Content-Encoding label on compressed
+	// responses: gzip bodies are labeled br and br bodies gzip.
+	QuirkMislabeledEncoding = "WT-Q-001"
+	// QuirkLargeResponse pads every API response with several megabytes.
+	QuirkLargeResponse = "WT-Q-002"
+	// QuirkInvalidUTF8 injects invalid UTF-8 into the first JSON string value.
+	QuirkInvalidUTF8 = "WT-Q-003"
+	// QuirkHTMLErrorPages replaces 5xx bodies with a CDN-style HTML page.
+	QuirkHTMLErrorPages = "WT-Q-004"
+	// QuirkChunkedDelays streams the body in pieces with pauses between them.
+	QuirkChunkedDelays = "WT-Q-005"
+)
+
+// QuirkInvalidCursors rejects a random share of pagination cursors as
+// invalid. Unlike the built-in quirks it only exists on twins whose stores
+// use signed cursors; see Middleware.CursorInvalidator.
+const QuirkInvalidCursors = "WT-Q-006"
+
+// invalidCursorRate is the share of cursors QuirkInvalidCursors rejects.
+const invalidCursorRate = 0.25
+
+// QuirkListLag keeps newly created records out of list responses for
+// Config.ListLag while GET by ID finds them at once, as when a provider's
+// list endpoints read from a lagging replica. It only exists on twins whose
+// stores support it; see Middleware.ListLag.
+const QuirkListLag = "WT-Q-007"
+
+// defaultListLag is the lag QuirkListLag applies when Config.ListLag is
+// unset.
+const defaultListLag = 2 * time.Second
+
+// BuiltinQuirks returns the quirks twincore implements for every twin, all
+// disabled.
+func BuiltinQuirks() []QuirkStatus {
+	return []QuirkStatus{
+		{ID: QuirkMislabeledEncoding, Summary: "Compressed responses carry the wrong Content-Encoding (gzip labeled br, br labeled gzip)", Type: "inconsistency", Severity: "moderate"},
+		{ID: QuirkLargeResponse, Summary: "API responses are padded to over 5 MB", Type: "side_effect", Severity: "moderate"},
+		{ID: QuirkInvalidUTF8, Summary: "The first string value in each JSON response contains invalid UTF-8", Type: "inconsistency", Severity: "critical"},
+		{ID: QuirkHTMLErrorPages, Summary: "5xx responses are CDN-style text/html pages instead of JSON", Type: "inconsistency", Severity: "moderate"},
+		{ID: QuirkChunkedDelays, Summary: "Response bodies arrive in four chunks 250ms apart", Type: "temporal", Severity: "minor"},
+		{ID: QuirkStaleCache, Summary: "GET responses are served from a cache that keeps returning the first representation of a URL after the record changes", Type: "temporal", Severity: "moderate"},
+		{ID: QuirkTruncatedBodies, Summary: "Request bodies over the size limit are silently cut to the limit instead of rejected", Type: "side_effect", Severity: "critical"},
+	}
+}
+
+// QuirkRegistry is a set of quirks that can be toggled at runtime. It
+// implements admin.QuirkStore. Twins add their own quirks with Register.
+type QuirkRegistry struct {
+	mu      sync.RWMutex
+	quirks  []QuirkStatus
+	enabled map[string]bool
+}
+
+// NewQuirkRegistry creates a registry holding the given quirks. Quirks
+// marked Enabled start enabled.
+func NewQuirkRegistry(quirks ...QuirkStatus) *QuirkRegistry {
+	qr := &QuirkRegistry{enabled: make(map[string]bool)}
+	qr.Register(quirks...)
+	return qr
+}
+
+// Register adds quirks, replacing any with the same ID.
+func (qr *QuirkRegistry) Register(quirks ...QuirkStatus) {
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+	for _, q := range quirks {
+		qr.enabled[q.ID] = q.Enabled
+		replaced := false
+		for i := range qr.quirks {
+			if qr.quirks[i].ID == q.ID {
+				qr.quirks[i] = q
+				replaced = true
+			}
+		}
+		if !replaced {
+			qr.quirks = append(qr.quirks, q)
+		}
+	}
+}
+
+// ListQuirks returns every quirk in registration order.
+func (qr *QuirkRegistry) ListQuirks() []QuirkStatus {
+	qr.mu.RLock()
+	defer qr.mu.RUnlock()
+	out := make([]QuirkStatus, len(qr.quirks))
+	for i, q := range qr.quirks {
+		q.Enabled = qr.enabled[q.ID]
+		out[i] = q
+	}
+	return out
+}
+
+// EnableQuirk turns a quirk on.
+func (qr *QuirkRegistry) EnableQuirk(id string) error {
+	return qr.set(id, true)
+}
+
+// DisableQuirk turns a quirk off.
+func (qr *QuirkRegistry) DisableQuirk(id string) error {
+	return qr.set(id, false)
+}
+
+// IsEnabled reports whether the quirk is on. Unknown quirks are off.
+func (qr *QuirkRegistry) IsEnabled(id string) bool {
+	qr.mu.RLock()
+	defer qr.mu.RUnlock()
+	return qr.enabled[id]
+}
+
+func (qr *QuirkRegistry) set(id string, on bool) error {
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+	if _, ok := qr.enabled[id]; !ok {
+		return fmt.Errorf("unknown quirk %q", id)
+	}
+	qr.enabled[id] = on
+	return nil
+}
+
+// CursorInvalidator registers QuirkInvalidCursors and returns a function
+// for store.CursorOptions.Invalidate that, while the quirk is on, rejects
+// one cursor in four, so client pagination retry logic gets exercised:
+//
+//	memStore.Contacts.SetCursors(store.CursorOptions{
+//		TTL:        10 * time.Minute,
+//		Clock:      memStore.Clock,
+//		Invalidate: twin.Middleware().CursorInvalidator(),
+//	})
+//
+// Stores can share one invalidator or each call it; the quirk is
+// registered once.
+func (m *Middleware) CursorInvalidator() func() bool {
+	m.cursorQuirk.Do(func() {
+		m.Quirks.Register(QuirkStatus{ID: QuirkInvalidCursors, Summary: "One pagination cursor in four is rejected as invalid before it expires", Type: "temporal", Severity: "moderate"})
+	})
+	return func() bool {
+		return m.Quirks.IsEnabled(QuirkInvalidCursors) && m.Rand.Float64() < invalidCursorRate
+	}
+}
+
+// ListLag registers QuirkListLag and returns a function for
+// store.ListLag.Lag that, while the quirk is on, reports Config.ListLag
+// (--list-lag, or list_lag in /admin/config), and zero otherwise.
+// admin.NewHandler attaches it to every collection of the twin's state.
+// The quirk is registered once however often ListLag is called.
+func (m *Middleware) ListLag() func() time.Duration {
+	m.listLagQuirk.Do(func() {
+		m.Quirks.Register(QuirkStatus{ID: QuirkListLag, Summary: "New records are missing from list responses for a while after they are created, though GET by ID finds them", Type: "temporal", Severity: "moderate"})
+	})
+	return func() time.Duration {
+		if !m.Quirks.IsEnabled(QuirkListLag) {
+			return 0
+		}
+		return m.cfg.listLag()
+	}
+}
+
+// listLag returns the lag QuirkListLag applies.
+func (c *Config) listLag() time.Duration {
+	if c.ListLag > 0 {
+		return c.ListLag
+	}
+	return defaultListLag
+}
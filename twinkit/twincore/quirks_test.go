@@ -0,0 +1,82 @@
+package twincore
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestQuirkRegistry(t *testing.T) {
+	qr := NewQuirkRegistry(BuiltinQuirks()...)
+	qr.Register(QuirkStatus{ID: "STRIPE-Q-001", Summary: "custom", Enabled: true})
+
+	list := qr.ListQuirks()
+	n := len(BuiltinQuirks())
+	if len(list) != n+1 || list[0].ID != QuirkMislabeledEncoding || !list[n].Enabled {
+		t.Fatalf("unexpected quirks %+v", list)
+	}
+
+	if err := qr.EnableQuirk(QuirkMislabeledEncoding); err != nil || !qr.IsEnabled(QuirkMislabeledEncoding) {
+		t.Errorf("expected %s enabled, err=%v", QuirkMislabeledEncoding, err)
+	}
+	if err := qr.DisableQuirk("STRIPE-Q-001"); err != nil || qr.IsEnabled("STRIPE-Q-001") {
+		t.Errorf("expected STRIPE-Q-001 disabled, err=%v", err)
+	}
+	if err := qr.EnableQuirk("NOPE-Q-1"); err == nil {
+		t.Error("expected an error for an unknown quirk")
+	}
+	if qr.IsEnabled("NOPE-Q-1") {
+		t.Error("expected unknown quirks to be off")
+	}
+}
+
+func TestCursorInvalidator(t *testing.T) {
+	mw := NewMiddleware(&Config{RandSeed: 7}, slog.Default())
+	invalidate := mw.CursorInvalidator()
+	mw.CursorInvalidator()
+
+	n := 0
+	for _, q := range mw.Quirks.ListQuirks() {
+		if q.ID == QuirkInvalidCursors {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Fatalf("expected %s registered once, got %d", QuirkInvalidCursors, n)
+	}
+
+	for i := 0; i < 100; i++ {
+		if invalidate() {
+			t.Fatal("expected no cursors rejected while the quirk is off")
+		}
+	}
+	mw.Quirks.EnableQuirk(QuirkInvalidCursors)
+	rejected := 0
+	for i := 0; i < 1000; i++ {
+		if invalidate() {
+			rejected++
+		}
+	}
+	if rejected < 150 || rejected > 350 {
+		t.Errorf("expected about a quarter of cursors rejected, got %d in 1000", rejected)
+	}
+}
+
+func TestListLag(t *testing.T) {
+	cfg := &Config{}
+	mw := NewMiddleware(cfg, slog.Default())
+	lag := mw.ListLag()
+	if lag() != 0 {
+		t.Error("expected no lag while the quirk is off")
+	}
+	if err := mw.Quirks.EnableQuirk(QuirkListLag); err != nil {
+		t.Fatal(err)
+	}
+	if got := lag(); got != defaultListLag {
+		t.Errorf("expected the default lag, got %v", got)
+	}
+	cfg.ListLag = 10 * time.Second
+	if got := lag(); got != 10*time.Second {
+		t.Errorf("expected the configured lag, got %v", got)
+	}
+}
@@ -0,0 +1,97 @@
+package twincore
+
+import (
+	"encoding/hex"
+	"math/rand/v2"
+	"strings"
+	"sync"
+)
+
+// Random is the source of every random value a twin produces: generated
+// codes, fault and fail-rate draws, and latency jitter. With a non-zero seed
+// (--rand-seed or rand_seed in /admin/config) it is deterministic, so two
+// runs that make the same requests get the same codes and the same faults,
+// which keeps golden-file tests stable. IDs are sequential and deterministic
+// regardless (see store.Store.NextID).
+type Random struct {
+	mu   sync.Mutex
+	seed uint64
+	r    *rand.Rand
+}
+
+// NewRandom creates a Random. A zero seed draws from a randomly seeded
+// generator instead.
+func NewRandom(seed uint64) *Random {
+	r := &Random{}
+	r.Reseed(seed)
+	return r
+}
+
+// Seed returns the seed, or 0 when the generator is not deterministic.
+func (r *Random) Seed() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.seed
+}
+
+// Deterministic reports whether the generator was seeded.
+func (r *Random) Deterministic() bool {
+	return r.Seed() != 0
+}
+
+// Reseed switches to a new seed and restarts its sequence. Zero turns
+// deterministic mode off.
+func (r *Random) Reseed(seed uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seed = seed
+	if seed == 0 {
+		r.r = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+		return
+	}
+	r.r = rand.New(rand.NewPCG(seed, seed))
+}
+
+// Reset restarts a deterministic sequence from its seed, so state reset
+// via /admin/reset replays the same values. It is a no-op otherwise.
+func (r *Random) Reset() {
+	if seed := r.Seed(); seed != 0 {
+		r.Reseed(seed)
+	}
+}
+
+// Float64 returns a value in [0.0, 1.0).
+func (r *Random) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Float64()
+}
+
+// IntN returns a value in [0, n).
+func (r *Random) IntN(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.IntN(n)
+}
+
+// Digits returns n random decimal digits, e.g. for verification codes.
+func (r *Random) Digits(n int) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var b strings.Builder
+	for range n {
+		b.WriteByte(byte('0' + r.r.IntN(10)))
+	}
+	return b.String()
+}
+
+// Hex returns n random bytes, hex-encoded.
+func (r *Random) Hex(n int) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.r.Uint32())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,176 @@
+package twincore
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RegionHeader selects the region a request is routed to, and is echoed on
+// every API response with the region that served it.
+const RegionHeader = "X-WT-Region"
+
+// Region is a simulated deployment region of a globally distributed
+// provider, for testing read-after-write behavior across regions.
+type Region struct {
+	Name string
+	// Latency replaces Config.Latency for requests routed to the region;
+	// zero means the twin's base latency.
+	Latency time.Duration
+	// ReplicationLag is how long writes made in other regions take to
+	// become visible here. See store.Replication.
+	ReplicationLag time.Duration
+}
+
+// ParseRegions parses the --regions flag: comma-separated entries of the
+// form name, name=latency, or name=latency/lag, e.g.
+// "us-east=20ms,eu-west=120ms/2s".
+func ParseRegions(s string) ([]Region, error) {
+	var regions []Region
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, _ := strings.Cut(entry, "=")
+		region := Region{Name: strings.TrimSpace(name)}
+		if region.Name == "" {
+			return nil, fmt.Errorf("region %q has no name", entry)
+		}
+		if seen[region.Name] {
+			return nil, fmt.Errorf("region %q is listed twice", region.Name)
+		}
+		seen[region.Name] = true
+		if spec != "" {
+			latency, lag, hasLag := strings.Cut(spec, "/")
+			var err error
+			if region.Latency, err = parseRegionDuration(latency); err != nil {
+				return nil, fmt.Errorf("region %s latency: %w", region.Name, err)
+			}
+			if hasLag {
+				if region.ReplicationLag, err = parseRegionDuration(lag); err != nil {
+					return nil, fmt.Errorf("region %s replication lag: %w", region.Name, err)
+				}
+			}
+		}
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+func parseRegionDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative, got %s", s)
+	}
+	return d, nil
+}
+
+// regionConfig describes regions for GetConfig, with durations written
+// the way --regions takes them.
+func regionConfig(regions []Region) []map[string]any {
+	out := make([]map[string]any, len(regions))
+	for i, region := range regions {
+		out[i] = map[string]any{
+			"name":            region.Name,
+			"latency":         region.Latency.String(),
+			"replication_lag": region.ReplicationLag.String(),
+		}
+	}
+	return out
+}
+
+// regionKey is the context key for the region a request was routed to.
+type regionKey struct{}
+
+// RequestRegion returns the region Middleware.Regions routed r to, or "" if
+// the twin runs without regions. Handlers pass it to store.Store.Region so
+// their reads and writes see that region's view of the data.
+func RequestRegion(r *http.Request) string {
+	region, _ := r.Context().Value(regionKey{}).(*Region)
+	if region == nil {
+		return ""
+	}
+	return region.Name
+}
+
+// Regions routes each API request to one of Config.Regions: the one named
+// by RegionHeader, or else the one named by the first label of the host, as
+// in eu-west.api.example.test. Requests that select neither are served by
+// the primary region, which sees every write at once. An unknown region in
+// the header is a 400. Admin endpoints are never routed.
+func (m *Middleware) Regions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(m.cfg.Regions) == 0 || strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var region *Region
+		if name := strings.TrimSpace(r.Header.Get(RegionHeader)); name != "" {
+			if region = m.region(name); region == nil {
+				Error(w, http.StatusBadRequest, fmt.Sprintf("unknown region %q (known: %s)", name, strings.Join(m.regionNames(), ", ")))
+				return
+			}
+		} else {
+			host := r.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			if label, _, ok := strings.Cut(host, "."); ok {
+				region = m.region(label)
+			}
+		}
+		if region == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set(RegionHeader, region.Name)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), regionKey{}, region)))
+	})
+}
+
+// ReplicationLags returns each configured region's replication lag, for
+// store.Replication. Regions without lag are left out; nil means no region
+// has any.
+func (m *Middleware) ReplicationLags() map[string]time.Duration {
+	var lags map[string]time.Duration
+	for _, region := range m.cfg.Regions {
+		if region.ReplicationLag > 0 {
+			if lags == nil {
+				lags = make(map[string]time.Duration)
+			}
+			lags[region.Name] = region.ReplicationLag
+		}
+	}
+	return lags
+}
+
+func (m *Middleware) region(name string) *Region {
+	for i := range m.cfg.Regions {
+		if m.cfg.Regions[i].Name == name {
+			return &m.cfg.Regions[i]
+		}
+	}
+	return nil
+}
+
+func (m *Middleware) regionNames() []string {
+	names := make([]string, len(m.cfg.Regions))
+	for i, region := range m.cfg.Regions {
+		names[i] = region.Name
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package twincore
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRegions(t *testing.T) {
+	got, err := ParseRegions("us-east=20ms, eu-west=120ms/2s,ap-south")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Region{
+		{Name: "us-east", Latency: 20 * time.Millisecond},
+		{Name: "eu-west", Latency: 120 * time.Millisecond, ReplicationLag: 2 * time.Second},
+		{Name: "ap-south"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if regions, err := ParseRegions(""); err != nil || regions != nil {
+		t.Errorf("empty: got %v, %v", regions, err)
+	}
+	for _, bad := range []string{"=20ms", "us-east,us-east", "us-east=fast", "us-east=20ms/-1s"} {
+		if _, err := ParseRegions(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestRegionsMiddleware(t *testing.T) {
+	regions, _ := ParseRegions("us-east,eu-west=0s/2s")
+	mw := NewMiddleware(&Config{Regions: regions}, slog.Default())
+	var got string
+	h := mw.Regions(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = RequestRegion(r)
+	}))
+
+	tests := []struct {
+		name, host, header, want string
+		status                   int
+	}{
+		{"header", "localhost:4111", "eu-west", "eu-west", http.StatusOK},
+		{"subdomain", "us-east.api.test:4111", "", "us-east", http.StatusOK},
+		{"header wins", "us-east.api.test", "eu-west", "eu-west", http.StatusOK},
+		{"unknown subdomain", "api.test", "", "", http.StatusOK},
+		{"unknown header", "localhost", "mars", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			req := httptest.NewRequest("GET", "/v1/things", nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set(RegionHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if got != tt.want || rec.Header().Get(RegionHeader) != tt.want {
+				t.Errorf("region = %q, header %q, want %q", got, rec.Header().Get(RegionHeader), tt.want)
+			}
+		})
+	}
+
+	if lags := mw.ReplicationLags(); !reflect.DeepEqual(lags, map[string]time.Duration{"eu-west": 2 * time.Second}) {
+		t.Errorf("ReplicationLags = %v", lags)
+	}
+}
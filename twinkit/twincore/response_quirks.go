@@ -0,0 +1,129 @@
+package twincore
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// largeResponsePadding is how much QuirkLargeResponse adds to each body.
+const largeResponsePadding = 5 << 20
+
+// chunkCount and chunkDelay shape QuirkChunkedDelays.
+const (
+	chunkCount = 4
+	chunkDelay = 250 * time.Millisecond
+)
+
+// ResponseQuirks applies the built-in response quirks (QuirkLargeResponse,
+// QuirkInvalidUTF8, QuirkHTMLErrorPages, QuirkChunkedDelays) to API
+// responses. Admin endpoints are never affected, so wt keeps working while
+// a quirk is on. With none enabled, responses pass through untouched.
+func (m *Middleware) ResponseQuirks(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := m.Quirks
+		if strings.HasPrefix(r.URL.Path, "/admin/") ||
+			!(q.IsEnabled(QuirkLargeResponse) || q.IsEnabled(QuirkInvalidUTF8) ||
+				q.IsEnabled(QuirkHTMLErrorPages) || q.IsEnabled(QuirkChunkedDelays)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		body := rec.body.Bytes()
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+
+		if q.IsEnabled(QuirkHTMLErrorPages) && rec.status >= 500 {
+			body = htmlErrorPage(rec.status)
+			w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		}
+		isJSON := strings.Contains(w.Header().Get("Content-Type"), "json")
+		if q.IsEnabled(QuirkInvalidUTF8) && isJSON {
+			body = injectInvalidUTF8(body)
+		}
+		if q.IsEnabled(QuirkLargeResponse) && r.Method != http.MethodHead {
+			body = padBody(body, isJSON)
+		}
+
+		w.Header().Del("Content-Length")
+		if !q.IsEnabled(QuirkChunkedDelays) {
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.WriteHeader(rec.status)
+			w.Write(body)
+			return
+		}
+
+		w.WriteHeader(rec.status)
+		rc := http.NewResponseController(w)
+		size := (len(body) + chunkCount - 1) / chunkCount
+		for i := 0; len(body) > 0; i++ {
+			if i > 0 {
+				time.Sleep(chunkDelay)
+			}
+			n := min(len(body), size)
+			w.Write(body[:n])
+			rc.Flush()
+			body = body[n:]
+		}
+	})
+}
+
+// bufferedResponse collects a handler's response so quirks can rewrite it.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedResponse) WriteHeader(code int)        { b.status = code }
+
+// htmlErrorPage returns the kind of page a CDN or load balancer serves when
+// the origin fails.
+func htmlErrorPage(status int) []byte {
+	title := fmt.Sprintf("%d %s", status, http.StatusText(status))
+	return []byte("<html>\r\n<head><title>" + title + "</title></head>\r\n<body>\r\n<center><h1>" + title +
+		"</h1></center>\r\n<hr><center>cloudflare</center>\r\n</body>\r\n</html>\r\n")
+}
+
+// injectInvalidUTF8 inserts bytes that are never valid UTF-8 at the start
+// of the first string value (the first `:"`) in a JSON body.
+func injectInvalidUTF8(body []byte) []byte {
+	i := bytes.Index(body, []byte(`:"`))
+	if i < 0 {
+		return body
+	}
+	i += 2
+	out := make([]byte, 0, len(body)+2)
+	out = append(out, body[:i]...)
+	out = append(out, 0xff, 0xfe)
+	return append(out, body[i:]...)
+}
+
+// padBody grows a body by largeResponsePadding. JSON objects get an extra
+// string field so they stay valid JSON; anything else gets trailing
+// whitespace, which JSON parsers also accept.
+func padBody(body []byte, isJSON bool) []byte {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if isJSON && len(trimmed) > 0 && trimmed[0] == '{' {
+		sep := ","
+		if bytes.HasPrefix(bytes.TrimLeft(trimmed[1:], " \t\r\n"), []byte("}")) {
+			sep = ""
+		}
+		var out bytes.Buffer
+		out.Grow(len(body) + largeResponsePadding + 32)
+		out.WriteString(`{"wt_padding":"`)
+		out.Write(bytes.Repeat([]byte("x"), largeResponsePadding))
+		out.WriteString(`"` + sep)
+		out.Write(trimmed[1:])
+		return out.Bytes()
+	}
+	return append(body, bytes.Repeat([]byte(" "), largeResponsePadding)...)
+}
@@ -0,0 +1,114 @@
+package twincore
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// serveQuirky runs a JSON handler through ResponseQuirks with the given
+// quirks enabled.
+func serveQuirky(t *testing.T, path string, status int, body string, quirks ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	mw := NewMiddleware(&Config{}, slog.Default())
+	for _, id := range quirks {
+		if err := mw.Quirks.EnableQuirk(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := mw.ResponseQuirks(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	return rec
+}
+
+func TestResponseQuirksPassThroughWhenDisabled(t *testing.T) {
+	rec := serveQuirky(t, "/v1/things", 200, `{"id":"thing_1"}`)
+	if rec.Body.String() != `{"id":"thing_1"}` {
+		t.Errorf("expected an untouched body, got %q", rec.Body.String())
+	}
+}
+
+func TestQuirkLargeResponse(t *testing.T) {
+	rec := serveQuirky(t, "/v1/things", 200, `{"id":"thing_1"}`, QuirkLargeResponse)
+	if rec.Body.Len() < largeResponsePadding {
+		t.Fatalf("expected at least %d bytes, got %d", largeResponsePadding, rec.Body.Len())
+	}
+	var v map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("expected valid JSON: %v", err)
+	}
+	if v["id"] != "thing_1" {
+		t.Errorf("expected original fields to survive, got id %v", v["id"])
+	}
+
+	rec = serveQuirky(t, "/v1/things", 200, `[1,2]`, QuirkLargeResponse)
+	var list []int
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list) != 2 {
+		t.Errorf("expected a padded array to stay valid JSON, got %v (%v)", list, err)
+	}
+
+	rec = serveQuirky(t, "/v1/things", 200, `{}`, QuirkLargeResponse)
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Errorf("expected a padded empty object to stay valid JSON: %v", err)
+	}
+}
+
+func TestQuirkInvalidUTF8(t *testing.T) {
+	rec := serveQuirky(t, "/v1/things", 200, `{"id":"thing_1","n":1}`, QuirkInvalidUTF8)
+	if utf8.Valid(rec.Body.Bytes()) {
+		t.Fatalf("expected invalid UTF-8, got %q", rec.Body.String())
+	}
+	if !strings.HasPrefix(rec.Body.String(), "{\"id\":\"\xff\xfething_1\"") {
+		t.Errorf("expected the bytes in the first string value, got %q", rec.Body.String())
+	}
+}
+
+func TestQuirkHTMLErrorPages(t *testing.T) {
+	rec := serveQuirky(t, "/v1/things", 502, `{"error":"upstream"}`, QuirkHTMLErrorPages)
+	if rec.Code != 502 {
+		t.Errorf("expected status 502, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("expected text/html, got %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "<h1>502 Bad Gateway</h1>") {
+		t.Errorf("expected an HTML error page, got %q", rec.Body.String())
+	}
+
+	rec = serveQuirky(t, "/v1/things", 404, `{"error":"missing"}`, QuirkHTMLErrorPages)
+	if rec.Body.String() != `{"error":"missing"}` {
+		t.Errorf("expected 4xx bodies to be untouched, got %q", rec.Body.String())
+	}
+}
+
+func TestQuirkChunkedDelays(t *testing.T) {
+	body := `{"data":["` + strings.Repeat("a", 100) + `"]}`
+	start := time.Now()
+	rec := serveQuirky(t, "/v1/things", 200, body, QuirkChunkedDelays)
+	if elapsed := time.Since(start); elapsed < (chunkCount-1)*chunkDelay {
+		t.Errorf("expected at least %s of delay, got %s", (chunkCount-1)*chunkDelay, elapsed)
+	}
+	if rec.Body.String() != body {
+		t.Errorf("expected the full body after reassembly, got %q", rec.Body.String())
+	}
+	if !rec.Flushed {
+		t.Error("expected chunks to be flushed")
+	}
+}
+
+func TestResponseQuirksSkipAdmin(t *testing.T) {
+	rec := serveQuirky(t, "/admin/state", 500, `{"id":"x"}`, QuirkHTMLErrorPages, QuirkInvalidUTF8, QuirkLargeResponse)
+	if rec.Body.String() != `{"id":"x"}` {
+		t.Errorf("expected admin responses to be untouched, got %d bytes", rec.Body.Len())
+	}
+}
@@ -8,10 +8,15 @@ import (
 	"flag"
 	"fmt"
 	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -28,29 +33,250 @@ type Config struct {
 	SeedFile   string
 	Verbose    bool
 	Name       string // twin name for logging
+
+	// Host is the address the twin listens on with Port, such as ::1 to
+	// accept only IPv6 loopback connections; empty means every interface.
+	// An IPv6 address listens on IPv6 only.
+	Host string
+	// Socket, when set, is a unix domain socket the twin listens on instead
+	// of a TCP port, for sandboxes where TCP ports are restricted or
+	// collide. See Listen.
+	Socket string
+
+	// WebhookDelivery is the webhook delivery guarantee: "at-most-once"
+	// (the default) drops failed webhooks; "at-least-once" redelivers them
+	// with backoff for hours and then dead-letters them. See
+	// webhook.Config.Guarantee.
+	WebhookDelivery string
+
+	// CaptureBodies records request headers and bodies in the request log
+	// so entries can be replayed via POST /admin/requests/{id}/replay.
+	CaptureBodies bool
+
+	// RandSeed makes generated codes, fault draws, and latency jitter
+	// reproducible across runs when non-zero. See Random.
+	RandSeed uint64
+
+	// DisableCompression turns off gzip/br response compression. See
+	// Middleware.Compression.
+	DisableCompression bool
+
+	// AdminReadOnly rejects admin requests that change the twin, such as
+	// reset, state loads, faults, and time travel, while inspection keeps
+	// working. See Middleware.AdminReadOnly. It cannot be changed at runtime.
+	AdminReadOnly bool
+
+	// ShadowURL mirrors every non-admin request to another twin or a real
+	// API sandbox and records response differences. See Middleware.Shadow.
+	ShadowURL string
+	// ShadowIgnore names JSON fields, such as "id" or "created", that are
+	// expected to differ and are skipped when comparing shadow responses.
+	ShadowIgnore []string
+
+	// StoreMaxRecords and StoreMaxMB bound each of the twin's stores, so
+	// long load tests cannot run the twin out of memory; zero means no
+	// limit. StoreLimitPolicy is "reject" (the default), which answers
+	// writes with 507 once a store is full, or "evict", which drops the
+	// oldest records. admin.NewHandler applies them. See store.Limits.
+	StoreMaxRecords  int
+	StoreMaxMB       int
+	StoreLimitPolicy string
+
+	// StoreScanWarn is how many records a Filter may scan before the twin
+	// logs a warning, at most once a minute per store, and counts a slow
+	// scan in GET /admin/state/stats; zero turns it off. admin.NewHandler
+	// applies it. See store.ScanWarning.
+	StoreScanWarn int
+
+	// Regions simulates a globally distributed provider: each request is
+	// routed to a region with its own latency, and writes reach other
+	// regions only after their replication lag. See Middleware.Regions.
+	Regions []Region
+
+	// ListLag is how long new records stay out of list responses while
+	// QuirkListLag is on; zero means two seconds. See Middleware.ListLag.
+	ListLag time.Duration
+
+	// CacheControl is the Cache-Control header for API GET responses that
+	// set none of their own; empty means none. CacheStale is how long
+	// QuirkStaleCache replays a response; zero means 30 seconds. See
+	// Middleware.HTTPCache.
+	CacheControl string
+	CacheStale   time.Duration
+
+	// MaxBodyKB rejects API request bodies over this many kilobytes; zero
+	// means no limit. BodyLimits overrides it per route, keyed by exact
+	// path or by a path ending in "/*" for everything under it. See
+	// Middleware.BodyLimit.
+	MaxBodyKB  int
+	BodyLimits map[string]int
+
+	// MaxInFlight and MaxRPS make the twin shed load like an overloaded
+	// provider: API requests beyond MaxInFlight concurrent ones, or beyond
+	// MaxRPS accepted in the last second, are answered with 503 and a
+	// Retry-After of BackpressureRetryAfter seconds (default 1). Zero means
+	// no threshold. See Middleware.Backpressure.
+	MaxInFlight            int
+	MaxRPS                 int
+	BackpressureRetryAfter int
+
+	// AuditKey signs /admin/audit/export with HMAC-SHA256 when set. It is
+	// never reported by /admin/config. See AuditLog.
+	AuditKey string
 }
 
+// Build metadata, set at build time via
+// -ldflags "-X github.com/wondertwin-ai/wondertwin/twinkit/twincore.Version=...".
+// Commit and BuildDate fall back to the VCS stamp Go embeds in the binary;
+// see ReadBuildInfo.
+var (
+	// Version is the twin release version.
+	Version = "dev"
+	// Commit is the git commit the twin was built from.
+	Commit = ""
+	// BuildDate is the build timestamp in RFC 3339 format.
+	BuildDate = ""
+)
+
 // ParseFlags parses common CLI flags and returns a Config.
-// The twinName is used for logging and identification.
+// The twinName is used for logging and identification. With --version it
+// prints the twin name and Version and exits.
 func ParseFlags(twinName string) *Config {
-	cfg := &Config{Name: twinName}
+	return ParseFlagsWithDescription(Description{Name: twinName})
+}
+
+// ParseFlagsWithDescription is ParseFlags for twins that describe themselves.
+// With --describe it prints desc as JSON (with Version filled in) and exits.
+// desc.DefaultPort is used when neither --port nor PORT is set.
+func ParseFlagsWithDescription(desc Description) *Config {
+	cfg := DefaultConfig(desc)
 	flag.IntVar(&cfg.Port, "port", 0, "HTTP listen port (default: auto-assigned)")
+	flag.StringVar(&cfg.Host, "host", "", "Address to listen on, e.g. ::1 for IPv6 loopback only (default: all interfaces)")
+	flag.StringVar(&cfg.Socket, "socket", "", "Listen on this unix domain socket instead of a TCP port")
 	flag.DurationVar(&cfg.Latency, "latency", 0, "Base simulated latency")
 	flag.Float64Var(&cfg.FailRate, "fail-rate", 0.0, "Random failure rate 0.0-1.0")
 	flag.StringVar(&cfg.WebhookURL, "webhook-url", "", "URL to send webhooks to")
+	flag.StringVar(&cfg.WebhookDelivery, "webhook-delivery", cfg.WebhookDelivery, "Webhook delivery guarantee: at-most-once (failed webhooks are dropped) or at-least-once (they are redelivered, then dead-lettered)")
 	flag.StringVar(&cfg.SeedFile, "seed-file", "", "Path to JSON fixture for initial state")
 	flag.BoolVar(&cfg.Verbose, "verbose", false, "Enable request/response logging")
+	flag.BoolVar(&cfg.CaptureBodies, "capture-bodies", false, "Record request bodies in the request log for replay")
+	flag.Uint64Var(&cfg.RandSeed, "rand-seed", 0, "Seed for reproducible generated codes and random faults (0 = random)")
+	flag.BoolVar(&cfg.DisableCompression, "disable-compression", false, "Never gzip/br-compress responses, whatever the client accepts")
+	flag.BoolVar(&cfg.AdminReadOnly, "admin-readonly", false, "Reject admin requests that modify the twin; inspection endpoints keep working")
+	flag.StringVar(&cfg.ShadowURL, "shadow-url", "", "Mirror requests to this base URL and record response differences at /admin/shadow/diffs")
+	flag.IntVar(&cfg.StoreMaxRecords, "store-max-records", 0, "Maximum records in each store (0 = unlimited)")
+	flag.IntVar(&cfg.StoreMaxMB, "store-max-mb", 0, "Maximum estimated size of each store in megabytes (0 = unlimited)")
+	flag.StringVar(&cfg.StoreLimitPolicy, "store-limit-policy", cfg.StoreLimitPolicy, "What a full store does: reject (writes fail with 507) or evict (oldest records are dropped)")
+	flag.IntVar(&cfg.StoreScanWarn, "store-scan-warn", cfg.StoreScanWarn, "Warn when a store filter scans more than this many records (0 = never)")
+	flag.DurationVar(&cfg.ListLag, "list-lag", cfg.ListLag, "How long new records stay out of list responses while quirk "+QuirkListLag+" is on")
+	flag.StringVar(&cfg.CacheControl, "cache-control", "", "Cache-Control header for API GET responses that set none, e.g. private, max-age=60")
+	flag.DurationVar(&cfg.CacheStale, "cache-stale", cfg.CacheStale, "How long a cached GET response is replayed while quirk "+QuirkStaleCache+" is on")
+	flag.StringVar(&cfg.AuditKey, "audit-key", cfg.AuditKey, "Key that signs the audit log export (default $WT_AUDIT_KEY)")
+	flag.IntVar(&cfg.MaxBodyKB, "max-body-kb", 0, "Reject API request bodies over this many kilobytes (0 = unlimited)")
+	flag.IntVar(&cfg.MaxInFlight, "max-in-flight", 0, "Answer API requests beyond this many concurrent ones with 503 and Retry-After (0 = unlimited)")
+	flag.IntVar(&cfg.MaxRPS, "max-rps", 0, "Answer API requests beyond this many per second with 503 and Retry-After (0 = unlimited)")
+	flag.IntVar(&cfg.BackpressureRetryAfter, "backpressure-retry-after", 0, "Retry-After, in seconds, of requests shed by --max-in-flight or --max-rps (default 1)")
+	bodyLimits := flag.String("body-limits", "", "Comma-separated per-route body limits as route=kilobytes, e.g. /v1/files=32768,/v1/accounts/*=64; overrides --max-body-kb")
+	regions := flag.String("regions", "", "Comma-separated simulated regions as name=latency/replication-lag, e.g. us-east=20ms,eu-west=120ms/2s; requests pick one with the X-WT-Region header or a subdomain")
+	shadowIgnore := flag.String("shadow-ignore", "", "Comma-separated JSON fields to skip when comparing shadow responses, e.g. id,created")
+	showVersion := flag.Bool("version", false, "Print the twin version and exit")
+	describe := flag.Bool("describe", false, "Print twin metadata as JSON and exit")
 	flag.Parse()
 
+	if *showVersion {
+		fmt.Printf("%s %s\n", desc.Name, Version)
+		os.Exit(0)
+	}
+
+	if *describe {
+		desc.Version = Version
+		for _, q := range BuiltinQuirks() {
+			desc.Quirks = append(desc.Quirks, q.ID)
+		}
+		desc.Faults = append(desc.Faults, BuiltinFaults()...)
+		if err := desc.WriteJSON(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "describe: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if cfg.StoreMaxRecords < 0 || cfg.StoreMaxMB < 0 || cfg.StoreScanWarn < 0 || cfg.MaxBodyKB < 0 {
+		fmt.Fprintln(os.Stderr, "--store-max-records, --store-max-mb, --store-scan-warn, and --max-body-kb must not be negative")
+		os.Exit(2)
+	}
+	if cfg.MaxInFlight < 0 || cfg.MaxRPS < 0 || cfg.BackpressureRetryAfter < 0 {
+		fmt.Fprintln(os.Stderr, "--max-in-flight, --max-rps, and --backpressure-retry-after must not be negative")
+		os.Exit(2)
+	}
+	if cfg.StoreLimitPolicy != "reject" && cfg.StoreLimitPolicy != "evict" {
+		fmt.Fprintf(os.Stderr, "--store-limit-policy must be reject or evict, got %q\n", cfg.StoreLimitPolicy)
+		os.Exit(2)
+	}
+	if cfg.WebhookDelivery != "at-least-once" && cfg.WebhookDelivery != "at-most-once" {
+		fmt.Fprintf(os.Stderr, "--webhook-delivery must be at-least-once or at-most-once, got %q\n", cfg.WebhookDelivery)
+		os.Exit(2)
+	}
+
+	if cfg.Socket != "" && cfg.Host != "" {
+		fmt.Fprintln(os.Stderr, "--socket and --host cannot be combined")
+		os.Exit(2)
+	}
+	if cfg.Host != "" && cfg.Host != "localhost" && net.ParseIP(cfg.Host) == nil {
+		fmt.Fprintf(os.Stderr, "--host must be an IP address or localhost, got %q\n", cfg.Host)
+		os.Exit(2)
+	}
+
+	var err error
+	if cfg.Regions, err = ParseRegions(*regions); err != nil {
+		fmt.Fprintf(os.Stderr, "--regions: %v\n", err)
+		os.Exit(2)
+	}
+
+	if cfg.BodyLimits, err = ParseBodyLimits(*bodyLimits); err != nil {
+		fmt.Fprintf(os.Stderr, "--body-limits: %v\n", err)
+		os.Exit(2)
+	}
+
+	for _, f := range strings.Split(*shadowIgnore, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			cfg.ShadowIgnore = append(cfg.ShadowIgnore, f)
+		}
+	}
+
 	if cfg.Port == 0 {
 		if p := os.Getenv("PORT"); p != "" {
 			fmt.Sscanf(p, "%d", &cfg.Port)
 		}
 	}
+	if cfg.Port == 0 {
+		cfg.Port = desc.DefaultPort
+	}
 
 	return cfg
 }
 
+// defaultStoreScanWarn is the StoreScanWarn twins start with: well above
+// what a test suite stores, so a warning points at a lookup that will not
+// scale rather than at a large seed.
+const defaultStoreScanWarn = 10000
+
+// DefaultConfig returns the Config ParseFlagsWithDescription returns when
+// no flags are given, for twins configured in code rather than from the
+// command line, such as those twin-multi hosts. Port is desc.DefaultPort.
+func DefaultConfig(desc Description) *Config {
+	return &Config{
+		Name:             desc.Name,
+		Port:             desc.DefaultPort,
+		WebhookDelivery:  "at-most-once",
+		StoreLimitPolicy: "reject",
+		StoreScanWarn:    defaultStoreScanWarn,
+		ListLag:          defaultListLag,
+		CacheStale:       defaultCacheStale,
+		AuditKey:         os.Getenv("WT_AUDIT_KEY"),
+	}
+}
+
 // Twin is the base server for a WonderTwin twin. It wraps a chi router with
 // common middleware and provides lifecycle management.
 type Twin struct {
@@ -59,6 +285,8 @@ type Twin struct {
 	Logger *slog.Logger
 	mw     *Middleware
 	mu     sync.RWMutex // protects Config fields during runtime updates
+
+	draining atomic.Bool // set once shutdown begins; fails health probes
 }
 
 // New creates a new Twin with the given config.
@@ -82,8 +310,22 @@ func New(cfg *Config) *Twin {
 	r.Use(chimw.RealIP)
 	r.Use(mw.CORS)
 	r.Use(mw.RequestLog)
+	r.Use(mw.AuditMutations)
+	r.Use(mw.AdminReadOnly)
+	r.Use(mw.Backpressure)
+	r.Use(mw.StoreCapacity)
+	r.Use(mw.BodyLimit)
+	r.Use(mw.Compression)
+	r.Use(mw.ResponseQuirks)
+	r.Use(mw.HTTPCache)
+	r.Use(mw.Regions)
+	r.Use(mw.InterceptResponses)
+	r.Use(mw.PauseAtBreakpoints)
 	r.Use(mw.LatencyInjection)
 	r.Use(mw.RandomFailure)
+	r.Use(mw.Shadow)
+	r.Use(mw.ResponseTemplates)
+	r.Use(mw.StorageFailures)
 
 	return &Twin{
 		Config: cfg,
@@ -98,32 +340,82 @@ func (t *Twin) Middleware() *Middleware {
 	return t.mw
 }
 
+// BuildInfo returns the twin's build metadata.
+// This implements the admin.VersionProvider interface.
+func (t *Twin) BuildInfo() BuildInfo {
+	return ReadBuildInfo(t.Config.Name)
+}
+
 // GetConfig returns the current runtime configuration as a map.
 // This implements the admin.ConfigProvider interface.
 func (t *Twin) GetConfig() map[string]any {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 	return map[string]any{
-		"name":        t.Config.Name,
-		"port":        t.Config.Port,
-		"latency":     t.Config.Latency.String(),
-		"fail_rate":   t.Config.FailRate,
-		"webhook_url": t.Config.WebhookURL,
-		"verbose":     t.Config.Verbose,
+		"name":           t.Config.Name,
+		"port":           t.Config.Port,
+		"host":           t.Config.Host,
+		"socket":         t.Config.Socket,
+		"latency":        t.Config.Latency.String(),
+		"fail_rate":      t.Config.FailRate,
+		"webhook_url":    t.Config.WebhookURL,
+		"verbose":        t.Config.Verbose,
+		"capture_bodies": t.Config.CaptureBodies,
+		"rand_seed":      t.mw.Rand.Seed(),
+		"deterministic":  t.mw.Rand.Deterministic(),
+		"compression":    !t.Config.DisableCompression,
+		"admin_readonly": t.Config.AdminReadOnly,
+		"shadow_url":     t.Config.ShadowURL,
+
+		"store_max_records":  t.Config.StoreMaxRecords,
+		"store_max_mb":       t.Config.StoreMaxMB,
+		"store_limit_policy": t.Config.StoreLimitPolicy,
+		"store_scan_warn":    t.Config.StoreScanWarn,
+
+		"webhook_delivery": t.Config.WebhookDelivery,
+
+		"regions": regionConfig(t.Config.Regions),
+
+		"list_lag": t.Config.listLag().String(),
+
+		"cache_control": t.Config.CacheControl,
+		"cache_stale":   t.Config.cacheStale().String(),
+
+		"max_body_kb": t.Config.MaxBodyKB,
+		"body_limits": bodyLimitConfig(t.Config.BodyLimits),
+
+		"max_in_flight":            t.Config.MaxInFlight,
+		"max_rps":                  t.Config.MaxRPS,
+		"backpressure_retry_after": t.Config.backpressureRetryAfter(),
 	}
 }
 
 // UpdateConfig updates runtime configuration fields from a map.
 // This implements the admin.ConfigProvider interface.
-// Only latency, fail_rate, verbose, and webhook_url can be updated at runtime.
+// Only latency, fail_rate, verbose, webhook_url, capture_bodies,
+// rand_seed, compression, shadow_url, list_lag, cache_control,
+// cache_stale, max_body_kb, body_limits, max_in_flight, max_rps, and
+// backpressure_retry_after can be updated at runtime.
 // All fields are validated before any are applied, ensuring atomicity.
 func (t *Twin) UpdateConfig(updates map[string]any) error {
 	// Phase 1: validate all updates before applying any
 	type configUpdate struct {
-		latency    *time.Duration
-		failRate   *float64
-		verbose    *bool
-		webhookURL *string
+		latency       *time.Duration
+		failRate      *float64
+		verbose       *bool
+		webhookURL    *string
+		captureBodies *bool
+		randSeed      *uint64
+		compression   *bool
+		shadowURL     *string
+		listLag       *time.Duration
+		cacheControl  *string
+		cacheStale    *time.Duration
+		maxBodyKB     *int
+		bodyLimits    map[string]int
+		maxInFlight   *int
+		maxRPS        *int
+		retryAfter    *int
 	}
 	var cu configUpdate
 
@@ -163,7 +455,108 @@ func (t *Twin) UpdateConfig(updates map[string]any) error {
 				return fmt.Errorf("webhook_url must be a string")
 			}
 			cu.webhookURL = &s
-		case "name", "port":
+		case "capture_bodies":
+			b, ok := v.(bool)
+			if !ok {
+				return fmt.Errorf("capture_bodies must be a boolean")
+			}
+			cu.captureBodies = &b
+		case "rand_seed":
+			f, ok := v.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) || f > 1<<53 {
+				return fmt.Errorf("rand_seed must be a non-negative integer")
+			}
+			seed := uint64(f)
+			cu.randSeed = &seed
+		case "compression":
+			b, ok := v.(bool)
+			if !ok {
+				return fmt.Errorf("compression must be a boolean")
+			}
+			cu.compression = &b
+		case "shadow_url":
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("shadow_url must be a string")
+			}
+			if s != "" {
+				if u, err := url.Parse(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("shadow_url must be an http:// or https:// URL, or empty to stop mirroring")
+				}
+			}
+			cu.shadowURL = &s
+		case "list_lag":
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("list_lag must be a duration string")
+			}
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("invalid list_lag duration: %w", err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("list_lag must be positive; disable quirk %s to list new records at once", QuirkListLag)
+			}
+			cu.listLag = &d
+		case "cache_control":
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("cache_control must be a string")
+			}
+			cu.cacheControl = &s
+		case "cache_stale":
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("cache_stale must be a duration string")
+			}
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("invalid cache_stale duration: %w", err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("cache_stale must be positive; disable quirk %s to stop serving stale responses", QuirkStaleCache)
+			}
+			cu.cacheStale = &d
+		case "max_body_kb":
+			f, ok := v.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				return fmt.Errorf("max_body_kb must be a non-negative integer")
+			}
+			kb := int(f)
+			cu.maxBodyKB = &kb
+		case "body_limits":
+			m, ok := v.(map[string]any)
+			if !ok {
+				return fmt.Errorf("body_limits must be an object of route to kilobytes")
+			}
+			cu.bodyLimits = map[string]int{}
+			for route, kb := range m {
+				f, ok := kb.(float64)
+				if !ok || f < 0 || f != math.Trunc(f) {
+					return fmt.Errorf("body_limits[%s] must be a non-negative integer", route)
+				}
+				if err := checkBodyLimitRoute(route); err != nil {
+					return fmt.Errorf("body_limits: %w", err)
+				}
+				cu.bodyLimits[route] = int(f)
+			}
+		case "max_in_flight", "max_rps", "backpressure_retry_after":
+			f, ok := v.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				return fmt.Errorf("%s must be a non-negative integer", k)
+			}
+			n := int(f)
+			switch k {
+			case "max_in_flight":
+				cu.maxInFlight = &n
+			case "max_rps":
+				cu.maxRPS = &n
+			default:
+				cu.retryAfter = &n
+			}
+		case "deterministic":
+			return fmt.Errorf("deterministic is read-only; set rand_seed instead")
+		case "name", "port", "host", "socket", "admin_readonly":
 			return fmt.Errorf("%s cannot be changed at runtime", k)
 		default:
 			return fmt.Errorf("unknown config key: %s", k)
@@ -185,28 +578,61 @@ func (t *Twin) UpdateConfig(updates map[string]any) error {
 	if cu.webhookURL != nil {
 		t.Config.WebhookURL = *cu.webhookURL
 	}
+	if cu.captureBodies != nil {
+		t.Config.CaptureBodies = *cu.captureBodies
+	}
+	if cu.randSeed != nil {
+		t.Config.RandSeed = *cu.randSeed
+		t.mw.Rand.Reseed(*cu.randSeed)
+	}
+	if cu.compression != nil {
+		t.Config.DisableCompression = !*cu.compression
+	}
+	if cu.shadowURL != nil {
+		t.Config.ShadowURL = *cu.shadowURL
+	}
+	if cu.listLag != nil {
+		t.Config.ListLag = *cu.listLag
+	}
+	if cu.cacheControl != nil {
+		t.Config.CacheControl = *cu.cacheControl
+	}
+	if cu.cacheStale != nil {
+		t.Config.CacheStale = *cu.cacheStale
+	}
+	if cu.maxBodyKB != nil {
+		t.Config.MaxBodyKB = *cu.maxBodyKB
+	}
+	if cu.bodyLimits != nil {
+		t.Config.BodyLimits = cu.bodyLimits
+	}
+	if cu.maxInFlight != nil {
+		t.Config.MaxInFlight = *cu.maxInFlight
+	}
+	if cu.maxRPS != nil {
+		t.Config.MaxRPS = *cu.maxRPS
+	}
+	if cu.retryAfter != nil {
+		t.Config.BackpressureRetryAfter = *cu.retryAfter
+	}
 	return nil
 }
 
 // Serve starts the HTTP server and blocks until shutdown signal.
 func (t *Twin) Serve() error {
-	addr := fmt.Sprintf(":%d", t.Config.Port)
-
-	srv := &http.Server{
-		Addr:         addr,
-		Handler:      t.Router,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	ln, err := t.Config.Listen()
+	if err != nil {
+		return err
 	}
+	srv := newServer(ln.Addr().String(), t)
 
 	// Graceful shutdown
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
-		t.Logger.Info("starting twin", "name", t.Config.Name, "addr", addr)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		t.Logger.Info("starting twin", "name", t.Config.Name, "addr", t.Config.Addr())
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			t.Logger.Error("server error", "err", err)
 			os.Exit(1)
 		}
@@ -214,14 +640,36 @@ func (t *Twin) Serve() error {
 
 	<-done
 	t.Logger.Info("shutting down twin", "name", t.Config.Name)
+	t.draining.Store(true)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	return srv.Shutdown(ctx)
 }
 
+// newServer returns the HTTP server twins listen with.
+func newServer(addr string, h http.Handler) *http.Server {
+	// Cleartext HTTP/2 alongside HTTP/1 lets gRPC health probes connect.
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	return &http.Server{
+		Addr:         addr,
+		Handler:      h,
+		Protocols:    protocols,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+}
+
 // ServeHTTP implements http.Handler so Twin can be used directly in tests.
+// Health probes (see health.go) are answered before the router.
 func (t *Twin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if t.serveHealth(w, r) {
+		return
+	}
 	t.Router.ServeHTTP(w, r)
 }
 
@@ -245,6 +693,20 @@ func Error(w http.ResponseWriter, status int, message string) {
 	})
 }
 
+// InvalidCursor writes the 400 for a pagination cursor the twin no longer
+// honors (see store.ErrInvalidCursor), with code "invalid_cursor", so
+// clients can tell it apart from other bad requests and restart from the
+// first page. Twins with their own error format write it themselves.
+func InvalidCursor(w http.ResponseWriter, err error) {
+	JSON(w, http.StatusBadRequest, map[string]any{
+		"error": map[string]any{
+			"message": err.Error() + "; restart pagination from the first page",
+			"type":    http.StatusText(http.StatusBadRequest),
+			"code":    "invalid_cursor",
+		},
+	})
+}
+
 // StripeError writes an error response in Stripe's error format.
 func StripeError(w http.ResponseWriter, status int, errType, code, message string) {
 	JSON(w, status, map[string]any{
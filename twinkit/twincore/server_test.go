@@ -1,10 +1,12 @@
 package twincore
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 // ---------------------------------------------------------------------------
@@ -200,6 +202,34 @@ func TestNewTwinWithLatencyAndFailRate(t *testing.T) {
 	}
 }
 
+func TestTwinUpdateConfigRandSeed(t *testing.T) {
+	twin := New(&Config{Name: "test-twin"})
+	if cfg := twin.GetConfig(); cfg["deterministic"] != false || cfg["rand_seed"] != uint64(0) {
+		t.Fatalf("expected deterministic mode off by default, got %v", cfg)
+	}
+
+	if err := twin.UpdateConfig(map[string]any{"rand_seed": float64(42)}); err != nil {
+		t.Fatalf("UpdateConfig() error: %v", err)
+	}
+	if cfg := twin.GetConfig(); cfg["deterministic"] != true || cfg["rand_seed"] != uint64(42) {
+		t.Errorf("expected rand_seed 42 to enable deterministic mode, got %v", cfg)
+	}
+	if want := NewRandom(42).Digits(6); twin.Middleware().Rand.Digits(6) != want {
+		t.Error("expected rand_seed to restart the sequence from the new seed")
+	}
+
+	for _, bad := range []map[string]any{
+		{"rand_seed": float64(-1)},
+		{"rand_seed": 1.5},
+		{"rand_seed": "42"},
+		{"deterministic": true},
+	} {
+		if err := twin.UpdateConfig(bad); err == nil {
+			t.Errorf("expected UpdateConfig(%v) to fail", bad)
+		}
+	}
+}
+
 func TestTwinServeHTTP(t *testing.T) {
 	cfg := &Config{Name: "test-twin"}
 	twin := New(cfg)
@@ -226,6 +256,102 @@ func TestTwinServeHTTP(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Description
+// ---------------------------------------------------------------------------
+
+func TestDescriptionWriteJSON(t *testing.T) {
+	desc := Description{
+		Name:         "twin-test",
+		Version:      "1.2.3",
+		SDKTarget:    SDKTarget{Package: "github.com/example/sdk", Version: "v2", APIVersion: "2024-01-01"},
+		DefaultPort:  4100,
+		Capabilities: []string{"clock"},
+		Faults: []NamedFault{
+			{Name: "down", Endpoint: "/v1/things", Fault: FaultConfig{StatusCode: 503, Rate: 1.0}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := desc.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON error: %v", err)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if body["name"] != "twin-test" || body["version"] != "1.2.3" {
+		t.Errorf("unexpected name/version: %+v", body)
+	}
+	if body["default_port"] != float64(4100) {
+		t.Errorf("expected default_port 4100, got %v", body["default_port"])
+	}
+	sdk := body["sdk_target"].(map[string]any)
+	if sdk["package"] != "github.com/example/sdk" || sdk["api_version"] != "2024-01-01" {
+		t.Errorf("unexpected sdk_target: %+v", sdk)
+	}
+	faults := body["faults"].([]any)
+	if len(faults) != 1 {
+		t.Fatalf("expected 1 fault, got %d", len(faults))
+	}
+	fault := faults[0].(map[string]any)["fault"].(map[string]any)
+	if fault["status_code"] != float64(503) {
+		t.Errorf("expected fault status 503, got %v", fault["status_code"])
+	}
+}
+
+func TestDescriptionWriteJSONEmptyLists(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (Description{Name: "twin-bare"}).WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON error: %v", err)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	for _, key := range []string{"capabilities", "quirks", "faults"} {
+		if list, ok := body[key].([]any); !ok || len(list) != 0 {
+			t.Errorf("expected %s to be an empty array, got %v", key, body[key])
+		}
+	}
+	if _, ok := body["default_port"]; ok {
+		t.Errorf("expected default_port to be omitted, got %v", body["default_port"])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Build info
+// ---------------------------------------------------------------------------
+
+func TestReadBuildInfoLdflags(t *testing.T) {
+	oldVersion, oldCommit, oldDate := Version, Commit, BuildDate
+	defer func() { Version, Commit, BuildDate = oldVersion, oldCommit, oldDate }()
+	Version, Commit, BuildDate = "1.4.0", "abc1234", "2026-01-02T03:04:05Z"
+
+	info := New(&Config{Name: "twin-test"}).BuildInfo()
+	if info.Name != "twin-test" || info.Version != "1.4.0" {
+		t.Errorf("unexpected name/version: %+v", info)
+	}
+	if info.Commit != "abc1234" || info.BuildDate != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected ldflags values to win, got %+v", info)
+	}
+}
+
+func TestReadBuildInfoDefaults(t *testing.T) {
+	info := ReadBuildInfo("twin-test")
+	if info.Commit == "" || info.BuildDate == "" {
+		t.Errorf("expected commit and build date to fall back to a placeholder, got %+v", info)
+	}
+	if info.TwinkitVersion == "" {
+		t.Error("expected twinkit version to be populated")
+	}
+	if info.GoVersion == "" {
+		t.Error("expected go version to be populated")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // statusRecorder
 // ---------------------------------------------------------------------------
@@ -249,3 +375,55 @@ func TestStatusRecorderExplicitCode(t *testing.T) {
 		t.Errorf("expected 404, got %d", sr.statusCode)
 	}
 }
+
+func TestTwinUpdateConfigCompression(t *testing.T) {
+	twin := New(&Config{Name: "test-twin"})
+	if cfg := twin.GetConfig(); cfg["compression"] != true {
+		t.Fatalf("expected compression on by default, got %v", cfg["compression"])
+	}
+	if err := twin.UpdateConfig(map[string]any{"compression": false}); err != nil {
+		t.Fatalf("UpdateConfig() error: %v", err)
+	}
+	if !twin.Config.DisableCompression {
+		t.Error("expected compression=false to disable compression")
+	}
+	if err := twin.UpdateConfig(map[string]any{"compression": "off"}); err == nil {
+		t.Error("expected a non-boolean compression to fail")
+	}
+}
+
+func TestTwinUpdateConfigListLag(t *testing.T) {
+	twin := New(&Config{Name: "test-twin"})
+	if cfg := twin.GetConfig(); cfg["list_lag"] != "2s" {
+		t.Fatalf("expected a 2s list lag by default, got %v", cfg["list_lag"])
+	}
+	if err := twin.UpdateConfig(map[string]any{"list_lag": "500ms"}); err != nil {
+		t.Fatalf("UpdateConfig() error: %v", err)
+	}
+	if twin.Config.ListLag != 500*time.Millisecond {
+		t.Errorf("expected list_lag 500ms, got %v", twin.Config.ListLag)
+	}
+	for _, bad := range []any{"0s", "-1s", "soon", 5} {
+		if err := twin.UpdateConfig(map[string]any{"list_lag": bad}); err == nil {
+			t.Errorf("expected list_lag %v to fail", bad)
+		}
+	}
+}
+
+func TestTwinUpdateConfigCaching(t *testing.T) {
+	twin := New(&Config{Name: "test-twin"})
+	if cfg := twin.GetConfig(); cfg["cache_control"] != "" || cfg["cache_stale"] != "30s" {
+		t.Fatalf("expected no Cache-Control and a 30s stale window by default, got %v %v", cfg["cache_control"], cfg["cache_stale"])
+	}
+	if err := twin.UpdateConfig(map[string]any{"cache_control": "no-cache", "cache_stale": "1m"}); err != nil {
+		t.Fatalf("UpdateConfig() error: %v", err)
+	}
+	if twin.Config.CacheControl != "no-cache" || twin.Config.CacheStale != time.Minute {
+		t.Errorf("expected cache_control no-cache and cache_stale 1m, got %q %v", twin.Config.CacheControl, twin.Config.CacheStale)
+	}
+	for _, bad := range []any{"0s", "later", 5} {
+		if err := twin.UpdateConfig(map[string]any{"cache_stale": bad}); err == nil {
+			t.Errorf("expected cache_stale %v to fail", bad)
+		}
+	}
+}
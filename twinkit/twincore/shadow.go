@@ -0,0 +1,262 @@
+package twincore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// maxShadowInFlight caps concurrent mirrored requests. Requests arriving
+// while the target is this far behind are counted as dropped rather than
+// queued, so a slow target never holds up the twin.
+const maxShadowInFlight = 32
+
+// maxShadowDifferences caps the differences recorded for one request.
+const maxShadowDifferences = 50
+
+// shadowHopHeaders are not copied onto mirrored requests. Accept-Encoding is
+// left to the Go transport so the target's body arrives decompressed.
+var shadowHopHeaders = []string{"Connection", "Keep-Alive", "Te", "Trailer", "Transfer-Encoding", "Upgrade", "Accept-Encoding"}
+
+// ShadowDiff records one mirrored request whose response from the shadow
+// target did not match the twin's.
+type ShadowDiff struct {
+	ID           string    `json:"id"`
+	Timestamp    time.Time `json:"timestamp"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Query        string    `json:"query,omitempty"`
+	RequestID    string    `json:"request_id,omitempty"`
+	StatusCode   int       `json:"status_code"`
+	ShadowStatus int       `json:"shadow_status_code,omitempty"`
+	Differences  []string  `json:"differences,omitempty"`
+	Error        string    `json:"error,omitempty"` // the target could not be reached
+}
+
+// ShadowReport is the response of GET /admin/shadow/diffs.
+type ShadowReport struct {
+	Target   string       `json:"target"`
+	Compared int          `json:"compared"`
+	Matched  int          `json:"matched"`
+	Dropped  int          `json:"dropped"`
+	Diffs    []ShadowDiff `json:"diffs"`
+}
+
+// ShadowLog mirrors requests to Config.ShadowURL and keeps a ring buffer of
+// the responses that differed.
+type ShadowLog struct {
+	mu       sync.RWMutex
+	diffs    []ShadowDiff
+	maxSize  int
+	counter  int
+	compared int
+	matched  int
+	dropped  int
+
+	client   *http.Client
+	inFlight chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewShadowLog creates a shadow log keeping up to maxSize diffs.
+func NewShadowLog(maxSize int) *ShadowLog {
+	return &ShadowLog{
+		diffs:    make([]ShadowDiff, 0, maxSize),
+		maxSize:  maxSize,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		inFlight: make(chan struct{}, maxShadowInFlight),
+	}
+}
+
+// ShadowReport returns what the shadow mirror has recorded so far, for
+// GET /admin/shadow/diffs.
+func (m *Middleware) ShadowReport() ShadowReport {
+	return m.ShadowLog.Report(m.cfg.ShadowURL)
+}
+
+// Report returns the counters and a copy of the recorded diffs.
+func (sl *ShadowLog) Report(target string) ShadowReport {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+	diffs := make([]ShadowDiff, len(sl.diffs))
+	copy(diffs, sl.diffs)
+	return ShadowReport{
+		Target:   target,
+		Compared: sl.compared,
+		Matched:  sl.matched,
+		Dropped:  sl.dropped,
+		Diffs:    diffs,
+	}
+}
+
+// Clear removes all diffs and zeroes the counters.
+func (sl *ShadowLog) Clear() {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.diffs = sl.diffs[:0]
+	sl.counter, sl.compared, sl.matched, sl.dropped = 0, 0, 0, 0
+}
+
+// Wait blocks until every mirrored request in flight has been compared.
+// Tests use it to observe results deterministically.
+func (sl *ShadowLog) Wait() {
+	sl.wg.Wait()
+}
+
+func (sl *ShadowLog) record(d *ShadowDiff) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.compared++
+	if d == nil {
+		sl.matched++
+		return
+	}
+	sl.counter++
+	d.ID = fmt.Sprintf("shd_%06d", sl.counter)
+	if len(sl.diffs) >= sl.maxSize {
+		sl.diffs = sl.diffs[1:]
+	}
+	sl.diffs = append(sl.diffs, *d)
+}
+
+func (sl *ShadowLog) drop() {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.dropped++
+}
+
+// shadowRecorder captures the status and body the twin writes so they can be
+// compared with the shadow target's response.
+type shadowRecorder struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (sr *shadowRecorder) WriteHeader(code int) {
+	sr.status = code
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+func (sr *shadowRecorder) Write(p []byte) (int, error) {
+	room := maxCapturedBody - sr.body.Len()
+	if len(p) > room {
+		sr.truncated = true
+	}
+	sr.body.Write(p[:min(len(p), room)])
+	return sr.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (sr *shadowRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// Shadow mirrors each non-admin request to Config.ShadowURL (--shadow-url,
+// or shadow_url in /admin/config) after the twin has answered it, and
+// records any difference between the two responses in Middleware.ShadowLog.
+// The mirror runs in the background and never changes the twin's response.
+// Status codes are compared, and JSON bodies field by field, skipping the
+// fields named in Config.ShadowIgnore at any depth.
+func (m *Middleware) Shadow(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := m.cfg.ShadowURL
+		if target == "" || strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, ok := captureBody(r)
+		if !ok {
+			// Too large to mirror faithfully
+			next.ServeHTTP(w, r)
+			return
+		}
+		rec := &shadowRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.truncated {
+			return
+		}
+
+		select {
+		case m.ShadowLog.inFlight <- struct{}{}:
+		default:
+			m.ShadowLog.drop()
+			return
+		}
+		req, err := shadowRequest(target, r, body)
+		if err != nil {
+			<-m.ShadowLog.inFlight
+			m.logger.Warn("shadow request", "err", err)
+			return
+		}
+		diff := ShadowDiff{
+			Timestamp:  time.Now(),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Query:      r.URL.RawQuery,
+			RequestID:  chimw.GetReqID(r.Context()),
+			StatusCode: rec.status,
+		}
+		primary := rec.body.Bytes()
+		ignore := m.cfg.ShadowIgnore
+		m.ShadowLog.wg.Add(1)
+		go func() {
+			defer m.ShadowLog.wg.Done()
+			defer func() { <-m.ShadowLog.inFlight }()
+			m.ShadowLog.record(compareShadow(m.ShadowLog.client, req, diff, primary, ignore))
+		}()
+	})
+}
+
+// shadowRequest copies r, with the given body, onto the shadow target.
+func shadowRequest(target string, r *http.Request, body []byte) (*http.Request, error) {
+	req, err := http.NewRequest(r.Method, strings.TrimRight(target, "/")+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.Header.Clone()
+	for _, h := range shadowHopHeaders {
+		req.Header.Del(h)
+	}
+	return req, nil
+}
+
+// compareShadow sends req and compares the target's response with the
+// twin's. It returns nil when they match.
+func compareShadow(client *http.Client, req *http.Request, diff ShadowDiff, primary []byte, ignore []string) *ShadowDiff {
+	resp, err := client.Do(req)
+	if err != nil {
+		diff.Error = err.Error()
+		return &diff
+	}
+	defer resp.Body.Close()
+	shadow, err := io.ReadAll(io.LimitReader(resp.Body, maxCapturedBody))
+	if err != nil {
+		diff.Error = err.Error()
+		return &diff
+	}
+
+	diff.ShadowStatus = resp.StatusCode
+	if resp.StatusCode != diff.StatusCode {
+		diff.Differences = append(diff.Differences, fmt.Sprintf("status: %d != %d", diff.StatusCode, resp.StatusCode))
+	}
+	diff.Differences = append(diff.Differences, DiffBodies(primary, shadow, shadowDiffOptions(ignore))...)
+	if len(diff.Differences) == 0 {
+		return nil
+	}
+	return &diff
+}
+
+// shadowDiffOptions returns the options comparing the twin's response
+// (a) with the shadow target's (b).
+func shadowDiffOptions(ignore []string) DiffOptions {
+	return DiffOptions{Ignore: ignore, Max: maxShadowDifferences, OnlyA: "missing from shadow", OnlyB: "only in shadow"}
+}
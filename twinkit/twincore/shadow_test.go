@@ -0,0 +1,107 @@
+package twincore
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShadowMirrorsRequests(t *testing.T) {
+	var gotBody, gotAuth string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody, gotAuth = string(b), r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/v1/same":
+			w.Write([]byte(`{"id":"cus_2","name":"Ada"}`))
+		case "/v1/diff":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"cus_2","name":"Bob","extra":true}`))
+		}
+	}))
+	defer target.Close()
+
+	twin := New(&Config{Name: "test", ShadowURL: target.URL, ShadowIgnore: []string{"id"}})
+	twin.Router.Post("/v1/*", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"cus_1","name":"Ada"}`))
+	})
+	twin.Router.Post("/admin/reset", func(w http.ResponseWriter, r *http.Request) {})
+	srv := httptest.NewServer(twin)
+	defer srv.Close()
+
+	for _, path := range []string{"/v1/same", "/v1/diff?expand=true", "/admin/reset"} {
+		req, _ := http.NewRequest("POST", srv.URL+path, strings.NewReader("name=Ada"))
+		req.Header.Set("Authorization", "Bearer sk_test")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	twin.mw.ShadowLog.Wait()
+
+	if gotBody != "name=Ada" || gotAuth != "Bearer sk_test" {
+		t.Errorf("expected body and headers to be mirrored, got %q, %q", gotBody, gotAuth)
+	}
+
+	report := twin.mw.ShadowReport()
+	if report.Target != target.URL || report.Compared != 2 || report.Matched != 1 {
+		t.Fatalf("expected 2 compared (admin excluded) and 1 matched, got %+v", report)
+	}
+	d := report.Diffs[0]
+	if d.Path != "/v1/diff" || d.Query != "expand=true" || d.StatusCode != 200 || d.ShadowStatus != 201 {
+		t.Errorf("unexpected diff: %+v", d)
+	}
+	want := []string{"status: 200 != 201", "$.extra: only in shadow", `$.name: "Ada" != "Bob"`}
+	if !reflect.DeepEqual(d.Differences, want) {
+		t.Errorf("expected differences %q, got %q", want, d.Differences)
+	}
+
+	twin.mw.ShadowLog.Clear()
+	if r := twin.mw.ShadowReport(); r.Compared != 0 || len(r.Diffs) != 0 {
+		t.Errorf("expected Clear to empty the report, got %+v", r)
+	}
+}
+
+func TestShadowUnreachableTarget(t *testing.T) {
+	twin := New(&Config{Name: "test", ShadowURL: "http://127.0.0.1:1"})
+	twin.Router.Get("/v1/x", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	twin.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/x", nil))
+	twin.mw.ShadowLog.Wait()
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected the twin's own response to be unaffected, got %d", rec.Code)
+	}
+	report := twin.mw.ShadowReport()
+	if len(report.Diffs) != 1 || report.Diffs[0].Error == "" {
+		t.Errorf("expected an unreachable target to be recorded as an error, got %+v", report)
+	}
+}
+
+func TestDiffBodies(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{"equal JSON", `{"a":1,"b":[1,2]}`, `{"b":[1,2],"a":1}`, nil},
+		{"nested", `{"data":[{"amount":100}]}`, `{"data":[{"amount":200}]}`, []string{"$.data[0].amount: 100 != 200"}},
+		{"length", `[1,2]`, `[1]`, []string{"$: length 2 != 1"}},
+		{"missing", `{"a":1}`, `{}`, []string{"$.a: missing from shadow"}},
+		{"type change", `{"a":"1"}`, `{"a":1}`, []string{`$.a: "1" != 1`}},
+		{"text", `ok`, `ok!`, []string{"body: 2 bytes != 3 bytes"}},
+		{"equal text", `ok`, `ok`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffBodies([]byte(tt.a), []byte(tt.b), shadowDiffOptions(nil)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffBodies() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
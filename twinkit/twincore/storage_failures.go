@@ -0,0 +1,49 @@
+package twincore
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/store"
+)
+
+// StorageErrorWriter writes the response for a request whose store
+// operation failed (see Middleware.StorageFailures).
+type StorageErrorWriter func(w http.ResponseWriter, r *http.Request, failure *store.StorageFailure)
+
+// WriteStorageError is the default StorageErrorWriter: a 500 in the shape
+// of Error.
+func WriteStorageError(w http.ResponseWriter, r *http.Request, failure *store.StorageFailure) {
+	Error(w, http.StatusInternalServerError, failure.Error())
+}
+
+// StorageFailures arms m.StoreFailures for each API request and answers a
+// request whose store operation failed with a 500 from
+// m.WriteStorageError, leaving whatever the handler stored before the
+// failure in place. Admin requests are not armed; one that overlaps an
+// armed request and fails gets a plain Error.
+func (m *Middleware) StorageFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		admin := strings.HasPrefix(r.URL.Path, "/admin/")
+		if !admin {
+			defer m.StoreFailures.Arm()()
+		}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			failure, ok := v.(*store.StorageFailure)
+			if !ok {
+				panic(v)
+			}
+			m.logger.Info("injected storage failure", "method", r.Method, "path", r.URL.Path, "error", failure.Error())
+			write := m.WriteStorageError
+			if write == nil || admin {
+				write = WriteStorageError
+			}
+			write(w, r, failure)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
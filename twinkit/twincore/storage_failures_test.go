@@ -0,0 +1,41 @@
+package twincore
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wondertwin-ai/wondertwin/twinkit/store"
+)
+
+func TestStorageFailures(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	items := store.New[string]("item")
+	items.SetFailures(mw.StoreFailures)
+	mw.StoreFailures.Configure(store.FailureConfig{Rate: 1})
+	mw.WriteStorageError = func(w http.ResponseWriter, r *http.Request, failure *store.StorageFailure) {
+		StripeError(w, http.StatusInternalServerError, "api_error", "", failure.Error())
+	}
+	h := mw.StorageFailures(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		items.Set("item_1", "a")
+		JSON(w, http.StatusOK, map[string]string{"id": "item_1"})
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/v1/items", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"type":"api_error"`) {
+		t.Errorf("expected the provider's error body, got %s", body)
+	}
+
+	// Admin requests are not armed.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/admin/state", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected admin requests unaffected, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,267 @@
+package twincore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Formats a response template can be written in.
+const (
+	// TemplateFormatGo is a text/template rendering the whole body.
+	TemplateFormatGo = "go"
+	// TemplateFormatJSON is a JSON document whose strings may hold
+	// {{path}} placeholders. A string that is a single placeholder takes
+	// the value at path, of any type; placeholders inside longer strings
+	// are interpolated as text.
+	TemplateFormatJSON = "json"
+)
+
+// ResponseTemplate overrides how one route's successful JSON responses are
+// rendered, so a response shape can follow a provider change before the
+// twin itself is updated.
+//
+// Templates render against the response the twin would have sent:
+//
+//	record  the decoded response body, e.g. {{.record.id}} or "{{record.id}}"
+//	status  the status code
+//	params  the route's URL parameters, e.g. {{.params.id}}
+//	query   the query string, first value of each parameter
+//
+// Go templates can call json to encode a value: {"id": {{json .record.id}}}.
+type ResponseTemplate struct {
+	Route  string    `json:"route"`  // method and chi route pattern, e.g. "GET /v1/customers/{id}"
+	Format string    `json:"format"` // TemplateFormatGo or TemplateFormatJSON
+	Body   string    `json:"body"`
+	SetAt  time.Time `json:"set_at"`
+
+	goTmpl   *template.Template
+	jsonTmpl any
+}
+
+// templateFuncs are the functions Go response templates can call.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// placeholder matches a {{path}} placeholder in a JSON template.
+var placeholder = regexp.MustCompile(`\{\{\s*\.?([A-Za-z0-9_.]+)\s*\}\}`)
+
+// render produces the response body from data.
+func (t *ResponseTemplate) render(data map[string]any) ([]byte, error) {
+	if t.goTmpl != nil {
+		var buf bytes.Buffer
+		if err := t.goTmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return json.Marshal(fillPlaceholders(t.jsonTmpl, data))
+}
+
+// fillPlaceholders returns a copy of v with its placeholders filled in.
+func fillPlaceholders(v any, data map[string]any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[k] = fillPlaceholders(val, data)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = fillPlaceholders(val, data)
+		}
+		return out
+	case string:
+		if m := placeholder.FindStringSubmatchIndex(v); m != nil && m[0] == 0 && m[1] == len(v) {
+			return lookupPath(data, v[m[2]:m[3]])
+		}
+		return placeholder.ReplaceAllStringFunc(v, func(p string) string {
+			val := lookupPath(data, placeholder.FindStringSubmatch(p)[1])
+			if s, ok := val.(string); ok {
+				return s
+			}
+			if val == nil {
+				return ""
+			}
+			out, _ := json.Marshal(val)
+			return string(out)
+		})
+	}
+	return v
+}
+
+// lookupPath returns the value at a dotted path such as "record.items.0.id",
+// or nil if there is none.
+func lookupPath(data map[string]any, path string) any {
+	var cur any = data
+	for _, key := range strings.Split(path, ".") {
+		switch c := cur.(type) {
+		case map[string]any:
+			cur = c[key]
+		case map[string]string:
+			cur = c[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil
+			}
+			cur = c[i]
+		default:
+			return nil
+		}
+	}
+	return cur
+}
+
+// TemplateRegistry holds the response templates set through
+// PUT /admin/templates/{method}/{route}, keyed by route.
+type TemplateRegistry struct {
+	mu        sync.RWMutex
+	templates map[string]*ResponseTemplate
+}
+
+// NewTemplateRegistry creates an empty template registry.
+func NewTemplateRegistry() *TemplateRegistry {
+	return &TemplateRegistry{templates: make(map[string]*ResponseTemplate)}
+}
+
+// Set parses body in format and makes it the template for route, replacing
+// any template route had. route is a method and a chi route pattern, e.g.
+// "GET /v1/customers/{id}".
+func (tr *TemplateRegistry) Set(route, format, body string) (ResponseTemplate, error) {
+	method, pattern, ok := strings.Cut(route, " ")
+	if !ok || method == "" || !strings.HasPrefix(pattern, "/") {
+		return ResponseTemplate{}, fmt.Errorf("route %q must be a method and a path pattern, e.g. \"GET /v1/customers/{id}\"", route)
+	}
+	t := &ResponseTemplate{
+		Route:  strings.ToUpper(method) + " " + pattern,
+		Format: format,
+		Body:   body,
+		SetAt:  time.Now(),
+	}
+	switch format {
+	case TemplateFormatGo:
+		tmpl, err := template.New(t.Route).Funcs(templateFuncs).Parse(body)
+		if err != nil {
+			return ResponseTemplate{}, err
+		}
+		t.goTmpl = tmpl
+	case TemplateFormatJSON:
+		if err := json.Unmarshal([]byte(body), &t.jsonTmpl); err != nil {
+			return ResponseTemplate{}, fmt.Errorf("invalid JSON template: %w", err)
+		}
+	default:
+		return ResponseTemplate{}, fmt.Errorf("unknown template format %q (expected %s or %s)", format, TemplateFormatGo, TemplateFormatJSON)
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.templates[t.Route] = t
+	return *t, nil
+}
+
+// Remove deletes the template for route and reports whether there was one.
+func (tr *TemplateRegistry) Remove(route string) bool {
+	method, pattern, _ := strings.Cut(route, " ")
+	route = strings.ToUpper(method) + " " + pattern
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	_, ok := tr.templates[route]
+	delete(tr.templates, route)
+	return ok
+}
+
+// All returns the templates, sorted by route.
+func (tr *TemplateRegistry) All() []ResponseTemplate {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	out := make([]ResponseTemplate, 0, len(tr.templates))
+	for _, t := range tr.templates {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Route < out[j].Route })
+	return out
+}
+
+func (tr *TemplateRegistry) get(route string) *ResponseTemplate {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	return tr.templates[route]
+}
+
+func (tr *TemplateRegistry) empty() bool {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	return len(tr.templates) == 0
+}
+
+// ResponseTemplates renders successful JSON responses of routes that have
+// a template in m.Templates through it. Admin endpoints are never affected,
+// and with no templates set, responses pass through untouched. A template
+// that fails to render answers 500, naming the route.
+func (m *Middleware) ResponseTemplates(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/admin/") || m.Templates.empty() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		body := rec.body.Bytes()
+
+		// The route pattern is only known once the router has matched.
+		rctx := chi.RouteContext(r.Context())
+		var t *ResponseTemplate
+		if rctx != nil {
+			t = m.Templates.get(r.Method + " " + rctx.RoutePattern())
+		}
+		var record any
+		if t != nil && rec.status >= 200 && rec.status < 300 &&
+			strings.Contains(w.Header().Get("Content-Type"), "json") && json.Unmarshal(body, &record) == nil {
+			params := make(map[string]string, len(rctx.URLParams.Keys))
+			for i, k := range rctx.URLParams.Keys {
+				params[k] = rctx.URLParams.Values[i]
+			}
+			query := make(map[string]string)
+			for k, v := range r.URL.Query() {
+				query[k] = v[0]
+			}
+			out, err := t.render(map[string]any{
+				"record": record,
+				"status": rec.status,
+				"params": params,
+				"query":  query,
+			})
+			if err != nil {
+				w.Header().Del("Content-Length")
+				Error(w, http.StatusInternalServerError, fmt.Sprintf("response template for %s: %v", t.Route, err))
+				return
+			}
+			body = out
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(rec.status)
+		w.Write(body)
+	})
+}
@@ -0,0 +1,137 @@
+package twincore
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// templatedRouter serves GET /v1/customers/{id} and a failing
+// POST /v1/customers through ResponseTemplates.
+func templatedRouter(mw *Middleware) http.Handler {
+	r := chi.NewRouter()
+	r.Use(mw.ResponseTemplates)
+	r.Route("/v1", func(r chi.Router) {
+		r.Get("/customers/{id}", func(w http.ResponseWriter, r *http.Request) {
+			JSON(w, http.StatusOK, map[string]any{
+				"id":      chi.URLParam(r, "id"),
+				"email":   "a@example.com",
+				"balance": 1200,
+				"tags":    []string{"vip"},
+			})
+		})
+		r.Post("/customers", func(w http.ResponseWriter, r *http.Request) {
+			Error(w, http.StatusBadRequest, "email is required")
+		})
+	})
+	return r
+}
+
+func serveTemplated(t *testing.T, h http.Handler, method, path string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	var body map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	return rec, body
+}
+
+func TestResponseTemplateJSON(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	h := templatedRouter(mw)
+
+	_, err := mw.Templates.Set("get /v1/customers/{id}", TemplateFormatJSON, `{
+		"id": "{{record.id}}",
+		"object": "customer",
+		"balance": {"amount": "{{record.balance}}", "currency": "{{query.currency}}"},
+		"first_tag": "{{record.tags.0}}",
+		"summary": "{{params.id}} <{{record.email}}>"
+	}`)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	rec, body := serveTemplated(t, h, "GET", "/v1/customers/cus_1?currency=usd")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	balance, _ := body["balance"].(map[string]any)
+	if body["id"] != "cus_1" || body["object"] != "customer" || body["first_tag"] != "vip" ||
+		balance["amount"] != float64(1200) || balance["currency"] != "usd" {
+		t.Errorf("unexpected templated body: %v", body)
+	}
+	if body["summary"] != "cus_1 <a@example.com>" {
+		t.Errorf("expected interpolated text, got %v", body["summary"])
+	}
+	if _, ok := body["email"]; ok {
+		t.Errorf("expected the template to replace the body, got %v", body)
+	}
+}
+
+func TestResponseTemplateGo(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	h := templatedRouter(mw)
+
+	_, err := mw.Templates.Set("GET /v1/customers/{id}", TemplateFormatGo,
+		`{"id": {{json .record.id}}, "balance_cents": {{.record.balance}}{{if .record.tags}}, "tagged": true{{end}}}`)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	_, body := serveTemplated(t, h, "GET", "/v1/customers/cus_2")
+	if body["id"] != "cus_2" || body["balance_cents"] != float64(1200) || body["tagged"] != true {
+		t.Errorf("unexpected templated body: %v", body)
+	}
+
+	// A template that fails to render names the route.
+	mw.Templates.Set("GET /v1/customers/{id}", TemplateFormatGo, `{{index .record.tags 5}}`)
+	rec, body := serveTemplated(t, h, "GET", "/v1/customers/cus_2")
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 for a failing template, got %d", rec.Code)
+	}
+	if msg, _ := body["error"].(map[string]any)["message"].(string); msg == "" {
+		t.Errorf("expected an error message, got %v", body)
+	}
+}
+
+func TestResponseTemplateScope(t *testing.T) {
+	mw := NewMiddleware(&Config{}, slog.Default())
+	h := templatedRouter(mw)
+	mw.Templates.Set("POST /v1/customers", TemplateFormatJSON, `{"replaced": true}`)
+
+	// Error responses and other routes are left alone.
+	rec, body := serveTemplated(t, h, "POST", "/v1/customers")
+	if rec.Code != http.StatusBadRequest || body["replaced"] != nil {
+		t.Errorf("expected the error response untouched, got %d %v", rec.Code, body)
+	}
+	_, body = serveTemplated(t, h, "GET", "/v1/customers/cus_3")
+	if body["email"] != "a@example.com" {
+		t.Errorf("expected an untemplated route untouched, got %v", body)
+	}
+
+	if !mw.Templates.Remove("post /v1/customers") || mw.Templates.Remove("POST /v1/customers") {
+		t.Error("expected Remove to report whether a template was set")
+	}
+	if len(mw.Templates.All()) != 0 {
+		t.Errorf("expected no templates, got %v", mw.Templates.All())
+	}
+}
+
+func TestTemplateRegistrySetErrors(t *testing.T) {
+	tr := NewTemplateRegistry()
+	for _, tc := range []struct{ route, format, body string }{
+		{"/v1/customers", TemplateFormatJSON, `{}`},
+		{"GET v1/customers", TemplateFormatJSON, `{}`},
+		{"GET /v1/customers", TemplateFormatJSON, `{bad`},
+		{"GET /v1/customers", TemplateFormatGo, `{{.record`},
+		{"GET /v1/customers", "xml", `<a/>`},
+	} {
+		if _, err := tr.Set(tc.route, tc.format, tc.body); err == nil {
+			t.Errorf("Set(%q, %q, %q): expected an error", tc.route, tc.format, tc.body)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package twincore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field error codes, so error writers can map them onto a provider's own
+// codes (Stripe's parameter_missing, parameter_unknown, parameter_invalid).
+const (
+	FieldMissing = "missing"
+	FieldUnknown = "unknown"
+	FieldInvalid = "invalid"
+)
+
+// FieldError is one problem Validate found in a request body.
+type FieldError struct {
+	Field   string `json:"field"` // "amount", "metadata[plan]" (form), "items[0].price" (JSON)
+	Code    string `json:"code"`  // FieldMissing, FieldUnknown, or FieldInvalid
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + " " + e.Message
+}
+
+// Validator checks a decoded request body: a JSON value, or a Form for
+// form-encoded requests.
+type Validator interface {
+	Validate(body any) []FieldError
+}
+
+// ValidatorFunc adapts a function to Validator.
+type ValidatorFunc func(body any) []FieldError
+
+// Validate calls f(body).
+func (f ValidatorFunc) Validate(body any) []FieldError { return f(body) }
+
+// ValidationErrorWriter writes the response for a request that failed
+// validation. errs is never empty.
+type ValidationErrorWriter func(w http.ResponseWriter, r *http.Request, errs []FieldError)
+
+// Validate rejects a request whose body does not satisfy v before the
+// route's handler runs, so client bugs surface in twin tests the way they
+// would against the real API. Apply it per route:
+//
+//	r.With(h.mw.Validate(createChargeSchema)).Post("/charges", h.CreateCharge)
+//
+// JSON and form-encoded bodies are validated; other content types, and
+// bodies too large to replay, pass through. Failures are written with
+// Middleware.WriteValidationError.
+func (m *Middleware) Validate(v Validator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, ok := captureBody(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, ok, err := decodeBody(r.Header.Get("Content-Type"), data)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var errs []FieldError
+			if err != nil {
+				errs = []FieldError{{Code: FieldInvalid, Message: err.Error()}}
+			} else {
+				errs = v.Validate(body)
+			}
+			if len(errs) > 0 {
+				write := m.WriteValidationError
+				if write == nil {
+					write = WriteValidationError
+				}
+				write(w, r, errs)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WriteValidationError is the default ValidationErrorWriter: a 400 in the
+// shape of Error, with every problem listed under "errors".
+func WriteValidationError(w http.ResponseWriter, r *http.Request, errs []FieldError) {
+	JSON(w, http.StatusBadRequest, map[string]any{
+		"error": map[string]any{
+			"message": errs[0].Error(),
+			"type":    http.StatusText(http.StatusBadRequest),
+			"code":    http.StatusBadRequest,
+			"errors":  errs,
+		},
+	})
+}
+
+// decodeBody decodes a JSON or form-encoded body. It reports false for
+// content types it does not validate. An empty body decodes to an empty
+// object so required fields are still reported.
+func decodeBody(contentType string, data []byte) (any, bool, error) {
+	ct := strings.ToLower(contentType)
+	switch {
+	case strings.Contains(ct, "json"):
+		if len(bytes.TrimSpace(data)) == 0 {
+			return map[string]any{}, true, nil
+		}
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, true, fmt.Errorf("request body is not valid JSON: %v", err)
+		}
+		return v, true, nil
+	case ct == "" || strings.Contains(ct, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, true, fmt.Errorf("request body is not valid form data: %v", err)
+		}
+		return formBody(values), true, nil
+	}
+	return nil, false, nil
+}
+
+// Form is a decoded form-encoded body. Bracketed keys nest the way
+// Stripe-style APIs read them: metadata[plan]=pro becomes
+// {"metadata": {"plan": "pro"}}, and items[0][price] or tags[] become
+// arrays. Every leaf is a string.
+type Form map[string]any
+
+func formBody(values url.Values) Form {
+	root := map[string]any{}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts := formKeyParts(key)
+		for _, v := range values[key] {
+			insertForm(root, parts, v)
+		}
+	}
+	return Form(arrayify(root).(map[string]any))
+}
+
+func formKeyParts(key string) []string {
+	i := strings.IndexByte(key, '[')
+	if i <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}
+	}
+	parts := []string{key[:i]}
+	return append(parts, strings.Split(key[i+1:len(key)-1], "][")...)
+}
+
+func insertForm(m map[string]any, parts []string, v string) {
+	key := parts[0]
+	if key == "" {
+		key = strconv.Itoa(len(m)) // tags[]=a&tags[]=b
+	}
+	if len(parts) == 1 {
+		m[key] = v
+		return
+	}
+	child, ok := m[key].(map[string]any)
+	if !ok {
+		child = map[string]any{}
+		m[key] = child
+	}
+	insertForm(child, parts[1:], v)
+}
+
+// arrayify turns maps keyed 0..n-1 into arrays.
+func arrayify(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for k, child := range m {
+		m[k] = arrayify(child)
+	}
+	if len(m) == 0 {
+		return m
+	}
+	arr := make([]any, len(m))
+	for k, child := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return m
+		}
+		arr[i] = child
+	}
+	return arr
+}
+
+// Schema is the subset of JSON Schema that twincore enforces: type,
+// required, properties, additionalProperties (a boolean), items, enum,
+// minimum, maximum, minLength, maxLength, and pattern. For form-encoded
+// bodies, strings that parse as the declared integer, number, or boolean
+// type are accepted, since form values are always strings.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"` // object, array, string, integer, number, boolean
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// ParseSchema parses a JSON Schema document.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+	if err := s.compile("$"); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// MustParseSchema is like ParseSchema but panics on error. It is meant for
+// schemas declared as package variables.
+func MustParseSchema(doc string) *Schema {
+	s, err := ParseSchema([]byte(doc))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *Schema) compile(path string) error {
+	switch s.Type {
+	case "", "object", "array", "string", "integer", "number", "boolean":
+	default:
+		return fmt.Errorf("schema %s: unsupported type %q", path, s.Type)
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("schema %s: %w", path, err)
+		}
+		s.pattern = re
+	}
+	for name, p := range s.Properties {
+		if err := p.compile(path + "." + name); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile(path + "[]")
+	}
+	return nil
+}
+
+// Validate implements Validator. Problems are reported in field order.
+func (s *Schema) Validate(body any) []FieldError {
+	form, isForm := body.(Form)
+	if isForm {
+		body = map[string]any(form)
+	}
+	var errs []FieldError
+	s.validate("", body, isForm, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, v any, form bool, errs *[]FieldError) {
+	invalid := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Field: path, Code: FieldInvalid, Message: fmt.Sprintf(format, args...)})
+	}
+
+	v, ok := s.coerce(v, form)
+	if !ok {
+		invalid("must be %s", article(s.Type))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum, form) {
+		opts := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			opts[i] = fmt.Sprint(e)
+		}
+		invalid("must be one of: %s", strings.Join(opts, ", "))
+		return
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, FieldError{Field: childPath(path, name, form), Code: FieldMissing, Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if p, ok := s.Properties[name]; ok {
+				p.validate(childPath(path, name, form), v[name], form, errs)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*errs = append(*errs, FieldError{Field: childPath(path, name, form), Code: FieldUnknown, Message: "is not a known parameter"})
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, form, errs)
+			}
+		}
+	case string:
+		n := len([]rune(v))
+		if s.MinLength != nil && n < *s.MinLength {
+			invalid("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			invalid("must be at most %d characters", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			invalid("must match %s", s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			invalid("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			invalid("must be at most %v", *s.Maximum)
+		}
+	}
+}
+
+// coerce checks v against s.Type, converting form strings to the declared
+// scalar type. It reports false when v is not of that type.
+func (s *Schema) coerce(v any, form bool) (any, bool) {
+	if str, ok := v.(string); ok && form {
+		switch s.Type {
+		case "integer", "number":
+			f, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				return v, false
+			}
+			v = f
+		case "boolean":
+			b, err := strconv.ParseBool(str)
+			if err != nil {
+				return v, false
+			}
+			return b, true
+		case "array":
+			// Stripe-style APIs clear an array with an empty value (tags=).
+			if str == "" {
+				return []any{}, true
+			}
+		}
+	}
+	switch s.Type {
+	case "":
+		return v, true
+	case "object":
+		_, ok := v.(map[string]any)
+		return v, ok
+	case "array":
+		_, ok := v.([]any)
+		return v, ok
+	case "string":
+		_, ok := v.(string)
+		return v, ok
+	case "integer":
+		f, ok := v.(float64)
+		return v, ok && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(float64)
+		return v, ok
+	case "boolean":
+		_, ok := v.(bool)
+		return v, ok
+	}
+	return v, false
+}
+
+func inEnum(v any, enum []any, form bool) bool {
+	for _, e := range enum {
+		if v == e || (form && fmt.Sprint(v) == fmt.Sprint(e)) {
+			return true
+		}
+	}
+	return false
+}
+
+// childPath names a field the way the body addresses it: metadata[plan] for
+// form bodies, metadata.plan for JSON.
+func childPath(path, name string, form bool) string {
+	switch {
+	case path == "":
+		return name
+	case form:
+		return path + "[" + name + "]"
+	}
+	return path + "." + name
+}
+
+func article(typ string) string {
+	switch typ {
+	case "integer", "object", "array":
+		return "an " + typ
+	}
+	return "a " + typ
+}
+
+// Struct returns a Validator that decodes the body into a T, reporting
+// fields of the wrong type and fields T does not declare. If *T has a
+// Validate() []FieldError method, it is called on the decoded value for
+// checks a schema cannot express. Struct suits JSON routes; form values are
+// all strings, so use a Schema for form-encoded routes.
+func Struct[T any]() Validator {
+	return ValidatorFunc(func(body any) []FieldError {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return []FieldError{{Code: FieldInvalid, Message: err.Error()}}
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return []FieldError{structFieldError(err)}
+		}
+		if c, ok := any(&v).(interface{ Validate() []FieldError }); ok {
+			return c.Validate()
+		}
+		return nil
+	})
+}
+
+func structFieldError(err error) FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return FieldError{Field: typeErr.Field, Code: FieldInvalid, Message: "must be " + article(jsonKind(typeErr.Type.Kind().String()))}
+	}
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return FieldError{Field: strings.Trim(name, `"`), Code: FieldUnknown, Message: "is not a known parameter"}
+	}
+	return FieldError{Code: FieldInvalid, Message: err.Error()}
+}
+
+// jsonKind names a Go kind as its JSON Schema type.
+func jsonKind(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"):
+		return "integer"
+	case strings.HasPrefix(kind, "float"):
+		return "number"
+	case kind == "bool":
+		return "boolean"
+	case kind == "slice", kind == "array":
+		return "array"
+	case kind == "struct", kind == "map":
+		return "object"
+	}
+	return kind
+}
@@ -0,0 +1,197 @@
+package twincore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var chargeSchema = MustParseSchema(`{
+	"type": "object",
+	"required": ["amount", "currency"],
+	"additionalProperties": false,
+	"properties": {
+		"amount": {"type": "integer", "minimum": 1},
+		"currency": {"type": "string", "enum": ["usd", "eur"]},
+		"capture": {"type": "boolean"},
+		"description": {"type": "string", "maxLength": 10},
+		"metadata": {"type": "object"},
+		"items": {"type": "array", "items": {"type": "object", "required": ["price"], "properties": {"price": {"type": "string", "pattern": "^price_"}}}}
+	}
+}`)
+
+func TestSchemaValidateJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []FieldError
+	}{
+		{"valid", `{"amount": 100, "currency": "usd", "items": [{"price": "price_1"}], "metadata": {"a": 1}}`, nil},
+		{"missing", `{"amount": 100}`, []FieldError{{"currency", FieldMissing, "is required"}}},
+		{"unknown", `{"amount": 100, "currency": "usd", "colour": "red"}`, []FieldError{{"colour", FieldUnknown, "is not a known parameter"}}},
+		{"wrong type", `{"amount": "100", "currency": "usd"}`, []FieldError{{"amount", FieldInvalid, "must be an integer"}}},
+		{"fraction", `{"amount": 1.5, "currency": "usd"}`, []FieldError{{"amount", FieldInvalid, "must be an integer"}}},
+		{"minimum", `{"amount": 0, "currency": "usd"}`, []FieldError{{"amount", FieldInvalid, "must be at least 1"}}},
+		{"enum", `{"amount": 1, "currency": "gbp"}`, []FieldError{{"currency", FieldInvalid, "must be one of: usd, eur"}}},
+		{"max length", `{"amount": 1, "currency": "usd", "description": "far too long"}`, []FieldError{{"description", FieldInvalid, "must be at most 10 characters"}}},
+		{"nested", `{"amount": 1, "currency": "usd", "items": [{"price": "price_1"}, {"price": "prod_1"}, {}]}`, []FieldError{
+			{"items[1].price", FieldInvalid, "must match ^price_"},
+			{"items[2].price", FieldMissing, "is required"},
+		}},
+		{"not an object", `[1]`, []FieldError{{"", FieldInvalid, "must be an object"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body any
+			if err := json.Unmarshal([]byte(tt.body), &body); err != nil {
+				t.Fatal(err)
+			}
+			if got := chargeSchema.Validate(body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaValidateForm(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []FieldError
+	}{
+		{"valid", "amount=100&currency=usd&capture=true&metadata[plan]=pro&items[0][price]=price_1", nil},
+		{"numeric strings only", "amount=ten&currency=usd", []FieldError{{"amount", FieldInvalid, "must be an integer"}}},
+		{"boolean", "amount=1&currency=usd&capture=maybe", []FieldError{{"capture", FieldInvalid, "must be a boolean"}}},
+		{"bracket paths", "amount=1&currency=usd&items[0][price]=prod_1", []FieldError{{"items[0][price]", FieldInvalid, "must match ^price_"}}},
+		{"empty array", "amount=1&currency=usd&items=", nil},
+		{"empty body", "", []FieldError{{"amount", FieldMissing, "is required"}, {"currency", FieldMissing, "is required"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, ok, err := decodeBody("application/x-www-form-urlencoded", []byte(tt.body))
+			if !ok || err != nil {
+				t.Fatalf("decodeBody() = %v, %v", ok, err)
+			}
+			if got := chargeSchema.Validate(body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormBody(t *testing.T) {
+	body, _, _ := decodeBody("", []byte("a=1&m[k]=v&tags[]=x&tags[]=y&items[1][p]=b&items[0][p]=a&sparse[3]=z"))
+	want := Form{
+		"a":      "1",
+		"m":      map[string]any{"k": "v"},
+		"tags":   []any{"x", "y"},
+		"items":  []any{map[string]any{"p": "a"}, map[string]any{"p": "b"}},
+		"sparse": map[string]any{"3": "z"},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("formBody() = %#v, want %#v", body, want)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	for doc, want := range map[string]string{
+		`{"type": "date"}`:                        `unsupported type "date"`,
+		`{"properties": {"a": {"pattern": "("}}}`: "schema $.a",
+		`{"type": 1}`:                             "parsing schema",
+	} {
+		if _, err := ParseSchema([]byte(doc)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseSchema(%s): expected error containing %q, got %v", doc, want, err)
+		}
+	}
+}
+
+type createCustomer struct {
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
+
+func (c *createCustomer) Validate() []FieldError {
+	if !strings.Contains(c.Email, "@") {
+		return []FieldError{{Field: "email", Code: FieldInvalid, Message: "must be an email address"}}
+	}
+	return nil
+}
+
+func TestStructValidator(t *testing.T) {
+	v := Struct[createCustomer]()
+	tests := []struct {
+		body string
+		want []FieldError
+	}{
+		{`{"email": "a@example.com", "age": 30}`, nil},
+		{`{"email": "a@example.com", "age": "30"}`, []FieldError{{"age", FieldInvalid, "must be an integer"}}},
+		{`{"email": "a@example.com", "name": "A"}`, []FieldError{{"name", FieldUnknown, "is not a known parameter"}}},
+		{`{"email": "nope"}`, []FieldError{{"email", FieldInvalid, "must be an email address"}}},
+	}
+	for _, tt := range tests {
+		var body any
+		json.Unmarshal([]byte(tt.body), &body)
+		if got := v.Validate(body); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Validate(%s) = %+v, want %+v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestValidateMiddleware(t *testing.T) {
+	mw := NewMiddleware(&Config{Name: "test"}, nil)
+	var got string
+	h := mw.Validate(chargeSchema)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		got = r.FormValue("amount")
+	}))
+
+	serve := func(contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/charges", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("application/x-www-form-urlencoded", "amount=100&currency=usd")
+	if rec.Code != http.StatusOK || got != "100" {
+		t.Errorf("expected a valid body to reach the handler intact, got %d, amount %q", rec.Code, got)
+	}
+
+	rec = serve("application/json", `{"amount": "x"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	var resp struct {
+		Error struct {
+			Message string       `json:"message"`
+			Errors  []FieldError `json:"errors"`
+		} `json:"error"`
+	}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Error.Message != "currency is required" || len(resp.Error.Errors) != 2 {
+		t.Errorf("unexpected error body: %+v", resp.Error)
+	}
+
+	rec = serve("application/json", `{"amount":`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "not valid JSON") {
+		t.Errorf("expected malformed JSON to be rejected, got %d %s", rec.Code, rec.Body)
+	}
+
+	rec = serve("text/plain", "anything")
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected unvalidated content types to pass through, got %d", rec.Code)
+	}
+
+	mw.WriteValidationError = func(w http.ResponseWriter, r *http.Request, errs []FieldError) {
+		Error(w, http.StatusUnprocessableEntity, errs[0].Field)
+	}
+	if rec = serve("", "currency=usd"); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected the twin's error writer to be used, got %d", rec.Code)
+	}
+}
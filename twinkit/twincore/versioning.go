@@ -0,0 +1,178 @@
+package twincore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// VersionTransform rewrites a decoded JSON response body for clients pinned
+// to an older API version. It returns the body to send, which may be body
+// itself modified in place.
+type VersionTransform func(r *http.Request, body any) any
+
+// InvalidVersionWriter writes the response for a request that asks for an
+// API version the twin does not know.
+type InvalidVersionWriter func(w http.ResponseWriter, r *http.Request, version string)
+
+// Versioning negotiates the provider API version of each request, so one
+// twin binary can serve SDKs pinned to different versions the way the real
+// API does. Handlers write responses in the shape of the latest version;
+// each change registered with Change is undone, newest first, for requests
+// pinned to a version older than the one that introduced it. This mirrors
+// how Stripe implements its own versioning.
+//
+// Mount the middleware on the API routes:
+//
+//	v := twincore.NewVersioning("Stripe-Version", "2023-10-16", "2024-06-20")
+//	v.Change("2024-06-20", func(r *http.Request, body any) any { ... })
+//	r.Use(v.Middleware)
+//
+// Only JSON responses are transformed, and only when a change applies; other
+// requests are not buffered.
+type Versioning struct {
+	// Header carries the requested version, and is echoed on every
+	// response with the version that was used.
+	Header string
+
+	// Default is the version used when a request sends none, as a
+	// provider does for an account's pinned version. Empty means the
+	// latest version.
+	Default string
+
+	// WriteInvalid writes the response for an unknown version. Twins set
+	// it to answer in their provider's error format; nil means a 400 in
+	// the shape of Error.
+	WriteInvalid InvalidVersionWriter
+
+	versions []string // oldest first
+	changes  []versionChange
+}
+
+// versionChange is a response change introduced in versions[index].
+type versionChange struct {
+	index     int
+	transform VersionTransform
+}
+
+// NewVersioning creates a Versioning that reads header and accepts versions,
+// listed oldest first.
+func NewVersioning(header string, versions ...string) *Versioning {
+	if len(versions) == 0 {
+		panic("twincore: NewVersioning needs at least one version")
+	}
+	return &Versioning{Header: header, versions: versions}
+}
+
+// Versions returns the accepted versions, oldest first.
+func (v *Versioning) Versions() []string {
+	return slices.Clone(v.versions)
+}
+
+// Latest returns the newest version, the shape handlers write.
+func (v *Versioning) Latest() string {
+	return v.versions[len(v.versions)-1]
+}
+
+// Change registers a response change introduced in version. Requests pinned
+// to an earlier version get their responses passed through undo. It panics
+// if version is not one of the accepted versions.
+func (v *Versioning) Change(version string, undo VersionTransform) {
+	i := slices.Index(v.versions, version)
+	if i < 0 {
+		panic(fmt.Sprintf("twincore: Change for unknown API version %q", version))
+	}
+	v.changes = append(v.changes, versionChange{index: i, transform: undo})
+	// Keep changes newest first, registration order within a version.
+	slices.SortStableFunc(v.changes, func(a, b versionChange) int { return b.index - a.index })
+}
+
+// Before reports whether version a is older than version b. Unknown
+// versions are never before anything.
+func (v *Versioning) Before(a, b string) bool {
+	i, j := slices.Index(v.versions, a), slices.Index(v.versions, b)
+	return i >= 0 && j >= 0 && i < j
+}
+
+// versionKey is the context key for the negotiated version.
+type versionKey struct{}
+
+// RequestVersion returns the API version negotiated for r by
+// Versioning.Middleware, or "" if none was.
+func RequestVersion(r *http.Request) string {
+	version, _ := r.Context().Value(versionKey{}).(string)
+	return version
+}
+
+// Middleware negotiates each request's version: it rejects unknown versions,
+// makes the version available through RequestVersion, echoes it in Header,
+// and applies the changes newer than it to JSON responses. Admin endpoints
+// are never versioned.
+func (v *Versioning) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		version := strings.TrimSpace(r.Header.Get(v.Header))
+		if version == "" {
+			version = v.Default
+		}
+		if version == "" {
+			version = v.Latest()
+		}
+		index := slices.Index(v.versions, version)
+		if index < 0 {
+			write := v.WriteInvalid
+			if write == nil {
+				write = writeInvalidVersion
+			}
+			write(w, r, version)
+			return
+		}
+
+		w.Header().Set(v.Header, version)
+		r = r.WithContext(context.WithValue(r.Context(), versionKey{}, version))
+
+		var undo []VersionTransform
+		for _, c := range v.changes {
+			if c.index > index {
+				undo = append(undo, c.transform)
+			}
+		}
+		if len(undo) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		for k, vals := range rec.header {
+			w.Header()[k] = vals
+		}
+		out := rec.body.Bytes()
+		var body any
+		if strings.Contains(w.Header().Get("Content-Type"), "json") && json.Unmarshal(out, &body) == nil {
+			for _, t := range undo {
+				body = t(r, body)
+			}
+			if data, err := json.Marshal(body); err == nil {
+				out = append(data, '\n')
+			}
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(out)))
+		w.WriteHeader(rec.status)
+		w.Write(out)
+	})
+}
+
+// writeInvalidVersion is the default InvalidVersionWriter.
+func writeInvalidVersion(w http.ResponseWriter, r *http.Request, version string) {
+	Error(w, http.StatusBadRequest, fmt.Sprintf("Invalid API version: %s", version))
+}
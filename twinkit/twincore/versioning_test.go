@@ -0,0 +1,129 @@
+package twincore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newTestVersioning has three versions. 2024-01-01 renamed "name" to
+// "display_name"; 2025-01-01 added "status".
+func newTestVersioning() *Versioning {
+	v := NewVersioning("Api-Version", "2023-01-01", "2024-01-01", "2025-01-01")
+	v.Change("2024-01-01", func(r *http.Request, body any) any {
+		m := body.(map[string]any)
+		m["name"] = m["display_name"]
+		delete(m, "display_name")
+		return m
+	})
+	v.Change("2025-01-01", func(r *http.Request, body any) any {
+		m := body.(map[string]any)
+		delete(m, "status")
+		return m
+	})
+	return v
+}
+
+// serveVersioned runs a JSON handler in the latest shape through v.
+func serveVersioned(v *Versioning, path, version string) (*httptest.ResponseRecorder, string) {
+	var seen string
+	handler := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestVersion(r)
+		JSON(w, http.StatusOK, map[string]any{"id": "thing_1", "display_name": "Thing", "status": "active"})
+	}))
+	req := httptest.NewRequest("GET", path, nil)
+	if version != "" {
+		req.Header.Set("Api-Version", version)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec, seen
+}
+
+func decodeObject(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &m); err != nil {
+		t.Fatalf("expected a JSON object, got %q: %v", rec.Body.String(), err)
+	}
+	return m
+}
+
+func TestVersioningLatestPassesThrough(t *testing.T) {
+	v := newTestVersioning()
+	rec, seen := serveVersioned(v, "/v1/things", "")
+	if seen != "2025-01-01" || rec.Header().Get("Api-Version") != "2025-01-01" {
+		t.Errorf("expected the latest version by default, saw %q and echoed %q", seen, rec.Header().Get("Api-Version"))
+	}
+	if m := decodeObject(t, rec); m["display_name"] != "Thing" || m["status"] != "active" {
+		t.Errorf("expected the latest shape, got %v", m)
+	}
+}
+
+func TestVersioningUndoesNewerChanges(t *testing.T) {
+	v := newTestVersioning()
+
+	rec, seen := serveVersioned(v, "/v1/things", "2024-01-01")
+	m := decodeObject(t, rec)
+	if seen != "2024-01-01" || m["display_name"] != "Thing" || m["status"] != nil {
+		t.Errorf("expected only the 2025 change undone, got %v", m)
+	}
+
+	rec, _ = serveVersioned(v, "/v1/things", "2023-01-01")
+	m = decodeObject(t, rec)
+	if m["name"] != "Thing" || m["display_name"] != nil || m["status"] != nil {
+		t.Errorf("expected both changes undone, got %v", m)
+	}
+	if rec.Header().Get("Api-Version") != "2023-01-01" {
+		t.Errorf("expected the requested version echoed, got %q", rec.Header().Get("Api-Version"))
+	}
+	if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(rec.Body.Len()) {
+		t.Errorf("expected Content-Length to match the rewritten body, got %s for %d bytes", got, rec.Body.Len())
+	}
+}
+
+func TestVersioningDefault(t *testing.T) {
+	v := newTestVersioning()
+	v.Default = "2024-01-01"
+	rec, _ := serveVersioned(v, "/v1/things", "")
+	if m := decodeObject(t, rec); m["status"] != nil || m["display_name"] != "Thing" {
+		t.Errorf("expected the default version's shape, got %v", m)
+	}
+}
+
+func TestVersioningRejectsUnknownVersion(t *testing.T) {
+	v := newTestVersioning()
+	rec, _ := serveVersioned(v, "/v1/things", "1999-01-01")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+
+	v.WriteInvalid = func(w http.ResponseWriter, r *http.Request, version string) {
+		StripeError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid Stripe API version: "+version)
+	}
+	rec, _ = serveVersioned(v, "/v1/things", "1999-01-01")
+	if m := decodeObject(t, rec); m["error"].(map[string]any)["type"] != "invalid_request_error" {
+		t.Errorf("expected the twin's error writer, got %v", m)
+	}
+
+	// Admin endpoints are not versioned.
+	rec, _ = serveVersioned(v, "/admin/state", "1999-01-01")
+	if rec.Code != http.StatusOK || rec.Header().Get("Api-Version") != "" {
+		t.Errorf("expected admin requests untouched, got %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestVersioningBefore(t *testing.T) {
+	v := newTestVersioning()
+	if !v.Before("2023-01-01", "2025-01-01") || v.Before("2025-01-01", "2023-01-01") || v.Before("2023-01-01", "nope") {
+		t.Error("unexpected version ordering")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Change for an unknown version to panic")
+		}
+	}()
+	v.Change("2030-01-01", func(r *http.Request, body any) any { return body })
+}
@@ -0,0 +1,53 @@
+package twincore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WebhookFaultPrefix marks a fault endpoint that addresses the twin's
+// webhook deliveries of an event type rather than an API path: POST
+// /admin/fault/webhook:charge.succeeded affects deliveries of
+// charge.succeeded events, and webhook:* those of every type. Such a fault
+// drops the deliveries it hits, so they are never sent or redelivered,
+// unless it sets only a Delay, in which case it holds them back that long
+// first. Either way the provider side fails, so consumers can test missing
+// and late webhooks. See FaultRegistry.CheckWebhook.
+const WebhookFaultPrefix = "webhook:"
+
+// IsWebhookFault reports whether a fault endpoint addresses webhook
+// deliveries.
+func IsWebhookFault(endpoint string) bool {
+	return strings.HasPrefix(endpoint, WebhookFaultPrefix)
+}
+
+// Drops reports whether a webhook fault drops the deliveries it hits, as
+// opposed to delaying them.
+func (f *FaultConfig) Drops() bool {
+	return f.Delay <= 0
+}
+
+// checkWebhookFault rejects the fault fields that only make sense for an
+// HTTP response.
+func checkWebhookFault(endpoint string, f *FaultConfig) error {
+	if strings.TrimPrefix(endpoint, WebhookFaultPrefix) == "" {
+		return fmt.Errorf("webhook faults name an event type, like webhook:charge.succeeded, or webhook:* for every type")
+	}
+	if f.StatusCode != 0 || f.Preset != "" || f.Body != "" || f.RetryAfter != 0 {
+		return fmt.Errorf("webhook faults drop deliveries, or delay them with delay_ms; status_code, preset, body, and retry_after do not apply")
+	}
+	return nil
+}
+
+// CheckWebhook returns the fault injected on deliveries of eventType,
+// through its own endpoint or webhook:*, or nil if none applies. A fault it
+// returns counts as a hit.
+func (fr *FaultRegistry) CheckWebhook(eventType string) *FaultConfig {
+	if fr == nil {
+		return nil
+	}
+	if f := fr.Check(WebhookFaultPrefix + eventType); f != nil {
+		return f
+	}
+	return fr.Check(WebhookFaultPrefix + "*")
+}
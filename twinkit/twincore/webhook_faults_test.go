@@ -0,0 +1,53 @@
+package twincore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebhookFaultValidation(t *testing.T) {
+	fr := NewFaultRegistry()
+	if err := fr.Set("webhook:charge.succeeded", FaultConfig{Rate: 1, Delay: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		endpoint string
+		fault    FaultConfig
+	}{
+		{"webhook:", FaultConfig{Rate: 1}},
+		{"webhook:charge.failed", FaultConfig{Rate: 1, StatusCode: 500}},
+		{"webhook:charge.failed", FaultConfig{Rate: 1, Preset: FaultAuthInvalid}},
+		{"webhook:charge.failed", FaultConfig{Rate: 1, Body: "nope"}},
+	} {
+		if err := fr.Set(tt.endpoint, tt.fault); err == nil {
+			t.Errorf("%s %+v: expected an error", tt.endpoint, tt.fault)
+		}
+	}
+}
+
+func TestCheckWebhook(t *testing.T) {
+	var nilRegistry *FaultRegistry
+	if nilRegistry.CheckWebhook("charge.succeeded") != nil {
+		t.Error("expected no fault from a nil registry")
+	}
+
+	fr := NewFaultRegistry()
+	fr.Set("webhook:charge.succeeded", FaultConfig{Rate: 1, Delay: time.Second})
+	if f := fr.CheckWebhook("charge.succeeded"); f == nil || f.Drops() {
+		t.Errorf("expected the delay fault, got %+v", f)
+	}
+	if f := fr.CheckWebhook("charge.failed"); f != nil {
+		t.Errorf("expected no fault on another type, got %+v", f)
+	}
+	if f := fr.Check("/v1/charges"); f != nil {
+		t.Errorf("expected the webhook fault not to hit API requests, got %+v", f)
+	}
+
+	fr.Set("webhook:*", FaultConfig{Rate: 1})
+	if f := fr.CheckWebhook("charge.failed"); f == nil || !f.Drops() {
+		t.Errorf("expected webhook:* to drop other types, got %+v", f)
+	}
+	if f := fr.CheckWebhook("charge.succeeded"); f == nil || f.Drops() {
+		t.Errorf("expected the type's own fault to win over webhook:*, got %+v", f)
+	}
+}
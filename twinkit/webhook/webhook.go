@@ -6,14 +6,54 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
+)
+
+// Guarantee is the delivery guarantee a Dispatcher gives.
+type Guarantee string
+
+const (
+	// AtLeastOnce moves events whose delivery attempts all fail to a
+	// redelivery queue, retrying them with exponential backoff for hours
+	// before giving up and dead-lettering them, as real providers do.
+	AtLeastOnce Guarantee = "at-least-once"
+	// AtMostOnce drops events whose delivery attempts all fail.
+	AtMostOnce Guarantee = "at-most-once"
 )
 
+// ErrNotDeadLettered is returned by RetryDeadLetter for an event that is not
+// in the dead-letter list.
+var ErrNotDeadLettered = errors.New("event is not dead-lettered")
+
+// PayloadError reports an event payload that does not satisfy the schema
+// registered for its type.
+type PayloadError struct {
+	EventType string
+	Errors    []twincore.FieldError
+}
+
+func (e *PayloadError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("%s payload does not match its schema: %s", e.EventType, strings.Join(msgs, "; "))
+}
+
+// redeliveryPoll is how often due redeliveries are checked for.
+const redeliveryPoll = time.Second
+
 // Signer signs webhook payloads. Each twin implements its own signing scheme.
 type Signer interface {
 	// Sign returns headers to add to the webhook request for signature verification.
@@ -26,16 +66,71 @@ type Event struct {
 	Type      string         `json:"type"`
 	Payload   map[string]any `json:"data"`
 	CreatedAt time.Time      `json:"created_at"`
+
+	// RequestID is the ID of the API request that produced the event, if
+	// it was enqueued with EnqueueContext. It is not part of the payload.
+	RequestID string `json:"-"`
+
+	// Tenant is the tenant the event belongs to, if it was enqueued with
+	// EnqueueContext from a context carrying one (see WithTenant). It
+	// selects the endpoint the event is delivered to and is not part of
+	// the payload.
+	Tenant string `json:"-"`
+}
+
+// Endpoint is where a tenant's events are delivered and the secret they
+// are signed with. An empty URL or Secret falls back to the dispatcher's.
+type Endpoint struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context whose events EnqueueContext delivers to the
+// tenant's endpoint. Multi-tenant twins call it in their auth middleware
+// once they know which account a request acts for.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, or "".
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
 }
 
 // Delivery records a webhook delivery attempt.
 type Delivery struct {
 	EventID    string    `json:"event_id"`
+	Tenant     string    `json:"tenant,omitempty"`
 	URL        string    `json:"url"`
 	StatusCode int       `json:"status_code"`
 	Error      string    `json:"error,omitempty"`
 	Attempt    int       `json:"attempt"`
 	Timestamp  time.Time `json:"timestamp"`
+
+	// Fault is the ID of the injected webhook fault that delayed the
+	// delivery, or dropped it when Dropped is set; a dropped delivery was
+	// never sent and has Attempt 0.
+	Fault   string `json:"fault,omitempty"`
+	Dropped bool   `json:"dropped,omitempty"`
+}
+
+// Redelivery is a failed event waiting in the redelivery queue.
+type Redelivery struct {
+	Event       Event     `json:"event"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// DeadLetter is an event the dispatcher gave up redelivering.
+type DeadLetter struct {
+	Event     Event     `json:"event"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
 }
 
 // Dispatcher manages outbound webhook delivery.
@@ -53,6 +148,22 @@ type Dispatcher struct {
 	eventPrefix string
 	counter     int
 	autoDeliver bool
+
+	guarantee       Guarantee
+	backoff         time.Duration
+	maxBackoff      time.Duration
+	maxRedeliveries int
+	now             func() time.Time
+	redeliveries    []Redelivery
+	deadLetters     []DeadLetter
+	polling         bool // a goroutine is polling for due redeliveries
+
+	byRequest map[string][]Event  // events by the request that produced them
+	endpoints map[string]Endpoint // per-tenant endpoints, by tenant
+
+	schemas map[string]twincore.Validator // payload schemas by event type
+	strict  bool                          // panic on invalid payloads
+	faults  *twincore.FaultRegistry       // webhook faults, if any
 }
 
 // Config configures the webhook dispatcher.
@@ -65,12 +176,42 @@ type Config struct {
 	RetryDelay  time.Duration
 	EventPrefix string // e.g., "evt" for Stripe-style events
 	AutoDeliver bool   // automatically deliver events when queued
+
+	// Guarantee is AtMostOnce (the default) or AtLeastOnce.
+	Guarantee Guarantee
+	// RedeliveryBackoff is the delay before the first redelivery, doubled
+	// for each one after up to MaxRedeliveryBackoff, with jitter.
+	RedeliveryBackoff    time.Duration
+	MaxRedeliveryBackoff time.Duration
+	// MaxRedeliveries is how many redeliveries fail before an event is
+	// dead-lettered.
+	MaxRedeliveries int
+	// Clock returns the time redeliveries are scheduled by, so advancing a
+	// twin's simulated clock brings them due. Defaults to time.Now.
+	Clock func() time.Time
+	// Schemas maps event types to the schema their payloads must satisfy,
+	// typically a twincore.Schema of the provider's documented shape. See
+	// RegisterSchema.
+	Schemas map[string]twincore.Validator
+	// StrictPayloads makes Enqueue panic with a *PayloadError on a payload
+	// that does not satisfy its schema, instead of logging it. Twin tests
+	// set it so the test that built the payload fails.
+	StrictPayloads bool
+	// Endpoints maps tenants to the endpoint their events are delivered
+	// to. See SetTenantEndpoint.
+	Endpoints map[string]Endpoint
+	// Faults is the twin's fault registry, typically twin.Middleware().Faults.
+	// Faults injected on webhook:<event type> there drop or delay deliveries
+	// of that type; see twincore.WebhookFaultPrefix.
+	Faults *twincore.FaultRegistry
 }
 
 // NewDispatcher creates a new webhook dispatcher.
 func NewDispatcher(cfg Config) *Dispatcher {
 	if cfg.MaxRetries == 0 {
 		cfg.MaxRetries = 3
+	} else if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 1 // every event gets at least one attempt
 	}
 	if cfg.RetryDelay == 0 {
 		cfg.RetryDelay = 1 * time.Second
@@ -81,6 +222,30 @@ func NewDispatcher(cfg Config) *Dispatcher {
 	if cfg.Logger == nil {
 		cfg.Logger = slog.Default()
 	}
+	if cfg.Guarantee == "" {
+		cfg.Guarantee = AtMostOnce
+	}
+	if cfg.RedeliveryBackoff == 0 {
+		cfg.RedeliveryBackoff = 1 * time.Minute
+	}
+	if cfg.MaxRedeliveryBackoff == 0 {
+		cfg.MaxRedeliveryBackoff = 1 * time.Hour
+	}
+	if cfg.MaxRedeliveries == 0 {
+		// With the default backoff, about eight hours of retrying.
+		cfg.MaxRedeliveries = 12
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	schemas := make(map[string]twincore.Validator, len(cfg.Schemas))
+	for eventType, schema := range cfg.Schemas {
+		schemas[eventType] = schema
+	}
+	endpoints := make(map[string]Endpoint, len(cfg.Endpoints))
+	for tenant, ep := range cfg.Endpoints {
+		endpoints[tenant] = ep
+	}
 
 	return &Dispatcher{
 		url:         cfg.URL,
@@ -94,6 +259,16 @@ func NewDispatcher(cfg Config) *Dispatcher {
 		client:      &http.Client{Timeout: 30 * time.Second},
 		eventPrefix: cfg.EventPrefix,
 		autoDeliver: cfg.AutoDeliver,
+
+		guarantee:       cfg.Guarantee,
+		backoff:         cfg.RedeliveryBackoff,
+		maxBackoff:      cfg.MaxRedeliveryBackoff,
+		maxRedeliveries: cfg.MaxRedeliveries,
+		now:             cfg.Clock,
+		schemas:         schemas,
+		strict:          cfg.StrictPayloads,
+		endpoints:       endpoints,
+		faults:          cfg.Faults,
 	}
 }
 
@@ -111,9 +286,93 @@ func (d *Dispatcher) SetSecret(secret string) {
 	d.secret = secret
 }
 
+// SetTenantEndpoint routes the tenant's events to ep instead of the
+// dispatcher's URL, signed with ep's secret, as providers deliver each
+// merchant's events to the callback it registered. Endpoints are
+// configuration and survive Reset.
+func (d *Dispatcher) SetTenantEndpoint(tenant string, ep Endpoint) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.endpoints[tenant] = ep
+}
+
+// RemoveTenantEndpoint sends the tenant's events back to the dispatcher's
+// URL. It reports whether the tenant had an endpoint.
+func (d *Dispatcher) RemoveTenantEndpoint(tenant string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.endpoints[tenant]
+	delete(d.endpoints, tenant)
+	return ok
+}
+
+// TenantEndpoints returns the per-tenant endpoints by tenant.
+func (d *Dispatcher) TenantEndpoints() map[string]Endpoint {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make(map[string]Endpoint, len(d.endpoints))
+	for tenant, ep := range d.endpoints {
+		out[tenant] = ep
+	}
+	return out
+}
+
+// RegisterSchema sets the schema payloads of eventType must satisfy,
+// replacing any registered before. A payload that does not satisfy it is a
+// bug in the twin that would let its webhooks drift from the provider's:
+// Enqueue logs the error and sends the event anyway, or with StrictPayloads
+// panics with a *PayloadError.
+func (d *Dispatcher) RegisterSchema(eventType string, schema twincore.Validator) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.schemas[eventType] = schema
+}
+
+// CheckPayload checks payload against the schema registered for eventType,
+// returning a *PayloadError if it does not satisfy it. Event types without
+// a schema always pass.
+func (d *Dispatcher) CheckPayload(eventType string, payload map[string]any) error {
+	d.mu.RLock()
+	schema := d.schemas[eventType]
+	d.mu.RUnlock()
+	if schema == nil {
+		return nil
+	}
+	// Validate the payload as it goes over the wire, not the Go values
+	// handlers built it from.
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return &PayloadError{EventType: eventType, Errors: []twincore.FieldError{{Code: twincore.FieldInvalid, Message: err.Error()}}}
+	}
+	var body any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return &PayloadError{EventType: eventType, Errors: []twincore.FieldError{{Code: twincore.FieldInvalid, Message: err.Error()}}}
+	}
+	if errs := schema.Validate(body); len(errs) > 0 {
+		return &PayloadError{EventType: eventType, Errors: errs}
+	}
+	return nil
+}
+
 // Enqueue adds an event to the dispatch queue. If AutoDeliver is true,
 // it will be delivered asynchronously.
 func (d *Dispatcher) Enqueue(eventType string, payload map[string]any) Event {
+	return d.EnqueueContext(context.Background(), eventType, payload)
+}
+
+// EnqueueContext is Enqueue for an event produced by an API request: ctx
+// is the request's context, whose request ID (set by chi's RequestID
+// middleware, which every twin mounts) is recorded on the event so
+// Correlate can find it, and whose tenant (see WithTenant) picks the
+// endpoint the event is delivered to.
+func (d *Dispatcher) EnqueueContext(ctx context.Context, eventType string, payload map[string]any) Event {
+	if err := d.CheckPayload(eventType, payload); err != nil {
+		if d.strict {
+			panic(err)
+		}
+		d.logger.Error("invalid webhook payload", "event_type", eventType, "error", err)
+	}
+
 	d.mu.Lock()
 	d.counter++
 	evt := Event{
@@ -121,8 +380,16 @@ func (d *Dispatcher) Enqueue(eventType string, payload map[string]any) Event {
 		Type:      eventType,
 		Payload:   payload,
 		CreatedAt: time.Now(),
+		RequestID: chimw.GetReqID(ctx),
+		Tenant:    TenantFromContext(ctx),
 	}
 	d.queue = append(d.queue, evt)
+	if evt.RequestID != "" {
+		if d.byRequest == nil {
+			d.byRequest = make(map[string][]Event)
+		}
+		d.byRequest[evt.RequestID] = append(d.byRequest[evt.RequestID], evt)
+	}
 	autoDeliver := d.autoDeliver
 	d.mu.Unlock()
 
@@ -159,72 +426,273 @@ func (d *Dispatcher) FlushWebhooks() error {
 	return d.Flush()
 }
 
+// deliverEvent makes up to maxRetries delivery attempts. If they all fail
+// and the dispatcher is AtLeastOnce, the event is queued for redelivery. A
+// webhook fault injected on the event's type drops the event first, or
+// delays the first attempt.
 func (d *Dispatcher) deliverEvent(evt Event) error {
+	var faultID string
+	if fault := d.faults.CheckWebhook(evt.Type); fault != nil {
+		if fault.Drops() {
+			d.drop(evt, fault.ID)
+			return nil
+		}
+		d.logger.Info("webhook delayed by injected fault", "event_id", evt.ID, "fault", fault.ID, "delay", fault.Delay)
+		time.Sleep(fault.Delay)
+		faultID = fault.ID
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= d.maxRetries; attempt++ {
+		var ok bool
+		ok, lastErr = d.attempt(evt, attempt, faultID)
+		if ok {
+			return nil
+		}
+		if attempt < d.maxRetries {
+			time.Sleep(d.retryDelay)
+		}
+	}
+
+	if d.guarantee == AtLeastOnce {
+		d.mu.Lock()
+		d.scheduleLocked(Redelivery{Event: evt, Attempts: d.maxRetries, LastError: lastErr.Error()})
+		d.mu.Unlock()
+	}
+	return lastErr
+}
+
+// drop records evt as dropped by the webhook fault with ID faultID.
+func (d *Dispatcher) drop(evt Event, faultID string) {
+	url, _ := d.destination(evt)
+	d.logger.Info("webhook dropped by injected fault", "event_id", evt.ID, "fault", faultID)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deliveries = append(d.deliveries, Delivery{
+		EventID:   evt.ID,
+		Tenant:    evt.Tenant,
+		URL:       url,
+		Error:     "dropped by injected fault " + faultID,
+		Timestamp: time.Now(),
+		Fault:     faultID,
+		Dropped:   true,
+	})
+}
+
+// destination returns the URL and signing secret evt is delivered with:
+// its tenant's endpoint, where set, or the dispatcher's.
+func (d *Dispatcher) destination(evt Event) (url, secret string) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	url, secret = d.url, d.secret
+	if ep, ok := d.endpoints[evt.Tenant]; ok && evt.Tenant != "" {
+		if ep.URL != "" {
+			url = ep.URL
+		}
+		if ep.Secret != "" {
+			secret = ep.Secret
+		}
+	}
+	return url, secret
+}
+
+// attempt sends evt once and records the delivery, with the ID of the
+// webhook fault that delayed it, if any. It reports true when the event was
+// delivered or there is no URL to deliver it to.
+func (d *Dispatcher) attempt(evt Event, attempt int, faultID string) (bool, error) {
+	url, secret := d.destination(evt)
 	d.mu.RLock()
-	url := d.url
-	secret := d.secret
 	signer := d.signer
 	d.mu.RUnlock()
 
 	if url == "" {
 		d.logger.Debug("no webhook URL configured, skipping delivery", "event_id", evt.ID)
-		return nil
+		return true, nil
 	}
 
 	payload, err := json.Marshal(evt)
 	if err != nil {
-		return fmt.Errorf("marshal event: %w", err)
+		return false, fmt.Errorf("marshal event: %w", err)
 	}
 
-	var lastErr error
-	for attempt := 1; attempt <= d.maxRetries; attempt++ {
-		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(payload))
-		if err != nil {
-			return fmt.Errorf("create request: %w", err)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if signer != nil && secret != "" {
+		for k, v := range signer.Sign(payload, secret) {
+			req.Header.Set(k, v)
 		}
-		req.Header.Set("Content-Type", "application/json")
+	}
 
-		if signer != nil && secret != "" {
-			for k, v := range signer.Sign(payload, secret) {
-				req.Header.Set(k, v)
-			}
+	resp, err := d.client.Do(req)
+	delivery := Delivery{
+		EventID:   evt.ID,
+		Tenant:    evt.Tenant,
+		URL:       url,
+		Attempt:   attempt,
+		Timestamp: time.Now(),
+		Fault:     faultID,
+	}
+
+	var ok bool
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+		delivery.StatusCode = resp.StatusCode
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			ok = true
+		} else {
+			err = fmt.Errorf("webhook delivery failed: status %d", resp.StatusCode)
 		}
+	}
 
-		resp, err := d.client.Do(req)
-		delivery := Delivery{
-			EventID:   evt.ID,
-			URL:       url,
-			Attempt:   attempt,
-			Timestamp: time.Now(),
+	d.mu.Lock()
+	d.deliveries = append(d.deliveries, delivery)
+	d.mu.Unlock()
+	return ok, err
+}
+
+// scheduleLocked queues r for redelivery after a backoff that doubles with
+// each redelivery, or dead-letters it once MaxRedeliveries have failed.
+// d.mu must be held.
+func (d *Dispatcher) scheduleLocked(r Redelivery) {
+	n := r.Attempts - d.maxRetries // redeliveries made so far
+	if n >= d.maxRedeliveries {
+		d.deadLetters = append(d.deadLetters, DeadLetter{
+			Event:     r.Event,
+			Attempts:  r.Attempts,
+			LastError: r.LastError,
+			FailedAt:  d.now(),
+		})
+		d.logger.Warn("webhook dead-lettered", "event_id", r.Event.ID, "attempts", r.Attempts, "error", r.LastError)
+		return
+	}
+
+	delay := d.maxBackoff
+	if n < 30 {
+		delay = min(d.backoff<<n, d.maxBackoff)
+	}
+	// Equal jitter: half the delay, plus up to the other half at random,
+	// so events that failed together do not all retry together.
+	delay = delay/2 + rand.N(delay/2+1)
+	r.NextAttempt = d.now().Add(delay)
+	d.redeliveries = append(d.redeliveries, r)
+
+	if !d.polling {
+		d.polling = true
+		go d.pollRedeliveries()
+	}
+}
+
+// pollRedeliveries redelivers events as they come due, until the redelivery
+// queue is empty.
+func (d *Dispatcher) pollRedeliveries() {
+	ticker := time.NewTicker(redeliveryPoll)
+	defer ticker.Stop()
+	for range ticker.C {
+		d.RedeliverDue()
+		d.mu.Lock()
+		if len(d.redeliveries) == 0 {
+			d.polling = false
+			d.mu.Unlock()
+			return
 		}
+		d.mu.Unlock()
+	}
+}
 
-		if err != nil {
-			delivery.Error = err.Error()
-			delivery.StatusCode = 0
-			lastErr = err
+// RedeliverDue makes one delivery attempt for each queued redelivery whose
+// time has come, rescheduling or dead-lettering those that fail. It returns
+// how many events it attempted. Redeliveries are normally made in the
+// background; RedeliverDue makes them immediately.
+func (d *Dispatcher) RedeliverDue() int {
+	d.mu.Lock()
+	now := d.now()
+	var due []Redelivery
+	pending := d.redeliveries[:0]
+	for _, r := range d.redeliveries {
+		if r.NextAttempt.After(now) {
+			pending = append(pending, r)
 		} else {
-			io.ReadAll(resp.Body)
-			resp.Body.Close()
-			delivery.StatusCode = resp.StatusCode
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				d.mu.Lock()
-				d.deliveries = append(d.deliveries, delivery)
-				d.mu.Unlock()
-				return nil
-			}
-			lastErr = fmt.Errorf("webhook delivery failed: status %d", resp.StatusCode)
+			due = append(due, r)
 		}
+	}
+	d.redeliveries = pending
+	d.mu.Unlock()
 
+	for _, r := range due {
+		r.Attempts++
+		ok, err := d.attempt(r.Event, r.Attempts, "")
+		if ok {
+			continue
+		}
+		r.LastError = err.Error()
 		d.mu.Lock()
-		d.deliveries = append(d.deliveries, delivery)
+		d.scheduleLocked(r)
 		d.mu.Unlock()
+	}
+	return len(due)
+}
 
-		if attempt < d.maxRetries {
-			time.Sleep(d.retryDelay)
+// Redeliveries returns the events waiting to be redelivered.
+func (d *Dispatcher) Redeliveries() []Redelivery {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make([]Redelivery, len(d.redeliveries))
+	copy(out, d.redeliveries)
+	return out
+}
+
+// DeadLetters returns the events the dispatcher gave up redelivering.
+func (d *Dispatcher) DeadLetters() []DeadLetter {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make([]DeadLetter, len(d.deadLetters))
+	copy(out, d.deadLetters)
+	return out
+}
+
+// RetryDeadLetter makes one more delivery attempt for a dead-lettered event,
+// removing it from the dead-letter list if it succeeds. It returns
+// ErrNotDeadLettered if no dead-lettered event has the ID.
+func (d *Dispatcher) RetryDeadLetter(eventID string) error {
+	d.mu.RLock()
+	var dl DeadLetter
+	found := false
+	for _, l := range d.deadLetters {
+		if l.Event.ID == eventID {
+			dl, found = l, true
+			break
 		}
 	}
+	d.mu.RUnlock()
+	if !found {
+		return ErrNotDeadLettered
+	}
 
-	return lastErr
+	ok, err := d.attempt(dl.Event, dl.Attempts+1, "")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, l := range d.deadLetters {
+		if l.Event.ID != eventID {
+			continue
+		}
+		if ok {
+			d.deadLetters = append(d.deadLetters[:i], d.deadLetters[i+1:]...)
+			return nil
+		}
+		d.deadLetters[i].Attempts++
+		d.deadLetters[i].LastError = err.Error()
+		d.deadLetters[i].FailedAt = d.now()
+		break
+	}
+	return err
 }
 
 // Deliveries returns all delivery records.
@@ -236,6 +704,73 @@ func (d *Dispatcher) Deliveries() []Delivery {
 	return out
 }
 
+// Delivery statuses reported by Correlate.
+const (
+	// StatusPending means no delivery attempt has been recorded yet, or
+	// none will be because no webhook URL is configured.
+	StatusPending = "pending"
+	// StatusDelivered means an attempt got a 2xx response.
+	StatusDelivered = "delivered"
+	// StatusRedelivering means every attempt so far failed and the event
+	// is waiting in the redelivery queue.
+	StatusRedelivering = "redelivering"
+	// StatusDeadLettered means the dispatcher gave up on the event.
+	StatusDeadLettered = "dead_lettered"
+	// StatusFailed means every attempt so far failed and the event is
+	// not queued for redelivery: attempts are still being made, or the
+	// dispatcher is AtMostOnce and dropped it.
+	StatusFailed = "failed"
+	// StatusDropped means an injected webhook fault dropped the event
+	// before any attempt.
+	StatusDropped = "dropped"
+)
+
+// Correlation is an event an API request produced and what became of it.
+type Correlation struct {
+	Event      Event      `json:"event"`
+	Status     string     `json:"status"`
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// Correlate returns the events enqueued with EnqueueContext during the
+// request with the given ID, oldest first, each with its delivery attempts
+// and status.
+func (d *Dispatcher) Correlate(requestID string) []Correlation {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make([]Correlation, 0, len(d.byRequest[requestID]))
+	for _, evt := range d.byRequest[requestID] {
+		c := Correlation{Event: evt, Status: StatusPending, Deliveries: []Delivery{}}
+		for _, dl := range d.deliveries {
+			if dl.EventID != evt.ID {
+				continue
+			}
+			c.Deliveries = append(c.Deliveries, dl)
+			if dl.Dropped {
+				c.Status = StatusDropped
+			} else if dl.StatusCode >= 200 && dl.StatusCode < 300 {
+				c.Status = StatusDelivered
+			} else if c.Status == StatusPending {
+				c.Status = StatusFailed
+			}
+		}
+		if c.Status == StatusFailed {
+			for _, r := range d.redeliveries {
+				if r.Event.ID == evt.ID {
+					c.Status = StatusRedelivering
+				}
+			}
+			for _, l := range d.deadLetters {
+				if l.Event.ID == evt.ID {
+					c.Status = StatusDeadLettered
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
 // QueuedEvents returns all queued but undelivered events.
 func (d *Dispatcher) QueuedEvents() []Event {
 	d.mu.RLock()
@@ -254,11 +789,15 @@ func (d *Dispatcher) AllEvents() []Event {
 	return out
 }
 
-// Reset clears all events, deliveries, and the queue.
+// Reset clears all events, deliveries, redeliveries, dead letters, and the
+// queue. Tenant endpoints are kept.
 func (d *Dispatcher) Reset() {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	d.queue = d.queue[:0]
 	d.deliveries = d.deliveries[:0]
+	d.redeliveries = d.redeliveries[:0]
+	d.deadLetters = d.deadLetters[:0]
+	d.byRequest = nil
 	d.counter = 0
 }
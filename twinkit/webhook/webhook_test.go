@@ -1,12 +1,19 @@
 package webhook
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/wondertwin-ai/wondertwin/twinkit/twincore"
 )
 
 // ---------------------------------------------------------------------------
@@ -273,6 +280,69 @@ func TestFlushWithSigner(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Tenant endpoints
+// ---------------------------------------------------------------------------
+
+func TestTenantEndpoints(t *testing.T) {
+	type received struct{ path, signature string }
+	var mu sync.Mutex
+	var got []received
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = append(got, received{r.URL.Path, r.Header.Get("X-Signature")})
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(Config{
+		URL:        srv.URL + "/platform",
+		Secret:     "whsec_platform",
+		Signer:     &mockSigner{},
+		MaxRetries: 1,
+		Endpoints:  map[string]Endpoint{"acct_a": {URL: srv.URL + "/a", Secret: "whsec_a"}},
+	})
+	d.SetTenantEndpoint("acct_b", Endpoint{Secret: "whsec_b"})
+
+	evt := d.EnqueueContext(WithTenant(context.Background(), "acct_a"), "test.a", nil)
+	if evt.Tenant != "acct_a" {
+		t.Errorf("expected the event to carry tenant acct_a, got %q", evt.Tenant)
+	}
+	d.EnqueueContext(WithTenant(context.Background(), "acct_b"), "test.b", nil)
+	d.EnqueueContext(WithTenant(context.Background(), "acct_c"), "test.c", nil)
+	d.Enqueue("test.platform", nil)
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	want := []received{
+		{"/a", "sig_whsec_a"},
+		{"/platform", "sig_whsec_b"},
+		{"/platform", "sig_whsec_platform"},
+		{"/platform", "sig_whsec_platform"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d deliveries, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delivery %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+	if dl := d.Deliveries()[0]; dl.Tenant != "acct_a" || dl.URL != srv.URL+"/a" {
+		t.Errorf("expected the delivery recorded for acct_a at its URL, got %+v", dl)
+	}
+
+	d.Reset()
+	if len(d.TenantEndpoints()) != 2 {
+		t.Errorf("expected tenant endpoints to survive reset, got %v", d.TenantEndpoints())
+	}
+	if !d.RemoveTenantEndpoint("acct_b") || d.RemoveTenantEndpoint("acct_b") {
+		t.Error("expected acct_b's endpoint removed exactly once")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // FlushWebhooks (alias)
 // ---------------------------------------------------------------------------
@@ -292,6 +362,214 @@ func TestFlushWebhooks(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Redelivery
+// ---------------------------------------------------------------------------
+
+// fakeClock is a Config.Clock that only moves when advanced.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestRedeliveryAfterBackoff(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	var received atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d := NewDispatcher(Config{
+		URL:               srv.URL,
+		MaxRetries:        1,
+		Guarantee:         AtLeastOnce,
+		RedeliveryBackoff: time.Minute,
+		Clock:             clock.Now,
+	})
+
+	d.Enqueue("test.redeliver", nil)
+	if err := d.Flush(); err == nil {
+		t.Fatal("expected the first delivery to fail")
+	}
+
+	queued := d.Redeliveries()
+	if len(queued) != 1 {
+		t.Fatalf("expected 1 queued redelivery, got %d", len(queued))
+	}
+	wait := queued[0].NextAttempt.Sub(clock.Now())
+	if wait < 30*time.Second || wait > time.Minute {
+		t.Errorf("expected the first redelivery within 30s-1m, got %v", wait)
+	}
+
+	if n := d.RedeliverDue(); n != 0 {
+		t.Errorf("expected nothing due before the backoff, redelivered %d", n)
+	}
+
+	fail.Store(false)
+	clock.Advance(time.Minute)
+	if n := d.RedeliverDue(); n != 1 {
+		t.Fatalf("expected 1 due redelivery, got %d", n)
+	}
+	if received.Load() != 1 {
+		t.Errorf("expected the event to be redelivered, got %d deliveries", received.Load())
+	}
+	if len(d.Redeliveries()) != 0 {
+		t.Errorf("expected an empty redelivery queue, got %+v", d.Redeliveries())
+	}
+
+	deliveries := d.Deliveries()
+	if last := deliveries[len(deliveries)-1]; last.Attempt != 2 || last.StatusCode != http.StatusOK {
+		t.Errorf("expected a successful second attempt, got %+v", last)
+	}
+}
+
+func TestRedeliveryBackoffGrows(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d := NewDispatcher(Config{
+		URL:                  srv.URL,
+		MaxRetries:           1,
+		Guarantee:            AtLeastOnce,
+		RedeliveryBackoff:    time.Minute,
+		MaxRedeliveryBackoff: 4 * time.Minute,
+		MaxRedeliveries:      10,
+		Clock:                clock.Now,
+	})
+	d.Enqueue("test.backoff", nil)
+	d.Flush()
+
+	// Expected ceilings: 1m, 2m, 4m, then capped at 4m.
+	for i, ceiling := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		r := d.Redeliveries()[0]
+		wait := r.NextAttempt.Sub(clock.Now())
+		if wait < ceiling/2 || wait > ceiling {
+			t.Errorf("redelivery %d: expected a wait within %v-%v, got %v", i+1, ceiling/2, ceiling, wait)
+		}
+		clock.Advance(wait)
+		d.RedeliverDue()
+	}
+}
+
+func TestDeadLetterAndRetry(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d := NewDispatcher(Config{
+		URL:               srv.URL,
+		MaxRetries:        1,
+		Guarantee:         AtLeastOnce,
+		RedeliveryBackoff: time.Minute,
+		MaxRedeliveries:   2,
+		Clock:             clock.Now,
+	})
+	evt := d.Enqueue("test.dead", nil)
+	d.Flush()
+
+	for range 2 {
+		clock.Advance(time.Hour)
+		d.RedeliverDue()
+	}
+
+	if len(d.Redeliveries()) != 0 {
+		t.Errorf("expected the redelivery queue to be empty, got %d", len(d.Redeliveries()))
+	}
+	dead := d.DeadLetters()
+	if len(dead) != 1 || dead[0].Event.ID != evt.ID {
+		t.Fatalf("expected %s to be dead-lettered, got %+v", evt.ID, dead)
+	}
+	if dead[0].Attempts != 3 || dead[0].LastError != "webhook delivery failed: status 502" {
+		t.Errorf("unexpected dead letter: %+v", dead[0])
+	}
+
+	if err := d.RetryDeadLetter(evt.ID); err == nil {
+		t.Error("expected retry to fail while the endpoint is down")
+	}
+	if dead := d.DeadLetters(); len(dead) != 1 || dead[0].Attempts != 4 {
+		t.Errorf("expected the failed retry to stay dead-lettered, got %+v", dead)
+	}
+
+	fail.Store(false)
+	if err := d.RetryDeadLetter(evt.ID); err != nil {
+		t.Fatalf("RetryDeadLetter: %v", err)
+	}
+	if len(d.DeadLetters()) != 0 {
+		t.Errorf("expected no dead letters after a successful retry, got %+v", d.DeadLetters())
+	}
+	if err := d.RetryDeadLetter(evt.ID); !errors.Is(err, ErrNotDeadLettered) {
+		t.Errorf("expected ErrNotDeadLettered, got %v", err)
+	}
+}
+
+func TestAtMostOnceDropsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	// At-most-once is the default.
+	d := NewDispatcher(Config{URL: srv.URL, MaxRetries: 1})
+	d.Enqueue("test.drop", nil)
+	if err := d.Flush(); err == nil {
+		t.Fatal("expected delivery to fail")
+	}
+	if len(d.Redeliveries()) != 0 || len(d.DeadLetters()) != 0 {
+		t.Errorf("expected at-most-once to drop the event, got %d redeliveries, %d dead letters",
+			len(d.Redeliveries()), len(d.DeadLetters()))
+	}
+}
+
+func TestNegativeMaxRetriesAttemptsOnce(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(Config{URL: srv.URL, MaxRetries: -1, Guarantee: AtLeastOnce})
+	d.Enqueue("test.negative", nil)
+	if err := d.Flush(); err == nil {
+		t.Fatal("expected delivery to fail")
+	}
+	if n := len(d.Deliveries()); n != 1 {
+		t.Errorf("expected 1 attempt, got %d", n)
+	}
+	if r := d.Redeliveries(); len(r) != 1 || r[0].LastError == "" {
+		t.Errorf("expected the failure queued for redelivery, got %+v", r)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // SetURL / SetSecret
 // ---------------------------------------------------------------------------
@@ -400,6 +678,9 @@ func TestReset(t *testing.T) {
 	if len(d.Deliveries()) != 0 {
 		t.Errorf("expected 0 deliveries after reset, got %d", len(d.Deliveries()))
 	}
+	if len(d.Redeliveries()) != 0 || len(d.DeadLetters()) != 0 {
+		t.Errorf("expected no redeliveries or dead letters after reset")
+	}
 
 	// Counter should reset, so next event starts at 1.
 	evt := d.Enqueue("c", nil)
@@ -407,3 +688,159 @@ func TestReset(t *testing.T) {
 		t.Errorf("expected evt_000001 after reset, got %s", evt.ID)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Correlate
+// ---------------------------------------------------------------------------
+
+func TestCorrelate(t *testing.T) {
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(Config{URL: srv.URL, MaxRetries: 1, Guarantee: AtLeastOnce})
+	ctx := context.WithValue(context.Background(), chimw.RequestIDKey, "req-1")
+	delivered := d.EnqueueContext(ctx, "test.delivered", nil)
+	d.Enqueue("test.uncorrelated", nil)
+	if err := d.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	fail.Store(true)
+	redelivering := d.EnqueueContext(ctx, "test.redelivering", nil)
+	pending := d.EnqueueContext(ctx, "test.pending", nil)
+	d.deliverEvent(redelivering)
+
+	got := d.Correlate("req-1")
+	if len(got) != 3 {
+		t.Fatalf("expected 3 correlated events, got %d", len(got))
+	}
+	want := []struct {
+		id, status string
+		deliveries int
+	}{
+		{delivered.ID, StatusDelivered, 1},
+		{redelivering.ID, StatusRedelivering, 1},
+		{pending.ID, StatusPending, 0},
+	}
+	for i, w := range want {
+		if got[i].Event.ID != w.id || got[i].Status != w.status || len(got[i].Deliveries) != w.deliveries {
+			t.Errorf("event %d: got %s %s with %d deliveries, want %s %s with %d",
+				i, got[i].Event.ID, got[i].Status, len(got[i].Deliveries), w.id, w.status, w.deliveries)
+		}
+	}
+
+	payload, _ := json.Marshal(delivered)
+	if strings.Contains(string(payload), "req-1") {
+		t.Errorf("request ID leaked into the payload: %s", payload)
+	}
+
+	d.Reset()
+	if got := d.Correlate("req-1"); len(got) != 0 {
+		t.Errorf("expected no correlations after reset, got %d", len(got))
+	}
+}
+
+func TestWebhookFaults(t *testing.T) {
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var evt Event
+		json.NewDecoder(r.Body).Decode(&evt)
+		received = append(received, evt.Type)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	faults := twincore.NewFaultRegistry()
+	d := NewDispatcher(Config{URL: srv.URL, MaxRetries: 1, Faults: faults})
+	if err := faults.Set("webhook:charge.succeeded", twincore.FaultConfig{Rate: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := faults.Set("webhook:charge.refunded", twincore.FaultConfig{Rate: 1, Delay: 10 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	dropFault := faults.All()["webhook:charge.succeeded"].ID
+
+	ctx := context.WithValue(context.Background(), chimw.RequestIDKey, "req-1")
+	dropped := d.EnqueueContext(ctx, "charge.succeeded", nil)
+	delayed := d.EnqueueContext(ctx, "charge.refunded", nil)
+	d.EnqueueContext(ctx, "charge.failed", nil)
+	if err := d.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(received, ",") != "charge.refunded,charge.failed" {
+		t.Errorf("expected only the undropped events delivered, got %v", received)
+	}
+	if len(d.Redeliveries()) != 0 {
+		t.Errorf("expected the dropped event not queued for redelivery, got %d", len(d.Redeliveries()))
+	}
+
+	got := d.Correlate("req-1")
+	if len(got) != 3 {
+		t.Fatalf("expected 3 correlated events, got %d", len(got))
+	}
+	if got[0].Event.ID != dropped.ID || got[0].Status != StatusDropped {
+		t.Errorf("expected %s dropped, got %s %s", dropped.ID, got[0].Event.ID, got[0].Status)
+	}
+	if dl := got[0].Deliveries; len(dl) != 1 || !dl[0].Dropped || dl[0].Attempt != 0 || dl[0].Fault != dropFault {
+		t.Errorf("expected one dropped delivery from fault %s, got %+v", dropFault, dl)
+	}
+	if got[1].Event.ID != delayed.ID || got[1].Status != StatusDelivered {
+		t.Errorf("expected %s delivered, got %s %s", delayed.ID, got[1].Event.ID, got[1].Status)
+	}
+	if dl := got[1].Deliveries; len(dl) != 1 || dl[0].Fault == "" || dl[0].Dropped {
+		t.Errorf("expected one delivery recording the delay fault, got %+v", dl)
+	}
+	if dl := got[2].Deliveries; len(dl) != 1 || dl[0].Fault != "" {
+		t.Errorf("expected an unfaulted delivery, got %+v", dl)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Payload schemas
+// ---------------------------------------------------------------------------
+
+func TestPayloadSchema(t *testing.T) {
+	d := NewDispatcher(Config{StrictPayloads: true, Schemas: map[string]twincore.Validator{
+		"charge.succeeded": twincore.MustParseSchema(`{
+			"type": "object",
+			"required": ["id", "amount"],
+			"properties": {"amount": {"type": "integer"}}
+		}`),
+	}})
+
+	if err := d.CheckPayload("charge.succeeded", map[string]any{"id": "ch_1", "amount": int64(2000)}); err != nil {
+		t.Errorf("expected a valid payload to pass, got %v", err)
+	}
+	if err := d.CheckPayload("payout.paid", map[string]any{"anything": true}); err != nil {
+		t.Errorf("expected an event type without a schema to pass, got %v", err)
+	}
+
+	err := d.CheckPayload("charge.succeeded", map[string]any{"amount": "2000"})
+	var pe *PayloadError
+	if !errors.As(err, &pe) || len(pe.Errors) != 2 {
+		t.Fatalf("expected a PayloadError with 2 problems, got %v", err)
+	}
+
+	lenient := NewDispatcher(Config{Schemas: d.schemas})
+	lenient.Enqueue("charge.succeeded", map[string]any{"id": "ch_1"})
+	if got := len(lenient.QueuedEvents()); got != 1 {
+		t.Errorf("expected an invalid event to be logged and queued without StrictPayloads, got %d queued", got)
+	}
+
+	defer func() {
+		if v := recover(); v == nil {
+			t.Error("expected Enqueue to panic on an invalid payload with StrictPayloads")
+		}
+		if got := len(d.QueuedEvents()); got != 0 {
+			t.Errorf("expected the invalid event not to be queued, got %d", got)
+		}
+	}()
+	d.Enqueue("charge.succeeded", map[string]any{"id": "ch_1"})
+}